@@ -11,6 +11,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/tools"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
 	"github.com/anyswap/CrossChain-Router/v3/tools/keystore"
 	"github.com/anyswap/CrossChain-Router/v3/tools/rlp"
 	"github.com/anyswap/CrossChain-Router/v3/types"
@@ -71,6 +72,11 @@ func Sign(method string, params []string) (rawTx string, err error) {
 	return common.ToHex(txdata), nil
 }
 
+// SignHash sign hash with admin key
+func SignHash(hash []byte) (signature []byte, err error) {
+	return crypto.Sign(hash, keyWrapper.PrivateKey)
+}
+
 // LoadKeyStore load keystore
 func LoadKeyStore(keyfile, passfile string) error {
 	key, err := tools.LoadKeyStore(keyfile, passfile)
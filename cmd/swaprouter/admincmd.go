@@ -1,12 +1,16 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/admin"
 	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/urfave/cli/v2"
 )
 
@@ -38,10 +42,16 @@ examples:
 <whitelist|unwhitelist> callbycontract,<chainID>,<address[,address]...>
 <whitelist|unwhitelist> callbycontractcodehash,<chainID>,<codehash[,codehash]...>
 <whitelist|unwhitelist> bigvalue,<tokenID>,<address[,address]...>
+<whitelist|unwhitelist> tokenroute,<tokenID>,<fromChainID:toChainID[,fromChainID:toChainID]...> ('*' matches any chain)
+<whitelist|unwhitelist> tokenrouteactive,<tokenID[,tokenID]...> (activate or deactivate token route whitelist)
 
 <blacklist|unblacklist> chainid,<chainID[,chainID]...>
 <blacklist|unblacklist> tokenid,<tokenID[,tokenID]...>
 <blacklist|unblacklist> account,<address[,address]...>
+
+ban <seconds>,<ip[,ip]...> (seconds 0 means escalated duration)
+unban <ip[,ip]...>
+banlist
 `,
 			},
 			{
@@ -87,11 +97,208 @@ forbid specified swap
 				Flags:  swapKeyFlags,
 				Description: `
 pass forbidden swapout
+`,
+			},
+			{
+				Name:   "vetoswap",
+				Usage:  "veto pending time locked swap",
+				Action: vetoswap,
+				Flags:  append(swapKeyFlags, utils.MemoFlag),
+				Description: `
+veto pending swap delayed by time lock policy (large withdrawal)
+`,
+			},
+			{
+				Name:   "approveswap",
+				Usage:  "approve swap waiting for manual approval",
+				Action: approveswap,
+				Flags:  swapKeyFlags,
+				Description: `
+approve swap to chain which requires manual approval (LocalChainConfig.ManualApproval)
+`,
+			},
+			{
+				Name:   "approvesign",
+				Usage:  "approve mpc sign request outside swap flow",
+				Action: approvesign,
+				Flags: []cli.Flag{
+					signPubkeyFlag,
+					msgHashFlag,
+					approvalLifetimeFlag,
+					fastMPCFlag,
+				},
+				Description: `
+approve mpc sign request not originating from a stored swap (dual control).
+the approval is signed by the keystore, which must be a configed approver.
+`,
+			},
+			{
+				Name:      "apitoken",
+				Usage:     "maintain delegated read-only api tokens",
+				Action:    apitoken,
+				ArgsUsage: "[command options] <mint|revoke|list> [name|id]",
+				Flags: []cli.Flag{
+					scopeBindFlag,
+					scopeTokenIDFlag,
+					apiTokenLifetimeFlag,
+				},
+				Description: `
+maintain delegated read-only api tokens, which can only query swaps
+of the scoped bind addresses and token ids (both are required if specified).
+
+examples:
+
+--bind <address> [--bind <address>]... [--tokenid <tokenID>]... [--lifetime <seconds>] mint <name>
+revoke <id>
+list
+
+(options must be placed before the action)
+
+the token secret is only returned once by mint, and is sent in
+'Authorization: Bearer <token>' header of scoped swap history requests.
+`,
+			},
+			{
+				Name:      "duplicate",
+				Usage:     "track recovery of duplicate destination deliveries",
+				Action:    duplicate,
+				ArgsUsage: "[command options] <list|update> [status|key status]",
+				Flags: []cli.Flag{
+					offsetFlag,
+					limitFlag,
+					recoveryTxFlag,
+					utils.MemoFlag,
+				},
+				Description: `
+list duplicate destination deliveries (more than one swap txs of a swap
+succeeded on chain) and track the recovery of the overpaid value.
+
+recovery status is one of unrecovered, recovering, recovered and writtenoff.
+
+examples:
+
+[--offset <offset>] [--limit <limit>] list [status]
+[--recoverytx <txid>] [--memo <memo>] update <key> <status>
+
+(options must be placed before the action)
+`,
+			},
+			{
+				Name:      "featureflag",
+				Usage:     "override feature flags gating risky behaviors",
+				Action:    featureFlag,
+				ArgsUsage: "<set|unset|list> [flag chainID [enabled]]",
+				Description: `
+override feature flags of chain (or all chains if chainID is 'all'),
+the overrides are persisted and synced to all the servers.
+
+flag is one of parallelSwap, autoReswap, batchSend, aggregate and bridge.
+assistants can only disable features, others need admin.
+
+examples:
+
+set bridge 1030 false
+unset bridge 1030
+list
+`,
+			},
+			{
+				Name:      "quarantine",
+				Usage:     "quarantine swaps verified in incident window",
+				Action:    quarantine,
+				ArgsUsage: "[command options] <time|block> <start> <end>",
+				Flags: []cli.Flag{
+					quarantineChainIDFlag,
+					utils.MemoFlag,
+				},
+				Description: `
+quarantine swaps verified in the time window (unix seconds) or whose source txs
+are in the block range of chain (both inclusive), eg. during a suspected rpc
+compromise. the unsigned swaps are re-verified with cross verification against
+the gateway endpoints (see Server.Quarantine config) before proceeding to signing,
+signed swaps are skipped.
+
+examples:
+
+[--memo <memo>] time 1700000000 1700003600
+--chainid 1 [--memo <memo>] block 18000000 18000300
+
+(options must be placed before the range type)
+`,
+			},
+			{
+				Name:      "resetratebreaker",
+				Usage:     "reset tripped circuit breaker of conversion rate oracle",
+				Action:    resetRateBreaker,
+				ArgsUsage: "<tokenID> <fromChainID> <toChainID>",
+				Description: `
+reset the tripped circuit breaker of the conversion rate oracle of route,
+the rate is fetched again and used without comparing to the last good rate.
+it only affects the swap server receiving the admin call.
 `,
 			},
 		},
 	}
 
+	scopeBindFlag = &cli.StringSliceFlag{
+		Name:  "bind",
+		Usage: "bind address in scope of api token",
+	}
+
+	scopeTokenIDFlag = &cli.StringSliceFlag{
+		Name:  "tokenid",
+		Usage: "token id in scope of api token",
+	}
+
+	apiTokenLifetimeFlag = &cli.Int64Flag{
+		Name:  "lifetime",
+		Usage: "lifetime of api token in seconds (0 means never expire)",
+	}
+
+	offsetFlag = &cli.IntFlag{
+		Name:  "offset",
+		Usage: "offset of list result",
+	}
+
+	limitFlag = &cli.IntFlag{
+		Name:  "limit",
+		Usage: "limit of list result (max 100)",
+		Value: 20,
+	}
+
+	recoveryTxFlag = &cli.StringFlag{
+		Name:  "recoverytx",
+		Usage: "tx id of recovering the overpaid value",
+	}
+
+	signPubkeyFlag = &cli.StringFlag{
+		Name:     "signpubkey",
+		Usage:    "mpc public key to sign with",
+		Required: true,
+	}
+
+	msgHashFlag = &cli.StringSliceFlag{
+		Name:     "msghash",
+		Usage:    "message hashes to sign (in order)",
+		Required: true,
+	}
+
+	approvalLifetimeFlag = &cli.Int64Flag{
+		Name:  "lifetime",
+		Usage: "lifetime of approval in seconds",
+		Value: 3600,
+	}
+
+	fastMPCFlag = &cli.BoolFlag{
+		Name:  "fastmpc",
+		Usage: "approve sign request of fast mpc",
+	}
+
+	quarantineChainIDFlag = &cli.StringFlag{
+		Name:  "chainid",
+		Usage: "source chain id of swaps to quarantine (required by block range)",
+	}
+
 	swapKeyFlags = []cli.Flag{
 		utils.ChainIDFlag,
 		utils.TxIDFlag,
@@ -259,3 +466,241 @@ func passforbiddenswapout(ctx *cli.Context) error {
 	log.Printf("result is '%v'", result)
 	return err
 }
+
+func vetoswap(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "vetoswap"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+	chainID, txid, logIndex, err := getKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	memo := ctx.String(utils.MemoFlag.Name)
+
+	log.Printf("%v: %v %v %v (memo: %v)", method, chainID, txid, logIndex, memo)
+
+	params := []string{chainID, txid, logIndex, memo}
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func approveswap(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "approveswap"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+	chainID, txid, logIndex, err := getKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%v: %v %v %v", method, chainID, txid, logIndex)
+
+	params := []string{chainID, txid, logIndex}
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func approvesign(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "approvesign"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	approval := &mpc.SignApproval{
+		SignPubkey: ctx.String(signPubkeyFlag.Name),
+		MsgHash:    ctx.StringSlice(msgHashFlag.Name),
+		Expiry:     time.Now().Unix() + ctx.Int64(approvalLifetimeFlag.Name),
+	}
+	signature, err := admin.SignHash(approval.Hash().Bytes())
+	if err != nil {
+		return err
+	}
+	approval.Signature = common.ToHex(signature)
+	data, err := json.Marshal(approval)
+	if err != nil {
+		return err
+	}
+
+	mpcType := "mpc"
+	if ctx.Bool(fastMPCFlag.Name) {
+		mpcType = "fastmpc"
+	}
+
+	log.Printf("%v: %v %v", method, string(data), mpcType)
+
+	params := []string{string(data), mpcType}
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func apitoken(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.NArg() == 0 {
+		return fmt.Errorf("apitoken: no action is specified")
+	}
+
+	method := "apitoken"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	action := ctx.Args().Get(0)
+	params := []string{action}
+	switch action {
+	case "mint":
+		if ctx.NArg() < 2 {
+			return fmt.Errorf("apitoken: no name is specified")
+		}
+		lifetime := ctx.Int64(apiTokenLifetimeFlag.Name)
+		if lifetime < 0 {
+			return fmt.Errorf("apitoken: wrong lifetime %v", lifetime)
+		}
+		params = append(params,
+			ctx.Args().Get(1),
+			strings.Join(ctx.StringSlice(scopeBindFlag.Name), ","),
+			strings.Join(ctx.StringSlice(scopeTokenIDFlag.Name), ","),
+			fmt.Sprintf("%d", lifetime),
+		)
+	case "revoke":
+		if ctx.NArg() < 2 {
+			return fmt.Errorf("apitoken: no id is specified")
+		}
+		params = append(params, ctx.Args().Get(1))
+	}
+
+	log.Printf("%v: %v", method, params)
+
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func duplicate(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.NArg() == 0 {
+		return fmt.Errorf("duplicate: no action is specified")
+	}
+
+	method := "duplicate"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	action := ctx.Args().Get(0)
+	params := []string{action}
+	switch action {
+	case "list":
+		params = append(params,
+			ctx.Args().Get(1),
+			fmt.Sprintf("%d", ctx.Int(offsetFlag.Name)),
+			fmt.Sprintf("%d", ctx.Int(limitFlag.Name)),
+		)
+	case "update":
+		if ctx.NArg() < 3 {
+			return fmt.Errorf("duplicate: no key or status is specified")
+		}
+		params = append(params,
+			ctx.Args().Get(1),
+			ctx.Args().Get(2),
+			ctx.String(recoveryTxFlag.Name),
+			ctx.String(utils.MemoFlag.Name),
+		)
+	}
+
+	log.Printf("%v: %v", method, params)
+
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func featureFlag(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.NArg() == 0 {
+		return fmt.Errorf("featureflag: no action is specified")
+	}
+
+	method := "featureflag"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := ctx.Args().Slice()
+
+	log.Printf("%v: %v", method, params)
+
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func quarantine(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.NArg() < 3 {
+		return fmt.Errorf("quarantine: no range type, start or end is specified")
+	}
+
+	method := "quarantine"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := []string{
+		ctx.String(quarantineChainIDFlag.Name),
+		ctx.Args().Get(0),
+		ctx.Args().Get(1),
+		ctx.Args().Get(2),
+		ctx.String(utils.MemoFlag.Name),
+	}
+
+	log.Printf("%v: %v", method, params)
+
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func resetRateBreaker(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.NArg() < 3 {
+		return fmt.Errorf("resetratebreaker: no tokenID, fromChainID or toChainID is specified")
+	}
+
+	method := "resetratebreaker"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := ctx.Args().Slice()[:3]
+
+	log.Printf("%v: %v", method, params)
+
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router/bridge"
+	"github.com/anyswap/CrossChain-Router/v3/tools"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/worker"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	auditCommand = &cli.Command{
+		Name:   "audit",
+		Usage:  "audit account history of chain in height range against database",
+		Action: auditAccountHistory,
+		Flags: []cli.Flag{
+			utils.ConfigFileFlag,
+			utils.GatewayConfigFlag,
+			auditChainIDFlag,
+			auditAccountFlag,
+			auditMinHeightFlag,
+			auditMaxHeightFlag,
+			utils.KeystoreFileFlag,
+			utils.PasswordFileFlag,
+			outputFileFlag,
+			utils.VerbosityFlag,
+			utils.JSONFormatFlag,
+			utils.ColorFormatFlag,
+		},
+		Description: `
+replay the validated history of the account (eg. the mpc) on chain in height range,
+re-derive all deposits and withdrawals of the account, and reconcile them against
+the swap records in database (chains implementing tokens.AccountHistoryAuditor, eg. ripple).
+the report is signed with the keystore (keccak256 hash of the report json
+without the signer and signature), and fails if any entry is not reconciled.
+`,
+	}
+
+	auditChainIDFlag = &cli.StringFlag{
+		Name:     "chainid",
+		Usage:    "chain id of the account",
+		Required: true,
+	}
+
+	auditAccountFlag = &cli.StringFlag{
+		Name:     "account",
+		Usage:    "account to audit (eg. the mpc)",
+		Required: true,
+	}
+
+	auditMinHeightFlag = &cli.Uint64Flag{
+		Name:     "minheight",
+		Usage:    "min height (ledger index) of the range (inclusive)",
+		Required: true,
+	}
+
+	auditMaxHeightFlag = &cli.Uint64Flag{
+		Name:     "maxheight",
+		Usage:    "max height (ledger index) of the range (inclusive)",
+		Required: true,
+	}
+)
+
+func auditAccountHistory(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	key, err := tools.LoadKeyStore(ctx.String(utils.KeystoreFileFlag.Name), ctx.String(utils.PasswordFileFlag.Name))
+	if err != nil {
+		return err
+	}
+	if ctx.IsSet(utils.GatewayConfigFlag.Name) {
+		params.GatewayConfigFile = ctx.String(utils.GatewayConfigFlag.Name)
+	}
+	configFile := utils.GetConfigFilePath(ctx)
+	config := params.LoadRouterConfig(configFile, true, false)
+	if config.Server == nil || config.Server.MongoDB == nil {
+		return fmt.Errorf("no mongodb config")
+	}
+	dbConfig := config.Server.MongoDB
+	mongodb.MongoServerInit(
+		params.GetIdentifier()+"-audit",
+		dbConfig.DBURLs,
+		dbConfig.DBName,
+		dbConfig.UserName,
+		dbConfig.Password,
+	)
+	bridge.InitRouterBridges(false)
+
+	chainID := ctx.String(auditChainIDFlag.Name)
+	report, err := worker.AuditAccountHistory(chainID, ctx.String(auditAccountFlag.Name),
+		ctx.Uint64(auditMinHeightFlag.Name), ctx.Uint64(auditMaxHeightFlag.Name))
+	if err != nil {
+		return err
+	}
+	hash, err := report.Hash()
+	if err != nil {
+		return err
+	}
+	signature, err := crypto.Sign(hash.Bytes(), key.PrivateKey)
+	if err != nil {
+		return err
+	}
+	report.Signer = key.Address.String()
+	report.Signature = common.ToHex(signature)
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	outputFile := ctx.String(outputFileFlag.Name)
+	if outputFile == "" {
+		fmt.Println(string(data))
+	} else if err = os.WriteFile(outputFile, data, 0o600); err != nil {
+		return err
+	}
+	log.Info("audit account history finished", "chainID", chainID, "account", report.Account, "minHeight", report.MinHeight, "maxHeight", report.MaxHeight, "summary", report.Summary, "hash", hash.String(), "signer", report.Signer)
+
+	unreconciled := len(report.Entries) - report.Summary[worker.AuditMatched] - report.Summary[worker.AuditIgnored]
+	if unreconciled > 0 {
+		return fmt.Errorf("%v of %v audit entries are not reconciled", unreconciled, len(report.Entries))
+	}
+	return nil
+}
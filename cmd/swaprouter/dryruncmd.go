@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/router/bridge"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/worker"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	dryRunCommand = &cli.Command{
+		Name:   "dryrun",
+		Usage:  "dry run verifying and building swaps of a source tx",
+		Action: dryRunSwaps,
+		Flags: []cli.Flag{
+			utils.ConfigFileFlag,
+			utils.GatewayConfigFlag,
+			dryRunChainIDFlag,
+			dryRunTxIDFlag,
+			dryRunLogIndexFlag,
+			dryRunBuildFlag,
+			dryRunFromFlag,
+			dryRunNonceFlag,
+			outputFileFlag,
+			utils.VerbosityFlag,
+			utils.JSONFormatFlag,
+			utils.ColorFormatFlag,
+		},
+		Description: `
+verify swaps of the source tx with the bridge of chainid (of any chain type),
+and optionally build the swap txs with the bridges of the destination chains.
+database is not used, and the built txs are never signed or sent.
+outputs the tx status, decoded swap infos, computed amounts, build args
+(including calldata) and the raw txs for debugging.
+`,
+	}
+
+	dryRunChainIDFlag = &cli.StringFlag{
+		Name:     "chainid",
+		Usage:    "chain id of the source tx",
+		Required: true,
+	}
+
+	dryRunTxIDFlag = &cli.StringFlag{
+		Name:     "txid",
+		Usage:    "hash of the source tx",
+		Required: true,
+	}
+
+	dryRunLogIndexFlag = &cli.IntFlag{
+		Name:  "logindex",
+		Usage: "log index of the swap (0 means all swaps in the tx)",
+	}
+
+	dryRunBuildFlag = &cli.BoolFlag{
+		Name:  "build",
+		Usage: "also build the swap txs",
+	}
+
+	dryRunFromFlag = &cli.StringFlag{
+		Name:  "from",
+		Usage: "sender of the built swap txs (default the router mpc)",
+	}
+
+	dryRunNonceFlag = &cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "nonce of the built swap txs",
+	}
+)
+
+// dryRunReport report of dry run
+type dryRunReport struct {
+	TxStatus *tokens.TxStatus       `json:"txStatus"`
+	Results  []*worker.DryRunResult `json:"results"`
+}
+
+func dryRunSwaps(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.IsSet(utils.GatewayConfigFlag.Name) {
+		params.GatewayConfigFile = ctx.String(utils.GatewayConfigFlag.Name)
+	}
+	configFile := utils.GetConfigFilePath(ctx)
+	params.LoadRouterConfig(configFile, true, false)
+
+	bridge.InitRouterBridges(false)
+	router.UpdateRouteRates()
+
+	txStatus, results, err := worker.DryRunSwaps(&worker.DryRunArgs{
+		ChainID:  ctx.String(dryRunChainIDFlag.Name),
+		TxID:     ctx.String(dryRunTxIDFlag.Name),
+		LogIndex: ctx.Int(dryRunLogIndexFlag.Name),
+		Build:    ctx.Bool(dryRunBuildFlag.Name),
+		From:     ctx.String(dryRunFromFlag.Name),
+		Nonce:    ctx.Uint64(dryRunNonceFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(&dryRunReport{TxStatus: txStatus, Results: results}, "", "  ")
+	if err != nil {
+		return err
+	}
+	outputFile := ctx.String(outputFileFlag.Name)
+	if outputFile == "" {
+		fmt.Println(string(data))
+	} else if err = os.WriteFile(outputFile, data, 0o600); err != nil {
+		return err
+	}
+	log.Info("dry run swaps finished", "chainID", ctx.String(dryRunChainIDFlag.Name), "txid", ctx.String(dryRunTxIDFlag.Name), "swaps", len(results))
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	exportCommand = &cli.Command{
+		Name:  "export",
+		Usage: "export data from database",
+		Flags: utils.CommonLogFlags,
+		Description: `
+export data from database
+`,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "accounting",
+				Usage:  "export snapshot consistent accounting extract of swaps in time range [start, end)",
+				Action: exportAccounting,
+				Flags: []cli.Flag{
+					utils.ConfigFileFlag,
+					startTimeFlag,
+					endTimeFlag,
+					outputFileFlag,
+				},
+			},
+		},
+	}
+
+	startTimeFlag = &cli.Int64Flag{
+		Name:  "start",
+		Usage: "start unix timestamp (inclusive)",
+	}
+
+	endTimeFlag = &cli.Int64Flag{
+		Name:  "end",
+		Usage: "end unix timestamp (exclusive)",
+	}
+
+	outputFileFlag = &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "output file (default stdout)",
+	}
+)
+
+func exportAccounting(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	startTime := ctx.Int64(startTimeFlag.Name)
+	endTime := ctx.Int64(endTimeFlag.Name)
+	if startTime <= 0 || endTime <= startTime {
+		return fmt.Errorf("wrong time range [%v, %v)", startTime, endTime)
+	}
+
+	configFile := utils.GetConfigFilePath(ctx)
+	config := params.LoadRouterConfig(configFile, true, false)
+	if config.Server == nil || config.Server.MongoDB == nil {
+		return fmt.Errorf("no mongodb config")
+	}
+	dbConfig := config.Server.MongoDB
+	mongodb.MongoServerInit(
+		params.GetIdentifier()+"-export",
+		dbConfig.DBURLs,
+		dbConfig.DBName,
+		dbConfig.UserName,
+		dbConfig.Password,
+	)
+
+	export, err := mongodb.ExportAccounting(startTime, endTime)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	outputFile := ctx.String(outputFileFlag.Name)
+	if outputFile == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	if err = os.WriteFile(outputFile, data, 0o600); err != nil {
+		return err
+	}
+	log.Info("export accounting finished", "output", outputFile, "snapshot", export.Snapshot, "swaps", len(export.Swaps), "token", export.ConsistencyToken)
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/internal/swapapi"
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/params"
@@ -32,7 +33,13 @@ func initApp() {
 	app.Copyright = "Copyright 2017-2020 The CrossChain-Router Authors"
 	app.Commands = []*cli.Command{
 		adminCommand,
+		auditCommand,
 		configCommand,
+		dryRunCommand,
+		exportCommand,
+		migrateCommand,
+		onboardCommand,
+		replayCommand,
 		toolsCommand,
 		utils.LicenseCommand,
 		utils.VersionCommand,
@@ -80,6 +87,8 @@ func swaprouter(ctx *cli.Context) error {
 	if isServer {
 		appName := params.GetIdentifier()
 		dbConfig := config.Server.MongoDB
+		mongodb.SetProfiling(dbConfig.EnableProfiling, dbConfig.SlowQueryThreshold)
+		mongodb.SetStatusQueues(dbConfig.EnableStatusQueues)
 		mongodb.MongoServerInit(
 			appName,
 			dbConfig.DBURLs,
@@ -87,6 +96,23 @@ func swaprouter(ctx *cli.Context) error {
 			dbConfig.UserName,
 			dbConfig.Password,
 		)
+		if mirror := dbConfig.Mirror; mirror != nil {
+			mongodb.MongoMirrorInit(appName, mirror.DBURLs, mirror.DBName, mirror.UserName, mirror.Password)
+		}
+		tokens.SetCheckpointStore(mongodb.CheckpointStore{})
+		if encConfig := dbConfig.FieldEncryption; encConfig != nil {
+			keys, current, err := encConfig.LoadKeys()
+			if err != nil {
+				log.Fatal("load field encryption keys failed", "err", err)
+			}
+			if err = mongodb.SetFieldEncryptionKeys(keys, current); err != nil {
+				log.Fatal("set field encryption keys failed", "err", err)
+			}
+			if encConfig.RotateOnStart {
+				go mongodb.RotateEncryptedFields()
+			}
+		}
+		worker.SetScannedTxRegister(swapapi.RegisterScannedTx)
 		worker.StartRouterSwapWork(true)
 		time.Sleep(100 * time.Millisecond)
 		rpcserver.StartAPIServer()
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	migrateCommand = &cli.Command{
+		Name:  "migrate",
+		Usage: "migrate database in dual write mode",
+		Flags: utils.CommonLogFlags,
+		Description: `
+migrate database to the mirror database configed in 'Server.MongoDB.Mirror'.
+the router server mirrors all writes to the new database while reading from the old one.
+`,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "check",
+				Usage:  "check consistency between the old and new database",
+				Action: migrateCheck,
+				Flags: []cli.Flag{
+					utils.ConfigFileFlag,
+					repairFlag,
+				},
+			},
+			{
+				Name:  "cutover",
+				Usage: "sync and verify the new database (stop the router server first)",
+				Description: `
+cutover copy all differences to the new database and verify again that they are consistent.
+after it succeeds, replace 'Server.MongoDB' with the mirror config and restart the router server.
+`,
+				Action: migrateCutover,
+				Flags: []cli.Flag{
+					utils.ConfigFileFlag,
+				},
+			},
+		},
+	}
+
+	repairFlag = &cli.BoolFlag{
+		Name:  "repair",
+		Usage: "copy differences to the new database",
+	}
+)
+
+func initMigrateDatabase(ctx *cli.Context) {
+	utils.SetLogger(ctx)
+	configFile := utils.GetConfigFilePath(ctx)
+	config := params.LoadRouterConfig(configFile, true, false)
+	if config.Server == nil || config.Server.MongoDB == nil || config.Server.MongoDB.Mirror == nil {
+		log.Fatal("no mongodb mirror config")
+	}
+	appName := params.GetIdentifier() + "-migrate"
+	dbConfig := config.Server.MongoDB
+	mongodb.MongoServerInit(
+		appName,
+		dbConfig.DBURLs,
+		dbConfig.DBName,
+		dbConfig.UserName,
+		dbConfig.Password,
+	)
+	mirror := dbConfig.Mirror
+	mongodb.MongoMirrorInit(appName, mirror.DBURLs, mirror.DBName, mirror.UserName, mirror.Password)
+}
+
+func printConsistency(result []*mongodb.MirrorConsistency) (consistent bool) {
+	data, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(data))
+	consistent = true
+	for _, res := range result {
+		if !res.IsConsistent() {
+			consistent = false
+		}
+	}
+	return consistent
+}
+
+func migrateCheck(ctx *cli.Context) error {
+	initMigrateDatabase(ctx)
+	result, err := mongodb.CheckMirrorConsistency(ctx.Bool(repairFlag.Name))
+	if err != nil {
+		return err
+	}
+	if !printConsistency(result) {
+		return fmt.Errorf("mirror database is not consistent")
+	}
+	return nil
+}
+
+func migrateCutover(ctx *cli.Context) error {
+	initMigrateDatabase(ctx)
+	result, err := mongodb.CheckMirrorConsistency(true)
+	if err != nil {
+		return err
+	}
+	log.Info("cutover sync finished, verify again")
+	printConsistency(result)
+
+	result, err = mongodb.CheckMirrorConsistency(false)
+	if err != nil {
+		return err
+	}
+	if !printConsistency(result) {
+		return fmt.Errorf("mirror database is still not consistent, make sure the router server is stopped")
+	}
+	log.Info("cutover success, replace 'Server.MongoDB' with the mirror config and restart the router server")
+	return nil
+}
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/router/bridge"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	onboardCommand = &cli.Command{
+		Name:   "onboard",
+		Usage:  "guided listing of a token on a chain",
+		Action: onboardToken,
+		Flags: []cli.Flag{
+			onboardChainIDFlag,
+			onboardBlockChainFlag,
+			onboardRPCFlag,
+			onboardRouterFlag,
+			onboardConfirmationsFlag,
+			onboardInitialHeightFlag,
+			onboardTokenIDFlag,
+			onboardTokenFlag,
+			onboardDecimalsFlag,
+			onboardVersionFlag,
+			onboardUnderlyingFlag,
+			onboardMPCFlag,
+			onboardMPCPubkeyFlag,
+			onboardMaxSwapFlag,
+			onboardMinSwapFlag,
+			onboardBigSwapFlag,
+			onboardMaxFeeFlag,
+			onboardMinFeeFlag,
+			onboardFeeRateFlag,
+			onchainContractFlag,
+			gatewaysFlag,
+			outputFileFlag,
+			utils.VerbosityFlag,
+			utils.JSONFormatFlag,
+			utils.ColorFormatFlag,
+		},
+		Description: `
+guided listing of a token on a chain (of any chain type).
+missing required inputs are asked interactively.
+outputs the local config section, the onchain router config contract
+calls (to be submitted by the contract owner), and the validation results
+of the inputs against the chain rpc and the current onchain configs.
+onchain calls of configs which are already set are skipped.
+swap and fee config values are in token units (decimals 18 onchain),
+and are set for swaps from any chain to this chain if MaximumSwap is given.
+`,
+	}
+
+	onboardChainIDFlag = &cli.StringFlag{
+		Name:  "chainid",
+		Usage: "chain id of the chain",
+	}
+
+	onboardBlockChainFlag = &cli.StringFlag{
+		Name:  "blockchain",
+		Usage: "block chain name of the chain config",
+	}
+
+	onboardRPCFlag = &cli.StringSliceFlag{
+		Name:  "rpc",
+		Usage: "rpc url of the chain",
+	}
+
+	onboardRouterFlag = &cli.StringFlag{
+		Name:  "router",
+		Usage: "router contract of the chain",
+	}
+
+	onboardConfirmationsFlag = &cli.Uint64Flag{
+		Name:  "confirmations",
+		Usage: "confirmations of the chain config",
+		Value: 1,
+	}
+
+	onboardInitialHeightFlag = &cli.Uint64Flag{
+		Name:  "initialheight",
+		Usage: "initial height of the chain config (default the latest height)",
+	}
+
+	onboardTokenIDFlag = &cli.StringFlag{
+		Name:  "tokenid",
+		Usage: "token ID",
+	}
+
+	onboardTokenFlag = &cli.StringFlag{
+		Name:  "token",
+		Usage: "token address on the chain",
+	}
+
+	onboardDecimalsFlag = &cli.IntFlag{
+		Name:  "decimals",
+		Usage: "token decimals (default queried from evm chains)",
+		Value: -1,
+	}
+
+	onboardVersionFlag = &cli.Uint64Flag{
+		Name:  "version",
+		Usage: "token contract version",
+		Value: 6,
+	}
+
+	onboardUnderlyingFlag = &cli.StringFlag{
+		Name:  "underlying",
+		Usage: "underlying token address (the extra of the token config)",
+	}
+
+	onboardMPCFlag = &cli.StringFlag{
+		Name:  "mpc",
+		Usage: "router mpc address (default derived from the mpc public key)",
+	}
+
+	onboardMPCPubkeyFlag = &cli.StringFlag{
+		Name:  "mpcpubkey",
+		Usage: "router mpc public key",
+	}
+
+	onboardMaxSwapFlag = &cli.StringFlag{Name: "maxswap", Usage: "MaximumSwap of swap config"}
+	onboardMinSwapFlag = &cli.StringFlag{Name: "minswap", Usage: "MinimumSwap of swap config"}
+	onboardBigSwapFlag = &cli.StringFlag{Name: "bigswap", Usage: "BigValueThreshold of swap config"}
+	onboardMaxFeeFlag  = &cli.StringFlag{Name: "maxfee", Usage: "MaximumSwapFee of fee config"}
+	onboardMinFeeFlag  = &cli.StringFlag{Name: "minfee", Usage: "MinimumSwapFee of fee config"}
+	onboardFeeRateFlag = &cli.Uint64Flag{Name: "feerate", Usage: "SwapFeeRatePerMillion of fee config"}
+
+	// function hashes of router config contract
+	setChainConfigFuncHash      = common.FromHex("0x2383006c") // setChainConfig(uint256,string,string,uint64,uint64,string)
+	setTokenConfigFuncHash      = common.FromHex("0x34fd0135") // setTokenConfig(string,uint256,string,uint8,uint256,string,string)
+	setSwapAndFeeConfigFuncHash = common.FromHex("0x7926422c") // setSwapAndFeeConfig(string,uint256,uint256,uint256,uint256,uint256,uint256,uint256,uint256)
+	setMPCPubkeyFuncHash        = common.FromHex("0x5b751858") // setMPCPubkey(address,string)
+)
+
+// onboardReport report of onboarding
+type onboardReport struct {
+	LocalConfig string            `json:"localConfig"`
+	Submissions []*onchainCall    `json:"submissions"`
+	Checks      []*onboardCheck   `json:"checks"`
+	Inputs      map[string]string `json:"inputs"`
+}
+
+// onchainCall call of router config contract
+type onchainCall struct {
+	Function string        `json:"function"`
+	Args     []string      `json:"args"`
+	To       string        `json:"to,omitempty"`
+	Data     hexutil.Bytes `json:"data"`
+}
+
+// onboardCheck validation result
+type onboardCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type onboarding struct {
+	ctx    *cli.Context
+	reader *bufio.Reader
+	report *onboardReport
+	bridge tokens.IBridge
+
+	chainID  *big.Int
+	decimals uint8
+	onchain  bool
+}
+
+//nolint:funlen,gocyclo // guided steps
+func onboardToken(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	o := &onboarding{
+		ctx:    ctx,
+		reader: bufio.NewReader(os.Stdin),
+		report: &onboardReport{Inputs: make(map[string]string)},
+	}
+
+	// step 1: inputs of the chain
+	chainIDStr := o.input(onboardChainIDFlag.Name, "", true)
+	chainID, err := common.GetBigIntFromStr(chainIDStr)
+	if err != nil || chainID.Sign() <= 0 {
+		return fmt.Errorf("wrong chainID '%v'", chainIDStr)
+	}
+	o.chainID = chainID
+	rpcs := ctx.StringSlice(onboardRPCFlag.Name)
+	if len(rpcs) == 0 {
+		rpcs = strings.Split(o.input(onboardRPCFlag.Name, "", true), ",")
+	}
+	o.report.Inputs[onboardRPCFlag.Name] = strings.Join(rpcs, ",")
+	blockChain := o.input(onboardBlockChainFlag.Name, "", true)
+	routerContract := o.input(onboardRouterFlag.Name, "", true)
+
+	o.bridge = bridge.NewCrossChainBridge(chainID)
+	o.bridge.SetChainConfig(&tokens.ChainConfig{
+		ChainID:        chainID.String(),
+		BlockChain:     blockChain,
+		RouterContract: routerContract,
+	})
+	o.bridge.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: rpcs})
+	evmBridge, isEvm := o.bridge.(*eth.Bridge)
+
+	latest, err := o.bridge.GetLatestBlockNumber()
+	o.check("rpc", err == nil, fmt.Sprintf("latest height %v, err %v", latest, err))
+	if isEvm {
+		rpcChainID, errf := evmBridge.ChainID()
+		o.check("chainID", errf == nil && rpcChainID.Cmp(chainID) == 0, fmt.Sprintf("rpc chainID %v, err %v", rpcChainID, errf))
+	}
+	o.checkAddress("router", routerContract, evmBridge)
+
+	// step 2: inputs of the token
+	tokenID := o.input(onboardTokenIDFlag.Name, "", true)
+	token := o.input(onboardTokenFlag.Name, "", true)
+	underlying := o.input(onboardUnderlyingFlag.Name, "", false)
+	o.checkAddress("token", token, evmBridge)
+	if underlying != "" {
+		o.checkAddress("underlying", underlying, evmBridge)
+	}
+	if err = o.getDecimals(token, evmBridge); err != nil {
+		return err
+	}
+
+	// step 3: inputs of the mpc
+	mpcPubkey := o.input(onboardMPCPubkeyFlag.Name, "", true)
+	mpcAddress, err := o.bridge.PublicKeyToAddress(mpcPubkey)
+	o.check("mpc public key", err == nil, fmt.Sprintf("address %v, err %v", mpcAddress, err))
+	if mpc := o.input(onboardMPCFlag.Name, mpcAddress, false); mpc != "" && mpcAddress != "" {
+		o.check("mpc address", strings.EqualFold(mpc, mpcAddress), fmt.Sprintf("given %v, derived %v", mpc, mpcAddress))
+	}
+
+	// step 4: current onchain configs
+	if contract := ctx.String(onchainContractFlag.Name); contract != "" {
+		router.InitRouterConfigClientsWithArgs(contract, ctx.StringSlice(gatewaysFlag.Name))
+		o.onchain = true
+	}
+
+	initialHeight := ctx.Uint64(onboardInitialHeightFlag.Name)
+	if initialHeight == 0 {
+		initialHeight = latest
+	}
+	if o.isChainConfigNeeded(blockChain, routerContract) {
+		o.addCall("setChainConfig", setChainConfigFuncHash,
+			chainID, blockChain, routerContract, ctx.Uint64(onboardConfirmationsFlag.Name), initialHeight, "")
+	}
+	if o.isTokenConfigNeeded(tokenID, token, routerContract, underlying) {
+		o.addCall("setTokenConfig", setTokenConfigFuncHash,
+			tokenID, chainID, token, o.decimals, new(big.Int).SetUint64(ctx.Uint64(onboardVersionFlag.Name)), routerContract, underlying)
+	}
+	if err = o.addSwapAndFeeConfig(tokenID); err != nil {
+		return err
+	}
+	if mpcAddress != "" && o.isMPCPubkeyNeeded(mpcAddress, mpcPubkey) && common.IsHexAddress(mpcAddress) {
+		o.addCall("setMPCPubkey", setMPCPubkeyFuncHash, common.HexToAddress(mpcAddress), mpcPubkey)
+	}
+
+	o.report.LocalConfig = fmt.Sprintf("[Gateways]\n%v = [\"%v\"]\n", chainID, strings.Join(rpcs, "\", \""))
+	return o.output()
+}
+
+// input get input from flag, or ask for it if it is missing
+func (o *onboarding) input(name, defValue string, required bool) (value string) {
+	defer func() { o.report.Inputs[name] = value }()
+	if o.ctx.IsSet(name) {
+		return strings.TrimSpace(o.ctx.String(name))
+	}
+	if defValue == "" && !required {
+		return ""
+	}
+	for {
+		if defValue != "" {
+			fmt.Printf("%v [%v]: ", name, defValue)
+		} else {
+			fmt.Printf("%v: ", name)
+		}
+		line, err := o.reader.ReadString('\n')
+		value = strings.TrimSpace(line)
+		if value == "" {
+			value = defValue
+		}
+		if value != "" || !required || err != nil {
+			return value
+		}
+	}
+}
+
+func (o *onboarding) check(name string, ok bool, detail string) {
+	o.report.Checks = append(o.report.Checks, &onboardCheck{Name: name, OK: ok, Detail: detail})
+	if !ok {
+		log.Warn("onboard check failed", "name", name, "detail", detail)
+	}
+}
+
+func (o *onboarding) checkAddress(name, address string, evmBridge *eth.Bridge) {
+	if !o.bridge.IsValidAddress(address) {
+		o.check(name+" address", false, address+" is invalid")
+		return
+	}
+	if evmBridge == nil {
+		o.check(name+" address", true, address)
+		return
+	}
+	code, err := evmBridge.GetCode(address)
+	o.check(name+" address", err == nil && len(code) > 0, fmt.Sprintf("%v code length %v, err %v", address, len(code), err))
+}
+
+func (o *onboarding) getDecimals(token string, evmBridge *eth.Bridge) error {
+	decimals := o.ctx.Int(onboardDecimalsFlag.Name)
+	if evmBridge != nil {
+		onchainDecimals, err := evmBridge.GetErc20Decimals(token)
+		if decimals < 0 && err == nil {
+			decimals = int(onchainDecimals)
+		}
+		o.check("decimals", err == nil && int(onchainDecimals) == decimals, fmt.Sprintf("onchain %v, err %v", onchainDecimals, err))
+	}
+	if decimals < 0 {
+		decimalsStr := o.input(onboardDecimalsFlag.Name, "", true)
+		value, err := common.GetUint64FromStr(decimalsStr)
+		if err != nil {
+			return fmt.Errorf("wrong decimals '%v'", decimalsStr)
+		}
+		decimals = int(value)
+	}
+	if decimals > 255 {
+		return fmt.Errorf("wrong decimals %v", decimals)
+	}
+	o.decimals = uint8(decimals)
+	o.report.Inputs[onboardDecimalsFlag.Name] = fmt.Sprint(decimals)
+	return nil
+}
+
+func (o *onboarding) isChainConfigNeeded(blockChain, routerContract string) bool {
+	if !o.onchain {
+		return true
+	}
+	exist, err := router.IsChainIDExist(o.chainID)
+	if err != nil || !exist {
+		return true
+	}
+	chainCfg, err := router.GetChainConfig(o.chainID)
+	if err != nil {
+		return true
+	}
+	same := strings.EqualFold(chainCfg.BlockChain, blockChain) && strings.EqualFold(chainCfg.RouterContract, routerContract)
+	o.check("onchain chain config", same, fmt.Sprintf("blockChain %v, router %v", chainCfg.BlockChain, chainCfg.RouterContract))
+	return !same
+}
+
+func (o *onboarding) isTokenConfigNeeded(tokenID, token, routerContract, underlying string) bool {
+	if !o.onchain {
+		return true
+	}
+	tokenCfg, err := router.GetTokenConfig(o.chainID, tokenID)
+	if err != nil || tokenCfg.ContractAddress == "" {
+		return true
+	}
+	same := strings.EqualFold(tokenCfg.ContractAddress, token) &&
+		strings.EqualFold(tokenCfg.RouterContract, routerContract) &&
+		strings.EqualFold(tokenCfg.Extra, underlying) &&
+		tokenCfg.Decimals == o.decimals
+	o.check("onchain token config", same, fmt.Sprintf("token %v, router %v, decimals %v, underlying %v",
+		tokenCfg.ContractAddress, tokenCfg.RouterContract, tokenCfg.Decimals, tokenCfg.Extra))
+	return !same
+}
+
+func (o *onboarding) isMPCPubkeyNeeded(mpcAddress, mpcPubkey string) bool {
+	if !o.onchain {
+		return true
+	}
+	pubkey, err := router.GetMPCPubkey(mpcAddress)
+	if err != nil || pubkey == "" {
+		return true
+	}
+	same := strings.EqualFold(pubkey, mpcPubkey)
+	o.check("onchain mpc public key", same, "onchain "+pubkey)
+	return !same
+}
+
+func (o *onboarding) addSwapAndFeeConfig(tokenID string) error {
+	maxSwap := o.input(onboardMaxSwapFlag.Name, "", false)
+	if maxSwap == "" {
+		return nil
+	}
+	values := make([]*big.Int, 0, 5)
+	for _, flag := range []*cli.StringFlag{onboardMaxSwapFlag, onboardMinSwapFlag, onboardBigSwapFlag, onboardMaxFeeFlag, onboardMinFeeFlag} {
+		valueStr := o.input(flag.Name, "", true)
+		value := tokens.ToBits(valueStr, 18)
+		if value == nil {
+			return fmt.Errorf("wrong %v '%v'", flag.Name, valueStr)
+		}
+		values = append(values, value)
+	}
+	if values[1].Cmp(values[0]) > 0 || values[4].Cmp(values[3]) > 0 {
+		return errors.New("minimum is larger than maximum in swap and fee config")
+	}
+	feeRate := new(big.Int).SetUint64(o.ctx.Uint64(onboardFeeRateFlag.Name))
+	o.addCall("setSwapAndFeeConfig", setSwapAndFeeConfigFuncHash,
+		tokenID, big.NewInt(0), o.chainID, values[0], values[1], values[2], values[3], values[4], feeRate)
+	return nil
+}
+
+func (o *onboarding) addCall(function string, funcHash []byte, args ...interface{}) {
+	strArgs := make([]string, len(args))
+	for i, arg := range args {
+		strArgs[i] = fmt.Sprint(arg)
+	}
+	call := &onchainCall{
+		Function: function,
+		Args:     strArgs,
+		Data:     abicoder.PackDataWithFuncHash(funcHash, args...),
+	}
+	if contract := o.ctx.String(onchainContractFlag.Name); contract != "" {
+		call.To = contract
+	}
+	o.report.Submissions = append(o.report.Submissions, call)
+}
+
+func (o *onboarding) output() error {
+	data, err := json.MarshalIndent(o.report, "", "  ")
+	if err != nil {
+		return err
+	}
+	outputFile := o.ctx.String(outputFileFlag.Name)
+	if outputFile == "" {
+		fmt.Println(string(data))
+	} else if err = os.WriteFile(outputFile, data, 0o600); err != nil {
+		return err
+	}
+	failed := 0
+	for _, c := range o.report.Checks {
+		if !c.OK {
+			failed++
+		}
+	}
+	log.Info("onboard token finished", "chainID", o.chainID, "submissions", len(o.report.Submissions), "checks", len(o.report.Checks), "failed", failed)
+	if failed > 0 {
+		return fmt.Errorf("%v of %v validation checks failed", failed, len(o.report.Checks))
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/urfave/cli/v2"
+)
+
+func newTestOnboarding(t *testing.T, stdin string, args ...string) *onboarding {
+	set := flag.NewFlagSet("onboard", flag.ContinueOnError)
+	for _, f := range onboardCommand.Flags {
+		if err := f.Apply(set); err != nil {
+			t.Fatalf("apply flag %v failed: %v", f.Names(), err)
+		}
+	}
+	if err := set.Parse(args); err != nil {
+		t.Fatalf("parse flags failed: %v", err)
+	}
+	return &onboarding{
+		ctx:     cli.NewContext(cli.NewApp(), set, nil),
+		reader:  bufio.NewReader(strings.NewReader(stdin)),
+		report:  &onboardReport{Inputs: make(map[string]string)},
+		chainID: big.NewInt(56),
+	}
+}
+
+func TestOnboardFuncHashes(t *testing.T) {
+	tests := []struct {
+		signature string
+		funcHash  []byte
+	}{
+		{"setChainConfig(uint256,string,string,uint64,uint64,string)", setChainConfigFuncHash},
+		{"setTokenConfig(string,uint256,string,uint8,uint256,string,string)", setTokenConfigFuncHash},
+		{"setSwapAndFeeConfig(string,uint256,uint256,uint256,uint256,uint256,uint256,uint256,uint256)", setSwapAndFeeConfigFuncHash},
+		{"setMPCPubkey(address,string)", setMPCPubkeyFuncHash},
+	}
+	for _, test := range tests {
+		if want := common.Keccak256Hash([]byte(test.signature)).Bytes()[:4]; !bytes.Equal(test.funcHash, want) {
+			t.Errorf("func hash of %v got %x, want %x", test.signature, test.funcHash, want)
+		}
+	}
+}
+
+func TestOnboardInput(t *testing.T) {
+	o := newTestOnboarding(t, "\nUSDC\n\n", "--token", " 0x01 ")
+	// inputs of flags are not asked
+	if got := o.input(onboardTokenFlag.Name, "", true); got != "0x01" {
+		t.Errorf("input of flag got %q, want 0x01", got)
+	}
+	// missing required inputs are asked until not empty
+	if got := o.input(onboardTokenIDFlag.Name, "", true); got != "USDC" {
+		t.Errorf("asked input got %q, want USDC", got)
+	}
+	// the default is used for empty answers
+	if got := o.input(onboardMPCFlag.Name, "0x02", false); got != "0x02" {
+		t.Errorf("asked input with default got %q, want 0x02", got)
+	}
+	// optional inputs without default are not asked
+	if got := o.input(onboardUnderlyingFlag.Name, "", false); got != "" {
+		t.Errorf("optional input got %q, want empty", got)
+	}
+	if o.report.Inputs[onboardTokenIDFlag.Name] != "USDC" || o.report.Inputs[onboardTokenFlag.Name] != "0x01" {
+		t.Errorf("reported inputs got %v", o.report.Inputs)
+	}
+}
+
+func TestOnboardSwapAndFeeConfig(t *testing.T) {
+	// swap and fee config is not set without maximum swap
+	o := newTestOnboarding(t, "")
+	if err := o.addSwapAndFeeConfig("USDC"); err != nil || len(o.report.Submissions) != 0 {
+		t.Errorf("swap and fee config without maximum swap got (%v, %v submissions)", err, len(o.report.Submissions))
+	}
+
+	o = newTestOnboarding(t, "", "--maxswap", "1000", "--minswap", "10", "--bigswap", "500",
+		"--maxfee", "5", "--minfee", "1", "--feerate", "1000", "--contract", "0x03")
+	if err := o.addSwapAndFeeConfig("USDC"); err != nil || len(o.report.Submissions) != 1 {
+		t.Fatalf("swap and fee config got (%v, %v submissions), want 1 submission", err, len(o.report.Submissions))
+	}
+	call := o.report.Submissions[0]
+	if call.Function != "setSwapAndFeeConfig" || call.To != "0x03" || !bytes.HasPrefix(call.Data, setSwapAndFeeConfigFuncHash) ||
+		len(call.Data) <= 4+9*32 {
+		t.Errorf("swap and fee config call got %+v", call)
+	}
+	// values are in token units of decimals 18
+	wantArgs := []string{"USDC", "0", "56", "1000000000000000000000", "10000000000000000000", "500000000000000000000", "5000000000000000000", "1000000000000000000", "1000"}
+	if strings.Join(call.Args, ",") != strings.Join(wantArgs, ",") {
+		t.Errorf("swap and fee config args got %v, want %v", call.Args, wantArgs)
+	}
+
+	tests := [][]string{
+		{"--maxswap", "10", "--minswap", "1000", "--bigswap", "500", "--maxfee", "5", "--minfee", "1"},
+		{"--maxswap", "1000", "--minswap", "10", "--bigswap", "500", "--maxfee", "1", "--minfee", "5"},
+		{"--maxswap", "1000", "--minswap", "abc", "--bigswap", "500", "--maxfee", "5", "--minfee", "1"},
+	}
+	for i, args := range tests {
+		o = newTestOnboarding(t, "", args...)
+		if err := o.addSwapAndFeeConfig("USDC"); err == nil || len(o.report.Submissions) != 0 {
+			t.Errorf("test %v: wrong swap and fee config %v should fail", i, args)
+		}
+	}
+}
+
+func TestOnboardOutput(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "onboard.json")
+	o := newTestOnboarding(t, "", "--output", outputFile)
+	o.check("rpc", true, "latest height 100")
+	if err := o.output(); err != nil {
+		t.Errorf("output of passed checks got error %v", err)
+	}
+	data, err := os.ReadFile(outputFile)
+	var report onboardReport
+	if err != nil || json.Unmarshal(data, &report) != nil || len(report.Checks) != 1 {
+		t.Errorf("output report got %s, err %v", data, err)
+	}
+
+	// failed checks are reported as error after the output
+	o.check("token address", false, "0x01 is invalid")
+	if err = o.output(); err == nil {
+		t.Errorf("output of failed checks should fail")
+	}
+}
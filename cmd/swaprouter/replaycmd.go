@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/router/bridge"
+	"github.com/anyswap/CrossChain-Router/v3/worker"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	replayCommand = &cli.Command{
+		Name:   "replay",
+		Usage:  "replay historical swaps to detect behavioral regressions",
+		Action: replaySwaps,
+		Flags: []cli.Flag{
+			utils.ConfigFileFlag,
+			utils.GatewayConfigFlag,
+			startTimeFlag,
+			endTimeFlag,
+			replayChainIDFlag,
+			replayLimitFlag,
+			replayAllFlag,
+			outputFileFlag,
+			utils.VerbosityFlag,
+			utils.JSONFormatFlag,
+			utils.ColorFormatFlag,
+		},
+		Description: `
+replay stable swaps registered in time range [start, end).
+re-run verify and build (never sign or send) with the current code,
+and diff the outputs against the stored swap results.
+`,
+	}
+
+	replayChainIDFlag = &cli.StringFlag{
+		Name:  "chainid",
+		Usage: "only replay swaps from this chain",
+	}
+
+	replayLimitFlag = &cli.Int64Flag{
+		Name:  "limit",
+		Usage: "max count of swaps to replay (0 means no limit)",
+		Value: 1000,
+	}
+
+	replayAllFlag = &cli.BoolFlag{
+		Name:  "all",
+		Usage: "output all replay results (default only regressions)",
+	}
+)
+
+// replayReport report of replaying swaps
+type replayReport struct {
+	StartTime   int64                  `json:"startTime"`
+	EndTime     int64                  `json:"endTime"`
+	Replayed    int                    `json:"replayed"`
+	Regressions int                    `json:"regressions"`
+	Results     []*worker.ReplayResult `json:"results"`
+}
+
+func replaySwaps(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	startTime := ctx.Int64(startTimeFlag.Name)
+	endTime := ctx.Int64(endTimeFlag.Name)
+	if startTime <= 0 || endTime <= startTime {
+		return fmt.Errorf("wrong time range [%v, %v)", startTime, endTime)
+	}
+
+	if ctx.IsSet(utils.GatewayConfigFlag.Name) {
+		params.GatewayConfigFile = ctx.String(utils.GatewayConfigFlag.Name)
+	}
+	configFile := utils.GetConfigFilePath(ctx)
+	config := params.LoadRouterConfig(configFile, true, false)
+	if config.Server == nil || config.Server.MongoDB == nil {
+		return fmt.Errorf("no mongodb config")
+	}
+	dbConfig := config.Server.MongoDB
+	mongodb.MongoServerInit(
+		params.GetIdentifier()+"-replay",
+		dbConfig.DBURLs,
+		dbConfig.DBName,
+		dbConfig.UserName,
+		dbConfig.Password,
+	)
+
+	swaps, err := mongodb.FindStableRouterSwapResultsInRange(
+		ctx.String(replayChainIDFlag.Name), startTime, endTime, ctx.Int64(replayLimitFlag.Name))
+	if err != nil {
+		return err
+	}
+	log.Info("start replay swaps", "startTime", startTime, "endTime", endTime, "count", len(swaps))
+
+	bridge.InitRouterBridges(true)
+	router.UpdateRouteRates()
+
+	report := &replayReport{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Results:   make([]*worker.ReplayResult, 0),
+	}
+	outputAll := ctx.Bool(replayAllFlag.Name)
+	for _, swap := range swaps {
+		result := worker.ReplaySwap(swap)
+		report.Replayed++
+		if result.IsRegression() {
+			report.Regressions++
+			log.Warn("replay found regression", "fromChainID", result.FromChainID, "txid", result.TxID, "logIndex", result.LogIndex,
+				"verifyError", result.VerifyError, "buildError", result.BuildError, "diffs", len(result.Diffs))
+		} else if !outputAll {
+			continue
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	outputFile := ctx.String(outputFileFlag.Name)
+	if outputFile == "" {
+		fmt.Println(string(data))
+	} else if err = os.WriteFile(outputFile, data, 0o600); err != nil {
+		return err
+	}
+	log.Info("replay swaps finished", "replayed", report.Replayed, "regressions", report.Regressions)
+	if report.Regressions > 0 {
+		return fmt.Errorf("found %v regressions in %v replayed swaps", report.Regressions, report.Replayed)
+	}
+	return nil
+}
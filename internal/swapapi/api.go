@@ -1,6 +1,9 @@
 package swapapi
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +23,9 @@ var (
 	oraclesInfo sync.Map // string -> *OracleInfo // key is enode
 
 	errAlreadyRegistered = newRPCError(-32001, "already registered")
+
+	maxSwapStatsCount = int64(10000)
+	maxMPCUsageCount  = int64(10000)
 )
 
 func newRPCError(ec rpcjson.ErrorCode, message string) error {
@@ -46,6 +52,11 @@ func GetServerInfo() *ServerInfo {
 		ExtraConfig:    extraCfg,
 		AllChainIDs:    router.AllChainIDs,
 		PausedChainIDs: router.GetPausedChainIDs(),
+		IsWatcher:      worker.IsWatcherMode,
+
+		DiscardedSignCount: worker.GetDiscardedSignCount(),
+		StaleHeadChainIDs:  worker.GetStaleHeadChainIDs(),
+		CrashLoopJobs:      worker.GetCrashLoopJobs(),
 	}
 }
 
@@ -66,6 +77,95 @@ func GetStatusInfo(status string) (map[string]interface{}, error) {
 	return mongodb.GetStatusInfo(status)
 }
 
+// GetDBProfiles get database operation profiles
+func GetDBProfiles() *DBProfiles {
+	return &DBProfiles{
+		LatencyBuckets: mongodb.GetLatencyBuckets(),
+		Collections:    mongodb.GetCollectionProfiles(),
+	}
+}
+
+// GetShadowReport get shadow mode comparison report
+func GetShadowReport() *ShadowReport {
+	compared, divergent, divergences := worker.GetShadowDivergences()
+	return &ShadowReport{
+		Compared:    compared,
+		Divergent:   divergent,
+		Divergences: divergences,
+	}
+}
+
+// GetSLOReport get service level report computed from swap completions
+func GetSLOReport() *SLOReport {
+	return worker.GetSLOReport()
+}
+
+// GetIntegrityReport get the latest integrity check report
+func GetIntegrityReport() (*IntegrityReport, error) {
+	report := worker.GetIntegrityReport()
+	if report == nil {
+		return nil, newRPCError(-32000, "integrity check is disabled or not finished yet")
+	}
+	return report, nil
+}
+
+// GetSwapStats get time bucketed (hourly or daily) stats of finalized swaps,
+// the default time range is the last day of hourly and the last 30 days of daily.
+func GetSwapStats(filter *SwapStatsFilter) ([]*SwapStat, error) {
+	var bucketSize, maxBuckets int64
+	switch filter.Interval {
+	case mongodb.SwapStatHourly:
+		bucketSize, maxBuckets = 3600, 24*31
+	case mongodb.SwapStatDaily:
+		bucketSize, maxBuckets = 86400, 366
+	default:
+		return nil, newRPCError(-32000, "unknown stats interval "+filter.Interval)
+	}
+	if filter.End <= 0 {
+		filter.End = time.Now().Unix()
+	}
+	if filter.Start <= 0 {
+		defaultBuckets := int64(24)
+		if filter.Interval == mongodb.SwapStatDaily {
+			defaultBuckets = 30
+		}
+		filter.Start = filter.End - defaultBuckets*bucketSize
+	}
+	if filter.Start >= filter.End {
+		return nil, newRPCError(-32000, "start time is not before end time")
+	}
+	if (filter.End-filter.Start)/bucketSize > maxBuckets {
+		return nil, newRPCError(-32000, fmt.Sprintf("time range exceeds %v buckets", maxBuckets))
+	}
+	return mongodb.FindSwapStats(filter, maxSwapStatsCount)
+}
+
+// GetMPCUsage get time bucketed mpc sign usage (sign and failure counts) per chain,
+// the default time range is the last day.
+func GetMPCUsage(filter *MPCUsageFilter) ([]*MPCUsage, error) {
+	if params.GetMPCUsageConfig() == nil {
+		return nil, newRPCError(-32000, "mpc usage accounting is disabled")
+	}
+	if filter.End <= 0 {
+		filter.End = time.Now().Unix()
+	}
+	if filter.Start <= 0 {
+		filter.Start = filter.End - 86400
+	}
+	if filter.Start >= filter.End {
+		return nil, newRPCError(-32000, "start time is not before end time")
+	}
+	return mongodb.FindMPCUsages(filter, maxMPCUsageCount)
+}
+
+// GetTimeLockedSwaps get pending swaps delayed by time lock policy
+func GetTimeLockedSwaps() *TimeLockedSwaps {
+	return &TimeLockedSwaps{
+		Delay: params.GetTimeLockDelay(),
+		Swaps: worker.GetTimeLockedSwaps(),
+	}
+}
+
 // ReportOracleInfo report oracle info
 func ReportOracleInfo(oracle string, info *OracleInfo) error {
 	oracleID := mpc.GetEnodeID(oracle)
@@ -107,9 +207,9 @@ func ReportOracleInfo(oracle string, info *OracleInfo) error {
 // RegisterRouterSwap register router swap
 // if logIndex is 0 then check all logs, otherwise only check the specified log
 //nolint:funlen,gocyclo // allow long method
-func RegisterRouterSwap(fromChainID, txid, logIndexStr string) (*MapIntResult, error) {
+func RegisterRouterSwap(fromChainID, txid, logIndexStr, requestID string) (*MapIntResult, error) {
 	swapType := tokens.GetRouterSwapType()
-	log.Debug("[api] register swap", "chainid", fromChainID, "txid", txid, "logIndex", logIndexStr, "swapType", swapType.String())
+	log.Debug("[api] register swap", "chainid", fromChainID, "txid", txid, "logIndex", logIndexStr, "swapType", swapType.String(), "requestID", requestID)
 	chainID, err := common.GetBigIntFromStr(fromChainID)
 	if err != nil {
 		return nil, newRPCInternalError(err)
@@ -132,7 +232,7 @@ func RegisterRouterSwap(fromChainID, txid, logIndexStr string) (*MapIntResult, e
 		SwapType: swapType,
 		LogIndex: logIndex,
 	}
-	log.Debug("[api] register swap start", "chainid", fromChainID, "txid", txid, "logIndex", logIndexStr, "swapType", swapType.String())
+	log.Debug("[api] register swap start", "chainid", fromChainID, "txid", txid, "logIndex", logIndexStr, "swapType", swapType.String(), "requestID", requestID)
 	swapInfos, errs := bridge.RegisterSwap(txid, registerArgs)
 	for i, swapInfo := range swapInfos {
 		var memo string
@@ -143,6 +243,9 @@ func RegisterRouterSwap(fromChainID, txid, logIndexStr string) (*MapIntResult, e
 		logIndex = swapInfo.LogIndex
 		if !tokens.ShouldRegisterRouterSwapForError(verifyErr) {
 			result[logIndex] = "verify error: " + memo
+			if !tokens.IsTransientVerifyError(verifyErr) {
+				_ = mongodb.AddSwapRejection(fromChainID, txid, logIndex, verifyErr)
+			}
 			continue
 		}
 		oldSwap, registeredOk := mongodb.GetRegisteredRouterSwap(fromChainID, txid, logIndex)
@@ -157,12 +260,13 @@ func RegisterRouterSwap(fromChainID, txid, logIndexStr string) (*MapIntResult, e
 			switch {
 			case verifyErr != nil:
 				result[-1-logIndex] = "verify error: " + memo
+				_ = mongodb.AddSwapRejection(fromChainID, txid, logIndex, verifyErr)
 			case router.IsBigValueSwap(swapInfo):
 				result[-1-logIndex] = "verify error: bigvalue"
 			case router.IsBlacklistSwap(swapInfo):
 				result[-1-logIndex] = "verify error: blacklist"
 			}
-			err = addMgoSwap(swapInfo, newStatus, memo)
+			err = addMgoSwap(swapInfo, newStatus, memo, requestID)
 			if err != nil {
 				result[logIndex] = "db error"
 			}
@@ -190,7 +294,7 @@ func RegisterRouterSwap(fromChainID, txid, logIndexStr string) (*MapIntResult, e
 	return &result, nil
 }
 
-func addMgoSwap(swapInfo *tokens.SwapTxInfo, status mongodb.SwapStatus, memo string) (err error) {
+func addMgoSwap(swapInfo *tokens.SwapTxInfo, status mongodb.SwapStatus, memo, requestID string) (err error) {
 	valueStr := "0"
 	if swapInfo.Value != nil {
 		valueStr = swapInfo.Value.String()
@@ -208,6 +312,7 @@ func addMgoSwap(swapInfo *tokens.SwapTxInfo, status mongodb.SwapStatus, memo str
 		Status:      status,
 		Timestamp:   time.Now().Unix(),
 		Memo:        memo,
+		RequestID:   requestID,
 	}
 	swap.SwapInfo = mongodb.ConvertToSwapInfo(&swapInfo.SwapInfo)
 	err = mongodb.AddRouterSwap(swap)
@@ -219,6 +324,24 @@ func addMgoSwap(swapInfo *tokens.SwapTxInfo, status mongodb.SwapStatus, memo str
 	return err
 }
 
+// RegisterScannedTx register router tx found by block scanning,
+// already registered txs are not errors.
+func RegisterScannedTx(chainID, txHash string) error {
+	result, err := RegisterRouterSwap(chainID, txHash, "", "blockscan")
+	if errors.Is(err, errAlreadyRegistered) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for logIndex, status := range *result {
+		if status == "db error" {
+			return fmt.Errorf("register log %v failed: %v", logIndex, status)
+		}
+	}
+	return nil
+}
+
 func getLogIndex(logindexStr string) (int, error) {
 	if logindexStr == "" {
 		return 0, nil
@@ -247,9 +370,158 @@ func GetRouterSwap(fromChainID, txid, logindexStr string) (*SwapInfo, error) {
 	if err == nil {
 		return ConvertMgoSwapToSwapInfo(register), nil
 	}
+	// explain why the swap is not found if it is rejected
+	rejections, _ := mongodb.FindSwapRejectionsOfTx(fromChainID, txid)
+	for _, rejection := range rejections {
+		if rejection.LogIndex == logindex || logindex == 0 {
+			return nil, newRPCError(-32000, fmt.Sprintf("swap is rejected: %v: %v", rejection.Reason, rejection.Message))
+		}
+	}
 	return nil, mongodb.ErrSwapNotFound
 }
 
+// long poll of swap status
+const (
+	DefaultLongPollTimeout = 30 * time.Second
+	MaxLongPollTimeout     = 120 * time.Second
+)
+
+var (
+	longPollInterval = 2 * time.Second
+
+	// get swap status in long poll, it is replaced in tests
+	getLongPollRouterSwap = GetRouterSwap
+)
+
+// WaitRouterSwap wait until the swap reaches a final status or timeout,
+// the latest swap status is returned on timeout.
+// not found swaps are waited too as they may be registered later.
+func WaitRouterSwap(ctx context.Context, fromChainID, txid, logindexStr string, timeout time.Duration) (*SwapInfo, error) {
+	if timeout <= 0 {
+		timeout = DefaultLongPollTimeout
+	} else if timeout > MaxLongPollTimeout {
+		timeout = MaxLongPollTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(longPollInterval)
+	defer ticker.Stop()
+	for {
+		res, err := getLongPollRouterSwap(fromChainID, txid, logindexStr)
+		if err == nil && res.Status.IsFinalStatus() {
+			return res, nil
+		}
+		if err != nil && !errors.Is(err, mongodb.ErrSwapNotFound) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return res, err
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetSwapRejections get rejection records of tx failed verification
+func GetSwapRejections(fromChainID, txid string) ([]*SwapRejection, error) {
+	rejections, err := mongodb.FindSwapRejectionsOfTx(fromChainID, txid)
+	if err != nil {
+		return nil, newRPCInternalError(err)
+	}
+	return rejections, nil
+}
+
+// GetSwapTimeline get lifecycle timeline of swap
+func GetSwapTimeline(fromChainID, txid, logindexStr string) (*SwapTimeline, error) {
+	logindex, err := getLogIndex(logindexStr)
+	if err != nil {
+		return nil, err
+	}
+	swap, err := mongodb.FindRouterSwapAuto(fromChainID, txid, logindex)
+	if err != nil {
+		return nil, mongodb.ErrSwapNotFound
+	}
+	timeline, err := worker.GetSwapTimeline(fromChainID, txid, swap.LogIndex)
+	if err != nil {
+		return nil, newRPCInternalError(err)
+	}
+	return timeline, nil
+}
+
+// GetSwapTransitions get status transitions of swap in time order
+func GetSwapTransitions(fromChainID, txid, logindexStr string) ([]*SwapTransition, error) {
+	logindex, err := getLogIndex(logindexStr)
+	if err != nil {
+		return nil, err
+	}
+	swap, err := mongodb.FindRouterSwapAuto(fromChainID, txid, logindex)
+	if err != nil {
+		return nil, mongodb.ErrSwapNotFound
+	}
+	return mongodb.FindSwapTransitions(fromChainID, txid, swap.LogIndex)
+}
+
+// GetSwapSnapshot get what the swap looked like at timestamp (milli seconds),
+// which is replayed from the swap history.
+func GetSwapSnapshot(fromChainID, txid, logindexStr string, timestamp int64) (*SwapSnapshot, error) {
+	logindex, err := getLogIndex(logindexStr)
+	if err != nil {
+		return nil, err
+	}
+	if timestamp <= 0 {
+		return nil, newRPCError(-32000, "snapshot timestamp is not positive")
+	}
+	swap, err := mongodb.FindRouterSwapAuto(fromChainID, txid, logindex)
+	if err != nil {
+		return nil, mongodb.ErrSwapNotFound
+	}
+	snapshot, err := mongodb.FindSwapSnapshot(fromChainID, txid, swap.LogIndex, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	result := &SwapSnapshot{
+		Timestamp: snapshot.Timestamp,
+		Replayed:  snapshot.Replayed,
+	}
+	if snapshot.SwapResult != nil {
+		result.SwapInfo = ConvertMgoSwapResultToSwapInfo(snapshot.SwapResult)
+	} else {
+		result.SwapInfo = ConvertMgoSwapToSwapInfo(snapshot.Swap)
+	}
+	return result, nil
+}
+
+// GetClaimSwap get claim status of two-phase swap
+func GetClaimSwap(fromChainID, txid, logindexStr string) (*ClaimSwap, error) {
+	logindex, err := getLogIndex(logindexStr)
+	if err != nil {
+		return nil, err
+	}
+	swap, err := mongodb.FindRouterSwapAuto(fromChainID, txid, logindex)
+	if err != nil {
+		return nil, mongodb.ErrSwapNotFound
+	}
+	claim, err := mongodb.FindClaimSwap(fromChainID, txid, swap.LogIndex)
+	if err != nil {
+		return nil, newRPCError(-32000, "claim swap not found (not a two-phase swap or not stable yet)")
+	}
+	return claim, nil
+}
+
+// GetTokenMigrations get token migration reports of chain
+func GetTokenMigrations(chainID string) ([]*TokenMigrationReport, error) {
+	bridge := router.GetBridgeByChainID(chainID)
+	if bridge == nil {
+		return nil, newRPCError(-32000, fmt.Sprintf("chainID %v not exist", chainID))
+	}
+	reporter, ok := bridge.(tokens.TokenMigrationReporter)
+	if !ok {
+		return nil, newRPCError(-32000, "token migration is not supported on this chain")
+	}
+	return reporter.GetTokenMigrationReports(), nil
+}
+
 // GetRouterSwaps impl
 func GetRouterSwaps(fromChainID, txid string) ([]*SwapInfo, error) {
 	result, _ := mongodb.FindRouterSwapResultsOfTx(fromChainID, txid)
@@ -277,6 +549,69 @@ func GetRouterSwapHistory(fromChainID, address string, offset, limit int, status
 	return ConvertMgoSwapResultsToSwapInfos(result), nil
 }
 
+// GetStuckRouterSwaps get registered but unprocessed swaps by processing stage
+func GetStuckRouterSwaps(filter *StuckSwapsFilter) ([]*SwapInfo, error) {
+	if !mongodb.IsValidSwapStage(filter.Stage) {
+		return nil, newRPCError(-32000, "unknown swap stage "+filter.Stage)
+	}
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = 20 // default
+	case filter.Limit > 100:
+		filter.Limit = 100
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	result, err := mongodb.FindStuckRouterSwaps(filter)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertMgoSwapResultsToSwapInfos(result), nil
+}
+
+// RegisterDepositAddress register deposit address on chainID which credits deposits to bind on toChainID
+func RegisterDepositAddress(chainID, toChainID, bind string) (string, error) {
+	bridge := router.GetBridgeByChainID(chainID)
+	if bridge == nil {
+		return "", newRPCInternalError(tokens.ErrNoBridgeForChainID)
+	}
+	provider, ok := bridge.(tokens.DepositAddressProvider)
+	if !ok || !provider.IsDepositAddressEnabled() {
+		return "", newRPCError(-32000, "deposit address is not supported on chain "+chainID)
+	}
+	depositAddress, err := provider.RegisterDepositAddress(toChainID, bind)
+	if err != nil {
+		return "", newRPCInternalError(err)
+	}
+	return depositAddress, nil
+}
+
+// GetSwapReceipt get signed completion receipt of stable swap
+func GetSwapReceipt(fromChainID, txid, logindexStr string) (*SwapReceipt, error) {
+	logindex, err := getLogIndex(logindexStr)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := mongodb.FindSwapReceipt(fromChainID, txid, logindex)
+	if err == nil {
+		return receipt, nil
+	}
+	res, err := mongodb.FindRouterSwapResultAuto(fromChainID, txid, logindex)
+	if err != nil {
+		return nil, mongodb.ErrSwapNotFound
+	}
+	if res.Status != mongodb.MatchTxStable {
+		return nil, newRPCError(-32000, "swap is not completed, status is "+res.Status.String())
+	}
+	// issue receipt for swaps completed before receipt is supported
+	receipt, err = worker.IssueSwapReceipt(res)
+	if err != nil {
+		return nil, newRPCInternalError(err)
+	}
+	return receipt, nil
+}
+
 // GetAllMultichainTokens impl
 func GetAllMultichainTokens(tokenID string) map[string]string {
 	m := make(map[string]string)
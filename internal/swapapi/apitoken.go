@@ -0,0 +1,170 @@
+package swapapi
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+)
+
+const apiTokenPrefix = "rt_"
+
+var (
+	errInvalidAPIToken = newRPCError(-32003, "invalid api token")
+	errOutOfTokenScope = newRPCError(-32003, "query is out of the scope of api token")
+)
+
+// getAPITokenKey get the stored key of api token secret
+func getAPITokenKey(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// newAPITokenSecret new random api token secret, which is returned only once when minted
+func newAPITokenSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return apiTokenPrefix + hex.EncodeToString(secret), nil
+}
+
+// MintAPIToken mint read-only api token which can only query swaps of binds or token ids.
+// lifetime is in seconds, 0 means never expire.
+func MintAPIToken(name string, binds, tokenIDs []string, lifetime int64) (*MintedAPIToken, error) {
+	if name == "" {
+		return nil, newRPCError(-32000, "api token name is empty")
+	}
+	binds, tokenIDs = compactStrings(binds), compactStrings(tokenIDs)
+	if len(binds) == 0 && len(tokenIDs) == 0 {
+		return nil, newRPCError(-32000, "api token scope is empty (no binds and token ids)")
+	}
+	if lifetime < 0 {
+		return nil, newRPCError(-32000, "api token lifetime is negative")
+	}
+
+	token, err := newAPITokenSecret()
+	if err != nil {
+		return nil, newRPCInternalError(err)
+	}
+
+	now := time.Now().Unix()
+	apiToken := &APIToken{
+		Key:        getAPITokenKey(token),
+		Name:       mongodb.EncryptedString(name),
+		Binds:      binds,
+		TokenIDs:   tokenIDs,
+		CreateTime: now,
+	}
+	if lifetime > 0 {
+		apiToken.ExpireTime = now + lifetime
+	}
+	if err := mongodb.AddAPIToken(apiToken); err != nil {
+		return nil, newRPCInternalError(err)
+	}
+	return &MintedAPIToken{APIToken: apiToken, Token: token}, nil
+}
+
+// RevokeAPIToken revoke api token by id
+func RevokeAPIToken(id string) error {
+	if err := mongodb.RevokeAPIToken(id); err != nil {
+		return newRPCInternalError(err)
+	}
+	return nil
+}
+
+// GetAPITokens get all api tokens (without secrets)
+func GetAPITokens() ([]*APIToken, error) {
+	return mongodb.FindAPITokens()
+}
+
+// ParseBearerToken parse api token from the value of 'Authorization' header
+func ParseBearerToken(authorization string) string {
+	const bearerPrefix = "Bearer "
+	if len(authorization) <= len(bearerPrefix) || !strings.EqualFold(authorization[:len(bearerPrefix)], bearerPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(authorization[len(bearerPrefix):])
+}
+
+// authAPIToken get the valid api token of secret
+func authAPIToken(token string) (*APIToken, error) {
+	if !strings.HasPrefix(token, apiTokenPrefix) {
+		return nil, errInvalidAPIToken
+	}
+	apiToken, err := mongodb.FindAPIToken(getAPITokenKey(token))
+	if err != nil || !isAPITokenValid(apiToken, time.Now().Unix()) {
+		return nil, errInvalidAPIToken
+	}
+	return apiToken, nil
+}
+
+// isAPITokenValid is api token neither revoked nor expired at the time
+func isAPITokenValid(apiToken *APIToken, now int64) bool {
+	return !apiToken.Revoked && !apiToken.IsExpired(now)
+}
+
+// narrowScope narrow the scope to value if specified, value must be in scope.
+// empty scope is not restricted.
+func narrowScope(scope []string, value string) ([]string, error) {
+	if value == "" {
+		return scope, nil
+	}
+	if len(scope) == 0 {
+		return []string{value}, nil
+	}
+	for _, item := range scope {
+		if strings.EqualFold(item, value) {
+			return []string{item}, nil
+		}
+	}
+	return nil, errOutOfTokenScope
+}
+
+// GetScopedSwapHistory get swap history in the scope of api token
+func GetScopedSwapHistory(token string, args *ScopedSwapHistoryArgs) ([]*SwapInfo, error) {
+	apiToken, err := authAPIToken(token)
+	if err != nil {
+		return nil, err
+	}
+	filter := &mongodb.ScopedSwapsFilter{
+		Offset: args.Offset,
+		Limit:  args.Limit,
+		Status: args.Status,
+	}
+	if filter.Binds, err = narrowScope(apiToken.Binds, args.Bind); err != nil {
+		return nil, err
+	}
+	if filter.TokenIDs, err = narrowScope(apiToken.TokenIDs, args.TokenID); err != nil {
+		return nil, err
+	}
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = 20 // default
+	case filter.Limit > 100:
+		filter.Limit = 100
+	case filter.Limit < -100:
+		filter.Limit = -100
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	result, err := mongodb.FindScopedRouterSwapResults(filter)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertMgoSwapResultsToSwapInfos(result), nil
+}
+
+func compactStrings(items []string) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+package swapapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAPITokenSecret(t *testing.T) {
+	token, err := newAPITokenSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token, apiTokenPrefix) || len(token) != len(apiTokenPrefix)+64 {
+		t.Fatalf("wrong api token format %v", token)
+	}
+	other, _ := newAPITokenSecret()
+	if other == token {
+		t.Fatalf("api token secrets should be random")
+	}
+
+	// only the hash of secret is stored as key
+	hash := sha256.Sum256([]byte(token))
+	key := getAPITokenKey(token)
+	if key != hex.EncodeToString(hash[:]) {
+		t.Errorf("api token key got %v, want sha256 of secret", key)
+	}
+	if strings.Contains(key, strings.TrimPrefix(token, apiTokenPrefix)) {
+		t.Errorf("api token key should not contain the secret")
+	}
+	if getAPITokenKey(token) != key || getAPITokenKey(other) == key {
+		t.Errorf("api token key should be the same only for the same secret")
+	}
+}
+
+func TestAuthAPITokenWrongFormat(t *testing.T) {
+	for _, token := range []string{"", "abc", "RT_" + strings.Repeat("0", 64)} {
+		if _, err := authAPIToken(token); !errors.Is(err, errInvalidAPIToken) {
+			t.Errorf("auth api token %q got err %v, want %v", token, err, errInvalidAPIToken)
+		}
+	}
+}
+
+func TestIsAPITokenValid(t *testing.T) {
+	now := int64(1000)
+	tests := []struct {
+		token *APIToken
+		valid bool
+	}{
+		{&APIToken{}, true},
+		{&APIToken{ExpireTime: now + 1}, true},
+		{&APIToken{ExpireTime: now}, false},
+		{&APIToken{ExpireTime: now - 1}, false},
+		{&APIToken{Revoked: true}, false},
+		{&APIToken{ExpireTime: now + 1, Revoked: true}, false},
+	}
+	for i, test := range tests {
+		if valid := isAPITokenValid(test.token, now); valid != test.valid {
+			t.Errorf("test %v: api token valid got %v, want %v", i, valid, test.valid)
+		}
+	}
+}
+
+func TestParseBearerToken(t *testing.T) {
+	tests := []struct {
+		authorization string
+		want          string
+	}{
+		{"Bearer rt_01", "rt_01"},
+		{"bearer  rt_01 ", "rt_01"},
+		{"Bearer ", ""},
+		{"Basic rt_01", ""},
+		{"rt_01", ""},
+	}
+	for i, test := range tests {
+		if got := ParseBearerToken(test.authorization); got != test.want {
+			t.Errorf("test %v: parse bearer token got %q, want %q", i, got, test.want)
+		}
+	}
+}
+
+func TestNarrowScope(t *testing.T) {
+	tests := []struct {
+		scope   []string
+		value   string
+		want    []string
+		wantErr error
+	}{
+		{[]string{"0xaa", "0xbb"}, "", []string{"0xaa", "0xbb"}, nil},
+		{[]string{"0xaa", "0xbb"}, "0xBB", []string{"0xbb"}, nil},
+		{nil, "0xcc", []string{"0xcc"}, nil},
+		// values out of the token scope are denied
+		{[]string{"0xaa", "0xbb"}, "0xcc", nil, errOutOfTokenScope},
+	}
+	for i, test := range tests {
+		got, err := narrowScope(test.scope, test.value)
+		if !errors.Is(err, test.wantErr) || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("test %v: narrow scope got (%v, %v), want (%v, %v)", i, got, err, test.want, test.wantErr)
+		}
+	}
+}
+
+func TestScopedSwapHistoryDenied(t *testing.T) {
+	if _, err := GetScopedSwapHistory("wrong", &ScopedSwapHistoryArgs{}); !errors.Is(err, errInvalidAPIToken) {
+		t.Errorf("scoped swap history of invalid token got err %v, want %v", err, errInvalidAPIToken)
+	}
+}
+
+func TestMintAPITokenWrongArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		binds    []string
+		tokenIDs []string
+		lifetime int64
+	}{
+		{"", []string{"0xaa"}, nil, 0},
+		{"partner", nil, nil, 0},
+		{"partner", []string{" ", ""}, []string{""}, 0},
+		{"partner", []string{"0xaa"}, nil, -1},
+	}
+	for i, test := range tests {
+		if _, err := MintAPIToken(test.name, test.binds, test.tokenIDs, test.lifetime); err == nil {
+			t.Errorf("test %v: mint api token with wrong args should fail", i)
+		}
+	}
+}
@@ -25,6 +25,8 @@ func ConvertMgoSwapToSwapInfo(ms *mongodb.MgoSwap) *SwapInfo {
 		InitTime:    ms.InitTime,
 		Timestamp:   ms.Timestamp,
 		Memo:        ms.Memo,
+
+		ValueDisplay: router.FormatAmount(ms.FromChainID, ms.GetTokenID(), ms.Value),
 	}
 }
 
@@ -63,6 +65,7 @@ func ConvertMgoSwapResultToSwapInfo(mr *mongodb.MgoSwapResult) *SwapInfo {
 		SwapHeight:    mr.SwapHeight,
 		SwapValue:     mr.SwapValue,
 		SwapNonce:     mr.SwapNonce,
+		SwapFee:       mr.SwapFee,
 		Status:        mr.Status,
 		StatusMsg:     mr.Status.String(),
 		InitTime:      mr.InitTime,
@@ -70,6 +73,9 @@ func ConvertMgoSwapResultToSwapInfo(mr *mongodb.MgoSwapResult) *SwapInfo {
 		Memo:          mr.Memo,
 		ReplaceCount:  len(mr.OldSwapTxs),
 		Confirmations: confirmations,
+
+		ValueDisplay:     router.FormatAmount(mr.FromChainID, mr.GetTokenID(), mr.Value),
+		SwapValueDisplay: router.FormatAmount(mr.ToChainID, mr.GetTokenID(), mr.SwapValue),
 	}
 }
 
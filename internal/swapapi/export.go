@@ -0,0 +1,455 @@
+package swapapi
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tools/parquet"
+)
+
+// swap export formats
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatParquet = "parquet"
+)
+
+// swap export job statuses
+const (
+	ExportJobRunning = mongodb.ExportJobRunning
+	ExportJobDone    = mongodb.ExportJobDone
+	ExportJobFailed  = mongodb.ExportJobFailed
+)
+
+const (
+	exportHeartbeatInterval = 30 * time.Second
+	exportStaleTimeout      = 300 // seconds without heartbeat
+)
+
+var (
+	errExportDisabled    = newRPCError(-32000, "swap export is disabled")
+	errExportJobNotFound = newRPCError(-32000, "export job not found")
+	errExportJobNotDone  = newRPCError(-32000, "export job is not done")
+	errTooManyExportJobs = newRPCError(-32000, "too many running export jobs")
+
+	exportHost = getExportHost()
+
+	exportColumns = []parquet.Column{
+		{Name: "fromChainID", Type: parquet.String},
+		{Name: "txid", Type: parquet.String},
+		{Name: "logIndex", Type: parquet.Int64},
+		{Name: "toChainID", Type: parquet.String},
+		{Name: "tokenID", Type: parquet.String},
+		{Name: "swapType", Type: parquet.Int64},
+		{Name: "from", Type: parquet.String},
+		{Name: "to", Type: parquet.String},
+		{Name: "bind", Type: parquet.String},
+		{Name: "value", Type: parquet.String},
+		{Name: "txHeight", Type: parquet.Int64},
+		{Name: "txTime", Type: parquet.Int64},
+		{Name: "swapTx", Type: parquet.String},
+		{Name: "swapHeight", Type: parquet.Int64},
+		{Name: "swapTime", Type: parquet.Int64},
+		{Name: "swapValue", Type: parquet.String},
+		{Name: "swapNonce", Type: parquet.Int64},
+		{Name: "status", Type: parquet.String},
+		{Name: "initTime", Type: parquet.Int64}, // milli seconds
+		{Name: "timestamp", Type: parquet.Int64},
+		{Name: "memo", Type: parquet.String},
+	}
+)
+
+// SwapExportArgs args of exporting swaps in scope of api token.
+// bind and tokenid narrow the scope further, and must be in the scope if specified.
+// start and end (seconds) is the range of register time, which is bounded by config.
+type SwapExportArgs struct {
+	Bind    string `json:"bind,omitempty"`
+	TokenID string `json:"tokenid,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Start   int64  `json:"start"`
+	End     int64  `json:"end"`
+	Format  string `json:"format"`
+}
+
+// SwapExportJob async swap export job
+type SwapExportJob struct {
+	ID         string          `json:"id"`
+	Args       *SwapExportArgs `json:"args"`
+	Status     string          `json:"status"`
+	Rows       int64           `json:"rows"`
+	Error      string          `json:"error,omitempty"`
+	CreateTime int64           `json:"createTime"`
+	FinishTime int64           `json:"finishTime,omitempty"`
+}
+
+// SwapExport prepared swap export
+type SwapExport struct {
+	Args   *SwapExportArgs
+	Count  int64
+	filter *mongodb.ScopedSwapsFilter
+}
+
+// GetExportContentType get content type of export format
+func GetExportContentType(format string) string {
+	if format == ExportFormatParquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// FileName get file name of export
+func (e *SwapExport) FileName() string {
+	return getExportFileName(e.Args)
+}
+
+func getExportHost() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return host
+}
+
+func getExportFileName(args *SwapExportArgs) string {
+	return fmt.Sprintf("swaps-%v-%v.%v", args.Start, args.End, args.Format)
+}
+
+// prepareSwapExport check args and count the swaps to export
+func prepareSwapExport(token string, args *SwapExportArgs, maxRows int64) (*SwapExport, *APIToken, error) {
+	cfg := params.GetExportConfig()
+	if cfg == nil {
+		return nil, nil, errExportDisabled
+	}
+	apiToken, err := authAPIToken(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args.Format = strings.ToLower(args.Format)
+	switch args.Format {
+	case "":
+		args.Format = ExportFormatCSV
+	case ExportFormatCSV, ExportFormatParquet:
+	default:
+		return nil, nil, newRPCError(-32000, "unknown export format "+args.Format)
+	}
+	if args.End <= 0 {
+		args.End = time.Now().Unix()
+	}
+	if args.Start <= 0 {
+		args.Start = args.End - 86400
+	}
+	if args.Start >= args.End {
+		return nil, nil, newRPCError(-32000, "start time is not before end time")
+	}
+	if args.End-args.Start > cfg.GetMaxRange() {
+		return nil, nil, newRPCError(-32000, fmt.Sprintf("time range exceeds %v seconds", cfg.GetMaxRange()))
+	}
+
+	filter := &mongodb.ScopedSwapsFilter{
+		Status:    args.Status,
+		StartTime: args.Start,
+		EndTime:   args.End,
+	}
+	if filter.Binds, err = narrowScope(apiToken.Binds, args.Bind); err != nil {
+		return nil, nil, err
+	}
+	if filter.TokenIDs, err = narrowScope(apiToken.TokenIDs, args.TokenID); err != nil {
+		return nil, nil, err
+	}
+	count, err := mongodb.CountScopedRouterSwapResults(filter)
+	if err != nil {
+		return nil, nil, newRPCInternalError(err)
+	}
+	if count > maxRows {
+		return nil, nil, newRPCError(-32000, fmt.Sprintf("too many swaps to export (%v > %v)", count, maxRows))
+	}
+	return &SwapExport{Args: args, Count: count, filter: filter}, apiToken, nil
+}
+
+// PrepareSwapExport prepare sync swap export, the swaps count is limited by config.
+// larger exports should be created as async jobs by CreateSwapExportJob.
+func PrepareSwapExport(token string, args *SwapExportArgs) (*SwapExport, error) {
+	cfg := params.GetExportConfig()
+	if cfg == nil {
+		return nil, errExportDisabled
+	}
+	export, _, err := prepareSwapExport(token, args, cfg.GetMaxSyncRows())
+	return export, err
+}
+
+// Write write the exported swaps in the format
+func (e *SwapExport) Write(w io.Writer) (rows int64, err error) {
+	if e.Args.Format == ExportFormatParquet {
+		pw := parquet.NewWriter(w, exportColumns)
+		err = mongodb.IterateScopedRouterSwapResults(e.filter, func(res *mongodb.MgoSwapResult) error {
+			return pw.Append(getSwapExportRow(res))
+		})
+		if err != nil {
+			return pw.NumRows(), err
+		}
+		return pw.NumRows(), pw.Close()
+	}
+
+	cw := csv.NewWriter(w)
+	header := make([]string, len(exportColumns))
+	for i, col := range exportColumns {
+		header[i] = col.Name
+	}
+	if err = cw.Write(header); err != nil {
+		return 0, err
+	}
+	record := make([]string, len(exportColumns))
+	err = mongodb.IterateScopedRouterSwapResults(e.filter, func(res *mongodb.MgoSwapResult) error {
+		for i, value := range getSwapExportRow(res) {
+			record[i] = fmt.Sprint(value)
+		}
+		rows++
+		return cw.Write(record)
+	})
+	if err != nil {
+		return rows, err
+	}
+	cw.Flush()
+	return rows, cw.Error()
+}
+
+func getSwapExportRow(res *mongodb.MgoSwapResult) []interface{} {
+	return []interface{}{
+		res.FromChainID,
+		res.TxID,
+		int64(res.LogIndex),
+		res.ToChainID,
+		res.GetTokenID(),
+		int64(res.SwapType),
+		res.From,
+		res.To,
+		res.Bind,
+		res.Value,
+		int64(res.TxHeight),
+		int64(res.TxTime),
+		res.SwapTx,
+		int64(res.SwapHeight),
+		int64(res.SwapTime),
+		res.SwapValue,
+		int64(res.SwapNonce),
+		res.Status.String(),
+		res.InitTime,
+		res.Timestamp,
+		res.Memo,
+	}
+}
+
+// CreateSwapExportJob create async swap export job, the file can be downloaded
+// by the same api token after the job is done.
+// the jobs are kept in mongodb, so they are shared by the api server replicas.
+func CreateSwapExportJob(token string, args *SwapExportArgs) (*SwapExportJob, error) {
+	cfg := params.GetExportConfig()
+	if cfg == nil {
+		return nil, errExportDisabled
+	}
+	export, apiToken, err := prepareSwapExport(token, args, cfg.GetMaxRows())
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 16)
+	if _, err = rand.Read(id); err != nil {
+		return nil, newRPCInternalError(err)
+	}
+	dir := cfg.GetDir()
+	if err = os.MkdirAll(dir, 0o700); err != nil {
+		return nil, newRPCInternalError(err)
+	}
+	pruneExportJobs(cfg)
+
+	now := time.Now().Unix()
+	mj := &mongodb.MgoExportJob{
+		Key:        hex.EncodeToString(id),
+		Owner:      apiToken.Key,
+		Bind:       args.Bind,
+		TokenID:    args.TokenID,
+		SwapStatus: args.Status,
+		Start:      args.Start,
+		End:        args.End,
+		Format:     args.Format,
+		Host:       exportHost,
+		Status:     mongodb.ExportJobRunning,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	mj.File = filepath.Join(dir, mj.Key+"."+args.Format)
+	added, err := mongodb.AddExportJob(mj, cfg.GetMaxJobs())
+	if err != nil {
+		return nil, newRPCInternalError(err)
+	}
+	if !added {
+		return nil, errTooManyExportJobs
+	}
+
+	log.Info("create swap export job", "id", mj.Key, "token", apiToken.Name, "args", args, "count", export.Count)
+	go runSwapExportJob(mj, export)
+	return convertExportJob(mj), nil
+}
+
+func convertExportJob(mj *mongodb.MgoExportJob) *SwapExportJob {
+	return &SwapExportJob{
+		ID: mj.Key,
+		Args: &SwapExportArgs{
+			Bind:    mj.Bind,
+			TokenID: mj.TokenID,
+			Status:  mj.SwapStatus,
+			Start:   mj.Start,
+			End:     mj.End,
+			Format:  mj.Format,
+		},
+		Status:     mj.Status,
+		Rows:       mj.Rows,
+		Error:      mj.Error,
+		CreateTime: mj.CreateTime,
+		FinishTime: mj.FinishTime,
+	}
+}
+
+func runSwapExportJob(mj *mongodb.MgoExportJob, export *SwapExport) {
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(exportHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				_ = mongodb.UpdateExportJobHeartbeat(mj.Key, time.Now().Unix())
+			}
+		}
+	}()
+
+	rows, err := writeSwapExportFile(mj.File, export)
+	close(stop)
+
+	status, errMsg := mongodb.ExportJobDone, ""
+	if err != nil {
+		_ = os.Remove(mj.File)
+		status, errMsg = mongodb.ExportJobFailed, err.Error()
+		log.Warn("swap export job failed", "id", mj.Key, "rows", rows, "err", err)
+	} else {
+		log.Info("swap export job finished", "id", mj.Key, "rows", rows)
+	}
+	_ = mongodb.FinishExportJob(mj.Key, status, rows, errMsg, time.Now().Unix())
+}
+
+func writeSwapExportFile(file string, export *SwapExport) (rows int64, err error) {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	rows, err = export.Write(f)
+	if errc := f.Close(); err == nil {
+		err = errc
+	}
+	return rows, err
+}
+
+// pruneExportJobs fail running jobs without heartbeat (their runners are stopped),
+// and remove finished jobs and export files out of retention.
+// files of this host which are not removed with their jobs are removed by modification time.
+func pruneExportJobs(cfg *params.ExportConfig) {
+	now := time.Now().Unix()
+	if count, err := mongodb.FailStaleExportJobs(now-exportStaleTimeout, now); err == nil && count > 0 {
+		log.Info("fail interrupted swap export jobs", "count", count)
+	}
+
+	expired := now - cfg.GetJobRetention()
+	jobs, err := mongodb.FindExpiredExportJobs(expired)
+	if err == nil {
+		for _, mj := range jobs {
+			if mj.Host == exportHost {
+				_ = os.Remove(mj.File)
+			}
+			_ = mongodb.RemoveExportJob(mj.Key)
+		}
+	}
+
+	entries, err := os.ReadDir(cfg.GetDir())
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if info, errf := entry.Info(); errf == nil && info.ModTime().Unix() < expired {
+			_ = os.Remove(filepath.Join(cfg.GetDir(), entry.Name()))
+		}
+	}
+}
+
+func getOwnedExportJob(token, id string) (*mongodb.MgoExportJob, error) {
+	cfg := params.GetExportConfig()
+	if cfg == nil {
+		return nil, errExportDisabled
+	}
+	apiToken, err := authAPIToken(token)
+	if err != nil {
+		return nil, err
+	}
+	pruneExportJobs(cfg)
+	mj, err := mongodb.FindExportJob(id)
+	if err != nil {
+		if errors.Is(err, mongodb.ErrItemNotFound) {
+			return nil, errExportJobNotFound
+		}
+		return nil, newRPCInternalError(err)
+	}
+	if mj.Owner != apiToken.Key {
+		return nil, errExportJobNotFound
+	}
+	return mj, nil
+}
+
+// GetSwapExportJob get swap export job created by the api token
+func GetSwapExportJob(token, id string) (*SwapExportJob, error) {
+	mj, err := getOwnedExportJob(token, id)
+	if err != nil {
+		return nil, err
+	}
+	return convertExportJob(mj), nil
+}
+
+// OpenSwapExportFile open file of done swap export job created by the api token.
+// the file is in the export dir of the host running the job, which should be
+// a shared storage if there are multiple api server replicas.
+func OpenSwapExportFile(token, id string) (*os.File, *SwapExportJob, error) {
+	mj, err := getOwnedExportJob(token, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if mj.Status != mongodb.ExportJobDone {
+		return nil, nil, errExportJobNotDone
+	}
+	f, err := os.Open(mj.File)
+	if err != nil {
+		if os.IsNotExist(err) && mj.Host != exportHost {
+			return nil, nil, newRPCError(-32000, "export file is on host "+mj.Host)
+		}
+		return nil, nil, newRPCInternalError(err)
+	}
+	return f, convertExportJob(mj), nil
+}
+
+// FileName get file name of export job
+func (j *SwapExportJob) FileName() string {
+	return getExportFileName(j.Args)
+}
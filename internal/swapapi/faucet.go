@@ -0,0 +1,305 @@
+package swapapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	errFaucetDisabled      = newRPCError(-32000, "faucet is disabled")
+	errFaucetUnauthorized  = newRPCError(-32003, "faucet request needs valid api key or captcha response")
+	errFaucetCoolingDown   = newRPCError(-32000, "faucet is requested recently, please retry later")
+	errFaucetNotSupported  = newRPCError(-32000, "faucet is not supported for this token on this chain")
+	errFaucetWrongReceiver = newRPCError(-32000, "faucet receiver is invalid")
+
+	faucetRequests     = make(map[string]int64) // requester or receiver -> last request time
+	faucetRequestsLock sync.Mutex
+
+	faucetChainLocks sync.Map // chain id -> *sync.Mutex, serialize nonces of faucet account
+
+	faucetRegisterInterval = 10 * time.Second
+	faucetRegisterTimeout  = 10 * time.Minute
+)
+
+// FaucetArgs args of faucet request
+type FaucetArgs struct {
+	ChainID   string `json:"chainid"`
+	TokenID   string `json:"tokenid"`
+	Receiver  string `json:"receiver"`  // mint receiver or bind address of swap
+	ToChainID string `json:"tochainid"` // empty means minting
+
+	APIKey   string `json:"-"`
+	Captcha  string `json:"-"`
+	RemoteIP string `json:"-"`
+}
+
+// FaucetResult result of faucet request
+type FaucetResult struct {
+	ChainID   string `json:"chainid"`
+	TokenID   string `json:"tokenid"`
+	Receiver  string `json:"receiver"`
+	ToChainID string `json:"tochainid,omitempty"`
+	Amount    string `json:"amount"`
+	MintTx    string `json:"mintTx"`
+	SwapOutTx string `json:"swapoutTx,omitempty"` // registered as swap after mined
+}
+
+// FaucetMint mint test tokens to receiver on testnet chain
+func FaucetMint(args *FaucetArgs) (*FaucetResult, error) {
+	args.ToChainID = ""
+	return faucet(args)
+}
+
+// FaucetSwap send scripted test swap, the faucet account mints test tokens to itself
+// and swaps them out to the bind address on the destination chain, the swap out tx
+// is registered after mined, then goes through the whole swap flow of the router.
+func FaucetSwap(args *FaucetArgs) (*FaucetResult, error) {
+	if args.ToChainID == "" || args.ToChainID == args.ChainID {
+		return nil, newRPCError(-32000, "faucet swap has wrong tochainid")
+	}
+	if dstBridge := router.GetBridgeByChainID(args.ToChainID); dstBridge == nil {
+		return nil, newRPCInternalError(tokens.ErrNoBridgeForChainID)
+	} else if !dstBridge.IsValidAddress(args.Receiver) {
+		return nil, errFaucetWrongReceiver
+	}
+	return faucet(args)
+}
+
+func faucet(args *FaucetArgs) (*FaucetResult, error) {
+	cfg := params.GetFaucetConfig()
+	if cfg == nil {
+		return nil, errFaucetDisabled
+	}
+	requester, err := authorizeFaucet(cfg, args)
+	if err != nil {
+		return nil, err
+	}
+	amount := cfg.GetMintAmount(args.ChainID, args.TokenID)
+	if amount == nil {
+		return nil, errFaucetNotSupported
+	}
+	bridge := router.GetBridgeByChainID(args.ChainID)
+	if bridge == nil {
+		return nil, newRPCInternalError(tokens.ErrNoBridgeForChainID)
+	}
+	if _, ok := bridge.(tokens.FaucetBridge); !ok {
+		return nil, errFaucetNotSupported
+	}
+	token := router.GetCachedMultichainToken(args.TokenID, args.ChainID)
+	if token == "" {
+		return nil, errFaucetNotSupported
+	}
+	isSwap := args.ToChainID != ""
+	if !isSwap && !bridge.IsValidAddress(args.Receiver) {
+		return nil, errFaucetWrongReceiver
+	}
+
+	receiverKey := strings.ToLower(args.ChainID + ":" + args.ToChainID + ":" + args.Receiver)
+	if !acquireFaucet(cfg.GetCoolDown(), requester, receiverKey) {
+		return nil, errFaucetCoolingDown
+	}
+	result, err := sendFaucetTxs(cfg, bridge, token, amount, args)
+	if err != nil {
+		if result == nil || result.MintTx == "" {
+			releaseFaucet(requester, receiverKey) // nothing is sent
+		}
+		log.Warn("[faucet] request failed", "chainid", args.ChainID, "tokenid", args.TokenID, "receiver", args.Receiver, "tochainid", args.ToChainID, "err", err)
+		return nil, newRPCInternalError(err)
+	}
+	log.Info("[faucet] request success", "chainid", args.ChainID, "tokenid", args.TokenID, "receiver", args.Receiver, "tochainid", args.ToChainID, "amount", amount, "mintTx", result.MintTx, "swapoutTx", result.SwapOutTx)
+	if isSwap {
+		go registerFaucetSwap(bridge, args.ChainID, result.SwapOutTx)
+	}
+	return result, nil
+}
+
+// authorizeFaucet authorize faucet request by api key or captcha, returns the requester
+func authorizeFaucet(cfg *params.FaucetConfig, args *FaucetArgs) (string, error) {
+	if args.APIKey != "" {
+		if !cfg.IsAPIKey(args.APIKey) {
+			return "", errFaucetUnauthorized
+		}
+		hash := sha256.Sum256([]byte(args.APIKey))
+		return "key:" + hex.EncodeToString(hash[:]), nil
+	}
+	if args.Captcha == "" || cfg.CaptchaSecret == "" {
+		return "", errFaucetUnauthorized
+	}
+	if err := verifyCaptcha(cfg, args.Captcha, args.RemoteIP); err != nil {
+		log.Info("[faucet] verify captcha failed", "ip", args.RemoteIP, "err", err)
+		return "", errFaucetUnauthorized
+	}
+	return "ip:" + args.RemoteIP, nil
+}
+
+// verifyCaptcha verify captcha response by the siteverify api (of hcaptcha or recaptcha)
+func verifyCaptcha(cfg *params.FaucetConfig, response, remoteIP string) error {
+	form := url.Values{}
+	form.Set("secret", cfg.CaptchaSecret)
+	form.Set("response", response)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	resp, err := client.HTTPRawPost(cfg.GetCaptchaVerifyURL(), form.Encode(), nil, nil, 10)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err = json.Unmarshal(body, &result); err != nil {
+		return err
+	}
+	if !result.Success {
+		return newRPCError(-32003, "captcha is not passed: "+strings.Join(result.ErrorCodes, ","))
+	}
+	return nil
+}
+
+// acquireFaucet returns false if the requester or receiver is cooling down
+func acquireFaucet(coolDown int64, keys ...string) bool {
+	faucetRequestsLock.Lock()
+	defer faucetRequestsLock.Unlock()
+
+	now := time.Now().Unix()
+	for key, last := range faucetRequests {
+		if now-last >= coolDown {
+			delete(faucetRequests, key)
+		}
+	}
+	for _, key := range keys {
+		if _, exist := faucetRequests[key]; exist {
+			return false
+		}
+	}
+	for _, key := range keys {
+		faucetRequests[key] = now
+	}
+	return true
+}
+
+// releaseFaucet release the cool down of failed request
+func releaseFaucet(keys ...string) {
+	faucetRequestsLock.Lock()
+	defer faucetRequestsLock.Unlock()
+	for _, key := range keys {
+		delete(faucetRequests, key)
+	}
+}
+
+func getFaucetChainLock(chainID string) *sync.Mutex {
+	lock, _ := faucetChainLocks.LoadOrStore(chainID, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+func sendFaucetTxs(cfg *params.FaucetConfig, bridge tokens.IBridge, token string, amount *big.Int, args *FaucetArgs) (result *FaucetResult, err error) {
+	var toChainID *big.Int
+	if args.ToChainID != "" {
+		toChainID, err = common.GetBigIntFromStr(args.ToChainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	faucetBridge := bridge.(tokens.FaucetBridge)
+	priKey := cfg.GetPrivateKey(args.ChainID)
+	faucetAddr, err := faucetBridge.GetFaucetAddress(priKey)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := getFaucetChainLock(args.ChainID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	nonce, err := faucetBridge.GetPoolNonce(faucetAddr, "pending")
+	if err != nil {
+		return nil, err
+	}
+	result = &FaucetResult{
+		ChainID:   args.ChainID,
+		TokenID:   args.TokenID,
+		Receiver:  args.Receiver,
+		ToChainID: args.ToChainID,
+		Amount:    amount.String(),
+	}
+
+	mintArgs := &tokens.FaucetTxArgs{
+		Faucet:   faucetAddr,
+		Token:    token,
+		Receiver: args.Receiver,
+		Amount:   amount,
+		Nonce:    nonce,
+	}
+	if toChainID != nil {
+		mintArgs.Receiver = faucetAddr
+	}
+	result.MintTx, err = sendFaucetTx(bridge, mintArgs, priKey)
+	if err != nil || toChainID == nil {
+		return result, err
+	}
+
+	// the swap out is sent before the minting is mined, gas can not be estimated
+	result.SwapOutTx, err = sendFaucetTx(bridge, &tokens.FaucetTxArgs{
+		Faucet:    faucetAddr,
+		Token:     token,
+		Receiver:  args.Receiver,
+		Amount:    amount,
+		ToChainID: toChainID,
+		Nonce:     nonce + 1,
+		GasLimit:  cfg.GetSwapOutGasLimit(),
+	}, priKey)
+	return result, err
+}
+
+func sendFaucetTx(bridge tokens.IBridge, args *tokens.FaucetTxArgs, priKey string) (string, error) {
+	faucetBridge := bridge.(tokens.FaucetBridge)
+	rawTx, err := faucetBridge.BuildFaucetTx(args)
+	if err != nil {
+		return "", err
+	}
+	signedTx, _, err := faucetBridge.SignTransactionWithPrivateKey(rawTx, priKey)
+	if err != nil {
+		return "", err
+	}
+	return bridge.SendTransaction(signedTx)
+}
+
+// registerFaucetSwap register the swap out tx of scripted test swap after mined
+func registerFaucetSwap(bridge tokens.IBridge, chainID, txid string) {
+	deadline := time.Now().Add(faucetRegisterTimeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(faucetRegisterInterval)
+		status, err := bridge.GetTransactionStatus(txid)
+		if err != nil || status == nil || status.BlockHeight == 0 {
+			continue
+		}
+		_, err = RegisterRouterSwap(chainID, txid, "0", "faucet")
+		if err != nil && err != errAlreadyRegistered {
+			log.Warn("[faucet] register swap failed", "chainid", chainID, "txid", txid, "err", err)
+			return
+		}
+		log.Info("[faucet] register swap success", "chainid", chainID, "txid", txid)
+		return
+	}
+	log.Warn("[faucet] register swap timeout", "chainid", chainID, "txid", txid)
+}
@@ -0,0 +1,85 @@
+package swapapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+)
+
+func setupLongPoll(getSwap func(fromChainID, txid, logindexStr string) (*SwapInfo, error)) func() {
+	oldGetSwap, oldInterval := getLongPollRouterSwap, longPollInterval
+	getLongPollRouterSwap = getSwap
+	longPollInterval = 10 * time.Millisecond
+	return func() {
+		getLongPollRouterSwap = oldGetSwap
+		longPollInterval = oldInterval
+	}
+}
+
+func TestWaitRouterSwap(t *testing.T) {
+	var polls int
+	statuses := []mongodb.SwapStatus{mongodb.TxNotStable, mongodb.MatchTxNotStable, mongodb.MatchTxStable}
+	defer setupLongPoll(func(fromChainID, txid, logindexStr string) (*SwapInfo, error) {
+		polls++
+		if polls == 1 {
+			// not registered yet
+			return nil, mongodb.ErrSwapNotFound
+		}
+		status := statuses[len(statuses)-1]
+		if polls-2 < len(statuses) {
+			status = statuses[polls-2]
+		}
+		return &SwapInfo{TxID: txid, Status: status}, nil
+	})()
+
+	res, err := WaitRouterSwap(context.Background(), "1", "0x01", "0", time.Second)
+	if err != nil || res.Status != mongodb.MatchTxStable || polls != 4 {
+		t.Errorf("wait swap to final status got (%+v, %v) after %v polls, want %v after 4 polls", res, err, polls, mongodb.MatchTxStable)
+	}
+}
+
+func TestWaitRouterSwapTimeout(t *testing.T) {
+	defer setupLongPoll(func(fromChainID, txid, logindexStr string) (*SwapInfo, error) {
+		return &SwapInfo{TxID: txid, Status: mongodb.MatchTxNotStable}, nil
+	})()
+	// the latest status is returned on timeout
+	res, err := WaitRouterSwap(context.Background(), "1", "0x01", "0", 50*time.Millisecond)
+	if err != nil || res == nil || res.Status != mongodb.MatchTxNotStable {
+		t.Errorf("wait swap timeout got (%+v, %v), want status %v", res, err, mongodb.MatchTxNotStable)
+	}
+
+	// and the waiting is stopped if the request is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if res, err = WaitRouterSwap(ctx, "1", "0x01", "0", 0); err != nil || res.Status != mongodb.MatchTxNotStable {
+		t.Errorf("wait swap of canceled request got (%+v, %v)", res, err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("wait swap of canceled request is not stopped")
+	}
+}
+
+func TestWaitRouterSwapError(t *testing.T) {
+	errTest := errors.New("wrong log index")
+	var polls int
+	defer setupLongPoll(func(fromChainID, txid, logindexStr string) (*SwapInfo, error) {
+		polls++
+		return nil, errTest
+	})()
+	// other errors than not found are returned at once
+	if _, err := WaitRouterSwap(context.Background(), "1", "0x01", "x", time.Second); !errors.Is(err, errTest) || polls != 1 {
+		t.Errorf("wait swap with error got %v after %v polls, want %v after 1 poll", err, polls, errTest)
+	}
+
+	// not found swaps are waited until timeout
+	defer setupLongPoll(func(fromChainID, txid, logindexStr string) (*SwapInfo, error) {
+		return nil, mongodb.ErrSwapNotFound
+	})()
+	if _, err := WaitRouterSwap(context.Background(), "1", "0x01", "0", 50*time.Millisecond); !errors.Is(err, mongodb.ErrSwapNotFound) {
+		t.Errorf("wait not found swap got %v, want %v", err, mongodb.ErrSwapNotFound)
+	}
+}
@@ -0,0 +1,170 @@
+package swapapi
+
+import (
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// sandbox scenarios
+const (
+	sandboxScenarioSuccess      = "success"
+	sandboxScenarioVerifyFailed = "verifyfailed"
+	sandboxScenarioSwapFailed   = "swapfailed"
+)
+
+var (
+	// simulated status sequence of each scenario, the last one is final
+	sandboxTimelines = map[string][]mongodb.SwapStatus{
+		sandboxScenarioSuccess:      {mongodb.TxNotStable, mongodb.TxNotSwapped, mongodb.MatchTxNotStable, mongodb.MatchTxStable},
+		sandboxScenarioVerifyFailed: {mongodb.TxNotStable, mongodb.TxVerifyFailed},
+		sandboxScenarioSwapFailed:   {mongodb.TxNotStable, mongodb.TxNotSwapped, mongodb.MatchTxNotStable, mongodb.MatchTxFailed},
+	}
+
+	sandboxSwaps     = make(map[string]*sandboxSwap) // key is router swap key
+	sandboxSwapKeys  []string                        // in register order, for evicting
+	sandboxSwapsLock sync.RWMutex
+
+	errSandboxDisabled        = newRPCError(-32000, "sandbox is disabled")
+	errUnknownSandboxScenario = newRPCError(-32000, "unknown sandbox scenario")
+
+	sandboxDefaultValue = "1000000000000000000"
+)
+
+// SandboxSwapArgs args of registering sandbox swap
+type SandboxSwapArgs struct {
+	ChainID   string `json:"chainid"`
+	TxID      string `json:"txid"`
+	LogIndex  string `json:"logindex"`
+	ToChainID string `json:"tochainid"`
+	Bind      string `json:"bind"`
+	TokenID   string `json:"tokenid"`
+	Value     string `json:"value"`
+	Scenario  string `json:"scenario"`
+}
+
+type sandboxSwap struct {
+	args     *SandboxSwapArgs
+	logIndex int
+	initTime int64 // milli seconds
+}
+
+// SandboxRegisterSwap register sandbox swap, which progresses through
+// the simulated states of the scenario without touching chains or mpc.
+func SandboxRegisterSwap(args *SandboxSwapArgs) (*MapIntResult, error) {
+	cfg := params.GetSandboxConfig()
+	if cfg == nil {
+		return nil, errSandboxDisabled
+	}
+	logIndex, err := getLogIndex(args.LogIndex)
+	if err != nil {
+		return nil, err
+	}
+	if args.Scenario == "" {
+		args.Scenario = sandboxScenarioSuccess
+	}
+	if _, exist := sandboxTimelines[args.Scenario]; !exist {
+		return nil, errUnknownSandboxScenario
+	}
+	if args.Value == "" {
+		args.Value = sandboxDefaultValue
+	} else if _, err = common.GetBigIntFromStr(args.Value); err != nil {
+		return nil, newRPCInternalError(err)
+	}
+
+	key := mongodb.GetRouterSwapKey(args.ChainID, args.TxID, logIndex)
+	result := MapIntResult(make(map[int]string))
+
+	sandboxSwapsLock.Lock()
+	defer sandboxSwapsLock.Unlock()
+
+	if _, exist := sandboxSwaps[key]; exist {
+		return nil, errAlreadyRegistered
+	}
+	for len(sandboxSwapKeys) >= cfg.GetMaxSwaps() {
+		delete(sandboxSwaps, sandboxSwapKeys[0])
+		sandboxSwapKeys = sandboxSwapKeys[1:]
+	}
+	sandboxSwaps[key] = &sandboxSwap{
+		args:     args,
+		logIndex: logIndex,
+		initTime: common.NowMilli(),
+	}
+	sandboxSwapKeys = append(sandboxSwapKeys, key)
+	log.Info("[sandbox] register swap", "chainid", args.ChainID, "txid", args.TxID, "logIndex", logIndex, "scenario", args.Scenario)
+
+	result[logIndex] = "success"
+	return &result, nil
+}
+
+// SandboxGetSwap get sandbox swap with its currently simulated state
+func SandboxGetSwap(fromChainID, txid, logindexStr string) (*SwapInfo, error) {
+	cfg := params.GetSandboxConfig()
+	if cfg == nil {
+		return nil, errSandboxDisabled
+	}
+	logIndex, err := getLogIndex(logindexStr)
+	if err != nil {
+		return nil, err
+	}
+	key := mongodb.GetRouterSwapKey(fromChainID, txid, logIndex)
+
+	sandboxSwapsLock.RLock()
+	swap, exist := sandboxSwaps[key]
+	sandboxSwapsLock.RUnlock()
+	if !exist {
+		return nil, mongodb.ErrSwapNotFound
+	}
+	return swap.simulate(common.NowMilli(), cfg.GetStepInterval()*1000), nil
+}
+
+// simulate the swap state at nowMilli, each state lasts stepMilli
+func (s *sandboxSwap) simulate(nowMilli, stepMilli int64) *SwapInfo {
+	args := s.args
+	timeline := sandboxTimelines[args.Scenario]
+	elapsed := nowMilli - s.initTime
+	stage := int(elapsed / stepMilli)
+	if stage >= len(timeline) {
+		stage = len(timeline) - 1
+	}
+	status := timeline[stage]
+	stageTime := s.initTime + int64(stage)*stepMilli
+
+	info := &SwapInfo{
+		TxID:        args.TxID,
+		TxHeight:    uint64(s.initTime / 1000),
+		From:        args.Bind,
+		To:          args.Bind,
+		Bind:        args.Bind,
+		Value:       args.Value,
+		LogIndex:    s.logIndex,
+		FromChainID: args.ChainID,
+		ToChainID:   args.ToChainID,
+		SwapInfo: mongodb.SwapInfo{
+			ERC20SwapInfo: &mongodb.ERC20SwapInfo{TokenID: args.TokenID},
+		},
+		Status:    status,
+		StatusMsg: status.String(),
+		InitTime:  s.initTime,
+		Timestamp: stageTime / 1000,
+	}
+
+	switch status {
+	case mongodb.TxVerifyFailed:
+		info.Memo = "sandbox: simulated verify failure"
+	case mongodb.MatchTxNotStable, mongodb.MatchTxStable, mongodb.MatchTxFailed:
+		swapTime := s.initTime + 2*stepMilli
+		info.SwapTx = common.Keccak256Hash([]byte("sandbox:" + mongodb.GetRouterSwapKey(args.ChainID, args.TxID, s.logIndex))).Hex()
+		info.SwapHeight = uint64(swapTime / 1000)
+		info.SwapValue = args.Value
+		info.SwapNonce = 1
+		info.Confirmations = uint64((nowMilli - swapTime) / 1000)
+		if status == mongodb.MatchTxFailed {
+			info.Memo = "sandbox: simulated swap tx failure"
+		}
+	}
+	return info
+}
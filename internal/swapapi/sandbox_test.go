@@ -0,0 +1,122 @@
+package swapapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+func setupSandbox(sandbox *params.SandboxConfig) func() {
+	cfg := params.GetRouterConfig()
+	oldServer := cfg.Server
+	cfg.Server = &params.RouterServerConfig{
+		APIServer: &params.APIServerConfig{Sandbox: sandbox},
+	}
+	return func() {
+		cfg.Server = oldServer
+		sandboxSwapsLock.Lock()
+		sandboxSwaps = make(map[string]*sandboxSwap)
+		sandboxSwapKeys = nil
+		sandboxSwapsLock.Unlock()
+	}
+}
+
+func TestSandboxDisabled(t *testing.T) {
+	defer setupSandbox(&params.SandboxConfig{Enable: false})()
+
+	args := &SandboxSwapArgs{ChainID: "1", TxID: "0x01", ToChainID: "56"}
+	if _, err := SandboxRegisterSwap(args); !errors.Is(err, errSandboxDisabled) {
+		t.Errorf("register sandbox swap got error %v, want %v", err, errSandboxDisabled)
+	}
+	if _, err := SandboxGetSwap("1", "0x01", "0"); !errors.Is(err, errSandboxDisabled) {
+		t.Errorf("get sandbox swap got error %v, want %v", err, errSandboxDisabled)
+	}
+}
+
+func TestSandboxRegisterSwap(t *testing.T) {
+	defer setupSandbox(&params.SandboxConfig{Enable: true, MaxSwaps: 2})()
+
+	args := &SandboxSwapArgs{ChainID: "1", TxID: "0x01", LogIndex: "1", ToChainID: "56", Bind: "0x2222", TokenID: "USDC"}
+	if res, err := SandboxRegisterSwap(args); err != nil || (*res)[1] != "success" {
+		t.Fatalf("register sandbox swap got (%v, %v)", res, err)
+	}
+	if args.Scenario != sandboxScenarioSuccess || args.Value != sandboxDefaultValue {
+		t.Errorf("register sandbox swap got scenario %v and value %v, want defaults", args.Scenario, args.Value)
+	}
+	swap, err := SandboxGetSwap("1", "0x01", "1")
+	if err != nil || swap.Status != mongodb.TxNotStable || swap.SwapInfo.ERC20SwapInfo.TokenID != "USDC" || swap.SwapTx != "" {
+		t.Errorf("get new sandbox swap got (%+v, %v)", swap, err)
+	}
+
+	tests := []struct {
+		args    *SandboxSwapArgs
+		wantErr error
+	}{
+		{&SandboxSwapArgs{ChainID: "1", TxID: "0x01", LogIndex: "1"}, errAlreadyRegistered},
+		{&SandboxSwapArgs{ChainID: "1", TxID: "0x02", Scenario: "timeout"}, errUnknownSandboxScenario},
+	}
+	for i, test := range tests {
+		if _, err := SandboxRegisterSwap(test.args); !errors.Is(err, test.wantErr) {
+			t.Errorf("test %v: register sandbox swap got error %v, want %v", i, err, test.wantErr)
+		}
+	}
+	for _, bad := range []*SandboxSwapArgs{
+		{ChainID: "1", TxID: "0x03", Value: "abc"},
+		{ChainID: "1", TxID: "0x03", LogIndex: "-1"},
+	} {
+		if _, err := SandboxRegisterSwap(bad); err == nil {
+			t.Errorf("register sandbox swap with %+v should fail", bad)
+		}
+	}
+	if _, err := SandboxGetSwap("1", "0x09", "0"); !errors.Is(err, mongodb.ErrSwapNotFound) {
+		t.Errorf("get unregistered sandbox swap got error %v, want %v", err, mongodb.ErrSwapNotFound)
+	}
+
+	// the oldest swaps are evicted when max swaps is reached
+	for _, txid := range []string{"0x04", "0x05"} {
+		if _, err := SandboxRegisterSwap(&SandboxSwapArgs{ChainID: "1", TxID: txid}); err != nil {
+			t.Fatalf("register sandbox swap %v failed: %v", txid, err)
+		}
+	}
+	if _, err := SandboxGetSwap("1", "0x01", "1"); !errors.Is(err, mongodb.ErrSwapNotFound) {
+		t.Errorf("get evicted sandbox swap got error %v, want %v", err, mongodb.ErrSwapNotFound)
+	}
+	if _, err := SandboxGetSwap("1", "0x05", "0"); err != nil {
+		t.Errorf("get latest sandbox swap failed: %v", err)
+	}
+}
+
+func TestSandboxSimulate(t *testing.T) {
+	const step = int64(10000)
+	tests := []struct {
+		scenario string
+		elapsed  int64
+		status   mongodb.SwapStatus
+		swapped  bool
+		memo     bool
+	}{
+		{sandboxScenarioSuccess, 0, mongodb.TxNotStable, false, false},
+		{sandboxScenarioSuccess, step, mongodb.TxNotSwapped, false, false},
+		{sandboxScenarioSuccess, 2 * step, mongodb.MatchTxNotStable, true, false},
+		{sandboxScenarioSuccess, 100 * step, mongodb.MatchTxStable, true, false},
+		{sandboxScenarioVerifyFailed, step - 1, mongodb.TxNotStable, false, false},
+		{sandboxScenarioVerifyFailed, 100 * step, mongodb.TxVerifyFailed, false, true},
+		{sandboxScenarioSwapFailed, 3 * step, mongodb.MatchTxFailed, true, true},
+	}
+	for i, test := range tests {
+		swap := &sandboxSwap{
+			args:     &SandboxSwapArgs{ChainID: "1", TxID: "0x01", ToChainID: "56", Value: "1000", Scenario: test.scenario},
+			initTime: 1700000000000,
+		}
+		info := swap.simulate(swap.initTime+test.elapsed, step)
+		if info.Status != test.status || (info.SwapTx != "") != test.swapped || (info.Memo != "") != test.memo {
+			t.Errorf("test %v: simulate %v swap after %v got status %v, swaptx %q, memo %q",
+				i, test.scenario, test.elapsed, info.Status, info.SwapTx, info.Memo)
+		}
+		if test.swapped && (info.SwapValue != "1000" || info.Confirmations != uint64((test.elapsed-2*step)/1000)) {
+			t.Errorf("test %v: simulate swapped swap got swap value %v, confirmations %v", i, info.SwapValue, info.Confirmations)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package swapapi
+
+import "testing"
+
+func TestGetSwapSnapshotWrongArgs(t *testing.T) {
+	tests := []struct {
+		logIndex  string
+		timestamp int64
+	}{
+		{"0", 0},
+		{"0", -1},
+		{"-1", 1700000000000},
+		{"abc", 1700000000000},
+	}
+	for i, test := range tests {
+		if _, err := GetSwapSnapshot("1", "0x01", test.logIndex, test.timestamp); err == nil {
+			t.Errorf("test %v: get swap snapshot with log index %v timestamp %v, want error", i, test.logIndex, test.timestamp)
+		}
+	}
+}
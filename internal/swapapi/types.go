@@ -5,6 +5,8 @@ import (
 
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/worker"
 )
 
 // MapIntResult type
@@ -17,6 +19,76 @@ type ServerInfo struct {
 	ExtraConfig    *params.ExtraConfig `json:",omitempty"`
 	AllChainIDs    []*big.Int
 	PausedChainIDs []*big.Int `json:",omitempty"`
+	IsWatcher      bool       `json:",omitempty"`
+
+	DiscardedSignCount uint64   `json:",omitempty"`
+	StaleHeadChainIDs  []string `json:",omitempty"`
+	CrashLoopJobs      []string `json:",omitempty"`
+}
+
+// DBProfiles database operation profiles
+type DBProfiles struct {
+	LatencyBuckets []int64                      `json:"latencyBuckets"`
+	Collections    []*mongodb.CollectionProfile `json:"collections"`
+}
+
+// SwapReceipt signed completion receipt of swap
+type SwapReceipt = mongodb.MgoSwapReceipt
+
+// SwapRejection rejection record of swap failed verification
+type SwapRejection = mongodb.MgoSwapRejection
+
+// ShadowReport shadow mode comparison report
+type ShadowReport struct {
+	Compared    uint64                     `json:"compared"`
+	Divergent   uint64                     `json:"divergent"`
+	Divergences []*worker.ShadowDivergence `json:"divergences"`
+}
+
+// SLOReport service level report for public status pages
+type SLOReport = worker.SLOReport
+
+// IntegrityReport consistency audit report of swap and swap result collections
+type IntegrityReport = mongodb.IntegrityReport
+
+// ClaimSwap claim status of two-phase swap
+type ClaimSwap = mongodb.MgoClaimSwap
+
+// TokenMigrationReport status of token contract address migration
+type TokenMigrationReport = tokens.TokenMigrationReport
+
+// SwapTimeline lifecycle timeline of swap
+type SwapTimeline = worker.SwapTimeline
+
+// SwapTransition status transition of swap
+type SwapTransition = mongodb.MgoSwapTransition
+
+// SwapSnapshot swap info at the time, replayed from the swap history
+type SwapSnapshot struct {
+	Timestamp int64     `json:"timestamp"` // milli seconds
+	Replayed  int       `json:"replayed"`  // count of replayed history records
+	SwapInfo  *SwapInfo `json:"swapInfo"`
+}
+
+// StuckSwapsFilter filter of finding stuck swaps
+type StuckSwapsFilter = mongodb.StuckSwapsFilter
+
+// SwapStatsFilter filter of finding swap stats
+type SwapStatsFilter = mongodb.SwapStatsFilter
+
+// SwapStat stats of finalized swaps of chain pair and token in time bucket
+type SwapStat = mongodb.MgoSwapStat
+
+// MPCUsageFilter filter of finding mpc usage
+type MPCUsageFilter = mongodb.MPCUsageFilter
+
+// MPCUsage mpc sign usage of chain in time bucket
+type MPCUsage = mongodb.MgoMPCUsage
+
+// TimeLockedSwaps pending swaps delayed by time lock policy
+type TimeLockedSwaps struct {
+	Delay int64                    `json:"delay"`
+	Swaps []*worker.TimeLockedSwap `json:"swaps"`
 }
 
 // OracleInfo oracle info
@@ -27,29 +99,32 @@ type OracleInfo struct {
 
 // SwapInfo swap info
 type SwapInfo struct {
-	SwapType      uint32             `json:"swaptype"`
-	TxID          string             `json:"txid"`
-	TxTo          string             `json:"txto,omitempty"`
-	TxHeight      uint64             `json:"txheight"`
-	From          string             `json:"from"`
-	To            string             `json:"to"`
-	Bind          string             `json:"bind"`
-	Value         string             `json:"value"`
-	LogIndex      int                `json:"logIndex,omitempty"`
-	FromChainID   string             `json:"fromChainID"`
-	ToChainID     string             `json:"toChainID"`
-	SwapInfo      mongodb.SwapInfo   `json:"swapinfo"`
-	SwapTx        string             `json:"swaptx"`
-	SwapHeight    uint64             `json:"swapheight"`
-	SwapValue     string             `json:"swapvalue"`
-	SwapNonce     uint64             `json:"swapnonce"`
-	Status        mongodb.SwapStatus `json:"status"`
-	StatusMsg     string             `json:"statusmsg"`
-	InitTime      int64              `json:"inittime"`
-	Timestamp     int64              `json:"timestamp"`
-	Memo          string             `json:"memo,omitempty"`
-	ReplaceCount  int                `json:"replaceCount,omitempty"`
-	Confirmations uint64             `json:"confirmations"`
+	SwapType         uint32             `json:"swaptype"`
+	TxID             string             `json:"txid"`
+	TxTo             string             `json:"txto,omitempty"`
+	TxHeight         uint64             `json:"txheight"`
+	From             string             `json:"from"`
+	To               string             `json:"to"`
+	Bind             string             `json:"bind"`
+	Value            string             `json:"value"`
+	ValueDisplay     string             `json:"valueDisplay,omitempty"`
+	LogIndex         int                `json:"logIndex,omitempty"`
+	FromChainID      string             `json:"fromChainID"`
+	ToChainID        string             `json:"toChainID"`
+	SwapInfo         mongodb.SwapInfo   `json:"swapinfo"`
+	SwapTx           string             `json:"swaptx"`
+	SwapHeight       uint64             `json:"swapheight"`
+	SwapValue        string             `json:"swapvalue"`
+	SwapValueDisplay string             `json:"swapvalueDisplay,omitempty"`
+	SwapNonce        uint64             `json:"swapnonce"`
+	SwapFee          string             `json:"swapfee,omitempty"`
+	Status           mongodb.SwapStatus `json:"status"`
+	StatusMsg        string             `json:"statusmsg"`
+	InitTime         int64              `json:"inittime"`
+	Timestamp        int64              `json:"timestamp"`
+	Memo             string             `json:"memo,omitempty"`
+	ReplaceCount     int                `json:"replaceCount,omitempty"`
+	Confirmations    uint64             `json:"confirmations"`
 }
 
 // ChainConfig rpc type
@@ -86,3 +161,23 @@ type FeeConfig struct {
 	MaximumSwapFee        string
 	MinimumSwapFee        string
 }
+
+// APIToken delegated read-only api token (without secret)
+type APIToken = mongodb.MgoAPIToken
+
+// MintedAPIToken minted api token with its secret,
+// the secret is only returned once on minting.
+type MintedAPIToken struct {
+	*APIToken
+	Token string `json:"token"`
+}
+
+// ScopedSwapHistoryArgs args of getting swap history in scope of api token.
+// bind and tokenid narrow the scope further, and must be in the scope if specified.
+type ScopedSwapHistoryArgs struct {
+	Bind    string `json:"bind"`
+	TokenID string `json:"tokenid"`
+	Offset  int    `json:"offset"`
+	Limit   int    `json:"limit"`
+	Status  string `json:"status"`
+}
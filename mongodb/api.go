@@ -51,6 +51,9 @@ func AddRouterSwap(ms *MgoSwap) error {
 	switch {
 	case err == nil:
 		log.Info("mongodb add router swap success", "chainid", ms.FromChainID, "txid", ms.TxID, "logindex", ms.LogIndex)
+		mirrorDocs(collRouterSwap, ms.Key)
+		enqueueSwap(ms.Key, ms.Status, ms.Timestamp, ms.InitTime)
+		addSwapTransition(collRouterSwap, ms.Key, nil, ms.Status, ActorRouter, ms.Memo, getHistoryChanges(ms))
 	case !mongo.IsDuplicateKeyError(err):
 		log.Error("mongodb add router swap failed", "chainid", ms.FromChainID, "txid", ms.TxID, "logindex", ms.LogIndex, "err", err)
 	default:
@@ -59,7 +62,12 @@ func AddRouterSwap(ms *MgoSwap) error {
 		if errt == nil && swap.Status == TxNotSwapped {
 			now := time.Now().Unix()
 			if swap.Timestamp+3*24*3600 < now {
-				_, _ = collRouterSwap.UpdateByID(clientCtx, ms.Key, bson.M{"$set": bson.M{"timestamp": now}})
+				_, errt = collRouterSwap.UpdateByID(clientCtx, ms.Key, bson.M{"$set": bson.M{"timestamp": now}})
+				if errt == nil {
+					mirrorDocs(collRouterSwap, ms.Key)
+					addSwapChanges(collRouterSwap, ms.Key, bson.M{"timestamp": now}, ActorRouter, "registered again")
+					enqueueSwap(ms.Key, TxNotSwapped, now, swap.InitTime)
+				}
 			}
 		}
 	}
@@ -81,9 +89,11 @@ func PassRouterSwapVerify(fromChainID, txid string, logindex int, timestamp int6
 
 	key := GetRouterSwapKey(fromChainID, txid, logindex)
 	updates := bson.M{"status": TxNotSwapped, "timestamp": timestamp}
-	_, err = collRouterSwap.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	_, err = updateSwapStatusByID(collRouterSwap, key, nil, updates, ActorRouter, "verify passed")
 	if err == nil {
 		log.Info("mongodb pass verify success", "chainid", fromChainID, "txid", txid, "logindex", logindex)
+		mirrorDocs(collRouterSwap, key)
+		enqueueSwap(key, TxNotSwapped, timestamp, swap.InitTime)
 	} else {
 		log.Error("mongodb pass verify failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "err", err)
 	}
@@ -96,6 +106,8 @@ func UpdateRouterSwapHeight(fromChainID, txid string, logindex int, height uint6
 	updates := bson.M{"txheight": height}
 	_, err := collRouterSwap.UpdateByID(clientCtx, key, bson.M{"$set": updates})
 	if err == nil {
+		mirrorDocs(collRouterSwap, key)
+		addSwapChanges(collRouterSwap, key, updates, ActorRouter, "tx height updated")
 		log.Info("mongodb update router swap height success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "txheight", height)
 	} else {
 		log.Error("mongodb update router swap height failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "txheight", height, "err", err)
@@ -103,8 +115,30 @@ func UpdateRouterSwapHeight(fromChainID, txid string, logindex int, height uint6
 	return mgoError(err)
 }
 
+// UpdateRouterSwapAttestation update attestation of anycall swap (usdc cctp)
+// in both router swap and swap result
+func UpdateRouterSwapAttestation(fromChainID, txid string, logindex int, attestation string) error {
+	key := GetRouterSwapKey(fromChainID, txid, logindex)
+	updates := bson.M{"swapinfo.anycallSwapInfo2.attestation": attestation}
+	for _, coll := range []*mongo.Collection{collRouterSwap, collRouterSwapResult} {
+		_, err := coll.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+		if err != nil {
+			log.Error("mongodb update router swap attestation failed", "coll", coll.Name(), "key", key, "err", err)
+			return mgoError(err)
+		}
+		mirrorDocs(coll, key)
+		addSwapChanges(coll, key, updates, ActorRouter, "attestation updated")
+	}
+	log.Info("mongodb update router swap attestation success", "chainid", fromChainID, "txid", txid, "logindex", logindex)
+	return nil
+}
+
 // UpdateRouterSwapStatus update router swap status
 func UpdateRouterSwapStatus(fromChainID, txid string, logindex int, status SwapStatus, timestamp int64, memo string) error {
+	return updateRouterSwapStatus(fromChainID, txid, logindex, status, timestamp, memo, ActorRouter)
+}
+
+func updateRouterSwapStatus(fromChainID, txid string, logindex int, status SwapStatus, timestamp int64, memo, actor string) error {
 	if status == TxNotStable {
 		return errors.New("forbid update swap status to TxNotStable")
 	}
@@ -115,10 +149,12 @@ func UpdateRouterSwapStatus(fromChainID, txid string, logindex int, status SwapS
 	} else if status == TxNotSwapped {
 		updates["memo"] = ""
 	}
-	_, err := collRouterSwap.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	_, err := updateSwapStatusByID(collRouterSwap, key, nil, updates, actor, memo)
 	if err == nil {
 		logFunc := log.GetPrintFuncOr(func() bool { return status == TxVerifyFailed }, log.Warn, log.Info)
 		logFunc("mongodb update router swap status success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status)
+		mirrorDocs(collRouterSwap, key)
+		enqueueSwap(key, status, timestamp, 0)
 	} else {
 		log.Error("mongodb update router swap status failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status, "err", err)
 	}
@@ -154,9 +190,11 @@ func UpdateRouterSwapInfoAndStatus(fromChainID, txid string, logindex int, swapI
 		"memo":      memo,
 	}
 
-	_, err = collRouterSwap.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	_, err = updateSwapStatusByID(collRouterSwap, key, nil, updates, ActorRouter, memo)
 	if err == nil {
 		log.Info("mongodb update router swap info and status success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status, "swapinfo", swapInfo)
+		mirrorDocs(collRouterSwap, key)
+		enqueueSwap(key, status, timestamp, timestamp*1000)
 	} else {
 		log.Error("mongodb update router swap info and status failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status, "swapinfo", swapInfo, "err", err)
 	}
@@ -216,6 +254,9 @@ func getStatusQueryWithChainID(fromChainID string, status SwapStatus, septime in
 
 // FindRouterSwapsWithStatus find router swap with status
 func FindRouterSwapsWithStatus(status SwapStatus, septime int64) ([]*MgoSwap, error) {
+	if queue := getSwapQueue(status); queue != nil {
+		return findQueuedRouterSwaps(queue, status, septime)
+	}
 	query := getStatusQuery(status, septime)
 	opts := &options.FindOptions{
 		Sort:  bson.D{{Key: "inittime", Value: 1}},
@@ -286,6 +327,9 @@ func AddRouterSwapResult(mr *MgoSwapResult) error {
 	_, err := collRouterSwapResult.InsertOne(clientCtx, mr)
 	if err == nil {
 		log.Info("mongodb add router swap result success", "chainid", mr.FromChainID, "txid", mr.TxID, "logindex", mr.LogIndex)
+		mirrorDocs(collRouterSwapResult, mr.Key)
+		enqueueSwapResult(mr.Key, mr.Status, mr.Timestamp, mr.InitTime)
+		addSwapTransition(collRouterSwapResult, mr.Key, nil, mr.Status, ActorRouter, mr.Memo, getHistoryChanges(mr))
 	} else if !mongo.IsDuplicateKeyError(err) {
 		log.Error("mongodb add router swap result failed", "chainid", mr.FromChainID, "txid", mr.TxID, "logindex", mr.LogIndex, "err", err)
 	}
@@ -328,17 +372,22 @@ func AllocateRouterSwapNonce(args *tokens.BuildTxArgs, nonceptr *uint64, isRecyc
 	if args.SwapValue != nil {
 		resUpdates["swapvalue"] = args.SwapValue.String()
 	}
-	_, err = collRouterSwapResult.UpdateByID(clientCtx, key, bson.M{"$set": resUpdates})
+	nonceReason := fmt.Sprintf("swap nonce %v allocated", swapnonce)
+	_, err = updateSwapStatusByID(collRouterSwapResult, key, nil, resUpdates, ActorRouter, nonceReason)
 	if err != nil {
 		log.Warn("mongodb allocate swap nonce failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce, "err", err)
 		return 0, mgoError(err)
 	}
 
 	log.Info("mongodb allocate swap nonce success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce)
+	mirrorDocs(collRouterSwapResult, key)
+	enqueueSwapResult(key, MatchTxNotStable, nowTime, swapRes.InitTime)
 
 	statusUpdates := bson.M{"status": TxProcessed, "timestamp": nowTime}
-	_, errf := collRouterSwap.UpdateByID(clientCtx, key, bson.M{"$set": statusUpdates})
-	if errf != nil {
+	_, errf := updateSwapStatusByID(collRouterSwap, key, nil, statusUpdates, ActorRouter, nonceReason)
+	if errf == nil {
+		mirrorDocs(collRouterSwap, key)
+	} else {
 		log.Warn("mongodb update swap status to TxProcessed failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce, "err", errf)
 	}
 
@@ -352,6 +401,10 @@ func AllocateRouterSwapNonce(args *tokens.BuildTxArgs, nonceptr *uint64, isRecyc
 
 // UpdateRouterSwapResultStatus update router swap result status
 func UpdateRouterSwapResultStatus(fromChainID, txid string, logindex int, status SwapStatus, timestamp int64, memo string) error {
+	return updateRouterSwapResultStatus(fromChainID, txid, logindex, status, timestamp, memo, ActorRouter)
+}
+
+func updateRouterSwapResultStatus(fromChainID, txid string, logindex int, status SwapStatus, timestamp int64, memo, actor string) error {
 	updateResultLock.Lock()
 	defer updateResultLock.Unlock()
 
@@ -368,9 +421,11 @@ func UpdateRouterSwapResultStatus(fromChainID, txid string, logindex int, status
 		updates["swaptime"] = 0
 		updates["swapnonce"] = 0
 	}
-	_, err := collRouterSwapResult.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	_, err := updateSwapStatusByID(collRouterSwapResult, key, nil, updates, actor, memo)
 	if err == nil {
 		log.Info("mongodb update swap result status success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status)
+		mirrorDocs(collRouterSwapResult, key)
+		enqueueSwapResult(key, status, timestamp, 0)
 	} else {
 		log.Error("mongodb update swap result status failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status, "err", err)
 	}
@@ -401,8 +456,9 @@ func UpdateRouterOldSwapTxs(fromChainID, txid string, logindex int, swapTx strin
 		}
 	}
 
+	nowTime := time.Now().Unix()
 	updateSet := bson.M{
-		"timestamp": time.Now().Unix(),
+		"timestamp": nowTime,
 	}
 	if swapRes.Status == TxNeedReswap {
 		updateSet["swaptx"] = ""
@@ -413,9 +469,14 @@ func UpdateRouterOldSwapTxs(fromChainID, txid string, logindex int, swapTx strin
 	}
 
 	var updates bson.M
+	changes := bson.M{"oldswaptxs": append(swapRes.OldSwapTxs, swapTx)}
+	for k, v := range updateSet {
+		changes[k] = v
+	}
 
 	if len(swapRes.OldSwapTxs) == 0 {
 		updateSet["oldswaptxs"] = []string{swapRes.SwapTx, swapTx}
+		changes["oldswaptxs"] = updateSet["oldswaptxs"]
 		updates = bson.M{"$set": updateSet}
 	} else {
 		updates = bson.M{
@@ -428,12 +489,39 @@ func UpdateRouterOldSwapTxs(fromChainID, txid string, logindex int, swapTx strin
 	_, err = collRouterSwapResult.UpdateByID(clientCtx, key, updates)
 	if err == nil {
 		log.Info("UpdateRouterOldSwapTxs success", "fromChainID", fromChainID, "txid", txid, "logIndex", logindex, "swaptx", swapTx, "nonce", swapRes.SwapNonce)
+		mirrorDocs(collRouterSwapResult, key)
+		addSwapChanges(collRouterSwapResult, key, changes, ActorRouter, "swap tx appended")
+		enqueueSwapResult(key, swapRes.Status, nowTime, swapRes.InitTime)
 	} else {
 		log.Error("UpdateRouterOldSwapTxs failed", "fromChainID", fromChainID, "txid", txid, "logIndex", logindex, "swaptx", swapTx, "nonce", swapRes.SwapNonce, "err", err)
 	}
 	return mgoError(err)
 }
 
+// UpdateRouterSwapResultNonce update swap nonce and ttl of replacing swap tx
+// (replacing an expired swap tx may use a fresh nonce and always has a new ttl)
+func UpdateRouterSwapResultNonce(fromChainID, txid string, logindex int, swapnonce, ttl uint64) error {
+	updateResultLock.Lock()
+	defer updateResultLock.Unlock()
+
+	key := GetRouterSwapKey(fromChainID, txid, logindex)
+	updates := bson.M{"swapnonce": swapnonce}
+	if ttl != 0 {
+		updates["ttl"] = ttl
+	}
+	matched, err := updateSwapStatusByID(collRouterSwapResult, key, bson.M{"status": MatchTxNotStable}, updates, ActorRouter, "swap nonce updated")
+	if err != nil {
+		log.Error("mongodb update swap result nonce failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce, "ttl", ttl, "err", err)
+		return mgoError(err)
+	}
+	if !matched {
+		return ErrItemNotFound
+	}
+	log.Info("mongodb update swap result nonce success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce, "ttl", ttl)
+	mirrorDocs(collRouterSwapResult, key)
+	return nil
+}
+
 // FindRouterSwapResult find router swap result
 func FindRouterSwapResult(fromChainID, txid string, logindex int) (*MgoSwapResult, error) {
 	key := GetRouterSwapKey(fromChainID, txid, logindex)
@@ -505,6 +593,9 @@ func FindRouterSwapResultsOfTx(fromChainID, txid string) ([]*MgoSwapResult, erro
 
 // FindRouterSwapResultsWithStatus find router swap result with status
 func FindRouterSwapResultsWithStatus(status SwapStatus, septime int64) ([]*MgoSwapResult, error) {
+	if queue := getResultQueue(status); queue != nil {
+		return findQueuedRouterSwapResults(queue, status, septime)
+	}
 	query := getStatusQuery(status, septime)
 	opts := &options.FindOptions{
 		Sort:  bson.D{{Key: "inittime", Value: 1}},
@@ -543,6 +634,33 @@ func FindRouterSwapResultsWithChainIDAndStatus(fromChainID string, status SwapSt
 	return result, nil
 }
 
+// FindStableRouterSwapResultsInRange find stable router swap results registered in time range [startTime, endTime).
+// fromChainID is optional, and the result count is not limited if limit is zero.
+func FindStableRouterSwapResultsInRange(fromChainID string, startTime, endTime, limit int64) ([]*MgoSwapResult, error) {
+	// init time is milli seconds
+	query := bson.M{
+		"status":   MatchTxStable,
+		"inittime": bson.M{"$gte": startTime * 1000, "$lt": endTime * 1000},
+	}
+	if fromChainID != "" {
+		query["fromChainID"] = fromChainID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "inittime", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	cur, err := collRouterSwapResult.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapResult, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
 // FindNextSwapNonce find next swap nonce
 func FindNextSwapNonce(chainID, mpc string) (uint64, error) {
 	qchainid := bson.M{"toChainID": chainID}
@@ -753,9 +871,18 @@ func UpdateRouterSwapResult(fromChainID, txid string, logindex int, items *SwapR
 	} else if items.Status == MatchTxNotStable {
 		updates["memo"] = ""
 	}
+	if items.SwapFee != "" {
+		updates["swapfee"] = items.SwapFee
+	}
 	if items.TTL != 0 {
 		updates["ttl"] = items.TTL
 	}
+	if items.BuilderVersion != 0 {
+		updates["builderversion"] = items.BuilderVersion
+	}
+	if items.DeliveryFeePercent != 0 {
+		updates["deliveryfeepercent"] = items.DeliveryFeePercent
+	}
 	if items.SwapNonce != 0 || items.Status == MatchTxNotStable {
 		err = checkRouterSwapResultUpdate(swapRes, items.SwapNonce)
 		if err != nil {
@@ -765,9 +892,15 @@ func UpdateRouterSwapResult(fromChainID, txid string, logindex int, items *SwapR
 			updates["swapnonce"] = items.SwapNonce
 		}
 	}
-	_, err = collRouterSwapResult.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	_, err = updateSwapStatusByID(collRouterSwapResult, key, nil, updates, ActorRouter, items.Memo)
 	if err == nil {
 		log.Info("mongodb update router swap result success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "updates", updates)
+		status := items.Status
+		if status == KeepStatus {
+			status = swapRes.Status
+		}
+		mirrorDocs(collRouterSwapResult, key)
+		enqueueSwapResult(key, status, items.Timestamp, swapRes.InitTime)
 	} else {
 		log.Error("mongodb update router swap result failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "updates", updates, "err", err)
 	}
@@ -798,6 +931,7 @@ func AddUsedRValue(pubkey, r string) error {
 	switch {
 	case err == nil:
 		log.Info("mongodb add used r success", "pubkey", pubkey, "r", r)
+		mirrorDocs(collUsedRValue, key)
 		return nil
 	case mongo.IsDuplicateKeyError(err):
 		log.Warn("mongodb add used r failed", "pubkey", pubkey, "r", r, "err", err)
@@ -810,7 +944,9 @@ func AddUsedRValue(pubkey, r string) error {
 		}
 
 		_, err = collUsedRValue.InsertOne(clientCtx, mr) // retry once
-		if err != nil {
+		if err == nil {
+			mirrorDocs(collUsedRValue, key)
+		} else {
 			log.Warn("mongodb add used r failed in retry", "pubkey", pubkey, "r", r, "err", err)
 		}
 		return mgoError(err)
@@ -820,7 +956,7 @@ func AddUsedRValue(pubkey, r string) error {
 // ----------------------------- admin functions -------------------------------------
 
 // RouterAdminPassBigValue pass big value
-func RouterAdminPassBigValue(fromChainID, txid string, logIndex int) error {
+func RouterAdminPassBigValue(fromChainID, txid string, logIndex int, actor string) error {
 	swap, err := FindRouterSwap(fromChainID, txid, logIndex)
 	if err != nil {
 		return err
@@ -833,11 +969,11 @@ func RouterAdminPassBigValue(fromChainID, txid string, logIndex int) error {
 	if err == nil {
 		return fmt.Errorf("can not pass big value swap with result exist")
 	}
-	return UpdateRouterSwapStatus(fromChainID, txid, logIndex, TxNotSwapped, time.Now().Unix(), "")
+	return updateRouterSwapStatus(fromChainID, txid, logIndex, TxNotSwapped, time.Now().Unix(), "", actor)
 }
 
 // RouterAdminPassForbiddenSwapout pass forbidden swapout
-func RouterAdminPassForbiddenSwapout(fromChainID, txid string, logIndex int) error {
+func RouterAdminPassForbiddenSwapout(fromChainID, txid string, logIndex int, actor string) error {
 	swap, err := FindRouterSwapResult(fromChainID, txid, logIndex)
 	if err != nil {
 		return err
@@ -846,12 +982,22 @@ func RouterAdminPassForbiddenSwapout(fromChainID, txid string, logIndex int) err
 		return fmt.Errorf("swap status is %v, not %v", swap.Status.String(), SwapoutForbidden.String())
 	}
 
-	_ = UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, MatchTxEmpty, time.Now().Unix(), "")
-	return UpdateRouterSwapStatus(fromChainID, txid, logIndex, TxNotSwapped, time.Now().Unix(), "")
+	_ = updateRouterSwapResultStatus(fromChainID, txid, logIndex, MatchTxEmpty, time.Now().Unix(), "", actor)
+	return updateRouterSwapStatus(fromChainID, txid, logIndex, TxNotSwapped, time.Now().Unix(), "", actor)
+}
+
+// RouterAdminForbidSwap forbid swap by making both swap and swap result failed
+func RouterAdminForbidSwap(fromChainID, txid string, logIndex int, memo, actor string) error {
+	err1 := updateRouterSwapResultStatus(fromChainID, txid, logIndex, ManualMakeFail, time.Now().Unix(), memo, actor)
+	err2 := updateRouterSwapStatus(fromChainID, txid, logIndex, ManualMakeFail, time.Now().Unix(), memo, actor)
+	if err1 != nil && err2 != nil {
+		return err1
+	}
+	return nil
 }
 
 // RouterAdminReswap reswap
-func RouterAdminReswap(fromChainID, txid string, logIndex int) error {
+func RouterAdminReswap(fromChainID, txid string, logIndex int, actor string) error {
 	swap, err := FindRouterSwap(fromChainID, txid, logIndex)
 	if err != nil {
 		return err
@@ -879,7 +1025,7 @@ func RouterAdminReswap(fromChainID, txid string, logIndex int) error {
 
 	txStatus, txHash := getSwapResultsTxStatus(resBridge, res)
 	if txStatus != nil && txStatus.BlockHeight > 0 && !txStatus.IsSwapTxOnChainAndFailed() {
-		_ = UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, MatchTxNotStable, time.Now().Unix(), "")
+		_ = updateRouterSwapResultStatus(fromChainID, txid, logIndex, MatchTxNotStable, time.Now().Unix(), "", actor)
 		return fmt.Errorf("swap succeed with swaptx %v", txHash)
 	}
 
@@ -898,12 +1044,39 @@ func RouterAdminReswap(fromChainID, txid string, logIndex int) error {
 
 	log.Info("[reswap] update status to TxNotSwapped", "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "swaptx", res.SwapTx)
 
-	err = UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, Reswapping, time.Now().Unix(), "")
+	err = updateRouterSwapResultStatus(fromChainID, txid, logIndex, Reswapping, time.Now().Unix(), "", actor)
 	if err != nil {
 		return err
 	}
 
-	return UpdateRouterSwapStatus(fromChainID, txid, logIndex, TxNotSwapped, time.Now().Unix(), "")
+	return updateRouterSwapStatus(fromChainID, txid, logIndex, TxNotSwapped, time.Now().Unix(), "", actor)
+}
+
+// RedeliverRouterSwap redeliver stable swap of which the claimable payout is
+// refunded to the mpc (eg. cancelled ripple checks), the swap is swapped again.
+func RedeliverRouterSwap(fromChainID, txid string, logIndex int, memo string) error {
+	swap, err := FindRouterSwap(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	if swap.Status != TxProcessed {
+		return fmt.Errorf("swap status is %v, can not redeliver", swap.Status.String())
+	}
+	res, err := FindRouterSwapResult(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	if res.Status != MatchTxStable {
+		return fmt.Errorf("swap result status is %v, can not redeliver", res.Status.String())
+	}
+
+	log.Info("[redeliver] update status to TxNotSwapped", "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "swaptx", res.SwapTx, "memo", memo)
+
+	err = updateRouterSwapResultStatus(fromChainID, txid, logIndex, Reswapping, time.Now().Unix(), memo, ActorRouter)
+	if err != nil {
+		return err
+	}
+	return updateRouterSwapStatus(fromChainID, txid, logIndex, TxNotSwapped, time.Now().Unix(), memo, ActorRouter)
 }
 
 func getSwapResultsTxStatus(bridge tokens.IBridge, res *MgoSwapResult) (status *tokens.TxStatus, txHash string) {
@@ -999,7 +1172,12 @@ func GetRegisteredRouterSwap(fromChainID, txid string, logIndex int) (oldSwap *M
 		if oldSwap.Status == TxNotSwapped {
 			now := time.Now().Unix()
 			if oldSwap.Timestamp+3*24*3600 < now {
-				_, _ = collRouterSwap.UpdateByID(clientCtx, oldSwap.Key, bson.M{"$set": bson.M{"timestamp": now}})
+				_, err = collRouterSwap.UpdateByID(clientCtx, oldSwap.Key, bson.M{"$set": bson.M{"timestamp": now}})
+				if err == nil {
+					mirrorDocs(collRouterSwap, oldSwap.Key)
+					addSwapChanges(collRouterSwap, oldSwap.Key, bson.M{"timestamp": now}, ActorRouter, "registered again")
+					enqueueSwap(oldSwap.Key, TxNotSwapped, now, oldSwap.InitTime)
+				}
 			}
 		}
 		return oldSwap, true
@@ -0,0 +1,245 @@
+package mongodb
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MgoAPIToken delegated read-only api token,
+// the secret is never stored, the key is the hash of it.
+type MgoAPIToken struct {
+	Key        string          `bson:"_id" json:"id"`
+	Name       EncryptedString `bson:"name" json:"name"`
+	Binds      []string        `bson:"binds,omitempty" json:"binds,omitempty"`
+	TokenIDs   []string        `bson:"tokenIDs,omitempty" json:"tokenIDs,omitempty"`
+	CreateTime int64           `bson:"createtime" json:"createTime"`
+	ExpireTime int64           `bson:"expiretime" json:"expireTime,omitempty"` // 0 means never expire
+	Revoked    bool            `bson:"revoked" json:"revoked,omitempty"`
+}
+
+// IsExpired is api token expired at the time
+func (t *MgoAPIToken) IsExpired(now int64) bool {
+	return t.ExpireTime > 0 && now >= t.ExpireTime
+}
+
+// ScopedSwapsFilter filter of finding swaps in scope of api token
+type ScopedSwapsFilter struct {
+	Binds    []string
+	TokenIDs []string
+	Offset   int
+	Limit    int
+	Status   string
+
+	// register time range [StartTime, EndTime) in seconds, zero means not limited
+	StartTime int64
+	EndTime   int64
+}
+
+// AddAPIToken add api token
+func AddAPIToken(mt *MgoAPIToken) error {
+	_, err := collAPIToken.InsertOne(clientCtx, mt)
+	if err == nil {
+		mirrorDocs(collAPIToken, mt.Key)
+		log.Info("mongodb add api token success", "id", mt.Key, "name", mt.Name, "binds", mt.Binds, "tokenIDs", mt.TokenIDs, "expireTime", mt.ExpireTime)
+	} else {
+		log.Warn("mongodb add api token failed", "id", mt.Key, "name", mt.Name, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindAPIToken find api token by key
+func FindAPIToken(key string) (*MgoAPIToken, error) {
+	result := &MgoAPIToken{}
+	err := collAPIToken.FindOne(clientCtx, bson.M{"_id": key}).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// FindAPITokens find all api tokens
+func FindAPITokens() ([]*MgoAPIToken, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createtime", Value: 1}})
+	cur, err := collAPIToken.Find(clientCtx, bson.M{}, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoAPIToken, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// RevokeAPIToken revoke api token
+func RevokeAPIToken(key string) error {
+	res, err := collAPIToken.UpdateByID(clientCtx, key, bson.M{"$set": bson.M{"revoked": true}})
+	if err == nil && res.MatchedCount == 0 {
+		err = mongo.ErrNoDocuments
+	}
+	if err == nil {
+		mirrorDocs(collAPIToken, key)
+		log.Info("mongodb revoke api token success", "id", key)
+	} else {
+		log.Warn("mongodb revoke api token failed", "id", key, "err", err)
+	}
+	return mgoError(err)
+}
+
+// caseInsensitiveIn matches any of the values ignoring case
+func caseInsensitiveIn(values []string) bson.M {
+	regexes := make([]interface{}, len(values))
+	for i, value := range values {
+		regexes[i] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(value) + "$", Options: "i"}
+	}
+	return bson.M{"$in": regexes}
+}
+
+// getScopedSwapsQuery get query of scoped swaps filter,
+// and whether to query in the swap result collection.
+func getScopedSwapsQuery(filter *ScopedSwapsFilter) (query bson.M, isInResultColl bool, err error) {
+	if len(filter.Binds) == 0 && len(filter.TokenIDs) == 0 {
+		return nil, false, errors.New("scoped swaps filter without scope")
+	}
+	queries := make([]bson.M, 0, 4)
+	if len(filter.Binds) > 0 {
+		queries = append(queries, bson.M{"bind": caseInsensitiveIn(filter.Binds)})
+	}
+	if len(filter.TokenIDs) > 0 {
+		queries = append(queries, bson.M{getTokenIDField(): bson.M{"$in": filter.TokenIDs}})
+	}
+
+	registerStatuses, resultStatuses := getStatusesFromStr(filter.Status)
+	filterStatuses, isInResultColl := resultStatuses, true
+	if len(resultStatuses) == 0 && len(registerStatuses) > 0 {
+		filterStatuses = registerStatuses
+		isInResultColl = false
+	}
+	if len(filterStatuses) > 0 {
+		queries = append(queries, bson.M{"status": bson.M{"$in": filterStatuses}})
+	}
+
+	// init time is milli seconds
+	timeRange := bson.M{}
+	if filter.StartTime > 0 {
+		timeRange["$gte"] = filter.StartTime * 1000
+	}
+	if filter.EndTime > 0 {
+		timeRange["$lt"] = filter.EndTime * 1000
+	}
+	if len(timeRange) > 0 {
+		queries = append(queries, bson.M{"inittime": timeRange})
+	}
+	return bson.M{"$and": queries}, isInResultColl, nil
+}
+
+func getScopedSwapsColl(isInResultColl bool) *mongo.Collection {
+	if isInResultColl {
+		return collRouterSwapResult
+	}
+	return collRouterSwap
+}
+
+// FindScopedRouterSwapResults find router swap results of binds and token ids.
+// the non empty scopes are all required to match.
+func FindScopedRouterSwapResults(filter *ScopedSwapsFilter) ([]*MgoSwapResult, error) {
+	query, isInResultColl, err := getScopedSwapsQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &options.FindOptions{}
+	if filter.Limit >= 0 {
+		opts = opts.SetSort(bson.D{{Key: "inittime", Value: 1}}).
+			SetSkip(int64(filter.Offset)).SetLimit(int64(filter.Limit))
+	} else {
+		opts = opts.SetSort(bson.D{{Key: "inittime", Value: -1}}).
+			SetSkip(int64(filter.Offset)).SetLimit(int64(-filter.Limit))
+	}
+
+	cur, err := getScopedSwapsColl(isInResultColl).Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapResult, 0, 20)
+	if isInResultColl {
+		err = cur.All(clientCtx, &result)
+	} else {
+		swaps := make([]*MgoSwap, 0, 20)
+		err = cur.All(clientCtx, &swaps)
+		if err == nil {
+			result = convertToSwapResults(swaps)
+		}
+	}
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// CountScopedRouterSwapResults count router swap results of scoped swaps filter
+func CountScopedRouterSwapResults(filter *ScopedSwapsFilter) (int64, error) {
+	query, isInResultColl, err := getScopedSwapsQuery(filter)
+	if err != nil {
+		return 0, err
+	}
+	count, err := getScopedSwapsColl(isInResultColl).CountDocuments(clientCtx, query)
+	return count, mgoError(err)
+}
+
+// IterateScopedRouterSwapResults iterate router swap results of scoped swaps filter
+// in register time order (offset and limit are ignored), it stops if fn returns error.
+func IterateScopedRouterSwapResults(filter *ScopedSwapsFilter, fn func(*MgoSwapResult) error) error {
+	query, isInResultColl, err := getScopedSwapsQuery(filter)
+	if err != nil {
+		return err
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "inittime", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := getScopedSwapsColl(isInResultColl).Find(clientCtx, query, opts)
+	if err != nil {
+		return mgoError(err)
+	}
+	defer cur.Close(clientCtx)
+
+	for cur.Next(clientCtx) {
+		res := &MgoSwapResult{}
+		if isInResultColl {
+			err = cur.Decode(res)
+		} else {
+			swap := &MgoSwap{}
+			if err = cur.Decode(swap); err == nil {
+				res = swap.ToSwapResult()
+			}
+		}
+		if err != nil {
+			return mgoError(err)
+		}
+		if err = fn(res); err != nil {
+			return err
+		}
+	}
+	return mgoError(cur.Err())
+}
+
+func ensureScopedSwapsIndexes() {
+	model := mongo.IndexModel{
+		Keys: bson.D{{Key: "bind", Value: 1}, {Key: "inittime", Value: 1}},
+	}
+	for _, coll := range []*mongo.Collection{collRouterSwap, collRouterSwapResult} {
+		addExpectedIndexes(coll, []mongo.IndexModel{model})
+		name, err := coll.Indexes().CreateOne(clientCtx, model)
+		if err != nil {
+			log.Warn("[mongodb] create scoped swaps indexes failed", "collection", coll.Name(), "err", err)
+			continue
+		}
+		log.Info("[mongodb] create scoped swaps indexes success", "collection", coll.Name(), "index", name)
+	}
+}
@@ -0,0 +1,66 @@
+package mongodb
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAPITokenIsExpired(t *testing.T) {
+	tests := []struct {
+		expireTime int64
+		now        int64
+		expired    bool
+	}{
+		{0, 1000, false}, // never expire
+		{1001, 1000, false},
+		{1000, 1000, true},
+		{999, 1000, true},
+	}
+	for i, test := range tests {
+		token := &MgoAPIToken{ExpireTime: test.expireTime}
+		if expired := token.IsExpired(test.now); expired != test.expired {
+			t.Errorf("test %v: api token expired got %v, want %v", i, expired, test.expired)
+		}
+	}
+}
+
+func TestScopedSwapsQuery(t *testing.T) {
+	// the query must be limited in the scope of api token
+	if _, _, err := getScopedSwapsQuery(&ScopedSwapsFilter{Status: "10"}); err == nil {
+		t.Fatal("scoped swaps query without scope should fail")
+	}
+
+	query, isInResultColl, err := getScopedSwapsQuery(&ScopedSwapsFilter{
+		Binds:     []string{"0xAA"},
+		TokenIDs:  []string{"USDC"},
+		StartTime: 100,
+		EndTime:   200,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isInResultColl {
+		t.Errorf("scoped swaps without status should be queried in result collection")
+	}
+	queries := query["$and"].([]bson.M)
+	if len(queries) != 3 {
+		t.Fatalf("scoped swaps query got %v conditions, want 3", len(queries))
+	}
+	if _, exist := queries[0]["bind"]; !exist {
+		t.Errorf("scoped swaps query miss bind condition: %v", queries[0])
+	}
+	if got := queries[1][getTokenIDField()]; !reflect.DeepEqual(got, bson.M{"$in": []string{"USDC"}}) {
+		t.Errorf("scoped swaps query token id condition got %v", got)
+	}
+	if got := queries[2]["inittime"]; !reflect.DeepEqual(got, bson.M{"$gte": int64(100000), "$lt": int64(200000)}) {
+		t.Errorf("scoped swaps query time range got %v, want milli seconds", got)
+	}
+
+	// register statuses are queried in the register collection
+	_, isInResultColl, err = getScopedSwapsQuery(&ScopedSwapsFilter{Binds: []string{"0xAA"}, Status: "0"})
+	if err != nil || isInResultColl {
+		t.Errorf("scoped swaps of register status got (%v, %v), want register collection", isInResultColl, err)
+	}
+}
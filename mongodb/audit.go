@@ -0,0 +1,49 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindRouterSwapResultBySwapTx find router swap result to chain with the swap tx (or old swap tx)
+func FindRouterSwapResultBySwapTx(toChainID, swapTx string) (*MgoSwapResult, error) {
+	query := bson.M{
+		"toChainID": toChainID,
+		"$or": []bson.M{
+			{"swaptx": swapTx},
+			{"oldswaptxs": swapTx},
+		},
+	}
+	result := &MgoSwapResult{}
+	err := collRouterSwapResult.FindOne(clientCtx, query).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// FindRouterSwapResultsInHeightRange find router swap results whose source tx height
+// (if isFromChain) or swap tx height on chain is in range [minHeight, maxHeight].
+func FindRouterSwapResultsInHeightRange(chainID string, isFromChain bool, minHeight, maxHeight uint64) ([]*MgoSwapResult, error) {
+	chainKey, heightKey := "toChainID", "swapheight"
+	if isFromChain {
+		chainKey, heightKey = "fromChainID", "txheight"
+	}
+	query := bson.M{
+		chainKey:  chainID,
+		heightKey: bson.M{"$gte": minHeight, "$lte": maxHeight},
+	}
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: heightKey, Value: 1}},
+	}
+	cur, err := collRouterSwapResult.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapResult, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CheckpointStore scan checkpoint store in mongodb
+type CheckpointStore struct{}
+
+var _ tokens.CheckpointStore = CheckpointStore{}
+
+func getScanCheckpointKey(chainID, scanner string) string {
+	return chainID + ":" + scanner
+}
+
+func getScanGapKey(chainID, scanner string, from uint64) string {
+	return fmt.Sprintf("%v:%v:%d", chainID, scanner, from)
+}
+
+// LoadCheckpoint load scan checkpoint, returns nil if not exist
+func (CheckpointStore) LoadCheckpoint(chainID, scanner string) (*tokens.ScanCheckpoint, error) {
+	result := &MgoScanCheckpoint{}
+	err := collScanCheckpoint.FindOne(clientCtx, bson.M{"_id": getScanCheckpointKey(chainID, scanner)}).Decode(result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return &tokens.ScanCheckpoint{
+		ChainID:    result.ChainID,
+		Scanner:    result.Scanner,
+		Height:     result.Height,
+		Marker:     result.Marker,
+		UpdateTime: result.UpdateTime,
+	}, nil
+}
+
+// AdvanceCheckpoint set scan checkpoint if the stored height is still prevHeight
+func (CheckpointStore) AdvanceCheckpoint(cp *tokens.ScanCheckpoint, prevHeight uint64) error {
+	key := getScanCheckpointKey(cp.ChainID, cp.Scanner)
+	filter := bson.M{"_id": key, "height": prevHeight}
+	update := bson.M{"$set": bson.M{
+		"chainID":    cp.ChainID,
+		"scanner":    cp.Scanner,
+		"height":     cp.Height,
+		"marker":     cp.Marker,
+		"updateTime": cp.UpdateTime,
+	}}
+	// insert the first checkpoint, conflicts with an existing one by duplicate key
+	opts := options.Update().SetUpsert(prevHeight == 0)
+	res, err := collScanCheckpoint.UpdateOne(clientCtx, filter, update, opts)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return tokens.ErrCheckpointConflict
+		}
+		log.Warn("mongodb advance scan checkpoint failed", "chainID", cp.ChainID, "scanner", cp.Scanner, "prevHeight", prevHeight, "height", cp.Height, "err", err)
+		return mgoError(err)
+	}
+	if res.MatchedCount == 0 && res.UpsertedCount == 0 {
+		return tokens.ErrCheckpointConflict
+	}
+	mirrorDocs(collScanCheckpoint, key)
+	log.Debug("mongodb advance scan checkpoint success", "chainID", cp.ChainID, "scanner", cp.Scanner, "prevHeight", prevHeight, "height", cp.Height, "marker", cp.Marker)
+	return nil
+}
+
+// AddScanGap add or replace scan gap starting at the same height
+func (CheckpointStore) AddScanGap(gap *tokens.ScanGap) error {
+	key := getScanGapKey(gap.ChainID, gap.Scanner, gap.From)
+	mg := &MgoScanGap{
+		Key:        key,
+		ChainID:    gap.ChainID,
+		Scanner:    gap.Scanner,
+		From:       gap.From,
+		To:         gap.To,
+		Reason:     gap.Reason,
+		CreateTime: gap.CreateTime,
+	}
+	_, err := collScanGap.ReplaceOne(clientCtx, bson.M{"_id": key}, mg, options.Replace().SetUpsert(true))
+	if err == nil {
+		mirrorDocs(collScanGap, key)
+		log.Info("mongodb add scan gap success", "chainID", gap.ChainID, "scanner", gap.Scanner, "from", gap.From, "to", gap.To, "reason", gap.Reason)
+	} else {
+		log.Warn("mongodb add scan gap failed", "chainID", gap.ChainID, "scanner", gap.Scanner, "from", gap.From, "to", gap.To, "err", err)
+	}
+	return mgoError(err)
+}
+
+// RemoveScanGap remove scan gap starting at height from
+func (CheckpointStore) RemoveScanGap(chainID, scanner string, from uint64) error {
+	key := getScanGapKey(chainID, scanner, from)
+	_, err := collScanGap.DeleteOne(clientCtx, bson.M{"_id": key})
+	if err == nil {
+		mirrorDocs(collScanGap, key)
+		log.Info("mongodb remove scan gap success", "chainID", chainID, "scanner", scanner, "from", from)
+	} else {
+		log.Warn("mongodb remove scan gap failed", "chainID", chainID, "scanner", scanner, "from", from, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindScanGaps find scan gaps of scanner on chain in height order
+func (CheckpointStore) FindScanGaps(chainID, scanner string) ([]*tokens.ScanGap, error) {
+	query := bson.M{"chainID": chainID, "scanner": scanner}
+	opts := options.Find().SetSort(bson.D{{Key: "from", Value: 1}})
+	cur, err := collScanGap.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	items := make([]*MgoScanGap, 0, 20)
+	err = cur.All(clientCtx, &items)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*tokens.ScanGap, 0, len(items))
+	for _, item := range items {
+		result = append(result, &tokens.ScanGap{
+			ChainID:    item.ChainID,
+			Scanner:    item.Scanner,
+			From:       item.From,
+			To:         item.To,
+			Reason:     item.Reason,
+			CreateTime: item.CreateTime,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,103 @@
+package mongodb
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// claim status of two-phase swaps
+const (
+	ClaimStatusPending   = "pending"   // funds are claimable by the receiver
+	ClaimStatusClaimed   = "claimed"   // finalized by the receiver (or a relayer)
+	ClaimStatusRefunding = "refunding" // refund tx of expired claim is sent
+	ClaimStatusRefunded  = "refunded"  // expired claim is refunded
+	ClaimStatusMissing   = "missing"   // swap tx is stable but no claim is placed in the escrow
+
+	// claims placed only if the direct delivery is impossible (eg. ripple checks)
+	// are refunded to the mpc, and the swap is redelivered directly
+	ClaimStatusRedelivering = "redelivering" // refunded and the swap is being redelivered
+	ClaimStatusRedelivered  = "redelivered"  // the swap is redelivered by a new swap tx
+)
+
+// AddClaimSwap add two-phase swap, if error mean already exist
+func AddClaimSwap(mc *MgoClaimSwap) error {
+	mc.Key = GetRouterSwapKey(mc.FromChainID, mc.TxID, mc.LogIndex)
+	_, err := collClaimSwap.InsertOne(clientCtx, mc)
+	if err == nil {
+		mirrorDocs(collClaimSwap, mc.Key)
+		log.Info("mongodb add claim swap success", "chainid", mc.FromChainID, "txid", mc.TxID, "logindex", mc.LogIndex, "claimID", mc.ClaimID)
+	} else if !mongo.IsDuplicateKeyError(err) {
+		log.Warn("mongodb add claim swap failed", "chainid", mc.FromChainID, "txid", mc.TxID, "logindex", mc.LogIndex, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindClaimSwap find two-phase swap
+func FindClaimSwap(fromChainID, txid string, logIndex int) (*MgoClaimSwap, error) {
+	key := GetRouterSwapKey(fromChainID, txid, logIndex)
+	result := &MgoClaimSwap{}
+	err := collClaimSwap.FindOne(clientCtx, bson.M{"_id": key}).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// FindClaimSwapsToCheck find two-phase swaps which are not finalized
+func FindClaimSwapsToCheck() ([]*MgoClaimSwap, error) {
+	query := bson.M{
+		"status": bson.M{"$in": []string{ClaimStatusPending, ClaimStatusRefunding, ClaimStatusRedelivering}},
+	}
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "timestamp", Value: 1}},
+		Limit: &maxCountOfResults,
+	}
+	cur, err := collClaimSwap.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoClaimSwap, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// UpdateClaimSwap update status, expiry and refund tx (if not empty) of two-phase swap
+func UpdateClaimSwap(fromChainID, txid string, logIndex int, status string, expiry int64, refundTx string, timestamp int64) error {
+	key := GetRouterSwapKey(fromChainID, txid, logIndex)
+	updates := bson.M{
+		"status":    status,
+		"expiry":    expiry,
+		"timestamp": timestamp,
+	}
+	if refundTx != "" {
+		updates["refundtx"] = refundTx
+		updates["refundtime"] = timestamp
+	}
+	_, err := collClaimSwap.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	if err == nil {
+		mirrorDocs(collClaimSwap, key)
+		log.Info("mongodb update claim swap success", "chainid", fromChainID, "txid", txid, "logindex", logIndex, "status", status, "refundTx", refundTx)
+	} else {
+		log.Warn("mongodb update claim swap failed", "chainid", fromChainID, "txid", txid, "logindex", logIndex, "status", status, "err", err)
+	}
+	return mgoError(err)
+}
+
+func ensureClaimSwapIndexes() {
+	model := mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "timestamp", Value: 1}},
+	}
+	addExpectedIndexes(collClaimSwap, []mongo.IndexModel{model})
+	name, err := collClaimSwap.Indexes().CreateOne(clientCtx, model)
+	if err != nil {
+		log.Warn("[mongodb] create claim swap indexes failed", "err", err)
+		return
+	}
+	log.Info("[mongodb] create claim swap indexes success", "index", name)
+}
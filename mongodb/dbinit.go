@@ -43,6 +43,7 @@ func MongoServerInit(appName string, hosts []string, dbName, user, pass string)
 			Username:   user,
 			Password:   pass,
 		},
+		Monitor: newCommandMonitor(),
 	}
 
 	if err := connect(clientOpts); err != nil {
@@ -59,6 +60,7 @@ func doCleanup() {
 	defer utils.TopWaitGroup.Done()
 	MgoWaitGroup.Wait()
 
+	closeMirror()
 	err := client.Disconnect(clientCtx)
 	if err != nil {
 		log.Error("[mongodb] close connection failed", "appName", appIdentifier, "err", err)
@@ -0,0 +1,44 @@
+package mongodb
+
+import (
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetDeliveryStatsKey get key of delivery stats
+func GetDeliveryStatsKey(tokenID, toChainID string) string {
+	return strings.ToLower(tokenID + ":" + toChainID)
+}
+
+// UpdateDeliveryStats add or replace delivery stats of token to chain
+func UpdateDeliveryStats(ms *MgoDeliveryStats) error {
+	ms.Key = GetDeliveryStatsKey(ms.TokenID, ms.ToChainID)
+	ms.Timestamp = time.Now().Unix()
+	opts := options.Replace().SetUpsert(true)
+	_, err := collDeliveryStats.ReplaceOne(clientCtx, bson.M{"_id": ms.Key}, ms, opts)
+	if err != nil {
+		log.Warn("mongodb update delivery stats failed", "key", ms.Key, "err", err)
+		return mgoError(err)
+	}
+	mirrorDocs(collDeliveryStats, ms.Key)
+	return nil
+}
+
+// FindAllDeliveryStats find all delivery stats
+func FindAllDeliveryStats() ([]*MgoDeliveryStats, error) {
+	cur, err := collDeliveryStats.Find(clientCtx, bson.M{})
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoDeliveryStats, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
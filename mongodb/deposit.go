@@ -0,0 +1,92 @@
+package mongodb
+
+import (
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetDepositAddressKey get deposit address key
+func GetDepositAddressKey(chainID, address string) string {
+	return strings.ToLower(chainID + ":" + address)
+}
+
+// AddDepositAddress add deposit address, if error mean already exist
+func AddDepositAddress(mr *MgoDepositAddress) error {
+	mr.Key = GetDepositAddressKey(mr.ChainID, mr.Address)
+	_, err := collDepositAddress.InsertOne(clientCtx, mr)
+	if err == nil {
+		mirrorDocs(collDepositAddress, mr.Key)
+		log.Info("mongodb add deposit address success", "chainid", mr.ChainID, "address", mr.Address, "toChainID", mr.ToChainID, "bind", mr.Bind)
+	} else if !mongo.IsDuplicateKeyError(err) {
+		log.Warn("mongodb add deposit address failed", "chainid", mr.ChainID, "address", mr.Address, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindDepositAddress find deposit address
+func FindDepositAddress(chainID, address string) (*MgoDepositAddress, error) {
+	key := GetDepositAddressKey(chainID, address)
+	result := &MgoDepositAddress{}
+	err := collDepositAddress.FindOne(clientCtx, bson.M{"_id": key}).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// FindDepositAddressesToSweep find deposit addresses which are not swept since sweepTime
+func FindDepositAddressesToSweep(chainID string, sweepTime int64) ([]*MgoDepositAddress, error) {
+	query := bson.M{
+		"chainID":   chainID,
+		"sweeptime": bson.M{"$lt": sweepTime},
+	}
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "sweeptime", Value: 1}},
+		Limit: &maxCountOfResults,
+	}
+	cur, err := collDepositAddress.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoDepositAddress, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// UpdateDepositAddressSwept update sweep time of deposit address,
+// and increase sweep count if it really swept something.
+func UpdateDepositAddressSwept(chainID, address string, sweepTime int64, swept bool) error {
+	key := GetDepositAddressKey(chainID, address)
+	updates := bson.M{"$set": bson.M{"sweeptime": sweepTime}}
+	if swept {
+		updates["$inc"] = bson.M{"sweepcount": 1}
+	}
+	_, err := collDepositAddress.UpdateByID(clientCtx, key, updates)
+	if err == nil {
+		mirrorDocs(collDepositAddress, key)
+	} else {
+		log.Warn("mongodb update deposit address sweep failed", "chainid", chainID, "address", address, "err", err)
+	}
+	return mgoError(err)
+}
+
+func ensureDepositAddressIndexes() {
+	model := mongo.IndexModel{
+		Keys: bson.D{{Key: "chainID", Value: 1}, {Key: "sweeptime", Value: 1}},
+	}
+	addExpectedIndexes(collDepositAddress, []mongo.IndexModel{model})
+	name, err := collDepositAddress.Indexes().CreateOne(clientCtx, model)
+	if err != nil {
+		log.Warn("[mongodb] create deposit address indexes failed", "err", err)
+		return
+	}
+	log.Info("[mongodb] create deposit address indexes success", "index", name)
+}
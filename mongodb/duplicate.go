@@ -0,0 +1,134 @@
+package mongodb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// recovery statuses of duplicate deliveries
+const (
+	DuplicateUnrecovered = "unrecovered"
+	DuplicateRecovering  = "recovering"
+	DuplicateRecovered   = "recovered"
+	DuplicateWrittenOff  = "writtenoff"
+)
+
+// IsValidDuplicateRecoveryStatus is valid recovery status of duplicate delivery
+func IsValidDuplicateRecoveryStatus(status string) bool {
+	switch status {
+	case DuplicateUnrecovered, DuplicateRecovering, DuplicateRecovered, DuplicateWrittenOff:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetDuplicateDeliveryKey get key of duplicate delivery
+func GetDuplicateDeliveryKey(fromChainID, txid string, logIndex int, duplicateTx string) string {
+	return fmt.Sprintf("%v:%v", GetRouterSwapKey(fromChainID, txid, logIndex), duplicateTx)
+}
+
+// AddDuplicateDelivery add duplicate delivery if not exist, isNew is false if it is already recorded
+func AddDuplicateDelivery(md *MgoDuplicateDelivery) (isNew bool, err error) {
+	md.Key = GetDuplicateDeliveryKey(md.FromChainID, md.TxID, md.LogIndex, md.DuplicateTx)
+	md.RecoveryStatus = DuplicateUnrecovered
+	md.DetectTime = time.Now().Unix()
+	md.UpdateTime = md.DetectTime
+	_, err = collDuplicate.InsertOne(clientCtx, md)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		log.Warn("mongodb add duplicate delivery failed", "key", md.Key, "err", err)
+		return false, mgoError(err)
+	}
+	mirrorDocs(collDuplicate, md.Key)
+	log.Info("mongodb add duplicate delivery success", "key", md.Key, "swaptx", md.SwapTx, "duplicateTx", md.DuplicateTx, "overpaid", md.OverpaidValue)
+	return true, nil
+}
+
+// FindDuplicateDeliveries find duplicate deliveries of recovery status (empty means all) in detect order
+func FindDuplicateDeliveries(status string, offset, limit int) ([]*MgoDuplicateDelivery, error) {
+	query := bson.M{}
+	if status != "" {
+		query["recoveryStatus"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "detectTime", Value: 1}}).
+		SetSkip(int64(offset)).SetLimit(int64(limit))
+	cur, err := collDuplicate.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoDuplicateDelivery, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// UpdateDuplicateRecovery update recovery progress of duplicate delivery
+func UpdateDuplicateRecovery(key, status, recoveryTx, memo, actor string) error {
+	updates := bson.M{
+		"recoveryStatus": status,
+		"actor":          EncryptedString(actor),
+		"updateTime":     time.Now().Unix(),
+	}
+	if recoveryTx != "" {
+		updates["recoveryTx"] = recoveryTx
+	}
+	if memo != "" {
+		updates["memo"] = memo
+	}
+	res, err := collDuplicate.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	if err == nil && res.MatchedCount == 0 {
+		err = mongo.ErrNoDocuments
+	}
+	if err == nil {
+		mirrorDocs(collDuplicate, key)
+		log.Info("mongodb update duplicate recovery success", "key", key, "status", status, "recoveryTx", recoveryTx, "actor", actor)
+	} else {
+		log.Warn("mongodb update duplicate recovery failed", "key", key, "status", status, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindStableSwapResultsWithOldSwapTxs find stable swap results which have
+// more than one swap txs (replaced or reswapped) updated in [since, before)
+func FindStableSwapResultsWithOldSwapTxs(since, before, limit int64) ([]*MgoSwapResult, error) {
+	query := bson.M{
+		"status":       MatchTxStable,
+		"timestamp":    bson.M{"$gte": since, "$lt": before},
+		"oldswaptxs.1": bson.M{"$exists": true},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}).SetLimit(limit)
+	cur, err := collRouterSwapResult.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapResult, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+func ensureDuplicateDeliveryIndexes() {
+	model := mongo.IndexModel{
+		Keys: bson.D{{Key: "recoveryStatus", Value: 1}, {Key: "detectTime", Value: 1}},
+	}
+	addExpectedIndexes(collDuplicate, []mongo.IndexModel{model})
+	name, err := collDuplicate.Indexes().CreateOne(clientCtx, model)
+	if err != nil {
+		log.Warn("[mongodb] create duplicate delivery indexes failed", "err", err)
+		return
+	}
+	log.Info("[mongodb] create duplicate delivery indexes success", "index", name)
+}
@@ -0,0 +1,195 @@
+package mongodb
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// encrypted value format is `enc:v1:<keyID>:<base64(nonce|ciphertext)>`
+const encryptedPrefix = "enc:v1:"
+
+var (
+	fieldKeys      map[string]cipher.AEAD
+	fieldCurKeyID  string
+	fieldKeysMutex sync.RWMutex
+
+	// collection and field names of the encrypted fields
+	encryptedFields = []struct {
+		coll  func() *mongo.Collection
+		field string
+	}{
+		{func() *mongo.Collection { return collAPIToken }, "name"},
+		{func() *mongo.Collection { return collSwapTransition }, "actor"},
+		{func() *mongo.Collection { return collDuplicate }, "actor"},
+		{func() *mongo.Collection { return collFeatureFlag }, "actor"},
+	}
+)
+
+// EncryptedString string field encrypted at rest if field encryption is enabled.
+// it is decrypted transparently on read, plaintext values written before
+// enabling encryption are read as is.
+type EncryptedString string
+
+// SetFieldEncryptionKeys set data keys of field encryption,
+// `current` is the key id used for encrypting, others are for decrypting only.
+func SetFieldEncryptionKeys(keys map[string][]byte, current string) error {
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for keyID, key := range keys {
+		if strings.Contains(keyID, ":") {
+			return fmt.Errorf("field encryption key id '%v' contains ':'", keyID)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return fmt.Errorf("field encryption key '%v': %w", keyID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return fmt.Errorf("field encryption key '%v': %w", keyID, err)
+		}
+		aeads[keyID] = aead
+	}
+	if _, exist := aeads[current]; !exist {
+		return fmt.Errorf("field encryption current key '%v' not found", current)
+	}
+	fieldKeysMutex.Lock()
+	defer fieldKeysMutex.Unlock()
+	fieldKeys = aeads
+	fieldCurKeyID = current
+	log.Info("[mongodb] set field encryption keys success", "current", current, "keys", len(aeads))
+	return nil
+}
+
+func encryptField(plaintext string) (string, error) {
+	fieldKeysMutex.RLock()
+	defer fieldKeysMutex.RUnlock()
+	if fieldCurKeyID == "" || plaintext == "" {
+		return plaintext, nil
+	}
+	aead := fieldKeys[fieldCurKeyID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(fieldCurKeyID))
+	return encryptedPrefix + fieldCurKeyID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptField(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(value, encryptedPrefix), ":", 2)
+	if len(parts) != 2 {
+		return "", errors.New("wrong encrypted field format")
+	}
+	keyID := parts[0]
+	fieldKeysMutex.RLock()
+	aead := fieldKeys[keyID]
+	fieldKeysMutex.RUnlock()
+	if aead == nil {
+		return "", fmt.Errorf("field encryption key '%v' not found", keyID)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("wrong encrypted field length")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// MarshalBSONValue implements bson.ValueMarshaler
+func (s EncryptedString) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	value, err := encryptField(string(s))
+	if err != nil {
+		return 0, nil, fmt.Errorf("encrypt field failed: %w", err)
+	}
+	return bson.MarshalValue(value)
+}
+
+// UnmarshalBSONValue implements bson.ValueUnmarshaler,
+// it returns error if the encrypted value can not be decrypted.
+func (s *EncryptedString) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t == bsontype.Null || t == bsontype.Undefined {
+		*s = ""
+		return nil
+	}
+	value, ok := bson.RawValue{Type: t, Value: data}.StringValueOK()
+	if !ok {
+		return fmt.Errorf("cannot decode %v into an EncryptedString", t)
+	}
+	plaintext, err := decryptField(value)
+	if err != nil {
+		return fmt.Errorf("decrypt field failed: %w", err)
+	}
+	*s = EncryptedString(plaintext)
+	return nil
+}
+
+// RotateEncryptedFields re-encrypt the encrypted fields which are
+// not encrypted by the current key (including plaintext values).
+func RotateEncryptedFields() {
+	fieldKeysMutex.RLock()
+	current := fieldCurKeyID
+	fieldKeysMutex.RUnlock()
+	if current == "" {
+		return
+	}
+	pattern := "^" + regexp.QuoteMeta(encryptedPrefix+current+":")
+	for _, ef := range encryptedFields {
+		coll := ef.coll()
+		query := bson.M{ef.field: bson.M{
+			"$type": "string",
+			"$ne":   "",
+			"$not":  primitive.Regex{Pattern: pattern},
+		}}
+		cur, err := coll.Find(clientCtx, query)
+		if err != nil {
+			log.Warn("[mongodb] rotate encrypted fields failed", "collection", coll.Name(), "field", ef.field, "err", err)
+			continue
+		}
+		var rotated, failed int
+		for cur.Next(clientCtx) {
+			key, _ := cur.Current.Lookup("_id").StringValueOK()
+			oldValue, _ := cur.Current.Lookup(ef.field).StringValueOK()
+			plaintext, err := decryptField(oldValue)
+			if err != nil {
+				log.Warn("[mongodb] rotate encrypted field failed", "collection", coll.Name(), "key", key, "field", ef.field, "err", err)
+				failed++
+				continue
+			}
+			// only update if unchanged to not overwrite concurrent writes
+			filter := bson.M{"_id": key, ef.field: oldValue}
+			update := bson.M{"$set": bson.M{ef.field: EncryptedString(plaintext)}}
+			if _, err = coll.UpdateOne(clientCtx, filter, update); err != nil {
+				log.Warn("[mongodb] rotate encrypted field failed", "collection", coll.Name(), "key", key, "field", ef.field, "err", err)
+				failed++
+				continue
+			}
+			mirrorDocs(coll, key)
+			rotated++
+		}
+		_ = cur.Close(clientCtx)
+		log.Info("[mongodb] rotate encrypted fields finished", "collection", coll.Name(), "field", ef.field, "current", current, "rotated", rotated, "failed", failed)
+	}
+}
@@ -0,0 +1,106 @@
+package mongodb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type testEncryptedDoc struct {
+	Name EncryptedString `bson:"name"`
+}
+
+func resetFieldEncryptionKeys() {
+	fieldKeysMutex.Lock()
+	defer fieldKeysMutex.Unlock()
+	fieldKeys = nil
+	fieldCurKeyID = ""
+}
+
+func marshalEncryptedDoc(t *testing.T, name string) (data []byte, stored string) {
+	data, err := bson.Marshal(&testEncryptedDoc{Name: EncryptedString(name)})
+	if err != nil {
+		t.Fatalf("marshal doc failed: %v", err)
+	}
+	stored, _ = bson.Raw(data).Lookup("name").StringValueOK()
+	return data, stored
+}
+
+func unmarshalEncryptedDoc(data []byte) (string, error) {
+	var doc testEncryptedDoc
+	err := bson.Unmarshal(data, &doc)
+	return string(doc.Name), err
+}
+
+func TestEncryptedStringRoundTrip(t *testing.T) {
+	defer resetFieldEncryptionKeys()
+
+	// plaintext written before enabling encryption is read as is
+	plainData, stored := marshalEncryptedDoc(t, "alice")
+	if stored != "alice" {
+		t.Fatalf("stored value without encryption got %v", stored)
+	}
+
+	err := SetFieldEncryptionKeys(map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}, "k1")
+	if err != nil {
+		t.Fatalf("set field encryption keys failed: %v", err)
+	}
+	data, stored := marshalEncryptedDoc(t, "alice")
+	if !strings.HasPrefix(stored, encryptedPrefix+"k1:") || strings.Contains(stored, "alice") {
+		t.Fatalf("stored value got %v, want encrypted by k1", stored)
+	}
+	if name, err := unmarshalEncryptedDoc(data); err != nil || name != "alice" {
+		t.Errorf("unmarshal encrypted got (%v, %v), want alice", name, err)
+	}
+	if name, err := unmarshalEncryptedDoc(plainData); err != nil || name != "alice" {
+		t.Errorf("unmarshal plaintext got (%v, %v), want alice", name, err)
+	}
+
+	// tampered value can not be decrypted
+	tampered := bytes.Replace(data, []byte(stored), []byte(stored[:len(stored)-4]+"AAA="), 1)
+	if _, err := unmarshalEncryptedDoc(tampered); err == nil {
+		t.Errorf("unmarshal tampered value should fail")
+	}
+}
+
+func TestEncryptedStringKeyRotation(t *testing.T) {
+	defer resetFieldEncryptionKeys()
+
+	k1, k2 := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	if err := SetFieldEncryptionKeys(map[string][]byte{"k1": k1}, "k1"); err != nil {
+		t.Fatalf("set field encryption keys failed: %v", err)
+	}
+	oldData, _ := marshalEncryptedDoc(t, "bob")
+
+	// old values are decrypted by the retired key, new values use the current key
+	if err := SetFieldEncryptionKeys(map[string][]byte{"k1": k1, "k2": k2}, "k2"); err != nil {
+		t.Fatalf("set field encryption keys failed: %v", err)
+	}
+	if name, err := unmarshalEncryptedDoc(oldData); err != nil || name != "bob" {
+		t.Errorf("unmarshal value of retired key got (%v, %v), want bob", name, err)
+	}
+	newData, stored := marshalEncryptedDoc(t, "bob")
+	if !strings.HasPrefix(stored, encryptedPrefix+"k2:") {
+		t.Errorf("stored value got %v, want encrypted by k2", stored)
+	}
+
+	// values of removed key fail to decode instead of leaking the ciphertext
+	if err := SetFieldEncryptionKeys(map[string][]byte{"k2": k2}, "k2"); err != nil {
+		t.Fatalf("set field encryption keys failed: %v", err)
+	}
+	if name, err := unmarshalEncryptedDoc(oldData); err == nil {
+		t.Errorf("unmarshal value of removed key got %v, want error", name)
+	}
+	if name, err := unmarshalEncryptedDoc(newData); err != nil || name != "bob" {
+		t.Errorf("unmarshal value of current key got (%v, %v), want bob", name, err)
+	}
+
+	if err := SetFieldEncryptionKeys(map[string][]byte{"k:3": k2}, "k:3"); err == nil {
+		t.Errorf("set key id containing ':' should fail")
+	}
+	if err := SetFieldEncryptionKeys(map[string][]byte{"k2": k2}, "k4"); err == nil {
+		t.Errorf("set missing current key should fail")
+	}
+}
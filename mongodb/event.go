@@ -0,0 +1,110 @@
+package mongodb
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// swap lifecycle events which are not kept in swap and swap result
+const (
+	SwapEventNonceAssigned = "nonceAssigned"
+	SwapEventSigned        = "signed"
+	SwapEventSent          = "sent"
+	SwapEventSendFailed    = "sendFailed"
+	SwapEventReplaced      = "replaced"
+	SwapEventStable        = "stable"
+	SwapEventFailed        = "failed"
+	SwapEventApproval      = "approval"
+	SwapEventGasRetry      = "gasRetry"
+	SwapEventDelivered     = "delivered"
+	SwapEventSendDelayed   = "sendDelayed"
+	SwapEventDuplicate     = "duplicateDelivered"
+	SwapEventReplaceGuard  = "replaceGuarded"
+	SwapEventRecovered     = "recovered"
+)
+
+// AddSwapEvent add lifecycle event of swap, duration is in milli seconds
+func AddSwapEvent(fromChainID, txid string, logIndex int, event string, duration int64, detail string) error {
+	swapKey := GetRouterSwapKey(fromChainID, txid, logIndex)
+	timestamp := common.NowMilli()
+	me := &MgoSwapEvent{
+		Key:       fmt.Sprintf("%v:%v:%v", swapKey, event, timestamp),
+		SwapKey:   swapKey,
+		Event:     event,
+		Timestamp: timestamp,
+		Duration:  duration,
+		Detail:    detail,
+	}
+	_, err := collSwapEvent.InsertOne(clientCtx, me)
+	if err == nil {
+		mirrorDocs(collSwapEvent, me.Key)
+		log.Debug("mongodb add swap event success", "chainid", fromChainID, "txid", txid, "logindex", logIndex, "event", event)
+	} else if !mongo.IsDuplicateKeyError(err) {
+		log.Warn("mongodb add swap event failed", "chainid", fromChainID, "txid", txid, "logindex", logIndex, "event", event, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindSwapEvents find lifecycle events of swap in time order
+func FindSwapEvents(fromChainID, txid string, logIndex int) ([]*MgoSwapEvent, error) {
+	swapKey := GetRouterSwapKey(fromChainID, txid, logIndex)
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "timestamp", Value: 1}},
+	}
+	cur, err := collSwapEvent.Find(clientCtx, bson.M{"swapkey": swapKey}, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapEvent, 0, 8)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// FindSwapEventsAfter find swap events after the position (timestamp and key of
+// the last event) and before `before` (milli seconds) in time order, events is the
+// filter of event names (all if empty).
+func FindSwapEventsAfter(timestamp int64, key string, before int64, events []string, limit int64) ([]*MgoSwapEvent, error) {
+	query := bson.M{
+		"$or": []bson.M{
+			{"timestamp": bson.M{"$gt": timestamp, "$lt": before}},
+			{"timestamp": timestamp, "_id": bson.M{"$gt": key}},
+		},
+	}
+	if len(events) > 0 {
+		query["event"] = bson.M{"$in": events}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit)
+	cur, err := collSwapEvent.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapEvent, 0, limit)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+func ensureSwapEventIndexes() {
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "swapkey", Value: 1}}},
+		{Keys: bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}},
+	}
+	addExpectedIndexes(collSwapEvent, models)
+	names, err := collSwapEvent.Indexes().CreateMany(clientCtx, models)
+	if err != nil {
+		log.Warn("[mongodb] create swap event indexes failed", "err", err)
+		return
+	}
+	log.Info("[mongodb] create swap event indexes success", "indexes", names)
+}
@@ -0,0 +1,165 @@
+package mongodb
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AccountingExport accounting extract of swaps in time range
+type AccountingExport struct {
+	StartTime int64 `json:"startTime"`
+	EndTime   int64 `json:"endTime"`
+	// Snapshot is true if all swaps are read from the same database snapshot
+	Snapshot bool `json:"snapshot"`
+	// ConsistencyToken is the checksum of the time range and the exported swaps,
+	// exporting the same range again gets the same token if and only if nothing changed.
+	ConsistencyToken string `json:"consistencyToken"`
+
+	Summaries []*AccountingSummary `json:"summaries"`
+	Swaps     []*MgoSwapResult     `json:"swaps"`
+}
+
+// AccountingSummary accounting summary of stable swaps of the same route
+type AccountingSummary struct {
+	TokenID        string `json:"tokenID"`
+	FromChainID    string `json:"fromChainID"`
+	ToChainID      string `json:"toChainID"`
+	Count          int    `json:"count"`
+	TotalValue     string `json:"totalValue"`
+	TotalSwapValue string `json:"totalSwapValue"`
+	TotalSwapFee   string `json:"totalSwapFee"`
+}
+
+// ExportAccounting export all swap results registered in the time range [startTime, endTime).
+// it reads in a snapshot session if the database supports (replica set of version 5.0+),
+// otherwise it falls back to normal reads and report `Snapshot` as false.
+func ExportAccounting(startTime, endTime int64) (*AccountingExport, error) {
+	if endTime <= startTime {
+		return nil, fmt.Errorf("wrong time range [%v, %v)", startTime, endTime)
+	}
+	export := &AccountingExport{
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+
+	err := exportSwapResults(export, true)
+	if err != nil {
+		log.Warn("[mongodb] export accounting with snapshot failed, fallback to normal reads", "err", err)
+		err = exportSwapResults(export, false)
+		if err != nil {
+			return nil, mgoError(err)
+		}
+	}
+
+	export.Summaries = summarizeAccounting(export.Swaps)
+	export.ConsistencyToken = getConsistencyToken(export)
+	log.Info("[mongodb] export accounting success", "startTime", startTime, "endTime", endTime, "snapshot", export.Snapshot, "swaps", len(export.Swaps), "token", export.ConsistencyToken)
+	return export, nil
+}
+
+func exportSwapResults(export *AccountingExport, snapshot bool) error {
+	sess, err := client.StartSession(options.Session().SetSnapshot(snapshot))
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(clientCtx)
+
+	// init time is milli seconds
+	query := bson.M{"inittime": bson.M{"$gte": export.StartTime * 1000, "$lt": export.EndTime * 1000}}
+	opts := options.Find().SetSort(bson.D{{Key: "inittime", Value: 1}, {Key: "_id", Value: 1}})
+
+	result := make([]*MgoSwapResult, 0, 100)
+	err = mongo.WithSession(clientCtx, sess, func(sc mongo.SessionContext) error {
+		cur, errf := collRouterSwapResult.Find(sc, query, opts)
+		if errf != nil {
+			return errf
+		}
+		return cur.All(sc, &result)
+	})
+	if err != nil {
+		return err
+	}
+
+	export.Snapshot = snapshot
+	export.Swaps = result
+	return nil
+}
+
+func summarizeAccounting(swaps []*MgoSwapResult) []*AccountingSummary {
+	type sums struct {
+		summary   *AccountingSummary
+		value     *big.Int
+		swapValue *big.Int
+		swapFee   *big.Int
+	}
+	sumsMap := make(map[string]*sums)
+	for _, swap := range swaps {
+		if swap.Status != MatchTxStable {
+			continue
+		}
+		tokenID := swap.GetTokenID()
+		key := fmt.Sprintf("%v:%v:%v", tokenID, swap.FromChainID, swap.ToChainID)
+		s, exist := sumsMap[key]
+		if !exist {
+			s = &sums{
+				summary: &AccountingSummary{
+					TokenID:     tokenID,
+					FromChainID: swap.FromChainID,
+					ToChainID:   swap.ToChainID,
+				},
+				value:     big.NewInt(0),
+				swapValue: big.NewInt(0),
+				swapFee:   big.NewInt(0),
+			}
+			sumsMap[key] = s
+		}
+		s.summary.Count++
+		if value, ok := new(big.Int).SetString(swap.Value, 0); ok {
+			s.value.Add(s.value, value)
+		}
+		if swapValue, ok := new(big.Int).SetString(swap.SwapValue, 0); ok {
+			s.swapValue.Add(s.swapValue, swapValue)
+		}
+		if swapFee, ok := new(big.Int).SetString(swap.SwapFee, 0); ok {
+			s.swapFee.Add(s.swapFee, swapFee)
+		}
+	}
+
+	result := make([]*AccountingSummary, 0, len(sumsMap))
+	for _, s := range sumsMap {
+		s.summary.TotalValue = s.value.String()
+		s.summary.TotalSwapValue = s.swapValue.String()
+		s.summary.TotalSwapFee = s.swapFee.String()
+		result = append(result, s.summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.TokenID != b.TokenID {
+			return a.TokenID < b.TokenID
+		}
+		if a.FromChainID != b.FromChainID {
+			return a.FromChainID < b.FromChainID
+		}
+		return a.ToChainID < b.ToChainID
+	})
+	return result
+}
+
+// getConsistencyToken checksum of the exported data only (no database state like cluster time),
+// the swaps are sorted by init time and key.
+func getConsistencyToken(export *AccountingExport) string {
+	hasher := sha256.New()
+	fmt.Fprintf(hasher, "%v|%v\n", export.StartTime, export.EndTime)
+	for _, swap := range export.Swaps {
+		fmt.Fprintf(hasher, "%v|%v|%v|%v|%v|%v|%v|%v\n", swap.Key, swap.InitTime, swap.Status, swap.Value, swap.SwapValue, swap.SwapTx, swap.SwapHeight, swap.SwapFee)
+	}
+	return fmt.Sprintf("%x", hasher.Sum(nil))
+}
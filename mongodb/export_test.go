@@ -0,0 +1,48 @@
+package mongodb
+
+import (
+	"testing"
+)
+
+var testERC20SwapInfo = SwapInfo{ERC20SwapInfo: &ERC20SwapInfo{TokenID: "USDC"}}
+
+func newTestExport() *AccountingExport {
+	return &AccountingExport{
+		StartTime: 1000,
+		EndTime:   2000,
+		Swaps: []*MgoSwapResult{
+			{Key: "1:0x01:0", FromChainID: "1", ToChainID: "56", SwapInfo: testERC20SwapInfo, Value: "1000", SwapValue: "990", SwapFee: "3", Status: MatchTxStable, InitTime: 1000100},
+			{Key: "1:0x02:0", FromChainID: "1", ToChainID: "56", SwapInfo: testERC20SwapInfo, Value: "2000", SwapValue: "1980", SwapFee: "4", Status: MatchTxStable, InitTime: 1000200},
+			{Key: "1:0x03:0", FromChainID: "1", ToChainID: "56", SwapInfo: testERC20SwapInfo, Value: "3000", SwapValue: "2970", Status: MatchTxNotStable, InitTime: 1000300},
+		},
+	}
+}
+
+func TestSummarizeAccounting(t *testing.T) {
+	summaries := summarizeAccounting(newTestExport().Swaps)
+	if len(summaries) != 1 {
+		t.Fatalf("summaries got %v, want 1", len(summaries))
+	}
+	s := summaries[0]
+	if s.Count != 2 || s.TotalValue != "3000" || s.TotalSwapValue != "2970" || s.TotalSwapFee != "7" {
+		t.Errorf("summary of stable swaps got %+v", s)
+	}
+}
+
+func TestConsistencyToken(t *testing.T) {
+	token := getConsistencyToken(newTestExport())
+	if again := getConsistencyToken(newTestExport()); again != token {
+		t.Errorf("token of the same data changed, got %v, want %v", again, token)
+	}
+
+	changed := newTestExport()
+	changed.Swaps[2].Status = MatchTxStable
+	if getConsistencyToken(changed) == token {
+		t.Errorf("token should change if the swap status changed")
+	}
+	changed = newTestExport()
+	changed.EndTime++
+	if getConsistencyToken(changed) == token {
+		t.Errorf("token should change if the time range changed")
+	}
+}
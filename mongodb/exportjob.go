@@ -0,0 +1,137 @@
+package mongodb
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// export job statuses
+const (
+	ExportJobRunning = "running"
+	ExportJobDone    = "done"
+	ExportJobFailed  = "failed"
+)
+
+// AddExportJob add export job if the running jobs are less than maxRunning
+func AddExportJob(mj *MgoExportJob, maxRunning int) (bool, error) {
+	running, err := collExportJob.CountDocuments(clientCtx, bson.M{"status": ExportJobRunning})
+	if err != nil {
+		return false, mgoError(err)
+	}
+	if running >= int64(maxRunning) {
+		return false, nil
+	}
+	_, err = collExportJob.InsertOne(clientCtx, mj)
+	if err != nil {
+		log.Warn("mongodb add export job failed", "id", mj.Key, "err", err)
+		return false, mgoError(err)
+	}
+	mirrorDocs(collExportJob, mj.Key)
+	log.Info("mongodb add export job success", "id", mj.Key, "host", mj.Host)
+	return true, nil
+}
+
+// FindExportJob find export job
+func FindExportJob(id string) (*MgoExportJob, error) {
+	result := &MgoExportJob{}
+	err := collExportJob.FindOne(clientCtx, bson.M{"_id": id}).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// UpdateExportJobHeartbeat update heartbeat time of running export job
+func UpdateExportJobHeartbeat(id string, updateTime int64) error {
+	query := bson.M{"_id": id, "status": ExportJobRunning}
+	_, err := collExportJob.UpdateOne(clientCtx, query, bson.M{"$set": bson.M{"updatetime": updateTime}})
+	if err != nil {
+		log.Warn("mongodb update export job heartbeat failed", "id", id, "err", err)
+		return mgoError(err)
+	}
+	mirrorDocs(collExportJob, id)
+	return nil
+}
+
+// FinishExportJob finish running export job with status done or failed
+func FinishExportJob(id, status string, rows int64, errMsg string, finishTime int64) error {
+	query := bson.M{"_id": id, "status": ExportJobRunning}
+	updates := bson.M{
+		"status":     status,
+		"rows":       rows,
+		"error":      errMsg,
+		"updatetime": finishTime,
+		"finishtime": finishTime,
+	}
+	_, err := collExportJob.UpdateOne(clientCtx, query, bson.M{"$set": updates})
+	if err != nil {
+		log.Warn("mongodb finish export job failed", "id", id, "status", status, "err", err)
+		return mgoError(err)
+	}
+	mirrorDocs(collExportJob, id)
+	log.Info("mongodb finish export job success", "id", id, "status", status, "rows", rows)
+	return nil
+}
+
+// FailStaleExportJobs fail running export jobs which have no heartbeat since `before` (seconds),
+// their runners are stopped (eg. restarted) and the jobs will never finish.
+func FailStaleExportJobs(before, now int64) (int64, error) {
+	query := bson.M{"status": ExportJobRunning, "updatetime": bson.M{"$lt": before}}
+	keys, err := findKeys(collExportJob, query, 0)
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	query["_id"] = bson.M{"$in": keys}
+	updates := bson.M{
+		"status":     ExportJobFailed,
+		"error":      "export job is interrupted",
+		"finishtime": now,
+	}
+	res, err := collExportJob.UpdateMany(clientCtx, query, bson.M{"$set": updates})
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	mirrorDocs(collExportJob, keys...)
+	return res.ModifiedCount, nil
+}
+
+// FindExpiredExportJobs find finished export jobs before `before` (seconds)
+func FindExpiredExportJobs(before int64) ([]*MgoExportJob, error) {
+	query := bson.M{"status": bson.M{"$ne": ExportJobRunning}, "finishtime": bson.M{"$lt": before}}
+	cur, err := collExportJob.Find(clientCtx, query)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoExportJob, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// RemoveExportJob remove export job
+func RemoveExportJob(id string) error {
+	_, err := collExportJob.DeleteOne(clientCtx, bson.M{"_id": id})
+	if err != nil {
+		log.Warn("mongodb remove export job failed", "id", id, "err", err)
+		return mgoError(err)
+	}
+	mirrorDocs(collExportJob, id)
+	return nil
+}
+
+func ensureExportJobIndexes() {
+	model := mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "updatetime", Value: 1}},
+	}
+	addExpectedIndexes(collExportJob, []mongo.IndexModel{model})
+	name, err := collExportJob.Indexes().CreateOne(clientCtx, model)
+	if err != nil {
+		log.Warn("[mongodb] create export job indexes failed", "err", err)
+		return
+	}
+	log.Info("[mongodb] create export job indexes success", "index", name)
+}
@@ -0,0 +1,64 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetFeatureFlagKey get key of feature flag override
+func GetFeatureFlagKey(flag, chainID string) string {
+	return flag + ":" + chainID
+}
+
+// SetFeatureFlag add or update feature flag override
+func SetFeatureFlag(flag, chainID string, enabled bool, actor string) error {
+	key := GetFeatureFlagKey(flag, chainID)
+	mf := &MgoFeatureFlag{
+		Key:        key,
+		Flag:       flag,
+		ChainID:    chainID,
+		Enabled:    enabled,
+		Actor:      EncryptedString(actor),
+		UpdateTime: time.Now().Unix(),
+	}
+	_, err := collFeatureFlag.ReplaceOne(clientCtx, bson.M{"_id": key}, mf, options.Replace().SetUpsert(true))
+	if err == nil {
+		mirrorDocs(collFeatureFlag, key)
+		log.Info("mongodb set feature flag success", "flag", flag, "chainID", chainID, "enabled", enabled, "actor", actor)
+	} else {
+		log.Warn("mongodb set feature flag failed", "flag", flag, "chainID", chainID, "enabled", enabled, "err", err)
+	}
+	return mgoError(err)
+}
+
+// RemoveFeatureFlag remove feature flag override
+func RemoveFeatureFlag(flag, chainID string) error {
+	key := GetFeatureFlagKey(flag, chainID)
+	_, err := collFeatureFlag.DeleteOne(clientCtx, bson.M{"_id": key})
+	if err == nil {
+		mirrorDocs(collFeatureFlag, key)
+		log.Info("mongodb remove feature flag success", "flag", flag, "chainID", chainID)
+	} else {
+		log.Warn("mongodb remove feature flag failed", "flag", flag, "chainID", chainID, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindFeatureFlags find all feature flag overrides
+func FindFeatureFlags() ([]*MgoFeatureFlag, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cur, err := collFeatureFlag.Find(clientCtx, bson.M{}, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoFeatureFlag, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,44 @@
+package mongodb
+
+import (
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetGasSamplesKey get key of gas samples
+func GetGasSamplesKey(chainID, tokenID, method string) string {
+	return strings.ToLower(chainID + ":" + tokenID + ":" + method)
+}
+
+// UpdateGasSamples add or replace gas samples of token and swap method
+func UpdateGasSamples(ms *MgoGasSamples) error {
+	ms.Key = GetGasSamplesKey(ms.ChainID, ms.TokenID, ms.Method)
+	ms.Timestamp = time.Now().Unix()
+	opts := options.Replace().SetUpsert(true)
+	_, err := collGasSamples.ReplaceOne(clientCtx, bson.M{"_id": ms.Key}, ms, opts)
+	if err != nil {
+		log.Warn("mongodb update gas samples failed", "key", ms.Key, "err", err)
+		return mgoError(err)
+	}
+	mirrorDocs(collGasSamples, ms.Key)
+	return nil
+}
+
+// FindAllGasSamples find all gas samples
+func FindAllGasSamples() ([]*MgoGasSamples, error) {
+	cur, err := collGasSamples.Find(clientCtx, bson.M{})
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoGasSamples, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
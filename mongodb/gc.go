@@ -0,0 +1,224 @@
+package mongodb
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// old swap txs are only needed to check which replaced tx is mined before
+// the swap is stable, and the replace count of pending swaps is the length
+// of old swap txs, so only finalized swaps are garbage collected.
+
+// PruneFinalizedOldSwapTxs remove old swap txs of stable swaps finalized before `before`
+func PruneFinalizedOldSwapTxs(before, limit int64) (int64, error) {
+	query := bson.M{
+		"status":       MatchTxStable,
+		"timestamp":    bson.M{"$lt": before},
+		"oldswaptxs.0": bson.M{"$exists": true},
+	}
+	keys, err := findKeys(collRouterSwapResult, query, limit)
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	query["_id"] = bson.M{"$in": keys}
+
+	updateResultLock.Lock()
+	defer updateResultLock.Unlock()
+
+	res, err := collRouterSwapResult.UpdateMany(clientCtx, query, bson.M{"$unset": bson.M{"oldswaptxs": ""}})
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	mirrorDocs(collRouterSwapResult, keys...)
+	addOldSwapTxsChanges(keys, "old swap txs pruned")
+	return res.ModifiedCount, nil
+}
+
+// CompactOldSwapTxs keep the latest `maxLen` old swap txs of stable swaps finalized before `before`
+func CompactOldSwapTxs(before int64, maxLen int, limit int64) (int64, error) {
+	query := bson.M{
+		"status":    MatchTxStable,
+		"timestamp": bson.M{"$lt": before},
+	}
+	query[fmt.Sprintf("oldswaptxs.%d", maxLen)] = bson.M{"$exists": true}
+	keys, err := findKeys(collRouterSwapResult, query, limit)
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	query["_id"] = bson.M{"$in": keys}
+	updates := bson.M{
+		"$push": bson.M{
+			"oldswaptxs": bson.M{"$each": []string{}, "$slice": -maxLen},
+		},
+	}
+
+	updateResultLock.Lock()
+	defer updateResultLock.Unlock()
+
+	res, err := collRouterSwapResult.UpdateMany(clientCtx, query, updates)
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	mirrorDocs(collRouterSwapResult, keys...)
+	addOldSwapTxsChanges(keys, "old swap txs compacted")
+	return res.ModifiedCount, nil
+}
+
+// addOldSwapTxsChanges record the old swap txs of garbage collected swap results
+// in swap history. the batch update modifies each result differently (or not at all
+// if it is updated meanwhile), so the current values are read back and recorded.
+func addOldSwapTxsChanges(keys []string, reason string) {
+	opts := options.Find().SetProjection(bson.M{"oldswaptxs": 1})
+	cur, err := collRouterSwapResult.Find(clientCtx, bson.M{"_id": bson.M{"$in": keys}}, opts)
+	if err != nil {
+		log.Warn("mongodb find garbage collected old swap txs failed", "count", len(keys), "err", err)
+		return
+	}
+	var results []*struct {
+		Key        string   `bson:"_id"`
+		OldSwapTxs []string `bson:"oldswaptxs"`
+	}
+	if err = cur.All(clientCtx, &results); err != nil {
+		log.Warn("mongodb decode garbage collected old swap txs failed", "count", len(keys), "err", err)
+		return
+	}
+	for _, res := range results {
+		addSwapChanges(collRouterSwapResult, res.Key, bson.M{"oldswaptxs": res.OldSwapTxs}, ActorRouter, reason)
+	}
+}
+
+// RemoveOrphanedSwapResults remove pending swap results (not swapped yet)
+// not updated since `before` whose router swap is deleted
+func RemoveOrphanedSwapResults(before, limit int64) (int64, error) {
+	query := bson.M{
+		"status":    MatchTxEmpty,
+		"swaptx":    "",
+		"timestamp": bson.M{"$lt": before},
+	}
+	return removeOrphans(collRouterSwapResult, collRouterSwap, query, limit)
+}
+
+// RemoveOrphanedQueueItems remove status queue items not updated since `before`
+// whose swap or swap result is deleted
+func RemoveOrphanedQueueItems(before, limit int64) (int64, error) {
+	if !statusQueuesEnabled {
+		return 0, nil
+	}
+	query := bson.M{"timestamp": bson.M{"$lt": before}}
+	var total int64
+	for _, queue := range swapQueues {
+		count, err := removeOrphans(queue, collRouterSwap, query, limit)
+		if err != nil {
+			return total, err
+		}
+		total += count
+	}
+	for _, queue := range resultQueues {
+		count, err := removeOrphans(queue, collRouterSwapResult, query, limit)
+		if err != nil {
+			return total, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// PruneSwapEvents remove swap lifecycle events recorded before `before` (seconds)
+func PruneSwapEvents(before, limit int64) (int64, error) {
+	query := bson.M{"timestamp": bson.M{"$lt": before * 1000}}
+	keys, err := findKeys(collSwapEvent, query, limit)
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	res, err := collSwapEvent.DeleteMany(clientCtx, bson.M{"_id": bson.M{"$in": keys}})
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	mirrorDocs(collSwapEvent, keys...)
+	return res.DeletedCount, nil
+}
+
+// removeOrphans remove items matching query whose key is not found in parent collection
+func removeOrphans(coll, parent *mongo.Collection, query bson.M, limit int64) (int64, error) {
+	keys, err := findKeys(coll, query, limit)
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	existKeys, err := findKeys(parent, bson.M{"_id": bson.M{"$in": keys}}, 0)
+	if err != nil {
+		return 0, err
+	}
+	exist := make(map[string]bool, len(existKeys))
+	for _, key := range existKeys {
+		exist[key] = true
+	}
+	orphans := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !exist[key] {
+			orphans = append(orphans, key)
+		}
+	}
+	if len(orphans) == 0 {
+		return 0, nil
+	}
+	// recheck the query on deleting to keep items refreshed meanwhile
+	deleteQuery := bson.M{"_id": bson.M{"$in": orphans}}
+	for k, v := range query {
+		deleteQuery[k] = v
+	}
+	res, err := coll.DeleteMany(clientCtx, deleteQuery)
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	if coll == collRouterSwapResult {
+		mirrorDocs(coll, orphans...)
+		addOrphanRemovals(coll, orphans)
+	}
+	log.Info("[mongodb] remove orphaned items", "collection", coll.Name(), "count", res.DeletedCount)
+	return res.DeletedCount, nil
+}
+
+// addOrphanRemovals record removal of the deleted orphans in swap history,
+// the orphans refreshed meanwhile are not deleted and are skipped.
+func addOrphanRemovals(coll *mongo.Collection, orphans []string) {
+	remainKeys, err := findKeys(coll, bson.M{"_id": bson.M{"$in": orphans}}, 0)
+	if err != nil {
+		log.Warn("[mongodb] find remaining orphaned items failed", "collection", coll.Name(), "err", err)
+		return
+	}
+	remain := make(map[string]bool, len(remainKeys))
+	for _, key := range remainKeys {
+		remain[key] = true
+	}
+	for _, key := range orphans {
+		if !remain[key] {
+			addSwapRemoval(coll, key, ActorRouter, "orphaned swap result removed")
+		}
+	}
+}
+
+// findKeys find keys of items matching query (limit 0 means no limit)
+func findKeys(coll *mongo.Collection, query bson.M, limit int64) ([]string, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	cur, err := coll.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	items := make([]*MgoQueueItem, 0, 20)
+	if err = cur.All(clientCtx, &items); err != nil {
+		return nil, mgoError(err)
+	}
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+	}
+	return keys, nil
+}
@@ -0,0 +1,63 @@
+package mongodb
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	// expected index names of collections, key is collection
+	expectedIndexes     = make(map[*mongo.Collection][]string)
+	expectedIndexesLock sync.Mutex
+)
+
+func addExpectedIndexes(coll *mongo.Collection, models []mongo.IndexModel) {
+	expectedIndexesLock.Lock()
+	defer expectedIndexesLock.Unlock()
+	for _, model := range models {
+		expectedIndexes[coll] = append(expectedIndexes[coll], getIndexName(model))
+	}
+}
+
+// getIndexName get index name in the same way as mongodb does by default
+func getIndexName(model mongo.IndexModel) string {
+	if model.Options != nil && model.Options.Name != nil {
+		return *model.Options.Name
+	}
+	keys, ok := model.Keys.(bson.D)
+	if !ok {
+		return ""
+	}
+	parts := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		parts = append(parts, key.Key, fmt.Sprint(key.Value))
+	}
+	return strings.Join(parts, "_")
+}
+
+// FindMissingIndexes find indexes which are expected but not present,
+// the result items are in format of 'collection.index'.
+func FindMissingIndexes() (missing []string, err error) {
+	expectedIndexesLock.Lock()
+	defer expectedIndexesLock.Unlock()
+	for coll, names := range expectedIndexes {
+		specs, errf := coll.Indexes().ListSpecifications(clientCtx)
+		if errf != nil {
+			return nil, mgoError(errf)
+		}
+		present := make(map[string]bool, len(specs))
+		for _, spec := range specs {
+			present[spec.Name] = true
+		}
+		for _, name := range names {
+			if name != "" && !present[name] {
+				missing = append(missing, coll.Name()+"."+name)
+			}
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,392 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// integrity finding kinds
+const (
+	FindingSwapWithoutResult = "swapWithoutResult"
+	FindingResultWithoutSwap = "resultWithoutSwap"
+	FindingImpossibleStatus  = "impossibleStatus"
+	FindingDuplicateNonce    = "duplicateNonce"
+)
+
+// IntegrityFinding divergence between swap and swap result collections.
+// repairable findings are known-benign divergences left by interrupted
+// two-step writes, the others need manual investigation.
+type IntegrityFinding struct {
+	Kind         string   `json:"kind"`
+	Keys         []string `json:"keys"`
+	ToChainID    string   `json:"toChainID,omitempty"`
+	SwapStatus   string   `json:"swapStatus,omitempty"`
+	ResultStatus string   `json:"resultStatus,omitempty"`
+	MPC          string   `json:"mpc,omitempty"`
+	SwapNonce    uint64   `json:"swapNonce,omitempty"`
+	Detail       string   `json:"detail"`
+	Repairable   bool     `json:"repairable"`
+	Repaired     bool     `json:"repaired"`
+	RepairError  string   `json:"repairError,omitempty"`
+
+	repair func() error
+}
+
+// IntegrityReport report of integrity check
+type IntegrityReport struct {
+	StartTime      int64               `json:"startTime"`
+	EndTime        int64               `json:"endTime"`
+	ScanSince      int64               `json:"scanSince"`
+	ScanBefore     int64               `json:"scanBefore"`
+	ScannedSwaps   int                 `json:"scannedSwaps"`
+	ScannedResults int                 `json:"scannedResults"`
+	Repaired       int                 `json:"repaired"`
+	Findings       []*IntegrityFinding `json:"findings"`
+}
+
+// IntegrityCheckArgs args of integrity check
+type IntegrityCheckArgs struct {
+	Since      int64 // seconds, check records updated since
+	Before     int64 // seconds, skip records updated after (still in processing)
+	BatchSize  int64
+	AutoRepair bool
+}
+
+// nonce allocated, swap status should be TxProcessed
+func isNonceAllocatedStatus(status SwapStatus) bool {
+	switch status {
+	case MatchTxNotStable, MatchTxStable, MatchTxFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// CheckIntegrity compare swap and swap result collections,
+// and repair known-benign divergences if `AutoRepair` is set.
+func CheckIntegrity(args *IntegrityCheckArgs) (*IntegrityReport, error) {
+	report := &IntegrityReport{
+		StartTime:  time.Now().Unix(),
+		ScanSince:  args.Since,
+		ScanBefore: args.Before,
+		Findings:   make([]*IntegrityFinding, 0),
+	}
+	timeQuery := bson.M{"$gte": args.Since, "$lt": args.Before}
+
+	swaps := make([]*MgoSwap, 0, 20)
+	err := findIntegrityItems(collRouterSwap, bson.M{
+		"status":    bson.M{"$in": []SwapStatus{TxNotSwapped, TxProcessed}},
+		"timestamp": timeQuery,
+	}, args.BatchSize, &swaps)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*MgoSwapResult, 0, 20)
+	err = findIntegrityItems(collRouterSwapResult, bson.M{
+		"timestamp": timeQuery,
+	}, args.BatchSize, &results)
+	if err != nil {
+		return nil, err
+	}
+	report.ScannedSwaps = len(swaps)
+	report.ScannedResults = len(results)
+
+	swapsMap := make(map[string]*MgoSwap, len(swaps)+len(results))
+	resultsMap := make(map[string]*MgoSwapResult, len(swaps)+len(results))
+	for _, swap := range swaps {
+		swapsMap[swap.Key] = swap
+	}
+	for _, res := range results {
+		resultsMap[res.Key] = res
+	}
+	if err = fillCounterparts(swapsMap, resultsMap); err != nil {
+		return nil, err
+	}
+
+	checked := make(map[string]bool, len(swaps)+len(results))
+	for _, swap := range swaps {
+		checked[swap.Key] = true
+		res := resultsMap[swap.Key]
+		if res == nil {
+			report.addFinding(checkSwapWithoutResult(swap))
+			continue
+		}
+		report.addFinding(checkStatusCombination(swap, res))
+	}
+	for _, res := range results {
+		if checked[res.Key] {
+			continue
+		}
+		swap := swapsMap[res.Key]
+		if swap == nil {
+			report.addFinding(checkResultWithoutSwap(res))
+			continue
+		}
+		report.addFinding(checkStatusCombination(swap, res))
+	}
+
+	dupNonces, err := findDuplicateNonces(args.Since, args.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	report.Findings = append(report.Findings, dupNonces...)
+
+	if args.AutoRepair {
+		for _, finding := range report.Findings {
+			if !finding.Repairable || finding.repair == nil {
+				continue
+			}
+			if err = finding.repair(); err != nil {
+				finding.RepairError = err.Error()
+				log.Warn("[mongodb] repair integrity finding failed", "kind", finding.Kind, "keys", finding.Keys, "err", err)
+				continue
+			}
+			finding.Repaired = true
+			report.Repaired++
+			log.Info("[mongodb] repair integrity finding success", "kind", finding.Kind, "keys", finding.Keys, "detail", finding.Detail)
+		}
+	}
+	report.EndTime = time.Now().Unix()
+	return report, nil
+}
+
+func (r *IntegrityReport) addFinding(finding *IntegrityFinding) {
+	if finding != nil {
+		r.Findings = append(r.Findings, finding)
+	}
+}
+
+func findIntegrityItems(coll *mongo.Collection, query bson.M, limit int64, result interface{}) error {
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	cur, err := coll.Find(clientCtx, query, opts)
+	if err != nil {
+		return mgoError(err)
+	}
+	return mgoError(cur.All(clientCtx, result))
+}
+
+// fillCounterparts load swaps of results and results of swaps not loaded yet
+func fillCounterparts(swapsMap map[string]*MgoSwap, resultsMap map[string]*MgoSwapResult) error {
+	var missSwaps, missResults []string
+	for key := range resultsMap {
+		if _, exist := swapsMap[key]; !exist {
+			missSwaps = append(missSwaps, key)
+		}
+	}
+	for key := range swapsMap {
+		if _, exist := resultsMap[key]; !exist {
+			missResults = append(missResults, key)
+		}
+	}
+	if len(missSwaps) > 0 {
+		swaps := make([]*MgoSwap, 0, len(missSwaps))
+		if err := findIntegrityItems(collRouterSwap, bson.M{"_id": bson.M{"$in": missSwaps}}, 0, &swaps); err != nil {
+			return err
+		}
+		for _, swap := range swaps {
+			swapsMap[swap.Key] = swap
+		}
+	}
+	if len(missResults) > 0 {
+		results := make([]*MgoSwapResult, 0, len(missResults))
+		if err := findIntegrityItems(collRouterSwapResult, bson.M{"_id": bson.M{"$in": missResults}}, 0, &results); err != nil {
+			return err
+		}
+		for _, res := range results {
+			resultsMap[res.Key] = res
+		}
+	}
+	return nil
+}
+
+func checkSwapWithoutResult(swap *MgoSwap) *IntegrityFinding {
+	finding := &IntegrityFinding{
+		Kind:       FindingSwapWithoutResult,
+		Keys:       []string{swap.Key},
+		ToChainID:  swap.ToChainID,
+		SwapStatus: swap.Status.String(),
+	}
+	switch swap.Status {
+	case TxNotSwapped:
+		// verify passed but adding the initial swap result failed,
+		// verify the swap again to add the swap result.
+		finding.Detail = "verified swap has no swap result, verify it again"
+		finding.Repairable = true
+		finding.repair = func() error { return reverifySwapWithoutResult(swap) }
+	default:
+		finding.Detail = "processed swap has no swap result"
+	}
+	return finding
+}
+
+func checkResultWithoutSwap(res *MgoSwapResult) *IntegrityFinding {
+	finding := &IntegrityFinding{
+		Kind:         FindingResultWithoutSwap,
+		Keys:         []string{res.Key},
+		ToChainID:    res.ToChainID,
+		ResultStatus: res.Status.String(),
+		MPC:          res.MPC,
+		SwapNonce:    res.SwapNonce,
+	}
+	if res.Status == MatchTxEmpty && res.SwapTx == "" && res.SwapNonce == 0 {
+		finding.Detail = "pending swap result has no swap, remove it"
+		finding.Repairable = true
+		finding.repair = func() error { return removeOrphanedSwapResult(res.Key) }
+	} else {
+		finding.Detail = "swap result has no swap"
+	}
+	return finding
+}
+
+func checkStatusCombination(swap *MgoSwap, res *MgoSwapResult) *IntegrityFinding {
+	newFinding := func(detail string) *IntegrityFinding {
+		return &IntegrityFinding{
+			Kind:         FindingImpossibleStatus,
+			Keys:         []string{swap.Key},
+			ToChainID:    res.ToChainID,
+			SwapStatus:   swap.Status.String(),
+			ResultStatus: res.Status.String(),
+			MPC:          res.MPC,
+			SwapNonce:    res.SwapNonce,
+			Detail:       detail,
+		}
+	}
+	switch {
+	case res.Status == MatchTxStable && res.SwapTx == "":
+		return newFinding("stable swap result has no swap tx")
+	case isNonceAllocatedStatus(res.Status) && swap.Status == TxNotSwapped:
+		// updating swap status failed after swap nonce is allocated
+		finding := newFinding("swap status is behind swap result, set it to processed")
+		finding.Repairable = true
+		finding.repair = func() error { return setSwapProcessed(swap.Key, res.FromChainID, res.TxID, res.LogIndex) }
+		return finding
+	case isNonceAllocatedStatus(res.Status) && swap.Status != TxProcessed:
+		return newFinding("swap result is swapped but swap is not processed")
+	case res.Status == MatchTxEmpty && (res.SwapTx != "" || res.SwapNonce > 0):
+		return newFinding("pending swap result has swap tx or swap nonce")
+	case res.Status == MatchTxEmpty && swap.Status == TxProcessed:
+		return newFinding("processed swap has pending swap result")
+	}
+	return nil
+}
+
+// findDuplicateNonces find swap results sharing the same nonce of chain and mpc
+func findDuplicateNonces(since, limit int64) ([]*IntegrityFinding, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"swapnonce": bson.M{"$gt": 0}, "timestamp": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"toChainID": "$toChainID", "mpc": "$mpc", "swapnonce": "$swapnonce"},
+			"keys":  bson.M{"$push": "$_id"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	}
+	if limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	}
+	cur, err := collRouterSwapResult.Aggregate(clientCtx, pipeline)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	var groups []struct {
+		ID struct {
+			ToChainID string `bson:"toChainID"`
+			MPC       string `bson:"mpc"`
+			SwapNonce uint64 `bson:"swapnonce"`
+		} `bson:"_id"`
+		Keys  []string `bson:"keys"`
+		Count int      `bson:"count"`
+	}
+	if err = cur.All(clientCtx, &groups); err != nil {
+		return nil, mgoError(err)
+	}
+	findings := make([]*IntegrityFinding, 0, len(groups))
+	for _, group := range groups {
+		findings = append(findings, &IntegrityFinding{
+			Kind:      FindingDuplicateNonce,
+			Keys:      group.Keys,
+			ToChainID: group.ID.ToChainID,
+			MPC:       group.ID.MPC,
+			SwapNonce: group.ID.SwapNonce,
+			Detail:    fmt.Sprintf("%v swap results use the same nonce", group.Count),
+		})
+	}
+	return findings, nil
+}
+
+// reverifySwapWithoutResult reset verified swap without swap result to be verified again
+func reverifySwapWithoutResult(swap *MgoSwap) error {
+	updateResultLock.Lock()
+	defer updateResultLock.Unlock()
+
+	count, err := collRouterSwapResult.CountDocuments(clientCtx, bson.M{"_id": swap.Key})
+	if err != nil {
+		return mgoError(err)
+	}
+	if count > 0 {
+		return errors.New("swap result exists now")
+	}
+	timestamp := time.Now().Unix()
+	memo := "integrity check: verify again"
+	updates := bson.M{"status": TxNotStable, "timestamp": timestamp, "memo": memo}
+	matched, err := updateSwapStatusByID(collRouterSwap, swap.Key, bson.M{"status": TxNotSwapped}, updates, ActorIntegrity, memo)
+	if err != nil {
+		return mgoError(err)
+	}
+	if !matched {
+		return errors.New("swap status changed")
+	}
+	mirrorDocs(collRouterSwap, swap.Key)
+	enqueueSwap(swap.Key, TxNotStable, timestamp, swap.InitTime)
+	return nil
+}
+
+// removeOrphanedSwapResult remove pending swap result whose swap is deleted
+func removeOrphanedSwapResult(key string) error {
+	query := bson.M{"_id": key, "status": MatchTxEmpty, "swaptx": "", "swapnonce": 0}
+	count, err := removeOrphans(collRouterSwapResult, collRouterSwap, query, 1)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("swap result changed")
+	}
+	return nil
+}
+
+// setSwapProcessed set swap status to processed if it is still not swapped
+// and its swap result is still nonce allocated (not reswapping)
+func setSwapProcessed(key, fromChainID, txid string, logindex int) error {
+	updateResultLock.Lock()
+	defer updateResultLock.Unlock()
+
+	swapRes, err := FindRouterSwapResult(fromChainID, txid, logindex)
+	if err != nil {
+		return err
+	}
+	if !isNonceAllocatedStatus(swapRes.Status) {
+		return errors.New("swap result status changed")
+	}
+	timestamp := time.Now().Unix()
+	updates := bson.M{"status": TxProcessed, "timestamp": timestamp}
+	matched, err := updateSwapStatusByID(collRouterSwap, key, bson.M{"status": TxNotSwapped}, updates, ActorIntegrity, "integrity check: swap result nonce allocated")
+	if err != nil {
+		return mgoError(err)
+	}
+	if !matched {
+		return errors.New("swap status changed")
+	}
+	mirrorDocs(collRouterSwap, key)
+	enqueueSwap(key, TxProcessed, timestamp, 0)
+	return nil
+}
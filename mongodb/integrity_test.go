@@ -0,0 +1,64 @@
+package mongodb
+
+import "testing"
+
+func TestCheckSwapAndResultWithoutCounterpart(t *testing.T) {
+	tests := []struct {
+		finding    *IntegrityFinding
+		kind       string
+		repairable bool
+	}{
+		{checkSwapWithoutResult(&MgoSwap{Key: "1:0x01:0", Status: TxNotSwapped}), FindingSwapWithoutResult, true},
+		{checkSwapWithoutResult(&MgoSwap{Key: "1:0x01:0", Status: TxProcessed}), FindingSwapWithoutResult, false},
+		{checkResultWithoutSwap(&MgoSwapResult{Key: "1:0x01:0", Status: MatchTxEmpty}), FindingResultWithoutSwap, true},
+		{checkResultWithoutSwap(&MgoSwapResult{Key: "1:0x01:0", Status: MatchTxEmpty, SwapNonce: 3}), FindingResultWithoutSwap, false},
+		{checkResultWithoutSwap(&MgoSwapResult{Key: "1:0x01:0", Status: MatchTxStable, SwapTx: "0x02"}), FindingResultWithoutSwap, false},
+	}
+	for i, test := range tests {
+		finding := test.finding
+		if finding.Kind != test.kind || finding.Repairable != test.repairable || (finding.repair != nil) != test.repairable {
+			t.Errorf("test %v: finding got kind %v repairable %v, want kind %v repairable %v", i, finding.Kind, finding.Repairable, test.kind, test.repairable)
+		}
+	}
+}
+
+func TestCheckStatusCombination(t *testing.T) {
+	tests := []struct {
+		swapStatus SwapStatus
+		res        *MgoSwapResult
+		found      bool
+		repairable bool
+	}{
+		// consistent combinations
+		{TxNotSwapped, &MgoSwapResult{Status: MatchTxEmpty}, false, false},
+		{TxProcessed, &MgoSwapResult{Status: MatchTxNotStable, SwapTx: "0x02", SwapNonce: 3}, false, false},
+		{TxProcessed, &MgoSwapResult{Status: MatchTxStable, SwapTx: "0x02", SwapNonce: 3}, false, false},
+		// swap status update failed after nonce allocated
+		{TxNotSwapped, &MgoSwapResult{Status: MatchTxNotStable, SwapNonce: 3}, true, true},
+		// impossible combinations need manual investigation
+		{TxProcessed, &MgoSwapResult{Status: MatchTxStable}, true, false},
+		{TxVerifyFailed, &MgoSwapResult{Status: MatchTxFailed, SwapTx: "0x02", SwapNonce: 3}, true, false},
+		{TxNotSwapped, &MgoSwapResult{Status: MatchTxEmpty, SwapNonce: 3}, true, false},
+		{TxProcessed, &MgoSwapResult{Status: MatchTxEmpty}, true, false},
+	}
+	for i, test := range tests {
+		swap := &MgoSwap{Key: "1:0x01:0", Status: test.swapStatus}
+		finding := checkStatusCombination(swap, test.res)
+		if (finding != nil) != test.found {
+			t.Errorf("test %v: check swap %v and result %v got finding %+v, want found %v", i, test.swapStatus, test.res.Status, finding, test.found)
+			continue
+		}
+		if finding != nil && (finding.Kind != FindingImpossibleStatus || finding.Repairable != test.repairable) {
+			t.Errorf("test %v: check status combination got %+v, want repairable %v", i, finding, test.repairable)
+		}
+	}
+}
+
+func TestIntegrityReportAddFinding(t *testing.T) {
+	report := &IntegrityReport{}
+	report.addFinding(nil)
+	report.addFinding(&IntegrityFinding{Kind: FindingDuplicateNonce})
+	if len(report.Findings) != 1 {
+		t.Errorf("add finding got %v findings, want 1", len(report.Findings))
+	}
+}
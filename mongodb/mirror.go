@@ -0,0 +1,272 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// dual write migration mode:
+// every write to the main collections is mirrored to the same collection of
+// the mirror database by copying the written documents (deletes are mirrored too),
+// while all reads are still from the primary database.
+// status queues are not mirrored as they are backfilled from the main
+// collections when they are enabled on the new database.
+// copies of the same document are serialized by the lock of the document key,
+// so that a stale copy never overwrites a newer one, and the consistency
+// repair does not block the mirroring of other documents.
+var (
+	mirrorClient   *mongo.Client
+	mirrorDatabase *mongo.Database
+	mirrorLocks    [64]sync.Mutex
+
+	mirrorFailures uint64
+
+	mirroredTables = []string{
+		tbRouterSwaps,
+		tbRouterSwapResults,
+		tbUsedRValues,
+		tbDepositAddresses,
+		tbSwapReceipts,
+		tbSwapRejections,
+		tbSwapEvents,
+		tbClaimSwaps,
+		tbAPITokens,
+		tbSwapTransitions,
+		tbDuplicates,
+		tbFeatureFlags,
+		tbScanCheckpoints,
+		tbScanGaps,
+		tbSignRequests,
+		tbTxReceipts,
+		tbSwapStats,
+		tbMPCUsages,
+		tbSignApprovals,
+		tbDeliveryStats,
+		tbWebhookCursors,
+		tbSwapApprovals,
+		tbGasSamples,
+		tbExportJobs,
+	}
+)
+
+// lockMirrorKey lock the mirroring of document, returns the unlock func
+func lockMirrorKey(coll, key string) func() {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(coll))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key))
+	lock := &mirrorLocks[h.Sum32()%uint32(len(mirrorLocks))]
+	lock.Lock()
+	return lock.Unlock
+}
+
+// MongoMirrorInit connect mirror database and enable dual write,
+// it should be called after connecting the primary database.
+func MongoMirrorInit(appName string, hosts []string, dbName, user, pass string) {
+	clientOpts := &options.ClientOptions{
+		AppName: &appName,
+		Hosts:   hosts,
+		Auth: &options.Credential{
+			AuthSource: dbName,
+			Username:   user,
+			Password:   pass,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(clientCtx, 10*time.Second)
+	defer cancel()
+
+	mclient, err := mongo.Connect(ctx, clientOpts)
+	if err == nil {
+		err = mclient.Ping(clientCtx, nil)
+	}
+	if err != nil {
+		log.Fatal("[mongodb] connect mirror database failed", "hosts", hosts, "dbName", dbName, "appName", appName, "err", err)
+	}
+	mirrorClient = mclient
+	mirrorDatabase = mclient.Database(dbName)
+	log.Info("[mongodb] connect mirror database success, dual write is enabled", "hosts", hosts, "dbName", dbName, "appName", appName)
+}
+
+// IsMirrorEnabled is dual write to mirror database enabled
+func IsMirrorEnabled() bool {
+	return mirrorDatabase != nil
+}
+
+// GetMirrorFailures get count of failed mirror writes since startup
+func GetMirrorFailures() uint64 {
+	return atomic.LoadUint64(&mirrorFailures)
+}
+
+func closeMirror() {
+	if mirrorClient == nil {
+		return
+	}
+	if err := mirrorClient.Disconnect(clientCtx); err != nil {
+		log.Error("[mongodb] close mirror connection failed", "appName", appIdentifier, "err", err)
+	} else {
+		log.Info("[mongodb] close mirror connection success", "appName", appIdentifier)
+	}
+}
+
+// mirrorDocs copy the current documents of keys from primary to mirror database.
+// documents not found in primary are deleted from mirror.
+// failures are only logged and counted, and are fixed by the consistency checker.
+func mirrorDocs(coll *mongo.Collection, keys ...string) {
+	if mirrorDatabase == nil {
+		return
+	}
+	mirror := mirrorDatabase.Collection(coll.Name())
+
+	for _, key := range keys {
+		unlock := lockMirrorKey(coll.Name(), key)
+		err := copyDoc(coll, mirror, key)
+		unlock()
+		if err != nil {
+			atomic.AddUint64(&mirrorFailures, 1)
+			log.Warn("[mongodb] mirror write failed", "collection", coll.Name(), "key", key, "err", err)
+		}
+	}
+}
+
+func copyDoc(from, to *mongo.Collection, key string) error {
+	raw, err := from.FindOne(clientCtx, bson.M{"_id": key}).DecodeBytes()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_, err = to.DeleteOne(clientCtx, bson.M{"_id": key})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	_, err = to.ReplaceOne(clientCtx, bson.M{"_id": key}, raw, options.Replace().SetUpsert(true))
+	return err
+}
+
+// MirrorConsistency consistency check result of collection between primary and mirror
+type MirrorConsistency struct {
+	Collection string `json:"collection"`
+	Primary    int64  `json:"primary"`  // count of documents in primary
+	Missing    int64  `json:"missing"`  // count of documents missing in mirror
+	Mismatch   int64  `json:"mismatch"` // count of documents differ in mirror
+	Extra      int64  `json:"extra"`    // count of documents only in mirror
+	Repaired   int64  `json:"repaired"`
+}
+
+// IsConsistent is mirror consistent with primary (after repaired)
+func (c *MirrorConsistency) IsConsistent() bool {
+	return c.Missing+c.Mismatch+c.Extra == c.Repaired
+}
+
+// CheckMirrorConsistency compare all documents of mirrored collections between
+// primary and mirror database, and copy missing and mismatched documents
+// to mirror and delete extra documents from mirror if `repair` is true.
+func CheckMirrorConsistency(repair bool) ([]*MirrorConsistency, error) {
+	if mirrorDatabase == nil {
+		return nil, errors.New("mirror database is not enabled")
+	}
+	database := client.Database(databaseName)
+	result := make([]*MirrorConsistency, 0, len(mirroredTables))
+	for _, name := range mirroredTables {
+		res, err := checkCollectionConsistency(database.Collection(name), mirrorDatabase.Collection(name), repair)
+		if err != nil {
+			return result, fmt.Errorf("check collection %v failed: %w", name, mgoError(err))
+		}
+		log.Info("[mongodb] check mirror consistency", "collection", name, "primary", res.Primary, "missing", res.Missing, "mismatch", res.Mismatch, "extra", res.Extra, "repaired", res.Repaired)
+		result = append(result, res)
+	}
+	return result, nil
+}
+
+func checkCollectionConsistency(primary, mirror *mongo.Collection, repair bool) (*MirrorConsistency, error) {
+	res := &MirrorConsistency{Collection: primary.Name()}
+
+	cur, err := primary.Find(clientCtx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(clientCtx)
+
+	for cur.Next(clientCtx) {
+		res.Primary++
+		key, ok := cur.Current.Lookup("_id").StringValueOK()
+		if !ok {
+			continue
+		}
+		raw, errf := mirror.FindOne(clientCtx, bson.M{"_id": key}).DecodeBytes()
+		switch {
+		case errors.Is(errf, mongo.ErrNoDocuments):
+			res.Missing++
+		case errf != nil:
+			return nil, errf
+		case bytes.Equal(raw, cur.Current):
+			continue
+		default:
+			res.Mismatch++
+		}
+		if repair {
+			unlock := lockMirrorKey(primary.Name(), key)
+			errf = copyDoc(primary, mirror, key)
+			unlock()
+			if errf != nil {
+				return nil, errf
+			}
+			res.Repaired++
+		}
+	}
+	if err = cur.Err(); err != nil {
+		return nil, err
+	}
+
+	mirrorCount, err := mirror.CountDocuments(clientCtx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	res.Extra = mirrorCount + res.Missing - res.Primary
+	if repair && res.Extra > 0 {
+		deleted, errd := removeExtraDocs(primary, mirror)
+		if errd != nil {
+			return nil, errd
+		}
+		res.Repaired += deleted
+	}
+	return res, nil
+}
+
+// removeExtraDocs delete documents only in mirror
+func removeExtraDocs(primary, mirror *mongo.Collection) (int64, error) {
+	keys, err := findKeys(mirror, bson.M{}, 0)
+	if err != nil {
+		return 0, err
+	}
+	var deleted int64
+	for _, key := range keys {
+		err = primary.FindOne(clientCtx, bson.M{"_id": key}).Err()
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return deleted, err
+		}
+		// copy again under the lock, the document may be inserted in the meantime
+		unlock := lockMirrorKey(primary.Name(), key)
+		err = copyDoc(primary, mirror, key)
+		unlock()
+		if err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
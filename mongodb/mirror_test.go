@@ -0,0 +1,258 @@
+package mongodb
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var mongoWriteMethods = map[string]bool{
+	"InsertOne":         true,
+	"InsertMany":        true,
+	"UpdateOne":         true,
+	"UpdateByID":        true,
+	"UpdateMany":        true,
+	"ReplaceOne":        true,
+	"DeleteOne":         true,
+	"DeleteMany":        true,
+	"FindOneAndUpdate":  true,
+	"FindOneAndReplace": true,
+	"FindOneAndDelete":  true,
+	"BulkWrite":         true,
+}
+
+// parseMongoPackage parse the non test source files of this package
+func parseMongoPackage(t *testing.T) map[string]*ast.File {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatalf("parse package failed: %v", err)
+	}
+	return pkgs["mongodb"].Files
+}
+
+// getCollectionTables get table names of the collection variables assigned in initCollections
+func getCollectionTables(files map[string]*ast.File) map[string]string {
+	tableNames := make(map[string]string) // tb const -> table name
+	collTables := make(map[string]string) // coll var -> table name
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.CONST {
+				for _, spec := range gen.Specs {
+					vs := spec.(*ast.ValueSpec)
+					for i, name := range vs.Names {
+						if lit, ok := vs.Values[i].(*ast.BasicLit); ok && strings.HasPrefix(name.Name, "tb") {
+							tableNames[name.Name] = strings.Trim(lit.Value, `"`)
+						}
+					}
+				}
+			}
+		}
+	}
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+				return true
+			}
+			lhs, ok := assign.Lhs[0].(*ast.Ident)
+			call, isCall := assign.Rhs[0].(*ast.CallExpr)
+			if !ok || !isCall || len(call.Args) != 1 {
+				return true
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Collection" {
+				if arg, ok := call.Args[0].(*ast.Ident); ok && tableNames[arg.Name] != "" {
+					collTables[lhs.Name] = tableNames[arg.Name]
+				}
+			}
+			return true
+		})
+	}
+	return collTables
+}
+
+func TestMirroredTables(t *testing.T) {
+	collTables := getCollectionTables(parseMongoPackage(t))
+	if len(collTables) != len(mirroredTables) {
+		t.Errorf("got %v collections, but %v mirrored tables", len(collTables), len(mirroredTables))
+	}
+	mirrored := make(map[string]bool)
+	for _, table := range mirroredTables {
+		mirrored[table] = true
+	}
+	for coll, table := range collTables {
+		if !mirrored[table] {
+			t.Errorf("table %v of %v is not mirrored", table, coll)
+		}
+	}
+}
+
+// getCollectionWrites get collections written and mirrored by the function body,
+// collections are identified by the names in `colls`, writeHelpers are functions
+// writing to their collection parameters (of indexes) without mirroring.
+func getCollectionWrites(body *ast.BlockStmt, colls map[string]bool, writeHelpers map[string][]int) (written, mirrored map[string]bool) {
+	written = make(map[string]bool)
+	mirrored = make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		switch fun := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			if coll, ok := fun.X.(*ast.Ident); ok && colls[coll.Name] && mongoWriteMethods[fun.Sel.Name] {
+				written[coll.Name] = true
+			}
+		case *ast.Ident:
+			if fun.Name == "mirrorDocs" && len(call.Args) > 0 {
+				if coll, ok := call.Args[0].(*ast.Ident); ok {
+					mirrored[coll.Name] = true
+				}
+			}
+			for _, i := range writeHelpers[fun.Name] {
+				if coll, ok := call.Args[i].(*ast.Ident); ok && colls[coll.Name] {
+					written[coll.Name] = true
+				}
+			}
+		}
+		return true
+	})
+	return written, mirrored
+}
+
+// isCollectionType is type expression `*mongo.Collection`
+func isCollectionType(expr ast.Expr) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Collection"
+}
+
+// getWriteHelpers get functions writing to their collection parameters without mirroring
+func getWriteHelpers(files map[string]*ast.File) map[string][]int {
+	writeHelpers := make(map[string][]int)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || fn.Recv != nil {
+				continue
+			}
+			params := make(map[string]int)
+			index := 0
+			for _, field := range fn.Type.Params.List {
+				for _, name := range field.Names {
+					if isCollectionType(field.Type) {
+						params[name.Name] = index
+					}
+					index++
+				}
+			}
+			if len(params) == 0 || fn.Name.Name == "mirrorDocs" {
+				continue
+			}
+			colls := make(map[string]bool, len(params))
+			for name := range params {
+				colls[name] = true
+			}
+			written, mirrored := getCollectionWrites(fn.Body, colls, nil)
+			for name := range written {
+				if !mirrored[name] {
+					writeHelpers[fn.Name.Name] = append(writeHelpers[fn.Name.Name], params[name])
+				}
+			}
+		}
+	}
+	return writeHelpers
+}
+
+// TestMirrorWrites every write to the main collections must be mirrored in the same function
+func TestMirrorWrites(t *testing.T) {
+	files := parseMongoPackage(t)
+	colls := make(map[string]bool)
+	for coll := range getCollectionTables(files) {
+		colls[coll] = true
+	}
+	writeHelpers := getWriteHelpers(files)
+	if len(writeHelpers["updateSwapStatusByID"]) != 1 {
+		t.Errorf("updateSwapStatusByID is not found as write helper")
+	}
+	checked := 0
+	for fileName, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			written, mirrored := getCollectionWrites(fn.Body, colls, writeHelpers)
+			for coll := range written {
+				checked++
+				if !mirrored[coll] {
+					t.Errorf("%v: %v writes %v without mirroring", fileName, fn.Name.Name, coll)
+				}
+			}
+		}
+	}
+	if checked == 0 {
+		t.Errorf("no writes to the main collections are found")
+	}
+}
+
+func TestMirrorDisabled(t *testing.T) {
+	if IsMirrorEnabled() {
+		t.Fatalf("mirror should be disabled without mirror database")
+	}
+	failures := GetMirrorFailures()
+	mirrorDocs(collRouterSwap, "1:0x01:0") // nil collection is never touched
+	if GetMirrorFailures() != failures {
+		t.Errorf("mirror docs without mirror database should do nothing")
+	}
+	if _, err := CheckMirrorConsistency(true); err == nil {
+		t.Errorf("check mirror consistency without mirror database should fail")
+	}
+}
+
+func TestMirrorConsistency(t *testing.T) {
+	tests := []struct {
+		res        MirrorConsistency
+		consistent bool
+	}{
+		{MirrorConsistency{Primary: 10}, true},
+		{MirrorConsistency{Primary: 10, Missing: 1}, false},
+		{MirrorConsistency{Primary: 10, Mismatch: 2, Extra: 1}, false},
+		{MirrorConsistency{Primary: 10, Missing: 1, Mismatch: 2, Extra: 1, Repaired: 4}, true},
+	}
+	for i, test := range tests {
+		if consistent := test.res.IsConsistent(); consistent != test.consistent {
+			t.Errorf("test %v: is consistent got %v, want %v", i, consistent, test.consistent)
+		}
+	}
+}
+
+func TestLockMirrorKey(t *testing.T) {
+	unlock := lockMirrorKey(tbRouterSwaps, "1:0x01:0")
+	locked := make(chan struct{})
+	go func() {
+		unlockAgain := lockMirrorKey(tbRouterSwaps, "1:0x01:0")
+		close(locked)
+		unlockAgain()
+	}()
+	select {
+	case <-locked:
+		t.Fatalf("copies of the same document should be serialized")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("lock of document is not released")
+	}
+}
@@ -0,0 +1,100 @@
+package mongodb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MPCUsageFilter filter of finding mpc usage
+type MPCUsageFilter struct {
+	ChainID string `json:"chainID"`
+	Start   int64  `json:"start"` // seconds, inclusive
+	End     int64  `json:"end"`   // seconds, exclusive
+}
+
+// GetMPCUsageKey get key of mpc usage
+func GetMPCUsageKey(bucket int64, chainID string) string {
+	return fmt.Sprintf("%v:%v", bucket, chainID)
+}
+
+// IncMPCUsage increase sign count (and fail count if failed) of chain in the bucket
+func IncMPCUsage(bucketSize int64, chainID string, failed bool) error {
+	nowTime := time.Now().Unix()
+	bucket := nowTime - nowTime%bucketSize
+	key := GetMPCUsageKey(bucket, chainID)
+	incs := bson.M{"signCount": int64(1)}
+	if failed {
+		incs["failCount"] = int64(1)
+	}
+	updates := bson.M{
+		"$inc": incs,
+		"$set": bson.M{"timestamp": nowTime},
+		"$setOnInsert": bson.M{
+			"bucket":     bucket,
+			"chainID":    chainID,
+			"bucketSize": bucketSize,
+		},
+	}
+	opts := options.Update().SetUpsert(true)
+	_, err := collMPCUsage.UpdateByID(clientCtx, key, updates, opts)
+	if err != nil {
+		log.Warn("mongodb increase mpc usage failed", "key", key, "failed", failed, "err", err)
+		return mgoError(err)
+	}
+	mirrorDocs(collMPCUsage, key)
+	return nil
+}
+
+// FindMPCUsages find mpc usages in bucket order
+func FindMPCUsages(filter *MPCUsageFilter, limit int64) ([]*MgoMPCUsage, error) {
+	query := bson.D{
+		{Key: "bucket", Value: bson.M{"$gte": filter.Start, "$lt": filter.End}},
+	}
+	if filter.ChainID != "" {
+		query = append(query, bson.E{Key: "chainID", Value: filter.ChainID})
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "bucket", Value: 1}}).SetLimit(limit)
+	cur, err := collMPCUsage.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoMPCUsage, 0, 24)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// PruneMPCUsages remove mpc usages of buckets before the specified time (seconds)
+func PruneMPCUsages(before, limit int64) (int64, error) {
+	keys, err := findKeys(collMPCUsage, bson.M{"bucket": bson.M{"$lt": before}}, limit)
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	res, err := collMPCUsage.DeleteMany(clientCtx, bson.M{"_id": bson.M{"$in": keys}})
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	mirrorDocs(collMPCUsage, keys...)
+	return res.DeletedCount, nil
+}
+
+func ensureMPCUsageIndexes() {
+	model := mongo.IndexModel{
+		Keys: bson.D{{Key: "bucket", Value: 1}, {Key: "chainID", Value: 1}},
+	}
+	addExpectedIndexes(collMPCUsage, []mongo.IndexModel{model})
+	name, err := collMPCUsage.Indexes().CreateOne(clientCtx, model)
+	if err != nil {
+		log.Warn("[mongodb] create mpc usage indexes failed", "err", err)
+		return
+	}
+	log.Info("[mongodb] create mpc usage indexes success", "index", name)
+}
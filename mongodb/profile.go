@@ -0,0 +1,193 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+var (
+	profilingEnabled   bool
+	slowQueryThreshold time.Duration
+
+	startedCommands = new(sync.Map) // key is request id
+
+	collProfiles     = make(map[string]*CollectionProfile) // key is collection name
+	collProfilesLock sync.RWMutex
+
+	// upper bounds (in milliseconds) of latency histogram buckets
+	latencyBuckets = []int64{1, 5, 10, 50, 100, 500, 1000, 5000}
+
+	maxLoggedCommandLength = 1024
+)
+
+type startedCommand struct {
+	collection string
+	command    string
+	filter     bson.RawValue
+}
+
+// CollectionProfile operation profile of collection
+type CollectionProfile struct {
+	Collection string                       `json:"collection"`
+	Operations map[string]*OperationProfile `json:"operations"`
+}
+
+// OperationProfile latency profile of operation
+type OperationProfile struct {
+	Count     uint64   `json:"count"`
+	Failures  uint64   `json:"failures"`
+	SlowCount uint64   `json:"slowCount"`
+	TotalMs   int64    `json:"totalMs"`
+	MaxMs     int64    `json:"maxMs"`
+	Buckets   []uint64 `json:"buckets"` // last bucket is overflow
+}
+
+// SetProfiling enable operation profiling and slow query logging.
+// slow query logging is disabled if threshold (milliseconds) is zero.
+func SetProfiling(enable bool, thresholdMs int64) {
+	profilingEnabled = enable
+	slowQueryThreshold = time.Duration(thresholdMs) * time.Millisecond
+	log.Info("[mongodb] set profiling", "enable", enable, "slowQueryThreshold", slowQueryThreshold)
+}
+
+// GetLatencyBuckets get upper bounds (in milliseconds) of latency histogram buckets
+func GetLatencyBuckets() []int64 {
+	return latencyBuckets
+}
+
+// GetCollectionProfiles get copy of all collection profiles
+func GetCollectionProfiles() []*CollectionProfile {
+	collProfilesLock.RLock()
+	defer collProfilesLock.RUnlock()
+	result := make([]*CollectionProfile, 0, len(collProfiles))
+	for coll, profile := range collProfiles {
+		cp := &CollectionProfile{
+			Collection: coll,
+			Operations: make(map[string]*OperationProfile, len(profile.Operations)),
+		}
+		for op, opProfile := range profile.Operations {
+			opCopy := *opProfile
+			opCopy.Buckets = append([]uint64{}, opProfile.Buckets...)
+			cp.Operations[op] = &opCopy
+		}
+		result = append(result, cp)
+	}
+	return result
+}
+
+func newCommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started:   onCommandStarted,
+		Succeeded: onCommandSucceeded,
+		Failed:    onCommandFailed,
+	}
+}
+
+func onCommandStarted(_ context.Context, evt *event.CommandStartedEvent) {
+	if !profilingEnabled && slowQueryThreshold == 0 {
+		return
+	}
+	elem, err := evt.Command.IndexErr(0)
+	if err != nil {
+		return
+	}
+	collection, ok := elem.Value().StringValueOK()
+	if !ok {
+		return // not collection level command
+	}
+	started := &startedCommand{
+		collection: collection,
+		command:    evt.CommandName,
+	}
+	if slowQueryThreshold > 0 {
+		started.filter = getCommandFilter(evt.Command)
+	}
+	startedCommands.Store(evt.RequestID, started)
+}
+
+func onCommandSucceeded(_ context.Context, evt *event.CommandSucceededEvent) {
+	onCommandFinished(&evt.CommandFinishedEvent, "")
+}
+
+func onCommandFailed(_ context.Context, evt *event.CommandFailedEvent) {
+	onCommandFinished(&evt.CommandFinishedEvent, evt.Failure)
+}
+
+func onCommandFinished(evt *event.CommandFinishedEvent, failure string) {
+	value, exist := startedCommands.LoadAndDelete(evt.RequestID)
+	if !exist {
+		return
+	}
+	started := value.(*startedCommand)
+	duration := time.Duration(evt.DurationNanos)
+	isSlow := slowQueryThreshold > 0 && duration >= slowQueryThreshold
+
+	if isSlow {
+		filter := started.filter.String()
+		if len(filter) > maxLoggedCommandLength {
+			filter = filter[:maxLoggedCommandLength] + "..."
+		}
+		log.Warn("[mongodb] slow query", "collection", started.collection, "command", started.command, "duration", duration.String(), "filter", filter, "failure", failure)
+	}
+
+	if profilingEnabled {
+		recordOperation(started.collection, started.command, duration, failure != "", isSlow)
+	}
+}
+
+func getCommandFilter(cmd bson.Raw) bson.RawValue {
+	for _, key := range []string{"filter", "query", "pipeline", "updates", "deletes"} {
+		if val, err := cmd.LookupErr(key); err == nil {
+			return val
+		}
+	}
+	return bson.RawValue{}
+}
+
+func recordOperation(collection, command string, duration time.Duration, failed, slow bool) {
+	collProfilesLock.Lock()
+	defer collProfilesLock.Unlock()
+
+	profile, exist := collProfiles[collection]
+	if !exist {
+		profile = &CollectionProfile{
+			Collection: collection,
+			Operations: make(map[string]*OperationProfile),
+		}
+		collProfiles[collection] = profile
+	}
+	opProfile, exist := profile.Operations[command]
+	if !exist {
+		opProfile = &OperationProfile{
+			Buckets: make([]uint64, len(latencyBuckets)+1),
+		}
+		profile.Operations[command] = opProfile
+	}
+
+	ms := duration.Milliseconds()
+	opProfile.Count++
+	opProfile.TotalMs += ms
+	if ms > opProfile.MaxMs {
+		opProfile.MaxMs = ms
+	}
+	if failed {
+		opProfile.Failures++
+	}
+	if slow {
+		opProfile.SlowCount++
+	}
+	bucket := len(latencyBuckets)
+	for i, upper := range latencyBuckets {
+		if ms < upper {
+			bucket = i
+			break
+		}
+	}
+	opProfile.Buckets[bucket]++
+}
@@ -0,0 +1,88 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func runTestCommand(t *testing.T, requestID int64, cmd bson.D, duration time.Duration, failure string) {
+	raw, err := bson.Marshal(cmd)
+	if err != nil {
+		t.Fatalf("marshal command failed: %v", err)
+	}
+	commandName := cmd[0].Key
+	onCommandStarted(context.Background(), &event.CommandStartedEvent{Command: raw, CommandName: commandName, RequestID: requestID})
+	finished := event.CommandFinishedEvent{DurationNanos: duration.Nanoseconds(), CommandName: commandName, RequestID: requestID}
+	if failure == "" {
+		onCommandSucceeded(context.Background(), &event.CommandSucceededEvent{CommandFinishedEvent: finished})
+	} else {
+		onCommandFailed(context.Background(), &event.CommandFailedEvent{CommandFinishedEvent: finished, Failure: failure})
+	}
+}
+
+func getTestOperationProfile(collection, command string) *OperationProfile {
+	for _, profile := range GetCollectionProfiles() {
+		if profile.Collection == collection {
+			return profile.Operations[command]
+		}
+	}
+	return nil
+}
+
+func TestCommandProfiling(t *testing.T) {
+	defer SetProfiling(false, 0)
+
+	// commands are not tracked if profiling and slow query logging are disabled
+	SetProfiling(false, 0)
+	runTestCommand(t, 1, bson.D{{Key: "find", Value: "TestProfiles"}}, time.Millisecond, "")
+	if op := getTestOperationProfile("TestProfiles", "find"); op != nil {
+		t.Fatalf("disabled profiling got profile %+v", op)
+	}
+
+	SetProfiling(true, 100)
+	runTestCommand(t, 2, bson.D{{Key: "find", Value: "TestProfiles"}, {Key: "filter", Value: bson.D{{Key: "status", Value: 1}}}}, 3*time.Millisecond, "")
+	runTestCommand(t, 3, bson.D{{Key: "find", Value: "TestProfiles"}}, 200*time.Millisecond, "")
+	runTestCommand(t, 4, bson.D{{Key: "find", Value: "TestProfiles"}}, 7*time.Second, "timeout")
+	// database level command is not profiled
+	runTestCommand(t, 5, bson.D{{Key: "ping", Value: 1}}, time.Millisecond, "")
+
+	op := getTestOperationProfile("TestProfiles", "find")
+	if op == nil {
+		t.Fatalf("profile of find command not found")
+	}
+	if op.Count != 3 || op.Failures != 1 || op.SlowCount != 2 || op.MaxMs != 7000 || op.TotalMs != 7203 {
+		t.Errorf("operation profile got %+v", op)
+	}
+	// buckets: 3ms < 5ms, 200ms < 500ms, 7000ms overflow
+	wantBuckets := []uint64{0, 1, 0, 0, 0, 1, 0, 0, 1}
+	for i, count := range wantBuckets {
+		if op.Buckets[i] != count {
+			t.Errorf("latency buckets got %v, want %v", op.Buckets, wantBuckets)
+			break
+		}
+	}
+
+	// profiles are returned as copies
+	op.Count = 0
+	if again := getTestOperationProfile("TestProfiles", "find"); again.Count != 3 {
+		t.Errorf("modify returned profile changed the recorded count to %v", again.Count)
+	}
+	if _, exist := startedCommands.Load(int64(5)); exist {
+		t.Errorf("started command should be removed after finished")
+	}
+}
+
+func TestGetCommandFilter(t *testing.T) {
+	raw, _ := bson.Marshal(bson.D{{Key: "delete", Value: "TestProfiles"}, {Key: "deletes", Value: bson.A{bson.D{{Key: "q", Value: bson.D{{Key: "_id", Value: "key"}}}}}}})
+	if filter := getCommandFilter(raw); filter.Type != bson.TypeArray {
+		t.Errorf("filter of delete command got type %v, want array", filter.Type)
+	}
+	raw, _ = bson.Marshal(bson.D{{Key: "count", Value: "TestProfiles"}})
+	if filter := getCommandFilter(raw); filter.Type != 0 {
+		t.Errorf("command without filter got %v", filter)
+	}
+}
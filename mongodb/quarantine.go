@@ -0,0 +1,139 @@
+package mongodb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// QuarantineFilter incident window of swaps to quarantine,
+// either the verify time range [StartTime, EndTime] (seconds)
+// or the source block range [FromHeight, ToHeight] of FromChainID.
+type QuarantineFilter struct {
+	FromChainID string
+	StartTime   int64
+	EndTime     int64
+	FromHeight  uint64
+	ToHeight    uint64
+}
+
+// QuarantineResult keys of quarantined swaps, and skipped swaps which
+// can not be quarantined as their swap txs are already signed.
+type QuarantineResult struct {
+	Quarantined []string `json:"quarantined"`
+	Skipped     []string `json:"skipped"`
+}
+
+// swaps which are verified but not signed yet
+var quarantineStatuses = []SwapStatus{TxNotSwapped, TxWithBigValue, TxProcessed}
+
+// QuarantineRouterSwaps quarantine swaps verified in the incident window.
+// the unsigned swap results are removed and the swaps are set back to TxNotStable
+// with the quarantined flag, so that they are re-verified with cross verification
+// before proceeding to signing again.
+func QuarantineRouterSwaps(filter *QuarantineFilter, memo, actor string) (*QuarantineResult, error) {
+	swaps, err := findSwapsToQuarantine(filter)
+	if err != nil {
+		return nil, err
+	}
+	result := &QuarantineResult{
+		Quarantined: make([]string, 0, len(swaps)),
+		Skipped:     make([]string, 0),
+	}
+	for _, swap := range swaps {
+		if quarantineRouterSwap(swap, memo, actor) {
+			result.Quarantined = append(result.Quarantined, swap.Key)
+		} else {
+			result.Skipped = append(result.Skipped, swap.Key)
+		}
+	}
+	log.Info("mongodb quarantine router swaps finished", "filter", filter, "quarantined", len(result.Quarantined), "skipped", len(result.Skipped), "actor", actor)
+	return result, nil
+}
+
+func findSwapsToQuarantine(filter *QuarantineFilter) ([]*MgoSwap, error) {
+	query := bson.M{"status": bson.M{"$in": quarantineStatuses}}
+	if filter.FromChainID != "" {
+		query["fromChainID"] = filter.FromChainID
+	}
+	switch {
+	case filter.ToHeight > 0:
+		if filter.FromChainID == "" {
+			return nil, errors.New("quarantine by block range without chain id")
+		}
+		query["txheight"] = bson.M{"$gte": filter.FromHeight, "$lte": filter.ToHeight}
+	case filter.EndTime > 0:
+		keys, err := findSwapKeysVerifiedInRange(filter.StartTime, filter.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		query["_id"] = bson.M{"$in": keys}
+	default:
+		return nil, errors.New("quarantine without time or block range")
+	}
+	cur, err := collRouterSwap.Find(clientCtx, query)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwap, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// findSwapKeysVerifiedInRange find keys of swaps which passed verify in time range (seconds)
+func findSwapKeysVerifiedInRange(startTime, endTime int64) ([]string, error) {
+	query := bson.M{
+		"stage":     TransitionStageSwap,
+		"newstatus": bson.M{"$in": []SwapStatus{TxNotSwapped, TxWithBigValue}},
+		"timestamp": bson.M{"$gte": startTime * 1000, "$lte": endTime*1000 + 999},
+	}
+	keys, err := collSwapTransition.Distinct(clientCtx, "swapkey", query)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if s, ok := key.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func quarantineRouterSwap(swap *MgoSwap, memo, actor string) bool {
+	key := swap.Key
+	// only unsigned swap result can be removed
+	res, err := collRouterSwapResult.DeleteOne(clientCtx, bson.M{"_id": key, "status": MatchTxEmpty, "swaptx": ""})
+	if err != nil {
+		log.Warn("mongodb quarantine router swap failed", "key", key, "err", err)
+		return false
+	}
+	if res.DeletedCount == 0 {
+		count, errc := collRouterSwapResult.CountDocuments(clientCtx, bson.M{"_id": key})
+		if errc != nil || count > 0 {
+			log.Warn("mongodb quarantine router swap skipped", "key", key, "status", swap.Status, "reason", "swap result is not empty", "err", errc)
+			return false
+		}
+	} else {
+		mirrorDocs(collRouterSwapResult, key)
+		addSwapRemoval(collRouterSwapResult, key, actor, "quarantined: "+memo)
+	}
+
+	nowTime := time.Now().Unix()
+	updates := bson.M{"status": TxNotStable, "timestamp": nowTime, "quarantined": true, "quarantinetime": nowTime, "memo": memo}
+	matched, err := updateSwapStatusByID(collRouterSwap, key, bson.M{"status": swap.Status}, updates, actor, "quarantined: "+memo)
+	if err != nil || !matched {
+		log.Warn("mongodb quarantine router swap failed", "key", key, "status", swap.Status, "matched", matched, "err", err)
+		return false
+	}
+	mirrorDocs(collRouterSwap, key)
+	enqueueSwap(key, TxNotStable, nowTime, swap.InitTime)
+	log.Info("mongodb quarantine router swap success", "key", key, "status", swap.Status, "memo", memo, "actor", actor)
+	return true
+}
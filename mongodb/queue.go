@@ -0,0 +1,259 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// status queues are optional secondary collections (one per processing stage)
+// holding keys of swaps waiting in the stage. workers read the small queues
+// instead of scanning the main collections with status filters.
+// queue items are added when the status is written, and removed lazily
+// when they are found not matching the status of the main collection.
+var (
+	statusQueuesEnabled bool
+
+	swapQueueNames = map[SwapStatus]string{
+		TxNotStable:    "QueueVerify",
+		TxWithBigValue: "QueueBigValue",
+		TxNotSwapped:   "QueueSwap",
+	}
+	resultQueueNames = map[SwapStatus]string{
+		MatchTxNotStable: "QueueStable",
+		TxNeedReswap:     "QueueReswap",
+		MatchTxFailed:    "QueueFailed",
+	}
+
+	swapQueues   = make(map[SwapStatus]*mongo.Collection)
+	resultQueues = make(map[SwapStatus]*mongo.Collection)
+)
+
+// SetStatusQueues enable per status queue collections,
+// it should be called before connecting database.
+func SetStatusQueues(enable bool) {
+	statusQueuesEnabled = enable
+	log.Info("[mongodb] set status queues", "enable", enable)
+}
+
+func initStatusQueues(database *mongo.Database) {
+	if !statusQueuesEnabled {
+		return
+	}
+	for status, name := range swapQueueNames {
+		swapQueues[status] = database.Collection(name)
+	}
+	for status, name := range resultQueueNames {
+		resultQueues[status] = database.Collection(name)
+	}
+
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "timestamp", Value: 1}, {Key: "inittime", Value: 1}}},
+	}
+	for status, coll := range swapQueues {
+		ensureStatusQueue(coll, collRouterSwap, status, models)
+	}
+	for status, coll := range resultQueues {
+		ensureStatusQueue(coll, collRouterSwapResult, status, models)
+	}
+}
+
+// ensureStatusQueue create indexes of queue and backfill it from the main collection,
+// so that items written before the queues are enabled are not missed.
+func ensureStatusQueue(queue, main *mongo.Collection, status SwapStatus, models []mongo.IndexModel) {
+	addExpectedIndexes(queue, models)
+	if _, err := queue.Indexes().CreateMany(clientCtx, models); err != nil {
+		log.Warn("[mongodb] create status queue indexes failed", "queue", queue.Name(), "err", err)
+	}
+
+	opts := options.Find().SetProjection(bson.M{"status": 1, "timestamp": 1, "inittime": 1})
+	cur, err := main.Find(clientCtx, bson.M{"status": status}, opts)
+	if err != nil {
+		log.Warn("[mongodb] backfill status queue failed", "queue", queue.Name(), "err", err)
+		return
+	}
+	items := make([]*MgoQueueItem, 0, 20)
+	if err = cur.All(clientCtx, &items); err != nil {
+		log.Warn("[mongodb] backfill status queue failed", "queue", queue.Name(), "err", err)
+		return
+	}
+	for _, item := range items {
+		enqueue(queue, item.Key, item.Status, item.Timestamp, item.InitTime)
+	}
+	log.Info("[mongodb] init status queue success", "queue", queue.Name(), "status", status, "backfilled", len(items))
+}
+
+func getSwapQueue(status SwapStatus) *mongo.Collection {
+	if !statusQueuesEnabled {
+		return nil
+	}
+	return swapQueues[status]
+}
+
+func getResultQueue(status SwapStatus) *mongo.Collection {
+	if !statusQueuesEnabled {
+		return nil
+	}
+	return resultQueues[status]
+}
+
+// enqueueSwap add router swap to the queue of its new status.
+// inittime is set only on insert if it is zero.
+func enqueueSwap(key string, status SwapStatus, timestamp, inittime int64) {
+	if queue := getSwapQueue(status); queue != nil {
+		enqueue(queue, key, status, timestamp, inittime)
+	}
+}
+
+// enqueueSwapResult add router swap result to the queue of its new status.
+// inittime is set only on insert if it is zero.
+func enqueueSwapResult(key string, status SwapStatus, timestamp, inittime int64) {
+	if queue := getResultQueue(status); queue != nil {
+		enqueue(queue, key, status, timestamp, inittime)
+	}
+}
+
+func getEnqueueUpdates(status SwapStatus, timestamp, inittime int64) bson.M {
+	updates := bson.M{
+		"$set": bson.M{"status": status, "timestamp": timestamp},
+	}
+	if inittime != 0 {
+		updates["$set"].(bson.M)["inittime"] = inittime
+	} else {
+		updates["$setOnInsert"] = bson.M{"inittime": timestamp * 1000}
+	}
+	return updates
+}
+
+func enqueue(queue *mongo.Collection, key string, status SwapStatus, timestamp, inittime int64) {
+	updates := getEnqueueUpdates(status, timestamp, inittime)
+	_, err := queue.UpdateByID(clientCtx, key, updates, options.Update().SetUpsert(true))
+	if err != nil {
+		log.Warn("[mongodb] enqueue failed", "queue", queue.Name(), "key", key, "status", status, "err", err)
+	}
+}
+
+// findQueuedKeys find keys in queue updated in the past septime
+func findQueuedKeys(queue *mongo.Collection, septime int64) ([]string, error) {
+	opts := &options.FindOptions{
+		Sort:       bson.D{{Key: "inittime", Value: 1}},
+		Limit:      &maxCountOfResults,
+		Projection: bson.M{"_id": 1},
+	}
+	cur, err := queue.Find(clientCtx, bson.M{"timestamp": bson.M{"$gte": septime}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*MgoQueueItem, 0, 20)
+	if err = cur.All(clientCtx, &items); err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+	}
+	return keys, nil
+}
+
+// getStaleQueueKeys get queued keys which are not found with the queue status
+func getStaleQueueKeys(keys []string, found map[string]bool) []string {
+	stale := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !found[key] {
+			stale = append(stale, key)
+		}
+	}
+	return stale
+}
+
+// removeStaleQueueItems remove queued keys which are not found with the queue status.
+// items refreshed after reading the queue are kept.
+func removeStaleQueueItems(queue *mongo.Collection, keys []string, found map[string]bool, readTime int64) {
+	stale := getStaleQueueKeys(keys, found)
+	if len(stale) == 0 {
+		return
+	}
+	query := bson.M{"_id": bson.M{"$in": stale}, "timestamp": bson.M{"$lt": readTime}}
+	res, err := queue.DeleteMany(clientCtx, query)
+	if err != nil {
+		log.Warn("[mongodb] remove stale queue items failed", "queue", queue.Name(), "count", len(stale), "err", err)
+		return
+	}
+	log.Debug("[mongodb] remove stale queue items", "queue", queue.Name(), "count", res.DeletedCount)
+}
+
+func getQueuedItemsQuery(keys []string, status SwapStatus, septime int64) bson.M {
+	return bson.M{
+		"_id":       bson.M{"$in": keys},
+		"status":    status,
+		"timestamp": bson.M{"$gte": septime},
+	}
+}
+
+// findQueuedRouterSwaps find router swaps with status by the status queue
+//
+//nolint:dupl // allow duplicate
+func findQueuedRouterSwaps(queue *mongo.Collection, status SwapStatus, septime int64) ([]*MgoSwap, error) {
+	readTime := time.Now().Unix()
+	keys, err := findQueuedKeys(queue, septime)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwap, 0, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "inittime", Value: 1}},
+	}
+	cur, err := collRouterSwap.Find(clientCtx, getQueuedItemsQuery(keys, status, septime), opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	found := make(map[string]bool, len(result))
+	for _, swap := range result {
+		found[swap.Key] = true
+	}
+	removeStaleQueueItems(queue, keys, found, readTime)
+	return result, nil
+}
+
+// findQueuedRouterSwapResults find router swap results with status by the status queue
+//
+//nolint:dupl // allow duplicate
+func findQueuedRouterSwapResults(queue *mongo.Collection, status SwapStatus, septime int64) ([]*MgoSwapResult, error) {
+	readTime := time.Now().Unix()
+	keys, err := findQueuedKeys(queue, septime)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapResult, 0, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "inittime", Value: 1}},
+	}
+	cur, err := collRouterSwapResult.Find(clientCtx, getQueuedItemsQuery(keys, status, septime), opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	found := make(map[string]bool, len(result))
+	for _, res := range result {
+		found[res.Key] = true
+	}
+	removeStaleQueueItems(queue, keys, found, readTime)
+	return result, nil
+}
@@ -0,0 +1,91 @@
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestGetStatusQueue(t *testing.T) {
+	// connect is lazy, no server is needed to get collections
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://127.0.0.1:27017"))
+	if err != nil {
+		t.Fatalf("new mongo client failed: %v", err)
+	}
+	defer func() { _ = client.Disconnect(context.Background()) }()
+	database := client.Database("queue-test")
+
+	defer func() {
+		statusQueuesEnabled = false
+		swapQueues = make(map[SwapStatus]*mongo.Collection)
+		resultQueues = make(map[SwapStatus]*mongo.Collection)
+	}()
+	for status, name := range swapQueueNames {
+		swapQueues[status] = database.Collection(name)
+	}
+	for status, name := range resultQueueNames {
+		resultQueues[status] = database.Collection(name)
+	}
+
+	// disabled queues fall back to scanning the main collections
+	statusQueuesEnabled = false
+	if getSwapQueue(TxNotSwapped) != nil || getResultQueue(MatchTxNotStable) != nil {
+		t.Errorf("get status queue should be nil when status queues are disabled")
+	}
+
+	statusQueuesEnabled = true
+	for status, name := range swapQueueNames {
+		if queue := getSwapQueue(status); queue == nil || queue.Name() != name {
+			t.Errorf("get swap queue of status %v got %v, want %v", status, queue, name)
+		}
+	}
+	for status, name := range resultQueueNames {
+		if queue := getResultQueue(status); queue == nil || queue.Name() != name {
+			t.Errorf("get result queue of status %v got %v, want %v", status, queue, name)
+		}
+	}
+	// statuses not waiting for processing have no queue
+	if getSwapQueue(TxProcessed) != nil || getResultQueue(MatchTxStable) != nil {
+		t.Errorf("get status queue of final status should be nil")
+	}
+}
+
+func TestGetEnqueueUpdates(t *testing.T) {
+	updates := getEnqueueUpdates(TxNotSwapped, 1700000000, 1600000000123)
+	want := bson.M{"$set": bson.M{"status": TxNotSwapped, "timestamp": int64(1700000000), "inittime": int64(1600000000123)}}
+	if !reflect.DeepEqual(updates, want) {
+		t.Errorf("enqueue updates with inittime got %v, want %v", updates, want)
+	}
+
+	// inittime is kept if the item is already queued
+	updates = getEnqueueUpdates(MatchTxNotStable, 1700000000, 0)
+	want = bson.M{
+		"$set":         bson.M{"status": MatchTxNotStable, "timestamp": int64(1700000000)},
+		"$setOnInsert": bson.M{"inittime": int64(1700000000000)},
+	}
+	if !reflect.DeepEqual(updates, want) {
+		t.Errorf("enqueue updates without inittime got %v, want %v", updates, want)
+	}
+}
+
+func TestGetStaleQueueKeys(t *testing.T) {
+	keys := []string{"1:0x01:0", "1:0x02:0", "1:0x03:1"}
+	found := map[string]bool{"1:0x02:0": true}
+	if stale := getStaleQueueKeys(keys, found); !reflect.DeepEqual(stale, []string{"1:0x01:0", "1:0x03:1"}) {
+		t.Errorf("get stale queue keys got %v", stale)
+	}
+	found["1:0x01:0"], found["1:0x03:1"] = true, true
+	if stale := getStaleQueueKeys(keys, found); len(stale) != 0 {
+		t.Errorf("get stale queue keys of all found keys got %v, want none", stale)
+	}
+
+	query := getQueuedItemsQuery(keys, TxNotSwapped, 1700000000)
+	want := bson.M{"_id": bson.M{"$in": keys}, "status": TxNotSwapped, "timestamp": bson.M{"$gte": int64(1700000000)}}
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("get queued items query got %v, want %v", query, want)
+	}
+}
@@ -0,0 +1,32 @@
+package mongodb
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AddSwapReceipt add swap receipt, if error mean already exist
+func AddSwapReceipt(mr *MgoSwapReceipt) error {
+	mr.Key = GetRouterSwapKey(mr.FromChainID, mr.TxID, mr.LogIndex)
+	_, err := collSwapReceipt.InsertOne(clientCtx, mr)
+	if err == nil {
+		mirrorDocs(collSwapReceipt, mr.Key)
+		log.Info("mongodb add swap receipt success", "chainid", mr.FromChainID, "txid", mr.TxID, "logindex", mr.LogIndex, "swaptx", mr.SwapTx)
+	} else if !mongo.IsDuplicateKeyError(err) {
+		log.Warn("mongodb add swap receipt failed", "chainid", mr.FromChainID, "txid", mr.TxID, "logindex", mr.LogIndex, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindSwapReceipt find swap receipt
+func FindSwapReceipt(fromChainID, txid string, logindex int) (*MgoSwapReceipt, error) {
+	key := GetRouterSwapKey(fromChainID, txid, logindex)
+	result := &MgoSwapReceipt{}
+	err := collSwapReceipt.FindOne(clientCtx, bson.M{"_id": key}).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
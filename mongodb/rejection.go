@@ -0,0 +1,76 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AddSwapRejection add or update rejection record of swap with verify error
+func AddSwapRejection(fromChainID, txid string, logIndex int, verifyErr error) error {
+	if verifyErr == nil {
+		return nil
+	}
+	key := GetRouterSwapKey(fromChainID, txid, logIndex)
+	reason := tokens.GetRejectReason(verifyErr)
+	updates := getSwapRejectionUpdates(fromChainID, txid, logIndex, reason, verifyErr.Error(), time.Now().Unix())
+	_, err := collSwapRejection.UpdateByID(clientCtx, key, updates, options.Update().SetUpsert(true))
+	if err == nil {
+		mirrorDocs(collSwapRejection, key)
+		log.Info("mongodb add swap rejection success", "chainid", fromChainID, "txid", txid, "logindex", logIndex, "reason", reason)
+	} else {
+		log.Warn("mongodb add swap rejection failed", "chainid", fromChainID, "txid", txid, "logindex", logIndex, "reason", reason, "err", err)
+	}
+	return mgoError(err)
+}
+
+// getSwapRejectionUpdates upsert the latest rejection and count the rejected times
+func getSwapRejectionUpdates(fromChainID, txid string, logIndex int, reason, message string, timestamp int64) bson.M {
+	return bson.M{
+		"$set": bson.M{
+			"fromChainID": fromChainID,
+			"txid":        txid,
+			"logIndex":    logIndex,
+			"reason":      reason,
+			"message":     message,
+			"timestamp":   timestamp,
+		},
+		"$setOnInsert": bson.M{"inittime": timestamp},
+		"$inc":         bson.M{"count": 1},
+	}
+}
+
+// FindSwapRejectionsOfTx find rejection records of tx
+func FindSwapRejectionsOfTx(fromChainID, txid string) ([]*MgoSwapRejection, error) {
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "logIndex", Value: 1}},
+	}
+	cur, err := collSwapRejection.Find(clientCtx, getChainAndTxIDQuery(fromChainID, txid), opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapRejection, 0, 5)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+func ensureSwapRejectionIndexes() {
+	model := mongo.IndexModel{
+		Keys: bson.D{{Key: "fromChainID", Value: 1}, {Key: "txid", Value: 1}},
+	}
+	addExpectedIndexes(collSwapRejection, []mongo.IndexModel{model})
+	name, err := collSwapRejection.Indexes().CreateOne(clientCtx, model)
+	if err != nil {
+		log.Warn("[mongodb] create swap rejection indexes failed", "err", err)
+		return
+	}
+	log.Info("[mongodb] create swap rejection indexes success", "index", name)
+}
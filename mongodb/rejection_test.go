@@ -0,0 +1,51 @@
+package mongodb
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAddSwapRejectionWithoutError(t *testing.T) {
+	// nil collection is never touched if there is no verify error
+	if err := AddSwapRejection("1", "0x01", 0, nil); err != nil {
+		t.Errorf("add swap rejection without verify error got error %v", err)
+	}
+}
+
+func TestGetSwapRejectionUpdates(t *testing.T) {
+	updates := getSwapRejectionUpdates("1", "0x01", 2, "WRONG_VALUE", "tx with wrong value", 1700000000)
+
+	// the updated fields are the fields of stored rejection records
+	doc := bson.M{"count": 1}
+	for k, v := range updates["$set"].(bson.M) {
+		doc[k] = v
+	}
+	for k, v := range updates["$setOnInsert"].(bson.M) {
+		doc[k] = v
+	}
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal swap rejection failed: %v", err)
+	}
+	var rejection MgoSwapRejection
+	if err = bson.Unmarshal(data, &rejection); err != nil {
+		t.Fatalf("unmarshal swap rejection failed: %v", err)
+	}
+	want := MgoSwapRejection{
+		FromChainID: "1",
+		TxID:        "0x01",
+		LogIndex:    2,
+		Reason:      "WRONG_VALUE",
+		Message:     "tx with wrong value",
+		Count:       1,
+		InitTime:    1700000000,
+		Timestamp:   1700000000,
+	}
+	if rejection != want {
+		t.Errorf("swap rejection got %+v, want %+v", rejection, want)
+	}
+	if inc := updates["$inc"].(bson.M); inc["count"] != 1 {
+		t.Errorf("swap rejection updates should increase count, got %v", inc)
+	}
+}
@@ -0,0 +1,98 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func getSignApprovalKey(approvalKey, approver string) string {
+	return approvalKey + ":" + approver
+}
+
+// AddSignApproval add or renew approval of approver
+func AddSignApproval(approvalKey, approver string, expiry int64) error {
+	key := getSignApprovalKey(approvalKey, approver)
+	mr := &MgoSignApproval{
+		Key:         key,
+		ApprovalKey: approvalKey,
+		Approver:    approver,
+		Expiry:      expiry,
+		Timestamp:   time.Now().Unix(),
+	}
+	opts := options.Replace().SetUpsert(true)
+	_, err := collSignApproval.ReplaceOne(clientCtx, bson.M{"_id": key}, mr, opts)
+	if err != nil {
+		log.Warn("mongodb add sign approval failed", "approvalKey", approvalKey, "approver", approver, "err", err)
+		return mgoError(err)
+	}
+	mirrorDocs(collSignApproval, key)
+	log.Info("mongodb add sign approval success", "approvalKey", approvalKey, "approver", approver, "expiry", expiry)
+	return nil
+}
+
+// FindSignApprovals find unexpired approvals of approval key
+func FindSignApprovals(approvalKey string, now int64) ([]*MgoSignApproval, error) {
+	query := bson.M{
+		"approvalKey": approvalKey,
+		"expiry":      bson.M{"$gt": now},
+	}
+	cur, err := collSignApproval.Find(clientCtx, query)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSignApproval, 0, 2)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// ConsumeSignApprovals remove approvals of approval key,
+// returns the number of approvals removed by this call,
+// so that concurrent consumers can not both use the same approvals.
+func ConsumeSignApprovals(approvalKey string, approvals []*MgoSignApproval) (int64, error) {
+	keys := make([]string, 0, len(approvals))
+	for _, approval := range approvals {
+		keys = append(keys, approval.Key)
+	}
+	res, err := collSignApproval.DeleteMany(clientCtx, bson.M{"_id": bson.M{"$in": keys}, "approvalKey": approvalKey})
+	if err != nil {
+		log.Warn("mongodb consume sign approvals failed", "approvalKey", approvalKey, "err", err)
+		return 0, mgoError(err)
+	}
+	mirrorDocs(collSignApproval, keys...)
+	return res.DeletedCount, nil
+}
+
+// PruneSignApprovals remove approvals expired before `before` (seconds)
+func PruneSignApprovals(before, limit int64) (int64, error) {
+	keys, err := findKeys(collSignApproval, bson.M{"expiry": bson.M{"$lt": before}}, limit)
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	res, err := collSignApproval.DeleteMany(clientCtx, bson.M{"_id": bson.M{"$in": keys}})
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	mirrorDocs(collSignApproval, keys...)
+	return res.DeletedCount, nil
+}
+
+func ensureSignApprovalIndexes() {
+	model := mongo.IndexModel{
+		Keys: bson.D{{Key: "approvalKey", Value: 1}, {Key: "expiry", Value: 1}},
+	}
+	addExpectedIndexes(collSignApproval, []mongo.IndexModel{model})
+	name, err := collSignApproval.Indexes().CreateOne(clientCtx, model)
+	if err != nil {
+		log.Warn("[mongodb] create sign approval indexes failed", "err", err)
+		return
+	}
+	log.Info("[mongodb] create sign approval indexes success", "index", name)
+}
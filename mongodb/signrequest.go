@@ -0,0 +1,90 @@
+package mongodb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetSignRequestKey get key of sign request.
+// sign requests of swaps are keyed by the swap and nonce, so that the swap
+// rebuilt with the same nonce (maybe with different msg hash) finds the pending one.
+func GetSignRequestKey(swapKey string, nonce uint64, signPubkey string, msgHash []string) string {
+	if swapKey != "" {
+		return fmt.Sprintf("%v:%v", swapKey, nonce)
+	}
+	data := strings.ToLower(signPubkey + ":" + strings.Join(msgHash, ","))
+	return common.Keccak256Hash([]byte(data)).String()
+}
+
+// AddSignRequest add or replace pending sign request of the same key
+func AddSignRequest(mr *MgoSignRequest) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := collSignRequest.ReplaceOne(clientCtx, bson.M{"_id": mr.Key}, mr, opts)
+	if err != nil {
+		log.Warn("mongodb add sign request failed", "key", mr.Key, "keyID", mr.KeyID, "msgHash", mr.MsgHash, "err", err)
+		return mgoError(err)
+	}
+	mirrorDocs(collSignRequest, mr.Key)
+	log.Info("mongodb add sign request success", "key", mr.Key, "keyID", mr.KeyID, "msgHash", mr.MsgHash)
+	return nil
+}
+
+// FindSignRequest find pending sign request
+func FindSignRequest(key string) (*MgoSignRequest, error) {
+	result := &MgoSignRequest{}
+	err := collSignRequest.FindOne(clientCtx, bson.M{"_id": key}).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// FindSwapSignRequests find pending sign requests of swaps (oldest first)
+func FindSwapSignRequests(limit int64) ([]*MgoSignRequest, error) {
+	query := bson.M{"swapKey": bson.M{"$exists": true, "$ne": ""}}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	cur, err := collSignRequest.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSignRequest, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// RemoveSignRequest remove sign request of keyID when the sign round finishes
+func RemoveSignRequest(key, keyID string) error {
+	_, err := collSignRequest.DeleteOne(clientCtx, bson.M{"_id": key, "keyID": keyID})
+	if err != nil {
+		log.Warn("mongodb remove sign request failed", "key", key, "keyID", keyID, "err", err)
+		return mgoError(err)
+	}
+	mirrorDocs(collSignRequest, key)
+	return nil
+}
+
+// PruneSignRequests remove stale sign requests added before `before` (seconds)
+func PruneSignRequests(before, limit int64) (int64, error) {
+	keys, err := findKeys(collSignRequest, bson.M{"timestamp": bson.M{"$lt": before}}, limit)
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	res, err := collSignRequest.DeleteMany(clientCtx, bson.M{"_id": bson.M{"$in": keys}})
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	mirrorDocs(collSignRequest, keys...)
+	return res.DeletedCount, nil
+}
@@ -0,0 +1,98 @@
+package mongodb
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrSwapHistoryIncomplete history of swap is recorded before supporting snapshots
+var ErrSwapHistoryIncomplete = errors.New("swap history is incomplete to replay snapshot")
+
+// SwapSnapshot swap and swap result at the time, replayed from the swap history
+type SwapSnapshot struct {
+	Timestamp  int64          `json:"timestamp"` // milli seconds
+	Swap       *MgoSwap       `json:"swap"`
+	SwapResult *MgoSwapResult `json:"swapResult,omitempty"` // nil if not created (or removed) at the time
+	Replayed   int            `json:"replayed"`             // count of replayed history records
+}
+
+// FindSwapSnapshot find what the swap and swap result looked like at timestamp (milli seconds).
+// it replays the created documents and all field changes recorded until the timestamp,
+// records at the same milli second are replayed in the recording order.
+// housekeeping compactions of old swap txs by swap gc are not recorded.
+func FindSwapSnapshot(fromChainID, txid string, logIndex int, timestamp int64) (*SwapSnapshot, error) {
+	swapKey := GetRouterSwapKey(fromChainID, txid, logIndex)
+	query := bson.M{
+		"swapkey":   swapKey,
+		"timestamp": bson.M{"$lte": timestamp},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "seq", Value: 1}})
+	cur, err := collSwapTransition.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	history := make([]*MgoSwapTransition, 0, 16)
+	err = cur.All(clientCtx, &history)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return replaySwapSnapshot(swapKey, timestamp, history)
+}
+
+// replaySwapSnapshot replay the sorted swap history to the snapshot
+func replaySwapSnapshot(swapKey string, timestamp int64, history []*MgoSwapTransition) (*SwapSnapshot, error) {
+	docs := make(map[string]bson.M, 2) // stage -> replayed document
+	for _, mt := range history {
+		doc, exist := docs[mt.Stage]
+		switch {
+		case mt.Removed:
+			delete(docs, mt.Stage)
+		case mt.OldStatus == nil && !mt.ChangeOnly: // creation
+			if mt.Changes == nil {
+				return nil, ErrSwapHistoryIncomplete
+			}
+			doc = make(bson.M, len(mt.Changes))
+			for k, v := range mt.Changes {
+				doc[k] = v
+			}
+			docs[mt.Stage] = doc
+		case !exist || mt.Changes == nil:
+			return nil, ErrSwapHistoryIncomplete
+		default:
+			for k, v := range mt.Changes {
+				doc[k] = v
+			}
+		}
+	}
+
+	swapDoc, exist := docs[TransitionStageSwap]
+	if !exist {
+		return nil, ErrSwapNotFound
+	}
+	snapshot := &SwapSnapshot{
+		Timestamp: timestamp,
+		Swap:      &MgoSwap{},
+		Replayed:  len(history),
+	}
+	if err := decodeSnapshotDoc(swapKey, swapDoc, snapshot.Swap); err != nil {
+		return nil, err
+	}
+	if resultDoc, exist := docs[TransitionStageResult]; exist {
+		snapshot.SwapResult = &MgoSwapResult{}
+		if err := decodeSnapshotDoc(swapKey, resultDoc, snapshot.SwapResult); err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+func decodeSnapshotDoc(key string, doc bson.M, result interface{}) error {
+	doc["_id"] = key
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(data, result)
+}
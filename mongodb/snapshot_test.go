@@ -0,0 +1,73 @@
+package mongodb
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestReplaySwapSnapshot(t *testing.T) {
+	swapKey := GetRouterSwapKey("1", "0x01", 0)
+	oldStatus := TxNotStable
+	history := []*MgoSwapTransition{
+		{Stage: TransitionStageSwap, NewStatus: TxNotStable, Changes: getHistoryChanges(&MgoSwap{
+			Key: swapKey, TxID: "0x01", FromChainID: "1", ToChainID: "56", Value: "1000", Status: TxNotStable,
+		})},
+		{Stage: TransitionStageSwap, OldStatus: &oldStatus, NewStatus: TxNotSwapped, Changes: bson.M{"status": TxNotSwapped}},
+		{Stage: TransitionStageSwap, ChangeOnly: true, Changes: bson.M{"memo": "verified"}},
+		{Stage: TransitionStageResult, NewStatus: MatchTxEmpty, Changes: getHistoryChanges(&MgoSwapResult{
+			Key: swapKey, TxID: "0x01", FromChainID: "1", ToChainID: "56", Value: "1000", Status: MatchTxEmpty,
+		})},
+		{Stage: TransitionStageResult, ChangeOnly: true, Changes: bson.M{"swaptx": "0x02", "swapvalue": "990"}},
+	}
+
+	snapshot, err := replaySwapSnapshot(swapKey, 100, history)
+	if err != nil {
+		t.Fatalf("replay swap snapshot failed: %v", err)
+	}
+	swap, res := snapshot.Swap, snapshot.SwapResult
+	if swap.Key != swapKey || swap.Status != TxNotSwapped || swap.Memo != "verified" || swap.Value != "1000" {
+		t.Errorf("replay swap snapshot got swap %+v", swap)
+	}
+	if res == nil || res.Key != swapKey || res.SwapTx != "0x02" || res.SwapValue != "990" || res.Status != MatchTxEmpty {
+		t.Errorf("replay swap snapshot got swap result %+v", res)
+	}
+	if snapshot.Timestamp != 100 || snapshot.Replayed != len(history) {
+		t.Errorf("replay swap snapshot got timestamp %v replayed %v, want 100 %v", snapshot.Timestamp, snapshot.Replayed, len(history))
+	}
+	// the replayed documents do not modify the history
+	if history[0].Changes["memo"] != "" {
+		t.Errorf("replay swap snapshot modified the creation history")
+	}
+
+	history = append(history, &MgoSwapTransition{Stage: TransitionStageResult, ChangeOnly: true, Removed: true})
+	snapshot, err = replaySwapSnapshot(swapKey, 200, history)
+	if err != nil {
+		t.Fatalf("replay swap snapshot with removal failed: %v", err)
+	}
+	if snapshot.SwapResult != nil || snapshot.Swap.Status != TxNotSwapped {
+		t.Errorf("replay swap snapshot with removed result got %+v", snapshot.SwapResult)
+	}
+}
+
+func TestReplaySwapSnapshotError(t *testing.T) {
+	swapKey := GetRouterSwapKey("1", "0x01", 0)
+	tests := []struct {
+		history []*MgoSwapTransition
+		wantErr error
+	}{
+		// changes before the creation is recorded
+		{[]*MgoSwapTransition{{Stage: TransitionStageSwap, ChangeOnly: true, Changes: bson.M{"memo": "verified"}}}, ErrSwapHistoryIncomplete},
+		// creation without changes (recorded before supporting snapshots)
+		{[]*MgoSwapTransition{{Stage: TransitionStageSwap, NewStatus: TxNotStable}}, ErrSwapHistoryIncomplete},
+		// swap is not created at the time
+		{[]*MgoSwapTransition{{Stage: TransitionStageResult, NewStatus: MatchTxEmpty, Changes: bson.M{"value": "1000"}}}, ErrSwapNotFound},
+		{nil, ErrSwapNotFound},
+	}
+	for i, test := range tests {
+		if _, err := replaySwapSnapshot(swapKey, 100, test.history); !errors.Is(err, test.wantErr) {
+			t.Errorf("test %v: replay swap snapshot got error %v, want %v", i, err, test.wantErr)
+		}
+	}
+}
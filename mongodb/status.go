@@ -14,11 +14,14 @@ import (
 // -----------------------------------------------
 // 1. swap register status change graph
 //
-// TxNotStable -> |- TxVerifyFailed    -> manual
-//                |- TxWithWrongValue  -> manual
-//                |- SwapInBlacklist   -> manual
-//                |- TxWithBigValue    ---> TxNotSwapped
+// TxNotStable -> |- TxVerifyFailed     -> manual
+//                |- TxWithWrongValue   -> manual
+//                |- SwapInBlacklist    -> manual
+//                |- TokenRouteDisabled -> manual
+//                |- TxWithBigValue     ---> TxNotSwapped
 //                |- TxNotSwapped -> |- TxProcessed (->MatchTxNotStable)
+//
+// TxNotSwapped, TxWithBigValue, TxProcessed (unsigned) ---> TxNotStable (quarantine)
 // -----------------------------------------------
 // 2. swap result status change graph
 //
@@ -32,24 +35,25 @@ type SwapStatus uint16
 
 // swap status values
 const (
-	TxNotStable       SwapStatus = 0
-	TxVerifyFailed    SwapStatus = 1
-	TxWithWrongValue  SwapStatus = 3
-	TxNotSwapped      SwapStatus = 5
-	TxProcessed       SwapStatus = 7
-	MatchTxEmpty      SwapStatus = 8
-	MatchTxNotStable  SwapStatus = 9
-	MatchTxStable     SwapStatus = 10
-	TxWithBigValue    SwapStatus = 12
-	MatchTxFailed     SwapStatus = 14
-	SwapInBlacklist   SwapStatus = 15
-	ManualMakeFail    SwapStatus = 16
-	TxWithWrongPath   SwapStatus = 19
-	MissTokenConfig   SwapStatus = 20
-	NoUnderlyingToken SwapStatus = 21
-	TxMaybeUnsafe     SwapStatus = 22
-	SwapoutForbidden  SwapStatus = 23
-	TxNeedReswap      SwapStatus = 24
+	TxNotStable        SwapStatus = 0
+	TxVerifyFailed     SwapStatus = 1
+	TxWithWrongValue   SwapStatus = 3
+	TxNotSwapped       SwapStatus = 5
+	TxProcessed        SwapStatus = 7
+	MatchTxEmpty       SwapStatus = 8
+	MatchTxNotStable   SwapStatus = 9
+	MatchTxStable      SwapStatus = 10
+	TxWithBigValue     SwapStatus = 12
+	MatchTxFailed      SwapStatus = 14
+	SwapInBlacklist    SwapStatus = 15
+	ManualMakeFail     SwapStatus = 16
+	TxWithWrongPath    SwapStatus = 19
+	MissTokenConfig    SwapStatus = 20
+	NoUnderlyingToken  SwapStatus = 21
+	TxMaybeUnsafe      SwapStatus = 22
+	SwapoutForbidden   SwapStatus = 23
+	TxNeedReswap       SwapStatus = 24
+	TokenRouteDisabled SwapStatus = 25
 
 	KeepStatus SwapStatus = 255
 	Reswapping SwapStatus = 256
@@ -67,6 +71,20 @@ func (status SwapStatus) IsResultStatus() bool {
 	}
 }
 
+// IsFinalStatus is swap in a terminal status, which will not be
+// changed by the router automatically (except by manual process)
+func (status SwapStatus) IsFinalStatus() bool {
+	switch status {
+	case MatchTxStable, MatchTxFailed, ManualMakeFail,
+		TxVerifyFailed, TxWithWrongValue, SwapInBlacklist,
+		TxWithWrongPath, MissTokenConfig, NoUnderlyingToken,
+		SwapoutForbidden, TokenRouteDisabled:
+		return true
+	default:
+		return false
+	}
+}
+
 // IsRegisteredOk is successfully registered
 func (status SwapStatus) IsRegisteredOk() bool {
 	switch status {
@@ -115,6 +133,8 @@ func (status SwapStatus) String() string {
 		return "TxMaybeUnsafe"
 	case SwapoutForbidden:
 		return "SwapoutForbidden"
+	case TokenRouteDisabled:
+		return "TokenRouteDisabled"
 
 	case KeepStatus:
 		return "KeepStatus"
@@ -0,0 +1,176 @@
+package mongodb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// swap processing stages of registered but unprocessed swaps
+const (
+	StageUnverified = "unverified" // registered, waiting for verify
+	StageBigValue   = "bigvalue"   // verified, waiting for passing big value
+	StageUnswapped  = "unswapped"  // verified, waiting for swap (build/sign/send)
+	StageUnstable   = "unstable"   // swap tx sent, waiting for stable
+	StageFailed     = "failed"     // swap tx failed, waiting for manual process
+)
+
+// sort fields of stuck swaps
+const (
+	SortByAge   = "age"
+	SortByValue = "value"
+)
+
+type swapStage struct {
+	isResult bool
+	statuses []SwapStatus
+}
+
+var swapStages = map[string]*swapStage{
+	StageUnverified: {isResult: false, statuses: []SwapStatus{TxNotStable}},
+	StageBigValue:   {isResult: false, statuses: []SwapStatus{TxWithBigValue}},
+	StageUnswapped:  {isResult: false, statuses: []SwapStatus{TxNotSwapped}},
+	StageUnstable:   {isResult: true, statuses: []SwapStatus{MatchTxNotStable}},
+	StageFailed:     {isResult: true, statuses: []SwapStatus{MatchTxFailed}},
+}
+
+// StuckSwapsFilter filter of finding stuck swaps
+type StuckSwapsFilter struct {
+	Stage       string `json:"stage"`
+	FromChainID string `json:"fromChainID"`
+	ToChainID   string `json:"toChainID"`
+	TokenID     string `json:"tokenID"`
+	SortBy      string `json:"sortBy"` // age (oldest first) or value (biggest first)
+	Offset      int    `json:"offset"`
+	Limit       int    `json:"limit"`
+}
+
+// IsValidSwapStage is valid swap stage
+func IsValidSwapStage(stage string) bool {
+	_, exist := swapStages[stage]
+	return exist
+}
+
+func getTokenIDField() string {
+	if tokens.IsNFTRouter() {
+		return "swapinfo.nftSwapInfo.tokenID"
+	}
+	return "swapinfo.routerSwapInfo.tokenID"
+}
+
+// getStuckSwapsQuery get the stage and query of finding stuck swaps by filter
+func getStuckSwapsQuery(filter *StuckSwapsFilter) (*swapStage, bson.D, error) {
+	stage, exist := swapStages[filter.Stage]
+	if !exist {
+		return nil, nil, fmt.Errorf("unknown swap stage '%v'", filter.Stage)
+	}
+	switch strings.ToLower(filter.SortBy) {
+	case SortByValue, SortByAge, "":
+	default:
+		return nil, nil, fmt.Errorf("unknown sort by '%v'", filter.SortBy)
+	}
+
+	query := bson.D{{Key: "status", Value: bson.M{"$in": stage.statuses}}}
+	if filter.FromChainID != "" {
+		query = append(query, bson.E{Key: "fromChainID", Value: filter.FromChainID})
+	}
+	if filter.ToChainID != "" {
+		query = append(query, bson.E{Key: "toChainID", Value: filter.ToChainID})
+	}
+	if filter.TokenID != "" {
+		query = append(query, bson.E{Key: getTokenIDField(), Value: filter.TokenID})
+	}
+	return stage, query, nil
+}
+
+// FindStuckRouterSwaps find registered but unprocessed swaps by processing stage
+func FindStuckRouterSwaps(filter *StuckSwapsFilter) ([]*MgoSwapResult, error) {
+	stage, query, err := getStuckSwapsQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var coll *mongo.Collection
+	if stage.isResult {
+		coll = collRouterSwapResult
+	} else {
+		coll = collRouterSwap
+	}
+
+	var cur *mongo.Cursor
+	switch strings.ToLower(filter.SortBy) {
+	case SortByValue:
+		// value is stored as decimal string, convert it to sort numerically
+		pipeline := mongo.Pipeline{
+			{{Key: "$match", Value: query}},
+			{{Key: "$addFields", Value: bson.M{"numvalue": bson.M{"$convert": bson.M{"input": "$value", "to": "decimal", "onError": 0, "onNull": 0}}}}},
+			{{Key: "$sort", Value: bson.D{{Key: "numvalue", Value: -1}, {Key: "inittime", Value: 1}}}},
+			{{Key: "$skip", Value: int64(filter.Offset)}},
+			{{Key: "$limit", Value: int64(filter.Limit)}},
+			{{Key: "$project", Value: bson.M{"numvalue": 0}}},
+		}
+		cur, err = coll.Aggregate(clientCtx, pipeline)
+	default:
+		opts := options.Find().SetSort(bson.D{{Key: "inittime", Value: 1}}).
+			SetSkip(int64(filter.Offset)).SetLimit(int64(filter.Limit))
+		cur, err = coll.Find(clientCtx, query, opts)
+	}
+	if err != nil {
+		return nil, mgoError(err)
+	}
+
+	result := make([]*MgoSwapResult, 0, 20)
+	if stage.isResult {
+		err = cur.All(clientCtx, &result)
+	} else {
+		swaps := make([]*MgoSwap, 0, 20)
+		err = cur.All(clientCtx, &swaps)
+		if err == nil {
+			result = convertToSwapResults(swaps)
+		}
+	}
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// CountInFlightRouterSwaps count swaps of chain pair whose swap txs are signed but not stable
+func CountInFlightRouterSwaps(fromChainID, toChainID string) (int64, error) {
+	query := bson.M{
+		"status":      MatchTxNotStable,
+		"fromChainID": fromChainID,
+		"toChainID":   toChainID,
+	}
+	count, err := collRouterSwapResult.CountDocuments(clientCtx, query)
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	return count, nil
+}
+
+// ensureStuckSwapsIndexes create indexes for finding stuck swaps by stage with filters
+func ensureStuckSwapsIndexes() {
+	tokenIDField := getTokenIDField()
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "inittime", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "fromChainID", Value: 1}, {Key: "inittime", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "toChainID", Value: 1}, {Key: "inittime", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: tokenIDField, Value: 1}, {Key: "inittime", Value: 1}}},
+	}
+	for _, coll := range []*mongo.Collection{collRouterSwap, collRouterSwapResult} {
+		addExpectedIndexes(coll, models)
+		names, err := coll.Indexes().CreateMany(clientCtx, models)
+		if err != nil {
+			log.Warn("[mongodb] create stuck swaps indexes failed", "collection", coll.Name(), "err", err)
+			continue
+		}
+		log.Info("[mongodb] create stuck swaps indexes success", "collection", coll.Name(), "indexes", names)
+	}
+}
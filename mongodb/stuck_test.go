@@ -0,0 +1,60 @@
+package mongodb
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestGetStuckSwapsQuery(t *testing.T) {
+	filter := &StuckSwapsFilter{
+		Stage:       StageUnstable,
+		FromChainID: "1",
+		ToChainID:   "56",
+		TokenID:     "USDC",
+		SortBy:      "Value",
+	}
+	stage, query, err := getStuckSwapsQuery(filter)
+	if err != nil {
+		t.Fatalf("get stuck swaps query failed: %v", err)
+	}
+	if !stage.isResult {
+		t.Errorf("stage %v should query swap results", filter.Stage)
+	}
+	want := bson.D{
+		{Key: "status", Value: bson.M{"$in": []SwapStatus{MatchTxNotStable}}},
+		{Key: "fromChainID", Value: "1"},
+		{Key: "toChainID", Value: "56"},
+		{Key: "swapinfo.routerSwapInfo.tokenID", Value: "USDC"},
+	}
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("get stuck swaps query got %v, want %v", query, want)
+	}
+
+	stage, query, err = getStuckSwapsQuery(&StuckSwapsFilter{Stage: StageUnswapped})
+	if err != nil || stage.isResult || len(query) != 1 {
+		t.Errorf("get unswapped stage query got (%+v, %v, %v), want status only query of swaps", stage, query, err)
+	}
+
+	tests := []*StuckSwapsFilter{
+		{Stage: "unknown"},
+		{Stage: StageFailed, SortBy: "fee"},
+	}
+	for i, test := range tests {
+		if _, _, err := getStuckSwapsQuery(test); err == nil {
+			t.Errorf("test %v: get stuck swaps query of %+v should fail", i, test)
+		}
+	}
+}
+
+func TestIsValidSwapStage(t *testing.T) {
+	for _, stage := range []string{StageUnverified, StageBigValue, StageUnswapped, StageUnstable, StageFailed} {
+		if !IsValidSwapStage(stage) {
+			t.Errorf("swap stage %v should be valid", stage)
+		}
+	}
+	if IsValidSwapStage("stable") {
+		t.Errorf("swap stage stable should be invalid")
+	}
+}
@@ -0,0 +1,43 @@
+package mongodb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AddSwapApproval add manual approval of swap
+func AddSwapApproval(fromChainID, txid string, logindex int, actor string) error {
+	key := GetRouterSwapKey(fromChainID, txid, logindex)
+	ma := &MgoSwapApproval{
+		Key:       key,
+		Actor:     actor,
+		Timestamp: time.Now().Unix(),
+	}
+	opts := options.Replace().SetUpsert(true)
+	_, err := collSwapApproval.ReplaceOne(clientCtx, bson.M{"_id": key}, ma, opts)
+	if err != nil {
+		log.Warn("mongodb add swap approval failed", "key", key, "actor", actor, "err", err)
+		return mgoError(err)
+	}
+	mirrorDocs(collSwapApproval, key)
+	log.Info("mongodb add swap approval success", "key", key, "actor", actor)
+	return nil
+}
+
+// IsSwapApproved is swap manually approved
+func IsSwapApproved(key string) (bool, error) {
+	err := collSwapApproval.FindOne(clientCtx, bson.M{"_id": key}).Err()
+	if err != nil {
+		err = mgoError(err)
+		if errors.Is(err, ErrItemNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
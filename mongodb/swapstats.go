@@ -0,0 +1,137 @@
+package mongodb
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// intervals of swap stats buckets
+const (
+	SwapStatHourly = "hourly"
+	SwapStatDaily  = "daily"
+)
+
+// SwapStatsFilter filter of finding swap stats
+type SwapStatsFilter struct {
+	Interval    string `json:"interval"`
+	FromChainID string `json:"fromChainID"`
+	ToChainID   string `json:"toChainID"`
+	TokenID     string `json:"tokenID"`
+	Start       int64  `json:"start"` // seconds, inclusive
+	End         int64  `json:"end"`   // seconds, exclusive
+}
+
+// GetSwapStatKey get key of swap stat
+func GetSwapStatKey(interval string, bucket int64, fromChainID, toChainID, tokenID string) string {
+	return fmt.Sprintf("%v:%v:%v:%v:%v", interval, bucket, fromChainID, toChainID, tokenID)
+}
+
+// FindStableSwapResultsAfter find stable swap results updated after the position
+// (timestamp and key of the last result) and before `before` in time order.
+// the position of the first page is the start time and an empty key.
+func FindStableSwapResultsAfter(timestamp int64, key string, before, limit int64) ([]*MgoSwapResult, error) {
+	query := bson.M{
+		"status": MatchTxStable,
+		"$or": []bson.M{
+			{"timestamp": bson.M{"$gt": timestamp, "$lt": before}},
+			{"timestamp": timestamp, "_id": bson.M{"$gt": key}},
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit)
+	cur, err := collRouterSwapResult.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapResult, 0, 100)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// UpsertSwapStats replace swap stats of buckets
+func UpsertSwapStats(stats []*MgoSwapStat) error {
+	opts := options.Replace().SetUpsert(true)
+	for _, stat := range stats {
+		_, err := collSwapStat.ReplaceOne(clientCtx, bson.M{"_id": stat.Key}, stat, opts)
+		if err != nil {
+			log.Warn("mongodb upsert swap stat failed", "key", stat.Key, "err", err)
+			return mgoError(err)
+		}
+		mirrorDocs(collSwapStat, stat.Key)
+	}
+	return nil
+}
+
+// FindSwapStats find swap stats in bucket order
+func FindSwapStats(filter *SwapStatsFilter, limit int64) ([]*MgoSwapStat, error) {
+	query := bson.D{
+		{Key: "interval", Value: filter.Interval},
+		{Key: "bucket", Value: bson.M{"$gte": filter.Start, "$lt": filter.End}},
+	}
+	if filter.FromChainID != "" {
+		query = append(query, bson.E{Key: "fromChainID", Value: filter.FromChainID})
+	}
+	if filter.ToChainID != "" {
+		query = append(query, bson.E{Key: "toChainID", Value: filter.ToChainID})
+	}
+	if filter.TokenID != "" {
+		query = append(query, bson.E{Key: "tokenID", Value: filter.TokenID})
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "bucket", Value: 1}}).SetLimit(limit)
+	cur, err := collSwapStat.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapStat, 0, 24)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// PruneSwapStats remove swap stats of buckets before the specified time (seconds)
+func PruneSwapStats(before, limit int64) (int64, error) {
+	keys, err := findKeys(collSwapStat, bson.M{"bucket": bson.M{"$lt": before}}, limit)
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	res, err := collSwapStat.DeleteMany(clientCtx, bson.M{"_id": bson.M{"$in": keys}})
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	mirrorDocs(collSwapStat, keys...)
+	return res.DeletedCount, nil
+}
+
+func ensureSwapStatIndexes() {
+	statModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "interval", Value: 1}, {Key: "bucket", Value: 1}},
+	}
+	addExpectedIndexes(collSwapStat, []mongo.IndexModel{statModel})
+	name, err := collSwapStat.Indexes().CreateOne(clientCtx, statModel)
+	if err != nil {
+		log.Warn("[mongodb] create swap stat indexes failed", "err", err)
+	} else {
+		log.Info("[mongodb] create swap stat indexes success", "index", name)
+	}
+
+	// find stable swap results updated recently
+	resultModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "timestamp", Value: 1}},
+	}
+	addExpectedIndexes(collRouterSwapResult, []mongo.IndexModel{resultModel})
+	name, err = collRouterSwapResult.Indexes().CreateOne(clientCtx, resultModel)
+	if err != nil {
+		log.Warn("[mongodb] create swap result timestamp indexes failed", "err", err)
+		return
+	}
+	log.Info("[mongodb] create swap result timestamp indexes success", "index", name)
+}
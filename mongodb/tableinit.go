@@ -8,12 +8,54 @@ const (
 	tbRouterSwaps       string = "RouterSwaps"
 	tbRouterSwapResults string = "RouterSwapResults"
 	tbUsedRValues       string = "UsedRValues"
+	tbDepositAddresses  string = "DepositAddresses"
+	tbSwapReceipts      string = "SwapReceipts"
+	tbSwapRejections    string = "SwapRejections"
+	tbSwapEvents        string = "SwapEvents"
+	tbClaimSwaps        string = "ClaimSwaps"
+	tbSwapStats         string = "SwapStats"
+	tbAPITokens         string = "APITokens"
+	tbSwapTransitions   string = "SwapTransitions"
+	tbMPCUsages         string = "MPCUsages"
+	tbDuplicates        string = "DuplicateDeliveries"
+	tbFeatureFlags      string = "FeatureFlags"
+	tbScanCheckpoints   string = "ScanCheckpoints"
+	tbScanGaps          string = "ScanGaps"
+	tbSignRequests      string = "SignRequests"
+	tbTxReceipts        string = "TxReceipts"
+	tbSignApprovals     string = "SignApprovals"
+	tbDeliveryStats     string = "DeliveryStats"
+	tbWebhookCursors    string = "WebhookCursors"
+	tbSwapApprovals     string = "SwapApprovals"
+	tbGasSamples        string = "GasSamples"
+	tbExportJobs        string = "ExportJobs"
 )
 
 var (
 	collRouterSwap       *mongo.Collection
 	collRouterSwapResult *mongo.Collection
 	collUsedRValue       *mongo.Collection
+	collDepositAddress   *mongo.Collection
+	collSwapReceipt      *mongo.Collection
+	collSwapRejection    *mongo.Collection
+	collSwapEvent        *mongo.Collection
+	collClaimSwap        *mongo.Collection
+	collSwapStat         *mongo.Collection
+	collAPIToken         *mongo.Collection
+	collSwapTransition   *mongo.Collection
+	collMPCUsage         *mongo.Collection
+	collDuplicate        *mongo.Collection
+	collFeatureFlag      *mongo.Collection
+	collScanCheckpoint   *mongo.Collection
+	collScanGap          *mongo.Collection
+	collSignRequest      *mongo.Collection
+	collTxReceipt        *mongo.Collection
+	collSignApproval     *mongo.Collection
+	collDeliveryStats    *mongo.Collection
+	collWebhookCursor    *mongo.Collection
+	collSwapApproval     *mongo.Collection
+	collGasSamples       *mongo.Collection
+	collExportJob        *mongo.Collection
 )
 
 func initCollections() {
@@ -22,4 +64,39 @@ func initCollections() {
 	collRouterSwap = database.Collection(tbRouterSwaps)
 	collRouterSwapResult = database.Collection(tbRouterSwapResults)
 	collUsedRValue = database.Collection(tbUsedRValues)
+	collDepositAddress = database.Collection(tbDepositAddresses)
+	collSwapReceipt = database.Collection(tbSwapReceipts)
+	collSwapRejection = database.Collection(tbSwapRejections)
+	collSwapEvent = database.Collection(tbSwapEvents)
+	collClaimSwap = database.Collection(tbClaimSwaps)
+	collSwapStat = database.Collection(tbSwapStats)
+	collAPIToken = database.Collection(tbAPITokens)
+	collSwapTransition = database.Collection(tbSwapTransitions)
+	collMPCUsage = database.Collection(tbMPCUsages)
+	collDuplicate = database.Collection(tbDuplicates)
+	collFeatureFlag = database.Collection(tbFeatureFlags)
+	collScanCheckpoint = database.Collection(tbScanCheckpoints)
+	collScanGap = database.Collection(tbScanGaps)
+	collSignRequest = database.Collection(tbSignRequests)
+	collTxReceipt = database.Collection(tbTxReceipts)
+	collSignApproval = database.Collection(tbSignApprovals)
+	collDeliveryStats = database.Collection(tbDeliveryStats)
+	collWebhookCursor = database.Collection(tbWebhookCursors)
+	collSwapApproval = database.Collection(tbSwapApprovals)
+	collGasSamples = database.Collection(tbGasSamples)
+	collExportJob = database.Collection(tbExportJobs)
+
+	ensureStuckSwapsIndexes()
+	ensureDepositAddressIndexes()
+	ensureSwapRejectionIndexes()
+	ensureSwapEventIndexes()
+	ensureClaimSwapIndexes()
+	ensureSwapStatIndexes()
+	ensureScopedSwapsIndexes()
+	ensureSwapTransitionIndexes()
+	ensureMPCUsageIndexes()
+	ensureDuplicateDeliveryIndexes()
+	ensureSignApprovalIndexes()
+	ensureExportJobIndexes()
+	initStatusQueues(database)
 }
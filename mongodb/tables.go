@@ -2,6 +2,8 @@ package mongodb
 
 import (
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
+
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 // MgoSwap registered swap
@@ -22,6 +24,10 @@ type MgoSwap struct {
 	InitTime    int64      `bson:"inittime"`
 	Timestamp   int64      `bson:"timestamp"`
 	Memo        string     `bson:"memo" json:",omitempty"`
+	RequestID   string     `bson:"requestID,omitempty" json:",omitempty"`   // request ID of registering api call
+	Quarantined bool       `bson:"quarantined,omitempty" json:",omitempty"` // re-verify with cross verification
+
+	QuarantineTime int64 `bson:"quarantinetime,omitempty" json:",omitempty"`
 }
 
 // IsValid is valid
@@ -45,7 +51,7 @@ func (swap *MgoSwap) IsValid() bool {
 		if swap.AnyCallSwapInfo == nil {
 			return false
 		}
-	case tokens.SapphireRPCType: // internal usage, never store
+	case tokens.SapphireRPCType, tokens.DepositSweepType, tokens.ClaimRefundType, tokens.AllowanceApproveType: // internal usage, never store
 		return false
 	default:
 		return false
@@ -98,12 +104,16 @@ type MgoSwapResult struct {
 	SwapTime    uint64     `bson:"swaptime"`
 	SwapValue   string     `bson:"swapvalue"`
 	SwapNonce   uint64     `bson:"swapnonce"`
+	SwapFee     string     `bson:"swapfee,omitempty" json:",omitempty"`
 	Status      SwapStatus `bson:"status"`
 	InitTime    int64      `bson:"inittime"`
 	Timestamp   int64      `bson:"timestamp"`
 	Memo        string     `bson:"memo" json:",omitempty"`
 	MPC         string     `bson:"mpc"`
 	TTL         uint64     `bson:"ttl"`
+
+	BuilderVersion     uint64 `bson:"builderversion,omitempty" json:",omitempty"`
+	DeliveryFeePercent uint64 `bson:"deliveryfeepercent,omitempty" json:",omitempty"`
 }
 
 // MgoUsedRValue security enhancement
@@ -112,6 +122,285 @@ type MgoUsedRValue struct {
 	Timestamp int64  `bson:"timestamp"`
 }
 
+// MgoDepositAddress per-user deposit address
+type MgoDepositAddress struct {
+	Key        string `bson:"_id"` // chainID + address
+	ChainID    string `bson:"chainID"`
+	Address    string `bson:"address"`
+	Salt       string `bson:"salt"`
+	ToChainID  string `bson:"toChainID"`
+	Bind       string `bson:"bind"`
+	Timestamp  int64  `bson:"timestamp"`
+	SweepCount int    `bson:"sweepcount"`
+	SweepTime  int64  `bson:"sweeptime"` // seconds
+}
+
+// MgoClaimSwap two-phase swap claimed on destination chain
+type MgoClaimSwap struct {
+	Key         string `bson:"_id" json:"-"` // fromChainID + txid + logindex
+	FromChainID string `bson:"fromChainID" json:"fromChainID"`
+	TxID        string `bson:"txid" json:"txid"`
+	LogIndex    int    `bson:"logIndex" json:"logIndex"`
+	ToChainID   string `bson:"toChainID" json:"toChainID"`
+	TokenID     string `bson:"tokenID" json:"tokenID"`
+	ClaimID     string `bson:"claimID" json:"claimID"`
+	Escrow      string `bson:"escrow" json:"escrow"`
+	Receiver    string `bson:"receiver" json:"receiver"`
+	Value       string `bson:"value" json:"value"`
+	SwapTx      string `bson:"swaptx" json:"swaptx"`
+	Status      string `bson:"status" json:"status"`
+	Expiry      int64  `bson:"expiry" json:"expiry"` // seconds, zero before checked onchain
+	RefundTx    string `bson:"refundtx,omitempty" json:"refundtx,omitempty"`
+	RefundTime  int64  `bson:"refundtime,omitempty" json:"refundtime,omitempty"`
+	InitTime    int64  `bson:"inittime" json:"inittime"`
+	Timestamp   int64  `bson:"timestamp" json:"timestamp"`
+}
+
+// MgoQueueItem item of status queue
+type MgoQueueItem struct {
+	Key       string     `bson:"_id"`
+	Status    SwapStatus `bson:"status"`
+	Timestamp int64      `bson:"timestamp"`
+	InitTime  int64      `bson:"inittime"`
+}
+
+// MgoSwapReceipt signed completion receipt of swap
+type MgoSwapReceipt struct {
+	Key         string `bson:"_id" json:"-"` // fromChainID + txid + logindex
+	Identifier  string `bson:"identifier" json:"identifier"`
+	FromChainID string `bson:"fromChainID" json:"fromChainID"`
+	TxID        string `bson:"txid" json:"txid"`
+	LogIndex    int    `bson:"logIndex" json:"logIndex"`
+	ToChainID   string `bson:"toChainID" json:"toChainID"`
+	TokenID     string `bson:"tokenID" json:"tokenID"`
+	From        string `bson:"from" json:"from"`
+	Bind        string `bson:"bind" json:"bind"`
+	Value       string `bson:"value" json:"value"`
+	SwapValue   string `bson:"swapvalue" json:"swapValue"`
+	SwapFee     string `bson:"swapfee" json:"swapFee"`
+	SwapTx      string `bson:"swaptx" json:"swapTx"`
+	SwapHeight  uint64 `bson:"swapheight" json:"swapHeight"`
+	Timestamp   int64  `bson:"timestamp" json:"timestamp"`
+	Message     string `bson:"message" json:"message"`
+	Signer      string `bson:"signer" json:"signer"`
+	Signature   string `bson:"signature" json:"signature"`
+}
+
+// MgoSwapRejection rejection record of swap failed verification
+type MgoSwapRejection struct {
+	Key         string `bson:"_id" json:"-"` // fromChainID + txid + logindex
+	FromChainID string `bson:"fromChainID" json:"fromChainID"`
+	TxID        string `bson:"txid" json:"txid"`
+	LogIndex    int    `bson:"logIndex" json:"logIndex"`
+	Reason      string `bson:"reason" json:"reason"`
+	Message     string `bson:"message" json:"message"`
+	Count       int    `bson:"count" json:"count"`
+	InitTime    int64  `bson:"inittime" json:"inittime"`
+	Timestamp   int64  `bson:"timestamp" json:"timestamp"`
+}
+
+// MgoSwapEvent lifecycle event of swap
+type MgoSwapEvent struct {
+	Key       string `bson:"_id" json:"-"` // swap key + event + timestamp
+	SwapKey   string `bson:"swapkey" json:"-"`
+	Event     string `bson:"event" json:"event"`
+	Timestamp int64  `bson:"timestamp" json:"timestamp"`                   // milli seconds
+	Duration  int64  `bson:"duration,omitempty" json:"duration,omitempty"` // milli seconds
+	Detail    string `bson:"detail,omitempty" json:"detail,omitempty"`
+}
+
+// MgoSwapTransition status transition of swap or swap result (append only)
+type MgoSwapTransition struct {
+	Key       string          `bson:"_id" json:"-"` // swap key + stage + timestamp + seq
+	SwapKey   string          `bson:"swapkey" json:"-"`
+	Stage     string          `bson:"stage" json:"stage"`                             // swap or result
+	OldStatus *SwapStatus     `bson:"oldstatus,omitempty" json:"oldStatus,omitempty"` // nil on creation
+	NewStatus SwapStatus      `bson:"newstatus" json:"newStatus"`
+	Actor     EncryptedString `bson:"actor" json:"actor"`
+	Reason    string          `bson:"reason,omitempty" json:"reason,omitempty"`
+	Timestamp int64           `bson:"timestamp" json:"timestamp"` // milli seconds
+
+	// event-sourced history of swap fields for point-in-time snapshots
+	Seq        uint64 `bson:"seq" json:"-"`
+	Changes    bson.M `bson:"changes,omitempty" json:"-"`    // updated fields (the whole document on creation)
+	ChangeOnly bool   `bson:"changeonly,omitempty" json:"-"` // field changes without status transition
+	Removed    bool   `bson:"removed,omitempty" json:"-"`    // the document is removed
+}
+
+// MgoSwapStat stats of finalized swaps of chain pair and token in time bucket
+type MgoSwapStat struct {
+	Key               string `bson:"_id" json:"-"` // interval + bucket + fromChainID + toChainID + tokenID
+	Interval          string `bson:"interval" json:"interval"`
+	Bucket            int64  `bson:"bucket" json:"bucket"` // start time of bucket, seconds
+	FromChainID       string `bson:"fromChainID" json:"fromChainID"`
+	ToChainID         string `bson:"toChainID" json:"toChainID"`
+	TokenID           string `bson:"tokenID" json:"tokenID"`
+	Count             int64  `bson:"count" json:"count"`
+	Volume            string `bson:"volume" json:"volume"`                       // normalized to 18 decimals
+	FeeRevenue        string `bson:"feeRevenue" json:"feeRevenue"`               // normalized to 18 decimals
+	AvgCompletionTime int64  `bson:"avgCompletionTime" json:"avgCompletionTime"` // milli seconds
+	Timestamp         int64  `bson:"timestamp" json:"-"`
+}
+
+// MgoMPCUsage mpc sign usage of chain in time bucket
+type MgoMPCUsage struct {
+	Key        string `bson:"_id" json:"-"`         // bucket + chainID
+	Bucket     int64  `bson:"bucket" json:"bucket"` // start time of bucket, seconds
+	ChainID    string `bson:"chainID" json:"chainID"`
+	SignCount  int64  `bson:"signCount" json:"signCount"`
+	FailCount  int64  `bson:"failCount" json:"failCount"`
+	BucketSize int64  `bson:"bucketSize" json:"bucketSize"` // seconds
+	Timestamp  int64  `bson:"timestamp" json:"-"`
+}
+
+// MgoSignRequest pending mpc sign request, it is kept until the sign round finishes
+// so that the sign result can be resumed after restart instead of signing again.
+// the msg context (build args of the tx) is kept to rebuild the same tx.
+type MgoSignRequest struct {
+	Key        string   `bson:"_id"` // swap key + nonce, or hash of signPubkey + msgHash
+	SwapKey    string   `bson:"swapKey,omitempty"`
+	SwapNonce  uint64   `bson:"swapNonce,omitempty"`
+	SignPubkey string   `bson:"signPubkey"`
+	MsgHash    []string `bson:"msgHash"`
+	MsgContext []string `bson:"msgContext"`
+	KeyID      string   `bson:"keyID"`
+	RPCAddr    string   `bson:"rpcAddr"`
+	SignGroup  string   `bson:"signGroup"`
+	Timestamp  int64    `bson:"timestamp"` // seconds
+}
+
+// MgoSignApproval dual control approval of sign request by an approver,
+// approvals are consumed (removed) when the sign request is allowed.
+type MgoSignApproval struct {
+	Key         string `bson:"_id"`         // approval key + approver
+	ApprovalKey string `bson:"approvalKey"` // signPubkey + msgHash
+	Approver    string `bson:"approver"`
+	Expiry      int64  `bson:"expiry"`    // seconds
+	Timestamp   int64  `bson:"timestamp"` // seconds
+}
+
+// MgoSwapApproval manual approval of swap waiting for approval (see LocalChainConfig.ManualApproval)
+type MgoSwapApproval struct {
+	Key       string `bson:"_id"` // swap key
+	Actor     string `bson:"actor"`
+	Timestamp int64  `bson:"timestamp"` // seconds
+}
+
+// MgoDeliveryStats persisted delivery stats of token to chain (see tokens.DeliveryStats)
+type MgoDeliveryStats struct {
+	Key         string  `bson:"_id"` // tokenID + toChainID
+	TokenID     string  `bson:"tokenID"`
+	ToChainID   string  `bson:"toChainID"`
+	Samples     int     `bson:"samples"`
+	AvgFeePaid  float64 `bson:"avgFeePaid"`
+	FailureRate float64 `bson:"failureRate"`
+	ReplaceRate float64 `bson:"replaceRate"`
+	Timestamp   int64   `bson:"timestamp"`
+}
+
+// MgoGasSamples persisted gas used samples of token and swap method (see tokens.GasSamples)
+type MgoGasSamples struct {
+	Key       string   `bson:"_id"` // chainID + tokenID + method
+	ChainID   string   `bson:"chainID"`
+	TokenID   string   `bson:"tokenID"`
+	Method    string   `bson:"method"`
+	Samples   []uint64 `bson:"samples"`
+	Timestamp int64    `bson:"timestamp"`
+}
+
+// MgoExportJob async swap export job, the export file is written to the
+// export dir of the host running the job (see ExportConfig.Dir).
+type MgoExportJob struct {
+	Key        string `bson:"_id"`   // job id
+	Owner      string `bson:"owner"` // key of api token
+	Bind       string `bson:"bind,omitempty"`
+	TokenID    string `bson:"tokenID,omitempty"`
+	SwapStatus string `bson:"swapStatus,omitempty"`
+	Start      int64  `bson:"start"`
+	End        int64  `bson:"end"`
+	Format     string `bson:"format"`
+	Host       string `bson:"host"`
+	File       string `bson:"file"`
+	Status     string `bson:"status"`
+	Rows       int64  `bson:"rows"`
+	Error      string `bson:"error,omitempty"`
+	CreateTime int64  `bson:"createtime"`
+	UpdateTime int64  `bson:"updatetime"` // heartbeat of running job
+	FinishTime int64  `bson:"finishtime,omitempty"`
+}
+
+// MgoWebhookCursor delivery position of webhook (the last delivered swap event)
+type MgoWebhookCursor struct {
+	Key        string `bson:"_id"`       // webhook name
+	Timestamp  int64  `bson:"timestamp"` // milli seconds
+	EventKey   string `bson:"eventKey"`
+	UpdateTime int64  `bson:"updateTime"` // seconds
+}
+
+// MgoTxReceipt raw receipt of swap tx saved at verify time,
+// so that later verification and audit do not depend on archive nodes.
+type MgoTxReceipt struct {
+	Key         string `bson:"_id"` // chainID + txid
+	ChainID     string `bson:"chainID"`
+	TxID        string `bson:"txid"`
+	BlockNumber uint64 `bson:"blockNumber"`
+	BlockHash   string `bson:"blockHash"`
+	Receipt     string `bson:"receipt"` // json of rpc receipt
+	Timestamp   int64  `bson:"timestamp"`
+}
+
+// MgoDuplicateDelivery duplicate destination tx of swap which also succeeded on chain
+type MgoDuplicateDelivery struct {
+	Key             string          `bson:"_id" json:"key"` // fromChainID + txid + logindex + duplicateTx
+	FromChainID     string          `bson:"fromChainID" json:"fromChainID"`
+	TxID            string          `bson:"txid" json:"txid"`
+	LogIndex        int             `bson:"logIndex" json:"logIndex"`
+	ToChainID       string          `bson:"toChainID" json:"toChainID"`
+	TokenID         string          `bson:"tokenID" json:"tokenID"`
+	Bind            string          `bson:"bind" json:"bind"`
+	SwapTx          string          `bson:"swaptx" json:"swaptx"` // the accepted swap tx
+	DuplicateTx     string          `bson:"duplicateTx" json:"duplicateTx"`
+	DuplicateHeight uint64          `bson:"duplicateHeight" json:"duplicateHeight"`
+	OverpaidValue   string          `bson:"overpaidValue" json:"overpaidValue"`
+	RecoveryStatus  string          `bson:"recoveryStatus" json:"recoveryStatus"`
+	RecoveryTx      string          `bson:"recoveryTx,omitempty" json:"recoveryTx,omitempty"`
+	Memo            string          `bson:"memo,omitempty" json:"memo,omitempty"`
+	Actor           EncryptedString `bson:"actor,omitempty" json:"actor,omitempty"` // last updated by
+	DetectTime      int64           `bson:"detectTime" json:"detectTime"`
+	UpdateTime      int64           `bson:"updateTime" json:"updateTime"`
+}
+
+// MgoFeatureFlag admin override of feature flag
+type MgoFeatureFlag struct {
+	Key        string          `bson:"_id" json:"-"` // flag + chainID
+	Flag       string          `bson:"flag" json:"flag"`
+	ChainID    string          `bson:"chainID" json:"chainID"` // 'all' means all chains
+	Enabled    bool            `bson:"enabled" json:"enabled"`
+	Actor      EncryptedString `bson:"actor,omitempty" json:"actor,omitempty"`
+	UpdateTime int64           `bson:"updateTime" json:"updateTime"`
+}
+
+// MgoScanCheckpoint last scanned position of scanner on chain
+type MgoScanCheckpoint struct {
+	Key        string `bson:"_id"` // chainID + scanner
+	ChainID    string `bson:"chainID"`
+	Scanner    string `bson:"scanner"`
+	Height     uint64 `bson:"height"`
+	Marker     string `bson:"marker,omitempty"`
+	UpdateTime int64  `bson:"updateTime"`
+}
+
+// MgoScanGap skipped heights range of scanner waiting to be backfilled
+type MgoScanGap struct {
+	Key        string `bson:"_id"` // chainID + scanner + from
+	ChainID    string `bson:"chainID"`
+	Scanner    string `bson:"scanner"`
+	From       uint64 `bson:"from"`
+	To         uint64 `bson:"to"`
+	Reason     string `bson:"reason,omitempty"`
+	CreateTime int64  `bson:"createTime"`
+}
+
 // SwapResultUpdateItems swap update items
 type SwapResultUpdateItems struct {
 	MPC        string
@@ -120,10 +409,14 @@ type SwapResultUpdateItems struct {
 	SwapTime   uint64
 	SwapValue  string
 	SwapNonce  uint64
+	SwapFee    string
 	Status     SwapStatus
 	Timestamp  int64
 	Memo       string
 	TTL        uint64
+
+	BuilderVersion     uint64
+	DeliveryFeePercent uint64
 }
 
 // SwapInfo struct
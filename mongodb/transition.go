@@ -0,0 +1,180 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// actors of swap status transitions, admin actor is suffixed with the admin address
+const (
+	ActorRouter    = "router"
+	ActorIntegrity = "integrity"
+	ActorAdmin     = "admin"
+)
+
+// stages of swap status transitions
+const (
+	TransitionStageSwap   = "swap"   // status of router swap
+	TransitionStageResult = "result" // status of router swap result
+)
+
+var transitionSeq uint64
+
+// GetAdminActor get actor of admin call
+func GetAdminActor(admin string) string {
+	return ActorAdmin + ":" + admin
+}
+
+// addSwapTransition append status transition of swap, old status is nil on creation.
+// changes are the updated fields (the whole document on creation) for snapshots.
+// the transition log is append only, failures are only logged.
+func addSwapTransition(coll *mongo.Collection, key string, oldStatus *SwapStatus, newStatus SwapStatus, actor, reason string, changes bson.M) {
+	addSwapHistory(coll, key, &MgoSwapTransition{
+		OldStatus: oldStatus,
+		NewStatus: newStatus,
+		Actor:     EncryptedString(actor),
+		Reason:    reason,
+		Changes:   changes,
+	})
+}
+
+// addSwapChanges append field changes of swap without status transition (used by snapshots only)
+func addSwapChanges(coll *mongo.Collection, key string, changes bson.M, actor, reason string) {
+	addSwapHistory(coll, key, &MgoSwapTransition{
+		Actor:      EncryptedString(actor),
+		Reason:     reason,
+		Changes:    changes,
+		ChangeOnly: true,
+	})
+}
+
+// addSwapRemoval append removal of swap document (used by snapshots only)
+func addSwapRemoval(coll *mongo.Collection, key, actor, reason string) {
+	addSwapHistory(coll, key, &MgoSwapTransition{
+		Actor:      EncryptedString(actor),
+		Reason:     reason,
+		ChangeOnly: true,
+		Removed:    true,
+	})
+}
+
+func addSwapHistory(coll *mongo.Collection, key string, mt *MgoSwapTransition) {
+	stage := TransitionStageSwap
+	if coll == collRouterSwapResult {
+		stage = TransitionStageResult
+	}
+	initSwapHistory(mt, key, stage)
+	_, err := collSwapTransition.InsertOne(clientCtx, mt)
+	if err == nil {
+		mirrorDocs(collSwapTransition, mt.Key)
+	} else {
+		log.Warn("mongodb add swap transition failed", "key", key, "stage", stage, "oldStatus", mt.OldStatus, "newStatus", mt.NewStatus, "changeOnly", mt.ChangeOnly, "actor", mt.Actor, "err", err)
+	}
+}
+
+// initSwapHistory set key, stage, timestamp and sequence of swap history
+func initSwapHistory(mt *MgoSwapTransition, key, stage string) {
+	mt.Timestamp = common.NowMilli()
+	mt.Seq = atomic.AddUint64(&transitionSeq, 1)
+	mt.Key = fmt.Sprintf("%v:%v:%v:%v", key, stage, mt.Timestamp, mt.Seq)
+	mt.SwapKey = key
+	mt.Stage = stage
+}
+
+// newSwapStatusHistory get history of updates, it is a status transition
+// if the status is changed by the updates, otherwise it is field changes only.
+func newSwapStatusHistory(oldStatus SwapStatus, updates bson.M, actor, reason string) *MgoSwapTransition {
+	mt := &MgoSwapTransition{
+		Actor:   EncryptedString(actor),
+		Reason:  reason,
+		Changes: updates,
+	}
+	if newStatus, ok := updates["status"].(SwapStatus); ok && newStatus != oldStatus {
+		mt.OldStatus = &oldStatus
+		mt.NewStatus = newStatus
+	} else {
+		mt.ChangeOnly = true
+	}
+	return mt
+}
+
+// getHistoryChanges get fields of the created document as history changes
+func getHistoryChanges(doc interface{}) bson.M {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		log.Warn("mongodb marshal swap history document failed", "err", err)
+		return nil
+	}
+	changes := bson.M{}
+	if err = bson.Unmarshal(data, &changes); err != nil {
+		log.Warn("mongodb unmarshal swap history document failed", "err", err)
+		return nil
+	}
+	delete(changes, "_id")
+	return changes
+}
+
+// updateSwapStatusByID update swap or swap result of key (with optional extra filter),
+// and append the status transition if status is changed by the updates.
+// it returns matched is false if no document is updated.
+func updateSwapStatusByID(coll *mongo.Collection, key string, filter, updates bson.M, actor, reason string) (matched bool, err error) {
+	query := bson.M{"_id": key}
+	for k, v := range filter {
+		query[k] = v
+	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.Before).
+		SetProjection(bson.M{"status": 1})
+	var old struct {
+		Status SwapStatus `bson:"status"`
+	}
+	err = coll.FindOneAndUpdate(clientCtx, query, bson.M{"$set": updates}, opts).Decode(&old)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	addSwapHistory(coll, key, newSwapStatusHistory(old.Status, updates, actor, reason))
+	return true, nil
+}
+
+// FindSwapTransitions find status transitions of swap in time order
+func FindSwapTransitions(fromChainID, txid string, logIndex int) ([]*MgoSwapTransition, error) {
+	swapKey := GetRouterSwapKey(fromChainID, txid, logIndex)
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "timestamp", Value: 1}},
+	}
+	query := bson.M{"swapkey": swapKey, "changeonly": bson.M{"$ne": true}}
+	cur, err := collSwapTransition.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapTransition, 0, 8)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+func ensureSwapTransitionIndexes() {
+	model := mongo.IndexModel{
+		Keys: bson.D{{Key: "swapkey", Value: 1}, {Key: "timestamp", Value: 1}},
+	}
+	addExpectedIndexes(collSwapTransition, []mongo.IndexModel{model})
+	name, err := collSwapTransition.Indexes().CreateOne(clientCtx, model)
+	if err != nil {
+		log.Warn("[mongodb] create swap transition indexes failed", "err", err)
+		return
+	}
+	log.Info("[mongodb] create swap transition indexes success", "index", name)
+}
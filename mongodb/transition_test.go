@@ -0,0 +1,57 @@
+package mongodb
+
+import (
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestNewSwapStatusHistory(t *testing.T) {
+	tests := []struct {
+		oldStatus  SwapStatus
+		updates    bson.M
+		changeOnly bool
+	}{
+		{TxNotStable, bson.M{"status": TxNotSwapped, "timestamp": int64(1)}, false},
+		// status is not changed
+		{TxNotSwapped, bson.M{"status": TxNotSwapped, "timestamp": int64(1)}, true},
+		// status is not updated
+		{TxNotSwapped, bson.M{"height": uint64(100)}, true},
+	}
+	for i, test := range tests {
+		mt := newSwapStatusHistory(test.oldStatus, test.updates, GetAdminActor("0x01"), "reason")
+		if mt.ChangeOnly != test.changeOnly || mt.Actor != "admin:0x01" || mt.Reason != "reason" || len(mt.Changes) != len(test.updates) {
+			t.Errorf("test %v: new swap status history got %+v, want change only %v", i, mt, test.changeOnly)
+			continue
+		}
+		if !test.changeOnly && (mt.OldStatus == nil || *mt.OldStatus != test.oldStatus || mt.NewStatus != test.updates["status"]) {
+			t.Errorf("test %v: swap status transition got %v -> %v, want %v -> %v", i, mt.OldStatus, mt.NewStatus, test.oldStatus, test.updates["status"])
+		}
+	}
+}
+
+func TestInitSwapHistory(t *testing.T) {
+	swapKey := GetRouterSwapKey("1", "0x01", 0)
+	mt1, mt2 := &MgoSwapTransition{}, &MgoSwapTransition{}
+	initSwapHistory(mt1, swapKey, TransitionStageSwap)
+	initSwapHistory(mt2, swapKey, TransitionStageSwap)
+	// histories of the same swap in the same milli second are appended, not overwritten
+	if mt1.Key == mt2.Key || mt2.Seq <= mt1.Seq {
+		t.Errorf("swap histories got keys %v and %v, want unique and ordered", mt1.Key, mt2.Key)
+	}
+	if mt1.SwapKey != swapKey || mt1.Stage != TransitionStageSwap || !strings.HasPrefix(mt1.Key, swapKey+":"+TransitionStageSwap+":") {
+		t.Errorf("swap history got %+v", mt1)
+	}
+}
+
+func TestGetHistoryChanges(t *testing.T) {
+	swap := &MgoSwap{Key: "1:0x01:0", FromChainID: "1", TxID: "0x01", Status: TxNotStable}
+	changes := getHistoryChanges(swap)
+	if _, exist := changes["_id"]; exist {
+		t.Errorf("history changes should not contain the document id")
+	}
+	if changes["txid"] != "0x01" || changes["fromChainID"] != "1" {
+		t.Errorf("history changes got %v", changes)
+	}
+}
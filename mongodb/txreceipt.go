@@ -0,0 +1,40 @@
+package mongodb
+
+import (
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetTxReceiptKey get key of tx receipt
+func GetTxReceiptKey(chainID, txid string) string {
+	return strings.ToLower(chainID + ":" + txid)
+}
+
+// AddTxReceipt add or replace receipt of tx (the tx may be reorged into another block)
+func AddTxReceipt(mr *MgoTxReceipt) error {
+	mr.Key = GetTxReceiptKey(mr.ChainID, mr.TxID)
+	mr.TxID = strings.ToLower(mr.TxID)
+	opts := options.Replace().SetUpsert(true)
+	_, err := collTxReceipt.ReplaceOne(clientCtx, bson.M{"_id": mr.Key}, mr, opts)
+	if err != nil {
+		log.Warn("mongodb add tx receipt failed", "chainID", mr.ChainID, "txid", mr.TxID, "err", err)
+		return mgoError(err)
+	}
+	mirrorDocs(collTxReceipt, mr.Key)
+	log.Info("mongodb add tx receipt success", "chainID", mr.ChainID, "txid", mr.TxID, "block", mr.BlockNumber)
+	return nil
+}
+
+// FindTxReceipt find saved receipt of tx
+func FindTxReceipt(chainID, txid string) (*MgoTxReceipt, error) {
+	result := &MgoTxReceipt{}
+	err := collTxReceipt.FindOne(clientCtx, bson.M{"_id": GetTxReceiptKey(chainID, txid)}).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
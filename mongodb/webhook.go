@@ -0,0 +1,38 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindWebhookCursor find delivery position of webhook
+func FindWebhookCursor(name string) (*MgoWebhookCursor, error) {
+	result := &MgoWebhookCursor{}
+	err := collWebhookCursor.FindOne(clientCtx, bson.M{"_id": name}).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// UpdateWebhookCursor update delivery position of webhook to the delivered event
+func UpdateWebhookCursor(name string, timestamp int64, eventKey string) error {
+	mc := &MgoWebhookCursor{
+		Key:        name,
+		Timestamp:  timestamp,
+		EventKey:   eventKey,
+		UpdateTime: time.Now().Unix(),
+	}
+	opts := options.Replace().SetUpsert(true)
+	_, err := collWebhookCursor.ReplaceOne(clientCtx, bson.M{"_id": name}, mc, opts)
+	if err != nil {
+		log.Warn("mongodb update webhook cursor failed", "name", name, "timestamp", timestamp, "eventKey", eventKey, "err", err)
+		return mgoError(err)
+	}
+	mirrorDocs(collWebhookCursor, name)
+	return nil
+}
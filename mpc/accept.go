@@ -4,10 +4,14 @@ import (
 	"encoding/json"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/params"
 )
 
 // DoAcceptSign accept sign
 func (c *Config) DoAcceptSign(keyID, agreeResult string, msgHash, msgContext []string) (string, error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	nonce := uint64(0)
 	data := AcceptData{
 		TxType:     "ACCEPTSIGN",
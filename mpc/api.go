@@ -9,6 +9,7 @@ import (
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
 )
 
@@ -133,6 +134,9 @@ func (c *Config) GetCurNodeSignInfo(expiredInterval int64) ([]*SignInfoData, err
 
 // Sign call sign
 func (c *Config) Sign(raw, rpcAddr string) (string, error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	var result DataResultResp
 	err := c.httpPostTo(&result, rpcAddr, "sign", raw)
 	if err != nil {
@@ -146,6 +150,9 @@ func (c *Config) Sign(raw, rpcAddr string) (string, error) {
 
 // AcceptSign call acceptSign
 func (c *Config) AcceptSign(raw string) (string, error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	var result DataResultResp
 	err := c.httpPost(&result, "acceptSign", raw)
 	if err != nil {
@@ -0,0 +1,230 @@
+package mpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+)
+
+const maxApprovalLifetime = int64(24 * 3600) // seconds
+
+var (
+	// key is sign pubkey and msg hashes, value is map of approver to expiry
+	signApprovals     = make(map[string]map[string]int64)
+	signApprovalsLock sync.Mutex
+
+	errSignNotApproved       = errors.New("sign request is not approved by dual control")
+	errApprovalExpired       = errors.New("sign approval is expired")
+	errApprovalTooLong       = errors.New("sign approval lifetime is too long")
+	errApproverNotAllowed    = errors.New("sign approval is not signed by approver")
+	errDualControlNotEnabled = errors.New("dual control is not enabled")
+	errNoSignRequestVerifier = errors.New("no sign request verifier")
+	errWrongMsgContext       = errors.New("wrong msg context")
+)
+
+// SignApproval approval of sign request outside normal swap flow,
+// it is signed by the approver's own key and can be used only once.
+type SignApproval struct {
+	SignPubkey string   `json:"signPubkey"`
+	MsgHash    []string `json:"msgHash"`
+	Expiry     int64    `json:"expiry"` // unix seconds
+	Signature  string   `json:"signature"`
+}
+
+// Hash get hash of approval to be signed
+func (a *SignApproval) Hash() common.Hash {
+	message := fmt.Sprintf("mpc sign approval:%v:%v:%v",
+		strings.ToLower(a.SignPubkey),
+		strings.ToLower(strings.Join(a.MsgHash, ",")),
+		a.Expiry)
+	return crypto.Keccak256Hash([]byte(message))
+}
+
+// Approver recover approver address from signature
+func (a *SignApproval) Approver() (common.Address, error) {
+	sig := common.FromHex(a.Signature)
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, errWrongSignatureLength
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pubkey, err := crypto.SigToPub(a.Hash().Bytes(), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+func getSignApprovalKey(signPubkey string, msgHash []string) string {
+	return strings.ToLower(signPubkey + ":" + strings.Join(msgHash, ","))
+}
+
+// SignRequestVerifier verify the msg hashes of sign request are of the tx
+// which is rebuilt from args (the msg context)
+type SignRequestVerifier func(msgHash []string, args *tokens.BuildTxArgs) error
+
+var signRequestVerifier SignRequestVerifier
+
+// SetSignRequestVerifier set verifier of sign requests of stored swaps
+// and internal txs, which are allowed without approvals of dual control.
+func SetSignRequestVerifier(verifier SignRequestVerifier) {
+	signRequestVerifier = verifier
+}
+
+// AddSignApproval verify and add approval of sign request
+func (c *Config) AddSignApproval(approval *SignApproval) (approver string, err error) {
+	if c.dualControl == nil {
+		return "", errDualControlNotEnabled
+	}
+	now := time.Now().Unix()
+	if approval.Expiry <= now {
+		return "", errApprovalExpired
+	}
+	if approval.Expiry > now+maxApprovalLifetime {
+		return "", errApprovalTooLong
+	}
+	address, err := approval.Approver()
+	if err != nil {
+		return "", err
+	}
+	approver = address.LowerHex()
+	if !c.dualControl.IsApprover(approver) {
+		return "", errApproverNotAllowed
+	}
+
+	key := getSignApprovalKey(approval.SignPubkey, approval.MsgHash)
+	if mongodb.HasClient() {
+		// persist approvals so that they survive restarts
+		if err = mongodb.AddSignApproval(key, approver, approval.Expiry); err != nil {
+			return "", err
+		}
+		log.Info("mpc add sign approval", "approver", approver, "signPubkey", approval.SignPubkey, "msgHash", approval.MsgHash, "expiry", approval.Expiry)
+		return approver, nil
+	}
+	signApprovalsLock.Lock()
+	defer signApprovalsLock.Unlock()
+	approvers, exist := signApprovals[key]
+	if !exist {
+		approvers = make(map[string]int64)
+		signApprovals[key] = approvers
+	}
+	approvers[approver] = approval.Expiry
+	log.Info("mpc add sign approval", "approver", approver, "signPubkey", approval.SignPubkey, "msgHash", approval.MsgHash, "expiry", approval.Expiry, "approvals", len(approvers))
+	return approver, nil
+}
+
+// checkDualControl check sign request is from a stored verified swap,
+// otherwise it must have enough distinct approvals, which are consumed.
+func (c *Config) checkDualControl(signPubkey string, msgHash, msgContext []string) error {
+	if c.dualControl == nil {
+		return nil
+	}
+	if isCanaryRequest(msgHash, msgContext) {
+		return nil
+	}
+	if err := verifySignRequest(msgHash, msgContext); err == nil {
+		return nil
+	} else if len(msgContext) > 0 {
+		log.Info("mpc sign request is not verified", "signPubkey", signPubkey, "msgHash", msgHash, "err", err)
+	}
+	if c.dualControl.RecoveryMode {
+		log.Warn("mpc sign bypass dual control in recovery mode", "signPubkey", signPubkey, "msgHash", msgHash, "msgContext", msgContext)
+		return nil
+	}
+
+	key := getSignApprovalKey(signPubkey, msgHash)
+	approved, err := c.consumeSignApprovals(key)
+	if err != nil {
+		log.Warn("mpc sign rejected by dual control", "signPubkey", signPubkey, "msgHash", msgHash, "err", err, "required", c.dualControl.GetMinApprovals())
+		return err
+	}
+	log.Info("mpc sign approved by dual control", "signPubkey", signPubkey, "msgHash", msgHash, "approvers", approved)
+	return nil
+}
+
+// consumeSignApprovals consume the approvals of key if there are enough
+func (c *Config) consumeSignApprovals(key string) (approved []string, err error) {
+	now := time.Now().Unix()
+	if mongodb.HasClient() {
+		approvals, errf := mongodb.FindSignApprovals(key, now)
+		if errf != nil && !errors.Is(errf, mongodb.ErrItemNotFound) {
+			return nil, errf
+		}
+		var valid []*mongodb.MgoSignApproval
+		valid, approved = c.getValidApprovals(approvals)
+		if len(valid) < c.dualControl.GetMinApprovals() {
+			return nil, errSignNotApproved
+		}
+		// the approvals are consumed only by the one who removes all of them
+		removed, errc := mongodb.ConsumeSignApprovals(key, valid)
+		if errc != nil {
+			return nil, errc
+		}
+		if removed != int64(len(valid)) {
+			return nil, errSignNotApproved
+		}
+		return approved, nil
+	}
+
+	signApprovalsLock.Lock()
+	defer signApprovalsLock.Unlock()
+	for approver, expiry := range signApprovals[key] {
+		if expiry > now && c.dualControl.IsApprover(approver) {
+			approved = append(approved, approver)
+		}
+	}
+	if len(approved) < c.dualControl.GetMinApprovals() {
+		return nil, errSignNotApproved
+	}
+	delete(signApprovals, key)
+	return approved, nil
+}
+
+// getValidApprovals get the persisted approvals of distinct allowed approvers
+func (c *Config) getValidApprovals(approvals []*mongodb.MgoSignApproval) (valid []*mongodb.MgoSignApproval, approved []string) {
+	valid = make([]*mongodb.MgoSignApproval, 0, len(approvals))
+	exist := make(map[string]struct{}, len(approvals))
+	for _, approval := range approvals {
+		approver := strings.ToLower(approval.Approver)
+		if _, dup := exist[approver]; dup || !c.dualControl.IsApprover(approver) {
+			continue
+		}
+		exist[approver] = struct{}{}
+		valid = append(valid, approval)
+		approved = append(approved, approval.Approver)
+	}
+	return valid, approved
+}
+
+// verifySignRequest verify every msg context is of a stored swap or an internal tx,
+// by rebuilding the tx and comparing the msg hashes.
+// the msg contexts of a sign request are the same build args (eg. btc inputs).
+func verifySignRequest(msgHash, msgContext []string) error {
+	if len(msgContext) == 0 {
+		return errSignNotApproved
+	}
+	verifier := signRequestVerifier
+	if verifier == nil || !mongodb.HasClient() {
+		return errNoSignRequestVerifier
+	}
+	for _, context := range msgContext[1:] {
+		if context != msgContext[0] {
+			return errWrongMsgContext
+		}
+	}
+	var args tokens.BuildTxArgs
+	if err := json.Unmarshal([]byte(msgContext[0]), &args); err != nil {
+		return errWrongMsgContext
+	}
+	return verifier(msgHash, &args)
+}
@@ -0,0 +1,198 @@
+package mpc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+)
+
+const testSignPubkey = "0x04aabbcc"
+
+type testApprover struct {
+	address string
+	sign    func(hash []byte) []byte
+}
+
+func newTestApprover(t *testing.T) *testApprover {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testApprover{
+		address: crypto.PubkeyToAddress(key.PublicKey).LowerHex(),
+		sign: func(hash []byte) []byte {
+			sig, err := crypto.Sign(hash, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return sig
+		},
+	}
+}
+
+func (a *testApprover) approve(msgHash []string, expiry int64) *SignApproval {
+	approval := &SignApproval{SignPubkey: testSignPubkey, MsgHash: msgHash, Expiry: expiry}
+	approval.Signature = common.ToHex(a.sign(approval.Hash().Bytes()))
+	return approval
+}
+
+func newDualControlConfig(minApprovals int, recoveryMode bool, approvers ...*testApprover) *Config {
+	cfg := &params.DualControlConfig{MinApprovals: minApprovals, RecoveryMode: recoveryMode}
+	for _, approver := range approvers {
+		cfg.Approvers = append(cfg.Approvers, approver.address)
+	}
+	return &Config{dualControl: cfg}
+}
+
+func TestSignApprovalApprover(t *testing.T) {
+	admin := newTestApprover(t)
+	approval := admin.approve([]string{"0x01"}, time.Now().Unix()+60)
+	approver, err := approval.Approver()
+	if err != nil || approver.LowerHex() != admin.address {
+		t.Fatalf("recover approver got (%v, %v), want %v", approver.LowerHex(), err, admin.address)
+	}
+	// the approval can not be moved to another sign request
+	approval.MsgHash = []string{"0x02"}
+	if approver, _ = approval.Approver(); approver.LowerHex() == admin.address {
+		t.Errorf("approval of changed msg hash should not recover the approver")
+	}
+}
+
+func TestAddSignApproval(t *testing.T) {
+	admin, other := newTestApprover(t), newTestApprover(t)
+	c := newDualControlConfig(2, false, admin)
+	now := time.Now().Unix()
+	msgHash := []string{"0x10"}
+
+	if _, err := (&Config{}).AddSignApproval(admin.approve(msgHash, now+60)); !errors.Is(err, errDualControlNotEnabled) {
+		t.Errorf("approval without dual control got err %v, want %v", err, errDualControlNotEnabled)
+	}
+	tests := []struct {
+		approval *SignApproval
+		wantErr  error
+	}{
+		{admin.approve(msgHash, now-1), errApprovalExpired},
+		{admin.approve(msgHash, now+maxApprovalLifetime+60), errApprovalTooLong},
+		{other.approve(msgHash, now+60), errApproverNotAllowed},
+		{&SignApproval{SignPubkey: testSignPubkey, MsgHash: msgHash, Expiry: now + 60, Signature: "0x1234"}, errWrongSignatureLength},
+	}
+	for i, test := range tests {
+		if _, err := c.AddSignApproval(test.approval); !errors.Is(err, test.wantErr) {
+			t.Errorf("test %v: add sign approval got err %v, want %v", i, err, test.wantErr)
+		}
+	}
+	defer delete(signApprovals, getSignApprovalKey(testSignPubkey, msgHash))
+	if approver, err := c.AddSignApproval(admin.approve(msgHash, now+60)); err != nil || approver != admin.address {
+		t.Errorf("add sign approval got (%v, %v), want %v", approver, err, admin.address)
+	}
+}
+
+func TestCheckDualControlApprovals(t *testing.T) {
+	admin1, admin2, admin3 := newTestApprover(t), newTestApprover(t), newTestApprover(t)
+	now := time.Now().Unix()
+
+	tests := []struct {
+		minApprovals int
+		approvals    []*testApprover
+		wantErr      error
+	}{
+		// one admin can not approve twice
+		{2, []*testApprover{admin1, admin1}, errSignNotApproved},
+		// two distinct admins reach the default threshold
+		{0, []*testApprover{admin1, admin2}, nil},
+		// two distinct admins are below the configured threshold
+		{3, []*testApprover{admin1, admin2}, errSignNotApproved},
+		{3, []*testApprover{admin1, admin2, admin3}, nil},
+	}
+	for i, test := range tests {
+		c := newDualControlConfig(test.minApprovals, false, admin1, admin2, admin3)
+		msgHash := []string{common.ToHex([]byte{byte(i + 1)})}
+		for _, approver := range test.approvals {
+			if _, err := c.AddSignApproval(approver.approve(msgHash, now+60)); err != nil {
+				t.Fatalf("test %v: add sign approval failed: %v", i, err)
+			}
+		}
+		// approvals are kept until consumed, eg. after the mpc config is reloaded
+		c = newDualControlConfig(test.minApprovals, false, admin1, admin2, admin3)
+		err := c.checkDualControl(testSignPubkey, msgHash, nil)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("test %v: check dual control got err %v, want %v", i, err, test.wantErr)
+		}
+		if test.wantErr == nil {
+			// approvals are consumed by the allowed sign request
+			if err = c.checkDualControl(testSignPubkey, msgHash, nil); !errors.Is(err, errSignNotApproved) {
+				t.Errorf("test %v: approvals should be used only once, got err %v", i, err)
+			}
+		}
+		delete(signApprovals, getSignApprovalKey(testSignPubkey, msgHash))
+	}
+}
+
+func TestCheckDualControlExpiredApproval(t *testing.T) {
+	admin1, admin2 := newTestApprover(t), newTestApprover(t)
+	c := newDualControlConfig(2, false, admin1, admin2)
+	msgHash := []string{"0x20"}
+	key := getSignApprovalKey(testSignPubkey, msgHash)
+	defer delete(signApprovals, key)
+
+	signApprovals[key] = map[string]int64{
+		admin1.address: time.Now().Unix() + 60,
+		admin2.address: time.Now().Unix() - 1,
+	}
+	if err := c.checkDualControl(testSignPubkey, msgHash, nil); !errors.Is(err, errSignNotApproved) {
+		t.Errorf("check dual control with expired approval got err %v, want %v", err, errSignNotApproved)
+	}
+}
+
+func TestGetValidApprovals(t *testing.T) {
+	admin1, admin2, other := newTestApprover(t), newTestApprover(t), newTestApprover(t)
+	c := newDualControlConfig(2, false, admin1, admin2)
+	approvals := []*mongodb.MgoSignApproval{
+		{Approver: admin1.address},
+		{Approver: other.address},
+		{Approver: admin2.address},
+		{Approver: admin1.address},
+	}
+	valid, approved := c.getValidApprovals(approvals)
+	if len(valid) != 2 || len(approved) != 2 || approved[0] != admin1.address || approved[1] != admin2.address {
+		t.Errorf("valid approvals got %v, want [%v %v]", approved, admin1.address, admin2.address)
+	}
+}
+
+func TestCheckDualControlBypass(t *testing.T) {
+	admin1, admin2 := newTestApprover(t), newTestApprover(t)
+	canaryHash := common.ToHex(crypto.Keccak256([]byte(CanaryMessage)))
+	tests := []struct {
+		recoveryMode bool
+		msgHash      []string
+		msgContext   []string
+		wantErr      error
+	}{
+		// canary signing is allowed without approvals
+		{false, []string{canaryHash}, []string{CanaryMsgContext}, nil},
+		// canary context of other msg hash is not canary
+		{false, []string{"0x30"}, []string{CanaryMsgContext}, errSignNotApproved},
+		// recovery mode bypasses approvals
+		{true, []string{"0x30"}, nil, nil},
+		{true, []string{"0x30"}, []string{"{}"}, nil},
+		// not in recovery mode, unverified requests need approvals
+		{false, []string{"0x30"}, nil, errSignNotApproved},
+		{false, []string{"0x30"}, []string{"{}"}, errSignNotApproved},
+	}
+	for i, test := range tests {
+		c := newDualControlConfig(2, test.recoveryMode, admin1, admin2)
+		err := c.checkDualControl(testSignPubkey, test.msgHash, test.msgContext)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("test %v: check dual control got err %v, want %v", i, err, test.wantErr)
+		}
+	}
+
+	if err := (&Config{}).checkDualControl(testSignPubkey, []string{"0x30"}, nil); err != nil {
+		t.Errorf("check without dual control got err %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package mpc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+// canary signing of a well-known test message
+const (
+	CanaryMessage    = "CrossChain-Router mpc canary"
+	CanaryMsgContext = "mpc canary"
+)
+
+// ErrCanaryNotPassed sign request before canary signing passes
+var ErrCanaryNotPassed = errors.New("mpc canary signing is not passed")
+
+type canaryState struct {
+	passedFingerprint string
+	round             uint64 // increased when canary signing is reset
+	lock              sync.RWMutex
+}
+
+// IsCanaryEnabled is canary signing enabled
+func (c *Config) IsCanaryEnabled() bool {
+	return c.canary != nil
+}
+
+// GetConfigFingerprint get fingerprint of the mpc settings of signing
+func (c *Config) GetConfigFingerprint() string {
+	parts := []string{c.mpcGroupID, c.mpcThreshold, c.mpcMode, c.signTypeEC256K1}
+	for _, node := range c.allInitiatorNodes {
+		parts = append(parts, node.mpcUser.LowerHex(), node.mpcRPCAddress, strings.Join(node.originSignGroups, ","))
+	}
+	return common.Keccak256Hash([]byte(strings.Join(parts, ";"))).Hex()
+}
+
+// IsCanaryPassed is canary signing passed with the current mpc settings
+func (c *Config) IsCanaryPassed() bool {
+	if c.canary == nil {
+		return true
+	}
+	c.canary.lock.RLock()
+	defer c.canary.lock.RUnlock()
+	return c.canary.passedFingerprint == c.GetConfigFingerprint()
+}
+
+// GetCanaryRound get the round of canary signing
+func (c *Config) GetCanaryRound() uint64 {
+	if c.canary == nil {
+		return 0
+	}
+	c.canary.lock.RLock()
+	defer c.canary.lock.RUnlock()
+	return c.canary.round
+}
+
+// SetCanaryPassed set canary signing of round passed with the mpc settings of fingerprint,
+// it is ignored if canary signing is reset after the round begins.
+func (c *Config) SetCanaryPassed(fingerprint string, round uint64) {
+	if c.canary == nil {
+		return
+	}
+	c.canary.lock.Lock()
+	defer c.canary.lock.Unlock()
+	if round != c.canary.round {
+		log.Info("ignore mpc canary signing of old round", "isFastMPC", c.IsFastMPC, "round", round, "current", c.canary.round)
+		return
+	}
+	c.canary.passedFingerprint = fingerprint
+	log.Info("mpc canary signing passed", "isFastMPC", c.IsFastMPC, "fingerprint", fingerprint, "round", round)
+}
+
+// ResetCanary reset canary signing (eg. the router config is reloaded),
+// production sign requests are refused until canary signing passes again.
+func (c *Config) ResetCanary() {
+	if c.canary == nil {
+		return
+	}
+	c.canary.lock.Lock()
+	defer c.canary.lock.Unlock()
+	c.canary.passedFingerprint = ""
+	c.canary.round++
+	log.Info("mpc canary signing is reset", "isFastMPC", c.IsFastMPC, "round", c.canary.round)
+}
+
+// checkCanary only canary sign requests are allowed before canary signing passes
+func (c *Config) checkCanary(msgHash, msgContext []string) error {
+	if c.IsCanaryPassed() || isCanaryRequest(msgHash, msgContext) {
+		return nil
+	}
+	return fmt.Errorf("%w with mpc settings %v", ErrCanaryNotPassed, c.GetConfigFingerprint())
+}
+
+// isCanaryRequest is every msg hash the canary message digest of a sign scheme
+func isCanaryRequest(msgHash, msgContext []string) bool {
+	if len(msgHash) == 0 || len(msgContext) != len(msgHash) {
+		return false
+	}
+	digests := getCanaryDigests()
+	for i, hash := range msgHash {
+		if msgContext[i] != CanaryMsgContext {
+			return false
+		}
+		if _, exist := digests[strings.ToLower(hash)]; !exist {
+			return false
+		}
+	}
+	return true
+}
+
+func getCanaryDigests() map[string]struct{} {
+	signSchemesLock.RLock()
+	defer signSchemesLock.RUnlock()
+	digests := make(map[string]struct{}, len(signSchemes))
+	for _, scheme := range signSchemes {
+		digests[strings.ToLower(common.ToHex(scheme.Hash([]byte(CanaryMessage))))] = struct{}{}
+	}
+	return digests
+}
@@ -0,0 +1,41 @@
+package mpc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResetCanary(t *testing.T) {
+	c := &Config{canary: &canaryState{}}
+	if c.IsCanaryPassed() {
+		t.Fatalf("canary signing should not pass before signing")
+	}
+
+	round := c.GetCanaryRound()
+	c.SetCanaryPassed(c.GetConfigFingerprint(), round)
+	if !c.IsCanaryPassed() {
+		t.Fatalf("canary signing should pass after signing")
+	}
+	if err := c.checkCanary([]string{"0x1234"}, []string{"swap"}); err != nil {
+		t.Errorf("sign request after canary passed got error %v", err)
+	}
+
+	// reloading router config resets canary signing
+	c.ResetCanary()
+	if c.IsCanaryPassed() {
+		t.Fatalf("canary signing should not pass after reset")
+	}
+	if err := c.checkCanary([]string{"0x1234"}, []string{"swap"}); !errors.Is(err, ErrCanaryNotPassed) {
+		t.Errorf("sign request after canary reset got error %v", err)
+	}
+
+	// signing of the round begun before reset does not pass
+	c.SetCanaryPassed(c.GetConfigFingerprint(), round)
+	if c.IsCanaryPassed() {
+		t.Errorf("canary signing of old round should be ignored")
+	}
+	c.SetCanaryPassed(c.GetConfigFingerprint(), c.GetCanaryRound())
+	if !c.IsCanaryPassed() {
+		t.Errorf("canary signing of current round should pass")
+	}
+}
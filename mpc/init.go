@@ -21,6 +21,7 @@ const (
 	mpcWalletServiceID = 30400
 
 	signTypeED25519 = "ED25519"
+	signTypeSR25519 = "SR25519"
 )
 
 var (
@@ -66,6 +67,15 @@ type Config struct {
 	maxSignGroupFailures      int
 	minIntervalToAddSignGroup int64                   // seconds
 	signGroupFailuresMap      map[string]signFailures // key is groupID
+
+	// limit outstanding sign requests, nil means no limit
+	signLimiter *signLimiter
+
+	// dual control of sign requests outside swap flow, nil means disabled
+	dualControl *params.DualControlConfig
+
+	// canary signing of mpc config changes, nil means disabled
+	canary *canaryState
 }
 
 type signFailures struct {
@@ -130,6 +140,12 @@ func Init(isServer bool) {
 func InitConfig(mpcParams *params.MPCConfig, isServer bool) *Config {
 	c := newConfig()
 
+	// the watcher never loads the mpc node keystore
+	if params.IsWatcherMode {
+		log.Info("ignore mpc init in watcher mode")
+		return c
+	}
+
 	if mpcParams.SignWithPrivateKey {
 		log.Info("ignore mpc init as sign with private key")
 		return c
@@ -168,6 +184,16 @@ func InitConfig(mpcParams *params.MPCConfig, isServer bool) *Config {
 
 	c.verifySignatureInAccept = mpcParams.VerifySignatureInAccept
 
+	if mpcParams.MaxConcurrentSigns > 0 {
+		c.signLimiter = newSignLimiter(mpcParams.MaxConcurrentSigns)
+	}
+
+	c.dualControl = mpcParams.DualControl
+
+	if mpcParams.Canary != nil && isServer {
+		c.canary = &canaryState{}
+	}
+
 	c.setMPCGroup(*mpcParams.GroupID, mpcParams.Mode, *mpcParams.NeededOracles, *mpcParams.TotalOracles)
 	c.setDefaultMPCNodeInfo(c.initMPCNodeInfo(mpcParams.DefaultNode, isServer))
 
@@ -186,6 +212,9 @@ func InitConfig(mpcParams *params.MPCConfig, isServer bool) *Config {
 		"rpcTimeout", c.mpcRPCTimeout, "signTimeout", c.mpcSignTimeout.String(),
 		"maxSignGroupFailures", c.maxSignGroupFailures,
 		"minIntervalToAddSignGroup", c.minIntervalToAddSignGroup,
+		"maxConcurrentSigns", mpcParams.MaxConcurrentSigns,
+		"dualControl", c.dualControl != nil,
+		"canary", c.canary != nil,
 	)
 
 	return c
@@ -0,0 +1,136 @@
+package mpc
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+const defaultSignQueueKey = "default"
+
+// signLimiter limits the count of outstanding sign requests,
+// the waiting requests are queued per destination chain and
+// woken up in round robin order to share the capacity fairly.
+type signLimiter struct {
+	maxConcurrent int
+	running       int
+
+	queues   map[string][]chan struct{} // key is destination chainID
+	keys     []string                   // round robin order of queue keys
+	nextTurn int
+
+	lock sync.Mutex
+}
+
+// SignQueueStats sign queue statistics
+type SignQueueStats struct {
+	MaxConcurrent int            `json:"maxConcurrent"`
+	Running       int            `json:"running"`
+	Waiting       map[string]int `json:"waiting"`
+}
+
+func newSignLimiter(maxConcurrent int) *signLimiter {
+	return &signLimiter{
+		maxConcurrent: maxConcurrent,
+		queues:        make(map[string][]chan struct{}),
+	}
+}
+
+func (l *signLimiter) hasWaiters() bool {
+	for _, queue := range l.queues {
+		if len(queue) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// acquire blocks until a sign slot is available
+func (l *signLimiter) acquire(key string) {
+	l.lock.Lock()
+	if l.running < l.maxConcurrent && !l.hasWaiters() {
+		l.running++
+		l.lock.Unlock()
+		return
+	}
+	ch := make(chan struct{})
+	if _, exist := l.queues[key]; !exist {
+		l.keys = append(l.keys, key)
+	}
+	l.queues[key] = append(l.queues[key], ch)
+	waiting := len(l.queues[key])
+	l.lock.Unlock()
+
+	log.Info("mpc sign request is queued", "queue", key, "waiting", waiting, "maxConcurrent", l.maxConcurrent)
+	<-ch
+}
+
+// release hands over the slot to the next queue in round robin order
+func (l *signLimiter) release() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for i := 0; i < len(l.keys); i++ {
+		idx := (l.nextTurn + i) % len(l.keys)
+		key := l.keys[idx]
+		queue := l.queues[key]
+		if len(queue) == 0 {
+			continue
+		}
+		l.queues[key] = queue[1:]
+		l.nextTurn = (idx + 1) % len(l.keys)
+		close(queue[0]) // transfer the slot, keep running count
+		return
+	}
+	l.running--
+}
+
+func (l *signLimiter) stats() *SignQueueStats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	waiting := make(map[string]int, len(l.queues))
+	for key, queue := range l.queues {
+		if len(queue) > 0 {
+			waiting[key] = len(queue)
+		}
+	}
+	return &SignQueueStats{
+		MaxConcurrent: l.maxConcurrent,
+		Running:       l.running,
+		Waiting:       waiting,
+	}
+}
+
+// getSignQueueKey get destination chainID from sign message context
+func getSignQueueKey(msgContext []string) string {
+	if len(msgContext) == 0 || msgContext[0] == "" {
+		return defaultSignQueueKey
+	}
+	var args struct {
+		SwapArgs struct {
+			ToChainID *big.Int `json:"toChainID"`
+		} `json:"swapArgs"`
+	}
+	if err := json.Unmarshal([]byte(msgContext[0]), &args); err != nil || args.SwapArgs.ToChainID == nil {
+		return defaultSignQueueKey
+	}
+	return args.SwapArgs.ToChainID.String()
+}
+
+// acquireSignSlot returns the release function of the acquired slot
+func (c *Config) acquireSignSlot(msgContext []string) (release func()) {
+	if c.signLimiter == nil {
+		return func() {}
+	}
+	c.signLimiter.acquire(getSignQueueKey(msgContext))
+	return c.signLimiter.release
+}
+
+// GetSignQueueStats get sign queue statistics (nil if not limited)
+func (c *Config) GetSignQueueStats() *SignQueueStats {
+	if c.signLimiter == nil {
+		return nil
+	}
+	return c.signLimiter.stats()
+}
@@ -0,0 +1,81 @@
+package mpc
+
+import (
+	"testing"
+	"time"
+)
+
+func waitSignQueued(t *testing.T, l *signLimiter, waiting int) {
+	for i := 0; i < 100; i++ {
+		count := 0
+		for _, n := range l.stats().Waiting {
+			count += n
+		}
+		if count == waiting {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("wait %v queued sign requests timeout, stats %+v", waiting, l.stats())
+}
+
+func TestSignLimiterRoundRobin(t *testing.T) {
+	l := newSignLimiter(1)
+	l.acquire("1")
+	if stats := l.stats(); stats.Running != 1 || len(stats.Waiting) != 0 {
+		t.Fatalf("stats after acquire got %+v", stats)
+	}
+
+	acquired := make(chan string, 3)
+	// queue one by one to fix the order in the queues
+	for i, key := range []string{"1", "1", "56"} {
+		go func(key string) {
+			l.acquire(key)
+			acquired <- key
+		}(key)
+		waitSignQueued(t, l, i+1)
+	}
+	if stats := l.stats(); stats.Waiting["1"] != 2 || stats.Waiting["56"] != 1 {
+		t.Fatalf("waiting got %v, want 2 of chain 1 and 1 of chain 56", stats.Waiting)
+	}
+
+	// the slot is handed over in round robin order of chains
+	var order []string
+	for i := 0; i < 3; i++ {
+		l.release()
+		select {
+		case key := <-acquired:
+			order = append(order, key)
+		case <-time.After(time.Second):
+			t.Fatalf("wait handed over sign slot timeout")
+		}
+	}
+	if order[0] != "1" || order[1] != "56" || order[2] != "1" {
+		t.Errorf("acquired order got %v, want [1 56 1]", order)
+	}
+	if stats := l.stats(); stats.Running != 1 {
+		t.Errorf("running got %v after handing over, want 1", stats.Running)
+	}
+	l.release()
+	if stats := l.stats(); stats.Running != 0 {
+		t.Errorf("running got %v after all released, want 0", stats.Running)
+	}
+}
+
+func TestGetSignQueueKey(t *testing.T) {
+	tests := []struct {
+		msgContext []string
+		want       string
+	}{
+		{nil, defaultSignQueueKey},
+		{[]string{""}, defaultSignQueueKey},
+		{[]string{"not json"}, defaultSignQueueKey},
+		{[]string{`{"swapArgs":{"fromChainID":1}}`}, defaultSignQueueKey},
+		{[]string{`{"swapArgs":{"fromChainID":1,"toChainID":56}}`}, "56"},
+	}
+	for _, test := range tests {
+		if got := getSignQueueKey(test.msgContext); got != test.want {
+			t.Errorf("get sign queue key of %v got %v, want %v", test.msgContext, got, test.want)
+		}
+	}
+}
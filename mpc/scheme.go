@@ -0,0 +1,238 @@
+package mpc
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ChainSafe/go-schnorrkel"
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+)
+
+// built-in signature schemes
+const (
+	SchemeSecp256k1Keccak = "secp256k1+keccak"
+	SchemeSecp256k1Sha256 = "secp256k1+sha256"
+	SchemeEd25519         = "ed25519"
+	SchemeSr25519         = "sr25519"
+)
+
+var (
+	signSchemes        = make(map[string]SignScheme) // key is scheme name
+	chainFamilySchemes = make(map[string]string)     // key is chain family, value is scheme name
+	signSchemesLock    sync.RWMutex
+
+	errSignSchemeNotFound = errors.New("sign scheme not found")
+	errSignatureMismatch  = errors.New("signature does not match public key")
+)
+
+// SignScheme signature scheme plugin of chain family
+type SignScheme interface {
+	// KeyType mpc key type of sign request, eg. EC256K1, ED25519, SR25519
+	KeyType() string
+	// Hash hash message to the digest which is signed by mpc
+	Hash(message []byte) []byte
+	// VerifySignature verify signature of digest returned by mpc
+	VerifySignature(pubkey, digest, signature []byte) error
+}
+
+// sr25519SigningContext signing context of substrate chains
+var sr25519SigningContext = []byte("substrate")
+
+func init() {
+	RegisterSignScheme(SchemeSecp256k1Keccak, NewSecp256k1Scheme(crypto.Keccak256))
+	RegisterSignScheme(SchemeSecp256k1Sha256, NewSecp256k1Scheme(sha256Hash))
+	RegisterSignScheme(SchemeEd25519, &ed25519Scheme{})
+	RegisterSignScheme(SchemeSr25519, &sr25519Scheme{})
+}
+
+// RegisterSignScheme register signature scheme plugin
+func RegisterSignScheme(name string, scheme SignScheme) {
+	signSchemesLock.Lock()
+	defer signSchemesLock.Unlock()
+	signSchemes[name] = scheme
+}
+
+// RegisterChainFamily register the signature scheme of chain family
+func RegisterChainFamily(family, schemeName string) {
+	signSchemesLock.Lock()
+	defer signSchemesLock.Unlock()
+	if _, exist := signSchemes[schemeName]; !exist {
+		log.Fatal("register chain family with unknown sign scheme", "family", family, "scheme", schemeName)
+	}
+	chainFamilySchemes[family] = schemeName
+}
+
+// GetSignScheme get the signature scheme of chain family
+func GetSignScheme(family string) (SignScheme, error) {
+	signSchemesLock.RLock()
+	defer signSchemesLock.RUnlock()
+	if scheme, exist := signSchemes[chainFamilySchemes[family]]; exist {
+		return scheme, nil
+	}
+	return nil, fmt.Errorf("%w for chain family '%v'", errSignSchemeNotFound, family)
+}
+
+// DoSignMessageWithScheme mpc sign the digest of message hashed by the signature scheme of chain family
+func (c *Config) DoSignMessageWithScheme(family, signPubkey string, message []byte, msgContext string) (keyID string, digest, signature []byte, err error) {
+	scheme, err := GetSignScheme(family)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	digest = scheme.Hash(message)
+	keyID, signature, err = c.doSignWithScheme(scheme, signPubkey, common.ToHex(digest), msgContext)
+	return keyID, digest, signature, err
+}
+
+// DoSignWithScheme mpc sign digest with the signature scheme of chain family,
+// and verify the returned signature with signPubkey.
+func (c *Config) DoSignWithScheme(family, signPubkey string, digest []byte, msgContext string) (keyID string, signature []byte, err error) {
+	return c.DoSignHashWithScheme(family, signPubkey, common.ToHex(digest), msgContext)
+}
+
+// DoSignHashWithScheme mpc sign the hex encoded digest with the signature scheme of chain family,
+// and verify the returned signature with signPubkey. msgHash is sent to mpc as is,
+// as its format is checked by the oracles when they accept the sign request.
+func (c *Config) DoSignHashWithScheme(family, signPubkey, msgHash, msgContext string) (keyID string, signature []byte, err error) {
+	scheme, err := GetSignScheme(family)
+	if err != nil {
+		return "", nil, err
+	}
+	return c.doSignWithScheme(scheme, signPubkey, msgHash, msgContext)
+}
+
+func (c *Config) doSignWithScheme(scheme SignScheme, signPubkey, msgHash, msgContext string) (keyID string, signature []byte, err error) {
+	keyType := scheme.KeyType()
+	if isEC(keyType) {
+		keyType = c.signTypeEC256K1
+	}
+	digest := common.FromHex(msgHash)
+	keyID, rsvs, err := c.DoSignOne(keyType, signPubkey, msgHash, msgContext)
+	if err != nil {
+		return keyID, nil, err
+	}
+	if len(rsvs) != 1 {
+		log.Warn("get sign status require one rsv but return many", "rsvs", len(rsvs), "keyID", keyID)
+		return keyID, nil, errors.New("get sign status require one rsv but return many")
+	}
+	signature = common.FromHex(rsvs[0])
+	if err = scheme.VerifySignature(common.FromHex(signPubkey), digest, signature); err != nil {
+		log.Error("verify mpc signature failed", "keyID", keyID, "pubkey", signPubkey, "msgHash", msgHash, "signature", rsvs[0], "err", err)
+		return keyID, nil, err
+	}
+	return keyID, signature, nil
+}
+
+func sha256Hash(data ...[]byte) []byte {
+	hasher := sha256.New()
+	for _, b := range data {
+		_, _ = hasher.Write(b)
+	}
+	return hasher.Sum(nil)
+}
+
+func checkSignatureLength(signature []byte, want int) error {
+	if len(signature) != want {
+		return fmt.Errorf("%w: have %v, want %v", errWrongSignatureLength, len(signature), want)
+	}
+	return nil
+}
+
+// secp256k1Scheme signature is 65 bytes [R || S || V],
+// V may be wrong as mpc does not know the recovery id.
+type secp256k1Scheme struct {
+	hash func(data ...[]byte) []byte
+}
+
+// NewSecp256k1Scheme new secp256k1 signature scheme with the hash function of chain family
+func NewSecp256k1Scheme(hash func(data ...[]byte) []byte) SignScheme {
+	return &secp256k1Scheme{hash: hash}
+}
+
+func (s *secp256k1Scheme) KeyType() string { return "EC256K1" }
+
+func (s *secp256k1Scheme) Hash(message []byte) []byte { return s.hash(message) }
+
+func (s *secp256k1Scheme) VerifySignature(pubkey, digest, signature []byte) error {
+	if err := checkSignatureLength(signature, crypto.SignatureLength); err != nil {
+		return err
+	}
+	switch len(pubkey) {
+	case 33:
+		pub, err := crypto.DecompressPubkey(pubkey)
+		if err != nil {
+			return err
+		}
+		pubkey = crypto.FromECDSAPub(pub)
+	case 64:
+		pubkey = append([]byte{4}, pubkey...)
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, signature)
+	for v := byte(0); v < 2; v++ {
+		sig[crypto.RecoveryIDOffset] = v
+		recovered, err := crypto.Ecrecover(digest, sig)
+		if err == nil && bytes.Equal(recovered, pubkey) {
+			return nil
+		}
+	}
+	return errSignatureMismatch
+}
+
+// ed25519Scheme ed25519 hashes the message itself
+type ed25519Scheme struct{}
+
+func (s *ed25519Scheme) KeyType() string { return signTypeED25519 }
+
+func (s *ed25519Scheme) Hash(message []byte) []byte { return message }
+
+func (s *ed25519Scheme) VerifySignature(pubkey, digest, signature []byte) error {
+	if err := checkSignatureLength(signature, ed25519.SignatureSize); err != nil {
+		return err
+	}
+	if len(pubkey) != ed25519.PublicKeySize {
+		return fmt.Errorf("wrong ed25519 public key length %v", len(pubkey))
+	}
+	if !ed25519.Verify(pubkey, digest, signature) {
+		return errSignatureMismatch
+	}
+	return nil
+}
+
+// sr25519Scheme schnorrkel signature in the substrate signing context
+type sr25519Scheme struct{}
+
+func (s *sr25519Scheme) KeyType() string { return signTypeSR25519 }
+
+func (s *sr25519Scheme) Hash(message []byte) []byte { return message }
+
+func (s *sr25519Scheme) VerifySignature(pubkey, digest, signature []byte) error {
+	var sigBytes [64]byte
+	var pubBytes [32]byte
+	if err := checkSignatureLength(signature, len(sigBytes)); err != nil {
+		return err
+	}
+	if len(pubkey) != len(pubBytes) {
+		return fmt.Errorf("wrong sr25519 public key length %v", len(pubkey))
+	}
+	copy(sigBytes[:], signature)
+	copy(pubBytes[:], pubkey)
+
+	pub := &schnorrkel.PublicKey{}
+	if err := pub.Decode(pubBytes); err != nil {
+		return err
+	}
+	sig := &schnorrkel.Signature{}
+	if err := sig.Decode(sigBytes); err != nil {
+		return err
+	}
+	if !pub.Verify(sig, schnorrkel.NewSigningContext(sr25519SigningContext, digest)) {
+		return errSignatureMismatch
+	}
+	return nil
+}
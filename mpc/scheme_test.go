@@ -0,0 +1,96 @@
+package mpc
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+)
+
+func TestSecp256k1SchemeVerifySignature(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	scheme := NewSecp256k1Scheme(crypto.Keccak256)
+	digest := scheme.Hash([]byte("test message"))
+	signature, err := crypto.Sign(digest, key)
+	if err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	// mpc does not know the recovery id
+	signature[crypto.RecoveryIDOffset] = 27
+
+	pubkeys := [][]byte{
+		crypto.CompressPubkey(&key.PublicKey),
+		crypto.FromECDSAPub(&key.PublicKey),
+		crypto.FromECDSAPub(&key.PublicKey)[1:],
+	}
+	for _, pubkey := range pubkeys {
+		if err = scheme.VerifySignature(pubkey, digest, signature); err != nil {
+			t.Errorf("verify signature with %v bytes public key failed: %v", len(pubkey), err)
+		}
+	}
+
+	other, _ := crypto.GenerateKey()
+	err = scheme.VerifySignature(crypto.FromECDSAPub(&other.PublicKey), digest, signature)
+	if !errors.Is(err, errSignatureMismatch) {
+		t.Errorf("verify signature with other public key got error %v", err)
+	}
+	err = scheme.VerifySignature(pubkeys[0], digest, signature[:64])
+	if !errors.Is(err, errWrongSignatureLength) {
+		t.Errorf("verify short signature got error %v", err)
+	}
+}
+
+func TestEd25519SchemeVerifySignature(t *testing.T) {
+	pubkey, prikey, _ := ed25519.GenerateKey(rand.Reader)
+	scheme := &ed25519Scheme{}
+	message := []byte("test message")
+	signature := ed25519.Sign(prikey, scheme.Hash(message))
+
+	if err := scheme.VerifySignature(pubkey, message, signature); err != nil {
+		t.Errorf("verify signature failed: %v", err)
+	}
+	signature[0] ^= 0xff
+	if err := scheme.VerifySignature(pubkey, message, signature); !errors.Is(err, errSignatureMismatch) {
+		t.Errorf("verify tampered signature got error %v", err)
+	}
+}
+
+func TestSr25519SchemeVerifySignature(t *testing.T) {
+	// test vector of the substrate signing context (see go-schnorrkel)
+	pubkey := common.FromHex("46ebddef8cd9bb167dc30878d7113b7e168e6f0646beffd77d69d39bad76b47a")
+	signature := common.FromHex("4e172314444b8f820bb54c22e95076f220ed25373e5c178234aa6c211d29271244b947e3ff3418ff6b45fd1df1140c8cbff69fc58ee6dc96df70936a2bb74b82")
+	message := []byte("this is a message")
+	scheme := &sr25519Scheme{}
+
+	if err := scheme.VerifySignature(pubkey, message, signature); err != nil {
+		t.Errorf("verify signature failed: %v", err)
+	}
+	if err := scheme.VerifySignature(pubkey, []byte("another message"), signature); !errors.Is(err, errSignatureMismatch) {
+		t.Errorf("verify signature of another message got error %v", err)
+	}
+	tampered := common.CopyBytes(signature)
+	tampered[0] ^= 0x01
+	if err := scheme.VerifySignature(pubkey, message, tampered); err == nil {
+		t.Errorf("verify tampered signature should fail")
+	}
+	if err := scheme.VerifySignature(pubkey[:31], message, signature); err == nil {
+		t.Errorf("verify signature with short public key should fail")
+	}
+}
+
+func TestGetSignScheme(t *testing.T) {
+	RegisterChainFamily("test-sr25519", SchemeSr25519)
+	scheme, err := GetSignScheme("test-sr25519")
+	if err != nil {
+		t.Fatalf("get sign scheme failed: %v", err)
+	}
+	if scheme.KeyType() != signTypeSR25519 {
+		t.Errorf("get sign scheme got key type %v, want %v", scheme.KeyType(), signTypeSR25519)
+	}
+	if _, err = GetSignScheme("test-unknown"); !errors.Is(err, errSignSchemeNotFound) {
+		t.Errorf("get sign scheme of unknown chain family got error %v", err)
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"errors"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
 	"github.com/anyswap/CrossChain-Router/v3/tools/keystore"
 	"github.com/anyswap/CrossChain-Router/v3/tools/rlp"
@@ -30,6 +33,11 @@ var (
 	errWrongSignatureLength = errors.New("wrong signature length")
 	errNoUsableSignGroups   = errors.New("no usable sign groups")
 	errEmptyKeyID           = errors.New("empty keyID")
+	errNoNodeKeystore       = errors.New("no mpc node keystore")
+	errNoPendingSignRequest = errors.New("no pending sign request")
+
+	// ErrNonceSignedWithOtherMsgs the swap nonce is signed with other msgs by a pending sign request
+	ErrNonceSignedWithOtherMsgs = errors.New("swap nonce is signed with other messages")
 )
 
 func (c *Config) pingMPCNode(nodeInfo *NodeInfo) (err error) {
@@ -63,9 +71,27 @@ func (c *Config) DoSignOne(signType, signPubkey, msgHash, msgContext string) (ke
 // DoSign mpc sign msgHash with context msgContext
 func (c *Config) DoSign(signType, signPubkey string, msgHash, msgContext []string) (keyID string, rsvs []string, err error) {
 	log.Debug("mpc DoSign", "msgHash", msgHash, "msgContext", msgContext, "signType", signType)
+	if params.IsWatcherMode {
+		return "", nil, params.ErrWatcherMode
+	}
 	if signPubkey == "" {
 		return "", nil, errSignWithoutPublickey
 	}
+	if err = c.checkCanary(msgHash, msgContext); err != nil {
+		return "", nil, err
+	}
+	if err = c.checkDualControl(signPubkey, msgHash, msgContext); err != nil {
+		return "", nil, err
+	}
+	release := c.acquireSignSlot(msgContext)
+	defer release()
+	keyID, rsvs, err = c.resumeSign(signType, signPubkey, msgHash, msgContext)
+	switch {
+	case err == nil:
+		return keyID, rsvs, nil
+	case errors.Is(err, ErrNonceSignedWithOtherMsgs):
+		return keyID, nil, err
+	}
 	for i := 0; i < retrySignLoop; i++ {
 		for _, mpcNode := range c.allInitiatorNodes {
 			if err = c.pingMPCNode(mpcNode); err != nil {
@@ -147,7 +173,26 @@ func (c *Config) doSignImpl(mpcNode *NodeInfo, signGroupIndex int, signType, sig
 
 	log.Info("mpc sign begin", "signGroup", signGroup, "signPubkey", signPubkey, "nonce", nonce, "msgHash", msgHash, "msgContext", msgContext, "signType", signType, "keyID", keyID, "ts", txdata.TimeStamp)
 
+	reqKey, swapKey, swapNonce := getSignRequestKey(signPubkey, msgHash, msgContext)
+	if mongodb.HasClient() {
+		_ = mongodb.AddSignRequest(&mongodb.MgoSignRequest{
+			Key:        reqKey,
+			SwapKey:    swapKey,
+			SwapNonce:  swapNonce,
+			SignPubkey: signPubkey,
+			MsgHash:    msgHash,
+			MsgContext: msgContext,
+			KeyID:      keyID,
+			RPCAddr:    rpcAddr,
+			SignGroup:  signGroup,
+			Timestamp:  time.Now().Unix(),
+		})
+	}
+
 	rsvs, err = c.getSignResult(keyID, rpcAddr, msgContext)
+	if mongodb.HasClient() {
+		_ = mongodb.RemoveSignRequest(reqKey, keyID)
+	}
 	if err != nil {
 		if c.maxSignGroupFailures > 0 {
 			old := c.signGroupFailuresMap[signGroup]
@@ -169,19 +214,79 @@ func (c *Config) doSignImpl(mpcNode *NodeInfo, signGroupIndex int, signType, sig
 			lastTime: time.Now().Unix(),
 		}
 	}
-	if mongodb.HasClient() && isEC(signType) { // prevent multiple use of same r value
-		for _, rsv := range rsvs {
-			signature := common.FromHex(rsv)
-			if len(signature) != crypto.SignatureLength {
-				return keyID, nil, errWrongSignatureLength
-			}
-			r := common.ToHex(signature[:32])
-			err = mongodb.AddUsedRValue(signPubkey, r)
-			if err != nil {
-				return keyID, nil, errRValueIsUsed
-			}
+	if err = checkUsedRValues(signType, signPubkey, rsvs); err != nil {
+		return keyID, nil, err
+	}
+	return keyID, rsvs, nil
+}
+
+// checkUsedRValues prevent multiple use of same r value
+func checkUsedRValues(signType, signPubkey string, rsvs []string) error {
+	if !mongodb.HasClient() || !isEC(signType) {
+		return nil
+	}
+	for _, rsv := range rsvs {
+		signature := common.FromHex(rsv)
+		if len(signature) != crypto.SignatureLength {
+			return errWrongSignatureLength
+		}
+		r := common.ToHex(signature[:32])
+		if err := mongodb.AddUsedRValue(signPubkey, r); err != nil {
+			return errRValueIsUsed
 		}
 	}
+	return nil
+}
+
+// getSignRequestKey get persisted key of sign request,
+// sign requests of swaps (and internal txs) are keyed by the swap and nonce.
+func getSignRequestKey(signPubkey string, msgHash, msgContext []string) (key, swapKey string, nonce uint64) {
+	if len(msgContext) > 0 {
+		var args tokens.BuildTxArgs
+		if err := json.Unmarshal([]byte(msgContext[0]), &args); err == nil &&
+			args.SwapID != "" && args.FromChainID != nil {
+			swapKey = mongodb.GetRouterSwapKey(args.FromChainID.String(), args.SwapID, args.LogIndex)
+			nonce = args.GetTxNonce()
+		}
+	}
+	return mongodb.GetSignRequestKey(swapKey, nonce, signPubkey, msgHash), swapKey, nonce
+}
+
+// resumeSign get the result of the pending sign request of the same swap nonce
+// (or the same messages), which is launched before restart, instead of launching
+// a new sign round. if the pending request of the same swap nonce has other
+// messages and is signed, ErrNonceSignedWithOtherMsgs is returned and the
+// request is kept, so that the signed tx is resumed instead of signing another
+// tx with the same nonce.
+func (c *Config) resumeSign(signType, signPubkey string, msgHash, msgContext []string) (keyID string, rsvs []string, err error) {
+	if !mongodb.HasClient() {
+		return "", nil, errNoPendingSignRequest
+	}
+	key, _, _ := getSignRequestKey(signPubkey, msgHash, msgContext)
+	req, err := mongodb.FindSignRequest(key)
+	if err != nil {
+		return "", nil, errNoPendingSignRequest
+	}
+	keyID = req.KeyID
+	sameMsgs := strings.EqualFold(strings.Join(req.MsgHash, ","), strings.Join(msgHash, ","))
+	log.Info("mpc resume sign", "signGroup", req.SignGroup, "signPubkey", signPubkey, "msgHash", msgHash, "pendingMsgHash", req.MsgHash, "msgContext", msgContext, "signType", signType, "keyID", keyID, "signTime", req.Timestamp)
+	rsvs, err = c.getSignResult(keyID, req.RPCAddr, msgContext)
+	if !sameMsgs {
+		if err == nil {
+			log.Warn("mpc resume sign found the same nonce signed with other messages", "key", key, "keyID", keyID, "msgHash", msgHash, "pendingMsgHash", req.MsgHash)
+			return keyID, nil, ErrNonceSignedWithOtherMsgs
+		}
+		_ = mongodb.RemoveSignRequest(key, keyID)
+		return keyID, nil, errNoPendingSignRequest
+	}
+	_ = mongodb.RemoveSignRequest(key, keyID)
+	if err != nil {
+		log.Warn("mpc resume sign failed, sign again", "keyID", keyID, "msgHash", msgHash, "err", err)
+		return keyID, nil, err
+	}
+	if err = checkUsedRValues(signType, signPubkey, rsvs); err != nil {
+		return keyID, nil, err
+	}
 	return keyID, rsvs, nil
 }
 
@@ -292,3 +397,19 @@ func (s *SignInfoData) HasValidSignature() bool {
 	copy(addr[:], crypto.Keccak256(pub[1:])[12:])
 	return addr == common.HexToAddress(s.Account)
 }
+
+// SignHashWithNodeKey sign hash with the keystore of the default mpc node,
+// it identifies this router node itself (eg. signing swap receipts).
+func (c *Config) SignHashWithNodeKey(hash []byte) (signature []byte, signer common.Address, err error) {
+	if params.IsWatcherMode {
+		return nil, signer, params.ErrWatcherMode
+	}
+	if c.defaultMPCNode == nil || c.defaultMPCNode.keyWrapper == nil {
+		return nil, signer, errNoNodeKeystore
+	}
+	signature, err = crypto.Sign(hash, c.defaultMPCNode.keyWrapper.PrivateKey)
+	if err != nil {
+		return nil, signer, err
+	}
+	return signature, c.defaultMPCNode.mpcUser, nil
+}
@@ -0,0 +1,128 @@
+package mpc
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/tools/keystore"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func newTestMsgContext(t *testing.T, nonce uint64) []string {
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapID:      "0xABCD",
+			LogIndex:    2,
+			FromChainID: big.NewInt(1),
+			ToChainID:   big.NewInt(56),
+		},
+		Extra:      &tokens.AllExtras{Sequence: &nonce},
+		SignExpiry: 1700000060,
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		t.Fatalf("marshal build args failed: %v", err)
+	}
+	return []string{string(data)}
+}
+
+func TestGetSignRequestKey(t *testing.T) {
+	msgContext := newTestMsgContext(t, 7)
+	key, swapKey, nonce := getSignRequestKey("0x04aa", []string{"0x01"}, msgContext)
+	if key != "1:0xabcd:2:7" || swapKey != "1:0xabcd:2" || nonce != 7 {
+		t.Errorf("sign request key of swap got (%v, %v, %v)", key, swapKey, nonce)
+	}
+
+	// the swap rebuilt with the same nonce finds the pending sign request
+	rebuiltKey, _, _ := getSignRequestKey("0x04aa", []string{"0x02"}, newTestMsgContext(t, 7))
+	if rebuiltKey != key {
+		t.Errorf("sign request key of rebuilt swap got %v, want %v", rebuiltKey, key)
+	}
+	otherKey, _, _ := getSignRequestKey("0x04aa", []string{"0x01"}, newTestMsgContext(t, 8))
+	if otherKey == key {
+		t.Errorf("sign requests of different nonces should have different keys")
+	}
+
+	// sign requests without swap context are keyed by the messages
+	key1, swapKey, _ := getSignRequestKey("0x04AA", []string{"0x01"}, nil)
+	key2, _, _ := getSignRequestKey("0x04aa", []string{"0x01"}, []string{"not build args"})
+	key3, _, _ := getSignRequestKey("0x04aa", []string{"0x02"}, nil)
+	if swapKey != "" || key1 != key2 || key1 == key3 {
+		t.Errorf("sign request keys of messages got (%v, %v, %v)", key1, key2, key3)
+	}
+}
+
+func TestSignRequestRoundTrip(t *testing.T) {
+	msgHash := []string{"0x01"}
+	msgContext := newTestMsgContext(t, 7)
+	key, swapKey, nonce := getSignRequestKey("0x04aa", msgHash, msgContext)
+	req := &mongodb.MgoSignRequest{
+		Key:        key,
+		SwapKey:    swapKey,
+		SwapNonce:  nonce,
+		SignPubkey: "0x04aa",
+		MsgHash:    msgHash,
+		MsgContext: msgContext,
+		KeyID:      "0x1234",
+		RPCAddr:    "http://127.0.0.1:5916",
+		SignGroup:  "group",
+		Timestamp:  1700000000,
+	}
+
+	data, err := bson.Marshal(req)
+	if err != nil {
+		t.Fatalf("store sign request failed: %v", err)
+	}
+	var reloaded mongodb.MgoSignRequest
+	if err = bson.Unmarshal(data, &reloaded); err != nil {
+		t.Fatalf("reload sign request failed: %v", err)
+	}
+	if !reflect.DeepEqual(&reloaded, req) {
+		t.Fatalf("reloaded sign request got %+v, want %+v", reloaded, req)
+	}
+
+	// the reloaded request is found by resume sign, and its build args are kept
+	if reloadedKey, _, _ := getSignRequestKey(reloaded.SignPubkey, reloaded.MsgHash, reloaded.MsgContext); reloadedKey != reloaded.Key {
+		t.Errorf("key of reloaded sign request got %v, want %v", reloadedKey, reloaded.Key)
+	}
+	var args tokens.BuildTxArgs
+	if err = json.Unmarshal([]byte(reloaded.MsgContext[0]), &args); err != nil {
+		t.Fatalf("unmarshal reloaded build args failed: %v", err)
+	}
+	if args.GetTxNonce() != reloaded.SwapNonce || args.SignExpiry != 1700000060 || args.ToChainID.Cmp(big.NewInt(56)) != 0 {
+		t.Errorf("reloaded build args got nonce %v, sign expiry %v, toChainID %v", args.GetTxNonce(), args.SignExpiry, args.ToChainID)
+	}
+}
+
+func TestSignHashWithNodeKey(t *testing.T) {
+	hash := common.Keccak256Hash([]byte("CrossChain-Router swap receipt"))
+	if _, _, err := (&Config{}).SignHashWithNodeKey(hash[:]); !errors.Is(err, errNoNodeKeystore) {
+		t.Errorf("sign without node keystore got error %v, want %v", err, errNoNodeKeystore)
+	}
+
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key failed: %v", err)
+	}
+	nodeAddr := crypto.PubkeyToAddress(privKey.PublicKey)
+	c := &Config{defaultMPCNode: &NodeInfo{
+		keyWrapper: &keystore.Key{Address: nodeAddr, PrivateKey: privKey},
+		mpcUser:    nodeAddr,
+	}}
+	signature, signer, err := c.SignHashWithNodeKey(hash[:])
+	if err != nil || signer != nodeAddr {
+		t.Fatalf("sign with node key got (%v, %v), want signer %v", signer, err, nodeAddr)
+	}
+	pubkey, err := crypto.SigToPub(hash[:], signature)
+	if err != nil || crypto.PubkeyToAddress(*pubkey) != nodeAddr {
+		t.Errorf("recover signer of signature failed: %v", err)
+	}
+}
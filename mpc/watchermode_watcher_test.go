@@ -0,0 +1,33 @@
+//go:build watcher
+
+package mpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+func TestWatcherModeRefuseSigning(t *testing.T) {
+	c := InitConfig(&params.MPCConfig{SignWithPrivateKey: true}, true)
+	if c.defaultMPCNode != nil {
+		t.Errorf("mpc node keystore should not be loaded in watcher mode")
+	}
+
+	if _, _, err := c.DoSign(c.signTypeEC256K1, "0x04aa", []string{"0x01"}, []string{"swap"}); !errors.Is(err, params.ErrWatcherMode) {
+		t.Errorf("do sign got err %v, want %v", err, params.ErrWatcherMode)
+	}
+	if _, err := c.Sign("raw", "http://127.0.0.1:5916"); !errors.Is(err, params.ErrWatcherMode) {
+		t.Errorf("sign got err %v, want %v", err, params.ErrWatcherMode)
+	}
+	if _, err := c.AcceptSign("raw"); !errors.Is(err, params.ErrWatcherMode) {
+		t.Errorf("accept sign got err %v, want %v", err, params.ErrWatcherMode)
+	}
+	if _, err := c.DoAcceptSign("keyID", "AGREE", []string{"0x01"}, []string{"swap"}); !errors.Is(err, params.ErrWatcherMode) {
+		t.Errorf("do accept sign got err %v, want %v", err, params.ErrWatcherMode)
+	}
+	if _, _, err := c.SignHashWithNodeKey(make([]byte, 32)); !errors.Is(err, params.ErrWatcherMode) {
+		t.Errorf("sign hash with node key got err %v, want %v", err, params.ErrWatcherMode)
+	}
+}
@@ -0,0 +1,53 @@
+package params
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestAllowanceMonitorConfig(t *testing.T) {
+	token := "0x1111111111111111111111111111111111111111"
+	spender := "0x2222222222222222222222222222222222222222"
+	for i, allowance := range []*AllowanceConfig{
+		{Token: "0x1234", Spender: spender, MinAllowance: "100"},
+		{Token: token, Spender: "0x1234", MinAllowance: "100"},
+		{Token: token, Spender: spender, MinAllowance: "0"},
+		{Token: token, Spender: spender, MinAllowance: "abc"},
+		{Token: token, Spender: spender, MinAllowance: "100", ApproveAmount: "99"},
+	} {
+		cfg := &AllowanceMonitorConfig{Allowances: []*AllowanceConfig{allowance}}
+		if err := cfg.CheckConfig(); err == nil {
+			t.Errorf("test %v: check wrong allowance config should fail", i)
+		}
+	}
+
+	cfg := &AllowanceMonitorConfig{Allowances: []*AllowanceConfig{
+		{Token: token, Spender: spender, MinAllowance: "100"},
+		{Token: token, Spender: token, MinAllowance: "100", ApproveAmount: "1000"},
+	}}
+	if err := cfg.CheckConfig(); err != nil {
+		t.Fatalf("check allowance config failed: %v", err)
+	}
+	allowance := cfg.GetAllowanceConfig("0x1111111111111111111111111111111111111111", "0x2222222222222222222222222222222222222222")
+	if allowance != cfg.Allowances[0] {
+		t.Fatalf("get allowance config got %+v", allowance)
+	}
+	if cfg.GetAllowanceConfig(spender, token) != nil {
+		t.Errorf("get allowance config of unmonitored spender should be nil")
+	}
+	if minAllowance := allowance.GetMinAllowance(); minAllowance.Int64() != 100 {
+		t.Errorf("min allowance got %v, want 100", minAllowance)
+	}
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	if amount := allowance.GetApproveAmount(); amount.Cmp(maxUint256) != 0 {
+		t.Errorf("default approve amount got %v, want max uint256", amount)
+	}
+	if amount := cfg.Allowances[1].GetApproveAmount(); amount.Int64() != 1000 {
+		t.Errorf("approve amount got %v, want 1000", amount)
+	}
+	// getters return copies
+	allowance.GetMinAllowance().SetInt64(1)
+	if minAllowance := allowance.GetMinAllowance(); minAllowance.Int64() != 100 {
+		t.Errorf("min allowance changed to %v by its getter result", minAllowance)
+	}
+}
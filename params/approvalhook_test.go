@@ -0,0 +1,27 @@
+package params
+
+import "testing"
+
+func TestCheckApprovalHooks(t *testing.T) {
+	tests := []struct {
+		hooks   []*ApprovalHookConfig
+		wantErr bool
+	}{
+		{nil, false},
+		{[]*ApprovalHookConfig{{Name: "compliance", URL: "https://compliance.example.com"}, {Name: "risk", URL: "http://127.0.0.1:8080"}}, false},
+		{[]*ApprovalHookConfig{{URL: "https://compliance.example.com"}}, true},
+		{[]*ApprovalHookConfig{{Name: "compliance", URL: "https://a.example.com"}, {Name: "compliance", URL: "https://b.example.com"}}, true},
+		{[]*ApprovalHookConfig{{Name: "compliance", URL: "compliance.example.com"}}, true},
+		{[]*ApprovalHookConfig{{Name: "compliance", URL: "https://compliance.example.com", HoldInterval: -1}}, true},
+	}
+	for i, test := range tests {
+		if err := checkApprovalHooks(test.hooks); (err != nil) != test.wantErr {
+			t.Errorf("test %v: check approval hooks got error %v, want error %v", i, err, test.wantErr)
+		}
+	}
+
+	hook := &ApprovalHookConfig{}
+	if hook.GetTimeout() != 10 || hook.GetHoldInterval() != 300 {
+		t.Errorf("default approval hook got timeout %v and hold interval %v", hook.GetTimeout(), hook.GetHoldInterval())
+	}
+}
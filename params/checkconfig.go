@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,7 +15,11 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
 )
 
-var blankOrCommaSepRegexp = regexp.MustCompile(`[\s,]+`) // blank or comma separated
+var (
+	blankOrCommaSepRegexp = regexp.MustCompile(`[\s,]+`) // blank or comma separated
+	deploymentSaltRegexp  = regexp.MustCompile(`^[0-9A-Za-z_-]{4,32}$`)
+	decimalAmountRegexp   = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+)
 
 func splitStringByBlankOrComma(str string) []string {
 	return blankOrCommaSepRegexp.Split(strings.TrimSpace(str), -1)
@@ -38,13 +43,16 @@ func (config *RouterConfig) CheckConfig(isServer bool) (err error) {
 	if !strings.HasPrefix(config.Identifier, RouterSwapPrefixID) || config.Identifier == RouterSwapPrefixID {
 		return fmt.Errorf("wrong identifier '%v', missing prefix '%v'", config.Identifier, RouterSwapPrefixID)
 	}
+	if config.DeploymentSalt != "" && !deploymentSaltRegexp.MatchString(config.DeploymentSalt) {
+		return fmt.Errorf("wrong deployment salt '%v', must be 4 to 32 alphanumeric, '_' or '-' characters", config.DeploymentSalt)
+	}
 	if config.SwapType == "" {
 		return errors.New("empty router swap type")
 	}
 	if config.SwapType == "anycallswap" && config.SwapSubType == "" {
 		return errors.New("anycall must config 'SwapSubType'")
 	}
-	log.Info("check identifier pass", "identifier", config.Identifier, "swaptype", config.SwapType, "swapsubtype", config.SwapSubType, "isServer", isServer)
+	log.Info("check identifier pass", "identifier", config.Identifier, "deploymentSalt", config.DeploymentSalt, "swaptype", config.SwapType, "swapsubtype", config.SwapSubType, "isServer", isServer)
 
 	err = config.CheckBlacklistConfig()
 	if err != nil {
@@ -205,6 +213,18 @@ func (c *RouterOracleConfig) CheckConfig() (err error) {
 	return err
 }
 
+// fee in fee denom preference list must be amount with denom
+func checkFeeDenomPreference(feeDenomPreference map[string][]string) error {
+	for cid, fees := range feeDenomPreference {
+		for _, fee := range fees {
+			if _, err := strconv.ParseFloat(fee, 64); fee == "" || err == nil {
+				return fmt.Errorf("chain %v has wrong fee '%v' in 'FeeDenomPreference' (must be amount with denom)", cid, fee)
+			}
+		}
+	}
+	return nil
+}
+
 // CheckConfig of router server
 //nolint:funlen,gocyclo // ok
 func (s *RouterServerConfig) CheckConfig() error {
@@ -220,6 +240,53 @@ func (s *RouterServerConfig) CheckConfig() error {
 	if err := s.MongoDB.CheckConfig(); err != nil {
 		return err
 	}
+	if err := s.APIServer.AbuseDetection.CheckConfig(); err != nil {
+		return err
+	}
+	if err := s.APIServer.Export.CheckConfig(); err != nil {
+		return err
+	}
+	if err := s.APIServer.Faucet.CheckConfig(); err != nil {
+		return err
+	}
+	if err := checkAPIDeprecations(s.APIServer.Deprecations); err != nil {
+		return err
+	}
+	if err := s.TimeLock.CheckConfig(); err != nil {
+		return err
+	}
+	if err := s.SwapGC.CheckConfig(); err != nil {
+		return err
+	}
+	if err := s.IntegrityCheck.CheckConfig(); err != nil {
+		return err
+	}
+	if err := s.SwapStats.CheckConfig(); err != nil {
+		return err
+	}
+	if err := s.MPCUsage.CheckConfig(); err != nil {
+		return err
+	}
+	if err := s.DuplicateCheck.CheckConfig(); err != nil {
+		return err
+	}
+	if err := s.Quarantine.CheckConfig(); err != nil {
+		return err
+	}
+	if err := checkApprovalHooks(s.ApprovalHooks); err != nil {
+		return err
+	}
+	if err := checkWebhooks(s.Webhooks); err != nil {
+		return err
+	}
+	for pair, maxCount := range s.MaxInFlightSwaps {
+		if err := checkTokenRoute(pair); err != nil || strings.Contains(pair, "*") {
+			return fmt.Errorf("wrong chain pair '%v' in 'MaxInFlightSwaps'", pair)
+		}
+		if maxCount <= 0 {
+			return fmt.Errorf("chain pair %v max in-flight swaps %v is not positive", pair, maxCount)
+		}
+	}
 	for cid, defGasLimit := range s.DefaultGasLimit {
 		masGasLimit := s.MaxGasLimit[cid]
 		if masGasLimit > 0 && defGasLimit > masGasLimit {
@@ -227,6 +294,10 @@ func (s *RouterServerConfig) CheckConfig() error {
 		}
 	}
 
+	if err := checkFeeDenomPreference(s.FeeDenomPreference); err != nil {
+		return err
+	}
+
 	initAutoSwapNonceEnabledChains()
 
 	tempFixGasPriceMap := make(map[string]*big.Int)
@@ -364,6 +435,304 @@ func (c *MongoDBConfig) CheckConfig() error {
 		}
 		c.DBURLs = splitStringByBlankOrComma(c.DBURL)
 	}
+	if c.SlowQueryThreshold < 0 {
+		return errors.New("mongodb 'SlowQueryThreshold' is negative")
+	}
+	if c.Mirror != nil {
+		if c.Mirror.Mirror != nil {
+			return errors.New("mongodb mirror can not config 'Mirror'")
+		}
+		if err := c.Mirror.CheckConfig(); err != nil {
+			return fmt.Errorf("mongodb mirror: %w", err)
+		}
+	}
+	if c.FieldEncryption != nil {
+		if err := c.FieldEncryption.CheckConfig(); err != nil {
+			return fmt.Errorf("mongodb field encryption: %w", err)
+		}
+	}
+	return nil
+}
+
+// CheckConfig check field encryption config
+func (c *FieldEncryptionConfig) CheckConfig() error {
+	if c.KMSURL == "" && c.KeyFile == "" {
+		return errors.New("must config 'KMSURL' or 'KeyFile'")
+	}
+	if c.KMSURL != "" && c.KeyFile != "" {
+		return errors.New("can not config both 'KMSURL' and 'KeyFile'")
+	}
+	return nil
+}
+
+// CheckConfig check swap data export config
+func (c *ExportConfig) CheckConfig() error {
+	if c == nil || !c.Enable {
+		return nil
+	}
+	if c.MaxRange < 0 || c.MaxSyncRows < 0 || c.MaxRows < 0 || c.MaxJobs < 0 || c.JobRetention < 0 {
+		return errors.New("export config has negative value")
+	}
+	if c.GetMaxSyncRows() > c.GetMaxRows() {
+		return errors.New("export config 'MaxSyncRows' is greater than 'MaxRows'")
+	}
+	return nil
+}
+
+// CheckConfig check testnet faucet config
+func (c *FaucetConfig) CheckConfig() error {
+	if c == nil || !c.Enable {
+		return nil
+	}
+	if IsWatcherMode {
+		return errors.New("faucet is not supported in watcher mode")
+	}
+	if !c.Testnet {
+		return errors.New("faucet must be enabled with 'Testnet = true' in testnet deployments only")
+	}
+	if len(c.APIKeys) == 0 && c.CaptchaSecret == "" {
+		return errors.New("faucet must config 'APIKeys' or 'CaptchaSecret'")
+	}
+	if c.CoolDown < 0 {
+		return errors.New("faucet config has negative 'CoolDown'")
+	}
+	if len(c.Chains) == 0 {
+		return errors.New("faucet has no 'Chains'")
+	}
+	for chainID, chainCfg := range c.Chains {
+		if !IsKnownTestnetChainID(chainID) {
+			return fmt.Errorf("faucet chain '%v' is not a known testnet", chainID)
+		}
+		if chainCfg == nil || chainCfg.PrivateKey == "" {
+			return fmt.Errorf("faucet of chain '%v' has no 'PrivateKey'", chainID)
+		}
+		if len(chainCfg.MintAmounts) == 0 {
+			return fmt.Errorf("faucet of chain '%v' has no 'MintAmounts'", chainID)
+		}
+		chainCfg.mintAmounts = make(map[string]*big.Int, len(chainCfg.MintAmounts))
+		for tokenID, amountStr := range chainCfg.MintAmounts {
+			amount, err := common.GetBigIntFromStr(amountStr)
+			if err != nil || amount.Sign() <= 0 {
+				return fmt.Errorf("faucet mint amount %q of token '%v' on chain '%v' is not a positive integer", amountStr, tokenID, chainID)
+			}
+			chainCfg.mintAmounts[strings.ToLower(tokenID)] = amount
+		}
+	}
+	return nil
+}
+
+func checkAPIDeprecations(deprecations []*APIDeprecationConfig) error {
+	paths := make(map[string]struct{}, len(deprecations))
+	for _, c := range deprecations {
+		if !strings.HasPrefix(c.Path, "/") {
+			return fmt.Errorf("api deprecation path '%v' does not start with '/'", c.Path)
+		}
+		if _, exist := paths[c.Path]; exist {
+			return fmt.Errorf("duplicate api deprecation path '%v'", c.Path)
+		}
+		paths[c.Path] = struct{}{}
+		deprecated, err := time.Parse("2006-01-02", c.Deprecated)
+		if err != nil {
+			return fmt.Errorf("api deprecation of '%v' has wrong 'Deprecated' date: %w", c.Path, err)
+		}
+		c.deprecatedTime = deprecated
+		if c.Sunset == "" {
+			continue
+		}
+		sunset, err := time.Parse("2006-01-02", c.Sunset)
+		if err != nil {
+			return fmt.Errorf("api deprecation of '%v' has wrong 'Sunset' date: %w", c.Path, err)
+		}
+		if !sunset.After(deprecated) {
+			return fmt.Errorf("api deprecation of '%v' sunsets before deprecated", c.Path)
+		}
+		c.sunsetTime = sunset
+	}
+	return nil
+}
+
+// CheckConfig check abuse detection config
+func (c *AbuseDetectionConfig) CheckConfig() error {
+	if c == nil || !c.Enable {
+		return nil
+	}
+	if c.WindowSeconds < 0 || c.BanSeconds < 0 || c.MaxBanSeconds < 0 {
+		return errors.New("abuse detection has negative seconds config")
+	}
+	if c.MaxRequestsPerWindow < 0 || c.MaxRegistersPerWindow < 0 || c.MaxFailedRegistersPerWindow < 0 {
+		return errors.New("abuse detection has negative limit config")
+	}
+	if c.GetBanDuration() > c.GetMaxBanDuration() {
+		return errors.New("abuse detection 'BanSeconds' is greater than 'MaxBanSeconds'")
+	}
+	return nil
+}
+
+// CheckConfig check quarantine config
+func (c *QuarantineConfig) CheckConfig() error {
+	if c == nil {
+		return nil
+	}
+	if c.Quorum < 0 || c.NotFoundTimeout < 0 {
+		return errors.New("quarantine config has negative value")
+	}
+	return nil
+}
+
+// CheckConfig check swap gc config
+func (c *SwapGCConfig) CheckConfig() error {
+	if c == nil {
+		return nil
+	}
+	if c.Interval < 0 || c.Retention < 0 || c.MaxOldSwapTxs < 0 || c.BatchSize < 0 {
+		return errors.New("swap gc config has negative value")
+	}
+	return nil
+}
+
+// CheckConfig check integrity check config
+func (c *IntegrityCheckConfig) CheckConfig() error {
+	if c == nil {
+		return nil
+	}
+	if c.Interval < 0 || c.ScanWindow < 0 || c.GracePeriod < 0 || c.BatchSize < 0 {
+		return errors.New("integrity check config has negative value")
+	}
+	return nil
+}
+
+// CheckConfig check swap stats config
+func (c *SwapStatsConfig) CheckConfig() error {
+	if c == nil {
+		return nil
+	}
+	if c.Interval < 0 || c.Retention < 0 || c.BatchSize < 0 {
+		return errors.New("swap stats config has negative value")
+	}
+	return nil
+}
+
+// CheckConfig check duplicate check config
+func (c *DuplicateCheckConfig) CheckConfig() error {
+	if c == nil {
+		return nil
+	}
+	if c.Interval < 0 || c.ScanWindow < 0 || c.BatchSize < 0 {
+		return errors.New("duplicate check config has negative value")
+	}
+	return nil
+}
+
+// CheckConfig check mpc usage config
+func (c *MPCUsageConfig) CheckConfig() error {
+	if c == nil {
+		return nil
+	}
+	if c.BucketSize < 0 || c.Retention < 0 || c.CheckInterval < 0 ||
+		c.BaselineBuckets < 0 || c.SpikeRatio < 0 || c.MinSpikeCount < 0 {
+		return errors.New("mpc usage config has negative value")
+	}
+	if c.GetRetention() < (c.GetBaselineBuckets()+1)*c.GetBucketSize() {
+		return errors.New("mpc usage config 'Retention' is shorter than baseline buckets")
+	}
+	return nil
+}
+
+func checkApprovalHooks(hooks []*ApprovalHookConfig) error {
+	names := make(map[string]struct{}, len(hooks))
+	for i, hook := range hooks {
+		if hook == nil || hook.Name == "" {
+			return fmt.Errorf("approval hook %v has empty 'Name'", i)
+		}
+		if _, exist := names[hook.Name]; exist {
+			return fmt.Errorf("duplicate approval hook '%v'", hook.Name)
+		}
+		names[hook.Name] = struct{}{}
+		if !strings.HasPrefix(hook.URL, "http://") && !strings.HasPrefix(hook.URL, "https://") {
+			return fmt.Errorf("approval hook '%v' has wrong 'URL' %v", hook.Name, hook.URL)
+		}
+		if hook.Timeout < 0 || hook.HoldInterval < 0 {
+			return fmt.Errorf("approval hook '%v' has negative value", hook.Name)
+		}
+	}
+	return nil
+}
+
+func checkWebhooks(hooks []*WebhookConfig) error {
+	names := make(map[string]struct{}, len(hooks))
+	for i, hook := range hooks {
+		if hook == nil || hook.Name == "" {
+			return fmt.Errorf("webhook %v has empty 'Name'", i)
+		}
+		if _, exist := names[hook.Name]; exist {
+			return fmt.Errorf("duplicate webhook '%v'", hook.Name)
+		}
+		names[hook.Name] = struct{}{}
+		if !strings.HasPrefix(hook.URL, "http://") && !strings.HasPrefix(hook.URL, "https://") {
+			return fmt.Errorf("webhook '%v' has wrong 'URL' %v", hook.Name, hook.URL)
+		}
+		if len(hook.Secrets) == 0 {
+			return fmt.Errorf("webhook '%v' has no 'Secrets'", hook.Name)
+		}
+		for _, secret := range hook.Secrets {
+			if secret == "" {
+				return fmt.Errorf("webhook '%v' has empty secret", hook.Name)
+			}
+		}
+		if hook.Timeout < 0 || hook.Interval < 0 || hook.BatchSize < 0 {
+			return fmt.Errorf("webhook '%v' has negative value", hook.Name)
+		}
+	}
+	return nil
+}
+
+func checkConfirmationTiers(tiersOfPairs map[string]map[string][]*ConfirmationTier) error {
+	for pair, tiersOfTokens := range tiersOfPairs {
+		if err := checkTokenRoute(pair); err != nil || strings.Contains(pair, "*") {
+			return fmt.Errorf("wrong chain pair '%v' in 'ConfirmationTiers'", pair)
+		}
+		for tokenID, tiers := range tiersOfTokens {
+			prevValue := float64(0)
+			for i, tier := range tiers {
+				if tier.Confirmations == 0 {
+					return fmt.Errorf("chain pair %v token %v tier %v has zero 'Confirmations'", pair, tokenID, i)
+				}
+				if tier.MaxValue == "" {
+					if i != len(tiers)-1 {
+						return fmt.Errorf("chain pair %v token %v tier %v has empty 'MaxValue' but is not the last", pair, tokenID, i)
+					}
+					continue
+				}
+				if !decimalAmountRegexp.MatchString(tier.MaxValue) {
+					return fmt.Errorf("chain pair %v token %v tier %v has wrong 'MaxValue' '%v'", pair, tokenID, i, tier.MaxValue)
+				}
+				value, _ := strconv.ParseFloat(tier.MaxValue, 64)
+				if value <= prevValue {
+					return fmt.Errorf("chain pair %v token %v tiers are not in ascending order of 'MaxValue'", pair, tokenID)
+				}
+				prevValue = value
+			}
+		}
+	}
+	return nil
+}
+
+// CheckConfig check time lock config
+func (c *TimeLockConfig) CheckConfig() error {
+	if c == nil {
+		return nil
+	}
+	if c.DelaySeconds < 0 {
+		return errors.New("time lock 'DelaySeconds' is negative")
+	}
+	for tokenID, threshold := range c.Thresholds {
+		if !decimalAmountRegexp.MatchString(threshold) {
+			return fmt.Errorf("token %v has wrong time lock threshold '%v'", tokenID, threshold)
+		}
+		if value, _ := strconv.ParseFloat(threshold, 64); value <= 0 {
+			return fmt.Errorf("token %v has wrong time lock threshold '%v'", tokenID, threshold)
+		}
+	}
 	return nil
 }
 
@@ -404,6 +773,9 @@ func (c *OnchainConfig) CheckConfig() error {
 //nolint:funlen,gocyclo // ok
 func (c *MPCConfig) CheckConfig(isServer bool) (err error) {
 	if c.SignWithPrivateKey {
+		if IsWatcherMode {
+			return errors.New("mpc 'SignWithPrivateKey' is not supported in watcher mode")
+		}
 		return nil
 	}
 	if c.GroupID == nil {
@@ -434,6 +806,36 @@ func (c *MPCConfig) CheckConfig(isServer bool) (err error) {
 			return err
 		}
 	}
+	if c.DualControl != nil {
+		err = c.DualControl.CheckConfig()
+		if err != nil {
+			return err
+		}
+	}
+	if c.Canary != nil && c.Canary.Interval < 0 {
+		return errors.New("mpc canary 'Interval' is negative")
+	}
+	return nil
+}
+
+// CheckConfig check dual control config
+func (c *DualControlConfig) CheckConfig() error {
+	if c.MinApprovals < 0 || c.MinApprovals == 1 {
+		return errors.New("dual control 'MinApprovals' must be at least 2")
+	}
+	approvers := make(map[string]struct{}, len(c.Approvers))
+	for _, approver := range c.Approvers {
+		if !common.IsHexAddress(approver) {
+			return fmt.Errorf("dual control wrong approver address '%v'", approver)
+		}
+		approvers[strings.ToLower(approver)] = struct{}{}
+	}
+	if len(approvers) < c.GetMinApprovals() {
+		return fmt.Errorf("dual control has %v distinct approvers, less than %v", len(approvers), c.GetMinApprovals())
+	}
+	if c.RecoveryMode {
+		log.Warn("mpc dual control is bypassed in recovery mode")
+	}
 	return nil
 }
 
@@ -460,11 +862,14 @@ func (c *ExtraConfig) CheckConfig() (err error) {
 	initCallByContractWhitelist()
 	initCallByContractCodeHashWhitelist()
 	initBigValueWhitelist()
+	initTokenRouteWhitelist()
+	initDestMethodAllowlist()
 	initDynamicFeeTxEnabledChains()
 	initEnableCheckTxBlockHashChains()
 	initEnableCheckTxBlockIndexChains()
 	initDisableUseFromChainIDInReceiptChains()
 	initUseFastMPCChains()
+	initShadowModeChains()
 	initIncreaseNonceWhenSendTxChains()
 	initDontCheckReceivedTokenIDs()
 	initDontCheckBalanceTokenIDs()
@@ -472,6 +877,24 @@ func (c *ExtraConfig) CheckConfig() (err error) {
 	initCheckTokenBalanceEnabledChains()
 	initIgnoreAnycallFallbackAppIDs()
 
+	if c.DefaultRetryPolicy != nil {
+		if err = c.DefaultRetryPolicy.CheckConfig(); err != nil {
+			return err
+		}
+	}
+
+	if err = checkConfirmationTiers(c.ConfirmationTiers); err != nil {
+		return err
+	}
+
+	if err = checkRebasingTokenModes(c.RebasingTokenModes); err != nil {
+		return err
+	}
+
+	if err = checkFeatureFlags(c.FeatureFlags); err != nil {
+		return err
+	}
+
 	for cid, cfg := range c.LocalChainConfig {
 		if err = cfg.CheckConfig(); err != nil {
 			log.Warn("check local chain config failed", "chainID", cid, "err", err)
@@ -483,6 +906,12 @@ func (c *ExtraConfig) CheckConfig() (err error) {
 		GetBalanceBlockNumberOpt = "pending"
 	}
 
+	for _, rateCfg := range c.RateOracles {
+		if err = rateCfg.CheckConfig(); err != nil {
+			return err
+		}
+	}
+
 	for chainID, baseFeePercent := range c.BaseFeePercent {
 		if _, ok := new(big.Int).SetString(chainID, 0); !ok {
 			return fmt.Errorf("wrong chain id '%v' in 'BaseFeePercent'", chainID)
@@ -506,5 +935,447 @@ func (c *LocalChainConfig) CheckConfig() (err error) {
 	if c.BigValueDiscount > 100 {
 		return errors.New("'BigValueDiscount' is larger than 100")
 	}
+	if c.DepositFactory != nil {
+		if err = c.DepositFactory.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.AllowanceMonitor != nil {
+		if err = c.AllowanceMonitor.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.HeadWatchdog != nil && c.HeadWatchdog.StallTimeout < 0 {
+		return errors.New("head watchdog 'StallTimeout' is negative")
+	}
+	if c.ReceiptCrossVerify != nil && (c.ReceiptCrossVerify.MinProviders < 0 || c.ReceiptCrossVerify.MinProviders == 1) {
+		return errors.New("receipt cross verify 'MinProviders' must be at least 2")
+	}
+	if c.RetryPolicy != nil {
+		if err = c.RetryPolicy.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.PathFind != nil && (c.PathFind.SlippagePercent < 0 || c.PathFind.SlippagePercent >= 100) {
+		return fmt.Errorf("path find 'SlippagePercent' %v is not in range [0, 100)", c.PathFind.SlippagePercent)
+	}
+	if c.CheckPayout != nil && c.CheckPayout.Expiration < 0 {
+		return errors.New("check payout 'Expiration' is negative")
+	}
+	if c.LedgerStream != nil {
+		if err = c.LedgerStream.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.LightClient != nil {
+		if err = c.LightClient.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.GasRetry != nil {
+		if err = c.GasRetry.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.AccountAbstraction != nil {
+		if err = c.AccountAbstraction.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.ClaimSwap != nil {
+		if err = c.ClaimSwap.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	for tokenID, migration := range c.TokenMigrations {
+		if err = migration.CheckConfig(); err != nil {
+			return fmt.Errorf("token migration of '%v': %w", tokenID, err)
+		}
+	}
+	if err = checkRouterEmitters(c.RouterEmitters); err != nil {
+		return err
+	}
+	if c.SwapFairness != nil {
+		if err = c.SwapFairness.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.SendDelay != nil && c.SendDelay.MaxDelay <= 0 {
+		return errors.New("send delay 'MaxDelay' must be positive")
+	}
+	if c.GasLearning != nil {
+		if err = c.GasLearning.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.SwapAuthorization != nil {
+		if err = c.SwapAuthorization.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.DeliveryFee != nil {
+		if err = c.DeliveryFee.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.ReplaceGuard != nil {
+		if err = c.ReplaceGuard.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.ShallowArchive != nil {
+		for _, msg := range c.ShallowArchive.PrunedErrors {
+			if strings.TrimSpace(msg) == "" {
+				return errors.New("shallow archive 'PrunedErrors' has empty message")
+			}
+		}
+	}
+	if c.MintBurn != nil {
+		if err = c.MintBurn.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	for url, role := range c.EndpointRoles {
+		if role != EndpointRoleRippled && role != EndpointRoleClio {
+			return fmt.Errorf("unknown role '%v' of endpoint %v", role, url)
+		}
+	}
+	if c.SwapPipelineDepth < 0 {
+		return errors.New("'SwapPipelineDepth' is negative")
+	}
+	if err = checkFeatureFlags(c.FeatureFlags); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CheckConfig check gas limit learning config
+func (c *GasLearningConfig) CheckConfig() error {
+	if c.Samples < 0 || c.MinSamples < 0 || c.MarginPercent < 0 {
+		return errors.New("gas learning config has negative value")
+	}
+	if c.GetMinSamples() > c.GetSamples() {
+		return fmt.Errorf("gas learning 'MinSamples' %v is larger than 'Samples' %v", c.GetMinSamples(), c.GetSamples())
+	}
+	if c.Percentile < 0 || c.Percentile > 100 {
+		return fmt.Errorf("gas learning 'Percentile' %v is not in range [0, 100]", c.Percentile)
+	}
+	return nil
+}
+
+// CheckConfig check delivery cost based fee config
+func (c *DeliveryFeeConfig) CheckConfig() error {
+	baseCost, err := common.GetBigIntFromStr(c.BaseCost)
+	if err != nil || baseCost.Sign() <= 0 {
+		return fmt.Errorf("delivery fee 'BaseCost' %q is not a positive integer", c.BaseCost)
+	}
+	c.baseCost = baseCost
+	if c.Samples < 0 || c.MinSamples < 0 {
+		return errors.New("delivery fee config has negative value")
+	}
+	if c.GetMinSamples() > c.GetSamples() {
+		return fmt.Errorf("delivery fee 'MinSamples' %v is larger than 'Samples' %v", c.GetMinSamples(), c.GetSamples())
+	}
+	if c.GetMinPercent() > 100 || c.GetMaxPercent() < 100 {
+		return fmt.Errorf("delivery fee percent range [%v, %v] does not contain 100", c.GetMinPercent(), c.GetMaxPercent())
+	}
+	return nil
+}
+
+// CheckConfig check replace guard config
+func (c *ReplaceGuardConfig) CheckConfig() error {
+	if c.MaxFeeMultiple == 0 && c.MaxValuePercent == 0 {
+		return errors.New("replace guard has neither 'MaxFeeMultiple' nor 'MaxValuePercent'")
+	}
+	if c.MaxValuePercent > 100 {
+		return fmt.Errorf("replace guard 'MaxValuePercent' %v is larger than 100", c.MaxValuePercent)
+	}
+	if len(c.CoinPrices) == 0 && len(c.PriceOracles) == 0 {
+		return errors.New("replace guard has neither 'CoinPrices' nor 'PriceOracles'")
+	}
+	for tokenID, oracle := range c.PriceOracles {
+		if oracle == nil {
+			return fmt.Errorf("replace guard price oracle of token '%v' is empty", tokenID)
+		}
+		oracle.TokenID = tokenID
+		if err := oracle.checkSource(); err != nil {
+			return fmt.Errorf("replace guard price %w", err)
+		}
+	}
+	c.coinPrices = make(map[string]*big.Float, len(c.CoinPrices))
+	for tokenID, priceStr := range c.CoinPrices {
+		price, ok := new(big.Float).SetString(priceStr)
+		if !ok || price.Sign() <= 0 {
+			return fmt.Errorf("replace guard coin price %q of token '%v' is not a positive number", priceStr, tokenID)
+		}
+		c.coinPrices[strings.ToLower(tokenID)] = price
+	}
+	return nil
+}
+
+// an emitter can only be accepted for one router
+func checkRouterEmitters(routerEmitters map[string][]string) error {
+	owners := make(map[string]string)
+	for router, emitters := range routerEmitters {
+		if !common.IsHexAddress(router) {
+			return fmt.Errorf("wrong router contract address '%v' of router emitters", router)
+		}
+		for _, emitter := range emitters {
+			if !common.IsHexAddress(emitter) {
+				return fmt.Errorf("wrong emitter address '%v' of router '%v'", emitter, router)
+			}
+			if strings.EqualFold(emitter, router) {
+				return fmt.Errorf("router '%v' is configured as its own emitter", router)
+			}
+			key := strings.ToLower(emitter)
+			if owner, exist := owners[key]; exist && !strings.EqualFold(owner, router) {
+				return fmt.Errorf("emitter '%v' is accepted by both router '%v' and '%v'", emitter, owner, router)
+			}
+			owners[key] = router
+		}
+	}
+	return nil
+}
+
+// CheckConfig check out of gas retry config
+func (c *GasRetryConfig) CheckConfig() error {
+	if c.MaxAttempts < 0 {
+		return errors.New("gas retry 'MaxAttempts' is negative")
+	}
+	if c.GasAdjustment != 0 && c.GasAdjustment <= 1 {
+		return fmt.Errorf("gas retry 'GasAdjustment' %v is not larger than 1", c.GasAdjustment)
+	}
+	if c.AdjustmentStep < 0 {
+		return errors.New("gas retry 'AdjustmentStep' is negative")
+	}
+	return nil
+}
+
+// CheckConfig check fair scheduling config of swap tasks
+func (c *SwapFairnessConfig) CheckConfig() error {
+	for tokenID, weight := range c.TokenWeights {
+		if weight <= 0 {
+			return fmt.Errorf("swap fairness weight %v of tokenID '%v' is not positive", weight, tokenID)
+		}
+	}
+	return nil
+}
+
+// CheckConfig check token migration config
+func (c *TokenMigrationConfig) CheckConfig() error {
+	if !common.IsHexAddress(c.OldToken) {
+		return fmt.Errorf("wrong old token address '%v'", c.OldToken)
+	}
+	if !common.IsHexAddress(c.NewToken) {
+		return fmt.Errorf("wrong new token address '%v'", c.NewToken)
+	}
+	if strings.EqualFold(c.OldToken, c.NewToken) {
+		return errors.New("old and new token are the same")
+	}
+	if c.CutoverHeight == 0 {
+		return errors.New("token migration without 'CutoverHeight'")
+	}
+	return nil
+}
+
+// CheckConfig check two-phase swap config
+func (c *ClaimSwapConfig) CheckConfig() error {
+	if !common.IsHexAddress(c.Escrow) {
+		return fmt.Errorf("wrong claim escrow address '%v'", c.Escrow)
+	}
+	if len(c.TokenIDs) == 0 {
+		return errors.New("claim swap without 'TokenIDs'")
+	}
+	if c.ClaimPeriod < 0 {
+		return errors.New("claim swap 'ClaimPeriod' is negative")
+	}
+	if c.RefundAddress != "" && !common.IsHexAddress(c.RefundAddress) {
+		return fmt.Errorf("wrong claim refund address '%v'", c.RefundAddress)
+	}
+	return nil
+}
+
+// CheckConfig check signed swap authorization config
+func (c *SwapAuthorizationConfig) CheckConfig() error {
+	if len(c.Domains) == 0 {
+		return errors.New("swap authorization without 'Domains'")
+	}
+	for version, domain := range c.Domains {
+		if domain == nil || domain.Name == "" || domain.Version == "" {
+			return fmt.Errorf("swap authorization domain of router version '%v' has empty name or version", version)
+		}
+	}
+	if c.Deadline < 0 {
+		return errors.New("swap authorization 'Deadline' is negative")
+	}
+	return nil
+}
+
+// CheckConfig check account abstraction config
+func (c *AccountAbstractionConfig) CheckConfig() error {
+	for name, address := range map[string]string{
+		"EntryPoint":   c.EntryPoint,
+		"SmartAccount": c.SmartAccount,
+		"Owner":        c.Owner,
+	} {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("account abstraction wrong '%v' '%v'", name, address)
+		}
+	}
+	if len(c.BundlerURLs) == 0 {
+		return errors.New("account abstraction without 'BundlerURLs'")
+	}
+	if c.PaymasterURL == "" {
+		return errors.New("account abstraction without 'PaymasterURL'")
+	}
+	if c.Paymaster != "" && !common.IsHexAddress(c.Paymaster) {
+		return fmt.Errorf("account abstraction wrong 'Paymaster' '%v'", c.Paymaster)
+	}
+	return nil
+}
+
+// CheckConfig check wrapped token mint and burn config
+func (c *MintBurnConfig) CheckConfig() error {
+	for token, kind := range c.Tokens {
+		switch kind {
+		case MintBurnKindTokenFactory, MintBurnKindCW20:
+		default:
+			return fmt.Errorf("mint burn token '%v' has unknown kind '%v'", token, kind)
+		}
+	}
+	if c.TokenFactoryAuthorityPath != "" && strings.Count(c.TokenFactoryAuthorityPath, "%v") != 1 {
+		return fmt.Errorf("mint burn wrong 'TokenFactoryAuthorityPath' '%v'", c.TokenFactoryAuthorityPath)
+	}
+	return nil
+}
+
+// CheckConfig check light client config
+func (c *LightClientConfig) CheckConfig() error {
+	if len(c.RPCEndpoints) == 0 {
+		return errors.New("light client without 'RPCEndpoints'")
+	}
+	if c.TrustedHeight <= 0 {
+		return errors.New("light client 'TrustedHeight' must be positive")
+	}
+	if len(common.FromHex(c.TrustedHash)) != common.HashLength {
+		return fmt.Errorf("light client wrong 'TrustedHash' '%v'", c.TrustedHash)
+	}
+	if c.TrustingPeriod < 0 || c.MaxClockDrift < 0 {
+		return errors.New("light client has negative period")
+	}
+	return nil
+}
+
+// CheckConfig check ledger stream config
+func (c *LedgerStreamConfig) CheckConfig() error {
+	if len(c.WSServers) == 0 {
+		return errors.New("ledger stream without 'WSServers'")
+	}
+	switch c.OverflowPolicy {
+	case "", "block", "drop-oldest", "spill":
+	default:
+		return fmt.Errorf("ledger stream unknown 'OverflowPolicy' '%v'", c.OverflowPolicy)
+	}
+	if c.AckTimeout < 0 {
+		return errors.New("ledger stream 'AckTimeout' is negative")
+	}
+	if c.MaxMessageSize < 0 {
+		return errors.New("ledger stream 'MaxMessageSize' is negative")
+	}
+	if c.IdleTimeout < 0 {
+		return errors.New("ledger stream 'IdleTimeout' is negative")
+	}
+	return nil
+}
+
+// CheckConfig check retry policy config
+func (c *RetryPolicyConfig) CheckConfig() error {
+	if c.MaxAttempts < 0 || c.InitialInterval < 0 || c.MaxInterval < 0 ||
+		c.Multiplier < 0 || c.MaxElapsedTime < 0 || c.BudgetPerMinute < 0 {
+		return errors.New("retry policy has negative value")
+	}
+	if c.Jitter < 0 || c.Jitter > 1 {
+		return fmt.Errorf("retry policy 'Jitter' %v is not in range [0, 1]", c.Jitter)
+	}
+	return nil
+}
+
+// CheckConfig check rate oracle config
+func (c *RateOracleConfig) CheckConfig() error {
+	if c.TokenID == "" {
+		return errors.New("rate oracle without 'TokenID'")
+	}
+	if _, err := common.GetBigIntFromStr(c.FromChainID); err != nil {
+		return fmt.Errorf("rate oracle of %v: wrong 'FromChainID' '%v'", c.TokenID, c.FromChainID)
+	}
+	if _, err := common.GetBigIntFromStr(c.ToChainID); err != nil {
+		return fmt.Errorf("rate oracle of %v: wrong 'ToChainID' '%v'", c.TokenID, c.ToChainID)
+	}
+	return c.checkSource()
+}
+
+// checkSource check rate source and breaker config of rate oracle
+func (c *RateOracleConfig) checkSource() error {
+	switch c.Type {
+	case "chainlink":
+		if _, err := common.GetBigIntFromStr(c.OracleChainID); err != nil {
+			return fmt.Errorf("rate oracle of %v: wrong 'OracleChainID' '%v'", c.TokenID, c.OracleChainID)
+		}
+		if !common.IsHexAddress(c.Aggregator) {
+			return fmt.Errorf("rate oracle of %v: wrong 'Aggregator' '%v'", c.TokenID, c.Aggregator)
+		}
+	case "jsonapi":
+		if c.URL == "" || c.JSONPath == "" {
+			return fmt.Errorf("rate oracle of %v: miss 'URL' or 'JSONPath'", c.TokenID)
+		}
+	default:
+		return fmt.Errorf("rate oracle of %v: unknown type '%v'", c.TokenID, c.Type)
+	}
+	if c.MaxStaleness < 0 {
+		return fmt.Errorf("rate oracle of %v: 'MaxStaleness' is negative", c.TokenID)
+	}
+	if c.BreakerResetTime < 0 {
+		return fmt.Errorf("rate oracle of %v: 'BreakerResetTime' is negative", c.TokenID)
+	}
+	return nil
+}
+
+// CheckConfig check allowance monitor config
+func (c *AllowanceMonitorConfig) CheckConfig() error {
+	for _, allowance := range c.Allowances {
+		if !common.IsHexAddress(allowance.Token) {
+			return fmt.Errorf("wrong allowance token address '%v'", allowance.Token)
+		}
+		if !common.IsHexAddress(allowance.Spender) {
+			return fmt.Errorf("wrong allowance spender address '%v'", allowance.Spender)
+		}
+		minAllowance, err := common.GetBigIntFromStr(allowance.MinAllowance)
+		if err != nil || minAllowance.Sign() <= 0 {
+			return fmt.Errorf("wrong allowance 'MinAllowance' '%v'", allowance.MinAllowance)
+		}
+		allowance.minAllowance = minAllowance
+		allowance.approveAmount = nil
+		if allowance.ApproveAmount != "" {
+			approveAmount, err := common.GetBigIntFromStr(allowance.ApproveAmount)
+			if err != nil || approveAmount.Cmp(minAllowance) < 0 {
+				return fmt.Errorf("wrong allowance 'ApproveAmount' '%v' (must not be less than 'MinAllowance')", allowance.ApproveAmount)
+			}
+			allowance.approveAmount = approveAmount
+		}
+	}
+	return nil
+}
+
+// CheckConfig check deposit factory config
+func (c *DepositFactoryConfig) CheckConfig() error {
+	if !common.IsHexAddress(c.Factory) {
+		return fmt.Errorf("wrong deposit factory address '%v'", c.Factory)
+	}
+	if len(common.FromHex(c.ForwarderInitCodeHash)) != common.HashLength {
+		return fmt.Errorf("wrong deposit forwarder init code hash '%v'", c.ForwarderInitCodeHash)
+	}
+	if c.SweepInterval < 0 {
+		return errors.New("deposit 'SweepInterval' is negative")
+	}
 	return nil
 }
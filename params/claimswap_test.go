@@ -0,0 +1,38 @@
+package params
+
+import "testing"
+
+func TestIsClaimSwap(t *testing.T) {
+	err := SetExtraConfig(&ExtraConfig{
+		LocalChainConfig: map[string]*LocalChainConfig{
+			"56": {ClaimSwap: &ClaimSwapConfig{Escrow: "0x1111111111111111111111111111111111111111", TokenIDs: []string{"USDC"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = SetExtraConfig(&ExtraConfig{}) }()
+
+	for _, test := range []struct {
+		chainID, tokenID string
+		want             bool
+	}{
+		{"56", "USDC", true},
+		{"56", "usdc", true},
+		{"56", "USDT", false},
+		{"1", "USDC", false},
+	} {
+		if got := IsClaimSwap(test.chainID, test.tokenID); got != test.want {
+			t.Errorf("is claim swap of %v on chain %v got %v, want %v", test.tokenID, test.chainID, got, test.want)
+		}
+	}
+
+	cfg := GetClaimSwapConfig("56")
+	if period := cfg.GetClaimPeriod(); period != 7*86400 {
+		t.Errorf("default claim period got %v, want %v", period, 7*86400)
+	}
+	cfg.ClaimPeriod = 3600
+	if period := cfg.GetClaimPeriod(); period != 3600 {
+		t.Errorf("claim period got %v, want 3600", period)
+	}
+}
@@ -1,11 +1,17 @@
 package params
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/anyswap/CrossChain-Router/v3/common"
@@ -23,6 +29,9 @@ var CustomizeConfigFunc func(*RouterConfig)
 // IsTestMode used for testing
 var IsTestMode bool
 
+// ErrWatcherMode signing and sending are refused in watcher mode
+var ErrWatcherMode = errors.New("signing and sending are not supported in watcher mode")
+
 // IsReload is reloading config
 var IsReload bool
 
@@ -44,14 +53,17 @@ var (
 	callByContractWhitelist              = make(map[string]map[string]struct{}) // chainID -> caller
 	callByContractCodeHashWhitelist      = make(map[string]map[string]struct{}) // chainID -> codehash
 	bigValueWhitelist                    = make(map[string]map[string]struct{}) // tokenID -> caller
+	destMethodAllowlist                  = make(map[string]map[string]struct{}) // chainID -> method
 	autoSwapNonceEnabledChains           = make(map[string]struct{})
 	dynamicFeeTxEnabledChains            = make(map[string]struct{})
 	enableCheckTxBlockHashChains         = make(map[string]struct{})
 	enableCheckTxBlockIndexChains        = make(map[string]struct{})
 	disableUseFromChainIDInReceiptChains = make(map[string]struct{})
 	useFastMPCChains                     = make(map[string]struct{})
+	shadowModeChains                     = make(map[string]struct{})
 	increaseNonceWhenSendTxChains        = make(map[string]struct{})
 	dontCheckReceivedTokenIDs            = make(map[string]struct{})
+	rebasingTokenModes                   = make(map[string]string)
 	dontCheckBalanceTokenIDs             = make(map[string]struct{})
 	dontCheckTotalSupplyTokenIDs         = make(map[string]struct{})
 	checkTokenBalanceEnabledChains       = make(map[string]struct{})
@@ -94,6 +106,7 @@ type RouterServerConfig struct {
 	WaitTimeToReplace          int64             `toml:",omitempty" json:",omitempty"` // seconds
 	MaxReplaceCount            int               `toml:",omitempty" json:",omitempty"`
 	MaxReplaceDistance         uint64            `toml:",omitempty" json:",omitempty"`
+	SignExpiry                 int64             `toml:",omitempty" json:",omitempty"` // seconds
 	PlusGasPricePercentage     uint64            `toml:",omitempty" json:",omitempty"`
 	MaxPlusGasPricePercentage  uint64            `toml:",omitempty" json:",omitempty"`
 	MaxGasPriceFluctPercent    uint64            `toml:",omitempty" json:",omitempty"`
@@ -105,12 +118,466 @@ type RouterServerConfig struct {
 	SendTxLoopCount            map[string]int    `toml:",omitempty" json:",omitempty"` // key is chain ID
 	SendTxLoopInterval         map[string]int    `toml:",omitempty" json:",omitempty"` // key is chain ID
 
-	DefaultFee       map[string]string            `toml:",omitempty" json:",omitempty"` // key is chain ID
-	DefaultGasLimit  map[string]uint64            `toml:",omitempty" json:",omitempty"` // key is chain ID
-	MaxGasLimit      map[string]uint64            `toml:",omitempty" json:",omitempty"` // key is chain ID
-	MaxTokenGasLimit map[string]map[string]uint64 `toml:",omitempty" json:",omitempty"` // key is tokenID,chainID
+	DefaultFee         map[string]string            `toml:",omitempty" json:",omitempty"` // key is chain ID
+	FeeDenomPreference map[string][]string          `toml:",omitempty" json:",omitempty"` // key is chain ID
+	DefaultGasLimit    map[string]uint64            `toml:",omitempty" json:",omitempty"` // key is chain ID
+	MaxGasLimit        map[string]uint64            `toml:",omitempty" json:",omitempty"` // key is chain ID
+	MaxTokenGasLimit   map[string]map[string]uint64 `toml:",omitempty" json:",omitempty"` // key is tokenID,chainID
 
 	DynamicFeeTx map[string]*DynamicFeeTxConfig `toml:",omitempty" json:",omitempty"` // key is chain ID
+
+	TimeLock *TimeLockConfig `toml:",omitempty" json:",omitempty"`
+	SwapGC   *SwapGCConfig   `toml:",omitempty" json:",omitempty"`
+
+	IntegrityCheck *IntegrityCheckConfig `toml:",omitempty" json:",omitempty"`
+	SwapStats      *SwapStatsConfig      `toml:",omitempty" json:",omitempty"`
+	MPCUsage       *MPCUsageConfig       `toml:",omitempty" json:",omitempty"`
+	DuplicateCheck *DuplicateCheckConfig `toml:",omitempty" json:",omitempty"`
+	Quarantine     *QuarantineConfig     `toml:",omitempty" json:",omitempty"`
+
+	ApprovalHooks []*ApprovalHookConfig `toml:",omitempty" json:",omitempty"`
+	Webhooks      []*WebhookConfig      `toml:",omitempty" json:",omitempty"`
+
+	MaxInFlightSwaps map[string]int64 `toml:",omitempty" json:",omitempty"` // key is fromChainID:toChainID
+}
+
+// SwapStatsConfig time bucketed stats of finalized swaps
+type SwapStatsConfig struct {
+	Interval  int64 `toml:",omitempty" json:",omitempty"` // seconds
+	Retention int64 `toml:",omitempty" json:",omitempty"` // seconds
+	BatchSize int64 `toml:",omitempty" json:",omitempty"`
+}
+
+// GetSwapStatsConfig get swap stats config (nil means stats job is disabled)
+func GetSwapStatsConfig() *SwapStatsConfig {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return nil
+	}
+	return serverCfg.SwapStats
+}
+
+// GetInterval get stats interval (seconds, default 10 minutes)
+func (c *SwapStatsConfig) GetInterval() int64 {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+	return 600
+}
+
+// GetRetention get retention of stats (seconds, default 90 days)
+func (c *SwapStatsConfig) GetRetention() int64 {
+	if c.Retention > 0 {
+		return c.Retention
+	}
+	return 90 * 86400
+}
+
+// GetBatchSize get max swaps read in one query, all swaps are read page by page (default 100000)
+func (c *SwapStatsConfig) GetBatchSize() int64 {
+	if c.BatchSize > 0 {
+		return c.BatchSize
+	}
+	return 100000
+}
+
+// DuplicateCheckConfig recheck of stable swaps with more than one swap txs,
+// to detect the duplicate swap txs which land on chain after the swap is stable.
+type DuplicateCheckConfig struct {
+	Interval   int64 `toml:",omitempty" json:",omitempty"` // seconds
+	ScanWindow int64 `toml:",omitempty" json:",omitempty"` // seconds
+	BatchSize  int64 `toml:",omitempty" json:",omitempty"`
+}
+
+// GetDuplicateCheckConfig get duplicate check config (nil means recheck is disabled)
+func GetDuplicateCheckConfig() *DuplicateCheckConfig {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return nil
+	}
+	return serverCfg.DuplicateCheck
+}
+
+// GetInterval get check interval (seconds, default 10 minutes)
+func (c *DuplicateCheckConfig) GetInterval() int64 {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+	return 600
+}
+
+// GetScanWindow get scan window of swaps stable recently (seconds, default 3 days)
+func (c *DuplicateCheckConfig) GetScanWindow() int64 {
+	if c.ScanWindow > 0 {
+		return c.ScanWindow
+	}
+	return 3 * 86400
+}
+
+// GetBatchSize get max swaps checked in one round (default 1000)
+func (c *DuplicateCheckConfig) GetBatchSize() int64 {
+	if c.BatchSize > 0 {
+		return c.BatchSize
+	}
+	return 1000
+}
+
+// MPCUsageConfig time bucketed accounting of mpc sign requests per chain,
+// a spike is alerted when the sign or failure count of the current bucket of a chain
+// exceeds SpikeRatio times its average of the previous BaselineBuckets buckets.
+type MPCUsageConfig struct {
+	BucketSize      int64   `toml:",omitempty" json:",omitempty"` // seconds
+	Retention       int64   `toml:",omitempty" json:",omitempty"` // seconds
+	CheckInterval   int64   `toml:",omitempty" json:",omitempty"` // seconds
+	BaselineBuckets int64   `toml:",omitempty" json:",omitempty"`
+	SpikeRatio      float64 `toml:",omitempty" json:",omitempty"`
+	MinSpikeCount   int64   `toml:",omitempty" json:",omitempty"`
+}
+
+// GetMPCUsageConfig get mpc usage config (nil means accounting is disabled)
+func GetMPCUsageConfig() *MPCUsageConfig {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return nil
+	}
+	return serverCfg.MPCUsage
+}
+
+// GetBucketSize get bucket size (seconds, default 1 hour)
+func (c *MPCUsageConfig) GetBucketSize() int64 {
+	if c.BucketSize > 0 {
+		return c.BucketSize
+	}
+	return 3600
+}
+
+// GetRetention get retention of usage (seconds, default 30 days)
+func (c *MPCUsageConfig) GetRetention() int64 {
+	if c.Retention > 0 {
+		return c.Retention
+	}
+	return 30 * 86400
+}
+
+// GetCheckInterval get interval of spike checking (seconds, default 5 minutes)
+func (c *MPCUsageConfig) GetCheckInterval() int64 {
+	if c.CheckInterval > 0 {
+		return c.CheckInterval
+	}
+	return 300
+}
+
+// GetBaselineBuckets get count of previous buckets as baseline (default 24)
+func (c *MPCUsageConfig) GetBaselineBuckets() int64 {
+	if c.BaselineBuckets > 0 {
+		return c.BaselineBuckets
+	}
+	return 24
+}
+
+// GetSpikeRatio get ratio to baseline regarded as spike (default 3)
+func (c *MPCUsageConfig) GetSpikeRatio() float64 {
+	if c.SpikeRatio > 0 {
+		return c.SpikeRatio
+	}
+	return 3
+}
+
+// GetMinSpikeCount get min count of bucket regarded as spike (default 100)
+func (c *MPCUsageConfig) GetMinSpikeCount() int64 {
+	if c.MinSpikeCount > 0 {
+		return c.MinSpikeCount
+	}
+	return 100
+}
+
+// ApprovalHookConfig external approval service called in turn before swaps are signed.
+// the service decides to approve, block or hold the swap. if the call fails or times out,
+// the swap is approved by this hook if FailOpen, otherwise it is held and asked again later.
+type ApprovalHookConfig struct {
+	Name         string
+	URL          string
+	Headers      map[string]string `toml:",omitempty" json:",omitempty"`
+	Timeout      int               `toml:",omitempty" json:",omitempty"` // seconds
+	HoldInterval int64             `toml:",omitempty" json:",omitempty"` // seconds
+	FailOpen     bool              `toml:",omitempty" json:",omitempty"`
+}
+
+// GetApprovalHooks get approval hooks config
+func GetApprovalHooks() []*ApprovalHookConfig {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return nil
+	}
+	return serverCfg.ApprovalHooks
+}
+
+// GetTimeout get timeout of calling the hook (seconds, default 10)
+func (c *ApprovalHookConfig) GetTimeout() int {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return 10
+}
+
+// GetHoldInterval get interval to ask again of held swaps (seconds, default 5 minutes)
+func (c *ApprovalHookConfig) GetHoldInterval() int64 {
+	if c.HoldInterval > 0 {
+		return c.HoldInterval
+	}
+	return 300
+}
+
+// WebhookConfig webhook notification of swap events to a subscriber.
+// the swap events (all if Events is empty) are posted to URL one by one in time order,
+// the body is json of sdk.WebhookEvent signed by Secrets in the sdk.HeaderWebhookSignature
+// header (every secret signs, so that the subscriber can rotate its secret).
+// the position of the last delivered event is persisted, a failed delivery is retried
+// every Interval seconds, so events are delivered at least once. a new webhook starts
+// from the events after it is added.
+type WebhookConfig struct {
+	Name      string
+	URL       string
+	Secrets   []string
+	Events    []string `toml:",omitempty" json:",omitempty"`
+	Timeout   int      `toml:",omitempty" json:",omitempty"` // seconds
+	Interval  int64    `toml:",omitempty" json:",omitempty"` // seconds
+	BatchSize int64    `toml:",omitempty" json:",omitempty"`
+}
+
+// GetWebhooks get webhooks config
+func GetWebhooks() []*WebhookConfig {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return nil
+	}
+	return serverCfg.Webhooks
+}
+
+// GetTimeout get timeout of posting to the webhook (seconds, default 10)
+func (c *WebhookConfig) GetTimeout() int {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return 10
+}
+
+// GetInterval get interval of delivery rounds (seconds, default 10)
+func (c *WebhookConfig) GetInterval() int64 {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+	return 10
+}
+
+// GetBatchSize get max events delivered in a round (default 100)
+func (c *WebhookConfig) GetBatchSize() int64 {
+	if c.BatchSize > 0 {
+		return c.BatchSize
+	}
+	return 100
+}
+
+// IntegrityCheckConfig consistency audit config of swap and swap result collections
+type IntegrityCheckConfig struct {
+	Interval    int64 `toml:",omitempty" json:",omitempty"` // seconds
+	ScanWindow  int64 `toml:",omitempty" json:",omitempty"` // seconds
+	GracePeriod int64 `toml:",omitempty" json:",omitempty"` // seconds
+	BatchSize   int64 `toml:",omitempty" json:",omitempty"`
+	AutoRepair  bool  `toml:",omitempty" json:",omitempty"`
+}
+
+// GetIntegrityCheckConfig get integrity check config (nil means check is disabled)
+func GetIntegrityCheckConfig() *IntegrityCheckConfig {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return nil
+	}
+	return serverCfg.IntegrityCheck
+}
+
+// GetInterval get check interval (seconds, default 1 hour)
+func (c *IntegrityCheckConfig) GetInterval() int64 {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+	return 3600
+}
+
+// GetScanWindow get scan window of records updated recently (seconds, default 7 days)
+func (c *IntegrityCheckConfig) GetScanWindow() int64 {
+	if c.ScanWindow > 0 {
+		return c.ScanWindow
+	}
+	return 7 * 86400
+}
+
+// GetGracePeriod get grace period of records in processing (seconds, default 10 minutes)
+func (c *IntegrityCheckConfig) GetGracePeriod() int64 {
+	if c.GracePeriod > 0 {
+		return c.GracePeriod
+	}
+	return 600
+}
+
+// GetBatchSize get max records of each collection checked in one round (default 5000)
+func (c *IntegrityCheckConfig) GetBatchSize() int64 {
+	if c.BatchSize > 0 {
+		return c.BatchSize
+	}
+	return 5000
+}
+
+// GetMaxInFlightSwaps get cap of in-flight swaps of chain pair (0 means no cap)
+func GetMaxInFlightSwaps(fromChainID, toChainID string) int64 {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return 0
+	}
+	return serverCfg.MaxInFlightSwaps[GetTokenRouteKey(fromChainID, toChainID)]
+}
+
+// ConfirmationTier required source confirmations of swaps with value not above `MaxValue`.
+// tiers are in ascending order of value, and the last tier may have empty `MaxValue`
+// to match all larger swaps.
+type ConfirmationTier struct {
+	MaxValue      string `toml:",omitempty" json:",omitempty"` // in token units
+	Confirmations uint64
+}
+
+// GetConfirmationTiers get value tiered confirmations of token on chain pair
+func GetConfirmationTiers(fromChainID, toChainID, tokenID string) []*ConfirmationTier {
+	extraCfg := GetExtraConfig()
+	if extraCfg == nil || len(extraCfg.ConfirmationTiers) == 0 {
+		return nil
+	}
+	return extraCfg.ConfirmationTiers[GetTokenRouteKey(fromChainID, toChainID)][tokenID]
+}
+
+// QuarantineConfig re-verification config of quarantined swaps.
+// quarantined swaps are cross verified against the gateway endpoints of the
+// source chain, at least Quorum endpoints must agree (default all endpoints).
+// swaps still not verified NotFoundTimeout seconds after quarantined are failed.
+type QuarantineConfig struct {
+	Quorum          int   `toml:",omitempty" json:",omitempty"`
+	NotFoundTimeout int64 `toml:",omitempty" json:",omitempty"` // seconds
+}
+
+// GetQuarantineQuorum get quorum of gateway endpoints agreeing on quarantined
+// swaps, it is capped at the number of endpoints (non positive means all)
+func GetQuarantineQuorum(endpoints int) int {
+	if serverCfg := GetRouterServerConfig(); serverCfg != nil && serverCfg.Quarantine != nil {
+		if quorum := serverCfg.Quarantine.Quorum; quorum > 0 && quorum < endpoints {
+			return quorum
+		}
+	}
+	return endpoints
+}
+
+// GetQuarantineNotFoundTimeout get timeout (seconds) of verifying quarantined swaps (default 3 days)
+func GetQuarantineNotFoundTimeout() int64 {
+	if serverCfg := GetRouterServerConfig(); serverCfg != nil && serverCfg.Quarantine != nil {
+		if timeout := serverCfg.Quarantine.NotFoundTimeout; timeout > 0 {
+			return timeout
+		}
+	}
+	return 3 * 86400
+}
+
+// SwapGCConfig garbage collection config of swap records
+type SwapGCConfig struct {
+	Interval      int64 `toml:",omitempty" json:",omitempty"` // seconds
+	Retention     int64 `toml:",omitempty" json:",omitempty"` // seconds
+	MaxOldSwapTxs int   `toml:",omitempty" json:",omitempty"`
+	BatchSize     int64 `toml:",omitempty" json:",omitempty"`
+}
+
+// GetSwapGCConfig get swap gc config (nil means gc is disabled)
+func GetSwapGCConfig() *SwapGCConfig {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return nil
+	}
+	return serverCfg.SwapGC
+}
+
+// GetInterval get gc interval (seconds, default 1 hour)
+func (c *SwapGCConfig) GetInterval() int64 {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+	return 3600
+}
+
+// GetRetention get how long (seconds) records are kept after finalized (default 1 day)
+func (c *SwapGCConfig) GetRetention() int64 {
+	if c.Retention > 0 {
+		return c.Retention
+	}
+	return 86400
+}
+
+// GetOldSwapTxsRetention get how long (seconds) old swap txs are kept after finalized,
+// it is at least the scan window of duplicate delivery check which needs the old swap txs.
+func (c *SwapGCConfig) GetOldSwapTxsRetention() int64 {
+	retention := c.GetRetention()
+	if dupCfg := GetDuplicateCheckConfig(); dupCfg != nil && dupCfg.GetScanWindow() > retention {
+		return dupCfg.GetScanWindow()
+	}
+	return retention
+}
+
+// GetMaxOldSwapTxs get max length of old swap txs of finalized swaps (default 20)
+func (c *SwapGCConfig) GetMaxOldSwapTxs() int {
+	if c.MaxOldSwapTxs > 0 {
+		return c.MaxOldSwapTxs
+	}
+	return 20
+}
+
+// GetBatchSize get max count of records processed in one gc round (default 1000)
+func (c *SwapGCConfig) GetBatchSize() int64 {
+	if c.BatchSize > 0 {
+		return c.BatchSize
+	}
+	return 1000
+}
+
+// TimeLockConfig time lock config of large withdrawals.
+// swaps with value above the threshold are delayed and can be vetoed by admins.
+type TimeLockConfig struct {
+	DelaySeconds int64             `toml:",omitempty" json:",omitempty"`
+	Thresholds   map[string]string `toml:",omitempty" json:",omitempty"` // key is tokenID, value is in token units
+}
+
+// GetTimeLockDelay get enforced delay (seconds) of time locked swaps (default 1 hour)
+func GetTimeLockDelay() int64 {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil || serverCfg.TimeLock == nil {
+		return 0
+	}
+	if serverCfg.TimeLock.DelaySeconds > 0 {
+		return serverCfg.TimeLock.DelaySeconds
+	}
+	return 3600
+}
+
+// GetTimeLockThreshold get time lock threshold (in token units) of tokenID
+func GetTimeLockThreshold(tokenID string) string {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil || serverCfg.TimeLock == nil {
+		return ""
+	}
+	return serverCfg.TimeLock.Thresholds[tokenID]
+}
+
+// GetSignExpiry get validity period (seconds) of swap sign requests (0 means no expiry)
+func GetSignExpiry() int64 {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return 0
+	}
+	return serverCfg.SignExpiry
 }
 
 // RouterOracleConfig only for oracle
@@ -129,6 +596,9 @@ type RouterConfig struct {
 	SwapType    string
 	SwapSubType string
 
+	// DeploymentSalt isolates multiple deployments running against shared chains
+	DeploymentSalt string `toml:",omitempty" json:",omitempty"`
+
 	Onchain *OnchainConfig
 	*GatewayConfigs
 
@@ -166,6 +636,7 @@ type ExtraConfig struct {
 	UsePendingBalance     bool `toml:",omitempty" json:",omitempty"`
 	DontPanicInInitRouter bool `toml:",omitempty" json:",omitempty"`
 	DontCheckInInitRouter bool `toml:",omitempty" json:",omitempty"`
+	EnableStartupCheck    bool `toml:",omitempty" json:",omitempty"`
 
 	MinReserveFee    map[string]uint64 `toml:",omitempty" json:",omitempty"`
 	BaseFeePercent   map[string]int64  `toml:",omitempty" json:",omitempty"` // key is chain ID
@@ -177,12 +648,16 @@ type ExtraConfig struct {
 	CallByContractWhitelist         map[string][]string `toml:",omitempty" json:",omitempty"` // chainID -> whitelist
 	CallByContractCodeHashWhitelist map[string][]string `toml:",omitempty" json:",omitempty"` // chainID -> whitelist
 	BigValueWhitelist               map[string][]string `toml:",omitempty" json:",omitempty"` // tokenID -> whitelist
+	TokenRouteWhitelist             map[string][]string `toml:",omitempty" json:",omitempty"` // tokenID -> fromChainID:toChainID
+	DestMethodAllowlist             map[string][]string `toml:",omitempty" json:",omitempty"` // chainID -> method selectors or message types
+	RebasingTokenModes              map[string]string   `toml:",omitempty" json:",omitempty"` // tokenID -> accounting mode
 
 	DynamicFeeTxEnabledChains            []string `toml:",omitempty" json:",omitempty"`
 	EnableCheckTxBlockHashChains         []string `toml:",omitempty" json:",omitempty"`
 	EnableCheckTxBlockIndexChains        []string `toml:",omitempty" json:",omitempty"`
 	DisableUseFromChainIDInReceiptChains []string `toml:",omitempty" json:",omitempty"`
 	UseFastMPCChains                     []string `toml:",omitempty" json:",omitempty"`
+	ShadowModeChains                     []string `toml:",omitempty" json:",omitempty"`
 	IncreaseNonceWhenSendTxChains        []string `toml:",omitempty" json:",omitempty"`
 	DontCheckReceivedTokenIDs            []string `toml:",omitempty" json:",omitempty"`
 	DontCheckBalanceTokenIDs             []string `toml:",omitempty" json:",omitempty"`
@@ -199,6 +674,96 @@ type ExtraConfig struct {
 	SpecialFlags map[string]string `toml:",omitempty" json:",omitempty"`
 
 	AttestationServer string `toml:",omitempty" json:",omitempty"`
+
+	RateOracles []*RateOracleConfig `toml:",omitempty" json:",omitempty"`
+
+	DefaultRetryPolicy *RetryPolicyConfig `toml:",omitempty" json:",omitempty"`
+
+	ConfirmationTiers map[string]map[string][]*ConfirmationTier `toml:",omitempty" json:",omitempty"` // key is fromChainID:toChainID,tokenID
+
+	FeatureFlags map[string]bool `toml:",omitempty" json:",omitempty"` // flag -> enabled
+}
+
+// RetryPolicyConfig retry policy of rpc calls in bridges, zero fields use defaults
+type RetryPolicyConfig struct {
+	MaxAttempts     int     `toml:",omitempty" json:",omitempty"`
+	InitialInterval int64   `toml:",omitempty" json:",omitempty"` // milliseconds
+	MaxInterval     int64   `toml:",omitempty" json:",omitempty"` // milliseconds
+	Multiplier      float64 `toml:",omitempty" json:",omitempty"`
+	Jitter          float64 `toml:",omitempty" json:",omitempty"` // in [0, 1]
+	MaxElapsedTime  int64   `toml:",omitempty" json:",omitempty"` // milliseconds
+	BudgetPerMinute int     `toml:",omitempty" json:",omitempty"` // max retries per minute of chain
+}
+
+// GetRetryPolicyConfig get retry policy config of chain (nil if not configed)
+func GetRetryPolicyConfig(chainID string) *RetryPolicyConfig {
+	if c := GetLocalChainConfig(chainID).RetryPolicy; c != nil {
+		return c
+	}
+	if GetExtraConfig() != nil {
+		return GetExtraConfig().DefaultRetryPolicy
+	}
+	return nil
+}
+
+// RateOracleConfig conversion rate oracle of cross-asset route (source and destination assets are not 1:1).
+// the swap value is multiplied by the rate after deducting fee and converting decimals.
+type RateOracleConfig struct {
+	TokenID     string
+	FromChainID string
+	ToChainID   string
+
+	// rate source, "chainlink": latestRoundData of Aggregator on OracleChainID,
+	// "jsonapi": number at JSONPath (dot separated keys) of the response of URL.
+	Type          string
+	OracleChainID string `toml:",omitempty" json:",omitempty"`
+	Aggregator    string `toml:",omitempty" json:",omitempty"`
+	URL           string `toml:",omitempty" json:",omitempty"`
+	JSONPath      string `toml:",omitempty" json:",omitempty"`
+
+	MaxStaleness     int64  `toml:",omitempty" json:",omitempty"` // seconds
+	MaxDeviation     uint64 `toml:",omitempty" json:",omitempty"` // percent
+	BreakerResetTime int64  `toml:",omitempty" json:",omitempty"` // seconds
+}
+
+// GetMaxStaleness get max age (seconds) of usable rate (default 10 minutes)
+func (c *RateOracleConfig) GetMaxStaleness() int64 {
+	if c.MaxStaleness > 0 {
+		return c.MaxStaleness
+	}
+	return 600
+}
+
+// GetMaxDeviation get max deviation (percent) between rate updates and between router nodes (default 5%)
+func (c *RateOracleConfig) GetMaxDeviation() uint64 {
+	if c.MaxDeviation > 0 {
+		return c.MaxDeviation
+	}
+	return 5
+}
+
+// GetBreakerResetTime get the time (seconds) the rate must be stable after the circuit breaker
+// is tripped before the breaker is reset with the new rate (default 30 minutes)
+func (c *RateOracleConfig) GetBreakerResetTime() int64 {
+	if c.BreakerResetTime > 0 {
+		return c.BreakerResetTime
+	}
+	return 1800
+}
+
+// GetRateOracleConfig get rate oracle config of route (nil if the route is 1:1)
+func GetRateOracleConfig(tokenID, fromChainID, toChainID string) *RateOracleConfig {
+	if GetExtraConfig() == nil {
+		return nil
+	}
+	for _, c := range GetExtraConfig().RateOracles {
+		if strings.EqualFold(c.TokenID, tokenID) &&
+			c.FromChainID == fromChainID &&
+			c.ToChainID == toChainID {
+			return c
+		}
+	}
+	return nil
 }
 
 // LocalChainConfig local chain config
@@ -216,11 +781,391 @@ type LocalChainConfig struct {
 	ChargeFeeOnDestChain   map[string][]string `toml:",omitempty" json:",omitempty"`
 	FeeReceiverOnDestChain string              `toml:",omitempty" json:",omitempty"`
 
+	DepositFactory *DepositFactoryConfig `toml:",omitempty" json:",omitempty"`
+	HeadWatchdog   *HeadWatchdogConfig   `toml:",omitempty" json:",omitempty"`
+	BlockScan      *BlockScanConfig      `toml:",omitempty" json:",omitempty"`
+
+	ReceiptCrossVerify *ReceiptCrossVerifyConfig `toml:",omitempty" json:",omitempty"`
+	GatewayCheck       *GatewayCheckConfig       `toml:",omitempty" json:",omitempty"`
+	RetryPolicy        *RetryPolicyConfig        `toml:",omitempty" json:",omitempty"`
+
+	// bridge implementation (eg. 'eth', 'cosmos') running in shadow mode (see ShadowModeChains),
+	// default is a new instance of the production implementation
+	ShadowImplementation string `toml:",omitempty" json:",omitempty"`
+
+	// message type urls allowed in deposit txs (cosmos chains)
+	AllowedMsgTypes []string `toml:",omitempty" json:",omitempty"`
+
+	// watch on-chain params affecting bridge operation (cosmos chains)
+	WatchChainParams bool `toml:",omitempty" json:",omitempty"`
+
+	// pathfinding of issued currency deliveries (ripple)
+	PathFind *PathFindConfig `toml:",omitempty" json:",omitempty"`
+
+	// deliver issued currencies by checks to receivers without trust line (ripple)
+	CheckPayout *CheckPayoutConfig `toml:",omitempty" json:",omitempty"`
+
+	// LastLedgerSequence of built txs is current ledger plus this window (ripple)
+	LastLedgerWindow uint64 `toml:",omitempty" json:",omitempty"`
+
+	// roles of gateway endpoints, url -> 'rippled' or 'clio' (ripple),
+	// the roles of unlisted endpoints are detected by 'server_info'
+	EndpointRoles map[string]string `toml:",omitempty" json:",omitempty"`
+
+	// websocket stream of validated deposits registered ahead of ledger scanning (ripple)
+	LedgerStream *LedgerStreamConfig `toml:",omitempty" json:",omitempty"`
+
+	// light client verification of big value deposits (cosmos chains)
+	LightClient *LightClientConfig `toml:",omitempty" json:",omitempty"`
+
+	// resending swap txs with re-simulated gas after out of gas failures (cosmos chains)
+	GasRetry *GasRetryConfig `toml:",omitempty" json:",omitempty"`
+
+	// execute swapouts through erc-4337 bundler (evm chains)
+	AccountAbstraction *AccountAbstractionConfig `toml:",omitempty" json:",omitempty"`
+
+	// two-phase swaps finalized by the recipient (evm chains)
+	ClaimSwap *ClaimSwapConfig `toml:",omitempty" json:",omitempty"`
+
+	// allowances of the mpc to spenders of underlying tokens (evm chains)
+	AllowanceMonitor *AllowanceMonitorConfig `toml:",omitempty" json:",omitempty"`
+
+	// token contract address changes (evm chains), tokenID -> migration
+	TokenMigrations map[string]*TokenMigrationConfig `toml:",omitempty" json:",omitempty"`
+
+	// addresses emitting router logs of proxy or diamond deployments (evm chains),
+	// router contract -> accepted emitters
+	RouterEmitters map[string][]string `toml:",omitempty" json:",omitempty"`
+
+	// fair scheduling of swap tasks across tokens to the chain
+	SwapFairness *SwapFairnessConfig `toml:",omitempty" json:",omitempty"`
+
+	// randomized delay of swapouts to the chain against mev and timing attacks
+	SendDelay *SendDelayConfig `toml:",omitempty" json:",omitempty"`
+
+	// gas limits of swap txs learned from receipts of stable swap txs (evm chains)
+	GasLearning *GasLearningConfig `toml:",omitempty" json:",omitempty"`
+
+	// EIP-712 signed authorizations of swap txs required by router contracts (evm chains)
+	SwapAuthorization *SwapAuthorizationConfig `toml:",omitempty" json:",omitempty"`
+
+	// minimum swap fees of swaps to the chain track the realized delivery costs
+	DeliveryFee *DeliveryFeeConfig `toml:",omitempty" json:",omitempty"`
+
+	// cost/benefit guard of replacing swap txs to the chain
+	ReplaceGuard *ReplaceGuardConfig `toml:",omitempty" json:",omitempty"`
+
+	// nodes of the chain prune receipts and states quickly (evm chains)
+	ShallowArchive *ShallowArchiveConfig `toml:",omitempty" json:",omitempty"`
+
+	// wrapped tokens minted to receivers and burned by the mpc (cosmos chains)
+	MintBurn *MintBurnConfig `toml:",omitempty" json:",omitempty"`
+
+	// max swaps in the signing and sending pipeline to the chain,
+	// 0 means signing and sending sequentially (not applied in parallel swap mode)
+	SwapPipelineDepth int `toml:",omitempty" json:",omitempty"`
+
+	// feature flags of the chain, flag -> enabled (override the deployment flags)
+	FeatureFlags map[string]bool `toml:",omitempty" json:",omitempty"`
+
+	// swapouts to the chain wait for manual approval of admin (approveswap)
+	ManualApproval bool `toml:",omitempty" json:",omitempty"`
+
 	forbidSwapoutTokenIDMap map[string]struct{}
 
 	lock *sync.Mutex
 }
 
+// DepositFactoryConfig per-user deposit address config.
+// deposit addresses are CREATE2 addresses of forwarder contracts deployed by the factory,
+// users can deposit to them by plain transfers and the funds are swept to the mpc address.
+type DepositFactoryConfig struct {
+	Factory               string
+	ForwarderInitCodeHash string
+	SweepInterval         int64 `toml:",omitempty" json:",omitempty"` // seconds
+}
+
+// HeadWatchdogConfig chain head lag watchdog config.
+// verification of the chain is paused when all gateway endpoints stall,
+// or their reported heads diverge beyond tolerance.
+type HeadWatchdogConfig struct {
+	StallTimeout  int64  `toml:",omitempty" json:",omitempty"` // seconds
+	MaxDivergence uint64 `toml:",omitempty" json:",omitempty"` // blocks
+}
+
+// BlockScanConfig block (or ledger) scanning config.
+// stable blocks are scanned for router txs which are registered as swaps,
+// the scanned height is kept in the shared scan checkpoint. on startup the
+// heights lagging behind latest more than MaxLag are recorded as gap and
+// backfilled after catching up, failed heights are recorded as gaps too.
+type BlockScanConfig struct {
+	MaxLag         uint64 `toml:",omitempty" json:",omitempty"` // blocks
+	BlocksPerRound uint64 `toml:",omitempty" json:",omitempty"`
+}
+
+// ReceiptCrossVerifyConfig receipt cross verification config.
+// receipts of big value deposits are fetched from all gateway endpoints,
+// and verification is deferred unless at least MinProviders of them agree.
+type ReceiptCrossVerifyConfig struct {
+	MinProviders int `toml:",omitempty" json:",omitempty"`
+}
+
+// GatewayCheckConfig gateway endpoint check config (evm chains).
+// chain ID and the canonical block hash ForkCheckDepth blocks below the
+// lowest head are checked on startup and periodically, endpoints disagreeing
+// with the config or the quorum of peers are not used to verify or send txs.
+type GatewayCheckConfig struct {
+	ForkCheckDepth uint64 `toml:",omitempty" json:",omitempty"` // blocks
+}
+
+// PathFindConfig pathfinding config of issued currency deliveries.
+// the payment uses the found paths with SendMax bounded by the
+// source amount plus SlippagePercent, the path quality is checked
+// again right before signing.
+type PathFindConfig struct {
+	SlippagePercent float64 `toml:",omitempty" json:",omitempty"`
+}
+
+// CheckPayoutConfig check based delivery config of issued currencies.
+// swapins to receivers which have no trust line of the currency are delivered
+// by CheckCreate instead of failing the payment, the receiver cashes the check
+// after setting the trust line. the check expires after Expiration seconds,
+// and the expired check is cancelled so that it can never be cashed later.
+type CheckPayoutConfig struct {
+	Expiration int64 `toml:",omitempty" json:",omitempty"` // seconds
+}
+
+// AllowanceMonitorConfig allowance monitor config (evm chains).
+// allowances of the mpc to spenders pulling underlying tokens by transferFrom
+// are checked periodically, low allowances are alerted and re-approved to
+// ApproveAmount (default max uint256) if AutoApprove is true. tokens like USDT
+// which reject changing a nonzero allowance are approved zero first if ZeroFirst.
+type AllowanceMonitorConfig struct {
+	AutoApprove bool `toml:",omitempty" json:",omitempty"`
+	Allowances  []*AllowanceConfig
+}
+
+// AllowanceConfig monitored allowance of token to spender.
+// amounts are in the smallest unit of the token.
+type AllowanceConfig struct {
+	Token         string
+	Spender       string
+	MinAllowance  string
+	ApproveAmount string `toml:",omitempty" json:",omitempty"`
+	ZeroFirst     bool   `toml:",omitempty" json:",omitempty"`
+
+	minAllowance  *big.Int
+	approveAmount *big.Int
+}
+
+// LedgerStreamConfig websocket stream config of validated deposits (ripple).
+// payments to the router accounts are streamed from WSServers (tried in turn)
+// and registered ahead of ledger scanning, they are redelivered if not
+// registered in AckTimeout seconds. OverflowPolicy ('block', 'drop-oldest'
+// or 'spill' to SpillDir) handles ledger messages if the consumer falls behind.
+// MaxMessageSize (bytes, default 16MB) limits the messages read from the servers,
+// it is shared by the websocket connections of the process.
+// the stream switches to the next server if no data is received in IdleTimeout
+// seconds (default 30), eg. half-open connections which still answer pings.
+type LedgerStreamConfig struct {
+	WSServers      []string
+	OverflowPolicy string `toml:",omitempty" json:",omitempty"`
+	SpillDir       string `toml:",omitempty" json:",omitempty"`
+	AckTimeout     int64  `toml:",omitempty" json:",omitempty"` // seconds
+	MaxMessageSize int64  `toml:",omitempty" json:",omitempty"` // bytes
+	IdleTimeout    int64  `toml:",omitempty" json:",omitempty"` // seconds
+}
+
+// roles of ripple gateway endpoints
+const (
+	EndpointRoleRippled = "rippled"
+	EndpointRoleClio    = "clio"
+)
+
+// LightClientConfig tendermint light client config.
+// the block containing a big value deposit is verified by commit signatures
+// against the validator set trusted from TrustedHeight and TrustedHash,
+// TrustingPeriod should be shorter than the unbonding period of the chain.
+type LightClientConfig struct {
+	RPCEndpoints   []string `toml:",omitempty" json:",omitempty"` // tendermint rpc
+	TrustedHeight  int64    `toml:",omitempty" json:",omitempty"`
+	TrustedHash    string   `toml:",omitempty" json:",omitempty"`
+	TrustingPeriod int64    `toml:",omitempty" json:",omitempty"` // seconds
+	MaxClockDrift  int64    `toml:",omitempty" json:",omitempty"` // seconds
+}
+
+// GasRetryConfig out of gas retry config.
+// swap txs failed to broadcast for out of gas or insufficient fee are
+// re-simulated with a larger gas adjustment, rebuilt and resent,
+// the adjustment of the n-th attempt is GasAdjustment plus (n-1) AdjustmentStep.
+type GasRetryConfig struct {
+	MaxAttempts    int     `toml:",omitempty" json:",omitempty"`
+	GasAdjustment  float64 `toml:",omitempty" json:",omitempty"`
+	AdjustmentStep float64 `toml:",omitempty" json:",omitempty"`
+}
+
+// mint burn token kinds
+const (
+	MintBurnKindTokenFactory = "tokenfactory"
+	MintBurnKindCW20         = "cw20"
+)
+
+// MintBurnConfig wrapped token mint and burn config.
+// swap txs of the tokens mint to the receivers instead of sending from the mpc,
+// and the tokens held by the mpc are burned in the same swap tx.
+// the mpc must hold the admin (tokenfactory) or minter (cw20) role of the tokens.
+type MintBurnConfig struct {
+	Tokens map[string]string `toml:",omitempty" json:",omitempty"` // denom or cw20 contract -> kind
+
+	// rest path of tokenfactory denom authority metadata, '%v' is the denom
+	TokenFactoryAuthorityPath string `toml:",omitempty" json:",omitempty"`
+}
+
+// SwapFairnessConfig fair scheduling config of swap tasks.
+// swap tasks to the chain are queued by tokenID and taken in weighted
+// round-robin order, each token takes at most its weight (default 1) of
+// tasks in turn, so that a flooding token can not starve the others.
+type SwapFairnessConfig struct {
+	TokenWeights map[string]int `toml:",omitempty" json:",omitempty"` // tokenID -> weight
+}
+
+// SendDelayConfig randomized send delay config of swapouts.
+// swapouts to the chain are deferred by a random delay before building,
+// the delay is at most MaxDelay seconds weighted by the swap value relative
+// to the big value threshold of the token, so larger swaps wait longer.
+type SendDelayConfig struct {
+	MaxDelay int64 // seconds
+}
+
+// GasLearningConfig gas limit learning config of swap txs.
+// gas used by the latest Samples stable swap txs are kept per token and swap method,
+// once there are at least MinSamples, the gas limit is the Percentile of the samples
+// plus MarginPercent (instead of the estimated gas plus 30% and the default gas limit),
+// or the estimated gas plus MarginPercent if it is larger. max gas limits still apply.
+type GasLearningConfig struct {
+	Samples       int     `toml:",omitempty" json:",omitempty"`
+	MinSamples    int     `toml:",omitempty" json:",omitempty"`
+	Percentile    float64 `toml:",omitempty" json:",omitempty"`
+	MarginPercent float64 `toml:",omitempty" json:",omitempty"`
+}
+
+// DeliveryFeeConfig delivery cost based fee config of swaps to the chain.
+// the realized fees paid by stable swap txs, the failure rate and the replace frequency
+// are tracked per token as moving averages over about Samples deliveries. the expected
+// delivery cost is the average fee paid divided by the success rate, plus ReplaceSurcharge
+// percent per replace on average. once there are MinSamples deliveries, the minimum swap fees
+// of the token to the chain are scaled by the ratio of the expected cost to BaseCost (the
+// delivery cost the configured minimum swap fees are tuned for, in the smallest unit of the
+// fee coin), bounded in [MinPercent, MaxPercent]. oracles accept the scale pinned by the
+// initiator if it deviates from their own view at most MaxDeviation percent (oracles learn
+// their view from the swap txs they accepted to sign, the scale of a swap is pinned once
+// its first swap tx is built and kept by replacing and reswapping).
+type DeliveryFeeConfig struct {
+	BaseCost         string
+	Samples          int    `toml:",omitempty" json:",omitempty"`
+	MinSamples       int    `toml:",omitempty" json:",omitempty"`
+	ReplaceSurcharge uint64 `toml:",omitempty" json:",omitempty"` // percent
+	MinPercent       uint64 `toml:",omitempty" json:",omitempty"`
+	MaxPercent       uint64 `toml:",omitempty" json:",omitempty"`
+	MaxDeviation     uint64 `toml:",omitempty" json:",omitempty"` // percent
+
+	baseCost *big.Int
+}
+
+// ReplaceGuardConfig cost/benefit guard of replacing swap txs to the chain.
+// before the gas price of a swap tx is bumped by replacing, the projected additional
+// max fee of the replacement over the sent tx is valued in the swap token with the price
+// of one whole fee coin in whole tokens. the price is from PriceOracles (tokenID -> oracle,
+// with the staleness and deviation breakers of rate oracles), and falls back to CoinPrices
+// (tokenID -> price) only if the oracle is unavailable (swaps of tokens without price
+// are not guarded). the replacement is skipped if the additional cost is larger than
+// MaxFeeMultiple times the swap fee or MaxValuePercent percent of the swap value (0 is no limit),
+// or capped to the limit if CapFee is true (if the capped gas price can still replace the sent tx).
+// guarded swaps are flagged with 'replaceGuarded' events for manual decision,
+// manual replacing with explicit gas price is not guarded.
+type ReplaceGuardConfig struct {
+	MaxFeeMultiple  uint64            `toml:",omitempty" json:",omitempty"`
+	MaxValuePercent uint64            `toml:",omitempty" json:",omitempty"`
+	CapFee          bool              `toml:",omitempty" json:",omitempty"`
+	CoinDecimals    uint8             `toml:",omitempty" json:",omitempty"` // default 18
+	CoinPrices      map[string]string `toml:",omitempty" json:",omitempty"`
+
+	PriceOracles map[string]*RateOracleConfig `toml:",omitempty" json:",omitempty"`
+
+	coinPrices map[string]*big.Float
+}
+
+// ShallowArchiveConfig config of chains whose nodes prune receipts and states quickly.
+// rpc errors of pruned data are reported as tokens.ErrTxPruned instead of not found,
+// PrunedErrors are extra (case insensitive) error messages of pruned data of the providers.
+// if PersistReceipts is true, receipts are saved when swaps are verified (at registration),
+// and later verification falls back to the saved receipts if the nodes have pruned them.
+type ShallowArchiveConfig struct {
+	PersistReceipts bool     `toml:",omitempty" json:",omitempty"`
+	PrunedErrors    []string `toml:",omitempty" json:",omitempty"`
+}
+
+// AccountAbstractionConfig erc-4337 (entry point v0.6) execution config.
+// swapouts are executed by the router-owned smart account (which should be
+// the router mpc of the chain) as user operations signed by the owner mpc,
+// and gas is sponsored by the paymaster.
+type AccountAbstractionConfig struct {
+	EntryPoint           string   `toml:",omitempty" json:",omitempty"`
+	SmartAccount         string   `toml:",omitempty" json:",omitempty"`
+	Owner                string   `toml:",omitempty" json:",omitempty"`
+	BundlerURLs          []string `toml:",omitempty" json:",omitempty"`
+	PaymasterURL         string   `toml:",omitempty" json:",omitempty"`
+	VerificationGasLimit uint64   `toml:",omitempty" json:",omitempty"`
+	PreVerificationGas   uint64   `toml:",omitempty" json:",omitempty"`
+
+	// bounds of sponsorships pinned in sign requests checked by oracles
+	Paymaster               string `toml:",omitempty" json:",omitempty"` // paymaster contract, prefix of paymasterAndData
+	MaxVerificationGasLimit uint64 `toml:",omitempty" json:",omitempty"`
+	MaxPreVerificationGas   uint64 `toml:",omitempty" json:",omitempty"`
+}
+
+// ClaimSwapConfig two-phase swap config of destination chain.
+// swapins of the tokens place funds into the claim escrow contract,
+// the recipient (or a relayer) finalizes with a claim call before expiry,
+// otherwise the funds are refunded to the sender (if it is a valid address
+// of this chain) or the refund address.
+type ClaimSwapConfig struct {
+	Escrow        string
+	TokenIDs      []string
+	ClaimPeriod   int64  `toml:",omitempty" json:",omitempty"` // seconds
+	RefundAddress string `toml:",omitempty" json:",omitempty"`
+}
+
+// SwapAuthorizationConfig signed swap authorization config of destination chain.
+// router contracts requiring an off-chain authorization of the mpc are called by
+// `execAuthorized(bytes data, uint256 nonce, uint256 deadline, bytes signature)`,
+// where data is the swap calldata and signature is the mpc signed EIP-712 typed data
+// `SwapAuthorization(bytes32 dataHash,uint256 nonce,uint256 deadline)`.
+// the domain is separated by chain id, router contract (verifying contract),
+// and the name and version of Domains keyed by router version ("default" for
+// routers without version). the nonce is the nonce of the swap tx.
+type SwapAuthorizationConfig struct {
+	Domains  map[string]*EIP712DomainConfig
+	TokenIDs []string `toml:",omitempty" json:",omitempty"` // empty means all tokens
+	Deadline int64    `toml:",omitempty" json:",omitempty"` // seconds
+}
+
+// EIP712DomainConfig name and version of EIP-712 domain
+type EIP712DomainConfig struct {
+	Name    string
+	Version string
+}
+
+// TokenMigrationConfig token contract address migration config.
+// deposits of both the old and new token are accepted until the grace window
+// (GraceBlocks after CutoverHeight) ends, swapins always deliver the new token.
+type TokenMigrationConfig struct {
+	OldToken      string
+	NewToken      string
+	CutoverHeight uint64
+	GraceBlocks   uint64 `toml:",omitempty" json:",omitempty"`
+}
+
 // OnchainConfig struct
 type OnchainConfig struct {
 	Contract    string
@@ -239,6 +1184,7 @@ type MPCConfig struct {
 	SignTimeout               uint64 `toml:",omitempty" json:",omitempty"`
 	MaxSignGroupFailures      int    `toml:",omitempty" json:",omitempty"`
 	MinIntervalToAddSignGroup int64  `toml:",omitempty" json:",omitempty"`
+	MaxConcurrentSigns        int    `toml:",omitempty" json:",omitempty"` // 0 means no limit
 
 	VerifySignatureInAccept bool `toml:",omitempty" json:",omitempty"`
 
@@ -257,10 +1203,57 @@ type MPCConfig struct {
 
 	SignWithPrivateKey bool              // use private key instead (use for testing)
 	SignerPrivateKeys  map[string]string `json:"-"` // key is chain ID (use for testing)
+
+	DualControl *DualControlConfig `toml:",omitempty" json:",omitempty"`
+
+	Canary *MPCCanaryConfig `toml:",omitempty" json:",omitempty"`
 }
 
-// MPCNodeConfig mpc node config
-type MPCNodeConfig struct {
+// MPCCanaryConfig canary signing of mpc config changes.
+// a well-known test message is signed and verified per chain scheme
+// before production sign requests are allowed, and again after the
+// mpc settings of signing (group, threshold, gateways) are changed.
+type MPCCanaryConfig struct {
+	Interval int64 `toml:",omitempty" json:",omitempty"` // seconds
+}
+
+// GetInterval get interval (seconds) of checking mpc config changes (default 60)
+func (c *MPCCanaryConfig) GetInterval() int64 {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+	return 60
+}
+
+// DualControlConfig dual control of mpc key usage outside normal swap flow.
+// sign requests not originating from a stored verified swap must be approved
+// by at least MinApprovals distinct approvers, unless in recovery mode.
+type DualControlConfig struct {
+	Approvers    []string
+	MinApprovals int  `toml:",omitempty" json:",omitempty"`
+	RecoveryMode bool `toml:",omitempty" json:",omitempty"`
+}
+
+// GetMinApprovals get min count of distinct approvals (default 2)
+func (c *DualControlConfig) GetMinApprovals() int {
+	if c.MinApprovals > 0 {
+		return c.MinApprovals
+	}
+	return 2
+}
+
+// IsApprover is approver of dual control
+func (c *DualControlConfig) IsApprover(account string) bool {
+	for _, approver := range c.Approvers {
+		if strings.EqualFold(account, approver) {
+			return true
+		}
+	}
+	return false
+}
+
+// MPCNodeConfig mpc node config
+type MPCNodeConfig struct {
 	RPCAddress   *string
 	SignGroups   []string `toml:",omitempty" json:",omitempty"`
 	KeystoreFile *string  `json:"-"`
@@ -272,6 +1265,319 @@ type APIServerConfig struct {
 	Port             int
 	AllowedOrigins   []string
 	MaxRequestsLimit int
+
+	AbuseDetection *AbuseDetectionConfig `toml:",omitempty" json:",omitempty"`
+	Sandbox        *SandboxConfig        `toml:",omitempty" json:",omitempty"`
+	Export         *ExportConfig         `toml:",omitempty" json:",omitempty"`
+	Faucet         *FaucetConfig         `toml:",omitempty" json:",omitempty"`
+
+	Deprecations []*APIDeprecationConfig `toml:",omitempty" json:",omitempty"`
+}
+
+// APIDeprecationConfig deprecation schedule of public rest endpoint.
+// Path is the route path with version prefix (eg. /v1/swap/status/{chainid}/{txid}),
+// unversioned legacy paths are served as v1. dates are in format 2006-01-02 (UTC),
+// the endpoint responds 410 Gone since the Sunset date.
+type APIDeprecationConfig struct {
+	Path       string
+	Deprecated string
+	Sunset     string `toml:",omitempty" json:",omitempty"`
+	Successor  string `toml:",omitempty" json:",omitempty"`
+	Note       string `toml:",omitempty" json:",omitempty"`
+
+	deprecatedTime time.Time
+	sunsetTime     time.Time
+}
+
+// GetDeprecatedTime get time from which the endpoint is deprecated
+func (c *APIDeprecationConfig) GetDeprecatedTime() time.Time {
+	return c.deprecatedTime
+}
+
+// GetSunsetTime get time the endpoint is removed (zero if not scheduled)
+func (c *APIDeprecationConfig) GetSunsetTime() time.Time {
+	return c.sunsetTime
+}
+
+// SandboxConfig integrator sandbox config,
+// sandbox swaps progress through simulated states without touching chains or mpc.
+type SandboxConfig struct {
+	Enable       bool
+	StepInterval int64 `toml:",omitempty" json:",omitempty"` // seconds
+	MaxSwaps     int   `toml:",omitempty" json:",omitempty"`
+}
+
+// GetStepInterval get duration of each simulated state (default 10 seconds)
+func (c *SandboxConfig) GetStepInterval() int64 {
+	if c.StepInterval > 0 {
+		return c.StepInterval
+	}
+	return 10
+}
+
+// GetMaxSwaps get max count of sandbox swaps kept in memory (default 10000)
+func (c *SandboxConfig) GetMaxSwaps() int {
+	if c.MaxSwaps > 0 {
+		return c.MaxSwaps
+	}
+	return 10000
+}
+
+// IsSandboxEnabled is integrator sandbox enabled
+func IsSandboxEnabled() bool {
+	serverCfg := GetRouterServerConfig()
+	return serverCfg != nil && serverCfg.APIServer != nil &&
+		serverCfg.APIServer.Sandbox != nil && serverCfg.APIServer.Sandbox.Enable
+}
+
+// GetSandboxConfig get integrator sandbox config
+func GetSandboxConfig() *SandboxConfig {
+	if !IsSandboxEnabled() {
+		return nil
+	}
+	return GetRouterServerConfig().APIServer.Sandbox
+}
+
+// ExportConfig swap data export endpoints config, the exports are authorized
+// by api tokens and limited in their scopes. exports of more than MaxSyncRows
+// swaps are run as async jobs, and the files are kept for JobRetention seconds.
+// the jobs are kept in mongodb, and their files are written to Dir of the host
+// running the job, so Dir should be a shared storage of the api server replicas.
+type ExportConfig struct {
+	Enable       bool
+	Dir          string `toml:",omitempty" json:",omitempty"`
+	MaxRange     int64  `toml:",omitempty" json:",omitempty"` // seconds
+	MaxSyncRows  int64  `toml:",omitempty" json:",omitempty"`
+	MaxRows      int64  `toml:",omitempty" json:",omitempty"`
+	MaxJobs      int    `toml:",omitempty" json:",omitempty"`
+	JobRetention int64  `toml:",omitempty" json:",omitempty"` // seconds
+}
+
+// GetDir get dir of async export files (default 'router-exports' in temp dir)
+func (c *ExportConfig) GetDir() string {
+	if c.Dir != "" {
+		return c.Dir
+	}
+	return filepath.Join(os.TempDir(), "router-exports")
+}
+
+// GetMaxRange get max time range of export (seconds, default 31 days)
+func (c *ExportConfig) GetMaxRange() int64 {
+	if c.MaxRange > 0 {
+		return c.MaxRange
+	}
+	return 31 * 86400
+}
+
+// GetMaxSyncRows get max swaps of sync export (default 10000)
+func (c *ExportConfig) GetMaxSyncRows() int64 {
+	if c.MaxSyncRows > 0 {
+		return c.MaxSyncRows
+	}
+	return 10000
+}
+
+// GetMaxRows get max swaps of async export job (default 1000000)
+func (c *ExportConfig) GetMaxRows() int64 {
+	if c.MaxRows > 0 {
+		return c.MaxRows
+	}
+	return 1000000
+}
+
+// GetMaxJobs get max count of running export jobs (default 2)
+func (c *ExportConfig) GetMaxJobs() int {
+	if c.MaxJobs > 0 {
+		return c.MaxJobs
+	}
+	return 2
+}
+
+// GetJobRetention get retention of finished export jobs (seconds, default 1 day)
+func (c *ExportConfig) GetJobRetention() int64 {
+	if c.JobRetention > 0 {
+		return c.JobRetention
+	}
+	return 86400
+}
+
+// GetExportConfig get swap data export config (nil means disabled)
+func GetExportConfig() *ExportConfig {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil || serverCfg.APIServer == nil ||
+		serverCfg.APIServer.Export == nil || !serverCfg.APIServer.Export.Enable {
+		return nil
+	}
+	return serverCfg.APIServer.Export
+}
+
+// FaucetConfig testnet faucet endpoints config, which mint test tokens or send
+// scripted test swaps with the faucet accounts (which must be minters of the test tokens).
+// requests are gated by api keys ('X-API-Key' header) or captcha responses,
+// and each requester and receiver can only request once in CoolDown seconds.
+// the faucet is refused unless Testnet is set explicitly and all of the Chains
+// are known testnets (see knownTestnetChainIDs).
+type FaucetConfig struct {
+	Enable           bool
+	Testnet          bool
+	APIKeys          []string `toml:",omitempty" json:"-"`
+	CaptchaSecret    string   `toml:",omitempty" json:"-"`
+	CaptchaVerifyURL string   `toml:",omitempty" json:",omitempty"`
+	CoolDown         int64    `toml:",omitempty" json:",omitempty"` // seconds
+	SwapOutGasLimit  uint64   `toml:",omitempty" json:",omitempty"`
+
+	Chains map[string]*FaucetChainConfig `toml:",omitempty" json:",omitempty"` // key is chain id
+}
+
+// FaucetChainConfig faucet config of testnet chain
+type FaucetChainConfig struct {
+	PrivateKey  string            `json:"-"`
+	MintAmounts map[string]string // key is token id, value is in the smallest unit
+
+	mintAmounts map[string]*big.Int
+}
+
+// GetCaptchaVerifyURL get captcha siteverify url (default hcaptcha)
+func (c *FaucetConfig) GetCaptchaVerifyURL() string {
+	if c.CaptchaVerifyURL != "" {
+		return c.CaptchaVerifyURL
+	}
+	return "https://hcaptcha.com/siteverify"
+}
+
+// GetCoolDown get cool down of requester and receiver (seconds, default 1 day)
+func (c *FaucetConfig) GetCoolDown() int64 {
+	if c.CoolDown > 0 {
+		return c.CoolDown
+	}
+	return 86400
+}
+
+// GetSwapOutGasLimit get gas limit of scripted swap out tx (default 300000),
+// which is sent before the minting is mined and can not be estimated.
+func (c *FaucetConfig) GetSwapOutGasLimit() uint64 {
+	if c.SwapOutGasLimit > 0 {
+		return c.SwapOutGasLimit
+	}
+	return 300000
+}
+
+// IsAPIKey is valid faucet api key
+func (c *FaucetConfig) IsAPIKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, k := range c.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMintAmount get faucet mint amount of token on chain (nil means not supported)
+func (c *FaucetConfig) GetMintAmount(chainID, tokenID string) *big.Int {
+	chainCfg, exist := c.Chains[chainID]
+	if !exist {
+		return nil
+	}
+	return chainCfg.mintAmounts[strings.ToLower(tokenID)]
+}
+
+// GetPrivateKey get private key of faucet account on chain
+func (c *FaucetConfig) GetPrivateKey(chainID string) string {
+	if IsWatcherMode {
+		return ""
+	}
+	if chainCfg, exist := c.Chains[chainID]; exist {
+		return chainCfg.PrivateKey
+	}
+	return ""
+}
+
+// knownTestnetChainIDs chain ids of public testnets which the faucet can serve
+var knownTestnetChainIDs = map[string]string{
+	"3":          "ropsten",
+	"4":          "rinkeby",
+	"5":          "goerli",
+	"42":         "kovan",
+	"65":         "okx testnet",
+	"97":         "bsc testnet",
+	"256":        "heco testnet",
+	"280":        "zksync era goerli",
+	"300":        "zksync era sepolia",
+	"420":        "optimism goerli",
+	"1287":       "moonbase alpha",
+	"1442":       "polygon zkevm testnet",
+	"4002":       "fantom testnet",
+	"10200":      "gnosis chiado",
+	"17000":      "holesky",
+	"43113":      "avalanche fuji",
+	"44787":      "celo alfajores",
+	"46688":      "fusion testnet",
+	"59140":      "linea goerli",
+	"59141":      "linea sepolia",
+	"80001":      "polygon mumbai",
+	"80002":      "polygon amoy",
+	"84531":      "base goerli",
+	"84532":      "base sepolia",
+	"421613":     "arbitrum goerli",
+	"421614":     "arbitrum sepolia",
+	"11155111":   "sepolia",
+	"11155420":   "optimism sepolia",
+	"1313161555": "aurora testnet",
+}
+
+// IsKnownTestnetChainID is chain id of a known public testnet
+func IsKnownTestnetChainID(chainID string) bool {
+	_, exist := knownTestnetChainIDs[chainID]
+	return exist
+}
+
+// GetFaucetConfig get testnet faucet config (nil means disabled)
+func GetFaucetConfig() *FaucetConfig {
+	serverCfg := GetRouterServerConfig()
+	if IsWatcherMode || serverCfg == nil || serverCfg.APIServer == nil ||
+		serverCfg.APIServer.Faucet == nil || !serverCfg.APIServer.Faucet.Enable {
+		return nil
+	}
+	return serverCfg.APIServer.Faucet
+}
+
+// AbuseDetectionConfig rpc abuse detection config
+type AbuseDetectionConfig struct {
+	Enable                      bool
+	WindowSeconds               int64    `toml:",omitempty" json:",omitempty"`
+	MaxRequestsPerWindow        int      `toml:",omitempty" json:",omitempty"`
+	MaxRegistersPerWindow       int      `toml:",omitempty" json:",omitempty"`
+	MaxFailedRegistersPerWindow int      `toml:",omitempty" json:",omitempty"`
+	BanSeconds                  int64    `toml:",omitempty" json:",omitempty"`
+	MaxBanSeconds               int64    `toml:",omitempty" json:",omitempty"`
+	Whitelist                   []string `toml:",omitempty" json:",omitempty"`
+}
+
+// GetWindow get counting window (default 60 seconds)
+func (c *AbuseDetectionConfig) GetWindow() time.Duration {
+	if c.WindowSeconds > 0 {
+		return time.Duration(c.WindowSeconds) * time.Second
+	}
+	return 60 * time.Second
+}
+
+// GetBanDuration get duration of first ban (default 300 seconds)
+func (c *AbuseDetectionConfig) GetBanDuration() time.Duration {
+	if c.BanSeconds > 0 {
+		return time.Duration(c.BanSeconds) * time.Second
+	}
+	return 300 * time.Second
+}
+
+// GetMaxBanDuration get maximum ban duration of escalation (default 1 day)
+func (c *AbuseDetectionConfig) GetMaxBanDuration() time.Duration {
+	if c.MaxBanSeconds > 0 {
+		return time.Duration(c.MaxBanSeconds) * time.Second
+	}
+	return 86400 * time.Second
 }
 
 // MongoDBConfig mongodb config
@@ -281,6 +1587,35 @@ type MongoDBConfig struct {
 	DBName   string
 	UserName string `json:"-"`
 	Password string `json:"-"`
+
+	EnableProfiling    bool  `toml:",omitempty" json:",omitempty"`
+	SlowQueryThreshold int64 `toml:",omitempty" json:",omitempty"` // milliseconds
+
+	EnableStatusQueues bool `toml:",omitempty" json:",omitempty"`
+
+	// Mirror is the new database in dual write migration mode
+	Mirror *MongoDBConfig `toml:",omitempty" json:",omitempty"`
+
+	// FieldEncryption encrypt sensitive stored fields at rest
+	FieldEncryption *FieldEncryptionConfig `toml:",omitempty" json:",omitempty"`
+}
+
+// FieldEncryptionConfig application level encryption of sensitive stored fields
+// (eg. api token names, operator identities). the data keys are provided by
+// the KMS endpoint (or a local key file), old keys are kept for decrypting.
+type FieldEncryptionConfig struct {
+	KMSURL       string `toml:",omitempty" json:",omitempty"`
+	KMSAuthToken string `toml:",omitempty" json:"-"`
+	KeyFile      string `toml:",omitempty" json:",omitempty"`
+
+	// re-encrypt fields encrypted by old keys (or not encrypted) on startup
+	RotateOnStart bool `toml:",omitempty" json:",omitempty"`
+}
+
+// FieldEncryptionKeys data keys of field encryption
+type FieldEncryptionKeys struct {
+	Current string            `json:"current"` // key id used for encrypting
+	Keys    map[string]string `json:"keys"`    // key id -> hex encoded 32 bytes key
 }
 
 // DynamicFeeTxConfig dynamic fee tx config
@@ -311,6 +1646,11 @@ func GetIdentifier() string {
 	return GetRouterConfig().Identifier
 }
 
+// GetDeploymentSalt get deployment salt (mixed into swap identity)
+func GetDeploymentSalt() string {
+	return GetRouterConfig().DeploymentSalt
+}
+
 // GetSwapType get router swap type
 func GetSwapType() string {
 	return GetRouterConfig().SwapType
@@ -465,6 +1805,9 @@ func GetCustom(chainID, key string) string {
 
 // GetSignerPrivateKey get signer private key (use for testing)
 func (c *MPCConfig) GetSignerPrivateKey(chainID string) string {
+	if IsWatcherMode {
+		return ""
+	}
 	if prikey, exist := c.SignerPrivateKeys[chainID]; exist {
 		return prikey
 	}
@@ -521,250 +1864,968 @@ func AllowCallByContract() bool {
 	return *isAllowCallByContract
 }
 
-// SetAllowCallByContract set allow call by contract flag (used in testing)
-func SetAllowCallByContract(allow bool) {
-	if isAllowCallByContract == nil {
-		isAllowCallByContract = &allow
-	} else {
-		*isAllowCallByContract = allow
+// SetAllowCallByContract set allow call by contract flag (used in testing)
+func SetAllowCallByContract(allow bool) {
+	if isAllowCallByContract == nil {
+		isAllowCallByContract = &allow
+	} else {
+		*isAllowCallByContract = allow
+	}
+}
+
+// CheckEIP1167Master whether check eip1167 master call by contract
+func CheckEIP1167Master() bool {
+	if isCheckEIP1167Master == nil {
+		flag := GetExtraConfig() != nil && GetExtraConfig().CheckEIP1167Master
+		isCheckEIP1167Master = &flag
+	}
+	return *isCheckEIP1167Master
+}
+
+func initCallByContractWhitelist() {
+	if GetExtraConfig() == nil || len(GetExtraConfig().CallByContractWhitelist) == 0 {
+		return
+	}
+	allWhitelist := make(map[string]map[string]struct{})
+	for cid, whitelist := range GetExtraConfig().CallByContractWhitelist {
+		if _, err := common.GetBigIntFromStr(cid); err != nil {
+			log.Fatal("initCallByContractWhitelist wrong chainID", "chainID", cid, "err", err)
+		}
+		whitelistMap := make(map[string]struct{}, len(whitelist))
+		for _, address := range whitelist {
+			if !common.IsHexAddress(address) {
+				log.Fatal("initCallByContractWhitelist wrong address", "chainID", cid, "address", address)
+			}
+			whitelistMap[strings.ToLower(address)] = struct{}{}
+		}
+		allWhitelist[cid] = whitelistMap
+	}
+	callByContractWhitelist = allWhitelist
+	log.Info("initCallByContractWhitelist success", "isReload", IsReload)
+}
+
+// IsInCallByContractWhitelist is in call by contract whitelist
+func IsInCallByContractWhitelist(chainID, caller string) bool {
+	whitelist, exist := callByContractWhitelist[chainID]
+	if !exist {
+		return false
+	}
+	_, exist = whitelist[strings.ToLower(caller)]
+	return exist
+}
+
+// AddOrRemoveCallByContractWhitelist add or remove call by contract whitelist
+//nolint:dupl // allow duplicate
+func AddOrRemoveCallByContractWhitelist(chainID string, callers []string, isAdd bool) {
+	whitelist, exist := callByContractWhitelist[chainID]
+	if !exist {
+		if !isAdd {
+			return
+		}
+		callByContractWhitelist[chainID] = make(map[string]struct{})
+		whitelist = callByContractWhitelist[chainID]
+	}
+	for _, caller := range callers {
+		key := strings.ToLower(caller)
+		if isAdd {
+			whitelist[key] = struct{}{}
+		} else {
+			delete(whitelist, key)
+		}
+	}
+	if GetExtraConfig() != nil {
+		chainWhitelist := make([]string, 0, len(whitelist))
+		for caller := range whitelist {
+			chainWhitelist = append(chainWhitelist, caller)
+		}
+		if GetExtraConfig().CallByContractWhitelist == nil {
+			GetExtraConfig().CallByContractWhitelist = make(map[string][]string)
+		}
+		GetExtraConfig().CallByContractWhitelist[chainID] = chainWhitelist
+	}
+}
+
+func initCallByContractCodeHashWhitelist() {
+	if GetExtraConfig() == nil || len(GetExtraConfig().CallByContractCodeHashWhitelist) == 0 {
+		return
+	}
+	allWhitelist := make(map[string]map[string]struct{})
+	for cid, whitelist := range GetExtraConfig().CallByContractCodeHashWhitelist {
+		if _, err := common.GetBigIntFromStr(cid); err != nil {
+			log.Fatal("initCallByContractCodeHashWhitelist wrong chainID", "chainID", cid, "err", err)
+		}
+		whitelistMap := make(map[string]struct{}, len(whitelist))
+		for _, codehash := range whitelist {
+			if !common.IsHexHash(codehash) {
+				log.Fatal("initCallByContractCodeHashWhitelist wrong code hash", "chainID", cid, "codehash", codehash)
+			}
+			whitelistMap[codehash] = struct{}{}
+		}
+		allWhitelist[cid] = whitelistMap
+	}
+	callByContractCodeHashWhitelist = allWhitelist
+	log.Info("initCallByContractCodeHashWhitelist success", "isReload", IsReload)
+}
+
+// HasCallByContractCodeHashWhitelist has call by contract code hash whitelist
+func HasCallByContractCodeHashWhitelist(chainID string) bool {
+	whitelist, exist := callByContractCodeHashWhitelist[chainID]
+	return exist && len(whitelist) > 0
+}
+
+// IsInCallByContractCodeHashWhitelist is in call by contract code hash whitelist
+func IsInCallByContractCodeHashWhitelist(chainID, codehash string) bool {
+	whitelist, exist := callByContractCodeHashWhitelist[chainID]
+	if !exist {
+		return false
+	}
+	_, exist = whitelist[codehash]
+	return exist
+}
+
+// AddOrRemoveCallByContractCodeHashWhitelist add or remove call by contract code hash whitelist
+func AddOrRemoveCallByContractCodeHashWhitelist(chainID string, codehashes []string, isAdd bool) {
+	whitelist, exist := callByContractCodeHashWhitelist[chainID]
+	if !exist {
+		if !isAdd {
+			return
+		}
+		callByContractCodeHashWhitelist[chainID] = make(map[string]struct{})
+		whitelist = callByContractCodeHashWhitelist[chainID]
+	}
+	for _, codehash := range codehashes {
+		key := codehash
+		if isAdd {
+			whitelist[key] = struct{}{}
+		} else {
+			delete(whitelist, key)
+		}
+	}
+	if GetExtraConfig() != nil {
+		chainWhitelist := make([]string, 0, len(whitelist))
+		for codehash := range whitelist {
+			chainWhitelist = append(chainWhitelist, codehash)
+		}
+		if GetExtraConfig().CallByContractCodeHashWhitelist == nil {
+			GetExtraConfig().CallByContractCodeHashWhitelist = make(map[string][]string)
+		}
+		GetExtraConfig().CallByContractCodeHashWhitelist[chainID] = chainWhitelist
+	}
+}
+
+func initBigValueWhitelist() {
+	if GetExtraConfig() == nil || len(GetExtraConfig().BigValueWhitelist) == 0 {
+		return
+	}
+	allWhitelist := make(map[string]map[string]struct{})
+	for tid, whitelist := range GetExtraConfig().BigValueWhitelist {
+		whitelistMap := make(map[string]struct{}, len(whitelist))
+		for _, address := range whitelist {
+			if address == "" {
+				log.Fatal("initBigValueWhitelist empty address", "tokenID", tid)
+			}
+			whitelistMap[strings.ToLower(address)] = struct{}{}
+		}
+		allWhitelist[tid] = whitelistMap
+	}
+	bigValueWhitelist = allWhitelist
+	log.Info("initBigValueWhitelist success", "isReload", IsReload)
+}
+
+// IsInBigValueWhitelist is in call by contract whitelist
+func IsInBigValueWhitelist(tokenID, caller string) bool {
+	whitelist, exist := bigValueWhitelist[tokenID]
+	if !exist {
+		return false
+	}
+	_, exist = whitelist[strings.ToLower(caller)]
+	return exist
+}
+
+// AddOrRemoveBigValueWhitelist add or remove big value whitelist
+//nolint:dupl // allow duplicate
+func AddOrRemoveBigValueWhitelist(tokenID string, callers []string, isAdd bool) {
+	whitelist, exist := bigValueWhitelist[tokenID]
+	if !exist {
+		if !isAdd {
+			return
+		}
+		bigValueWhitelist[tokenID] = make(map[string]struct{})
+		whitelist = bigValueWhitelist[tokenID]
+	}
+	for _, caller := range callers {
+		key := strings.ToLower(caller)
+		if isAdd {
+			whitelist[key] = struct{}{}
+		} else {
+			delete(whitelist, key)
+		}
+	}
+	if GetExtraConfig() != nil {
+		tokenWhitelist := make([]string, 0, len(whitelist))
+		for caller := range whitelist {
+			tokenWhitelist = append(tokenWhitelist, caller)
+		}
+		if GetExtraConfig().BigValueWhitelist == nil {
+			GetExtraConfig().BigValueWhitelist = make(map[string][]string)
+		}
+		GetExtraConfig().BigValueWhitelist[tokenID] = tokenWhitelist
+	}
+}
+
+// GetTokenRouteKey get token route key (`*` matches any chain)
+func GetTokenRouteKey(fromChainID, toChainID string) string {
+	return fromChainID + ":" + toChainID
+}
+
+func checkTokenRoute(route string) error {
+	parts := strings.Split(route, ":")
+	if len(parts) != 2 {
+		return fmt.Errorf("wrong token route '%v'", route)
+	}
+	for _, chainID := range parts {
+		if chainID == "*" {
+			continue
+		}
+		if _, err := common.GetBigIntFromStr(chainID); err != nil {
+			return fmt.Errorf("wrong chainID '%v' in token route '%v'", chainID, route)
+		}
+	}
+	return nil
+}
+
+// tokenRouteWhitelistItem routes of token, the routes are checked only if
+// the whitelist is active, an active whitelist without routes rejects all.
+type tokenRouteWhitelistItem struct {
+	active bool
+	routes map[string]struct{}
+}
+
+var tokenRouteWhitelist = make(map[string]*tokenRouteWhitelistItem) // key is tokenID
+
+func getTokenRouteWhitelistItem(tokenID string, create bool) *tokenRouteWhitelistItem {
+	key := strings.ToLower(tokenID)
+	item := tokenRouteWhitelist[key]
+	if item == nil && create {
+		item = &tokenRouteWhitelistItem{routes: make(map[string]struct{})}
+		tokenRouteWhitelist[key] = item
+	}
+	return item
+}
+
+func initTokenRouteWhitelist() {
+	allWhitelist := make(map[string]*tokenRouteWhitelistItem)
+	if GetExtraConfig() != nil {
+		// token configed is active even if no routes are listed
+		for tid, routes := range GetExtraConfig().TokenRouteWhitelist {
+			whitelistMap := make(map[string]struct{}, len(routes))
+			for _, route := range routes {
+				if err := checkTokenRoute(route); err != nil {
+					log.Fatal("initTokenRouteWhitelist failed", "tokenID", tid, "err", err)
+				}
+				whitelistMap[route] = struct{}{}
+			}
+			allWhitelist[strings.ToLower(tid)] = &tokenRouteWhitelistItem{
+				active: true,
+				routes: whitelistMap,
+			}
+		}
+	}
+	tokenRouteWhitelist = allWhitelist
+	log.Info("initTokenRouteWhitelist success", "isReload", IsReload)
+}
+
+// IsTokenRouteAllowed is token allowed to swap from `fromChainID` to `toChainID`.
+// token without active route whitelist is allowed in all directions.
+func IsTokenRouteAllowed(tokenID, fromChainID, toChainID string) bool {
+	item := getTokenRouteWhitelistItem(tokenID, false)
+	if item == nil || !item.active {
+		return true
+	}
+	for _, route := range []string{
+		GetTokenRouteKey(fromChainID, toChainID),
+		GetTokenRouteKey(fromChainID, "*"),
+		GetTokenRouteKey("*", toChainID),
+	} {
+		if _, exist := item.routes[route]; exist {
+			return true
+		}
+	}
+	return false
+}
+
+// AddOrRemoveTokenRouteWhitelist add or remove token route whitelist.
+// the active state of the whitelist is not changed (see SetTokenRouteWhitelistActive),
+// so adding routes does not restrict an unrestricted token, and removing
+// the last route of an active whitelist does not allow all directions.
+func AddOrRemoveTokenRouteWhitelist(tokenID string, routes []string, isAdd bool) error {
+	for _, route := range routes {
+		if err := checkTokenRoute(route); err != nil {
+			return err
+		}
+	}
+	item := getTokenRouteWhitelistItem(tokenID, isAdd)
+	if item == nil {
+		return nil
+	}
+	for _, route := range routes {
+		if isAdd {
+			item.routes[route] = struct{}{}
+		} else {
+			delete(item.routes, route)
+		}
+	}
+	syncTokenRouteWhitelistConfig(tokenID, item)
+	return nil
+}
+
+// SetTokenRouteWhitelistActive activate or deactivate token route whitelist
+func SetTokenRouteWhitelistActive(tokenID string, active bool) {
+	item := getTokenRouteWhitelistItem(tokenID, active)
+	if item == nil {
+		return
+	}
+	item.active = active
+	syncTokenRouteWhitelistConfig(tokenID, item)
+}
+
+// syncTokenRouteWhitelistConfig only active whitelists are kept in config
+func syncTokenRouteWhitelistConfig(tokenID string, item *tokenRouteWhitelistItem) {
+	if GetExtraConfig() == nil {
+		return
+	}
+	key := strings.ToLower(tokenID)
+	if !item.active {
+		delete(GetExtraConfig().TokenRouteWhitelist, key)
+		return
+	}
+	if GetExtraConfig().TokenRouteWhitelist == nil {
+		GetExtraConfig().TokenRouteWhitelist = make(map[string][]string)
+	}
+	tokenRoutes := make([]string, 0, len(item.routes))
+	for route := range item.routes {
+		tokenRoutes = append(tokenRoutes, route)
+	}
+	sort.Strings(tokenRoutes)
+	GetExtraConfig().TokenRouteWhitelist[key] = tokenRoutes
+}
+
+func initDestMethodAllowlist() {
+	allAllowlist := make(map[string]map[string]struct{})
+	if GetExtraConfig() != nil {
+		for cid, methods := range GetExtraConfig().DestMethodAllowlist {
+			if _, err := common.GetBigIntFromStr(cid); err != nil {
+				log.Fatal("initDestMethodAllowlist wrong chainID", "chainID", cid, "err", err)
+			}
+			allowlistMap := make(map[string]struct{}, len(methods))
+			for _, method := range methods {
+				if method == "" {
+					log.Fatal("initDestMethodAllowlist empty method", "chainID", cid)
+				}
+				allowlistMap[strings.ToLower(method)] = struct{}{}
+			}
+			allAllowlist[cid] = allowlistMap
+		}
+	}
+	destMethodAllowlist = allAllowlist
+	log.Info("initDestMethodAllowlist success", "isReload", IsReload)
+}
+
+// IsDestMethodAllowed is method (4-byte selector or message type) allowed to
+// be constructed and signed on destination chain.
+// chain without method allowlist config is allowed for all methods.
+func IsDestMethodAllowed(chainID, method string) bool {
+	allowlist, exist := destMethodAllowlist[chainID]
+	if !exist {
+		return true
+	}
+	_, exist = allowlist[strings.ToLower(method)]
+	return exist
+}
+
+// GetRouterConfig get router config
+func GetRouterConfig() *RouterConfig {
+	return routerConfig
+}
+
+// GetRouterServerConfig get router server config
+func GetRouterServerConfig() *RouterServerConfig {
+	return routerConfig.Server
+}
+
+// GetRouterOracleConfig get router oracle config
+func GetRouterOracleConfig() *RouterOracleConfig {
+	return routerConfig.Oracle
+}
+
+// GetMPCConfig get mpc config
+func GetMPCConfig(isFastMPC bool) *MPCConfig {
+	if isFastMPC {
+		return routerConfig.FastMPC
+	}
+	return routerConfig.MPC
+}
+
+// GetLocalChainConfig get local chain config
+func GetLocalChainConfig(chainID string) *LocalChainConfig {
+	if GetExtraConfig() != nil {
+		c := GetExtraConfig().LocalChainConfig[chainID]
+		if c != nil {
+			if c.lock == nil {
+				c.lock = new(sync.Mutex)
+			}
+			return c
+		}
+	}
+	return &LocalChainConfig{}
+}
+
+// GetSweepInterval get sweep interval (seconds) of deposit addresses (default 10 minutes)
+func (c *DepositFactoryConfig) GetSweepInterval() int64 {
+	if c.SweepInterval > 0 {
+		return c.SweepInterval
+	}
+	return 600
+}
+
+// GetDepositFactoryConfig get deposit factory config of chain (nil if not supported)
+func GetDepositFactoryConfig(chainID string) *DepositFactoryConfig {
+	return GetLocalChainConfig(chainID).DepositFactory
+}
+
+// GetAllowanceMonitorConfig get allowance monitor config of chain (nil if not monitored)
+func GetAllowanceMonitorConfig(chainID string) *AllowanceMonitorConfig {
+	return GetLocalChainConfig(chainID).AllowanceMonitor
+}
+
+// GetAllowanceConfig get monitored allowance of token to spender (nil if not monitored)
+func (c *AllowanceMonitorConfig) GetAllowanceConfig(token, spender string) *AllowanceConfig {
+	for _, allowance := range c.Allowances {
+		if strings.EqualFold(allowance.Token, token) && strings.EqualFold(allowance.Spender, spender) {
+			return allowance
+		}
+	}
+	return nil
+}
+
+// GetMinAllowance get allowance below which is alerted and re-approved
+func (c *AllowanceConfig) GetMinAllowance() *big.Int {
+	return new(big.Int).Set(c.minAllowance)
+}
+
+// GetApproveAmount get allowance to approve (default max uint256)
+func (c *AllowanceConfig) GetApproveAmount() *big.Int {
+	if c.approveAmount == nil {
+		return new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	}
+	return new(big.Int).Set(c.approveAmount)
+}
+
+// GetStallTimeout get time (seconds) without head advancing to regard an endpoint as stalled
+func (c *HeadWatchdogConfig) GetStallTimeout() int64 {
+	if c.StallTimeout > 0 {
+		return c.StallTimeout
+	}
+	return 300
+}
+
+// GetMaxDivergence get max tolerated divergence (blocks) of endpoint heads
+func (c *HeadWatchdogConfig) GetMaxDivergence() uint64 {
+	if c.MaxDivergence > 0 {
+		return c.MaxDivergence
+	}
+	return 100
+}
+
+// GetHeadWatchdogConfig get head watchdog config of chain (nil if not enabled)
+func GetHeadWatchdogConfig(chainID string) *HeadWatchdogConfig {
+	return GetLocalChainConfig(chainID).HeadWatchdog
+}
+
+// GetMaxLag get max lag (blocks) of the scan checkpoint to recover on startup (default 10000)
+func (c *BlockScanConfig) GetMaxLag() uint64 {
+	if c.MaxLag > 0 {
+		return c.MaxLag
+	}
+	return 10000
+}
+
+// GetBlocksPerRound get max count of blocks scanned in one round (default 100)
+func (c *BlockScanConfig) GetBlocksPerRound() uint64 {
+	if c.BlocksPerRound > 0 {
+		return c.BlocksPerRound
+	}
+	return 100
+}
+
+// GetBlockScanConfig get block scan config of chain (nil if not enabled)
+func GetBlockScanConfig(chainID string) *BlockScanConfig {
+	return GetLocalChainConfig(chainID).BlockScan
+}
+
+// GetMinProviders get min count of agreeing providers (default 2)
+func (c *ReceiptCrossVerifyConfig) GetMinProviders() int {
+	if c.MinProviders > 0 {
+		return c.MinProviders
+	}
+	return 2
+}
+
+// GetReceiptCrossVerifyConfig get receipt cross verify config of chain (nil if not enabled)
+func GetReceiptCrossVerifyConfig(chainID string) *ReceiptCrossVerifyConfig {
+	return GetLocalChainConfig(chainID).ReceiptCrossVerify
+}
+
+// GetForkCheckDepth get depth of block whose hash is compared (default 12)
+func (c *GatewayCheckConfig) GetForkCheckDepth() uint64 {
+	if c.ForkCheckDepth > 0 {
+		return c.ForkCheckDepth
+	}
+	return 12
+}
+
+// GetGatewayCheckConfig get gateway check config of chain (nil if not enabled)
+func GetGatewayCheckConfig(chainID string) *GatewayCheckConfig {
+	return GetLocalChainConfig(chainID).GatewayCheck
+}
+
+// GetSlippagePercent get slippage percent of SendMax (default 1)
+func (c *PathFindConfig) GetSlippagePercent() float64 {
+	if c.SlippagePercent > 0 {
+		return c.SlippagePercent
+	}
+	return 1
+}
+
+// GetPathFindConfig get pathfinding config of chain (nil if not enabled)
+func GetPathFindConfig(chainID string) *PathFindConfig {
+	return GetLocalChainConfig(chainID).PathFind
+}
+
+// GetExpiration get expiration of checks (seconds, default 7 days)
+func (c *CheckPayoutConfig) GetExpiration() int64 {
+	if c.Expiration > 0 {
+		return c.Expiration
+	}
+	return 7 * 86400
+}
+
+// GetCheckPayoutConfig get check payout config of chain (nil if not enabled)
+func GetCheckPayoutConfig(chainID string) *CheckPayoutConfig {
+	return GetLocalChainConfig(chainID).CheckPayout
+}
+
+// GetLedgerStreamConfig get ledger stream config of chain (nil if not enabled)
+func GetLedgerStreamConfig(chainID string) *LedgerStreamConfig {
+	return GetLocalChainConfig(chainID).LedgerStream
+}
+
+// GetIdleTimeout get max duration without data before switching server (default 30 seconds)
+func (c *LedgerStreamConfig) GetIdleTimeout() time.Duration {
+	if c.IdleTimeout > 0 {
+		return time.Duration(c.IdleTimeout) * time.Second
+	}
+	return 30 * time.Second
+}
+
+// GetEndpointRole get configed role of gateway endpoint (empty if not configed)
+func GetEndpointRole(chainID, url string) string {
+	return GetLocalChainConfig(chainID).EndpointRoles[url]
+}
+
+// GetLastLedgerWindow get ledger window of LastLedgerSequence (default 20)
+func GetLastLedgerWindow(chainID string) uint64 {
+	if window := GetLocalChainConfig(chainID).LastLedgerWindow; window > 0 {
+		return window
+	}
+	return 20
+}
+
+// GetMaxAttempts get max attempts of out of gas retry (default 3)
+func (c *GasRetryConfig) GetMaxAttempts() int {
+	if c.MaxAttempts > 0 {
+		return c.MaxAttempts
+	}
+	return 3
+}
+
+// GetAdjustment get gas adjustment of the attempt (starts from 1)
+// (default 1.5 for the first attempt, and 0.5 more for each subsequent one)
+func (c *GasRetryConfig) GetAdjustment(attempt int) float64 {
+	adjustment, step := c.GasAdjustment, c.AdjustmentStep
+	if adjustment <= 0 {
+		adjustment = 1.5
+	}
+	if step <= 0 {
+		step = 0.5
+	}
+	return adjustment + float64(attempt-1)*step
+}
+
+// GetGasRetryConfig get out of gas retry config of chain (default config if not set)
+func GetGasRetryConfig(chainID string) *GasRetryConfig {
+	if c := GetLocalChainConfig(chainID).GasRetry; c != nil {
+		return c
+	}
+	return &GasRetryConfig{}
+}
+
+// GetSwapFairnessConfig get fair scheduling config of swap tasks to chain
+func GetSwapFairnessConfig(chainID string) *SwapFairnessConfig {
+	return GetLocalChainConfig(chainID).SwapFairness
+}
+
+// GetSwapPipelineDepth get max swaps in the signing and sending pipeline to chain
+func GetSwapPipelineDepth(chainID string) int {
+	if IsParallelSwapEnabledOnChain(chainID) {
+		return 0
+	}
+	return GetLocalChainConfig(chainID).SwapPipelineDepth
+}
+
+// IsChainParamWatchEnabled is watching on-chain params of chain enabled
+func IsChainParamWatchEnabled(chainID string) bool {
+	return GetLocalChainConfig(chainID).WatchChainParams
+}
+
+// IsManualApprovalRequired do swapouts to chain wait for manual approval
+func IsManualApprovalRequired(chainID string) bool {
+	return GetLocalChainConfig(chainID).ManualApproval
+}
+
+// GetSendDelayConfig get randomized send delay config of swapouts to chain (nil if not enabled)
+func GetSendDelayConfig(chainID string) *SendDelayConfig {
+	return GetLocalChainConfig(chainID).SendDelay
+}
+
+// GetGasLearningConfig get gas limit learning config of chain (nil if not enabled)
+func GetGasLearningConfig(chainID string) *GasLearningConfig {
+	return GetLocalChainConfig(chainID).GasLearning
+}
+
+// GetSamples get max samples kept per token and swap method (default 100)
+func (c *GasLearningConfig) GetSamples() int {
+	if c.Samples > 0 {
+		return c.Samples
+	}
+	return 100
+}
+
+// GetMinSamples get min samples to apply the learned gas limit (default 20)
+func (c *GasLearningConfig) GetMinSamples() int {
+	if c.MinSamples > 0 {
+		return c.MinSamples
+	}
+	return 20
+}
+
+// GetPercentile get percentile of samples (default 95)
+func (c *GasLearningConfig) GetPercentile() float64 {
+	if c.Percentile > 0 {
+		return c.Percentile
+	}
+	return 95
+}
+
+// GetMarginPercent get safety margin of learned gas limit (default 10)
+func (c *GasLearningConfig) GetMarginPercent() float64 {
+	if c.MarginPercent > 0 {
+		return c.MarginPercent
+	}
+	return 10
+}
+
+// GetDeliveryFeeConfig get delivery cost based fee config of chain (nil if not enabled)
+func GetDeliveryFeeConfig(chainID string) *DeliveryFeeConfig {
+	return GetLocalChainConfig(chainID).DeliveryFee
+}
+
+// GetBaseCost get delivery cost the configured minimum swap fees are tuned for
+func (c *DeliveryFeeConfig) GetBaseCost() *big.Int {
+	return c.baseCost
+}
+
+// GetSamples get window of moving averages in deliveries (default 50)
+func (c *DeliveryFeeConfig) GetSamples() int {
+	if c.Samples > 0 {
+		return c.Samples
+	}
+	return 50
+}
+
+// GetMinSamples get min deliveries to scale the minimum swap fees (default 20)
+func (c *DeliveryFeeConfig) GetMinSamples() int {
+	if c.MinSamples > 0 {
+		return c.MinSamples
+	}
+	return 20
+}
+
+// GetMinPercent get lower bound of the scale percent (default 50)
+func (c *DeliveryFeeConfig) GetMinPercent() uint64 {
+	if c.MinPercent > 0 {
+		return c.MinPercent
+	}
+	return 50
+}
+
+// GetMaxPercent get upper bound of the scale percent (default 300)
+func (c *DeliveryFeeConfig) GetMaxPercent() uint64 {
+	if c.MaxPercent > 0 {
+		return c.MaxPercent
+	}
+	return 300
+}
+
+// GetMaxDeviation get max deviation percent of the pinned scale from our own view (default 20)
+func (c *DeliveryFeeConfig) GetMaxDeviation() uint64 {
+	if c.MaxDeviation > 0 {
+		return c.MaxDeviation
+	}
+	return 20
+}
+
+// GetReplaceGuardConfig get replace cost guard config of chain (nil if not enabled)
+func GetReplaceGuardConfig(chainID string) *ReplaceGuardConfig {
+	return GetLocalChainConfig(chainID).ReplaceGuard
+}
+
+// GetCoinDecimals get decimals of the fee coin (default 18)
+func (c *ReplaceGuardConfig) GetCoinDecimals() uint8 {
+	if c.CoinDecimals > 0 {
+		return c.CoinDecimals
 	}
+	return 18
 }
 
-// CheckEIP1167Master whether check eip1167 master call by contract
-func CheckEIP1167Master() bool {
-	if isCheckEIP1167Master == nil {
-		flag := GetExtraConfig() != nil && GetExtraConfig().CheckEIP1167Master
-		isCheckEIP1167Master = &flag
-	}
-	return *isCheckEIP1167Master
+// GetCoinPrice get price of one whole fee coin in whole tokens (nil if not configured)
+func (c *ReplaceGuardConfig) GetCoinPrice(tokenID string) *big.Float {
+	return c.coinPrices[strings.ToLower(tokenID)]
 }
 
-func initCallByContractWhitelist() {
-	if GetExtraConfig() == nil || len(GetExtraConfig().CallByContractWhitelist) == 0 {
-		return
+// GetCoinPriceOracleConfig get replace guard price oracle of token on chain (nil if not configured)
+func GetCoinPriceOracleConfig(chainID, tokenID string) *RateOracleConfig {
+	cfg := GetReplaceGuardConfig(chainID)
+	if cfg == nil {
+		return nil
 	}
-	allWhitelist := make(map[string]map[string]struct{})
-	for cid, whitelist := range GetExtraConfig().CallByContractWhitelist {
-		if _, err := common.GetBigIntFromStr(cid); err != nil {
-			log.Fatal("initCallByContractWhitelist wrong chainID", "chainID", cid, "err", err)
-		}
-		whitelistMap := make(map[string]struct{}, len(whitelist))
-		for _, address := range whitelist {
-			if !common.IsHexAddress(address) {
-				log.Fatal("initCallByContractWhitelist wrong address", "chainID", cid, "address", address)
-			}
-			whitelistMap[strings.ToLower(address)] = struct{}{}
+	for tid, c := range cfg.PriceOracles {
+		if strings.EqualFold(tid, tokenID) {
+			return c
 		}
-		allWhitelist[cid] = whitelistMap
 	}
-	callByContractWhitelist = allWhitelist
-	log.Info("initCallByContractWhitelist success", "isReload", IsReload)
+	return nil
 }
 
-// IsInCallByContractWhitelist is in call by contract whitelist
-func IsInCallByContractWhitelist(chainID, caller string) bool {
-	whitelist, exist := callByContractWhitelist[chainID]
-	if !exist {
-		return false
-	}
-	_, exist = whitelist[strings.ToLower(caller)]
-	return exist
+// GetShallowArchiveConfig get shallow archive config of chain (nil if not configured)
+func GetShallowArchiveConfig(chainID string) *ShallowArchiveConfig {
+	return GetLocalChainConfig(chainID).ShallowArchive
 }
 
-// AddOrRemoveCallByContractWhitelist add or remove call by contract whitelist
-//nolint:dupl // allow duplicate
-func AddOrRemoveCallByContractWhitelist(chainID string, callers []string, isAdd bool) {
-	whitelist, exist := callByContractWhitelist[chainID]
-	if !exist {
-		if !isAdd {
-			return
+// IsPersistReceipts is persisting receipts of verified swap txs of chain
+func IsPersistReceipts(chainID string) bool {
+	cfg := GetShallowArchiveConfig(chainID)
+	return cfg != nil && cfg.PersistReceipts
+}
+
+// GetTokenWeight get scheduling weight of token (default 1)
+func (c *SwapFairnessConfig) GetTokenWeight(tokenID string) int {
+	for tid, weight := range c.TokenWeights {
+		if strings.EqualFold(tid, tokenID) && weight > 0 {
+			return weight
 		}
-		callByContractWhitelist[chainID] = make(map[string]struct{})
-		whitelist = callByContractWhitelist[chainID]
 	}
-	for _, caller := range callers {
-		key := strings.ToLower(caller)
-		if isAdd {
-			whitelist[key] = struct{}{}
-		} else {
-			delete(whitelist, key)
-		}
+	return 1
+}
+
+// GetTrustingPeriod get trusting period (default 14 days)
+func (c *LightClientConfig) GetTrustingPeriod() time.Duration {
+	if c.TrustingPeriod > 0 {
+		return time.Duration(c.TrustingPeriod) * time.Second
 	}
-	if GetExtraConfig() != nil {
-		chainWhitelist := make([]string, 0, len(whitelist))
-		for caller := range whitelist {
-			chainWhitelist = append(chainWhitelist, caller)
-		}
-		if GetExtraConfig().CallByContractWhitelist == nil {
-			GetExtraConfig().CallByContractWhitelist = make(map[string][]string)
-		}
-		GetExtraConfig().CallByContractWhitelist[chainID] = chainWhitelist
+	return 14 * 24 * time.Hour
+}
+
+// GetMaxClockDrift get max clock drift (default 10 seconds)
+func (c *LightClientConfig) GetMaxClockDrift() time.Duration {
+	if c.MaxClockDrift > 0 {
+		return time.Duration(c.MaxClockDrift) * time.Second
 	}
+	return 10 * time.Second
 }
 
-func initCallByContractCodeHashWhitelist() {
-	if GetExtraConfig() == nil || len(GetExtraConfig().CallByContractCodeHashWhitelist) == 0 {
-		return
+// GetMintBurnConfig get wrapped token mint and burn config of chain (nil if not enabled)
+func GetMintBurnConfig(chainID string) *MintBurnConfig {
+	return GetLocalChainConfig(chainID).MintBurn
+}
+
+// GetTokenKind get mint burn kind of token (empty if not minted and burned)
+func (c *MintBurnConfig) GetTokenKind(token string) string {
+	if c == nil {
+		return ""
 	}
-	allWhitelist := make(map[string]map[string]struct{})
-	for cid, whitelist := range GetExtraConfig().CallByContractCodeHashWhitelist {
-		if _, err := common.GetBigIntFromStr(cid); err != nil {
-			log.Fatal("initCallByContractCodeHashWhitelist wrong chainID", "chainID", cid, "err", err)
-		}
-		whitelistMap := make(map[string]struct{}, len(whitelist))
-		for _, codehash := range whitelist {
-			if !common.IsHexHash(codehash) {
-				log.Fatal("initCallByContractCodeHashWhitelist wrong code hash", "chainID", cid, "codehash", codehash)
-			}
-			whitelistMap[codehash] = struct{}{}
-		}
-		allWhitelist[cid] = whitelistMap
+	return c.Tokens[token]
+}
+
+// GetTokenFactoryAuthorityPath get rest path of tokenfactory denom authority metadata
+func (c *MintBurnConfig) GetTokenFactoryAuthorityPath() string {
+	if c.TokenFactoryAuthorityPath != "" {
+		return c.TokenFactoryAuthorityPath
 	}
-	callByContractCodeHashWhitelist = allWhitelist
-	log.Info("initCallByContractCodeHashWhitelist success", "isReload", IsReload)
+	return "/osmosis/tokenfactory/v1beta1/denoms/%v/authority_metadata"
 }
 
-// HasCallByContractCodeHashWhitelist has call by contract code hash whitelist
-func HasCallByContractCodeHashWhitelist(chainID string) bool {
-	whitelist, exist := callByContractCodeHashWhitelist[chainID]
-	return exist && len(whitelist) > 0
+// GetLightClientConfig get light client config of chain (nil if not enabled)
+func GetLightClientConfig(chainID string) *LightClientConfig {
+	return GetLocalChainConfig(chainID).LightClient
 }
 
-// IsInCallByContractCodeHashWhitelist is in call by contract code hash whitelist
-func IsInCallByContractCodeHashWhitelist(chainID, codehash string) bool {
-	whitelist, exist := callByContractCodeHashWhitelist[chainID]
-	if !exist {
-		return false
+// GetVerificationGasLimit get verification gas limit of user operations (default 150000)
+func (c *AccountAbstractionConfig) GetVerificationGasLimit() uint64 {
+	if c.VerificationGasLimit > 0 {
+		return c.VerificationGasLimit
 	}
-	_, exist = whitelist[codehash]
-	return exist
+	return 150000
 }
 
-// AddOrRemoveCallByContractCodeHashWhitelist add or remove call by contract code hash whitelist
-func AddOrRemoveCallByContractCodeHashWhitelist(chainID string, codehashes []string, isAdd bool) {
-	whitelist, exist := callByContractCodeHashWhitelist[chainID]
-	if !exist {
-		if !isAdd {
-			return
-		}
-		callByContractCodeHashWhitelist[chainID] = make(map[string]struct{})
-		whitelist = callByContractCodeHashWhitelist[chainID]
+// GetPreVerificationGas get pre verification gas of user operations (default 50000)
+func (c *AccountAbstractionConfig) GetPreVerificationGas() uint64 {
+	if c.PreVerificationGas > 0 {
+		return c.PreVerificationGas
 	}
-	for _, codehash := range codehashes {
-		key := codehash
-		if isAdd {
-			whitelist[key] = struct{}{}
-		} else {
-			delete(whitelist, key)
-		}
+	return 50000
+}
+
+// GetMaxVerificationGasLimit get max verification gas limit of sponsored user operations (default 1000000)
+func (c *AccountAbstractionConfig) GetMaxVerificationGasLimit() uint64 {
+	if c.MaxVerificationGasLimit > 0 {
+		return c.MaxVerificationGasLimit
 	}
-	if GetExtraConfig() != nil {
-		chainWhitelist := make([]string, 0, len(whitelist))
-		for codehash := range whitelist {
-			chainWhitelist = append(chainWhitelist, codehash)
-		}
-		if GetExtraConfig().CallByContractCodeHashWhitelist == nil {
-			GetExtraConfig().CallByContractCodeHashWhitelist = make(map[string][]string)
-		}
-		GetExtraConfig().CallByContractCodeHashWhitelist[chainID] = chainWhitelist
+	return 1000000
+}
+
+// GetMaxPreVerificationGas get max pre verification gas of sponsored user operations (default 500000)
+func (c *AccountAbstractionConfig) GetMaxPreVerificationGas() uint64 {
+	if c.MaxPreVerificationGas > 0 {
+		return c.MaxPreVerificationGas
 	}
+	return 500000
 }
 
-func initBigValueWhitelist() {
-	if GetExtraConfig() == nil || len(GetExtraConfig().BigValueWhitelist) == 0 {
-		return
+// GetAccountAbstractionConfig get erc-4337 execution config of chain (nil if not enabled)
+func GetAccountAbstractionConfig(chainID string) *AccountAbstractionConfig {
+	return GetLocalChainConfig(chainID).AccountAbstraction
+}
+
+// GetClaimPeriod get claim period (seconds) of claimable swaps (default 7 days)
+func (c *ClaimSwapConfig) GetClaimPeriod() int64 {
+	if c.ClaimPeriod > 0 {
+		return c.ClaimPeriod
 	}
-	allWhitelist := make(map[string]map[string]struct{})
-	for tid, whitelist := range GetExtraConfig().BigValueWhitelist {
-		whitelistMap := make(map[string]struct{}, len(whitelist))
-		for _, address := range whitelist {
-			if address == "" {
-				log.Fatal("initBigValueWhitelist empty address", "tokenID", tid)
-			}
-			whitelistMap[strings.ToLower(address)] = struct{}{}
+	return 7 * 86400
+}
+
+// IsClaimToken is swapins of tokenID claimed in two phases
+func (c *ClaimSwapConfig) IsClaimToken(tokenID string) bool {
+	for _, id := range c.TokenIDs {
+		if strings.EqualFold(id, tokenID) {
+			return true
 		}
-		allWhitelist[tid] = whitelistMap
 	}
-	bigValueWhitelist = allWhitelist
-	log.Info("initBigValueWhitelist success", "isReload", IsReload)
+	return false
 }
 
-// IsInBigValueWhitelist is in call by contract whitelist
-func IsInBigValueWhitelist(tokenID, caller string) bool {
-	whitelist, exist := bigValueWhitelist[tokenID]
-	if !exist {
-		return false
+// GetClaimSwapConfig get two-phase swap config of chain (nil if not enabled)
+func GetClaimSwapConfig(chainID string) *ClaimSwapConfig {
+	return GetLocalChainConfig(chainID).ClaimSwap
+}
+
+// IsClaimSwap is swapin of tokenID to chain claimed in two phases
+func IsClaimSwap(chainID, tokenID string) bool {
+	cfg := GetClaimSwapConfig(chainID)
+	return cfg != nil && cfg.IsClaimToken(tokenID)
+}
+
+// GetDeadline get valid period (seconds) of swap authorizations (default 1 hour)
+func (c *SwapAuthorizationConfig) GetDeadline() int64 {
+	if c.Deadline > 0 {
+		return c.Deadline
 	}
-	_, exist = whitelist[strings.ToLower(caller)]
-	return exist
+	return 3600
 }
 
-// AddOrRemoveBigValueWhitelist add or remove big value whitelist
-//nolint:dupl // allow duplicate
-func AddOrRemoveBigValueWhitelist(tokenID string, callers []string, isAdd bool) {
-	whitelist, exist := bigValueWhitelist[tokenID]
-	if !exist {
-		if !isAdd {
-			return
-		}
-		bigValueWhitelist[tokenID] = make(map[string]struct{})
-		whitelist = bigValueWhitelist[tokenID]
+// IsAuthorizedToken is swap of tokenID authorized
+func (c *SwapAuthorizationConfig) IsAuthorizedToken(tokenID string) bool {
+	if len(c.TokenIDs) == 0 {
+		return true
 	}
-	for _, caller := range callers {
-		key := strings.ToLower(caller)
-		if isAdd {
-			whitelist[key] = struct{}{}
-		} else {
-			delete(whitelist, key)
+	for _, id := range c.TokenIDs {
+		if strings.EqualFold(id, tokenID) {
+			return true
 		}
 	}
-	if GetExtraConfig() != nil {
-		tokenWhitelist := make([]string, 0, len(whitelist))
-		for caller := range whitelist {
-			tokenWhitelist = append(tokenWhitelist, caller)
-		}
-		if GetExtraConfig().BigValueWhitelist == nil {
-			GetExtraConfig().BigValueWhitelist = make(map[string][]string)
-		}
-		GetExtraConfig().BigValueWhitelist[tokenID] = tokenWhitelist
+	return false
+}
+
+// GetDomain get EIP-712 domain of router version
+func (c *SwapAuthorizationConfig) GetDomain(routerVersion string) *EIP712DomainConfig {
+	if routerVersion == "" {
+		routerVersion = "default"
 	}
+	return c.Domains[routerVersion]
 }
 
-// GetRouterConfig get router config
-func GetRouterConfig() *RouterConfig {
-	return routerConfig
+// GetSwapAuthorizationConfig get signed swap authorization config of chain (nil if not enabled)
+func GetSwapAuthorizationConfig(chainID string) *SwapAuthorizationConfig {
+	return GetLocalChainConfig(chainID).SwapAuthorization
 }
 
-// GetRouterServerConfig get router server config
-func GetRouterServerConfig() *RouterServerConfig {
-	return routerConfig.Server
+// IsOldTokenAccepted is deposit of the old token at height accepted
+func (c *TokenMigrationConfig) IsOldTokenAccepted(height uint64) bool {
+	return height < c.CutoverHeight+c.GraceBlocks
 }
 
-// GetRouterOracleConfig get router oracle config
-func GetRouterOracleConfig() *RouterOracleConfig {
-	return routerConfig.Oracle
+// GetTokenMigration get token migration config of tokenID (nil if not exist)
+func GetTokenMigration(chainID, tokenID string) *TokenMigrationConfig {
+	for id, migration := range GetLocalChainConfig(chainID).TokenMigrations {
+		if strings.EqualFold(id, tokenID) {
+			return migration
+		}
+	}
+	return nil
 }
 
-// GetMPCConfig get mpc config
-func GetMPCConfig(isFastMPC bool) *MPCConfig {
-	if isFastMPC {
-		return routerConfig.FastMPC
+// GetTokenMigrationByAddress get token migration config whose old or new token is address
+func GetTokenMigrationByAddress(chainID, address string) (tokenID string, migration *TokenMigrationConfig) {
+	for id, m := range GetLocalChainConfig(chainID).TokenMigrations {
+		if strings.EqualFold(m.OldToken, address) || strings.EqualFold(m.NewToken, address) {
+			return id, m
+		}
 	}
-	return routerConfig.MPC
+	return "", nil
 }
 
-// GetLocalChainConfig get local chain config
-func GetLocalChainConfig(chainID string) *LocalChainConfig {
-	if GetExtraConfig() != nil {
-		c := GetExtraConfig().LocalChainConfig[chainID]
-		if c != nil {
-			if c.lock == nil {
-				c.lock = new(sync.Mutex)
-			}
-			return c
+// GetRouterEmitters get accepted log emitters of router contract
+func GetRouterEmitters(chainID, routerContract string) []string {
+	for router, emitters := range GetLocalChainConfig(chainID).RouterEmitters {
+		if strings.EqualFold(router, routerContract) {
+			return emitters
 		}
 	}
-	return &LocalChainConfig{}
+	return nil
+}
+
+// IsAcceptedRouterEmitter is emitter the router contract itself or one of its accepted emitters
+func IsAcceptedRouterEmitter(chainID, routerContract, emitter string) bool {
+	if strings.EqualFold(emitter, routerContract) {
+		return true
+	}
+	for _, accepted := range GetRouterEmitters(chainID, routerContract) {
+		if strings.EqualFold(emitter, accepted) {
+			return true
+		}
+	}
+	return false
 }
 
 // GetSpecialFlag get special flag
@@ -1022,6 +3083,31 @@ func IsUseFastMPC(chainID string) bool {
 	return exist
 }
 
+func initShadowModeChains() {
+	tempMap := make(map[string]struct{})
+	if GetExtraConfig() != nil {
+		for _, cid := range GetExtraConfig().ShadowModeChains {
+			if _, err := common.GetBigIntFromStr(cid); err != nil {
+				log.Fatal("initShadowModeChains wrong chainID", "chainID", cid, "err", err)
+			}
+			tempMap[cid] = struct{}{}
+		}
+	}
+	shadowModeChains = tempMap
+	log.Info("initShadowModeChains success", "isReload", IsReload)
+}
+
+// GetShadowImplementation get bridge implementation running in shadow mode of chain
+func GetShadowImplementation(chainID string) string {
+	return GetLocalChainConfig(chainID).ShadowImplementation
+}
+
+// IsShadowModeChain is shadow bridge enabled on chain
+func IsShadowModeChain(chainID string) bool {
+	_, exist := shadowModeChains[chainID]
+	return exist
+}
+
 func initIncreaseNonceWhenSendTxChains() {
 	if GetExtraConfig() == nil || len(GetExtraConfig().IncreaseNonceWhenSendTxChains) == 0 {
 		return
@@ -1058,6 +3144,35 @@ func DontCheckTokenReceived(tokenID string) bool {
 	return exist
 }
 
+// accounting modes of rebasing tokens
+const (
+	// RebasingModeConservative credit the least of the logged and delivered amounts
+	// and require the delivered amount is exactly verified
+	RebasingModeConservative = "conservative"
+	// RebasingModeReject reject swapouts of the token
+	RebasingModeReject = "reject"
+)
+
+func checkRebasingTokenModes(modes map[string]string) error {
+	tempMap := make(map[string]string, len(modes))
+	for tokenID, mode := range modes {
+		switch mode {
+		case RebasingModeConservative, RebasingModeReject:
+		default:
+			return fmt.Errorf("unknown rebasing token mode '%v' of tokenID '%v'", mode, tokenID)
+		}
+		tempMap[strings.ToLower(tokenID)] = mode
+	}
+	rebasingTokenModes = tempMap
+	log.Info("init rebasing token modes success", "count", len(tempMap), "isReload", IsReload)
+	return nil
+}
+
+// GetRebasingTokenMode get accounting mode of rebasing token (empty if not rebasing)
+func GetRebasingTokenMode(tokenID string) string {
+	return rebasingTokenModes[strings.ToLower(tokenID)]
+}
+
 func initDontCheckBalanceTokenIDs() {
 	if GetExtraConfig() == nil || len(GetExtraConfig().DontCheckBalanceTokenIDs) == 0 {
 		return
@@ -1313,6 +3428,11 @@ func DontCheckInInitRouter() bool {
 	return GetExtraConfig() != nil && GetExtraConfig().DontCheckInInitRouter
 }
 
+// IsStartupCheckEnabled is startup self check enabled
+func IsStartupCheckEnabled() bool {
+	return GetExtraConfig() != nil && GetExtraConfig().EnableStartupCheck
+}
+
 // FeeReceiverOnDestChain fee receiver on dest chain
 func FeeReceiverOnDestChain(toChainID string) string {
 	c := GetLocalChainConfig(toChainID)
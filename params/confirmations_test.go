@@ -0,0 +1,52 @@
+package params
+
+import "testing"
+
+func TestCheckConfirmationTiers(t *testing.T) {
+	newTiers := func(tiers ...*ConfirmationTier) map[string]map[string][]*ConfirmationTier {
+		return map[string]map[string][]*ConfirmationTier{"1:56": {"USDC": tiers}}
+	}
+	tests := []struct {
+		tiers map[string]map[string][]*ConfirmationTier
+		ok    bool
+	}{
+		{nil, true},
+		{newTiers(&ConfirmationTier{MaxValue: "1000", Confirmations: 3}), true},
+		{newTiers(&ConfirmationTier{MaxValue: "1000", Confirmations: 3}, &ConfirmationTier{MaxValue: "100000.5", Confirmations: 12}, &ConfirmationTier{Confirmations: 64}), true},
+		{newTiers(&ConfirmationTier{MaxValue: "1000", Confirmations: 0}), false},
+		{newTiers(&ConfirmationTier{Confirmations: 3}, &ConfirmationTier{MaxValue: "1000", Confirmations: 12}), false},
+		{newTiers(&ConfirmationTier{MaxValue: "-1", Confirmations: 3}), false},
+		{newTiers(&ConfirmationTier{MaxValue: "1000", Confirmations: 3}, &ConfirmationTier{MaxValue: "1000", Confirmations: 12}), false},
+		{newTiers(&ConfirmationTier{MaxValue: "1000", Confirmations: 3}, &ConfirmationTier{MaxValue: "10", Confirmations: 12}), false},
+		{map[string]map[string][]*ConfirmationTier{"1:*": {"USDC": {{Confirmations: 3}}}}, false},
+		{map[string]map[string][]*ConfirmationTier{"1-56": {"USDC": {{Confirmations: 3}}}}, false},
+	}
+	for i, test := range tests {
+		err := checkConfirmationTiers(test.tiers)
+		if (err == nil) != test.ok {
+			t.Errorf("test %v: check confirmation tiers got err %v, want ok %v", i, err, test.ok)
+		}
+	}
+}
+
+func TestGetConfirmationTiers(t *testing.T) {
+	err := SetExtraConfig(&ExtraConfig{
+		ConfirmationTiers: map[string]map[string][]*ConfirmationTier{
+			"1:56": {"USDC": {{MaxValue: "1000", Confirmations: 3}, {Confirmations: 64}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = SetExtraConfig(&ExtraConfig{}) }()
+
+	if tiers := GetConfirmationTiers("1", "56", "USDC"); len(tiers) != 2 || tiers[0].Confirmations != 3 {
+		t.Errorf("confirmation tiers got %v items, want 2", len(tiers))
+	}
+	if tiers := GetConfirmationTiers("56", "1", "USDC"); len(tiers) != 0 {
+		t.Errorf("confirmation tiers of reverse chain pair got %v items, want none", len(tiers))
+	}
+	if tiers := GetConfirmationTiers("1", "56", "USDT"); len(tiers) != 0 {
+		t.Errorf("confirmation tiers of other token got %v items, want none", len(tiers))
+	}
+}
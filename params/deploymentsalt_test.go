@@ -0,0 +1,23 @@
+package params
+
+import "testing"
+
+func TestDeploymentSaltFormat(t *testing.T) {
+	for _, test := range []struct {
+		salt  string
+		valid bool
+	}{
+		{"deployment-a", true},
+		{"Deploy_01", true},
+		{"abcd", true},
+		{"abc", false},
+		{"0123456789abcdef0123456789abcdef", true},
+		{"0123456789abcdef0123456789abcdef0", false},
+		{"deployment a", false},
+		{"deployment:a", false},
+	} {
+		if valid := deploymentSaltRegexp.MatchString(test.salt); valid != test.valid {
+			t.Errorf("deployment salt %q got valid %v, want %v", test.salt, valid, test.valid)
+		}
+	}
+}
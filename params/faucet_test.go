@@ -0,0 +1,49 @@
+package params
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestFaucetConfig(testnet bool, chainID string) *FaucetConfig {
+	return &FaucetConfig{
+		Enable:  true,
+		Testnet: testnet,
+		APIKeys: []string{"key"},
+		Chains: map[string]*FaucetChainConfig{
+			chainID: {PrivateKey: "0x01", MintAmounts: map[string]string{"USDC": "1000"}},
+		},
+	}
+}
+
+func TestFaucetConfigTestnetOnly(t *testing.T) {
+	if err := newTestFaucetConfig(true, "11155111").CheckConfig(); err != nil {
+		t.Fatalf("faucet of testnet should be allowed, err %v", err)
+	}
+	if err := newTestFaucetConfig(false, "11155111").CheckConfig(); err == nil || !strings.Contains(err.Error(), "Testnet") {
+		t.Errorf("faucet without explicit testnet flag got error %v", err)
+	}
+	for _, chainID := range []string{"1", "56", "137", "999999"} {
+		if err := newTestFaucetConfig(true, chainID).CheckConfig(); err == nil || !strings.Contains(err.Error(), "not a known testnet") {
+			t.Errorf("faucet of chain %v got error %v", chainID, err)
+		}
+	}
+	disabled := newTestFaucetConfig(false, "1")
+	disabled.Enable = false
+	if err := disabled.CheckConfig(); err != nil {
+		t.Errorf("disabled faucet should not be checked, err %v", err)
+	}
+}
+
+func TestFaucetMintAmount(t *testing.T) {
+	c := newTestFaucetConfig(true, "97")
+	if err := c.CheckConfig(); err != nil {
+		t.Fatalf("check faucet config failed: %v", err)
+	}
+	if amount := c.GetMintAmount("97", "usdc"); amount == nil || amount.String() != "1000" {
+		t.Errorf("faucet mint amount got %v, want 1000", amount)
+	}
+	if amount := c.GetMintAmount("5", "USDC"); amount != nil {
+		t.Errorf("faucet mint amount of unconfigured chain got %v, want nil", amount)
+	}
+}
@@ -0,0 +1,148 @@
+package params
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// feature flags gating risky behaviors (enabled by default).
+// they can be disabled in config per deployment or per chain for gradual rollout,
+// and overridden by admin calls (persisted by the server) as instant kill-switches.
+const (
+	FeatureParallelSwap = "parallelSwap" // process swaps in parallel mode (if enabled in config)
+	FeatureAutoReswap   = "autoReswap"   // reswap timed out swaps automatically
+	FeatureBatchSend    = "batchSend"    // submit signed txs in batches
+	FeatureAggregate    = "aggregate"    // aggregate utxos of mpc
+	FeatureBridge       = "bridge"       // process swaps from or to the chain
+
+	// FeatureFlagAllChains chainID of feature flag overrides applying to all chains
+	FeatureFlagAllChains = "all"
+)
+
+var (
+	allFeatureFlags = []string{
+		FeatureParallelSwap,
+		FeatureAutoReswap,
+		FeatureBatchSend,
+		FeatureAggregate,
+		FeatureBridge,
+	}
+
+	// flag:chainID -> enabled
+	featureFlagOverrides     = make(map[string]bool)
+	featureFlagOverridesLock sync.RWMutex
+)
+
+// FeatureFlagOverride admin override of feature flag
+type FeatureFlagOverride struct {
+	Flag    string `json:"flag"`
+	ChainID string `json:"chainID"` // 'all' means all chains
+	Enabled bool   `json:"enabled"`
+}
+
+// GetAllFeatureFlags get names of all feature flags
+func GetAllFeatureFlags() []string {
+	return allFeatureFlags
+}
+
+// IsValidFeatureFlag is valid feature flag name
+func IsValidFeatureFlag(flag string) bool {
+	for _, f := range allFeatureFlags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+func getFeatureFlagKey(flag, chainID string) string {
+	return flag + ":" + chainID
+}
+
+// IsFeatureEnabled is feature enabled on chain.
+// the first found of chain override, all chains override,
+// chain config and deployment config is used, default is enabled.
+func IsFeatureEnabled(flag, chainID string) bool {
+	featureFlagOverridesLock.RLock()
+	enabled, exist := featureFlagOverrides[getFeatureFlagKey(flag, chainID)]
+	if !exist {
+		enabled, exist = featureFlagOverrides[getFeatureFlagKey(flag, FeatureFlagAllChains)]
+	}
+	featureFlagOverridesLock.RUnlock()
+	if exist {
+		return enabled
+	}
+	if enabled, exist = GetLocalChainConfig(chainID).FeatureFlags[flag]; exist {
+		return enabled
+	}
+	if GetExtraConfig() != nil {
+		if enabled, exist = GetExtraConfig().FeatureFlags[flag]; exist {
+			return enabled
+		}
+	}
+	return true
+}
+
+// IsParallelSwapEnabledOnChain is swaps to chain processed in parallel mode,
+// the chain falls back to serial mode if the parallelSwap feature is disabled.
+func IsParallelSwapEnabledOnChain(chainID string) bool {
+	return IsParallelSwapEnabled() && IsFeatureEnabled(FeatureParallelSwap, chainID)
+}
+
+// SetFeatureFlagOverride set admin override of feature flag
+func SetFeatureFlagOverride(flag, chainID string, enabled bool) {
+	featureFlagOverridesLock.Lock()
+	defer featureFlagOverridesLock.Unlock()
+	featureFlagOverrides[getFeatureFlagKey(flag, chainID)] = enabled
+}
+
+// RemoveFeatureFlagOverride remove admin override of feature flag
+func RemoveFeatureFlagOverride(flag, chainID string) {
+	featureFlagOverridesLock.Lock()
+	defer featureFlagOverridesLock.Unlock()
+	delete(featureFlagOverrides, getFeatureFlagKey(flag, chainID))
+}
+
+// ResetFeatureFlagOverrides replace all admin overrides of feature flags (eg. loaded from db)
+func ResetFeatureFlagOverrides(overrides []*FeatureFlagOverride) {
+	m := make(map[string]bool, len(overrides))
+	for _, o := range overrides {
+		m[getFeatureFlagKey(o.Flag, o.ChainID)] = o.Enabled
+	}
+	featureFlagOverridesLock.Lock()
+	defer featureFlagOverridesLock.Unlock()
+	featureFlagOverrides = m
+}
+
+// GetFeatureFlagOverrides get admin overrides of feature flags
+func GetFeatureFlagOverrides() []*FeatureFlagOverride {
+	featureFlagOverridesLock.RLock()
+	defer featureFlagOverridesLock.RUnlock()
+	result := make([]*FeatureFlagOverride, 0, len(featureFlagOverrides))
+	for key, enabled := range featureFlagOverrides {
+		parts := strings.SplitN(key, ":", 2)
+		result = append(result, &FeatureFlagOverride{
+			Flag:    parts[0],
+			ChainID: parts[1],
+			Enabled: enabled,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Flag != result[j].Flag {
+			return result[i].Flag < result[j].Flag
+		}
+		return result[i].ChainID < result[j].ChainID
+	})
+	return result
+}
+
+func checkFeatureFlags(flags map[string]bool) error {
+	for flag := range flags {
+		if !IsValidFeatureFlag(flag) {
+			return fmt.Errorf("unknown feature flag '%v'", flag)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package params
+
+import "testing"
+
+func TestIsParallelSwapEnabledOnChain(t *testing.T) {
+	defer ResetFeatureFlagOverrides(nil)
+	err := SetExtraConfig(&ExtraConfig{
+		EnableParallelSwap: true,
+		LocalChainConfig: map[string]*LocalChainConfig{
+			"56": {FeatureFlags: map[string]bool{FeatureParallelSwap: false}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	if !IsParallelSwapEnabledOnChain("1") {
+		t.Errorf("parallel swap should be enabled on chain 1")
+	}
+	if IsParallelSwapEnabledOnChain("56") {
+		t.Errorf("chain disabling parallel swap should fall back to serial mode")
+	}
+
+	SetFeatureFlagOverride(FeatureParallelSwap, "1", false)
+	if IsParallelSwapEnabledOnChain("1") {
+		t.Errorf("admin override disabling parallel swap should fall back to serial mode")
+	}
+	SetFeatureFlagOverride(FeatureParallelSwap, "56", true)
+	if !IsParallelSwapEnabledOnChain("56") {
+		t.Errorf("admin override should take precedence over chain config")
+	}
+
+	if err = SetExtraConfig(&ExtraConfig{}); err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	if IsParallelSwapEnabledOnChain("56") {
+		t.Errorf("parallel swap is not enabled in config")
+	}
+}
@@ -0,0 +1,21 @@
+package params
+
+import "testing"
+
+func TestCheckFeeDenomPreference(t *testing.T) {
+	tests := []struct {
+		prefs   map[string][]string
+		wantErr bool
+	}{
+		{nil, false},
+		{map[string][]string{"cosmoshub-4": {"6250uatom", "5000ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"}}, false},
+		{map[string][]string{"cosmoshub-4": {"6250uatom", "5000"}}, true},
+		{map[string][]string{"cosmoshub-4": {""}}, true},
+	}
+	for i, test := range tests {
+		err := checkFeeDenomPreference(test.prefs)
+		if (err != nil) != test.wantErr {
+			t.Errorf("test %v: check fee denom preference %v got error %v, want error %v", i, test.prefs, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package params
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+)
+
+// LoadKeys load data keys of field encryption from KMS or key file
+func (c *FieldEncryptionConfig) LoadKeys() (map[string][]byte, string, error) {
+	var keys *FieldEncryptionKeys
+	if c.KMSURL != "" {
+		var headers map[string]string
+		if c.KMSAuthToken != "" {
+			headers = map[string]string{"Authorization": "Bearer " + c.KMSAuthToken}
+		}
+		if err := client.RPCGetRequest(&keys, c.KMSURL, nil, headers, 60); err != nil {
+			return nil, "", fmt.Errorf("get field encryption keys from kms failed: %w", err)
+		}
+	} else {
+		data, err := os.ReadFile(c.KeyFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("read field encryption key file failed: %w", err)
+		}
+		if err = json.Unmarshal(data, &keys); err != nil {
+			return nil, "", fmt.Errorf("parse field encryption key file failed: %w", err)
+		}
+	}
+	if keys == nil || keys.Current == "" {
+		return nil, "", errors.New("field encryption keys has no current key")
+	}
+	result := make(map[string][]byte, len(keys.Keys))
+	for keyID, keyHex := range keys.Keys {
+		key, err := hex.DecodeString(keyHex)
+		if err != nil || len(key) != 32 {
+			return nil, "", fmt.Errorf("field encryption key '%v' is not hex encoded 32 bytes", keyID)
+		}
+		result[keyID] = key
+	}
+	if _, exist := result[keys.Current]; !exist {
+		return nil, "", fmt.Errorf("field encryption current key '%v' not found", keys.Current)
+	}
+	return result, keys.Current, nil
+}
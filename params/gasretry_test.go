@@ -0,0 +1,36 @@
+package params
+
+import "testing"
+
+func TestGasRetryConfig(t *testing.T) {
+	tests := []struct {
+		cfg         *GasRetryConfig
+		wantErr     bool
+		maxAttempts int
+		adjustments []float64
+	}{
+		{&GasRetryConfig{}, false, 3, []float64{1.5, 2, 2.5}},
+		{&GasRetryConfig{MaxAttempts: 2, GasAdjustment: 1.2, AdjustmentStep: 0.3}, false, 2, []float64{1.2, 1.5}},
+		{&GasRetryConfig{MaxAttempts: -1}, true, 0, nil},
+		{&GasRetryConfig{GasAdjustment: 0.9}, true, 0, nil},
+		{&GasRetryConfig{GasAdjustment: 1}, true, 0, nil},
+		{&GasRetryConfig{AdjustmentStep: -0.5}, true, 0, nil},
+	}
+	for i, test := range tests {
+		err := test.cfg.CheckConfig()
+		if (err != nil) != test.wantErr {
+			t.Errorf("test %v: check gas retry config got error %v, want error %v", i, err, test.wantErr)
+		}
+		if test.wantErr {
+			continue
+		}
+		if got := test.cfg.GetMaxAttempts(); got != test.maxAttempts {
+			t.Errorf("test %v: max attempts got %v, want %v", i, got, test.maxAttempts)
+		}
+		for j, want := range test.adjustments {
+			if got := test.cfg.GetAdjustment(j + 1); got != want {
+				t.Errorf("test %v: adjustment of attempt %v got %v, want %v", i, j+1, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package params
+
+import "testing"
+
+func TestIntegrityCheckConfig(t *testing.T) {
+	var disabled *IntegrityCheckConfig
+	if err := disabled.CheckConfig(); err != nil {
+		t.Errorf("check disabled integrity check config failed: %v", err)
+	}
+	cfg := &IntegrityCheckConfig{}
+	if err := cfg.CheckConfig(); err != nil {
+		t.Errorf("check default integrity check config failed: %v", err)
+	}
+	if cfg.GetInterval() != 3600 || cfg.GetScanWindow() != 7*86400 || cfg.GetGracePeriod() != 600 || cfg.GetBatchSize() != 5000 {
+		t.Errorf("default integrity check config got interval %v, scan window %v, grace period %v, batch size %v",
+			cfg.GetInterval(), cfg.GetScanWindow(), cfg.GetGracePeriod(), cfg.GetBatchSize())
+	}
+	for _, bad := range []*IntegrityCheckConfig{{Interval: -1}, {ScanWindow: -1}, {GracePeriod: -1}, {BatchSize: -1}} {
+		if err := bad.CheckConfig(); err == nil {
+			t.Errorf("check integrity check config %+v should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package params
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLedgerStreamConfig(t *testing.T) {
+	cfg := &LedgerStreamConfig{WSServers: []string{"wss://s1.ripple.com:443"}}
+	if err := cfg.CheckConfig(); err != nil {
+		t.Fatalf("check ledger stream config failed: %v", err)
+	}
+	if timeout := cfg.GetIdleTimeout(); timeout != 30*time.Second {
+		t.Errorf("default idle timeout got %v, want 30s", timeout)
+	}
+	cfg.IdleTimeout = 10
+	if timeout := cfg.GetIdleTimeout(); timeout != 10*time.Second {
+		t.Errorf("idle timeout got %v, want 10s", timeout)
+	}
+
+	cfg.IdleTimeout = -1
+	if err := cfg.CheckConfig(); err == nil {
+		t.Errorf("check negative idle timeout should fail")
+	}
+	cfg.IdleTimeout = 0
+	cfg.MaxMessageSize = -1
+	if err := cfg.CheckConfig(); err == nil {
+		t.Errorf("check negative max message size should fail")
+	}
+}
@@ -0,0 +1,31 @@
+package params
+
+import "testing"
+
+func TestGetQuarantineQuorum(t *testing.T) {
+	oldServer := routerConfig.Server
+	defer func() { routerConfig.Server = oldServer }()
+
+	routerConfig.Server = &RouterServerConfig{}
+	if quorum := GetQuarantineQuorum(3); quorum != 3 {
+		t.Errorf("default quorum got %v, want all 3 endpoints", quorum)
+	}
+	if timeout := GetQuarantineNotFoundTimeout(); timeout != 3*86400 {
+		t.Errorf("default not found timeout got %v, want %v", timeout, 3*86400)
+	}
+
+	routerConfig.Server.Quarantine = &QuarantineConfig{Quorum: 2, NotFoundTimeout: 3600}
+	if quorum := GetQuarantineQuorum(3); quorum != 2 {
+		t.Errorf("quorum got %v, want 2", quorum)
+	}
+	if quorum := GetQuarantineQuorum(1); quorum != 1 {
+		t.Errorf("quorum more than endpoints got %v, want 1", quorum)
+	}
+	if timeout := GetQuarantineNotFoundTimeout(); timeout != 3600 {
+		t.Errorf("not found timeout got %v, want 3600", timeout)
+	}
+
+	if err := (&QuarantineConfig{Quorum: -1}).CheckConfig(); err == nil {
+		t.Errorf("check negative quorum should fail")
+	}
+}
@@ -0,0 +1,69 @@
+package params
+
+import "testing"
+
+func TestReplaceGuardConfig(t *testing.T) {
+	for i, cfg := range []*ReplaceGuardConfig{
+		{CoinPrices: map[string]string{"USDC": "3000"}},
+		{MaxValuePercent: 101, CoinPrices: map[string]string{"USDC": "3000"}},
+		{MaxFeeMultiple: 3},
+		{MaxFeeMultiple: 3, CoinPrices: map[string]string{"USDC": "0"}},
+		{MaxFeeMultiple: 3, CoinPrices: map[string]string{"USDC": "abc"}},
+		{MaxFeeMultiple: 3, PriceOracles: map[string]*RateOracleConfig{"USDC": nil}},
+		{MaxFeeMultiple: 3, PriceOracles: map[string]*RateOracleConfig{"USDC": {Type: "jsonapi"}}},
+		{MaxFeeMultiple: 3, PriceOracles: map[string]*RateOracleConfig{"USDC": {Type: "unknown"}}},
+	} {
+		if err := cfg.CheckConfig(); err == nil {
+			t.Errorf("test %v: check wrong replace guard config should fail", i)
+		}
+	}
+
+	cfg := &ReplaceGuardConfig{MaxFeeMultiple: 3, CoinPrices: map[string]string{"USDC": "3000.5"}}
+	if err := cfg.CheckConfig(); err != nil {
+		t.Fatalf("check replace guard config failed: %v", err)
+	}
+	if price := cfg.GetCoinPrice("usdc"); price == nil || price.String() != "3000.5" {
+		t.Errorf("coin price got %v, want 3000.5", price)
+	}
+	if price := cfg.GetCoinPrice("USDT"); price != nil {
+		t.Errorf("coin price of token without price got %v", price)
+	}
+	if decimals := cfg.GetCoinDecimals(); decimals != 18 {
+		t.Errorf("default coin decimals got %v, want 18", decimals)
+	}
+	cfg.CoinDecimals = 8
+	if decimals := cfg.GetCoinDecimals(); decimals != 8 {
+		t.Errorf("coin decimals got %v, want 8", decimals)
+	}
+}
+
+func TestReplaceGuardPriceOracles(t *testing.T) {
+	oracle := &RateOracleConfig{Type: "jsonapi", URL: "http://127.0.0.1/price", JSONPath: "usdc"}
+	cfg := &ReplaceGuardConfig{MaxFeeMultiple: 3, PriceOracles: map[string]*RateOracleConfig{"USDC": oracle}}
+	if err := cfg.CheckConfig(); err != nil {
+		t.Fatalf("check replace guard config with only price oracles failed: %v", err)
+	}
+	if oracle.TokenID != "USDC" {
+		t.Errorf("price oracle token id got %v, want USDC", oracle.TokenID)
+	}
+
+	err := SetExtraConfig(&ExtraConfig{
+		LocalChainConfig: map[string]*LocalChainConfig{
+			"56": {ReplaceGuard: cfg},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = SetExtraConfig(&ExtraConfig{}) }()
+
+	if got := GetCoinPriceOracleConfig("56", "usdc"); got != oracle {
+		t.Errorf("price oracle of usdc got %v, want %v", got, oracle)
+	}
+	if got := GetCoinPriceOracleConfig("56", "USDT"); got != nil {
+		t.Errorf("price oracle of token without oracle got %v", got)
+	}
+	if got := GetCoinPriceOracleConfig("1", "USDC"); got != nil {
+		t.Errorf("price oracle of chain without replace guard got %v", got)
+	}
+}
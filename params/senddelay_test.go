@@ -0,0 +1,20 @@
+package params
+
+import "testing"
+
+func TestSendDelayConfig(t *testing.T) {
+	tests := []struct {
+		maxDelay int64
+		wantErr  bool
+	}{
+		{60, false},
+		{0, true},
+		{-1, true},
+	}
+	for i, test := range tests {
+		c := &LocalChainConfig{SendDelay: &SendDelayConfig{MaxDelay: test.maxDelay}}
+		if err := c.CheckConfig(); (err != nil) != test.wantErr {
+			t.Errorf("test %v: check send delay config got error %v, want error %v", i, err, test.wantErr)
+		}
+	}
+}
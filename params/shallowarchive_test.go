@@ -0,0 +1,19 @@
+package params
+
+import "testing"
+
+func TestShallowArchiveConfig(t *testing.T) {
+	tests := []struct {
+		cfg     *ShallowArchiveConfig
+		wantErr bool
+	}{
+		{&ShallowArchiveConfig{PersistReceipts: true, PrunedErrors: []string{"receipt is unavailable"}}, false},
+		{&ShallowArchiveConfig{PrunedErrors: []string{" "}}, true},
+	}
+	for i, test := range tests {
+		c := &LocalChainConfig{ShallowArchive: test.cfg}
+		if err := c.CheckConfig(); (err != nil) != test.wantErr {
+			t.Errorf("test %v: check shallow archive config got error %v, want error %v", i, err, test.wantErr)
+		}
+	}
+}
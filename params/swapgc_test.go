@@ -0,0 +1,24 @@
+package params
+
+import "testing"
+
+func TestSwapGCOldSwapTxsRetention(t *testing.T) {
+	oldServer := routerConfig.Server
+	defer func() { routerConfig.Server = oldServer }()
+
+	gcCfg := &SwapGCConfig{Retention: 86400}
+	routerConfig.Server = &RouterServerConfig{SwapGC: gcCfg}
+	if retention := gcCfg.GetOldSwapTxsRetention(); retention != 86400 {
+		t.Errorf("retention without duplicate check got %v, want 86400", retention)
+	}
+
+	// old swap txs are kept during the scan window of duplicate check
+	routerConfig.Server.DuplicateCheck = &DuplicateCheckConfig{}
+	if retention := gcCfg.GetOldSwapTxsRetention(); retention != 3*86400 {
+		t.Errorf("retention with default scan window got %v, want %v", retention, 3*86400)
+	}
+	routerConfig.Server.DuplicateCheck.ScanWindow = 3600
+	if retention := gcCfg.GetOldSwapTxsRetention(); retention != 86400 {
+		t.Errorf("retention longer than scan window got %v, want 86400", retention)
+	}
+}
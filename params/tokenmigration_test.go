@@ -0,0 +1,66 @@
+package params
+
+import "testing"
+
+const (
+	testOldToken = "0x7777777777777777777777777777777777777777"
+	testNewToken = "0x8888888888888888888888888888888888888888"
+)
+
+func TestTokenMigrationConfig(t *testing.T) {
+	tests := []struct {
+		cfg     *TokenMigrationConfig
+		wantErr bool
+	}{
+		{&TokenMigrationConfig{OldToken: testOldToken, NewToken: testNewToken, CutoverHeight: 100}, false},
+		{&TokenMigrationConfig{OldToken: "0x77", NewToken: testNewToken, CutoverHeight: 100}, true},
+		{&TokenMigrationConfig{OldToken: testOldToken, NewToken: "", CutoverHeight: 100}, true},
+		{&TokenMigrationConfig{OldToken: testOldToken, NewToken: testOldToken, CutoverHeight: 100}, true},
+		{&TokenMigrationConfig{OldToken: testOldToken, NewToken: testNewToken}, true},
+	}
+	for i, test := range tests {
+		if err := test.cfg.CheckConfig(); (err != nil) != test.wantErr {
+			t.Errorf("test %v: check token migration config got error %v, want error %v", i, err, test.wantErr)
+		}
+	}
+
+	cfg := &TokenMigrationConfig{OldToken: testOldToken, NewToken: testNewToken, CutoverHeight: 100, GraceBlocks: 10}
+	if !cfg.IsOldTokenAccepted(109) || cfg.IsOldTokenAccepted(110) {
+		t.Errorf("old token should be accepted until the grace window ends at height 110")
+	}
+}
+
+func TestGetTokenMigration(t *testing.T) {
+	migration := &TokenMigrationConfig{OldToken: testOldToken, NewToken: testNewToken, CutoverHeight: 100}
+	err := SetExtraConfig(&ExtraConfig{
+		LocalChainConfig: map[string]*LocalChainConfig{
+			"1": {TokenMigrations: map[string]*TokenMigrationConfig{"USDC": migration}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = SetExtraConfig(&ExtraConfig{}) }()
+
+	if GetTokenMigration("1", "usdc") != migration || GetTokenMigration("1", "USDT") != nil || GetTokenMigration("56", "USDC") != nil {
+		t.Errorf("get token migration should only find the migration of USDC on chain 1")
+	}
+	for _, address := range []string{testOldToken, testNewToken} {
+		if tokenID, m := GetTokenMigrationByAddress("1", address); tokenID != "USDC" || m != migration {
+			t.Errorf("get token migration by address %v got (%v, %v)", address, tokenID, m)
+		}
+	}
+	if tokenID, m := GetTokenMigrationByAddress("1", "0x9999999999999999999999999999999999999999"); tokenID != "" || m != nil {
+		t.Errorf("get token migration by other address got (%v, %v)", tokenID, m)
+	}
+
+	bad := &TokenMigrationConfig{OldToken: testOldToken, NewToken: testOldToken, CutoverHeight: 100}
+	err = SetExtraConfig(&ExtraConfig{
+		LocalChainConfig: map[string]*LocalChainConfig{
+			"1": {TokenMigrations: map[string]*TokenMigrationConfig{"USDC": bad}},
+		},
+	})
+	if err == nil {
+		t.Errorf("set extra config with wrong token migration should fail")
+	}
+}
@@ -0,0 +1,75 @@
+package params
+
+import "testing"
+
+func setTestTokenRouteWhitelist(t *testing.T, whitelist map[string][]string) {
+	if err := SetExtraConfig(&ExtraConfig{TokenRouteWhitelist: whitelist}); err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	initTokenRouteWhitelist()
+}
+
+func TestIsTokenRouteAllowed(t *testing.T) {
+	setTestTokenRouteWhitelist(t, map[string][]string{"USDC": {"1:56", "56:*"}})
+
+	for _, test := range []struct {
+		tokenID, from, to string
+		allowed           bool
+	}{
+		{"usdc", "1", "56", true},
+		{"USDC", "56", "137", true},
+		{"USDC", "1", "137", false},
+		{"USDC", "137", "1", false},
+		{"USDT", "1", "137", true},
+	} {
+		if got := IsTokenRouteAllowed(test.tokenID, test.from, test.to); got != test.allowed {
+			t.Errorf("token %v route %v:%v allowed got %v, want %v", test.tokenID, test.from, test.to, got, test.allowed)
+		}
+	}
+}
+
+func TestRemoveLastTokenRoute(t *testing.T) {
+	setTestTokenRouteWhitelist(t, map[string][]string{"USDC": {"1:56"}})
+
+	if err := AddOrRemoveTokenRouteWhitelist("USDC", []string{"1:56"}, false); err != nil {
+		t.Fatalf("remove token route failed: %v", err)
+	}
+	// the active whitelist without routes rejects all directions
+	if IsTokenRouteAllowed("USDC", "1", "56") || IsTokenRouteAllowed("USDC", "56", "1") {
+		t.Errorf("removing the last route should not allow all directions")
+	}
+	if routes, exist := GetExtraConfig().TokenRouteWhitelist["usdc"]; !exist || len(routes) != 0 {
+		t.Errorf("active whitelist without routes should be kept in config, got %v (exist %v)", routes, exist)
+	}
+
+	SetTokenRouteWhitelistActive("USDC", false)
+	if !IsTokenRouteAllowed("USDC", "1", "137") {
+		t.Errorf("token with deactivated whitelist should be allowed in all directions")
+	}
+	if _, exist := GetExtraConfig().TokenRouteWhitelist["usdc"]; exist {
+		t.Errorf("deactivated whitelist should be removed from config")
+	}
+}
+
+func TestAddTokenRouteToUnrestrictedToken(t *testing.T) {
+	setTestTokenRouteWhitelist(t, nil)
+
+	if err := AddOrRemoveTokenRouteWhitelist("USDC", []string{"1:56"}, true); err != nil {
+		t.Fatalf("add token route failed: %v", err)
+	}
+	if !IsTokenRouteAllowed("USDC", "1", "137") {
+		t.Errorf("adding a route should not restrict an unrestricted token")
+	}
+
+	SetTokenRouteWhitelistActive("USDC", true)
+	if !IsTokenRouteAllowed("USDC", "1", "56") || IsTokenRouteAllowed("USDC", "1", "137") {
+		t.Errorf("activated whitelist should only allow the added routes")
+	}
+	if routes := GetExtraConfig().TokenRouteWhitelist["usdc"]; len(routes) != 1 || routes[0] != "1:56" {
+		t.Errorf("activated whitelist in config got %v", routes)
+	}
+
+	if err := AddOrRemoveTokenRouteWhitelist("USDC", []string{"1:56:137"}, true); err == nil {
+		t.Errorf("wrong token route should be rejected")
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !watcher
+
+package params
+
+// IsWatcherMode is watcher mode (build with `-tags watcher`),
+// which refuses signing and sending at the mpc and bridge layer.
+const IsWatcherMode = false
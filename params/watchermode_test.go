@@ -0,0 +1,25 @@
+//go:build !watcher
+
+package params
+
+import "testing"
+
+func TestNotWatcherMode(t *testing.T) {
+	if IsWatcherMode {
+		t.Fatalf("default build should not be watcher mode")
+	}
+	mpcCfg := &MPCConfig{SignWithPrivateKey: true, SignerPrivateKeys: map[string]string{"1": "0x01"}}
+	if err := mpcCfg.CheckConfig(true); err != nil {
+		t.Errorf("check mpc config of signing with private key got err %v", err)
+	}
+	if key := mpcCfg.GetSignerPrivateKey("1"); key != "0x01" {
+		t.Errorf("signer private key got %v, want 0x01", key)
+	}
+	faucetCfg := newTestFaucetConfig(true, "11155111")
+	if err := faucetCfg.CheckConfig(); err != nil {
+		t.Errorf("check faucet config got err %v", err)
+	}
+	if key := faucetCfg.GetPrivateKey("11155111"); key != "0x01" {
+		t.Errorf("faucet private key got %v, want 0x01", key)
+	}
+}
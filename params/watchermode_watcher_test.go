@@ -0,0 +1,33 @@
+//go:build watcher
+
+package params
+
+import "testing"
+
+func TestWatcherMode(t *testing.T) {
+	if !IsWatcherMode {
+		t.Fatalf("watcher build should be watcher mode")
+	}
+	mpcCfg := &MPCConfig{SignWithPrivateKey: true, SignerPrivateKeys: map[string]string{"1": "0x01"}}
+	if err := mpcCfg.CheckConfig(true); err == nil {
+		t.Errorf("signing with private key should be refused in watcher mode")
+	}
+	if key := mpcCfg.GetSignerPrivateKey("1"); key != "" {
+		t.Errorf("signer private key should not be loaded in watcher mode")
+	}
+	faucetCfg := newTestFaucetConfig(true, "11155111")
+	if err := faucetCfg.CheckConfig(); err == nil {
+		t.Errorf("faucet should be refused in watcher mode")
+	}
+	if key := faucetCfg.GetPrivateKey("11155111"); key != "" {
+		t.Errorf("faucet private key should not be loaded in watcher mode")
+	}
+
+	cfg := GetRouterConfig()
+	oldServer := cfg.Server
+	cfg.Server = &RouterServerConfig{APIServer: &APIServerConfig{Faucet: faucetCfg}}
+	defer func() { cfg.Server = oldServer }()
+	if GetFaucetConfig() != nil {
+		t.Errorf("faucet should be disabled in watcher mode")
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tools"
@@ -43,8 +44,33 @@ func (m gatewayQualityMap) String() string {
 }
 
 type adjustContext struct {
-	WeightedAPIs   tools.WeightedStringSlice
-	GatewayQuality gatewayQualityMap
+	WeightedAPIs     tools.WeightedStringSlice
+	GatewayQuality   gatewayQualityMap
+	RejectedGateways map[string]string `json:",omitempty"` // url -> reason
+}
+
+// verifyGateways check gateways against config and peers if supported,
+// and record the rejected gateways which are never used.
+func verifyGateways(bridge tokens.IBridge, chainID string, adjustCtx *adjustContext, urls []string) {
+	verifier, ok := bridge.(tokens.GatewayVerifier)
+	if !ok || params.GetGatewayCheckConfig(chainID) == nil {
+		return
+	}
+	rejected := verifier.VerifyGateways(chainID, urls)
+	for url, err := range rejected {
+		if _, exist := adjustCtx.RejectedGateways[url]; !exist {
+			log.Error("reject mismatched gateway", "url", url, "chainID", chainID, "err", err)
+		}
+	}
+	for url := range adjustCtx.RejectedGateways {
+		if _, exist := rejected[url]; !exist {
+			log.Info("recover mismatched gateway", "url", url, "chainID", chainID)
+		}
+	}
+	adjustCtx.RejectedGateways = make(map[string]string, len(rejected))
+	for url, err := range rejected {
+		adjustCtx.RejectedGateways[url] = err.Error()
+	}
 }
 
 // AdjustGatewayOrder adjust gateway order once
@@ -64,11 +90,15 @@ func AdjustGatewayOrder(bridge tokens.IBridge, chainID string) {
 	adjustCtx = gateway.AdjustContext.(*adjustContext)
 	var maxHeight uint64
 	originURLs := gateway.OriginAllGatewayURLs
+	verifyGateways(bridge, chainID, adjustCtx, originURLs)
 	for i := len(originURLs); i > 0; i-- { // query in reverse order
 		if utils.IsCleanuping() {
 			return
 		}
 		apiAddress := originURLs[i-1]
+		if _, rejected := adjustCtx.RejectedGateways[apiAddress]; rejected {
+			continue
+		}
 		if adjustCtx.GatewayQuality[apiAddress] == nil {
 			adjustCtx.GatewayQuality[apiAddress] = &gatewayQuality{}
 		}
@@ -105,14 +135,19 @@ func AdjustGatewayOrder(bridge tokens.IBridge, chainID string) {
 		weightedAPIs = weightedAPIs.Sort()
 		gateway.AllGatewayURLs = weightedAPIs.GetStrings()
 	} else if len(originURLs) > 0 {
-		// no one is usable, then recover to the original state
-		gateway.AllGatewayURLs = gateway.OriginAllGatewayURLs
-		log.Info("reset to original gateways", "chainID", chainID, "count", len(gateway.AllGatewayURLs))
+		// no one is usable, then recover to the original state (except the rejected)
+		gateway.AllGatewayURLs = make([]string, 0, len(originURLs))
+		for _, url := range originURLs {
+			if _, rejected := adjustCtx.RejectedGateways[url]; !rejected {
+				gateway.AllGatewayURLs = append(gateway.AllGatewayURLs, url)
+			}
+		}
+		log.Info("reset to original gateways", "chainID", chainID, "count", len(gateway.AllGatewayURLs), "rejected", len(adjustCtx.RejectedGateways))
 	}
 	adjustCtx.WeightedAPIs = weightedAPIs
 
 	if _, exist := adjustGatewayChains.Load(chainID); !exist {
-		log.Info(fmt.Sprintf("adjust gateways of chain %v", chainID), "result", adjustCtx.WeightedAPIs, "gatewayQuality", adjustCtx.GatewayQuality)
+		log.Info(fmt.Sprintf("adjust gateways of chain %v", chainID), "result", adjustCtx.WeightedAPIs, "gatewayQuality", adjustCtx.GatewayQuality, "rejected", adjustCtx.RejectedGateways)
 
 		adjustGatewayChains.Store(chainID, struct{}{})
 		go adjustGatewayOrder(bridge, chainID)
@@ -136,6 +136,8 @@ func InitRouterBridges(isServer bool) {
 				}
 				wg2.Wait()
 			}
+
+			InitShadowBridge(bridge, chainID.String(), tokenIDs)
 		}(wg, chainID)
 	}
 	wg.Wait()
@@ -443,6 +445,7 @@ func InitTokenConfig(b tokens.IBridge, tokenID string, chainID *big.Int) {
 	}
 	router.InitOnchainCustomConfig(chainID, tokenID)
 	b.SetTokenConfig(tokenAddr, tokenCfg)
+	initMigrationTokenConfig(b, tokenID, chainID, tokenCfg)
 
 	router.SetMultichainToken(tokenID, chainID.String(), tokenAddr)
 
@@ -460,6 +463,32 @@ func InitTokenConfig(b tokens.IBridge, tokenID string, chainID *big.Int) {
 	}
 }
 
+// initMigrationTokenConfig set token config of the other token of token migration,
+// so that deposits of both the old and new token can be verified.
+func initMigrationTokenConfig(b tokens.IBridge, tokenID string, chainID *big.Int, tokenCfg *tokens.TokenConfig) {
+	migration := params.GetTokenMigration(chainID.String(), tokenID)
+	if migration == nil {
+		return
+	}
+	var otherToken string
+	switch {
+	case strings.EqualFold(tokenCfg.ContractAddress, migration.OldToken):
+		otherToken = migration.NewToken
+	case strings.EqualFold(tokenCfg.ContractAddress, migration.NewToken):
+		otherToken = migration.OldToken
+	default:
+		log.Warn("token migration mismatch token address", "tokenID", tokenID, "chainID", chainID, "tokenAddr", tokenCfg.ContractAddress, "oldToken", migration.OldToken, "newToken", migration.NewToken)
+		return
+	}
+	otherCfg := *tokenCfg
+	otherCfg.ContractAddress = otherToken
+	// keep the underlying until it is reloaded from the other token on checking,
+	// so that the old token remainder report has the underlying balance.
+	otherCfg.Checked = false
+	b.SetTokenConfig(otherToken, &otherCfg)
+	log.Info(fmt.Sprintf("[%5v] init '%v' migration token config success", chainID, tokenID), "tokenAddr", otherToken, "oldToken", migration.OldToken, "newToken", migration.NewToken, "cutoverHeight", migration.CutoverHeight, "graceBlocks", migration.GraceBlocks)
+}
+
 func initSwapNonces() {
 	if !mongodb.HasClient() {
 		return
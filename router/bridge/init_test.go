@@ -0,0 +1,58 @@
+package bridge
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testTokenConfigBridge struct {
+	tokens.IBridge
+	tokenCfgs map[string]*tokens.TokenConfig
+}
+
+func (b *testTokenConfigBridge) SetTokenConfig(tokenAddr string, tokenCfg *tokens.TokenConfig) {
+	b.tokenCfgs[strings.ToLower(tokenAddr)] = tokenCfg
+}
+
+func TestInitMigrationTokenConfig(t *testing.T) {
+	oldToken := "0x7777777777777777777777777777777777777777"
+	newToken := "0x8888888888888888888888888888888888888888"
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			"1": {TokenMigrations: map[string]*params.TokenMigrationConfig{
+				"USDC": {OldToken: oldToken, NewToken: newToken, CutoverHeight: 100},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }()
+
+	chainID := big.NewInt(1)
+	b := &testTokenConfigBridge{tokenCfgs: make(map[string]*tokens.TokenConfig)}
+	tokenCfg := &tokens.TokenConfig{TokenID: "USDC", Decimals: 6, ContractAddress: oldToken, Checked: true}
+	tokenCfg.SetUnderlying("0x9999999999999999999999999999999999999999")
+	initMigrationTokenConfig(b, "USDC", chainID, tokenCfg)
+
+	// the other token is verified with the same tokenID and decimals
+	otherCfg := b.tokenCfgs[newToken]
+	if otherCfg == nil || otherCfg.TokenID != "USDC" || otherCfg.Decimals != 6 || otherCfg.Checked {
+		t.Fatalf("init migration token config got %+v", otherCfg)
+	}
+	if otherCfg.GetUnderlying() != tokenCfg.GetUnderlying() || tokenCfg.ContractAddress != oldToken {
+		t.Errorf("init migration token config should keep underlying and not change the original config")
+	}
+
+	// tokens without migration or mismatching the migration are not touched
+	b.tokenCfgs = make(map[string]*tokens.TokenConfig)
+	initMigrationTokenConfig(b, "USDT", chainID, &tokens.TokenConfig{TokenID: "USDT", ContractAddress: oldToken})
+	initMigrationTokenConfig(b, "USDC", chainID, &tokens.TokenConfig{TokenID: "USDC", ContractAddress: "0x1111111111111111111111111111111111111111"})
+	if len(b.tokenCfgs) != 0 {
+		t.Errorf("init migration token config without matched migration got %v", b.tokenCfgs)
+	}
+}
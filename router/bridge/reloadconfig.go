@@ -14,7 +14,28 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 )
 
-var reloadRouterConfigLock sync.Mutex
+var (
+	reloadRouterConfigLock sync.Mutex
+
+	reloadCallbacks     []func()
+	reloadCallbacksLock sync.Mutex
+)
+
+// OnReloadRouterConfig add callback which is called after router config is reloaded successfully
+func OnReloadRouterConfig(callback func()) {
+	reloadCallbacksLock.Lock()
+	defer reloadCallbacksLock.Unlock()
+	reloadCallbacks = append(reloadCallbacks, callback)
+}
+
+func callReloadCallbacks() {
+	reloadCallbacksLock.Lock()
+	callbacks := reloadCallbacks
+	reloadCallbacksLock.Unlock()
+	for _, callback := range callbacks {
+		callback()
+	}
+}
 
 // StartReloadRouterConfigTask start reload config
 func StartReloadRouterConfigTask() {
@@ -228,6 +249,8 @@ func ReloadRouterConfig() (success bool) {
 		router.SetBridge(chainID, nil)
 	}
 
+	callReloadCallbacks()
+
 	success = true
 	return success
 }
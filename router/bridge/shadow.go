@@ -0,0 +1,112 @@
+package bridge
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/aptos"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/btc"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/cardano"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/cosmos"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/flow"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/iota"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/near"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/reef"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/solana"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/stellar"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/tron"
+)
+
+// shadowImplementations bridge implementations can be run in shadow mode, key is the name in config
+var shadowImplementations = map[string]func(chainID *big.Int) tokens.IBridge{
+	"aptos":   func(*big.Int) tokens.IBridge { return aptos.NewCrossChainBridge() },
+	"btc":     func(*big.Int) tokens.IBridge { return btc.NewCrossChainBridge() },
+	"cardano": func(*big.Int) tokens.IBridge { return cardano.NewCrossChainBridge() },
+	"cosmos":  func(*big.Int) tokens.IBridge { return cosmos.NewCrossChainBridge() },
+	"eth":     func(*big.Int) tokens.IBridge { return eth.NewCrossChainBridge() },
+	"flow":    func(*big.Int) tokens.IBridge { return flow.NewCrossChainBridge() },
+	"iota":    func(*big.Int) tokens.IBridge { return iota.NewCrossChainBridge() },
+	"near":    func(*big.Int) tokens.IBridge { return near.NewCrossChainBridge() },
+	"reef":    func(*big.Int) tokens.IBridge { return reef.NewCrossChainBridge() },
+	"ripple":  func(*big.Int) tokens.IBridge { return ripple.NewCrossChainBridge() },
+	"solana":  func(*big.Int) tokens.IBridge { return solana.NewCrossChainBridge() },
+	"stellar": func(chainID *big.Int) tokens.IBridge { return stellar.NewCrossChainBridge(chainID.String()) },
+	"tron":    func(*big.Int) tokens.IBridge { return tron.NewCrossChainBridge() },
+}
+
+// newShadowBridgeFactory new shadow bridge factory of the configed implementation,
+// a new instance of the production implementation is used if not configed.
+func newShadowBridgeFactory(chainID *big.Int) (router.ShadowBridgeFactory, error) {
+	name := params.GetShadowImplementation(chainID.String())
+	if name == "" {
+		return func() tokens.IBridge { return NewCrossChainBridge(chainID) }, nil
+	}
+	newBridge, exist := shadowImplementations[name]
+	if !exist {
+		return nil, fmt.Errorf("unknown shadow implementation '%v'", name)
+	}
+	return func() tokens.IBridge { return newBridge(chainID) }, nil
+}
+
+// InitShadowBridge init shadow bridge with the same configs of the production bridge
+func InitShadowBridge(prodBridge tokens.IBridge, chainID string, tokenIDs []string) {
+	if !params.IsShadowModeChain(chainID) {
+		return
+	}
+	factory := router.GetShadowBridgeFactory(chainID)
+	if factory == nil {
+		bigChainID, err := common.GetBigIntFromStr(chainID)
+		if err != nil {
+			return
+		}
+		factory, err = newShadowBridgeFactory(bigChainID)
+		if err != nil {
+			log.Warn("shadow mode enabled but no shadow bridge", "chainID", chainID, "err", err)
+			return
+		}
+	}
+	chainCfg := prodBridge.GetChainConfig()
+	if chainCfg == nil {
+		return
+	}
+
+	shadow := factory()
+	SetGatewayConfig(shadow, chainID)
+	shadow.SetChainConfig(chainCfg)
+
+	routerContracts := make(map[string]string)
+	if chainCfg.RouterContract != "" {
+		routerContracts[chainCfg.RouterContract] = chainCfg.RouterVersion
+	}
+	for _, tokenID := range tokenIDs {
+		tokenAddr := router.GetCachedMultichainToken(tokenID, chainID)
+		if tokenAddr == "" {
+			continue
+		}
+		tokenCfg := prodBridge.GetTokenConfig(tokenAddr)
+		if tokenCfg == nil {
+			continue
+		}
+		shadow.SetTokenConfig(tokenAddr, tokenCfg)
+		if tokenCfg.RouterContract != "" {
+			routerContracts[tokenCfg.RouterContract] = tokenCfg.RouterVersion
+		}
+	}
+	for routerContract, routerVersion := range routerContracts {
+		if err := shadow.InitRouterInfo(routerContract, routerVersion); err != nil {
+			log.Warn("init shadow bridge router info failed", "chainID", chainID, "routerContract", routerContract, "err", err)
+			return
+		}
+	}
+	shadow.InitAfterConfig()
+
+	router.SetShadowBridge(chainID, shadow)
+	log.Info("init shadow bridge success", "chainID", chainID)
+}
@@ -0,0 +1,46 @@
+package bridge
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/cosmos"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth"
+)
+
+func TestNewShadowBridgeFactory(t *testing.T) {
+	defer func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }()
+
+	chainID := big.NewInt(1)
+	if err := params.SetExtraConfig(&params.ExtraConfig{}); err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	factory, err := newShadowBridgeFactory(chainID)
+	if err != nil {
+		t.Fatalf("new default shadow bridge factory failed: %v", err)
+	}
+	if _, ok := factory().(*eth.Bridge); !ok {
+		t.Errorf("default shadow bridge should be the production implementation")
+	}
+
+	extra := &params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			chainID.String(): {ShadowImplementation: "cosmos"},
+		},
+	}
+	if err = params.SetExtraConfig(extra); err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	if factory, err = newShadowBridgeFactory(chainID); err != nil {
+		t.Fatalf("new shadow bridge factory failed: %v", err)
+	}
+	if _, ok := factory().(*cosmos.Bridge); !ok {
+		t.Errorf("shadow bridge should be the configed implementation")
+	}
+
+	extra.LocalChainConfig[chainID.String()].ShadowImplementation = "unknown"
+	if _, err = newShadowBridgeFactory(chainID); err == nil {
+		t.Errorf("new shadow bridge factory of unknown implementation should fail")
+	}
+}
@@ -0,0 +1,291 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	// func latestRoundData() returns (uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound)
+	latestRoundDataFuncHash = common.FromHex("0xfeaf968c")
+	// func decimals() returns (uint8)
+	decimalsFuncHash = common.FromHex("0x313ce567")
+
+	routeRates = new(sync.Map) // key is tokenID:fromChainID:toChainID
+
+	rateAPITimeout = 10 // seconds
+
+	errRateBreakerNotTripped = errors.New("circuit breaker is not tripped")
+)
+
+type routeRate struct {
+	rate       *big.Int
+	updateTime int64
+	// circuit breaker is tripped when rate jumps beyond the max deviation,
+	// it is reset when a later rate returns back within the deviation,
+	// or the new rate keeps within the deviation of the candidate rate
+	// (the first rate after tripping) for the breaker reset time.
+	tripped        bool
+	candidate      *big.Int
+	candidateSince int64
+}
+
+type contractCaller interface {
+	CallContract(contract string, data hexutil.Bytes, blockNumber string) (string, error)
+}
+
+func getRouteRateKey(tokenID, fromChainID, toChainID string) string {
+	return strings.ToLower(fmt.Sprintf("%v:%v:%v", tokenID, fromChainID, toChainID))
+}
+
+// GetRouteRate get usable conversion rate of cross-asset route.
+// it returns nil rate if the route is 1:1,
+// and returns error if the rate is stale or the circuit breaker is tripped.
+func GetRouteRate(tokenID, fromChainID, toChainID string) (*big.Int, error) {
+	cfg := params.GetRateOracleConfig(tokenID, fromChainID, toChainID)
+	if cfg == nil {
+		return nil, nil
+	}
+	return getUsableRate(cfg, getRouteRateKey(tokenID, fromChainID, toChainID),
+		fmt.Sprintf("%v from %v to %v", tokenID, fromChainID, toChainID))
+}
+
+// GetCoinPrice get usable price (18 decimals) of one whole fee coin of chain in whole tokens
+// from the replace guard price oracle. it returns nil price if there is no price oracle,
+// and returns error if the price is stale or the circuit breaker is tripped.
+func GetCoinPrice(chainID, tokenID string) (*big.Int, error) {
+	cfg := params.GetCoinPriceOracleConfig(chainID, tokenID)
+	if cfg == nil {
+		return nil, nil
+	}
+	return getUsableRate(cfg, getCoinPriceKey(chainID, tokenID),
+		fmt.Sprintf("fee coin price of %v on %v", tokenID, chainID))
+}
+
+func getCoinPriceKey(chainID, tokenID string) string {
+	return strings.ToLower(fmt.Sprintf("price:%v:%v", chainID, tokenID))
+}
+
+func getUsableRate(cfg *params.RateOracleConfig, key, desc string) (*big.Int, error) {
+	v, exist := routeRates.Load(key)
+	if !exist {
+		return nil, fmt.Errorf("%w: no rate of %v", tokens.ErrRateUnavailable, desc)
+	}
+	rr := v.(*routeRate)
+	if rr.tripped {
+		return nil, fmt.Errorf("%w: circuit breaker of %v is tripped", tokens.ErrRateUnavailable, desc)
+	}
+	if time.Now().Unix()-rr.updateTime > cfg.GetMaxStaleness() {
+		return nil, fmt.Errorf("%w: rate of %v is stale since %v", tokens.ErrRateUnavailable, desc, rr.updateTime)
+	}
+	return new(big.Int).Set(rr.rate), nil
+}
+
+// CheckRouteRate check the claimed conversion rate against our own view
+func CheckRouteRate(tokenID, fromChainID, toChainID string, claimed *big.Int) error {
+	rate, err := GetRouteRate(tokenID, fromChainID, toChainID)
+	if err != nil {
+		return err
+	}
+	if rate == nil {
+		if claimed != nil {
+			return fmt.Errorf("%w: route of %v from %v to %v has no rate", tokens.ErrRateDeviation, tokenID, fromChainID, toChainID)
+		}
+		return nil
+	}
+	if claimed == nil || claimed.Sign() <= 0 {
+		return fmt.Errorf("%w: miss rate of %v from %v to %v", tokens.ErrRateDeviation, tokenID, fromChainID, toChainID)
+	}
+	cfg := params.GetRateOracleConfig(tokenID, fromChainID, toChainID)
+	if isRateDeviated(rate, claimed, cfg.GetMaxDeviation()) {
+		return fmt.Errorf("%w: claimed %v, ours %v", tokens.ErrRateDeviation, claimed, rate)
+	}
+	return nil
+}
+
+// UpdateRouteRates fetch conversion rates of all cross-asset routes,
+// and fee coin prices of the replace guard price oracles
+func UpdateRouteRates() {
+	extra := params.GetExtraConfig()
+	if extra == nil {
+		return
+	}
+	for _, cfg := range extra.RateOracles {
+		updateRouteRate(cfg)
+	}
+	for chainID, c := range extra.LocalChainConfig {
+		if c == nil || c.ReplaceGuard == nil {
+			continue
+		}
+		for tokenID, cfg := range c.ReplaceGuard.PriceOracles {
+			updateRate(cfg, getCoinPriceKey(chainID, tokenID))
+		}
+	}
+}
+
+func updateRouteRate(cfg *params.RateOracleConfig) {
+	updateRate(cfg, getRouteRateKey(cfg.TokenID, cfg.FromChainID, cfg.ToChainID))
+}
+
+func updateRate(cfg *params.RateOracleConfig, key string) {
+	rate, updateTime, err := fetchRate(cfg)
+	if err != nil {
+		log.Warn("fetch conversion rate failed", "key", key, "type", cfg.Type, "err", err)
+		return
+	}
+	if rate.Sign() <= 0 {
+		log.Warn("fetch conversion rate get non positive rate", "key", key, "rate", rate)
+		return
+	}
+
+	var old *routeRate
+	if v, exist := routeRates.Load(key); exist {
+		old = v.(*routeRate)
+	}
+	newRate := nextRouteRate(cfg, old, rate, updateTime, time.Now().Unix())
+	routeRates.Store(key, newRate)
+	log.Trace("update conversion rate", "key", key, "rate", newRate.rate, "updateTime", newRate.updateTime, "tripped", newRate.tripped)
+}
+
+// nextRouteRate get the route rate after fetching rate at time now
+func nextRouteRate(cfg *params.RateOracleConfig, old *routeRate, rate *big.Int, updateTime, now int64) *routeRate {
+	if old == nil {
+		return &routeRate{rate: rate, updateTime: updateTime}
+	}
+	maxDeviation := cfg.GetMaxDeviation()
+	if !isRateDeviated(old.rate, rate, maxDeviation) {
+		if old.tripped {
+			log.Info("conversion rate circuit breaker reset", "tokenID", cfg.TokenID, "fromChainID", cfg.FromChainID, "toChainID", cfg.ToChainID, "rate", rate)
+		}
+		return &routeRate{rate: rate, updateTime: updateTime}
+	}
+	if !old.tripped {
+		log.Error("conversion rate circuit breaker tripped", "tokenID", cfg.TokenID, "fromChainID", cfg.FromChainID, "toChainID", cfg.ToChainID, "lastRate", old.rate, "newRate", rate, "maxDeviation", maxDeviation)
+	}
+	// keep the last good rate for comparing
+	newRate := &routeRate{rate: old.rate, updateTime: old.updateTime, tripped: true, candidate: rate, candidateSince: now}
+	if old.candidate != nil && !isRateDeviated(old.candidate, rate, maxDeviation) {
+		if now-old.candidateSince >= cfg.GetBreakerResetTime() {
+			log.Info("conversion rate circuit breaker reset with stable new rate", "tokenID", cfg.TokenID, "fromChainID", cfg.FromChainID, "toChainID", cfg.ToChainID, "lastRate", old.rate, "rate", rate, "stableSince", old.candidateSince)
+			return &routeRate{rate: rate, updateTime: updateTime}
+		}
+		newRate.candidate, newRate.candidateSince = old.candidate, old.candidateSince
+	}
+	return newRate
+}
+
+// ResetRouteRateBreaker reset the tripped circuit breaker of route by admin,
+// the rate is fetched again and used without comparing to the last good rate.
+func ResetRouteRateBreaker(tokenID, fromChainID, toChainID string) error {
+	cfg := params.GetRateOracleConfig(tokenID, fromChainID, toChainID)
+	if cfg == nil {
+		return fmt.Errorf("no rate oracle of %v from %v to %v", tokenID, fromChainID, toChainID)
+	}
+	key := getRouteRateKey(tokenID, fromChainID, toChainID)
+	v, exist := routeRates.Load(key)
+	if !exist || !v.(*routeRate).tripped {
+		return errRateBreakerNotTripped
+	}
+	routeRates.Delete(key)
+	log.Info("conversion rate circuit breaker reset by admin", "tokenID", tokenID, "fromChainID", fromChainID, "toChainID", toChainID, "lastRate", v.(*routeRate).rate)
+	updateRouteRate(cfg)
+	_, err := GetRouteRate(tokenID, fromChainID, toChainID)
+	return err
+}
+
+func isRateDeviated(base, rate *big.Int, maxDeviation uint64) bool {
+	diff := new(big.Int).Sub(rate, base)
+	diff.Abs(diff)
+	diff.Mul(diff, big.NewInt(100))
+	maxDiff := new(big.Int).Mul(base, new(big.Int).SetUint64(maxDeviation))
+	return diff.Cmp(maxDiff) > 0
+}
+
+func fetchRate(cfg *params.RateOracleConfig) (rate *big.Int, updateTime int64, err error) {
+	switch cfg.Type {
+	case "chainlink":
+		return fetchChainlinkRate(cfg)
+	case "jsonapi":
+		rate, err = fetchJSONAPIRate(cfg)
+		return rate, time.Now().Unix(), err
+	default:
+		return nil, 0, fmt.Errorf("unknown rate oracle type '%v'", cfg.Type)
+	}
+}
+
+func fetchChainlinkRate(cfg *params.RateOracleConfig) (rate *big.Int, updateTime int64, err error) {
+	bridge := GetBridgeByChainID(cfg.OracleChainID)
+	if bridge == nil {
+		return nil, 0, tokens.ErrNoBridgeForChainID
+	}
+	caller, ok := bridge.(contractCaller)
+	if !ok {
+		return nil, 0, fmt.Errorf("chain %v does not support calling contract", cfg.OracleChainID)
+	}
+	res, err := caller.CallContract(cfg.Aggregator, latestRoundDataFuncHash, "latest")
+	if err != nil {
+		return nil, 0, err
+	}
+	data := common.FromHex(res)
+	if len(data) < 5*32 {
+		return nil, 0, errors.New("wrong latestRoundData result length")
+	}
+	answer := common.GetBigInt(data, 32, 32)
+	if data[32]&0x80 != 0 { // negative int256
+		return nil, 0, errors.New("negative latestRoundData answer")
+	}
+	updatedAt := common.GetBigInt(data, 3*32, 32)
+
+	res, err = caller.CallContract(cfg.Aggregator, decimalsFuncHash, "latest")
+	if err != nil {
+		return nil, 0, err
+	}
+	decimals := common.GetBigInt(common.FromHex(res), 0, 32)
+	if !decimals.IsUint64() || decimals.Uint64() > 36 {
+		return nil, 0, fmt.Errorf("wrong aggregator decimals %v", decimals)
+	}
+
+	rate = tokens.ConvertTokenValue(answer, uint8(decimals.Uint64()), 18)
+	return rate, updatedAt.Int64(), nil
+}
+
+func fetchJSONAPIRate(cfg *params.RateOracleConfig) (*big.Int, error) {
+	var result interface{}
+	err := client.RPCGetWithTimeout(&result, cfg.URL, rateAPITimeout)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range strings.Split(cfg.JSONPath, ".") {
+		obj, ok := result.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("json path '%v' not found", cfg.JSONPath)
+		}
+		result = obj[key]
+	}
+	var rateStr string
+	switch v := result.(type) {
+	case string:
+		rateStr = v
+	case float64:
+		rateStr = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return nil, fmt.Errorf("json path '%v' is not a number", cfg.JSONPath)
+	}
+	rate := tokens.ToBits(rateStr, 18)
+	if rate == nil {
+		return nil, fmt.Errorf("wrong rate '%v'", rateStr)
+	}
+	return rate, nil
+}
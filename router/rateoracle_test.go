@@ -0,0 +1,104 @@
+package router
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestNextRouteRateBreaker(t *testing.T) {
+	cfg := &params.RateOracleConfig{MaxDeviation: 5, BreakerResetTime: 600}
+	rr := nextRouteRate(cfg, nil, big.NewInt(1000), 100, 100)
+	if rr.tripped || rr.rate.Int64() != 1000 {
+		t.Fatalf("first rate got %+v", rr)
+	}
+	rr = nextRouteRate(cfg, rr, big.NewInt(1040), 200, 200)
+	if rr.tripped || rr.rate.Int64() != 1040 {
+		t.Fatalf("rate within deviation got %+v", rr)
+	}
+
+	// jump beyond the deviation trips the breaker and keeps the last good rate
+	rr = nextRouteRate(cfg, rr, big.NewInt(1500), 300, 300)
+	if !rr.tripped || rr.rate.Int64() != 1040 || rr.candidateSince != 300 {
+		t.Fatalf("rate jump got %+v", rr)
+	}
+	// unstable new rate restarts the stable period
+	rr = nextRouteRate(cfg, rr, big.NewInt(2000), 400, 400)
+	if !rr.tripped || rr.candidate.Int64() != 2000 || rr.candidateSince != 400 {
+		t.Fatalf("unstable new rate got %+v", rr)
+	}
+	rr = nextRouteRate(cfg, rr, big.NewInt(2010), 900, 900)
+	if !rr.tripped || rr.candidateSince != 400 {
+		t.Fatalf("new rate in stable period got %+v", rr)
+	}
+	// stable for the breaker reset time resets the breaker with the new rate
+	rr = nextRouteRate(cfg, rr, big.NewInt(1990), 1000, 1000)
+	if rr.tripped || rr.rate.Int64() != 1990 || rr.updateTime != 1000 {
+		t.Fatalf("stable new rate got %+v", rr)
+	}
+}
+
+func TestNextRouteRateReturnBack(t *testing.T) {
+	cfg := &params.RateOracleConfig{MaxDeviation: 5}
+	rr := nextRouteRate(cfg, nil, big.NewInt(1000), 100, 100)
+	rr = nextRouteRate(cfg, rr, big.NewInt(900), 200, 200)
+	if !rr.tripped {
+		t.Fatalf("rate jump should trip the breaker")
+	}
+	rr = nextRouteRate(cfg, rr, big.NewInt(1010), 300, 300)
+	if rr.tripped || rr.rate.Int64() != 1010 || rr.candidate != nil {
+		t.Errorf("rate returns back got %+v", rr)
+	}
+}
+
+func TestGetCoinPrice(t *testing.T) {
+	oracle := &params.RateOracleConfig{Type: "jsonapi", URL: "http://127.0.0.1/price", JSONPath: "usdc", MaxStaleness: 600}
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			"56": {ReplaceGuard: &params.ReplaceGuardConfig{
+				MaxFeeMultiple: 3,
+				PriceOracles:   map[string]*params.RateOracleConfig{"USDC": oracle},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }()
+	key := getCoinPriceKey("56", "USDC")
+	defer routeRates.Delete(key)
+
+	if price, err := GetCoinPrice("56", "USDT"); price != nil || err != nil {
+		t.Errorf("price of token without oracle got (%v, %v), want (nil, nil)", price, err)
+	}
+	if _, err := GetCoinPrice("56", "USDC"); !errors.Is(err, tokens.ErrRateUnavailable) {
+		t.Errorf("price without fetched rate got err %v, want %v", err, tokens.ErrRateUnavailable)
+	}
+
+	now := time.Now().Unix()
+	tests := []struct {
+		rr      *routeRate
+		wantErr bool
+	}{
+		{&routeRate{rate: big.NewInt(3000), updateTime: now}, false},
+		{&routeRate{rate: big.NewInt(3000), updateTime: now - 601}, true},
+		{&routeRate{rate: big.NewInt(3000), updateTime: now, tripped: true}, true},
+	}
+	for i, test := range tests {
+		routeRates.Store(key, test.rr)
+		price, err := GetCoinPrice("56", "usdc")
+		if test.wantErr {
+			if !errors.Is(err, tokens.ErrRateUnavailable) {
+				t.Errorf("test %v: price got err %v, want %v", i, err, tokens.ErrRateUnavailable)
+			}
+			continue
+		}
+		if err != nil || price.Cmp(test.rr.rate) != 0 {
+			t.Errorf("test %v: price got (%v, %v), want %v", i, price, err, test.rr.rate)
+		}
+	}
+}
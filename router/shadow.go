@@ -0,0 +1,48 @@
+package router
+
+import (
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// ShadowBridgeFactory new shadow bridge (new or updated bridge implementation)
+type ShadowBridgeFactory func() tokens.IBridge
+
+var (
+	shadowBridges         = new(sync.Map) // key is chainID
+	shadowBridgeFactories = new(sync.Map) // key is chainID
+)
+
+// RegisterShadowBridgeFactory register a new bridge implementation running
+// in shadow mode for chainID (it only verifies and builds, never signs).
+func RegisterShadowBridgeFactory(chainID string, factory ShadowBridgeFactory) {
+	shadowBridgeFactories.Store(chainID, factory)
+	log.Info("register shadow bridge factory", "chainID", chainID)
+}
+
+// GetShadowBridgeFactory get shadow bridge factory
+func GetShadowBridgeFactory(chainID string) ShadowBridgeFactory {
+	if factory, exist := shadowBridgeFactories.Load(chainID); exist {
+		return factory.(ShadowBridgeFactory)
+	}
+	return nil
+}
+
+// SetShadowBridge set shadow bridge
+func SetShadowBridge(chainID string, bridge tokens.IBridge) {
+	if bridge != nil {
+		shadowBridges.Store(chainID, bridge)
+	} else {
+		shadowBridges.Delete(chainID)
+	}
+}
+
+// GetShadowBridge get shadow bridge by chain id
+func GetShadowBridge(chainID string) tokens.IBridge {
+	if bridge, exist := shadowBridges.Load(chainID); exist {
+		return bridge.(tokens.IBridge)
+	}
+	return nil
+}
@@ -252,6 +252,24 @@ func IsBigValueSwap(swapInfo *tokens.SwapTxInfo) bool {
 	return swapInfo.Value.Cmp(bigValueThreshold) > 0
 }
 
+// IsTimeLockedSwap is swap value above the time lock threshold of its token
+func IsTimeLockedSwap(tokenID, fromChainID, token string, value *big.Int) bool {
+	threshold := params.GetTimeLockThreshold(tokenID)
+	if threshold == "" || value == nil {
+		return false
+	}
+	bridge := GetBridgeByChainID(fromChainID)
+	if bridge == nil {
+		return false
+	}
+	tokenCfg := bridge.GetTokenConfig(token)
+	if tokenCfg == nil {
+		return false
+	}
+	thresholdValue := tokens.ToBits(threshold, tokenCfg.Decimals)
+	return thresholdValue != nil && value.Cmp(thresholdValue) > 0
+}
+
 // IsBlacklistSwap is swap blacked
 func IsBlacklistSwap(swapInfo *tokens.SwapTxInfo) bool {
 	return params.IsChainIDInBlackList(swapInfo.FromChainID.String()) ||
@@ -306,9 +324,10 @@ func GetPausedChainIDs() []*big.Int {
 	return chainIDs
 }
 
-// IsChainIDPaused is chainID paused
+// IsChainIDPaused is chainID paused (by admin or disabled bridge feature flag)
 func IsChainIDPaused(chainID string) bool {
-	return pausedChainIDs.Contains(chainID)
+	return pausedChainIDs.Contains(chainID) ||
+		!params.IsFeatureEnabled(params.FeatureBridge, chainID)
 }
 
 // IsNonceSupported is nonce supported
@@ -320,6 +339,33 @@ func IsNonceSupported(chainID string) bool {
 	return false
 }
 
+// FormatAmount format raw amount to human readable value.
+// returns empty string if the chain does not support formatting.
+func FormatAmount(chainID, tokenID, value string) string {
+	bridge := GetBridgeByChainID(chainID)
+	if bridge == nil {
+		return ""
+	}
+	formatter, ok := bridge.(tokens.AmountFormatter)
+	if !ok {
+		return ""
+	}
+	tokenAddr := GetCachedMultichainToken(tokenID, chainID)
+	if tokenAddr == "" {
+		return ""
+	}
+	amount, err := common.GetBigIntFromStr(value)
+	if err != nil {
+		return ""
+	}
+	display, err := formatter.FormatAmount(tokenAddr, amount)
+	if err != nil {
+		log.Debug("format amount failed", "chainID", chainID, "tokenID", tokenID, "value", value, "err", err)
+		return ""
+	}
+	return display
+}
+
 // IsReswapSupported is reswap supported
 func IsReswapSupported(chainID string) bool {
 	if !params.GetLocalChainConfig(chainID).IsReswapSupported {
@@ -0,0 +1,54 @@
+package router
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testAmountBridge struct {
+	tokens.IBridge
+}
+
+func (b *testAmountBridge) FormatAmount(tokenAddr string, amount *big.Int) (string, error) {
+	if tokenAddr != "0x01" {
+		return "", tokens.ErrMissTokenConfig
+	}
+	return fmt.Sprintf("%v/%v", amount, tokenAddr), nil
+}
+
+type testNoAmountBridge struct {
+	tokens.IBridge
+}
+
+func TestFormatAmount(t *testing.T) {
+	SetBridge("1", &testAmountBridge{})
+	SetBridge("56", &testNoAmountBridge{})
+	defer SetBridge("1", nil)
+	defer SetBridge("56", nil)
+	SetMultichainToken("USDC", "1", "0x01")
+	SetMultichainToken("USDC", "56", "0x02")
+	SetMultichainToken("USDT", "1", "0x03")
+	defer SetMultichainTokens("USDC", nil)
+	defer SetMultichainTokens("USDT", nil)
+
+	tests := []struct {
+		chainID, tokenID, value string
+		want                    string
+	}{
+		{"1", "USDC", "1000", "1000/0x01"},
+		// not supported or failed formatting is empty
+		{"56", "USDC", "1000", ""},
+		{"137", "USDC", "1000", ""},
+		{"1", "DAI", "1000", ""},
+		{"1", "USDC", "bad", ""},
+		{"1", "USDT", "1000", ""},
+	}
+	for i, test := range tests {
+		if got := FormatAmount(test.chainID, test.tokenID, test.value); got != test.want {
+			t.Errorf("test %v: format amount got %v, want %v", i, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+// Package abuse provides per IP abuse detection and temporary bans of RPC requests.
+package abuse
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/versioning"
+)
+
+var (
+	detectorCfg *params.AbuseDetectionConfig
+	whitelist   = make(map[string]struct{})
+
+	records     = make(map[string]*ipRecord) // key is ip
+	recordsLock sync.Mutex
+
+	maxPeekBodySize     = int64(64 * 1024)
+	maxPeekResponseSize = 512
+	cleanupInterval     = 10 * time.Minute
+
+	timeNow = time.Now // replaced by fake clock in tests
+
+	registerRESTPathPrefix = "/swap/register/"
+	faucetRESTPath         = "/faucet/"
+	registerRPCMethod      = []byte("RegisterRouterSwap")
+	rpcErrorField          = []byte(`"error":`)
+)
+
+type ipRecord struct {
+	windowStart time.Time
+	requests    int
+	registers   int
+	failures    int
+
+	banCount    int
+	bannedAt    time.Time
+	bannedUntil time.Time
+	banReason   string
+	lastSeen    time.Time
+}
+
+// BannedIP banned ip info
+type BannedIP struct {
+	IP          string `json:"ip"`
+	Reason      string `json:"reason"`
+	BanCount    int    `json:"banCount"`
+	BannedAt    int64  `json:"bannedAt"`
+	BannedUntil int64  `json:"bannedUntil"`
+}
+
+// Init init abuse detection
+func Init(cfg *params.AbuseDetectionConfig) {
+	if cfg == nil || !cfg.Enable {
+		return
+	}
+	detectorCfg = cfg
+	for _, ip := range cfg.Whitelist {
+		whitelist[ip] = struct{}{}
+	}
+	go cleanupLoop()
+	log.Info("init rpc abuse detection success", "config", cfg)
+}
+
+// IsEnabled is abuse detection enabled
+func IsEnabled() bool {
+	return detectorCfg != nil
+}
+
+// Middleware detect abuse requests and reject the banned ones.
+// getIP is used to get the remote ip of a request.
+func Middleware(next http.Handler, getIP func(r *http.Request) string) http.Handler {
+	if !IsEnabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := getIP(r)
+		if _, ok := whitelist[ip]; ok || ip == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		isRegister := isRegisterRequest(r)
+		if !onRequest(ip, isRegister) {
+			http.Error(w, "too many abnormal requests, please retry later", http.StatusTooManyRequests)
+			return
+		}
+		if !isRegister {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rw := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rw, r)
+		if rw.isFailed() {
+			onRegisterFailed(ip)
+		}
+	})
+}
+
+func isRegisterRequest(r *http.Request) bool {
+	// the register routes are served under the versioned prefixes too
+	if strings.HasPrefix(versioning.StripVersion(r.URL.Path), registerRESTPathPrefix) {
+		return true
+	}
+	// failed faucet requests (eg. wrong captcha) are counted as failed registers
+	if strings.Contains(r.URL.Path, faucetRESTPath) {
+		return true
+	}
+	if r.Method != http.MethodPost || r.Body == nil {
+		return false
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPeekBodySize))
+	if err != nil {
+		return false
+	}
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	return bytes.Contains(body, registerRPCMethod)
+}
+
+// onRequest returns false if the ip is banned
+func onRequest(ip string, isRegister bool) bool {
+	recordsLock.Lock()
+	defer recordsLock.Unlock()
+
+	now := timeNow()
+	rec := getRecord(ip, now)
+	rec.lastSeen = now
+	if now.Before(rec.bannedUntil) {
+		return false
+	}
+
+	rec.requests++
+	if isRegister {
+		rec.registers++
+	}
+
+	switch {
+	case detectorCfg.MaxRequestsPerWindow > 0 && rec.requests > detectorCfg.MaxRequestsPerWindow:
+		ban(ip, rec, now, "scraping", 0)
+		return false
+	case detectorCfg.MaxRegistersPerWindow > 0 && rec.registers > detectorCfg.MaxRegistersPerWindow:
+		ban(ip, rec, now, "register spam", 0)
+		return false
+	}
+	return true
+}
+
+func onRegisterFailed(ip string) {
+	recordsLock.Lock()
+	defer recordsLock.Unlock()
+
+	now := timeNow()
+	rec := getRecord(ip, now)
+	rec.failures++
+	if detectorCfg.MaxFailedRegistersPerWindow > 0 && rec.failures > detectorCfg.MaxFailedRegistersPerWindow {
+		ban(ip, rec, now, "brute force register", 0)
+	}
+}
+
+// getRecord get record of ip and reset its counters if window expired
+func getRecord(ip string, now time.Time) *ipRecord {
+	rec, exist := records[ip]
+	if !exist {
+		rec = &ipRecord{windowStart: now}
+		records[ip] = rec
+	}
+	if now.Sub(rec.windowStart) >= detectorCfg.GetWindow() {
+		rec.windowStart = now
+		rec.requests = 0
+		rec.registers = 0
+		rec.failures = 0
+	}
+	return rec
+}
+
+// ban the ip, the ban duration doubles on each repeated ban (escalation)
+// unless the duration is specified explicitly.
+func ban(ip string, rec *ipRecord, now time.Time, reason string, duration time.Duration) {
+	// forget the escalation level if the last ban is long ago
+	if rec.banCount > 0 && now.Sub(rec.bannedUntil) > detectorCfg.GetMaxBanDuration() {
+		rec.banCount = 0
+	}
+	rec.banCount++
+	if duration == 0 {
+		duration = detectorCfg.GetBanDuration()
+		for i := 1; i < rec.banCount && duration < detectorCfg.GetMaxBanDuration(); i++ {
+			duration *= 2
+		}
+		if duration > detectorCfg.GetMaxBanDuration() {
+			duration = detectorCfg.GetMaxBanDuration()
+		}
+	}
+	rec.bannedAt = now
+	rec.bannedUntil = now.Add(duration)
+	rec.banReason = reason
+	rec.windowStart = now
+	rec.requests = 0
+	rec.registers = 0
+	rec.failures = 0
+	log.Warn("rpc abuse detected, ban ip", "ip", ip, "reason", reason, "banCount", rec.banCount, "duration", duration.String())
+}
+
+// BanIP ban ip manually, use the escalated duration if duration is zero
+func BanIP(ip string, duration time.Duration) {
+	if !IsEnabled() {
+		return
+	}
+	recordsLock.Lock()
+	defer recordsLock.Unlock()
+
+	now := timeNow()
+	ban(ip, getRecord(ip, now), now, "manual", duration)
+}
+
+// UnbanIP unban ip and reset its escalation level
+func UnbanIP(ip string) {
+	recordsLock.Lock()
+	defer recordsLock.Unlock()
+
+	if _, exist := records[ip]; exist {
+		delete(records, ip)
+		log.Info("unban ip", "ip", ip)
+	}
+}
+
+// GetBannedIPs get banned ips
+func GetBannedIPs() []*BannedIP {
+	recordsLock.Lock()
+	defer recordsLock.Unlock()
+
+	now := timeNow()
+	result := make([]*BannedIP, 0)
+	for ip, rec := range records {
+		if !now.Before(rec.bannedUntil) {
+			continue
+		}
+		result = append(result, &BannedIP{
+			IP:          ip,
+			Reason:      rec.banReason,
+			BanCount:    rec.banCount,
+			BannedAt:    rec.bannedAt.Unix(),
+			BannedUntil: rec.bannedUntil.Unix(),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].BannedUntil > result[j].BannedUntil
+	})
+	return result
+}
+
+func cleanupLoop() {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		cleanup()
+	}
+}
+
+// cleanup delete records which are idle and can not affect escalation
+func cleanup() {
+	recordsLock.Lock()
+	defer recordsLock.Unlock()
+
+	now := timeNow()
+	for ip, rec := range records {
+		if now.Sub(rec.lastSeen) < detectorCfg.GetWindow() || now.Before(rec.bannedUntil) {
+			continue
+		}
+		if rec.banCount > 0 && now.Sub(rec.bannedUntil) <= detectorCfg.GetMaxBanDuration() {
+			continue
+		}
+		delete(records, ip)
+	}
+}
+
+// responseRecorder records the leading part of response to detect failures.
+// REST api returns errors in plain text, JSON RPC api returns errors in 'error' field.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	head   []byte
+}
+
+func (rw *responseRecorder) WriteHeader(status int) {
+	rw.status = status
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseRecorder) Write(data []byte) (int, error) {
+	if remain := maxPeekResponseSize - len(rw.head); remain > 0 {
+		if len(data) < remain {
+			remain = len(data)
+		}
+		rw.head = append(rw.head, data[:remain]...)
+	}
+	return rw.ResponseWriter.Write(data)
+}
+
+func (rw *responseRecorder) isFailed() bool {
+	if rw.status >= http.StatusBadRequest {
+		return true
+	}
+	if strings.HasPrefix(rw.Header().Get("Content-Type"), "text/plain") {
+		return true
+	}
+	return bytes.Contains(rw.head, rpcErrorField)
+}
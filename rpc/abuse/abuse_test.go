@@ -0,0 +1,246 @@
+package abuse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+func TestIsRegisterRequest(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		want   bool
+	}{
+		{"POST", "/swap/register/1/0x01", "", true},
+		{"POST", "/v1/swap/register/1/0x01", "", true},
+		{"POST", "/v2/swap/register/1/0x01", "", true},
+		{"GET", "/v2/swap/status/1/0x01", "", false},
+		{"POST", "/v3/swap/register/1/0x01", "", false},
+		{"POST", "/rpc", `{"method":"swap.RegisterRouterSwap"}`, true},
+		{"POST", "/rpc", `{"method":"swap.GetRouterSwap"}`, false},
+	}
+	for i, test := range tests {
+		r := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+		if got := isRegisterRequest(r); got != test.want {
+			t.Errorf("test %v: is register request of %v %v got %v, want %v", i, test.method, test.path, got, test.want)
+		}
+	}
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// setupDetector enable abuse detection with fake clock, returns the restore func
+func setupDetector(cfg *params.AbuseDetectionConfig) (*fakeClock, func()) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	oldCfg, oldNow := detectorCfg, timeNow
+	detectorCfg, timeNow = cfg, clock.Now
+	records = make(map[string]*ipRecord)
+	return clock, func() {
+		detectorCfg, timeNow = oldCfg, oldNow
+		records = make(map[string]*ipRecord)
+	}
+}
+
+func TestBanEscalation(t *testing.T) {
+	clock, restore := setupDetector(&params.AbuseDetectionConfig{
+		MaxRequestsPerWindow: 2,
+		BanSeconds:           60,
+		MaxBanSeconds:        300,
+	})
+	defer restore()
+
+	const ip = "10.0.0.1"
+	tests := []struct {
+		pause   time.Duration // pause after the last ban expires
+		wantBan time.Duration
+	}{
+		{0, 60 * time.Second},
+		{0, 120 * time.Second},
+		{0, 240 * time.Second},
+		{0, 300 * time.Second}, // capped to max ban duration
+		{0, 300 * time.Second},
+		// escalation level is forgotten if the last ban is long ago
+		{301 * time.Second, 60 * time.Second},
+	}
+	for i, test := range tests {
+		clock.Advance(test.pause)
+		for j := 0; j < 2; j++ {
+			if !onRequest(ip, false) {
+				t.Fatalf("test %v: request %v in limit should pass", i, j)
+			}
+		}
+		if onRequest(ip, false) {
+			t.Fatalf("test %v: request over limit should be rejected", i)
+		}
+		rec := records[ip]
+		if got := rec.bannedUntil.Sub(clock.Now()); got != test.wantBan {
+			t.Errorf("test %v: ban duration got %v, want %v", i, got, test.wantBan)
+		}
+		if rec.banReason != "scraping" {
+			t.Errorf("test %v: ban reason got %v, want scraping", i, rec.banReason)
+		}
+		clock.Advance(test.wantBan)
+	}
+}
+
+func TestBanExpiry(t *testing.T) {
+	clock, restore := setupDetector(&params.AbuseDetectionConfig{
+		WindowSeconds:         60,
+		MaxRegistersPerWindow: 1,
+		BanSeconds:            100,
+	})
+	defer restore()
+
+	const ip = "10.0.0.2"
+	tests := []struct {
+		advance    time.Duration
+		isRegister bool
+		want       bool
+	}{
+		{0, true, true},
+		{0, false, true}, // normal requests are not limited
+		{0, true, false}, // register spam is banned
+		{99 * time.Second, false, false},
+		{time.Second, false, true}, // ban expires
+		{0, true, true},            // counters are reset by the ban
+	}
+	for i, test := range tests {
+		clock.Advance(test.advance)
+		if got := onRequest(ip, test.isRegister); got != test.want {
+			t.Errorf("test %v: request passed got %v, want %v", i, got, test.want)
+		}
+	}
+}
+
+func TestWindowReset(t *testing.T) {
+	clock, restore := setupDetector(&params.AbuseDetectionConfig{
+		WindowSeconds:               60,
+		MaxFailedRegistersPerWindow: 1,
+	})
+	defer restore()
+
+	const ip = "10.0.0.3"
+	onRegisterFailed(ip)
+	clock.Advance(60 * time.Second)
+	// the failure in last window is not counted
+	onRegisterFailed(ip)
+	if !onRequest(ip, true) {
+		t.Fatalf("failures of different windows should not ban ip")
+	}
+	onRegisterFailed(ip)
+	if onRequest(ip, true) {
+		t.Fatalf("too many failures in window should ban ip")
+	}
+	if reason := records[ip].banReason; reason != "brute force register" {
+		t.Errorf("ban reason got %v, want brute force register", reason)
+	}
+}
+
+func TestAdminBanList(t *testing.T) {
+	clock, restore := setupDetector(&params.AbuseDetectionConfig{BanSeconds: 60, MaxBanSeconds: 600})
+	defer restore()
+
+	BanIP("10.0.0.4", 0)              // escalated duration
+	BanIP("10.0.0.5", 30*time.Minute) // explicit duration is not capped
+	BanIP("10.0.0.4", 0)
+
+	banned := GetBannedIPs()
+	if len(banned) != 2 {
+		t.Fatalf("banned ips got %v, want 2", len(banned))
+	}
+	now := clock.Now().Unix()
+	want := []BannedIP{
+		{IP: "10.0.0.5", Reason: "manual", BanCount: 1, BannedAt: now, BannedUntil: now + 1800},
+		{IP: "10.0.0.4", Reason: "manual", BanCount: 2, BannedAt: now, BannedUntil: now + 120},
+	}
+	for i, item := range banned {
+		if *item != want[i] {
+			t.Errorf("banned ip %v got %+v, want %+v", i, *item, want[i])
+		}
+	}
+	if onRequest("10.0.0.4", false) {
+		t.Errorf("manually banned ip should be rejected")
+	}
+
+	// unban resets the escalation level
+	UnbanIP("10.0.0.4")
+	if !onRequest("10.0.0.4", false) {
+		t.Errorf("unbanned ip should pass")
+	}
+	BanIP("10.0.0.4", 0)
+	if banCount := records["10.0.0.4"].banCount; banCount != 1 {
+		t.Errorf("ban count after unban got %v, want 1", banCount)
+	}
+
+	// expired bans are not listed
+	clock.Advance(time.Hour)
+	if banned = GetBannedIPs(); len(banned) != 0 {
+		t.Errorf("banned ips after expiry got %v, want none", len(banned))
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	clock, restore := setupDetector(&params.AbuseDetectionConfig{WindowSeconds: 60, BanSeconds: 60, MaxBanSeconds: 600})
+	defer restore()
+
+	onRequest("10.0.0.6", false)
+	BanIP("10.0.0.7", 0)
+	clock.Advance(61 * time.Second)
+	cleanup()
+	if _, exist := records["10.0.0.6"]; exist {
+		t.Errorf("idle record should be cleaned up")
+	}
+	// keep the escalation level until max ban duration after the ban
+	if _, exist := records["10.0.0.7"]; !exist {
+		t.Errorf("record of recent ban should be kept")
+	}
+	clock.Advance(600 * time.Second)
+	cleanup()
+	if _, exist := records["10.0.0.7"]; exist {
+		t.Errorf("record of old ban should be cleaned up")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	_, restore := setupDetector(&params.AbuseDetectionConfig{MaxFailedRegistersPerWindow: 1})
+	defer restore()
+	whitelist["10.0.0.9"] = struct{}{}
+	defer delete(whitelist, "10.0.0.9")
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "swap not found", http.StatusNotFound)
+	}), func(r *http.Request) string { return r.Header.Get("X-Real-Ip") })
+
+	tests := []struct {
+		ip   string
+		want int
+	}{
+		{"10.0.0.8", http.StatusNotFound},
+		{"10.0.0.8", http.StatusNotFound},
+		{"10.0.0.8", http.StatusTooManyRequests}, // banned after too many failed registers
+		{"10.0.0.9", http.StatusNotFound},
+		{"10.0.0.9", http.StatusNotFound},
+		{"10.0.0.9", http.StatusNotFound}, // whitelisted ip is never banned
+	}
+	for i, test := range tests {
+		r := httptest.NewRequest("POST", "/swap/register/1/0x01", nil)
+		r.Header.Set("X-Real-Ip", test.ip)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != test.want {
+			t.Errorf("test %v: response status of %v got %v, want %v", i, test.ip, w.Code, test.want)
+		}
+	}
+}
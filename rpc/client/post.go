@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -18,6 +19,8 @@ const (
 	defaultRequestID   = 1
 )
 
+var errBatchNoResponse = errors.New("no response of batch request")
+
 // Request json rpc request
 type Request struct {
 	Method  string
@@ -240,3 +243,92 @@ func RPCJsonPostWithTimeout(url, reqBody string, timeout int) (string, error) {
 		}
 	}
 }
+
+// RPCPostBatch rpc post batch requests in one http request,
+// the responses are mapped back to the requests by id,
+// and each request's failure is reported in its own error.
+// results must have the same length as reqs.
+func RPCPostBatch(url string, reqs []*Request, results []interface{}, timeout int) []error {
+	return RPCPostBatchWithContext(httpCtx, url, reqs, results, timeout)
+}
+
+// RPCPostBatchWithContext rpc post batch requests with context
+func RPCPostBatchWithContext(ctx context.Context, url string, reqs []*Request, results []interface{}, timeout int) []error {
+	errs := make([]error, len(reqs))
+	setErrs := func(err error) []error {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+	if len(reqs) == 0 {
+		return errs
+	}
+	if len(results) != len(reqs) {
+		return setErrs(errors.New("batch results length mismatch"))
+	}
+	reqBodys := make([]*RequestBody, len(reqs))
+	indexes := make(map[int]int, len(reqs)) // key is id, value is index
+	for i, req := range reqs {
+		// ids must be unique to map the responses back
+		id := i + 1
+		reqBodys[i] = &RequestBody{
+			Version: "2.0",
+			Method:  req.Method,
+			Params:  req.Params,
+			ID:      id,
+		}
+		indexes[id] = i
+	}
+	resp, err := HTTPPostWithContext(ctx, url, reqBodys, nil, nil, timeout)
+	if err != nil {
+		log.Trace("post batch rpc error", "url", url, "count", len(reqs), "err", err)
+		return setErrs(err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	const maxReadContentLength int64 = 1024 * 1024 * 10 // 10M
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxReadContentLength))
+	if err != nil {
+		return setErrs(fmt.Errorf("read body error: %w", err))
+	}
+	if resp.StatusCode != 200 {
+		return setErrs(fmt.Errorf("wrong response status %v", resp.StatusCode))
+	}
+	var jsonResps []*jsonrpcResponse
+	err = json.Unmarshal(body, &jsonResps)
+	if err != nil {
+		// the whole batch is rejected with one error response
+		var jsonResp jsonrpcResponse
+		if json.Unmarshal(body, &jsonResp) == nil && jsonResp.Error != nil {
+			return setErrs(fmt.Errorf("return error: %w", jsonResp.Error))
+		}
+		return setErrs(fmt.Errorf("unmarshal body error, body is \"%v\" err=\"%w\"", string(body), err))
+	}
+	answered := make([]bool, len(reqs))
+	for _, jsonResp := range jsonResps {
+		var id int
+		if jsonResp == nil || json.Unmarshal(jsonResp.ID, &id) != nil {
+			continue
+		}
+		i, exist := indexes[id]
+		if !exist || answered[i] {
+			continue
+		}
+		answered[i] = true
+		if jsonResp.Error != nil {
+			errs[i] = fmt.Errorf("return error: %w", jsonResp.Error)
+			continue
+		}
+		if err := json.Unmarshal(jsonResp.Result, results[i]); err != nil {
+			errs[i] = fmt.Errorf("unmarshal result error: %w", err)
+		}
+	}
+	for i := range reqs {
+		if !answered[i] {
+			errs[i] = errBatchNoResponse
+		}
+	}
+	return errs
+}
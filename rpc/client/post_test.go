@@ -0,0 +1,72 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRPCPostBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []*RequestBody
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			t.Errorf("decode batch request failed: %v", err)
+			return
+		}
+		// responses in reverse order, the second member fails, the last one has no response
+		var resps []map[string]interface{}
+		for i := len(reqs) - 2; i >= 0; i-- {
+			req := reqs[i]
+			resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+			if i == 1 {
+				resp["error"] = map[string]interface{}{"code": -32000, "message": "nonce too low"}
+			} else {
+				resp["result"] = req.Params.([]interface{})[0]
+			}
+			resps = append(resps, resp)
+		}
+		_ = json.NewEncoder(w).Encode(resps)
+	}))
+	defer server.Close()
+
+	params := []string{"0x01", "0x02", "0x03", "0x04"}
+	reqs := make([]*Request, len(params))
+	results := make([]interface{}, len(params))
+	for i, param := range params {
+		reqs[i] = NewRequest("eth_sendRawTransaction", param)
+		results[i] = new(string)
+	}
+	errs := RPCPostBatch(server.URL, reqs, results, defaultTimeout)
+
+	if errs[0] != nil || *results[0].(*string) != "0x01" {
+		t.Errorf("member 0 got (%v, %v), want 0x01", *results[0].(*string), errs[0])
+	}
+	var jsonErr *jsonError
+	if !errors.As(errs[1], &jsonErr) || jsonErr.Code != -32000 {
+		t.Errorf("member 1 got error %v, want json error -32000", errs[1])
+	}
+	if errs[2] != nil || *results[2].(*string) != "0x03" {
+		t.Errorf("member 2 got (%v, %v), want 0x03", *results[2].(*string), errs[2])
+	}
+	if !errors.Is(errs[3], errBatchNoResponse) {
+		t.Errorf("member 3 got error %v, want %v", errs[3], errBatchNoResponse)
+	}
+}
+
+func TestRPCPostBatchRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"batch not supported"}}`))
+	}))
+	defer server.Close()
+
+	reqs := []*Request{NewRequest("eth_sendRawTransaction", "0x01"), NewRequest("eth_sendRawTransaction", "0x02")}
+	results := []interface{}{new(string), new(string)}
+	for i, err := range RPCPostBatch(server.URL, reqs, results, defaultTimeout) {
+		var jsonErr *jsonError
+		if !errors.As(err, &jsonErr) || jsonErr.Code != -32600 {
+			t.Errorf("member %v got error %v, want json error -32600", i, err)
+		}
+	}
+}
@@ -3,17 +3,24 @@ package restapi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/internal/swapapi"
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/tracing"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/versioning"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tokens/tests/config"
 	"github.com/gorilla/mux"
+	rpcjson "github.com/gorilla/rpc/v2/json2"
 )
 
 func writeResponse(w http.ResponseWriter, resp interface{}, err error) {
@@ -40,12 +47,45 @@ func writeJSONResponse(w http.ResponseWriter, jsonData []byte) {
 }
 
 func writeErrResponse(w http.ResponseWriter, err error) {
+	if w.Header().Get(versioning.HeaderAPIVersion) == versioning.V2 {
+		status, code := getErrorStatus(err)
+		versioning.WriteError(w, versioning.V2, status, code, err.Error())
+		return
+	}
 	// Note: must set header before write header
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
+	if requestID := w.Header().Get(tracing.HeaderRequestID); requestID != "" {
+		fmt.Fprintf(w, "%v (requestID: %v)", err, requestID)
+		return
+	}
 	fmt.Fprint(w, err.Error())
 }
 
+// getErrorStatus get http status and error code of v2 error response
+func getErrorStatus(err error) (status, code int) {
+	var rpcErr *rpcjson.Error
+	if errors.As(err, &rpcErr) {
+		code = int(rpcErr.Code)
+	}
+	switch {
+	case errors.Is(err, mongodb.ErrItemNotFound), errors.Is(err, mongodb.ErrSwapNotFound),
+		strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound, code
+	case errors.Is(err, mongodb.ErrItemIsDup):
+		return http.StatusConflict, code
+	case strings.HasPrefix(err.Error(), "mgoError:"): // database errors
+		return http.StatusInternalServerError, code
+	default:
+		return http.StatusBadRequest, code
+	}
+}
+
+// SunsetScheduleHandler handler of deprecation and sunset schedule of endpoints
+func SunsetScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, versioning.GetDeprecations(), nil)
+}
+
 // VersionInfoHandler handler
 func VersionInfoHandler(w http.ResponseWriter, r *http.Request) {
 	version := params.VersionWithMeta
@@ -76,6 +116,135 @@ func StatusInfoHandler(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, res, err)
 }
 
+// GetSLOReportHandler handler
+func GetSLOReportHandler(w http.ResponseWriter, r *http.Request) {
+	res := swapapi.GetSLOReport()
+	writeResponse(w, res, nil)
+}
+
+// GetIntegrityReportHandler handler
+func GetIntegrityReportHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := swapapi.GetIntegrityReport()
+	writeResponse(w, res, err)
+}
+
+// GetSwapStatsHandler handler
+func GetSwapStatsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	vals := r.URL.Query()
+	filter := &swapapi.SwapStatsFilter{
+		Interval:    vars["interval"],
+		FromChainID: vals.Get("fromchainid"),
+		ToChainID:   vals.Get("tochainid"),
+		TokenID:     vals.Get("tokenid"),
+	}
+	for name, ptr := range map[string]*int64{"start": &filter.Start, "end": &filter.End} {
+		if str := vals.Get(name); str != "" {
+			value, err := common.GetUint64FromStr(str)
+			if err != nil {
+				writeResponse(w, nil, fmt.Errorf("wrong %v: %w", name, err))
+				return
+			}
+			*ptr = int64(value)
+		}
+	}
+	res, err := swapapi.GetSwapStats(filter)
+	writeResponse(w, res, err)
+}
+
+// GetMPCUsageHandler handler
+func GetMPCUsageHandler(w http.ResponseWriter, r *http.Request) {
+	vals := r.URL.Query()
+	filter := &swapapi.MPCUsageFilter{
+		ChainID: vals.Get("chainid"),
+	}
+	for name, ptr := range map[string]*int64{"start": &filter.Start, "end": &filter.End} {
+		if str := vals.Get(name); str != "" {
+			value, err := common.GetUint64FromStr(str)
+			if err != nil {
+				writeResponse(w, nil, fmt.Errorf("wrong %v: %w", name, err))
+				return
+			}
+			*ptr = int64(value)
+		}
+	}
+	res, err := swapapi.GetMPCUsage(filter)
+	writeResponse(w, res, err)
+}
+
+// GetTimeLockedSwapsHandler handler
+func GetTimeLockedSwapsHandler(w http.ResponseWriter, r *http.Request) {
+	res := swapapi.GetTimeLockedSwaps()
+	writeResponse(w, res, nil)
+}
+
+// RegisterDepositAddressHandler handler
+func RegisterDepositAddressHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chainID := vars["chainid"]
+	toChainID := vars["tochainid"]
+	bind := vars["bind"]
+	res, err := swapapi.RegisterDepositAddress(chainID, toChainID, bind)
+	writeResponse(w, res, err)
+}
+
+// GetSwapReceiptHandler handler
+func GetSwapReceiptHandler(w http.ResponseWriter, r *http.Request) {
+	chainID, txid, logIndex := getRouterSwapKeys(r)
+	res, err := swapapi.GetSwapReceipt(chainID, txid, logIndex)
+	writeResponse(w, res, err)
+}
+
+// GetSwapTimelineHandler handler
+func GetSwapTimelineHandler(w http.ResponseWriter, r *http.Request) {
+	chainID, txid, logIndex := getRouterSwapKeys(r)
+	res, err := swapapi.GetSwapTimeline(chainID, txid, logIndex)
+	writeResponse(w, res, err)
+}
+
+// GetSwapTransitionsHandler handler
+func GetSwapTransitionsHandler(w http.ResponseWriter, r *http.Request) {
+	chainID, txid, logIndex := getRouterSwapKeys(r)
+	res, err := swapapi.GetSwapTransitions(chainID, txid, logIndex)
+	writeResponse(w, res, err)
+}
+
+// GetSwapSnapshotHandler handler
+func GetSwapSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	chainID, txid, logIndex := getRouterSwapKeys(r)
+	timestamp, err := common.GetUint64FromStr(r.URL.Query().Get("timestamp"))
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	res, err := swapapi.GetSwapSnapshot(chainID, txid, logIndex, int64(timestamp))
+	writeResponse(w, res, err)
+}
+
+// GetClaimSwapHandler handler
+func GetClaimSwapHandler(w http.ResponseWriter, r *http.Request) {
+	chainID, txid, logIndex := getRouterSwapKeys(r)
+	res, err := swapapi.GetClaimSwap(chainID, txid, logIndex)
+	writeResponse(w, res, err)
+}
+
+// GetSwapRejectionsHandler handler
+func GetSwapRejectionsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chainID := vars["chainid"]
+	txid := vars["txid"]
+	res, err := swapapi.GetSwapRejections(chainID, txid)
+	writeResponse(w, res, err)
+}
+
+// GetTokenMigrationsHandler handler
+func GetTokenMigrationsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chainID := vars["chainid"]
+	res, err := swapapi.GetTokenMigrations(chainID)
+	writeResponse(w, res, err)
+}
+
 func getRouterSwapKeys(r *http.Request) (chainID, txid, logIndex string) {
 	vars := mux.Vars(r)
 	chainID = vars["chainid"]
@@ -93,7 +262,69 @@ func getRouterSwapKeys(r *http.Request) (chainID, txid, logIndex string) {
 // RegisterRouterSwapHandler handler
 func RegisterRouterSwapHandler(w http.ResponseWriter, r *http.Request) {
 	chainID, txid, logIndex := getRouterSwapKeys(r)
-	res, err := swapapi.RegisterRouterSwap(chainID, txid, logIndex)
+	res, err := swapapi.RegisterRouterSwap(chainID, txid, logIndex, tracing.GetRequestID(r))
+	writeResponse(w, res, err)
+}
+
+// SandboxRegisterSwapHandler handler
+func SandboxRegisterSwapHandler(w http.ResponseWriter, r *http.Request) {
+	chainID, txid, logIndex := getRouterSwapKeys(r)
+	vals := r.URL.Query()
+	args := &swapapi.SandboxSwapArgs{
+		ChainID:   chainID,
+		TxID:      txid,
+		LogIndex:  logIndex,
+		ToChainID: vals.Get("tochainid"),
+		Bind:      vals.Get("bind"),
+		TokenID:   vals.Get("tokenid"),
+		Value:     vals.Get("value"),
+		Scenario:  vals.Get("scenario"),
+	}
+	res, err := swapapi.SandboxRegisterSwap(args)
+	writeResponse(w, res, err)
+}
+
+// SandboxGetSwapHandler handler
+func SandboxGetSwapHandler(w http.ResponseWriter, r *http.Request) {
+	chainID, txid, logIndex := getRouterSwapKeys(r)
+	res, err := swapapi.SandboxGetSwap(chainID, txid, logIndex)
+	writeResponse(w, res, err)
+}
+
+// faucet request headers
+const (
+	HeaderFaucetAPIKey  = "X-API-Key"
+	HeaderFaucetCaptcha = "X-Captcha-Response"
+)
+
+// RemoteIPGetter get remote ip of request (set by api server)
+var RemoteIPGetter func(r *http.Request) string
+
+func getFaucetArgs(r *http.Request) *swapapi.FaucetArgs {
+	vars := mux.Vars(r)
+	args := &swapapi.FaucetArgs{
+		ChainID:   vars["chainid"],
+		TokenID:   vars["tokenid"],
+		Receiver:  vars["receiver"],
+		ToChainID: vars["tochainid"],
+		APIKey:    r.Header.Get(HeaderFaucetAPIKey),
+		Captcha:   r.Header.Get(HeaderFaucetCaptcha),
+	}
+	if RemoteIPGetter != nil {
+		args.RemoteIP = RemoteIPGetter(r)
+	}
+	return args
+}
+
+// FaucetMintHandler handler, authorized by api key or captcha response in headers
+func FaucetMintHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := swapapi.FaucetMint(getFaucetArgs(r))
+	writeResponse(w, res, err)
+}
+
+// FaucetSwapHandler handler, authorized by api key or captcha response in headers
+func FaucetSwapHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := swapapi.FaucetSwap(getFaucetArgs(r))
 	writeResponse(w, res, err)
 }
 
@@ -123,6 +354,22 @@ func GetRouterSwapHandler(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, res, err)
 }
 
+// WaitRouterSwapHandler handler (long poll until the swap is in final status)
+func WaitRouterSwapHandler(w http.ResponseWriter, r *http.Request) {
+	chainID, txid, logIndex := getRouterSwapKeys(r)
+	var timeout time.Duration
+	if timeoutStr := r.URL.Query().Get("timeout"); timeoutStr != "" {
+		seconds, err := common.GetIntFromStr(timeoutStr)
+		if err != nil {
+			writeResponse(w, nil, err)
+			return
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	res, err := swapapi.WaitRouterSwap(r.Context(), chainID, txid, logIndex, timeout)
+	writeResponse(w, res, err)
+}
+
 // GetRouterSwapsHandler handler
 func GetRouterSwapsHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -174,6 +421,128 @@ func GetRouterSwapHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GetScopedSwapHistoryHandler handler, authorized by api token in 'Authorization: Bearer <token>' header
+func GetScopedSwapHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	offset, limit, status, err := getHistoryRequestVaules(r)
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	vals := r.URL.Query()
+	args := &swapapi.ScopedSwapHistoryArgs{
+		Bind:    vals.Get("bind"),
+		TokenID: vals.Get("tokenid"),
+		Offset:  offset,
+		Limit:   limit,
+		Status:  status,
+	}
+	token := swapapi.ParseBearerToken(r.Header.Get("Authorization"))
+	res, err := swapapi.GetScopedSwapHistory(token, args)
+	writeResponse(w, res, err)
+}
+
+func getSwapExportArgs(r *http.Request) (*swapapi.SwapExportArgs, error) {
+	vals := r.URL.Query()
+	args := &swapapi.SwapExportArgs{
+		Bind:    vals.Get("bind"),
+		TokenID: vals.Get("tokenid"),
+		Status:  vals.Get("status"),
+		Format:  vals.Get("format"),
+	}
+	for name, ptr := range map[string]*int64{"start": &args.Start, "end": &args.End} {
+		if str := vals.Get(name); str != "" {
+			value, err := common.GetUint64FromStr(str)
+			if err != nil {
+				return nil, fmt.Errorf("wrong %v: %w", name, err)
+			}
+			*ptr = int64(value)
+		}
+	}
+	return args, nil
+}
+
+func setAttachmentHeader(w http.ResponseWriter, format, fileName string) {
+	w.Header().Set("Content-Type", swapapi.GetExportContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+}
+
+// ExportSwapsHandler handler, authorized by api token in 'Authorization: Bearer <token>' header
+func ExportSwapsHandler(w http.ResponseWriter, r *http.Request) {
+	args, err := getSwapExportArgs(r)
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	token := swapapi.ParseBearerToken(r.Header.Get("Authorization"))
+	export, err := swapapi.PrepareSwapExport(token, args)
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	setAttachmentHeader(w, args.Format, export.FileName())
+	w.WriteHeader(http.StatusOK)
+	rows, err := export.Write(w)
+	if err != nil {
+		log.Warn("export swaps failed", "args", args, "rows", rows, "err", err)
+	}
+}
+
+// CreateSwapExportJobHandler handler, authorized by api token in 'Authorization: Bearer <token>' header
+func CreateSwapExportJobHandler(w http.ResponseWriter, r *http.Request) {
+	args, err := getSwapExportArgs(r)
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	token := swapapi.ParseBearerToken(r.Header.Get("Authorization"))
+	res, err := swapapi.CreateSwapExportJob(token, args)
+	writeResponse(w, res, err)
+}
+
+// GetSwapExportJobHandler handler, authorized by api token in 'Authorization: Bearer <token>' header
+func GetSwapExportJobHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	token := swapapi.ParseBearerToken(r.Header.Get("Authorization"))
+	res, err := swapapi.GetSwapExportJob(token, vars["id"])
+	writeResponse(w, res, err)
+}
+
+// DownloadSwapExportJobHandler handler, authorized by api token in 'Authorization: Bearer <token>' header
+func DownloadSwapExportJobHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	token := swapapi.ParseBearerToken(r.Header.Get("Authorization"))
+	f, job, err := swapapi.OpenSwapExportFile(token, vars["id"])
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	defer f.Close()
+	setAttachmentHeader(w, job.Args.Format, job.FileName())
+	http.ServeContent(w, r, job.FileName(), time.Unix(job.FinishTime, 0), f)
+}
+
+// GetStuckRouterSwapsHandler handler
+func GetStuckRouterSwapsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	vals := r.URL.Query()
+	offset, limit, _, err := getHistoryRequestVaules(r)
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	filter := &swapapi.StuckSwapsFilter{
+		Stage:       vars["stage"],
+		FromChainID: vals.Get("fromchainid"),
+		ToChainID:   vals.Get("tochainid"),
+		TokenID:     vals.Get("tokenid"),
+		SortBy:      vals.Get("sortby"),
+		Offset:      offset,
+		Limit:       limit,
+	}
+	res, err := swapapi.GetStuckRouterSwaps(filter)
+	writeResponse(w, res, err)
+}
+
 // GetAllChainIDsHandler handler
 func GetAllChainIDsHandler(w http.ResponseWriter, r *http.Request) {
 	allChainIDs := router.AllChainIDs
@@ -1,19 +1,24 @@
 package rpcapi
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/admin"
 	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/internal/swapapi"
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/abuse"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/worker"
 )
@@ -25,6 +30,14 @@ const (
 	replaceswapCmd          = "replaceswap"
 	forbidSwapCmd           = "forbidswap"
 	passForbiddenSwapoutCmd = "passforbiddenswapout"
+	vetoSwapCmd             = "vetoswap"
+	approveSwapCmd          = "approveswap"
+	approveSignCmd          = "approvesign"
+	apiTokenCmd             = "apitoken"
+	duplicateCmd            = "duplicate"
+	featureFlagCmd          = "featureflag"
+	quarantineCmd           = "quarantine"
+	resetRateBreakerCmd     = "resetratebreaker"
 
 	// maintain actions
 	actPause       = "pause"
@@ -33,6 +46,24 @@ const (
 	actUnwhitelist = "unwhitelist"
 	actBlacklist   = "blacklist"
 	actUnblacklist = "unblacklist"
+	actBan         = "ban"
+	actUnban       = "unban"
+	actBanList     = "banlist"
+
+	// api token actions
+	actMint   = "mint"
+	actRevoke = "revoke"
+	actList   = "list"
+
+	actUpdate = "update"
+
+	// feature flag actions
+	actSet   = "set"
+	actUnset = "unset"
+
+	// quarantine ranges
+	rangeTime  = "time"
+	rangeBlock = "block"
 
 	successReuslt = "Success"
 )
@@ -51,9 +82,19 @@ func (s *RouterSwapAPI) AdminCall(r *http.Request, rawTx, result *string) (err e
 		return err
 	}
 	senderAddress := sender.String()
+	if err = checkAdminCallPermission(senderAddress, args); err != nil {
+		return err
+	}
+	log.Info("admin call", "caller", senderAddress, "args", args, "result", result)
+	return doRouterAdminCall(mongodb.GetAdminActor(senderAddress), args, result)
+}
+
+// checkAdminCallPermission check sender is allowed to do the admin call,
+// assistants can only do part of the admin calls.
+func checkAdminCallPermission(senderAddress string, args *admin.CallArgs) error {
 	if !params.IsRouterAdmin(senderAddress) {
 		switch args.Method {
-		case reswapCmd, passForbiddenSwapoutCmd:
+		case reswapCmd, passForbiddenSwapoutCmd, apiTokenCmd, resetRateBreakerCmd:
 			return fmt.Errorf("sender %v is not admin", senderAddress)
 		case maintainCmd:
 			action := args.Params[0]
@@ -61,7 +102,17 @@ func (s *RouterSwapAPI) AdminCall(r *http.Request, rawTx, result *string) (err e
 			case actPause, actUnpause:
 				return fmt.Errorf("sender %v is not admin", senderAddress)
 			}
-		case passbigvalueCmd, replaceswapCmd, forbidSwapCmd:
+		case duplicateCmd:
+			if len(args.Params) > 0 && args.Params[0] == actUpdate {
+				return fmt.Errorf("sender %v is not admin", senderAddress)
+			}
+		case featureFlagCmd:
+			// assistants can only disable features (kill-switch)
+			if len(args.Params) > 0 && args.Params[0] != actList &&
+				!(args.Params[0] == actSet && len(args.Params) > 3 && args.Params[3] == "false") {
+				return fmt.Errorf("sender %v is not admin", senderAddress)
+			}
+		case passbigvalueCmd, replaceswapCmd, forbidSwapCmd, vetoSwapCmd, approveSwapCmd, approveSignCmd, quarantineCmd:
 		default:
 			return fmt.Errorf("unknown admin method '%v'", args.Method)
 		}
@@ -69,24 +120,40 @@ func (s *RouterSwapAPI) AdminCall(r *http.Request, rawTx, result *string) (err e
 			return fmt.Errorf("sender %v is not assistant", senderAddress)
 		}
 	}
-	log.Info("admin call", "caller", senderAddress, "args", args, "result", result)
-	return doRouterAdminCall(args, result)
+	return nil
 }
 
-func doRouterAdminCall(args *admin.CallArgs, result *string) error {
+// doRouterAdminCall do admin call, actor is recorded in swap status transitions
+func doRouterAdminCall(actor string, args *admin.CallArgs, result *string) error {
 	switch args.Method {
 	case maintainCmd:
 		return maintain(args, result)
 	case passbigvalueCmd:
-		return routerPassBigValue(args, result)
+		return routerPassBigValue(actor, args, result)
 	case reswapCmd:
-		return routerReswap(args, result)
+		return routerReswap(actor, args, result)
 	case replaceswapCmd:
 		return routerReplaceSwap(args, result)
 	case forbidSwapCmd:
-		return routerForbidSwap(args, result)
+		return routerForbidSwap(actor, args, result)
 	case passForbiddenSwapoutCmd:
-		return routerPassForbiddenSwapout(args, result)
+		return routerPassForbiddenSwapout(actor, args, result)
+	case vetoSwapCmd:
+		return routerVetoSwap(args, result)
+	case approveSwapCmd:
+		return routerApproveSwap(actor, args, result)
+	case approveSignCmd:
+		return routerApproveSign(args, result)
+	case apiTokenCmd:
+		return maintainAPITokens(args, result)
+	case duplicateCmd:
+		return maintainDuplicateDeliveries(actor, args, result)
+	case featureFlagCmd:
+		return maintainFeatureFlags(actor, args, result)
+	case quarantineCmd:
+		return routerQuarantineSwaps(actor, args, result)
+	case resetRateBreakerCmd:
+		return resetRateBreaker(args, result)
 	default:
 		return fmt.Errorf("unknown admin method '%v'", args.Method)
 	}
@@ -135,10 +202,10 @@ func maintain(args *admin.CallArgs, result *string) (err error) {
 	case actWhitelist, actUnwhitelist:
 		isAdd := strings.EqualFold(action, actWhitelist)
 		args := strings.Split(arguments, ",")
-		if len(args) < 3 {
+		whitelistType := strings.ToLower(args[0])
+		if len(args) < 3 && !(whitelistType == "tokenrouteactive" && len(args) == 2) {
 			return fmt.Errorf("miss arguments")
 		}
-		whitelistType := strings.ToLower(args[0])
 		switch whitelistType {
 		case "callbycontract":
 			params.AddOrRemoveCallByContractWhitelist(args[1], args[2:], isAdd)
@@ -146,6 +213,14 @@ func maintain(args *admin.CallArgs, result *string) (err error) {
 			params.AddOrRemoveCallByContractCodeHashWhitelist(args[1], args[2:], isAdd)
 		case "bigvalue":
 			params.AddOrRemoveBigValueWhitelist(args[1], args[2:], isAdd)
+		case "tokenroute":
+			if err := params.AddOrRemoveTokenRouteWhitelist(args[1], args[2:], isAdd); err != nil {
+				return err
+			}
+		case "tokenrouteactive":
+			for _, tokenID := range args[1:] {
+				params.SetTokenRouteWhitelistActive(tokenID, isAdd)
+			}
 		default:
 			return fmt.Errorf("unknown whitelist type '%v'", whitelistType)
 		}
@@ -166,6 +241,8 @@ func maintain(args *admin.CallArgs, result *string) (err error) {
 		default:
 			return fmt.Errorf("unknown blacklist type '%v'", blacklistType)
 		}
+	case actBan, actUnban, actBanList:
+		return maintainBannedIPs(action, arguments, result)
 	default:
 		return fmt.Errorf("unknown maintain action '%v'", action)
 	}
@@ -173,6 +250,39 @@ func maintain(args *admin.CallArgs, result *string) (err error) {
 	return nil
 }
 
+func maintainBannedIPs(action, arguments string, result *string) error {
+	if !abuse.IsEnabled() {
+		return fmt.Errorf("rpc abuse detection is not enabled")
+	}
+	switch action {
+	case actBanList:
+		data, err := json.Marshal(abuse.GetBannedIPs())
+		if err != nil {
+			return err
+		}
+		*result = string(data)
+		return nil
+	case actBan:
+		args := strings.Split(arguments, ",")
+		if len(args) < 2 {
+			return fmt.Errorf("miss arguments")
+		}
+		seconds, err := common.GetUint64FromStr(args[0])
+		if err != nil {
+			return fmt.Errorf("wrong ban seconds '%v'", args[0])
+		}
+		for _, ip := range args[1:] {
+			abuse.BanIP(ip, time.Duration(seconds)*time.Second)
+		}
+	case actUnban:
+		for _, ip := range strings.Split(arguments, ",") {
+			abuse.UnbanIP(ip)
+		}
+	}
+	*result = successReuslt
+	return nil
+}
+
 func getGasPrice(args *admin.CallArgs, startPos int) (gasPrice *big.Int, err error) {
 	if len(args.Params) < startPos+1 {
 		err = fmt.Errorf("wrong number of params, have %v want at least %v", len(args.Params), startPos+3)
@@ -188,7 +298,7 @@ func getGasPrice(args *admin.CallArgs, startPos int) (gasPrice *big.Int, err err
 	return
 }
 
-func routerPassBigValue(args *admin.CallArgs, result *string) (err error) {
+func routerPassBigValue(actor string, args *admin.CallArgs, result *string) (err error) {
 	chainID, txid, logIndex, err := getKeys(args, 0)
 	if err != nil {
 		return err
@@ -206,7 +316,7 @@ func routerPassBigValue(args *admin.CallArgs, result *string) (err error) {
 	if err != nil {
 		return err
 	}
-	err = mongodb.RouterAdminPassBigValue(chainID, txid, logIndex)
+	err = mongodb.RouterAdminPassBigValue(chainID, txid, logIndex, actor)
 	if err != nil {
 		return err
 	}
@@ -215,12 +325,12 @@ func routerPassBigValue(args *admin.CallArgs, result *string) (err error) {
 	return nil
 }
 
-func routerReswap(args *admin.CallArgs, result *string) (err error) {
+func routerReswap(actor string, args *admin.CallArgs, result *string) (err error) {
 	chainID, txid, logIndex, err := getKeys(args, 0)
 	if err != nil {
 		return err
 	}
-	err = mongodb.RouterAdminReswap(chainID, txid, logIndex)
+	err = mongodb.RouterAdminReswap(chainID, txid, logIndex, actor)
 	if err != nil {
 		return err
 	}
@@ -250,7 +360,7 @@ func routerReplaceSwap(args *admin.CallArgs, result *string) (err error) {
 	return nil
 }
 
-func routerForbidSwap(args *admin.CallArgs, result *string) (err error) {
+func routerForbidSwap(actor string, args *admin.CallArgs, result *string) (err error) {
 	chainID, txid, logIndex, err := getKeys(args, 0)
 	if err != nil {
 		return err
@@ -259,24 +369,15 @@ func routerForbidSwap(args *admin.CallArgs, result *string) (err error) {
 	if len(args.Params) > 3 {
 		memo = args.Params[3]
 	}
-	err1 := mongodb.UpdateRouterSwapResultStatus(
-		chainID, txid, logIndex,
-		mongodb.ManualMakeFail,
-		time.Now().Unix(), memo,
-	)
-	err2 := mongodb.UpdateRouterSwapStatus(
-		chainID, txid, logIndex,
-		mongodb.ManualMakeFail,
-		time.Now().Unix(), memo,
-	)
-	if err1 != nil && err2 != nil {
-		return err1
+	err = mongodb.RouterAdminForbidSwap(chainID, txid, logIndex, memo, actor)
+	if err != nil {
+		return err
 	}
 	*result = successReuslt
 	return nil
 }
 
-func routerPassForbiddenSwapout(args *admin.CallArgs, result *string) (err error) {
+func routerPassForbiddenSwapout(actor string, args *admin.CallArgs, result *string) (err error) {
 	chainID, txid, logIndex, err := getKeys(args, 0)
 	if err != nil {
 		return err
@@ -294,10 +395,286 @@ func routerPassForbiddenSwapout(args *admin.CallArgs, result *string) (err error
 	if !errors.Is(err, tokens.ErrSwapoutForbidden) {
 		return fmt.Errorf("verify error mismatch, %v", err)
 	}
-	err = mongodb.RouterAdminPassForbiddenSwapout(chainID, txid, logIndex)
+	err = mongodb.RouterAdminPassForbiddenSwapout(chainID, txid, logIndex, actor)
 	if err != nil {
 		return err
 	}
 	*result = successReuslt
 	return nil
 }
+
+func routerVetoSwap(args *admin.CallArgs, result *string) (err error) {
+	chainID, txid, logIndex, err := getKeys(args, 0)
+	if err != nil {
+		return err
+	}
+	var memo string
+	if len(args.Params) > 3 {
+		memo = args.Params[3]
+	}
+	err = worker.VetoTimeLockedSwap(chainID, txid, logIndex, memo)
+	if err != nil {
+		return err
+	}
+	*result = successReuslt
+	return nil
+}
+
+func routerApproveSwap(actor string, args *admin.CallArgs, result *string) (err error) {
+	chainID, txid, logIndex, err := getKeys(args, 0)
+	if err != nil {
+		return err
+	}
+	err = worker.ApproveDeferredSwap(actor, chainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	*result = successReuslt
+	return nil
+}
+
+// routerApproveSign add dual control approval of sign request outside swap flow,
+// the approval is authorized by its own signature rather than the caller.
+func routerApproveSign(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 2 {
+		return fmt.Errorf("wrong number of params, have %v want 2", len(args.Params))
+	}
+	var approval mpc.SignApproval
+	if err = json.Unmarshal([]byte(args.Params[0]), &approval); err != nil {
+		return fmt.Errorf("wrong sign approval: %w", err)
+	}
+	isFastMPC := strings.EqualFold(args.Params[1], "fastmpc")
+	mpcConfig := mpc.GetMPCConfig(isFastMPC)
+	if mpcConfig == nil {
+		return fmt.Errorf("mpc config is not initialized (fastmpc: %v)", isFastMPC)
+	}
+	approver, err := mpcConfig.AddSignApproval(&approval)
+	if err != nil {
+		return err
+	}
+	*result = fmt.Sprintf("%v (approver %v)", successReuslt, approver)
+	return nil
+}
+
+// maintainAPITokens mint, revoke and list delegated read-only api tokens.
+// params of mint are name, comma separated binds, comma separated token ids and lifetime in seconds.
+func maintainAPITokens(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) == 0 {
+		return fmt.Errorf("no api token action is specified")
+	}
+	var res interface{}
+	switch action := args.Params[0]; action {
+	case actMint:
+		if len(args.Params) != 5 {
+			return fmt.Errorf("wrong number of params, have %v want 5", len(args.Params))
+		}
+		lifetime, errf := common.GetUint64FromStr(args.Params[4])
+		if errf != nil {
+			return fmt.Errorf("wrong lifetime '%v'", args.Params[4])
+		}
+		binds := strings.Split(args.Params[2], ",")
+		tokenIDs := strings.Split(args.Params[3], ",")
+		res, err = swapapi.MintAPIToken(args.Params[1], binds, tokenIDs, int64(lifetime))
+	case actRevoke:
+		if len(args.Params) != 2 {
+			return fmt.Errorf("wrong number of params, have %v want 2", len(args.Params))
+		}
+		if err = swapapi.RevokeAPIToken(args.Params[1]); err != nil {
+			return err
+		}
+		*result = successReuslt
+		return nil
+	case actList:
+		res, err = swapapi.GetAPITokens()
+	default:
+		return fmt.Errorf("unknown api token action '%v'", action)
+	}
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+// maintainDuplicateDeliveries list duplicate destination deliveries and track their recovery.
+// params of list are optional recovery status, offset and limit,
+// params of update are key, recovery status, optional recovery tx and memo.
+func maintainDuplicateDeliveries(actor string, args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) == 0 {
+		return fmt.Errorf("no duplicate action is specified")
+	}
+	switch action := args.Params[0]; action {
+	case actList:
+		var status string
+		offset, limit := 0, 20
+		if len(args.Params) > 1 {
+			status = args.Params[1]
+			if status != "" && !mongodb.IsValidDuplicateRecoveryStatus(status) {
+				return fmt.Errorf("wrong recovery status '%v'", status)
+			}
+		}
+		if len(args.Params) > 2 {
+			if offset, err = strconv.Atoi(args.Params[2]); err != nil || offset < 0 {
+				return fmt.Errorf("wrong offset '%v'", args.Params[2])
+			}
+		}
+		if len(args.Params) > 3 {
+			if limit, err = strconv.Atoi(args.Params[3]); err != nil || limit <= 0 || limit > 100 {
+				return fmt.Errorf("wrong limit '%v'", args.Params[3])
+			}
+		}
+		res, errf := mongodb.FindDuplicateDeliveries(status, offset, limit)
+		if errf != nil {
+			return errf
+		}
+		data, errf := json.Marshal(res)
+		if errf != nil {
+			return errf
+		}
+		*result = string(data)
+		return nil
+	case actUpdate:
+		if len(args.Params) < 3 {
+			return fmt.Errorf("wrong number of params, have %v want at least 3", len(args.Params))
+		}
+		key, status := args.Params[1], args.Params[2]
+		if !mongodb.IsValidDuplicateRecoveryStatus(status) {
+			return fmt.Errorf("wrong recovery status '%v'", status)
+		}
+		var recoveryTx, memo string
+		if len(args.Params) > 3 {
+			recoveryTx = args.Params[3]
+		}
+		if len(args.Params) > 4 {
+			memo = args.Params[4]
+		}
+		err = mongodb.UpdateDuplicateRecovery(key, status, recoveryTx, memo, actor)
+		if err != nil {
+			return err
+		}
+		*result = successReuslt
+		return nil
+	default:
+		return fmt.Errorf("unknown duplicate action '%v'", action)
+	}
+}
+
+// maintainFeatureFlags override feature flags (persisted and synced to other servers).
+// params of set are flag, chainID ('all' means all chains) and enabled,
+// params of unset are flag and chainID.
+func maintainFeatureFlags(actor string, args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) == 0 {
+		return fmt.Errorf("no feature flag action is specified")
+	}
+	switch action := args.Params[0]; action {
+	case actList:
+		data, errf := json.Marshal(params.GetFeatureFlagOverrides())
+		if errf != nil {
+			return errf
+		}
+		*result = string(data)
+		return nil
+	case actSet, actUnset:
+		wantParams := 3
+		if action == actSet {
+			wantParams = 4
+		}
+		if len(args.Params) != wantParams {
+			return fmt.Errorf("wrong number of params, have %v want %v", len(args.Params), wantParams)
+		}
+		flag, chainID := args.Params[1], args.Params[2]
+		if !params.IsValidFeatureFlag(flag) {
+			return fmt.Errorf("unknown feature flag '%v', all flags are %v", flag, params.GetAllFeatureFlags())
+		}
+		if chainID != params.FeatureFlagAllChains {
+			if _, err = common.GetBigIntFromStr(chainID); err != nil || chainID == "" {
+				return fmt.Errorf("wrong chain id '%v'", chainID)
+			}
+		}
+		if action == actUnset {
+			if err = mongodb.RemoveFeatureFlag(flag, chainID); err != nil {
+				return err
+			}
+			params.RemoveFeatureFlagOverride(flag, chainID)
+		} else {
+			enabled, errf := strconv.ParseBool(args.Params[3])
+			if errf != nil {
+				return fmt.Errorf("wrong enabled value '%v'", args.Params[3])
+			}
+			if err = mongodb.SetFeatureFlag(flag, chainID, enabled, actor); err != nil {
+				return err
+			}
+			params.SetFeatureFlagOverride(flag, chainID, enabled)
+		}
+		log.Info("maintain feature flag success", "action", action, "flag", flag, "chainID", chainID, "actor", actor)
+		*result = successReuslt
+		return nil
+	default:
+		return fmt.Errorf("unknown feature flag action '%v'", action)
+	}
+}
+
+// routerQuarantineSwaps quarantine swaps verified in the incident window to re-verify them.
+// params are fromChainID (empty means all chains for time range), range type (time or block),
+// start and end (unix seconds or block heights, inclusive), and optional memo.
+func routerQuarantineSwaps(actor string, args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) < 4 {
+		return fmt.Errorf("wrong number of params, have %v want at least 4", len(args.Params))
+	}
+	chainID, rangeType := args.Params[0], args.Params[1]
+	if chainID != "" {
+		if _, err = common.GetBigIntFromStr(chainID); err != nil {
+			return fmt.Errorf("wrong chain id '%v'", chainID)
+		}
+	}
+	start, err := strconv.ParseUint(args.Params[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("wrong range start '%v'", args.Params[2])
+	}
+	end, err := strconv.ParseUint(args.Params[3], 10, 64)
+	if err != nil || end < start || end == 0 {
+		return fmt.Errorf("wrong range end '%v'", args.Params[3])
+	}
+	var memo string
+	if len(args.Params) > 4 {
+		memo = args.Params[4]
+	}
+	filter := &mongodb.QuarantineFilter{FromChainID: chainID}
+	switch rangeType {
+	case rangeTime:
+		filter.StartTime, filter.EndTime = int64(start), int64(end)
+	case rangeBlock:
+		if chainID == "" {
+			return errors.New("quarantine by block range without chain id")
+		}
+		filter.FromHeight, filter.ToHeight = start, end
+	default:
+		return fmt.Errorf("unknown quarantine range type '%v'", rangeType)
+	}
+	res, err := worker.QuarantineSwaps(filter, memo, actor)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func resetRateBreaker(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 3 {
+		return fmt.Errorf("wrong number of params, have %v want 3", len(args.Params))
+	}
+	tokenID, fromChainID, toChainID := args.Params[0], args.Params[1], args.Params[2]
+	if err = router.ResetRouteRateBreaker(tokenID, fromChainID, toChainID); err != nil {
+		return err
+	}
+	*result = successReuslt
+	return nil
+}
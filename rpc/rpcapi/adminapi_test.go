@@ -0,0 +1,46 @@
+package rpcapi
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/admin"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+func TestCheckAdminCallPermission(t *testing.T) {
+	const (
+		adminAddr     = "0x1111111111111111111111111111111111111111"
+		assistantAddr = "0x2222222222222222222222222222222222222222"
+		otherAddr     = "0x3333333333333333333333333333333333333333"
+	)
+	cfg := params.GetRouterConfig()
+	oldServer := cfg.Server
+	cfg.Server = &params.RouterServerConfig{Admins: []string{adminAddr}, Assistants: []string{assistantAddr}}
+	defer func() { cfg.Server = oldServer }()
+
+	mint := &admin.CallArgs{Method: apiTokenCmd, Params: []string{actMint, "partner", "0xaa", "", "0"}}
+	tests := []struct {
+		sender  string
+		args    *admin.CallArgs
+		allowed bool
+	}{
+		// only admins can mint api tokens
+		{adminAddr, mint, true},
+		{assistantAddr, mint, false},
+		{otherAddr, mint, false},
+		{assistantAddr, &admin.CallArgs{Method: apiTokenCmd, Params: []string{actList}}, false},
+		// assistants can do part of admin calls
+		{assistantAddr, &admin.CallArgs{Method: vetoSwapCmd}, true},
+		{otherAddr, &admin.CallArgs{Method: vetoSwapCmd}, false},
+		{assistantAddr, &admin.CallArgs{Method: maintainCmd, Params: []string{actPause}}, false},
+		{assistantAddr, &admin.CallArgs{Method: featureFlagCmd, Params: []string{actSet, "1", "flag", "false"}}, true},
+		{assistantAddr, &admin.CallArgs{Method: featureFlagCmd, Params: []string{actSet, "1", "flag", "true"}}, false},
+		{assistantAddr, &admin.CallArgs{Method: "unknown"}, false},
+	}
+	for i, test := range tests {
+		err := checkAdminCallPermission(test.sender, test.args)
+		if allowed := err == nil; allowed != test.allowed {
+			t.Errorf("test %v: admin call %v of %v got allowed %v (err %v), want %v", i, test.args.Method, test.sender, allowed, err, test.allowed)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/internal/swapapi"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/tracing"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 )
 
@@ -26,6 +27,13 @@ type RouterSwapKeyArgs struct {
 	LogIndex string `json:"logindex"`
 }
 
+// DepositAddressArgs args
+type DepositAddressArgs struct {
+	ChainID   string `json:"chainid"`
+	ToChainID string `json:"tochainid"`
+	Bind      string `json:"bind"`
+}
+
 // GetVersionInfo api
 func (s *RouterSwapAPI) GetVersionInfo(r *http.Request, args *RPCNullArgs, result *string) error {
 	version := params.VersionWithMeta
@@ -40,6 +48,12 @@ func (s *RouterSwapAPI) GetServerInfo(r *http.Request, args *RPCNullArgs, result
 	return nil
 }
 
+// GetFeatureFlags api
+func (s *RouterSwapAPI) GetFeatureFlags(r *http.Request, args *RPCNullArgs, result *[]*params.FeatureFlagOverride) error {
+	*result = params.GetFeatureFlagOverrides()
+	return nil
+}
+
 type getOracleInfoResult map[string]*swapapi.OracleInfo
 
 // GetOracleInfo api
@@ -73,6 +87,61 @@ func (args *OracleInfoArgs) toOracleInfo() *swapapi.OracleInfo {
 	}
 }
 
+// GetDBProfiles api
+func (s *RouterSwapAPI) GetDBProfiles(r *http.Request, args *RPCNullArgs, result *swapapi.DBProfiles) error {
+	profiles := swapapi.GetDBProfiles()
+	*result = *profiles
+	return nil
+}
+
+// GetShadowReport api
+func (s *RouterSwapAPI) GetShadowReport(r *http.Request, args *RPCNullArgs, result *swapapi.ShadowReport) error {
+	report := swapapi.GetShadowReport()
+	*result = *report
+	return nil
+}
+
+// GetSLOReport api
+func (s *RouterSwapAPI) GetSLOReport(r *http.Request, args *RPCNullArgs, result *swapapi.SLOReport) error {
+	report := swapapi.GetSLOReport()
+	*result = *report
+	return nil
+}
+
+// GetIntegrityReport api
+func (s *RouterSwapAPI) GetIntegrityReport(r *http.Request, args *RPCNullArgs, result *swapapi.IntegrityReport) error {
+	report, err := swapapi.GetIntegrityReport()
+	if err == nil && report != nil {
+		*result = *report
+	}
+	return err
+}
+
+// GetSwapStats api
+func (s *RouterSwapAPI) GetSwapStats(r *http.Request, args *swapapi.SwapStatsFilter, result *[]*swapapi.SwapStat) error {
+	res, err := swapapi.GetSwapStats(args)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// GetMPCUsage api
+func (s *RouterSwapAPI) GetMPCUsage(r *http.Request, args *swapapi.MPCUsageFilter, result *[]*swapapi.MPCUsage) error {
+	res, err := swapapi.GetMPCUsage(args)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// GetTimeLockedSwaps api
+func (s *RouterSwapAPI) GetTimeLockedSwaps(r *http.Request, args *RPCNullArgs, result *swapapi.TimeLockedSwaps) error {
+	swaps := swapapi.GetTimeLockedSwaps()
+	*result = *swaps
+	return nil
+}
+
 // ReportOracleInfo api
 func (s *RouterSwapAPI) ReportOracleInfo(r *http.Request, args *OracleInfoArgs, result *string) error {
 	err := swapapi.ReportOracleInfo(args.Enode, args.toOracleInfo())
@@ -85,7 +154,7 @@ func (s *RouterSwapAPI) ReportOracleInfo(r *http.Request, args *OracleInfoArgs,
 
 // RegisterRouterSwap api
 func (s *RouterSwapAPI) RegisterRouterSwap(r *http.Request, args *RouterSwapKeyArgs, result *swapapi.MapIntResult) error {
-	res, err := swapapi.RegisterRouterSwap(args.ChainID, args.TxID, args.LogIndex)
+	res, err := swapapi.RegisterRouterSwap(args.ChainID, args.TxID, args.LogIndex, tracing.GetRequestID(r))
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -129,6 +198,112 @@ func (s *RouterSwapAPI) GetRouterSwapHistory(r *http.Request, args *RouterGetSwa
 	return err
 }
 
+// GetScopedSwapHistory api, authorized by api token in 'Authorization: Bearer <token>' header
+func (s *RouterSwapAPI) GetScopedSwapHistory(r *http.Request, args *swapapi.ScopedSwapHistoryArgs, result *[]*swapapi.SwapInfo) error {
+	token := swapapi.ParseBearerToken(r.Header.Get("Authorization"))
+	res, err := swapapi.GetScopedSwapHistory(token, args)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// GetStuckRouterSwaps api
+func (s *RouterSwapAPI) GetStuckRouterSwaps(r *http.Request, args *swapapi.StuckSwapsFilter, result *[]*swapapi.SwapInfo) error {
+	res, err := swapapi.GetStuckRouterSwaps(args)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// RegisterDepositAddress api
+func (s *RouterSwapAPI) RegisterDepositAddress(r *http.Request, args *DepositAddressArgs, result *string) error {
+	res, err := swapapi.RegisterDepositAddress(args.ChainID, args.ToChainID, args.Bind)
+	if err == nil {
+		*result = res
+	}
+	return err
+}
+
+// GetSwapReceipt api
+func (s *RouterSwapAPI) GetSwapReceipt(r *http.Request, args *RouterSwapKeyArgs, result *swapapi.SwapReceipt) error {
+	res, err := swapapi.GetSwapReceipt(args.ChainID, args.TxID, args.LogIndex)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// GetSwapRejections api
+func (s *RouterSwapAPI) GetSwapRejections(r *http.Request, args *RouterSwapKeyArgs, result *[]*swapapi.SwapRejection) error {
+	res, err := swapapi.GetSwapRejections(args.ChainID, args.TxID)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// GetSwapTimeline api
+func (s *RouterSwapAPI) GetSwapTimeline(r *http.Request, args *RouterSwapKeyArgs, result *swapapi.SwapTimeline) error {
+	res, err := swapapi.GetSwapTimeline(args.ChainID, args.TxID, args.LogIndex)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// GetSwapTransitions api
+func (s *RouterSwapAPI) GetSwapTransitions(r *http.Request, args *RouterSwapKeyArgs, result *[]*swapapi.SwapTransition) error {
+	res, err := swapapi.GetSwapTransitions(args.ChainID, args.TxID, args.LogIndex)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// SwapSnapshotArgs args
+type SwapSnapshotArgs struct {
+	RouterSwapKeyArgs
+	Timestamp int64 `json:"timestamp"` // milli seconds
+}
+
+// GetSwapSnapshot api
+func (s *RouterSwapAPI) GetSwapSnapshot(r *http.Request, args *SwapSnapshotArgs, result *swapapi.SwapSnapshot) error {
+	res, err := swapapi.GetSwapSnapshot(args.ChainID, args.TxID, args.LogIndex, args.Timestamp)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// GetClaimSwap api
+func (s *RouterSwapAPI) GetClaimSwap(r *http.Request, args *RouterSwapKeyArgs, result *swapapi.ClaimSwap) error {
+	res, err := swapapi.GetClaimSwap(args.ChainID, args.TxID, args.LogIndex)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// SandboxRegisterSwap api
+func (s *RouterSwapAPI) SandboxRegisterSwap(r *http.Request, args *swapapi.SandboxSwapArgs, result *swapapi.MapIntResult) error {
+	res, err := swapapi.SandboxRegisterSwap(args)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// SandboxGetSwap api
+func (s *RouterSwapAPI) SandboxGetSwap(r *http.Request, args *RouterSwapKeyArgs, result *swapapi.SwapInfo) error {
+	res, err := swapapi.SandboxGetSwap(args.ChainID, args.TxID, args.LogIndex)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
 // GetAllChainIDs api
 func (s *RouterSwapAPI) GetAllChainIDs(r *http.Request, args *RPCNullArgs, result *[]*big.Int) error {
 	*result = router.AllChainIDs
@@ -164,6 +339,15 @@ func (s *RouterSwapAPI) GetChainConfig(r *http.Request, args *string, result *sw
 	return fmt.Errorf("chain config not found")
 }
 
+// GetTokenMigrations api
+func (s *RouterSwapAPI) GetTokenMigrations(r *http.Request, args *string, result *[]*swapapi.TokenMigrationReport) error {
+	res, err := swapapi.GetTokenMigrations(*args)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
 // GetTokenConfigArgs args
 type GetTokenConfigArgs struct {
 	ChainID string `json:"chainid"`
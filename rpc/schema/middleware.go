@@ -0,0 +1,165 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/tracing"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/versioning"
+	"github.com/gorilla/mux"
+	rpcjson "github.com/gorilla/rpc/v2/json2"
+)
+
+var (
+	maxRequestBodySize = int64(1024 * 1024)
+
+	// rest path vars of chainid format
+	chainIDPathVars = []string{"chainid", "fromchainid", "tochainid"}
+	// rest paths which accept `all` as chainid
+	allChainIDPathPrefixes = []string{"/swap/history/"}
+)
+
+type rpcRequest struct {
+	Method string           `json:"method"`
+	Params *json.RawMessage `json:"params"`
+	ID     *json.RawMessage `json:"id"`
+}
+
+type rpcErrorResponse struct {
+	Version string           `json:"jsonrpc"`
+	Error   *rpcjson.Error   `json:"error"`
+	ID      *json.RawMessage `json:"id"`
+}
+
+// RPCMiddleware validate json rpc request params before handlers run.
+// requests which can not be parsed are passed through to the rpc server
+// to report the parse error as before.
+func RPCMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Body == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize+1))
+		_ = r.Body.Close()
+		if err != nil {
+			http.Error(w, "read request body failed", http.StatusBadRequest)
+			return
+		}
+		if int64(len(body)) > maxRequestBodySize {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var req rpcRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		schema := GetMethodSchema(req.Method)
+		if schema == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		errs := validateRPCParams(schema, req.Params)
+		if len(errs) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		log.Debug("rpc request with invalid params", "method", req.Method, "errs", errs)
+		writeRPCError(w, req.ID, errs)
+	})
+}
+
+func validateRPCParams(schema *Schema, rawParams *json.RawMessage) []*FieldError {
+	var params interface{}
+	if rawParams != nil {
+		if err := json.Unmarshal(*rawParams, &params); err != nil {
+			return []*FieldError{{Error: "invalid params json: " + err.Error()}}
+		}
+	}
+	// params can be passed as an array with one element
+	if arr, ok := params.([]interface{}); ok {
+		switch len(arr) {
+		case 0:
+			params = nil
+		case 1:
+			params = arr[0]
+		default:
+			return []*FieldError{{Error: fmt.Sprintf("params array should have at most 1 element, have %v", len(arr))}}
+		}
+	}
+	return schema.Validate(params)
+}
+
+func writeRPCError(w http.ResponseWriter, id *json.RawMessage, errs []*FieldError) {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.String()
+	}
+	var data interface{} = errs
+	if requestID := w.Header().Get(tracing.HeaderRequestID); requestID != "" {
+		data = &tracing.RequestIDErrorData{RequestID: requestID, Data: errs}
+	}
+	resp := &rpcErrorResponse{
+		Version: "2.0",
+		Error: &rpcjson.Error{
+			Code:    rpcjson.E_BAD_PARAMS,
+			Message: "invalid params: " + strings.Join(msgs, "; "),
+			Data:    data,
+		},
+		ID: id,
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Warn("write rpc error response failed", "err", err)
+	}
+}
+
+// RESTMiddleware validate rest path vars and query values before handlers run
+func RESTMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var errs []*FieldError
+		vars := mux.Vars(r)
+		chainIDFormat := FormatChainID
+		for _, prefix := range allChainIDPathPrefixes {
+			if strings.HasPrefix(versioning.StripVersion(r.URL.Path), prefix) {
+				chainIDFormat = FormatChainIDOrAll
+				break
+			}
+		}
+		for _, name := range chainIDPathVars {
+			if value, exist := vars[name]; exist {
+				if err := checkFormat(chainIDFormat, value); err != nil {
+					errs = append(errs, &FieldError{Field: name, Error: err.Error()})
+				}
+			}
+		}
+		if logIndex := r.URL.Query().Get("logindex"); logIndex != "" {
+			if err := checkFormat(FormatLogIndex, logIndex); err != nil {
+				errs = append(errs, &FieldError{Field: "logindex", Error: err.Error()})
+			}
+		}
+		if len(errs) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.String()
+		}
+		// Note: keep the same error response format as the rest api handlers
+		version := versioning.GetVersion(r.URL.Path)
+		status := http.StatusOK
+		if version == versioning.V2 {
+			status = http.StatusBadRequest
+		}
+		versioning.WriteError(w, version, status, int(rpcjson.E_BAD_PARAMS), "invalid request: "+strings.Join(msgs, "; "))
+	})
+}
@@ -0,0 +1,310 @@
+// Package schema provides request schema validation of RPC requests.
+package schema
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	cmath "github.com/anyswap/CrossChain-Router/v3/common/math"
+)
+
+// FieldType json type of field
+type FieldType string
+
+// field types
+const (
+	TypeString  FieldType = "string"
+	TypeInteger FieldType = "integer"
+)
+
+// field formats
+const (
+	FormatChainID      = "chainid"      // positive decimal integer
+	FormatChainIDOrAll = "chainidOrAll" // chainid or `all`
+	FormatLogIndex     = "logindex"     // non-negative integer
+)
+
+var chainIDPattern = regexp.MustCompile(`^[1-9][0-9]*$`)
+
+// Field schema of field
+type Field struct {
+	Name     string
+	Type     FieldType
+	Required bool
+	Format   string
+}
+
+// Schema params schema of rpc method.
+// params is either an object with fields, or a single scalar value.
+type Schema struct {
+	Fields []*Field
+	Scalar *Field
+}
+
+// FieldError field level validation error
+type FieldError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+func (e *FieldError) String() string {
+	if e.Field == "" {
+		return e.Error
+	}
+	return e.Field + ": " + e.Error
+}
+
+func required(name string, typ FieldType, format string) *Field {
+	return &Field{Name: name, Type: typ, Required: true, Format: format}
+}
+
+func optional(name string, typ FieldType, format string) *Field {
+	return &Field{Name: name, Type: typ, Format: format}
+}
+
+var swapKeySchema = &Schema{
+	Fields: []*Field{
+		required("chainid", TypeString, FormatChainID),
+		required("txid", TypeString, ""),
+		optional("logindex", TypeString, FormatLogIndex),
+	},
+}
+
+var swapConfigSchema = &Schema{
+	Fields: []*Field{
+		required("tokenid", TypeString, ""),
+		required("fromchainid", TypeString, FormatChainID),
+		required("tochainid", TypeString, FormatChainID),
+	},
+}
+
+// methods without params are not listed here
+var methodSchemas = map[string]*Schema{
+	"swap.RegisterRouterSwap": swapKeySchema,
+	"swap.GetRouterSwap":      swapKeySchema,
+	"swap.GetRouterSwaps":     swapKeySchema,
+	"swap.GetSwapReceipt":     swapKeySchema,
+	"swap.GetSwapRejections":  swapKeySchema,
+	"swap.GetSwapTimeline":    swapKeySchema,
+	"swap.GetSwapTransitions": swapKeySchema,
+	"swap.GetClaimSwap":       swapKeySchema,
+	"swap.SandboxGetSwap":     swapKeySchema,
+	"swap.GetSwapConfig":      swapConfigSchema,
+	"swap.GetFeeConfig":       swapConfigSchema,
+	"swap.GetSwapSnapshot": {
+		Fields: []*Field{
+			required("chainid", TypeString, FormatChainID),
+			required("txid", TypeString, ""),
+			optional("logindex", TypeString, FormatLogIndex),
+			required("timestamp", TypeInteger, ""),
+		},
+	},
+	"swap.GetRouterSwapHistory": {
+		Fields: []*Field{
+			optional("chainid", TypeString, FormatChainIDOrAll),
+			optional("address", TypeString, ""),
+			optional("offset", TypeInteger, ""),
+			optional("limit", TypeInteger, ""),
+			optional("status", TypeString, ""),
+		},
+	},
+	"swap.GetScopedSwapHistory": {
+		Fields: []*Field{
+			optional("bind", TypeString, ""),
+			optional("tokenid", TypeString, ""),
+			optional("offset", TypeInteger, ""),
+			optional("limit", TypeInteger, ""),
+			optional("status", TypeString, ""),
+		},
+	},
+	"swap.GetStuckRouterSwaps": {
+		Fields: []*Field{
+			required("stage", TypeString, ""),
+			optional("fromChainID", TypeString, FormatChainID),
+			optional("toChainID", TypeString, FormatChainID),
+			optional("tokenID", TypeString, ""),
+			optional("sortBy", TypeString, ""),
+			optional("offset", TypeInteger, ""),
+			optional("limit", TypeInteger, ""),
+		},
+	},
+	"swap.GetSwapStats": {
+		Fields: []*Field{
+			required("interval", TypeString, ""),
+			optional("fromChainID", TypeString, FormatChainID),
+			optional("toChainID", TypeString, FormatChainID),
+			optional("tokenID", TypeString, ""),
+			optional("start", TypeInteger, ""),
+			optional("end", TypeInteger, ""),
+		},
+	},
+	"swap.GetMPCUsage": {
+		Fields: []*Field{
+			optional("chainID", TypeString, FormatChainID),
+			optional("start", TypeInteger, ""),
+			optional("end", TypeInteger, ""),
+		},
+	},
+	"swap.RegisterDepositAddress": {
+		Fields: []*Field{
+			required("chainid", TypeString, FormatChainID),
+			required("tochainid", TypeString, FormatChainID),
+			required("bind", TypeString, ""),
+		},
+	},
+	"swap.SandboxRegisterSwap": {
+		Fields: []*Field{
+			required("chainid", TypeString, FormatChainID),
+			required("txid", TypeString, ""),
+			optional("logindex", TypeString, FormatLogIndex),
+			optional("tochainid", TypeString, FormatChainID),
+			optional("bind", TypeString, ""),
+			optional("tokenid", TypeString, ""),
+			optional("value", TypeString, ""),
+			optional("scenario", TypeString, ""),
+		},
+	},
+	"swap.ReportOracleInfo": {
+		Fields: []*Field{
+			required("enode", TypeString, ""),
+			required("timestamp", TypeInteger, ""),
+		},
+	},
+	"swap.GetTokenConfig": {
+		Fields: []*Field{
+			required("chainid", TypeString, FormatChainID),
+			required("address", TypeString, ""),
+		},
+	},
+	"swap.GetStatusInfo":          {Scalar: optional("statuses", TypeString, "")},
+	"swap.GetAllMultichainTokens": {Scalar: required("tokenid", TypeString, "")},
+	"swap.GetChainConfig":         {Scalar: required("chainid", TypeString, FormatChainID)},
+	"swap.GetTokenMigrations":     {Scalar: required("chainid", TypeString, FormatChainID)},
+	"swap.AdminCall":              {Scalar: required("rawTx", TypeString, "")},
+}
+
+// GetMethodSchema get params schema of rpc method
+func GetMethodSchema(method string) *Schema {
+	return methodSchemas[method]
+}
+
+// Validate validate decoded json params (nil if absent)
+func (s *Schema) Validate(params interface{}) (errs []*FieldError) {
+	if s.Scalar != nil {
+		if err := s.Scalar.validate(params); err != nil {
+			errs = append(errs, err)
+		}
+		return errs
+	}
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	obj, ok := params.(map[string]interface{})
+	if !ok {
+		return []*FieldError{{Error: fmt.Sprintf("params should be an object, have %v", jsonTypeOf(params))}}
+	}
+	for _, field := range s.Fields {
+		if err := field.validate(lookupField(obj, field.Name)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// lookupField get value of field by name case-insensitively,
+// the same as json decoding params into the args struct of the handler.
+// the exact match is preferred to the case-insensitive ones.
+func lookupField(obj map[string]interface{}, name string) interface{} {
+	if value, exist := obj[name]; exist {
+		return value
+	}
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		if strings.EqualFold(key, name) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	return obj[keys[0]]
+}
+
+func (f *Field) validate(value interface{}) *FieldError {
+	if value == nil {
+		if f.Required {
+			return &FieldError{Field: f.Name, Error: "missing required field"}
+		}
+		return nil
+	}
+	switch f.Type {
+	case TypeString:
+		str, ok := value.(string)
+		if !ok {
+			return f.typeError(value)
+		}
+		if f.Required && strings.TrimSpace(str) == "" {
+			return &FieldError{Field: f.Name, Error: "should not be empty"}
+		}
+		if str != "" {
+			if err := checkFormat(f.Format, str); err != nil {
+				return &FieldError{Field: f.Name, Error: err.Error()}
+			}
+		}
+	case TypeInteger:
+		num, ok := value.(float64)
+		if !ok || num != math.Trunc(num) {
+			return f.typeError(value)
+		}
+	}
+	return nil
+}
+
+func (f *Field) typeError(value interface{}) *FieldError {
+	return &FieldError{Field: f.Name, Error: fmt.Sprintf("wrong type, want %v, have %v", f.Type, jsonTypeOf(value))}
+}
+
+// checkFormat check string value format
+func checkFormat(format, value string) error {
+	switch format {
+	case FormatChainID:
+		if !chainIDPattern.MatchString(value) {
+			return fmt.Errorf("invalid chainid format %q, should be a positive decimal integer", value)
+		}
+	case FormatChainIDOrAll:
+		if value != "all" && !chainIDPattern.MatchString(value) {
+			return fmt.Errorf("invalid chainid format %q, should be a positive decimal integer or 'all'", value)
+		}
+	case FormatLogIndex:
+		if logIndex, err := cmath.ParseInt(value); err != nil || logIndex < 0 {
+			return fmt.Errorf("invalid logindex %q, should be a non-negative integer", value)
+		}
+	}
+	return nil
+}
+
+func jsonTypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,59 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func validateJSON(t *testing.T, method, params string) []*FieldError {
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(params), &decoded); err != nil {
+		t.Fatalf("unmarshal params failed: %v", err)
+	}
+	return GetMethodSchema(method).Validate(decoded)
+}
+
+func TestValidateCaseInsensitive(t *testing.T) {
+	// the handlers decode params case-insensitively, so do the schemas
+	tests := []string{
+		`{"chainid":"1","txid":"0x01","logindex":"0"}`,
+		`{"chainID":"1","txID":"0x01","logIndex":"0"}`,
+		`{"ChainId":"1","TxId":"0x01"}`,
+	}
+	for i, params := range tests {
+		if errs := validateJSON(t, "swap.GetRouterSwap", params); len(errs) != 0 {
+			t.Errorf("test %v: validate %v got errors %v", i, params, errs)
+		}
+	}
+	if errs := validateJSON(t, "swap.GetStuckRouterSwaps", `{"Stage":"verify","fromchainid":"56"}`); len(errs) != 0 {
+		t.Errorf("validate mixed case fields got errors %v", errs)
+	}
+}
+
+func TestValidateFieldErrors(t *testing.T) {
+	tests := []struct {
+		method string
+		params string
+		field  string
+	}{
+		{"swap.GetRouterSwap", `{"txid":"0x01"}`, "chainid"},
+		{"swap.GetRouterSwap", `{"chainID":"0x1","txid":"0x01"}`, "chainid"},
+		{"swap.GetRouterSwap", `{"ChainID":1,"txid":"0x01"}`, "chainid"},
+		{"swap.GetRouterSwap", `{"chainid":"1","TXID":""}`, "txid"},
+		{"swap.GetRouterSwap", `{"chainid":"1","txid":"0x01","LogIndex":"-1"}`, "logindex"},
+		{"swap.GetSwapSnapshot", `{"chainid":"1","txid":"0x01","Timestamp":"1"}`, "timestamp"},
+	}
+	for i, test := range tests {
+		errs := validateJSON(t, test.method, test.params)
+		if len(errs) != 1 || errs[0].Field != test.field {
+			t.Errorf("test %v: validate %v got errors %v, want error of %v", i, test.params, errs, test.field)
+		}
+	}
+}
+
+func TestValidateExactMatchPreferred(t *testing.T) {
+	errs := validateJSON(t, "swap.GetRouterSwap", `{"chainid":"1","CHAINID":"bad","txid":"0x01"}`)
+	if len(errs) != 0 {
+		t.Errorf("exact match should be preferred, got errors %v", errs)
+	}
+}
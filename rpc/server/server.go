@@ -19,8 +19,12 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/abuse"
 	"github.com/anyswap/CrossChain-Router/v3/rpc/restapi"
 	"github.com/anyswap/CrossChain-Router/v3/rpc/rpcapi"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/schema"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/tracing"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/versioning"
 )
 
 // StartAPIServer start api server
@@ -38,10 +42,18 @@ func StartAPIServer() {
 
 	corsOptions := []handlers.CORSOption{
 		handlers.AllowedMethods([]string{"GET", "POST"}),
+		handlers.ExposedHeaders([]string{
+			tracing.HeaderRequestID,
+			versioning.HeaderAPIVersion,
+			versioning.HeaderDeprecation,
+			versioning.HeaderSunset,
+			versioning.HeaderLink,
+		}),
 	}
 	if len(allowedOrigins) != 0 {
 		corsOptions = append(corsOptions,
-			handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type"}),
+			handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type", "Authorization", tracing.HeaderRequestID,
+				restapi.HeaderFaucetAPIKey, restapi.HeaderFaucetCaptcha}),
 			handlers.AllowedOrigins(allowedOrigins),
 		)
 	}
@@ -58,6 +70,15 @@ func StartAPIServer() {
 		log.Warnf("rpc limit reached: %v\n", remoteIP)
 	})
 	handler := tollbooth.LimitHandler(lmt, handlers.CORS(corsOptions...)(router))
+	getRemoteIP := func(r *http.Request) string {
+		remoteIP := libstring.RemoteIP(lmt.GetIPLookups(), lmt.GetForwardedForIndexFromBehind(), r)
+		return libstring.CanonicalizeIP(remoteIP)
+	}
+	restapi.RemoteIPGetter = getRemoteIP
+	abuse.Init(apiServer.AbuseDetection)
+	versioning.Init(apiServer.Deprecations)
+	handler = abuse.Middleware(handler, getRemoteIP)
+	handler = tracing.Middleware(handler)
 	svr := http.Server{
 		Addr:         fmt.Sprintf(":%v", apiPort),
 		ReadTimeout:  60 * time.Second,
@@ -121,28 +142,64 @@ func doCleanup(svr *http.Server) {
 
 func initRouterSwapRouter(r *mux.Router) {
 	rpcserver := rpc.NewServer()
-	rpcserver.RegisterCodec(rpcjson.NewCodec(), "application/json")
+	rpcserver.RegisterCodec(tracing.NewRPCCodec(rpcjson.NewCodec()), "application/json")
 	err := rpcserver.RegisterService(new(rpcapi.RouterSwapAPI), "swap")
 	if err != nil {
 		log.Fatal("start rpc service failed", "err", err)
 	}
 
-	r.Handle("/rpc", rpcserver)
+	r.Handle("/rpc", schema.RPCMiddleware(rpcserver))
+	r.Use(versioning.Middleware)
+	r.Use(schema.RESTMiddleware)
+
+	r.HandleFunc(versioning.SunsetSchedulePath, restapi.SunsetScheduleHandler).Methods("GET")
+
+	// unversioned legacy routes are the same as v1 routes,
+	// v2 routes differ in response shapes (eg. json error responses).
+	initRESTRoutes(r)
+	initRESTRoutes(r.PathPrefix("/" + versioning.V1).Subrouter())
+	initRESTRoutes(r.PathPrefix("/" + versioning.V2).Subrouter())
+}
 
+func initRESTRoutes(r *mux.Router) {
 	r.HandleFunc("/versioninfo", restapi.VersionInfoHandler).Methods("GET")
 	r.HandleFunc("/serverinfo", restapi.ServerInfoHandler).Methods("GET")
 	r.HandleFunc("/oracleinfo", restapi.OracleInfoHandler).Methods("GET")
 	r.HandleFunc("/statusinfo", restapi.StatusInfoHandler).Methods("GET")
+	r.HandleFunc("/slo", restapi.GetSLOReportHandler).Methods("GET")
+	r.HandleFunc("/integrity/report", restapi.GetIntegrityReportHandler).Methods("GET")
+	r.HandleFunc("/stats/swaps/{interval}", restapi.GetSwapStatsHandler).Methods("GET")
+	r.HandleFunc("/stats/mpc", restapi.GetMPCUsageHandler).Methods("GET")
 	r.HandleFunc("/swap/register/{chainid}/{txid}", restapi.RegisterRouterSwapHandler).Methods("POST")
 	r.HandleFunc("/swap/status/{chainid}/{txid}", restapi.GetRouterSwapHandler).Methods("GET")
 	r.HandleFunc("/swap/status/{chainid}/{txid}/all", restapi.GetRouterSwapsHandler).Methods("GET")
+	r.HandleFunc("/swap/status/{chainid}/{txid}/wait", restapi.WaitRouterSwapHandler).Methods("GET")
+	r.HandleFunc("/swap/receipt/{chainid}/{txid}", restapi.GetSwapReceiptHandler).Methods("GET")
+	r.HandleFunc("/swap/rejections/{chainid}/{txid}", restapi.GetSwapRejectionsHandler).Methods("GET")
+	r.HandleFunc("/swap/timeline/{chainid}/{txid}", restapi.GetSwapTimelineHandler).Methods("GET")
+	r.HandleFunc("/swap/transitions/{chainid}/{txid}", restapi.GetSwapTransitionsHandler).Methods("GET")
+	r.HandleFunc("/swap/snapshot/{chainid}/{txid}", restapi.GetSwapSnapshotHandler).Methods("GET")
+	r.HandleFunc("/swap/claim/{chainid}/{txid}", restapi.GetClaimSwapHandler).Methods("GET")
 	r.HandleFunc("/swap/history/{chainid}/{address}", restapi.GetRouterSwapHistoryHandler).Methods("GET")
+	r.HandleFunc("/swap/timelocked", restapi.GetTimeLockedSwapsHandler).Methods("GET")
+	r.HandleFunc("/swap/stuck/{stage}", restapi.GetStuckRouterSwapsHandler).Methods("GET")
+	r.HandleFunc("/swap/scoped/history", restapi.GetScopedSwapHistoryHandler).Methods("GET")
+	r.HandleFunc("/export/swaps", restapi.ExportSwapsHandler).Methods("GET")
+	r.HandleFunc("/export/jobs", restapi.CreateSwapExportJobHandler).Methods("POST")
+	r.HandleFunc("/export/jobs/{id}", restapi.GetSwapExportJobHandler).Methods("GET")
+	r.HandleFunc("/export/jobs/{id}/download", restapi.DownloadSwapExportJobHandler).Methods("GET")
+	r.HandleFunc("/deposit/register/{chainid}/{tochainid}/{bind}", restapi.RegisterDepositAddressHandler).Methods("POST")
+	r.HandleFunc("/sandbox/swap/register/{chainid}/{txid}", restapi.SandboxRegisterSwapHandler).Methods("POST")
+	r.HandleFunc("/sandbox/swap/status/{chainid}/{txid}", restapi.SandboxGetSwapHandler).Methods("GET")
+	r.HandleFunc("/faucet/mint/{chainid}/{tokenid}/{receiver}", restapi.FaucetMintHandler).Methods("POST")
+	r.HandleFunc("/faucet/swap/{chainid}/{tochainid}/{tokenid}/{receiver}", restapi.FaucetSwapHandler).Methods("POST")
 
 	r.HandleFunc("/allchainids", restapi.GetAllChainIDsHandler).Methods("GET")
 	r.HandleFunc("/alltokenids", restapi.GetAllTokenIDsHandler).Methods("GET")
 	r.HandleFunc("/allmultichaintokens/{tokenid}", restapi.GetAllMultichainTokensHandler).Methods("GET")
 	r.HandleFunc("/chainconfig/{chainid}", restapi.GetChainConfigHandler).Methods("GET")
 	r.HandleFunc("/tokenconfig/{chainid}/{address:.*}", restapi.GetTokenConfigHandler).Methods("GET")
+	r.HandleFunc("/tokenmigrations/{chainid}", restapi.GetTokenMigrationsHandler).Methods("GET")
 	r.HandleFunc("/swapconfig/{tokenid}/{fromchainid}/{tochainid}", restapi.GetSwapConfigHandler).Methods("GET")
 	r.HandleFunc("/feeconfig/{tokenid}/{fromchainid}/{tochainid}", restapi.GetFeeConfigHandler).Methods("GET")
 }
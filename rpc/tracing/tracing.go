@@ -0,0 +1,115 @@
+// Package tracing provides request IDs of RPC requests for correlating
+// user reports with server logs.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+	rpcjson "github.com/gorilla/rpc/v2/json2"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+// HeaderRequestID header of request ID in requests and responses
+const HeaderRequestID = "X-Request-ID"
+
+type contextKey struct{}
+
+// accepted request IDs from clients, others are replaced by generated ones
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// NewRequestID generate request ID
+func NewRequestID() string {
+	id := make([]byte, 12)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// GetRequestID get request ID of request (empty if not traced)
+func GetRequestID(r *http.Request) string {
+	return FromContext(r.Context())
+}
+
+// FromContext get request ID from context
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Middleware accept the request ID of the request or generate one,
+// set it to the response header and the request context, and log the request.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(HeaderRequestID)
+		if !validRequestID.MatchString(id) {
+			id = NewRequestID()
+		}
+		w.Header().Set(HeaderRequestID, id)
+		r = r.WithContext(context.WithValue(r.Context(), contextKey{}, id))
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		log.Info("api request", "requestID", id, "method", r.Method, "path", r.URL.Path, "status", rec.status, "timespent", time.Since(start).String())
+	})
+}
+
+// NewRPCCodec wrap json rpc codec to return request ID in error data
+func NewRPCCodec(codec rpc.Codec) rpc.Codec {
+	return &rpcCodec{Codec: codec}
+}
+
+type rpcCodec struct {
+	rpc.Codec
+}
+
+func (c *rpcCodec) NewRequest(r *http.Request) rpc.CodecRequest {
+	return &rpcCodecRequest{CodecRequest: c.Codec.NewRequest(r)}
+}
+
+type rpcCodecRequest struct {
+	rpc.CodecRequest
+}
+
+// RequestIDErrorData error data containing request ID
+type RequestIDErrorData struct {
+	RequestID string      `json:"requestID"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+func (c *rpcCodecRequest) WriteError(w http.ResponseWriter, status int, err error) {
+	if id := w.Header().Get(HeaderRequestID); id != "" {
+		var jsonErr *rpcjson.Error
+		if errors.As(err, &jsonErr) {
+			errCopy := *jsonErr
+			errCopy.Data = &RequestIDErrorData{RequestID: id, Data: jsonErr.Data}
+			err = &errCopy
+		} else {
+			err = &rpcjson.Error{
+				Code:    rpcjson.E_SERVER,
+				Message: err.Error(),
+				Data:    &RequestIDErrorData{RequestID: id},
+			}
+		}
+	}
+	c.CodecRequest.WriteError(w, status, err)
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	rpcjson "github.com/gorilla/rpc/v2/json2"
+)
+
+func TestMiddleware(t *testing.T) {
+	var handledID string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handledID = GetRequestID(r)
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	tests := []struct {
+		requestID string
+		accepted  bool
+	}{
+		{"client-id.1:2", true},
+		{"", false},
+		{"has space", false},
+		{strings.Repeat("a", 65), false},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/swap/status/1/0x01", nil)
+		if test.requestID != "" {
+			req.Header.Set(HeaderRequestID, test.requestID)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		respID := rec.Header().Get(HeaderRequestID)
+		if respID == "" || respID != handledID || rec.Code != http.StatusTeapot {
+			t.Errorf("test %v: response request ID %q, handled request ID %q, status %v", i, respID, handledID, rec.Code)
+		}
+		if (respID == test.requestID) != test.accepted {
+			t.Errorf("test %v: request ID %q got %q, want accepted %v", i, test.requestID, respID, test.accepted)
+		}
+	}
+}
+
+type testService struct{}
+
+// TestArgs args of test service
+type TestArgs struct{}
+
+func (s *testService) Fail(r *http.Request, args *TestArgs, result *string) error {
+	return errors.New("swap not found")
+}
+
+func TestRPCCodec(t *testing.T) {
+	server := rpc.NewServer()
+	server.RegisterCodec(NewRPCCodec(rpcjson.NewCodec()), "application/json")
+	if err := server.RegisterService(new(testService), "test"); err != nil {
+		t.Fatalf("register service failed: %v", err)
+	}
+	handler := Middleware(server)
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"test.Fail","params":[{}]}`
+	req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderRequestID, "client-id")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var resp struct {
+		Error *struct {
+			Message string             `json:"message"`
+			Data    RequestIDErrorData `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Error == nil {
+		t.Fatalf("decode rpc error response %v failed: %v", rec.Body.String(), err)
+	}
+	if resp.Error.Message != "swap not found" || resp.Error.Data.RequestID != "client-id" {
+		t.Errorf("rpc error response got %+v, want request ID client-id", resp.Error)
+	}
+}
@@ -0,0 +1,194 @@
+// Package versioning provides versioned route groups of the RESTful api
+// and deprecation metadata of endpoints, so that api changes are announced
+// to integrators before old endpoints are removed.
+package versioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/tracing"
+)
+
+// api versions
+const (
+	V1 = "v1"
+	V2 = "v2"
+)
+
+// headers of versioning and deprecation
+const (
+	HeaderAPIVersion  = "X-API-Version"
+	HeaderDeprecation = "Deprecation" // RFC 9745
+	HeaderSunset      = "Sunset"      // RFC 8594
+	HeaderLink        = "Link"
+)
+
+// SunsetSchedulePath path of the sunset schedule endpoint
+const SunsetSchedulePath = "/sunset"
+
+// Deprecation deprecation metadata of endpoint
+type Deprecation struct {
+	Path       string `json:"path"`
+	Deprecated int64  `json:"deprecated"`       // unix seconds
+	Sunset     int64  `json:"sunset,omitempty"` // unix seconds
+	Successor  string `json:"successor,omitempty"`
+	Note       string `json:"note,omitempty"`
+}
+
+// ErrorResponse v2 error response
+type ErrorResponse struct {
+	Error *ErrorInfo `json:"error"`
+}
+
+// ErrorInfo v2 error info
+type ErrorInfo struct {
+	Code      int    `json:"code,omitempty"`
+	Message   string `json:"message"`
+	RequestID string `json:"requestID,omitempty"`
+}
+
+var deprecations = make(map[string]*Deprecation) // key is path
+
+// Init init deprecations of endpoints
+func Init(configs []*params.APIDeprecationConfig) {
+	for _, c := range configs {
+		d := &Deprecation{
+			Path:       c.Path,
+			Deprecated: c.GetDeprecatedTime().Unix(),
+			Successor:  c.Successor,
+			Note:       c.Note,
+		}
+		if !c.GetSunsetTime().IsZero() {
+			d.Sunset = c.GetSunsetTime().Unix()
+		}
+		deprecations[c.Path] = d
+		log.Info("api endpoint deprecation", "path", d.Path, "deprecated", c.Deprecated, "sunset", c.Sunset, "successor", d.Successor)
+	}
+}
+
+// GetDeprecations get deprecations of endpoints in sunset order (unscheduled last)
+func GetDeprecations() []*Deprecation {
+	result := make([]*Deprecation, 0, len(deprecations))
+	for _, d := range deprecations {
+		result = append(result, d)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		si, sj := result[i].Sunset, result[j].Sunset
+		if si != sj {
+			return sj == 0 || (si != 0 && si < sj)
+		}
+		return result[i].Path < result[j].Path
+	})
+	return result
+}
+
+// GetVersion get api version of path, unversioned legacy paths are v1
+func GetVersion(path string) string {
+	if strings.HasPrefix(path, "/"+V2+"/") {
+		return V2
+	}
+	return V1
+}
+
+// StripVersion strip version prefix of path
+func StripVersion(path string) string {
+	for _, version := range []string{V1, V2} {
+		if strings.HasPrefix(path, "/"+version+"/") {
+			return path[len(version)+1:]
+		}
+	}
+	return path
+}
+
+// getVersionedPath get path of deprecation lookup, unversioned legacy paths are v1
+func getVersionedPath(path string) string {
+	if StripVersion(path) == path {
+		return "/" + V1 + path
+	}
+	return path
+}
+
+// Middleware set api version and deprecation headers of the matched route,
+// and respond 410 Gone to endpoints which are sunset.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := mux.CurrentRoute(r)
+		if route == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		version := GetVersion(path)
+		w.Header().Set(HeaderAPIVersion, version)
+
+		d, exist := deprecations[path]
+		if !exist {
+			d, exist = deprecations[getVersionedPath(path)]
+		}
+		if !exist || time.Now().Unix() < d.Deprecated {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set(HeaderDeprecation, fmt.Sprintf("@%d", d.Deprecated))
+		links := []string{fmt.Sprintf("<%v>; rel=\"deprecation\"", SunsetSchedulePath)}
+		if d.Successor != "" {
+			links = append(links, fmt.Sprintf("<%v>; rel=\"successor-version\"", d.Successor))
+		}
+		w.Header().Set(HeaderLink, strings.Join(links, ", "))
+		if d.Sunset != 0 {
+			sunset := time.Unix(d.Sunset, 0)
+			w.Header().Set(HeaderSunset, sunset.UTC().Format(http.TimeFormat))
+			if !time.Now().Before(sunset) {
+				msg := fmt.Sprintf("endpoint %v is sunset since %v", path, sunset.UTC().Format("2006-01-02"))
+				if d.Successor != "" {
+					msg += ", use " + d.Successor
+				}
+				WriteError(w, version, http.StatusGone, 0, msg)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WriteError write error response of api version,
+// v1 responds plain text, v2 responds json error with the http status.
+func WriteError(w http.ResponseWriter, version string, status, code int, message string) {
+	requestID := w.Header().Get(tracing.HeaderRequestID)
+	if version != V2 {
+		// Note: must set header before write header
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(status)
+		if requestID != "" {
+			fmt.Fprintf(w, "%v (requestID: %v)", message, requestID)
+			return
+		}
+		fmt.Fprint(w, message)
+		return
+	}
+	resp := &ErrorResponse{
+		Error: &ErrorInfo{
+			Code:      code,
+			Message:   message,
+			RequestID: requestID,
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Warn("write error response failed", "err", err)
+	}
+}
@@ -0,0 +1,205 @@
+// Package sdk provides a lightweight client of the router RESTful api
+// for go integrators. It only depends on the go standard library,
+// so integrators need not import the whole router to talk to it.
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HeaderRequestID header of request ID in requests and responses
+const HeaderRequestID = "X-Request-ID"
+
+const (
+	// long poll timeout of waiting swap limited by the router
+	maxWaitTimeout = 120 * time.Second
+
+	// max size of response body
+	maxResponseSize = 10 * 1024 * 1024
+
+	// message of not found swaps
+	swapNotFoundMessage = "Swap is not found"
+)
+
+// APIError error returned by the router api
+type APIError struct {
+	Message   string
+	RequestID string
+}
+
+// Error implements error
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("router api error: %v (requestID: %v)", e.Message, e.RequestID)
+	}
+	return "router api error: " + e.Message
+}
+
+// IsNotFound is error of not found swap
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && strings.Contains(apiErr.Message, swapNotFoundMessage)
+}
+
+// Client router api client
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient new client of router api server, eg. https://router.example.com
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: maxWaitTimeout + 10*time.Second}
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID attach request ID to context, it is sent in the
+// X-Request-ID header for correlating with the router logs.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RegisterSwap register swap of tx on chain,
+// logIndex 0 registers all swaps in the tx.
+func (c *Client) RegisterSwap(ctx context.Context, chainID, txid string, logIndex int) (RegisterResult, error) {
+	var result RegisterResult
+	err := c.do(ctx, http.MethodPost, swapPath("/swap/register", chainID, txid, ""), logIndexQuery(logIndex), &result)
+	return result, err
+}
+
+// GetSwap get swap status
+func (c *Client) GetSwap(ctx context.Context, chainID, txid string, logIndex int) (*SwapInfo, error) {
+	var result SwapInfo
+	err := c.do(ctx, http.MethodGet, swapPath("/swap/status", chainID, txid, ""), logIndexQuery(logIndex), &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetSwaps get statuses of all swaps in tx
+func (c *Client) GetSwaps(ctx context.Context, chainID, txid string) ([]*SwapInfo, error) {
+	var result []*SwapInfo
+	err := c.do(ctx, http.MethodGet, swapPath("/swap/status", chainID, txid, "/all"), nil, &result)
+	return result, err
+}
+
+// WaitSwap wait until the swap is in final status or ctx is done.
+// it long polls the router, each poll is limited to 120 seconds by the router,
+// not found swaps are waited too as they may be registered later.
+func (c *Client) WaitSwap(ctx context.Context, chainID, txid string, logIndex int) (*SwapInfo, error) {
+	for {
+		timeout := maxWaitTimeout
+		if deadline, ok := ctx.Deadline(); ok {
+			if timeout = time.Until(deadline); timeout > maxWaitTimeout {
+				timeout = maxWaitTimeout
+			}
+		}
+		if timeout < time.Second {
+			return nil, context.DeadlineExceeded
+		}
+		query := logIndexQuery(logIndex)
+		query.Set("timeout", fmt.Sprint(int64(timeout/time.Second)))
+		var result SwapInfo
+		err := c.do(ctx, http.MethodGet, swapPath("/swap/status", chainID, txid, "/wait"), query, &result)
+		if err != nil && !IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil && result.Status.IsFinalStatus() {
+			return &result, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+	}
+}
+
+// GetSwapConfig get swap config of token route
+func (c *Client) GetSwapConfig(ctx context.Context, tokenID, fromChainID, toChainID string) (*SwapConfig, error) {
+	var result SwapConfig
+	err := c.do(ctx, http.MethodGet, routePath("/swapconfig", tokenID, fromChainID, toChainID), nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetFeeConfig get fee config of token route
+func (c *Client) GetFeeConfig(ctx context.Context, tokenID, fromChainID, toChainID string) (*FeeConfig, error) {
+	var result FeeConfig
+	err := c.do(ctx, http.MethodGet, routePath("/feeconfig", tokenID, fromChainID, toChainID), nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func swapPath(prefix, chainID, txid, suffix string) string {
+	return prefix + "/" + url.PathEscape(chainID) + "/" + url.PathEscape(txid) + suffix
+}
+
+func routePath(prefix, tokenID, fromChainID, toChainID string) string {
+	return prefix + "/" + url.PathEscape(tokenID) + "/" + url.PathEscape(fromChainID) + "/" + url.PathEscape(toChainID)
+}
+
+func logIndexQuery(logIndex int) url.Values {
+	query := url.Values{}
+	if logIndex != 0 {
+		query.Set("logindex", fmt.Sprint(logIndex))
+	}
+	return query
+}
+
+// do send request and decode the json result.
+// the router responds errors in plain text with status 200.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, result interface{}) error {
+	reqURL := c.baseURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+	if err != nil {
+		return err
+	}
+	if requestID, ok := ctx.Value(requestIDKey{}).(string); ok && requestID != "" {
+		req.Header.Set(HeaderRequestID, requestID)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	requestID := resp.Header.Get(HeaderRequestID)
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{Message: fmt.Sprintf("%v %v", resp.Status, strings.TrimSpace(string(body))), RequestID: requestID}
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		msg := strings.TrimSpace(string(body))
+		msg = strings.TrimSuffix(msg, fmt.Sprintf(" (requestID: %v)", requestID))
+		return &APIError{Message: msg, RequestID: requestID}
+	}
+	if len(body) == 0 || string(body) == "null" {
+		return &APIError{Message: "empty response", RequestID: requestID}
+	}
+	return json.Unmarshal(body, result)
+}
@@ -0,0 +1,121 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/swap/register/1/0xabc", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Query().Get("logindex") != "2" {
+			t.Errorf("wrong register request %v %v", r.Method, r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"2":"success"}`)
+	})
+	mux.HandleFunc("/swap/status/1/0xabc", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderRequestID, r.Header.Get(HeaderRequestID))
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintf(w, "mgoError: Swap is not found (requestID: %v)", r.Header.Get(HeaderRequestID))
+	})
+	mux.HandleFunc("/swap/status/1/0xabc/wait", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"txid":"0xabc","fromChainID":"1","toChainID":"56","status":10,"swaptx":"0xdef","swapinfo":{}}`)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestClient(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := NewClient(server.URL+"/", nil)
+	ctx := context.Background()
+
+	registered, err := client.RegisterSwap(ctx, "1", "0xabc", 2)
+	if err != nil || registered[2] != "success" {
+		t.Fatalf("register swap failed, result %v, err %v", registered, err)
+	}
+
+	_, err = client.GetSwap(WithRequestID(ctx, "req-1"), "1", "0xabc", 0)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "req-1" || apiErr.Message != "mgoError: Swap is not found" {
+		t.Fatalf("get swap error mismatch, err %v", err)
+	}
+	if !IsNotFound(err) {
+		t.Fatalf("get swap error is not not-found error, err %v", err)
+	}
+
+	swap, err := client.WaitSwap(ctx, "1", "0xabc", 0)
+	if err != nil || !swap.Status.IsSuccess() || swap.SwapTx != "0xdef" {
+		t.Fatalf("wait swap failed, result %+v, err %v", swap, err)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	swapCfg := &SwapConfig{MinimumSwap: "10000000000000000000", MaximumSwap: "1000000000000000000000000", BigValueThreshold: "100000000000000000000000"}
+	feeCfg := &FeeConfig{SwapFeeRatePerMillion: 1000, MinimumSwapFee: "1000000000000000000", MaximumSwapFee: "100000000000000000000"}
+	args := &QuoteArgs{FromDecimals: 6, ToDecimals: 18}
+
+	tests := []struct {
+		value   int64 // in 6 decimals
+		fee     int64
+		receive string
+		err     error
+	}{
+		{value: 1e6, err: ErrValueTooSmall},
+		{value: 10e6, fee: 1e6, receive: "9000000000000000000"},
+		{value: 5000e6, fee: 5e6, receive: "4995000000000000000000"},
+		{value: 500000e6, fee: 100e6, receive: "499900000000000000000000"},
+		{value: 2000000e6, err: ErrValueTooLarge},
+	}
+	for i, test := range tests {
+		args.Value = big.NewInt(test.value)
+		quote, err := calcQuote(args, swapCfg, feeCfg)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("test %v: error mismatch, have %v, want %v", i, err, test.err)
+		}
+		if err != nil {
+			continue
+		}
+		if quote.SwapFee.Int64() != test.fee || quote.ReceiveValue.String() != test.receive {
+			t.Fatalf("test %v: quote mismatch, have %v %v, want %v %v", i, quote.SwapFee, quote.ReceiveValue, test.fee, test.receive)
+		}
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	secret := []byte("whsec")
+	body := []byte(`{"txid":"0xabc","status":10}`)
+	header := SignWebhook(secret, body, time.Now())
+	if err := VerifyWebhook(secret, body, header, 0); err != nil {
+		t.Fatalf("verify webhook failed, err %v", err)
+	}
+	rotated := header + ",v1=" + computeWebhookSignature([]byte("old"), "0", body)
+	if err := VerifyWebhook(secret, body, rotated, 0); err != nil {
+		t.Fatalf("verify webhook with rotated secret failed, err %v", err)
+	}
+	rotating := SignWebhookWithSecrets([][]byte{[]byte("new"), secret}, body, time.Now())
+	if err := VerifyWebhook(secret, body, rotating, 0); err != nil {
+		t.Fatalf("verify webhook signed by rotating secrets failed, err %v", err)
+	}
+	if err := VerifyWebhook([]byte("other"), body, rotating, 0); !errors.Is(err, ErrWebhookNoMatching) {
+		t.Fatalf("verify webhook with unknown secret, err %v", err)
+	}
+	if err := VerifyWebhook(secret, []byte(`{}`), header, 0); !errors.Is(err, ErrWebhookNoMatching) {
+		t.Fatalf("verify tampered webhook, err %v", err)
+	}
+	old := SignWebhook(secret, body, time.Now().Add(-time.Hour))
+	if err := VerifyWebhook(secret, body, old, 0); !errors.Is(err, ErrWebhookExpired) {
+		t.Fatalf("verify expired webhook, err %v", err)
+	}
+	if err := VerifyWebhook(secret, body, "v1", 0); !errors.Is(err, ErrWebhookBadHeader) {
+		t.Fatalf("verify malformed webhook, err %v", err)
+	}
+}
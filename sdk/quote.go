@@ -0,0 +1,113 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// quote errors
+var (
+	ErrValueTooSmall = errors.New("swap value is less than minimum swap")
+	ErrValueTooLarge = errors.New("swap value is greater than maximum swap")
+	ErrValueNotCover = errors.New("swap value does not cover swap fee")
+)
+
+// QuoteArgs args of quoting swap, value is in decimals of token on source chain
+type QuoteArgs struct {
+	TokenID      string
+	FromChainID  string
+	ToChainID    string
+	Value        *big.Int
+	FromDecimals uint8
+	ToDecimals   uint8
+}
+
+// Quote quote of swap
+type Quote struct {
+	SwapFee      *big.Int // in decimals of token on source chain
+	ReceiveValue *big.Int // in decimals of token on destination chain
+	IsBigValue   bool     // big value swaps wait for manual review
+}
+
+// GetQuote quote swap by the swap and fee configs of token route.
+// it is an estimation which does not count additional source chain fees
+// configured on chain and base fee adjustments of destination chain.
+func (c *Client) GetQuote(ctx context.Context, args *QuoteArgs) (*Quote, error) {
+	if args.Value == nil || args.Value.Sign() <= 0 {
+		return nil, ErrValueTooSmall
+	}
+	swapCfg, err := c.GetSwapConfig(ctx, args.TokenID, args.FromChainID, args.ToChainID)
+	if err != nil {
+		return nil, err
+	}
+	feeCfg, err := c.GetFeeConfig(ctx, args.TokenID, args.FromChainID, args.ToChainID)
+	if err != nil {
+		return nil, err
+	}
+	return calcQuote(args, swapCfg, feeCfg)
+}
+
+func calcQuote(args *QuoteArgs, swapCfg *SwapConfig, feeCfg *FeeConfig) (*Quote, error) {
+	var minSwap, maxSwap, bigValue, minFee, maxFee *big.Int
+	for _, v := range []struct {
+		dst **big.Int
+		src string
+	}{
+		{&minSwap, swapCfg.MinimumSwap},
+		{&maxSwap, swapCfg.MaximumSwap},
+		{&bigValue, swapCfg.BigValueThreshold},
+		{&minFee, feeCfg.MinimumSwapFee},
+		{&maxFee, feeCfg.MaximumSwapFee},
+	} {
+		value, ok := new(big.Int).SetString(v.src, 10)
+		if !ok {
+			return nil, fmt.Errorf("wrong config value %q", v.src)
+		}
+		*v.dst = convertTokenValue(value, 18, args.FromDecimals)
+	}
+
+	value := args.Value
+	if value.Cmp(minSwap) < 0 {
+		return nil, ErrValueTooSmall
+	}
+	if value.Cmp(maxSwap) > 0 {
+		return nil, ErrValueTooLarge
+	}
+
+	feeRate := feeCfg.SwapFeeRatePerMillion
+	useFixedFee := minFee.Sign() > 0 && minFee.Cmp(maxFee) == 0
+	if useFixedFee {
+		feeRate = 0
+	}
+	swapFee := new(big.Int).Mul(value, new(big.Int).SetUint64(feeRate))
+	swapFee.Div(swapFee, big.NewInt(1000000))
+	if swapFee.Cmp(minFee) < 0 {
+		swapFee = minFee
+	} else if swapFee.Cmp(maxFee) > 0 {
+		swapFee = maxFee
+	}
+	if feeRate == 0 && !useFixedFee {
+		swapFee = big.NewInt(0)
+	}
+	if value.Cmp(swapFee) <= 0 {
+		return nil, ErrValueNotCover
+	}
+	return &Quote{
+		SwapFee:      swapFee,
+		ReceiveValue: convertTokenValue(new(big.Int).Sub(value, swapFee), args.FromDecimals, args.ToDecimals),
+		IsBigValue:   value.Cmp(bigValue) > 0,
+	}, nil
+}
+
+func convertTokenValue(value *big.Int, fromDecimals, toDecimals uint8) *big.Int {
+	switch {
+	case fromDecimals > toDecimals:
+		return new(big.Int).Div(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(fromDecimals-toDecimals)), nil))
+	case fromDecimals < toDecimals:
+		return new(big.Int).Mul(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(toDecimals-fromDecimals)), nil))
+	default:
+		return value
+	}
+}
@@ -0,0 +1,97 @@
+package sdk
+
+import (
+	"encoding/json"
+)
+
+// RegisterResult result of registering swap, key is log index, value is "success" or error message
+type RegisterResult map[int]string
+
+// SwapStatus swap status (same values as the router)
+type SwapStatus uint16
+
+// swap status values
+const (
+	TxNotStable        SwapStatus = 0
+	TxVerifyFailed     SwapStatus = 1
+	TxWithWrongValue   SwapStatus = 3
+	TxNotSwapped       SwapStatus = 5
+	TxProcessed        SwapStatus = 7
+	MatchTxEmpty       SwapStatus = 8
+	MatchTxNotStable   SwapStatus = 9
+	MatchTxStable      SwapStatus = 10
+	TxWithBigValue     SwapStatus = 12
+	MatchTxFailed      SwapStatus = 14
+	SwapInBlacklist    SwapStatus = 15
+	ManualMakeFail     SwapStatus = 16
+	TxWithWrongPath    SwapStatus = 19
+	MissTokenConfig    SwapStatus = 20
+	NoUnderlyingToken  SwapStatus = 21
+	TxMaybeUnsafe      SwapStatus = 22
+	SwapoutForbidden   SwapStatus = 23
+	TxNeedReswap       SwapStatus = 24
+	TokenRouteDisabled SwapStatus = 25
+)
+
+// IsFinalStatus is swap in a terminal status, which will not be
+// changed by the router automatically (except by manual process)
+func (status SwapStatus) IsFinalStatus() bool {
+	switch status {
+	case MatchTxStable, MatchTxFailed, ManualMakeFail,
+		TxVerifyFailed, TxWithWrongValue, SwapInBlacklist,
+		TxWithWrongPath, MissTokenConfig, NoUnderlyingToken,
+		SwapoutForbidden, TokenRouteDisabled:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSuccess is swap finished successfully
+func (status SwapStatus) IsSuccess() bool {
+	return status == MatchTxStable
+}
+
+// SwapInfo swap status info
+type SwapInfo struct {
+	SwapType         uint32          `json:"swaptype"`
+	TxID             string          `json:"txid"`
+	TxTo             string          `json:"txto,omitempty"`
+	TxHeight         uint64          `json:"txheight"`
+	From             string          `json:"from"`
+	To               string          `json:"to"`
+	Bind             string          `json:"bind"`
+	Value            string          `json:"value"`
+	ValueDisplay     string          `json:"valueDisplay,omitempty"`
+	LogIndex         int             `json:"logIndex,omitempty"`
+	FromChainID      string          `json:"fromChainID"`
+	ToChainID        string          `json:"toChainID"`
+	SwapInfo         json.RawMessage `json:"swapinfo"`
+	SwapTx           string          `json:"swaptx"`
+	SwapHeight       uint64          `json:"swapheight"`
+	SwapValue        string          `json:"swapvalue"`
+	SwapValueDisplay string          `json:"swapvalueDisplay,omitempty"`
+	SwapNonce        uint64          `json:"swapnonce"`
+	SwapFee          string          `json:"swapfee,omitempty"`
+	Status           SwapStatus      `json:"status"`
+	StatusMsg        string          `json:"statusmsg"`
+	InitTime         int64           `json:"inittime"`
+	Timestamp        int64           `json:"timestamp"`
+	Memo             string          `json:"memo,omitempty"`
+	ReplaceCount     int             `json:"replaceCount,omitempty"`
+	Confirmations    uint64          `json:"confirmations"`
+}
+
+// SwapConfig swap config of token route, values are in 18 decimals
+type SwapConfig struct {
+	MaximumSwap       string
+	MinimumSwap       string
+	BigValueThreshold string
+}
+
+// FeeConfig fee config of token route, values are in 18 decimals
+type FeeConfig struct {
+	SwapFeeRatePerMillion uint64
+	MaximumSwapFee        string
+	MinimumSwapFee        string
+}
@@ -0,0 +1,107 @@
+package sdk
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// webhook notifications of swap events (body is json of WebhookEvent) are
+// signed by the shared secret of the subscriber:
+//
+//	X-Router-Signature: t=<unix seconds>,v1=<hex of hmac-sha256(secret, "<unix seconds>.<body>")>
+//
+// more than one v1 signatures may be present when the secret is being rotated.
+// the notifications are delivered at least once in time order, subscribers
+// should ignore the events of the same ID delivered again.
+
+// HeaderWebhookSignature header of webhook signature
+const HeaderWebhookSignature = "X-Router-Signature"
+
+// DefaultWebhookTolerance max age of webhook timestamps to prevent replay
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// webhook errors
+var (
+	ErrWebhookBadHeader  = errors.New("webhook signature header is malformed")
+	ErrWebhookExpired    = errors.New("webhook timestamp is out of tolerance")
+	ErrWebhookNoMatching = errors.New("webhook signature does not match")
+)
+
+// WebhookEvent swap event notified by webhook
+type WebhookEvent struct {
+	ID          string `json:"id"`
+	Identifier  string `json:"identifier"`
+	FromChainID string `json:"fromChainID"`
+	TxID        string `json:"txid"`
+	LogIndex    int    `json:"logIndex"`
+	Event       string `json:"event"`
+	Timestamp   int64  `json:"timestamp"`          // milli seconds
+	Duration    int64  `json:"duration,omitempty"` // milli seconds
+	Detail      string `json:"detail,omitempty"`
+}
+
+// SignWebhook sign webhook body, returns the signature header value
+func SignWebhook(secret, body []byte, timestamp time.Time) string {
+	return SignWebhookWithSecrets([][]byte{secret}, body, timestamp)
+}
+
+// SignWebhookWithSecrets sign webhook body with every secret (in rotating),
+// returns the signature header value
+func SignWebhookWithSecrets(secrets [][]byte, body []byte, timestamp time.Time) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	header := "t=" + ts
+	for _, secret := range secrets {
+		header += ",v1=" + computeWebhookSignature(secret, ts, body)
+	}
+	return header
+}
+
+// VerifyWebhook verify the signature header of webhook body,
+// tolerance <= 0 means DefaultWebhookTolerance.
+func VerifyWebhook(secret, body []byte, header string, tolerance time.Duration) error {
+	var ts string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			return ErrWebhookBadHeader
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case "v1":
+			signatures = append(signatures, kv[1])
+		}
+	}
+	timestamp, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrWebhookBadHeader
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+	age := time.Since(time.Unix(timestamp, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrWebhookExpired
+	}
+	expected := computeWebhookSignature(secret, ts, body)
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrWebhookNoMatching
+}
+
+func computeWebhookSignature(secret []byte, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
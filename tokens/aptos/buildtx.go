@@ -218,7 +218,7 @@ func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken
 	if toTokenCfg == nil {
 		return receiver, amount, tokens.ErrMissTokenConfig
 	}
-	swapValue := tokens.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo)
+	swapValue := args.ApplyConversionRate(args.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo))
 	if !swapValue.IsUint64() {
 		return receiver, amount, tokens.ErrTxWithWrongValue
 	}
@@ -257,7 +257,7 @@ func (b *Bridge) getMaxFee() string {
 func (b *Bridge) getAccountNonce(args *tokens.BuildTxArgs) (nonceptr *uint64, err error) {
 	var nonce uint64
 
-	if params.IsParallelSwapEnabled() {
+	if params.IsParallelSwapEnabledOnChain(b.ChainConfig.ChainID) {
 		nonce, err = b.AllocateNonce(args)
 		return &nonce, err
 	}
@@ -3,6 +3,7 @@ package aptos
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 
@@ -102,12 +103,6 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 		return nil, errTxResultType
 	}
 
-	// Check tx status
-	if !txres.Success {
-		log.Warn("Aptos tx status is not success", "result", txres.Success)
-		return nil, tokens.ErrTxWithWrongStatus
-	}
-
 	status.Receipt = nil
 	inledger, err := strconv.ParseUint(txres.Version, 10, 64)
 	if err != nil {
@@ -115,8 +110,19 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 	}
 	status.BlockHeight = inledger
 
-	if latest, err := b.GetLatestBlockNumber(); err == nil && latest > uint64(inledger) {
-		status.Confirmations = latest - uint64(inledger)
+	// Check tx status
+	if !txres.Success {
+		log.Warn("Aptos tx status is not success", "result", txres.Success, "vmStatus", txres.VmStatus)
+		status.SetFailed(txres.VmStatus)
+	}
+	if gasUsed, errf := strconv.ParseUint(txres.GasUsed, 10, 64); errf == nil {
+		if gasPrice, errf := strconv.ParseUint(txres.GasUnitPrice, 10, 64); errf == nil {
+			status.FeePaid = new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), new(big.Int).SetUint64(gasPrice)).String()
+		}
+	}
+
+	if latest, err := b.GetLatestBlockNumber(); err == nil {
+		status.SetLatestHeight(latest, b.GetChainConfig().Confirmations)
 	}
 	return
 }
@@ -6,7 +6,6 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -153,24 +152,6 @@ func (c *RestClient) GetTransactionByVersion(version string) (*TransactionInfo,
 	return &resp, err
 }
 
-func (c *RestClient) GetTransactionsNotPending(txHash string) (*TransactionInfo, error) {
-	resp := TransactionInfo{}
-	param := map[string]string{
-		"txn_hash": txHash,
-	}
-	for i := 0; i < 3; i++ {
-		err := c.GetRequest(&resp, GetTransactionsPath, param)
-		if err != nil {
-			return nil, err
-		}
-		if resp.Success {
-			break
-		}
-		time.Sleep(time.Second * 3)
-	}
-	return &resp, nil
-}
-
 func (c *RestClient) GetSigningMessage(request interface{}) (*string, error) {
 	resp := ""
 	err := c.PostRequest(&resp, GetSigningMessagePath, request)
@@ -1,13 +1,19 @@
 package aptos
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tools/retry"
 )
 
-var wrapRPCQueryError = tokens.WrapRPCQueryError
+var (
+	wrapRPCQueryError = tokens.WrapRPCQueryError
+
+	errTxIsPending = errors.New("tx is pending")
+)
 
 // GetLedger get ledger info
 func (b *Bridge) GetLedger() (result *LedgerInfo, err error) {
@@ -77,17 +83,23 @@ func (b *Bridge) GetTransactions(txHash string) (result *TransactionInfo, err er
 	return nil, wrapRPCQueryError(err, "GetTransactions")
 }
 
-// GetTransactionsNotPending get non pending tx by hash
+// GetTransactionsNotPending get tx by hash, retry with the retry policy of chain until it is not pending,
+// the last pending tx is returned if it is still pending after retrying.
 func (b *Bridge) GetTransactionsNotPending(txHash string) (result *TransactionInfo, err error) {
-	cli := RestClient{Timeout: b.RPCClientTimeout}
-	for _, url := range b.GatewayConfig.AllGatewayURLs {
-		cli.Url = url
-		result, err = cli.GetTransactionsNotPending(txHash)
-		if err == nil {
-			return result, nil
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
+		result, err = b.GetTransactions(txHash)
+		if err != nil {
+			return retry.Permanent(err)
+		}
+		if !result.Success {
+			return errTxIsPending
 		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errTxIsPending) {
+		return nil, err
 	}
-	return nil, wrapRPCQueryError(err, "GetTransactionsNotPending")
+	return result, nil
 }
 
 // EstimateGasPrice estimate gas price
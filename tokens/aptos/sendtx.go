@@ -4,10 +4,14 @@ import (
 	"errors"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
 )
 
 // SendTransaction impl
 func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	tx, ok := signedTx.(*Transaction)
 	if !ok {
 		return "", errors.New("wrong signed transaction type")
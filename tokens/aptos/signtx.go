@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/anyswap/CrossChain-Router/v3/params"
@@ -13,6 +14,13 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 )
 
+// chainFamily chain family in the mpc sign scheme registry
+const chainFamily = "aptos"
+
+func init() {
+	mpc.RegisterChainFamily(chainFamily, mpc.SchemeEd25519)
+}
+
 func (b *Bridge) verifyTransactionWithArgs(tx *Transaction, args *tokens.BuildTxArgs) error {
 	swapin := tx.Payload.Arguments
 
@@ -52,6 +60,12 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 		log.Warn("Verify transaction failed", "txid", args.SwapID, "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "err", err)
 		return nil, "", err
 	}
+	if tx.Payload == nil {
+		return nil, "", errors.New("transaction without payload")
+	}
+	if err = tokens.CheckDestMethods(b.ChainConfig.ChainID, tx.Payload.Function); err != nil {
+		return nil, "", err
+	}
 
 	mpcParams := params.GetMPCConfig(b.UseFastMPC)
 	if mpcParams.SignWithPrivateKey {
@@ -82,20 +96,14 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 	log.Info(logPrefix+"start", "txid", txid, "fromChainID", args.FromChainID, "toChainID", args.ToChainID)
 
 	mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
-	keyID, rsvs, err := mpcConfig.DoSignOneED(mpcPubkey, msgContent, msgContext)
+	keyID, signature, err := mpcConfig.DoSignHashWithScheme(chainFamily, mpcPubkey, msgContent, msgContext)
 	if err != nil {
 		log.Info(logPrefix+"failed", "keyID", keyID, "txid", txid, "err", err)
 		return nil, "", err
 	}
 	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "fromChainID", args.FromChainID, "toChainID", args.ToChainID)
 
-	if len(rsvs) != 1 {
-		log.Error("get sign status require one rsv but return many",
-			"rsvs", len(rsvs), "keyID", keyID, "txid", txid, "fromChainID", args.FromChainID, "toChainID", args.ToChainID)
-		return nil, "", errors.New("get sign status require one rsv but return many")
-	}
-
-	rsv := rsvs[0]
+	rsv := common.ToHex(signature)
 	log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "rsv", rsv)
 
 	tx.Signature = &TransactionSignature{
@@ -0,0 +1,52 @@
+package tokens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+)
+
+const (
+	attestationStatusComplete = "complete"
+
+	attestationTimeout = 10 // seconds
+)
+
+// USDCAttestation usdc attestation,
+// the attestation is 'PENDING' until the status is 'complete'
+type USDCAttestation struct {
+	Attestation string `json:"attestation"`
+	Status      string `json:"status"`
+}
+
+// IsComplete is attestation complete
+func (a *USDCAttestation) IsComplete() bool {
+	return a.Status == attestationStatusComplete &&
+		common.HasHexPrefix(a.Attestation) &&
+		len(a.Attestation) > 2 &&
+		common.IsHex(a.Attestation[2:])
+}
+
+// GetUSDCAttestation get usdc attestation of message
+func GetUSDCAttestation(message []byte) (*USDCAttestation, error) {
+	attestationURL := strings.TrimSuffix(params.GetAttestationServer(), "/")
+	if attestationURL == "" {
+		return nil, ErrNoAttestationServer
+	}
+
+	messageHash := common.Keccak256Hash(message)
+	url := fmt.Sprintf("%v/attestations/%v", attestationURL, messageHash.String())
+
+	var res *USDCAttestation
+	err := client.RPCGetWithTimeout(&res, url, attestationTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w. %v %v", ErrGetAttestationFailed, messageHash.String(), err)
+	}
+	if res == nil {
+		return nil, fmt.Errorf("%w. %v empty result", ErrGetAttestationFailed, messageHash.String())
+	}
+	return res, nil
+}
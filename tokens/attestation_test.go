@@ -0,0 +1,21 @@
+package tokens
+
+import "testing"
+
+func TestUSDCAttestationIsComplete(t *testing.T) {
+	tests := []struct {
+		attestation USDCAttestation
+		want        bool
+	}{
+		{USDCAttestation{Attestation: "PENDING", Status: "pending_confirmations"}, false},
+		{USDCAttestation{Attestation: "PENDING", Status: "complete"}, false},
+		{USDCAttestation{Attestation: "0x", Status: "complete"}, false},
+		{USDCAttestation{Attestation: "0xaabbcc", Status: "pending_confirmations"}, false},
+		{USDCAttestation{Attestation: "0xaabbcc", Status: "complete"}, true},
+	}
+	for i, test := range tests {
+		if got := test.attestation.IsComplete(); got != test.want {
+			t.Errorf("test %v: is complete got %v, want %v", i, got, test.want)
+		}
+	}
+}
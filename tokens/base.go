@@ -22,6 +22,9 @@ var (
 
 	// StubChainIDBase stub chainID base value
 	StubChainIDBase = big.NewInt(1000000000000)
+
+	// RateUnit unit of conversion rates (1e18)
+	RateUnit = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
 )
 
 // OnchainCustomConfig onchain custom config (in router config)
@@ -321,11 +324,18 @@ func CheckTokenSwapValue(swapInfo *SwapTxInfo, fromDecimals, toDecimals uint8) b
 		!params.IsInBigValueWhitelist(tokenID, swapInfo.TxTo) {
 		return false
 	}
-	return CalcSwapValue(tokenID, fromChainID, toChainID, value, fromDecimals, toDecimals, swapInfo.From, swapInfo.TxTo).Sign() > 0
+	feePercent := GetDeliveryFeePercent(tokenID, toChainID)
+	return calcSwapValue(tokenID, fromChainID, toChainID, value, fromDecimals, toDecimals, swapInfo.From, swapInfo.TxTo, feePercent).Sign() > 0
 }
 
 // CalcSwapValue calc swap value (get rid of fee and convert by decimals)
 func CalcSwapValue(tokenID, fromChainID, toChainID string, value *big.Int, fromDecimals, toDecimals uint8, originFrom, originTxTo string) *big.Int {
+	return calcSwapValue(tokenID, fromChainID, toChainID, value, fromDecimals, toDecimals, originFrom, originTxTo, 0)
+}
+
+// calcSwapValue calc swap value, the minimum swap fees are scaled
+// by feePercent of delivery costs if it is not zero.
+func calcSwapValue(tokenID, fromChainID, toChainID string, value *big.Int, fromDecimals, toDecimals uint8, originFrom, originTxTo string, feePercent uint64) *big.Int {
 	if !IsERC20Router() {
 		return value
 	}
@@ -354,13 +364,20 @@ func CalcSwapValue(tokenID, fromChainID, toChainID string, value *big.Int, fromD
 		maximumSwapFee = cmath.BigMax(feeCfg.MaximumSwapFee, srcFeeCfg.AdditionalSrcMaximumSwapFee)
 	}
 
+	fixedSwapFee := feeCfg.MinimumSwapFee
+	if feePercent != 0 && feePercent != 100 {
+		minimumSwapFee = scaleByPercent(minimumSwapFee, feePercent)
+		fixedSwapFee = scaleByPercent(fixedSwapFee, feePercent)
+		maximumSwapFee = cmath.BigMax(maximumSwapFee, minimumSwapFee)
+	}
+
 	valueLeft := value
 	if swapfeeRatePerMillion > 0 || useFixedFee {
 		log.Info("calc swap fee start",
 			"tokenID", tokenID, "fromChainID", fromChainID, "toChainID", toChainID,
 			"value", value, "feeRate", swapfeeRatePerMillion, "useFixedFee", useFixedFee,
 			"cfgFeeRate", feeCfg.SwapFeeRatePerMillion, "srcFeeRate", srcFeeRate,
-			"minFee", minimumSwapFee, "maxFee", maximumSwapFee)
+			"minFee", minimumSwapFee, "maxFee", maximumSwapFee, "deliveryFeePercent", feePercent)
 
 		var swapFee, adjustBaseFee *big.Int
 		minSwapFee := ConvertTokenValue(minimumSwapFee, 18, fromDecimals)
@@ -376,7 +393,7 @@ func CalcSwapValue(tokenID, fromChainID, toChainID string, value *big.Int, fromD
 			}
 
 			if useFixedFee {
-				fixedFee := ConvertTokenValue(feeCfg.MinimumSwapFee, 18, fromDecimals)
+				fixedFee := ConvertTokenValue(fixedSwapFee, 18, fromDecimals)
 				swapFee = cmath.BigMax(swapFee, fixedFee)
 			}
 
@@ -414,6 +431,11 @@ func CalcSwapValue(tokenID, fromChainID, toChainID string, value *big.Int, fromD
 	return ConvertTokenValue(valueLeft, fromDecimals, toDecimals)
 }
 
+func scaleByPercent(value *big.Int, percent uint64) *big.Int {
+	result := new(big.Int).Mul(value, new(big.Int).SetUint64(percent))
+	return result.Div(result, big.NewInt(100))
+}
+
 // ToBits calc
 func ToBits(valueStr string, decimals uint8) *big.Int {
 	parts := strings.Split(valueStr, ".")
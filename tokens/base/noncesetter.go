@@ -12,9 +12,6 @@ import (
 )
 
 var (
-	retryRPCCount    = 3
-	retryRPCInterval = 1 * time.Second
-
 	recycleAckInterval = int64(300) // seconds
 
 	errRecycleNotAcked = errors.New("recycle timestamp does not pass ack interval")
@@ -86,19 +83,16 @@ func (b *NonceSetterBase) InitSwapNonce(br tokens.NonceSetter, address string, n
 	defer b.swapNonceLock.Unlock()
 
 	dbNexNonce := nonce
-	for i := 0; i < retryRPCCount; i++ {
-		pendingNonce, err := br.GetPoolNonce(address, "pending")
-		if err == nil {
-			if pendingNonce > nonce {
-				log.Warn("init swap nonce with onchain account nonce", "chainID", b.ChainConfig.ChainID, "dbNonce", nonce, "accountNonce", pendingNonce)
-				nonce = pendingNonce
-			}
-			break
-		}
-		if i+1 == retryRPCCount {
-			log.Warn("init swap nonce get account nonce failed", "chainID", b.ChainConfig.ChainID, "account", address, "err", err)
-		}
-		time.Sleep(retryRPCInterval)
+	var pendingNonce uint64
+	err := tokens.RetryRPC(b.ChainConfig.ChainID, func() (err error) {
+		pendingNonce, err = br.GetPoolNonce(address, "pending")
+		return err
+	})
+	if err != nil {
+		log.Warn("init swap nonce get account nonce failed", "chainID", b.ChainConfig.ChainID, "account", address, "err", err)
+	} else if pendingNonce > nonce {
+		log.Warn("init swap nonce with onchain account nonce", "chainID", b.ChainConfig.ChainID, "dbNonce", nonce, "accountNonce", pendingNonce)
+		nonce = pendingNonce
 	}
 	b.swapNonce[strings.ToLower(address)] = &nonce
 	log.Info("init swap nonce success", "chainID", b.ChainConfig.ChainID, "account", address, "dbNexNonce", dbNexNonce, "nonce", nonce)
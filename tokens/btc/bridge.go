@@ -3,7 +3,6 @@ package btc
 import (
 	"math/big"
 	"sync"
-	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
@@ -126,22 +125,18 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 		txStatus.BlockHeight = *electStatus.BlockHeight
 		latest, errt := b.GetLatestBlockNumber()
 		if errt == nil {
-			if latest > txStatus.BlockHeight {
-				txStatus.Confirmations = latest - txStatus.BlockHeight
-			}
+			// confirmations are counted from the next block
+			txStatus.SetLatestHeight(latest, b.GetChainConfig().Confirmations)
 		}
 	}
 	return txStatus, nil
 }
 
 func (b *Bridge) getTransactionByHashWithRetry(txid string) (tx *ElectTx, err error) {
-	for i := 0; i < retryCount; i++ {
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
 		tx, err = b.GetTransactionByHash(txid)
-		if err == nil {
-			break
-		}
-		time.Sleep(retryInterval)
-	}
+		return err
+	})
 	return tx, err
 }
 
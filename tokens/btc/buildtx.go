@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -20,10 +19,7 @@ import (
 )
 
 var (
-	retryCount = 3
-
 	UnlockMemoPrefix     = "SWAPTX:"
-	retryInterval        = 3 * time.Second
 	cfgEstimateFeeBlocks = 6
 	cfgPlusFeePercentage uint64
 	cfgMinRelayFeePerKb  int64 = 2000
@@ -176,13 +172,10 @@ func (b *Bridge) NewUnsignedTransaction(outputs []*wire.TxOut, relayFeePerKb btc
 }
 
 func (b *Bridge) findUxtosWithRetry(from string) (utxos []*ElectUtxo, err error) {
-	for i := 0; i < retryCount; i++ {
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
 		utxos, err = b.FindUtxos(from)
-		if err == nil {
-			break
-		}
-		time.Sleep(retryInterval)
-	}
+		return err
+	})
 	return utxos, err
 }
 
@@ -247,13 +240,10 @@ func (b *Bridge) selectUtxos(from string, target btcAmountType) (total btcAmount
 }
 
 func (b *Bridge) getRelayFeePerKb() (estimateFee int64, err error) {
-	for i := 0; i < retryCount; i++ {
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
 		estimateFee, err = b.EstimateFeePerKb(cfgEstimateFeeBlocks)
-		if err == nil {
-			break
-		}
-		time.Sleep(retryInterval)
-	}
+		return err
+	})
 	if err != nil {
 		log.Warn("estimate smart fee failed", "err", err)
 		return 0, err
@@ -331,6 +321,6 @@ func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken
 	if toTokenCfg == nil {
 		return receiver, amount, tokens.ErrMissTokenConfig
 	}
-	amount = tokens.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo)
+	amount = args.ApplyConversionRate(args.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo))
 	return receiver, amount, err
 }
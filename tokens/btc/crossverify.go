@@ -0,0 +1,18 @@
+package btc
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var _ tokens.TxCrossVerifier = &Bridge{}
+
+// CrossVerifyTx impl tokens.TxCrossVerifier
+func (b *Bridge) CrossVerifyTx(txHash string, quorum int) error {
+	return tokens.CrossVerifyTx(b.ChainConfig.ChainID, txHash, b.GatewayConfig.AllGatewayURLs, quorum, func(url string) (string, error) {
+		tx, err := GetTransactionByHash(url, txHash)
+		if err != nil {
+			return "", err
+		}
+		return tokens.TxFingerprint(tx)
+	})
+}
@@ -5,12 +5,16 @@ import (
 	"encoding/hex"
 	"errors"
 
+	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 )
 
 // SendTransaction send signed tx
 func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	authoredTx, ok := signedTx.(*txauthor.AuthoredTx)
 	if !ok {
 		return "", tokens.ErrWrongRawTx
@@ -10,6 +10,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/payload"
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 )
 
@@ -243,14 +244,11 @@ func GetBindAddressFromMemoScipt(memoScript string) (bind string, toChainID stri
 	}
 	memoHex := strings.TrimSpace(parts[1])
 	memo := common.FromHex(memoHex)
-	memoStr := string(memo)
-	memoArray := strings.Split(memoStr, ":")
-	if len(memoArray) != 2 {
+	p, err := payload.Decode(payload.FormatTextV1, memo)
+	if err != nil {
 		return "", "", false
 	}
-	bind = memoArray[0]
-	toChainID = memoArray[1]
-	return bind, toChainID, true
+	return p.Bind, p.ToChainID.String(), true
 }
 
 // return priorityAddress if has it in Vin
@@ -325,19 +325,18 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 	if res, err := b.GetTransactionByHash(txHash); err != nil {
 		return nil, err
 	} else {
+		status.BlockHeight = res.Block.Number
+		status.Receipt = nil
 		if !res.ValidContract {
 			ClearTransactionChainingKeyCache(txHash)
-			return nil, tokens.ErrTxIsNotValidated
+			status.SetFailed(tokens.ErrTxIsNotValidated.Error())
+		}
+		if lastHeight, err := b.GetLatestBlockNumber(); err != nil {
+			return nil, err
 		} else {
-			status.BlockHeight = res.Block.Number
-			status.Receipt = nil
-			if lastHeight, err := b.GetLatestBlockNumber(); err != nil {
-				return nil, err
-			} else if lastHeight > res.Block.Number {
-				status.Confirmations = lastHeight - res.Block.Number
-				if status.Confirmations > b.GetChainConfig().Confirmations {
-					ClearTransactionChainingKeyCache(txHash)
-				}
+			status.SetLatestHeight(lastHeight, b.GetChainConfig().Confirmations)
+			if res.ValidContract && status.Confirmations > b.GetChainConfig().Confirmations {
+				ClearTransactionChainingKeyCache(txHash)
 			}
 		}
 	}
@@ -1,9 +1,15 @@
 package cardano
 
-import "github.com/anyswap/CrossChain-Router/v3/log"
+import (
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
 
 // SendTransaction send signed tx
 func (b *Bridge) SendTransaction(signedTx interface{}) (string, error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	signedTransaction := signedTx.(*SignedTransaction)
 
 	txhash, err := b.RpcClient.SubmitTx(signedTransaction.Tx)
@@ -1,9 +1,7 @@
 package cardano
 
 import (
-	"crypto/ed25519"
 	"encoding/json"
-	"errors"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -16,6 +14,13 @@ import (
 	"github.com/echovl/cardano-go/crypto"
 )
 
+// chainFamily chain family in the mpc sign scheme registry
+const chainFamily = "cardano"
+
+func init() {
+	mpc.RegisterChainFamily(chainFamily, mpc.SchemeEd25519)
+}
+
 // MPCSignTransaction mpc sign raw tx
 func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
 	if rawTransaction, ok := rawTx.(*RawTransaction); !ok {
@@ -49,39 +54,27 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 		log.Info(logPrefix+"start", "txid", txid)
 
 		mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
-		if keyID, rsvs, err := mpcConfig.DoSignOneED(mpcPubkey, signingMsg.String(), msgContext); err != nil {
+		keyID, sig, err := mpcConfig.DoSignHashWithScheme(chainFamily, mpcPubkey, signingMsg.String(), msgContext)
+		if err != nil {
 			log.Info(logPrefix+"failed", "keyID", keyID, "txid", txid, "err", err)
 			return nil, "", err
-		} else {
-			if len(rsvs) != 1 {
-				log.Warn("get sign status require one rsv but return many",
-					"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
-				return nil, "", errors.New("get sign status require one rsv but return many")
-			}
-
-			rsv := rsvs[0]
-			log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", rsv)
-			sig := common.FromHex(rsv)
-			if len(sig) != ed25519.SignatureSize {
-				log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(sig), "want", ed25519.SignatureSize)
-				return nil, "", errors.New("wrong signature length")
-			}
-
-			pubStr, _ := bech32.EncodeFromBase256("addr_vk", common.FromHex(mpcPubkey))
-			pubKey, _ := crypto.NewPubKey(pubStr)
-			b.AppendSignature(tx, pubKey, sig)
-
-			cacheAssetsMap := rawTransaction.TxOuts[args.From]
-			txInputs := rawTransaction.TxIns
-			txIndex := rawTransaction.TxIndex
-			return &SignedTransaction{
-				TxIns:     txInputs,
-				TxHash:    signingMsg.String(),
-				TxIndex:   txIndex,
-				AssetsMap: cacheAssetsMap,
-				Tx:        tx,
-			}, signingMsg.String(), nil
 		}
+		log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", common.ToHex(sig))
+
+		pubStr, _ := bech32.EncodeFromBase256("addr_vk", common.FromHex(mpcPubkey))
+		pubKey, _ := crypto.NewPubKey(pubStr)
+		b.AppendSignature(tx, pubKey, sig)
+
+		cacheAssetsMap := rawTransaction.TxOuts[args.From]
+		txInputs := rawTransaction.TxIns
+		txIndex := rawTransaction.TxIndex
+		return &SignedTransaction{
+			TxIns:     txInputs,
+			TxHash:    signingMsg.String(),
+			TxIndex:   txIndex,
+			AssetsMap: cacheAssetsMap,
+			Tx:        tx,
+		}, signingMsg.String(), nil
 	}
 }
 
@@ -184,38 +177,26 @@ func (b *Bridge) MPCSignSwapTransaction(rawTx interface{}, args *tokens.BuildTxA
 		log.Info(logPrefix+"start", "txid", txid)
 
 		mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
-		if keyID, rsvs, err := mpcConfig.DoSignOneED(mpcPubkey, signingMsg.String(), msgContext); err != nil {
+		keyID, sig, err := mpcConfig.DoSignHashWithScheme(chainFamily, mpcPubkey, signingMsg.String(), msgContext)
+		if err != nil {
 			log.Info(logPrefix+"failed", "keyID", keyID, "txid", txid, "err", err)
 			return nil, "", err
-		} else {
-			if len(rsvs) != 1 {
-				log.Warn("get sign status require one rsv but return many",
-					"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
-				return nil, "", errors.New("get sign status require one rsv but return many")
-			}
-
-			rsv := rsvs[0]
-			log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", rsv)
-			sig := common.FromHex(rsv)
-			if len(sig) != ed25519.SignatureSize {
-				log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(sig), "want", ed25519.SignatureSize)
-				return nil, "", errors.New("wrong signature length")
-			}
-
-			pubStr, _ := bech32.EncodeFromBase256("addr_vk", common.FromHex(mpcPubkey))
-			pubKey, _ := crypto.NewPubKey(pubStr)
-			b.AppendSignature(tx, pubKey, sig)
-
-			cacheAssetsMap := rawTransaction.TxOuts[args.From]
-			txInputs := rawTransaction.TxIns
-			txIndex := rawTransaction.TxIndex
-			return &SignedTransaction{
-				TxIns:     txInputs,
-				TxHash:    txHash,
-				TxIndex:   txIndex,
-				AssetsMap: cacheAssetsMap,
-				Tx:        tx,
-			}, txHash, nil
 		}
+		log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", common.ToHex(sig))
+
+		pubStr, _ := bech32.EncodeFromBase256("addr_vk", common.FromHex(mpcPubkey))
+		pubKey, _ := crypto.NewPubKey(pubStr)
+		b.AppendSignature(tx, pubKey, sig)
+
+		cacheAssetsMap := rawTransaction.TxOuts[args.From]
+		txInputs := rawTransaction.TxIns
+		txIndex := rawTransaction.TxIndex
+		return &SignedTransaction{
+			TxIns:     txInputs,
+			TxHash:    txHash,
+			TxIndex:   txIndex,
+			AssetsMap: cacheAssetsMap,
+			Tx:        tx,
+		}, txHash, nil
 	}
 }
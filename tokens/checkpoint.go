@@ -0,0 +1,296 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// errors of scan checkpoints
+var (
+	ErrCheckpointConflict = errors.New("scan checkpoint is advanced by others")
+	ErrCheckpointBackward = errors.New("scan checkpoint can not go backward")
+)
+
+// BlockScanner scan block (or ledger) for txs which have router swapout logs
+type BlockScanner interface {
+	ScanBlockRouterTxs(height uint64) (txHashes []string, err error)
+}
+
+// RouterTxStreamer stream router txs of validated blocks (or ledgers) ahead of
+// block scanning, the streamed tx is redelivered until it is acknowledged.
+type RouterTxStreamer interface {
+	StreamRouterTxs() <-chan *StreamedRouterTx // nil if streaming is not enabled
+}
+
+// StreamedRouterTx router tx received from stream, Ack it after it is registered
+type StreamedRouterTx struct {
+	TxHash string
+	Height uint64
+	Ack    func()
+}
+
+// ScanCheckpoint last scanned position of scanner on chain.
+// Marker is the chain specific cursor inside the height (eg. ledger marker
+// of paginated queries), empty means the height is scanned completely.
+type ScanCheckpoint struct {
+	ChainID    string `json:"chainID"`
+	Scanner    string `json:"scanner"`
+	Height     uint64 `json:"height"`
+	Marker     string `json:"marker,omitempty"`
+	UpdateTime int64  `json:"updateTime"`
+}
+
+// ScanGap heights range [From, To] which are skipped and wait to be backfilled
+type ScanGap struct {
+	ChainID    string `json:"chainID"`
+	Scanner    string `json:"scanner"`
+	From       uint64 `json:"from"`
+	To         uint64 `json:"to"`
+	Reason     string `json:"reason,omitempty"`
+	CreateTime int64  `json:"createTime"`
+}
+
+// CheckpointStore storage of scan checkpoints and gaps
+type CheckpointStore interface {
+	// LoadCheckpoint returns nil if there is no checkpoint
+	LoadCheckpoint(chainID, scanner string) (*ScanCheckpoint, error)
+	// AdvanceCheckpoint set checkpoint only if the stored height is still prevHeight
+	// (or there is no checkpoint if prevHeight is 0), otherwise returns ErrCheckpointConflict
+	AdvanceCheckpoint(cp *ScanCheckpoint, prevHeight uint64) error
+	AddScanGap(gap *ScanGap) error
+	RemoveScanGap(chainID, scanner string, from uint64) error
+	FindScanGaps(chainID, scanner string) ([]*ScanGap, error)
+}
+
+var (
+	checkpointStore     CheckpointStore = newMemCheckpointStore()
+	checkpointStoreLock sync.RWMutex
+)
+
+// SetCheckpointStore set the shared checkpoint store (default is in memory)
+func SetCheckpointStore(store CheckpointStore) {
+	checkpointStoreLock.Lock()
+	defer checkpointStoreLock.Unlock()
+	checkpointStore = store
+}
+
+// GetCheckpointStore get the shared checkpoint store
+func GetCheckpointStore() CheckpointStore {
+	checkpointStoreLock.RLock()
+	defer checkpointStoreLock.RUnlock()
+	return checkpointStore
+}
+
+// GetScanCheckpoint get scan checkpoint, returns zero height checkpoint if not exist
+func GetScanCheckpoint(chainID, scanner string) (*ScanCheckpoint, error) {
+	cp, err := GetCheckpointStore().LoadCheckpoint(chainID, scanner)
+	if err != nil {
+		return nil, err
+	}
+	if cp == nil {
+		cp = &ScanCheckpoint{ChainID: chainID, Scanner: scanner}
+	}
+	return cp, nil
+}
+
+// AdvanceScanCheckpoint atomically advance checkpoint from prevHeight to height
+func AdvanceScanCheckpoint(chainID, scanner string, prevHeight, height uint64, marker string) error {
+	if height < prevHeight {
+		return fmt.Errorf("%w: from %v to %v", ErrCheckpointBackward, prevHeight, height)
+	}
+	return GetCheckpointStore().AdvanceCheckpoint(&ScanCheckpoint{
+		ChainID:    chainID,
+		Scanner:    scanner,
+		Height:     height,
+		Marker:     marker,
+		UpdateTime: time.Now().Unix(),
+	}, prevHeight)
+}
+
+// RecoverScanCheckpoint get the height to continue scanning from on startup.
+// if the checkpoint lags behind latest more than maxLag (0 means no limit),
+// the lagged range is recorded as gap to backfill and scanning jumps forward.
+// if there is no checkpoint, scanning starts from the latest height.
+func RecoverScanCheckpoint(chainID, scanner string, latest, maxLag uint64) (start uint64, err error) {
+	cp, err := GetScanCheckpoint(chainID, scanner)
+	if err != nil {
+		return 0, err
+	}
+	if cp.Height == 0 {
+		if err = AdvanceScanCheckpoint(chainID, scanner, 0, latest, ""); err != nil {
+			return 0, err
+		}
+		return latest, nil
+	}
+	start = cp.Height
+	if cp.Marker == "" {
+		start++ // the checkpoint height is scanned completely
+	}
+	if maxLag == 0 || latest <= maxLag || start+maxLag >= latest {
+		return start, nil
+	}
+	jumpTo := latest - maxLag
+	err = GetCheckpointStore().AddScanGap(&ScanGap{
+		ChainID:    chainID,
+		Scanner:    scanner,
+		From:       start,
+		To:         jumpTo - 1,
+		Reason:     "checkpoint lagged too much on recovery",
+		CreateTime: time.Now().Unix(),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err = AdvanceScanCheckpoint(chainID, scanner, cp.Height, jumpTo-1, ""); err != nil {
+		return 0, err
+	}
+	return jumpTo, nil
+}
+
+// ScanToHeight scan heights after the checkpoint up to `to` by the scan func,
+// the checkpoint is advanced after each scanned height. a failed height is
+// recorded as gap if recordGap is true and scanning goes on, otherwise stops.
+func ScanToHeight(chainID, scanner string, to uint64, recordGap bool, scan func(height uint64) error) (scanned uint64, err error) {
+	cp, err := GetScanCheckpoint(chainID, scanner)
+	if err != nil {
+		return 0, err
+	}
+	prev := cp.Height
+	for height := prev + 1; height <= to; height++ {
+		if errs := scan(height); errs != nil {
+			if !recordGap {
+				return scanned, errs
+			}
+			err = GetCheckpointStore().AddScanGap(&ScanGap{
+				ChainID:    chainID,
+				Scanner:    scanner,
+				From:       height,
+				To:         height,
+				Reason:     errs.Error(),
+				CreateTime: time.Now().Unix(),
+			})
+			if err != nil {
+				return scanned, err
+			}
+		}
+		if err = AdvanceScanCheckpoint(chainID, scanner, prev, height, ""); err != nil {
+			return scanned, err
+		}
+		prev = height
+		scanned++
+	}
+	return scanned, nil
+}
+
+// BackfillScanGaps scan the heights of recorded gaps by the scan func,
+// a gap is removed once all its heights are scanned, or shrunk to
+// the remaining heights if scanning fails in the middle.
+func BackfillScanGaps(chainID, scanner string, scan func(height uint64) error) (filled int, err error) {
+	store := GetCheckpointStore()
+	gaps, err := store.FindScanGaps(chainID, scanner)
+	if err != nil {
+		return 0, err
+	}
+	for _, gap := range gaps {
+		for height := gap.From; height <= gap.To; height++ {
+			if errs := scan(height); errs != nil {
+				if height > gap.From {
+					remain := *gap
+					remain.From = height
+					remain.Reason = errs.Error()
+					if err = store.AddScanGap(&remain); err != nil {
+						return filled, err
+					}
+					if err = store.RemoveScanGap(chainID, scanner, gap.From); err != nil {
+						return filled, err
+					}
+				}
+				return filled, errs
+			}
+		}
+		if err = store.RemoveScanGap(chainID, scanner, gap.From); err != nil {
+			return filled, err
+		}
+		filled++
+	}
+	return filled, nil
+}
+
+// memCheckpointStore in memory checkpoint store (not persisted)
+type memCheckpointStore struct {
+	lock        sync.Mutex
+	checkpoints map[string]*ScanCheckpoint
+	gaps        map[string]map[uint64]*ScanGap
+}
+
+func newMemCheckpointStore() *memCheckpointStore {
+	return &memCheckpointStore{
+		checkpoints: make(map[string]*ScanCheckpoint),
+		gaps:        make(map[string]map[uint64]*ScanGap),
+	}
+}
+
+func getCheckpointKey(chainID, scanner string) string {
+	return chainID + ":" + scanner
+}
+
+func (s *memCheckpointStore) LoadCheckpoint(chainID, scanner string) (*ScanCheckpoint, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if cp, exist := s.checkpoints[getCheckpointKey(chainID, scanner)]; exist {
+		cpCopy := *cp
+		return &cpCopy, nil
+	}
+	return nil, nil
+}
+
+func (s *memCheckpointStore) AdvanceCheckpoint(cp *ScanCheckpoint, prevHeight uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := getCheckpointKey(cp.ChainID, cp.Scanner)
+	var height uint64
+	if old, exist := s.checkpoints[key]; exist {
+		height = old.Height
+	}
+	if height != prevHeight {
+		return ErrCheckpointConflict
+	}
+	cpCopy := *cp
+	s.checkpoints[key] = &cpCopy
+	return nil
+}
+
+func (s *memCheckpointStore) AddScanGap(gap *ScanGap) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := getCheckpointKey(gap.ChainID, gap.Scanner)
+	if s.gaps[key] == nil {
+		s.gaps[key] = make(map[uint64]*ScanGap)
+	}
+	gapCopy := *gap
+	s.gaps[key][gap.From] = &gapCopy
+	return nil
+}
+
+func (s *memCheckpointStore) RemoveScanGap(chainID, scanner string, from uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.gaps[getCheckpointKey(chainID, scanner)], from)
+	return nil
+}
+
+func (s *memCheckpointStore) FindScanGaps(chainID, scanner string) ([]*ScanGap, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	gaps := s.gaps[getCheckpointKey(chainID, scanner)]
+	result := make([]*ScanGap, 0, len(gaps))
+	for _, gap := range gaps {
+		gapCopy := *gap
+		result = append(result, &gapCopy)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].From < result[j].From })
+	return result, nil
+}
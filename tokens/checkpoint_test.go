@@ -0,0 +1,69 @@
+package tokens
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestScanCheckpoint(t *testing.T) {
+	SetCheckpointStore(newMemCheckpointStore())
+	defer SetCheckpointStore(newMemCheckpointStore())
+
+	const chainID, scanner = "1", "router"
+
+	start, err := RecoverScanCheckpoint(chainID, scanner, 100, 0)
+	if err != nil || start != 100 {
+		t.Fatalf("recover without checkpoint got %v %v, want %v", start, err, 100)
+	}
+
+	failed := map[uint64]bool{103: true}
+	scan := func(height uint64) error {
+		if failed[height] {
+			return errors.New("scan failed")
+		}
+		return nil
+	}
+	scanned, err := ScanToHeight(chainID, scanner, 105, true, scan)
+	if err != nil || scanned != 5 {
+		t.Fatalf("scan to height got %v %v, want %v", scanned, err, 5)
+	}
+	if cp, _ := GetScanCheckpoint(chainID, scanner); cp.Height != 105 {
+		t.Errorf("checkpoint height got %v, want %v", cp.Height, 105)
+	}
+
+	if err = AdvanceScanCheckpoint(chainID, scanner, 100, 106, ""); !errors.Is(err, ErrCheckpointConflict) {
+		t.Errorf("advance from stale height got %v, want %v", err, ErrCheckpointConflict)
+	}
+	if err = AdvanceScanCheckpoint(chainID, scanner, 105, 104, ""); !errors.Is(err, ErrCheckpointBackward) {
+		t.Errorf("advance backward got %v, want %v", err, ErrCheckpointBackward)
+	}
+
+	// lagged checkpoint jumps forward and records the gap
+	start, err = RecoverScanCheckpoint(chainID, scanner, 200, 50)
+	if err != nil || start != 150 {
+		t.Fatalf("recover lagged checkpoint got %v %v, want %v", start, err, 150)
+	}
+	gaps, _ := GetCheckpointStore().FindScanGaps(chainID, scanner)
+	if len(gaps) != 2 || gaps[0].From != 103 || gaps[0].To != 103 || gaps[1].From != 106 || gaps[1].To != 149 {
+		t.Fatalf("scan gaps got %+v", gaps)
+	}
+
+	// backfill stops at failed height and keeps the remaining range
+	failed = map[uint64]bool{120: true}
+	filled, err := BackfillScanGaps(chainID, scanner, scan)
+	if err == nil || filled != 1 {
+		t.Fatalf("backfill got %v %v, want error and %v", filled, err, 1)
+	}
+	gaps, _ = GetCheckpointStore().FindScanGaps(chainID, scanner)
+	if len(gaps) != 1 || gaps[0].From != 120 || gaps[0].To != 149 {
+		t.Fatalf("remaining scan gaps got %+v", gaps)
+	}
+
+	failed = nil
+	if filled, err = BackfillScanGaps(chainID, scanner, scan); err != nil || filled != 1 {
+		t.Fatalf("backfill got %v %v, want %v", filled, err, 1)
+	}
+	if gaps, _ = GetCheckpointStore().FindScanGaps(chainID, scanner); len(gaps) != 0 {
+		t.Errorf("scan gaps after backfill got %+v", gaps)
+	}
+}
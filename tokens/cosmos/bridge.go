@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/router"
@@ -19,6 +20,10 @@ var (
 	_ tokens.IBridge = &Bridge{}
 	// ensure Bridge impl tokens.NonceSetter
 	_ tokens.NonceSetter = &Bridge{}
+	// ensure Bridge impl tokens.SendTxErrorMapper
+	_ tokens.SendTxErrorMapper = &Bridge{}
+	// ensure Bridge impl tokens.ChainParamWatcher
+	_ tokens.ChainParamWatcher = &Bridge{}
 )
 
 // Bridge base bridge
@@ -29,18 +34,34 @@ type Bridge struct {
 
 	grpc.ClientContext
 
+	chainClient *ChainClient
+
 	Prefix string
 	Denom  string
+
+	lightClientRoot string
+	lightBlocks     []*lightBlock // trusted, sorted by height
+	lightClientLock sync.Mutex
+
+	watchedParams     *watchedParams // nil if not watched
+	watchedParamsLock sync.RWMutex
 }
 
 // NewCrossChainBridge new bridge
 func NewCrossChainBridge() *Bridge {
-	clientCtx := NewClientContext()
-	return &Bridge{
+	b := &Bridge{
 		NonceSetterBase: base.NewNonceSetterBase(),
-		TxConfig:        clientCtx.TxConfig,
-		ClientContext:   grpc.NewClientContext(clientCtx),
 	}
+	// use a standalone client until the chain config is set
+	b.setChainClient(NewChainClient(""))
+	return b
+}
+
+func (b *Bridge) setChainClient(chainClient *ChainClient) {
+	clientCtx := chainClient.ClientContext()
+	b.chainClient = chainClient
+	b.TxConfig = clientCtx.TxConfig
+	b.ClientContext = grpc.NewClientContext(clientCtx)
 }
 
 func (b *Bridge) SetPrefixAndDenom(prefix, denom string) {
@@ -48,9 +69,40 @@ func (b *Bridge) SetPrefixAndDenom(prefix, denom string) {
 	b.Denom = denom
 	log.Info("SetPrefixAndDenom finished", "prefix", prefix, "denom", denom)
 
-	config := sdk.GetConfig()
-	config.SetBech32PrefixForAccount(b.Prefix, "")
-	config.Seal()
+	setGlobalBech32Prefix(prefix)
+}
+
+var (
+	globalBech32Prefix     string
+	globalBech32PrefixOnce sync.Once
+)
+
+// setGlobalBech32Prefix set and seal the process wide sdk bech32 config once.
+// our address handling always uses the bridge's own prefix,
+// but the sdk msg signers are parsed with this global prefix.
+func setGlobalBech32Prefix(prefix string) {
+	globalBech32PrefixOnce.Do(func() {
+		globalBech32Prefix = prefix
+		config := sdk.GetConfig()
+		config.SetBech32PrefixForAccount(prefix, "")
+		config.Seal()
+	})
+	if prefix != globalBech32Prefix {
+		log.Warn("bech32 prefix differs from the sealed sdk config", "prefix", prefix, "sealed", globalBech32Prefix)
+	}
+}
+
+// SetChainConfig set chain config and bind the chain's shared client
+func (b *Bridge) SetChainConfig(chainCfg *tokens.ChainConfig) {
+	b.CrossChainBridgeBase.SetChainConfig(chainCfg)
+	chainClient := defaultClientManager.GetOrCreateClient(chainCfg.ChainID)
+	if chainClient == b.chainClient {
+		return
+	}
+	b.setChainClient(chainClient)
+	if b.GatewayConfig != nil {
+		b.initGrpcClients()
+	}
 }
 
 // InitAfterConfig init variables (ie. extra members) after loading config
@@ -155,18 +207,19 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 		log.Trace(b.ChainConfig.BlockChain+" GetTransactionStatus fail", "tx", txHash, "err", err)
 		return status, err
 	} else {
-		if res.TxResponse.Code != 0 {
-			return status, tokens.ErrTxWithWrongStatus
-		}
 		if txHeight, err := strconv.ParseUint(res.TxResponse.Height, 10, 64); err != nil {
 			return status, err
 		} else {
 			status.BlockHeight = txHeight
 		}
+		if res.TxResponse.Code != 0 {
+			status.SetFailed(fmt.Sprintf("code %v: %v", res.TxResponse.Code, res.TxResponse.RawLog))
+		}
+		if res.Tx != nil && len(res.Tx.AuthInfo.Fee.Amount) > 0 {
+			status.FeePaid = res.Tx.AuthInfo.Fee.Amount.String()
+		}
 		if blockNumber, err := b.GetLatestBlockNumber(); err == nil {
-			if blockNumber > status.BlockHeight {
-				status.Confirmations = blockNumber - status.BlockHeight
-			}
+			status.SetLatestHeight(blockNumber, b.GetChainConfig().Confirmations)
 		}
 	}
 	return status, nil
@@ -0,0 +1,52 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+func TestGetTransactionStatus(t *testing.T) {
+	var code uint32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == LatestBlock:
+			_ = json.NewEncoder(w).Encode(&GetLatestBlockResponse{Block: &Block{Header: Header{Height: "110"}}})
+		case strings.HasPrefix(r.URL.Path, TxByHash):
+			fee := TxFee{Amount: sdk.NewCoins(sdk.NewInt64Coin("uatom", 2500))}
+			_ = json.NewEncoder(w).Encode(&GetTxResponse{
+				Tx:         &Tx{AuthInfo: TxAuthInfo{Fee: fee}},
+				TxResponse: &TxResponse{Height: "100", TxHash: "TXHASH", Code: code, RawLog: "out of gas"},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: "cosmos-tx-status-test", Confirmations: 10})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{server.URL}})
+
+	status, err := b.GetTransactionStatus("TXHASH")
+	if err != nil {
+		t.Fatalf("get tx status failed: %v", err)
+	}
+	if !status.IsSwapTxOnChainAndSucceed() || !status.IsFinalized() ||
+		status.BlockHeight != 100 || status.Confirmations != 10 || status.FeePaid != "2500uatom" {
+		t.Errorf("get succeed tx status got %+v", status)
+	}
+
+	// failed txs on chain are reported with the failure reason
+	code = sdkerrors.ErrOutOfGas.ABCICode()
+	status, err = b.GetTransactionStatus("TXHASH")
+	if err != nil || !status.IsSwapTxOnChainAndFailed() || !strings.Contains(status.FailReason, "out of gas") {
+		t.Errorf("get failed tx status got (%+v, %v)", status, err)
+	}
+}
@@ -0,0 +1,150 @@
+package cosmos
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/cosmos/grpc"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+)
+
+// some nodes report the tx already in mempool cache as rpc error rather than abci code
+const txInMempoolCacheError = "tx already exists in cache"
+
+var errNoBroadcastEndpoint = errors.New("no broadcast endpoint")
+
+// broadcastResult result of broadcasting tx to one endpoint
+type broadcastResult struct {
+	endpoint string
+	response *BroadcastTxResponse
+	err      error
+}
+
+// isAccepted the tx is accepted by the endpoint, or is already in its mempool cache
+func (r *broadcastResult) isAccepted() bool {
+	if r.err != nil {
+		return false
+	}
+	code := r.response.TxResponse.Code
+	return code == 0 || code == sdkerrors.ErrTxInMempoolCache.ABCICode()
+}
+
+// BroadcastTx broadcast tx to all the grpc and rest endpoints concurrently,
+// and return the first accepted response. as the tx propagates among the nodes,
+// 'tx already in mempool cache' responses of the slower endpoints are accepted too.
+// if no endpoint accepts the tx, the first rejected response (with non zero code)
+// is returned, or the last error if no endpoint responds.
+func (b *Bridge) BroadcastTx(req *BroadcastTxRequest) (string, error) {
+	txBytes, err := base64.StdEncoding.DecodeString(req.TxBytes)
+	if err != nil {
+		return "", wrapRPCQueryError(err, "BroadcastTx")
+	}
+	reqData, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	txHash := fmt.Sprintf("%X", sha256.Sum256(txBytes))
+
+	grpcClients := b.chainClient.GetGRPCClients()
+	urls := b.GatewayConfig.AllGatewayURLs
+	count := len(grpcClients) + len(urls)
+	if count == 0 {
+		return "", wrapRPCQueryError(errNoBroadcastEndpoint, "BroadcastTx")
+	}
+	// buffered, so that the slower endpoints never block after returning
+	results := make(chan *broadcastResult, count)
+	for i, rpcClient := range grpcClients {
+		go func(endpoint string, rpcClient rpcclient.Client) {
+			results <- b.grpcBroadcastTx(endpoint, rpcClient, txBytes, txHash)
+		}(fmt.Sprintf("grpc#%d", i), rpcClient)
+	}
+	for _, url := range urls {
+		go func(url string) {
+			results <- restBroadcastTx(url, reqData, txHash)
+		}(url)
+	}
+
+	var rejected *broadcastResult
+	for i := 0; i < count; i++ {
+		res := <-results
+		switch {
+		case res.isAccepted():
+			log.Info("broadcast tx accepted", "chainID", b.ChainConfig.ChainID, "txHash", res.response.TxResponse.TxHash, "endpoint", res.endpoint, "code", res.response.TxResponse.Code)
+			data, errf := json.Marshal(res.response)
+			return string(data), errf
+		case res.err != nil:
+			err = res.err
+			log.Warn("broadcast tx failed", "chainID", b.ChainConfig.ChainID, "txHash", txHash, "endpoint", res.endpoint, "err", res.err)
+		case rejected == nil:
+			rejected = res
+		}
+	}
+	if rejected != nil {
+		log.Warn("broadcast tx rejected", "chainID", b.ChainConfig.ChainID, "txHash", txHash, "endpoint", rejected.endpoint, "code", rejected.response.TxResponse.Code, "log", rejected.response.TxResponse.RawLog)
+		data, errf := json.Marshal(rejected.response)
+		return string(data), errf
+	}
+	return "", wrapRPCQueryError(err, "BroadcastTx")
+}
+
+func (b *Bridge) grpcBroadcastTx(endpoint string, rpcClient rpcclient.Client, txBytes []byte, txHash string) *broadcastResult {
+	clientCtx := b.ClientContext.
+		WithClient(rpcClient).
+		WithBroadcastMode(flags.BroadcastSync)
+	res, err := grpc.BroadcastRawTx(ctx, clientCtx, txBytes)
+	if err != nil {
+		return newBroadcastErrorResult(endpoint, txHash, err)
+	}
+	return &broadcastResult{
+		endpoint: endpoint,
+		response: &BroadcastTxResponse{
+			TxResponse: &TxResponse{
+				Height: fmt.Sprintf("%d", res.Height),
+				TxHash: res.TxHash,
+				Code:   res.Code,
+				RawLog: res.RawLog,
+				Logs:   res.Logs,
+			},
+		},
+	}
+}
+
+func restBroadcastTx(url string, reqData []byte, txHash string) *broadcastResult {
+	res, err := client.RPCJsonPostWithTimeout(joinURLPath(url, BroadTx), string(reqData), 120)
+	if err != nil {
+		return newBroadcastErrorResult(url, txHash, err)
+	}
+	var response *BroadcastTxResponse
+	if err = json.Unmarshal([]byte(res), &response); err != nil {
+		return &broadcastResult{endpoint: url, err: err}
+	}
+	if response == nil || response.TxResponse == nil {
+		return &broadcastResult{endpoint: url, err: fmt.Errorf("broadcast tx without tx response: %v", res)}
+	}
+	return &broadcastResult{endpoint: url, response: response}
+}
+
+// newBroadcastErrorResult treat 'tx already in mempool cache' rpc error as accepted
+func newBroadcastErrorResult(endpoint, txHash string, err error) *broadcastResult {
+	if !strings.Contains(err.Error(), txInMempoolCacheError) {
+		return &broadcastResult{endpoint: endpoint, err: err}
+	}
+	return &broadcastResult{
+		endpoint: endpoint,
+		response: &BroadcastTxResponse{
+			TxResponse: &TxResponse{
+				TxHash: txHash,
+				Code:   sdkerrors.ErrTxInMempoolCache.ABCICode(),
+				RawLog: err.Error(),
+			},
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// newTestBroadcastServer returns server of broadcast endpoint which responds
+// the tx response with the code, or http error if code is negative
+func newTestBroadcastServer(code int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != BroadTx || code < 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&BroadcastTxResponse{
+			TxResponse: &TxResponse{TxHash: "TXHASH", Code: uint32(code), RawLog: "test"},
+		})
+	}))
+}
+
+func newTestBroadcastBridge(codes ...int) (*Bridge, func()) {
+	urls := make([]string, 0, len(codes))
+	servers := make([]*httptest.Server, 0, len(codes))
+	for _, code := range codes {
+		server := newTestBroadcastServer(code)
+		servers = append(servers, server)
+		urls = append(urls, server.URL)
+	}
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: "cosmos-broadcast-test"})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: urls})
+	return b, func() {
+		for _, server := range servers {
+			server.Close()
+		}
+	}
+}
+
+func TestBroadcastTx(t *testing.T) {
+	req := &BroadcastTxRequest{TxBytes: base64.StdEncoding.EncodeToString([]byte("tx")), Mode: "BROADCAST_MODE_SYNC"}
+	inMempoolCode := int(sdkerrors.ErrTxInMempoolCache.ABCICode())
+
+	tests := []struct {
+		codes    []int
+		wantCode uint32
+	}{
+		{[]int{5, -1, 0}, 0},
+		{[]int{-1, inMempoolCode}, uint32(inMempoolCode)},
+		{[]int{-1, 5, -1}, 5},
+	}
+	for i, test := range tests {
+		b, closeServers := newTestBroadcastBridge(test.codes...)
+		res, err := b.BroadcastTx(req)
+		closeServers()
+		if err != nil {
+			t.Errorf("test %v: broadcast tx failed: %v", i, err)
+			continue
+		}
+		var response *BroadcastTxResponse
+		if err = json.Unmarshal([]byte(res), &response); err != nil || response.TxResponse == nil {
+			t.Errorf("test %v: broadcast tx got wrong response %v", i, res)
+			continue
+		}
+		if response.TxResponse.Code != test.wantCode {
+			t.Errorf("test %v: broadcast tx got code %v, want %v", i, response.TxResponse.Code, test.wantCode)
+		}
+	}
+}
+
+func TestBroadcastTxError(t *testing.T) {
+	req := &BroadcastTxRequest{TxBytes: base64.StdEncoding.EncodeToString([]byte("tx"))}
+
+	b, closeServers := newTestBroadcastBridge(-1, -1)
+	defer closeServers()
+	if _, err := b.BroadcastTx(req); err == nil {
+		t.Errorf("broadcast tx to failed endpoints, want error")
+	}
+
+	if _, err := b.BroadcastTx(&BroadcastTxRequest{TxBytes: "not base64"}); err == nil {
+		t.Errorf("broadcast tx with wrong tx bytes, want error")
+	}
+
+	noEndpoint := NewCrossChainBridge()
+	noEndpoint.SetChainConfig(&tokens.ChainConfig{ChainID: "cosmos-broadcast-test"})
+	noEndpoint.SetGatewayConfig(&tokens.GatewayConfig{})
+	if _, err := noEndpoint.BroadcastTx(req); err == nil || !strings.Contains(err.Error(), errNoBroadcastEndpoint.Error()) {
+		t.Errorf("broadcast tx without endpoint, got error %v, want %v", err, errNoBroadcastEndpoint)
+	}
+}
+
+func TestNewBroadcastErrorResult(t *testing.T) {
+	res := newBroadcastErrorResult("grpc#0", "TXHASH", errors.New("rpc error: "+txInMempoolCacheError))
+	if !res.isAccepted() || res.response.TxResponse.TxHash != "TXHASH" {
+		t.Errorf("broadcast error of tx in mempool cache is not accepted")
+	}
+
+	res = newBroadcastErrorResult("grpc#0", "TXHASH", errors.New("connection refused"))
+	if res.isAccepted() || !strings.Contains(res.err.Error(), "connection refused") {
+		t.Errorf("broadcast error of connection is accepted")
+	}
+}
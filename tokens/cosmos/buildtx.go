@@ -6,7 +6,6 @@ import (
 	"math/big"
 	"regexp"
 	"strconv"
-	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -17,10 +16,8 @@ import (
 )
 
 var (
-	retryRPCCount           = 3
-	retryRPCInterval        = 1 * time.Second
-	DefaultGasLimit  uint64 = 150000
-	DefaultFee              = "500"
+	DefaultGasLimit uint64 = 150000
+	DefaultFee             = "500"
 
 	cachedAccountNumberMap = make(map[string]uint64)
 
@@ -67,11 +64,22 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		return nil, tokens.ErrMissTokenConfig
 	}
 
+	if err = b.checkWatchedParams(multichainToken); err != nil {
+		log.Warn("build tx is paused by chain params", "chainID", b.ChainConfig.ChainID, "tokenID", erc20SwapInfo.TokenID, "err", err)
+		return nil, err
+	}
+
+	if b.getMintBurnKind(multichainToken) != "" {
+		if err = b.checkMinterRole(multichainToken, args.From); err != nil {
+			return nil, err
+		}
+	}
+
 	if receiver, amount, err := b.getReceiverAndAmount(args, multichainToken); err != nil {
 		return nil, err
 	} else {
 		args.SwapValue = amount // SwapValue
-		if extra, err := b.initExtra(args); err != nil {
+		if extra, err := b.initExtra(args, multichainToken, amount); err != nil {
 			return nil, err
 		} else {
 			memo := args.GetUniqueSwapIdentifier()
@@ -102,7 +110,7 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 	}
 }
 
-func (b *Bridge) initExtra(args *tokens.BuildTxArgs) (extra *tokens.AllExtras, err error) {
+func (b *Bridge) initExtra(args *tokens.BuildTxArgs, denom string, amount *big.Int) (extra *tokens.AllExtras, err error) {
 	extra = args.Extra
 	if extra == nil {
 		extra = &tokens.AllExtras{}
@@ -117,7 +125,14 @@ func (b *Bridge) initExtra(args *tokens.BuildTxArgs) (extra *tokens.AllExtras, e
 		extra.Gas = &DefaultGasLimit
 	}
 	if extra.Fee == nil {
-		fee := b.getDefaultFee()
+		sendAmount := new(big.Int).Set(amount)
+		if extra.BridgeFee != nil && extra.BridgeFee.Sign() > 0 {
+			sendAmount.Add(sendAmount, extra.BridgeFee)
+		}
+		fee, err := b.selectFee(args.From, denom, sendAmount)
+		if err != nil {
+			return nil, err
+		}
 		replaceNum := args.GetReplaceNum()
 		if replaceNum > 0 {
 			serverCfg := params.GetRouterServerConfig()
@@ -144,6 +159,12 @@ func (b *Bridge) initExtra(args *tokens.BuildTxArgs) (extra *tokens.AllExtras, e
 		} else {
 			extra.Fee = &fee
 		}
+		b.adjustFeeToMinGasPrice(extra)
+	}
+	if b.getMintBurnKind(denom) != "" {
+		if err = b.initBurnAmount(args, denom); err != nil {
+			return nil, err
+		}
 	}
 	return extra, nil
 }
@@ -162,6 +183,39 @@ func (b *Bridge) getDefaultFee() string {
 	return fee
 }
 
+// selectFee select the first fee in the configed preference list
+// which the sender's balance can afford (together with the sending amount).
+// use the default fee if no preference list is configed.
+func (b *Bridge) selectFee(from, sendDenom string, sendAmount *big.Int) (string, error) {
+	serverCfg := params.GetRouterServerConfig()
+	if serverCfg == nil || len(serverCfg.FeeDenomPreference[b.ChainConfig.ChainID]) == 0 {
+		return b.getDefaultFee(), nil
+	}
+	for _, pref := range serverCfg.FeeDenomPreference[b.ChainConfig.ChainID] {
+		coinsFee, err := ParseCoinsFee(pref)
+		if err != nil || len(coinsFee) != 1 {
+			log.Warn("wrong fee in fee denom preference", "chainID", b.ChainConfig.ChainID, "fee", pref, "err", err)
+			continue
+		}
+		coinFee := coinsFee[0]
+		needAmount := coinFee.Amount.BigInt()
+		if coinFee.Denom == sendDenom {
+			needAmount.Add(needAmount, sendAmount)
+		}
+		balance, err := b.GetDenomBalance(from, coinFee.Denom)
+		if err != nil {
+			log.Warn("get fee denom balance failed", "chainID", b.ChainConfig.ChainID, "account", from, "denom", coinFee.Denom, "err", err)
+			continue
+		}
+		if balance.BigInt().Cmp(needAmount) >= 0 {
+			log.Info("select fee denom", "chainID", b.ChainConfig.ChainID, "account", from, "fee", coinFee.String(), "balance", balance)
+			return coinFee.String(), nil
+		}
+		log.Info("fee denom balance not enough", "chainID", b.ChainConfig.ChainID, "account", from, "fee", coinFee.String(), "balance", balance, "needAmount", needAmount)
+	}
+	return "", tokens.ErrBalanceNotEnough
+}
+
 func is_numeric(word string) bool {
 	return numberPattern.MatchString(word)
 }
@@ -186,7 +240,7 @@ func (b *Bridge) GetPoolNonce(address, _height string) (uint64, error) {
 func (b *Bridge) GetSeq(args *tokens.BuildTxArgs) (nonceptr *uint64, err error) {
 	var nonce uint64
 
-	if params.IsParallelSwapEnabled() {
+	if params.IsParallelSwapEnabledOnChain(b.ChainConfig.ChainID) {
 		nonce, err = b.AllocateNonce(args)
 		return &nonce, err
 	}
@@ -196,13 +250,10 @@ func (b *Bridge) GetSeq(args *tokens.BuildTxArgs) (nonceptr *uint64, err error)
 		return &nonce, nil
 	}
 
-	for i := 0; i < retryRPCCount; i++ {
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
 		nonce, err = b.GetPoolNonce(args.From, "pending")
-		if err == nil {
-			break
-		}
-		time.Sleep(retryRPCInterval)
-	}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -250,7 +301,7 @@ func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken
 	if toTokenCfg == nil {
 		return receiver, amount, tokens.ErrMissTokenConfig
 	}
-	amount = tokens.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo)
+	amount = args.ApplyConversionRate(args.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo))
 	totalAmount := tokens.ConvertTokenValue(args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals)
 	args.Extra.BridgeFee = new(big.Int).Sub(totalAmount, amount)
 	return receiver, amount, err
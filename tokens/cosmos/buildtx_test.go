@@ -0,0 +1,70 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const testIBCDenom = "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"
+
+func TestSelectFee(t *testing.T) {
+	var balances sdk.Coins
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, Balances) {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&QueryAllBalancesResponse{Balances: balances})
+	}))
+	defer server.Close()
+
+	chainID := "cosmos-fee-denom-test"
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: chainID})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{server.URL}})
+	b.SetPrefixAndDenom("cosmos", "uatom")
+
+	cfg := params.GetRouterConfig()
+	oldServer := cfg.Server
+	defer func() { cfg.Server = oldServer }()
+
+	cfg.Server = &params.RouterServerConfig{}
+	if fee, err := b.selectFee("mpc", "uatom", big.NewInt(1000)); err != nil || fee != b.getDefaultFee() {
+		t.Errorf("select fee without preference got (%v, %v), want default fee %v", fee, err, b.getDefaultFee())
+	}
+
+	cfg.Server = &params.RouterServerConfig{
+		FeeDenomPreference: map[string][]string{
+			chainID: {"6250uatom", "5000" + testIBCDenom},
+		},
+	}
+	tests := []struct {
+		atom    int64
+		ibc     int64
+		fee     string
+		wantErr error
+	}{
+		{7250, 0, "6250uatom", nil},
+		{7249, 5000, "5000" + testIBCDenom, nil},
+		{7249, 4999, "", tokens.ErrBalanceNotEnough},
+	}
+	for i, test := range tests {
+		balances = sdk.NewCoins(
+			sdk.NewInt64Coin("uatom", test.atom),
+			sdk.NewInt64Coin(testIBCDenom, test.ibc),
+		)
+		fee, err := b.selectFee("mpc", "uatom", big.NewInt(1000))
+		if !errors.Is(err, test.wantErr) || fee != test.fee {
+			t.Errorf("test %v: select fee got (%v, %v), want (%v, %v)", i, fee, err, test.fee, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package cosmos
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	cosmosClient "github.com/cosmos/cosmos-sdk/client"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+)
+
+// InterfaceRegistrar registers interfaces and implementations (eg. Msg types) into a codec registry
+type InterfaceRegistrar func(registry codecTypes.InterfaceRegistry)
+
+var (
+	// chain name (in `ChainsList`) -> extra interface registrars of this chain
+	chainRegistrars     = make(map[string][]InterfaceRegistrar)
+	chainRegistrarsLock sync.RWMutex
+
+	defaultClientManager = NewClientManager()
+)
+
+// RegisterChainInterfaces register extra interfaces of the specified cosmos sub chain.
+// chains register different Msg types, the registrars are only applied to the
+// codec registry of this chain, so they never collide with other chains.
+// should be called before the bridge's chain config is set.
+func RegisterChainInterfaces(chainName string, registrars ...InterfaceRegistrar) {
+	chainName = strings.ToUpper(chainName)
+	chainRegistrarsLock.Lock()
+	defer chainRegistrarsLock.Unlock()
+	chainRegistrars[chainName] = append(chainRegistrars[chainName], registrars...)
+}
+
+func getChainRegistrars(chainName string) []InterfaceRegistrar {
+	chainRegistrarsLock.RLock()
+	defer chainRegistrarsLock.RUnlock()
+	return chainRegistrars[strings.ToUpper(chainName)]
+}
+
+// ChainClient the client of a cosmos chain,
+// holds the chain's own codec registry and grpc clients
+type ChainClient struct {
+	chainID   string
+	clientCtx cosmosClient.Context
+
+	rpcClients    []rpcclient.Client
+	rpcClientsMap map[string]rpcclient.Client
+	rpcLock       sync.RWMutex
+}
+
+// NewChainClient new chain client
+func NewChainClient(chainID string, registrars ...InterfaceRegistrar) *ChainClient {
+	return &ChainClient{
+		chainID:       chainID,
+		clientCtx:     NewClientContext(registrars...),
+		rpcClientsMap: make(map[string]rpcclient.Client),
+	}
+}
+
+// ChainID chain id
+func (c *ChainClient) ChainID() string {
+	return c.chainID
+}
+
+// ClientContext sdk client context (with this chain's codec registry)
+func (c *ChainClient) ClientContext() cosmosClient.Context {
+	return c.clientCtx
+}
+
+// SetGRPCClients reset grpc clients to the specified urls
+func (c *ChainClient) SetGRPCClients(urls []string) {
+	clients := make([]rpcclient.Client, 0, len(urls))
+	clientsMap := make(map[string]rpcclient.Client, len(urls))
+	for _, url := range urls {
+		rpcClient, err := cosmosClient.NewClientFromNode(url)
+		if err != nil {
+			log.Warn("new grpc client failed", "chainID", c.chainID, "url", url, "err", err)
+			continue
+		}
+		clients = append(clients, rpcClient)
+		clientsMap[url] = rpcClient
+	}
+
+	c.rpcLock.Lock()
+	c.rpcClients = clients
+	c.rpcClientsMap = clientsMap
+	c.rpcLock.Unlock()
+
+	if len(clients) > 0 {
+		log.Info("init grpc clients success", "chainID", c.chainID, "count", len(clients))
+	}
+}
+
+// GetGRPCClients get grpc clients
+func (c *ChainClient) GetGRPCClients() []rpcclient.Client {
+	c.rpcLock.RLock()
+	defer c.rpcLock.RUnlock()
+	return c.rpcClients
+}
+
+// GetGRPCClient get grpc client of url
+func (c *ChainClient) GetGRPCClient(url string) (rpcclient.Client, bool) {
+	c.rpcLock.RLock()
+	defer c.rpcLock.RUnlock()
+	rpcClient, exist := c.rpcClientsMap[url]
+	return rpcClient, exist
+}
+
+// ClientManager manages the clients of multiple cosmos chains,
+// so that one router process can serve many cosmos chains.
+type ClientManager struct {
+	clients map[string]*ChainClient
+	lock    sync.Mutex
+}
+
+// NewClientManager new client manager
+func NewClientManager() *ClientManager {
+	return &ClientManager{
+		clients: make(map[string]*ChainClient),
+	}
+}
+
+// GetOrCreateClient get client of chainID, create it if not exist.
+// the codec registry is built with the registrars of the chain's name.
+func (m *ClientManager) GetOrCreateClient(chainID string) *ChainClient {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if c, exist := m.clients[chainID]; exist {
+		return c
+	}
+	chainName := GetChainNameByStubChainID(chainID)
+	c := NewChainClient(chainID, getChainRegistrars(chainName)...)
+	m.clients[chainID] = c
+	log.Info("create cosmos chain client", "chainID", chainID, "chainName", chainName)
+	return c
+}
+
+// GetClient get client of chainID
+func (m *ClientManager) GetClient(chainID string) *ChainClient {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.clients[chainID]
+}
+
+// GetChainClient get client of chainID from the default client manager
+func GetChainClient(chainID string) *ChainClient {
+	return defaultClientManager.GetClient(chainID)
+}
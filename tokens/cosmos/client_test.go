@@ -0,0 +1,68 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+)
+
+const msgExecTypeURL = "/cosmos.authz.v1beta1.MsgExec"
+
+func TestGetChainNameByStubChainID(t *testing.T) {
+	for _, network := range []string{mainnetNetWork, testnetNetWork, devnetNetWork} {
+		chainID := GetStubChainID("SEI", network).String()
+		if name := GetChainNameByStubChainID(chainID); name != "SEI" {
+			t.Errorf("chain name of %v stub chain id got %q, want SEI", network, name)
+		}
+	}
+	if name := GetChainNameByStubChainID("1"); name != "" {
+		t.Errorf("chain name of unknown chain id got %q, want empty", name)
+	}
+}
+
+func TestClientManager(t *testing.T) {
+	RegisterChainInterfaces("sei", authz.RegisterInterfaces)
+	defer func() {
+		chainRegistrarsLock.Lock()
+		delete(chainRegistrars, "SEI")
+		chainRegistrarsLock.Unlock()
+	}()
+
+	m := NewClientManager()
+	seiChainID := GetStubChainID("SEI", mainnetNetWork).String()
+	osmoChainID := GetStubChainID("OSMOSIS", mainnetNetWork).String()
+	sei := m.GetOrCreateClient(seiChainID)
+	osmo := m.GetOrCreateClient(osmoChainID)
+	if m.GetOrCreateClient(seiChainID) != sei || m.GetClient(seiChainID) != sei || sei == osmo {
+		t.Fatalf("client manager should create one client per chain")
+	}
+	if m.GetClient("1") != nil {
+		t.Errorf("get client of unknown chain should be nil")
+	}
+
+	// chain specific msg types are only registered in the chain's own codec registry
+	if _, err := sei.ClientContext().InterfaceRegistry.Resolve(msgExecTypeURL); err != nil {
+		t.Errorf("resolve registered msg type of chain failed: %v", err)
+	}
+	if _, err := osmo.ClientContext().InterfaceRegistry.Resolve(msgExecTypeURL); err == nil {
+		t.Errorf("resolve msg type registered by other chain should fail")
+	}
+}
+
+func TestBridgesShareChainClient(t *testing.T) {
+	chainID := GetStubChainID("COREUM", devnetNetWork).String()
+	b1, b2, other := NewCrossChainBridge(), NewCrossChainBridge(), NewCrossChainBridge()
+	if b1.chainClient == b2.chainClient {
+		t.Errorf("bridges without chain config should use standalone clients")
+	}
+	b1.SetChainConfig(&tokens.ChainConfig{ChainID: chainID})
+	b2.SetChainConfig(&tokens.ChainConfig{ChainID: chainID})
+	other.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID("COREUM", testnetNetWork).String()})
+	if b1.chainClient != b2.chainClient || b1.chainClient != GetChainClient(chainID) {
+		t.Errorf("bridges of the same chain should share the chain client")
+	}
+	if other.chainClient == b1.chainClient {
+		t.Errorf("bridges of different chains should not share the chain client")
+	}
+}
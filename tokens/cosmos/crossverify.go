@@ -0,0 +1,23 @@
+package cosmos
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var _ tokens.TxCrossVerifier = &Bridge{}
+
+// CrossVerifyTx impl tokens.TxCrossVerifier
+// the raw log is not compared as it may be non-deterministic.
+func (b *Bridge) CrossVerifyTx(txHash string, quorum int) error {
+	return tokens.CrossVerifyTx(b.ChainConfig.ChainID, txHash, b.GatewayConfig.AllGatewayURLs, quorum, func(url string) (string, error) {
+		var result *GetTxResponse
+		if err := restGetAtHeight(&result, joinURLPath(url, TxByHash+txHash), 0); err != nil {
+			return "", err
+		}
+		if result == nil || result.TxResponse == nil {
+			return "", tokens.ErrTxNotFound
+		}
+		res := result.TxResponse
+		return tokens.TxFingerprint([]interface{}{res.Height, res.TxHash, res.Code, res.Logs, result.Tx})
+	})
+}
@@ -0,0 +1,112 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	// ensure Bridge impl tokens.GasReestimator
+	_ tokens.GasReestimator = &Bridge{}
+)
+
+// ReestimateGas impl tokens.GasReestimator
+// the gas limit is the simulated gas used multiplied by the adjustment (not less than
+// the current one), the fee is raised by the adjustment or the ratio of gas limits,
+// whichever is larger, so that neither the gas limit nor the gas price is decreased.
+func (b *Bridge) ReestimateGas(args *tokens.BuildTxArgs, adjustment float64) error {
+	extra := args.Extra
+	if extra == nil || extra.Sequence == nil || extra.Gas == nil || extra.Fee == nil {
+		return errors.New("re-estimate gas of tx which is not built")
+	}
+	rawTx, err := b.BuildRawTransaction(args)
+	if err != nil {
+		return err
+	}
+	txBytes, err := b.TxConfig.TxEncoder()(rawTx.(*BuildRawTx).TxBuilder.GetTx())
+	if err != nil {
+		return err
+	}
+	gasUsed, err := b.simulateGasUsed(txBytes)
+	if err != nil {
+		return err
+	}
+
+	oldGas := *extra.Gas
+	newGas, newFee, err := adjustGasAndFee(gasUsed, oldGas, *extra.Fee, adjustment)
+	if err != nil {
+		return err
+	}
+
+	log.Info("re-estimate gas success", "chainID", b.ChainConfig.ChainID, "swapID", args.SwapID,
+		"sequence", *extra.Sequence, "gasUsed", gasUsed, "adjustment", adjustment,
+		"oldGas", oldGas, "newGas", newGas, "oldFee", *extra.Fee, "newFee", newFee)
+	extra.Gas = &newGas
+	extra.Fee = &newFee
+	return nil
+}
+
+// adjustGasAndFee get the adjusted gas limit and fee
+func adjustGasAndFee(gasUsed, oldGas uint64, oldFee string, adjustment float64) (newGas uint64, newFee string, err error) {
+	newGas = uint64(math.Ceil(float64(gasUsed) * adjustment))
+	if newGas < oldGas {
+		newGas = oldGas
+	}
+	ratio := adjustment
+	if gasRatio := float64(newGas) / float64(oldGas); gasRatio > ratio {
+		ratio = gasRatio
+	}
+	coinsFee, err := ParseCoinsFee(oldFee)
+	if err != nil {
+		return 0, "", err
+	}
+	permille := big.NewInt(int64(math.Ceil(ratio * 1000)))
+	for i, coin := range coinsFee {
+		amount := new(big.Int).Mul(coin.Amount.BigInt(), permille)
+		amount.Add(amount, big.NewInt(999))
+		amount.Div(amount, big.NewInt(1000))
+		coinsFee[i].Amount = sdk.NewIntFromBigInt(amount)
+	}
+	return newGas, coinsFee.String(), nil
+}
+
+// simulateGasUsed simulate tx and get the gas used
+func (b *Bridge) simulateGasUsed(txBytes []byte) (uint64, error) {
+	res, err := b.GRPCSimulateTx(&SimulateRequest{TxBytes: string(txBytes)})
+	if err == nil {
+		return res.GasInfo.GasUsed, nil
+	} else if len(b.GatewayConfig.AllGatewayURLs) == 0 {
+		return 0, err
+	}
+	data, err := json.Marshal(&SimulateRequest{TxBytes: base64.StdEncoding.EncodeToString(txBytes)})
+	if err != nil {
+		return 0, err
+	}
+	for _, url := range b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(url, SimulateTx)
+		var resp string
+		resp, err = client.RPCRawPostWithTimeout(restApi, string(data), 120)
+		if err != nil {
+			continue
+		}
+		var result SimulateResponse
+		if err = json.Unmarshal([]byte(resp), &result); err != nil || result.GasInfo == nil {
+			log.Warn("simulate tx with wrong response", "url", restApi, "response", resp, "err", err)
+			continue
+		}
+		var gasUsed uint64
+		if gasUsed, err = strconv.ParseUint(result.GasInfo.GasUsed, 10, 64); err == nil {
+			return gasUsed, nil
+		}
+	}
+	return 0, wrapRPCQueryError(err, "SimulateTx")
+}
@@ -0,0 +1,86 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+func TestAdjustGasAndFee(t *testing.T) {
+	tests := []struct {
+		gasUsed    uint64
+		oldGas     uint64
+		oldFee     string
+		adjustment float64
+		newGas     uint64
+		newFee     string
+	}{
+		// fee is raised by the adjustment
+		{150000, 200000, "2000uatom", 1.5, 225000, "3000uatom"},
+		// or the ratio of gas limits if it is larger
+		{300000, 200000, "2000uatom", 1.5, 450000, "4500uatom"},
+		// gas limit is not decreased
+		{100000, 200000, "2000uatom", 1.5, 200000, "3000uatom"},
+		// fee amounts are rounded up
+		{100000, 200000, "3uatom,1uosmo", 2.5, 250000, "8uatom,3uosmo"},
+	}
+	for i, test := range tests {
+		newGas, newFee, err := adjustGasAndFee(test.gasUsed, test.oldGas, test.oldFee, test.adjustment)
+		if err != nil || newGas != test.newGas || newFee != test.newFee {
+			t.Errorf("test %v: adjust gas and fee got (%v, %v, %v), want (%v, %v)", i, newGas, newFee, err, test.newGas, test.newFee)
+		}
+	}
+	if _, _, err := adjustGasAndFee(150000, 200000, "uatom", 1.5); err == nil {
+		t.Errorf("adjust gas and fee of wrong fee should fail")
+	}
+}
+
+func TestSimulateGasUsed(t *testing.T) {
+	var gasUsed string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != SimulateTx {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		res := &SimulateResponse{}
+		if gasUsed != "" {
+			res.GasInfo = &GasInfo{GasUsed: gasUsed}
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: "osmosis-1"})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{server.URL}})
+
+	gasUsed = "123456"
+	if got, err := b.simulateGasUsed([]byte("tx")); err != nil || got != 123456 {
+		t.Errorf("simulate gas used got (%v, %v), want 123456", got, err)
+	}
+	gasUsed = ""
+	if _, err := b.simulateGasUsed([]byte("tx")); err == nil {
+		t.Errorf("simulate gas used without gas info should fail")
+	}
+}
+
+func TestReestimateGasNotBuilt(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: "osmosis-1"})
+	if err := b.ReestimateGas(&tokens.BuildTxArgs{}, 1.5); err == nil {
+		t.Errorf("re-estimate gas of tx which is not built should fail")
+	}
+}
+
+func TestMapOutOfGasSendTxError(t *testing.T) {
+	b := NewCrossChainBridge()
+	for _, code := range []uint32{sdkerrors.ErrOutOfGas.ABCICode(), sdkerrors.ErrInsufficientFee.ABCICode()} {
+		if class := b.MapSendTxError(&sendTxCodeError{code: code}); class != tokens.SendTxErrOutOfGas {
+			t.Errorf("map send tx error code %v got %v, want %v", code, class, tokens.SendTxErrOutOfGas)
+		}
+	}
+}
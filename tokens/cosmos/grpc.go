@@ -2,44 +2,36 @@ package cosmos
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
+	"strconv"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/tokens/cosmos/grpc"
 	cosmosclient "github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/flags"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
 	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	"github.com/pkg/errors"
-	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	"google.golang.org/grpc/metadata"
 )
 
-var (
-	rpcClients    []rpcclient.Client
-	rpcClientsMap = make(map[string]rpcclient.Client)
+var ctx = context.Background()
 
-	ctx = context.Background()
-)
+// heightContext pins the abci queries to the specified block height (0 means latest)
+func heightContext(height uint64) context.Context {
+	if height == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatUint(height, 10))
+}
 
 func (b *Bridge) initGrpcClients() {
-	for _, url := range b.GatewayConfig.GRPCAPIAddress {
-		rpcClient, err := cosmosclient.NewClientFromNode(url)
-		if err != nil {
-			log.Warn("new grpc client failed", "url", url, "err", err)
-			continue
-		}
-		rpcClients = append(rpcClients, rpcClient)
-		rpcClientsMap[url] = rpcClient
-	}
-	if len(rpcClients) > 0 {
-		log.Info("init grpc clients success", "count", len(rpcClients))
-	}
+	b.chainClient.SetGRPCClients(b.GatewayConfig.GRPCAPIAddress)
 }
 
 func (b *Bridge) GRPCGetLatestBlockNumber() (res uint64, err error) {
-	for _, rpcClient := range rpcClients {
+	for _, rpcClient := range b.chainClient.GetGRPCClients() {
 		clientCtx := b.ClientContext.WithClient(rpcClient)
 		res, err = grpc.GetLatestBlockNumber(ctx, clientCtx)
 		if err == nil {
@@ -53,7 +45,7 @@ func (b *Bridge) GRPCGetLatestBlockNumber() (res uint64, err error) {
 }
 
 func (b *Bridge) GRPCGetLatestBlockNumberOf(url string) (res uint64, err error) {
-	rpcClient, exist := rpcClientsMap[url]
+	rpcClient, exist := b.chainClient.GetGRPCClient(url)
 	if !exist {
 		rpcClient, err = cosmosclient.NewClientFromNode(url)
 		if err != nil {
@@ -73,7 +65,7 @@ func (b *Bridge) GRPCGetLatestBlockNumberOf(url string) (res uint64, err error)
 }
 
 func (b *Bridge) GRPCGetChainID() (res string, err error) {
-	for _, rpcClient := range rpcClients {
+	for _, rpcClient := range b.chainClient.GetGRPCClients() {
 		clientCtx := b.ClientContext.WithClient(rpcClient)
 		res, err = grpc.GetChainID(ctx, clientCtx)
 		if err == nil {
@@ -86,11 +78,11 @@ func (b *Bridge) GRPCGetChainID() (res string, err error) {
 	return "", wrapRPCQueryError(err, "GRPCGetChainID")
 }
 
-func (b *Bridge) GRPCGetTransactionByHash(txHash string) (res *GetTxResponse, err error) {
+func (b *Bridge) GRPCGetTransactionByHash(txHash string, height uint64) (res *GetTxResponse, err error) {
 	var txres *sdk.TxResponse
-	for _, rpcClient := range rpcClients {
+	for _, rpcClient := range b.chainClient.GetGRPCClients() {
 		clientCtx := b.ClientContext.WithClient(rpcClient)
-		txres, err = grpc.GetTransactionByHash(ctx, clientCtx, txHash)
+		txres, err = grpc.GetTransactionByHash(heightContext(height), clientCtx, txHash)
 		if err == nil {
 			var tx *sdktx.Tx
 			if err := clientCtx.InterfaceRegistry().UnpackAny(txres.Tx, &tx); err != nil {
@@ -101,35 +93,46 @@ func (b *Bridge) GRPCGetTransactionByHash(txHash string) (res *GetTxResponse, er
 				return nil, fmt.Errorf("unpack tx error")
 			}
 			var txMemo string
+			var txMsgs []TxMessage
 			if tx.Body != nil {
 				txMemo = tx.Body.Memo
+				for _, msg := range tx.Body.Messages {
+					txMsgs = append(txMsgs, TxMessage{Type: msg.TypeUrl})
+				}
+			}
+			var txFee TxFee
+			if tx.AuthInfo != nil && tx.AuthInfo.Fee != nil {
+				txFee.Amount = tx.AuthInfo.Fee.Amount
 			}
 			return &GetTxResponse{
 				Tx: &Tx{
 					Body: TxBody{
-						Memo: txMemo,
+						Memo:     txMemo,
+						Messages: txMsgs,
 					},
+					AuthInfo: TxAuthInfo{Fee: txFee},
 				},
 				TxResponse: &TxResponse{
 					Height: fmt.Sprintf("%v", txres.Height),
 					TxHash: txres.TxHash,
 					Code:   txres.Code,
+					RawLog: txres.RawLog,
 					Logs:   txres.Logs,
 				},
 			}, nil
 		}
 	}
 	if err != nil {
-		log.Warn("GRPCGetTransactionByHash failed", "txHash", txHash, "err", err)
+		log.Warn("GRPCGetTransactionByHash failed", "txHash", txHash, "height", height, "err", err)
 	}
 	return nil, wrapRPCQueryError(err, "GRPCGetTransactionByHash", txHash)
 }
 
-func (b *Bridge) GRPCGetBaseAccount(address string) (res *QueryAccountResponse, err error) {
+func (b *Bridge) GRPCGetBaseAccount(address string, height uint64) (res *QueryAccountResponse, err error) {
 	var ret authtypes.AccountI
-	for _, rpcClient := range rpcClients {
+	for _, rpcClient := range b.chainClient.GetGRPCClients() {
 		clientCtx := b.ClientContext.WithClient(rpcClient)
-		ret, err = grpc.GetAccountInfo(ctx, clientCtx, address)
+		ret, err = grpc.GetAccountInfo(heightContext(height), clientCtx, address)
 		if err == nil {
 			return &QueryAccountResponse{
 				Account: &BaseAccount{
@@ -141,27 +144,27 @@ func (b *Bridge) GRPCGetBaseAccount(address string) (res *QueryAccountResponse,
 		}
 	}
 	if err != nil {
-		log.Warn("GRPCGetBaseAccount failed", "address", address, "err", err)
+		log.Warn("GRPCGetBaseAccount failed", "address", address, "height", height, "err", err)
 	}
 	return nil, wrapRPCQueryError(err, "GRPCGetBaseAccount", address)
 }
 
-func (b *Bridge) GRPCGetDenomBalance(address, denom string) (res sdk.Int, err error) {
-	for _, rpcClient := range rpcClients {
+func (b *Bridge) GRPCGetDenomBalance(address, denom string, height uint64) (res sdk.Int, err error) {
+	for _, rpcClient := range b.chainClient.GetGRPCClients() {
 		clientCtx := b.ClientContext.WithClient(rpcClient)
-		res, err = grpc.GetDenomBalance(ctx, clientCtx, address, denom)
+		res, err = grpc.GetDenomBalance(heightContext(height), clientCtx, address, denom)
 		if err == nil {
 			return res, nil
 		}
 	}
 	if err != nil {
-		log.Warn("GRPCGetDenomBalance failed", "address", address, "denom", denom, "err", err)
+		log.Warn("GRPCGetDenomBalance failed", "address", address, "denom", denom, "height", height, "err", err)
 	}
 	return sdk.ZeroInt(), wrapRPCQueryError(err, "GRPCGetDenomBalance", address, denom)
 }
 
 func (b *Bridge) GRPCSimulateTx(simulateReq *SimulateRequest) (res *sdktx.SimulateResponse, err error) {
-	for _, rpcClient := range rpcClients {
+	for _, rpcClient := range b.chainClient.GetGRPCClients() {
 		clientCtx := b.ClientContext.WithClient(rpcClient)
 		res, err = grpc.SimulateTx(ctx, clientCtx, []byte(simulateReq.TxBytes))
 		if err == nil {
@@ -173,23 +176,3 @@ func (b *Bridge) GRPCSimulateTx(simulateReq *SimulateRequest) (res *sdktx.Simula
 	}
 	return nil, wrapRPCQueryError(err, "GRPCSimulateTx")
 }
-
-func (b *Bridge) GRPCBroadcastTx(req *BroadcastTxRequest) (res *sdk.TxResponse, err error) {
-	txBytes, err := base64.StdEncoding.DecodeString(req.TxBytes)
-	if err != nil {
-		return nil, wrapRPCQueryError(err, "GRPCBroadcastTx")
-	}
-	for _, rpcClient := range rpcClients {
-		clientCtx := b.ClientContext.
-			WithClient(rpcClient).
-			WithBroadcastMode(flags.BroadcastSync)
-		res, err = grpc.BroadcastRawTx(ctx, clientCtx, txBytes)
-		if err == nil {
-			return res, nil
-		}
-	}
-	if err != nil {
-		log.Warn("GRPCBroadcastTx failed", "err", err)
-	}
-	return nil, wrapRPCQueryError(err, "GRPCBroadcastTx")
-}
@@ -16,6 +16,7 @@ import (
 	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
 	authTx "github.com/cosmos/cosmos-sdk/x/auth/tx"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 )
 
 var (
@@ -30,13 +31,20 @@ const (
 	devnetNetWork  = "devnet"
 )
 
-func NewClientContext() cosmosClient.Context {
+// NewClientContext new client context with its own codec registry,
+// the registrars register extra chain specific interfaces (eg. Msg types)
+func NewClientContext(registrars ...InterfaceRegistrar) cosmosClient.Context {
 	amino := codec.NewLegacyAmino()
 
 	interfaceRegistry := codecTypes.NewInterfaceRegistry()
 	interfaceRegistry.RegisterImplementations((*cryptoTypes.PubKey)(nil), &secp256k1.PubKey{})
 	interfaceRegistry.RegisterImplementations((*authtypes.AccountI)(nil), &authtypes.BaseAccount{})
 	interfaceRegistry.RegisterImplementations((*sdk.Tx)(nil), &sdktx.Tx{})
+	bankTypes.RegisterInterfaces(interfaceRegistry)
+	for _, register := range registrars {
+		register(interfaceRegistry)
+	}
+	RegisterMintBurnInterfaces(interfaceRegistry)
 
 	protoCodec := codec.NewProtoCodec(interfaceRegistry)
 	txConfig := authTx.NewTxConfig(protoCodec, authTx.DefaultSignModes)
@@ -60,6 +68,18 @@ func SupportsChainID(chainID *big.Int) bool {
 	return supportedChainIDs[chainID.String()]
 }
 
+// GetChainNameByStubChainID get chain name in `ChainsList` by stub chainID
+func GetChainNameByStubChainID(chainID string) string {
+	for _, chainName := range ChainsList {
+		for _, network := range []string{mainnetNetWork, testnetNetWork, devnetNetWork} {
+			if GetStubChainID(chainName, network).String() == chainID {
+				return chainName
+			}
+		}
+	}
+	return ""
+}
+
 // IsSupportedCosmosSubChain is supported
 func IsSupportedCosmosSubChain(chainName string) bool {
 	var match bool
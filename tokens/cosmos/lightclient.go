@@ -0,0 +1,292 @@
+package cosmos
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/light"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+const (
+	maxTrustedLightBlocks  = 100
+	maxLightBisectionDepth = 16
+	validatorsPerPage      = 100
+	msgSendTypeURL         = "/cosmos.bank.v1beta1.MsgSend"
+)
+
+// lightBlock signed header with its validator set
+type lightBlock struct {
+	*tmtypes.SignedHeader
+	vals *tmtypes.ValidatorSet
+}
+
+func lightClientError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %v", tokens.ErrLightClientVerify, fmt.Sprintf(format, args...))
+}
+
+// lightClientCall call tendermint rpc (results are in tendermint json format)
+func lightClientCall(cfg *params.LightClientConfig, result interface{}, method string, args ...interface{}) (err error) {
+	for _, url := range cfg.RPCEndpoints {
+		var raw json.RawMessage
+		err = client.RPCPostWithTimeout(60, &raw, url, method, args...)
+		if err == nil {
+			err = tmjson.Unmarshal(raw, result)
+			if err == nil {
+				return nil
+			}
+		}
+		log.Warn("light client call failed", "url", url, "method", method, "args", args, "err", err)
+	}
+	return wrapRPCQueryError(err, method, args...)
+}
+
+// fetchLightBlock fetch signed header and validator set at height,
+// the validator set is checked to match the header.
+func fetchLightBlock(cfg *params.LightClientConfig, height int64) (*lightBlock, error) {
+	heightStr := strconv.FormatInt(height, 10)
+	var commit ctypes.ResultCommit
+	if err := lightClientCall(cfg, &commit, "commit", heightStr); err != nil {
+		return nil, err
+	}
+	if commit.Header == nil || commit.Commit == nil || commit.Height != height {
+		return nil, lightClientError("wrong commit at height %v", height)
+	}
+
+	var validators []*tmtypes.Validator
+	for page := 1; ; page++ {
+		var res ctypes.ResultValidators
+		err := lightClientCall(cfg, &res, "validators", heightStr, strconv.Itoa(page), strconv.Itoa(validatorsPerPage))
+		if err != nil {
+			return nil, err
+		}
+		validators = append(validators, res.Validators...)
+		if len(res.Validators) == 0 || len(validators) >= res.Total {
+			break
+		}
+	}
+	vals := tmtypes.NewValidatorSet(validators)
+	if !bytes.Equal(vals.Hash(), commit.Header.ValidatorsHash) {
+		return nil, lightClientError("validators hash mismatch at height %v", height)
+	}
+	return &lightBlock{SignedHeader: &commit.SignedHeader, vals: vals}, nil
+}
+
+func getTrustedRootKey(cfg *params.LightClientConfig) string {
+	return fmt.Sprintf("%d:%x", cfg.TrustedHeight, common.FromHex(cfg.TrustedHash))
+}
+
+// getTrustedLightBlock get the highest trusted light block not above height,
+// the trusted blocks are reset to the configed root if the config changes.
+func (b *Bridge) getTrustedLightBlock(cfg *params.LightClientConfig, height int64) (*lightBlock, error) {
+	if rootKey := getTrustedRootKey(cfg); rootKey != b.lightClientRoot {
+		root, err := fetchLightBlock(cfg, cfg.TrustedHeight)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(root.Hash(), common.FromHex(cfg.TrustedHash)) {
+			log.Error("light client trusted hash mismatch", "chainID", b.ChainConfig.ChainID, "height", cfg.TrustedHeight, "want", cfg.TrustedHash, "have", root.Hash())
+			return nil, lightClientError("trusted hash mismatch at height %v", cfg.TrustedHeight)
+		}
+		b.lightClientRoot = rootKey
+		b.lightBlocks = []*lightBlock{root}
+		log.Info("light client init trusted block", "chainID", b.ChainConfig.ChainID, "height", cfg.TrustedHeight, "hash", root.Hash())
+	}
+	i := sort.Search(len(b.lightBlocks), func(i int) bool {
+		return b.lightBlocks[i].Height > height
+	})
+	if i == 0 {
+		return nil, lightClientError("height %v is below trusted height %v", height, b.lightBlocks[0].Height)
+	}
+	return b.lightBlocks[i-1], nil
+}
+
+func (b *Bridge) addTrustedLightBlock(block *lightBlock) {
+	i := sort.Search(len(b.lightBlocks), func(i int) bool {
+		return b.lightBlocks[i].Height >= block.Height
+	})
+	if i < len(b.lightBlocks) && b.lightBlocks[i].Height == block.Height {
+		return
+	}
+	b.lightBlocks = append(b.lightBlocks, nil)
+	copy(b.lightBlocks[i+1:], b.lightBlocks[i:])
+	b.lightBlocks[i] = block
+	if len(b.lightBlocks) > maxTrustedLightBlocks {
+		b.lightBlocks = b.lightBlocks[1:]
+	}
+}
+
+// verifyLightBlock verify light block at height from the nearest trusted one,
+// bisect when the validator set changes too much to skip directly.
+func (b *Bridge) verifyLightBlock(cfg *params.LightClientConfig, height int64) (*lightBlock, error) {
+	b.lightClientLock.Lock()
+	defer b.lightClientLock.Unlock()
+
+	trusted, err := b.getTrustedLightBlock(cfg, height)
+	if err != nil {
+		return nil, err
+	}
+	if trusted.Height == height {
+		return trusted, nil
+	}
+	untrusted, err := fetchLightBlock(cfg, height)
+	if err != nil {
+		return nil, err
+	}
+	if err = b.verifySkipping(cfg, trusted, untrusted, 0); err != nil {
+		return nil, err
+	}
+	return untrusted, nil
+}
+
+func (b *Bridge) verifySkipping(cfg *params.LightClientConfig, trusted, untrusted *lightBlock, depth int) error {
+	err := light.Verify(trusted.SignedHeader, trusted.vals, untrusted.SignedHeader, untrusted.vals,
+		cfg.GetTrustingPeriod(), time.Now(), cfg.GetMaxClockDrift(), light.DefaultTrustLevel)
+	if err == nil {
+		b.addTrustedLightBlock(untrusted)
+		return nil
+	}
+	var expiredErr light.ErrOldHeaderExpired
+	if errors.As(err, &expiredErr) {
+		log.Error("light client trusted header is expired, please reconfig trusted block", "chainID", b.ChainConfig.ChainID, "trustedHeight", trusted.Height, "err", err)
+		return lightClientError("%v", err)
+	}
+	var cantTrustErr light.ErrNewValSetCantBeTrusted
+	if !errors.As(err, &cantTrustErr) || depth >= maxLightBisectionDepth {
+		log.Warn("light client verify header failed", "chainID", b.ChainConfig.ChainID, "trustedHeight", trusted.Height, "height", untrusted.Height, "err", err)
+		return lightClientError("%v", err)
+	}
+	pivotHeight := (trusted.Height + untrusted.Height) / 2
+	if pivotHeight == trusted.Height {
+		return lightClientError("%v", err)
+	}
+	pivot, err := fetchLightBlock(cfg, pivotHeight)
+	if err != nil {
+		return err
+	}
+	if err = b.verifySkipping(cfg, trusted, pivot, depth+1); err != nil {
+		return err
+	}
+	return b.verifySkipping(cfg, pivot, untrusted, depth+1)
+}
+
+// verifyByLightClient verify the deposit tx is included in a block signed by
+// the trusted validators, succeeds and matches the tx queried from rest api.
+func (b *Bridge) verifyByLightClient(txr *GetTxResponse, swapInfo *tokens.SwapTxInfo) error {
+	cfg := params.GetLightClientConfig(b.ChainConfig.ChainID)
+	if cfg == nil {
+		return nil
+	}
+	height := int64(swapInfo.Height)
+	header, err := b.verifyLightBlock(cfg, height)
+	if err != nil {
+		return err
+	}
+
+	var block ctypes.ResultBlock
+	if err = lightClientCall(cfg, &block, "block", strconv.FormatInt(height, 10)); err != nil {
+		return err
+	}
+	if block.Block == nil || !bytes.Equal(block.Block.Hash(), header.Hash()) {
+		return lightClientError("block hash mismatch at height %v", height)
+	}
+	if err = block.Block.ValidateBasic(); err != nil {
+		return lightClientError("invalid block at height %v: %v", height, err)
+	}
+	txIndex := -1
+	for i, tx := range block.Block.Txs {
+		if strings.EqualFold(fmt.Sprintf("%X", tx.Hash()), swapInfo.Hash) {
+			txIndex = i
+			break
+		}
+	}
+	if txIndex < 0 {
+		return lightClientError("tx %v is not included at height %v", swapInfo.Hash, height)
+	}
+
+	// results of block are committed in the next header
+	nextHeader, err := b.verifyLightBlock(cfg, height+1)
+	if err != nil {
+		return err
+	}
+	var results ctypes.ResultBlockResults
+	if err = lightClientCall(cfg, &results, "block_results", strconv.FormatInt(height, 10)); err != nil {
+		return err
+	}
+	if !bytes.Equal(tmtypes.NewResults(results.TxsResults).Hash(), nextHeader.LastResultsHash) {
+		return lightClientError("block results hash mismatch at height %v", height)
+	}
+	if txIndex >= len(results.TxsResults) || results.TxsResults[txIndex].Code != 0 {
+		return fmt.Errorf("%w: tx %v is failed", tokens.ErrTxWithWrongStatus, swapInfo.Hash)
+	}
+
+	if err = checkTxBytes(block.Block.Txs[txIndex], txr, swapInfo, b.GetRouterContract(swapInfo.ERC20SwapInfo.Token), b.getAllowedMsgTypes()); err != nil {
+		return err
+	}
+	log.Info("light client verify deposit success", "chainID", b.ChainConfig.ChainID, "txid", swapInfo.Hash, "height", height)
+	return nil
+}
+
+// checkTxBytes check the verified tx bytes match the tx queried from rest api.
+// all messages must be of the allowed types, and the deposit message must be
+// a bank MsgSend, as the value of other messages can not be checked here.
+func checkTxBytes(txBytes tmtypes.Tx, txr *GetTxResponse, swapInfo *tokens.SwapTxInfo, receiver string, allowedMsgTypes []string) error {
+	var txRaw sdktx.TxRaw
+	if err := txRaw.Unmarshal(txBytes); err != nil {
+		return lightClientError("decode tx failed: %v", err)
+	}
+	var body sdktx.TxBody
+	if err := body.Unmarshal(txRaw.BodyBytes); err != nil {
+		return lightClientError("decode tx body failed: %v", err)
+	}
+	if body.Memo != txr.Tx.Body.Memo {
+		return lightClientError("tx memo mismatch")
+	}
+	if len(body.Messages) != len(txr.Tx.Body.Messages) {
+		return lightClientError("tx messages count mismatch")
+	}
+	for i, msg := range body.Messages {
+		if msg.TypeUrl != txr.Tx.Body.Messages[i].Type {
+			return lightClientError("tx message %v type mismatch", i)
+		}
+		if !isAllowedMsgType(msg.TypeUrl, allowedMsgTypes) {
+			return lightClientError("tx message %v type %v is not allowed", i, msg.TypeUrl)
+		}
+	}
+
+	msgIndex := swapInfo.LogIndex - 1
+	if msgIndex < 0 || msgIndex >= len(body.Messages) {
+		return lightClientError("deposit message index %v out of range", msgIndex)
+	}
+	if msgType := body.Messages[msgIndex].TypeUrl; msgType != msgSendTypeURL {
+		return lightClientError("deposit message type %v is not supported", msgType)
+	}
+	var msgSend banktypes.MsgSend
+	if err := msgSend.Unmarshal(body.Messages[msgIndex].Value); err != nil {
+		return lightClientError("decode msg send failed: %v", err)
+	}
+	value := big.NewInt(0)
+	if common.IsEqualIgnoreCase(msgSend.ToAddress, receiver) {
+		value = msgSend.Amount.AmountOfNoDenomValidation(swapInfo.ERC20SwapInfo.Token).BigInt()
+	}
+	if value.Cmp(swapInfo.Value) != 0 || !common.IsEqualIgnoreCase(msgSend.FromAddress, swapInfo.From) {
+		return lightClientError("msg send mismatch, value %v, from %v", value, msgSend.FromAddress)
+	}
+	return nil
+}
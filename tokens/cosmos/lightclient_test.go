@@ -0,0 +1,83 @@
+package cosmos
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+const testDepositMemo = "0x1111111111111111111111111111111111111111:1"
+
+func newTestTxBytes(t *testing.T, memo string, msgs ...*codecTypes.Any) (tmtypes.Tx, *GetTxResponse) {
+	body := sdktx.TxBody{Messages: msgs, Memo: memo}
+	bodyBytes, err := body.Marshal()
+	if err != nil {
+		t.Fatalf("marshal tx body failed: %v", err)
+	}
+	txRaw := sdktx.TxRaw{BodyBytes: bodyBytes}
+	txBytes, err := txRaw.Marshal()
+	if err != nil {
+		t.Fatalf("marshal tx raw failed: %v", err)
+	}
+	txr := &GetTxResponse{Tx: &Tx{Body: TxBody{Memo: memo}}}
+	for _, msg := range msgs {
+		txr.Tx.Body.Messages = append(txr.Tx.Body.Messages, TxMessage{Type: msg.TypeUrl})
+	}
+	return txBytes, txr
+}
+
+func newTestMsgSend(t *testing.T, from, to string, amount int64) *codecTypes.Any {
+	msg := &banktypes.MsgSend{FromAddress: from, ToAddress: to, Amount: sdk.NewCoins(sdk.NewInt64Coin("uatom", amount))}
+	value, err := msg.Marshal()
+	if err != nil {
+		t.Fatalf("marshal msg send failed: %v", err)
+	}
+	return &codecTypes.Any{TypeUrl: msgSendTypeURL, Value: value}
+}
+
+func TestCheckTxBytes(t *testing.T) {
+	newSwapInfo := func(logIndex int, value int64) *tokens.SwapTxInfo {
+		return &tokens.SwapTxInfo{
+			SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{Token: "uatom"}},
+			From:     testMinter,
+			Value:    big.NewInt(value),
+			LogIndex: logIndex,
+		}
+	}
+	allowed := []string{msgSendTypeURL, TokenFactoryMintTypeURL}
+	send := newTestMsgSend(t, testMinter, testReceiver, 1000)
+	mint := &codecTypes.Any{TypeUrl: TokenFactoryMintTypeURL}
+
+	txBytes, txr := newTestTxBytes(t, testDepositMemo, send)
+	if err := checkTxBytes(txBytes, txr, newSwapInfo(1, 1000), testReceiver, allowed); err != nil {
+		t.Errorf("check msg send got error %v", err)
+	}
+	if err := checkTxBytes(txBytes, txr, newSwapInfo(1, 999), testReceiver, allowed); !errors.Is(err, tokens.ErrLightClientVerify) {
+		t.Errorf("check msg send with wrong value got error %v", err)
+	}
+	if err := checkTxBytes(txBytes, txr, newSwapInfo(2, 1000), testReceiver, allowed); !errors.Is(err, tokens.ErrLightClientVerify) {
+		t.Errorf("check out of range deposit message got error %v", err)
+	}
+
+	// deposit message which is not msg send is rejected
+	txBytes, txr = newTestTxBytes(t, testDepositMemo, send, mint)
+	if err := checkTxBytes(txBytes, txr, newSwapInfo(2, 1000), testReceiver, allowed); !errors.Is(err, tokens.ErrLightClientVerify) {
+		t.Errorf("check deposit message of other type got error %v", err)
+	}
+	// messages not allowed are rejected
+	if err := checkTxBytes(txBytes, txr, newSwapInfo(1, 1000), testReceiver, DefaultAllowedMsgTypes); !errors.Is(err, tokens.ErrLightClientVerify) {
+		t.Errorf("check tx with not allowed message got error %v", err)
+	}
+	// verified bytes must match the rest api result
+	txr.Tx.Body.Memo = "other memo"
+	if err := checkTxBytes(txBytes, txr, newSwapInfo(1, 1000), testReceiver, allowed); !errors.Is(err, tokens.ErrLightClientVerify) {
+		t.Errorf("check tx with mismatched memo got error %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// WasmSmartQueryPath rest path of cosmwasm smart query, args are contract and base64 query
+const WasmSmartQueryPath = "/cosmwasm/wasm/v1/contract/%v/smart/%v"
+
+// MintBurnMsgBuilder builds the mint and burn msgs of wrapped tokens.
+// the default builders build osmosis compatible tokenfactory msgs and
+// wasm execute of cw20 contracts, chains with other msg types register
+// their own builders, and the msg types to the chain's codec by `RegisterChainInterfaces` too.
+type MintBurnMsgBuilder interface {
+	BuildMintMsg(minter, token, receiver string, amount *big.Int) (sdk.Msg, error)
+	BuildBurnMsg(minter, token string, amount *big.Int) (sdk.Msg, error)
+}
+
+var (
+	// chain name (in `ChainsList`) -> token kind -> msg builder
+	mintBurnMsgBuilders     = make(map[string]map[string]MintBurnMsgBuilder)
+	mintBurnMsgBuildersLock sync.RWMutex
+)
+
+// RegisterMintBurnMsgBuilder register mint and burn msg builder of token kind
+// (tokenfactory or cw20) of the specified cosmos sub chain.
+func RegisterMintBurnMsgBuilder(chainName, kind string, builder MintBurnMsgBuilder) {
+	chainName = strings.ToUpper(chainName)
+	mintBurnMsgBuildersLock.Lock()
+	defer mintBurnMsgBuildersLock.Unlock()
+	if mintBurnMsgBuilders[chainName] == nil {
+		mintBurnMsgBuilders[chainName] = make(map[string]MintBurnMsgBuilder)
+	}
+	mintBurnMsgBuilders[chainName][kind] = builder
+}
+
+func (b *Bridge) getMintBurnMsgBuilder(kind string) (MintBurnMsgBuilder, error) {
+	chainName := strings.ToUpper(GetChainNameByStubChainID(b.ChainConfig.ChainID))
+	mintBurnMsgBuildersLock.RLock()
+	defer mintBurnMsgBuildersLock.RUnlock()
+	builder := mintBurnMsgBuilders[chainName][kind]
+	if builder == nil {
+		builder = defaultMintBurnMsgBuilders[kind]
+	}
+	if builder == nil {
+		return nil, fmt.Errorf("no %v mint burn msg builder of chain %v", kind, chainName)
+	}
+	return builder, nil
+}
+
+// getMintBurnKind get mint burn kind of token (empty if not minted and burned)
+func (b *Bridge) getMintBurnKind(token string) string {
+	return params.GetMintBurnConfig(b.ChainConfig.ChainID).GetTokenKind(token)
+}
+
+// QueryDenomAuthorityMetadataResponse tokenfactory denom authority metadata response
+type QueryDenomAuthorityMetadataResponse struct {
+	AuthorityMetadata struct {
+		Admin string `json:"admin"`
+	} `json:"authority_metadata"`
+}
+
+// QueryCW20MinterResponse cw20 minter smart query response
+type QueryCW20MinterResponse struct {
+	Data *struct {
+		Minter string `json:"minter"`
+	} `json:"data"`
+}
+
+// QueryCW20BalanceResponse cw20 balance smart query response
+type QueryCW20BalanceResponse struct {
+	Data struct {
+		Balance sdk.Int `json:"balance"`
+	} `json:"data"`
+}
+
+func (b *Bridge) wasmSmartQuery(result interface{}, contract, query string) error {
+	queryData := base64.URLEncoding.EncodeToString([]byte(query))
+	return b.restGet(result, fmt.Sprintf(WasmSmartQueryPath, contract, queryData))
+}
+
+// getMinter get the account holding the admin (tokenfactory)
+// or minter (cw20) role of token, empty if there is none.
+func (b *Bridge) getMinter(token string) (string, error) {
+	cfg := params.GetMintBurnConfig(b.ChainConfig.ChainID)
+	switch kind := cfg.GetTokenKind(token); kind {
+	case params.MintBurnKindTokenFactory:
+		var res *QueryDenomAuthorityMetadataResponse
+		path := fmt.Sprintf(cfg.GetTokenFactoryAuthorityPath(), url.PathEscape(token))
+		if err := b.restGet(&res, path); err != nil {
+			return "", err
+		}
+		return res.AuthorityMetadata.Admin, nil
+	case params.MintBurnKindCW20:
+		var res *QueryCW20MinterResponse
+		if err := b.wasmSmartQuery(&res, token, `{"minter":{}}`); err != nil {
+			return "", err
+		}
+		if res.Data == nil {
+			return "", nil
+		}
+		return res.Data.Minter, nil
+	default:
+		return "", fmt.Errorf("token %v is not minted and burned", token)
+	}
+}
+
+// checkMinterRole check the mpc holds the minter role of token before building
+func (b *Bridge) checkMinterRole(token, mpc string) error {
+	minter, err := b.getMinter(token)
+	if err != nil {
+		return err
+	}
+	if !common.IsEqualIgnoreCase(minter, mpc) {
+		log.Warn("mpc is not the minter of token", "chainID", b.ChainConfig.ChainID, "token", token, "minter", minter, "mpc", mpc)
+		return fmt.Errorf("%w mpc %v is not the minter of token %v (minter is '%v')", tokens.ErrBuildTxErrorAndDelay, mpc, token, minter)
+	}
+	return nil
+}
+
+// getHeldBalance get balance of wrapped token held by the mpc
+func (b *Bridge) getHeldBalance(mpc, token string) (*big.Int, error) {
+	if b.getMintBurnKind(token) == params.MintBurnKindCW20 {
+		var res *QueryCW20BalanceResponse
+		query := fmt.Sprintf(`{"balance":{"address":%q}}`, mpc)
+		if err := b.wasmSmartQuery(&res, token, query); err != nil {
+			return nil, err
+		}
+		if res.Data.Balance.IsNil() {
+			return big.NewInt(0), nil
+		}
+		return res.Data.Balance.BigInt(), nil
+	}
+	balance, err := b.GetDenomBalance(mpc, token)
+	if err != nil {
+		return nil, err
+	}
+	return balance.BigInt(), nil
+}
+
+// initBurnAmount burn all the wrapped token held by the mpc in the swap tx,
+// the amount is fixed in extra so that the rebuilt tx is the same.
+func (b *Bridge) initBurnAmount(args *tokens.BuildTxArgs, token string) error {
+	if args.Extra.BurnAmount != nil {
+		return nil
+	}
+	held, err := b.getHeldBalance(args.From, token)
+	if err != nil {
+		return err
+	}
+	if held.Sign() > 0 {
+		args.Extra.BurnAmount = held
+	}
+	return nil
+}
+
+// buildMintBurnMsgs build msgs minting to the receivers and burning the held tokens
+func (b *Bridge) buildMintBurnMsgs(args *tokens.BuildTxArgs, token string, receivers []string, amounts []*big.Int) (msgs []sdk.Msg, err error) {
+	builder, err := b.getMintBurnMsgBuilder(b.getMintBurnKind(token))
+	if err != nil {
+		return nil, err
+	}
+	from := args.From
+	if burnAmount := args.Extra.BurnAmount; burnAmount != nil && burnAmount.Sign() > 0 {
+		held, err := b.getHeldBalance(from, token)
+		if err != nil {
+			return nil, err
+		}
+		if held.Cmp(burnAmount) < 0 {
+			log.Info("held balance not enough to burn", "token", token, "held", held, "burnAmount", burnAmount)
+			return nil, tokens.ErrBalanceNotEnough
+		}
+		burnMsg, err := builder.BuildBurnMsg(from, token, burnAmount)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, burnMsg)
+	}
+	for i, receiver := range receivers {
+		mintMsg, err := builder.BuildMintMsg(from, token, receiver, amounts[i])
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, mintMsg)
+	}
+	return msgs, nil
+}
+
+// getMinters get minters of the mint burn tokens for watching,
+// the previous minter is kept if the query fails.
+func (b *Bridge) getMinters(prev map[string]string) map[string]string {
+	cfg := params.GetMintBurnConfig(b.ChainConfig.ChainID)
+	if cfg == nil {
+		return nil
+	}
+	minters := make(map[string]string, len(cfg.Tokens))
+	for token := range cfg.Tokens {
+		minter, err := b.getMinter(token)
+		if err != nil {
+			log.Warn("get minter of token failed", "chainID", b.ChainConfig.ChainID, "token", token, "err", err)
+			if prevMinter, exist := prev[token]; exist {
+				minters[token] = prevMinter
+			}
+			continue
+		}
+		minters[token] = minter
+	}
+	return minters
+}
@@ -0,0 +1,506 @@
+package cosmos
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// type urls of the default mint and burn msgs
+const (
+	TokenFactoryMintTypeURL = "/osmosis.tokenfactory.v1beta1.MsgMint"
+	TokenFactoryBurnTypeURL = "/osmosis.tokenfactory.v1beta1.MsgBurn"
+	WasmExecuteTypeURL      = "/cosmwasm.wasm.v1.MsgExecuteContract"
+)
+
+var (
+	errInvalidMintBurnMsg = errors.New("invalid mint burn msg")
+
+	// default msg builders of token kinds, used if the chain registers none
+	defaultMintBurnMsgBuilders = map[string]MintBurnMsgBuilder{
+		params.MintBurnKindTokenFactory: TokenFactoryMsgBuilder{},
+		params.MintBurnKindCW20:         CW20MsgBuilder{},
+	}
+
+	_ sdk.Msg = &MsgTokenFactoryMint{}
+	_ sdk.Msg = &MsgTokenFactoryBurn{}
+	_ sdk.Msg = &MsgWasmExecuteContract{}
+
+	// gzipped file descriptors of the msgs (the same fields as the chains' tx.proto),
+	// the sdk tx decoder checks unknown fields of msgs with them.
+	tokenFactoryFileDescriptor = newGzippedFileDescriptor("osmosis/tokenfactory/v1beta1/tx.proto", "osmosis.tokenfactory.v1beta1",
+		newMsgDescriptor("MsgMint", newStringField("sender", 1), newCoinField("amount", 2, false), newStringField("mintToAddress", 3)),
+		newMsgDescriptor("MsgBurn", newStringField("sender", 1), newCoinField("amount", 2, false), newStringField("burnFromAddress", 3)),
+	)
+	wasmFileDescriptor = newGzippedFileDescriptor("cosmwasm/wasm/v1/tx.proto", "cosmwasm.wasm.v1",
+		newMsgDescriptor("MsgExecuteContract", newStringField("sender", 1), newStringField("contract", 2), newBytesField("msg", 3), newCoinField("funds", 5, true)),
+	)
+)
+
+// RegisterMintBurnInterfaces register the default mint and burn msgs,
+// the msgs already registered by the chain (eg. the chain's own generated types) are kept.
+func RegisterMintBurnInterfaces(registry codecTypes.InterfaceRegistry) {
+	for typeURL, msg := range map[string]sdk.Msg{
+		TokenFactoryMintTypeURL: &MsgTokenFactoryMint{},
+		TokenFactoryBurnTypeURL: &MsgTokenFactoryBurn{},
+		WasmExecuteTypeURL:      &MsgWasmExecuteContract{},
+	} {
+		if _, err := registry.Resolve(typeURL); err == nil {
+			continue
+		}
+		registry.RegisterImplementations((*sdk.Msg)(nil), msg)
+	}
+}
+
+// TokenFactoryMsgBuilder builds tokenfactory MsgMint and MsgBurn (osmosis compatible)
+type TokenFactoryMsgBuilder struct{}
+
+// BuildMintMsg impl MintBurnMsgBuilder
+func (TokenFactoryMsgBuilder) BuildMintMsg(minter, token, receiver string, amount *big.Int) (sdk.Msg, error) {
+	msg := &MsgTokenFactoryMint{
+		Sender:        minter,
+		Amount:        sdk.NewCoin(token, sdk.NewIntFromBigInt(amount)),
+		MintToAddress: receiver,
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// BuildBurnMsg impl MintBurnMsgBuilder
+func (TokenFactoryMsgBuilder) BuildBurnMsg(minter, token string, amount *big.Int) (sdk.Msg, error) {
+	msg := &MsgTokenFactoryBurn{
+		Sender:          minter,
+		Amount:          sdk.NewCoin(token, sdk.NewIntFromBigInt(amount)),
+		BurnFromAddress: minter,
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// CW20MsgBuilder builds wasm execute of cw20 contract mint and burn
+type CW20MsgBuilder struct{}
+
+type cw20MintMsg struct {
+	Mint struct {
+		Recipient string `json:"recipient"`
+		Amount    string `json:"amount"`
+	} `json:"mint"`
+}
+
+type cw20BurnMsg struct {
+	Burn struct {
+		Amount string `json:"amount"`
+	} `json:"burn"`
+}
+
+// BuildMintMsg impl MintBurnMsgBuilder
+func (CW20MsgBuilder) BuildMintMsg(minter, token, receiver string, amount *big.Int) (sdk.Msg, error) {
+	var execMsg cw20MintMsg
+	execMsg.Mint.Recipient = receiver
+	execMsg.Mint.Amount = amount.String()
+	return newWasmExecuteMsg(minter, token, execMsg)
+}
+
+// BuildBurnMsg impl MintBurnMsgBuilder
+func (CW20MsgBuilder) BuildBurnMsg(minter, token string, amount *big.Int) (sdk.Msg, error) {
+	var execMsg cw20BurnMsg
+	execMsg.Burn.Amount = amount.String()
+	return newWasmExecuteMsg(minter, token, execMsg)
+}
+
+func newWasmExecuteMsg(sender, contract string, execMsg interface{}) (*MsgWasmExecuteContract, error) {
+	data, err := json.Marshal(execMsg)
+	if err != nil {
+		return nil, err
+	}
+	msg := &MsgWasmExecuteContract{
+		Sender:   sender,
+		Contract: contract,
+		Msg:      data,
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// MsgTokenFactoryMint tokenfactory MsgMint
+type MsgTokenFactoryMint struct {
+	Sender        string
+	Amount        sdk.Coin
+	MintToAddress string
+}
+
+// XXX_MessageName proto message name
+func (*MsgTokenFactoryMint) XXX_MessageName() string {
+	return TokenFactoryMintTypeURL[1:]
+}
+
+// Reset impl proto.Message
+func (m *MsgTokenFactoryMint) Reset() { *m = MsgTokenFactoryMint{} }
+
+// String impl proto.Message
+func (m *MsgTokenFactoryMint) String() string {
+	return fmt.Sprintf("MsgMint{Sender:%v Amount:%v MintToAddress:%v}", m.Sender, m.Amount, m.MintToAddress)
+}
+
+// ProtoMessage impl proto.Message
+func (*MsgTokenFactoryMint) ProtoMessage() {}
+
+// Descriptor proto descriptor
+func (*MsgTokenFactoryMint) Descriptor() ([]byte, []int) {
+	return tokenFactoryFileDescriptor, []int{0}
+}
+
+// ValidateBasic impl sdk.Msg
+func (m *MsgTokenFactoryMint) ValidateBasic() error {
+	if err := validateMintBurnAddresses(m.Sender, m.MintToAddress); err != nil {
+		return err
+	}
+	return validateMintBurnCoin(m.Amount)
+}
+
+// GetSigners impl sdk.Msg
+func (m *MsgTokenFactoryMint) GetSigners() []sdk.AccAddress {
+	return getMintBurnSigners(m.Sender)
+}
+
+// Marshal proto marshal
+func (m *MsgTokenFactoryMint) Marshal() ([]byte, error) {
+	var b []byte
+	b = appendStringField(b, 1, m.Sender)
+	b = appendBytesField(b, 2, marshalCoin(m.Amount))
+	b = appendStringField(b, 3, m.MintToAddress)
+	return b, nil
+}
+
+// Unmarshal proto unmarshal
+func (m *MsgTokenFactoryMint) Unmarshal(data []byte) error {
+	m.Reset()
+	return unmarshalFields(data, func(num protowire.Number, value []byte) (err error) {
+		switch num {
+		case 1:
+			m.Sender = string(value)
+		case 2:
+			m.Amount, err = unmarshalCoin(value)
+		case 3:
+			m.MintToAddress = string(value)
+		}
+		return err
+	})
+}
+
+// MsgTokenFactoryBurn tokenfactory MsgBurn
+type MsgTokenFactoryBurn struct {
+	Sender          string
+	Amount          sdk.Coin
+	BurnFromAddress string
+}
+
+// XXX_MessageName proto message name
+func (*MsgTokenFactoryBurn) XXX_MessageName() string {
+	return TokenFactoryBurnTypeURL[1:]
+}
+
+// Reset impl proto.Message
+func (m *MsgTokenFactoryBurn) Reset() { *m = MsgTokenFactoryBurn{} }
+
+// String impl proto.Message
+func (m *MsgTokenFactoryBurn) String() string {
+	return fmt.Sprintf("MsgBurn{Sender:%v Amount:%v BurnFromAddress:%v}", m.Sender, m.Amount, m.BurnFromAddress)
+}
+
+// ProtoMessage impl proto.Message
+func (*MsgTokenFactoryBurn) ProtoMessage() {}
+
+// Descriptor proto descriptor
+func (*MsgTokenFactoryBurn) Descriptor() ([]byte, []int) {
+	return tokenFactoryFileDescriptor, []int{1}
+}
+
+// ValidateBasic impl sdk.Msg
+func (m *MsgTokenFactoryBurn) ValidateBasic() error {
+	if err := validateMintBurnAddresses(m.Sender, m.BurnFromAddress); err != nil {
+		return err
+	}
+	return validateMintBurnCoin(m.Amount)
+}
+
+// GetSigners impl sdk.Msg
+func (m *MsgTokenFactoryBurn) GetSigners() []sdk.AccAddress {
+	return getMintBurnSigners(m.Sender)
+}
+
+// Marshal proto marshal
+func (m *MsgTokenFactoryBurn) Marshal() ([]byte, error) {
+	var b []byte
+	b = appendStringField(b, 1, m.Sender)
+	b = appendBytesField(b, 2, marshalCoin(m.Amount))
+	b = appendStringField(b, 3, m.BurnFromAddress)
+	return b, nil
+}
+
+// Unmarshal proto unmarshal
+func (m *MsgTokenFactoryBurn) Unmarshal(data []byte) error {
+	m.Reset()
+	return unmarshalFields(data, func(num protowire.Number, value []byte) (err error) {
+		switch num {
+		case 1:
+			m.Sender = string(value)
+		case 2:
+			m.Amount, err = unmarshalCoin(value)
+		case 3:
+			m.BurnFromAddress = string(value)
+		}
+		return err
+	})
+}
+
+// MsgWasmExecuteContract cosmwasm MsgExecuteContract
+type MsgWasmExecuteContract struct {
+	Sender   string
+	Contract string
+	Msg      []byte // json encoded
+	Funds    sdk.Coins
+}
+
+// XXX_MessageName proto message name
+func (*MsgWasmExecuteContract) XXX_MessageName() string {
+	return WasmExecuteTypeURL[1:]
+}
+
+// Reset impl proto.Message
+func (m *MsgWasmExecuteContract) Reset() { *m = MsgWasmExecuteContract{} }
+
+// String impl proto.Message
+func (m *MsgWasmExecuteContract) String() string {
+	return fmt.Sprintf("MsgExecuteContract{Sender:%v Contract:%v Msg:%s Funds:%v}", m.Sender, m.Contract, m.Msg, m.Funds)
+}
+
+// ProtoMessage impl proto.Message
+func (*MsgWasmExecuteContract) ProtoMessage() {}
+
+// Descriptor proto descriptor
+func (*MsgWasmExecuteContract) Descriptor() ([]byte, []int) {
+	return wasmFileDescriptor, []int{0}
+}
+
+// ValidateBasic impl sdk.Msg
+func (m *MsgWasmExecuteContract) ValidateBasic() error {
+	if err := validateMintBurnAddresses(m.Sender, m.Contract); err != nil {
+		return err
+	}
+	if !json.Valid(m.Msg) {
+		return fmt.Errorf("%w: wasm execute msg is not json", errInvalidMintBurnMsg)
+	}
+	if !m.Funds.IsValid() {
+		return fmt.Errorf("%w: invalid funds %v", errInvalidMintBurnMsg, m.Funds)
+	}
+	return nil
+}
+
+// GetSigners impl sdk.Msg
+func (m *MsgWasmExecuteContract) GetSigners() []sdk.AccAddress {
+	return getMintBurnSigners(m.Sender)
+}
+
+// Marshal proto marshal
+func (m *MsgWasmExecuteContract) Marshal() ([]byte, error) {
+	var b []byte
+	b = appendStringField(b, 1, m.Sender)
+	b = appendStringField(b, 2, m.Contract)
+	if len(m.Msg) > 0 {
+		b = appendBytesField(b, 3, m.Msg)
+	}
+	for _, coin := range m.Funds {
+		b = appendBytesField(b, 5, marshalCoin(coin))
+	}
+	return b, nil
+}
+
+// Unmarshal proto unmarshal
+func (m *MsgWasmExecuteContract) Unmarshal(data []byte) error {
+	m.Reset()
+	return unmarshalFields(data, func(num protowire.Number, value []byte) error {
+		switch num {
+		case 1:
+			m.Sender = string(value)
+		case 2:
+			m.Contract = string(value)
+		case 3:
+			m.Msg = append([]byte{}, value...)
+		case 5:
+			coin, err := unmarshalCoin(value)
+			if err != nil {
+				return err
+			}
+			m.Funds = append(m.Funds, coin)
+		}
+		return nil
+	})
+}
+
+func validateMintBurnAddresses(addresses ...string) error {
+	for _, address := range addresses {
+		if _, _, err := bech32.DecodeAndConvert(address); err != nil {
+			return fmt.Errorf("%w: invalid address '%v', %v", errInvalidMintBurnMsg, address, err)
+		}
+	}
+	return nil
+}
+
+func validateMintBurnCoin(coin sdk.Coin) error {
+	if coin.Amount.IsNil() || !coin.IsValid() || coin.IsZero() {
+		return fmt.Errorf("%w: invalid amount %v", errInvalidMintBurnMsg, coin)
+	}
+	return nil
+}
+
+// getMintBurnSigners decode signer with its own prefix,
+// as the process wide bech32 config is sealed with the first chain's prefix.
+func getMintBurnSigners(sender string) []sdk.AccAddress {
+	_, bz, err := bech32.DecodeAndConvert(sender)
+	if err != nil {
+		panic(err) // checked by ValidateBasic, the same as sdk msgs
+	}
+	return []sdk.AccAddress{bz}
+}
+
+func newGzippedFileDescriptor(name, pkg string, msgs ...*descriptor.DescriptorProto) []byte {
+	fd := &descriptor.FileDescriptorProto{
+		Name:        proto.String(name),
+		Package:     proto.String(pkg),
+		Dependency:  []string{"cosmos/base/v1beta1/coin.proto"},
+		MessageType: msgs,
+		Syntax:      proto.String("proto3"),
+	}
+	data, err := proto.Marshal(fd)
+	if err != nil {
+		panic(err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err = zw.Write(data); err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+func newMsgDescriptor(name string, fields ...*descriptor.FieldDescriptorProto) *descriptor.DescriptorProto {
+	return &descriptor.DescriptorProto{Name: proto.String(name), Field: fields}
+}
+
+func newField(name string, num int32, typ descriptor.FieldDescriptorProto_Type) *descriptor.FieldDescriptorProto {
+	return &descriptor.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(name),
+		Number:   proto.Int32(num),
+		Label:    descriptor.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     typ.Enum(),
+	}
+}
+
+func newStringField(name string, num int32) *descriptor.FieldDescriptorProto {
+	return newField(name, num, descriptor.FieldDescriptorProto_TYPE_STRING)
+}
+
+func newBytesField(name string, num int32) *descriptor.FieldDescriptorProto {
+	return newField(name, num, descriptor.FieldDescriptorProto_TYPE_BYTES)
+}
+
+func newCoinField(name string, num int32, repeated bool) *descriptor.FieldDescriptorProto {
+	field := newField(name, num, descriptor.FieldDescriptorProto_TYPE_MESSAGE)
+	field.TypeName = proto.String(".cosmos.base.v1beta1.Coin")
+	if repeated {
+		field.Label = descriptor.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	}
+	return field
+}
+
+// proto3 omits empty scalar fields
+func appendStringField(b []byte, num protowire.Number, value string) []byte {
+	if value == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+func appendBytesField(b []byte, num protowire.Number, value []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, value)
+}
+
+func marshalCoin(coin sdk.Coin) []byte {
+	var b []byte
+	b = appendStringField(b, 1, coin.Denom)
+	if !coin.Amount.IsNil() {
+		b = appendStringField(b, 2, coin.Amount.String())
+	}
+	return b
+}
+
+func unmarshalCoin(data []byte) (coin sdk.Coin, err error) {
+	coin.Amount = sdk.ZeroInt()
+	err = unmarshalFields(data, func(num protowire.Number, value []byte) error {
+		switch num {
+		case 1:
+			coin.Denom = string(value)
+		case 2:
+			amount, ok := sdk.NewIntFromString(string(value))
+			if !ok {
+				return fmt.Errorf("%w: invalid coin amount '%s'", errInvalidMintBurnMsg, value)
+			}
+			coin.Amount = amount
+		}
+		return nil
+	})
+	return coin, err
+}
+
+// unmarshalFields calls handle with the value of length delimited fields,
+// the msgs here have no other wire types, unknown fields are skipped.
+func unmarshalFields(data []byte, handle func(num protowire.Number, value []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			data = data[n:]
+			continue
+		}
+		value, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		if err := handle(num, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
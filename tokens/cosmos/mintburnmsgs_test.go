@@ -0,0 +1,153 @@
+package cosmos
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+const (
+	testMinter   = "osmo1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5helwsw"
+	testReceiver = "osmo1z5tpwxqergd3c8g7ruszzg3rysjjvfegqutqcc"
+	testCW20     = "osmo1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq65mj83"
+	testDenom    = "factory/" + testMinter + "/uwbtc"
+)
+
+// reference encodings are computed from the proto definitions
+// (osmosis tokenfactory v1beta1 and cosmwasm wasm v1) field by field.
+var mintBurnMsgTests = []struct {
+	build   func() (sdk.Msg, error)
+	typeURL string
+	encoded string
+}{
+	{
+		build: func() (sdk.Msg, error) {
+			return TokenFactoryMsgBuilder{}.BuildMintMsg(testMinter, testDenom, testReceiver, big.NewInt(1000000))
+		},
+		typeURL: TokenFactoryMintTypeURL,
+		encoded: "0x0a2b6f736d6f31717970717870713971637273737a673270767871367273307a7167337979633568656c77737712440a39666163746f72792f6f736d6f31717970717870713971637273737a673270767871367273307a7167337979633568656c7773772f75776274631207313030303030301a2b6f736d6f317a3574707778716572676433633867377275737a7a67337279736a6a76666567717574716363",
+	},
+	{
+		build: func() (sdk.Msg, error) {
+			return TokenFactoryMsgBuilder{}.BuildBurnMsg(testMinter, testDenom, big.NewInt(1000000))
+		},
+		typeURL: TokenFactoryBurnTypeURL,
+		encoded: "0x0a2b6f736d6f31717970717870713971637273737a673270767871367273307a7167337979633568656c77737712440a39666163746f72792f6f736d6f31717970717870713971637273737a673270767871367273307a7167337979633568656c7773772f75776274631207313030303030301a2b6f736d6f31717970717870713971637273737a673270767871367273307a7167337979633568656c777377",
+	},
+	{
+		build: func() (sdk.Msg, error) {
+			return CW20MsgBuilder{}.BuildMintMsg(testMinter, testCW20, testReceiver, big.NewInt(1000000))
+		},
+		typeURL: WasmExecuteTypeURL,
+		encoded: "0x0a2b6f736d6f31717970717870713971637273737a673270767871367273307a7167337979633568656c777377123f6f736d6f317171717171717171717171717171717171717171717171717171717171717171717171717171717171717171717171717171717136356d6a38331a577b226d696e74223a7b22726563697069656e74223a226f736d6f317a3574707778716572676433633867377275737a7a67337279736a6a76666567717574716363222c22616d6f756e74223a2231303030303030227d7d",
+	},
+	{
+		build: func() (sdk.Msg, error) {
+			return CW20MsgBuilder{}.BuildBurnMsg(testMinter, testCW20, big.NewInt(1000000))
+		},
+		typeURL: WasmExecuteTypeURL,
+		encoded: "0x0a2b6f736d6f31717970717870713971637273737a673270767871367273307a7167337979633568656c777377123f6f736d6f317171717171717171717171717171717171717171717171717171717171717171717171717171717171717171717171717171717136356d6a38331a1d7b226275726e223a7b22616d6f756e74223a2231303030303030227d7d",
+	},
+}
+
+func TestMintBurnMsgEncoding(t *testing.T) {
+	for i, test := range mintBurnMsgTests {
+		msg, err := test.build()
+		if err != nil {
+			t.Fatalf("test %v: build msg failed: %v", i, err)
+		}
+		if got := "/" + proto.MessageName(msg); got != test.typeURL {
+			t.Errorf("test %v: type url got %v, want %v", i, got, test.typeURL)
+		}
+		encoded, err := proto.Marshal(msg)
+		if err != nil {
+			t.Fatalf("test %v: marshal msg failed: %v", i, err)
+		}
+		if !bytes.Equal(encoded, common.FromHex(test.encoded)) {
+			t.Errorf("test %v: encoded msg got %x, want %v", i, encoded, test.encoded)
+		}
+		decoded := reflect.New(reflect.TypeOf(msg).Elem()).Interface().(sdk.Msg)
+		if err := proto.Unmarshal(encoded, decoded); err != nil {
+			t.Fatalf("test %v: unmarshal msg failed: %v", i, err)
+		}
+		if decoded.String() != msg.String() {
+			t.Errorf("test %v: decoded msg got %v, want %v", i, decoded, msg)
+		}
+		if signers := decoded.GetSigners(); len(signers) != 1 || !bytes.Equal(signers[0], common.FromHex("0x0102030405060708090a0b0c0d0e0f1011121314")) {
+			t.Errorf("test %v: msg signers got %v", i, signers)
+		}
+	}
+}
+
+func TestMintBurnMsgsInTx(t *testing.T) {
+	// the default msgs are registered to the codec of every chain
+	clientCtx := NewClientContext()
+	txConfig := clientCtx.TxConfig
+	var msgs []sdk.Msg
+	for i, test := range mintBurnMsgTests {
+		msg, err := test.build()
+		if err != nil {
+			t.Fatalf("test %v: build msg failed: %v", i, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	txBuilder := txConfig.NewTxBuilder()
+	if err := txBuilder.SetMsgs(msgs...); err != nil {
+		t.Fatalf("set msgs failed: %v", err)
+	}
+	txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		t.Fatalf("encode tx failed: %v", err)
+	}
+	tx, err := txConfig.TxDecoder()(txBytes)
+	if err != nil {
+		t.Fatalf("decode tx failed: %v", err)
+	}
+	decodedMsgs := tx.GetMsgs()
+	if len(decodedMsgs) != len(msgs) {
+		t.Fatalf("decoded msgs count got %v, want %v", len(decodedMsgs), len(msgs))
+	}
+	for i, msg := range decodedMsgs {
+		if msg.String() != msgs[i].String() {
+			t.Errorf("decoded msg %v got %v, want %v", i, msg, msgs[i])
+		}
+	}
+}
+
+func TestMintBurnMsgDescriptors(t *testing.T) {
+	for i, test := range mintBurnMsgTests {
+		msg, err := test.build()
+		if err != nil {
+			t.Fatalf("test %v: build msg failed: %v", i, err)
+		}
+		fd, md := descriptor.ForMessage(msg.(descriptor.Message))
+		if got := "/" + fd.GetPackage() + "." + md.GetName(); got != test.typeURL {
+			t.Errorf("test %v: descriptor name got %v, want %v", i, got, test.typeURL)
+		}
+	}
+}
+
+func TestMintBurnMsgValidation(t *testing.T) {
+	if _, err := (TokenFactoryMsgBuilder{}).BuildMintMsg(testMinter, testDenom, "osmo1invalid", big.NewInt(1)); !errors.Is(err, errInvalidMintBurnMsg) {
+		t.Errorf("mint to invalid address got error %v", err)
+	}
+	if _, err := (TokenFactoryMsgBuilder{}).BuildBurnMsg(testMinter, testDenom, big.NewInt(0)); !errors.Is(err, errInvalidMintBurnMsg) {
+		t.Errorf("burn zero amount got error %v", err)
+	}
+	if _, err := (CW20MsgBuilder{}).BuildMintMsg(testMinter, "invalid contract", testReceiver, big.NewInt(1)); !errors.Is(err, errInvalidMintBurnMsg) {
+		t.Errorf("mint of invalid cw20 contract got error %v", err)
+	}
+	for _, kind := range []string{params.MintBurnKindTokenFactory, params.MintBurnKindCW20} {
+		if defaultMintBurnMsgBuilders[kind] == nil {
+			t.Errorf("no default mint burn msg builder of %v", kind)
+		}
+	}
+}
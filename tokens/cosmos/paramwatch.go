@@ -0,0 +1,200 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	ibcDenomPrefix   = "ibc/"
+	ibcChannelOpened = "STATE_OPEN"
+)
+
+var errNoRestGateway = errors.New("no rest gateway")
+
+// watchedParams on-chain params affecting bridge operation
+type watchedParams struct {
+	sendDisabled map[string]bool   // denom -> disabled
+	closedIBC    map[string]string // ibc denom -> channel and its state
+	minGasPrices sdk.DecCoins
+	minters      map[string]string // mint burn token -> minter
+}
+
+// WatchChainParams impl tokens.ChainParamWatcher
+// watch bank send_enabled of token denoms, node minimum gas price,
+// channel state of ibc denoms, and minters of mint burn tokens.
+func (b *Bridge) WatchChainParams() (changes []string, err error) {
+	if len(b.GatewayConfig.AllGatewayURLs) == 0 {
+		return nil, errNoRestGateway
+	}
+	var bankParams *QueryBankParamsResponse
+	if err = b.restGet(&bankParams, BankParamsPath); err != nil {
+		return nil, err
+	}
+	watched := &watchedParams{
+		sendDisabled: make(map[string]bool),
+		closedIBC:    make(map[string]string),
+	}
+	for _, denom := range b.getTokenDenoms() {
+		if !bankParams.Params.IsSendEnabled(denom) {
+			watched.sendDisabled[denom] = true
+		}
+		if strings.HasPrefix(denom, ibcDenomPrefix) {
+			if state, err := b.getIBCChannelState(denom); err != nil {
+				log.Warn("get ibc channel state failed", "chainID", b.ChainConfig.ChainID, "denom", denom, "err", err)
+			} else if !strings.HasSuffix(state, ibcChannelOpened) {
+				watched.closedIBC[denom] = state
+			}
+		}
+	}
+	// minimum gas price of node is not supported by old nodes
+	var nodeConfig *QueryNodeConfigResponse
+	if err := b.restGet(&nodeConfig, NodeConfigPath); err == nil && nodeConfig.MinimumGasPrice != "" {
+		watched.minGasPrices, err = sdk.ParseDecCoins(nodeConfig.MinimumGasPrice)
+		if err != nil {
+			log.Warn("wrong node minimum gas price", "chainID", b.ChainConfig.ChainID, "minGasPrice", nodeConfig.MinimumGasPrice, "err", err)
+		}
+	}
+
+	var prevMinters map[string]string
+	if prev := b.getWatchedParams(); prev != nil {
+		prevMinters = prev.minters
+	}
+	watched.minters = b.getMinters(prevMinters)
+
+	b.watchedParamsLock.Lock()
+	changes = diffWatchedParams(b.watchedParams, watched)
+	b.watchedParams = watched
+	b.watchedParamsLock.Unlock()
+	return changes, nil
+}
+
+func (b *Bridge) restGet(result interface{}, path string) (err error) {
+	for _, url := range b.GatewayConfig.AllGatewayURLs {
+		if err = client.RPCGet(result, joinURLPath(url, path)); err == nil {
+			return nil
+		}
+	}
+	return wrapRPCQueryError(err, path)
+}
+
+func (b *Bridge) getTokenDenoms() []string {
+	denoms := make([]string, 0)
+	b.TokenConfigMap.Range(func(k, v interface{}) bool {
+		if tokenCfg, ok := v.(*tokens.TokenConfig); ok && tokenCfg.ContractAddress != "" {
+			denoms = append(denoms, tokenCfg.ContractAddress)
+		}
+		return true
+	})
+	sort.Strings(denoms)
+	return denoms
+}
+
+// getIBCChannelState get state of the channel which the ibc denom is received from
+func (b *Bridge) getIBCChannelState(denom string) (string, error) {
+	var trace *QueryDenomTraceResponse
+	if err := b.restGet(&trace, DenomTracePath+strings.TrimPrefix(denom, ibcDenomPrefix)); err != nil {
+		return "", err
+	}
+	// path is 'port/channel' pairs, the first is the channel on this chain
+	parts := strings.Split(trace.DenomTrace.Path, "/")
+	if len(parts) < 2 {
+		return "", fmt.Errorf("wrong denom trace path '%v'", trace.DenomTrace.Path)
+	}
+	port, channel := parts[0], parts[1]
+	var channelRes *QueryChannelResponse
+	if err := b.restGet(&channelRes, fmt.Sprintf(ChannelPath, channel, port)); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v/%v %v", port, channel, channelRes.Channel.State), nil
+}
+
+func (b *Bridge) getWatchedParams() *watchedParams {
+	b.watchedParamsLock.RLock()
+	defer b.watchedParamsLock.RUnlock()
+	return b.watchedParams
+}
+
+// checkWatchedParams pause building swap txs of denom affected by on-chain params
+func (b *Bridge) checkWatchedParams(denom string) error {
+	watched := b.getWatchedParams()
+	if watched == nil {
+		return nil
+	}
+	if watched.sendDisabled[denom] {
+		return fmt.Errorf("%w send of denom %v is disabled", tokens.ErrBuildTxErrorAndDelay, denom)
+	}
+	if state, exist := watched.closedIBC[denom]; exist {
+		return fmt.Errorf("%w ibc channel %v of denom %v is not open", tokens.ErrBuildTxErrorAndDelay, state, denom)
+	}
+	return nil
+}
+
+// adjustFeeToMinGasPrice raise fee to the minimum gas price of node if it is lower
+func (b *Bridge) adjustFeeToMinGasPrice(extra *tokens.AllExtras) {
+	watched := b.getWatchedParams()
+	if watched == nil || len(watched.minGasPrices) == 0 || extra.Fee == nil || extra.Gas == nil {
+		return
+	}
+	coinsFee, err := ParseCoinsFee(*extra.Fee)
+	if err != nil || len(coinsFee) != 1 {
+		return
+	}
+	minGasPrice := watched.minGasPrices.AmountOf(coinsFee[0].Denom)
+	if !minGasPrice.IsPositive() {
+		return
+	}
+	minFee := minGasPrice.MulInt64(int64(*extra.Gas)).Ceil().TruncateInt()
+	if coinsFee[0].Amount.GTE(minFee) {
+		return
+	}
+	coinsFee[0].Amount = minFee
+	adjustFee := coinsFee.String()
+	log.Info("raise fee to minimum gas price", "chainID", b.ChainConfig.ChainID, "fee", *extra.Fee, "adjustFee", adjustFee, "gas", *extra.Gas, "minGasPrice", minGasPrice)
+	extra.Fee = &adjustFee
+}
+
+// diffWatchedParams describe the changes of watched params,
+// minimum gas price and minters are not regarded as changed in the first watch.
+func diffWatchedParams(old, cur *watchedParams) (changes []string) {
+	if old == nil {
+		old = &watchedParams{minGasPrices: cur.minGasPrices, minters: cur.minters}
+	}
+	for denom := range cur.sendDisabled {
+		if !old.sendDisabled[denom] {
+			changes = append(changes, fmt.Sprintf("send of denom %v is disabled", denom))
+		}
+	}
+	for denom := range old.sendDisabled {
+		if !cur.sendDisabled[denom] {
+			changes = append(changes, fmt.Sprintf("send of denom %v is enabled", denom))
+		}
+	}
+	for denom, state := range cur.closedIBC {
+		if old.closedIBC[denom] != state {
+			changes = append(changes, fmt.Sprintf("ibc channel %v of denom %v is not open", state, denom))
+		}
+	}
+	for denom := range old.closedIBC {
+		if _, exist := cur.closedIBC[denom]; !exist {
+			changes = append(changes, fmt.Sprintf("ibc channel of denom %v is open", denom))
+		}
+	}
+	for token, minter := range cur.minters {
+		if oldMinter, exist := old.minters[token]; exist && oldMinter != minter {
+			changes = append(changes, fmt.Sprintf("minter of token %v is changed from '%v' to '%v'", token, oldMinter, minter))
+		}
+	}
+	if old.minGasPrices.String() != cur.minGasPrices.String() {
+		changes = append(changes, fmt.Sprintf("minimum gas price is changed from '%v' to '%v'", old.minGasPrices, cur.minGasPrices))
+	}
+	sort.Strings(changes)
+	return changes
+}
@@ -0,0 +1,121 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestWatchChainParams(t *testing.T) {
+	sendEnabled := true
+	channelState := "STATE_OPEN"
+	minGasPrice := "0.025uatom"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var res interface{}
+		switch r.URL.Path {
+		case BankParamsPath:
+			res = &QueryBankParamsResponse{Params: BankParams{
+				SendEnabled:        []*SendEnabled{{Denom: "uatom", Enabled: sendEnabled}},
+				DefaultSendEnabled: true,
+			}}
+		case NodeConfigPath:
+			res = &QueryNodeConfigResponse{MinimumGasPrice: minGasPrice}
+		case DenomTracePath + "ABC":
+			trace := &QueryDenomTraceResponse{}
+			trace.DenomTrace.Path = "transfer/channel-0"
+			trace.DenomTrace.BaseDenom = "uosmo"
+			res = trace
+		case "/ibc/core/channel/v1/channels/channel-0/ports/transfer":
+			channel := &QueryChannelResponse{}
+			channel.Channel.State = channelState
+			res = channel
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: "cosmos-param-watch-test"})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{server.URL}})
+	b.SetTokenConfig("uatom", &tokens.TokenConfig{ContractAddress: "uatom"})
+	b.SetTokenConfig("ibc/ABC", &tokens.TokenConfig{ContractAddress: "ibc/ABC"})
+
+	// the first watch records the params without changes
+	changes, err := b.WatchChainParams()
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("first watch chain params got (%v, %v), want no changes", changes, err)
+	}
+	for _, denom := range []string{"uatom", "ibc/ABC"} {
+		if err = b.checkWatchedParams(denom); err != nil {
+			t.Errorf("check watched params of %v got error %v", denom, err)
+		}
+	}
+
+	// building of affected denoms is paused
+	sendEnabled = false
+	channelState = "STATE_CLOSED"
+	minGasPrice = "0.05uatom"
+	changes, err = b.WatchChainParams()
+	if err != nil || len(changes) != 3 {
+		t.Errorf("watch changed chain params got (%v, %v), want 3 changes", changes, err)
+	}
+	for _, denom := range []string{"uatom", "ibc/ABC"} {
+		if err = b.checkWatchedParams(denom); !errors.Is(err, tokens.ErrBuildTxErrorAndDelay) {
+			t.Errorf("check watched params of %v got error %v, want %v", denom, err, tokens.ErrBuildTxErrorAndDelay)
+		}
+	}
+
+	// and resumed if the params are restored
+	sendEnabled = true
+	channelState = "STATE_OPEN"
+	changes, err = b.WatchChainParams()
+	if err != nil || len(changes) != 2 {
+		t.Errorf("watch restored chain params got (%v, %v), want 2 changes", changes, err)
+	}
+	if err = b.checkWatchedParams("uatom"); err != nil {
+		t.Errorf("check restored watched params got error %v", err)
+	}
+}
+
+func TestAdjustFeeToMinGasPrice(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: "cosmos-min-gas-price-test"})
+
+	newExtra := func(fee string) *tokens.AllExtras {
+		gas := uint64(200000)
+		return &tokens.AllExtras{Gas: &gas, Fee: &fee}
+	}
+	// not adjusted if not watched
+	extra := newExtra("1000uatom")
+	b.adjustFeeToMinGasPrice(extra)
+	if *extra.Fee != "1000uatom" {
+		t.Errorf("fee without watched params got %v, want 1000uatom", *extra.Fee)
+	}
+
+	b.watchedParams = &watchedParams{}
+	b.watchedParams.minGasPrices = sdk.NewDecCoins(sdk.NewDecCoinFromDec("uatom", sdk.NewDecWithPrec(25, 3)))
+	tests := []struct {
+		fee  string
+		want string
+	}{
+		{"1000uatom", "5000uatom"},
+		{"8000uatom", "8000uatom"},
+		// fee of other denoms is not adjusted
+		{"1000uosmo", "1000uosmo"},
+	}
+	for i, test := range tests {
+		extra = newExtra(test.fee)
+		b.adjustFeeToMinGasPrice(extra)
+		if *extra.Fee != test.want {
+			t.Errorf("test %v: adjust fee %v got %v, want %v", i, test.fee, *extra.Fee, test.want)
+		}
+	}
+}
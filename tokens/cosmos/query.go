@@ -2,7 +2,6 @@ package cosmos
 
 import (
 	"encoding/json"
-	"fmt"
 	"strconv"
 	"strings"
 
@@ -20,6 +19,14 @@ const (
 	Balances    = "/cosmos/bank/v1beta1/balances/"
 	SimulateTx  = "/cosmos/tx/v1beta1/simulate"
 	BroadTx     = "/cosmos/tx/v1beta1/txs"
+
+	BankParamsPath = "/cosmos/bank/v1beta1/params"
+	NodeConfigPath = "/cosmos/base/node/v1beta1/config"
+	DenomTracePath = "/ibc/apps/transfer/v1beta1/denom_traces/"
+	ChannelPath    = "/ibc/core/channel/v1/channels/%v/ports/%v"
+
+	// BlockHeightHeader pins the rest queries to the specified block height
+	BlockHeightHeader = "x-cosmos-block-height"
 )
 
 var wrapRPCQueryError = tokens.WrapRPCQueryError
@@ -32,6 +39,17 @@ func joinURLPath(url, path string) string {
 	return url + path
 }
 
+// restGetAtHeight query rest api at the specified block height (0 means latest)
+func restGetAtHeight(result interface{}, url string, height uint64) error {
+	if height == 0 {
+		return client.RPCGet(result, url)
+	}
+	headers := map[string]string{
+		BlockHeightHeader: strconv.FormatUint(height, 10),
+	}
+	return client.RPCGetRequest(result, url, nil, headers, 60)
+}
+
 func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
 	if result, err := b.GRPCGetLatestBlockNumber(); err == nil {
 		return result, nil
@@ -52,7 +70,7 @@ func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
 }
 
 func (b *Bridge) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
-	if _, exist := rpcClientsMap[apiAddress]; exist {
+	if _, exist := b.chainClient.GetGRPCClient(apiAddress); exist {
 		if result, err := b.GRPCGetLatestBlockNumberOf(apiAddress); err == nil {
 			return result, nil
 		} else if len(b.GatewayConfig.AllGatewayURLs) == 0 {
@@ -86,7 +104,29 @@ func (b *Bridge) GetChainID() (string, error) {
 }
 
 func (b *Bridge) GetTransactionByHash(txHash string) (*GetTxResponse, error) {
-	if result, err := b.GRPCGetTransactionByHash(txHash); err == nil {
+	return b.GetTransactionByHashAtHeight(txHash, 0)
+}
+
+// GetTransactionByHashAtHeight get transaction as observed at the specified block height (0 means latest).
+// transactions included after the pinned height are treated as not found.
+func (b *Bridge) GetTransactionByHashAtHeight(txHash string, height uint64) (*GetTxResponse, error) {
+	result, err := b.getTransactionByHash(txHash, height)
+	if err != nil || height == 0 {
+		return result, err
+	}
+	if result.TxResponse == nil {
+		return nil, tokens.ErrTxNotFound
+	}
+	txHeight, err := strconv.ParseUint(result.TxResponse.Height, 10, 64)
+	if err != nil || txHeight > height {
+		log.Warn("tx is not included at pinned height", "txHash", txHash, "txHeight", result.TxResponse.Height, "pinnedHeight", height)
+		return nil, tokens.ErrTxNotFound
+	}
+	return result, nil
+}
+
+func (b *Bridge) getTransactionByHash(txHash string, height uint64) (*GetTxResponse, error) {
+	if result, err := b.GRPCGetTransactionByHash(txHash, height); err == nil {
 		return result, nil
 	} else if len(b.GatewayConfig.AllGatewayURLs) == 0 {
 		return nil, err
@@ -95,7 +135,7 @@ func (b *Bridge) GetTransactionByHash(txHash string) (*GetTxResponse, error) {
 	var err error
 	for _, url := range b.GatewayConfig.AllGatewayURLs {
 		restApi := joinURLPath(url, TxByHash+txHash)
-		if err = client.RPCGet(&result, restApi); err == nil {
+		if err = restGetAtHeight(&result, restApi, height); err == nil {
 			return result, nil
 		}
 	}
@@ -103,7 +143,12 @@ func (b *Bridge) GetTransactionByHash(txHash string) (*GetTxResponse, error) {
 }
 
 func (b *Bridge) GetBaseAccount(address string) (*QueryAccountResponse, error) {
-	if result, err := b.GRPCGetBaseAccount(address); err == nil {
+	return b.GetBaseAccountAtHeight(address, 0)
+}
+
+// GetBaseAccountAtHeight get account info at the specified block height (0 means latest)
+func (b *Bridge) GetBaseAccountAtHeight(address string, height uint64) (*QueryAccountResponse, error) {
+	if result, err := b.GRPCGetBaseAccount(address, height); err == nil {
 		return result, nil
 	} else if len(b.GatewayConfig.AllGatewayURLs) == 0 {
 		return nil, err
@@ -112,17 +157,22 @@ func (b *Bridge) GetBaseAccount(address string) (*QueryAccountResponse, error) {
 	var err error
 	for _, url := range b.GatewayConfig.AllGatewayURLs {
 		restApi := joinURLPath(url, AccountInfo+address)
-		if err = client.RPCGet(&result, restApi); err == nil {
+		if err = restGetAtHeight(&result, restApi, height); err == nil {
 			return result, nil
 		} else {
-			log.Warn("GetBaseAccount failed", "url", restApi, "err", err)
+			log.Warn("GetBaseAccount failed", "url", restApi, "height", height, "err", err)
 		}
 	}
 	return nil, wrapRPCQueryError(err, "GetBaseAccount")
 }
 
 func (b *Bridge) GetDenomBalance(address, denom string) (sdk.Int, error) {
-	if result, err := b.GRPCGetDenomBalance(address, denom); err == nil {
+	return b.GetDenomBalanceAtHeight(address, denom, 0)
+}
+
+// GetDenomBalanceAtHeight get denom balance at the specified block height (0 means latest)
+func (b *Bridge) GetDenomBalanceAtHeight(address, denom string, height uint64) (sdk.Int, error) {
+	if result, err := b.GRPCGetDenomBalance(address, denom, height); err == nil {
 		return result, nil
 	} else if len(b.GatewayConfig.AllGatewayURLs) == 0 {
 		return sdk.ZeroInt(), err
@@ -131,7 +181,7 @@ func (b *Bridge) GetDenomBalance(address, denom string) (sdk.Int, error) {
 	var err error
 	for _, url := range b.GatewayConfig.AllGatewayURLs {
 		restApi := joinURLPath(url, Balances+address)
-		if err = client.RPCGet(&result, restApi); err == nil {
+		if err = restGetAtHeight(&result, restApi, height); err == nil {
 			for _, coin := range result.Balances {
 				if coin.Denom == denom {
 					return coin.Amount, nil
@@ -139,7 +189,7 @@ func (b *Bridge) GetDenomBalance(address, denom string) (sdk.Int, error) {
 			}
 			return sdk.ZeroInt(), nil
 		} else {
-			log.Warn("GetDenomBalance failed", "url", restApi, "err", err)
+			log.Warn("GetDenomBalance failed", "url", restApi, "height", height, "err", err)
 		}
 	}
 	return sdk.ZeroInt(), wrapRPCQueryError(err, "GetDenomBalance")
@@ -163,35 +213,3 @@ func (b *Bridge) SimulateTx(simulateReq *SimulateRequest) (string, error) {
 		return "", wrapRPCQueryError(err, "SimulateTx")
 	}
 }
-
-func (b *Bridge) BroadcastTx(req *BroadcastTxRequest) (string, error) {
-	if result, err := b.GRPCBroadcastTx(req); err == nil {
-		data, _ := json.Marshal(BroadcastTxResponse{
-			TxResponse: &TxResponse{
-				Height: fmt.Sprintf("%d", result.Height),
-				TxHash: result.TxHash,
-				Code:   result.Code,
-				Logs:   result.Logs,
-			},
-		})
-		return string(data), nil
-	} else if len(b.GatewayConfig.AllGatewayURLs) == 0 {
-		return "", err
-	}
-	if data, err := json.Marshal(req); err != nil {
-		return "", err
-	} else {
-		var res string
-		var success bool
-		for _, url := range b.GatewayConfig.AllGatewayURLs {
-			restApi := joinURLPath(url, BroadTx)
-			if res, err = client.RPCJsonPostWithTimeout(restApi, string(data), 120); err == nil {
-				success = true
-			}
-		}
-		if success {
-			return res, nil
-		}
-		return "", wrapRPCQueryError(err, "BroadcastTx")
-	}
-}
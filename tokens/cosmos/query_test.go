@@ -0,0 +1,63 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestHeightContext(t *testing.T) {
+	if _, exist := metadata.FromOutgoingContext(heightContext(0)); exist {
+		t.Errorf("latest height context should not have metadata")
+	}
+	md, _ := metadata.FromOutgoingContext(heightContext(100))
+	if heights := md.Get(grpctypes.GRPCBlockHeightHeader); len(heights) != 1 || heights[0] != "100" {
+		t.Errorf("pinned height context got metadata %v, want 100", heights)
+	}
+}
+
+func TestGetTransactionByHashAtHeight(t *testing.T) {
+	var pinnedHeights []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pinnedHeights = append(pinnedHeights, r.Header.Get(BlockHeightHeader))
+		_ = json.NewEncoder(w).Encode(&GetTxResponse{
+			Tx:         &Tx{},
+			TxResponse: &TxResponse{Height: "100", TxHash: "TXHASH"},
+		})
+	}))
+	defer server.Close()
+
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: "cosmos-pinned-height-test"})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{server.URL}})
+
+	tests := []struct {
+		height   uint64
+		header   string
+		notFound bool
+	}{
+		{0, "", false},
+		{100, "100", false},
+		{120, "120", false},
+		{99, "99", true},
+	}
+	for i, test := range tests {
+		res, err := b.GetTransactionByHashAtHeight("TXHASH", test.height)
+		if test.notFound {
+			if !errors.Is(err, tokens.ErrTxNotFound) {
+				t.Errorf("get tx at height %v got error %v, want %v", test.height, err, tokens.ErrTxNotFound)
+			}
+		} else if err != nil || res.TxResponse.TxHash != "TXHASH" {
+			t.Errorf("get tx at height %v got (%+v, %v)", test.height, res, err)
+		}
+		if len(pinnedHeights) != i+1 || pinnedHeights[i] != test.header {
+			t.Errorf("get tx at height %v got header %v, want %q", test.height, pinnedHeights, test.header)
+		}
+	}
+}
@@ -5,11 +5,16 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 )
 
 // SendTransaction send signed tx
 func (b *Bridge) SendTransaction(signedTx interface{}) (string, error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	if txBytes, ok := signedTx.([]byte); !ok {
 		return "", errors.New("wrong signed transaction type")
 	} else {
@@ -28,9 +33,55 @@ func (b *Bridge) SendTransaction(signedTx interface{}) (string, error) {
 				return "", err
 			}
 			if txResponse.TxResponse.Code != 0 && txResponse.TxResponse.Code != 19 {
-				return "", fmt.Errorf("SendTransaction error, code: %v", txResponse.TxResponse.Code)
+				return "", &sendTxCodeError{code: txResponse.TxResponse.Code}
 			}
 			return txResponse.TxResponse.TxHash, nil
 		}
 	}
 }
+
+// SendTransactions impl tokens.BatchSender.
+// the signed txs of one batch are broadcasted in order (of their sequences),
+// and each member's result is reported on its own.
+// (a multi-msg tx needs all the msgs to be signed together, so the individually
+// signed txs of the swaps can not be merged into one tx)
+func (b *Bridge) SendTransactions(signedTxs []interface{}) []*tokens.BatchSendResult {
+	results := make([]*tokens.BatchSendResult, len(signedTxs))
+	for i, signedTx := range signedTxs {
+		txHash, err := b.SendTransaction(signedTx)
+		results[i] = &tokens.BatchSendResult{TxHash: txHash, Err: err}
+	}
+	return results
+}
+
+// sendTxCodeError send tx error with abci code of the root codespace
+type sendTxCodeError struct {
+	code uint32
+}
+
+func (e *sendTxCodeError) Error() string {
+	return fmt.Sprintf("SendTransaction error, code: %v", e.code)
+}
+
+// MapSendTxError impl, classify errors by abci codes of the root codespace
+func (b *Bridge) MapSendTxError(err error) tokens.SendTxErrorClass {
+	var codeErr *sendTxCodeError
+	if !errors.As(err, &codeErr) {
+		return tokens.SendTxErrUnknown
+	}
+	switch codeErr.code {
+	case sdkerrors.ErrTxInMempoolCache.ABCICode():
+		return tokens.SendTxErrAlreadyKnown
+	case sdkerrors.ErrWrongSequence.ABCICode():
+		return tokens.SendTxErrNonceTooLow
+	case sdkerrors.ErrInsufficientFee.ABCICode(),
+		sdkerrors.ErrOutOfGas.ABCICode():
+		return tokens.SendTxErrOutOfGas
+	case sdkerrors.ErrInsufficientFunds.ABCICode():
+		return tokens.SendTxErrInsufficientFunds
+	case sdkerrors.ErrMempoolIsFull.ABCICode():
+		return tokens.SendTxErrTemporary
+	default:
+		return tokens.SendTxErrUnknown
+	}
+}
@@ -0,0 +1,65 @@
+package cosmos
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+func TestSendTransactions(t *testing.T) {
+	var broadcasted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req BroadcastTxRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode broadcast request failed: %v", err)
+			return
+		}
+		txBytes, _ := base64.StdEncoding.DecodeString(req.TxBytes)
+		broadcasted = append(broadcasted, string(txBytes))
+		res := &TxResponse{TxHash: fmt.Sprintf("%X", sha256.Sum256(txBytes))}
+		if string(txBytes) == "tx2" {
+			res.Code = sdkerrors.ErrWrongSequence.ABCICode()
+		}
+		_ = json.NewEncoder(w).Encode(&BroadcastTxResponse{TxResponse: res})
+	}))
+	defer server.Close()
+
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: "osmosis-1"})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{server.URL}})
+
+	var signedTxs []interface{}
+	for _, tx := range []string{"tx1", "tx2", "tx3"} {
+		signedTxs = append(signedTxs, []byte(base64.StdEncoding.EncodeToString([]byte(tx))))
+	}
+	signedTxs = append(signedTxs, "not a tx")
+
+	results := b.SendTransactions(signedTxs)
+	if len(results) != len(signedTxs) {
+		t.Fatalf("results count got %v, want %v", len(results), len(signedTxs))
+	}
+	// members are broadcasted in order
+	if len(broadcasted) != 3 || broadcasted[0] != "tx1" || broadcasted[1] != "tx2" || broadcasted[2] != "tx3" {
+		t.Errorf("broadcasted txs got %v, want [tx1 tx2 tx3]", broadcasted)
+	}
+	for _, i := range []int{0, 2} {
+		want := fmt.Sprintf("%X", sha256.Sum256([]byte(broadcasted[i])))
+		if results[i].Err != nil || results[i].TxHash != want {
+			t.Errorf("member %v got (%v, %v), want %v", i, results[i].TxHash, results[i].Err, want)
+		}
+	}
+	// the rejected member does not fail the others
+	if class := b.MapSendTxError(results[1].Err); class != tokens.SendTxErrNonceTooLow {
+		t.Errorf("member 1 got error %v (class %v), want wrong sequence", results[1].Err, class)
+	}
+	if results[3].Err == nil {
+		t.Errorf("member 3 of wrong type should fail")
+	}
+}
@@ -13,13 +13,34 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+// chainFamily chain family in the mpc sign scheme registry
+const chainFamily = "cosmos"
+
+func init() {
+	mpc.RegisterChainFamily(chainFamily, mpc.SchemeSecp256k1Sha256)
+}
+
+// GetMPCSignKey impl tokens.MPCSchemeSigner
+func (b *Bridge) GetMPCSignKey(mpc string) (family, signPubkey string, err error) {
+	mpcPubkey := router.GetMPCPublicKey(mpc)
+	if mpcPubkey == "" {
+		return "", "", tokens.ErrMissMPCPublicKey
+	}
+	return chainFamily, mpcPubkey, nil
+}
+
 // MPCSignTransaction mpc sign raw tx
 func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signedTx interface{}, txHash string, err error) {
 	if buildRawTx, ok := rawTx.(*BuildRawTx); !ok {
 		return nil, txHash, errors.New("wrong raw tx param")
 	} else {
+		if err := b.checkDestMethods(buildRawTx); err != nil {
+			return nil, "", err
+		}
+
 		mpcParams := params.GetMPCConfig(b.UseFastMPC)
 		if mpcParams.SignWithPrivateKey {
 			priKey := mpcParams.GetSignerPrivateKey(b.ChainConfig.ChainID)
@@ -45,29 +66,12 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 			log.Info(logPrefix+"start", "txid", txid)
 
 			mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
-			msgHash := fmt.Sprintf("%X", Sha256Sum(signBytes))
-			if keyID, rsvs, err := mpcConfig.DoSignOneEC(mpcPubkey, msgHash, msgContext); err != nil {
+			if keyID, _, signature, err := mpcConfig.DoSignMessageWithScheme(chainFamily, mpcPubkey, signBytes, msgContext); err != nil {
 				log.Info(logPrefix+"failed", "keyID", keyID, "txid", txid, "err", err)
 				return nil, "", err
 			} else {
-				if len(rsvs) != 1 {
-					log.Warn("get sign status require one rsv but return many",
-						"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
-					return nil, "", errors.New("get sign status require one rsv but return many")
-				}
-
-				rsv := rsvs[0]
-				log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", rsv)
-				signature := common.FromHex(rsv)
-
-				if len(signature) == crypto.SignatureLength {
-					signature = signature[:crypto.SignatureLength-1]
-				}
-
-				if len(signature) != crypto.SignatureLength-1 {
-					log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(signature), "want", crypto.SignatureLength)
-					return nil, "", errors.New("wrong signature length")
-				}
+				log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", common.ToHex(signature))
+				signature = signature[:crypto.SignatureLength-1]
 
 				if !pubKey.VerifySignature(signBytes, signature) {
 					log.Error("verify signature failed", "signBytes", common.ToHex(signBytes), "signature", signature)
@@ -128,3 +132,14 @@ func (b *Bridge) SignTransactionWithPrivateKey(buildRawTx *BuildRawTx, privKey s
 		}
 	}
 }
+
+// checkDestMethods check message types of tx are all in allowlist
+func (b *Bridge) checkDestMethods(buildRawTx *BuildRawTx) error {
+	for _, msg := range buildRawTx.TxBuilder.GetTx().GetMsgs() {
+		msgType := sdk.MsgTypeURL(msg)
+		if !params.IsDestMethodAllowed(b.ChainConfig.ChainID, msgType) {
+			return fmt.Errorf("%w: %v", tokens.ErrDestMethodNotAllowed, msgType)
+		}
+	}
+	return nil
+}
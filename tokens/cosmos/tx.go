@@ -58,58 +58,70 @@ func (b *Bridge) BuildTx(
 	from := args.From
 	extra := args.Extra
 	log.Info("start to build tx", "swapID", args.SwapID, "from", from, "to", to, "denom", denom, "memo", memo, "amount", amount, "fee", *extra.Fee, "gas", *extra.Gas, "sequence", *extra.Sequence)
-	if balance, err := b.GetDenomBalance(from, denom); err != nil {
-		return nil, err
-	} else {
-		var msgs []sdk.Msg
-		if balance.BigInt().Cmp(amount) >= 0 {
-			sendMsg := BuildSendMsg(from, to, denom, amount)
-			msgs = append(msgs, sendMsg)
-		} else {
-			log.Info("balance not enough", "denom", denom, "balance", balance, "amount", amount)
-			return nil, tokens.ErrBalanceNotEnough
-		}
+	receivers := []string{to}
+	amounts := []*big.Int{amount}
 
-		// process charge fee on dest chain
-		tokenID := args.GetTokenID()
-		fromChainID := args.FromChainID
-		toChainID := args.ToChainID
-		if params.ChargeFeeOnDestChain(tokenID, fromChainID.String(), toChainID.String()) {
-			if extra.BridgeFee != nil && extra.BridgeFee.Sign() > 0 {
-				bridgeFeeReceiver := params.FeeReceiverOnDestChain(toChainID.String())
-				if bridgeFeeReceiver != "" {
-					sendMsg := BuildSendMsg(from, bridgeFeeReceiver, denom, extra.BridgeFee)
-					msgs = append(msgs, sendMsg)
-					log.Info("build charge fee on dest chain", "swapID", args.SwapID, "from", from, "receiver", bridgeFeeReceiver, "denom", denom, "amount", extra.BridgeFee)
-				}
+	// process charge fee on dest chain
+	tokenID := args.GetTokenID()
+	fromChainID := args.FromChainID
+	toChainID := args.ToChainID
+	if params.ChargeFeeOnDestChain(tokenID, fromChainID.String(), toChainID.String()) {
+		if extra.BridgeFee != nil && extra.BridgeFee.Sign() > 0 {
+			bridgeFeeReceiver := params.FeeReceiverOnDestChain(toChainID.String())
+			if bridgeFeeReceiver != "" {
+				receivers = append(receivers, bridgeFeeReceiver)
+				amounts = append(amounts, extra.BridgeFee)
+				log.Info("build charge fee on dest chain", "swapID", args.SwapID, "from", from, "receiver", bridgeFeeReceiver, "denom", denom, "amount", extra.BridgeFee)
 			}
 		}
+	}
 
-		txBuilder := b.TxConfig.NewTxBuilder()
-		if err := txBuilder.SetMsgs(msgs...); err != nil {
-			return nil, err
-		}
-		txBuilder.SetMemo(memo)
-		if fee, err := ParseCoinsFee(*extra.Fee); err != nil {
-			return nil, err
-		} else {
-			txBuilder.SetFeeAmount(fee)
-		}
-		txBuilder.SetGasLimit(*extra.Gas)
-		pubKey, err := PubKeyFromStr(publicKey)
+	var msgs []sdk.Msg
+	if b.getMintBurnKind(denom) != "" {
+		mintBurnMsgs, err := b.buildMintBurnMsgs(args, denom, receivers, amounts)
 		if err != nil {
 			return nil, err
 		}
-		sig := BuildSignatures(pubKey, *extra.Sequence, nil)
-		if err := txBuilder.SetSignatures(sig); err != nil {
-			return nil, err
-		}
-		if err := txBuilder.GetTx().ValidateBasic(); err != nil {
-			return nil, err
+		log.Info("build mint burn msgs", "swapID", args.SwapID, "denom", denom, "burnAmount", extra.BurnAmount)
+		msgs = append(msgs, mintBurnMsgs...)
+	} else if balance, err := b.GetDenomBalance(from, denom); err != nil {
+		return nil, err
+	} else {
+		if balance.BigInt().Cmp(amount) >= 0 {
+			for i, receiver := range receivers {
+				sendMsg := BuildSendMsg(from, receiver, denom, amounts[i])
+				msgs = append(msgs, sendMsg)
+			}
+		} else {
+			log.Info("balance not enough", "denom", denom, "balance", balance, "amount", amount)
+			return nil, tokens.ErrBalanceNotEnough
 		}
+	}
 
-		return txBuilder, nil
+	txBuilder := b.TxConfig.NewTxBuilder()
+	if err := txBuilder.SetMsgs(msgs...); err != nil {
+		return nil, err
 	}
+	txBuilder.SetMemo(memo)
+	if fee, err := ParseCoinsFee(*extra.Fee); err != nil {
+		return nil, err
+	} else {
+		txBuilder.SetFeeAmount(fee)
+	}
+	txBuilder.SetGasLimit(*extra.Gas)
+	pubKey, err := PubKeyFromStr(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	sig := BuildSignatures(pubKey, *extra.Sequence, nil)
+	if err := txBuilder.SetSignatures(sig); err != nil {
+		return nil, err
+	}
+	if err := txBuilder.GetTx().ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	return txBuilder, nil
 }
 
 func (b *Bridge) GetSignBytes(tx *BuildRawTx) ([]byte, error) {
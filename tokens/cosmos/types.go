@@ -45,13 +45,26 @@ type TxResponse struct {
 	TxHash string `protobuf:"bytes,2,opt,name=txhash,proto3" json:"txhash,omitempty"`
 	// Response code.
 	Code uint32 `protobuf:"varint,4,opt,name=code,proto3" json:"code,omitempty"`
+	// The output of the application's logger (raw string). May be non-deterministic.
+	RawLog string `protobuf:"bytes,6,opt,name=raw_log,json=rawLog,proto3" json:"raw_log,omitempty"`
 	// The output of the application's logger (typed). May be non-deterministic.
 	Logs sdk.ABCIMessageLogs `protobuf:"bytes,7,rep,name=logs,proto3,castrepeated=ABCIMessageLogs" json:"logs"`
 }
 
 // Tx tx
 type Tx struct {
-	Body TxBody `protobuf:"bytes,1,opt,name=body,proto3" json:"body,omitempty"`
+	Body     TxBody     `protobuf:"bytes,1,opt,name=body,proto3" json:"body,omitempty"`
+	AuthInfo TxAuthInfo `protobuf:"bytes,2,opt,name=auth_info,json=authInfo,proto3" json:"auth_info,omitempty"`
+}
+
+// TxAuthInfo tx auth info
+type TxAuthInfo struct {
+	Fee TxFee `protobuf:"bytes,2,opt,name=fee,proto3" json:"fee,omitempty"`
+}
+
+// TxFee tx fee
+type TxFee struct {
+	Amount sdk.Coins `protobuf:"bytes,1,rep,name=amount,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins" json:"amount"`
 }
 
 type TxBody struct {
@@ -59,6 +72,13 @@ type TxBody struct {
 	// WARNING: in clients, any publicly exposed text should not be called memo,
 	// but should be called `note` instead (see https://github.com/cosmos/cosmos-sdk/issues/9122).
 	Memo string `protobuf:"bytes,2,opt,name=memo,proto3" json:"memo,omitempty"`
+	// messages is a list of messages to be executed.
+	Messages []TxMessage `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+}
+
+// TxMessage tx message (only the type url is decoded)
+type TxMessage struct {
+	Type string `json:"@type"`
 }
 
 // SimulateRequest is the request type for the Service.Simulate
@@ -67,6 +87,53 @@ type SimulateRequest struct {
 	TxBytes string `json:"tx_bytes"`
 }
 
+// QueryBankParamsResponse bank params response
+type QueryBankParamsResponse struct {
+	Params BankParams `json:"params"`
+}
+
+// BankParams bank params
+type BankParams struct {
+	SendEnabled        []*SendEnabled `json:"send_enabled"`
+	DefaultSendEnabled bool           `json:"default_send_enabled"`
+}
+
+// SendEnabled send enabled flag of denom
+type SendEnabled struct {
+	Denom   string `json:"denom"`
+	Enabled bool   `json:"enabled"`
+}
+
+// IsSendEnabled is sending denom enabled
+func (p *BankParams) IsSendEnabled(denom string) bool {
+	for _, item := range p.SendEnabled {
+		if item.Denom == denom {
+			return item.Enabled
+		}
+	}
+	return p.DefaultSendEnabled
+}
+
+// QueryNodeConfigResponse node config response
+type QueryNodeConfigResponse struct {
+	MinimumGasPrice string `json:"minimum_gas_price"`
+}
+
+// QueryDenomTraceResponse ibc denom trace response
+type QueryDenomTraceResponse struct {
+	DenomTrace struct {
+		Path      string `json:"path"`
+		BaseDenom string `json:"base_denom"`
+	} `json:"denom_trace"`
+}
+
+// QueryChannelResponse ibc channel response
+type QueryChannelResponse struct {
+	Channel struct {
+		State string `json:"state"`
+	} `json:"channel"`
+}
+
 type BroadcastTxRequest struct {
 	TxBytes string `json:"tx_bytes"`
 	Mode    string `json:"mode"`
@@ -8,8 +8,10 @@ import (
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/payload"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -17,6 +19,9 @@ const (
 	TransferType = "transfer"
 )
 
+// DefaultAllowedMsgTypes default message types allowed in deposit txs
+var DefaultAllowedMsgTypes = []string{"/cosmos.bank.v1beta1.MsgSend"}
+
 // VerifyMsgHash verify msg hash
 func (b *Bridge) VerifyMsgHash(tx interface{}, msgHashes []string) (err error) {
 	if len(msgHashes) < 1 {
@@ -44,24 +49,25 @@ func (b *Bridge) VerifyTransaction(txHash string, args *tokens.VerifyArgs) (*tok
 	swapType := args.SwapType
 	logIndex := args.LogIndex
 	allowUnstable := args.AllowUnstable
+	pinnedHeight := args.PinnedHeight
 
 	switch swapType {
 	case tokens.ERC20SwapType:
-		return b.verifySwapoutTx(txHash, logIndex, allowUnstable)
+		return b.verifySwapoutTx(txHash, logIndex, allowUnstable, pinnedHeight)
 	default:
 		return nil, tokens.ErrSwapTypeNotSupported
 	}
 }
 
-func (b *Bridge) verifySwapoutTx(txHash string, logIndex int, allowUnstable bool) (*tokens.SwapTxInfo, error) {
+func (b *Bridge) verifySwapoutTx(txHash string, logIndex int, allowUnstable bool, pinnedHeight uint64) (*tokens.SwapTxInfo, error) {
 	swapInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}}
 	swapInfo.SwapType = tokens.ERC20SwapType          // SwapType
 	swapInfo.Hash = txHash                            // Hash
 	swapInfo.LogIndex = logIndex                      // LogIndex
 	swapInfo.FromChainID = b.ChainConfig.GetChainID() // FromChainID
 
-	if txr, err := b.GetTransactionByHash(txHash); err != nil {
-		log.Debug("[verifySwapin] "+b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "err", err)
+	if txr, err := b.GetTransactionByHashAtHeight(txHash, pinnedHeight); err != nil {
+		log.Debug("[verifySwapin] "+b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "pinnedHeight", pinnedHeight, "err", err)
 		return swapInfo, tokens.ErrTxNotFound
 	} else {
 		if txHeight, err := b.checkTxStatus(txr, allowUnstable); err != nil {
@@ -70,6 +76,10 @@ func (b *Bridge) verifySwapoutTx(txHash string, logIndex int, allowUnstable bool
 			swapInfo.Height = txHeight // Height
 		}
 
+		if err := b.checkTxMessages(txr); err != nil {
+			return swapInfo, err
+		}
+
 		if err := ParseMemo(swapInfo, txr.Tx.Body.Memo); err != nil {
 			return swapInfo, err
 		}
@@ -82,6 +92,12 @@ func (b *Bridge) verifySwapoutTx(txHash string, logIndex int, allowUnstable bool
 			return swapInfo, checkErr
 		}
 
+		if !allowUnstable && router.IsBigValueSwap(swapInfo) {
+			if err := b.verifyByLightClient(txr, swapInfo); err != nil {
+				return swapInfo, err
+			}
+		}
+
 		if !allowUnstable {
 			log.Info("verify swapout pass",
 				"token", swapInfo.ERC20SwapInfo.Token, "from", swapInfo.From, "to", swapInfo.To,
@@ -158,21 +174,57 @@ func (b *Bridge) checkTxStatus(txres *GetTxResponse, allowUnstable bool) (txHeig
 	}
 }
 
-func ParseMemo(swapInfo *tokens.SwapTxInfo, memo string) error {
-	fields := strings.Split(memo, ":")
-	if len(fields) == 2 {
-		if toChainID, err := common.GetBigIntFromStr(fields[1]); err != nil {
-			return err
-		} else {
-			dstBridge := router.GetBridgeByChainID(toChainID.String())
-			if dstBridge != nil && dstBridge.IsValidAddress(fields[0]) {
-				swapInfo.Bind = fields[0]      // Bind
-				swapInfo.ToChainID = toChainID // ToChainID
-				swapInfo.To = swapInfo.Bind    // To
-				return nil
-			}
+func (b *Bridge) getAllowedMsgTypes() []string {
+	allowedMsgTypes := params.GetLocalChainConfig(b.ChainConfig.ChainID).AllowedMsgTypes
+	if len(allowedMsgTypes) == 0 {
+		allowedMsgTypes = DefaultAllowedMsgTypes
+	}
+	return allowedMsgTypes
+}
+
+func isAllowedMsgType(msgType string, allowedMsgTypes []string) bool {
+	for _, allowed := range allowedMsgTypes {
+		if msgType == allowed {
+			return true
 		}
 	}
+	return false
+}
+
+// checkTxMessages only credit txs containing exclusively allowed message types,
+// to reject txs bundling authz exec or multisend to confuse amount attribution.
+func (b *Bridge) checkTxMessages(txr *GetTxResponse) error {
+	if txr.Tx == nil || len(txr.Tx.Body.Messages) == 0 {
+		return fmt.Errorf("%w: no messages", tokens.ErrTxWithWrongMsgType)
+	}
+	allowedMsgTypes := b.getAllowedMsgTypes()
+	for _, msg := range txr.Tx.Body.Messages {
+		if !isAllowedMsgType(msg.Type, allowedMsgTypes) {
+			log.Warn("tx with not allowed message type", "chainID", b.ChainConfig.ChainID, "txHash", txr.TxResponse.TxHash, "msgType", msg.Type, "allowed", allowedMsgTypes)
+			return fmt.Errorf("%w: %v", tokens.ErrTxWithWrongMsgType, msg.Type)
+		}
+	}
+	return nil
+}
+
+// ParseMemo parse memo of `bind:toChainID` or json object (payload format json/v1),
+// the hash of json memo is the sha256 of its canonical json (see payload.CanonicalJSON).
+func ParseMemo(swapInfo *tokens.SwapTxInfo, memo string) error {
+	format := payload.FormatTextV1
+	if strings.HasPrefix(memo, "{") {
+		format = payload.FormatJSONV1
+	}
+	p, err := payload.Decode(format, []byte(memo))
+	if err != nil {
+		return fmt.Errorf("%w: %v", tokens.ErrTxWithWrongMemo, err)
+	}
+	dstBridge := router.GetBridgeByChainID(p.ToChainID.String())
+	if dstBridge != nil && dstBridge.IsValidAddress(p.Bind) {
+		swapInfo.Bind = p.Bind           // Bind
+		swapInfo.ToChainID = p.ToChainID // ToChainID
+		swapInfo.To = swapInfo.Bind      // To
+		return nil
+	}
 	return tokens.ErrTxWithWrongMemo
 }
 
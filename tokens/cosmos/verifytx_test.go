@@ -0,0 +1,84 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	testMsgSend      = "/cosmos.bank.v1beta1.MsgSend"
+	testMsgMultiSend = "/cosmos.bank.v1beta1.MsgMultiSend"
+	testMsgExec      = "/cosmos.authz.v1beta1.MsgExec"
+)
+
+func newTestTxWithMsgs(msgTypes ...string) *GetTxResponse {
+	msgs := make([]TxMessage, 0, len(msgTypes))
+	for _, msgType := range msgTypes {
+		msgs = append(msgs, TxMessage{Type: msgType})
+	}
+	return &GetTxResponse{
+		Tx:         &Tx{Body: TxBody{Messages: msgs}},
+		TxResponse: &TxResponse{TxHash: "TXHASH"},
+	}
+}
+
+func TestCheckTxMessages(t *testing.T) {
+	const chainID = "cosmos-msg-type-test"
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: chainID})
+
+	tests := []struct {
+		txr     *GetTxResponse
+		allowed bool
+	}{
+		{newTestTxWithMsgs(testMsgSend), true},
+		{newTestTxWithMsgs(testMsgSend, testMsgSend), true},
+		{newTestTxWithMsgs(), false},
+		{&GetTxResponse{TxResponse: &TxResponse{}}, false},
+		{newTestTxWithMsgs(testMsgExec), false},
+		{newTestTxWithMsgs(testMsgMultiSend), false},
+		// bundled messages confuse amount attribution
+		{newTestTxWithMsgs(testMsgSend, testMsgExec), false},
+		{newTestTxWithMsgs(testMsgSend, testMsgMultiSend), false},
+	}
+	for i, test := range tests {
+		err := b.checkTxMessages(test.txr)
+		if (err == nil) != test.allowed || (err != nil && !errors.Is(err, tokens.ErrTxWithWrongMsgType)) {
+			t.Errorf("test %v: check tx messages got err %v, want allowed %v", i, err, test.allowed)
+		}
+	}
+
+	// allowed message types are configured per chain
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			chainID: {AllowedMsgTypes: []string{testMsgSend, testMsgMultiSend}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }()
+
+	if err = b.checkTxMessages(newTestTxWithMsgs(testMsgSend, testMsgMultiSend)); err != nil {
+		t.Errorf("check configured message types got err %v", err)
+	}
+	if err = b.checkTxMessages(newTestTxWithMsgs(testMsgMultiSend, testMsgExec)); !errors.Is(err, tokens.ErrTxWithWrongMsgType) {
+		t.Errorf("check not configured message type got err %v, want %v", err, tokens.ErrTxWithWrongMsgType)
+	}
+}
+
+func TestDecodeTxMessages(t *testing.T) {
+	data := `{"tx":{"body":{"messages":[{"@type":"/cosmos.bank.v1beta1.MsgSend","amount":[]},{"@type":"/cosmos.authz.v1beta1.MsgExec"}],"memo":"bind:1"}}}`
+	var txr GetTxResponse
+	if err := json.Unmarshal([]byte(data), &txr); err != nil {
+		t.Fatalf("decode tx failed: %v", err)
+	}
+	msgs := txr.Tx.Body.Messages
+	if len(msgs) != 2 || msgs[0].Type != testMsgSend || msgs[1].Type != testMsgExec {
+		t.Errorf("decoded tx messages got %+v", msgs)
+	}
+}
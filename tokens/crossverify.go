@@ -0,0 +1,56 @@
+package tokens
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+// TxCrossVerifier cross verify tx against every gateway endpoint (eg. quarantined swaps)
+type TxCrossVerifier interface {
+	CrossVerifyTx(txHash string, quorum int) error
+}
+
+// CrossVerifyTx query tx from every url and compare the fingerprints of the results.
+// at least quorum urls (capped at the number of urls) must agree,
+// and any divergence fails the verification.
+func CrossVerifyTx(chainID, txHash string, urls []string, quorum int, fingerprint func(url string) (string, error)) error {
+	if quorum > len(urls) {
+		quorum = len(urls)
+	}
+	if quorum < 1 {
+		quorum = 1
+	}
+	var expected, expectedURL string
+	agreed := 0
+	for _, url := range urls {
+		fp, err := fingerprint(url)
+		if err != nil || fp == "" {
+			log.Warn("cross verify tx query failed", "chainID", chainID, "txid", txHash, "url", url, "err", err)
+			continue
+		}
+		if expected == "" {
+			expected, expectedURL = fp, url
+		} else if fp != expected {
+			log.Error("cross verify tx diverges", "chainID", chainID, "txid", txHash, "url", url, "other", expectedURL, "fingerprint", fp, "expected", expected)
+			return fmt.Errorf("%w: %v differs from %v", ErrReceiptDivergence, url, expectedURL)
+		}
+		agreed++
+	}
+	if agreed < quorum {
+		log.Warn("cross verify tx without enough providers", "chainID", chainID, "txid", txHash, "agreed", agreed, "quorum", quorum, "urls", len(urls))
+		return fmt.Errorf("%w: only %v of %v providers agree on tx, require %v", ErrRPCQueryError, agreed, len(urls), quorum)
+	}
+	log.Info("cross verify tx success", "chainID", chainID, "txid", txHash, "agreed", agreed)
+	return nil
+}
+
+// TxFingerprint fingerprint of the significant fields of tx queried from an url
+func TxFingerprint(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
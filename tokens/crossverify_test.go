@@ -0,0 +1,45 @@
+package tokens
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestFingerprints(results map[string]string) func(url string) (string, error) {
+	return func(url string) (string, error) {
+		fp, exist := results[url]
+		if !exist {
+			return "", ErrTxNotFound
+		}
+		return fp, nil
+	}
+}
+
+func TestCrossVerifyTx(t *testing.T) {
+	urls := []string{"url1", "url2", "url3"}
+
+	agreed := newTestFingerprints(map[string]string{"url1": "tx", "url2": "tx", "url3": "tx"})
+	if err := CrossVerifyTx("1", "0x01", urls, 3, agreed); err != nil {
+		t.Errorf("cross verify agreed tx got error %v", err)
+	}
+	// quorum is capped at the number of urls
+	if err := CrossVerifyTx("1", "0x01", urls, 5, agreed); err != nil {
+		t.Errorf("cross verify with quorum more than urls got error %v", err)
+	}
+
+	diverged := newTestFingerprints(map[string]string{"url1": "tx", "url2": "fake", "url3": "tx"})
+	if err := CrossVerifyTx("1", "0x01", urls, 1, diverged); !errors.Is(err, ErrReceiptDivergence) {
+		t.Errorf("cross verify diverged tx got error %v, want %v", err, ErrReceiptDivergence)
+	}
+
+	partial := newTestFingerprints(map[string]string{"url1": "tx", "url3": "tx"})
+	if err := CrossVerifyTx("1", "0x01", urls, 2, partial); err != nil {
+		t.Errorf("cross verify with quorum of available urls got error %v", err)
+	}
+	if err := CrossVerifyTx("1", "0x01", urls, 3, partial); !errors.Is(err, ErrRPCQueryError) {
+		t.Errorf("cross verify without quorum got error %v, want %v", err, ErrRPCQueryError)
+	}
+	if err := CrossVerifyTx("1", "0x01", nil, 0, partial); !errors.Is(err, ErrRPCQueryError) {
+		t.Errorf("cross verify without urls got error %v, want %v", err, ErrRPCQueryError)
+	}
+}
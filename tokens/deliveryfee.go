@@ -0,0 +1,152 @@
+package tokens
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// delivery stats of tokens are learned from the swap txs finalized on chain
+// (the swap server records its swap results, the oracles record the swap txs
+// they accepted to sign), and scale the minimum swap fees of the token to the
+// destination chain (see params.DeliveryFeeConfig). the stats are persisted by
+// the worker and restored by LoadDeliveryStats after restart.
+var deliveryStats sync.Map // key is tokenID:toChainID, value is *deliveryStat
+
+// min success rate in calculating expected delivery cost
+const minSuccessRate = 0.1
+
+// DeliveryStats moving averages of deliveries of token to chain
+type DeliveryStats struct {
+	TokenID     string  `json:"tokenID"`
+	ToChainID   string  `json:"toChainID"`
+	Samples     int     `json:"samples"`
+	AvgFeePaid  float64 `json:"avgFeePaid"` // smallest unit of the fee coin
+	FailureRate float64 `json:"failureRate"`
+	ReplaceRate float64 `json:"replaceRate"` // replaces per delivery
+}
+
+type deliveryStat struct {
+	lock  sync.Mutex
+	stats DeliveryStats
+}
+
+func getDeliveryStatKey(tokenID, toChainID string) string {
+	return strings.ToLower(tokenID + ":" + toChainID)
+}
+
+// RecordDelivery record the swap tx finalized on chain, feePaid is nil if unknown.
+// returns the updated stats to persist, nil if delivery fee is not enabled.
+func RecordDelivery(tokenID, toChainID string, feePaid *big.Int, replaces int, failed bool) *DeliveryStats {
+	cfg := params.GetDeliveryFeeConfig(toChainID)
+	if cfg == nil || tokenID == "" {
+		return nil
+	}
+	v, _ := deliveryStats.LoadOrStore(getDeliveryStatKey(tokenID, toChainID),
+		&deliveryStat{stats: DeliveryStats{TokenID: tokenID, ToChainID: toChainID}})
+	stat := v.(*deliveryStat)
+	stat.lock.Lock()
+	defer stat.lock.Unlock()
+
+	s := &stat.stats
+	if s.Samples < cfg.GetSamples() {
+		s.Samples++
+	} else {
+		s.Samples = cfg.GetSamples() // the restored window may be larger
+	}
+	// cumulative average until the window is filled, then exponential moving average
+	weight := 1 / float64(s.Samples)
+	failure := 0.0
+	if failed {
+		failure = 1
+	}
+	s.FailureRate += (failure - s.FailureRate) * weight
+	s.ReplaceRate += (float64(replaces) - s.ReplaceRate) * weight
+	if feePaid != nil && feePaid.Sign() > 0 {
+		fee, _ := new(big.Float).SetInt(feePaid).Float64()
+		if s.AvgFeePaid == 0 {
+			s.AvgFeePaid = fee
+		} else {
+			s.AvgFeePaid += (fee - s.AvgFeePaid) * weight
+		}
+	}
+	stats := *s
+	return &stats
+}
+
+// LoadDeliveryStats restore the persisted delivery stats
+func LoadDeliveryStats(stats *DeliveryStats) {
+	if stats == nil || stats.TokenID == "" || stats.ToChainID == "" {
+		return
+	}
+	deliveryStats.Store(getDeliveryStatKey(stats.TokenID, stats.ToChainID), &deliveryStat{stats: *stats})
+}
+
+// GetDeliveryStats get delivery stats of token to chain (nil if not recorded)
+func GetDeliveryStats(tokenID, toChainID string) *DeliveryStats {
+	v, exist := deliveryStats.Load(getDeliveryStatKey(tokenID, toChainID))
+	if !exist {
+		return nil
+	}
+	stat := v.(*deliveryStat)
+	stat.lock.Lock()
+	defer stat.lock.Unlock()
+	stats := stat.stats
+	return &stats
+}
+
+// GetDeliveryFeePercent get percent of minimum swap fees of token to chain
+// scaled by the expected delivery cost, 0 if not enabled or not enough samples.
+func GetDeliveryFeePercent(tokenID, toChainID string) uint64 {
+	cfg := params.GetDeliveryFeeConfig(toChainID)
+	if cfg == nil || cfg.GetBaseCost() == nil {
+		return 0
+	}
+	stats := GetDeliveryStats(tokenID, toChainID)
+	if stats == nil || stats.Samples < cfg.GetMinSamples() || stats.AvgFeePaid == 0 {
+		return 0
+	}
+	expectedCost := stats.AvgFeePaid / math.Max(1-stats.FailureRate, minSuccessRate)
+	expectedCost *= 1 + stats.ReplaceRate*float64(cfg.ReplaceSurcharge)/100
+	baseCost, _ := new(big.Float).SetInt(cfg.GetBaseCost()).Float64()
+	percent := uint64(math.Round(expectedCost * 100 / baseCost))
+	if percent < cfg.GetMinPercent() {
+		return cfg.GetMinPercent()
+	}
+	if percent > cfg.GetMaxPercent() {
+		return cfg.GetMaxPercent()
+	}
+	return percent
+}
+
+// CheckDeliveryFeePercent check the pinned delivery fee percent against our own view.
+// unpinned percent (unscaled fees) is always accepted, as the stats of the initiator
+// are empty for a while after restart.
+func CheckDeliveryFeePercent(tokenID, toChainID string, claimed uint64) error {
+	if claimed == 0 {
+		return nil
+	}
+	cfg := params.GetDeliveryFeeConfig(toChainID)
+	if cfg == nil {
+		return fmt.Errorf("%w: delivery fee of %v to %v is not enabled", ErrDeliveryFeeDeviation, tokenID, toChainID)
+	}
+	if claimed < cfg.GetMinPercent() || claimed > cfg.GetMaxPercent() {
+		return fmt.Errorf("%w: percent %v is not in range [%v, %v]", ErrDeliveryFeeDeviation, claimed, cfg.GetMinPercent(), cfg.GetMaxPercent())
+	}
+	own := GetDeliveryFeePercent(tokenID, toChainID)
+	if own == 0 {
+		return nil // not enough samples of our own
+	}
+	diff := claimed - own
+	if claimed < own {
+		diff = own - claimed
+	}
+	if diff*100 > own*cfg.GetMaxDeviation() {
+		return fmt.Errorf("%w: percent %v, our view %v, max deviation %v%%", ErrDeliveryFeeDeviation, claimed, own, cfg.GetMaxDeviation())
+	}
+	return nil
+}
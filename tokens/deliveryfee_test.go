@@ -0,0 +1,82 @@
+package tokens
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+const (
+	testDeliveryTokenID = "testToken"
+	testDeliveryChainID = "5"
+)
+
+func setTestDeliveryFeeConfig(t *testing.T) {
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			testDeliveryChainID: {DeliveryFee: &params.DeliveryFeeConfig{
+				BaseCost:   "1000",
+				Samples:    4,
+				MinSamples: 2,
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	deliveryStats.Delete(getDeliveryStatKey(testDeliveryTokenID, testDeliveryChainID))
+}
+
+func TestDeliveryFeePercent(t *testing.T) {
+	setTestDeliveryFeeConfig(t)
+
+	stats := RecordDelivery(testDeliveryTokenID, testDeliveryChainID, big.NewInt(2000), 0, false)
+	if stats == nil || stats.Samples != 1 || stats.AvgFeePaid != 2000 {
+		t.Fatalf("record delivery got %+v", stats)
+	}
+	if percent := GetDeliveryFeePercent(testDeliveryTokenID, testDeliveryChainID); percent != 0 {
+		t.Errorf("delivery fee percent with not enough samples got %v, want 0", percent)
+	}
+	// one of two deliveries failed, the expected cost doubles
+	RecordDelivery(testDeliveryTokenID, testDeliveryChainID, big.NewInt(2000), 0, true)
+	if percent := GetDeliveryFeePercent(testDeliveryTokenID, testDeliveryChainID); percent != 300 {
+		t.Errorf("delivery fee percent got %v, want max percent 300", percent)
+	}
+
+	if err := CheckDeliveryFeePercent(testDeliveryTokenID, testDeliveryChainID, 0); err != nil {
+		t.Errorf("unscaled fee should be accepted, err %v", err)
+	}
+	if err := CheckDeliveryFeePercent(testDeliveryTokenID, testDeliveryChainID, 280); err != nil {
+		t.Errorf("percent in max deviation should be accepted, err %v", err)
+	}
+	if err := CheckDeliveryFeePercent(testDeliveryTokenID, testDeliveryChainID, 200); !errors.Is(err, ErrDeliveryFeeDeviation) {
+		t.Errorf("percent out of max deviation got error %v", err)
+	}
+	if err := CheckDeliveryFeePercent(testDeliveryTokenID, testDeliveryChainID, 400); !errors.Is(err, ErrDeliveryFeeDeviation) {
+		t.Errorf("percent out of range got error %v", err)
+	}
+}
+
+func TestLoadDeliveryStats(t *testing.T) {
+	setTestDeliveryFeeConfig(t)
+
+	// restored stats of a larger window are shrunk to the configured window
+	LoadDeliveryStats(&DeliveryStats{
+		TokenID:    testDeliveryTokenID,
+		ToChainID:  testDeliveryChainID,
+		Samples:    10,
+		AvgFeePaid: 1000,
+	})
+	if percent := GetDeliveryFeePercent(testDeliveryTokenID, testDeliveryChainID); percent != 100 {
+		t.Errorf("delivery fee percent of restored stats got %v, want 100", percent)
+	}
+	stats := RecordDelivery(testDeliveryTokenID, testDeliveryChainID, big.NewInt(1400), 1, false)
+	if stats.Samples != 4 || stats.AvgFeePaid != 1100 || stats.ReplaceRate != 0.25 {
+		t.Errorf("record delivery after restore got %+v", stats)
+	}
+	if got := GetDeliveryStats(testDeliveryTokenID, testDeliveryChainID); *got != *stats {
+		t.Errorf("get delivery stats got %+v, want %+v", got, stats)
+	}
+}
@@ -0,0 +1,30 @@
+package tokens
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// GetMethodSelector get 4-byte method selector of input data,
+// the selector of empty input data (native transfer) is '0x'
+func GetMethodSelector(input []byte) string {
+	if len(input) > 4 {
+		input = input[:4]
+	}
+	return common.ToHex(input)
+}
+
+// CheckDestMethods check the methods of built tx are all allowed on the
+// destination chain before mpc signing (see params.IsDestMethodAllowed).
+// methods are chain specific, eg. 4-byte selectors of evm chains,
+// message type urls of cosmos chains, or program ids of solana.
+func CheckDestMethods(chainID string, methods ...string) error {
+	for _, method := range methods {
+		if !params.IsDestMethodAllowed(chainID, method) {
+			return fmt.Errorf("%w: %v", ErrDestMethodNotAllowed, method)
+		}
+	}
+	return nil
+}
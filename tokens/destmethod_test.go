@@ -0,0 +1,66 @@
+package tokens
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+func TestGetMethodSelector(t *testing.T) {
+	tests := []struct {
+		input []byte
+		want  string
+	}{
+		{nil, "0x"},
+		{common.FromHex("0x825bb1"), "0x825bb1"},
+		{common.FromHex("0x825bb13c"), "0x825bb13c"},
+		{common.FromHex("0x825bb13c0000000000000000000000000000000000000000000000000000000000000001"), "0x825bb13c"},
+	}
+	for _, test := range tests {
+		if got := GetMethodSelector(test.input); got != test.want {
+			t.Errorf("get method selector of %x got %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestCheckDestMethods(t *testing.T) {
+	extra := &params.ExtraConfig{
+		DestMethodAllowlist: map[string][]string{
+			"1":             {"0x825bb13c"},
+			"1001129115693": {"ft_transfer", "Transfer"},
+		},
+	}
+	// allowlist is initialized from the config set before
+	for i := 0; i < 2; i++ {
+		if err := params.SetExtraConfig(extra); err != nil {
+			t.Fatalf("set extra config failed: %v", err)
+		}
+	}
+	defer func() {
+		_ = params.SetExtraConfig(&params.ExtraConfig{})
+		_ = params.SetExtraConfig(&params.ExtraConfig{})
+	}()
+
+	tests := []struct {
+		chainID string
+		methods []string
+		allowed bool
+	}{
+		{"1", []string{"0x825BB13C"}, true},
+		{"1", []string{"0x"}, false},
+		{"1001129115693", []string{"ft_transfer", "Transfer"}, true},
+		{"1001129115693", []string{"ft_transfer", "DeployContract"}, false},
+		{"56", []string{"0x12345678"}, true},
+	}
+	for _, test := range tests {
+		err := CheckDestMethods(test.chainID, test.methods...)
+		if test.allowed && err != nil {
+			t.Errorf("check methods %v of chain %v got error %v", test.methods, test.chainID, err)
+		}
+		if !test.allowed && !errors.Is(err, ErrDestMethodNotAllowed) {
+			t.Errorf("check methods %v of chain %v got error %v, want %v", test.methods, test.chainID, err, ErrDestMethodNotAllowed)
+		}
+	}
+}
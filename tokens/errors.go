@@ -30,6 +30,8 @@ var (
 	ErrWrongCountOfMsgHashes  = errors.New("wrong count of msg hashed")
 	ErrMsgHashMismatch        = errors.New("message hash mismatch")
 	ErrSwapInBlacklist        = errors.New("swap is in black list")
+	ErrTokenRouteDisabled     = errors.New("token route is disabled")
+	ErrDestMethodNotAllowed   = errors.New("destination method is not allowed")
 	ErrTxBeforeInitialHeight  = errors.New("transaction before initial block height")
 	ErrEstimateGasFailed      = errors.New("estimate gas failed")
 	ErrRPCQueryError          = errors.New("rpc query error")
@@ -96,6 +98,15 @@ var (
 	ErrGetBlockNumberByID     = errors.New("get block number by id error")
 	ErrSendTx                 = errors.New("send tx fails")
 	ErrGetAccount             = errors.New("get account fails")
+	ErrRateUnavailable        = errors.New("conversion rate is unavailable")
+	ErrRateDeviation          = errors.New("conversion rate deviates too much")
+	ErrTxWithWrongMsgType     = errors.New("tx with wrong message type")
+	ErrReceiptDivergence      = errors.New("tx receipt diverges between rpc providers")
+	ErrLightClientVerify      = errors.New("light client verification failed")
+	ErrTxExpired              = errors.New("tx is expired")
+	ErrGatewayMismatch        = errors.New("gateway endpoint disagrees with config or peers")
+	ErrDeliveryFeeDeviation   = errors.New("delivery fee percent deviates too much")
+	ErrTxPruned               = errors.New("tx data is pruned by rpc nodes")
 )
 
 // errors should register in router swap
@@ -106,6 +117,7 @@ var (
 	ErrNoUnderlyingToken = errors.New("no underlying token")
 	ErrVerifyTxUnsafe    = errors.New("[tx maybe unsafe]")
 	ErrSwapoutForbidden  = errors.New("swapout forbidden")
+	ErrTokenMigrated     = errors.New("token is migrated to new contract")
 )
 
 // ShouldRegisterRouterSwapForError return true if this error should record in database
@@ -117,7 +129,8 @@ func ShouldRegisterRouterSwapForError(err error) bool {
 		errors.Is(err, ErrMissTokenConfig),
 		errors.Is(err, ErrNoUnderlyingToken),
 		errors.Is(err, ErrVerifyTxUnsafe),
-		errors.Is(err, ErrSwapoutForbidden):
+		errors.Is(err, ErrSwapoutForbidden),
+		errors.Is(err, ErrTokenMigrated):
 		return true
 	}
 	return false
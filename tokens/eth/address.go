@@ -2,14 +2,15 @@ package eth
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
 )
 
@@ -22,6 +23,8 @@ var (
 
 	contractCodeHashes    = make(map[common.Address]common.Hash)
 	maxContractCodeHashes = 2000
+
+	errEmptyContractCode = errors.New("empty contract code")
 )
 
 // IsValidAddress check address
@@ -98,15 +101,19 @@ func (b *Bridge) GetContractCodeHash(contract common.Address) common.Hash {
 }
 
 func (b *Bridge) getContractCode(contract string) (code []byte, err error) {
-	for i := 0; i < retryRPCCount; i++ {
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
 		code, err = b.GetCode(contract)
-		if err == nil && len(code) > 1 {
-			return code, nil
-		}
 		if err != nil {
 			log.Warn("get contract code failed", "contract", contract, "err", err)
+			return err
+		}
+		if len(code) <= 1 {
+			return errEmptyContractCode
 		}
-		time.Sleep(retryRPCInterval)
+		return nil
+	})
+	if errors.Is(err, errEmptyContractCode) {
+		return code, nil
 	}
 	return code, err
 }
@@ -0,0 +1,141 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+)
+
+var _ tokens.AllowanceManager = &Bridge{}
+
+var (
+	errAllowanceNotMonitored = errors.New("allowance is not monitored")
+	errWrongApproveID        = errors.New("wrong allowance approve id")
+	errWrongApproveAmount    = errors.New("wrong allowance approve amount")
+)
+
+// GetErc20Allowance get erc20 allowance of owner to spender
+func (b *Bridge) GetErc20Allowance(contract, owner, spender string) (*big.Int, error) {
+	data := make(hexutil.Bytes, 68)
+	copy(data[:4], erc20CodeParts["allowance"])
+	copy(data[4:36], common.HexToAddress(owner).Hash().Bytes())
+	copy(data[36:68], common.HexToAddress(spender).Hash().Bytes())
+	result, err := b.EvmContractBridge.CallContract(contract, data, "latest")
+	if err != nil {
+		return nil, err
+	}
+	return common.GetBigIntFromStr(result)
+}
+
+// GetMPCAllowance get allowance of the mpc to spender of token
+func (b *Bridge) GetMPCAllowance(token, spender string) (*big.Int, error) {
+	tokenCfg := b.getTokenConfigByAddress(common.HexToAddress(token))
+	if tokenCfg == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	routerMPC, err := router.GetRouterMPC(tokenCfg.TokenID, b.ChainConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	return b.GetErc20Allowance(token, routerMPC, spender)
+}
+
+// GetAllowanceApproveArgs get build args of the tx approving allowance of the mpc to spender of token
+func (b *Bridge) GetAllowanceApproveArgs(token, spender string, amount *big.Int, sequence int) (*tokens.BuildTxArgs, error) {
+	if params.IsParallelSwapEnabledOnChain(b.ChainConfig.ChainID) {
+		return nil, errors.New("allowance approve is not supported in parallel swap mode")
+	}
+	approveID := getAllowanceApproveID(common.HexToAddress(token), common.HexToAddress(spender), amount)
+	swapInfo, err := b.verifyAllowanceApprove(approveID)
+	if err != nil {
+		return nil, err
+	}
+	routerMPC, err := router.GetRouterMPC(swapInfo.ERC20SwapInfo.TokenID, b.ChainConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	return &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapInfo:    swapInfo.SwapInfo,
+			Identifier:  params.GetIdentifier(),
+			Salt:        params.GetDeploymentSalt(),
+			SwapID:      approveID,
+			SwapType:    tokens.AllowanceApproveType,
+			LogIndex:    sequence,
+			FromChainID: swapInfo.FromChainID,
+			ToChainID:   swapInfo.ToChainID,
+		},
+		From: routerMPC,
+	}, nil
+}
+
+func getAllowanceApproveID(token, spender common.Address, amount *big.Int) string {
+	return fmt.Sprintf("%v:%v:%v", token.LowerHex(), spender.LowerHex(), amount)
+}
+
+func parseAllowanceApproveID(approveID string) (token, spender common.Address, amount *big.Int, err error) {
+	parts := strings.Split(approveID, ":")
+	if len(parts) != 3 ||
+		!common.IsHexAddress(parts[0]) ||
+		!common.IsHexAddress(parts[1]) {
+		return token, spender, nil, errWrongApproveID
+	}
+	amount, ok := new(big.Int).SetString(parts[2], 10)
+	if !ok || amount.Sign() < 0 {
+		return token, spender, nil, errWrongApproveID
+	}
+	return common.HexToAddress(parts[0]), common.HexToAddress(parts[1]), amount, nil
+}
+
+// verifyAllowanceApprove verify allowance approve.
+// approving is only allowed to the monitored spenders with the configured amount,
+// or zero amount if the token needs to be approved zero first.
+func (b *Bridge) verifyAllowanceApprove(approveID string) (*tokens.SwapTxInfo, error) {
+	token, spender, amount, err := parseAllowanceApproveID(approveID)
+	if err != nil {
+		return nil, err
+	}
+	cfg := params.GetAllowanceMonitorConfig(b.ChainConfig.ChainID)
+	if cfg == nil {
+		return nil, errAllowanceNotMonitored
+	}
+	allowanceCfg := cfg.GetAllowanceConfig(token.LowerHex(), spender.LowerHex())
+	if allowanceCfg == nil {
+		return nil, errAllowanceNotMonitored
+	}
+	if !(amount.Sign() == 0 && allowanceCfg.ZeroFirst) && amount.Cmp(allowanceCfg.GetApproveAmount()) != 0 {
+		return nil, errWrongApproveAmount
+	}
+	tokenCfg := b.getTokenConfigByAddress(token)
+	if tokenCfg == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	chainID := b.ChainConfig.GetChainID()
+	swapInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}}
+	swapInfo.SwapType = tokens.AllowanceApproveType
+	swapInfo.Hash = approveID
+	swapInfo.FromChainID = chainID
+	swapInfo.ToChainID = chainID
+	swapInfo.ERC20SwapInfo.Token = tokenCfg.ContractAddress
+	swapInfo.ERC20SwapInfo.TokenID = tokenCfg.TokenID
+	return swapInfo, nil
+}
+
+func (b *Bridge) buildAllowanceApproveTxInput(args *tokens.BuildTxArgs) error {
+	if _, err := b.verifyAllowanceApprove(args.SwapID); err != nil {
+		return err
+	}
+	token, spender, amount, _ := parseAllowanceApproveID(args.SwapID)
+	input := abicoder.PackDataWithFuncHash(erc20CodeParts["approve"], spender, amount)
+	args.Input = (*hexutil.Bytes)(&input) // input
+	args.To = token.LowerHex()            // to
+	return nil
+}
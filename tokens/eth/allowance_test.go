@@ -0,0 +1,138 @@
+package eth
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+)
+
+func TestParseAllowanceApproveID(t *testing.T) {
+	token := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	spender := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	approveID := getAllowanceApproveID(token, spender, big.NewInt(1000))
+	gotToken, gotSpender, amount, err := parseAllowanceApproveID(approveID)
+	if err != nil {
+		t.Fatalf("parse allowance approve id failed: %v", err)
+	}
+	if gotToken != token || gotSpender != spender || amount.Int64() != 1000 {
+		t.Errorf("parse allowance approve id got (%v, %v, %v)", gotToken, gotSpender, amount)
+	}
+
+	for _, approveID := range []string{
+		"",
+		token.LowerHex() + ":" + spender.LowerHex(),
+		token.LowerHex() + ":0x1234:1000",
+		token.LowerHex() + ":" + spender.LowerHex() + ":-1",
+		token.LowerHex() + ":" + spender.LowerHex() + ":0x10",
+	} {
+		if _, _, _, err := parseAllowanceApproveID(approveID); !errors.Is(err, errWrongApproveID) {
+			t.Errorf("parse allowance approve id '%v' got error %v", approveID, err)
+		}
+	}
+}
+
+func TestVerifyAllowanceApprove(t *testing.T) {
+	token := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	spender := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	other := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	monitorCfg := &params.AllowanceMonitorConfig{Allowances: []*params.AllowanceConfig{
+		{Token: token.Hex(), Spender: spender.Hex(), MinAllowance: "100", ApproveAmount: "1000", ZeroFirst: true},
+		{Token: token.Hex(), Spender: other.Hex(), MinAllowance: "100", ApproveAmount: "1000"},
+	}}
+	if err := monitorCfg.CheckConfig(); err != nil {
+		t.Fatalf("check allowance monitor config failed: %v", err)
+	}
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			"1": {AllowanceMonitor: monitorCfg},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }()
+
+	b := NewCrossChainBridge()
+	b.ChainConfig = &tokens.ChainConfig{ChainID: "1"}
+	b.SetTokenConfig(token.LowerHex(), &tokens.TokenConfig{TokenID: "USDT", ContractAddress: token.LowerHex()})
+
+	tests := []struct {
+		spender common.Address
+		amount  int64
+		want    error
+	}{
+		{spender, 1000, nil},
+		{spender, 0, nil},
+		{spender, 999, errWrongApproveAmount},
+		{other, 0, errWrongApproveAmount},
+		{token, 1000, errAllowanceNotMonitored},
+	}
+	for i, test := range tests {
+		approveID := getAllowanceApproveID(token, test.spender, big.NewInt(test.amount))
+		swapInfo, err := b.verifyAllowanceApprove(approveID)
+		if !errors.Is(err, test.want) {
+			t.Errorf("test %v: verify allowance approve got error %v, want %v", i, err, test.want)
+			continue
+		}
+		if err == nil && (swapInfo.SwapType != tokens.AllowanceApproveType ||
+			swapInfo.ERC20SwapInfo.TokenID != "USDT" || swapInfo.Hash != approveID) {
+			t.Errorf("test %v: verify allowance approve got %+v", i, swapInfo)
+		}
+	}
+
+	b.ChainConfig = &tokens.ChainConfig{ChainID: "56"}
+	if _, err = b.verifyAllowanceApprove(getAllowanceApproveID(token, spender, big.NewInt(1000))); !errors.Is(err, errAllowanceNotMonitored) {
+		t.Errorf("verify allowance approve on unmonitored chain got error %v", err)
+	}
+}
+
+func TestBuildAllowanceApproveTxInput(t *testing.T) {
+	token := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	spender := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	monitorCfg := &params.AllowanceMonitorConfig{Allowances: []*params.AllowanceConfig{
+		{Token: token.Hex(), Spender: spender.Hex(), MinAllowance: "100"},
+	}}
+	if err := monitorCfg.CheckConfig(); err != nil {
+		t.Fatalf("check allowance monitor config failed: %v", err)
+	}
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			"1": {AllowanceMonitor: monitorCfg},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }()
+
+	b := NewCrossChainBridge()
+	b.ChainConfig = &tokens.ChainConfig{ChainID: "1"}
+	b.SetTokenConfig(token.LowerHex(), &tokens.TokenConfig{TokenID: "USDT", ContractAddress: token.LowerHex()})
+
+	amount := monitorCfg.Allowances[0].GetApproveAmount()
+	args := &tokens.BuildTxArgs{SwapArgs: tokens.SwapArgs{SwapID: getAllowanceApproveID(token, spender, amount)}}
+	if err = b.buildAllowanceApproveTxInput(args); err != nil {
+		t.Fatalf("build allowance approve tx input failed: %v", err)
+	}
+	if args.To != token.LowerHex() {
+		t.Errorf("approve tx to got %v, want %v", args.To, token.LowerHex())
+	}
+	want := abicoder.PackDataWithFuncHash(erc20CodeParts["approve"], spender, amount)
+	if args.Input == nil || !bytes.Equal(*args.Input, want) {
+		t.Errorf("approve tx input got %v, want %v", args.Input, common.ToHex(want))
+	}
+	if selector := tokens.GetMethodSelector(want); selector != "0x095ea7b3" {
+		t.Errorf("approve tx method got %v, want approve(address,uint256)", selector)
+	}
+
+	args = &tokens.BuildTxArgs{SwapArgs: tokens.SwapArgs{SwapID: getAllowanceApproveID(token, spender, big.NewInt(1))}}
+	if err = b.buildAllowanceApproveTxInput(args); !errors.Is(err, errWrongApproveAmount) || args.Input != nil {
+		t.Errorf("build approve tx input of wrong amount got error %v", err)
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
-	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
 	"github.com/anyswap/CrossChain-Router/v3/types"
@@ -191,11 +190,7 @@ func (b *Bridge) verifyAnyCallSwapTxLog(swapInfo *tokens.SwapTxInfo, rlog *types
 	}
 
 	routerContract := b.GetRouterContract("")
-	if !common.IsEqualIgnoreCase(rlog.Address.LowerHex(), routerContract) {
-		log.Warn("tx to address mismatch", "have", rlog.Address.LowerHex(), "want", routerContract, "chainID", b.ChainConfig.ChainID, "txid", swapInfo.Hash, "logIndex", swapInfo.LogIndex, "err", tokens.ErrTxWithWrongContract)
-		return tokens.ErrTxWithWrongContract
-	}
-	return nil
+	return b.checkRouterLogEmitter(swapInfo, rlog, routerContract)
 }
 
 func (b *Bridge) parseAnyCallSwapTxLog(swapInfo *tokens.SwapTxInfo, rlog *types.RPCLog) (err error) {
@@ -372,13 +367,10 @@ func (b *Bridge) checkAnyCallSwapInfo(swapInfo *tokens.SwapTxInfo) error {
 		callFrom := getCallFrom(swapInfo)
 		routerContract := b.GetRouterContract("")
 		var budgetBalance *big.Int
-		var err error
-		for i := 0; i < 3; i++ {
+		err := tokens.RetryRPC(b.ChainConfig.ChainID, func() (err error) {
 			budgetBalance, err = b.GetExecutionBudget(routerContract, callFrom)
-			if err == nil {
-				break
-			}
-		}
+			return err
+		})
 		if err != nil {
 			return fmt.Errorf("get budget error: %w", err)
 		}
@@ -421,15 +413,19 @@ func (b *Bridge) findMessageSentInfo(swapInfo *tokens.SwapTxInfo, logs []*types.
 			messageHash := common.Keccak256Hash(messageBytes)
 			log.Info("find message sent info success", "txHash", swapInfo.Hash, "logIndex", logIndex, "msgIndex", i, "message", common.ToHex(messageBytes), "messageHash", messageHash.String())
 
-			// only the swap server need get attestation
+			// only the swap server need get attestation, the swap waits for the
+			// attestation in the swap job if it is not complete yet.
 			if params.IsSwapServer && !allowUnstable {
-				var attestation *USDCAttestation
-				attestation, err = GetUSDCAttestation(messageHash)
-				if err != nil {
-					return fmt.Errorf("%w. %v %v", tokens.ErrGetAttestationFailed, messageHash.String(), err)
+				attestation, err := tokens.GetUSDCAttestation(messageBytes)
+				switch {
+				case err != nil:
+					log.Warn("get attestation failed", "txHash", swapInfo.Hash, "logIndex", logIndex, "msgHash", messageHash.String(), "err", err)
+				case !attestation.IsComplete():
+					log.Info("attestation is not complete", "txHash", swapInfo.Hash, "logIndex", logIndex, "msgHash", messageHash.String(), "status", attestation.Status)
+				default:
+					anycallSwapInfo.Attestation = common.FromHex(attestation.Attestation)
+					log.Info("get attestation success", "txHash", swapInfo.Hash, "logIndex", logIndex, "msgHash", messageHash.String(), "attestation", attestation.Attestation, "status", attestation.Status)
 				}
-				anycallSwapInfo.Attestation = attestation.Attestation
-				log.Info("get attestation success", "txHash", swapInfo.Hash, "logIndex", logIndex, "msgHash", messageHash.String(), "attestation", attestation.Attestation, "status", attestation.Status)
 			}
 			return nil
 		}
@@ -523,23 +519,3 @@ func (b *Bridge) buildAnyCallSwapTxInput(args *tokens.BuildTxArgs) (err error) {
 
 	return nil
 }
-
-// USDCAttestation usdc attestation
-type USDCAttestation struct {
-	Attestation hexutil.Bytes `json:"attestation"`
-	Status      string        `json:"status"`
-}
-
-// GetUSDCAttestation get usdc attestation
-func GetUSDCAttestation(messageHash common.Hash) (*USDCAttestation, error) {
-	attestationURL := strings.TrimSuffix(params.GetAttestationServer(), "/")
-	if attestationURL == "" {
-		return nil, tokens.ErrNoAttestationServer
-	}
-
-	url := fmt.Sprintf("%v/attestations/%v", attestationURL, messageHash.String())
-
-	var res *USDCAttestation
-	err := client.RPCGetWithTimeout(&res, url, 60)
-	return res, err
-}
@@ -0,0 +1,97 @@
+package eth
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+var (
+	errBatchSendNotSupported = errors.New("batch send is not supported")
+	errEmptyTxHash           = errors.New("empty tx hash")
+)
+
+// SendTransactions impl tokens.BatchSender,
+// send the signed txs in one json rpc batch of eth_sendRawTransaction to every gateway,
+// each member succeeds if any gateway accepts it.
+func (b *Bridge) SendTransactions(signedTxs []interface{}) []*tokens.BatchSendResult {
+	results := make([]*tokens.BatchSendResult, len(signedTxs))
+	if params.IsWatcherMode {
+		return fillBatchResults(results, params.ErrWatcherMode)
+	}
+	if b.IsAccountAbstraction() || b.IsZKSync() || b.IsSapphireChain() {
+		return fillBatchResults(results, errBatchSendNotSupported)
+	}
+
+	reqs := make([]*client.Request, len(signedTxs))
+	for i, signedTx := range signedTxs {
+		tx, ok := signedTx.(*types.Transaction)
+		if !ok {
+			results[i] = &tokens.BatchSendResult{Err: tokens.ErrWrongRawTx}
+			continue
+		}
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			results[i] = &tokens.BatchSendResult{Err: err}
+			continue
+		}
+		reqs[i] = client.NewRequest("eth_sendRawTransaction", common.ToHex(data))
+	}
+
+	var validReqs []*client.Request
+	var validIndexes []int
+	for i, req := range reqs {
+		if req != nil {
+			validReqs = append(validReqs, req)
+			validIndexes = append(validIndexes, i)
+		}
+	}
+	if len(validReqs) == 0 {
+		return results
+	}
+
+	chainID := b.ChainConfig.ChainID
+	for _, url := range b.GatewayConfig.AllGatewayURLs {
+		txHashes := make([]interface{}, len(validReqs))
+		for i := range txHashes {
+			txHashes[i] = new(string)
+		}
+		errs := client.RPCPostBatch(url, validReqs, txHashes, b.RPCClientTimeout)
+		for j, i := range validIndexes {
+			if results[i] != nil && results[i].Err == nil {
+				continue // accepted by other gateway
+			}
+			txHash := *txHashes[j].(*string)
+			if errs[j] == nil && txHash != "" {
+				results[i] = &tokens.BatchSendResult{TxHash: txHash}
+				continue
+			}
+			err := errs[j]
+			if err == nil {
+				err = errEmptyTxHash
+			}
+			results[i] = &tokens.BatchSendResult{Err: wrapRPCQueryError(err, "eth_sendRawTransaction")}
+		}
+	}
+
+	var failed int
+	for _, res := range results {
+		if res == nil || res.Err != nil {
+			failed++
+		}
+	}
+	log.Info("SendTransactions in batch finished", "chainID", chainID, "count", len(signedTxs), "failed", failed)
+	return results
+}
+
+func fillBatchResults(results []*tokens.BatchSendResult, err error) []*tokens.BatchSendResult {
+	for i := range results {
+		results[i] = &tokens.BatchSendResult{Err: err}
+	}
+	return results
+}
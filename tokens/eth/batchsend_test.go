@@ -0,0 +1,80 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+// newBatchSendServer accept the raw txs of the given indexes in batch
+func newBatchSendServer(t *testing.T, accepts map[int]bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []struct {
+			ID     int      `json:"id"`
+			Params []string `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			t.Errorf("decode batch request failed: %v", err)
+			return
+		}
+		resps := make([]map[string]interface{}, len(reqs))
+		for i, req := range reqs {
+			resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+			if accepts[i] {
+				var tx types.Transaction
+				if err := tx.UnmarshalBinary(common.FromHex(req.Params[0])); err != nil {
+					t.Errorf("unmarshal raw tx failed: %v", err)
+				}
+				resp["result"] = tx.Hash().String()
+			} else {
+				resp["error"] = map[string]interface{}{"code": -32000, "message": "nonce too low"}
+			}
+			resps[i] = resp
+		}
+		_ = json.NewEncoder(w).Encode(resps)
+	}))
+}
+
+func TestSendTransactions(t *testing.T) {
+	gateway1 := newBatchSendServer(t, map[int]bool{0: true})
+	defer gateway1.Close()
+	gateway2 := newBatchSendServer(t, map[int]bool{1: true})
+	defer gateway2.Close()
+
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: "1"})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{gateway1.URL, gateway2.URL}})
+
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	var signedTxs []interface{}
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		signedTxs = append(signedTxs, types.NewTransaction(nonce, to, big.NewInt(1), 21000, big.NewInt(1), nil))
+	}
+	signedTxs = append(signedTxs, "not a tx")
+
+	results := b.SendTransactions(signedTxs)
+	if len(results) != len(signedTxs) {
+		t.Fatalf("results count got %v, want %v", len(results), len(signedTxs))
+	}
+	// each member is accepted by any gateway
+	for i := 0; i < 2; i++ {
+		want := signedTxs[i].(*types.Transaction).Hash().String()
+		if results[i].Err != nil || results[i].TxHash != want {
+			t.Errorf("member %v got (%v, %v), want %v", i, results[i].TxHash, results[i].Err, want)
+		}
+	}
+	// the failed member does not fail the others
+	if results[2].Err == nil || results[2].TxHash != "" {
+		t.Errorf("member 2 should fail, got (%v, %v)", results[2].TxHash, results[2].Err)
+	}
+	if !errors.Is(results[3].Err, tokens.ErrWrongRawTx) {
+		t.Errorf("member 3 got error %v, want %v", results[3].Err, tokens.ErrWrongRawTx)
+	}
+}
@@ -22,6 +22,16 @@ var (
 	_ tokens.IBridge = &Bridge{}
 	// ensure Bridge impl tokens.NonceSetter
 	_ tokens.NonceSetter = &Bridge{}
+	// ensure Bridge impl tokens.SendTxErrorMapper
+	_ tokens.SendTxErrorMapper = &Bridge{}
+	// ensure Bridge impl tokens.DepositAddressProvider
+	_ tokens.DepositAddressProvider = &Bridge{}
+	// ensure Bridge impl tokens.SignedTxStaleChecker
+	_ tokens.SignedTxStaleChecker = &Bridge{}
+	// ensure Bridge impl tokens.ReplaceCostEstimator
+	_ tokens.ReplaceCostEstimator = &Bridge{}
+	// ensure Bridge impl tokens.FaucetBridge
+	_ tokens.FaucetBridge = &Bridge{}
 )
 
 type EvmContractBridge interface {
@@ -10,7 +10,6 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
-	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
 )
 
 // router contract's func hashs
@@ -138,10 +137,14 @@ func (b *Bridge) BuildERC20SwapTxInput(args *tokens.BuildTxArgs) (err error) {
 		log.Warn("get multichain token failed", "tokenID", erc20SwapInfo.TokenID, "chainID", args.ToChainID)
 		return tokens.ErrMissTokenConfig
 	}
+	multichainToken = b.getMigratedToken(erc20SwapInfo.TokenID, multichainToken)
 
 	if args.SwapType == tokens.ERC20SwapTypeMixPool {
 		return b.buildMixPoolSwapinTxInput(args, multichainToken)
 	}
+	if b.isClaimSwap(erc20SwapInfo.TokenID) {
+		return b.buildClaimSwapinTxInput(args, multichainToken)
+	}
 	if erc20SwapInfo.CallProxy != "" {
 		return b.buildSwapAndExecTxInput(args, multichainToken)
 	}
@@ -163,20 +166,8 @@ func (b *Bridge) buildSwapAndExecTxInput(args *tokens.BuildTxArgs, multichainTok
 		return tokens.ErrRouterVersionMismatch
 	}
 
-	erc20SwapInfo := args.ERC20SwapInfo
-
-	funcHash := GetSwapInAndExecFuncHashV7(toTokenCfg)
-
-	input := abicoder.PackDataWithFuncHash(funcHash,
-		args.GetUniqueSwapIdentifier(),
-		common.HexToHash(erc20SwapInfo.SwapoutID),
-		common.HexToAddress(multichainToken),
-		receiver,
-		amount,
-		args.FromChainID,
-		common.HexToAddress(erc20SwapInfo.CallProxy),
-		erc20SwapInfo.CallData,
-	)
+	calldata := newSwapinCalldata(args, multichainToken, receiver, amount)
+	input := PackSwapinAndExecCalldata(toTokenCfg, calldata)
 	args.Input = (*hexutil.Bytes)(&input)          // input
 	args.To = b.GetRouterContract(multichainToken) // to
 	args.SwapValue = amount                        // swapValue
@@ -195,38 +186,8 @@ func (b *Bridge) buildERC20SwapinTxInput(args *tokens.BuildTxArgs, multichainTok
 		return tokens.ErrMissTokenConfig
 	}
 
-	erc20SwapInfo := args.ERC20SwapInfo
-
-	var funcHash, input []byte
-
-	switch b.GetRouterVersion(multichainToken) {
-	case "v7":
-		funcHash = GetSwapInFuncHashV7(toTokenCfg)
-		input = abicoder.PackDataWithFuncHash(funcHash,
-			args.GetUniqueSwapIdentifier(),
-			common.HexToHash(erc20SwapInfo.SwapoutID),
-			common.HexToAddress(multichainToken),
-			receiver,
-			amount,
-			args.FromChainID,
-		)
-	default:
-		funcHash = GetSwapInFuncHash1(toTokenCfg)
-		var swapIDHash common.Hash
-		if common.IsHexHash(args.SwapID) {
-			swapIDHash = common.HexToHash(args.SwapID)
-		} else {
-			swapIDHash = common.BytesToHash([]byte(args.SwapID))
-		}
-		input = abicoder.PackDataWithFuncHash(funcHash,
-			swapIDHash,
-			common.HexToAddress(multichainToken),
-			receiver,
-			amount,
-			args.FromChainID,
-		)
-	}
-
+	calldata := newSwapinCalldata(args, multichainToken, receiver, amount)
+	input := PackSwapinCalldata(b.GetRouterVersion(multichainToken), toTokenCfg, calldata)
 	args.Input = (*hexutil.Bytes)(&input)          // input
 	args.To = b.GetRouterContract(multichainToken) // to
 	args.SwapValue = amount                        // swapValue
@@ -245,15 +206,8 @@ func (b *Bridge) buildMixPoolSwapinTxInput(args *tokens.BuildTxArgs, multichainT
 		return tokens.ErrMissTokenConfig
 	}
 
-	funcHash := MixPoolAnySwapInFuncHash
-
-	input := abicoder.PackDataWithFuncHash(funcHash,
-		args.GetUniqueSwapIdentifier(),
-		common.HexToAddress(multichainToken),
-		receiver,
-		amount,
-		args.FromChainID,
-	)
+	calldata := newSwapinCalldata(args, multichainToken, receiver, amount)
+	input := PackMixPoolSwapinCalldata(calldata)
 	args.Input = (*hexutil.Bytes)(&input)          // input
 	args.To = b.GetRouterContract(multichainToken) // to
 	args.SwapValue = amount                        // swapValue
@@ -261,6 +215,21 @@ func (b *Bridge) buildMixPoolSwapinTxInput(args *tokens.BuildTxArgs, multichainT
 	return nil
 }
 
+func newSwapinCalldata(args *tokens.BuildTxArgs, multichainToken string, receiver common.Address, amount *big.Int) *SwapinCalldata {
+	erc20SwapInfo := args.ERC20SwapInfo
+	return &SwapinCalldata{
+		SwapID:       args.SwapID,
+		UniqueSwapID: args.GetUniqueSwapIdentifier(),
+		SwapoutID:    erc20SwapInfo.SwapoutID,
+		Token:        common.HexToAddress(multichainToken),
+		Receiver:     receiver,
+		Amount:       amount,
+		FromChainID:  args.FromChainID,
+		CallProxy:    common.HexToAddress(erc20SwapInfo.CallProxy),
+		CallData:     erc20SwapInfo.CallData,
+	}
+}
+
 func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken string) (receiver common.Address, amount *big.Int, err error) {
 	erc20SwapInfo := args.ERC20SwapInfo
 	receiver = common.HexToAddress(args.Bind)
@@ -281,7 +250,7 @@ func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken
 	if toTokenCfg == nil {
 		return receiver, amount, tokens.ErrMissTokenConfig
 	}
-	amount = tokens.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo)
+	amount = args.ApplyConversionRate(args.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo))
 	totalAmount := tokens.ConvertTokenValue(args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals)
 	args.Extra.BridgeFee = new(big.Int).Sub(totalAmount, amount)
 	return receiver, amount, err
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
-	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
@@ -18,9 +17,6 @@ import (
 )
 
 var (
-	retryRPCCount    = 3
-	retryRPCInterval = 1 * time.Second
-
 	cachedNonce = make(map[string]uint64)
 )
 
@@ -58,6 +54,12 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 	switch args.SwapType {
 	case tokens.ERC20SwapType, tokens.ERC20SwapTypeMixPool:
 		err = b.BuildERC20SwapTxInput(args)
+	case tokens.DepositSweepType:
+		err = b.buildDepositSweepTxInput(args)
+	case tokens.ClaimRefundType:
+		err = b.buildClaimRefundTxInput(args)
+	case tokens.AllowanceApproveType:
+		err = b.buildAllowanceApproveTxInput(args)
 	case tokens.NFTSwapType:
 		err = b.buildNFTSwapTxInput(args)
 	case tokens.AnyCallSwapType:
@@ -70,10 +72,16 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		return nil, err
 	}
 
+	err = b.authorizeSwapTx(args)
+	if err != nil {
+		return nil, err
+	}
+
 	err = b.setDefaults(args)
 	if err != nil {
 		return nil, err
 	}
+	args.Extra.BuilderVersion = CalldataBuilderVersion
 
 	return b.buildTx(args)
 }
@@ -92,9 +100,12 @@ func (b *Bridge) buildTx(args *tokens.BuildTxArgs) (rawTx interface{}, err error
 		isDynamicFeeTx = params.IsDynamicFeeTxEnabled(b.ChainConfig.ChainID)
 	)
 
-	if params.IsSwapServer ||
+	// gas of user operations is sponsored by the paymaster
+	isAccountAbstraction := b.IsAccountAbstraction()
+
+	if !isAccountAbstraction && (params.IsSwapServer ||
 		(params.GetRouterOracleConfig() != nil &&
-			params.GetRouterOracleConfig().CheckGasTokenBalance) {
+			params.GetRouterOracleConfig().CheckGasTokenBalance)) {
 		minReserveFee := b.getMinReserveFee()
 		// if min reserve fee is zero, then do not check balance
 		if minReserveFee.Sign() > 0 {
@@ -139,7 +150,12 @@ func (b *Bridge) buildTx(args *tokens.BuildTxArgs) (rawTx interface{}, err error
 	}
 	cachedNonce[key] = nonce
 
-	if b.IsZKSync() {
+	if isAccountAbstraction {
+		rawTx, err = b.buildUserOperation(args, to, value, input, nonce)
+		if err != nil {
+			return nil, err
+		}
+	} else if b.IsZKSync() {
 		chainId, _ := new(big.Int).SetString(b.ChainConfig.ChainID, 0)
 		tx := zksync2.CreateFunctionCallTransaction(
 			ethcommon.HexToAddress(args.From),
@@ -254,10 +270,14 @@ func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) (err error) {
 				"value", args.Value, "data", *args.Input)
 			return fmt.Errorf("%w %v", tokens.ErrBuildTxErrorAndDelay, tokens.ErrEstimateGasFailed)
 		}
-		esGasLimit += esGasLimit * 30 / 100
-		defGasLimit := b.getDefaultGasLimit()
-		if esGasLimit < defGasLimit {
-			esGasLimit = defGasLimit
+		if learnedGasLimit, ok := b.getLearnedGasLimit(args, esGasLimit); ok {
+			esGasLimit = learnedGasLimit
+		} else {
+			esGasLimit += esGasLimit * 30 / 100
+			defGasLimit := b.getDefaultGasLimit()
+			if esGasLimit < defGasLimit {
+				esGasLimit = defGasLimit
+			}
 		}
 		// max token gas limit consider first, then max chain gas limit
 		maxTokenGasLimit := params.GetMaxTokenGasLimit(args.GetTokenID(), b.ChainConfig.ChainID)
@@ -316,13 +336,10 @@ func (b *Bridge) getGasPrice(args *tokens.BuildTxArgs) (price *big.Int, err erro
 			return price, nil
 		}
 	} else {
-		for i := 0; i < retryRPCCount; i++ {
+		err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
 			price, err = b.SuggestPrice()
-			if err == nil {
-				break
-			}
-			time.Sleep(retryRPCInterval)
-		}
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -410,7 +427,7 @@ func (b *Bridge) adjustSwapGasPrice(args *tokens.BuildTxArgs, oldGasPrice *big.I
 func (b *Bridge) getAccountNonce(args *tokens.BuildTxArgs) (nonceptr *uint64, err error) {
 	var nonce uint64
 
-	if params.IsParallelSwapEnabled() {
+	if params.IsParallelSwapEnabledOnChain(b.ChainConfig.ChainID) {
 		nonce, err = b.AllocateNonce(args)
 		return &nonce, err
 	}
@@ -432,13 +449,10 @@ func (b *Bridge) getPoolNonce(args *tokens.BuildTxArgs) (nonceptr *uint64, err e
 		getPoolNonceBlockNumberOpt = "latest"
 	}
 
-	for i := 0; i < retryRPCCount; i++ {
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
 		nonce, err = b.GetPoolNonce(args.From, getPoolNonceBlockNumberOpt)
-		if err == nil {
-			break
-		}
-		time.Sleep(retryRPCInterval)
-	}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -460,13 +474,10 @@ func (b *Bridge) getMinReserveFee() *big.Int {
 
 func (b *Bridge) checkCoinBalance(sender string, needValue *big.Int) (err error) {
 	var balance *big.Int
-	for i := 0; i < retryRPCCount; i++ {
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
 		balance, err = b.GetBalance(sender)
-		if err == nil {
-			break
-		}
-		time.Sleep(retryRPCInterval)
-	}
+		return err
+	})
 	if err == nil && balance.Cmp(needValue) < 0 {
 		return fmt.Errorf("not enough coin balance. %v < %v", balance, needValue)
 	}
@@ -483,13 +494,10 @@ func (b *Bridge) getGasTipCap(args *tokens.BuildTxArgs) (gasTipCap *big.Int, err
 		return nil, tokens.ErrMissDynamicFeeConfig
 	}
 
-	for i := 0; i < retryRPCCount; i++ {
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
 		gasTipCap, err = b.SuggestGasTipCap()
-		if err == nil {
-			break
-		}
-		time.Sleep(retryRPCInterval)
-	}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -522,13 +530,10 @@ func (b *Bridge) getGasFeeCap(_ *tokens.BuildTxArgs, gasTipCap *big.Int) (gasFee
 
 	blockCount := dfConfig.BlockCountFeeHistory
 	var baseFee *big.Int
-	for i := 0; i < retryRPCCount; i++ {
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
 		baseFee, err = b.GetBaseFee(blockCount)
-		if err == nil {
-			break
-		}
-		time.Sleep(retryRPCInterval)
-	}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
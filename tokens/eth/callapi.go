@@ -168,10 +168,14 @@ func (b *Bridge) getTransactionByBlockNumberAndIndex(blockNumber *big.Int, txInd
 
 // GetTransactionReceipt call eth_getTransactionReceipt
 func (b *Bridge) GetTransactionReceipt(txHash string) (result *types.RPCTxReceipt, err error) {
+	var prunedErr error
 	for _, url := range b.GatewayConfig.AllGatewayURLs {
 		start := time.Now()
 		err = client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "eth_getTransactionReceipt", txHash)
 		log.Info("call getTransactionReceipt finished", "txhash", txHash, "url", url, "timespent", time.Since(start).String())
+		if b.isPrunedError(err) {
+			prunedErr = err
+		}
 		if err == nil && result != nil {
 			if result.BlockNumber == nil || result.BlockHash == nil || result.TxIndex == nil {
 				return nil, errTxReceiptMissBlockInfo
@@ -202,6 +206,15 @@ func (b *Bridge) GetTransactionReceipt(txHash string) (result *types.RPCTxReceip
 			return result, nil
 		}
 	}
+	if params.GetShallowArchiveConfig(b.ChainConfig.ChainID) != nil {
+		// pruned nodes may also respond null receipts
+		if saved, errs := b.getSavedTxReceipt(txHash); errs == nil {
+			return saved, nil
+		}
+		if prunedErr != nil {
+			return nil, fmt.Errorf("%w: call 'eth_getTransactionReceipt %v' failed, err='%v'", tokens.ErrTxPruned, txHash, common.FirstN(prunedErr.Error(), 166))
+		}
+	}
 	return nil, wrapRPCQueryError(err, "eth_getTransactionReceipt", txHash)
 }
 
@@ -220,6 +233,9 @@ func (b *Bridge) checkTxBlockHash(blockNumber *big.Int, blockHash common.Hash) e
 
 // GetPoolNonce call eth_getTransactionCount
 func (b *Bridge) GetPoolNonce(address, height string) (mdPoolNonce uint64, err error) {
+	if b.isSmartAccount(address) {
+		return b.getUserOpNonce(params.GetAccountAbstractionConfig(b.ChainConfig.ChainID), height)
+	}
 	start := time.Now()
 	allPoolNonces := make([]uint64, 0, 10)
 	account := common.HexToAddress(address)
@@ -269,18 +285,20 @@ func (b *Bridge) SuggestPrice() (*big.Int, error) {
 
 func (b *Bridge) getGasPriceFromURL(url string) (*big.Int, error) {
 	logFunc := log.GetPrintFuncOr(params.IsDebugMode, log.Info, log.Trace)
-	var err error
-	for i := 0; i < 3; i++ {
-		var result hexutil.Big
-		err = client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "eth_gasPrice")
-		if err == nil {
-			gasPrice := result.ToInt()
-			logFunc("call eth_gasPrice success", "chainID", b.ChainConfig.ChainID, "url", url, "gasPrice", gasPrice)
-			return gasPrice, nil
+	var result hexutil.Big
+	err := tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
+		err := client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "eth_gasPrice")
+		if err != nil {
+			logFunc("call eth_gasPrice failed", "chainID", b.ChainConfig.ChainID, "url", url, "err", err)
 		}
-		logFunc("call eth_gasPrice failed", "chainID", b.ChainConfig.ChainID, "url", url, "err", err)
+		return err
+	})
+	if err != nil {
+		return nil, wrapRPCQueryError(err, "eth_gasPrice")
 	}
-	return nil, wrapRPCQueryError(err, "eth_gasPrice")
+	gasPrice := result.ToInt()
+	logFunc("call eth_gasPrice success", "chainID", b.ChainConfig.ChainID, "url", url, "gasPrice", gasPrice)
+	return gasPrice, nil
 }
 
 func (b *Bridge) getMaxGasPrice() (*big.Int, error) {
@@ -345,6 +363,9 @@ func (b *Bridge) getMedianGasPrice() (mdGasPrice *big.Int, err error) {
 
 // SendSignedTransaction call eth_sendRawTransaction
 func (b *Bridge) SendSignedTransaction(tx *types.Transaction) (txHash string, err error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	if b.IsSapphireChain() {
 		return b.SendSignedTransactionSapphire(tx)
 	}
@@ -385,6 +406,9 @@ func (b *Bridge) SendSignedTransaction(tx *types.Transaction) (txHash string, er
 }
 
 func (b *Bridge) SendSignedZKSyncTransaction(data []byte) (txHash string, err error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	log.Info("call eth_sendRawTransaction start")
 	hexData := common.ToHex(data)
 	urlCount := len(b.GatewayConfig.AllGatewayURLs)
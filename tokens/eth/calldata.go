@@ -0,0 +1,85 @@
+package eth
+
+import (
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+)
+
+// CalldataBuilderVersion version of the router swapin calldata builder.
+// increase it whenever the calldata built for the same swap changes
+// (and regenerate the golden file by `go test ./tokens/eth -run Golden -update`).
+// the version is recorded in swap results to attribute swaps to the builder.
+const CalldataBuilderVersion uint64 = 1
+
+// SwapinCalldata params of router swapin calldata
+type SwapinCalldata struct {
+	SwapID       string // swap tx id (used by router before v7)
+	UniqueSwapID string // unique swap identifier (used by router v7)
+	SwapoutID    string
+	Token        common.Address // multichain token
+	Receiver     common.Address
+	Amount       *big.Int
+	FromChainID  *big.Int
+	CallProxy    common.Address
+	CallData     []byte
+}
+
+// PackSwapinCalldata pack anySwapIn calldata of router version
+func PackSwapinCalldata(routerVersion string, tokenCfg *tokens.TokenConfig, p *SwapinCalldata) []byte {
+	if routerVersion == "v7" {
+		return abicoder.PackDataWithFuncHash(GetSwapInFuncHashV7(tokenCfg),
+			p.UniqueSwapID,
+			common.HexToHash(p.SwapoutID),
+			p.Token,
+			p.Receiver,
+			p.Amount,
+			p.FromChainID,
+		)
+	}
+	var swapIDHash common.Hash
+	if common.IsHexHash(p.SwapID) {
+		swapIDHash = common.HexToHash(p.SwapID)
+	} else {
+		swapIDHash = common.BytesToHash([]byte(p.SwapID))
+	}
+	return abicoder.PackDataWithFuncHash(GetSwapInFuncHash1(tokenCfg),
+		swapIDHash,
+		p.Token,
+		p.Receiver,
+		p.Amount,
+		p.FromChainID,
+	)
+}
+
+// PackSwapinAndExecCalldata pack anySwapInAndExec calldata (router v7 only)
+func PackSwapinAndExecCalldata(tokenCfg *tokens.TokenConfig, p *SwapinCalldata) []byte {
+	return abicoder.PackDataWithFuncHash(GetSwapInAndExecFuncHashV7(tokenCfg),
+		p.UniqueSwapID,
+		common.HexToHash(p.SwapoutID),
+		p.Token,
+		p.Receiver,
+		p.Amount,
+		p.FromChainID,
+		p.CallProxy,
+		p.CallData,
+	)
+}
+
+// PackMixPoolSwapinCalldata pack mix pool anySwapIn calldata
+func PackMixPoolSwapinCalldata(p *SwapinCalldata) []byte {
+	return abicoder.PackDataWithFuncHash(MixPoolAnySwapInFuncHash,
+		p.UniqueSwapID,
+		p.Token,
+		p.Receiver,
+		p.Amount,
+		p.FromChainID,
+	)
+}
+
+// PackClaimCallData pack the exec data passed to claim escrow
+func PackClaimCallData(claimID string, refundTo common.Address, claimPeriod int64) []byte {
+	return abicoder.PackData(claimID, refundTo, big.NewInt(claimPeriod))
+}
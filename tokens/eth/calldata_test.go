@@ -0,0 +1,140 @@
+package eth
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+var calldataGoldenFile = filepath.Join("testdata", "calldata.golden")
+
+type calldataGolden struct {
+	Version  uint64            `json:"version"`
+	Calldata map[string]string `json:"calldata"`
+}
+
+func newGoldenTokenConfig(contractVersion uint64, underlying string) *tokens.TokenConfig {
+	tokenCfg := &tokens.TokenConfig{ContractVersion: contractVersion}
+	if underlying != "" {
+		tokenCfg.SetUnderlying(underlying)
+	}
+	return tokenCfg
+}
+
+func buildGoldenCalldata() map[string]string {
+	underlying := "0x4444444444444444444444444444444444444444"
+	tokenCfgs := []struct {
+		name     string
+		tokenCfg *tokens.TokenConfig
+	}{
+		{"v0", newGoldenTokenConfig(0, "")},
+		{"v0Underlying", newGoldenTokenConfig(0, underlying)},
+		{"v6", newGoldenTokenConfig(6, "")},
+		{"v6Underlying", newGoldenTokenConfig(6, underlying)},
+		{"forceAuto", newGoldenTokenConfig(ForceAnySwapInAutoTokenVersion, underlying)},
+		{"forceSwapIn", newGoldenTokenConfig(ForceAnySwapInTokenVersion, underlying)},
+		{"forceUnderlying", newGoldenTokenConfig(ForceAnySwapInUnderlyingTokenVersion, underlying)},
+		{"forceNative", newGoldenTokenConfig(ForceAnySwapInNativeTokenVersion, underlying)},
+		{"forceAndCall", newGoldenTokenConfig(ForceAnySwapInAndCallTokenVersion, underlying)},
+		{"forceUnderlyingAndCall", newGoldenTokenConfig(ForceAnySwapInUnerlyingAndCallTokenVersion, underlying)},
+		{"wrapper", newGoldenTokenConfig(tokens.MinWrapperTokenVersion, underlying)},
+	}
+	calldata := &SwapinCalldata{
+		SwapID:       "0x1111111111111111111111111111111111111111111111111111111111111111",
+		UniqueSwapID: "1:0x1111111111111111111111111111111111111111111111111111111111111111:2",
+		SwapoutID:    "0x2222222222222222222222222222222222222222222222222222222222222222",
+		Token:        common.HexToAddress("0x3333333333333333333333333333333333333333"),
+		Receiver:     common.HexToAddress("0x5555555555555555555555555555555555555555"),
+		Amount:       big.NewInt(1234567890),
+		FromChainID:  big.NewInt(1),
+		CallProxy:    common.HexToAddress("0x6666666666666666666666666666666666666666"),
+		CallData:     common.FromHex("0x12345678abcdef"),
+	}
+	nonHexSwapID := *calldata
+	nonHexSwapID.SwapID = "swap-id-not-hex"
+
+	result := make(map[string]string)
+	add := func(name string, input []byte) {
+		result[name] = hexutil.Encode(input)
+	}
+	for _, c := range tokenCfgs {
+		add(fmt.Sprintf("anySwapIn/%v", c.name), PackSwapinCalldata("", c.tokenCfg, calldata))
+		add(fmt.Sprintf("anySwapIn/v7/%v", c.name), PackSwapinCalldata("v7", c.tokenCfg, calldata))
+		add(fmt.Sprintf("anySwapInAndExec/v7/%v", c.name), PackSwapinAndExecCalldata(c.tokenCfg, calldata))
+	}
+	add("anySwapIn/nonHexSwapID", PackSwapinCalldata("", tokenCfgs[0].tokenCfg, &nonHexSwapID))
+	add("mixPoolAnySwapIn", PackMixPoolSwapinCalldata(calldata))
+
+	claimCalldata := *calldata
+	claimCalldata.CallData = PackClaimCallData(calldata.UniqueSwapID, calldata.Receiver, 7*24*3600)
+	add("claimAnySwapIn/v7", PackSwapinAndExecCalldata(tokenCfgs[0].tokenCfg, &claimCalldata))
+	return result
+}
+
+func TestCalldataGolden(t *testing.T) {
+	got := buildGoldenCalldata()
+
+	var golden calldataGolden
+	data, err := os.ReadFile(calldataGoldenFile)
+	if err != nil && !*updateGolden {
+		t.Fatalf("read golden file failed: %v", err)
+	}
+	if err == nil {
+		if err = json.Unmarshal(data, &golden); err != nil {
+			t.Fatalf("parse golden file failed: %v", err)
+		}
+	}
+
+	// new cases can be added without increasing the builder version
+	var changed, added []string
+	for name, input := range got {
+		if want, exist := golden.Calldata[name]; !exist {
+			added = append(added, name)
+		} else if want != input {
+			changed = append(changed, name)
+		}
+	}
+	for name := range golden.Calldata {
+		if _, exist := got[name]; !exist {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(added)
+
+	if *updateGolden {
+		if len(changed) > 0 && golden.Calldata != nil && golden.Version == CalldataBuilderVersion {
+			t.Fatalf("calldata of %v changed, increase CalldataBuilderVersion before updating golden file", changed)
+		}
+		golden = calldataGolden{Version: CalldataBuilderVersion, Calldata: got}
+		data, err = json.MarshalIndent(golden, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(calldataGoldenFile, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	if golden.Version != CalldataBuilderVersion {
+		t.Errorf("golden file version %v mismatch builder version %v, update golden file", golden.Version, CalldataBuilderVersion)
+	}
+	for _, name := range changed {
+		t.Errorf("calldata of %v changed, have %v want %v", name, got[name], golden.Calldata[name])
+	}
+	for _, name := range added {
+		t.Errorf("calldata of %v is not in golden file, update golden file", name)
+	}
+}
@@ -0,0 +1,174 @@
+package eth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+)
+
+// two-phase swap model:
+// swapins of claim tokens call router v7 `anySwapInAndExec` with the claim escrow
+// as the anycall proxy, the router transfers the funds to the escrow and calls
+// `exec(token, receiver, amount, data)` where data is abi.encode(claimID, refundTo, claimPeriod).
+// the escrow keeps the funds claimable by the receiver until block time + claimPeriod,
+// the receiver (or a relayer) finalizes with `claim(string claimID)`,
+// and after expiry `refund(string claimID)` transfers the funds to refundTo.
+var (
+	// getClaim(string claimID) returns (uint8 status, uint256 expiry, address token)
+	getClaimFuncHash = common.FromHex("0x2bb81546")
+	// refund(string claimID)
+	refundClaimFuncHash = common.FromHex("0xfe5f2e88")
+
+	errClaimSwapNotEnabled = errors.New("claim swap is not enabled")
+	errClaimNotRefundable  = errors.New("claim is not refundable")
+	errClaimNoRefundTo     = errors.New("claim swap has no refund address")
+)
+
+// isClaimSwap is swapin of tokenID claimed in two phases
+func (b *Bridge) isClaimSwap(tokenID string) bool {
+	return params.IsClaimSwap(b.ChainConfig.ChainID, tokenID)
+}
+
+func (b *Bridge) buildClaimSwapinTxInput(args *tokens.BuildTxArgs, multichainToken string) (err error) {
+	cfg := params.GetClaimSwapConfig(b.ChainConfig.ChainID)
+	if cfg == nil {
+		return errClaimSwapNotEnabled
+	}
+	erc20SwapInfo := args.ERC20SwapInfo
+	if erc20SwapInfo.CallProxy != "" {
+		return errors.New("claim swap does not support call proxy")
+	}
+
+	receiver, amount, err := b.getReceiverAndAmount(args, multichainToken)
+	if err != nil {
+		return err
+	}
+
+	toTokenCfg := b.GetTokenConfig(multichainToken)
+	if toTokenCfg == nil {
+		return tokens.ErrMissTokenConfig
+	}
+
+	if b.GetRouterVersion(multichainToken) != "v7" {
+		return tokens.ErrRouterVersionMismatch
+	}
+
+	refundTo, err := b.getClaimRefundAddress(args, cfg)
+	if err != nil {
+		return err
+	}
+
+	calldata := newSwapinCalldata(args, multichainToken, receiver, amount)
+	calldata.CallProxy = common.HexToAddress(cfg.Escrow)
+	calldata.CallData = PackClaimCallData(calldata.UniqueSwapID, refundTo, cfg.GetClaimPeriod())
+	input := PackSwapinAndExecCalldata(toTokenCfg, calldata)
+	args.Input = (*hexutil.Bytes)(&input)          // input
+	args.To = b.GetRouterContract(multichainToken) // to
+	args.SwapValue = amount                        // swapValue
+
+	return nil
+}
+
+// getClaimRefundAddress refund to the refund address if configured,
+// otherwise refund to the sender if it is a valid address of this chain.
+func (b *Bridge) getClaimRefundAddress(args *tokens.BuildTxArgs, cfg *params.ClaimSwapConfig) (common.Address, error) {
+	if cfg.RefundAddress != "" {
+		return common.HexToAddress(cfg.RefundAddress), nil
+	}
+	if args.OriginFrom != "" && b.IsValidAddress(args.OriginFrom) {
+		return common.HexToAddress(args.OriginFrom), nil
+	}
+	return common.Address{}, errClaimNoRefundTo
+}
+
+// GetClaimInfo get onchain info of claimable swap
+func (b *Bridge) GetClaimInfo(claimID string) (*tokens.ClaimInfo, error) {
+	cfg := params.GetClaimSwapConfig(b.ChainConfig.ChainID)
+	if cfg == nil {
+		return nil, errClaimSwapNotEnabled
+	}
+	data := abicoder.PackDataWithFuncHash(getClaimFuncHash, claimID)
+	res, err := b.CallContract(cfg.Escrow, data, "latest")
+	if err != nil {
+		return nil, err
+	}
+	result := common.FromHex(res)
+	if len(result) < 96 {
+		return nil, abicoder.ErrParseDataError
+	}
+	return &tokens.ClaimInfo{
+		Status: tokens.ClaimStatus(common.GetBigInt(result, 0, 32).Uint64()),
+		Expiry: common.GetBigInt(result, 32, 32).Int64(),
+		Token:  common.BytesToAddress(common.GetData(result, 64, 32)).LowerHex(),
+	}, nil
+}
+
+// verifyClaimRefund verify claim refund.
+// refunding only returns expired claims to the refund address,
+// so we only need to check the onchain claim status here.
+func (b *Bridge) verifyClaimRefund(claimID string) (*tokens.SwapTxInfo, error) {
+	claim, err := b.GetClaimInfo(claimID)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Status != tokens.ClaimPending || claim.Expiry > time.Now().Unix() {
+		return nil, errClaimNotRefundable
+	}
+	tokenCfg := b.getTokenConfigByAddress(common.HexToAddress(claim.Token))
+	if tokenCfg == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	chainID := b.ChainConfig.GetChainID()
+	swapInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}}
+	swapInfo.SwapType = tokens.ClaimRefundType
+	swapInfo.Hash = claimID
+	swapInfo.FromChainID = chainID
+	swapInfo.ToChainID = chainID
+	swapInfo.ERC20SwapInfo.Token = tokenCfg.ContractAddress
+	swapInfo.ERC20SwapInfo.TokenID = tokenCfg.TokenID
+	return swapInfo, nil
+}
+
+func (b *Bridge) buildClaimRefundTxInput(args *tokens.BuildTxArgs) error {
+	cfg := params.GetClaimSwapConfig(b.ChainConfig.ChainID)
+	if cfg == nil {
+		return errClaimSwapNotEnabled
+	}
+	if _, err := b.verifyClaimRefund(args.SwapID); err != nil {
+		return err
+	}
+	input := abicoder.PackDataWithFuncHash(refundClaimFuncHash, args.SwapID)
+	args.Input = (*hexutil.Bytes)(&input) // input
+	args.To = cfg.Escrow                  // to
+	return nil
+}
+
+// GetClaimRefundArgs get build args of refunding expired claim to the refund address
+func (b *Bridge) GetClaimRefundArgs(claimID string) (*tokens.BuildTxArgs, error) {
+	swapInfo, err := b.verifyClaimRefund(claimID)
+	if err != nil {
+		return nil, err
+	}
+	routerMPC, err := router.GetRouterMPC(swapInfo.ERC20SwapInfo.TokenID, b.ChainConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	return &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapInfo:    swapInfo.SwapInfo,
+			Identifier:  params.GetIdentifier(),
+			Salt:        params.GetDeploymentSalt(),
+			SwapID:      claimID,
+			SwapType:    tokens.ClaimRefundType,
+			FromChainID: swapInfo.FromChainID,
+			ToChainID:   swapInfo.ToChainID,
+		},
+		From: routerMPC,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package eth
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+)
+
+func TestClaimFuncHashes(t *testing.T) {
+	for sig, funcHash := range map[string][]byte{
+		"getClaim(string)": getClaimFuncHash,
+		"refund(string)":   refundClaimFuncHash,
+	} {
+		if want := crypto.Keccak256([]byte(sig))[:4]; !bytes.Equal(funcHash, want) {
+			t.Errorf("func hash of %v got %x, want %x", sig, funcHash, want)
+		}
+	}
+}
+
+func TestPackClaimCallData(t *testing.T) {
+	refundTo := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	data := PackClaimCallData("1:0xab:0", refundTo, 86400)
+	want := common.FromHex("0x" +
+		"0000000000000000000000000000000000000000000000000000000000000060" +
+		"0000000000000000000000001111111111111111111111111111111111111111" +
+		"0000000000000000000000000000000000000000000000000000000000015180" +
+		"0000000000000000000000000000000000000000000000000000000000000008" +
+		"313a307861623a30000000000000000000000000000000000000000000000000")
+	if !bytes.Equal(data, want) {
+		t.Errorf("claim call data got %x, want %x", data, want)
+	}
+}
+
+func TestGetClaimRefundAddress(t *testing.T) {
+	b := NewCrossChainBridge()
+	sender := "0x2222222222222222222222222222222222222222"
+	refundAddress := "0x3333333333333333333333333333333333333333"
+
+	tests := []struct {
+		refundAddress string
+		originFrom    string
+		want          string
+		err           error
+	}{
+		{refundAddress, sender, refundAddress, nil},
+		{"", sender, sender, nil},
+		{"", "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", "", errClaimNoRefundTo},
+		{"", "", "", errClaimNoRefundTo},
+	}
+	for _, test := range tests {
+		cfg := &params.ClaimSwapConfig{RefundAddress: test.refundAddress}
+		args := &tokens.BuildTxArgs{OriginFrom: test.originFrom}
+		refundTo, err := b.getClaimRefundAddress(args, cfg)
+		if !errors.Is(err, test.err) {
+			t.Errorf("get refund address of %+v got error %v, want %v", test, err, test.err)
+			continue
+		}
+		if err == nil && refundTo != common.HexToAddress(test.want) {
+			t.Errorf("get refund address of %+v got %v, want %v", test, refundTo.LowerHex(), test.want)
+		}
+	}
+}
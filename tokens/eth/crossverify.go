@@ -0,0 +1,114 @@
+package eth
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+// crossVerifyReceipt fetch receipt from every gateway endpoint and compare
+// with the verified receipt, to protect against a single malicious or
+// compromised endpoint fabricating deposits.
+func (b *Bridge) crossVerifyReceipt(txHash string, receipt *types.RPCTxReceipt) error {
+	cfg := params.GetReceiptCrossVerifyConfig(b.ChainConfig.ChainID)
+	if cfg == nil {
+		return nil
+	}
+	return b.crossVerifyReceiptWith(txHash, receipt, cfg.GetMinProviders())
+}
+
+// CrossVerifyTx impl tokens.TxCrossVerifier
+// cross verify receipt of tx regardless of the swap value.
+func (b *Bridge) CrossVerifyTx(txHash string, quorum int) error {
+	receipt, err := b.GetTransactionReceipt(txHash)
+	if err != nil || receipt == nil {
+		return fmt.Errorf("%w: get receipt failed, %v", tokens.ErrRPCQueryError, err)
+	}
+	return b.crossVerifyReceiptWith(txHash, receipt, quorum)
+}
+
+func (b *Bridge) crossVerifyReceiptWith(txHash string, receipt *types.RPCTxReceipt, minProviders int) error {
+	urls := b.GatewayConfig.AllGatewayURLs
+
+	agreed := 0
+	for _, url := range urls {
+		var result *types.RPCTxReceipt
+		err := client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "eth_getTransactionReceipt", txHash)
+		if err != nil || result == nil {
+			log.Warn("cross verify receipt query failed", "chainID", b.ChainConfig.ChainID, "txid", txHash, "url", url, "err", err)
+			continue
+		}
+		if field := diffReceipt(receipt, result); field != "" {
+			log.Error("cross verify receipt diverges", "chainID", b.ChainConfig.ChainID, "txid", txHash, "url", url, "field", field)
+			return fmt.Errorf("%w: %v differs from %v", tokens.ErrReceiptDivergence, field, url)
+		}
+		agreed++
+	}
+	if agreed < minProviders {
+		log.Warn("cross verify receipt without enough providers", "chainID", b.ChainConfig.ChainID, "txid", txHash, "agreed", agreed, "minProviders", minProviders, "urls", len(urls))
+		return fmt.Errorf("%w: only %v of %v providers agree on receipt, require %v", tokens.ErrRPCQueryError, agreed, len(urls), minProviders)
+	}
+	log.Info("cross verify receipt success", "chainID", b.ChainConfig.ChainID, "txid", txHash, "agreed", agreed)
+	return nil
+}
+
+// diffReceipt return the name of the first differing field (empty if same)
+//
+//nolint:gocyclo // allow long compare
+func diffReceipt(a, b *types.RPCTxReceipt) string {
+	switch {
+	case a.TxHash == nil || b.TxHash == nil || *a.TxHash != *b.TxHash:
+		return "transactionHash"
+	case a.TxIndex == nil || b.TxIndex == nil || *a.TxIndex != *b.TxIndex:
+		return "transactionIndex"
+	case a.BlockHash == nil || b.BlockHash == nil || *a.BlockHash != *b.BlockHash:
+		return "blockHash"
+	case a.BlockNumber == nil || b.BlockNumber == nil ||
+		a.BlockNumber.ToInt().Cmp(b.BlockNumber.ToInt()) != 0:
+		return "blockNumber"
+	case a.Status == nil || b.Status == nil || *a.Status != *b.Status:
+		return "status"
+	case a.From == nil || b.From == nil || *a.From != *b.From:
+		return "from"
+	case (a.Recipient == nil) != (b.Recipient == nil) ||
+		(a.Recipient != nil && *a.Recipient != *b.Recipient):
+		return "to"
+	case len(a.Logs) != len(b.Logs):
+		return "logs"
+	}
+	for i, alog := range a.Logs {
+		if !isSameLog(alog, b.Logs[i]) {
+			return fmt.Sprintf("logs[%d]", i)
+		}
+	}
+	return ""
+}
+
+func isSameLog(a, b *types.RPCLog) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Address == nil || b.Address == nil || *a.Address != *b.Address {
+		return false
+	}
+	if a.Data == nil || b.Data == nil || !bytes.Equal(*a.Data, *b.Data) {
+		return false
+	}
+	if (a.Removed != nil && *a.Removed) != (b.Removed != nil && *b.Removed) {
+		return false
+	}
+	if len(a.Topics) != len(b.Topics) {
+		return false
+	}
+	for i, topic := range a.Topics {
+		if topic != b.Topics[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+func newTestReceipt() *types.RPCTxReceipt {
+	txHash := common.HexToHash("0x01")
+	blockHash := common.HexToHash("0x02")
+	txIndex := hexutil.Uint(3)
+	status := hexutil.Uint64(1)
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress(tRouterAddress)
+	logAddr := common.HexToAddress(tRouterAddress)
+	data := hexutil.Bytes(common.FromHex("0x1234"))
+	return &types.RPCTxReceipt{
+		TxHash:      &txHash,
+		TxIndex:     &txIndex,
+		BlockNumber: (*hexutil.Big)(big.NewInt(100)),
+		BlockHash:   &blockHash,
+		Status:      &status,
+		From:        &from,
+		Recipient:   &to,
+		Logs: []*types.RPCLog{
+			{
+				Address: &logAddr,
+				Topics:  []common.Hash{common.BytesToHash(LogAnySwapOutTopic)},
+				Data:    &data,
+			},
+		},
+	}
+}
+
+func TestDiffReceipt(t *testing.T) {
+	tests := []struct {
+		modify func(r *types.RPCTxReceipt)
+		want   string
+	}{
+		{func(r *types.RPCTxReceipt) {}, ""},
+		{func(r *types.RPCTxReceipt) {
+			h := common.HexToHash("0x03")
+			r.BlockHash = &h
+		}, "blockHash"},
+		{func(r *types.RPCTxReceipt) { r.BlockNumber = (*hexutil.Big)(big.NewInt(101)) }, "blockNumber"},
+		{func(r *types.RPCTxReceipt) { r.Status = nil }, "status"},
+		{func(r *types.RPCTxReceipt) { r.Recipient = nil }, "to"},
+		{func(r *types.RPCTxReceipt) { r.Logs = nil }, "logs"},
+		{func(r *types.RPCTxReceipt) {
+			data := hexutil.Bytes(common.FromHex("0x5678"))
+			r.Logs[0].Data = &data
+		}, "logs[0]"},
+		{func(r *types.RPCTxReceipt) {
+			removed := true
+			r.Logs[0].Removed = &removed
+		}, "logs[0]"},
+	}
+	for i, test := range tests {
+		other := newTestReceipt()
+		test.modify(other)
+		if got := diffReceipt(newTestReceipt(), other); got != test.want {
+			t.Errorf("test %v: want '%v', got '%v'", i, test.want, got)
+		}
+	}
+}
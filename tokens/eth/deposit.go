@@ -0,0 +1,485 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+// deposit address model:
+// every (toChainID, bind) pair has a deposit address, which is the CREATE2 address
+// of a forwarder contract deployed by the factory with salt keccak256(abi.encode(toChainID, bind)).
+// users deposit to it by plain native or token transfers, the deposits are credited
+// as swaps to the bind address, and the factory's `sweep` deploys the forwarder
+// if necessary and forwards the deposited funds to the mpc address.
+var (
+	// sweep(bytes32 salt, address token), token is zero address for native
+	depositSweepFuncHash = common.FromHex("0x4aef4070")
+
+	errDepositNotEnabled    = errors.New("deposit address is not enabled")
+	errNotDepositSwapTx     = errors.New("not deposit swap tx")
+	errWrongDepositSweepID  = errors.New("wrong deposit sweep id")
+	errDepositNotRegistered = errors.New("deposit address is not registered")
+	errNoDepositFunds       = errors.New("deposit address has no funds to sweep")
+)
+
+// CalcDepositSalt calc deposit forwarder salt
+func CalcDepositSalt(toChainID *big.Int, bind string) common.Hash {
+	return common.Keccak256Hash(abicoder.PackData(toChainID, bind))
+}
+
+// CalcCreate2Address calc CREATE2 contract address
+func CalcCreate2Address(deployer common.Address, salt, initCodeHash common.Hash) common.Address {
+	hash := common.Keccak256Hash([]byte{0xff}, deployer.Bytes(), salt.Bytes(), initCodeHash.Bytes())
+	return common.BytesToAddress(hash.Bytes()[12:])
+}
+
+// IsDepositAddressEnabled is deposit address enabled on this chain
+func (b *Bridge) IsDepositAddressEnabled() bool {
+	return params.GetDepositFactoryConfig(b.ChainConfig.ChainID) != nil
+}
+
+// GetDepositAddress get deposit address and salt of (toChainID, bind)
+func (b *Bridge) GetDepositAddress(toChainID *big.Int, bind string) (common.Address, common.Hash, error) {
+	cfg := params.GetDepositFactoryConfig(b.ChainConfig.ChainID)
+	if cfg == nil {
+		return common.Address{}, common.Hash{}, errDepositNotEnabled
+	}
+	salt := CalcDepositSalt(toChainID, bind)
+	address := CalcCreate2Address(common.HexToAddress(cfg.Factory), salt, common.HexToHash(cfg.ForwarderInitCodeHash))
+	return address, salt, nil
+}
+
+// RegisterDepositAddress register deposit address of (toChainID, bind)
+func (b *Bridge) RegisterDepositAddress(toChainIDStr, bind string) (string, error) {
+	toChainID, err := common.GetBigIntFromStr(toChainIDStr)
+	if err != nil {
+		return "", fmt.Errorf("wrong toChainID %v", toChainIDStr)
+	}
+	if toChainID.String() == b.ChainConfig.ChainID {
+		return "", tokens.ErrSameFromAndToChainID
+	}
+	dstBridge := router.GetBridgeByChainID(toChainID.String())
+	if dstBridge == nil {
+		return "", tokens.ErrNoBridgeForChainID
+	}
+	if !dstBridge.IsValidAddress(bind) {
+		return "", tokens.ErrWrongBindAddress
+	}
+	address, salt, err := b.GetDepositAddress(toChainID, bind)
+	if err != nil {
+		return "", err
+	}
+	depositAddress := address.LowerHex()
+	if mongodb.HasClient() {
+		err = mongodb.AddDepositAddress(&mongodb.MgoDepositAddress{
+			ChainID:   b.ChainConfig.ChainID,
+			Address:   depositAddress,
+			Salt:      salt.Hex(),
+			ToChainID: toChainID.String(),
+			Bind:      bind,
+			Timestamp: common.NowMilli(),
+		})
+		if err != nil && !errors.Is(err, mongodb.ErrItemIsDup) {
+			return "", err
+		}
+	}
+	return depositAddress, nil
+}
+
+// resolveDepositBind resolve the (toChainID, bind) of deposit address.
+// the registered record in database is used first, otherwise the verify hints are used.
+// returns errNotDepositSwapTx if the address is not a deposit address.
+func (b *Bridge) resolveDepositBind(depositAddress common.Address, hints *tokens.VerifyArgs) (toChainID *big.Int, bind string, err error) {
+	fromDB := mongodb.HasClient()
+	if fromDB {
+		rec, errf := mongodb.FindDepositAddress(b.ChainConfig.ChainID, depositAddress.LowerHex())
+		if errf != nil {
+			if errors.Is(errf, mongodb.ErrItemNotFound) {
+				return nil, "", errNotDepositSwapTx
+			}
+			return nil, "", errf
+		}
+		toChainID, err = common.GetBigIntFromStr(rec.ToChainID)
+		if err != nil {
+			return nil, "", err
+		}
+		bind = rec.Bind
+	} else {
+		if hints == nil || hints.Bind == "" || hints.ToChainID == nil {
+			return nil, "", errNotDepositSwapTx
+		}
+		toChainID, bind = hints.ToChainID, hints.Bind
+	}
+	derived, _, err := b.GetDepositAddress(toChainID, bind)
+	if err != nil {
+		return nil, "", err
+	}
+	if derived != depositAddress {
+		if fromDB {
+			log.Error("deposit address record mismatch", "chainID", b.ChainConfig.ChainID, "address", depositAddress.LowerHex(), "derived", derived.LowerHex(), "toChainID", toChainID, "bind", bind)
+			return nil, "", tokens.ErrWrongBindAddress
+		}
+		return nil, "", errNotDepositSwapTx
+	}
+	return toChainID, bind, nil
+}
+
+// getTokenConfigByAddress get token config of token or underlying (zero address for native)
+func (b *Bridge) getTokenConfigByAddress(token common.Address) *tokens.TokenConfig {
+	tokenAddr := token.LowerHex()
+	if token == (common.Address{}) {
+		routerInfo := router.GetRouterInfo(b.ChainConfig.RouterContract, b.ChainConfig.ChainID)
+		if routerInfo == nil || routerInfo.RouterWNative == "" {
+			return nil
+		}
+		tokenAddr = routerInfo.RouterWNative
+	}
+	var contractAddress string
+	b.TokenConfigMap.Range(func(k, v interface{}) bool {
+		tokenCfg := v.(*tokens.TokenConfig)
+		if common.IsEqualIgnoreCase(tokenCfg.ContractAddress, tokenAddr) ||
+			common.IsEqualIgnoreCase(tokenCfg.GetUnderlying(), tokenAddr) {
+			contractAddress = tokenCfg.ContractAddress
+			return false
+		}
+		return true
+	})
+	if contractAddress == "" {
+		return nil
+	}
+	return b.GetTokenConfig(contractAddress)
+}
+
+// parseDepositSwap parse deposit swap at log index.
+// native deposit is a tx sent to deposit address directly with log index 0.
+func (b *Bridge) parseDepositSwap(swapInfo *tokens.SwapTxInfo, receipt *types.RPCTxReceipt, hints *tokens.VerifyArgs) (err error) {
+	var (
+		depositAddress common.Address
+		token          common.Address
+		sender         common.Address
+		amount         *big.Int
+		toChainID      *big.Int
+		bind           string
+	)
+
+	logIndex := swapInfo.LogIndex
+	isNative := false
+	if logIndex == 0 && receipt.Recipient != nil {
+		toChainID, bind, err = b.resolveDepositBind(*receipt.Recipient, hints)
+		switch {
+		case err == nil:
+			tx, errt := b.EvmContractBridge.GetTransactionByHash(swapInfo.Hash)
+			if errt != nil {
+				return errt
+			}
+			if tx.Amount == nil || tx.Amount.ToInt().Sign() <= 0 {
+				return tokens.ErrTxWithWrongValue
+			}
+			isNative = true
+			depositAddress = *receipt.Recipient
+			sender = *receipt.From
+			amount = tx.Amount.ToInt()
+		case !errors.Is(err, errNotDepositSwapTx):
+			return err
+		}
+	}
+
+	if !isNative {
+		if logIndex < 0 || logIndex >= len(receipt.Logs) {
+			return errNotDepositSwapTx
+		}
+		rlog := receipt.Logs[logIndex]
+		if rlog == nil || rlog.Address == nil || len(rlog.Topics) != 3 ||
+			rlog.Topics[0] != LogTokenTransferTopics[0] ||
+			rlog.Data == nil || len(*rlog.Data) < 32 {
+			return errNotDepositSwapTx
+		}
+		if rlog.Removed != nil && *rlog.Removed {
+			return tokens.ErrTxWithRemovedLog
+		}
+		depositAddress = common.BytesToAddress(rlog.Topics[2].Bytes())
+		toChainID, bind, err = b.resolveDepositBind(depositAddress, hints)
+		if err != nil {
+			return err
+		}
+		token = *rlog.Address
+		sender = common.BytesToAddress(rlog.Topics[1].Bytes())
+		amount = common.GetBigInt(*rlog.Data, 0, 32)
+	}
+
+	tokenCfg := b.getTokenConfigByAddress(token)
+	if tokenCfg == nil {
+		log.Warn("deposit token config not found", "chainID", b.ChainConfig.ChainID, "token", token.LowerHex(), "txid", swapInfo.Hash, "logIndex", logIndex)
+		return tokens.ErrMissTokenConfig
+	}
+
+	swapInfo.ERC20SwapInfo.Token = tokenCfg.ContractAddress // Token
+	swapInfo.ERC20SwapInfo.TokenID = tokenCfg.TokenID       // TokenID
+	swapInfo.From = sender.LowerHex()                       // From
+	swapInfo.To = depositAddress.LowerHex()                 // To
+	swapInfo.Bind = bind                                    // Bind
+	swapInfo.Value = amount                                 // Value
+	swapInfo.FromChainID = b.ChainConfig.GetChainID()       // FromChainID
+	swapInfo.ToChainID = toChainID                          // ToChainID
+	return nil
+}
+
+func (b *Bridge) checkDepositSwapInfo(swapInfo *tokens.SwapTxInfo) error {
+	err := b.checkERC20SwapRoute(swapInfo)
+	if err != nil {
+		return err
+	}
+	if params.IsSwapoutForbidden(b.ChainConfig.ChainID, swapInfo.ERC20SwapInfo.TokenID) {
+		return tokens.ErrSwapoutForbidden
+	}
+	return nil
+}
+
+// verifyDepositSwapTx verify deposit swap, returns errNotDepositSwapTx if it's not
+func (b *Bridge) verifyDepositSwapTx(txHash string, args *tokens.VerifyArgs) (*tokens.SwapTxInfo, error) {
+	swapInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}}
+	swapInfo.SwapType = tokens.ERC20SwapType // SwapType
+	swapInfo.Hash = strings.ToLower(txHash)  // Hash
+	swapInfo.LogIndex = args.LogIndex        // LogIndex
+
+	receipt, err := b.getSwapTxReceipt(swapInfo, args.AllowUnstable)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.parseDepositSwap(swapInfo, receipt, args)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.checkDepositSwapInfo(swapInfo)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	if !args.AllowUnstable {
+		log.Info("verify deposit swap tx stable pass",
+			"identifier", params.GetIdentifier(),
+			"from", swapInfo.From, "depositAddress", swapInfo.To,
+			"bind", swapInfo.Bind, "value", swapInfo.Value,
+			"txid", txHash, "logIndex", args.LogIndex,
+			"height", swapInfo.Height, "timestamp", swapInfo.Timestamp,
+			"fromChainID", swapInfo.FromChainID, "toChainID", swapInfo.ToChainID,
+			"token", swapInfo.ERC20SwapInfo.Token, "tokenID", swapInfo.ERC20SwapInfo.TokenID)
+	}
+
+	return swapInfo, nil
+}
+
+func (b *Bridge) registerDepositSwapTx(commonInfo *tokens.SwapTxInfo, receipt *types.RPCTxReceipt, logIndex int) ([]*tokens.SwapTxInfo, []error) {
+	swapInfos := make([]*tokens.SwapTxInfo, 0)
+	errs := make([]error, 0)
+	startIndex, endIndex := 0, len(receipt.Logs)
+	if endIndex == 0 {
+		endIndex = 1 // native deposit has no logs
+	}
+
+	if logIndex != 0 {
+		startIndex = logIndex
+		endIndex = logIndex + 1
+	}
+
+	for i := startIndex; i < endIndex; i++ {
+		swapInfo := &tokens.SwapTxInfo{}
+		*swapInfo = *commonInfo
+		swapInfo.ERC20SwapInfo = &tokens.ERC20SwapInfo{}
+		swapInfo.LogIndex = i // LogIndex
+		err := b.parseDepositSwap(swapInfo, receipt, nil)
+		switch {
+		case errors.Is(err, errNotDepositSwapTx):
+			continue
+		case err == nil:
+			err = b.checkDepositSwapInfo(swapInfo)
+		default:
+			log.Info(b.ChainConfig.BlockChain+" register deposit swap error", "txHash", swapInfo.Hash, "logIndex", swapInfo.LogIndex, "err", err)
+		}
+		swapInfos = append(swapInfos, swapInfo)
+		errs = append(errs, err)
+	}
+
+	if len(swapInfos) == 0 {
+		return []*tokens.SwapTxInfo{commonInfo}, []error{tokens.ErrSwapoutLogNotFound}
+	}
+
+	return swapInfos, errs
+}
+
+// getDepositSweepID deposit sweep id is composed of salt and token
+func getDepositSweepID(salt common.Hash, token common.Address) string {
+	return salt.Hex() + ":" + token.LowerHex()
+}
+
+func parseDepositSweepID(sweepID string) (salt common.Hash, token common.Address, err error) {
+	parts := strings.Split(sweepID, ":")
+	if len(parts) != 2 ||
+		len(common.FromHex(parts[0])) != common.HashLength ||
+		!common.IsHexAddress(parts[1]) {
+		return salt, token, errWrongDepositSweepID
+	}
+	return common.HexToHash(parts[0]), common.HexToAddress(parts[1]), nil
+}
+
+// verifyDepositSweep verify deposit sweep.
+// sweeping only forwards funds of forwarders to the mpc address,
+// the salt must be of a registered deposit address which has funds of the token.
+func (b *Bridge) verifyDepositSweep(sweepID string) (*tokens.SwapTxInfo, error) {
+	cfg := params.GetDepositFactoryConfig(b.ChainConfig.ChainID)
+	if cfg == nil {
+		return nil, errDepositNotEnabled
+	}
+	salt, token, err := parseDepositSweepID(sweepID)
+	if err != nil {
+		return nil, err
+	}
+	tokenCfg := b.getTokenConfigByAddress(token)
+	if tokenCfg == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	depositAddress := CalcCreate2Address(common.HexToAddress(cfg.Factory), salt, common.HexToHash(cfg.ForwarderInitCodeHash))
+	if mongodb.HasClient() {
+		rec, errf := mongodb.FindDepositAddress(b.ChainConfig.ChainID, depositAddress.LowerHex())
+		if errf != nil {
+			if errors.Is(errf, mongodb.ErrItemNotFound) {
+				return nil, errDepositNotRegistered
+			}
+			return nil, errf
+		}
+		if common.HexToHash(rec.Salt) != salt {
+			return nil, errDepositNotRegistered
+		}
+	}
+	balance, err := b.getDepositBalance(depositAddress, token)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Sign() <= 0 {
+		return nil, errNoDepositFunds
+	}
+	chainID := b.ChainConfig.GetChainID()
+	swapInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}}
+	swapInfo.SwapType = tokens.DepositSweepType
+	swapInfo.Hash = sweepID
+	swapInfo.FromChainID = chainID
+	swapInfo.ToChainID = chainID
+	swapInfo.ERC20SwapInfo.Token = tokenCfg.ContractAddress
+	swapInfo.ERC20SwapInfo.TokenID = tokenCfg.TokenID
+	return swapInfo, nil
+}
+
+func (b *Bridge) getDepositBalance(depositAddress, token common.Address) (*big.Int, error) {
+	if token == (common.Address{}) {
+		return b.GetBalance(depositAddress.LowerHex())
+	}
+	return b.GetErc20Balance(token.LowerHex(), depositAddress.LowerHex())
+}
+
+func (b *Bridge) buildDepositSweepTxInput(args *tokens.BuildTxArgs) error {
+	cfg := params.GetDepositFactoryConfig(b.ChainConfig.ChainID)
+	if cfg == nil {
+		return errDepositNotEnabled
+	}
+	if _, err := b.verifyDepositSweep(args.SwapID); err != nil {
+		return err
+	}
+	salt, token, _ := parseDepositSweepID(args.SwapID)
+	input := abicoder.PackDataWithFuncHash(depositSweepFuncHash, salt, token)
+	args.Input = (*hexutil.Bytes)(&input) // input
+	args.To = cfg.Factory                 // to
+	return nil
+}
+
+// ScanDepositFunds find the tokens (zero address for native) of which
+// the registered deposit address has funds to sweep
+func (b *Bridge) ScanDepositFunds(depositAddress string) (fundTokens []string, err error) {
+	if !b.IsDepositAddressEnabled() {
+		return nil, errDepositNotEnabled
+	}
+	rec, err := mongodb.FindDepositAddress(b.ChainConfig.ChainID, depositAddress)
+	if err != nil {
+		return nil, err
+	}
+	address := common.HexToAddress(rec.Address)
+
+	balance, err := b.getDepositBalance(address, common.Address{})
+	if err != nil {
+		return nil, err
+	}
+	if balance.Sign() > 0 {
+		fundTokens = append(fundTokens, common.Address{}.LowerHex())
+	}
+	exist := make(map[string]struct{})
+	b.TokenConfigMap.Range(func(k, v interface{}) bool {
+		tokenCfg := v.(*tokens.TokenConfig)
+		token := tokenCfg.GetUnderlying()
+		if token == "" {
+			token = tokenCfg.ContractAddress
+		}
+		key := strings.ToLower(token)
+		if _, ok := exist[key]; ok {
+			return true
+		}
+		exist[key] = struct{}{}
+		balance, err = b.getDepositBalance(address, common.HexToAddress(token))
+		if err != nil {
+			return false
+		}
+		if balance.Sign() > 0 {
+			fundTokens = append(fundTokens, key)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fundTokens, nil
+}
+
+// GetDepositSweepArgs get build args of the tx sweeping token of deposit address
+func (b *Bridge) GetDepositSweepArgs(depositAddress, token string, sequence int) (*tokens.BuildTxArgs, error) {
+	if params.IsParallelSwapEnabledOnChain(b.ChainConfig.ChainID) {
+		return nil, errors.New("deposit sweep is not supported in parallel swap mode")
+	}
+	rec, err := mongodb.FindDepositAddress(b.ChainConfig.ChainID, depositAddress)
+	if err != nil {
+		return nil, err
+	}
+	sweepID := getDepositSweepID(common.HexToHash(rec.Salt), common.HexToAddress(token))
+	swapInfo, err := b.verifyDepositSweep(sweepID)
+	if err != nil {
+		return nil, err
+	}
+	routerMPC, err := router.GetRouterMPC(swapInfo.ERC20SwapInfo.TokenID, b.ChainConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	return &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapInfo:    swapInfo.SwapInfo,
+			Identifier:  params.GetIdentifier(),
+			Salt:        params.GetDeploymentSalt(),
+			SwapID:      sweepID,
+			SwapType:    tokens.DepositSweepType,
+			LogIndex:    sequence,
+			FromChainID: swapInfo.FromChainID,
+			ToChainID:   swapInfo.ToChainID,
+		},
+		From: routerMPC,
+	}, nil
+}
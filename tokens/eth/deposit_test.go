@@ -0,0 +1,57 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+)
+
+func TestCalcCreate2Address(t *testing.T) {
+	// examples in EIP-1014
+	tests := []struct {
+		deployer string
+		salt     string
+		initCode string
+		want     string
+	}{
+		{
+			"0x0000000000000000000000000000000000000000",
+			"0x0000000000000000000000000000000000000000000000000000000000000000",
+			"0x00",
+			"0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38",
+		},
+		{
+			"0xdeadbeef00000000000000000000000000000000",
+			"0x000000000000000000000000feed000000000000000000000000000000000000",
+			"0x00",
+			"0xD04116cDd17beBE565EB2422F2497E06cC1C9833",
+		},
+		{
+			"0x00000000000000000000000000000000deadbeef",
+			"0x00000000000000000000000000000000000000000000000000000000cafebabe",
+			"0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			"0x1d8bfDC5D46DC4f61D6b6115972536eBE6A8854C",
+		},
+	}
+	for i, test := range tests {
+		initCodeHash := common.Keccak256Hash(common.FromHex(test.initCode))
+		got := CalcCreate2Address(common.HexToAddress(test.deployer), common.HexToHash(test.salt), initCodeHash)
+		if got != common.HexToAddress(test.want) {
+			t.Errorf("test %v: calc create2 address got %v, want %v", i, got.Hex(), test.want)
+		}
+	}
+}
+
+func TestParseDepositSweepID(t *testing.T) {
+	salt := CalcDepositSalt(common.Big1, "0x1111111111111111111111111111111111111111")
+	token := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	gotSalt, gotToken, err := parseDepositSweepID(getDepositSweepID(salt, token))
+	if err != nil || gotSalt != salt || gotToken != token {
+		t.Errorf("parse deposit sweep id failed, salt %v, token %v, err %v", gotSalt.Hex(), gotToken.Hex(), err)
+	}
+	for _, id := range []string{"", salt.Hex(), token.Hex() + ":" + salt.Hex(), "0x1234:" + token.Hex()} {
+		if _, _, err := parseDepositSweepID(id); err == nil {
+			t.Errorf("parse wrong deposit sweep id %q should fail", id)
+		}
+	}
+}
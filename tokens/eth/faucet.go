@@ -0,0 +1,85 @@
+package eth
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+)
+
+var (
+	// FaucetMintFuncHash mint(address,uint256)
+	FaucetMintFuncHash = common.FromHex("0x40c10f19")
+	// FaucetSwapOutFuncHash anySwapOut(address,address,uint256,uint256)
+	FaucetSwapOutFuncHash = common.FromHex("0x241dc2df")
+	// FaucetSwapOutStringFuncHash anySwapOut(address,string,uint256,uint256) of v6 and v7 router
+	FaucetSwapOutStringFuncHash = common.FromHex("0xc604b0b8")
+
+	errFaucetNotSupported = errors.New("faucet is not supported on account abstraction or zksync chain")
+)
+
+// GetFaucetAddress get address of faucet account
+func (b *Bridge) GetFaucetAddress(priKey string) (string, error) {
+	privKey, err := crypto.ToECDSA(common.FromHex(priKey))
+	if err != nil {
+		return "", err
+	}
+	return crypto.PubkeyToAddress(privKey.PublicKey).LowerHex(), nil
+}
+
+// BuildFaucetTx build tx minting test tokens to receiver,
+// or swapping out test tokens of faucet account to bind address on another chain.
+func (b *Bridge) BuildFaucetTx(args *tokens.FaucetTxArgs) (rawTx interface{}, err error) {
+	if b.IsAccountAbstraction() || b.IsZKSync() {
+		return nil, errFaucetNotSupported
+	}
+	tokenCfg := b.GetTokenConfig(args.Token)
+	if tokenCfg == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	var to string
+	var input []byte
+	if args.ToChainID == nil {
+		if !b.IsValidAddress(args.Receiver) {
+			return nil, tokens.ErrWrongBindAddress
+		}
+		to = tokenCfg.ContractAddress
+		input = abicoder.PackDataWithFuncHash(FaucetMintFuncHash,
+			common.HexToAddress(args.Receiver), args.Amount)
+	} else {
+		to = b.GetRouterContract(args.Token)
+		if to == "" {
+			return nil, tokens.ErrMissRouterInfo
+		}
+		if b.GetRouterVersion(args.Token) == "" {
+			input = abicoder.PackDataWithFuncHash(FaucetSwapOutFuncHash,
+				common.HexToAddress(tokenCfg.ContractAddress),
+				common.HexToAddress(args.Receiver),
+				args.Amount, args.ToChainID)
+		} else {
+			input = abicoder.PackDataWithFuncHash(FaucetSwapOutStringFuncHash,
+				common.HexToAddress(tokenCfg.ContractAddress),
+				args.Receiver, args.Amount, args.ToChainID)
+		}
+	}
+
+	nonce := args.Nonce
+	buildArgs := &tokens.BuildTxArgs{
+		From:  args.Faucet,
+		To:    to,
+		Input: (*hexutil.Bytes)(&input),
+		Extra: &tokens.AllExtras{Sequence: &nonce},
+	}
+	if args.GasLimit > 0 {
+		gasLimit := args.GasLimit
+		buildArgs.Extra.Gas = &gasLimit
+	}
+	err = b.setDefaults(buildArgs)
+	if err != nil {
+		return nil, err
+	}
+	return b.buildTx(buildArgs)
+}
@@ -0,0 +1,157 @@
+package eth
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+// gas limit learning:
+// the gas used by stable swap txs is sampled per token and swap method
+// (the 4 bytes selector of the tx input), the samples are persisted by the
+// swap server and loaded on restart, and the gas limit of
+// later swap txs of the same token and method is set from the percentile
+// of the samples, instead of over provisioning every swap tx with the
+// default gas limit, which inflates the worst case fee of balance checks.
+var (
+	// ensure Bridge impl tokens.GasUsageRecorder
+	_ tokens.GasUsageRecorder = &Bridge{}
+
+	gasSamples sync.Map // key is chainID:tokenID:method, value is *gasSampleRing
+)
+
+type gasSampleRing struct {
+	lock    sync.Mutex
+	samples []uint64
+	next    int
+}
+
+func (r *gasSampleRing) add(gasUsed uint64, size int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if len(r.samples) > size {
+		// keep the latest samples
+		ordered := make([]uint64, 0, len(r.samples))
+		ordered = append(ordered, r.samples[r.next:]...)
+		ordered = append(ordered, r.samples[:r.next]...)
+		r.samples = ordered[len(ordered)-size:]
+		r.next = 0
+	}
+	if len(r.samples) < size {
+		r.samples = append(r.samples, gasUsed)
+		return
+	}
+	r.samples[r.next] = gasUsed
+	r.next = (r.next + 1) % size
+}
+
+// snapshot get samples in the order of recording (oldest first)
+func (r *gasSampleRing) snapshot() []uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	samples := make([]uint64, 0, len(r.samples))
+	samples = append(samples, r.samples[r.next:]...)
+	samples = append(samples, r.samples[:r.next]...)
+	return samples
+}
+
+// percentile get the percentile of samples, ok is false if samples are not enough
+func (r *gasSampleRing) percentile(percent float64, minSamples int) (gas uint64, ok bool) {
+	r.lock.Lock()
+	sorted := make([]uint64, len(r.samples))
+	copy(sorted, r.samples)
+	r.lock.Unlock()
+
+	if len(sorted) == 0 || len(sorted) < minSamples {
+		return 0, false
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := int(math.Ceil(percent/100*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index], true
+}
+
+func getSwapMethod(input []byte) string {
+	if len(input) < 4 {
+		return ""
+	}
+	return common.ToHex(input[:4])
+}
+
+func getGasSampleKey(chainID, tokenID, method string) string {
+	return strings.ToLower(fmt.Sprintf("%v:%v:%v", chainID, tokenID, method))
+}
+
+// RecordGasUsed impl tokens.GasUsageRecorder
+func (b *Bridge) RecordGasUsed(tokenID, txHash string, receipt interface{}) *tokens.GasSamples {
+	cfg := params.GetGasLearningConfig(b.ChainConfig.ChainID)
+	if cfg == nil || tokenID == "" || b.IsAccountAbstraction() {
+		return nil
+	}
+	txr, ok := receipt.(*types.RPCTxReceipt)
+	if !ok || !txr.IsStatusOk() || txr.GasUsed == nil {
+		return nil
+	}
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil || tx.Payload == nil {
+		log.Debug("get tx to record gas used failed", "chainID", b.ChainConfig.ChainID, "txHash", txHash, "err", err)
+		return nil
+	}
+	method := getSwapMethod(*tx.Payload)
+	key := getGasSampleKey(b.ChainConfig.ChainID, tokenID, method)
+	ring, _ := gasSamples.LoadOrStore(key, &gasSampleRing{})
+	ring.(*gasSampleRing).add(uint64(*txr.GasUsed), cfg.GetSamples())
+	return &tokens.GasSamples{
+		ChainID: b.ChainConfig.ChainID,
+		TokenID: tokenID,
+		Method:  method,
+		Samples: ring.(*gasSampleRing).snapshot(),
+	}
+}
+
+// LoadGasSamples impl tokens.GasUsageRecorder
+func (b *Bridge) LoadGasSamples(samples *tokens.GasSamples) {
+	cfg := params.GetGasLearningConfig(b.ChainConfig.ChainID)
+	if cfg == nil || samples == nil || samples.TokenID == "" {
+		return
+	}
+	ring := &gasSampleRing{}
+	for _, gasUsed := range samples.Samples {
+		ring.add(gasUsed, cfg.GetSamples())
+	}
+	gasSamples.Store(getGasSampleKey(b.ChainConfig.ChainID, samples.TokenID, samples.Method), ring)
+}
+
+// getLearnedGasLimit get the gas limit learned from the gas used of stable swap txs,
+// ok is false if learning is not enabled or the samples are not enough.
+func (b *Bridge) getLearnedGasLimit(args *tokens.BuildTxArgs, estimatedGas uint64) (gasLimit uint64, ok bool) {
+	cfg := params.GetGasLearningConfig(b.ChainConfig.ChainID)
+	if cfg == nil || args.Input == nil {
+		return 0, false
+	}
+	key := getGasSampleKey(b.ChainConfig.ChainID, args.GetTokenID(), getSwapMethod(*args.Input))
+	ring, exist := gasSamples.Load(key)
+	if !exist {
+		return 0, false
+	}
+	learned, ok := ring.(*gasSampleRing).percentile(cfg.GetPercentile(), cfg.GetMinSamples())
+	if !ok {
+		return 0, false
+	}
+	if estimatedGas > learned {
+		learned = estimatedGas
+	}
+	gasLimit = learned + uint64(float64(learned)*cfg.GetMarginPercent()/100)
+	log.Debug("use learned gas limit", "chainID", b.ChainConfig.ChainID, "tokenID", args.GetTokenID(), "swapID", args.SwapID, "estimated", estimatedGas, "gasLimit", gasLimit)
+	return gasLimit, true
+}
@@ -0,0 +1,87 @@
+package eth
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestGasSampleRing(t *testing.T) {
+	ring := &gasSampleRing{}
+	for _, gasUsed := range []uint64{100, 200, 300, 400, 500} {
+		ring.add(gasUsed, 4)
+	}
+	if got, want := ring.snapshot(), []uint64{200, 300, 400, 500}; !reflect.DeepEqual(got, want) {
+		t.Errorf("snapshot got %v, want %v", got, want)
+	}
+	if _, ok := ring.percentile(50, 5); ok {
+		t.Errorf("percentile of not enough samples should not be ok")
+	}
+	for _, test := range []struct {
+		percent float64
+		want    uint64
+	}{
+		{0, 200},
+		{50, 300},
+		{75, 400},
+		{95, 500},
+		{100, 500},
+	} {
+		if gas, ok := ring.percentile(test.percent, 4); !ok || gas != test.want {
+			t.Errorf("percentile %v got (%v, %v), want %v", test.percent, gas, ok, test.want)
+		}
+	}
+
+	// shrinking the samples size keeps the latest samples
+	ring.add(600, 2)
+	if got, want := ring.snapshot(), []uint64{500, 600}; !reflect.DeepEqual(got, want) {
+		t.Errorf("snapshot after shrinking got %v, want %v", got, want)
+	}
+}
+
+func TestLoadGasSamples(t *testing.T) {
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			"1": {GasLearning: &params.GasLearningConfig{Samples: 3, MinSamples: 2, Percentile: 100, MarginPercent: 10}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	b := NewCrossChainBridge()
+	b.ChainConfig = &tokens.ChainConfig{ChainID: "1"}
+
+	input := hexutil.Bytes(common.FromHex("0x825bb13c0000000000000000000000000000000000000000000000000000000000000001"))
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{TokenID: "USDC"}}},
+		Input:    &input,
+	}
+	if _, ok := b.getLearnedGasLimit(args, 50000); ok {
+		t.Fatalf("learned gas limit without samples should not be ok")
+	}
+
+	// persisted samples are loaded with the latest samples kept
+	b.LoadGasSamples(&tokens.GasSamples{
+		ChainID: "1",
+		TokenID: "USDC",
+		Method:  getSwapMethod(input),
+		Samples: []uint64{90000, 70000, 80000, 100000},
+	})
+	if gasLimit, ok := b.getLearnedGasLimit(args, 50000); !ok || gasLimit != 110000 {
+		t.Errorf("learned gas limit got (%v, %v), want 110000", gasLimit, ok)
+	}
+	// estimated gas larger than the learned
+	if gasLimit, ok := b.getLearnedGasLimit(args, 200000); !ok || gasLimit != 220000 {
+		t.Errorf("learned gas limit with larger estimated gas got (%v, %v), want 220000", gasLimit, ok)
+	}
+	// samples of other methods are not used
+	otherInput := hexutil.Bytes(common.FromHex("0x0175b1c4"))
+	args.Input = &otherInput
+	if _, ok := b.getLearnedGasLimit(args, 50000); ok {
+		t.Errorf("learned gas limit of other method should not be ok")
+	}
+}
@@ -0,0 +1,135 @@
+package eth
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+// VerifyGateways impl tokens.GatewayVerifier
+// endpoints are rejected if their chain ID differs from the signer chain ID, or their
+// block hash at the check height differs from the quorum of peer endpoints.
+// unreachable endpoints are left to the gateway quality adjustment.
+func (b *Bridge) VerifyGateways(chainID string, urls []string) (rejected map[string]error) {
+	rejected = make(map[string]error)
+	cfg := params.GetGatewayCheckConfig(chainID)
+	if cfg == nil || len(urls) == 0 {
+		return rejected
+	}
+	wantChainID := b.getGatewayChainID(chainID)
+	if wantChainID == nil {
+		return rejected
+	}
+
+	heads := make(map[string]uint64, len(urls))
+	var minHead uint64
+	for _, url := range urls {
+		onlineChainID, err := b.getChainIDOf(url)
+		if err != nil {
+			log.Trace("check gateway chain ID failed", "chainID", chainID, "url", url, "err", err)
+			continue
+		}
+		if onlineChainID.Cmp(wantChainID) != 0 {
+			rejected[url] = fmt.Errorf("%w: chain ID is %v", tokens.ErrGatewayMismatch, onlineChainID)
+			continue
+		}
+		height, err := b.EvmContractBridge.GetLatestBlockNumberOf(url)
+		if err != nil {
+			continue
+		}
+		heads[url] = height
+		if minHead == 0 || height < minHead {
+			minHead = height
+		}
+	}
+
+	depth := cfg.GetForkCheckDepth()
+	if minHead <= depth {
+		return rejected
+	}
+	checkHeight := new(big.Int).SetUint64(minHead - depth)
+	hashes := make(map[string]string, len(heads))
+	for url := range heads {
+		hash, err := b.getBlockHashOf(url, checkHeight)
+		if err != nil {
+			log.Trace("check gateway block hash failed", "chainID", chainID, "url", url, "height", checkHeight, "err", err)
+			continue
+		}
+		hashes[url] = hash
+	}
+	quorumHash, ok := findQuorumHash(hashes)
+	if !ok {
+		log.Warn("check gateway block hash without quorum", "chainID", chainID, "height", checkHeight, "hashes", hashes)
+		return rejected
+	}
+	for url, hash := range hashes {
+		if hash != quorumHash {
+			rejected[url] = fmt.Errorf("%w: block %v hash is %v, quorum is %v", tokens.ErrGatewayMismatch, checkHeight, hash, quorumHash)
+		}
+	}
+	return rejected
+}
+
+// getGatewayChainID get the chain ID endpoints should report, which is the
+// signer chain ID (may differ from the router chain ID, eg. ETC).
+// the router chain ID is used before the signer is initialized.
+func (b *Bridge) getGatewayChainID(chainID string) *big.Int {
+	if b.SignerChainID != nil {
+		return b.SignerChainID
+	}
+	wantChainID, err := common.GetBigIntFromStr(chainID)
+	if err != nil {
+		return nil
+	}
+	return wantChainID
+}
+
+// getChainIDOf call eth_chainId of url, use net_version if it is zero
+func (b *Bridge) getChainIDOf(url string) (*big.Int, error) {
+	var result hexutil.Big
+	err := client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "eth_chainId")
+	if err != nil {
+		return nil, wrapRPCQueryError(err, "eth_chainId")
+	}
+	if result.ToInt().Sign() != 0 {
+		return result.ToInt(), nil
+	}
+	var version string
+	err = client.RPCPostWithTimeout(b.RPCClientTimeout, &version, url, "net_version")
+	if err != nil {
+		return nil, wrapRPCQueryError(err, "net_version")
+	}
+	return common.GetBigIntFromStr(version)
+}
+
+// getBlockHashOf call eth_getBlockByNumber of url
+func (b *Bridge) getBlockHashOf(url string, number *big.Int) (string, error) {
+	var result *types.RPCBlock
+	err := client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "eth_getBlockByNumber", types.ToBlockNumArg(number), false)
+	if err != nil {
+		return "", wrapRPCQueryError(err, "eth_getBlockByNumber", number)
+	}
+	if result == nil || result.Hash == nil {
+		return "", tokens.ErrNotFound
+	}
+	return result.Hash.Hex(), nil
+}
+
+// findQuorumHash find the hash reported by more than half of the endpoints
+func findQuorumHash(hashes map[string]string) (string, bool) {
+	counts := make(map[string]int, len(hashes))
+	for _, hash := range hashes {
+		counts[hash]++
+		if counts[hash]*2 > len(hashes) {
+			return hash, true
+		}
+	}
+	return "", false
+}
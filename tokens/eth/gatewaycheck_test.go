@@ -0,0 +1,44 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFindQuorumHash(t *testing.T) {
+	tests := []struct {
+		hashes map[string]string
+		want   string
+		ok     bool
+	}{
+		{map[string]string{}, "", false},
+		{map[string]string{"a": "0x01"}, "0x01", true},
+		{map[string]string{"a": "0x01", "b": "0x01", "c": "0x02"}, "0x01", true},
+		{map[string]string{"a": "0x01", "b": "0x02"}, "", false},
+		{map[string]string{"a": "0x01", "b": "0x02", "c": "0x03"}, "", false},
+		{map[string]string{"a": "0x02", "b": "0x01", "c": "0x02", "d": "0x02"}, "0x02", true},
+		{map[string]string{"a": "0x01", "b": "0x01", "c": "0x02", "d": "0x02"}, "", false},
+	}
+	for i, test := range tests {
+		got, ok := findQuorumHash(test.hashes)
+		if got != test.want || ok != test.ok {
+			t.Errorf("test %v: findQuorumHash got (%v, %v), want (%v, %v)", i, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestGetGatewayChainID(t *testing.T) {
+	b := NewCrossChainBridge()
+	if got := b.getGatewayChainID("61"); got == nil || got.Int64() != 61 {
+		t.Errorf("chain ID before signer initialized got %v, want 61", got)
+	}
+	// ETC router chain ID differs from its signer chain ID
+	b.SignerChainID = big.NewInt(1)
+	if got := b.getGatewayChainID("61"); got == nil || got.Int64() != 1 {
+		t.Errorf("chain ID got %v, want signer chain ID 1", got)
+	}
+	b.SignerChainID = nil
+	if got := b.getGatewayChainID("not a number"); got != nil {
+		t.Errorf("chain ID of wrong router chain ID got %v, want nil", got)
+	}
+}
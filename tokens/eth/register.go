@@ -70,6 +70,9 @@ func (b *Bridge) registerERC20SwapTx(txHash string, logIndex int) ([]*tokens.Swa
 	}
 
 	if len(swapInfos) == 0 {
+		if b.IsDepositAddressEnabled() {
+			return b.registerDepositSwapTx(commonInfo, receipt, logIndex)
+		}
 		return []*tokens.SwapTxInfo{commonInfo}, []error{tokens.ErrSwapoutLogNotFound}
 	}
 
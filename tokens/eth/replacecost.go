@@ -0,0 +1,103 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+// min percent of gas price bump to replace tx in mempool (same as geth)
+const replaceMinBumpPercent = 110
+
+var errReplaceTxUnderpriced = errors.New("capped replace tx is underpriced")
+
+// GetSentTxMaxFee get max fee of sent tx (gas limit * gas fee cap or gas price)
+func (b *Bridge) GetSentTxMaxFee(txHash string) (*big.Int, error) {
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if tx.GasLimit == nil {
+		return nil, fmt.Errorf("tx %v without gas limit", txHash)
+	}
+	price := getMaxGasPrice(tx.GasFeeCap, tx.Price)
+	if price == nil {
+		return nil, fmt.Errorf("tx %v without gas price", txHash)
+	}
+	return new(big.Int).Mul(price, new(big.Int).SetUint64(uint64(*tx.GasLimit))), nil
+}
+
+// GetBuiltTxMaxFee get max fee of built tx (nil if the args is not built)
+func (b *Bridge) GetBuiltTxMaxFee(args *tokens.BuildTxArgs) *big.Int {
+	extra := args.Extra
+	if extra == nil || extra.Gas == nil {
+		return nil
+	}
+	price := extra.GasFeeCap
+	if price == nil {
+		price = extra.GasPrice
+	}
+	if price == nil {
+		return nil
+	}
+	return new(big.Int).Mul(price, new(big.Int).SetUint64(*extra.Gas))
+}
+
+// CapReplaceTxFee lower gas price (or gas fee cap) of built args to cap its max fee,
+// the capped gas price (and gas tip cap) must still be bumped enough to replace the sent tx.
+func (b *Bridge) CapReplaceTxFee(args *tokens.BuildTxArgs, sentTxHash string, maxFee *big.Int) error {
+	extra := args.Extra
+	if extra == nil || extra.Gas == nil || *extra.Gas == 0 {
+		return errors.New("cap replace tx fee of args which is not built")
+	}
+	tx, err := b.GetTransactionByHash(sentTxHash)
+	if err != nil {
+		return err
+	}
+	return capReplaceTxFee(extra, tx, maxFee)
+}
+
+func capReplaceTxFee(extra *tokens.AllExtras, tx *types.RPCTransaction, maxFee *big.Int) error {
+	capPrice := new(big.Int).Div(maxFee, new(big.Int).SetUint64(*extra.Gas))
+	sentPrice := getMaxGasPrice(tx.GasFeeCap, tx.Price)
+	if sentPrice == nil || capPrice.Cmp(getReplaceMinPrice(sentPrice)) < 0 {
+		return fmt.Errorf("%w: capped gas price %v, sent gas price %v", errReplaceTxUnderpriced, capPrice, sentPrice)
+	}
+
+	if extra.GasFeeCap == nil {
+		if extra.GasPrice != nil && extra.GasPrice.Cmp(capPrice) > 0 {
+			extra.GasPrice = capPrice
+		}
+		return nil
+	}
+	if extra.GasFeeCap.Cmp(capPrice) > 0 {
+		extra.GasFeeCap = capPrice
+	}
+	if extra.GasTipCap != nil && extra.GasTipCap.Cmp(extra.GasFeeCap) > 0 {
+		extra.GasTipCap = new(big.Int).Set(extra.GasFeeCap)
+	}
+	if tx.GasTipCap != nil && (extra.GasTipCap == nil ||
+		extra.GasTipCap.Cmp(getReplaceMinPrice(tx.GasTipCap.ToInt())) < 0) {
+		return fmt.Errorf("%w: capped gas tip cap %v, sent gas tip cap %v", errReplaceTxUnderpriced, extra.GasTipCap, tx.GasTipCap)
+	}
+	return nil
+}
+
+func getMaxGasPrice(gasFeeCap, gasPrice *hexutil.Big) *big.Int {
+	if gasFeeCap != nil {
+		return gasFeeCap.ToInt()
+	}
+	if gasPrice != nil {
+		return gasPrice.ToInt()
+	}
+	return nil
+}
+
+func getReplaceMinPrice(sentPrice *big.Int) *big.Int {
+	minPrice := new(big.Int).Mul(sentPrice, big.NewInt(replaceMinBumpPercent))
+	return minPrice.Div(minPrice, big.NewInt(100))
+}
@@ -0,0 +1,92 @@
+package eth
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+func newTestReplaceExtra(gasPrice, gasTipCap, gasFeeCap int64) *tokens.AllExtras {
+	gas := uint64(100)
+	extra := &tokens.AllExtras{Gas: &gas}
+	if gasPrice > 0 {
+		extra.GasPrice = big.NewInt(gasPrice)
+	}
+	if gasTipCap > 0 {
+		extra.GasTipCap = big.NewInt(gasTipCap)
+	}
+	if gasFeeCap > 0 {
+		extra.GasFeeCap = big.NewInt(gasFeeCap)
+	}
+	return extra
+}
+
+func newTestSentTx(gasPrice, gasTipCap, gasFeeCap int64) *types.RPCTransaction {
+	tx := &types.RPCTransaction{}
+	if gasPrice > 0 {
+		tx.Price = (*hexutil.Big)(big.NewInt(gasPrice))
+	}
+	if gasTipCap > 0 {
+		tx.GasTipCap = (*hexutil.Big)(big.NewInt(gasTipCap))
+	}
+	if gasFeeCap > 0 {
+		tx.GasFeeCap = (*hexutil.Big)(big.NewInt(gasFeeCap))
+	}
+	return tx
+}
+
+func TestGetBuiltTxMaxFee(t *testing.T) {
+	b := NewCrossChainBridge()
+	if fee := b.GetBuiltTxMaxFee(&tokens.BuildTxArgs{}); fee != nil {
+		t.Errorf("max fee of args not built got %v", fee)
+	}
+	args := &tokens.BuildTxArgs{Extra: newTestReplaceExtra(200, 0, 0)}
+	if fee := b.GetBuiltTxMaxFee(args); fee == nil || fee.Int64() != 20000 {
+		t.Errorf("max fee of legacy tx got %v, want 20000", fee)
+	}
+	args = &tokens.BuildTxArgs{Extra: newTestReplaceExtra(0, 20, 300)}
+	if fee := b.GetBuiltTxMaxFee(args); fee == nil || fee.Int64() != 30000 {
+		t.Errorf("max fee of dynamic fee tx got %v, want 30000", fee)
+	}
+}
+
+func TestCapReplaceTxFee(t *testing.T) {
+	tests := []struct {
+		extra                 *tokens.AllExtras
+		sentTx                *types.RPCTransaction
+		maxFee                int64
+		err                   error
+		gasPrice, tip, feeCap int64
+	}{
+		// legacy tx is capped to max fee / gas
+		{newTestReplaceExtra(200, 0, 0), newTestSentTx(100, 0, 0), 12000, nil, 120, 0, 0},
+		{newTestReplaceExtra(115, 0, 0), newTestSentTx(100, 0, 0), 12000, nil, 115, 0, 0},
+		// capped gas price must be bumped 10 percent to replace
+		{newTestReplaceExtra(200, 0, 0), newTestSentTx(100, 0, 0), 10900, errReplaceTxUnderpriced, 0, 0, 0},
+		// dynamic fee tx caps gas fee cap, and gas tip cap to the gas fee cap
+		{newTestReplaceExtra(0, 20, 200), newTestSentTx(0, 10, 100), 15000, nil, 0, 20, 150},
+		{newTestReplaceExtra(0, 200, 200), newTestSentTx(0, 100, 100), 12000, nil, 0, 120, 120},
+		{newTestReplaceExtra(0, 5, 200), newTestSentTx(0, 10, 100), 15000, errReplaceTxUnderpriced, 0, 0, 0},
+	}
+	for i, test := range tests {
+		err := capReplaceTxFee(test.extra, test.sentTx, big.NewInt(test.maxFee))
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %v: cap replace tx fee got error %v, want %v", i, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		extra := test.extra
+		if test.gasPrice > 0 && extra.GasPrice.Int64() != test.gasPrice {
+			t.Errorf("test %v: capped gas price got %v, want %v", i, extra.GasPrice, test.gasPrice)
+		}
+		if test.feeCap > 0 && (extra.GasFeeCap.Int64() != test.feeCap || extra.GasTipCap.Int64() != test.tip) {
+			t.Errorf("test %v: capped gas fee cap and tip cap got (%v, %v), want (%v, %v)", i, extra.GasFeeCap, extra.GasTipCap, test.feeCap, test.tip)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package eth
+
+import (
+	"bytes"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+// isRouterAddress is address the router contract or one of its accepted emitters
+func (b *Bridge) isRouterAddress(address, routerContract string) bool {
+	return params.IsAcceptedRouterEmitter(b.ChainConfig.ChainID, routerContract, address)
+}
+
+// checkRouterLogEmitter check the log is emitted by the router contract,
+// logs emitted by accepted emitters (proxy or diamond deployments)
+// must have a router swapout topic of the swap type.
+func (b *Bridge) checkRouterLogEmitter(swapInfo *tokens.SwapTxInfo, rlog *types.RPCLog, routerContract string) error {
+	emitter := rlog.Address.LowerHex()
+	if common.IsEqualIgnoreCase(emitter, routerContract) {
+		return nil
+	}
+	if !b.isRouterAddress(emitter, routerContract) {
+		log.Warn("tx to address mismatch", "have", emitter, "want", routerContract, "chainID", b.ChainConfig.ChainID, "txid", swapInfo.Hash, "logIndex", swapInfo.LogIndex, "err", tokens.ErrTxWithWrongContract)
+		return tokens.ErrTxWithWrongContract
+	}
+	if len(rlog.Topics) == 0 || !isSwapLogTopic(swapInfo.SwapType, rlog.Topics[0].Bytes()) {
+		log.Warn("router emitter log with wrong topic", "emitter", emitter, "router", routerContract, "chainID", b.ChainConfig.ChainID, "txid", swapInfo.Hash, "logIndex", swapInfo.LogIndex, "err", tokens.ErrTxWithWrongTopics)
+		return tokens.ErrTxWithWrongTopics
+	}
+	return nil
+}
+
+func isSwapLogTopic(swapType tokens.SwapType, topic []byte) bool {
+	for _, routerTopic := range getSwapLogTopics(swapType) {
+		if bytes.Equal(topic, routerTopic) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package eth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+func TestCheckRouterLogEmitter(t *testing.T) {
+	routerContract := "0x1111111111111111111111111111111111111111"
+	emitter := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	other := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			"1": {RouterEmitters: map[string][]string{routerContract: {emitter.LowerHex()}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	b := NewCrossChainBridge()
+	b.ChainConfig = &tokens.ChainConfig{ChainID: "1"}
+
+	router := common.HexToAddress(routerContract)
+	swapoutTopic := common.BytesToHash(LogAnySwapOutV7Topic)
+	transferTopic := common.BytesToHash(erc20CodeParts["LogTransfer"])
+	tests := []struct {
+		address common.Address
+		topic   common.Hash
+		want    error
+	}{
+		{router, swapoutTopic, nil},
+		{router, transferTopic, nil},
+		{emitter, swapoutTopic, nil},
+		{emitter, transferTopic, tokens.ErrTxWithWrongTopics},
+		{other, swapoutTopic, tokens.ErrTxWithWrongContract},
+	}
+	swapInfo := &tokens.SwapTxInfo{SwapType: tokens.ERC20SwapType}
+	for i, test := range tests {
+		address := test.address
+		rlog := &types.RPCLog{Address: &address, Topics: []common.Hash{test.topic}}
+		if err := b.checkRouterLogEmitter(swapInfo, rlog, routerContract); !errors.Is(err, test.want) {
+			t.Errorf("test %v: check router log emitter got %v, want %v", i, err, test.want)
+		}
+	}
+}
+
+func TestCheckRouterEmittersConfig(t *testing.T) {
+	routers := []string{"0x1111111111111111111111111111111111111111", "0x4444444444444444444444444444444444444444"}
+	emitter := "0x2222222222222222222222222222222222222222"
+	for i, routerEmitters := range []map[string][]string{
+		{routers[0]: {routers[0]}},
+		{routers[0]: {"0x1234"}},
+		{routers[0]: {emitter}, routers[1]: {emitter}},
+	} {
+		cfg := &params.LocalChainConfig{RouterEmitters: routerEmitters}
+		if err := cfg.CheckConfig(); err == nil {
+			t.Errorf("test %v: check wrong router emitters config should fail", i)
+		}
+	}
+}
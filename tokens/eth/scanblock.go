@@ -0,0 +1,167 @@
+package eth
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+var _ tokens.BlockScanner = &Bridge{}
+
+// getRouterLogTopics get router swapout log topics of the router swap type
+func getRouterLogTopics() [][]byte {
+	return getSwapLogTopics(tokens.GetRouterSwapType())
+}
+
+// getSwapLogTopics get router swapout log topics of the swap type
+func getSwapLogTopics(swapType tokens.SwapType) [][]byte {
+	switch swapType {
+	case tokens.ERC20SwapType, tokens.ERC20SwapTypeMixPool:
+		return [][]byte{
+			LogAnySwapOutTopic,
+			LogAnySwapOut2Topic,
+			LogAnySwapOutMixPoolTopic,
+			LogAnySwapOutV7Topic,
+			LogAnySwapOutAndCallV7Topic,
+		}
+	case tokens.NFTSwapType:
+		return [][]byte{
+			LogNFT721SwapOutTopic,
+			LogNFT1155SwapOutTopic,
+			LogNFT1155SwapOutBatchTopic,
+			LogNFT721SwapOutWithDataTopic,
+		}
+	case tokens.AnyCallSwapType:
+		switch params.GetSwapSubType() {
+		case tokens.AnycallSubTypeV7:
+			return [][]byte{LogAnyCallV7Topic, LogAnyCallV7Topic2}
+		case tokens.AnycallSubTypeV6:
+			return [][]byte{LogAnyCallV6Topic}
+		default:
+			return [][]byte{LogAnyCallV5Topic}
+		}
+	default:
+		return nil
+	}
+}
+
+// getAllRouterContracts get router contracts of chain and all tokens,
+// and the accepted emitters of router logs of them.
+func (b *Bridge) getAllRouterContracts() []common.Address {
+	exist := make(map[string]struct{})
+	result := make([]common.Address, 0, 1)
+	add := func(routerContract string) {
+		key := strings.ToLower(routerContract)
+		if key == "" {
+			return
+		}
+		if _, ok := exist[key]; ok {
+			return
+		}
+		exist[key] = struct{}{}
+		result = append(result, common.HexToAddress(routerContract))
+	}
+	addRouter := func(routerContract string) {
+		add(routerContract)
+		for _, emitter := range params.GetRouterEmitters(b.ChainConfig.ChainID, routerContract) {
+			add(emitter)
+		}
+	}
+	addRouter(b.ChainConfig.RouterContract)
+	b.TokenConfigMap.Range(func(k, v interface{}) bool {
+		addRouter(v.(*tokens.TokenConfig).RouterContract)
+		return true
+	})
+	return result
+}
+
+// MayContainRouterLogs check block log bloom against router contracts and log topics.
+// return true if the block may contain router logs or the block has no log bloom.
+func (b *Bridge) MayContainRouterLogs(block *types.RPCBlock) bool {
+	return mayContainRouterLogs(block, b.getAllRouterContracts(), getRouterLogTopics())
+}
+
+func mayContainRouterLogs(block *types.RPCBlock, routerContracts []common.Address, routerTopics [][]byte) bool {
+	if block == nil || block.LogsBloom == nil {
+		return true
+	}
+	bloom := types.BytesToBloom(*block.LogsBloom)
+	if bloom == nil {
+		return true
+	}
+	hasRouter := false
+	for _, routerContract := range routerContracts {
+		if bloom.Test(routerContract.Bytes()) {
+			hasRouter = true
+			break
+		}
+	}
+	if !hasRouter {
+		return false
+	}
+	for _, topic := range routerTopics {
+		if bloom.Test(topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScanBlockRouterTxs scan block for txs which have router swapout logs.
+// block log bloom is checked first to skip fetching receipts of blocks without router activity.
+func (b *Bridge) ScanBlockRouterTxs(height uint64) (txHashes []string, err error) {
+	block, err := b.GetBlockByNumber(new(big.Int).SetUint64(height))
+	if err != nil {
+		return nil, err
+	}
+	routerContracts := b.getAllRouterContracts()
+	routerTopics := getRouterLogTopics()
+	if !mayContainRouterLogs(block, routerContracts, routerTopics) {
+		log.Trace("scan block skipped by log bloom", "chainID", b.ChainConfig.ChainID, "height", height, "txs", len(block.Transactions))
+		return nil, nil
+	}
+	for _, txHash := range block.Transactions {
+		receipt, errf := b.GetTransactionReceipt(txHash.Hex())
+		if errf != nil {
+			return nil, errf
+		}
+		if hasRouterLog(receipt, routerContracts, routerTopics) {
+			txHashes = append(txHashes, txHash.Hex())
+		}
+	}
+	log.Info("scan block finished", "chainID", b.ChainConfig.ChainID, "height", height, "txs", len(block.Transactions), "routerTxs", len(txHashes))
+	return txHashes, nil
+}
+
+func hasRouterLog(receipt *types.RPCTxReceipt, routerContracts []common.Address, routerTopics [][]byte) bool {
+	for _, rlog := range receipt.Logs {
+		if rlog == nil || rlog.Address == nil || len(rlog.Topics) == 0 {
+			continue
+		}
+		if rlog.Removed != nil && *rlog.Removed {
+			continue
+		}
+		isRouter := false
+		for _, routerContract := range routerContracts {
+			if *rlog.Address == routerContract {
+				isRouter = true
+				break
+			}
+		}
+		if !isRouter {
+			continue
+		}
+		for _, topic := range routerTopics {
+			if bytes.Equal(rlog.Topics[0].Bytes(), topic) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+const testScanRouter = "0x2222222222222222222222222222222222222222"
+
+func TestHasRouterLog(t *testing.T) {
+	routerContract := common.HexToAddress(testScanRouter)
+	otherContract := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	routerTopics := getSwapLogTopics(tokens.ERC20SwapType)
+	removed := true
+
+	newReceipt := func(address common.Address, topic []byte, removed *bool) *types.RPCTxReceipt {
+		return &types.RPCTxReceipt{Logs: []*types.RPCLog{{
+			Address: &address,
+			Topics:  []common.Hash{common.BytesToHash(topic)},
+			Removed: removed,
+		}}}
+	}
+	tests := []struct {
+		receipt *types.RPCTxReceipt
+		want    bool
+	}{
+		{newReceipt(routerContract, LogAnySwapOutTopic, nil), true},
+		{newReceipt(routerContract, LogAnySwapOutV7Topic, nil), true},
+		{newReceipt(routerContract, LogAnySwapOutTopic, &removed), false},
+		{newReceipt(otherContract, LogAnySwapOutTopic, nil), false},
+		{newReceipt(routerContract, LogNFT721SwapOutTopic, nil), false},
+		{&types.RPCTxReceipt{Logs: []*types.RPCLog{nil}}, false},
+	}
+	for i, test := range tests {
+		if got := hasRouterLog(test.receipt, []common.Address{routerContract}, routerTopics); got != test.want {
+			t.Errorf("test %v: has router log got %v, want %v", i, got, test.want)
+		}
+	}
+}
+
+func TestMayContainRouterLogs(t *testing.T) {
+	routerContracts := []common.Address{common.HexToAddress(testScanRouter)}
+	routerTopics := getSwapLogTopics(tokens.ERC20SwapType)
+
+	newBlock := func(items ...[]byte) *types.RPCBlock {
+		var bloom types.Bloom
+		for _, item := range items {
+			bloom.Add(item)
+		}
+		logsBloom := hexutil.Bytes(bloom[:])
+		return &types.RPCBlock{LogsBloom: &logsBloom}
+	}
+	router := common.HexToAddress(testScanRouter).Bytes()
+	other := common.HexToAddress("0x3333333333333333333333333333333333333333").Bytes()
+
+	if !mayContainRouterLogs(&types.RPCBlock{}, routerContracts, routerTopics) {
+		t.Errorf("block without log bloom should be scanned")
+	}
+	if !mayContainRouterLogs(newBlock(router, LogAnySwapOutTopic), routerContracts, routerTopics) {
+		t.Errorf("block with router log should be scanned")
+	}
+	if mayContainRouterLogs(newBlock(other, LogAnySwapOutTopic), routerContracts, routerTopics) {
+		t.Errorf("block without router contract should be skipped")
+	}
+	if mayContainRouterLogs(newBlock(router), routerContracts, routerTopics) {
+		t.Errorf("block without router log topic should be skipped")
+	}
+}
@@ -0,0 +1,30 @@
+package eth
+
+import (
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// MapSendTxError impl, classify errors of eth_sendRawTransaction
+func (b *Bridge) MapSendTxError(err error) tokens.SendTxErrorClass {
+	errMsg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(errMsg, "already known"),
+		strings.Contains(errMsg, "known transaction"),
+		strings.Contains(errMsg, "already imported"),
+		strings.Contains(errMsg, "tx already in mempool"):
+		return tokens.SendTxErrAlreadyKnown
+	case strings.Contains(errMsg, "nonce too low"),
+		strings.Contains(errMsg, "invalid nonce"):
+		return tokens.SendTxErrNonceTooLow
+	case strings.Contains(errMsg, "underpriced"),
+		strings.Contains(errMsg, "fee cap less than block base fee"),
+		strings.Contains(errMsg, "max fee per gas less than block base fee"):
+		return tokens.SendTxErrUnderpriced
+	case strings.Contains(errMsg, "insufficient funds"):
+		return tokens.SendTxErrInsufficientFunds
+	default:
+		return tokens.SendTxErrUnknown
+	}
+}
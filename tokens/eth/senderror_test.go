@@ -0,0 +1,26 @@
+package eth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestMapSendTxError(t *testing.T) {
+	tests := map[string]tokens.SendTxErrorClass{
+		"already known":                                              tokens.SendTxErrAlreadyKnown,
+		"nonce too low":                                              tokens.SendTxErrNonceTooLow,
+		"replacement transaction underpriced":                        tokens.SendTxErrUnderpriced,
+		"max fee per gas less than block base fee":                   tokens.SendTxErrUnderpriced,
+		"insufficient funds for gas * price + value":                 tokens.SendTxErrInsufficientFunds,
+		"Post \"x\": Client.Timeout exceeded while awaiting headers": tokens.SendTxErrTemporary,
+		"execution reverted":                                         tokens.SendTxErrUnknown,
+	}
+	for msg, want := range tests {
+		err := tokens.WrapRPCQueryError(errors.New(msg), "eth_sendRawTransaction")
+		if got := tokens.ClassifySendTxError(br, err); got != want {
+			t.Errorf("classify send tx error %q, got %v, want %v", msg, got, want)
+		}
+	}
+}
@@ -26,6 +26,12 @@ func (b *Bridge) SendZKSyncTransaction(signedTx interface{}) (txHash string, err
 
 // SendTransaction send signed tx
 func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
+	if b.IsAccountAbstraction() {
+		return b.SendUserOperation(signedTx)
+	}
 	if b.IsZKSync() {
 		return b.SendZKSyncTransaction(signedTx)
 	}
@@ -0,0 +1,24 @@
+//go:build watcher
+
+package eth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+func TestWatcherModeRefuseSending(t *testing.T) {
+	b := NewCrossChainBridge()
+	if _, err := b.SendTransaction(&types.Transaction{}); !errors.Is(err, params.ErrWatcherMode) {
+		t.Errorf("send transaction got err %v, want %v", err, params.ErrWatcherMode)
+	}
+	if _, err := b.SendSignedTransaction(&types.Transaction{}); !errors.Is(err, params.ErrWatcherMode) {
+		t.Errorf("send signed transaction got err %v, want %v", err, params.ErrWatcherMode)
+	}
+	if _, err := b.SendSignedZKSyncTransaction([]byte{0x01}); !errors.Is(err, params.ErrWatcherMode) {
+		t.Errorf("send signed zksync transaction got err %v, want %v", err, params.ErrWatcherMode)
+	}
+}
@@ -0,0 +1,90 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+// error messages of pruned receipts and states of common node implementations
+var prunedErrorMessages = []string{
+	"pruned",
+	"missing trie node",
+	"state is not available",
+	"historical state",
+}
+
+// isPrunedError is rpc error of pruned data of shallow archive chain
+func (b *Bridge) isPrunedError(err error) bool {
+	cfg := params.GetShallowArchiveConfig(b.ChainConfig.ChainID)
+	if cfg == nil || err == nil {
+		return false
+	}
+	errMsg := strings.ToLower(err.Error())
+	for _, msg := range prunedErrorMessages {
+		if strings.Contains(errMsg, msg) {
+			return true
+		}
+	}
+	for _, msg := range cfg.PrunedErrors {
+		if strings.Contains(errMsg, strings.ToLower(msg)) {
+			return true
+		}
+	}
+	return false
+}
+
+// saveTxReceipt save receipt of swap tx if persisting receipts is enabled
+func (b *Bridge) saveTxReceipt(txHash string, receipt *types.RPCTxReceipt) {
+	if !params.IsPersistReceipts(b.ChainConfig.ChainID) || !mongodb.HasClient() {
+		return
+	}
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		log.Warn("marshal tx receipt failed", "chainID", b.ChainConfig.ChainID, "txid", txHash, "err", err)
+		return
+	}
+	_ = mongodb.AddTxReceipt(&mongodb.MgoTxReceipt{
+		ChainID:     b.ChainConfig.ChainID,
+		TxID:        txHash,
+		BlockNumber: receipt.BlockNumber.ToInt().Uint64(),
+		BlockHash:   receipt.BlockHash.Hex(),
+		Receipt:     string(data),
+		Timestamp:   time.Now().Unix(),
+	})
+}
+
+// getSavedTxReceipt get saved receipt of tx when the nodes have pruned it,
+// the block hash is checked again as the receipt may be saved before stable.
+func (b *Bridge) getSavedTxReceipt(txHash string) (*types.RPCTxReceipt, error) {
+	if !params.IsPersistReceipts(b.ChainConfig.ChainID) || !mongodb.HasClient() {
+		return nil, tokens.ErrTxNotFound
+	}
+	saved, err := mongodb.FindTxReceipt(b.ChainConfig.ChainID, txHash)
+	if err != nil {
+		if errors.Is(err, mongodb.ErrItemNotFound) {
+			return nil, tokens.ErrTxNotFound
+		}
+		return nil, err
+	}
+	var receipt types.RPCTxReceipt
+	if err = json.Unmarshal([]byte(saved.Receipt), &receipt); err != nil {
+		return nil, fmt.Errorf("unmarshal saved tx receipt failed: %w", err)
+	}
+	if receipt.BlockNumber == nil || receipt.BlockHash == nil || receipt.TxIndex == nil {
+		return nil, errTxReceiptMissBlockInfo
+	}
+	if err = b.checkTxBlockHash(receipt.BlockNumber.ToInt(), *receipt.BlockHash); err != nil {
+		return nil, err
+	}
+	log.Info("use saved tx receipt", "chainID", b.ChainConfig.ChainID, "txid", txHash, "block", saved.BlockNumber)
+	return &receipt, nil
+}
@@ -0,0 +1,86 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func setupShallowArchive(t *testing.T, chainID string, cfg *params.ShallowArchiveConfig) func() {
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			chainID: {ShallowArchive: cfg},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	return func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }
+}
+
+func TestIsPrunedError(t *testing.T) {
+	defer setupShallowArchive(t, "1", &params.ShallowArchiveConfig{PrunedErrors: []string{"Receipt is unavailable"}})()
+
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: "1"})
+	other := NewCrossChainBridge()
+	other.SetChainConfig(&tokens.ChainConfig{ChainID: "56"})
+
+	tests := []struct {
+		err    error
+		pruned bool
+	}{
+		{nil, false},
+		{errors.New("json-rpc error -32000, missing trie node 0x01"), true},
+		{errors.New("json-rpc error -32000, receipt is unavailable"), true},
+		{errors.New("json-rpc error -32000, nonce too low"), false},
+	}
+	for i, test := range tests {
+		if got := b.isPrunedError(test.err); got != test.pruned {
+			t.Errorf("test %v: is pruned error %v got %v, want %v", i, test.err, got, test.pruned)
+		}
+		// chains without shallow archive config are not checked
+		if other.isPrunedError(test.err) {
+			t.Errorf("test %v: is pruned error %v of chain without config got true", i, test.err)
+		}
+	}
+}
+
+func TestGetPrunedTransactionReceipt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID int `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0", "id": req.ID,
+			"error": map[string]interface{}{"code": -32000, "message": "missing trie node 0x01"},
+		})
+	}))
+	defer server.Close()
+
+	newBridge := func(chainID string) *Bridge {
+		b := NewCrossChainBridge()
+		b.SetChainConfig(&tokens.ChainConfig{ChainID: chainID})
+		b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{server.URL}})
+		return b
+	}
+	txHash := "0x0000000000000000000000000000000000000000000000000000000000000001"
+
+	// pruned receipts are not reported as not found, and the verification is retried
+	defer setupShallowArchive(t, "1", &params.ShallowArchiveConfig{PersistReceipts: true})()
+	_, err := newBridge("1").GetTransactionReceipt(txHash)
+	if !errors.Is(err, tokens.ErrTxPruned) || !tokens.IsTransientVerifyError(err) {
+		t.Errorf("get pruned receipt got error %v, want %v", err, tokens.ErrTxPruned)
+	}
+
+	_, err = newBridge("56").GetTransactionReceipt(txHash)
+	if err == nil || errors.Is(err, tokens.ErrTxPruned) {
+		t.Errorf("get receipt of chain without config got error %v, want rpc query error", err)
+	}
+}
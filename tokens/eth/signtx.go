@@ -21,6 +21,22 @@ import (
 	"github.com/zksync-sdk/zksync2-go"
 )
 
+// chainFamily chain family in the mpc sign scheme registry
+const chainFamily = "evm"
+
+func init() {
+	mpc.RegisterChainFamily(chainFamily, mpc.SchemeSecp256k1Keccak)
+}
+
+// GetMPCSignKey impl tokens.MPCSchemeSigner
+func (b *Bridge) GetMPCSignKey(mpc string) (family, signPubkey string, err error) {
+	mpcPubkey := router.GetMPCPublicKey(mpc)
+	if mpcPubkey == "" {
+		return "", "", tokens.ErrMissMPCPublicKey
+	}
+	return chainFamily, mpcPubkey, nil
+}
+
 func (b *Bridge) verifyTransactionReceiver(rawTx interface{}, tokenID string) (*types.Transaction, error) {
 	tx, ok := rawTx.(*types.Transaction)
 	if !ok {
@@ -36,9 +52,20 @@ func (b *Bridge) verifyTransactionReceiver(rawTx interface{}, tokenID string) (*
 	if !strings.EqualFold(tx.To().String(), checkReceiver) {
 		return nil, fmt.Errorf("[sign] tx receiver mismatch. have %v want %v", tx.To().String(), checkReceiver)
 	}
+	if err = b.checkDestMethod(tx.Data()); err != nil {
+		return nil, err
+	}
 	return tx, nil
 }
 
+// checkDestMethod check method selector of input data is in allowlist
+func (b *Bridge) checkDestMethod(input []byte) error {
+	if err := tokens.CheckDestMethods(b.ChainConfig.ChainID, tokens.GetMethodSelector(input)); err != nil {
+		return fmt.Errorf("[sign] %w", err)
+	}
+	return nil
+}
+
 func (b *Bridge) verifyZkSyncTransactionReceiver(rawTx interface{}, tokenID string) (*zksync2.Transaction712, error) {
 	tx, ok := rawTx.(*zksync2.Transaction712)
 	if !ok {
@@ -54,6 +81,9 @@ func (b *Bridge) verifyZkSyncTransactionReceiver(rawTx interface{}, tokenID stri
 	if !strings.EqualFold(tx.To.String(), checkReceiver) {
 		return nil, fmt.Errorf("[sign] tx receiver mismatch. have %v want %v", tx.To.String(), checkReceiver)
 	}
+	if err = b.checkDestMethod(tx.Data); err != nil {
+		return nil, err
+	}
 	return tx, nil
 }
 
@@ -160,6 +190,9 @@ func (b *Bridge) MPCSignZkSyncTransaction(rawTx interface{}, args *tokens.BuildT
 
 // MPCSignTransaction mpc sign raw tx
 func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
+	if b.IsAccountAbstraction() {
+		return b.MPCSignUserOperation(rawTx, args)
+	}
 	if b.IsZKSync() {
 		return b.MPCSignZkSyncTransaction(rawTx, args)
 	}
@@ -197,26 +230,13 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 	logPrefix := b.ChainConfig.BlockChain + " MPCSignTransaction "
 	log.Info(logPrefix+"start", "txid", txid, "msghash", msgHash.String())
 	mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
-	keyID, rsvs, err := mpcConfig.DoSignOneEC(mpcPubkey, msgHash.String(), msgContext)
+	keyID, signature, err := mpcConfig.DoSignWithScheme(chainFamily, mpcPubkey, msgHash[:], msgContext)
 	if err != nil {
 		log.Info(logPrefix+"failed", "keyID", keyID, "txid", txid, "err", err)
 		return nil, "", err
 	}
 	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "msghash", msgHash.String())
-
-	if len(rsvs) != 1 {
-		log.Warn("get sign status require one rsv but return many",
-			"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
-		return nil, "", errors.New("get sign status require one rsv but return many")
-	}
-
-	rsv := rsvs[0]
-	log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", rsv)
-	signature := common.FromHex(rsv)
-	if len(signature) != crypto.SignatureLength {
-		log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(signature), "want", crypto.SignatureLength)
-		return nil, "", errors.New("wrong signature length")
-	}
+	log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", common.ToHex(signature))
 
 	signedTx, err := b.signTxWithSignature(tx, signature, common.HexToAddress(args.From))
 	if err != nil {
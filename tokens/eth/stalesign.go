@@ -0,0 +1,52 @@
+package eth
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// CheckSignedTxStale check whether the signed tx is stale in the current destination context.
+// the signed tx is stale if its nonce is already consumed, or the gas market
+// has moved beyond what the signed tx can pay. rpc errors are not treated as stale.
+func (b *Bridge) CheckSignedTxStale(args *tokens.BuildTxArgs) error {
+	extra := args.Extra
+	if extra == nil {
+		return nil
+	}
+
+	if extra.Sequence != nil {
+		latestNonce, err := b.GetPoolNonce(args.From, "latest")
+		if err == nil && latestNonce > *extra.Sequence {
+			return fmt.Errorf("nonce %v is consumed, latest nonce is %v", *extra.Sequence, latestNonce)
+		}
+	}
+
+	switch {
+	case extra.GasFeeCap != nil:
+		baseFee, err := b.GetBaseFee(0)
+		if err == nil && baseFee != nil && baseFee.Cmp(extra.GasFeeCap) > 0 {
+			return fmt.Errorf("base fee %v exceeds signed gas fee cap %v", baseFee, extra.GasFeeCap)
+		}
+	case extra.GasPrice != nil:
+		serverCfg := params.GetRouterServerConfig()
+		if serverCfg == nil || serverCfg.MaxGasPriceFluctPercent == 0 {
+			break
+		}
+		gasPrice, err := b.SuggestPrice()
+		if err != nil {
+			break
+		}
+		maxGasPrice := new(big.Int).Mul(extra.GasPrice, new(big.Int).SetUint64(100+serverCfg.MaxGasPriceFluctPercent))
+		maxGasPrice.Div(maxGasPrice, big.NewInt(100))
+		if gasPrice.Cmp(maxGasPrice) > 0 {
+			log.Info("gas price moved beyond fluct limit after sign", "chainID", b.ChainConfig.ChainID, "signed", extra.GasPrice, "current", gasPrice)
+			return fmt.Errorf("gas price %v exceeds signed gas price %v beyond %v%%", gasPrice, extra.GasPrice, serverCfg.MaxGasPriceFluctPercent)
+		}
+	}
+
+	return nil
+}
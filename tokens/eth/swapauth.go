@@ -0,0 +1,249 @@
+package eth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+	ethmath "github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+var (
+	// ExecAuthorizedFuncHash execAuthorized(bytes,uint256,uint256,bytes)
+	ExecAuthorizedFuncHash = common.FromHex("0x3365e917")
+
+	swapAuthorizationTypes = apitypes.Types{
+		"EIP712Domain": []apitypes.Type{
+			{Name: "name", Type: "string"},
+			{Name: "version", Type: "string"},
+			{Name: "chainId", Type: "uint256"},
+			{Name: "verifyingContract", Type: "address"},
+		},
+		"SwapAuthorization": []apitypes.Type{
+			{Name: "dataHash", Type: "bytes32"},
+			{Name: "nonce", Type: "uint256"},
+			{Name: "deadline", Type: "uint256"},
+		},
+	}
+
+	errSwapAuthorizationNotSupported = errors.New("swap authorization is not supported on account abstraction or zksync chain")
+	errSwapAuthorizationNoDomain     = errors.New("swap authorization has no domain of router version")
+	errSwapAuthorizationMismatch     = errors.New("swap authorization mismatch")
+	errSwapAuthorizationExpired      = errors.New("swap authorization is expired")
+	errSwapAuthorizationSigner       = errors.New("swap authorization is not signed by mpc")
+)
+
+// authorizeSwapTx wrap the swap calldata with the EIP-712 signed authorization of the mpc.
+// the authorization in args.Extra (eg. in sign requests verified by oracles) is verified
+// and reused, otherwise a new one is created and signed by the swap server, builds of
+// others (eg. dry run, verifying the sign request of the authorization) leave it unsigned.
+func (b *Bridge) authorizeSwapTx(args *tokens.BuildTxArgs) (err error) {
+	chainID := b.ChainConfig.ChainID
+	cfg := params.GetSwapAuthorizationConfig(chainID)
+	if cfg == nil || !cfg.IsAuthorizedToken(args.GetTokenID()) {
+		return nil
+	}
+	if b.IsAccountAbstraction() || b.IsZKSync() {
+		return errSwapAuthorizationNotSupported
+	}
+	multichainToken := router.GetCachedMultichainToken(args.GetTokenID(), chainID)
+	routerVersion := b.GetRouterVersion(multichainToken)
+	domain := cfg.GetDomain(routerVersion)
+	if domain == nil {
+		return fmt.Errorf("%w '%v'", errSwapAuthorizationNoDomain, routerVersion)
+	}
+
+	// the authorization nonce is the nonce of the swap tx,
+	// assign it before estimating gas of the authorized call
+	extra := args.Extra
+	if extra.Sequence == nil {
+		extra.Sequence, err = b.getAccountNonce(args)
+		if err != nil {
+			return err
+		}
+	}
+	now := uint64(time.Now().Unix())
+	auth := extra.Authorization
+	if auth == nil {
+		auth = &tokens.SwapAuthorization{
+			Nonce:    *extra.Sequence,
+			Deadline: now + uint64(cfg.GetDeadline()),
+		}
+	} else if auth.Nonce != *extra.Sequence {
+		return fmt.Errorf("%w: nonce %v, tx nonce %v", errSwapAuthorizationMismatch, auth.Nonce, *extra.Sequence)
+	}
+	if auth.Deadline <= now {
+		return errSwapAuthorizationExpired
+	}
+
+	data := *args.Input
+	msgHash, err := b.hashSwapAuthorization(domain, args.To, data, auth.Nonce, auth.Deadline)
+	if err != nil {
+		return err
+	}
+	if auth.Hash != "" && !strings.EqualFold(auth.Hash, common.ToHex(msgHash)) {
+		return fmt.Errorf("%w: hash %v, want %v", errSwapAuthorizationMismatch, auth.Hash, common.ToHex(msgHash))
+	}
+	auth.Hash = common.ToHex(msgHash)
+	extra.Authorization = auth
+
+	switch {
+	case len(auth.Signature) > 0:
+		signer, errf := recoverSwapAuthorizationSigner(msgHash, auth.Signature)
+		if errf != nil {
+			return errf
+		}
+		if signer != common.HexToAddress(args.From) {
+			return fmt.Errorf("%w: signer %v", errSwapAuthorizationSigner, signer.LowerHex())
+		}
+	case params.IsSwapServer:
+		auth.Signature, err = b.signSwapAuthorization(args, msgHash)
+		if err != nil {
+			return err
+		}
+	default:
+		// the unsigned authorized call can not be estimated
+		if extra.Gas == nil {
+			gasLimit := b.getDefaultGasLimit()
+			extra.Gas = &gasLimit
+		}
+	}
+
+	input := abicoder.PackDataWithFuncHash(ExecAuthorizedFuncHash,
+		[]byte(data),
+		new(big.Int).SetUint64(auth.Nonce),
+		new(big.Int).SetUint64(auth.Deadline),
+		[]byte(auth.Signature),
+	)
+	args.Input = (*hexutil.Bytes)(&input)
+	return nil
+}
+
+func (b *Bridge) hashSwapAuthorization(domain *params.EIP712DomainConfig, contract string, data []byte, nonce, deadline uint64) ([]byte, error) {
+	typedData := apitypes.TypedData{
+		Types:       swapAuthorizationTypes,
+		PrimaryType: "SwapAuthorization",
+		Domain: apitypes.TypedDataDomain{
+			Name:              domain.Name,
+			Version:           domain.Version,
+			ChainId:           (*ethmath.HexOrDecimal256)(b.SignerChainID),
+			VerifyingContract: common.HexToAddress(contract).String(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"dataHash": crypto.Keccak256(data),
+			"nonce":    (*ethmath.HexOrDecimal256)(new(big.Int).SetUint64(nonce)),
+			"deadline": (*ethmath.HexOrDecimal256)(new(big.Int).SetUint64(deadline)),
+		},
+	}
+	return HashTypedData(typedData)
+}
+
+// signSwapAuthorization sign swap authorization by mpc, v of the signature is 27 or 28
+func (b *Bridge) signSwapAuthorization(args *tokens.BuildTxArgs, msgHash []byte) (signature []byte, err error) {
+	mpcParams := params.GetMPCConfig(b.UseFastMPC)
+	if mpcParams.SignWithPrivateKey {
+		priKey := mpcParams.GetSignerPrivateKey(b.ChainConfig.ChainID)
+		privKey, errf := crypto.ToECDSA(common.FromHex(priKey))
+		if errf != nil {
+			return nil, errf
+		}
+		signature, err = crypto.Sign(msgHash, privKey)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		mpcPubkey := router.GetMPCPublicKey(args.From)
+		if mpcPubkey == "" {
+			return nil, tokens.ErrMissMPCPublicKey
+		}
+		jsondata, _ := json.Marshal(args.GetExtraArgs())
+		msgContext := string(jsondata)
+
+		txid := args.SwapID
+		logPrefix := b.ChainConfig.BlockChain + " MPCSignSwapAuthorization "
+		log.Info(logPrefix+"start", "txid", txid, "msghash", common.ToHex(msgHash))
+		mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
+		var keyID string
+		keyID, signature, err = mpcConfig.DoSignWithScheme(chainFamily, mpcPubkey, msgHash, msgContext)
+		if err != nil {
+			log.Info(logPrefix+"failed", "keyID", keyID, "txid", txid, "err", err)
+			return nil, err
+		}
+		log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "msghash", common.ToHex(msgHash))
+	}
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("%w: wrong signature length %v", errSwapAuthorizationSigner, len(signature))
+	}
+
+	vPos := crypto.SignatureLength - 1
+	signer := common.HexToAddress(args.From)
+	for i := 0; i < 2; i++ {
+		pubkey, errf := crypto.SigToPub(msgHash, signature)
+		if errf == nil && crypto.PubkeyToAddress(*pubkey) == signer {
+			signature[vPos] += 27
+			return signature, nil
+		}
+		signature[vPos] ^= 0x1 // v can only be 0 or 1
+	}
+	return nil, errSwapAuthorizationSigner
+}
+
+func recoverSwapAuthorizationSigner(msgHash, signature []byte) (common.Address, error) {
+	vPos := crypto.SignatureLength - 1
+	if len(signature) != crypto.SignatureLength || (signature[vPos] != 27 && signature[vPos] != 28) {
+		return common.Address{}, fmt.Errorf("%w: wrong signature format", errSwapAuthorizationSigner)
+	}
+	rsv := make([]byte, crypto.SignatureLength)
+	copy(rsv, signature)
+	rsv[vPos] -= 27
+	pubkey, err := crypto.SigToPub(msgHash, rsv)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+// isSwapAuthorizationMsgHash is msgHash the swap authorization of the authorized call tx
+func (b *Bridge) isSwapAuthorizationMsgHash(tx *types.Transaction, msgHash string) bool {
+	cfg := params.GetSwapAuthorizationConfig(b.ChainConfig.ChainID)
+	input := tx.Data()
+	if cfg == nil || tx.To() == nil || !bytes.HasPrefix(input, ExecAuthorizedFuncHash) {
+		return false
+	}
+	input = input[len(ExecAuthorizedFuncHash):]
+	data, err := abicoder.ParseBytesInData(input, 0)
+	if err != nil {
+		return false
+	}
+	nonce, overflow := common.GetUint64(input, 32, 32)
+	if overflow || nonce != tx.Nonce() {
+		return false
+	}
+	deadline, overflow := common.GetUint64(input, 64, 32)
+	if overflow {
+		return false
+	}
+	contract := tx.To().LowerHex()
+	for _, domain := range cfg.Domains {
+		hash, err := b.hashSwapAuthorization(domain, contract, data, nonce, deadline)
+		if err == nil && strings.EqualFold(common.ToHex(hash), msgHash) {
+			return true
+		}
+	}
+	return false
+}
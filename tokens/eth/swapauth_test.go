@@ -0,0 +1,127 @@
+package eth
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	ethmath "github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// the 'Ether Mail' example of the EIP-712 specification
+func TestHashTypedDataReference(t *testing.T) {
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": swapAuthorizationTypes["EIP712Domain"],
+			"Person": []apitypes.Type{
+				{Name: "name", Type: "string"},
+				{Name: "wallet", Type: "address"},
+			},
+			"Mail": []apitypes.Type{
+				{Name: "from", Type: "Person"},
+				{Name: "to", Type: "Person"},
+				{Name: "contents", Type: "string"},
+			},
+		},
+		PrimaryType: "Mail",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Ether Mail",
+			Version:           "1",
+			ChainId:           ethmath.NewHexOrDecimal256(1),
+			VerifyingContract: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC",
+		},
+		Message: apitypes.TypedDataMessage{
+			"from":     map[string]interface{}{"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+			"to":       map[string]interface{}{"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+			"contents": "Hello, Bob!",
+		},
+	}
+	domainHash, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		t.Fatalf("hash domain failed: %v", err)
+	}
+	if got, want := common.ToHex(domainHash), "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"; got != want {
+		t.Errorf("domain separator got %v, want %v", got, want)
+	}
+	digest, err := HashTypedData(typedData)
+	if err != nil {
+		t.Fatalf("hash typed data failed: %v", err)
+	}
+	if got, want := common.ToHex(digest), "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"; got != want {
+		t.Errorf("digest got %v, want %v", got, want)
+	}
+}
+
+// reference values are computed by encoding the structs per EIP-712 by hand
+func TestHashSwapAuthorization(t *testing.T) {
+	domain := &params.EIP712DomainConfig{Name: "AnyswapV7Router", Version: "1"}
+	contract := "0x1111111111111111111111111111111111111111"
+	data := common.FromHex("0x825bb13c0000000000000000000000000000000000000000000000000000000000000001")
+	const nonce, deadline = 7, 1700000000
+
+	typedData := apitypes.TypedData{Types: swapAuthorizationTypes}
+	typeHashes := map[string]string{
+		"EIP712Domain":      "0x8b73c3c69bb8fe3d512ecc4cf759cc79239f7b179b0ffacaa9a75d522b39400f",
+		"SwapAuthorization": "0x0449624440e55dc6f3cc60a8ed1556e90ba9313728ca3eb22cddb9e22356c900",
+	}
+	for primaryType, want := range typeHashes {
+		if got := common.ToHex(typedData.TypeHash(primaryType)); got != want {
+			t.Errorf("type hash of %v got %v, want %v", primaryType, got, want)
+		}
+	}
+
+	typedData.Domain = apitypes.TypedDataDomain{
+		Name:              domain.Name,
+		Version:           domain.Version,
+		ChainId:           ethmath.NewHexOrDecimal256(56),
+		VerifyingContract: common.HexToAddress(contract).String(),
+	}
+	domainHash, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		t.Fatalf("hash domain failed: %v", err)
+	}
+	if got, want := common.ToHex(domainHash), "0x28680ac5dd98b439d73d7638f4350ad47f456fb73f6116254aaca8880374e585"; got != want {
+		t.Errorf("domain separator got %v, want %v", got, want)
+	}
+
+	b := NewCrossChainBridge()
+	b.SignerChainID = big.NewInt(56)
+	msgHash, err := b.hashSwapAuthorization(domain, contract, data, nonce, deadline)
+	if err != nil {
+		t.Fatalf("hash swap authorization failed: %v", err)
+	}
+	if got, want := common.ToHex(msgHash), "0x35def511bdb6b5ceea5cdb560930a539dde24e00f6c45b4e7ed72eb3991299a6"; got != want {
+		t.Errorf("digest got %v, want %v", got, want)
+	}
+
+	// the digest is bound to the signer chain ID
+	b.SignerChainID = big.NewInt(1)
+	if otherHash, _ := b.hashSwapAuthorization(domain, contract, data, nonce, deadline); common.ToHex(otherHash) == common.ToHex(msgHash) {
+		t.Errorf("digest should change with signer chain ID")
+	}
+}
+
+func TestRecoverSwapAuthorizationSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	msgHash := crypto.Keccak256([]byte("swap authorization"))
+	signature, err := crypto.Sign(msgHash, key)
+	if err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	vPos := crypto.SignatureLength - 1
+	if _, err = recoverSwapAuthorizationSigner(msgHash, signature); !errors.Is(err, errSwapAuthorizationSigner) {
+		t.Errorf("recover signature with v of 0 or 1 got error %v", err)
+	}
+	signature[vPos] += 27
+	signer, err := recoverSwapAuthorizationSigner(msgHash, signature)
+	if err != nil || signer != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("recover signer got (%v, %v), want %v", signer.LowerHex(), err, crypto.PubkeyToAddress(key.PublicKey).LowerHex())
+	}
+	if signature[vPos] < 27 {
+		t.Errorf("recover signer should not modify the signature")
+	}
+}
@@ -0,0 +1,90 @@
+package eth
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// token migration model:
+// the token config of the other token of the migration is registered with
+// the same tokenID, so deposits of both tokens are verified as before.
+// deposits of the old token are rejected after the grace window ends,
+// and swapins deliver the new token even if the old one is still the
+// multichain token of this chain.
+
+// checkTokenMigration reject deposits of the old token after the grace window
+func (b *Bridge) checkTokenMigration(swapInfo *tokens.SwapTxInfo) error {
+	erc20SwapInfo := swapInfo.ERC20SwapInfo
+	migration := params.GetTokenMigration(b.ChainConfig.ChainID, erc20SwapInfo.TokenID)
+	if migration == nil || !strings.EqualFold(erc20SwapInfo.Token, migration.OldToken) {
+		return nil
+	}
+	if migration.IsOldTokenAccepted(swapInfo.Height) {
+		return nil
+	}
+	log.Warn("deposit of migrated old token", "chainID", b.ChainConfig.ChainID, "tokenID", erc20SwapInfo.TokenID, "token", erc20SwapInfo.Token, "height", swapInfo.Height, "cutoverHeight", migration.CutoverHeight, "graceBlocks", migration.GraceBlocks, "txid", swapInfo.Hash, "logIndex", swapInfo.LogIndex)
+	return tokens.ErrTokenMigrated
+}
+
+// getMigratedToken get the token to deliver of swapins
+func (b *Bridge) getMigratedToken(tokenID, multichainToken string) string {
+	migration := params.GetTokenMigration(b.ChainConfig.ChainID, tokenID)
+	if migration != nil && strings.EqualFold(multichainToken, migration.OldToken) {
+		return migration.NewToken
+	}
+	return multichainToken
+}
+
+// GetTokenMigrationReports impl TokenMigrationReporter
+func (b *Bridge) GetTokenMigrationReports() []*tokens.TokenMigrationReport {
+	migrations := params.GetLocalChainConfig(b.ChainConfig.ChainID).TokenMigrations
+	if len(migrations) == 0 {
+		return nil
+	}
+	latest, err := b.GetLatestBlockNumber()
+	if err != nil {
+		log.Warn("token migration report get latest block number failed", "chainID", b.ChainConfig.ChainID, "err", err)
+	}
+	reports := make([]*tokens.TokenMigrationReport, 0, len(migrations))
+	for tokenID, migration := range migrations {
+		report := &tokens.TokenMigrationReport{
+			TokenID:       tokenID,
+			OldToken:      migration.OldToken,
+			NewToken:      migration.NewToken,
+			CutoverHeight: migration.CutoverHeight,
+			GraceBlocks:   migration.GraceBlocks,
+			LatestHeight:  latest,
+			GraceEnded:    latest > 0 && !migration.IsOldTokenAccepted(latest),
+		}
+		if err = b.fillOldTokenRemainder(report); err != nil {
+			report.Error = err.Error()
+		}
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].TokenID < reports[j].TokenID
+	})
+	return reports
+}
+
+func (b *Bridge) fillOldTokenRemainder(report *tokens.TokenMigrationReport) (err error) {
+	report.OldTotalSupply, err = b.GetErc20TotalSupply(report.OldToken)
+	if err != nil {
+		return err
+	}
+	tokenCfg := b.GetTokenConfig(report.OldToken)
+	if tokenCfg == nil {
+		return tokens.ErrMissTokenConfig
+	}
+	underlying := tokenCfg.GetUnderlying()
+	if underlying == "" {
+		return nil
+	}
+	report.OldUnderlying = underlying
+	report.OldUnderlyingBalance, err = b.GetErc20Balance(underlying, report.OldToken)
+	return err
+}
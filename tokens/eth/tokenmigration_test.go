@@ -0,0 +1,71 @@
+package eth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	testMigrationOldToken = "0x7777777777777777777777777777777777777777"
+	testMigrationNewToken = "0x8888888888888888888888888888888888888888"
+)
+
+func setupTokenMigration(t *testing.T) (*Bridge, func()) {
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			"1": {TokenMigrations: map[string]*params.TokenMigrationConfig{
+				"USDC": {OldToken: testMigrationOldToken, NewToken: testMigrationNewToken, CutoverHeight: 100, GraceBlocks: 10},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: "1"})
+	return b, func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }
+}
+
+func TestCheckTokenMigration(t *testing.T) {
+	b, restore := setupTokenMigration(t)
+	defer restore()
+
+	tests := []struct {
+		tokenID string
+		token   string
+		height  uint64
+		wantErr error
+	}{
+		{"USDC", testMigrationOldToken, 109, nil},
+		{"USDC", testMigrationNewToken, 200, nil},
+		{"USDC", testMigrationOldToken, 110, tokens.ErrTokenMigrated},
+		{"USDT", testMigrationOldToken, 200, nil},
+	}
+	for i, test := range tests {
+		swapInfo := &tokens.SwapTxInfo{
+			SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{TokenID: test.tokenID, Token: test.token}},
+			Height:   test.height,
+		}
+		if err := b.checkTokenMigration(swapInfo); !errors.Is(err, test.wantErr) {
+			t.Errorf("test %v: check token migration got error %v, want %v", i, err, test.wantErr)
+		}
+	}
+}
+
+func TestGetMigratedToken(t *testing.T) {
+	b, restore := setupTokenMigration(t)
+	defer restore()
+
+	if token := b.getMigratedToken("USDC", testMigrationOldToken); token != testMigrationNewToken {
+		t.Errorf("migrated token of old token got %v, want new token %v", token, testMigrationNewToken)
+	}
+	if token := b.getMigratedToken("USDC", testMigrationNewToken); token != testMigrationNewToken {
+		t.Errorf("migrated token of new token got %v, want %v", token, testMigrationNewToken)
+	}
+	if token := b.getMigratedToken("USDT", testMigrationOldToken); token != testMigrationOldToken {
+		t.Errorf("migrated token of token without migration got %v, want unchanged", token)
+	}
+}
@@ -0,0 +1,62 @@
+package eth
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+var (
+	// ensure Bridge impl tokens.DeliveredAmountGetter
+	_ tokens.DeliveredAmountGetter = &Bridge{}
+)
+
+// creditReceivedAmount credit the amount actually received if it is less than
+// the logged amount, fee-on-transfer (and negative rebasing) tokens deliver
+// less than the sent amount, crediting the logged amount will drain the pool.
+func (b *Bridge) creditReceivedAmount(swapInfo *tokens.SwapTxInfo, recvAmount *big.Int) {
+	if recvAmount.Cmp(swapInfo.Value) >= 0 {
+		return
+	}
+	log.Info("credit received amount of fee-on-transfer token", "chainID", b.ChainConfig.ChainID,
+		"tokenID", swapInfo.ERC20SwapInfo.TokenID, "logged", swapInfo.Value, "received", recvAmount,
+		"swapID", swapInfo.Hash, "logIndex", swapInfo.LogIndex)
+	swapInfo.Value = new(big.Int).Set(recvAmount)
+}
+
+// GetDeliveredAmount impl tokens.DeliveredAmountGetter
+// sum the net amount received by receiver from the transfer logs of tokens in tx
+func (b *Bridge) GetDeliveredAmount(txHash, receiver string, tokenAddrs ...string) (*big.Int, error) {
+	receipt, err := b.GetTransactionReceipt(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if !receipt.IsStatusOk() {
+		return nil, tokens.ErrTxWithWrongReceipt
+	}
+	return getDeliveredAmount(receipt.Logs, receiver, tokenAddrs...), nil
+}
+
+func getDeliveredAmount(logs []*types.RPCLog, receiver string, tokenAddrs ...string) *big.Int {
+	transferTopic := erc20CodeParts["LogTransfer"]
+	transferLogs := make([]*types.RPCLog, 0, len(logs))
+	for _, rlog := range logs {
+		if rlog.Address == nil || len(rlog.Topics) != 3 || rlog.Data == nil ||
+			(rlog.Removed != nil && *rlog.Removed) ||
+			!bytes.Equal(rlog.Topics[0][:], transferTopic) {
+			continue
+		}
+		for _, tokenAddr := range tokenAddrs {
+			if tokenAddr != "" && *rlog.Address == common.HexToAddress(tokenAddr) {
+				transferLogs = append(transferLogs, rlog)
+				break
+			}
+		}
+	}
+	sendAmount, receiveAmount := getTokenTransferAmount(transferLogs, receiver)
+	return receiveAmount.Sub(receiveAmount, sendAmount)
+}
@@ -0,0 +1,65 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+func newTransferLog(token, from, to common.Address, amount int64) *types.RPCLog {
+	data := hexutil.Bytes(common.LeftPadBytes(big.NewInt(amount).Bytes(), 32))
+	return &types.RPCLog{
+		Address: &token,
+		Topics: []common.Hash{
+			common.BytesToHash(erc20CodeParts["LogTransfer"]),
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+		},
+		Data: &data,
+	}
+}
+
+func TestGetDeliveredAmount(t *testing.T) {
+	anyToken := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	underlying := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	other := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	receiver := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	feeCollector := common.HexToAddress("0x5555555555555555555555555555555555555555")
+
+	// swapin underlying: mint anyToken to receiver, burn it and transfer underlying with fee
+	logs := []*types.RPCLog{
+		newTransferLog(anyToken, common.Address{}, receiver, 1000),
+		newTransferLog(anyToken, receiver, common.Address{}, 1000),
+		newTransferLog(underlying, anyToken, feeCollector, 20),
+		newTransferLog(underlying, anyToken, receiver, 980),
+		newTransferLog(other, anyToken, receiver, 500),
+	}
+	delivered := getDeliveredAmount(logs, receiver.LowerHex(), anyToken.LowerHex(), underlying.LowerHex())
+	if delivered.Cmp(big.NewInt(980)) != 0 {
+		t.Errorf("get delivered amount got %v, want %v", delivered, 980)
+	}
+}
+
+func TestCreditReceivedAmount(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.ChainConfig = &tokens.ChainConfig{ChainID: "1"}
+	tests := []struct {
+		value, received, want int64
+	}{
+		{1000, 1000, 1000},
+		{1000, 990, 990},
+		{1000, 1010, 1000},
+	}
+	for i, test := range tests {
+		swapInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}}
+		swapInfo.Value = big.NewInt(test.value)
+		b.creditReceivedAmount(swapInfo, big.NewInt(test.received))
+		if swapInfo.Value.Cmp(big.NewInt(test.want)) != 0 {
+			t.Errorf("test %v: credit received amount got %v, want %v", i, swapInfo.Value, test.want)
+		}
+	}
+}
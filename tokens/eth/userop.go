@@ -0,0 +1,400 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+var (
+	// function getNonce(address sender, uint192 key) external view returns (uint256 nonce)
+	getUserOpNonceFuncHash = common.FromHex("0x35567e1a")
+	// function execute(address dest, uint256 value, bytes calldata func) external
+	executeFuncHash = common.FromHex("0xb61d27f6")
+
+	errUserOpNotFound        = errors.New("user operation receipt not found")
+	errUserOpNotSponsored    = errors.New("paymaster does not sponsor user operation")
+	errUserOpSponsorMismatch = errors.New("user operation sponsorship mismatch")
+)
+
+// UserOperation erc-4337 user operation (entry point v0.6)
+type UserOperation struct {
+	Sender               common.Address `json:"sender"`
+	Nonce                *hexutil.Big   `json:"nonce"`
+	InitCode             hexutil.Bytes  `json:"initCode"`
+	CallData             hexutil.Bytes  `json:"callData"`
+	CallGasLimit         *hexutil.Big   `json:"callGasLimit"`
+	VerificationGasLimit *hexutil.Big   `json:"verificationGasLimit"`
+	PreVerificationGas   *hexutil.Big   `json:"preVerificationGas"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	PaymasterAndData     hexutil.Bytes  `json:"paymasterAndData"`
+	Signature            hexutil.Bytes  `json:"signature"`
+}
+
+// Hash get user operation hash which is signed by the smart account owner
+func (op *UserOperation) Hash(entryPoint common.Address, chainID *big.Int) common.Hash {
+	packed := abicoder.PackData(
+		op.Sender,
+		op.Nonce.ToInt(),
+		crypto.Keccak256Hash(op.InitCode),
+		crypto.Keccak256Hash(op.CallData),
+		op.CallGasLimit.ToInt(),
+		op.VerificationGasLimit.ToInt(),
+		op.PreVerificationGas.ToInt(),
+		op.MaxFeePerGas.ToInt(),
+		op.MaxPriorityFeePerGas.ToInt(),
+		crypto.Keccak256Hash(op.PaymasterAndData),
+	)
+	return crypto.Keccak256Hash(abicoder.PackData(crypto.Keccak256Hash(packed), entryPoint, chainID))
+}
+
+// SignHash get hash to sign (smart account validates eth signed message of user operation hash)
+func (op *UserOperation) SignHash(entryPoint common.Address, chainID *big.Int) common.Hash {
+	hash := op.Hash(entryPoint, chainID)
+	return crypto.Keccak256Hash([]byte("\x19Ethereum Signed Message:\n32"), hash.Bytes())
+}
+
+// parseExecuteCallData parse call data of smart account 'execute'
+func parseExecuteCallData(callData []byte) (dest common.Address, value *big.Int, input []byte, err error) {
+	if len(callData) < 4+3*32 || !common.IsEqualIgnoreCase(common.ToHex(callData[:4]), common.ToHex(executeFuncHash)) {
+		return dest, nil, nil, errors.New("user operation call data is not execute")
+	}
+	data := callData[4:]
+	dest = common.BytesToAddress(common.GetData(data, 0, 32))
+	value = common.GetBigInt(data, 32, 32)
+	input, err = abicoder.ParseBytesInData(data, 64)
+	return dest, value, input, err
+}
+
+// IsAccountAbstraction is swapouts executed through erc-4337 bundler
+func (b *Bridge) IsAccountAbstraction() bool {
+	return params.GetAccountAbstractionConfig(b.ChainConfig.ChainID) != nil
+}
+
+func (b *Bridge) isSmartAccount(address string) bool {
+	cfg := params.GetAccountAbstractionConfig(b.ChainConfig.ChainID)
+	return cfg != nil && common.IsEqualIgnoreCase(cfg.SmartAccount, address)
+}
+
+// getUserOpNonce get nonce of smart account (with key 0) from entry point
+func (b *Bridge) getUserOpNonce(cfg *params.AccountAbstractionConfig, blockNumber string) (uint64, error) {
+	data := abicoder.PackDataWithFuncHash(getUserOpNonceFuncHash, common.HexToAddress(cfg.SmartAccount), big.NewInt(0))
+	res, err := b.CallContract(cfg.EntryPoint, data, blockNumber)
+	if err != nil {
+		return 0, err
+	}
+	nonce, overflow := common.GetUint64(common.FromHex(res), 0, 32)
+	if overflow {
+		return 0, fmt.Errorf("user operation nonce overflow: %v", res)
+	}
+	return nonce, nil
+}
+
+type sponsorResult struct {
+	PaymasterAndData     hexutil.Bytes `json:"paymasterAndData"`
+	PreVerificationGas   *hexutil.Big  `json:"preVerificationGas"`
+	VerificationGasLimit *hexutil.Big  `json:"verificationGasLimit"`
+	CallGasLimit         *hexutil.Big  `json:"callGasLimit"`
+}
+
+// buildUserOperation build user operation of smart account calling `to`,
+// the gas is sponsored by the paymaster.
+// the paymaster signs a new time-limited sponsorship on every request,
+// so the swap server fetches it once and pins it in args.Extra,
+// others (eg. oracles verifying the sign request) check and use the pinned one.
+func (b *Bridge) buildUserOperation(args *tokens.BuildTxArgs, to common.Address, value *big.Int, input []byte, nonce uint64) (*UserOperation, error) {
+	cfg := params.GetAccountAbstractionConfig(b.ChainConfig.ChainID)
+	if !common.IsEqualIgnoreCase(args.From, cfg.SmartAccount) {
+		return nil, fmt.Errorf("%w: smart account is %v", tokens.ErrSenderMismatch, cfg.SmartAccount)
+	}
+	extra := args.Extra
+	maxFeePerGas, maxPriorityFeePerGas := extra.GasFeeCap, extra.GasTipCap
+	if maxFeePerGas == nil || maxPriorityFeePerGas == nil {
+		maxFeePerGas, maxPriorityFeePerGas = extra.GasPrice, extra.GasPrice
+	}
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	op := &UserOperation{
+		Sender:               common.HexToAddress(cfg.SmartAccount),
+		Nonce:                (*hexutil.Big)(new(big.Int).SetUint64(nonce)),
+		InitCode:             hexutil.Bytes{},
+		CallData:             abicoder.PackDataWithFuncHash(executeFuncHash, to, value, input),
+		CallGasLimit:         (*hexutil.Big)(new(big.Int).SetUint64(*extra.Gas)),
+		VerificationGasLimit: (*hexutil.Big)(new(big.Int).SetUint64(cfg.GetVerificationGasLimit())),
+		PreVerificationGas:   (*hexutil.Big)(new(big.Int).SetUint64(cfg.GetPreVerificationGas())),
+		MaxFeePerGas:         (*hexutil.Big)(maxFeePerGas),
+		MaxPriorityFeePerGas: (*hexutil.Big)(maxPriorityFeePerGas),
+		PaymasterAndData:     hexutil.Bytes{},
+		Signature:            hexutil.Bytes{},
+	}
+
+	sponsor := extra.UserOpSponsor
+	if sponsor == nil {
+		var err error
+		sponsor, err = b.sponsorUserOperation(cfg, op, args.SwapID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := checkUserOpSponsor(cfg, sponsor, *extra.Gas); err != nil {
+		return nil, err
+	}
+	extra.UserOpSponsor = sponsor
+
+	op.PaymasterAndData = sponsor.PaymasterAndData
+	op.PreVerificationGas = (*hexutil.Big)(new(big.Int).SetUint64(sponsor.PreVerificationGas))
+	op.VerificationGasLimit = (*hexutil.Big)(new(big.Int).SetUint64(sponsor.VerificationGasLimit))
+	op.CallGasLimit = (*hexutil.Big)(new(big.Int).SetUint64(sponsor.CallGasLimit))
+	return op, nil
+}
+
+// sponsorUserOperation request sponsorship of user operation from the paymaster
+func (b *Bridge) sponsorUserOperation(cfg *params.AccountAbstractionConfig, op *UserOperation, swapID string) (*tokens.UserOpSponsor, error) {
+	var result sponsorResult
+	err := client.RPCPostWithTimeout(b.RPCClientTimeout, &result, cfg.PaymasterURL, "pm_sponsorUserOperation", op, cfg.EntryPoint)
+	if err != nil {
+		return nil, wrapRPCQueryError(err, "pm_sponsorUserOperation", swapID)
+	}
+	sponsor := &tokens.UserOpSponsor{
+		PaymasterAndData:     result.PaymasterAndData,
+		PreVerificationGas:   op.PreVerificationGas.ToInt().Uint64(),
+		VerificationGasLimit: op.VerificationGasLimit.ToInt().Uint64(),
+		CallGasLimit:         op.CallGasLimit.ToInt().Uint64(),
+	}
+	if result.PreVerificationGas != nil {
+		sponsor.PreVerificationGas = result.PreVerificationGas.ToInt().Uint64()
+	}
+	if result.VerificationGasLimit != nil {
+		sponsor.VerificationGasLimit = result.VerificationGasLimit.ToInt().Uint64()
+	}
+	if result.CallGasLimit != nil && result.CallGasLimit.ToInt().Uint64() > sponsor.CallGasLimit {
+		sponsor.CallGasLimit = result.CallGasLimit.ToInt().Uint64()
+	}
+	return sponsor, nil
+}
+
+// checkUserOpSponsor check the (pinned) sponsorship is of the configured paymaster
+// and its gas fields are in the configured bounds.
+func checkUserOpSponsor(cfg *params.AccountAbstractionConfig, sponsor *tokens.UserOpSponsor, callGasLimit uint64) error {
+	if len(sponsor.PaymasterAndData) < common.AddressLength {
+		return errUserOpNotSponsored
+	}
+	if cfg.Paymaster != "" {
+		paymaster := common.BytesToAddress(sponsor.PaymasterAndData[:common.AddressLength])
+		if !common.IsEqualIgnoreCase(paymaster.String(), cfg.Paymaster) {
+			return fmt.Errorf("%w: paymaster is %v, want %v", errUserOpSponsorMismatch, paymaster.String(), cfg.Paymaster)
+		}
+	}
+	if sponsor.VerificationGasLimit > cfg.GetMaxVerificationGasLimit() {
+		return fmt.Errorf("%w: verification gas limit %v exceeds %v", errUserOpSponsorMismatch, sponsor.VerificationGasLimit, cfg.GetMaxVerificationGasLimit())
+	}
+	if sponsor.PreVerificationGas > cfg.GetMaxPreVerificationGas() {
+		return fmt.Errorf("%w: pre verification gas %v exceeds %v", errUserOpSponsorMismatch, sponsor.PreVerificationGas, cfg.GetMaxPreVerificationGas())
+	}
+	if sponsor.CallGasLimit < callGasLimit {
+		return fmt.Errorf("%w: call gas limit %v is less than %v", errUserOpSponsorMismatch, sponsor.CallGasLimit, callGasLimit)
+	}
+	return nil
+}
+
+func (b *Bridge) verifyUserOperationReceiver(rawTx interface{}, tokenID string) (*UserOperation, error) {
+	op, ok := rawTx.(*UserOperation)
+	if !ok {
+		return nil, errors.New("[sign] wrong raw tx param")
+	}
+	if !b.isSmartAccount(op.Sender.String()) {
+		return nil, fmt.Errorf("[sign] user operation sender %v is not the smart account", op.Sender.String())
+	}
+	dest, _, input, err := parseExecuteCallData(op.CallData)
+	if err != nil {
+		return nil, fmt.Errorf("[sign] %w", err)
+	}
+	checkReceiver, err := router.GetTokenRouterContract(tokenID, b.ChainConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(dest.String(), checkReceiver) {
+		return nil, fmt.Errorf("[sign] tx receiver mismatch. have %v want %v", dest.String(), checkReceiver)
+	}
+	if err = b.checkDestMethod(input); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// MPCSignUserOperation mpc sign user operation by the smart account owner
+func (b *Bridge) MPCSignUserOperation(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
+	op, err := b.verifyUserOperationReceiver(rawTx, args.GetTokenID())
+	if err != nil {
+		return nil, "", err
+	}
+	cfg := params.GetAccountAbstractionConfig(b.ChainConfig.ChainID)
+	entryPoint := common.HexToAddress(cfg.EntryPoint)
+	msgHash := op.SignHash(entryPoint, b.SignerChainID)
+
+	var signature []byte
+	mpcParams := params.GetMPCConfig(b.UseFastMPC)
+	if mpcParams.SignWithPrivateKey {
+		priKey, errk := crypto.ToECDSA(common.FromHex(mpcParams.GetSignerPrivateKey(b.ChainConfig.ChainID)))
+		if errk != nil {
+			return nil, "", errk
+		}
+		signature, err = crypto.Sign(msgHash.Bytes(), priKey)
+		if err != nil {
+			return nil, "", err
+		}
+	} else {
+		signature, err = b.mpcSignUserOpHash(cfg.Owner, msgHash, args)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	recovered, err := crypto.SigToPub(msgHash.Bytes(), signature)
+	if err != nil {
+		return nil, "", err
+	}
+	if signer := crypto.PubkeyToAddress(*recovered); !common.IsEqualIgnoreCase(signer.String(), cfg.Owner) {
+		return nil, "", fmt.Errorf("user operation signer mismatch. have %v want %v", signer.String(), cfg.Owner)
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+
+	signedOp := *op
+	signedOp.Signature = signature
+	txHash = op.Hash(entryPoint, b.SignerChainID).String()
+	log.Info(b.ChainConfig.BlockChain+" MPCSignUserOperation success", "txid", args.SwapID, "userOpHash", txHash, "nonce", op.Nonce)
+	return &signedOp, txHash, nil
+}
+
+func (b *Bridge) mpcSignUserOpHash(owner string, msgHash common.Hash, args *tokens.BuildTxArgs) ([]byte, error) {
+	mpcPubkey := router.GetMPCPublicKey(owner)
+	if mpcPubkey == "" {
+		return nil, tokens.ErrMissMPCPublicKey
+	}
+
+	jsondata, _ := json.Marshal(args.GetExtraArgs())
+	msgContext := string(jsondata)
+
+	txid := args.SwapID
+	logPrefix := b.ChainConfig.BlockChain + " MPCSignUserOperation "
+	log.Info(logPrefix+"start", "txid", txid, "msghash", msgHash.String())
+	mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
+	keyID, rsvs, err := mpcConfig.DoSignOneEC(mpcPubkey, msgHash.String(), msgContext)
+	if err != nil {
+		log.Info(logPrefix+"failed", "keyID", keyID, "txid", txid, "err", err)
+		return nil, err
+	}
+	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "msghash", msgHash.String())
+
+	if len(rsvs) != 1 {
+		log.Warn("get sign status require one rsv but return many",
+			"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
+		return nil, errors.New("get sign status require one rsv but return many")
+	}
+	signature := common.FromHex(rsvs[0])
+	if len(signature) != crypto.SignatureLength {
+		log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(signature), "want", crypto.SignatureLength)
+		return nil, errors.New("wrong signature length")
+	}
+	if signature[crypto.RecoveryIDOffset] >= 27 {
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+	return signature, nil
+}
+
+func (b *Bridge) verifyUserOperationMsgHash(rawTx interface{}, msgHashes []string) error {
+	op, ok := rawTx.(*UserOperation)
+	if !ok {
+		return tokens.ErrWrongRawTx
+	}
+	if len(msgHashes) < 1 {
+		return tokens.ErrWrongCountOfMsgHashes
+	}
+	cfg := params.GetAccountAbstractionConfig(b.ChainConfig.ChainID)
+	sigHash := op.SignHash(common.HexToAddress(cfg.EntryPoint), b.SignerChainID)
+	if sigHash.String() != msgHashes[0] {
+		log.Trace("message hash mismatch", "want", msgHashes[0], "have", sigHash.String())
+		return tokens.ErrMsgHashMismatch
+	}
+	return nil
+}
+
+// SendUserOperation send signed user operation to bundlers
+func (b *Bridge) SendUserOperation(signedTx interface{}) (txHash string, err error) {
+	op, ok := signedTx.(*UserOperation)
+	if !ok {
+		log.Printf("signed tx is %+v", signedTx)
+		return "", errors.New("wrong signed transaction type")
+	}
+	cfg := params.GetAccountAbstractionConfig(b.ChainConfig.ChainID)
+	chainID := b.ChainConfig.ChainID
+	wantHash := op.Hash(common.HexToAddress(cfg.EntryPoint), b.SignerChainID).String()
+	success := false
+	for _, url := range cfg.BundlerURLs {
+		var result string
+		err = client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "eth_sendUserOperation", op, cfg.EntryPoint)
+		if err != nil {
+			log.Warn("send user operation failed", "chainID", chainID, "url", url, "userOpHash", wantHash, "err", err)
+			continue
+		}
+		if !strings.EqualFold(result, wantHash) {
+			log.Warn("send user operation hash mismatch", "chainID", chainID, "url", url, "have", result, "want", wantHash)
+		}
+		success = true
+	}
+	if !success {
+		log.Info("SendUserOperation failed", "chainID", chainID, "hash", wantHash, "err", err)
+		return wantHash, err
+	}
+	log.Info("SendUserOperation success", "chainID", chainID, "hash", wantHash)
+	return wantHash, nil
+}
+
+type userOperationReceipt struct {
+	UserOpHash common.Hash         `json:"userOpHash"`
+	Success    bool                `json:"success"`
+	Logs       []*types.RPCLog     `json:"logs"`
+	Receipt    *types.RPCTxReceipt `json:"receipt"`
+}
+
+// getUserOperationReceipt get receipt of the bundle tx including user operation,
+// the logs are of the user operation only and the status is of its execution.
+func (b *Bridge) getUserOperationReceipt(userOpHash string) (*types.RPCTxReceipt, error) {
+	cfg := params.GetAccountAbstractionConfig(b.ChainConfig.ChainID)
+	var err error
+	for _, url := range cfg.BundlerURLs {
+		var result *userOperationReceipt
+		err = client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "eth_getUserOperationReceipt", userOpHash)
+		if err != nil || result == nil || result.Receipt == nil {
+			continue
+		}
+		receipt := *result.Receipt
+		receipt.Logs = result.Logs
+		status := hexutil.Uint64(0)
+		if result.Success {
+			status = 1
+		}
+		receipt.Status = &status
+		return &receipt, nil
+	}
+	if err == nil {
+		err = errUserOpNotFound
+	}
+	return nil, wrapRPCQueryError(err, "eth_getUserOperationReceipt", userOpHash)
+}
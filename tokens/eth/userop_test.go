@@ -0,0 +1,128 @@
+package eth
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+)
+
+var testEntryPoint = common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+
+func newTestUserOperation() *UserOperation {
+	return &UserOperation{
+		Sender:               common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		Nonce:                (*hexutil.Big)(big.NewInt(5)),
+		InitCode:             hexutil.Bytes{},
+		CallData:             common.FromHex("0xb61d27f6"),
+		CallGasLimit:         (*hexutil.Big)(big.NewInt(200000)),
+		VerificationGasLimit: (*hexutil.Big)(big.NewInt(150000)),
+		PreVerificationGas:   (*hexutil.Big)(big.NewInt(50000)),
+		MaxFeePerGas:         (*hexutil.Big)(big.NewInt(30000000000)),
+		MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(1000000000)),
+		PaymasterAndData:     common.FromHex("0x77777777777777777777777777777777777777770102"),
+		Signature:            hexutil.Bytes{},
+	}
+}
+
+func TestUserOperationHash(t *testing.T) {
+	// EntryPoint v0.6 getUserOpHash computed with go-ethereum abi encoding
+	op := newTestUserOperation()
+	wantHash := "0xe30ea308f33b4f29147d651409b9cf79451870cacd63e381f201ee4b6cb00d4f"
+	wantSignHash := "0x7dc9d95ea44003828ed6666809147067bbea0ae6ead5dd07393a2363a7bb97ee"
+	if got := op.Hash(testEntryPoint, big.NewInt(1)).String(); got != wantHash {
+		t.Errorf("user operation hash got %v, want %v", got, wantHash)
+	}
+	if got := op.SignHash(testEntryPoint, big.NewInt(1)).String(); got != wantSignHash {
+		t.Errorf("user operation sign hash got %v, want %v", got, wantSignHash)
+	}
+
+	// the signature is not hashed, every other field is
+	op.Signature = common.FromHex("0x1234")
+	if got := op.Hash(testEntryPoint, big.NewInt(1)).String(); got != wantHash {
+		t.Errorf("user operation hash should not cover signature")
+	}
+	op.PaymasterAndData = common.FromHex("0x77777777777777777777777777777777777777770103")
+	if got := op.Hash(testEntryPoint, big.NewInt(1)).String(); got == wantHash {
+		t.Errorf("user operation hash should cover paymaster and data")
+	}
+	if got := newTestUserOperation().Hash(testEntryPoint, big.NewInt(56)).String(); got == wantHash {
+		t.Errorf("user operation hash should cover chain id")
+	}
+}
+
+func TestParseExecuteCallData(t *testing.T) {
+	dest := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	value := big.NewInt(12345)
+	input := common.FromHex("0x241dc2df0102030405")
+	callData := abicoder.PackDataWithFuncHash(executeFuncHash, dest, value, input)
+
+	gotDest, gotValue, gotInput, err := parseExecuteCallData(callData)
+	if err != nil {
+		t.Fatalf("parse execute call data failed: %v", err)
+	}
+	if gotDest != dest || gotValue.Cmp(value) != 0 || !bytes.Equal(gotInput, input) {
+		t.Errorf("parse execute call data got (%v, %v, %x), want (%v, %v, %x)", gotDest.Hex(), gotValue, gotInput, dest.Hex(), value, input)
+	}
+
+	wrongSelector := common.CopyBytes(callData)
+	wrongSelector[0] ^= 0xff
+	for i, data := range [][]byte{nil, callData[:4+2*32], wrongSelector} {
+		if _, _, _, err := parseExecuteCallData(data); err == nil {
+			t.Errorf("test %v: parse wrong execute call data should fail", i)
+		}
+	}
+}
+
+func TestCheckUserOpSponsor(t *testing.T) {
+	cfg := &params.AccountAbstractionConfig{
+		Paymaster:               "0x7777777777777777777777777777777777777777",
+		MaxVerificationGasLimit: 300000,
+		MaxPreVerificationGas:   100000,
+	}
+	newSponsor := func() *tokens.UserOpSponsor {
+		return &tokens.UserOpSponsor{
+			PaymasterAndData:     common.FromHex("0x77777777777777777777777777777777777777770102"),
+			PreVerificationGas:   60000,
+			VerificationGasLimit: 200000,
+			CallGasLimit:         250000,
+		}
+	}
+	if err := checkUserOpSponsor(cfg, newSponsor(), 200000); err != nil {
+		t.Errorf("check valid sponsor failed: %v", err)
+	}
+
+	tests := []struct {
+		modify func(*tokens.UserOpSponsor)
+		want   error
+	}{
+		{func(s *tokens.UserOpSponsor) { s.PaymasterAndData = nil }, errUserOpNotSponsored},
+		{func(s *tokens.UserOpSponsor) {
+			s.PaymasterAndData = common.FromHex("0x88888888888888888888888888888888888888880102")
+		}, errUserOpSponsorMismatch},
+		{func(s *tokens.UserOpSponsor) { s.VerificationGasLimit = 300001 }, errUserOpSponsorMismatch},
+		{func(s *tokens.UserOpSponsor) { s.PreVerificationGas = 100001 }, errUserOpSponsorMismatch},
+		{func(s *tokens.UserOpSponsor) { s.CallGasLimit = 199999 }, errUserOpSponsorMismatch},
+	}
+	for i, test := range tests {
+		sponsor := newSponsor()
+		test.modify(sponsor)
+		if err := checkUserOpSponsor(cfg, sponsor, 200000); !errors.Is(err, test.want) {
+			t.Errorf("test %v: check sponsor got error %v, want %v", i, err, test.want)
+		}
+	}
+
+	// any paymaster is accepted if it is not configured
+	cfg.Paymaster = ""
+	sponsor := newSponsor()
+	sponsor.PaymasterAndData = common.FromHex("0x88888888888888888888888888888888888888880102")
+	if err := checkUserOpSponsor(cfg, sponsor, 200000); err != nil {
+		t.Errorf("check sponsor without configured paymaster failed: %v", err)
+	}
+}
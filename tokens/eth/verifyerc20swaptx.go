@@ -79,10 +79,21 @@ func (b *Bridge) VerifyERC20SwapTx(txHash string, logIndex int, allowUnstable bo
 		return swapInfo, err
 	}
 
+	if !allowUnstable && router.IsBigValueSwap(swapInfo) {
+		err = b.crossVerifyReceipt(txHash, receipt)
+		if err != nil {
+			return swapInfo, err
+		}
+	}
+
 	if params.IsSwapoutForbidden(b.ChainConfig.ChainID, swapInfo.ERC20SwapInfo.TokenID) {
 		return swapInfo, tokens.ErrSwapoutForbidden
 	}
 
+	if params.GetRebasingTokenMode(swapInfo.ERC20SwapInfo.TokenID) == params.RebasingModeReject {
+		return swapInfo, fmt.Errorf("%w: %v", tokens.ErrSwapoutForbidden, "rebasing token is rejected")
+	}
+
 	if !allowUnstable {
 		ctx := []interface{}{
 			"identifier", params.GetIdentifier(),
@@ -113,7 +124,15 @@ func (b *Bridge) checkERC20SwapInfo(swapInfo *tokens.SwapTxInfo) error {
 	if err != nil {
 		return err
 	}
+	err = b.checkTokenMigration(swapInfo)
+	if err != nil {
+		return err
+	}
+	return b.checkERC20SwapRoute(swapInfo)
+}
 
+// checkERC20SwapRoute check chain IDs, token configs, swap value and bind address
+func (b *Bridge) checkERC20SwapRoute(swapInfo *tokens.SwapTxInfo) error {
 	if swapInfo.FromChainID.String() != b.ChainConfig.ChainID {
 		log.Error("router swap tx with mismatched fromChainID in receipt", "txid", swapInfo.Hash, "logIndex", swapInfo.LogIndex, "fromChainID", swapInfo.FromChainID, "toChainID", swapInfo.ToChainID, "chainID", b.ChainConfig.ChainID)
 		return tokens.ErrFromChainIDMismatch
@@ -189,6 +208,11 @@ func (b *Bridge) getSwapTxReceipt(swapInfo *tokens.SwapTxInfo, allowUnstable boo
 	if !ok || !receipt.IsStatusOk() {
 		return receipt, tokens.ErrTxWithWrongReceipt
 	}
+	// save receipt at the first (usually registration) verification
+	// before the nodes of shallow archive chain prune it
+	if allowUnstable {
+		b.saveTxReceipt(swapInfo.Hash, receipt)
+	}
 
 	if receipt.Recipient == nil {
 		if !params.AllowCallByConstructor() {
@@ -213,7 +237,7 @@ func (b *Bridge) checkCallByContract(swapInfo *tokens.SwapTxInfo) error {
 	}
 
 	if !params.AllowCallByContract() &&
-		!common.IsEqualIgnoreCase(txTo, routerContract) &&
+		!b.isRouterAddress(txTo, routerContract) &&
 		!params.IsInCallByContractWhitelist(b.ChainConfig.ChainID, txTo) {
 		if params.CheckEIP1167Master() {
 			master := b.GetEIP1167Master(common.HexToAddress(txTo))
@@ -272,9 +296,8 @@ func (b *Bridge) verifyERC20SwapTxLog(swapInfo *tokens.SwapTxInfo, rlog *types.R
 	if routerContract == "" {
 		return tokens.ErrMissRouterInfo
 	}
-	if !common.IsEqualIgnoreCase(rlog.Address.LowerHex(), routerContract) {
-		log.Warn("tx to address mismatch", "have", rlog.Address.LowerHex(), "want", routerContract, "chainID", b.ChainConfig.ChainID, "txid", swapInfo.Hash, "logIndex", swapInfo.LogIndex, "err", tokens.ErrTxWithWrongContract)
-		return tokens.ErrTxWithWrongContract
+	if err = b.checkRouterLogEmitter(swapInfo, rlog, routerContract); err != nil {
+		return err
 	}
 
 	swapoutID := swapInfo.ERC20SwapInfo.SwapoutID
@@ -443,7 +466,7 @@ func (b *Bridge) checkSwapWithPermit(swapInfo *tokens.SwapTxInfo) error {
 		return tokens.ErrMissRouterInfo
 	}
 
-	if common.IsEqualIgnoreCase(swapInfo.TxTo, routerContract) {
+	if b.isRouterAddress(swapInfo.TxTo, routerContract) {
 		tx, err := b.EvmContractBridge.GetTransactionByHash(swapInfo.Hash)
 		if err != nil {
 			return err
@@ -477,13 +500,17 @@ func (b *Bridge) checkTokenReceived(swapInfo *tokens.SwapTxInfo, receipt *types.
 	if tokenCfg == nil || tokenID == "" {
 		return tokens.ErrMissTokenConfig
 	}
-	if params.DontCheckTokenReceived(tokenID) {
+	isConservative := params.GetRebasingTokenMode(tokenID) == params.RebasingModeConservative
+	if params.DontCheckTokenReceived(tokenID) && !isConservative {
 		return nil
 	}
 	tokenAddr := common.HexToAddress(token)
 	underlyingAddr := tokenCfg.GetUnderlying()
 	if common.HexToAddress(underlyingAddr) == (common.Address{}) ||
 		tokenCfg.IsWrapperTokenVersion() {
+		if isConservative {
+			return fmt.Errorf("%w %v", tokens.ErrVerifyTxUnsafe, "can not check received amount of rebasing token")
+		}
 		return nil
 	}
 	routerContract := b.GetRouterContract(token)
@@ -504,7 +531,7 @@ func (b *Bridge) checkTokenReceived(swapInfo *tokens.SwapTxInfo, receipt *types.
 	// find in reverse order
 	for i := swapInfo.LogIndex - 1; i >= 0; i-- {
 		rlog := receipt.Logs[i]
-		if common.IsEqualIgnoreCase(rlog.Address.LowerHex(), routerContract) {
+		if b.isRouterAddress(rlog.Address.LowerHex(), routerContract) {
 			log.Info("check token received prevent reentrance", "chainID", b.ChainConfig.ChainID, "index", i, "logAddress", rlog.Address.LowerHex(), "logTopic", rlog.Topics[0].Hex(), "swapID", swapInfo.Hash)
 			break // prevent re-entrance
 		}
@@ -533,7 +560,7 @@ func (b *Bridge) checkTokenReceived(swapInfo *tokens.SwapTxInfo, receipt *types.
 				break
 			} else if toAddr == tokenAddr {
 				if common.IsEqualIgnoreCase(from, swapInfo.From) ||
-					common.IsEqualIgnoreCase(from, routerContract) {
+					b.isRouterAddress(from, routerContract) {
 					recvAmount = common.GetBigInt(*rlog.Data, 0, 32)
 				}
 				log.Info("check token received found underlying.transfer", "chainID", b.ChainConfig.ChainID, "index", i, "amount", recvAmount, "from", from, "swapID", swapInfo.Hash)
@@ -579,6 +606,7 @@ func (b *Bridge) checkTokenReceived(swapInfo *tokens.SwapTxInfo, receipt *types.
 		return fmt.Errorf("%w %v", tokens.ErrVerifyTxUnsafe, "check underlying token received failed")
 	}
 	log.Info("check token received success", "chainID", b.ChainConfig.ChainID, "isBurn", isBurn, "received", recvAmount, "swapValue", swapInfo.Value, "swapID", swapInfo.Hash)
+	b.creditReceivedAmount(swapInfo, recvAmount)
 	return nil
 }
 
@@ -1,10 +1,10 @@
 package eth
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
-	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -17,7 +17,15 @@ import (
 
 // GetTransactionStatus impl
 func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
-	txr, err := b.EvmContractBridge.GetTransactionReceipt(txHash)
+	var txr *types.RPCTxReceipt
+	var err error
+	// swap txs are user operation hashes in account abstraction mode
+	if b.IsAccountAbstraction() {
+		txr, err = b.getUserOperationReceipt(txHash)
+	}
+	if txr == nil {
+		txr, err = b.EvmContractBridge.GetTransactionReceipt(txHash)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -27,15 +35,21 @@ func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
 	txStatus.Receipt = txr
 	txStatus.BlockHeight = txr.BlockNumber.ToInt().Uint64()
 	txStatus.BlockHash = txr.BlockHash.String()
+	if !txr.IsStatusOk() {
+		txStatus.SetFailed(txr.GetFailReason())
+	}
+	if fee := txr.GetFeePaid(); fee != nil {
+		txStatus.FeePaid = fee.String()
+	}
 
 	if txStatus.BlockHeight != 0 {
-		for i := 0; i < 3; i++ {
-			confirmations, errt := b.GetBlockConfirmations(txr)
-			if errt == nil {
-				txStatus.Confirmations = confirmations
-				break
-			}
-			time.Sleep(1 * time.Second)
+		var confirmations uint64
+		errt := tokens.RetryRPC(b.ChainConfig.ChainID, func() (err error) {
+			confirmations, err = b.GetBlockConfirmations(txr)
+			return err
+		})
+		if errt == nil {
+			txStatus.SetConfirmations(confirmations, b.GetChainConfig().Confirmations)
 		}
 	}
 
@@ -44,6 +58,9 @@ func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
 
 // VerifyMsgHash verify msg hash
 func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHashes []string) error {
+	if b.IsAccountAbstraction() {
+		return b.verifyUserOperationMsgHash(rawTx, msgHashes)
+	}
 	if b.IsZKSync() {
 		return b.verifyZKSyncMsgHash(rawTx, msgHashes)
 	}
@@ -75,7 +92,7 @@ func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHashes []string) error {
 	msgHash := msgHashes[0]
 	signer := b.Signer
 	sigHash := signer.Hash(tx)
-	if sigHash.String() != msgHash {
+	if sigHash.String() != msgHash && !b.isSwapAuthorizationMsgHash(tx, msgHash) {
 		log.Trace("message hash mismatch", "want", msgHash, "have", sigHash.String())
 		return tokens.ErrMsgHashMismatch
 	}
@@ -123,6 +140,12 @@ func (b *Bridge) VerifyTransaction(txHash string, args *tokens.VerifyArgs) (*tok
 
 	switch swapType {
 	case tokens.ERC20SwapType, tokens.ERC20SwapTypeMixPool:
+		if b.IsDepositAddressEnabled() {
+			swapInfo, err := b.verifyDepositSwapTx(txHash, args)
+			if !errors.Is(err, errNotDepositSwapTx) {
+				return swapInfo, err
+			}
+		}
 		return b.VerifyERC20SwapTx(txHash, logIndex, allowUnstable)
 	case tokens.NFTSwapType:
 		return b.verifyNFTSwapTx(txHash, logIndex, allowUnstable)
@@ -130,6 +153,12 @@ func (b *Bridge) VerifyTransaction(txHash string, args *tokens.VerifyArgs) (*tok
 		return b.verifyAnyCallSwapTx(txHash, logIndex, allowUnstable)
 	case tokens.SapphireRPCType:
 		return b.verifySapphireRPC(txHash, args)
+	case tokens.DepositSweepType:
+		return b.verifyDepositSweep(txHash)
+	case tokens.ClaimRefundType:
+		return b.verifyClaimRefund(txHash)
+	case tokens.AllowanceApproveType:
+		return b.verifyAllowanceApprove(txHash)
 	default:
 		return nil, tokens.ErrSwapTypeNotSupported
 	}
@@ -142,12 +142,6 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 		return nil, errTxResultType
 	}
 
-	// Check tx status
-	if txres.Status.String() != Success_Status {
-		log.Warn("Near tx status is not success", "result", txres.Status.String())
-		return nil, tokens.ErrTxWithWrongStatus
-	}
-
 	status.Receipt = nil
 	blockHeight, blockErr := b.GetBlockNumberByHash(txres.BlockID)
 	if blockErr != nil {
@@ -156,8 +150,16 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 	}
 	status.BlockHeight = blockHeight
 
-	if latest, err := b.GetLatestBlockNumber(); err == nil && latest > blockHeight {
-		status.Confirmations = latest - blockHeight
+	// Check tx status
+	if txres.Status.String() != Success_Status {
+		log.Warn("Flow tx status is not success", "result", txres.Status.String())
+		status.SetFailed(txres.Status.String())
+	} else if txres.Error != nil {
+		status.SetFailed(txres.Error.Error())
+	}
+
+	if latest, err := b.GetLatestBlockNumber(); err == nil {
+		status.SetLatestHeight(latest, b.GetChainConfig().Confirmations)
 	}
 	return status, nil
 }
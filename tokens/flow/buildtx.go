@@ -6,7 +6,6 @@ import (
 	"io/ioutil"
 	"math/big"
 	"strings"
-	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -18,9 +17,7 @@ import (
 )
 
 var (
-	retryRPCCount           = 3
-	retryRPCInterval        = 1 * time.Second
-	defaultGasLimit  uint64 = 1_000_000
+	defaultGasLimit uint64 = 1_000_000
 )
 
 // BuildRawTransaction build raw tx
@@ -243,7 +240,7 @@ func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken
 	if toTokenCfg == nil {
 		return receiver, amount, tokens.ErrMissTokenConfig
 	}
-	amount = tokens.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo)
+	amount = args.ApplyConversionRate(args.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo))
 	return receiver, amount, err
 }
 
@@ -272,7 +269,7 @@ func (b *Bridge) GetPoolNonce(address, _height string) (uint64, error) {
 func (b *Bridge) GetSeq(args *tokens.BuildTxArgs) (nonceptr *uint64, err error) {
 	var nonce uint64
 
-	if params.IsParallelSwapEnabled() {
+	if params.IsParallelSwapEnabledOnChain(b.ChainConfig.ChainID) {
 		nonce, err = b.AllocateNonce(args)
 		return &nonce, err
 	}
@@ -282,13 +279,10 @@ func (b *Bridge) GetSeq(args *tokens.BuildTxArgs) (nonceptr *uint64, err error)
 		return &nonce, nil
 	}
 
-	for i := 0; i < retryRPCCount; i++ {
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
 		nonce, err = b.GetPoolNonce(args.From, "pending")
-		if err == nil {
-			break
-		}
-		time.Sleep(retryRPCInterval)
-	}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -9,6 +9,9 @@ import (
 
 // SendTransaction send signed tx
 func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	tx := signedTx.(*sdk.Transaction)
 	txHash, err = b.BroadcastTxCommit(tx)
 	if err != nil {
@@ -16,7 +19,7 @@ func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error
 		return "", err
 	}
 	log.Info("SendTransaction success", "hash", txHash)
-	if !params.IsParallelSwapEnabled() {
+	if !params.IsParallelSwapEnabledOnChain(b.ChainConfig.ChainID) {
 		sender := tx.Payer
 		nonce := tx.ProposalKey.SequenceNumber
 		b.SetNonce(sender.Hex(), nonce+1)
@@ -2,7 +2,6 @@ package flow
 
 import (
 	"encoding/json"
-	"errors"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -10,17 +9,41 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
-	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
 	sdk "github.com/onflow/flow-go-sdk"
 	fcrypto "github.com/onflow/flow-go-sdk/crypto"
 )
 
+const (
+	// chainFamily chain family in the mpc sign scheme registry
+	chainFamily = "flow"
+	// schemeSecp256k1Sha3 flow signs the sha3-256 digest of the envelope message
+	schemeSecp256k1Sha3 = "secp256k1+sha3_256"
+)
+
+func init() {
+	mpc.RegisterSignScheme(schemeSecp256k1Sha3, mpc.NewSecp256k1Scheme(sha3Hash))
+	mpc.RegisterChainFamily(chainFamily, schemeSecp256k1Sha3)
+}
+
+func sha3Hash(data ...[]byte) []byte {
+	hasher, _ := fcrypto.NewHasher(fcrypto.SHA3_256)
+	var message []byte
+	for _, b := range data {
+		message = append(message, b...)
+	}
+	return hasher.ComputeHash(message)
+}
+
 // MPCSignTransaction mpc sign raw tx
 func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signedTx interface{}, txHash string, err error) {
 	tx, ok := rawTx.(*sdk.Transaction)
 	if !ok {
 		return nil, "", tokens.ErrWrongRawTx
 	}
+	// cadence scripts are identified by their keccak256 hash
+	if err = tokens.CheckDestMethods(b.ChainConfig.ChainID, common.Keccak256Hash(tx.Script).Hex()); err != nil {
+		return nil, "", err
+	}
 
 	mpcParams := params.GetMPCConfig(b.UseFastMPC)
 	if mpcParams.SignWithPrivateKey {
@@ -49,24 +72,11 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 	if err != nil {
 		return nil, "", err
 	}
-	keyID, rsvs, err := mpcConfig.DoSignOneEC(mpcRealPubkey, common.ToHex(hash[:]), msgContext)
+	keyID, sig, err := mpcConfig.DoSignWithScheme(chainFamily, mpcRealPubkey, hash[:], msgContext)
 	if err != nil {
 		return nil, "", err
 	}
-
-	if len(rsvs) != 1 {
-		log.Warn("get sign status require one rsv but return many",
-			"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
-		return nil, "", errors.New("get sign status require one rsv but return many")
-	}
-
-	rsv := rsvs[0]
-	log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", rsv)
-	sig := common.FromHex(rsv)
-	if len(sig) != crypto.SignatureLength {
-		log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(sig), "want", crypto.SignatureLength)
-		return nil, "", errors.New("wrong signature length")
-	}
+	log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", common.ToHex(sig))
 
 	tx.AddEnvelopeSignature(tx.Payer, tx.ProposalKey.KeyIndex, sig[:64])
 
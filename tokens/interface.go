@@ -71,6 +71,159 @@ type NonceSetter interface {
 	RecycleSwapNonce(sender string, nonce uint64)
 }
 
+// AmountFormatter interface (format raw amount to human readable value)
+type AmountFormatter interface {
+	FormatAmount(tokenAddr string, amount *big.Int) (string, error)
+}
+
+// BatchSender interface (submit multiple signed txs in one batch)
+// the results must be in the same order as the signed txs,
+// and each member's failure is reported in its own result.
+type BatchSender interface {
+	SendTransactions(signedTxs []interface{}) []*BatchSendResult
+}
+
+// BatchSendResult result of one member of batch submission
+type BatchSendResult struct {
+	TxHash string
+	Err    error
+}
+
+// DepositAddressProvider interface (per-user deposit addresses)
+// deposits to the registered addresses are credited as swaps to the bind address,
+// and the deposited funds are swept to the mpc address periodically.
+type DepositAddressProvider interface {
+	IsDepositAddressEnabled() bool
+	RegisterDepositAddress(toChainID, bind string) (depositAddress string, err error)
+	// ScanDepositFunds find the tokens (zero address for native) of which
+	// the deposit address has funds to sweep
+	ScanDepositFunds(depositAddress string) (tokens []string, err error)
+	// GetDepositSweepArgs get build args of the tx sweeping token of deposit address,
+	// the tx is built, signed and sent by the swap job in order with the swaps.
+	// sequence distinguishes sweeps of the same deposit address
+	GetDepositSweepArgs(depositAddress, token string, sequence int) (*BuildTxArgs, error)
+}
+
+// AllowanceManager interface (allowances of the mpc to spenders of underlying tokens)
+// approving is only allowed to the monitored spenders in the allowance monitor config.
+type AllowanceManager interface {
+	GetMPCAllowance(token, spender string) (*big.Int, error)
+	// GetAllowanceApproveArgs get build args of the approve tx,
+	// the tx is built, signed and sent by the swap job in order with the swaps.
+	// sequence distinguishes approvals of the same amount
+	GetAllowanceApproveArgs(token, spender string, amount *big.Int, sequence int) (*BuildTxArgs, error)
+}
+
+// SignedTxStaleChecker interface (check signed tx against the current destination context)
+// a non nil error means the signed tx is stale and should be rebuilt rather than sent.
+type SignedTxStaleChecker interface {
+	CheckSignedTxStale(args *BuildTxArgs) error
+}
+
+// ClaimSwapper interface (two-phase swaps claimed on destination chain)
+// swapped funds are placed into a claimable state, the recipient (or a relayer)
+// finalizes with a claim call, and the funds are refunded after the claim expires.
+// claims are identified by the unique swap identifier of the swapin.
+type ClaimSwapper interface {
+	GetClaimInfo(claimID string) (*ClaimInfo, error)
+	// GetClaimRefundArgs get build args of the tx refunding the expired claim,
+	// the tx is built, signed and sent by the swap job in order with the swaps.
+	GetClaimRefundArgs(claimID string) (*BuildTxArgs, error)
+}
+
+// ClaimSwapTxResolver interface (two-phase swaps decided when building)
+// chains which place funds into a claimable state only if the direct delivery
+// is impossible report the claim id of the swap tx (empty if delivered directly).
+// the refunded value is kept by the mpc, and the swap is redelivered directly.
+type ClaimSwapTxResolver interface {
+	GetClaimIDOfSwapTx(txHash string) (claimID string, err error)
+}
+
+// TokenMigrationReporter interface (token contract address migrations)
+// reports the migrations of the chain and the old token remainder to sweep.
+type TokenMigrationReporter interface {
+	GetTokenMigrationReports() []*TokenMigrationReport
+}
+
+// TxExpiryChecker interface (txs with a last valid height)
+// the last valid height of built txs is set in `BuildTxArgs.Extra.TTL`,
+// expired txs will never be included and can be replaced with a fresh nonce.
+type TxExpiryChecker interface {
+	IsTxExpired(txHash string, lastValidHeight uint64) (bool, error)
+}
+
+// GasReestimator interface (re-estimate gas of txs failed for out of gas)
+// the gas limit and fee in `BuildTxArgs.Extra` are updated by simulating the tx
+// with the gas adjustment, the tx should be rebuilt with the same nonce later.
+type GasReestimator interface {
+	ReestimateGas(args *BuildTxArgs, adjustment float64) error
+}
+
+// ReplaceCostEstimator interface (fees of replacing swap txs)
+// fees are the max fees of txs in the smallest unit of the fee coin,
+// the max fee of the replacement is got from `BuildTxArgs.Extra` after building.
+// CapReplaceTxFee lowers the gas price in the args to cap the max fee of the rebuilt tx,
+// it returns an error if the capped tx is underpriced to replace the sent tx.
+type ReplaceCostEstimator interface {
+	GetSentTxMaxFee(txHash string) (*big.Int, error)
+	GetBuiltTxMaxFee(args *BuildTxArgs) *big.Int
+	CapReplaceTxFee(args *BuildTxArgs, sentTxHash string, maxFee *big.Int) error
+}
+
+// GasUsageRecorder interface (learn gas limits from historical receipts)
+// the gas used by stable swap txs is recorded per token, and the gas limits
+// of later swap txs are set from the learned distribution.
+// the recorded samples are returned to be persisted and loaded on restart.
+type GasUsageRecorder interface {
+	RecordGasUsed(tokenID, txHash string, receipt interface{}) *GasSamples
+	LoadGasSamples(samples *GasSamples)
+}
+
+// DeliveredAmountGetter interface (get the net amount of tokens
+// actually delivered to receiver by the swap tx)
+type DeliveredAmountGetter interface {
+	GetDeliveredAmount(txHash, receiver string, tokenAddrs ...string) (*big.Int, error)
+}
+
+// AccountHistoryAuditor interface (replay history of account for audits)
+// all txs of the account in the height range [minHeight, maxHeight] are re-derived
+// from validated chain history, it errors if the history of the range is incomplete.
+// deposits to the account are verified as swaps again.
+type AccountHistoryAuditor interface {
+	GetAccountTransfers(account string, minHeight, maxHeight uint64) ([]*AccountTransfer, error)
+}
+
+// FaucetBridge interface (testnet faucet of test tokens)
+// the faucet account must be a minter of the test tokens, faucet txs
+// call the token to mint, or call the router to swap out minted tokens.
+type FaucetBridge interface {
+	GetFaucetAddress(priKey string) (string, error)
+	GetPoolNonce(address, height string) (uint64, error)
+	BuildFaucetTx(args *FaucetTxArgs) (rawTx interface{}, err error)
+	SignTransactionWithPrivateKey(rawTx interface{}, priKey string) (signedTx interface{}, txHash string, err error)
+}
+
+// GatewayVerifier interface (check gateway endpoints against config and peers)
+// the returned endpoints report another chain or fork than the configured chain
+// and the quorum of peer endpoints, they should not be used in any rpc call.
+type GatewayVerifier interface {
+	VerifyGateways(chainID string, urls []string) (rejected map[string]error)
+}
+
+// ChainParamWatcher interface (watch on-chain params affecting bridge operation)
+// the bridge refreshes the params, pauses or adjusts building of affected tokens
+// accordingly, and returns the changes since the last watch for alerting.
+type ChainParamWatcher interface {
+	WatchChainParams() (changes []string, err error)
+}
+
+// MPCSchemeSigner interface (chains signing with the mpc sign scheme registry)
+// get the chain family and the mpc sign public key of mpc address,
+// which are used in canary signing of mpc config changes.
+type MPCSchemeSigner interface {
+	GetMPCSignKey(mpc string) (family, signPubkey string, err error)
+}
+
 type ReSwapable interface {
 	SetTxTimeout(args *BuildTxArgs, txTimeout *uint64)
 	GetCurrentThreshold() (*uint64, error)
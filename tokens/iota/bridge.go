@@ -143,8 +143,8 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 		inledger := tx.ReferencedByMilestoneIndex
 		status.BlockHeight = uint64(*inledger)
 
-		if latest, err := b.GetLatestBlockNumber(); err == nil && latest > uint64(*inledger) {
-			status.Confirmations = latest - uint64(*inledger)
+		if latest, err := b.GetLatestBlockNumber(); err == nil {
+			status.SetLatestHeight(latest, b.GetChainConfig().Confirmations)
 		}
 	}
 
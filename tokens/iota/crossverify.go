@@ -0,0 +1,22 @@
+package iota
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var _ tokens.TxCrossVerifier = &Bridge{}
+
+// CrossVerifyTx impl tokens.TxCrossVerifier
+func (b *Bridge) CrossVerifyTx(txHash string, quorum int) error {
+	msgID, err := ConvertMessageID(txHash)
+	if err != nil {
+		return err
+	}
+	return tokens.CrossVerifyTx(b.ChainConfig.ChainID, txHash, b.GetGatewayConfig().AllGatewayURLs, quorum, func(url string) (string, error) {
+		msg, err := GetTransactionByHash(url, msgID)
+		if err != nil {
+			return "", err
+		}
+		return tokens.TxFingerprint(msg)
+	})
+}
@@ -71,11 +71,12 @@ func (b *Bridge) InitRouterInfo(routerContract string, routerVersion string) (er
 
 	if mongodb.HasClient() {
 		var nextSwapNonce uint64
-		for i := 0; i < 3; i++ {
+		err = tokens.RetryRPC(chainID, func() (err error) {
 			nextSwapNonce, err = mongodb.FindNextSwapNonce(chainID, routerMPC)
-			if err == nil {
-				break
-			}
+			return err
+		})
+		if err != nil {
+			log.Warn("find next swap nonce failed", "chainID", chainID, "mpc", routerMPC, "err", err)
 		}
 		b.InitSwapNonce(b, routerMPC, nextSwapNonce)
 	}
@@ -2,12 +2,16 @@ package iota
 
 import (
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	iotago "github.com/iotaledger/iota.go/v2"
 )
 
 // SendTransaction send signed tx
 func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	message := signedTx.(*iotago.Message)
 	urls := b.GetGatewayConfig().AllGatewayURLs
 	for _, url := range urls {
@@ -1,10 +1,8 @@
 package iota
 
 import (
-	"crypto/ed25519"
 	"encoding/hex"
 	"encoding/json"
-	"errors"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -16,6 +14,13 @@ import (
 	iotago "github.com/iotaledger/iota.go/v2"
 )
 
+// chainFamily chain family in the mpc sign scheme registry
+const chainFamily = "iota"
+
+func init() {
+	mpc.RegisterChainFamily(chainFamily, mpc.SchemeEd25519)
+}
+
 // MPCSignTransaction mpc sign raw tx
 func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
 	if messageBuilder, ok := rawTx.(*MessageBuilder); !ok {
@@ -47,24 +52,11 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 			log.Info(logPrefix+"start", "txid", txid)
 
 			mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
-			keyID, rsvs, err := mpcConfig.DoSignOneED(mpcPubkey, common.ToHex(signMessage[:]), msgContext)
+			keyID, sig, err := mpcConfig.DoSignWithScheme(chainFamily, mpcPubkey, signMessage[:], msgContext)
 			if err != nil {
 				return nil, "", err
 			}
-
-			if len(rsvs) != 1 {
-				log.Warn("get sign status require one rsv but return many",
-					"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
-				return nil, "", errors.New("get sign status require one rsv but return many")
-			}
-
-			rsv := rsvs[0]
-			log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", rsv)
-			sig := common.FromHex(rsv)
-			if len(sig) != ed25519.SignatureSize {
-				log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(sig), "want", ed25519.SignatureSize)
-				return nil, "", errors.New("wrong signature length")
-			}
+			log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", common.ToHex(sig))
 
 			signature := &iotago.Ed25519Signature{}
 			copy(signature.Signature[:], sig)
@@ -3,6 +3,7 @@ package iota
 import (
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
@@ -10,6 +11,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	routingpayload "github.com/anyswap/CrossChain-Router/v3/tokens/payload"
 	iotago "github.com/iotaledger/iota.go/v2"
 )
 
@@ -162,15 +164,15 @@ func ParseIndexPayload(payload Payload) (string, string, error) {
 	if index, err := hex.DecodeString(payload.Index); err != nil || string(index) != SWAPOUT {
 		return "", "", tokens.ErrPayloadType
 	}
-	if data, err := hex.DecodeString(payload.Data); err != nil {
+	data, err := hex.DecodeString(payload.Data)
+	if err != nil {
 		return "", "", tokens.ErrPayloadType
-	} else {
-		if fields := strings.Split(string(data), ":"); len(fields) != 2 {
-			return "", "", tokens.ErrPayloadType
-		} else {
-			return fields[0], fields[1], nil
-		}
 	}
+	p, err := routingpayload.Decode(routingpayload.FormatTextV1, data)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", tokens.ErrPayloadType, err)
+	}
+	return p.Bind, p.ToChainID.String(), nil
 }
 
 func (b *Bridge) checkSwapoutInfo(swapInfo *tokens.SwapTxInfo) error {
@@ -1,6 +1,7 @@
 package near
 
 import (
+	"fmt"
 	"math/big"
 	"sync"
 
@@ -161,12 +162,6 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 		return nil, tokens.ErrTxResultType
 	}
 
-	// Check tx status
-	if txres.Status.Failure != nil || txres.Status.SuccessValue == nil {
-		log.Warn("Near tx status is not success", "result", txres.Status.Failure)
-		return nil, tokens.ErrTxWithWrongStatus
-	}
-
 	status.Receipt = nil
 	blockHeight, err := b.GetBlockNumberByHash(txres.TransactionOutcome.BlockHash)
 	if err != nil {
@@ -175,8 +170,29 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 	}
 	status.BlockHeight = blockHeight
 
-	if latest, err := b.GetLatestBlockNumber(); err == nil && latest > blockHeight {
-		status.Confirmations = latest - blockHeight
+	// Check tx status
+	if txres.Status.Failure != nil || txres.Status.SuccessValue == nil {
+		log.Warn("Near tx status is not success", "result", txres.Status.Failure)
+		status.SetFailed(fmt.Sprintf("%v", txres.Status.Failure))
+	}
+	status.FeePaid = getTokensBurnt(txres).String()
+
+	if latest, err := b.GetLatestBlockNumber(); err == nil {
+		status.SetLatestHeight(latest, b.GetChainConfig().Confirmations)
 	}
 	return status, nil
 }
+
+// getTokensBurnt sum the burnt tokens of tx outcome and receipts outcomes
+func getTokensBurnt(txres *TransactionResult) *big.Int {
+	total := new(big.Int)
+	if burnt, ok := new(big.Int).SetString(txres.TransactionOutcome.Outcome.TokensBurnt, 10); ok {
+		total.Add(total, burnt)
+	}
+	for _, receipt := range txres.ReceiptsOutcome {
+		if burnt, ok := new(big.Int).SetString(receipt.Outcome.TokensBurnt, 10); ok {
+			total.Add(total, burnt)
+		}
+	}
+	return total
+}
@@ -0,0 +1,19 @@
+package near
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var _ tokens.TxCrossVerifier = &Bridge{}
+
+// CrossVerifyTx impl tokens.TxCrossVerifier
+func (b *Bridge) CrossVerifyTx(txHash string, quorum int) error {
+	router := b.ChainConfig.RouterContract
+	return tokens.CrossVerifyTx(b.ChainConfig.ChainID, txHash, b.GatewayConfig.AllGatewayURLs, quorum, func(url string) (string, error) {
+		result, err := GetTransactionByHash(url, txHash, router)
+		if err != nil {
+			return "", err
+		}
+		return tokens.TxFingerprint(result)
+	})
+}
@@ -2,12 +2,16 @@ package near
 
 import (
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/near/borsh-go"
 )
 
 // SendTransaction send signed tx
 func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	signTx := signedTx.(*SignedTransaction)
 	buf, err := borsh.Serialize(*signTx)
 	if err != nil {
@@ -7,7 +7,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
-	"errors"
+	"fmt"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -19,12 +19,35 @@ import (
 	"github.com/near/borsh-go"
 )
 
+// chainFamily chain family in the mpc sign scheme registry
+const chainFamily = "near"
+
+func init() {
+	mpc.RegisterChainFamily(chainFamily, mpc.SchemeEd25519)
+}
+
+// GetMPCSignKey impl tokens.MPCSchemeSigner
+func (b *Bridge) GetMPCSignKey(mpc string) (family, signPubkey string, err error) {
+	mpcPubkey := router.GetMPCPublicKey(mpc)
+	if mpcPubkey == "" {
+		return "", "", tokens.ErrMissMPCPublicKey
+	}
+	nearPubkey, err := PublicKeyFromString(mpcPubkey)
+	if err != nil {
+		return "", "", err
+	}
+	return chainFamily, hex.EncodeToString(nearPubkey.Bytes()), nil
+}
+
 // MPCSignTransaction mpc sign raw tx
 func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signedTx interface{}, txHash string, err error) {
 	tx, ok := rawTx.(*RawTransaction)
 	if !ok {
 		return nil, "", tokens.ErrWrongRawTx
 	}
+	if err = tokens.CheckDestMethods(b.ChainConfig.ChainID, getDestMethods(tx)...); err != nil {
+		return nil, "", err
+	}
 
 	mpcParams := params.GetMPCConfig(b.UseFastMPC)
 	if mpcParams.SignWithPrivateKey {
@@ -56,25 +79,12 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 	log.Info(logPrefix+"start", "txid", txid)
 
 	mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
-	keyID, rsvs, err := mpcConfig.DoSignOneED(mpcSignPubkey, common.ToHex(hash[:]), msgContext)
+	keyID, sig, err := mpcConfig.DoSignWithScheme(chainFamily, mpcSignPubkey, hash[:], msgContext)
 	if err != nil {
 		log.Info(logPrefix+"failed", "keyID", keyID, "txid", txid, "err", err)
 		return nil, "", err
 	}
-
-	if len(rsvs) != 1 {
-		log.Warn("get sign status require one rsv but return many",
-			"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
-		return nil, "", errors.New("get sign status require one rsv but return many")
-	}
-
-	rsv := rsvs[0]
-	log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", rsv)
-	sig := common.FromHex(rsv)
-	if len(sig) != ed25519.SignatureSize {
-		log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(sig), "want", ed25519.SignatureSize)
-		return nil, "", errors.New("wrong signature length")
-	}
+	log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", common.ToHex(sig))
 
 	var signature Signature
 	signature.KeyType = ED25519
@@ -127,3 +137,22 @@ func signTransaction(tx *RawTransaction, privKey *ed25519.PrivateKey) (signedTx
 
 	return &stx, txHash, nil
 }
+
+// names of action kinds in the order of borsh enum
+var actionKinds = []string{"CreateAccount", "DeployContract", "FunctionCall", "Transfer", "Stake", "AddKey", "DeleteKey", "DeleteAccount"}
+
+// getDestMethods get method names of function call actions, or kind names of other actions
+func getDestMethods(tx *RawTransaction) []string {
+	methods := make([]string, 0, len(tx.Actions))
+	for _, action := range tx.Actions {
+		switch {
+		case action.Enum == 2:
+			methods = append(methods, action.FunctionCall.MethodName)
+		case int(action.Enum) < len(actionKinds):
+			methods = append(methods, actionKinds[action.Enum])
+		default:
+			methods = append(methods, fmt.Sprintf("Action%d", action.Enum))
+		}
+	}
+	return methods
+}
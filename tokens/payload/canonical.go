@@ -0,0 +1,201 @@
+package payload
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// canonical json of payloads follows JSON Canonicalization Scheme (RFC 8785)
+// restricted to integer numbers, so that it is easy to be implemented
+// identically by sdks of other languages:
+//   - no whitespace between tokens
+//   - object members are sorted by keys in utf-16 code units, duplicate keys are rejected
+//   - strings escape only '"', '\\' and control characters (\b \f \n \r \t or lowercase \u00xx),
+//     others are literal utf-8
+//   - numbers must be integers in the safe range [-(2^53-1), 2^53-1] (eg. 1.0 and 1e2 are allowed),
+//     and are written in decimal without fraction and exponent
+//
+// the hash of payload is the hex encoded sha256 of its canonical json.
+
+const maxSafeInteger = 1<<53 - 1
+
+// errors of canonical json
+var (
+	ErrWrongJSON         = errors.New("wrong json")
+	ErrJSONDuplicateKey  = errors.New("duplicate key in json object")
+	ErrJSONUnsafeNumber  = errors.New("json number is not a safe integer")
+	ErrJSONInvalidString = errors.New("json string is not valid utf-8")
+)
+
+// CanonicalJSON canonicalize json data
+func CanonicalJSON(data []byte) ([]byte, error) {
+	if !utf8.Valid(data) {
+		return nil, ErrJSONInvalidString
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var buf bytes.Buffer
+	if err := writeCanonicalValue(&buf, dec); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: trailing data", ErrWrongJSON)
+	}
+	return buf.Bytes(), nil
+}
+
+// JSONHash hex encoded sha256 hash of canonical json of data
+func JSONHash(data []byte) (string, error) {
+	canonical, err := CanonicalJSON(data)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(canonical)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func writeCanonicalValue(buf *bytes.Buffer, dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWrongJSON, err)
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			return writeCanonicalObject(buf, dec)
+		case '[':
+			return writeCanonicalArray(buf, dec)
+		default:
+			return fmt.Errorf("%w: unexpected %v", ErrWrongJSON, v)
+		}
+	case string:
+		writeCanonicalString(buf, v)
+	case json.Number:
+		return writeCanonicalNumber(buf, v)
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case nil:
+		buf.WriteString("null")
+	default:
+		return fmt.Errorf("%w: unexpected token %v", ErrWrongJSON, tok)
+	}
+	return nil
+}
+
+func writeCanonicalObject(buf *bytes.Buffer, dec *json.Decoder) error {
+	members := make(map[string][]byte)
+	keys := make([]string, 0)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrWrongJSON, err)
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("%w: object key is not string", ErrWrongJSON)
+		}
+		if _, exist := members[key]; exist {
+			return fmt.Errorf("%w: %q", ErrJSONDuplicateKey, key)
+		}
+		var value bytes.Buffer
+		if err = writeCanonicalValue(&value, dec); err != nil {
+			return err
+		}
+		members[key] = value.Bytes()
+		keys = append(keys, key)
+	}
+	if _, err := dec.Token(); err != nil { // '}'
+		return fmt.Errorf("%w: %v", ErrWrongJSON, err)
+	}
+	sort.Slice(keys, func(i, j int) bool { return lessUTF16(keys[i], keys[j]) })
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeCanonicalString(buf, key)
+		buf.WriteByte(':')
+		buf.Write(members[key])
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func writeCanonicalArray(buf *bytes.Buffer, dec *json.Decoder) error {
+	buf.WriteByte('[')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := writeCanonicalValue(buf, dec); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil { // ']'
+		return fmt.Errorf("%w: %v", ErrWrongJSON, err)
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+func writeCanonicalNumber(buf *bytes.Buffer, num json.Number) error {
+	f, err := strconv.ParseFloat(string(num), 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > maxSafeInteger {
+		return fmt.Errorf("%w: %v", ErrJSONUnsafeNumber, num)
+	}
+	buf.WriteString(strconv.FormatInt(int64(f), 10))
+	return nil
+}
+
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	const hexDigits = "0123456789abcdef"
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[c>>4])
+				buf.WriteByte(hexDigits[c&0xf])
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// lessUTF16 compare strings by utf-16 code units
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
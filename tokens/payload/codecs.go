@@ -0,0 +1,165 @@
+package payload
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const textSeparator = ':'
+
+// textCodec codec of text formats
+type textCodec struct {
+	withExtra bool
+}
+
+func (c *textCodec) SupportExtra() bool {
+	return c.withExtra
+}
+
+func (c *textCodec) Encode(p *Payload) ([]byte, error) {
+	text := p.Bind + string(textSeparator) + p.ToChainID.String()
+	if p.Extra != "" {
+		text += string(textSeparator) + p.Extra
+	}
+	return []byte(text), nil
+}
+
+func (c *textCodec) Decode(data []byte) (*Payload, error) {
+	if len(data) > MaxTextLength {
+		return nil, ErrPayloadTooLong
+	}
+	fieldsCount := 2
+	if c.withExtra {
+		fieldsCount = 3 // the extra may contain separators
+	}
+	fields := strings.SplitN(string(data), string(textSeparator), fieldsCount)
+	if len(fields) < 2 || (!c.withExtra && strings.ContainsRune(fields[1], textSeparator)) {
+		return nil, ErrWrongPayload
+	}
+	toChainID, err := parseChainID(fields[1])
+	if err != nil {
+		return nil, err
+	}
+	p := &Payload{
+		Bind:      fields[0],
+		ToChainID: toChainID,
+	}
+	if len(fields) == 3 {
+		if fields[2] == "" {
+			return nil, ErrWrongExtra
+		}
+		p.Extra = fields[2]
+	}
+	return p, nil
+}
+
+// parseChainID parse canonical decimal chain ID (no sign, no leading zeros)
+func parseChainID(s string) (*big.Int, error) {
+	if s == "" || len(s) > MaxChainIDDigits || s[0] == '0' {
+		return nil, ErrWrongToChainID
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return nil, ErrWrongToChainID
+		}
+	}
+	chainID, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, ErrWrongToChainID
+	}
+	return chainID, nil
+}
+
+// binaryCodec codec of binary format, which fits in 32 bytes hash memo
+type binaryCodec struct{}
+
+const binaryPayloadLength = 32
+
+func (c *binaryCodec) SupportExtra() bool {
+	return false
+}
+
+func (c *binaryCodec) Encode(p *Payload) ([]byte, error) {
+	bind := strings.TrimPrefix(strings.TrimPrefix(p.Bind, "0x"), "0X")
+	bindBytes, err := hex.DecodeString(bind)
+	if err != nil || len(bindBytes) == 0 {
+		return nil, ErrWrongBind
+	}
+	chainIDBytes := p.ToChainID.Bytes()
+	if 1+len(bindBytes)+len(chainIDBytes) > binaryPayloadLength {
+		return nil, ErrPayloadTooLong
+	}
+	data := make([]byte, binaryPayloadLength)
+	data[0] = byte(len(bindBytes))
+	copy(data[1:], bindBytes)
+	copy(data[binaryPayloadLength-len(chainIDBytes):], chainIDBytes)
+	return data, nil
+}
+
+func (c *binaryCodec) Decode(data []byte) (*Payload, error) {
+	if len(data) > binaryPayloadLength {
+		return nil, ErrPayloadTooLong
+	}
+	if len(data) == 0 {
+		return nil, ErrWrongPayload
+	}
+	bindEnd := 1 + int(data[0])
+	if data[0] == 0 || len(data) < bindEnd+1 {
+		return nil, ErrWrongPayload
+	}
+	return &Payload{
+		Bind:      "0x" + hex.EncodeToString(data[1:bindEnd]),
+		ToChainID: new(big.Int).SetBytes(data[bindEnd:]),
+	}, nil
+}
+
+// jsonCodec codec of json format, encoded in canonical json.
+// unknown members are ignored on decoding, but covered by the payload hash.
+type jsonCodec struct{}
+
+type jsonPayload struct {
+	Bind      string `json:"bind"`
+	ToChainID string `json:"toChainID"`
+	Extra     string `json:"extra,omitempty"`
+}
+
+func (c *jsonCodec) SupportExtra() bool {
+	return true
+}
+
+func (c *jsonCodec) Encode(p *Payload) ([]byte, error) {
+	data, err := json.Marshal(&jsonPayload{
+		Bind:      p.Bind,
+		ToChainID: p.ToChainID.String(),
+		Extra:     p.Extra,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return CanonicalJSON(data)
+}
+
+func (c *jsonCodec) Decode(data []byte) (*Payload, error) {
+	if len(data) > MaxJSONLength {
+		return nil, ErrPayloadTooLong
+	}
+	if _, err := CanonicalJSON(data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWrongPayload, err)
+	}
+	var jp jsonPayload
+	if err := json.Unmarshal(data, &jp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWrongPayload, err)
+	}
+	toChainID, err := parseChainID(jp.ToChainID)
+	if err != nil {
+		return nil, err
+	}
+	return &Payload{
+		Bind:      jp.Bind,
+		ToChainID: toChainID,
+		Extra:     jp.Extra,
+	}, nil
+}
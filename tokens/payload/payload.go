@@ -0,0 +1,167 @@
+// Package payload provides the registry of routing payload formats,
+// which carry the bind address and destination chain ID (and optional extras)
+// in the memo or payload of swap transactions on non-evm chains.
+package payload
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// payload formats
+const (
+	// FormatTextV1 is text of `bind:toChainID`
+	FormatTextV1 = "text/v1"
+	// FormatTextV2 is text of `bind:toChainID[:extra]`
+	FormatTextV2 = "text/v2"
+	// FormatBinaryV1 is 32 bytes of `len(bind) | bind | zero padding | toChainID`,
+	// the bind address is hex bytes and toChainID is right aligned big endian bytes.
+	FormatBinaryV1 = "binary/v1"
+	// FormatJSONV1 is json object of `{"bind":bind,"toChainID":toChainID[,"extra":extra]}`,
+	// toChainID is decimal string, and the payload hash is sha256 of its canonical json.
+	FormatJSONV1 = "json/v1"
+)
+
+// payload limits
+const (
+	MaxBindLength    = 128
+	MaxChainIDDigits = 78 // uint256
+	MaxExtraLength   = 128
+	MaxTextLength    = MaxBindLength + MaxChainIDDigits + MaxExtraLength + 2
+	MaxJSONLength    = 512
+)
+
+// errors
+var (
+	ErrUnknownFormat    = errors.New("unknown payload format")
+	ErrPayloadTooLong   = errors.New("payload is too long")
+	ErrWrongPayload     = errors.New("wrong payload")
+	ErrWrongBind        = errors.New("wrong bind address in payload")
+	ErrWrongToChainID   = errors.New("wrong toChainID in payload")
+	ErrWrongExtra       = errors.New("wrong extra in payload")
+	ErrExtraUnsupported = errors.New("payload format does not support extra")
+)
+
+// Payload routing payload of swap
+type Payload struct {
+	Bind      string
+	ToChainID *big.Int
+	Extra     string // optional
+}
+
+// Codec encoder and decoder of payload format.
+// the payload is validated by the registry before encoding and after decoding.
+type Codec interface {
+	Encode(p *Payload) ([]byte, error)
+	Decode(data []byte) (*Payload, error)
+	// SupportExtra is extra supported
+	SupportExtra() bool
+}
+
+var (
+	codecs     = make(map[string]Codec)
+	codecsLock sync.RWMutex
+)
+
+func init() {
+	Register(FormatTextV1, &textCodec{})
+	Register(FormatTextV2, &textCodec{withExtra: true})
+	Register(FormatBinaryV1, &binaryCodec{})
+	Register(FormatJSONV1, &jsonCodec{})
+}
+
+// Register register codec of format, it panics if the format is registered.
+func Register(format string, codec Codec) {
+	codecsLock.Lock()
+	defer codecsLock.Unlock()
+	if _, exist := codecs[format]; exist {
+		panic(fmt.Sprintf("payload format %v is already registered", format))
+	}
+	codecs[format] = codec
+}
+
+// Formats get all registered formats in order
+func Formats() []string {
+	codecsLock.RLock()
+	defer codecsLock.RUnlock()
+	formats := make([]string, 0, len(codecs))
+	for format := range codecs {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+func getCodec(format string) (Codec, error) {
+	codecsLock.RLock()
+	defer codecsLock.RUnlock()
+	codec, exist := codecs[format]
+	if !exist {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownFormat, format)
+	}
+	return codec, nil
+}
+
+// Encode encode payload in format
+func Encode(format string, p *Payload) ([]byte, error) {
+	codec, err := getCodec(format)
+	if err != nil {
+		return nil, err
+	}
+	if err = validate(codec, p); err != nil {
+		return nil, err
+	}
+	return codec.Encode(p)
+}
+
+// Decode decode payload in format
+func Decode(format string, data []byte) (*Payload, error) {
+	codec, err := getCodec(format)
+	if err != nil {
+		return nil, err
+	}
+	p, err := codec.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if err = validate(codec, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func validate(codec Codec, p *Payload) error {
+	if p == nil {
+		return ErrWrongPayload
+	}
+	if p.Bind == "" || len(p.Bind) > MaxBindLength {
+		return ErrWrongBind
+	}
+	for i := 0; i < len(p.Bind); i++ {
+		// printable ascii without space and separator
+		if c := p.Bind[i]; c <= ' ' || c > '~' || c == textSeparator {
+			return ErrWrongBind
+		}
+	}
+	if p.ToChainID == nil || p.ToChainID.Sign() <= 0 || len(p.ToChainID.String()) > MaxChainIDDigits {
+		return ErrWrongToChainID
+	}
+	if p.Extra == "" {
+		return nil
+	}
+	if !codec.SupportExtra() {
+		return ErrExtraUnsupported
+	}
+	if len(p.Extra) > MaxExtraLength {
+		return ErrWrongExtra
+	}
+	for i := 0; i < len(p.Extra); i++ {
+		if c := p.Extra[i]; c < ' ' || c > '~' {
+			return ErrWrongExtra
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package payload
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTextPayload(t *testing.T) {
+	cases := []struct {
+		format string
+		data   string
+		want   *Payload
+		err    error
+	}{
+		{FormatTextV1, "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe:56", &Payload{Bind: "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe", ToChainID: big.NewInt(56)}, nil},
+		{FormatTextV1, "0x1F05D743517471a4C4d2273F07B78Bfbd758e3c5:1000004346947", &Payload{Bind: "0x1F05D743517471a4C4d2273F07B78Bfbd758e3c5", ToChainID: big.NewInt(1000004346947)}, nil},
+		{FormatTextV1, "bind:56:extra", nil, ErrWrongPayload},
+		{FormatTextV1, "bind", nil, ErrWrongPayload},
+		{FormatTextV1, ":56", nil, ErrWrongBind},
+		{FormatTextV1, "bi nd:56", nil, ErrWrongBind},
+		{FormatTextV1, "bind:056", nil, ErrWrongToChainID},
+		{FormatTextV1, "bind:0x38", nil, ErrWrongToChainID},
+		{FormatTextV1, "bind:-56", nil, ErrWrongToChainID},
+		{FormatTextV1, "bind:", nil, ErrWrongToChainID},
+		{FormatTextV1, strings.Repeat("a", MaxBindLength+1) + ":56", nil, ErrWrongBind},
+		{FormatTextV1, strings.Repeat("a", MaxTextLength+1), nil, ErrPayloadTooLong},
+		{FormatTextV2, "bind:56", &Payload{Bind: "bind", ToChainID: big.NewInt(56)}, nil},
+		{FormatTextV2, "bind:56:ref:123 x", &Payload{Bind: "bind", ToChainID: big.NewInt(56), Extra: "ref:123 x"}, nil},
+		{FormatTextV2, "bind:56:", nil, ErrWrongExtra},
+		{FormatTextV2, "bind:56:a\nb", nil, ErrWrongExtra},
+		{"text/v0", "bind:56", nil, ErrUnknownFormat},
+	}
+	for _, c := range cases {
+		p, err := Decode(c.format, []byte(c.data))
+		if !errors.Is(err, c.err) {
+			t.Errorf("decode %v %q got error %v, want %v", c.format, c.data, err, c.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if p.Bind != c.want.Bind || p.ToChainID.Cmp(c.want.ToChainID) != 0 || p.Extra != c.want.Extra {
+			t.Errorf("decode %v %q got %+v, want %+v", c.format, c.data, p, c.want)
+		}
+		encoded, err := Encode(c.format, p)
+		if err != nil || string(encoded) != c.data {
+			t.Errorf("encode %v %+v got %q (%v), want %q", c.format, p, encoded, err, c.data)
+		}
+	}
+
+	if _, err := Encode(FormatTextV1, &Payload{Bind: "bind", ToChainID: big.NewInt(56), Extra: "x"}); !errors.Is(err, ErrExtraUnsupported) {
+		t.Errorf("encode extra in %v got error %v, want %v", FormatTextV1, err, ErrExtraUnsupported)
+	}
+}
+
+func TestBinaryPayload(t *testing.T) {
+	cases := []struct {
+		data string
+		want *Payload
+		err  error
+	}{
+		{"14c5107334a3ae117e3dad3570b419618c905aa5ec0000000000000000001691", &Payload{Bind: "0xc5107334a3ae117e3dad3570b419618c905aa5ec", ToChainID: big.NewInt(5777)}, nil},
+		{"141f05d743517471a4c4d2273f07b78bfbd758e3c50000000000000000000005", &Payload{Bind: "0x1f05d743517471a4c4d2273f07b78bfbd758e3c5", ToChainID: big.NewInt(5)}, nil},
+		{"141f05d743517471a4c4d2273f07b78bfbd758e3c50000000000000000000000", nil, ErrWrongToChainID},
+		{"1f1f05d743517471a4c4d2273f07b78bfbd758e3c50000000000000000000005", nil, ErrWrongPayload},
+		{"00", nil, ErrWrongPayload},
+		{"", nil, ErrWrongPayload},
+	}
+	for _, c := range cases {
+		data, _ := hex.DecodeString(c.data)
+		p, err := Decode(FormatBinaryV1, data)
+		if !errors.Is(err, c.err) {
+			t.Errorf("decode %v got error %v, want %v", c.data, err, c.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if p.Bind != c.want.Bind || p.ToChainID.Cmp(c.want.ToChainID) != 0 {
+			t.Errorf("decode %v got %+v, want %+v", c.data, p, c.want)
+		}
+		encoded, err := Encode(FormatBinaryV1, p)
+		if err != nil || hex.EncodeToString(encoded) != c.data {
+			t.Errorf("encode %+v got %x (%v), want %v", p, encoded, err, c.data)
+		}
+	}
+
+	long := &Payload{Bind: "0x" + strings.Repeat("ab", 30), ToChainID: big.NewInt(5777)}
+	if _, err := Encode(FormatBinaryV1, long); !errors.Is(err, ErrPayloadTooLong) {
+		t.Errorf("encode long bind got error %v, want %v", err, ErrPayloadTooLong)
+	}
+}
+
+func TestJSONPayload(t *testing.T) {
+	cases := []struct {
+		data string
+		want *Payload
+		err  error
+	}{
+		{`{"bind":"0x1F05D743517471a4C4d2273F07B78Bfbd758e3c5","toChainID":"56"}`, &Payload{Bind: "0x1F05D743517471a4C4d2273F07B78Bfbd758e3c5", ToChainID: big.NewInt(56)}, nil},
+		{`{"bind":"osmo1abc","extra":"ref:123","toChainID":"1000004346947"}`, &Payload{Bind: "osmo1abc", ToChainID: big.NewInt(1000004346947), Extra: "ref:123"}, nil},
+		{`{"toChainID":"56","bind":"bind"}`, &Payload{Bind: "bind", ToChainID: big.NewInt(56)}, nil},
+		{`{"bind":"bind","toChainID":56}`, nil, ErrWrongPayload},
+		{`{"bind":"bind","toChainID":"056"}`, nil, ErrWrongToChainID},
+		{`{"bind":"bind","bind":"other","toChainID":"56"}`, nil, ErrWrongPayload},
+		{`{"bind":"bi nd","toChainID":"56"}`, nil, ErrWrongBind},
+		{`{"bind":"bind","toChainID":"56"`, nil, ErrWrongPayload},
+		{`{"bind":"` + strings.Repeat("a", MaxJSONLength) + `","toChainID":"56"}`, nil, ErrPayloadTooLong},
+	}
+	for _, c := range cases {
+		p, err := Decode(FormatJSONV1, []byte(c.data))
+		if !errors.Is(err, c.err) {
+			t.Errorf("decode %q got error %v, want %v", c.data, err, c.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if p.Bind != c.want.Bind || p.ToChainID.Cmp(c.want.ToChainID) != 0 || p.Extra != c.want.Extra {
+			t.Errorf("decode %q got %+v, want %+v", c.data, p, c.want)
+		}
+		encoded, err := Encode(FormatJSONV1, p)
+		canonical, _ := CanonicalJSON([]byte(c.data))
+		if err != nil || string(encoded) != string(canonical) {
+			t.Errorf("encode %+v got %q (%v), want %q", p, encoded, err, canonical)
+		}
+	}
+}
+
+// canonical json test vectors shared with sdks of other languages
+var canonicalJSONVectorsFile = filepath.Join("testdata", "canonical_json.json")
+
+func TestCanonicalJSON(t *testing.T) {
+	data, err := os.ReadFile(canonicalJSONVectorsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors []struct {
+		Name      string `json:"name"`
+		Input     string `json:"input"`
+		Canonical string `json:"canonical"`
+		SHA256    string `json:"sha256"`
+		Error     string `json:"error"`
+	}
+	if err = json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range vectors {
+		canonical, err := CanonicalJSON([]byte(v.Input))
+		if v.Error != "" {
+			if err == nil || !strings.Contains(err.Error(), v.Error) {
+				t.Errorf("%v: got error %v, want %q", v.Name, err, v.Error)
+			}
+			continue
+		}
+		if err != nil || string(canonical) != v.Canonical {
+			t.Errorf("%v: got %q (%v), want %q", v.Name, canonical, err, v.Canonical)
+			continue
+		}
+		if hash, _ := JSONHash([]byte(v.Input)); hash != v.SHA256 {
+			t.Errorf("%v: got hash %v, want %v", v.Name, hash, v.SHA256)
+		}
+		if again, _ := CanonicalJSON(canonical); string(again) != v.Canonical {
+			t.Errorf("%v: canonical json is not idempotent, got %q", v.Name, again)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	formats := Formats()
+	want := []string{FormatBinaryV1, FormatJSONV1, FormatTextV1, FormatTextV2}
+	if strings.Join(formats, ",") != strings.Join(want, ",") {
+		t.Errorf("formats got %v, want %v", formats, want)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("register duplicate format should panic")
+		}
+	}()
+	Register(FormatTextV1, &textCodec{})
+}
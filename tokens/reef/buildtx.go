@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"math/big"
 	"strconv"
-	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -13,11 +12,6 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 )
 
-var (
-	retryRPCCount    = 3
-	retryRPCInterval = 1 * time.Second
-)
-
 // BuildRawTransaction build raw tx
 func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
 	if !params.IsTestMode && args.ToChainID.String() != b.ChainConfig.ChainID {
@@ -201,13 +195,10 @@ func (b *Bridge) getMinReserveFee() *big.Int {
 
 func (b *Bridge) checkCoinBalance(reefAddr string, needValue *big.Int) (err error) {
 	var balance *big.Int
-	for i := 0; i < retryRPCCount; i++ {
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
 		balance, err = b.GetBalance(reefAddr)
-		if err == nil {
-			break
-		}
-		time.Sleep(retryRPCInterval)
-	}
+		return err
+	})
 	if err == nil && balance.Cmp(needValue) < 0 {
 		return fmt.Errorf("not enough coin balance. %v < %v", balance, needValue)
 	}
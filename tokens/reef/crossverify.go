@@ -0,0 +1,36 @@
+package reef
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var _ tokens.TxCrossVerifier = &Bridge{}
+
+// CrossVerifyTx impl tokens.TxCrossVerifier
+// overrides the eth bridge as reef txs are queried through the websockets.
+func (b *Bridge) CrossVerifyTx(txHash string, quorum int) error {
+	urls := make([]string, 0, len(b.WS))
+	wsMap := make(map[string]*WebSocket, len(b.WS))
+	for _, ws := range b.WS {
+		if ws.IsClose {
+			continue
+		}
+		urls = append(urls, ws.endpoint)
+		wsMap[ws.endpoint] = ws
+	}
+	return tokens.CrossVerifyTx(b.ChainConfig.ChainID, txHash, urls, quorum, func(url string) (string, error) {
+		ws := wsMap[url]
+		extrinsic, err := ws.QueryTx(txHash)
+		if err != nil {
+			return "", err
+		}
+		if extrinsic == nil || extrinsic.ID == nil {
+			return "", tokens.ErrTxNotFound
+		}
+		logs, err := ws.QueryEventLogs(*extrinsic.ID)
+		if err != nil {
+			return "", err
+		}
+		return tokens.TxFingerprint([]interface{}{extrinsic, logs})
+	})
+}
@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"log"
 	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
 )
 
 func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	tx, ok := signedTx.(*ReefTransaction)
 	if !ok {
 		log.Printf("signed tx is %+v", signedTx)
@@ -1,6 +1,7 @@
 package reef
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -30,9 +31,23 @@ func (b *Bridge) verifyTransactionReceiver(rawTx interface{}, tokenID string) (*
 	if !strings.EqualFold(*tx.To, checkReceiver) {
 		return nil, fmt.Errorf("[sign] tx receiver mismatch. have %v want %v", tx.To, checkReceiver)
 	}
+	var input []byte
+	if tx.Data != nil {
+		input = *tx.Data
+	}
+	if err = tokens.CheckDestMethods(b.ChainConfig.ChainID, tokens.GetMethodSelector(input)); err != nil {
+		return nil, fmt.Errorf("[sign] %w", err)
+	}
 	return tx, nil
 }
 
+// chainFamily chain family in the mpc sign scheme registry
+const chainFamily = "reef"
+
+func init() {
+	mpc.RegisterChainFamily(chainFamily, mpc.SchemeSr25519)
+}
+
 // MPCSignTransaction mpc sign raw tx
 func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
 	tx, err := b.verifyTransactionReceiver(rawTx, args.GetTokenID())
@@ -83,26 +98,15 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 	logPrefix := b.ChainConfig.BlockChain + " MPCSignTransaction "
 	log.Info(logPrefix+"start", "txid", txid, "msghash", msgHash)
 	mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
-	keyID, rsvs, err := mpcConfig.DoSignOne(MPC_PUBLICKEY_TYPE, mpcPubkey, msgHash, msgContext)
+	keyID, signature, err := mpcConfig.DoSignHashWithScheme(chainFamily, mpcPubkey, msgHash, msgContext)
 	if err != nil {
 		log.Info(logPrefix+"failed", "keyID", keyID, "txid", txid, "err", err)
 		return nil, "", err
 	}
 	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "msghash", msgHash)
 
-	if len(rsvs) != 1 {
-		log.Warn("get sign status require one rsv but return many",
-			"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
-		return nil, "", errors.New("get sign status require one rsv but return many")
-	}
-
-	rsv := rsvs[0]
+	rsv := hex.EncodeToString(signature)
 	log.Info(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", rsv)
-	signature := common.FromHex(rsv)
-	if len(signature) != crypto.RecoveryIDOffset {
-		log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(signature), "want", crypto.RecoveryIDOffset)
-		return nil, "", errors.New("wrong signature length")
-	}
 
 	tx.Signature = &rsv
 
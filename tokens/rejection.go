@@ -0,0 +1,82 @@
+package tokens
+
+import (
+	"errors"
+)
+
+// reason codes of rejected swaps
+const (
+	RejectWrongValue    = "WRONG_VALUE"    // value out of allowed range (eg. below minimum)
+	RejectWrongToken    = "WRONG_TOKEN"    // token not supported or misconfigured
+	RejectWrongBind     = "WRONG_BIND"     // bind (receiver) address is invalid
+	RejectWrongChain    = "WRONG_CHAIN"    // from or to chain is not supported
+	RejectWrongContract = "WRONG_CONTRACT" // tx not calling the router contract
+	RejectNoSwapLog     = "NO_SWAP_LOG"    // no swap log found at log index
+	RejectTxFailed      = "TX_FAILED"      // tx is reverted or with wrong status
+	RejectRouteDisabled = "ROUTE_DISABLED" // route is disabled or paused
+	RejectBlacklisted   = "BLACKLISTED"    // swap is in black list
+	RejectUnsafe        = "UNSAFE"         // tx maybe unsafe
+	RejectOther         = "OTHER"
+)
+
+// IsTransientVerifyError is verify error which may disappear on retry,
+// swaps with these errors are deferred rather than rejected.
+func IsTransientVerifyError(err error) bool {
+	return errors.Is(err, ErrTxNotStable) ||
+		errors.Is(err, ErrTxNotFound) ||
+		errors.Is(err, ErrReceiptDivergence) ||
+		errors.Is(err, ErrLightClientVerify) ||
+		errors.Is(err, ErrTxPruned) ||
+		IsRPCQueryOrNotFoundError(err)
+}
+
+// GetRejectReason get reason code of verify error
+//
+//nolint:gocyclo // allow big switch
+func GetRejectReason(err error) string {
+	switch {
+	case errors.Is(err, ErrTxWithWrongValue),
+		errors.Is(err, ErrTxWithZeroValue),
+		errors.Is(err, ErrSwapValueTooLess):
+		return RejectWrongValue
+	case errors.Is(err, ErrMissTokenConfig),
+		errors.Is(err, ErrNoUnderlyingToken),
+		errors.Is(err, ErrEmptyTokenID),
+		errors.Is(err, ErrTxWithWrongPath):
+		return RejectWrongToken
+	case errors.Is(err, ErrWrongBindAddress),
+		errors.Is(err, ErrTxWithWrongMemo),
+		errors.Is(err, ErrTxWithWrongReceiver):
+		return RejectWrongBind
+	case errors.Is(err, ErrNoBridgeForChainID),
+		errors.Is(err, ErrFromChainIDMismatch),
+		errors.Is(err, ErrToChainIDMismatch),
+		errors.Is(err, ErrSameFromAndToChainID):
+		return RejectWrongChain
+	case errors.Is(err, ErrTxWithWrongContract),
+		errors.Is(err, ErrUnsupportedFuncHash):
+		return RejectWrongContract
+	case errors.Is(err, ErrLogIndexOutOfRange),
+		errors.Is(err, ErrSwapoutLogNotFound),
+		errors.Is(err, ErrTxWithWrongTopics),
+		errors.Is(err, ErrTxWithRemovedLog),
+		errors.Is(err, ErrDepositNotFound):
+		return RejectNoSwapLog
+	case errors.Is(err, ErrTxWithWrongReceipt),
+		errors.Is(err, ErrTxWithWrongStatus),
+		errors.Is(err, ErrTxIsNotValidated),
+		errors.Is(err, ErrTxWithWrongMsgType):
+		return RejectTxFailed
+	case errors.Is(err, ErrTokenRouteDisabled),
+		errors.Is(err, ErrSwapoutForbidden),
+		errors.Is(err, ErrPauseSwapInto),
+		errors.Is(err, ErrTxBeforeInitialHeight):
+		return RejectRouteDisabled
+	case errors.Is(err, ErrSwapInBlacklist):
+		return RejectBlacklisted
+	case errors.Is(err, ErrVerifyTxUnsafe):
+		return RejectUnsafe
+	default:
+		return RejectOther
+	}
+}
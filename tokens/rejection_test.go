@@ -0,0 +1,45 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestGetRejectReason(t *testing.T) {
+	tests := []struct {
+		err    error
+		reason string
+	}{
+		{ErrTxWithWrongValue, RejectWrongValue},
+		{fmt.Errorf("%w: below minimum", ErrTxWithWrongValue), RejectWrongValue},
+		{ErrMissTokenConfig, RejectWrongToken},
+		{ErrWrongBindAddress, RejectWrongBind},
+		{ErrSameFromAndToChainID, RejectWrongChain},
+		{ErrTxWithWrongContract, RejectWrongContract},
+		{ErrLogIndexOutOfRange, RejectNoSwapLog},
+		{ErrTxWithWrongStatus, RejectTxFailed},
+		{ErrTokenRouteDisabled, RejectRouteDisabled},
+		{ErrSwapInBlacklist, RejectBlacklisted},
+		{ErrVerifyTxUnsafe, RejectUnsafe},
+		{errors.New("unknown verify error"), RejectOther},
+	}
+	for i, test := range tests {
+		if reason := GetRejectReason(test.err); reason != test.reason {
+			t.Errorf("test %v: reject reason of %v got %v, want %v", i, test.err, reason, test.reason)
+		}
+	}
+}
+
+func TestIsTransientVerifyError(t *testing.T) {
+	for _, err := range []error{ErrTxNotStable, ErrTxNotFound, ErrTxPruned, fmt.Errorf("%w: timeout", ErrRPCQueryError)} {
+		if !IsTransientVerifyError(err) {
+			t.Errorf("verify error %v should be transient", err)
+		}
+	}
+	for _, err := range []error{ErrTxWithWrongValue, ErrSwapInBlacklist, errors.New("unknown verify error")} {
+		if IsTransientVerifyError(err) {
+			t.Errorf("verify error %v should not be transient", err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package tokens
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tools/retry"
+)
+
+// default retry policy of rpc calls (same as the former ad-hoc retry loops)
+const (
+	defaultRetryAttempts = 3
+	defaultRetryInterval = 1 * time.Second
+)
+
+var retryBudgets sync.Map // key is chainID, value is *retry.Budget
+
+// GetRetryPolicy get retry policy of rpc calls of chain
+func GetRetryPolicy(chainID string) *retry.Policy {
+	policy := &retry.Policy{
+		MaxAttempts:     defaultRetryAttempts,
+		InitialInterval: defaultRetryInterval,
+	}
+	cfg := params.GetRetryPolicyConfig(chainID)
+	if cfg == nil {
+		return policy
+	}
+	if cfg.MaxAttempts > 0 {
+		policy.MaxAttempts = cfg.MaxAttempts
+	}
+	if cfg.InitialInterval > 0 {
+		policy.InitialInterval = time.Duration(cfg.InitialInterval) * time.Millisecond
+	}
+	policy.MaxInterval = time.Duration(cfg.MaxInterval) * time.Millisecond
+	policy.Multiplier = cfg.Multiplier
+	policy.Jitter = cfg.Jitter
+	policy.MaxElapsedTime = time.Duration(cfg.MaxElapsedTime) * time.Millisecond
+	if cfg.BudgetPerMinute > 0 {
+		policy.Budget = getRetryBudget(chainID, cfg.BudgetPerMinute)
+	}
+	return policy
+}
+
+// getRetryBudget get budget shared by rpc calls of chain,
+// the budget is recreated if its config is changed by reloading.
+func getRetryBudget(chainID string, budgetPerMinute int) *retry.Budget {
+	if v, exist := retryBudgets.Load(chainID); exist {
+		if budget := v.(*retry.Budget); budget.Max() == budgetPerMinute {
+			return budget
+		}
+	}
+	budget := retry.NewBudget(budgetPerMinute, time.Minute)
+	retryBudgets.Store(chainID, budget)
+	return budget
+}
+
+// RetryRPC call fn with the retry policy of chain
+func RetryRPC(chainID string, fn func() error) error {
+	return retry.Do(GetRetryPolicy(chainID), fn)
+}
@@ -0,0 +1,26 @@
+package ripple
+
+import (
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	// ensure Bridge impl tokens.AmountFormatter
+	_ tokens.AmountFormatter = &Bridge{}
+)
+
+// FormatAmount format raw amount (drops for XRP) to human readable value,
+// eg. `1.5/XRP` or `100.25/USD/rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B`
+func (b *Bridge) FormatAmount(tokenAddr string, amount *big.Int) (string, error) {
+	token := b.GetTokenConfig(tokenAddr)
+	if token == nil {
+		return "", tokens.ErrMissTokenConfig
+	}
+	value, err := getPaymentAmount(amount, token)
+	if err != nil {
+		return "", err
+	}
+	return value.String(), nil
+}
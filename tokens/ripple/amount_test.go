@@ -0,0 +1,41 @@
+package ripple
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestFormatAmount(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: "1000005788240"})
+	b.SetTokenConfig("XRP", &tokens.TokenConfig{TokenID: "XRP", Decimals: 6, ContractAddress: "XRP"})
+	usdAddr := "USD/" + testCheckIssuer
+	b.SetTokenConfig(usdAddr, &tokens.TokenConfig{TokenID: "USD", Decimals: 6, ContractAddress: usdAddr})
+
+	tests := []struct {
+		tokenAddr string
+		amount    *big.Int
+		want      string
+	}{
+		{"XRP", big.NewInt(1500000), "1.5/XRP"},
+		{"XRP", big.NewInt(1), "0.000001/XRP"},
+		{usdAddr, big.NewInt(100250000), "100.25/USD/" + testCheckIssuer},
+	}
+	for i, test := range tests {
+		got, err := b.FormatAmount(test.tokenAddr, test.amount)
+		if err != nil || got != test.want {
+			t.Errorf("test %v: format amount got (%v, %v), want %v", i, got, err, test.want)
+		}
+	}
+
+	if _, err := b.FormatAmount("EUR/"+testCheckIssuer, big.NewInt(1)); !errors.Is(err, tokens.ErrMissTokenConfig) {
+		t.Errorf("format amount of unknown token got err %v, want %v", err, tokens.ErrMissTokenConfig)
+	}
+	overflow := new(big.Int).Lsh(big.NewInt(1), 64)
+	if _, err := b.FormatAmount("XRP", overflow); err == nil {
+		t.Errorf("format overflowed amount should fail")
+	}
+}
@@ -0,0 +1,136 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+var (
+	// ensure Bridge impl tokens.AccountHistoryAuditor
+	_ tokens.AccountHistoryAuditor = &Bridge{}
+
+	errLedgerRangeNotCovered = errors.New("ledger range is not covered by validated history")
+)
+
+const auditTxsLimit = 200
+
+type accountTxsResult struct {
+	LedgerIndexMin uint64                `json:"ledger_index_min"`
+	LedgerIndexMax uint64                `json:"ledger_index_max"`
+	Transactions   data.TransactionSlice `json:"transactions"`
+	Marker         interface{}           `json:"marker"`
+	Error          string                `json:"error"`
+}
+
+// GetAccountTransfers impl tokens.AccountHistoryAuditor
+// replay the validated ledger range with account_tx pagination in ledger order.
+func (b *Bridge) GetAccountTransfers(account string, minHeight, maxHeight uint64) ([]*tokens.AccountTransfer, error) {
+	if minHeight == 0 || maxHeight < minHeight {
+		return nil, fmt.Errorf("wrong ledger range [%v, %v]", minHeight, maxHeight)
+	}
+	rpcParams := map[string]interface{}{
+		"account":          account,
+		"ledger_index_min": minHeight,
+		"ledger_index_max": maxHeight,
+		"forward":          true,
+		"limit":            auditTxsLimit,
+	}
+	transfers := make([]*tokens.AccountTransfer, 0, auditTxsLimit)
+	for {
+		txsRes, err := b.getAccountTxs(rpcParams)
+		if err != nil {
+			return nil, err
+		}
+		// the node clamps the range to the validated ledgers it has
+		if txsRes.LedgerIndexMin > minHeight || txsRes.LedgerIndexMax < maxHeight {
+			return nil, fmt.Errorf("%w: want [%v, %v], have [%v, %v]", errLedgerRangeNotCovered,
+				minHeight, maxHeight, txsRes.LedgerIndexMin, txsRes.LedgerIndexMax)
+		}
+		for _, txmeta := range txsRes.Transactions {
+			transfers = append(transfers, b.deriveAccountTransfer(account, txmeta))
+		}
+		if txsRes.Marker == nil {
+			return transfers, nil
+		}
+		rpcParams["marker"] = txsRes.Marker
+	}
+}
+
+func (b *Bridge) getAccountTxs(rpcParams map[string]interface{}) (txsRes *accountTxsResult, err error) {
+	urls := b.getMethodURLs("account_tx")
+	for i := 0; i < rpcRetryTimes; i++ {
+		for _, url := range urls {
+			var res *accountTxsResult
+			err = client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "account_tx", rpcParams)
+			if err == nil && res != nil && res.Error == "" {
+				return res, nil
+			}
+			if err == nil && res != nil {
+				err = fmt.Errorf("get account txs failed, %v", res.Error)
+			}
+		}
+		time.Sleep(rpcRetryInterval)
+	}
+	return nil, wrapRPCQueryError(err, "account_tx", rpcParams["account"])
+}
+
+// deriveAccountTransfer derive transfer of account from tx with meta data,
+// payments and checks to or from the account are transfers.
+func (b *Bridge) deriveAccountTransfer(account string, txmeta *data.TransactionWithMetaData) *tokens.AccountTransfer {
+	base := txmeta.Transaction.GetBase()
+	transfer := &tokens.AccountTransfer{
+		TxHash:    txmeta.GetHash().String(),
+		Height:    uint64(txmeta.LedgerSequence),
+		Timestamp: uint64(txmeta.Date.Time().Unix()),
+		TxType:    txmeta.GetType(),
+		Success:   txmeta.MetaData.TransactionResult.Success(),
+		Fee:       base.Fee.String(),
+	}
+
+	var amount *data.Amount
+	var sender, receiver string
+	switch tx := txmeta.Transaction.(type) {
+	case *data.Payment:
+		sender, receiver = tx.Account.String(), tx.Destination.String()
+		amount = txmeta.MetaData.DeliveredAmount
+		if amount == nil {
+			amount = &tx.Amount
+		}
+	case *data.CheckCreate:
+		sender, receiver = tx.Account.String(), tx.Destination.String()
+		amount = &tx.SendMax
+	default:
+		return transfer
+	}
+
+	switch {
+	case common.IsEqualIgnoreCase(receiver, account):
+		transfer.Direction = tokens.TransferIn
+		transfer.Counterparty = sender
+	case common.IsEqualIgnoreCase(sender, account):
+		transfer.Direction = tokens.TransferOut
+		transfer.Counterparty = receiver
+	default:
+		return transfer
+	}
+	transfer.Amount = amount.String()
+	transfer.Token = amount.Asset().String()
+	if token := b.GetTokenConfig(transfer.Token); token != nil {
+		transfer.Value = tokens.ToBits(amount.Value.String(), token.Decimals)
+	}
+
+	if transfer.Direction == tokens.TransferIn && transfer.Success && base.GetTransactionType() == data.PAYMENT {
+		swapInfo, err := b.verifySwapoutTx(transfer.TxHash, 0, true)
+		transfer.SwapInfo = swapInfo
+		if err != nil {
+			transfer.VerifyError = err.Error()
+		}
+	}
+	return transfer
+}
@@ -0,0 +1,138 @@
+package ripple
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func newTestAccountTxsBridge(ledgerRange string, markers *[]interface{}) (*Bridge, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string                   `json:"method"`
+			Params []map[string]interface{} `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Method != "account_tx" {
+			_, _ = w.Write([]byte(`{"result":{}}`))
+			return
+		}
+		marker := req.Params[0]["marker"]
+		*markers = append(*markers, marker)
+		nextMarker := `"page2"`
+		if marker != nil {
+			nextMarker = `null`
+		}
+		_, _ = w.Write([]byte(`{"result":{` + ledgerRange + `,"transactions":[],"marker":` + nextMarker + `}}`))
+	}))
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{server.URL}})
+	return b, server.Close
+}
+
+func TestGetAccountTransfers(t *testing.T) {
+	var markers []interface{}
+	b, closeServer := newTestAccountTxsBridge(`"ledger_index_min":1,"ledger_index_max":1000`, &markers)
+	defer closeServer()
+
+	transfers, err := b.GetAccountTransfers(testCheckAccount, 10, 100)
+	if err != nil {
+		t.Fatalf("get account transfers failed: %v", err)
+	}
+	if len(transfers) != 0 {
+		t.Errorf("get account transfers got %v transfers, want 0", len(transfers))
+	}
+	// the second page is queried with the marker of the first page
+	if len(markers) != 2 || markers[0] != nil || markers[1] != "page2" {
+		t.Errorf("get account transfers queried with markers %v, want [<nil> page2]", markers)
+	}
+}
+
+func TestGetAccountTransfersError(t *testing.T) {
+	var markers []interface{}
+	b, closeServer := newTestAccountTxsBridge(`"ledger_index_min":50,"ledger_index_max":1000`, &markers)
+	defer closeServer()
+
+	if _, err := b.GetAccountTransfers(testCheckAccount, 10, 100); !errors.Is(err, errLedgerRangeNotCovered) {
+		t.Errorf("get account transfers of not covered range, got error %v, want %v", err, errLedgerRangeNotCovered)
+	}
+
+	wrongRanges := [][2]uint64{{0, 100}, {100, 10}}
+	for i, r := range wrongRanges {
+		if _, err := b.GetAccountTransfers(testCheckAccount, r[0], r[1]); err == nil {
+			t.Errorf("test %v: get account transfers of wrong range %v, want error", i, r)
+		}
+	}
+}
+
+func newTestAccount(t *testing.T, address string) data.Account {
+	account, err := data.NewAccountFromAddress(address)
+	if err != nil {
+		t.Fatalf("new account failed: %v", err)
+	}
+	return *account
+}
+
+func TestDeriveAccountTransfer(t *testing.T) {
+	b := NewCrossChainBridge()
+	account := newTestAccount(t, testCheckAccount)
+	other := newTestAccount(t, testCheckIssuer)
+	fee, _ := data.NewNativeValue(12)
+	amount := newTestIOUAmount(t, "100")
+
+	newPayment := func(from, to data.Account) data.Transaction {
+		return &data.Payment{
+			TxBase:      data.TxBase{TransactionType: data.PAYMENT, Account: from, Fee: *fee},
+			Destination: to,
+			Amount:      *amount,
+		}
+	}
+
+	tests := []struct {
+		tx           data.Transaction
+		result       data.TransactionResult
+		direction    string
+		counterparty string
+		success      bool
+	}{
+		{newPayment(account, other), 0, tokens.TransferOut, testCheckIssuer, true},
+		{newPayment(other, account), 104, tokens.TransferIn, testCheckIssuer, false},
+		{newPayment(other, other), 0, "", "", true},
+		{&data.CheckCreate{
+			TxBase:      data.TxBase{TransactionType: data.CHECK_CREATE, Account: other, Fee: *fee},
+			Destination: account,
+			SendMax:     *amount,
+		}, 0, tokens.TransferIn, testCheckIssuer, true},
+		{&data.AccountSet{TxBase: data.TxBase{TransactionType: data.ACCOUNT_SET, Account: account, Fee: *fee}}, 0, "", "", true},
+	}
+
+	for i, test := range tests {
+		txmeta := &data.TransactionWithMetaData{
+			Transaction:    test.tx,
+			MetaData:       data.MetaData{TransactionResult: test.result},
+			LedgerSequence: 100,
+		}
+		transfer := b.deriveAccountTransfer(testCheckAccount, txmeta)
+		if transfer.Direction != test.direction || transfer.Counterparty != test.counterparty || transfer.Success != test.success {
+			t.Errorf("test %v: derive transfer got %v/%v/%v, want %v/%v/%v", i,
+				transfer.Direction, transfer.Counterparty, transfer.Success,
+				test.direction, test.counterparty, test.success)
+		}
+		if transfer.Height != 100 || transfer.Fee != fee.String() {
+			t.Errorf("test %v: derive transfer got height %v fee %v, want 100 %v", i, transfer.Height, transfer.Fee, fee)
+		}
+		if test.direction != "" && transfer.Amount != amount.String() {
+			t.Errorf("test %v: derive transfer got amount %v, want %v", i, transfer.Amount, amount)
+		}
+		// only successful incoming payments are verified as swaps
+		if transfer.SwapInfo != nil || transfer.VerifyError != "" {
+			t.Errorf("test %v: derive transfer verified swap unexpectedly", i)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package ripple
 
 import (
 	"math/big"
+	"strconv"
 	"sync"
 	"time"
 
@@ -39,12 +40,21 @@ const (
 // Bridge block bridge inherit from btc bridge
 type Bridge struct {
 	*base.NonceSetterBase
+
+	// detected roles of gateway endpoints (rippled or clio)
+	endpointRoles     map[string]*endpointRole
+	endpointRolesLock sync.RWMutex
+
+	// validated deposits streamed ahead of ledger scanning
+	txStream        chan *tokens.StreamedRouterTx
+	txStreamStarter sync.Once
 }
 
 // NewCrossChainBridge new bridge
 func NewCrossChainBridge() *Bridge {
 	b := &Bridge{
 		NonceSetterBase: base.NewNonceSetterBase(),
+		endpointRoles:   make(map[string]*endpointRole),
 	}
 	b.RPCClientTimeout = defRPCClientTimeout
 	return b
@@ -85,7 +95,7 @@ func SetRPCRetryTimes(times int) {
 // GetLatestBlockNumber gets latest block number
 // For ripple, GetLatestBlockNumber returns current ledger version
 func (b *Bridge) GetLatestBlockNumber() (num uint64, err error) {
-	urls := b.GetGatewayConfig().AllGatewayURLs
+	urls := b.getMethodURLs("ledger_current")
 	for _, url := range urls {
 		num, err = b.GetLatestBlockNumberOf(url)
 		if err == nil {
@@ -119,7 +129,7 @@ func (b *Bridge) GetTransactionByHash(txHash string) (txRes *websockets.TxResult
 	rpcParams := map[string]interface{}{
 		"transaction": txHash,
 	}
-	urls := b.GetGatewayConfig().AllGatewayURLs
+	urls := b.getMethodURLs("tx")
 	for i := 0; i < rpcRetryTimes; i++ {
 		for _, url := range urls {
 			var res *websockets.TxResult
@@ -148,18 +158,22 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 		return nil, errTxResultType
 	}
 
-	// Check tx status
-	if !txres.TransactionWithMetaData.MetaData.TransactionResult.Success() {
-		log.Warn("Ripple tx status is not success", "result", txres.TransactionWithMetaData.MetaData.TransactionResult)
-		return nil, tokens.ErrTxWithWrongStatus
-	}
-
 	status.Receipt = nil
 	inledger := txres.LedgerSequence
 	status.BlockHeight = uint64(inledger)
 
-	if latest, err := b.GetLatestBlockNumber(); err == nil && latest > uint64(inledger) {
-		status.Confirmations = latest - uint64(inledger)
+	// Check tx status
+	txResult := txres.TransactionWithMetaData.MetaData.TransactionResult
+	if !txResult.Success() {
+		log.Warn("Ripple tx status is not success", "result", txResult)
+		status.SetFailed(txResult.String())
+	}
+	if txres.TransactionWithMetaData.Transaction != nil {
+		status.FeePaid = strconv.FormatInt(txres.TransactionWithMetaData.Transaction.GetBase().Fee.Drops(), 10)
+	}
+
+	if latest, err := b.GetLatestBlockNumber(); err == nil {
+		status.SetLatestHeight(latest, b.GetChainConfig().Confirmations)
 	}
 	return status, nil
 }
@@ -184,7 +198,7 @@ func (b *Bridge) GetAccount(address string) (acctRes *websockets.AccountInfoResu
 		"account":      address,
 		"ledger_index": "current",
 	}
-	urls := b.GetGatewayConfig().AllGatewayURLs
+	urls := b.getMethodURLs("account_info")
 	for i := 0; i < rpcRetryTimes; i++ {
 		for _, url := range urls {
 			var res *websockets.AccountInfoResult
@@ -206,7 +220,7 @@ func (b *Bridge) GetAccountLine(currency, issuer, accountAddress string) (line *
 		"limit":        400,
 		"ledger_index": "current",
 	}
-	urls := b.GetGatewayConfig().AllGatewayURLs
+	urls := b.getMethodURLs("account_lines")
 	var acclRes *websockets.AccountLinesResult
 PAGE_LOOP:
 	for {
@@ -246,7 +260,7 @@ PAGE_LOOP:
 // GetFee get fee
 func (b *Bridge) GetFee() (feeRes *websockets.FeeResult, err error) {
 	rpcParams := map[string]interface{}{}
-	urls := b.GetGatewayConfig().AllGatewayURLs
+	urls := b.getMethodURLs("fee")
 	for i := 0; i < rpcRetryTimes; i++ {
 		for _, url := range urls {
 			var res *websockets.FeeResult
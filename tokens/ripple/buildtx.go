@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -40,6 +41,8 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 
 	switch args.SwapType {
 	case tokens.ERC20SwapType:
+	case tokens.ClaimRefundType:
+		return b.buildCheckCancelTx(args)
 	default:
 		return nil, tokens.ErrSwapTypeNotSupported
 	}
@@ -78,6 +81,9 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		return nil, err
 	}
 
+	var paths *data.PathSet
+	var sendMax *data.Amount
+	var isCheck bool
 	if asset.IsNative() {
 		needAmount := new(big.Int).Add(amount, b.getMinReserveFee())
 		err = b.checkNativeBalance(args.From, needAmount, true)
@@ -93,7 +99,19 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		if err != nil {
 			return nil, err
 		}
-		err = b.checkNonNativeBalance(asset.Currency, asset.Issuer, args.From, receiver, amt)
+		isCheck, err = b.isCheckPayout(args, asset, receiver)
+		if err != nil {
+			return nil, err
+		}
+		payAmount := amt
+		if cfg := params.GetPathFindConfig(b.ChainConfig.ChainID); cfg != nil && asset.Issuer != args.From && !isCheck {
+			paths, sendMax, err = b.getPaymentPathAndSendMax(args.From, receiver, amt, cfg)
+			if err != nil {
+				return nil, err
+			}
+			payAmount = sendMax
+		}
+		err = b.checkNonNativeBalance(asset.Currency, asset.Issuer, args.From, receiver, payAmount, isCheck)
 		if err != nil {
 			return nil, err
 		}
@@ -107,14 +125,25 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 	ripplePubKey := ImportPublicKey(common.FromHex(mpcPubkey))
 	memo := args.GetUniqueSwapIdentifier()
 
+	if isCheck {
+		expiration, errf := getCheckExpiration(params.GetCheckPayoutConfig(b.ChainConfig.ChainID), extra, time.Now().Unix())
+		if errf != nil {
+			return nil, errf
+		}
+		return NewUnsignedCheckCreateTransaction(
+			ripplePubKey, nil, uint32(*extra.Sequence), uint32(*extra.TTL),
+			receiver, toTag, &expiration, amt.String(), *extra.Fee, memo)
+	}
+
 	flags := uint32(0)
 	if token.ContractVersion == uint64(tfPartialPayment) {
 		flags = uint32(tfPartialPayment)
 	}
 
 	return NewUnsignedPaymentTransaction(
-		ripplePubKey, nil, uint32(*extra.Sequence),
-		receiver, toTag, amt.String(), *extra.Fee, memo, "", flags)
+		ripplePubKey, nil, uint32(*extra.Sequence), uint32(*extra.TTL),
+		receiver, toTag, amt.String(), *extra.Fee, memo, "", flags,
+		paths, sendMax)
 }
 
 func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken string) (receiver string, destTag *uint32, amount *big.Int, err error) {
@@ -140,7 +169,7 @@ func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken
 	if toTokenCfg == nil {
 		return receiver, destTag, amount, tokens.ErrMissTokenConfig
 	}
-	amount = tokens.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo)
+	amount = args.ApplyConversionRate(args.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo))
 	return receiver, destTag, amount, err
 }
 
@@ -211,6 +240,16 @@ func (b *Bridge) setExtraArgs(args *tokens.BuildTxArgs) (*tokens.AllExtras, erro
 		extra.Sequence = seq
 	}
 
+	if extra.TTL == nil {
+		latest, err := b.GetLatestBlockNumber()
+		if err != nil {
+			log.Warn("get current ledger failed", "err", err)
+			return nil, err
+		}
+		lastLedgerSeq := latest + params.GetLastLedgerWindow(b.ChainConfig.ChainID)
+		extra.TTL = &lastLedgerSeq
+	}
+
 	if extra.Fee == nil {
 		feeRes, err := b.GetFee()
 		if err != nil {
@@ -254,14 +293,17 @@ func (b *Bridge) checkNativeBalance(account string, amount *big.Int, isPay bool)
 	return nil
 }
 
-func (b *Bridge) checkNonNativeBalance(currency, issuer, account, receiver string, amount *data.Amount) error {
+// checkNonNativeBalance check sender balance and receiver trust line (not required by check payout)
+func (b *Bridge) checkNonNativeBalance(currency, issuer, account, receiver string, amount *data.Amount, isCheck bool) error {
 	if !params.IsSwapServer {
 		return nil
 	}
-	_, err := b.GetAccountLine(currency, issuer, receiver)
-	if err != nil {
-		log.Error("get receiver account line failed", "currency", currency, "issuer", issuer, "receiver", receiver, "err", err)
-		return fmt.Errorf("%w %v", tokens.ErrBuildTxErrorAndDelay, "get receiver account line failed")
+	if !isCheck {
+		_, err := b.GetAccountLine(currency, issuer, receiver)
+		if err != nil {
+			log.Error("get receiver account line failed", "currency", currency, "issuer", issuer, "receiver", receiver, "err", err)
+			return fmt.Errorf("%w %v", tokens.ErrBuildTxErrorAndDelay, "get receiver account line failed")
+		}
 	}
 
 	if issuer == account {
@@ -272,7 +314,19 @@ func (b *Bridge) checkNonNativeBalance(currency, issuer, account, receiver strin
 	if err != nil {
 		return fmt.Errorf("sender account line: %w", err)
 	}
-	if accl.Balance.Value.Compare(*amount.Value) < 0 {
+	available := &accl.Balance.Value
+	if params.GetCheckPayoutConfig(b.ChainConfig.ChainID) != nil {
+		outstanding, errf := b.getOutstandingCheckAmount(account, &data.Asset{Currency: currency, Issuer: issuer})
+		if errf != nil {
+			log.Error("get outstanding checks failed", "currency", currency, "issuer", issuer, "account", account, "err", errf)
+			return fmt.Errorf("%w %v", tokens.ErrBuildTxErrorAndDelay, "get outstanding checks failed")
+		}
+		available, err = available.Subtract(*outstanding)
+		if err != nil {
+			return err
+		}
+	}
+	if available.Compare(*amount.Value) < 0 {
 		return fmt.Errorf("insufficient %v balance, issuer: %v, account: %v", currency, issuer, account)
 	}
 
@@ -305,7 +359,7 @@ func (b *Bridge) GetPoolNonce(address, _height string) (uint64, error) {
 func (b *Bridge) GetSeq(args *tokens.BuildTxArgs) (nonceptr *uint64, err error) {
 	var nonce uint64
 
-	if params.IsParallelSwapEnabled() {
+	if params.IsParallelSwapEnabledOnChain(b.ChainConfig.ChainID) {
 		nonce, err = b.AllocateNonce(args)
 		return &nonce, err
 	}
@@ -325,9 +379,10 @@ func (b *Bridge) GetSeq(args *tokens.BuildTxArgs) (nonceptr *uint64, err error)
 
 // NewUnsignedPaymentTransaction build ripple payment tx
 func NewUnsignedPaymentTransaction(
-	key crypto.Key, keyseq *uint32, txseq uint32,
+	key crypto.Key, keyseq *uint32, txseq, lastLedgerSeq uint32,
 	dest string, destinationTag *uint32,
 	amt, fee, memo, path string, flags uint32,
+	paths *data.PathSet, sendMax *data.Amount,
 ) (data.Transaction, error) {
 	destination, err := data.NewAccountFromAddress(dest)
 	if err != nil {
@@ -358,11 +413,17 @@ func NewUnsignedPaymentTransaction(
 		if err != nil {
 			return nil, err
 		}
+	} else if paths != nil {
+		tx.Paths = paths
 	}
+	tx.SendMax = sendMax
 
 	base := tx.GetBase()
 
 	base.Sequence = txseq
+	if lastLedgerSeq > 0 {
+		base.LastLedgerSequence = &lastLedgerSeq
+	}
 
 	fei, err := data.NewValue(fee, true)
 	if err != nil {
@@ -379,8 +440,8 @@ func NewUnsignedPaymentTransaction(
 		return nil, err
 	}
 	log.Info("Build unsigned payment tx success",
-		"destination", dest, "amount", amt, "memo", memo,
-		"fee", fee, "sequence", txseq, "txflags", txFlags.String(),
+		"destination", dest, "amount", amt, "sendMax", sendMax, "memo", memo,
+		"fee", fee, "sequence", txseq, "lastLedgerSeq", lastLedgerSeq, "txflags", txFlags.String(),
 		"signing hash", hash.String(), "blob", fmt.Sprintf("%X", msg))
 
 	return tx, nil
@@ -0,0 +1,532 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// check payout model:
+// swapins of issued currencies to receivers which have no trust line of the
+// currency are delivered by CheckCreate (with SendMax of the swap value), the
+// receiver cashes the check by CheckCash after setting the trust line.
+// checks are tracked as two-phase swaps with the swap tx hash as claim id,
+// the check expires on ledger at the build time plus Expiration (pinned in the
+// sign request), and then it is cancelled by the mpc with CheckCancel in the
+// swap job. as checks do not hold the funds, the swap value is kept by the mpc
+// after cancelling and the swap is redelivered by a normal Payment.
+// outstanding checks are subtracted from the mpc balance when building.
+var (
+	// ensure Bridge impl tokens.ClaimSwapper
+	_ tokens.ClaimSwapper = &Bridge{}
+	// ensure Bridge impl tokens.ClaimSwapTxResolver
+	_ tokens.ClaimSwapTxResolver = &Bridge{}
+
+	errCheckPayoutNotEnabled = errors.New("check payout is not enabled")
+	errNotCheckCreateTx      = errors.New("not a check create transaction")
+	errCheckNotCancelable    = errors.New("check is not cancelable")
+	errCheckTxsTooMany       = errors.New("too many txs of check receiver to scan")
+	errCheckExpiration       = errors.New("wrong check expiration")
+
+	maxCheckTxPages = 20
+
+	// allowed drift of the pinned check expiration between swap server and oracles
+	checkExpirationDrift int64 = 600
+)
+
+const (
+	entryNotFound     = "entryNotFound"
+	checkTxsLimit     = 200
+	checkObjectsLimit = 400
+
+	rippleTimeEpoch int64 = 946684800
+)
+
+type checkPayout struct {
+	CheckID     string
+	Account     string
+	Destination string
+	Token       string
+	Ledger      uint32
+	CreateTime  int64
+	Expiration  int64 // unix seconds, zero if the check never expires
+}
+
+type ledgerEntryResult struct {
+	Index string `json:"index"`
+	Error string `json:"error"`
+}
+
+type checkTxsResult struct {
+	Transactions []struct {
+		Tx struct {
+			TransactionType string `json:"TransactionType"`
+			CheckID         string `json:"CheckID"`
+		} `json:"tx"`
+		Meta struct {
+			TransactionResult string `json:"TransactionResult"`
+		} `json:"meta"`
+	} `json:"transactions"`
+	Marker interface{} `json:"marker"`
+	Error  string      `json:"error"`
+}
+
+type checkObject struct {
+	LedgerEntryType string      `json:"LedgerEntryType"`
+	Account         string      `json:"Account"`
+	SendMax         data.Amount `json:"SendMax"`
+	Expiration      uint32      `json:"Expiration"`
+}
+
+type checkObjectsResult struct {
+	AccountObjects []*checkObject `json:"account_objects"`
+	Marker         interface{}    `json:"marker"`
+	Error          string         `json:"error"`
+}
+
+func toRippleTime(unixTime int64) uint32 {
+	return uint32(unixTime - rippleTimeEpoch)
+}
+
+func fromRippleTime(rippleTime uint32) int64 {
+	return int64(rippleTime) + rippleTimeEpoch
+}
+
+// isCheckPayout deliver by check if check payout is enabled
+// and the receiver has no trust line of the issued currency
+func (b *Bridge) isCheckPayout(args *tokens.BuildTxArgs, asset *data.Asset, receiver string) (bool, error) {
+	if params.GetCheckPayoutConfig(b.ChainConfig.ChainID) == nil || asset.Issuer == receiver {
+		return false, nil
+	}
+	if args.Extra != nil && args.Extra.DirectPayout {
+		return false, nil
+	}
+	_, err := b.GetAccountLine(asset.Currency, asset.Issuer, receiver)
+	if err == nil {
+		return false, nil
+	}
+	if errors.Is(err, tokens.ErrNotFound) {
+		log.Info("receiver has no trust line, deliver by check", "currency", asset.Currency, "issuer", asset.Issuer, "receiver", receiver)
+		return true, nil
+	}
+	return false, fmt.Errorf("%w %v", tokens.ErrBuildTxErrorAndDelay, "get receiver account line failed")
+}
+
+// GetClaimIDOfSwapTx impl tokens.ClaimSwapTxResolver
+// the claim id of check payout is the hash of the CheckCreate swap tx.
+func (b *Bridge) GetClaimIDOfSwapTx(txHash string) (string, error) {
+	if params.GetCheckPayoutConfig(b.ChainConfig.ChainID) == nil {
+		return "", nil
+	}
+	_, err := b.getCheckPayout(txHash)
+	if errors.Is(err, errNotCheckCreateTx) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return txHash, nil
+}
+
+// getCheckPayout get the check created by the CheckCreate tx
+func (b *Bridge) getCheckPayout(txHash string) (*checkPayout, error) {
+	txres, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if !txres.Validated {
+		return nil, tokens.ErrTxIsNotValidated
+	}
+	txmeta := &txres.TransactionWithMetaData
+	checkCreate, ok := txmeta.Transaction.(*data.CheckCreate)
+	if !ok || checkCreate.GetTransactionType() != data.CHECK_CREATE {
+		return nil, errNotCheckCreateTx
+	}
+	if !txmeta.MetaData.TransactionResult.Success() {
+		return nil, tokens.ErrTxWithWrongStatus
+	}
+	for _, effect := range txmeta.MetaData.AffectedNodes {
+		node := effect.CreatedNode
+		if node == nil || node.LedgerEntryType != data.CHECK || node.LedgerIndex == nil {
+			continue
+		}
+		check := &checkPayout{
+			CheckID:     node.LedgerIndex.String(),
+			Account:     checkCreate.Account.String(),
+			Destination: checkCreate.Destination.String(),
+			Token:       checkCreate.SendMax.Asset().String(),
+			Ledger:      txmeta.LedgerSequence,
+			CreateTime:  txmeta.Date.Time().Unix(),
+		}
+		if checkCreate.Expiration != nil {
+			check.Expiration = fromRippleTime(*checkCreate.Expiration)
+		}
+		return check, nil
+	}
+	return nil, fmt.Errorf("no check is created by tx %v", txHash)
+}
+
+// GetClaimInfo impl tokens.ClaimSwapper
+func (b *Bridge) GetClaimInfo(claimID string) (*tokens.ClaimInfo, error) {
+	cfg := params.GetCheckPayoutConfig(b.ChainConfig.ChainID)
+	if cfg == nil {
+		return nil, errCheckPayoutNotEnabled
+	}
+	check, err := b.getCheckPayout(claimID)
+	if err != nil {
+		return nil, err
+	}
+	info := &tokens.ClaimInfo{
+		Expiry: check.Expiration,
+		Token:  check.Token,
+	}
+	if info.Expiry == 0 { // checks created without on ledger expiration
+		info.Expiry = check.CreateTime + cfg.GetExpiration()
+	}
+	exist, err := b.isCheckExist(check.CheckID)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		info.Status = tokens.ClaimPending
+		return info, nil
+	}
+	info.Status, err = b.getCheckDeletedStatus(check)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (b *Bridge) isCheckExist(checkID string) (bool, error) {
+	rpcParams := map[string]interface{}{
+		"check":        checkID,
+		"ledger_index": "validated",
+	}
+	var err error
+	urls := b.getMethodURLs("ledger_entry")
+	for i := 0; i < rpcRetryTimes; i++ {
+		for _, url := range urls {
+			var res *ledgerEntryResult
+			err = client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "ledger_entry", rpcParams)
+			if err != nil || res == nil {
+				continue
+			}
+			switch res.Error {
+			case "":
+				return true, nil
+			case entryNotFound:
+				return false, nil
+			default:
+				err = fmt.Errorf("get ledger entry failed, %v", res.Error)
+			}
+		}
+		time.Sleep(rpcRetryInterval)
+	}
+	return false, wrapRPCQueryError(err, "ledger_entry", checkID)
+}
+
+// getCheckDeletedStatus find the tx deleting the check in the txs of the receiver,
+// CheckCash means claimed and CheckCancel means refunded (funds kept by the mpc).
+func (b *Bridge) getCheckDeletedStatus(check *checkPayout) (tokens.ClaimStatus, error) {
+	rpcParams := map[string]interface{}{
+		"account":          check.Destination,
+		"ledger_index_min": check.Ledger,
+		"ledger_index_max": -1,
+		"forward":          true,
+		"limit":            checkTxsLimit,
+	}
+	urls := b.getMethodURLs("account_tx")
+	for page := 0; page < maxCheckTxPages; page++ {
+		var txsRes *checkTxsResult
+		var err error
+	RETRY_LOOP:
+		for i := 0; i < rpcRetryTimes; i++ {
+			for _, url := range urls {
+				var res *checkTxsResult
+				err = client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "account_tx", rpcParams)
+				if err == nil && res != nil && res.Error == "" {
+					txsRes = res
+					break RETRY_LOOP
+				}
+				if err == nil && res != nil {
+					err = fmt.Errorf("get account txs failed, %v", res.Error)
+				}
+			}
+			time.Sleep(rpcRetryInterval)
+		}
+		if txsRes == nil {
+			return tokens.ClaimNotExist, wrapRPCQueryError(err, "account_tx", check.Destination)
+		}
+		for _, tx := range txsRes.Transactions {
+			if !strings.EqualFold(tx.Tx.CheckID, check.CheckID) || tx.Meta.TransactionResult != "tesSUCCESS" {
+				continue
+			}
+			switch tx.Tx.TransactionType {
+			case "CheckCash":
+				return tokens.ClaimClaimed, nil
+			case "CheckCancel":
+				return tokens.ClaimRefunded, nil
+			}
+		}
+		if txsRes.Marker == nil {
+			return tokens.ClaimNotExist, nil
+		}
+		rpcParams["marker"] = txsRes.Marker
+	}
+	return tokens.ClaimNotExist, errCheckTxsTooMany
+}
+
+// verifyCheckCancel verify cancelling of expired check.
+// we only need to check the check is still pending and expired here.
+func (b *Bridge) verifyCheckCancel(claimID string) (*tokens.SwapTxInfo, error) {
+	claim, err := b.GetClaimInfo(claimID)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Status != tokens.ClaimPending || claim.Expiry > time.Now().Unix() {
+		return nil, errCheckNotCancelable
+	}
+	tokenCfg := b.GetTokenConfig(claim.Token)
+	if tokenCfg == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	chainID := b.ChainConfig.GetChainID()
+	swapInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}}
+	swapInfo.SwapType = tokens.ClaimRefundType
+	swapInfo.Hash = claimID
+	swapInfo.FromChainID = chainID
+	swapInfo.ToChainID = chainID
+	swapInfo.ERC20SwapInfo.Token = tokenCfg.ContractAddress
+	swapInfo.ERC20SwapInfo.TokenID = tokenCfg.TokenID
+	return swapInfo, nil
+}
+
+func (b *Bridge) buildCheckCancelTx(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	if params.GetCheckPayoutConfig(b.ChainConfig.ChainID) == nil {
+		return nil, errCheckPayoutNotEnabled
+	}
+	if _, err = b.verifyCheckCancel(args.SwapID); err != nil {
+		return nil, err
+	}
+	check, err := b.getCheckPayout(args.SwapID)
+	if err != nil {
+		return nil, err
+	}
+	if !common.IsEqualIgnoreCase(check.Account, args.From) {
+		log.Error("cancel check mpc mismatch", "have", args.From, "want", check.Account)
+		return nil, tokens.ErrSenderMismatch
+	}
+	mpcPubkey := router.GetMPCPublicKey(args.From)
+	if mpcPubkey == "" {
+		return nil, tokens.ErrMissMPCPublicKey
+	}
+	extra, err := b.setExtraArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ripplePubKey := ImportPublicKey(common.FromHex(mpcPubkey))
+	return NewUnsignedCheckCancelTransaction(
+		ripplePubKey, nil, uint32(*extra.Sequence), uint32(*extra.TTL),
+		check.CheckID, *extra.Fee)
+}
+
+// GetClaimRefundArgs impl tokens.ClaimSwapper, cancel the expired check
+func (b *Bridge) GetClaimRefundArgs(claimID string) (*tokens.BuildTxArgs, error) {
+	swapInfo, err := b.verifyCheckCancel(claimID)
+	if err != nil {
+		return nil, err
+	}
+	routerMPC, err := router.GetRouterMPC(swapInfo.ERC20SwapInfo.TokenID, b.ChainConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	return &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapInfo:    swapInfo.SwapInfo,
+			Identifier:  params.GetIdentifier(),
+			Salt:        params.GetDeploymentSalt(),
+			SwapID:      claimID,
+			SwapType:    tokens.ClaimRefundType,
+			FromChainID: swapInfo.FromChainID,
+			ToChainID:   swapInfo.ToChainID,
+		},
+		From: routerMPC,
+	}, nil
+}
+
+// getCheckExpiration get the on ledger expiration (ripple time) of the check,
+// it is pinned in the sign request and checked against the config by oracles.
+func getCheckExpiration(cfg *params.CheckPayoutConfig, extra *tokens.AllExtras, now int64) (uint32, error) {
+	if extra.Expiration == nil {
+		expiration := uint64(now + cfg.GetExpiration())
+		extra.Expiration = &expiration
+	}
+	expiration := int64(*extra.Expiration)
+	if expiration <= now || expiration > now+cfg.GetExpiration()+checkExpirationDrift {
+		return 0, fmt.Errorf("%w: %v", errCheckExpiration, expiration)
+	}
+	return toRippleTime(expiration), nil
+}
+
+// getOutstandingCheckAmount get the total SendMax of checks created by the account
+// which are not expired, they can be cashed any time and reduce the available balance.
+func (b *Bridge) getOutstandingCheckAmount(account string, asset *data.Asset) (*data.Value, error) {
+	rpcParams := map[string]interface{}{
+		"account":      account,
+		"type":         "check",
+		"ledger_index": "validated",
+		"limit":        checkObjectsLimit,
+	}
+	var objects []*checkObject
+	urls := b.getMethodURLs("account_objects")
+	for page := 0; page < maxCheckTxPages; page++ {
+		var objsRes *checkObjectsResult
+		var err error
+	RETRY_LOOP:
+		for i := 0; i < rpcRetryTimes; i++ {
+			for _, url := range urls {
+				var res *checkObjectsResult
+				err = client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "account_objects", rpcParams)
+				if err == nil && res != nil && res.Error == "" {
+					objsRes = res
+					break RETRY_LOOP
+				}
+				if err == nil && res != nil {
+					err = fmt.Errorf("get account objects failed, %v", res.Error)
+				}
+			}
+			time.Sleep(rpcRetryInterval)
+		}
+		if objsRes == nil {
+			return nil, wrapRPCQueryError(err, "account_objects", account)
+		}
+		objects = append(objects, objsRes.AccountObjects...)
+		if objsRes.Marker == nil {
+			return sumOutstandingChecks(objects, account, asset, toRippleTime(time.Now().Unix()))
+		}
+		rpcParams["marker"] = objsRes.Marker
+	}
+	return nil, errCheckTxsTooMany
+}
+
+// sumOutstandingChecks sum SendMax of the asset of checks created by the account
+// which are not expired at ripple time `now`
+func sumOutstandingChecks(objects []*checkObject, account string, asset *data.Asset, now uint32) (*data.Value, error) {
+	sum, err := data.NewNonNativeValue(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objects {
+		if obj.LedgerEntryType != "Check" || obj.Account != account || obj.SendMax.Value == nil {
+			continue
+		}
+		if obj.Expiration != 0 && obj.Expiration <= now {
+			continue
+		}
+		if !asset.Matches(&obj.SendMax) {
+			continue
+		}
+		sum, err = sum.Add(*obj.SendMax.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sum, nil
+}
+
+// NewUnsignedCheckCreateTransaction build ripple check create tx
+func NewUnsignedCheckCreateTransaction(
+	key crypto.Key, keyseq *uint32, txseq, lastLedgerSeq uint32,
+	dest string, destinationTag *uint32, expiration *uint32,
+	amt, fee, memo string,
+) (data.Transaction, error) {
+	destination, err := data.NewAccountFromAddress(dest)
+	if err != nil {
+		return nil, err
+	}
+	sendMax, err := data.NewAmount(amt)
+	if err != nil {
+		return nil, err
+	}
+	tx := &data.CheckCreate{
+		Destination:    *destination,
+		SendMax:        *sendMax,
+		DestinationTag: destinationTag,
+		Expiration:     expiration,
+	}
+	tx.TransactionType = data.CHECK_CREATE
+
+	if memo != "" {
+		memoStr := new(data.Memo)
+		memoStr.Memo.MemoData = []byte(memo)
+		tx.Memos = append(tx.Memos, *memoStr)
+	}
+
+	hash, msg, err := initUnsignedTx(tx, key, keyseq, txseq, lastLedgerSeq, fee)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Build unsigned check create tx success",
+		"destination", dest, "sendMax", amt, "memo", memo,
+		"fee", fee, "sequence", txseq, "lastLedgerSeq", lastLedgerSeq,
+		"signing hash", hash.String(), "blob", fmt.Sprintf("%X", msg))
+
+	return tx, nil
+}
+
+// NewUnsignedCheckCancelTransaction build ripple check cancel tx
+func NewUnsignedCheckCancelTransaction(
+	key crypto.Key, keyseq *uint32, txseq, lastLedgerSeq uint32,
+	checkID, fee string,
+) (data.Transaction, error) {
+	id, err := data.NewHash256(checkID)
+	if err != nil {
+		return nil, err
+	}
+	tx := &data.CheckCancel{
+		CheckID: *id,
+	}
+	tx.TransactionType = data.CHECK_CANCEL
+
+	hash, msg, err := initUnsignedTx(tx, key, keyseq, txseq, lastLedgerSeq, fee)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Build unsigned check cancel tx success",
+		"checkID", checkID, "fee", fee, "sequence", txseq, "lastLedgerSeq", lastLedgerSeq,
+		"signing hash", hash.String(), "blob", fmt.Sprintf("%X", msg))
+
+	return tx, nil
+}
+
+func initUnsignedTx(tx data.Transaction, key crypto.Key, keyseq *uint32, txseq, lastLedgerSeq uint32, fee string) (hash data.Hash256, msg []byte, err error) {
+	base := tx.GetBase()
+
+	base.Sequence = txseq
+	if lastLedgerSeq > 0 {
+		base.LastLedgerSequence = &lastLedgerSeq
+	}
+
+	fei, err := data.NewValue(fee, true)
+	if err != nil {
+		return hash, nil, err
+	}
+	base.Fee = *fei
+
+	copy(base.Account[:], key.Id(keyseq))
+
+	tx.InitialiseForSigning()
+	copy(tx.GetPublicKey().Bytes(), key.Public(keyseq))
+	return data.SigningHash(tx)
+}
@@ -0,0 +1,90 @@
+package ripple
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+const (
+	testCheckAccount = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	testCheckIssuer  = "rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn"
+)
+
+func TestRippleTime(t *testing.T) {
+	unixTime := int64(1700000000)
+	if got := fromRippleTime(toRippleTime(unixTime)); got != unixTime {
+		t.Errorf("ripple time round trip got %v, want %v", got, unixTime)
+	}
+	if got := toRippleTime(rippleTimeEpoch); got != 0 {
+		t.Errorf("ripple time of epoch got %v, want 0", got)
+	}
+}
+
+func TestGetCheckExpiration(t *testing.T) {
+	cfg := &params.CheckPayoutConfig{Expiration: 3600}
+	now := int64(1700000000)
+
+	// the swap server pins the expiration
+	extra := &tokens.AllExtras{}
+	expiration, err := getCheckExpiration(cfg, extra, now)
+	if err != nil {
+		t.Fatalf("get check expiration failed: %v", err)
+	}
+	if extra.Expiration == nil || *extra.Expiration != uint64(now+3600) || expiration != toRippleTime(now+3600) {
+		t.Fatalf("get check expiration got %v (pinned %v), want %v", expiration, extra.Expiration, now+3600)
+	}
+
+	// oracles rebuild with the pinned expiration a bit later
+	got, err := getCheckExpiration(cfg, extra, now+30)
+	if err != nil || got != expiration {
+		t.Errorf("rebuild with pinned expiration got %v, err %v, want %v", got, err, expiration)
+	}
+
+	for _, pinned := range []int64{now - 1, now, now + 3600 + checkExpirationDrift + 1} {
+		value := uint64(pinned)
+		if _, err := getCheckExpiration(cfg, &tokens.AllExtras{Expiration: &value}, now); !errors.Is(err, errCheckExpiration) {
+			t.Errorf("pinned expiration %v should be rejected, err %v", pinned, err)
+		}
+	}
+}
+
+func TestSumOutstandingChecks(t *testing.T) {
+	objects := `[
+		{"LedgerEntryType":"Check","Account":"` + testCheckAccount + `","SendMax":{"currency":"USD","issuer":"` + testCheckIssuer + `","value":"10"},"Expiration":2000},
+		{"LedgerEntryType":"Check","Account":"` + testCheckAccount + `","SendMax":{"currency":"USD","issuer":"` + testCheckIssuer + `","value":"2.5"}},
+		{"LedgerEntryType":"Check","Account":"` + testCheckAccount + `","SendMax":{"currency":"USD","issuer":"` + testCheckIssuer + `","value":"100"},"Expiration":1000},
+		{"LedgerEntryType":"Check","Account":"` + testCheckAccount + `","SendMax":{"currency":"EUR","issuer":"` + testCheckIssuer + `","value":"7"}},
+		{"LedgerEntryType":"Check","Account":"` + testCheckIssuer + `","SendMax":{"currency":"USD","issuer":"` + testCheckIssuer + `","value":"9"}},
+		{"LedgerEntryType":"Check","Account":"` + testCheckAccount + `","SendMax":"1000000"}
+	]`
+	var checks []*checkObject
+	if err := json.Unmarshal([]byte(objects), &checks); err != nil {
+		t.Fatalf("unmarshal check objects failed: %v", err)
+	}
+	asset := &data.Asset{Currency: "USD", Issuer: testCheckIssuer}
+
+	// the expired check (Expiration 1000) can not be cashed any more,
+	// the checks of other currencies or created by others are ignored
+	sum, err := sumOutstandingChecks(checks, testCheckAccount, asset, 1500)
+	if err != nil {
+		t.Fatalf("sum outstanding checks failed: %v", err)
+	}
+	want, _ := data.NewNonNativeValue(125, -1)
+	if !sum.Equals(*want) {
+		t.Errorf("sum outstanding checks got %v, want %v", sum, want)
+	}
+
+	sum, err = sumOutstandingChecks(checks, testCheckAccount, asset, 500)
+	if err != nil {
+		t.Fatalf("sum outstanding checks failed: %v", err)
+	}
+	want, _ = data.NewNonNativeValue(1125, -1)
+	if !sum.Equals(*want) {
+		t.Errorf("sum outstanding checks before expiry got %v, want %v", sum, want)
+	}
+}
@@ -0,0 +1,26 @@
+package ripple
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/websockets"
+)
+
+var _ tokens.TxCrossVerifier = &Bridge{}
+
+// CrossVerifyTx impl tokens.TxCrossVerifier
+func (b *Bridge) CrossVerifyTx(txHash string, quorum int) error {
+	rpcParams := map[string]interface{}{
+		"transaction": txHash,
+	}
+	return tokens.CrossVerifyTx(b.ChainConfig.ChainID, txHash, b.getMethodURLs("tx"), quorum, func(url string) (string, error) {
+		var res *websockets.TxResult
+		if err := client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "tx", rpcParams); err != nil {
+			return "", err
+		}
+		if res == nil {
+			return "", tokens.ErrTxNotFound
+		}
+		return tokens.TxFingerprint(res)
+	})
+}
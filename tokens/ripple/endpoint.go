@@ -0,0 +1,115 @@
+package ripple
+
+import (
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+)
+
+// failed detections of endpoint role are retried after this interval
+var endpointRoleRetryInterval = time.Minute
+
+var (
+	// history queries are served by clio endpoints first.
+	// clio knows only validated txs, unvalidated ones fall back to rippled.
+	historyMethods = map[string]bool{
+		"tx":         true,
+		"account_tx": true,
+		"ledger":     true,
+	}
+
+	// commands on the open ledger are sent to rippled endpoints only,
+	// clio forwards them to its rippled which may submit a tx twice.
+	rippledMethods = map[string]bool{
+		"submit":           true,
+		"ledger_current":   true,
+		"fee":              true,
+		"ripple_path_find": true,
+	}
+)
+
+// serverInfoResult server_info result, clio reports its version as 'clio_version'
+type serverInfoResult struct {
+	Info struct {
+		BuildVersion string `json:"build_version"`
+		ClioVersion  string `json:"clio_version"`
+	} `json:"info"`
+}
+
+// endpointRole detected role of endpoint,
+// failed detection is cached as rippled until the expire time.
+type endpointRole struct {
+	role     string
+	expireAt time.Time // zero means never expire
+}
+
+func (r *endpointRole) isExpired(now time.Time) bool {
+	return !r.expireAt.IsZero() && now.After(r.expireAt)
+}
+
+// getEndpointRole get role of gateway endpoint from config,
+// or detect it by 'server_info' (rippled if detection fails).
+func (b *Bridge) getEndpointRole(url string) string {
+	if role := params.GetEndpointRole(b.ChainConfig.ChainID, url); role != "" {
+		return role
+	}
+	b.endpointRolesLock.RLock()
+	cached, exist := b.endpointRoles[url]
+	b.endpointRolesLock.RUnlock()
+	if exist && !cached.isExpired(time.Now()) {
+		return cached.role
+	}
+
+	var res *serverInfoResult
+	err := client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "server_info", map[string]interface{}{})
+	if err != nil || res == nil {
+		log.Warn("detect endpoint role failed", "chainID", b.ChainConfig.ChainID, "url", url, "err", err, "retryAfter", endpointRoleRetryInterval.String())
+		b.setEndpointRole(url, &endpointRole{
+			role:     params.EndpointRoleRippled,
+			expireAt: time.Now().Add(endpointRoleRetryInterval),
+		})
+		return params.EndpointRoleRippled
+	}
+	role := params.EndpointRoleRippled
+	if res.Info.ClioVersion != "" {
+		role = params.EndpointRoleClio
+	}
+	b.setEndpointRole(url, &endpointRole{role: role})
+	log.Info("detect endpoint role success", "chainID", b.ChainConfig.ChainID, "url", url, "role", role, "buildVersion", res.Info.BuildVersion, "clioVersion", res.Info.ClioVersion)
+	return role
+}
+
+func (b *Bridge) setEndpointRole(url string, role *endpointRole) {
+	b.endpointRolesLock.Lock()
+	b.endpointRoles[url] = role
+	b.endpointRolesLock.Unlock()
+}
+
+// getMethodURLs get gateway urls to call the method in order.
+// history queries try clio endpoints first and then rippled endpoints,
+// rippled only commands use rippled endpoints (all if there is none).
+func (b *Bridge) getMethodURLs(method string) []string {
+	urls := b.GetGatewayConfig().AllGatewayURLs
+	isHistory, isRippledOnly := historyMethods[method], rippledMethods[method]
+	if !isHistory && !isRippledOnly {
+		return urls
+	}
+	rippledURLs := make([]string, 0, len(urls))
+	clioURLs := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if b.getEndpointRole(url) == params.EndpointRoleClio {
+			clioURLs = append(clioURLs, url)
+		} else {
+			rippledURLs = append(rippledURLs, url)
+		}
+	}
+	if isHistory {
+		return append(clioURLs, rippledURLs...)
+	}
+	if len(rippledURLs) == 0 {
+		return urls
+	}
+	return rippledURLs
+}
@@ -0,0 +1,49 @@
+package ripple
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestGetEndpointRoleCachesFailedDetection(t *testing.T) {
+	serverInfoCalls := 0
+	isDown := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverInfoCalls++
+		if isDown {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":{"info":{"clio_version":"2.0.0"}}}`))
+	}))
+	defer server.Close()
+
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+
+	// failed detection is not retried before the retry interval
+	for i := 0; i < 3; i++ {
+		if role := b.getEndpointRole(server.URL); role != params.EndpointRoleRippled {
+			t.Errorf("role of failed detection got %v, want %v", role, params.EndpointRoleRippled)
+		}
+	}
+	if serverInfoCalls != 1 {
+		t.Errorf("server_info called %v times, want 1", serverInfoCalls)
+	}
+
+	// retried after the retry interval
+	isDown = false
+	b.endpointRoles[server.URL].expireAt = time.Now().Add(-time.Second)
+	if role := b.getEndpointRole(server.URL); role != params.EndpointRoleClio {
+		t.Errorf("role after retry got %v, want %v", role, params.EndpointRoleClio)
+	}
+	// detected role never expires
+	if role := b.getEndpointRole(server.URL); role != params.EndpointRoleClio || serverInfoCalls != 2 {
+		t.Errorf("cached role got %v with %v server_info calls, want %v with 2 calls", role, serverInfoCalls, params.EndpointRoleClio)
+	}
+}
@@ -0,0 +1,100 @@
+package ripple
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	// ensure Bridge impl tokens.TxExpiryChecker
+	_ tokens.TxExpiryChecker = &Bridge{}
+)
+
+const (
+	engineResultMaxLedger = "tefMAX_LEDGER"
+	txnNotFound           = "txnNotFound"
+)
+
+type validatedLedgerResult struct {
+	LedgerIndex uint32 `json:"ledger_index"`
+	Validated   bool   `json:"validated"`
+}
+
+type txLookupResult struct {
+	Validated bool   `json:"validated"`
+	Error     string `json:"error"`
+}
+
+// GetValidatedLedgerIndex get the latest validated ledger index
+func (b *Bridge) GetValidatedLedgerIndex() (index uint64, err error) {
+	rpcParams := map[string]interface{}{
+		"ledger_index": "validated",
+	}
+	urls := b.getMethodURLs("ledger")
+	for i := 0; i < rpcRetryTimes; i++ {
+		for _, url := range urls {
+			var res *validatedLedgerResult
+			err = client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "ledger", rpcParams)
+			if err == nil && res != nil && res.Validated {
+				return uint64(res.LedgerIndex), nil
+			}
+		}
+		time.Sleep(rpcRetryInterval)
+	}
+	return 0, wrapRPCQueryError(err, "GetValidatedLedgerIndex")
+}
+
+// IsTxExpired impl TxExpiryChecker
+// a tx is expired if it is not in a validated ledger while
+// the validated ledger has passed its LastLedgerSequence.
+func (b *Bridge) IsTxExpired(txHash string, lastLedgerSeq uint64) (bool, error) {
+	if lastLedgerSeq == 0 {
+		return false, nil
+	}
+	validated, err := b.GetValidatedLedgerIndex()
+	if err != nil {
+		return false, err
+	}
+	if validated <= lastLedgerSeq {
+		return false, nil
+	}
+	found, err := b.isTxValidated(txHash)
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		log.Info("ripple tx is expired", "txHash", txHash, "lastLedgerSeq", lastLedgerSeq, "validatedLedger", validated)
+	}
+	return !found, nil
+}
+
+func (b *Bridge) isTxValidated(txHash string) (bool, error) {
+	rpcParams := map[string]interface{}{
+		"transaction": txHash,
+	}
+	var err error
+	urls := b.getMethodURLs("tx")
+	for i := 0; i < rpcRetryTimes; i++ {
+		for _, url := range urls {
+			var res *txLookupResult
+			err = client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "tx", rpcParams)
+			if err != nil || res == nil {
+				continue
+			}
+			switch res.Error {
+			case "":
+				return res.Validated, nil
+			case txnNotFound:
+				return false, nil
+			default:
+				err = fmt.Errorf("get tx failed, %v", res.Error)
+			}
+		}
+		time.Sleep(rpcRetryInterval)
+	}
+	return false, wrapRPCQueryError(err, "IsTxExpired")
+}
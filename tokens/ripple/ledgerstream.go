@@ -0,0 +1,160 @@
+package ripple
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/websockets"
+)
+
+var _ tokens.RouterTxStreamer = &Bridge{}
+
+const (
+	// Interval of reconnecting to the next websocket server.
+	ledgerStreamRetryInterval = 5 * time.Second
+
+	// Interval of logging the incoming stream stats.
+	ledgerStreamStatsInterval = time.Minute
+)
+
+var (
+	errLedgerStreamClosed = errors.New("ledger stream is closed")
+	errLedgerStreamStale  = errors.New("ledger stream is stale")
+)
+
+// StreamRouterTxs impl tokens.RouterTxStreamer
+// stream validated payments to the deposit addresses.
+func (b *Bridge) StreamRouterTxs() <-chan *tokens.StreamedRouterTx {
+	cfg := params.GetLedgerStreamConfig(b.ChainConfig.ChainID)
+	if cfg == nil || len(cfg.WSServers) == 0 {
+		return nil
+	}
+	b.txStreamStarter.Do(func() {
+		if cfg.MaxMessageSize > 0 {
+			websockets.SetMaxMessageSize(cfg.MaxMessageSize)
+		}
+		b.txStream = make(chan *tokens.StreamedRouterTx)
+		go b.runLedgerStream(cfg)
+	})
+	return b.txStream
+}
+
+// runLedgerStream stream from the websocket servers in turn
+func (b *Bridge) runLedgerStream(cfg *params.LedgerStreamConfig) {
+	chainID := b.ChainConfig.ChainID
+	for i := 0; ; i++ {
+		url := cfg.WSServers[i%len(cfg.WSServers)]
+		err := b.streamLedger(url, cfg)
+		log.Warn("ripple ledger stream disconnected", "chainID", chainID, "url", url, "err", err)
+		time.Sleep(ledgerStreamRetryInterval)
+	}
+}
+
+func (b *Bridge) streamLedger(url string, cfg *params.LedgerStreamConfig) error {
+	chainID := b.ChainConfig.ChainID
+	policy, err := websockets.ParseOverflowPolicy(cfg.OverflowPolicy)
+	if err != nil {
+		return err
+	}
+	depositAddresses := b.getAllDepositAddresses()
+	accounts := make([]data.Account, 0, len(depositAddresses))
+	for _, address := range depositAddresses {
+		account, errf := data.NewAccountFromAddress(address)
+		if errf != nil {
+			return errf
+		}
+		accounts = append(accounts, *account)
+	}
+
+	remote, err := websockets.NewRemote(url)
+	if err != nil {
+		return err
+	}
+	defer remote.Close()
+	remote.SetIdleTimeout(cfg.GetIdleTimeout())
+
+	if err = remote.SetOverflowPolicy(policy, cfg.SpillDir); err != nil {
+		return err
+	}
+	deliveries, err := remote.EnableAck(websockets.ValidatedTransactions, time.Duration(cfg.AckTimeout)*time.Second)
+	if err != nil {
+		return err
+	}
+	if _, err = remote.Subscribe(true, false, false, false); err != nil {
+		return err
+	}
+	if _, err = remote.SubscribeAccounts(accounts); err != nil {
+		return err
+	}
+	log.Info("subscribe ripple ledger stream success", "chainID", chainID, "url", url, "accounts", depositAddresses, "policy", policy)
+
+	statsTicker := time.NewTicker(ledgerStreamStatsInterval)
+	defer statsTicker.Stop()
+
+	var ledgerIndex uint32
+	for {
+		select {
+		case msg, ok := <-remote.Incoming:
+			if !ok {
+				return errLedgerStreamClosed
+			}
+			if ledger, isLedger := msg.(*websockets.LedgerStreamMsg); isLedger {
+				ledgerIndex = ledger.LedgerSequence
+			}
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return errLedgerStreamClosed
+			}
+			b.dispatchDelivery(remote, delivery, depositAddresses)
+		case state, ok := <-remote.StateChanges():
+			if !ok || state == websockets.StateClosed {
+				return errLedgerStreamClosed
+			}
+			if state == websockets.StateStale {
+				// switch to the next server proactively
+				liveness := remote.Liveness()
+				log.Warn("ripple ledger stream is stale", "chainID", chainID, "url", url, "ledger", ledgerIndex, "lastActivity", liveness.LastActivity, "lastPong", liveness.LastPong)
+				return errLedgerStreamStale
+			}
+		case <-statsTicker.C:
+			stats := remote.IncomingStats()
+			log.Info("ripple ledger stream stats", "chainID", chainID, "url", url, "ledger", ledgerIndex,
+				"received", stats.Received, "lag", stats.Lag, "dropped", stats.Dropped, "spilled", stats.Spilled,
+				"blocked", stats.BlockedTime.String(), "unacked", stats.Unacked, "redelivered", stats.Redelivered,
+				"idle", remote.Liveness().Idle.String())
+		}
+	}
+}
+
+// dispatchDelivery send the deposit to the consumer and ack it after registered,
+// deliveries which are not deposits are acked directly.
+func (b *Bridge) dispatchDelivery(remote *websockets.Remote, delivery websockets.Delivery, depositAddresses []string) {
+	streamed := getStreamedDeposit(delivery, depositAddresses)
+	if streamed == nil {
+		remote.Ack(delivery.Seq)
+		return
+	}
+	streamed.Ack = func() { remote.Ack(delivery.Seq) }
+	b.txStream <- streamed
+}
+
+// getStreamedDeposit get the streamed deposit of the delivery (nil if it is not a deposit)
+func getStreamedDeposit(delivery websockets.Delivery, depositAddresses []string) *tokens.StreamedRouterTx {
+	msg, ok := delivery.Msg.(*websockets.TransactionStreamMsg)
+	if !ok || !msg.Validated {
+		return nil
+	}
+	for _, depositAddress := range depositAddresses {
+		if isDepositPayment(&msg.Transaction, depositAddress) {
+			return &tokens.StreamedRouterTx{
+				TxHash: msg.Transaction.GetHash().String(),
+				Height: uint64(msg.LedgerSequence),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/websockets"
+)
+
+var errEmptyPathFindResult = errors.New("empty ripple_path_find result")
+
+// RipplePathFind call ripple_path_find
+func (b *Bridge) RipplePathFind(from, to string, amount *data.Amount) (pathRes *websockets.RipplePathFindResult, err error) {
+	rpcParams := map[string]interface{}{
+		"source_account":      from,
+		"destination_account": to,
+		"destination_amount":  amount,
+		"source_currencies": []map[string]string{
+			{
+				"currency": amount.Currency.Machine(),
+				"issuer":   amount.Issuer.String(),
+			},
+		},
+	}
+	urls := b.getMethodURLs("ripple_path_find")
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
+		for _, url := range urls {
+			var res *websockets.RipplePathFindResult
+			err = client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "ripple_path_find", rpcParams)
+			if err == nil && res != nil {
+				pathRes = res
+				return nil
+			}
+		}
+		if err == nil {
+			err = errEmptyPathFindResult
+		}
+		return err
+	})
+	if err != nil {
+		return nil, wrapRPCQueryError(err, "ripple_path_find")
+	}
+	return pathRes, nil
+}
+
+// findPaymentPath find the cheapest paths of delivering amount,
+// the source amount is paid in the same currency as the amount.
+func (b *Bridge) findPaymentPath(from, to string, amount *data.Amount) (paths data.PathSet, srcAmount *data.Amount, err error) {
+	res, err := b.RipplePathFind(from, to, amount)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range res.Alternatives {
+		alt := &res.Alternatives[i]
+		if alt.SrcAmount.Value == nil || alt.SrcAmount.IsNative() || alt.SrcAmount.Currency != amount.Currency {
+			continue
+		}
+		if srcAmount == nil || alt.SrcAmount.Value.Less(*srcAmount.Value) {
+			paths, srcAmount = alt.PathsComputed, &alt.SrcAmount
+		}
+	}
+	if srcAmount == nil {
+		return nil, nil, fmt.Errorf("%w %v", tokens.ErrBuildTxErrorAndDelay, "no payment path found")
+	}
+	return paths, srcAmount, nil
+}
+
+// getPaymentPathAndSendMax get paths and SendMax of delivering issued currency
+func (b *Bridge) getPaymentPathAndSendMax(from, to string, amount *data.Amount, cfg *params.PathFindConfig) (*data.PathSet, *data.Amount, error) {
+	paths, srcAmount, err := b.findPaymentPath(from, to, amount)
+	if err != nil {
+		log.Warn("find payment path failed", "from", from, "to", to, "amount", amount, "err", err)
+		return nil, nil, err
+	}
+	slippage := cfg.GetSlippagePercent()
+	factor, err := data.NewValue(strconv.FormatFloat(1+slippage/100, 'f', -1, 64), false)
+	if err != nil {
+		return nil, nil, err
+	}
+	sendMaxValue, err := srcAmount.Value.Multiply(*factor)
+	if err != nil {
+		return nil, nil, err
+	}
+	sendMax := &data.Amount{
+		Value:    sendMaxValue,
+		Currency: amount.Currency,
+		Issuer:   amount.Issuer,
+	}
+	log.Info("find payment path success", "from", from, "to", to, "amount", amount, "srcAmount", srcAmount, "sendMax", sendMax, "slippage", slippage, "paths", len(paths))
+	if len(paths) == 0 {
+		return nil, sendMax, nil
+	}
+	return &paths, sendMax, nil
+}
+
+// checkPaymentPathQuality check the cheapest source amount of delivering
+// does not exceed SendMax as order books may move after building tx.
+func (b *Bridge) checkPaymentPathQuality(payment *data.Payment) error {
+	if payment.SendMax == nil || payment.Amount.IsNative() {
+		return nil
+	}
+	_, srcAmount, err := b.findPaymentPath(payment.Account.String(), payment.Destination.String(), &payment.Amount)
+	if err != nil {
+		return err
+	}
+	if payment.SendMax.Value.Less(*srcAmount.Value) {
+		log.Warn("payment path quality changed", "amount", payment.Amount, "srcAmount", srcAmount, "sendMax", payment.SendMax)
+		return fmt.Errorf("%w path quality changed, need %v but sendmax is %v", tokens.ErrBuildTxErrorAndDelay, srcAmount, payment.SendMax)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package ripple
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func newTestPathFindBridge(alternatives *string) (*Bridge, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"result":{"alternatives":` + *alternatives + `,"destination_account":"` + testCheckAccount + `"}}`))
+	}))
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{server.URL}})
+	return b, server.Close
+}
+
+func newTestIOUAmount(t *testing.T, value string) *data.Amount {
+	amount, err := data.NewAmount(value + "/USD/" + testCheckIssuer)
+	if err != nil {
+		t.Fatalf("new amount failed: %v", err)
+	}
+	return amount
+}
+
+func TestGetPaymentPathAndSendMax(t *testing.T) {
+	alternatives := `[
+		{"source_amount":{"currency":"USD","issuer":"` + testCheckIssuer + `","value":"101"},"paths_computed":[[{"account":"` + testCheckIssuer + `"}]]},
+		{"source_amount":{"currency":"USD","issuer":"` + testCheckIssuer + `","value":"100.5"},"paths_computed":[[{"account":"` + testCheckIssuer + `"}],[{"account":"` + testCheckAccount + `"}]]},
+		{"source_amount":"1000000"}
+	]`
+	b, closeServer := newTestPathFindBridge(&alternatives)
+	defer closeServer()
+
+	amount := newTestIOUAmount(t, "100")
+	paths, sendMax, err := b.getPaymentPathAndSendMax(testCheckAccount, testCheckAccount, amount, &params.PathFindConfig{SlippagePercent: 2})
+	if err != nil {
+		t.Fatalf("get payment path failed: %v", err)
+	}
+	// the cheapest paths in the same currency are used, native alternatives are ignored
+	if paths == nil || len(*paths) != 2 {
+		t.Errorf("get payment path got paths %v, want the 2 paths of the cheapest alternative", paths)
+	}
+	if want := newTestIOUAmount(t, "102.51"); !sendMax.Equals(*want) {
+		t.Errorf("get payment path got send max %v, want %v", sendMax, want)
+	}
+
+	// the path quality is checked again before signing
+	payment := &data.Payment{Amount: *amount, SendMax: sendMax}
+	if err = b.checkPaymentPathQuality(payment); err != nil {
+		t.Errorf("check unchanged path quality failed: %v", err)
+	}
+	payment.SendMax = newTestIOUAmount(t, "100.4")
+	if err = b.checkPaymentPathQuality(payment); !errors.Is(err, tokens.ErrBuildTxErrorAndDelay) {
+		t.Errorf("check changed path quality got error %v, want %v", err, tokens.ErrBuildTxErrorAndDelay)
+	}
+}
+
+func TestFindPaymentPathNotFound(t *testing.T) {
+	alternatives := `[{"source_amount":"1000000"}]`
+	b, closeServer := newTestPathFindBridge(&alternatives)
+	defer closeServer()
+
+	amount := newTestIOUAmount(t, "100")
+	if _, _, err := b.findPaymentPath(testCheckAccount, testCheckAccount, amount); !errors.Is(err, tokens.ErrBuildTxErrorAndDelay) {
+		t.Errorf("find payment path without alternatives got error %v, want %v", err, tokens.ErrBuildTxErrorAndDelay)
+	}
+	alternatives = `[]`
+	if _, _, err := b.getPaymentPathAndSendMax(testCheckAccount, testCheckAccount, amount, &params.PathFindConfig{}); !errors.Is(err, tokens.ErrBuildTxErrorAndDelay) {
+		t.Errorf("get payment path without alternatives got error %v, want %v", err, tokens.ErrBuildTxErrorAndDelay)
+	}
+}
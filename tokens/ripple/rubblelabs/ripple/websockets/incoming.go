@@ -0,0 +1,695 @@
+package websockets
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+// OverflowPolicy policy of handling stream messages when the Incoming channel is full
+type OverflowPolicy int
+
+// overflow policies
+const (
+	// OverflowBlock block the run loop until consumers catch up, and alarm if blocked too long
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest drop the oldest queued message to make room for the new one
+	OverflowDropOldest
+	// OverflowSpill spill messages to disk and replay them in order when consumers catch up
+	OverflowSpill
+)
+
+const (
+	// Capacity of the Incoming channel.
+	incomingBufferSize = 1000
+
+	// Alarm interval when the run loop is blocked by slow consumers.
+	blockAlarmInterval = 10 * time.Second
+
+	// Default duration to wait for acknowledgment before redelivering a message.
+	defaultAckTimeout = 30 * time.Second
+
+	// Interval of checking acknowledgment timeouts.
+	ackCheckInterval = time.Second
+
+	// Alarm every time unacknowledged messages grow by this count.
+	unackedAlarmStep = 1000
+)
+
+var (
+	errAckEnabled  = errors.New("acknowledgment is already enabled")
+	errSpillClosed = errors.New("spill file is closed")
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropOldest:
+		return "drop-oldest"
+	case OverflowSpill:
+		return "spill"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseOverflowPolicy parse overflow policy from its name, empty means OverflowBlock
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	switch name {
+	case "", "block":
+		return OverflowBlock, nil
+	case "drop-oldest":
+		return OverflowDropOldest, nil
+	case "spill":
+		return OverflowSpill, nil
+	default:
+		return OverflowBlock, fmt.Errorf("unknown overflow policy '%v'", name)
+	}
+}
+
+// AckFilter selects critical stream messages which require acknowledgment
+type AckFilter func(msg interface{}) bool
+
+// ValidatedTransactions ack filter of validated transaction stream messages
+func ValidatedTransactions(msg interface{}) bool {
+	tx, ok := msg.(*TransactionStreamMsg)
+	return ok && tx.Validated
+}
+
+// Delivery critical stream message which is redelivered until acknowledged
+type Delivery struct {
+	Seq      uint64
+	Msg      interface{}
+	Received time.Time
+	Attempts int
+}
+
+// IncomingStats consumer lag metrics of the Incoming stream
+type IncomingStats struct {
+	Policy        OverflowPolicy
+	Capacity      int
+	Queued        int           // messages in the Incoming channel
+	SpillPending  uint64        // messages spilled to disk and not replayed yet
+	Lag           uint64        // messages received but not consumed yet
+	Received      uint64        // stream messages received since connected
+	Dropped       uint64        // messages dropped by drop-oldest policy or spill failures
+	Spilled       uint64        // messages spilled to disk
+	Blocked       uint64        // times the run loop is blocked by slow consumers
+	BlockedTime   time.Duration // total time the run loop is blocked
+	Alarms        uint64
+	Unacked       int
+	Redelivered   uint64
+	OldestUnacked time.Duration // age of the oldest unacknowledged message
+}
+
+// incomingQueue dispatches stream messages to consumers
+type incomingQueue struct {
+	out    chan interface{}
+	policy OverflowPolicy
+	spill  *spillQueue
+	acks   *ackQueue
+	closed bool
+	lock   sync.RWMutex // protects policy, spill, acks and closed
+
+	received    uint64
+	dropped     uint64
+	spilled     uint64
+	blocked     uint64
+	blockedTime uint64
+	alarms      uint64
+
+	spillNotify chan struct{}
+	quit        chan struct{}
+	wg          sync.WaitGroup
+}
+
+func newIncomingQueue(out chan interface{}) *incomingQueue {
+	q := &incomingQueue{
+		out:         out,
+		policy:      OverflowBlock,
+		spillNotify: make(chan struct{}, 1),
+		quit:        make(chan struct{}),
+	}
+	q.wg.Add(1)
+	go q.replaySpilled()
+	return q
+}
+
+// deliver is called by the run loop only
+func (q *incomingQueue) deliver(raw []byte, msg interface{}) {
+	atomic.AddUint64(&q.received, 1)
+
+	q.lock.RLock()
+	policy, spill, acks := q.policy, q.spill, q.acks
+	q.lock.RUnlock()
+
+	if acks != nil && acks.filter(msg) {
+		acks.add(msg)
+		return
+	}
+	switch policy {
+	case OverflowDropOldest:
+		q.dropOldest(msg)
+	case OverflowSpill:
+		q.spillOrSend(spill, raw, msg)
+	default:
+		q.sendOrBlock(msg)
+	}
+}
+
+func (q *incomingQueue) sendOrBlock(msg interface{}) {
+	select {
+	case q.out <- msg:
+		return
+	default:
+	}
+	atomic.AddUint64(&q.blocked, 1)
+	start := time.Now()
+	alarm := time.NewTicker(blockAlarmInterval)
+	defer func() {
+		alarm.Stop()
+		atomic.AddUint64(&q.blockedTime, uint64(time.Since(start)))
+	}()
+	for {
+		select {
+		case q.out <- msg:
+			return
+		case <-alarm.C:
+			atomic.AddUint64(&q.alarms, 1)
+			log.Warn("remote incoming stream is blocked by slow consumers", "blocked", time.Since(start).String(), "capacity", cap(q.out))
+		}
+	}
+}
+
+func (q *incomingQueue) dropOldest(msg interface{}) {
+	for {
+		select {
+		case q.out <- msg:
+			return
+		default:
+		}
+		select {
+		case <-q.out:
+			dropped := atomic.AddUint64(&q.dropped, 1)
+			if dropped == 1 || dropped%uint64(incomingBufferSize) == 0 {
+				log.Warn("remote incoming stream dropped oldest message", "dropped", dropped)
+			}
+		default:
+		}
+	}
+}
+
+// spillOrSend keeps messages in order by spilling all messages while there are pending spilled ones
+func (q *incomingQueue) spillOrSend(spill *spillQueue, raw []byte, msg interface{}) {
+	if spill.getPending() == 0 {
+		select {
+		case q.out <- msg:
+			return
+		default:
+		}
+	}
+	if err := spill.push(raw); err != nil {
+		log.Error("spill remote incoming message failed", "err", err)
+		q.sendOrBlock(msg)
+		return
+	}
+	atomic.AddUint64(&q.spilled, 1)
+	select {
+	case q.spillNotify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *incomingQueue) getSpill() *spillQueue {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+	return q.spill
+}
+
+// replaySpilled replay spilled messages to the Incoming channel in order
+func (q *incomingQueue) replaySpilled() {
+	defer q.wg.Done()
+	for {
+		select {
+		case <-q.quit:
+			return
+		case <-q.spillNotify:
+		}
+		for {
+			spill := q.getSpill()
+			if spill == nil {
+				break
+			}
+			raw, size, err := spill.peek()
+			if err != nil {
+				discarded := spill.reset()
+				atomic.AddUint64(&q.dropped, discarded)
+				log.Error("read spilled remote incoming message failed", "discarded", discarded, "err", err)
+				break
+			}
+			if raw == nil {
+				break
+			}
+			msg, err := decodeStreamMessage(raw)
+			if err != nil {
+				spill.commit(size)
+				atomic.AddUint64(&q.dropped, 1)
+				log.Error("decode spilled remote incoming message failed", "err", err)
+				continue
+			}
+			select {
+			case q.out <- msg:
+				spill.commit(size)
+			case <-q.quit:
+				return
+			}
+		}
+	}
+}
+
+// checkLag alarm if consumers are lagging behind
+func (q *incomingQueue) checkLag() {
+	var spillPending uint64
+	if spill := q.getSpill(); spill != nil {
+		spillPending = spill.getPending()
+	}
+	if queued := len(q.out); queued == cap(q.out) || spillPending > 0 {
+		log.Warn("remote incoming stream consumers are lagging", "queued", queued, "spillPending", spillPending)
+	}
+}
+
+func (q *incomingQueue) setPolicy(policy OverflowPolicy, spillDir string) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.closed {
+		return ErrNotConnected
+	}
+	if q.spill != nil {
+		if policy == OverflowSpill {
+			return nil
+		}
+		if pending := q.spill.getPending(); pending > 0 {
+			return fmt.Errorf("can not change overflow policy with %v spilled messages pending", pending)
+		}
+		q.spill.close()
+		q.spill = nil
+	}
+	if policy == OverflowSpill {
+		spill, err := newSpillQueue(spillDir)
+		if err != nil {
+			return err
+		}
+		q.spill = spill
+	}
+	log.Info("set remote incoming overflow policy", "from", q.policy, "to", policy)
+	q.policy = policy
+	return nil
+}
+
+func (q *incomingQueue) enableAck(filter AckFilter, timeout time.Duration) (<-chan Delivery, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.closed {
+		return nil, ErrNotConnected
+	}
+	if q.acks != nil {
+		return nil, errAckEnabled
+	}
+	if timeout <= 0 {
+		timeout = defaultAckTimeout
+	}
+	q.acks = newAckQueue(filter, timeout)
+	q.wg.Add(1)
+	go q.dispatchAcks(q.acks)
+	return q.acks.out, nil
+}
+
+func (q *incomingQueue) dispatchAcks(acks *ackQueue) {
+	defer q.wg.Done()
+	ticker := time.NewTicker(ackCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.quit:
+			return
+		case <-ticker.C:
+			acks.checkTimeouts()
+		case <-acks.notify:
+		}
+		for {
+			delivery, ok := acks.next()
+			if !ok {
+				break
+			}
+			select {
+			case acks.out <- delivery:
+				acks.markDelivered(delivery.Seq)
+			case <-q.quit:
+				return
+			}
+		}
+	}
+}
+
+func (q *incomingQueue) getAcks() *ackQueue {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+	return q.acks
+}
+
+func (q *incomingQueue) stats() *IncomingStats {
+	q.lock.RLock()
+	policy, spill, acks := q.policy, q.spill, q.acks
+	q.lock.RUnlock()
+
+	stats := &IncomingStats{
+		Policy:      policy,
+		Capacity:    cap(q.out),
+		Queued:      len(q.out),
+		Received:    atomic.LoadUint64(&q.received),
+		Dropped:     atomic.LoadUint64(&q.dropped),
+		Spilled:     atomic.LoadUint64(&q.spilled),
+		Blocked:     atomic.LoadUint64(&q.blocked),
+		BlockedTime: time.Duration(atomic.LoadUint64(&q.blockedTime)),
+		Alarms:      atomic.LoadUint64(&q.alarms),
+	}
+	if spill != nil {
+		stats.SpillPending = spill.getPending()
+	}
+	stats.Lag = uint64(stats.Queued) + stats.SpillPending
+	if acks != nil {
+		acks.fillStats(stats)
+	}
+	return stats
+}
+
+// close stops the dispatching goroutines and then closes the channels
+func (q *incomingQueue) close() {
+	q.lock.Lock()
+	q.closed = true
+	q.lock.Unlock()
+
+	close(q.quit)
+	q.wg.Wait()
+	close(q.out)
+	if q.acks != nil {
+		close(q.acks.out)
+	}
+	if q.spill != nil {
+		q.spill.close()
+	}
+}
+
+func decodeStreamMessage(raw []byte) (interface{}, error) {
+	var response Command
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, err
+	}
+	factory, ok := streamMessageFactory[response.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown stream message type '%v'", response.Type)
+	}
+	msg := factory()
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// spillQueue length prefixed messages in a temp file.
+// the file is truncated when all spilled messages are replayed.
+type spillQueue struct {
+	file     *os.File
+	readOff  int64
+	writeOff int64
+	pending  uint64
+	closed   bool
+	lock     sync.Mutex
+}
+
+func newSpillQueue(dir string) (*spillQueue, error) {
+	file, err := os.CreateTemp(dir, "ripple-incoming-*.spill")
+	if err != nil {
+		return nil, err
+	}
+	return &spillQueue{file: file}, nil
+}
+
+func (s *spillQueue) push(raw []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return errSpillClosed
+	}
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(raw)))
+	if _, err := s.file.WriteAt(header[:], s.writeOff); err != nil {
+		return err
+	}
+	if _, err := s.file.WriteAt(raw, s.writeOff+4); err != nil {
+		return err
+	}
+	s.writeOff += int64(4 + len(raw))
+	s.pending++
+	return nil
+}
+
+// peek reads the oldest spilled message without removing it
+func (s *spillQueue) peek() (raw []byte, size int64, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed || s.pending == 0 {
+		return nil, 0, nil
+	}
+	var header [4]byte
+	if _, err = s.file.ReadAt(header[:], s.readOff); err != nil {
+		return nil, 0, err
+	}
+	raw = make([]byte, binary.BigEndian.Uint32(header[:]))
+	if _, err = s.file.ReadAt(raw, s.readOff+4); err != nil {
+		return nil, 0, err
+	}
+	return raw, int64(4 + len(raw)), nil
+}
+
+// commit removes the peeked message
+func (s *spillQueue) commit(size int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed || s.pending == 0 {
+		return
+	}
+	s.readOff += size
+	s.pending--
+	if s.pending == 0 {
+		s.truncate()
+	}
+}
+
+// reset discards all spilled messages
+func (s *spillQueue) reset() (discarded uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	discarded = s.pending
+	s.pending = 0
+	if !s.closed {
+		s.truncate()
+	}
+	return discarded
+}
+
+// truncate must be called with lock held
+func (s *spillQueue) truncate() {
+	s.readOff, s.writeOff = 0, 0
+	if err := s.file.Truncate(0); err != nil {
+		log.Warn("truncate spill file failed", "file", s.file.Name(), "err", err)
+	}
+}
+
+func (s *spillQueue) getPending() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.pending
+}
+
+func (s *spillQueue) close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	_ = s.file.Close()
+	if err := os.Remove(s.file.Name()); err != nil {
+		log.Warn("remove spill file failed", "file", s.file.Name(), "err", err)
+	}
+}
+
+type unackedMsg struct {
+	delivery    Delivery
+	deliveredAt time.Time // zero if it is queued or being delivered
+	queued      bool
+}
+
+// ackQueue keeps critical messages until they are acknowledged
+type ackQueue struct {
+	filter      AckFilter
+	timeout     time.Duration
+	out         chan Delivery
+	notify      chan struct{}
+	nextSeq     uint64
+	unacked     map[uint64]*unackedMsg
+	ready       []uint64 // seqs waiting to be delivered in order
+	redelivered uint64
+	lock        sync.Mutex
+}
+
+func newAckQueue(filter AckFilter, timeout time.Duration) *ackQueue {
+	return &ackQueue{
+		filter:  filter,
+		timeout: timeout,
+		out:     make(chan Delivery), // unbuffered, so that delivered means received by consumer
+		notify:  make(chan struct{}, 1),
+		unacked: make(map[uint64]*unackedMsg),
+	}
+}
+
+func (a *ackQueue) add(msg interface{}) {
+	a.lock.Lock()
+	a.nextSeq++
+	seq := a.nextSeq
+	a.unacked[seq] = &unackedMsg{
+		delivery: Delivery{Seq: seq, Msg: msg, Received: time.Now()},
+		queued:   true,
+	}
+	a.ready = append(a.ready, seq)
+	unacked := len(a.unacked)
+	a.lock.Unlock()
+
+	if unacked%unackedAlarmStep == 0 {
+		log.Warn("remote incoming stream has too many unacknowledged messages", "unacked", unacked)
+	}
+	a.signal()
+}
+
+func (a *ackQueue) signal() {
+	select {
+	case a.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next pops the next message to be delivered
+func (a *ackQueue) next() (Delivery, bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for len(a.ready) > 0 {
+		seq := a.ready[0]
+		a.ready = a.ready[1:]
+		msg, exist := a.unacked[seq]
+		if !exist {
+			continue // acknowledged while waiting for redelivery
+		}
+		msg.queued = false
+		msg.delivery.Attempts++
+		return msg.delivery, true
+	}
+	return Delivery{}, false
+}
+
+func (a *ackQueue) markDelivered(seq uint64) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if msg, exist := a.unacked[seq]; exist {
+		msg.deliveredAt = time.Now()
+	}
+}
+
+// checkTimeouts requeue delivered messages which are not acknowledged in time
+func (a *ackQueue) checkTimeouts() {
+	a.lock.Lock()
+	now := time.Now()
+	var expired []uint64
+	for seq, msg := range a.unacked {
+		if msg.queued || msg.deliveredAt.IsZero() || now.Sub(msg.deliveredAt) < a.timeout {
+			continue
+		}
+		msg.queued = true
+		msg.deliveredAt = time.Time{}
+		expired = append(expired, seq)
+	}
+	if len(expired) > 0 {
+		sort.Slice(expired, func(i, j int) bool { return expired[i] < expired[j] })
+		a.ready = append(a.ready, expired...)
+		a.redelivered += uint64(len(expired))
+	}
+	a.lock.Unlock()
+
+	if len(expired) > 0 {
+		log.Warn("redeliver unacknowledged remote incoming messages", "count", len(expired), "timeout", a.timeout.String())
+		a.signal()
+	}
+}
+
+func (a *ackQueue) ack(seq uint64) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	_, exist := a.unacked[seq]
+	delete(a.unacked, seq)
+	return exist
+}
+
+func (a *ackQueue) fillStats(stats *IncomingStats) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	stats.Unacked = len(a.unacked)
+	stats.Redelivered = a.redelivered
+	var oldest time.Time
+	for _, msg := range a.unacked {
+		if oldest.IsZero() || msg.delivery.Received.Before(oldest) {
+			oldest = msg.delivery.Received
+		}
+	}
+	if !oldest.IsZero() {
+		stats.OldestUnacked = time.Since(oldest)
+	}
+}
+
+// SetOverflowPolicy set the policy of handling stream messages when the Incoming channel is full.
+// spillDir is the directory of spill file for OverflowSpill, empty means the default temp directory.
+// It should be called before subscribing streams, the default policy is OverflowBlock.
+func (r *Remote) SetOverflowPolicy(policy OverflowPolicy, spillDir string) error {
+	return r.incoming.setPolicy(policy, spillDir)
+}
+
+// EnableAck deliver critical stream messages selected by filter over the returned
+// channel instead of the Incoming channel. These messages are never dropped, and are
+// redelivered if not acknowledged by Ack within timeout (non positive means the default).
+// The returned channel is closed after the remote is closed.
+func (r *Remote) EnableAck(filter AckFilter, timeout time.Duration) (<-chan Delivery, error) {
+	return r.incoming.enableAck(filter, timeout)
+}
+
+// Ack acknowledge the delivered critical stream message,
+// returns false if it is unknown or has been acknowledged already.
+func (r *Remote) Ack(seq uint64) bool {
+	acks := r.incoming.getAcks()
+	if acks == nil {
+		return false
+	}
+	return acks.ack(seq)
+}
+
+// IncomingStats returns the consumer lag metrics of the Incoming stream
+func (r *Remote) IncomingStats() *IncomingStats {
+	return r.incoming.stats()
+}
@@ -0,0 +1,191 @@
+package websockets
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func newTestLedgerMsg(t *testing.T, index uint32) (raw []byte, msg *LedgerStreamMsg) {
+	raw, err := json.Marshal(map[string]interface{}{"type": "ledgerClosed", "ledger_index": index})
+	if err != nil {
+		t.Fatalf("marshal ledger message failed: %v", err)
+	}
+	return raw, &LedgerStreamMsg{LedgerSequence: index}
+}
+
+func nextLedgerIndex(t *testing.T, out <-chan interface{}) uint32 {
+	select {
+	case msg := <-out:
+		ledger, ok := msg.(*LedgerStreamMsg)
+		if !ok {
+			t.Fatalf("got unexpected message %#v", msg)
+		}
+		return ledger.LedgerSequence
+	case <-time.After(testWaitTimeout):
+		t.Fatalf("wait incoming message timeout")
+	}
+	return 0
+}
+
+func nextDelivery(t *testing.T, deliveries <-chan Delivery) Delivery {
+	select {
+	case delivery := <-deliveries:
+		return delivery
+	case <-time.After(testWaitTimeout):
+		t.Fatalf("wait delivery timeout")
+	}
+	return Delivery{}
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	for _, policy := range []OverflowPolicy{OverflowBlock, OverflowDropOldest, OverflowSpill} {
+		if parsed, err := ParseOverflowPolicy(policy.String()); err != nil || parsed != policy {
+			t.Errorf("parse overflow policy %v got (%v, %v)", policy, parsed, err)
+		}
+	}
+	if parsed, err := ParseOverflowPolicy(""); err != nil || parsed != OverflowBlock {
+		t.Errorf("parse empty overflow policy got (%v, %v), want block", parsed, err)
+	}
+	if _, err := ParseOverflowPolicy("unknown"); err == nil {
+		t.Errorf("parse unknown overflow policy should fail")
+	}
+}
+
+func TestIncomingDropOldest(t *testing.T) {
+	out := make(chan interface{}, 2)
+	q := newIncomingQueue(out)
+	if err := q.setPolicy(OverflowDropOldest, ""); err != nil {
+		t.Fatalf("set overflow policy failed: %v", err)
+	}
+	for i := uint32(1); i <= 3; i++ {
+		raw, msg := newTestLedgerMsg(t, i)
+		q.deliver(raw, msg)
+	}
+	stats := q.stats()
+	if stats.Received != 3 || stats.Dropped != 1 || stats.Lag != 2 {
+		t.Errorf("stats got %+v, want 3 received, 1 dropped and lag 2", stats)
+	}
+	if first, second := nextLedgerIndex(t, out), nextLedgerIndex(t, out); first != 2 || second != 3 {
+		t.Errorf("got ledgers %v and %v, want the oldest ledger dropped", first, second)
+	}
+	q.close()
+}
+
+func TestIncomingSpill(t *testing.T) {
+	out := make(chan interface{}, 1)
+	q := newIncomingQueue(out)
+	if err := q.setPolicy(OverflowSpill, t.TempDir()); err != nil {
+		t.Fatalf("set overflow policy failed: %v", err)
+	}
+	for i := uint32(1); i <= 3; i++ {
+		raw, msg := newTestLedgerMsg(t, i)
+		q.deliver(raw, msg)
+	}
+	if stats := q.stats(); stats.Spilled != 2 || stats.Dropped != 0 {
+		t.Errorf("stats got %+v, want 2 spilled", stats)
+	}
+	// spilled messages are replayed in order
+	for i := uint32(1); i <= 3; i++ {
+		if index := nextLedgerIndex(t, out); index != i {
+			t.Errorf("got ledger %v, want %v", index, i)
+		}
+	}
+	if err := q.setPolicy(OverflowBlock, ""); err != nil {
+		t.Errorf("change overflow policy after replayed got error %v", err)
+	}
+	q.close()
+}
+
+func TestIncomingAck(t *testing.T) {
+	out := make(chan interface{}, 10)
+	q := newIncomingQueue(out)
+	deliveries, err := q.enableAck(ValidatedTransactions, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("enable ack failed: %v", err)
+	}
+	if _, err = q.enableAck(ValidatedTransactions, 0); err != errAckEnabled {
+		t.Errorf("enable ack again got error %v, want %v", err, errAckEnabled)
+	}
+
+	q.deliver(nil, &TransactionStreamMsg{Validated: false})
+	q.deliver(nil, &TransactionStreamMsg{Validated: true})
+	select {
+	case msg := <-out:
+		if tx, ok := msg.(*TransactionStreamMsg); !ok || tx.Validated {
+			t.Errorf("got unexpected incoming message %#v", msg)
+		}
+	case <-time.After(testWaitTimeout):
+		t.Fatalf("unvalidated tx is not sent to the incoming channel")
+	}
+
+	delivery := nextDelivery(t, deliveries)
+	if delivery.Attempts != 1 {
+		t.Errorf("first delivery got %v attempts", delivery.Attempts)
+	}
+	// redelivered if not acknowledged in time
+	redelivery := nextDelivery(t, deliveries)
+	if redelivery.Seq != delivery.Seq || redelivery.Attempts != 2 {
+		t.Errorf("redelivery got seq %v attempts %v, want seq %v attempts 2", redelivery.Seq, redelivery.Attempts, delivery.Seq)
+	}
+	if stats := q.stats(); stats.Unacked != 1 || stats.Redelivered != 1 {
+		t.Errorf("stats got %+v, want 1 unacked and 1 redelivered", stats)
+	}
+	acks := q.getAcks()
+	if !acks.ack(delivery.Seq) {
+		t.Errorf("ack delivered message failed")
+	}
+	if acks.ack(delivery.Seq) {
+		t.Errorf("ack message twice should fail")
+	}
+	if stats := q.stats(); stats.Unacked != 0 {
+		t.Errorf("stats got %v unacked after ack", stats.Unacked)
+	}
+	q.close()
+}
+
+func TestRemoteSubscribeAccounts(t *testing.T) {
+	m := newMockRippled(t)
+	m.handleResult("subscribe", map[string]interface{}{})
+	r := newTestRemote(t, m)
+	defer r.Close()
+
+	isLedger := func(msg interface{}) bool {
+		_, ok := msg.(*LedgerStreamMsg)
+		return ok
+	}
+	deliveries, err := r.EnableAck(isLedger, 0)
+	if err != nil {
+		t.Fatalf("enable ack failed: %v", err)
+	}
+
+	const address = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	account, err := data.NewAccountFromAddress(address)
+	if err != nil {
+		t.Fatalf("new account failed: %v", err)
+	}
+	if _, err = r.SubscribeAccounts([]data.Account{*account}); err != nil {
+		t.Fatalf("subscribe accounts failed: %v", err)
+	}
+	req := m.waitRequests(1)[0]
+	if accounts, ok := req["accounts"].([]interface{}); !ok || len(accounts) != 1 || accounts[0] != address {
+		t.Errorf("subscribe request got accounts %v, want [%v]", req["accounts"], address)
+	}
+	if _, exist := req["streams"]; exist {
+		t.Errorf("subscribe accounts request should not have streams")
+	}
+
+	m.send(map[string]interface{}{"type": "ledgerClosed", "ledger_index": 300})
+	delivery := nextDelivery(t, deliveries)
+	if ledger, ok := delivery.Msg.(*LedgerStreamMsg); !ok || ledger.LedgerSequence != 300 {
+		t.Fatalf("got unexpected delivery %#v", delivery.Msg)
+	}
+	if !r.Ack(delivery.Seq) {
+		t.Errorf("ack delivery failed")
+	}
+	if stats := r.IncomingStats(); stats.Received != 1 || stats.Unacked != 0 {
+		t.Errorf("stats got %+v, want 1 received and 0 unacked", stats)
+	}
+}
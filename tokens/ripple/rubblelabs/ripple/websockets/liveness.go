@@ -0,0 +1,155 @@
+package websockets
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+// ConnState connection state of remote
+type ConnState int
+
+// connection states
+const (
+	// StateConnected data is flowing normally
+	StateConnected ConnState = iota
+	// StateStale pings succeed but no data is received in the idle timeout (half-open connection)
+	StateStale
+	// StateClosed connection is closed
+	StateClosed
+)
+
+const (
+	// Interval of checking liveness of the connection.
+	livenessCheckInterval = 5 * time.Second
+
+	// Capacity of the state change channel, changes are dropped if consumers are slow.
+	stateChangesBufferSize = 8
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case StateConnected:
+		return "connected"
+	case StateStale:
+		return "stale"
+	case StateClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// Liveness liveness info of remote
+type Liveness struct {
+	State        ConnState
+	LastActivity time.Time // time of last received data message
+	LastPong     time.Time // time of last received pong
+	Idle         time.Duration
+}
+
+// liveness tracks the activity of the connection
+type liveness struct {
+	lastActivity time.Time
+	lastPong     time.Time
+	idleTimeout  time.Duration // zero means stale detection is disabled
+	state        ConnState
+	changes      chan ConnState
+	lock         sync.RWMutex
+}
+
+func newLiveness() *liveness {
+	now := time.Now()
+	return &liveness{
+		lastActivity: now,
+		lastPong:     now,
+		state:        StateConnected,
+		changes:      make(chan ConnState, stateChangesBufferSize),
+	}
+}
+
+func (l *liveness) onActivity() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.lastActivity = time.Now()
+	if l.state == StateStale {
+		l.setState(StateConnected)
+	}
+}
+
+func (l *liveness) onPong() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.lastPong = time.Now()
+}
+
+// check detects half-open connection where pongs are still received but data stops flowing
+func (l *liveness) check() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.state != StateConnected || l.idleTimeout <= 0 {
+		return
+	}
+	if time.Since(l.lastActivity) > l.idleTimeout {
+		l.setState(StateStale)
+	}
+}
+
+func (l *liveness) close() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.state == StateClosed {
+		return
+	}
+	l.setState(StateClosed)
+	close(l.changes)
+}
+
+// setState must be called with lock held
+func (l *liveness) setState(state ConnState) {
+	if l.state == state {
+		return
+	}
+	log.Info("remote connection state changed", "from", l.state, "to", state, "lastActivity", l.lastActivity, "lastPong", l.lastPong)
+	l.state = state
+	select {
+	case l.changes <- state:
+	default:
+		log.Warn("remote connection state change is dropped", "state", state)
+	}
+}
+
+// LastActivity returns the time of the last received data message
+func (r *Remote) LastActivity() time.Time {
+	r.liveness.lock.RLock()
+	defer r.liveness.lock.RUnlock()
+	return r.liveness.lastActivity
+}
+
+// Liveness returns the liveness info of the connection
+func (r *Remote) Liveness() *Liveness {
+	r.liveness.lock.RLock()
+	defer r.liveness.lock.RUnlock()
+	return &Liveness{
+		State:        r.liveness.state,
+		LastActivity: r.liveness.lastActivity,
+		LastPong:     r.liveness.lastPong,
+		Idle:         time.Since(r.liveness.lastActivity),
+	}
+}
+
+// StateChanges returns a channel signaling connection state changes.
+// The channel is closed after the StateClosed state is sent.
+func (r *Remote) StateChanges() <-chan ConnState {
+	return r.liveness.changes
+}
+
+// SetIdleTimeout set the max duration without receiving data messages
+// before the connection is considered stale. Zero disables the detection.
+// Subscribers of ledger stream are expected to receive data every few seconds.
+func (r *Remote) SetIdleTimeout(timeout time.Duration) {
+	r.liveness.lock.Lock()
+	defer r.liveness.lock.Unlock()
+	r.liveness.idleTimeout = timeout
+}
@@ -0,0 +1,234 @@
+package websockets
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// mockHandler returns the messages replied to the request in order,
+// returns nil to hold the response (reply it later with send).
+type mockHandler func(req map[string]interface{}) []interface{}
+
+// mockRippled a scripted rippled websocket server for testing Remote.
+// command responses are scripted by handlers of command names, stream
+// messages and raw frames are sent to all connections on demand.
+type mockRippled struct {
+	t        *testing.T
+	server   *httptest.Server
+	upgrader websocket.Upgrader
+
+	lock     sync.Mutex
+	cond     *sync.Cond
+	handlers map[string]mockHandler
+	conns    map[*websocket.Conn]*sync.Mutex // connection -> write lock
+	requests []map[string]interface{}
+	connects int
+}
+
+func newMockRippled(t *testing.T) *mockRippled {
+	m := &mockRippled{
+		t:        t,
+		handlers: make(map[string]mockHandler),
+		conns:    make(map[*websocket.Conn]*sync.Mutex),
+	}
+	m.cond = sync.NewCond(&m.lock)
+	m.server = httptest.NewServer(http.HandlerFunc(m.serveWS))
+	t.Cleanup(m.close)
+	return m
+}
+
+// url websocket endpoint of the server
+func (m *mockRippled) url() string {
+	return "ws" + strings.TrimPrefix(m.server.URL, "http")
+}
+
+func (m *mockRippled) close() {
+	m.dropConns()
+	m.server.Close()
+}
+
+// handle script the responses of command
+func (m *mockRippled) handle(command string, handler mockHandler) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.handlers[command] = handler
+}
+
+// handleResult script the success result of command
+func (m *mockRippled) handleResult(command string, result interface{}) {
+	m.handle(command, func(req map[string]interface{}) []interface{} {
+		return []interface{}{mockResult(req, result)}
+	})
+}
+
+func mockResult(req map[string]interface{}, result interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"id":     req["id"],
+		"status": "success",
+		"type":   "response",
+		"result": result,
+	}
+}
+
+func mockError(req map[string]interface{}, name string, code int, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            req["id"],
+		"status":        "error",
+		"type":          "response",
+		"error":         name,
+		"error_code":    code,
+		"error_message": message,
+		"request":       req,
+	}
+}
+
+// send json messages to all connections
+func (m *mockRippled) send(msgs ...interface{}) {
+	for _, msg := range msgs {
+		b, err := json.Marshal(msg)
+		if err != nil {
+			m.t.Fatalf("mock rippled marshal message failed: %v", err)
+		}
+		m.sendRaw(websocket.TextMessage, b)
+	}
+}
+
+// sendRaw send frame to all connections
+func (m *mockRippled) sendRaw(messageType int, b []byte) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for conn, writeLock := range m.conns {
+		writeLock.Lock()
+		err := conn.WriteMessage(messageType, b)
+		writeLock.Unlock()
+		if err != nil {
+			m.t.Logf("mock rippled write message failed: %v", err)
+		}
+	}
+}
+
+// dropConns close all connections without close handshake
+func (m *mockRippled) dropConns() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for conn := range m.conns {
+		_ = conn.Close()
+		delete(m.conns, conn)
+	}
+	m.cond.Broadcast()
+}
+
+// waitRequests wait until count requests are received and return them
+func (m *mockRippled) waitRequests(count int) []map[string]interface{} {
+	timer := time.AfterFunc(5*time.Second, func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		m.cond.Broadcast()
+	})
+	defer timer.Stop()
+	deadline := time.Now().Add(5 * time.Second)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for len(m.requests) < count {
+		if time.Now().After(deadline) {
+			m.t.Fatalf("mock rippled wait requests timeout, got %v, want %v", len(m.requests), count)
+		}
+		m.cond.Wait()
+	}
+	return append([]map[string]interface{}{}, m.requests[:count]...)
+}
+
+// waitConnected wait until a connection is accepted,
+// stream messages sent before it are lost.
+func (m *mockRippled) waitConnected(t *testing.T) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		m.lock.Lock()
+		connected := len(m.conns) > 0
+		m.lock.Unlock()
+		if connected {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("mock rippled wait connection timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// getConnects get the number of accepted connections
+func (m *mockRippled) getConnects() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.connects
+}
+
+func (m *mockRippled) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := m.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		m.t.Logf("mock rippled upgrade failed: %v", err)
+		return
+	}
+	writeLock := new(sync.Mutex)
+	m.lock.Lock()
+	m.conns[conn] = writeLock
+	m.connects++
+	m.lock.Unlock()
+
+	defer func() {
+		m.lock.Lock()
+		delete(m.conns, conn)
+		m.lock.Unlock()
+		_ = conn.Close()
+	}()
+
+	for {
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if messageType != websocket.TextMessage {
+			continue
+		}
+		var req map[string]interface{}
+		if err = json.Unmarshal(message, &req); err != nil {
+			m.t.Logf("mock rippled unmarshal request failed: %v", err)
+			continue
+		}
+
+		m.lock.Lock()
+		m.requests = append(m.requests, req)
+		m.cond.Broadcast()
+		command, _ := req["command"].(string)
+		handler := m.handlers[command]
+		m.lock.Unlock()
+
+		var replies []interface{}
+		if handler != nil {
+			replies = handler(req)
+		} else {
+			replies = []interface{}{mockError(req, "unknownCmd", 32, "Unknown method.")}
+		}
+		for _, reply := range replies {
+			b, err := json.Marshal(reply)
+			if err != nil {
+				m.t.Errorf("mock rippled marshal reply failed: %v", err)
+				continue
+			}
+			writeLock.Lock()
+			err = conn.WriteMessage(websocket.TextMessage, b)
+			writeLock.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
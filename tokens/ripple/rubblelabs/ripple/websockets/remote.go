@@ -6,12 +6,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/url"
 	"reflect"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -32,15 +34,43 @@ const (
 
 	// Time allowed to connect to server.
 	dialTimeout = 5 * time.Second
+
+	// Default maximum size in bytes of a message read from the peer.
+	defaultMaxMessageSize = 16 * 1024 * 1024
+
+	// Close the connection after receiving this many consecutive malformed frames.
+	maxMalformedFrames = 10
 )
 
-// ErrNotConnected not connected
-var ErrNotConnected = errors.New("websocket not connected")
+var (
+	// ErrNotConnected not connected
+	ErrNotConnected = errors.New("websocket not connected")
+
+	// maximum size in bytes of a message read from the peer,
+	// the connection is closed if the peer sends a larger message.
+	maxMessageSize int64 = defaultMaxMessageSize
+)
+
+// SetMaxMessageSize set the maximum size in bytes of a message read from the peer.
+// It applies to remotes created afterwards, non positive size means the default.
+func SetMaxMessageSize(size int64) {
+	if size <= 0 {
+		size = defaultMaxMessageSize
+	}
+	atomic.StoreInt64(&maxMessageSize, size)
+}
+
+// GetMaxMessageSize get the maximum size in bytes of a message read from the peer
+func GetMaxMessageSize() int64 {
+	return atomic.LoadInt64(&maxMessageSize)
+}
 
 type Remote struct {
 	Incoming chan interface{}
 	outgoing chan Syncer
 	ws       *websocket.Conn
+	liveness *liveness
+	incoming *incomingQueue
 }
 
 // NewRemote returns a new remote session connected to the specified
@@ -59,10 +89,15 @@ func NewRemote(endpoint string) (*Remote, error) {
 	if err != nil {
 		return nil, err
 	}
+	// protect against peers streaming giant frames into memory
+	ws.SetReadLimit(GetMaxMessageSize())
+	incoming := make(chan interface{}, incomingBufferSize)
 	r := &Remote{
-		Incoming: make(chan interface{}, 1000),
+		Incoming: incoming,
 		outgoing: make(chan Syncer, 10),
 		ws:       ws,
+		liveness: newLiveness(),
+		incoming: newIncomingQueue(incoming),
 	}
 
 	go r.run()
@@ -86,10 +121,13 @@ func (r *Remote) run() {
 	outbound := make(chan interface{})
 	inbound := make(chan []byte)
 	pending := make(map[uint64]Syncer)
+	livenessTicker := time.NewTicker(livenessCheckInterval)
 
 	defer func() {
+		livenessTicker.Stop()
+		r.liveness.close()
 		close(outbound) // Shuts down the writePump
-		close(r.Incoming)
+		r.incoming.close()
 
 		// Cancel all pending commands with an error
 		for _, c := range pending {
@@ -142,7 +180,7 @@ func (r *Remote) run() {
 					log.Error("json unmarshal command error", "err", err)
 					continue
 				}
-				r.Incoming <- cmd
+				r.incoming.deliver(in, cmd)
 				continue
 			}
 
@@ -158,6 +196,10 @@ func (r *Remote) run() {
 				continue
 			}
 			cmd.Done()
+
+		case <-livenessTicker.C:
+			r.liveness.check()
+			r.incoming.checkLag()
 		}
 	}
 }
@@ -523,6 +565,21 @@ func (r *Remote) SubscribeOrderBooks(books []OrderBookSubscription) (*SubscribeR
 	return cmd.Result, nil
 }
 
+// SubscribeAccounts subscribe to validated transactions affecting the accounts,
+// the transactions are received asynchronously over the Incoming channel
+func (r *Remote) SubscribeAccounts(accounts []data.Account) (*SubscribeResult, error) {
+	cmd := &SubscribeCommand{
+		Command:  newCommand("subscribe"),
+		Accounts: accounts,
+	}
+	r.outgoing <- cmd
+	<-cmd.Ready
+	if cmd.CommandError != nil {
+		return nil, cmd.CommandError
+	}
+	return cmd.Result, nil
+}
+
 func (r *Remote) Fee() (*FeeResult, error) {
 	cmd := &FeeCommand{
 		Command: newCommand("fee"),
@@ -539,21 +596,73 @@ func (r *Remote) Fee() (*FeeResult, error) {
 // Expects to receive PONGs at specified interval, or logs an error and returns.
 func (r *Remote) readPump(inbound chan<- []byte) {
 	r.ws.SetReadDeadline(time.Now().Add(pongWait))
-	r.ws.SetPongHandler(func(string) error { r.ws.SetReadDeadline(time.Now().Add(pongWait)); return nil })
+	r.ws.SetPongHandler(func(string) error {
+		r.liveness.onPong()
+		r.ws.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+	malformed := 0
 	for {
-		_, message, err := r.ws.ReadMessage()
+		message, err := r.readTextMessage()
 		if err != nil {
-			log.Error("ws read message error", "remote", r.ws.RemoteAddr(), "err", err)
-			return
+			var frameErr *malformedFrameError
+			if !errors.As(err, &frameErr) {
+				log.Error("ws read message error", "remote", r.ws.RemoteAddr(), "maxMessageSize", GetMaxMessageSize(), "err", err)
+				return
+			}
+			malformed++
+			log.Warn("ws drop malformed frame", "remote", r.ws.RemoteAddr(), "count", malformed, "err", err)
+			if malformed >= maxMalformedFrames {
+				log.Error("ws close connection for too many malformed frames", "remote", r.ws.RemoteAddr(), "count", malformed)
+				return
+			}
+			r.ws.SetReadDeadline(time.Now().Add(pongWait))
+			continue
 		}
+		malformed = 0
 		if params.IsDebugMode() {
 			log.Info("ws read message", "message", dump(message))
 		}
+		r.liveness.onActivity()
 		r.ws.SetReadDeadline(time.Now().Add(pongWait))
 		inbound <- message
 	}
 }
 
+// malformedFrameError a frame which is dropped without closing the connection
+type malformedFrameError struct {
+	reason string
+}
+
+func (e *malformedFrameError) Error() string {
+	return "malformed frame: " + e.reason
+}
+
+// readTextMessage reads the next message, which must be a text frame holding a json object.
+// Binary frames are drained without buffering and reported as malformed.
+func (r *Remote) readTextMessage() ([]byte, error) {
+	messageType, reader, err := r.ws.NextReader()
+	if err != nil {
+		return nil, err
+	}
+	if messageType != websocket.TextMessage {
+		n, err := io.Copy(io.Discard, reader)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &malformedFrameError{reason: fmt.Sprintf("unexpected message type %v of %v bytes", messageType, n)}
+	}
+	message, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	message = bytes.TrimSpace(message)
+	if len(message) == 0 || message[0] != '{' || !json.Valid(message) {
+		return nil, &malformedFrameError{reason: fmt.Sprintf("invalid json object of %v bytes", len(message))}
+	}
+	return message, nil
+}
+
 // Consumes from the outbound channel and sends them over the websocket.
 // Also sends PING messages at the specified interval.
 // Returns when outbound channel is closed, or an error is encountered.
@@ -0,0 +1,301 @@
+package websockets
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const testWaitTimeout = 5 * time.Second
+
+func newTestRemote(t *testing.T, m *mockRippled) *Remote {
+	r, err := NewRemote(m.url())
+	if err != nil {
+		t.Fatalf("new remote failed: %v", err)
+	}
+	return r
+}
+
+func nextIncoming(t *testing.T, r *Remote) (msg interface{}, ok bool) {
+	select {
+	case msg, ok = <-r.Incoming:
+		return msg, ok
+	case <-time.After(testWaitTimeout):
+		t.Fatalf("wait incoming message timeout")
+	}
+	return nil, false
+}
+
+func TestRemoteCommandResponses(t *testing.T) {
+	m := newMockRippled(t)
+	r := newTestRemote(t, m)
+	defer r.Close()
+
+	tests := []struct {
+		name      string
+		handler   mockHandler
+		wantSize  uint32
+		wantError string
+	}{
+		{
+			name: "success",
+			handler: func(req map[string]interface{}) []interface{} {
+				return []interface{}{mockResult(req, map[string]interface{}{"current_ledger_size": "42"})}
+			},
+			wantSize: 42,
+		},
+		{
+			name: "command error",
+			handler: func(req map[string]interface{}) []interface{} {
+				return []interface{}{mockError(req, "noNetwork", 17, "Not synced to the network.")}
+			},
+			wantError: "noNetwork 17",
+		},
+		{
+			name: "unexpected message before response",
+			handler: func(req map[string]interface{}) []interface{} {
+				return []interface{}{
+					map[string]interface{}{"id": 0, "status": "success", "type": "response"},
+					mockResult(req, map[string]interface{}{"current_ledger_size": "7"}),
+				}
+			},
+			wantSize: 7,
+		},
+		{
+			name:      "unknown command",
+			wantError: "unknownCmd 32",
+		},
+	}
+	for _, test := range tests {
+		m.handle("fee", test.handler) // nil handler is unknown command
+		res, err := r.Fee()
+		if test.wantError != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantError) {
+				t.Errorf("%v: got error %v, want %v", test.name, err, test.wantError)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: got error %v", test.name, err)
+			continue
+		}
+		if res.CurrentLedgerSize != test.wantSize {
+			t.Errorf("%v: got current ledger size %v, want %v", test.name, res.CurrentLedgerSize, test.wantSize)
+		}
+	}
+}
+
+func TestRemotePendingResponsesOutOfOrder(t *testing.T) {
+	m := newMockRippled(t)
+	m.handle("fee", func(map[string]interface{}) []interface{} { return nil }) // hold responses
+	r := newTestRemote(t, m)
+	defer r.Close()
+
+	const count = 3
+	results := make([]uint32, count)
+	errs := make([]error, count)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res, err := r.Fee()
+			if err == nil {
+				results[i] = res.CurrentLedgerSize
+			}
+			errs[i] = err
+		}(i)
+		m.waitRequests(i + 1) // keep requests in order of callers
+	}
+
+	// reply in reverse order, the size is the caller index plus 100
+	reqs := m.waitRequests(count)
+	for i := count - 1; i >= 0; i-- {
+		size := 100 + i
+		m.send(mockResult(reqs[i], map[string]interface{}{"current_ledger_size": strconv.Itoa(size)}))
+	}
+	wg.Wait()
+
+	for i := 0; i < count; i++ {
+		if errs[i] != nil {
+			t.Errorf("caller %v: got error %v", i, errs[i])
+		} else if results[i] != uint32(100+i) {
+			t.Errorf("caller %v: got current ledger size %v, want %v", i, results[i], 100+i)
+		}
+	}
+}
+
+func TestRemoteStreamRouting(t *testing.T) {
+	m := newMockRippled(t)
+	r := newTestRemote(t, m)
+	defer r.Close()
+	m.waitConnected(t)
+
+	tests := []struct {
+		name  string
+		msgs  []interface{}
+		check func(msg interface{}) bool
+	}{
+		{
+			name: "ledger closed",
+			msgs: []interface{}{map[string]interface{}{"type": "ledgerClosed", "ledger_index": 100}},
+			check: func(msg interface{}) bool {
+				ledger, ok := msg.(*LedgerStreamMsg)
+				return ok && ledger.LedgerSequence == 100
+			},
+		},
+		{
+			name: "server status",
+			msgs: []interface{}{map[string]interface{}{"type": "serverStatus", "server_status": "full", "load_base": 256, "load_factor": 256}},
+			check: func(msg interface{}) bool {
+				server, ok := msg.(*ServerStreamMsg)
+				return ok && server.Status == "full" && server.LoadFactor == 256
+			},
+		},
+		{
+			name: "unknown type and unmatched response are not routed",
+			msgs: []interface{}{
+				map[string]interface{}{"type": "unknownStream"},
+				map[string]interface{}{"id": 12345, "type": "response", "status": "success"},
+				map[string]interface{}{"type": "ledgerClosed", "ledger_index": 101},
+			},
+			check: func(msg interface{}) bool {
+				ledger, ok := msg.(*LedgerStreamMsg)
+				return ok && ledger.LedgerSequence == 101
+			},
+		},
+	}
+	for _, test := range tests {
+		m.send(test.msgs...)
+		msg, ok := nextIncoming(t, r)
+		if !ok {
+			t.Fatalf("%v: incoming channel is closed", test.name)
+		}
+		if !test.check(msg) {
+			t.Errorf("%v: got unexpected message %#v", test.name, msg)
+		}
+	}
+}
+
+func TestRemoteMalformedFrames(t *testing.T) {
+	m := newMockRippled(t)
+	r := newTestRemote(t, m)
+	defer r.Close()
+	m.waitConnected(t)
+
+	// malformed frames are dropped without closing the connection
+	m.sendRaw(websocket.BinaryMessage, []byte{0x01, 0x02})
+	m.sendRaw(websocket.TextMessage, []byte("not json"))
+	m.send(map[string]interface{}{"type": "ledgerClosed", "ledger_index": 200})
+	msg, ok := nextIncoming(t, r)
+	if ledger, isLedger := msg.(*LedgerStreamMsg); !ok || !isLedger || ledger.LedgerSequence != 200 {
+		t.Fatalf("got unexpected message %#v after malformed frames", msg)
+	}
+
+	// too many consecutive malformed frames close the connection
+	for i := 0; i < maxMalformedFrames; i++ {
+		m.sendRaw(websocket.TextMessage, []byte("[]"))
+	}
+	if msg, ok = nextIncoming(t, r); ok {
+		t.Fatalf("got message %#v, want connection closed", msg)
+	}
+}
+
+func TestRemoteMalformedFramesReset(t *testing.T) {
+	m := newMockRippled(t)
+	r := newTestRemote(t, m)
+	defer r.Close()
+	m.waitConnected(t)
+
+	// only consecutive malformed frames are counted
+	for round := 0; round < 3; round++ {
+		for i := 0; i < maxMalformedFrames-1; i++ {
+			m.sendRaw(websocket.TextMessage, []byte("[]"))
+		}
+		m.send(map[string]interface{}{"type": "ledgerClosed", "ledger_index": 300 + round})
+		msg, ok := nextIncoming(t, r)
+		if ledger, isLedger := msg.(*LedgerStreamMsg); !ok || !isLedger || ledger.LedgerSequence != uint32(300+round) {
+			t.Fatalf("round %v: got unexpected message %#v", round, msg)
+		}
+	}
+}
+
+func TestRemoteMaxMessageSize(t *testing.T) {
+	SetMaxMessageSize(256)
+	defer SetMaxMessageSize(0)
+	if size := GetMaxMessageSize(); size != 256 {
+		t.Fatalf("got max message size %v, want %v", size, 256)
+	}
+
+	m := newMockRippled(t)
+	r := newTestRemote(t, m)
+	defer r.Close()
+	m.waitConnected(t)
+
+	m.send(map[string]interface{}{"type": "ledgerClosed", "ledger_index": 400})
+	msg, ok := nextIncoming(t, r)
+	if ledger, isLedger := msg.(*LedgerStreamMsg); !ok || !isLedger || ledger.LedgerSequence != 400 {
+		t.Fatalf("got unexpected message %#v within size limit", msg)
+	}
+
+	// the connection is closed if the peer sends a larger message
+	m.send(map[string]interface{}{"type": "ledgerClosed", "ledger_index": 401, "padding": strings.Repeat("x", 256)})
+	if msg, ok = nextIncoming(t, r); ok {
+		t.Fatalf("got message %#v, want connection closed", msg)
+	}
+
+	// non positive size means the default
+	SetMaxMessageSize(-1)
+	if size := GetMaxMessageSize(); size != defaultMaxMessageSize {
+		t.Errorf("got max message size %v, want %v", size, defaultMaxMessageSize)
+	}
+}
+
+func TestRemoteReconnect(t *testing.T) {
+	m := newMockRippled(t)
+	m.handle("fee", func(map[string]interface{}) []interface{} { return nil }) // hold responses
+	r := newTestRemote(t, m)
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := r.Fee()
+		errc <- err
+	}()
+	m.waitRequests(1)
+	m.dropConns()
+
+	// pending commands fail and the remote is closed after the server drops connection
+	select {
+	case err := <-errc:
+		var cmdErr *CommandError
+		if !errors.As(err, &cmdErr) || cmdErr.Message != "Connection Closed" {
+			t.Errorf("pending command got error %v, want connection closed", err)
+		}
+	case <-time.After(testWaitTimeout):
+		t.Fatalf("pending command is not failed after connection dropped")
+	}
+	if msg, ok := nextIncoming(t, r); ok {
+		t.Errorf("got message %#v, want incoming channel closed", msg)
+	}
+	if state := r.Liveness().State; state != StateClosed {
+		t.Errorf("got connection state %v, want %v", state, StateClosed)
+	}
+	r.Close()
+
+	// reconnect with a new remote
+	m.handleResult("fee", map[string]interface{}{"current_ledger_size": "9"})
+	r = newTestRemote(t, m)
+	defer r.Close()
+	res, err := r.Fee()
+	if err != nil || res.CurrentLedgerSize != 9 {
+		t.Errorf("reconnected remote got fee %v error %v", res, err)
+	}
+	if connects := m.getConnects(); connects != 2 {
+		t.Errorf("got %v connections, want %v", connects, 2)
+	}
+}
@@ -0,0 +1,81 @@
+package ripple
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+var _ tokens.BlockScanner = &Bridge{}
+
+// getAllDepositAddresses get deposit addresses of chain and all tokens
+func (b *Bridge) getAllDepositAddresses() []string {
+	exist := make(map[string]struct{})
+	result := make([]string, 0, 1)
+	add := func(address string) {
+		key := strings.ToLower(address)
+		if key == "" {
+			return
+		}
+		if _, ok := exist[key]; ok {
+			return
+		}
+		exist[key] = struct{}{}
+		result = append(result, address)
+	}
+	add(b.ChainConfig.RouterContract)
+	b.TokenConfigMap.Range(func(k, v interface{}) bool {
+		add(v.(*tokens.TokenConfig).RouterContract)
+		return true
+	})
+	return result
+}
+
+// ScanBlockRouterTxs impl tokens.BlockScanner
+// scan ledger for successful payments to the deposit addresses.
+func (b *Bridge) ScanBlockRouterTxs(height uint64) (txHashes []string, err error) {
+	for _, depositAddress := range b.getAllDepositAddresses() {
+		rpcParams := map[string]interface{}{
+			"account":          depositAddress,
+			"ledger_index_min": height,
+			"ledger_index_max": height,
+			"forward":          true,
+			"limit":            auditTxsLimit,
+		}
+		for {
+			txsRes, errf := b.getAccountTxs(rpcParams)
+			if errf != nil {
+				return nil, errf
+			}
+			// the node clamps the range to the validated ledgers it has
+			if txsRes.LedgerIndexMin > height || txsRes.LedgerIndexMax < height {
+				return nil, fmt.Errorf("%w: want %v, have [%v, %v]", errLedgerRangeNotCovered,
+					height, txsRes.LedgerIndexMin, txsRes.LedgerIndexMax)
+			}
+			for _, txmeta := range txsRes.Transactions {
+				if isDepositPayment(txmeta, depositAddress) {
+					txHashes = append(txHashes, txmeta.GetHash().String())
+				}
+			}
+			if txsRes.Marker == nil {
+				break
+			}
+			rpcParams["marker"] = txsRes.Marker
+		}
+	}
+	log.Info("scan ledger finished", "chainID", b.ChainConfig.ChainID, "ledger", height, "routerTxs", len(txHashes))
+	return txHashes, nil
+}
+
+func isDepositPayment(txmeta *data.TransactionWithMetaData, depositAddress string) bool {
+	payment, ok := txmeta.Transaction.(*data.Payment)
+	if !ok || payment.GetTransactionType() != data.PAYMENT {
+		return false
+	}
+	return txmeta.MetaData.TransactionResult.Success() &&
+		common.IsEqualIgnoreCase(payment.Destination.String(), depositAddress)
+}
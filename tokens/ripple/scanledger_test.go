@@ -0,0 +1,66 @@
+package ripple
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/websockets"
+)
+
+func TestIsDepositPayment(t *testing.T) {
+	payment := newTestPayment(t, 1) // pays to testCheckAccount
+	offer := &data.OfferCreate{}
+	offer.TransactionType = data.OFFER_CREATE
+
+	tests := []struct {
+		tx      data.Transaction
+		result  data.TransactionResult
+		deposit string
+		want    bool
+	}{
+		{payment, 0, testCheckAccount, true},
+		{payment, 0, testCheckIssuer, false},    // to other address
+		{payment, 100, testCheckAccount, false}, // failed
+		{offer, 0, testCheckAccount, false},     // not payment
+	}
+	for i, test := range tests {
+		txmeta := &data.TransactionWithMetaData{
+			Transaction: test.tx,
+			MetaData:    data.MetaData{TransactionResult: test.result},
+		}
+		if got := isDepositPayment(txmeta, test.deposit); got != test.want {
+			t.Errorf("test %v: is deposit payment got %v, want %v", i, got, test.want)
+		}
+	}
+}
+
+func TestGetStreamedDeposit(t *testing.T) {
+	payment := newTestPayment(t, 1) // pays to testCheckAccount
+	newDelivery := func(validated bool, result data.TransactionResult) websockets.Delivery {
+		msg := &websockets.TransactionStreamMsg{LedgerSequence: 100, Validated: validated}
+		msg.Transaction = data.TransactionWithMetaData{
+			Transaction: payment,
+			MetaData:    data.MetaData{TransactionResult: result},
+		}
+		return websockets.Delivery{Seq: 1, Msg: msg}
+	}
+
+	streamed := getStreamedDeposit(newDelivery(true, 0), []string{testCheckIssuer, testCheckAccount})
+	if streamed == nil || streamed.TxHash != payment.GetHash().String() || streamed.Height != 100 {
+		t.Errorf("streamed deposit got %+v", streamed)
+	}
+	tests := []struct {
+		delivery websockets.Delivery
+		deposits []string
+	}{
+		{newDelivery(false, 0), []string{testCheckAccount}},                                   // not validated
+		{newDelivery(true, 100), []string{testCheckAccount}},                                  // failed
+		{newDelivery(true, 0), []string{testCheckIssuer}},                                     // to other address
+		{websockets.Delivery{Msg: &websockets.LedgerStreamMsg{}}, []string{testCheckAccount}}, // not tx
+	}
+	for i, test := range tests {
+		if streamed = getStreamedDeposit(test.delivery, test.deposits); streamed != nil {
+			t.Errorf("test %v: got streamed deposit %+v, want nil", i, streamed)
+		}
+	}
+}
@@ -1,10 +1,12 @@
 package ripple
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
@@ -13,6 +15,9 @@ import (
 
 // SendTransaction send signed tx
 func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	tx, ok := signedTx.(data.Transaction)
 	if !ok {
 		return "", tokens.ErrWrongRawTx
@@ -21,33 +26,74 @@ func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error
 	if err != nil {
 		return "", err
 	}
+	for i := 0; i < rpcRetryTimes; i++ {
+		txHash, err = b.submitTransaction(tx, raw)
+		if err == nil || errors.Is(err, tokens.ErrTxExpired) {
+			return txHash, err
+		}
+		time.Sleep(rpcRetryInterval)
+	}
+	return "", err
+}
+
+// SendTransactions impl tokens.BatchSender.
+// the signed txs of one batch are submitted in order in one round to all remotes,
+// each member's result is reported on its own and is not retried here.
+// (the Batch amendment wraps unsigned inner txs, so the individually signed
+// txs of the swaps can not be put into one Batch transaction)
+func (b *Bridge) SendTransactions(signedTxs []interface{}) []*tokens.BatchSendResult {
+	results := make([]*tokens.BatchSendResult, len(signedTxs))
+	for i, signedTx := range signedTxs {
+		res := &tokens.BatchSendResult{}
+		results[i] = res
+		if params.IsWatcherMode {
+			res.Err = params.ErrWatcherMode
+			continue
+		}
+		tx, ok := signedTx.(data.Transaction)
+		if !ok {
+			res.Err = tokens.ErrWrongRawTx
+			continue
+		}
+		_, raw, err := data.Raw(tx)
+		if err != nil {
+			res.Err = err
+			continue
+		}
+		res.TxHash, res.Err = b.submitTransaction(tx, raw)
+	}
+	return results
+}
+
+// submitTransaction submit signed tx to all remotes once,
+// succeed if any remote accepts it.
+func (b *Bridge) submitTransaction(tx data.Transaction, raw []byte) (txHash string, err error) {
 	rpcParams := map[string]interface{}{
 		"tx_blob": fmt.Sprintf("%X", raw),
 	}
 	var success bool
-	urls := b.GetGatewayConfig().AllGatewayURLs
-	for i := 0; i < rpcRetryTimes; i++ {
-		// try send to all remotes
-		for _, url := range urls {
-			var resp *websockets.SubmitResult
-			err = client.RPCPostWithTimeout(b.RPCClientTimeout, &resp, url, "submit", rpcParams)
-			if err != nil || resp == nil {
-				log.Warn("Try sending transaction failed", "error", err)
-				continue
-			}
-			if !resp.EngineResult.Success() {
-				log.Warn("send tx with error result", "result", resp.EngineResult, "message", resp.EngineResultMessage)
-			}
-			txHash = tx.GetBase().Hash.String()
-			success = true
+	urls := b.getMethodURLs("submit")
+	for _, url := range urls {
+		var resp *websockets.SubmitResult
+		err = client.RPCPostWithTimeout(b.RPCClientTimeout, &resp, url, "submit", rpcParams)
+		if err != nil || resp == nil {
+			log.Warn("Try sending transaction failed", "error", err)
+			continue
 		}
-		if success {
-			break
+		if !resp.EngineResult.Success() {
+			log.Warn("send tx with error result", "result", resp.EngineResult, "message", resp.EngineResultMessage)
+			if resp.EngineResult.String() == engineResultMaxLedger {
+				return "", fmt.Errorf("%w: %v", tokens.ErrTxExpired, resp.EngineResultMessage)
+			}
 		}
-		time.Sleep(rpcRetryInterval)
+		txHash = tx.GetBase().Hash.String()
+		success = true
 	}
 	if success {
 		return txHash, nil
 	}
+	if err == nil {
+		err = tokens.ErrSendTx
+	}
 	return "", err
 }
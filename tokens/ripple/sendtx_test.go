@@ -0,0 +1,101 @@
+package ripple
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func newTestPayment(t *testing.T, sequence uint32) *data.Payment {
+	account, err := data.NewAccountFromAddress(testCheckAccount)
+	if err != nil {
+		t.Fatalf("new account failed: %v", err)
+	}
+	amount, err := data.NewAmount("1000000/XRP")
+	if err != nil {
+		t.Fatalf("new amount failed: %v", err)
+	}
+	tx := &data.Payment{Amount: *amount}
+	tx.TransactionType = data.PAYMENT
+	tx.Account = *account
+	tx.Destination = *account
+	tx.Sequence = sequence
+	copy(tx.Hash[:], []byte(fmt.Sprintf("hash%v", sequence)))
+	return tx
+}
+
+func TestSendTransactions(t *testing.T) {
+	var submitted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string                   `json:"method"`
+			Params []map[string]interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request failed: %v", err)
+			return
+		}
+		if req.Method == "server_info" {
+			_, _ = w.Write([]byte(`{"result":{"info":{"build_version":"1.12.0"}}}`))
+			return
+		}
+		blob := req.Params[0]["tx_blob"].(string)
+		submitted = append(submitted, blob)
+		switch len(submitted) {
+		case 2:
+			w.WriteHeader(http.StatusInternalServerError)
+		case 3:
+			_, _ = w.Write([]byte(`{"result":{"engine_result":"tefMAX_LEDGER","engine_result_message":"Ledger sequence too high."}}`))
+		default:
+			_, _ = w.Write([]byte(`{"result":{"engine_result":"tesSUCCESS"}}`))
+		}
+	}))
+	defer server.Close()
+
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{server.URL}})
+
+	var signedTxs []interface{}
+	for sequence := uint32(1); sequence <= 4; sequence++ {
+		signedTxs = append(signedTxs, newTestPayment(t, sequence))
+	}
+	signedTxs = append(signedTxs, "not a tx")
+
+	results := b.SendTransactions(signedTxs)
+	if len(results) != len(signedTxs) {
+		t.Fatalf("results count got %v, want %v", len(results), len(signedTxs))
+	}
+	// members are submitted in order and only once
+	if len(submitted) != 4 {
+		t.Fatalf("submitted count got %v, want 4", len(submitted))
+	}
+	for i, signedTx := range signedTxs[:4] {
+		_, raw, _ := data.Raw(signedTx.(data.Transaction))
+		if submitted[i] != fmt.Sprintf("%X", raw) {
+			t.Errorf("member %v is not submitted in order", i)
+		}
+	}
+	for _, i := range []int{0, 3} {
+		want := signedTxs[i].(data.Transaction).GetBase().Hash.String()
+		if results[i].Err != nil || results[i].TxHash != want {
+			t.Errorf("member %v got (%v, %v), want %v", i, results[i].TxHash, results[i].Err, want)
+		}
+	}
+	// the failed members do not fail the others
+	if results[1].Err == nil {
+		t.Errorf("member 1 should fail")
+	}
+	if !errors.Is(results[2].Err, tokens.ErrTxExpired) {
+		t.Errorf("member 2 got error %v, want %v", results[2].Err, tokens.ErrTxExpired)
+	}
+	if !errors.Is(results[4].Err, tokens.ErrWrongRawTx) {
+		t.Errorf("member 4 got error %v, want %v", results[4].Err, tokens.ErrWrongRawTx)
+	}
+}
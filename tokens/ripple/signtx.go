@@ -20,18 +20,50 @@ import (
 	"github.com/btcsuite/btcd/btcec"
 )
 
-func (b *Bridge) verifyTransactionWithArgs(tx data.Transaction, args *tokens.BuildTxArgs) error {
-	if tx.GetTransactionType() != data.PAYMENT {
-		return nil
-	}
+const (
+	// chainFamily chain family of ec keys in the mpc sign scheme registry
+	chainFamily = "ripple"
+	// chainFamilyEd25519 chain family of ed25519 keys in the mpc sign scheme registry
+	chainFamilyEd25519 = "ripple-ed25519"
+	// schemeSecp256k1Sha512Half ripple signs the first half of the sha512 digest
+	schemeSecp256k1Sha512Half = "secp256k1+sha512half"
+)
 
-	payment, ok := tx.(*data.Payment)
-	if !ok {
-		return tokens.ErrWrongRawTx
+func init() {
+	mpc.RegisterSignScheme(schemeSecp256k1Sha512Half, mpc.NewSecp256k1Scheme(sha512Half))
+	mpc.RegisterChainFamily(chainFamily, schemeSecp256k1Sha512Half)
+	mpc.RegisterChainFamily(chainFamilyEd25519, mpc.SchemeEd25519)
+}
+
+func sha512Half(msgs ...[]byte) []byte {
+	var message []byte
+	for _, b := range msgs {
+		message = append(message, b...)
 	}
+	return rcrypto.Sha512Half(message)
+}
 
-	to := payment.Destination.String()
-	toTag := payment.DestinationTag
+func (b *Bridge) verifyTransactionWithArgs(tx data.Transaction, args *tokens.BuildTxArgs) error {
+	var to string
+	var toTag *uint32
+	var payment *data.Payment
+	switch tx.GetTransactionType() {
+	case data.PAYMENT:
+		var ok bool
+		payment, ok = tx.(*data.Payment)
+		if !ok {
+			return tokens.ErrWrongRawTx
+		}
+		to, toTag = payment.Destination.String(), payment.DestinationTag
+	case data.CHECK_CREATE:
+		checkCreate, ok := tx.(*data.CheckCreate)
+		if !ok {
+			return tokens.ErrWrongRawTx
+		}
+		to, toTag = checkCreate.Destination.String(), checkCreate.DestinationTag
+	default:
+		return nil
+	}
 
 	checkReceiver, checkTag, err := GetAddressAndTag(args.Bind)
 	if err != nil {
@@ -46,7 +78,10 @@ func (b *Bridge) verifyTransactionWithArgs(tx data.Transaction, args *tokens.Bui
 		return fmt.Errorf("[sign] verify payment tx destination tag failed")
 	}
 
-	return nil
+	if payment == nil {
+		return nil
+	}
+	return b.checkPaymentPathQuality(payment)
 }
 
 // MPCSignTransaction mpc sign raw tx
@@ -61,6 +96,9 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 		log.Warn("Verify transaction failed", "error", err)
 		return nil, "", err
 	}
+	if err = tokens.CheckDestMethods(b.ChainConfig.ChainID, tx.GetTransactionType().String()); err != nil {
+		return nil, "", err
+	}
 
 	mpcParams := params.GetMPCConfig(b.UseFastMPC)
 	if mpcParams.SignWithPrivateKey {
@@ -81,7 +119,7 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 	isEd := isEd25519Pubkey(pubkey)
 
 	var keyID string
-	var rsvs []string
+	var signature []byte
 
 	mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
 	if isEd {
@@ -90,11 +128,11 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 		// the real sign content is (signing prefix + msg)
 		// when we hex encoding here, the mpc should do hex decoding there.
 		signContent := common.ToHex(msg)
-		keyID, rsvs, err = mpcConfig.DoSignOneED(signPubKey, signContent, msgContext)
+		keyID, signature, err = mpcConfig.DoSignHashWithScheme(chainFamilyEd25519, signPubKey, signContent, msgContext)
 	} else {
 		signPubKey := pubkeyStr
 		signContent := msgHash.String()
-		keyID, rsvs, err = mpcConfig.DoSignOneEC(signPubKey, signContent, msgContext)
+		keyID, signature, err = mpcConfig.DoSignHashWithScheme(chainFamily, signPubKey, signContent, msgContext)
 	}
 
 	if err != nil {
@@ -103,11 +141,7 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 	}
 	log.Info(b.ChainConfig.BlockChain+" MPCSignTransaction finished", "keyID", keyID, "txid", args.SwapID)
 
-	if len(rsvs) != 1 {
-		return nil, "", fmt.Errorf("get sign status require one rsv but have %v (keyID = %v)", len(rsvs), keyID)
-	}
-
-	rsv := rsvs[0]
+	rsv := hex.EncodeToString(signature)
 	log.Trace(b.ChainConfig.BlockChain+" MPCSignTransaction get rsv success", "keyID", keyID, "rsv", rsv)
 
 	sig := rsvToSig(rsv, isEd)
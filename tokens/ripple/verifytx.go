@@ -9,6 +9,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/payload"
 	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
 	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/websockets"
 )
@@ -55,6 +56,8 @@ func (b *Bridge) VerifyTransaction(txHash string, args *tokens.VerifyArgs) (*tok
 	switch swapType {
 	case tokens.ERC20SwapType:
 		return b.verifySwapoutTx(txHash, logIndex, allowUnstable)
+	case tokens.ClaimRefundType:
+		return b.verifyCheckCancel(txHash)
 	default:
 		return nil, tokens.ErrSwapTypeNotSupported
 	}
@@ -193,23 +196,17 @@ func getTargetMemo(memoStr string) (memo string) {
 func parseSwapMemos(swapInfo *tokens.SwapTxInfo, memos data.Memos) bool {
 	for _, memo := range memos {
 		memoStr := getTargetMemo(string(memo.Memo.MemoData.Bytes()))
-		parts := strings.Split(memoStr, ":")
-		if len(parts) < 2 {
-			continue
-		}
-		bindStr := parts[0]
-		toChainIDStr := parts[1]
-		biToChainID, err := common.GetBigIntFromStr(toChainIDStr)
+		p, err := payload.Decode(payload.FormatTextV2, []byte(memoStr))
 		if err != nil {
 			continue
 		}
-		dstBridge := router.GetBridgeByChainID(toChainIDStr)
+		dstBridge := router.GetBridgeByChainID(p.ToChainID.String())
 		if dstBridge == nil {
 			continue
 		}
-		if dstBridge.IsValidAddress(bindStr) {
-			swapInfo.Bind = bindStr          // Bind
-			swapInfo.ToChainID = biToChainID // ToChainID
+		if dstBridge.IsValidAddress(p.Bind) {
+			swapInfo.Bind = p.Bind           // Bind
+			swapInfo.ToChainID = p.ToChainID // ToChainID
 			return true
 		}
 	}
@@ -0,0 +1,79 @@
+package tokens
+
+import (
+	"strings"
+)
+
+// SendTxErrorClass common taxonomy of send transaction errors
+type SendTxErrorClass int
+
+// send transaction error classes
+const (
+	// SendTxErrUnknown unknown error, the sending is failed
+	SendTxErrUnknown SendTxErrorClass = iota
+	// SendTxErrTemporary temporary network or node error, retry sending
+	SendTxErrTemporary
+	// SendTxErrAlreadyKnown tx is already in the pool or on chain, treat as sent
+	SendTxErrAlreadyKnown
+	// SendTxErrNonceTooLow nonce is consumed, refresh the sender's nonce
+	SendTxErrNonceTooLow
+	// SendTxErrUnderpriced gas price is too low, bump gas price by replacing
+	SendTxErrUnderpriced
+	// SendTxErrInsufficientFunds sender can not afford the tx, fail until funded
+	SendTxErrInsufficientFunds
+	// SendTxErrOutOfGas gas limit or fee is not enough, rebuild with re-estimated gas
+	SendTxErrOutOfGas
+)
+
+func (c SendTxErrorClass) String() string {
+	switch c {
+	case SendTxErrTemporary:
+		return "Temporary"
+	case SendTxErrAlreadyKnown:
+		return "AlreadyKnown"
+	case SendTxErrNonceTooLow:
+		return "NonceTooLow"
+	case SendTxErrUnderpriced:
+		return "Underpriced"
+	case SendTxErrInsufficientFunds:
+		return "InsufficientFunds"
+	case SendTxErrOutOfGas:
+		return "OutOfGas"
+	default:
+		return "Unknown"
+	}
+}
+
+// SendTxErrorMapper interface (classify chain specific send transaction errors)
+type SendTxErrorMapper interface {
+	MapSendTxError(err error) SendTxErrorClass
+}
+
+// ClassifySendTxError classify send transaction error with the bridge's
+// error mapper, fall back to the common classification if not supported.
+func ClassifySendTxError(bridge IBridge, err error) SendTxErrorClass {
+	if err == nil {
+		return SendTxErrUnknown
+	}
+	if mapper, ok := bridge.(SendTxErrorMapper); ok {
+		if class := mapper.MapSendTxError(err); class != SendTxErrUnknown {
+			return class
+		}
+	}
+	return DefaultMapSendTxError(err)
+}
+
+// DefaultMapSendTxError common classification of send transaction errors
+func DefaultMapSendTxError(err error) SendTxErrorClass {
+	errMsg := err.Error()
+	switch {
+	case strings.Contains(errMsg, "Client.Timeout exceeded while awaiting headers"), // timeout
+		strings.Contains(errMsg, "connection refused"),
+		strings.Contains(errMsg, "connection reset by peer"),
+		strings.Contains(errMsg, "json-rpc error -32000, internal"), // cronos specific
+		strings.EqualFold(errMsg, "json-rpc error -32000, "):        // cronos specific
+		return SendTxErrTemporary
+	default:
+		return SendTxErrUnknown
+	}
+}
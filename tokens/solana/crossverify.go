@@ -0,0 +1,26 @@
+package solana
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/solana/types"
+)
+
+var _ tokens.TxCrossVerifier = &Bridge{}
+
+// CrossVerifyTx impl tokens.TxCrossVerifier
+func (b *Bridge) CrossVerifyTx(txHash string, quorum int) error {
+	obj := map[string]interface{}{
+		"encoding":   "json",
+		"commitment": "finalized",
+	}
+	return tokens.CrossVerifyTx(b.ChainConfig.ChainID, txHash, b.GatewayConfig.AllGatewayURLs, quorum, func(url string) (string, error) {
+		var tx types.TransactionWithMeta
+		if err := RPCCall(&tx, []string{url}, "getTransaction", txHash, obj); err != nil {
+			return "", err
+		}
+		if uint64(tx.Slot) == 0 {
+			return "", tokens.ErrTxNotFound
+		}
+		return tokens.TxFingerprint(&tx)
+	})
+}
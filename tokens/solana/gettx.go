@@ -1,7 +1,8 @@
 package solana
 
 import (
-	"time"
+	"fmt"
+	"strconv"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
@@ -26,15 +27,23 @@ func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
 	txStatus.BlockTime = uint64(txm.BlockTime)
 
 	if txStatus.BlockHeight != 0 {
-		for i := 0; i < 3; i++ {
-			latest, errt := b.GetLatestBlockNumber()
-			if errt == nil {
-				if latest > txStatus.BlockHeight {
-					txStatus.Confirmations = latest - txStatus.BlockHeight
-				}
-				break
+		if !txm.IsStatusOk() {
+			var failReason interface{}
+			if txm.Meta != nil {
+				failReason = txm.Meta.Err
 			}
-			time.Sleep(1 * time.Second)
+			txStatus.SetFailed(fmt.Sprintf("%v", failReason))
+		}
+		if txm.Meta != nil {
+			txStatus.FeePaid = strconv.FormatUint(uint64(txm.Meta.Fee), 10)
+		}
+		var latest uint64
+		errt := tokens.RetryRPC(b.ChainConfig.ChainID, func() (err error) {
+			latest, err = b.GetLatestBlockNumber()
+			return err
+		})
+		if errt == nil {
+			txStatus.SetLatestHeight(latest, b.GetChainConfig().Confirmations)
 		}
 	}
 
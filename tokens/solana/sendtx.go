@@ -20,6 +20,9 @@ var (
 
 // SendTransaction impl
 func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	tx, ok := signedTx.(*types.Transaction)
 	if !ok {
 		return "", errors.New("wrong signed transaction type")
@@ -16,6 +16,13 @@ import (
 	bin "github.com/streamingfast/binary"
 )
 
+// chainFamily chain family in the mpc sign scheme registry
+const chainFamily = "solana"
+
+func init() {
+	mpc.RegisterChainFamily(chainFamily, mpc.SchemeEd25519)
+}
+
 func (b *Bridge) verifyTransactionWithArgs(tx *types.Transaction, args *tokens.BuildTxArgs) error {
 	fmt.Println(tx.Message.Instructions[0].Data)
 
@@ -52,6 +59,17 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 		log.Warn("Verify transaction failed", "error", err)
 		return nil, "", err
 	}
+	programIDs := make([]string, 0, len(tx.Message.Instructions))
+	for _, instruction := range tx.Message.Instructions {
+		programID, errf := tx.ResolveProgramIDIndex(instruction.ProgramIDIndex)
+		if errf != nil {
+			return nil, "", errf
+		}
+		programIDs = append(programIDs, programID.String())
+	}
+	if err = tokens.CheckDestMethods(b.ChainConfig.ChainID, programIDs...); err != nil {
+		return nil, "", err
+	}
 
 	signerKeys := tx.Message.SignerKeys()
 	if len(signerKeys) != 1 {
@@ -86,27 +104,19 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 	log.Info(logPrefix+"start", "txid", txid, "fromChainID", args.FromChainID, "toChainID", args.ToChainID)
 
 	mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
-	keyID, rsvs, err := mpcConfig.DoSignOneED(mpcPubkey, common.ToHex(msgContent), msgContext)
+	keyID, signature, err := mpcConfig.DoSignWithScheme(chainFamily, mpcPubkey, msgContent, msgContext)
 	if err != nil {
 		log.Info(logPrefix+"failed", "keyID", keyID, "txid", txid, "err", err)
 		return nil, "", err
 	}
 	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "fromChainID", args.FromChainID, "toChainID", args.ToChainID)
 
-	if len(rsvs) != 1 {
-		log.Error("get sign status require one rsv but return many",
-			"rsvs", len(rsvs), "keyID", keyID, "txid", txid, "fromChainID", args.FromChainID, "toChainID", args.ToChainID)
-		return nil, "", errors.New("get sign status require one rsv but return many")
-	}
-
-	rsv := rsvs[0]
-	log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "rsv", rsv)
-
-	sig, err := types.NewSignatureFromString(rsv)
+	sig, err := types.NewSignatureFromBytes(signature)
 	if err != nil {
 		log.Error("get signature from rsv failed", "keyID", keyID, "txid", txid, "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "err", err)
 		return nil, "", err
 	}
+	log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "rsv", common.ToHex(signature))
 
 	tx.Signatures = append(tx.Signatures, sig)
 
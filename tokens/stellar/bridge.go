@@ -162,18 +162,19 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 		return nil, errTxResultType
 	}
 
+	status.Receipt = nil
+	inledger := relTx.Ledger
+	status.BlockHeight = uint64(inledger)
+
 	// Check tx status
 	if !relTx.Successful {
 		log.Warn("Stellar tx status is not success", "result", relTx.ResultMetaXdr)
-		return nil, tokens.ErrTxWithWrongStatus
+		status.SetFailed(relTx.ResultXdr)
 	}
+	status.FeePaid = strconv.FormatInt(relTx.FeeCharged, 10)
 
-	status.Receipt = nil
-	inledger := relTx.Ledger
-	status.BlockHeight = uint64(inledger)
-
-	if latest, err := b.GetLatestBlockNumber(); err == nil && latest > uint64(inledger) {
-		status.Confirmations = latest - uint64(inledger)
+	if latest, err := b.GetLatestBlockNumber(); err == nil {
+		status.SetLatestHeight(latest, b.GetChainConfig().Confirmations)
 	}
 	return status, nil
 }
@@ -0,0 +1,25 @@
+package stellar
+
+import (
+	"sort"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var _ tokens.TxCrossVerifier = &Bridge{}
+
+// CrossVerifyTx impl tokens.TxCrossVerifier
+func (b *Bridge) CrossVerifyTx(txHash string, quorum int) error {
+	urls := make([]string, 0, len(b.Remotes))
+	for url := range b.Remotes {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	return tokens.CrossVerifyTx(b.ChainConfig.ChainID, txHash, urls, quorum, func(url string) (string, error) {
+		tx, err := b.Remotes[url].TransactionDetail(txHash)
+		if err != nil {
+			return "", err
+		}
+		return tokens.TxFingerprint([]interface{}{tx.Hash, tx.Ledger, tx.Successful, tx.EnvelopeXdr, tx.ResultXdr})
+	})
+}
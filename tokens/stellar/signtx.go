@@ -1,10 +1,8 @@
 package stellar
 
 import (
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"math/big"
 	"strings"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
@@ -13,7 +11,6 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
-	"github.com/btcsuite/btcd/btcec"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
 	"github.com/stellar/go/txnbuild"
@@ -42,6 +39,13 @@ func (b *Bridge) verifyTransactionWithArgs(tx *txnbuild.Transaction, args *token
 	return nil
 }
 
+// chainFamily chain family in the mpc sign scheme registry
+const chainFamily = "stellar"
+
+func init() {
+	mpc.RegisterChainFamily(chainFamily, mpc.SchemeEd25519)
+}
+
 // MPCSignTransaction mpc sign raw tx
 func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
 	tx, ok := rawTx.(*txnbuild.Transaction)
@@ -53,6 +57,15 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 		log.Warn("Verify transaction failed", "error", err)
 		return nil, "", err
 	}
+	for _, op := range tx.Operations() {
+		xdrOp, errf := op.BuildXDR()
+		if errf != nil {
+			return nil, "", errf
+		}
+		if err = tokens.CheckDestMethods(b.ChainConfig.ChainID, xdrOp.Body.Type.String()); err != nil {
+			return nil, "", err
+		}
+	}
 
 	mpcParams := params.GetMPCConfig(b.UseFastMPC)
 	if mpcParams.SignWithPrivateKey {
@@ -76,27 +89,13 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 		return nil, "", err
 	}
 
-	var keyID string
-	var rsvs []string
-
 	mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
-
-	signContent := common.ToHex(txMsg[:])
-	keyID, rsvs, err = mpcConfig.DoSignOneED(signPubkeyStr, signContent, msgContext)
-
+	keyID, sig, err := mpcConfig.DoSignWithScheme(chainFamily, signPubkeyStr, txMsg[:], msgContext)
 	if err != nil {
 		return nil, "", err
 	}
 	log.Info(b.ChainConfig.BlockChain+" MPCSignTransaction finished", "keyID", keyID, "txid", args.SwapID)
-
-	if len(rsvs) != 1 {
-		return nil, "", fmt.Errorf("get sign status require one rsv but have %v (keyID = %v)", len(rsvs), keyID)
-	}
-
-	rsv := rsvs[0]
-	log.Trace(b.ChainConfig.BlockChain+" MPCSignTransaction get rsv success", "keyID", keyID, "rsv", rsv)
-
-	sig := rsvToSig(rsv, true)
+	log.Trace(b.ChainConfig.BlockChain+" MPCSignTransaction get rsv success", "keyID", keyID, "rsv", common.ToHex(sig))
 
 	pubkeyAddr, _ := b.PublicKeyToAddress(pubkeyStr)
 	pubkeyKeyPair := keypair.MustParseAddress(pubkeyAddr)
@@ -146,19 +145,3 @@ func MakeSignedTransaction(pubkey *keypair.FromAddress, sig []byte, tx *txnbuild
 	decoratedSignature := xdr.NewDecoratedSignature(sig, pubkey.Hint())
 	return tx.AddSignatureDecorated(decoratedSignature)
 }
-
-func rsvToSig(rsv string, isEd bool) []byte {
-	if isEd {
-		return common.FromHex(rsv)
-	}
-	b, _ := hex.DecodeString(rsv)
-	rx := hex.EncodeToString(b[:32])
-	sx := hex.EncodeToString(b[32:64])
-	r, _ := new(big.Int).SetString(rx, 16)
-	s, _ := new(big.Int).SetString(sx, 16)
-	signature := &btcec.Signature{
-		R: r,
-		S: s,
-	}
-	return signature.Serialize()
-}
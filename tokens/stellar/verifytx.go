@@ -2,7 +2,6 @@ package stellar
 
 import (
 	"encoding/base64"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
@@ -12,6 +11,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/payload"
 	"github.com/stellar/go/network"
 	hProtocol "github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/txnbuild"
@@ -104,7 +104,7 @@ func (b *Bridge) verifySwapoutTx(txHash string, logIndex int, allowUnstable bool
 	return b.buildSwapInfoFromOperation(txres, opt, logIndex)
 }
 
-// memo format:
+// memo is in payload.FormatBinaryV1 format:
 // three parts: | 0 (bindAddrBytesLen) | 1...pos (bindAddr) | pos+1... (tochainID) |
 // memo[0] (=len): bind address bytes length
 // memo[1:len+1]: bind address bytes
@@ -131,38 +131,22 @@ func checkSwapMemos(swapInfo *tokens.SwapTxInfo, memoStr string) bool {
 
 func DecodeMemos(memoStr string) (string, *big.Int) {
 	memobytes, err := base64.StdEncoding.DecodeString(memoStr)
-	if err != nil || len(memobytes) == 0 {
+	if err != nil {
 		return "", nil
 	}
-	addrLen := int(memobytes[0])
-	addEnd := 1 + addrLen
-	if len(memobytes) < addEnd+1 {
+	p, err := payload.Decode(payload.FormatBinaryV1, memobytes)
+	if err != nil {
 		return "", nil
 	}
-	bindStr := common.ToHex(memobytes[1:addEnd])
-	bigToChainID := new(big.Int).SetBytes(memobytes[addEnd:])
-	return bindStr, bigToChainID
+	return p.Bind, p.ToChainID
 }
 
 func EncodeMemo(chainId *big.Int, bindAddr string) (*txnbuild.MemoHash, error) {
-	if common.HasHexPrefix(bindAddr) {
-		bindAddr = bindAddr[2:]
-	}
-	b, err := hex.DecodeString(bindAddr)
+	data, err := payload.Encode(payload.FormatBinaryV1, &payload.Payload{Bind: bindAddr, ToChainID: chainId})
 	if err != nil {
-		return nil, err
-	}
-	c := chainId.Bytes()
-	if len(b)+len(c) > 31 {
-		return nil, fmt.Errorf("memo too long,chainID %s addr %s", chainId.String(), bindAddr)
+		return nil, fmt.Errorf("encode memo failed, chainID %v addr %v: %w", chainId, bindAddr, err)
 	}
 	rtn := new(txnbuild.MemoHash)
-	rtn[0] = byte(len(b))
-	for i := 0; i < len(b); i++ {
-		rtn[i+1] = b[i]
-	}
-	for i := 0; i < len(c); i++ {
-		rtn[32-len(c)+i] = c[i]
-	}
+	copy(rtn[:], data)
 	return rtn, nil
 }
@@ -0,0 +1,30 @@
+#!/bin/sh
+# deploy the contracts of the integration tests to the local evm chain.
+# the deployer is the first development account of anvil, so the addresses
+# of the contracts created by its first txs are fixed (see harness.go):
+#   nonce 0: TestToken  0x5FbDB2315678afecb367f032d93F642f64180aa3
+#   nonce 1: TestRouter 0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512
+set -e
+
+RPC_URL=${RPC_URL:-http://anvil:8545}
+DEPLOYER_KEY=0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80
+MPC_ADDRESS=0x70997970C51812dc3A010C7d01b50e0d17dc79C8
+ROUTER_ADDRESS=0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512
+INITIAL_SUPPLY=1000000000000000000000000
+
+until cast block-number --rpc-url "$RPC_URL" >/dev/null 2>&1; do
+	sleep 1
+done
+
+if [ "$(cast code --rpc-url "$RPC_URL" "$ROUTER_ADDRESS")" != "0x" ]; then
+	echo "contracts are already deployed"
+	exit 0
+fi
+
+create() {
+	forge create --root /contracts --out /tmp/out --cache-path /tmp/cache \
+		--rpc-url "$RPC_URL" --private-key "$DEPLOYER_KEY" --broadcast "$@"
+}
+
+create src/TestToken.sol:TestToken --constructor-args "Test Token" TEST "$ROUTER_ADDRESS" "$INITIAL_SUPPLY"
+create src/TestRouter.sol:TestRouter --constructor-args "$MPC_ADDRESS"
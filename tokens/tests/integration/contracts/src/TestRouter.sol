@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+pragma solidity 0.8.20;
+
+interface ITestToken {
+    function mint(address to, uint256 amount) external;
+
+    function burn(address from, uint256 amount) external;
+}
+
+// TestRouter the minimal router contract of the integration tests,
+// it has the swap functions and events of the anyswap v6 router
+// which are used by the bridge to verify swapouts and build swapins.
+contract TestRouter {
+    address public mpc;
+
+    mapping(bytes32 => bool) public completed;
+
+    event LogAnySwapOut(
+        address indexed token,
+        address indexed from,
+        string to,
+        uint256 amount,
+        uint256 fromChainID,
+        uint256 toChainID
+    );
+    event LogAnySwapIn(
+        bytes32 indexed txhash,
+        address indexed token,
+        address indexed to,
+        uint256 amount,
+        uint256 fromChainID,
+        uint256 toChainID
+    );
+
+    constructor(address _mpc) {
+        mpc = _mpc;
+    }
+
+    function anySwapOut(address token, string memory to, uint256 amount, uint256 toChainID) external {
+        ITestToken(token).burn(msg.sender, amount);
+        emit LogAnySwapOut(token, msg.sender, to, amount, block.chainid, toChainID);
+    }
+
+    function anySwapIn(bytes32 txs, address token, address to, uint256 amount, uint256 fromChainID) external {
+        require(msg.sender == mpc, "TestRouter: FORBIDDEN");
+        require(!completed[txs], "TestRouter: swapin is completed");
+        completed[txs] = true;
+        ITestToken(token).mint(to, amount);
+        emit LogAnySwapIn(txs, token, to, amount, fromChainID, block.chainid);
+    }
+}
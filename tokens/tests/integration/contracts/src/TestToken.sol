@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+pragma solidity 0.8.20;
+
+// TestToken the minimal anyswap token of the integration tests,
+// it is minted and burned by the router contract in swaps.
+contract TestToken {
+    string public name;
+    string public symbol;
+    uint8 public constant decimals = 18;
+    uint256 public totalSupply;
+    address public immutable router;
+
+    mapping(address => uint256) public balanceOf;
+
+    event Transfer(address indexed from, address indexed to, uint256 value);
+
+    modifier onlyRouter() {
+        require(msg.sender == router, "TestToken: FORBIDDEN");
+        _;
+    }
+
+    constructor(string memory _name, string memory _symbol, address _router, uint256 _initialSupply) {
+        name = _name;
+        symbol = _symbol;
+        router = _router;
+        _mint(msg.sender, _initialSupply);
+    }
+
+    function transfer(address to, uint256 amount) external returns (bool) {
+        require(balanceOf[msg.sender] >= amount, "TestToken: insufficient balance");
+        balanceOf[msg.sender] -= amount;
+        balanceOf[to] += amount;
+        emit Transfer(msg.sender, to, amount);
+        return true;
+    }
+
+    function mint(address to, uint256 amount) external onlyRouter {
+        _mint(to, amount);
+    }
+
+    function burn(address from, uint256 amount) external onlyRouter {
+        require(balanceOf[from] >= amount, "TestToken: insufficient balance");
+        balanceOf[from] -= amount;
+        totalSupply -= amount;
+        emit Transfer(from, address(0), amount);
+    }
+
+    function _mint(address to, uint256 amount) internal {
+        totalSupply += amount;
+        balanceOf[to] += amount;
+        emit Transfer(address(0), to, amount);
+    }
+}
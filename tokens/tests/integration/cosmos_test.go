@@ -0,0 +1,83 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/hex"
+	"sync"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/cosmos"
+)
+
+var (
+	localCosmosBridge     *cosmos.Bridge
+	localCosmosBridgeOnce sync.Once
+)
+
+// the sdk bech32 config can be sealed only once, so share the bridge
+func newLocalCosmosBridge(t *testing.T) *cosmos.Bridge {
+	localCosmosBridgeOnce.Do(func() {
+		b := cosmos.NewCrossChainBridge()
+		b.SetPrefixAndDenom("cosmos", "stake")
+		b.SetChainConfig(&tokens.ChainConfig{
+			ChainID:    cosmos.GetStubChainID("COSMOSHUB", "devnet").String(),
+			BlockChain: LocalCosmosChain.Name,
+		})
+		b.SetGatewayConfig(LocalCosmosChain.GatewayConfig())
+		localCosmosBridge = b
+	})
+	b := localCosmosBridge
+	if _, err := LocalCosmosChain.WaitReady(b.GetLatestBlockNumberOf); err != nil {
+		t.Skip(err)
+	}
+	return b
+}
+
+func TestCosmosChainID(t *testing.T) {
+	b := newLocalCosmosBridge(t)
+	chainID, err := b.GetChainID()
+	if err != nil {
+		t.Fatalf("get chain id failed: %v", err)
+	}
+	if chainID != LocalCosmosChain.ChainID {
+		t.Fatalf("chain id mismatch, have %v want %v", chainID, LocalCosmosChain.ChainID)
+	}
+}
+
+func TestCosmosBlockProduction(t *testing.T) {
+	b := newLocalCosmosBridge(t)
+	height, err := b.GetLatestBlockNumber()
+	if err != nil {
+		t.Fatalf("get latest block number failed: %v", err)
+	}
+	if _, err = LocalCosmosChain.WaitNewBlock(b.GetLatestBlockNumberOf, height); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCosmosGenesisAccount(t *testing.T) {
+	b := newLocalCosmosBridge(t)
+	privKeyBytes, err := hd.Secp256k1.Derive()(LocalCosmosMnemonic, "", hd.CreateHDPath(118, 0, 0).String())
+	if err != nil {
+		t.Fatalf("derive validator key failed: %v", err)
+	}
+	pubKey := hd.Secp256k1.Generate()(privKeyBytes).PubKey()
+	validator, err := cosmos.PublicKeyToAddress("cosmos", hex.EncodeToString(pubKey.Bytes()))
+	if err != nil {
+		t.Fatalf("get validator address failed: %v", err)
+	}
+	if _, err = b.GetBaseAccount(validator); err != nil {
+		t.Fatalf("get validator account failed: %v", err)
+	}
+	balance, err := b.GetDenomBalance(validator, LocalCosmosDenom)
+	if err != nil {
+		t.Fatalf("get validator balance failed: %v", err)
+	}
+	if balance.Int64() != LocalCosmosGenesisBalance {
+		t.Fatalf("validator balance mismatch, have %v want %v", balance, LocalCosmosGenesisBalance)
+	}
+}
@@ -0,0 +1,211 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+// the swap txs are sent with fixed gas limit, so the failed txs are mined
+// (with failed status) instead of being rejected by gas estimation.
+const localEVMGasLimit = 300000
+
+func newLocalEVMBridge(t *testing.T) *eth.Bridge {
+	b := eth.NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{
+		ChainID:    LocalEVMChain.ChainID,
+		BlockChain: LocalEVMChain.Name,
+	})
+	b.SetGatewayConfig(LocalEVMChain.GatewayConfig())
+	if _, err := LocalEVMChain.WaitReady(b.GetLatestBlockNumberOf); err != nil {
+		t.Skip(err)
+	}
+	return b
+}
+
+func TestEVMChainID(t *testing.T) {
+	b := newLocalEVMBridge(t)
+	chainID, err := b.ChainID()
+	if err != nil {
+		t.Fatalf("get chain id failed: %v", err)
+	}
+	if chainID.String() != LocalEVMChain.ChainID {
+		t.Fatalf("chain id mismatch, have %v want %v", chainID, LocalEVMChain.ChainID)
+	}
+}
+
+func TestEVMBlockProduction(t *testing.T) {
+	b := newLocalEVMBridge(t)
+	height, err := b.GetLatestBlockNumber()
+	if err != nil {
+		t.Fatalf("get latest block number failed: %v", err)
+	}
+	if _, err = LocalEVMChain.WaitNewBlock(b.GetLatestBlockNumberOf, height); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = b.GetBlockByNumber(nil); err != nil {
+		t.Fatalf("get latest block failed: %v", err)
+	}
+}
+
+func TestEVMGasPrice(t *testing.T) {
+	b := newLocalEVMBridge(t)
+	gasPrice, err := b.SuggestPrice()
+	if err != nil {
+		t.Fatalf("suggest gas price failed: %v", err)
+	}
+	if gasPrice.Sign() <= 0 {
+		t.Fatalf("wrong gas price %v", gasPrice)
+	}
+}
+
+func newLocalEVMRouterBridge(t *testing.T) *eth.Bridge {
+	b := newLocalEVMBridge(t)
+	chainID, _ := common.GetBigIntFromStr(LocalEVMChain.ChainID)
+	b.SignerChainID = chainID
+	b.Signer = types.MakeSigner("London", chainID)
+	if err := LocalEVMChain.WaitContract(b.GetCode, LocalEVMRouter); err != nil {
+		t.Skip(err)
+	}
+	return b
+}
+
+func getLocalEVMAddress(t *testing.T, key string) common.Address {
+	privKey, err := crypto.ToECDSA(common.FromHex(key))
+	if err != nil {
+		t.Fatalf("wrong private key: %v", err)
+	}
+	return crypto.PubkeyToAddress(privKey.PublicKey)
+}
+
+func getFuncHash(signature string) []byte {
+	return common.Keccak256Hash([]byte(signature)).Bytes()[:4]
+}
+
+// sendLocalEVMTx send contract call and wait for the receipt
+func sendLocalEVMTx(t *testing.T, b *eth.Bridge, key, contract string, input []byte) *types.RPCTxReceipt {
+	from := getLocalEVMAddress(t, key)
+	nonce, err := b.GetPoolNonce(from.LowerHex(), "pending")
+	if err != nil {
+		t.Fatalf("get nonce failed: %v", err)
+	}
+	gasPrice, err := b.SuggestPrice()
+	if err != nil {
+		t.Fatalf("suggest gas price failed: %v", err)
+	}
+	rawTx := types.NewTransaction(nonce, common.HexToAddress(contract), big.NewInt(0), localEVMGasLimit, gasPrice, input)
+	signedTx, txHash, err := b.SignTransactionWithPrivateKey(rawTx, key)
+	if err != nil {
+		t.Fatalf("sign tx failed: %v", err)
+	}
+	if _, err = b.SendSignedTransaction(signedTx.(*types.Transaction)); err != nil {
+		t.Fatalf("send tx failed: %v", err)
+	}
+	deadline := time.Now().Add(GetReadyTimeout())
+	for time.Now().Before(deadline) {
+		receipt, errr := b.GetTransactionReceipt(txHash)
+		if errr == nil && receipt != nil {
+			return receipt
+		}
+		time.Sleep(time.Second)
+	}
+	t.Fatalf("tx %v is not mined", txHash)
+	return nil
+}
+
+func TestEVMRouterMPC(t *testing.T) {
+	b := newLocalEVMRouterBridge(t)
+	mpc, err := b.GetMPCAddress(LocalEVMRouter)
+	if err != nil {
+		t.Fatalf("get router mpc failed: %v", err)
+	}
+	if want := getLocalEVMAddress(t, LocalEVMMPCKey); !common.IsEqualIgnoreCase(mpc, want.LowerHex()) {
+		t.Fatalf("router mpc mismatch, have %v want %v", mpc, want.LowerHex())
+	}
+}
+
+func TestEVMRouterSwap(t *testing.T) {
+	b := newLocalEVMRouterBridge(t)
+	user := getLocalEVMAddress(t, LocalEVMUserKey)
+	receiver := getLocalEVMAddress(t, LocalEVMMPCKey) // any address not holding the token before
+	token := common.HexToAddress(LocalEVMToken)
+	amount := big.NewInt(1e18)
+	fromChainID, _ := common.GetBigIntFromStr(LocalEVMChain.ChainID)
+	toChainID := big.NewInt(5777)
+
+	userBalance, err := b.GetErc20Balance(LocalEVMToken, user.LowerHex())
+	if err != nil || userBalance.Cmp(amount) < 0 {
+		t.Fatalf("wrong test token balance %v, err %v", userBalance, err)
+	}
+	receiverBalance, err := b.GetErc20Balance(LocalEVMToken, receiver.LowerHex())
+	if err != nil {
+		t.Fatalf("get receiver balance failed: %v", err)
+	}
+
+	// swapout: burn the token and emit the log verified by the bridge
+	input := abicoder.PackDataWithFuncHash(getFuncHash("anySwapOut(address,string,uint256,uint256)"),
+		token, receiver.LowerHex(), amount, toChainID)
+	receipt := sendLocalEVMTx(t, b, LocalEVMUserKey, LocalEVMRouter, input)
+	if !receipt.IsStatusOk() {
+		t.Fatalf("swapout tx %v failed", receipt.TxHash.Hex())
+	}
+	var swapoutLog *types.RPCLog
+	for _, rlog := range receipt.Logs {
+		if len(rlog.Topics) == 3 && bytes.Equal(rlog.Topics[0].Bytes(), eth.LogAnySwapOut2Topic) {
+			swapoutLog = rlog
+		}
+	}
+	if swapoutLog == nil {
+		t.Fatalf("swapout log not found in tx %v", receipt.TxHash.Hex())
+	}
+	if common.BytesToAddress(swapoutLog.Topics[1].Bytes()) != token ||
+		common.BytesToAddress(swapoutLog.Topics[2].Bytes()) != user {
+		t.Errorf("wrong swapout log topics %v", swapoutLog.Topics)
+	}
+	logData := *swapoutLog.Data
+	bind, err := abicoder.ParseStringInData(logData, 0)
+	if err != nil || !common.IsEqualIgnoreCase(bind, receiver.LowerHex()) {
+		t.Errorf("wrong swapout bind %v, err %v", bind, err)
+	}
+	if common.GetBigInt(logData, 32, 32).Cmp(amount) != 0 ||
+		common.GetBigInt(logData, 64, 32).Cmp(fromChainID) != 0 ||
+		common.GetBigInt(logData, 96, 32).Cmp(toChainID) != 0 {
+		t.Errorf("wrong swapout log data %x", logData)
+	}
+	balance, _ := b.GetErc20Balance(LocalEVMToken, user.LowerHex())
+	if new(big.Int).Sub(userBalance, balance).Cmp(amount) != 0 {
+		t.Errorf("swapout amount is not burned, balance %v -> %v", userBalance, balance)
+	}
+
+	// swapin: the mpc mints the token to the receiver
+	swapinInput := abicoder.PackDataWithFuncHash(eth.AnySwapInFuncHash,
+		*receipt.TxHash, token, receiver, amount, fromChainID)
+	receipt = sendLocalEVMTx(t, b, LocalEVMMPCKey, LocalEVMRouter, swapinInput)
+	if !receipt.IsStatusOk() {
+		t.Fatalf("swapin tx %v failed", receipt.TxHash.Hex())
+	}
+	balance, _ = b.GetErc20Balance(LocalEVMToken, receiver.LowerHex())
+	if new(big.Int).Sub(balance, receiverBalance).Cmp(amount) != 0 {
+		t.Errorf("swapin amount is not minted, balance %v -> %v", receiverBalance, balance)
+	}
+
+	// the same swapin can not be done twice, and only mpc can swapin
+	if receipt = sendLocalEVMTx(t, b, LocalEVMMPCKey, LocalEVMRouter, swapinInput); receipt.IsStatusOk() {
+		t.Errorf("replayed swapin tx %v should fail", receipt.TxHash.Hex())
+	}
+	swapinInput = abicoder.PackDataWithFuncHash(eth.AnySwapInFuncHash,
+		common.HexToHash("0x01"), token, user, amount, fromChainID)
+	if receipt = sendLocalEVMTx(t, b, LocalEVMUserKey, LocalEVMRouter, swapinInput); receipt.IsStatusOk() {
+		t.Errorf("swapin tx %v not sent by mpc should fail", receipt.TxHash.Hex())
+	}
+}
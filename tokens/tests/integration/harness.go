@@ -0,0 +1,134 @@
+// Package integration provides the harness of integration tests running
+// against dockerized local chains (see docker-compose.yml).
+package integration
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// LocalChain local chain info
+type LocalChain struct {
+	Name     string
+	ChainID  string
+	Endpoint string
+}
+
+// default local chains started by docker-compose.yml
+var (
+	LocalEVMChain = &LocalChain{
+		Name:     "anvil",
+		ChainID:  "31337",
+		Endpoint: getEnvOr("ROUTER_IT_EVM_RPC", "http://127.0.0.1:8545"),
+	}
+
+	LocalRippleChain = &LocalChain{
+		Name:     "rippled",
+		Endpoint: getEnvOr("ROUTER_IT_RIPPLE_RPC", "http://127.0.0.1:5005"),
+	}
+
+	LocalCosmosChain = &LocalChain{
+		Name:     "simd",
+		ChainID:  "localnet-1",
+		Endpoint: getEnvOr("ROUTER_IT_COSMOS_API", "http://127.0.0.1:1317"),
+	}
+
+	defaultReadyTimeout = 120 * time.Second
+	readyCheckInterval  = 2 * time.Second
+)
+
+// accounts and contracts of the local evm chain, the keys are the development
+// keys of anvil, the contracts are deployed by contracts/deploy.sh.
+const (
+	LocalEVMUserKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80" // deployer and test token holder
+	LocalEVMMPCKey  = "0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d" // mpc of the router contract
+	LocalEVMToken   = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	LocalEVMRouter  = "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"
+)
+
+// genesis accounts of the local chains
+const (
+	// genesis account of rippled standalone mode
+	LocalRippleGenesisAccount = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	LocalRippleGenesisSeed    = "snoPBrXtMeMyMHUVTgbuqAfg1SUTb"
+
+	// the validator of simd is recovered from this mnemonic (see docker-compose.yml)
+	LocalCosmosMnemonic       = "test test test test test test test test test test test junk"
+	LocalCosmosDenom          = "stake"
+	LocalCosmosGenesisBalance = 100000000000 - 1000000000 // genesis minus self delegation
+)
+
+func getEnvOr(key, defVal string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return defVal
+}
+
+// GetReadyTimeout get ready timeout
+func GetReadyTimeout() time.Duration {
+	if val := os.Getenv("ROUTER_IT_READY_TIMEOUT"); val != "" {
+		if secs, err := strconv.Atoi(val); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	return defaultReadyTimeout
+}
+
+// GatewayConfig get gateway config of local chain
+func (c *LocalChain) GatewayConfig() *tokens.GatewayConfig {
+	return &tokens.GatewayConfig{
+		APIAddress: []string{c.Endpoint},
+	}
+}
+
+// WaitReady wait until local chain produces blocks
+func (c *LocalChain) WaitReady(getLatestBlock func(url string) (uint64, error)) (uint64, error) {
+	var (
+		height uint64
+		err    error
+	)
+	deadline := time.Now().Add(GetReadyTimeout())
+	for time.Now().Before(deadline) {
+		height, err = getLatestBlock(c.Endpoint)
+		if err == nil && height > 0 {
+			return height, nil
+		}
+		time.Sleep(readyCheckInterval)
+	}
+	return 0, fmt.Errorf("local chain %v at %v is not ready: %w", c.Name, c.Endpoint, err)
+}
+
+// WaitContract wait until the contract is deployed on local chain
+func (c *LocalChain) WaitContract(getCode func(contract string) ([]byte, error), contract string) error {
+	var (
+		code []byte
+		err  error
+	)
+	deadline := time.Now().Add(GetReadyTimeout())
+	for time.Now().Before(deadline) {
+		code, err = getCode(contract)
+		if err == nil && len(code) > 0 {
+			return nil
+		}
+		time.Sleep(readyCheckInterval)
+	}
+	return fmt.Errorf("contract %v is not deployed on local chain %v: %v", contract, c.Name, err)
+}
+
+// WaitNewBlock wait until local chain height is greater than the specified height
+func (c *LocalChain) WaitNewBlock(getLatestBlock func(url string) (uint64, error), height uint64) (uint64, error) {
+	deadline := time.Now().Add(GetReadyTimeout())
+	for time.Now().Before(deadline) {
+		latest, err := getLatestBlock(c.Endpoint)
+		if err == nil && latest > height {
+			return latest, nil
+		}
+		time.Sleep(readyCheckInterval)
+	}
+	return 0, fmt.Errorf("local chain %v has no new block after height %v", c.Name, height)
+}
@@ -0,0 +1,128 @@
+//go:build integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/websockets"
+)
+
+func newLocalRippleBridge(t *testing.T) *ripple.Bridge {
+	b := ripple.NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{
+		ChainID:    ripple.GetStubChainID("devnet").String(),
+		BlockChain: LocalRippleChain.Name,
+	})
+	b.SetGatewayConfig(LocalRippleChain.GatewayConfig())
+	if _, err := LocalRippleChain.WaitReady(b.GetLatestBlockNumberOf); err != nil {
+		t.Skip(err)
+	}
+	return b
+}
+
+func TestRippleLedgerCurrent(t *testing.T) {
+	b := newLocalRippleBridge(t)
+	if _, err := b.GetLatestBlockNumber(); err != nil {
+		t.Fatalf("get current ledger failed: %v", err)
+	}
+}
+
+func TestRippleGenesisAccount(t *testing.T) {
+	b := newLocalRippleBridge(t)
+	balance, err := b.GetBalance(LocalRippleGenesisAccount)
+	if err != nil {
+		t.Fatalf("get genesis account balance failed: %v", err)
+	}
+	if balance.Sign() <= 0 {
+		t.Fatalf("wrong genesis account balance %v", balance)
+	}
+}
+
+func TestRippleFee(t *testing.T) {
+	b := newLocalRippleBridge(t)
+	if _, err := b.GetFee(); err != nil {
+		t.Fatalf("get fee failed: %v", err)
+	}
+}
+
+// acceptLocalRippleLedger close the current ledger, rippled standalone mode
+// does not close ledgers by itself.
+func acceptLocalRippleLedger(t *testing.T) {
+	var result interface{}
+	if err := client.RPCPost(&result, LocalRippleChain.Endpoint, "ledger_accept", map[string]interface{}{}); err != nil {
+		t.Fatalf("accept ledger failed: %v", err)
+	}
+}
+
+// TestRippleSwapoutPayment a swapout on ripple is a payment to the mpc with the bind address and
+// the destination chain id in memo, the mpc account is created by the payment of the test.
+func TestRippleSwapoutPayment(t *testing.T) {
+	b := newLocalRippleBridge(t)
+	keyseq := uint32(0)
+	key, err := ripple.ImportKeyFromSeed(LocalRippleGenesisSeed, "ecdsa")
+	if err != nil {
+		t.Fatalf("import genesis key failed: %v", err)
+	}
+	if address := ripple.GetAddress(key, &keyseq); address != LocalRippleGenesisAccount {
+		t.Fatalf("genesis account mismatch, have %v want %v", address, LocalRippleGenesisAccount)
+	}
+	mpcKey := crypto.NewECDSAKeyFromPrivKeyBytes(common.FromHex(LocalEVMMPCKey))
+	mpcAccount := ripple.GetAddress(mpcKey, nil)
+
+	sequence, err := b.GetPoolNonce(LocalRippleGenesisAccount, "pending")
+	if err != nil {
+		t.Fatalf("get genesis account sequence failed: %v", err)
+	}
+	memo := getLocalEVMAddress(t, LocalEVMUserKey).LowerHex() + ":" + LocalEVMChain.ChainID
+	rawTx, err := ripple.NewUnsignedPaymentTransaction(key, &keyseq, uint32(sequence), 0,
+		mpcAccount, nil, "100000000", "10", memo, "", 0, nil, nil)
+	if err != nil {
+		t.Fatalf("build payment tx failed: %v", err)
+	}
+	signedTx, txHash, err := b.SignTransactionWithRippleKey(rawTx, key, &keyseq)
+	if err != nil {
+		t.Fatalf("sign payment tx failed: %v", err)
+	}
+	if _, err = b.SendTransaction(signedTx); err != nil {
+		t.Fatalf("send payment tx failed: %v", err)
+	}
+	acceptLocalRippleLedger(t)
+
+	var txRes interface{}
+	deadline := time.Now().Add(GetReadyTimeout())
+	for time.Now().Before(deadline) {
+		if txRes, err = b.GetTransaction(txHash); err == nil {
+			break
+		}
+		acceptLocalRippleLedger(t)
+		time.Sleep(time.Second)
+	}
+	if err != nil {
+		t.Fatalf("get payment tx %v failed: %v", txHash, err)
+	}
+	tx := txRes.(*websockets.TxResult)
+	if !tx.MetaData.TransactionResult.Success() {
+		t.Fatalf("payment tx %v failed with result %v", txHash, tx.MetaData.TransactionResult)
+	}
+	payment, ok := tx.Transaction.(*data.Payment)
+	if !ok {
+		t.Fatalf("tx %v is not a payment", txHash)
+	}
+	amount, _ := data.NewAmount(int64(100000000))
+	if payment.Destination.String() != mpcAccount || !payment.Amount.Equals(*amount) ||
+		len(payment.Memos) != 1 || string(payment.Memos[0].Memo.MemoData) != memo {
+		t.Errorf("wrong payment %v to %v amount %v", txHash, payment.Destination.String(), payment.Amount.String())
+	}
+	balance, err := b.GetBalance(mpcAccount)
+	if err != nil || balance.Int64() != 100000000 {
+		t.Errorf("wrong mpc account balance %v, err %v", balance, err)
+	}
+}
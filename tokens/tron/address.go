@@ -2,13 +2,14 @@ package tron
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
 
 	tronaddress "github.com/fbsobreira/gotron-sdk/pkg/address"
@@ -23,6 +24,8 @@ var (
 
 	contractCodeHashes    = make(map[string]common.Hash)
 	maxContractCodeHashes = 2000
+
+	errEmptyContractCode = errors.New("empty contract code")
 )
 
 // IsValidAddress check address
@@ -91,15 +94,19 @@ func (b *Bridge) GetContractCodeHash(contract string) common.Hash {
 }
 
 func (b *Bridge) getContractCode(contract string) (code []byte, err error) {
-	for i := 0; i < retryRPCCount; i++ {
+	err = tokens.RetryRPC(b.ChainConfig.ChainID, func() error {
 		code, err = b.GetCode(contract)
-		if err == nil && len(code) > 1 {
-			return code, nil
-		}
 		if err != nil {
 			log.Warn("get contract code failed", "contract", contract, "err", err)
+			return err
+		}
+		if len(code) <= 1 {
+			return errEmptyContractCode
 		}
-		time.Sleep(retryRPCInterval)
+		return nil
+	})
+	if errors.Is(err, errEmptyContractCode) {
+		return code, nil
 	}
 	return code, err
 }
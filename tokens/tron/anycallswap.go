@@ -243,13 +243,10 @@ func (b *Bridge) checkAnyCallSwapInfo(swapInfo *tokens.SwapTxInfo) error {
 		callFrom := getCallFrom(swapInfo)
 		routerContract := b.GetRouterContract("")
 		var budgetBalance *big.Int
-		var err error
-		for i := 0; i < 3; i++ {
+		err := tokens.RetryRPC(b.ChainConfig.ChainID, func() (err error) {
 			budgetBalance, err = b.GetExecutionBudget(routerContract, callFrom)
-			if err == nil {
-				break
-			}
-		}
+			return err
+		})
 		if err != nil {
 			return fmt.Errorf("get budget error: %w", err)
 		}
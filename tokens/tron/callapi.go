@@ -256,6 +256,7 @@ type rpcGetTxInfoRes struct {
 	Receipt         map[string]interface{}   `json:"receipt"`
 	Result          string                   `json:"result,omitempty"`
 	ResultMsg       string                   `json:"resMessage,omitempty"`
+	Fee             uint64                   `json:"fee,omitempty"`
 	Log             rpcLogSlice              `json:"log"`
 	InternalTxs     []map[string]interface{} `json:"internal_transactions"`
 }
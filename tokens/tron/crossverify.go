@@ -0,0 +1,29 @@
+package tron
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var _ tokens.TxCrossVerifier = &Bridge{}
+
+// CrossVerifyTx impl tokens.TxCrossVerifier
+func (b *Bridge) CrossVerifyTx(txHash string, quorum int) error {
+	return tokens.CrossVerifyTx(b.ChainConfig.ChainID, txHash, b.GatewayConfig.AllGatewayURLs, quorum, func(endpoint string) (string, error) {
+		apiurl := strings.TrimSuffix(endpoint, "/") + `/wallet/gettransactionbyid`
+		res, err := post(apiurl, `{"value":"`+txHash+`"}`)
+		if err != nil {
+			return "", err
+		}
+		var txi rpcGetTxRes
+		if err = json.Unmarshal(res, &txi); err != nil {
+			return "", err
+		}
+		if txi.TxID == "" {
+			return "", tokens.ErrTxNotFound
+		}
+		return tokens.TxFingerprint([]interface{}{txi.TxID, txi.Ret, txi.RawDataHex})
+	})
+}
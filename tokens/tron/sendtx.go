@@ -7,10 +7,14 @@ import (
 	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
 )
 
 // SendTransaction send signed tx
 func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	if params.IsWatcherMode {
+		return "", params.ErrWatcherMode
+	}
 	tx, ok := signedTx.(*core.Transaction)
 	if !ok {
 		fmt.Printf("signed tx is %+v\n", signedTx)
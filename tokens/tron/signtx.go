@@ -23,6 +23,22 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
 )
 
+// chainFamily chain family in the mpc sign scheme registry
+const chainFamily = "tron"
+
+func init() {
+	mpc.RegisterChainFamily(chainFamily, mpc.SchemeSecp256k1Sha256)
+}
+
+// GetMPCSignKey impl tokens.MPCSchemeSigner
+func (b *Bridge) GetMPCSignKey(mpc string) (family, signPubkey string, err error) {
+	mpcPubkey := router.GetMPCPublicKey(mpc)
+	if mpcPubkey == "" {
+		return "", "", tokens.ErrMissMPCPublicKey
+	}
+	return chainFamily, mpcPubkey, nil
+}
+
 func getTriggerSmartContract(tx *core.Transaction) (*core.TriggerSmartContract, error) {
 	rawdata := tx.GetRawData()
 	contracts := rawdata.GetContract()
@@ -57,6 +73,9 @@ func (b *Bridge) verifyTransactionReceiver(rawTx interface{}, tokenID string) (*
 	if !strings.EqualFold(txRecipient, checkReceiver) {
 		return nil, fmt.Errorf("[sign] tx receiver mismatch. have %v want %v", txRecipient, checkReceiver)
 	}
+	if err = tokens.CheckDestMethods(b.ChainConfig.ChainID, tokens.GetMethodSelector(contract.Data)); err != nil {
+		return nil, fmt.Errorf("[sign] %w", err)
+	}
 	return tx, nil
 }
 
@@ -86,26 +105,13 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 	logPrefix := b.ChainConfig.BlockChain + " MPCSignTransaction "
 	log.Info(logPrefix+"start", "txid", txid, "msghash", txHash)
 	mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
-	keyID, rsvs, err := mpcConfig.DoSignOneEC(mpcPubkey, txHash, msgContext)
+	keyID, signature, err := mpcConfig.DoSignWithScheme(chainFamily, mpcPubkey, common.FromHex(txHash), msgContext)
 	if err != nil {
 		log.Info(logPrefix+"failed", "keyID", keyID, "txid", txid, "err", err)
 		return nil, "", err
 	}
 	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "msghash", txHash)
-
-	if len(rsvs) != 1 {
-		log.Warn("get sign status require one rsv but return many",
-			"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
-		return nil, "", errors.New("get sign status require one rsv but return many")
-	}
-
-	rsv := rsvs[0]
-	log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", rsv)
-	signature := common.FromHex(rsv)
-	if len(signature) != crypto.SignatureLength {
-		log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(signature), "want", crypto.SignatureLength)
-		return nil, "", errors.New("wrong signature length")
-	}
+	log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", common.ToHex(signature))
 
 	tx.Signature = append(tx.Signature, signature)
 	signedTx := tx
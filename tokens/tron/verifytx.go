@@ -3,6 +3,7 @@ package tron
 import (
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
@@ -26,9 +27,17 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 	status.BlockHeight = txInfo.BlockNumber
 	status.BlockTime = txInfo.BlockTimeStamp
 	status.BlockHash = txInfo.TxID
+	status.FeePaid = fmt.Sprintf("%v", txInfo.Fee)
+	if !txInfo.IsStatusOk() {
+		reason, _ := txInfo.Receipt["result"].(string)
+		if txInfo.ResultMsg != "" {
+			reason = fmt.Sprintf("%v: %v", reason, txInfo.ResultMsg)
+		}
+		status.SetFailed(reason)
+	}
 
 	if latest, err := b.GetLatestBlockNumber(); err == nil {
-		status.Confirmations = latest - status.BlockHeight
+		status.SetLatestHeight(latest, b.GetChainConfig().Confirmations)
 	}
 	return status, nil
 }
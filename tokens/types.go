@@ -21,6 +21,9 @@ const (
 	// special flags, do not use in register
 	ERC20SwapTypeMixPool
 	SapphireRPCType
+	DepositSweepType
+	ClaimRefundType
+	AllowanceApproveType
 
 	MaxValidSwapType
 )
@@ -55,6 +58,12 @@ func (s SwapType) String() string {
 		return "mixpool"
 	case SapphireRPCType:
 		return "sapphireRPCType"
+	case DepositSweepType:
+		return "depositSweep"
+	case ClaimRefundType:
+		return "claimRefund"
+	case AllowanceApproveType:
+		return "allowanceApprove"
 	default:
 		return "unknownswap"
 	}
@@ -65,6 +74,90 @@ func (s SwapType) IsValidType() bool {
 	return s > UnknownSwapType && s < MaxValidSwapType
 }
 
+// ClaimStatus onchain status of claimable swap
+type ClaimStatus uint8
+
+// ClaimStatus constants
+const (
+	ClaimNotExist ClaimStatus = iota
+	ClaimPending
+	ClaimClaimed
+	ClaimRefunded
+)
+
+func (s ClaimStatus) String() string {
+	switch s {
+	case ClaimNotExist:
+		return "notExist"
+	case ClaimPending:
+		return "pending"
+	case ClaimClaimed:
+		return "claimed"
+	case ClaimRefunded:
+		return "refunded"
+	default:
+		return "unknown"
+	}
+}
+
+// ClaimInfo onchain info of claimable swap
+type ClaimInfo struct {
+	Status ClaimStatus
+	Expiry int64 // seconds
+	Token  string
+}
+
+// transfer directions of account
+const (
+	TransferIn  = "in"
+	TransferOut = "out"
+)
+
+// AccountTransfer tx of account re-derived from chain history
+type AccountTransfer struct {
+	TxHash       string
+	Height       uint64
+	Timestamp    uint64
+	TxType       string
+	Success      bool
+	Fee          string
+	Direction    string   `json:",omitempty"` // empty for txs without transfer
+	Counterparty string   `json:",omitempty"`
+	Amount       string   `json:",omitempty"` // chain representation
+	Token        string   `json:",omitempty"`
+	Value        *big.Int `json:",omitempty"` // in token decimals (nil if token is not configured)
+	// swap re-derived from deposit
+	SwapInfo    *SwapTxInfo `json:",omitempty"`
+	VerifyError string      `json:",omitempty"`
+}
+
+// FaucetTxArgs args of building testnet faucet tx
+type FaucetTxArgs struct {
+	Faucet    string
+	Token     string
+	Receiver  string   // mint receiver or bind address of swap
+	Amount    *big.Int // in the smallest unit of token
+	ToChainID *big.Int // nil means minting, otherwise swapping out to the chain
+	Nonce     uint64
+	GasLimit  uint64 // zero means estimating gas
+}
+
+// TokenMigrationReport status of token contract address migration
+type TokenMigrationReport struct {
+	TokenID       string
+	OldToken      string
+	NewToken      string
+	CutoverHeight uint64
+	GraceBlocks   uint64
+	LatestHeight  uint64
+	GraceEnded    bool
+	// remainder of the old token which should be swept manually
+	OldUnderlying        string   `json:",omitempty"`
+	OldUnderlyingBalance *big.Int `json:",omitempty"`
+	OldTotalSupply       *big.Int `json:",omitempty"`
+	Error                string   `json:",omitempty"`
+}
+
 // ERC20SwapInfo struct
 type ERC20SwapInfo struct {
 	Token     string `json:"token"`
@@ -147,12 +240,20 @@ type SwapTxInfo struct {
 }
 
 // TxStatus struct
+// BlockHeight is the included height (zero if not on chain),
+// Finalized is whether the confirmations reach the required of the chain,
+// Failed is whether the tx is on chain but failed (FailReason is the reason),
+// FeePaid is the effective fee paid in the smallest unit of the fee coin.
 type TxStatus struct {
 	Receipt       interface{} `json:"receipt,omitempty"`
 	Confirmations uint64      `json:"confirmations"`
 	BlockHeight   uint64      `json:"block_height"`
 	BlockHash     string      `json:"block_hash"`
 	BlockTime     uint64      `json:"block_time,omitempty"`
+	Finalized     bool        `json:"finalized"`
+	Failed        bool        `json:"failed,omitempty"`
+	FailReason    string      `json:"fail_reason,omitempty"`
+	FeePaid       string      `json:"fee_paid,omitempty"`
 }
 
 // StatusInterface interface
@@ -160,6 +261,27 @@ type StatusInterface interface {
 	IsStatusOk() bool
 }
 
+// SetConfirmations set confirmations and finalized flag by the required confirmations
+func (s *TxStatus) SetConfirmations(confirmations, required uint64) {
+	s.Confirmations = confirmations
+	s.Finalized = s.BlockHeight > 0 && confirmations >= required
+}
+
+// SetLatestHeight set confirmations and finalized flag by the latest height
+func (s *TxStatus) SetLatestHeight(latest, required uint64) {
+	var confirmations uint64
+	if latest > s.BlockHeight {
+		confirmations = latest - s.BlockHeight
+	}
+	s.SetConfirmations(confirmations, required)
+}
+
+// SetFailed set tx is on chain but failed
+func (s *TxStatus) SetFailed(reason string) {
+	s.Failed = true
+	s.FailReason = reason
+}
+
 // IsSwapTxOnChain is tx onchain
 func (s *TxStatus) IsSwapTxOnChain() bool {
 	return s != nil && s.BlockHeight > 0
@@ -167,15 +289,17 @@ func (s *TxStatus) IsSwapTxOnChain() bool {
 
 // IsSwapTxOnChainAndFailed to make failed of swaptx
 func (s *TxStatus) IsSwapTxOnChainAndFailed() bool {
-	if !s.IsSwapTxOnChain() {
-		return false // not on chain
-	}
-	if status, ok := s.Receipt.(StatusInterface); ok {
-		if !status.IsStatusOk() {
-			return true
-		}
-	}
-	return false
+	return s.IsSwapTxOnChain() && s.Failed
+}
+
+// IsSwapTxOnChainAndSucceed is tx on chain and not failed
+func (s *TxStatus) IsSwapTxOnChainAndSucceed() bool {
+	return s.IsSwapTxOnChain() && !s.Failed
+}
+
+// IsFinalized is tx on chain and finalized
+func (s *TxStatus) IsFinalized() bool {
+	return s.IsSwapTxOnChain() && s.Finalized
 }
 
 // VerifyArgs struct
@@ -183,6 +307,12 @@ type VerifyArgs struct {
 	SwapType      SwapType `json:"swaptype,omitempty"`
 	LogIndex      int      `json:"logIndex,omitempty"`
 	AllowUnstable bool     `json:"allowUnstable,omitempty"`
+	// PinnedHeight verify against the chain state at this height if supported (0 means latest)
+	PinnedHeight uint64 `json:"pinnedHeight,omitempty"`
+	// Bind and ToChainID are hints of swaps which can not be recovered from the tx itself
+	// (ie. deposit address swaps without database), bridges must verify them before usage
+	Bind      string   `json:"bind,omitempty"`
+	ToChainID *big.Int `json:"toChainID,omitempty"`
 }
 
 // RegisterArgs struct
@@ -195,6 +325,7 @@ type RegisterArgs struct {
 type SwapArgs struct {
 	SwapInfo    `json:"swapinfo"`
 	Identifier  string   `json:"identifier,omitempty"`
+	Salt        string   `json:"salt,omitempty"`
 	SwapID      string   `json:"swapid,omitempty"`
 	SwapType    SwapType `json:"swaptype,omitempty"`
 	Bind        string   `json:"bind,omitempty"`
@@ -202,6 +333,11 @@ type SwapArgs struct {
 	FromChainID *big.Int `json:"fromChainID"`
 	ToChainID   *big.Int `json:"toChainID"`
 	Reswapping  bool     `json:"reswapping,omitempty"`
+	TxHeight    uint64   `json:"txHeight,omitempty"`
+	// conversion rate (scaled by 1e18) of cross-asset route pinned when building
+	ConversionRate *big.Int `json:"conversionRate,omitempty"`
+	// percent of minimum swap fees scaled by delivery costs pinned when building (0 is unscaled)
+	DeliveryFeePercent uint64 `json:"deliveryFeePercent,omitempty"`
 }
 
 // BuildTxArgs struct
@@ -218,6 +354,7 @@ type BuildTxArgs struct {
 	Selector    string         `json:"selector,omitempty"`
 	Input       *hexutil.Bytes `json:"input,omitempty"`
 	Extra       *AllExtras     `json:"extra,omitempty"`
+	SignExpiry  int64          `json:"signExpiry,omitempty"` // unix seconds
 }
 
 // AllExtras struct
@@ -235,6 +372,34 @@ type AllExtras struct {
 	BlockNumber *uint64       `json:"blockNumber,omitempty"`
 	TTL         *uint64       `json:"ttl,omitempty"`
 	BridgeFee   *big.Int      `json:"bridgeFee,omitempty"`
+	BurnAmount  *big.Int      `json:"burnAmount,omitempty"`
+	Expiration  *uint64       `json:"expiration,omitempty"` // unix seconds, expiration of claimable payouts (eg. ripple checks)
+
+	// deliver directly instead of by claimable payouts (eg. redelivery of cancelled ripple checks)
+	DirectPayout bool `json:"directPayout,omitempty"`
+
+	UserOpSponsor *UserOpSponsor     `json:"userOpSponsor,omitempty"`
+	Authorization *SwapAuthorization `json:"authorization,omitempty"`
+
+	BuilderVersion uint64 `json:"builderVersion,omitempty"`
+}
+
+// UserOpSponsor paymaster sponsorship of erc-4337 user operation,
+// the paymaster signs a new time-limited sponsorship on every request,
+// so it is fetched once by the swap server and pinned in the sign request.
+type UserOpSponsor struct {
+	PaymasterAndData     hexutil.Bytes `json:"paymasterAndData"`
+	PreVerificationGas   uint64        `json:"preVerificationGas"`
+	VerificationGasLimit uint64        `json:"verificationGasLimit"`
+	CallGasLimit         uint64        `json:"callGasLimit"`
+}
+
+// SwapAuthorization EIP-712 signed authorization of swap tx required by router contract
+type SwapAuthorization struct {
+	Nonce     uint64        `json:"nonce"`
+	Deadline  uint64        `json:"deadline"` // unix seconds
+	Hash      string        `json:"hash"`     // typed data hash
+	Signature hexutil.Bytes `json:"signature,omitempty"`
 }
 
 // GetReplaceNum get rplace swap count
@@ -255,10 +420,30 @@ func (args *BuildTxArgs) GetExtraArgs() *BuildTxArgs {
 		swapArgs.SwapInfo.AnyCallSwapInfo = &anycallInfo
 	}
 	return &BuildTxArgs{
-		From:     args.From,
-		SwapArgs: swapArgs,
-		Extra:    args.Extra,
+		From:       args.From,
+		SwapArgs:   swapArgs,
+		Extra:      args.Extra,
+		SignExpiry: args.SignExpiry,
+	}
+}
+
+// ApplyConversionRate convert swap value by the pinned conversion rate of cross-asset route
+func (args *BuildTxArgs) ApplyConversionRate(value *big.Int) *big.Int {
+	if args.ConversionRate == nil || value == nil {
+		return value
 	}
+	result := new(big.Int).Mul(value, args.ConversionRate)
+	return result.Div(result, RateUnit)
+}
+
+// CalcSwapValue calc swap value with the pinned delivery fee percent
+func (args *BuildTxArgs) CalcSwapValue(tokenID, fromChainID, toChainID string, value *big.Int, fromDecimals, toDecimals uint8, originFrom, originTxTo string) *big.Int {
+	return calcSwapValue(tokenID, fromChainID, toChainID, value, fromDecimals, toDecimals, originFrom, originTxTo, args.DeliveryFeePercent)
+}
+
+// IsSignExpired is sign request expired
+func (args *BuildTxArgs) IsSignExpired(now int64) bool {
+	return args.SignExpiry > 0 && now > args.SignExpiry
 }
 
 // GetTxNonce get tx nonce
@@ -279,6 +464,7 @@ func (args *BuildTxArgs) SetTxNonce(nonce uint64) {
 }
 
 // GetUniqueSwapIdentifier get unique swap identifier
+// the deployment salt is appended if exist to isolate deployments
 func (args *BuildTxArgs) GetUniqueSwapIdentifier() string {
 	fromChainID := args.FromChainID
 	swapID := args.SwapID
@@ -286,5 +472,16 @@ func (args *BuildTxArgs) GetUniqueSwapIdentifier() string {
 	if common.IsHexHash(swapID) {
 		swapID = common.HexToHash(swapID).Hex()
 	}
+	if args.Salt != "" {
+		return fmt.Sprintf("%v:%v:%v:%v", fromChainID, swapID, logIndex, args.Salt)
+	}
 	return fmt.Sprintf("%v:%v:%v", fromChainID, swapID, logIndex)
 }
+
+// GasSamples gas used by stable swap txs of token and swap method (oldest first)
+type GasSamples struct {
+	ChainID string   `json:"chainID"`
+	TokenID string   `json:"tokenID"`
+	Method  string   `json:"method"`
+	Samples []uint64 `json:"samples"`
+}
@@ -0,0 +1,61 @@
+package tokens
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestGetUniqueSwapIdentifier(t *testing.T) {
+	args := &BuildTxArgs{SwapArgs: SwapArgs{
+		FromChainID: big.NewInt(1),
+		SwapID:      "0x00000000000000000000000000000000000000000000000000000000000000ab",
+		LogIndex:    2,
+	}}
+	if got, want := args.GetUniqueSwapIdentifier(), "1:0x00000000000000000000000000000000000000000000000000000000000000ab:2"; got != want {
+		t.Errorf("identifier without salt got %v, want %v", got, want)
+	}
+	args.Salt = "deployment-a"
+	if got, want := args.GetUniqueSwapIdentifier(), "1:0x00000000000000000000000000000000000000000000000000000000000000ab:2:deployment-a"; got != want {
+		t.Errorf("identifier with salt got %v, want %v", got, want)
+	}
+}
+
+func TestTxStatus(t *testing.T) {
+	var nilStatus *TxStatus
+	if nilStatus.IsSwapTxOnChain() || nilStatus.IsSwapTxOnChainAndFailed() || nilStatus.IsFinalized() {
+		t.Errorf("nil tx status should not be on chain")
+	}
+
+	tests := []struct {
+		blockHeight uint64
+		latest      uint64
+		failReason  string
+		onChainOk   bool
+		failed      bool
+		finalized   bool
+	}{
+		{0, 100, "", false, false, false},
+		{95, 100, "", true, false, false},
+		{90, 100, "", true, false, true},
+		{90, 100, "out of gas", false, true, true},
+		// latest height behind the included height
+		{110, 100, "", true, false, false},
+	}
+	for i, test := range tests {
+		status := &TxStatus{BlockHeight: test.blockHeight}
+		status.SetLatestHeight(test.latest, 10)
+		if test.failReason != "" {
+			status.SetFailed(test.failReason)
+		}
+		if status.IsSwapTxOnChainAndSucceed() != test.onChainOk ||
+			status.IsSwapTxOnChainAndFailed() != test.failed ||
+			status.IsFinalized() != test.finalized {
+			t.Errorf("test %v: tx status %+v got (succeed %v, failed %v, finalized %v), want (%v, %v, %v)", i, status,
+				status.IsSwapTxOnChainAndSucceed(), status.IsSwapTxOnChainAndFailed(), status.IsFinalized(),
+				test.onChainOk, test.failed, test.finalized)
+		}
+		if test.failed && status.FailReason != test.failReason {
+			t.Errorf("test %v: fail reason got %v, want %v", i, status.FailReason, test.failReason)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package fifo
+
+import (
+	"sync"
+)
+
+// FairQueue weighted round-robin queue of items grouped by key.
+// items of the same key are in fifo order, and keys take turns
+// in the order they become active, each takes at most its weight
+// of items in turn.
+type FairQueue struct {
+	queues map[string]*Queue
+	keys   []string // active keys in round-robin order
+	cursor int      // index of the key in turn
+	served int      // items taken by the key in turn
+	count  int
+	weight func(key string) int
+	lock   sync.Mutex
+}
+
+// NewFairQueue creates a new and empty *fifo.FairQueue,
+// weight of key is 1 if weight func is nil or returns non positive.
+func NewFairQueue(weight func(key string) int) *FairQueue {
+	return &FairQueue{
+		queues: make(map[string]*Queue),
+		weight: weight,
+	}
+}
+
+// Len Return the number of items in the queue
+func (q *FairQueue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.count
+}
+
+// Add an item of key to the end of its queue
+func (q *FairQueue) Add(key string, item interface{}) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	queue, exist := q.queues[key]
+	if !exist {
+		queue = NewQueue()
+		q.queues[key] = queue
+		q.keys = append(q.keys, key)
+	}
+	queue.Add(item)
+	q.count++
+}
+
+// Next Remove the item at the head of the queue of key in turn and return it.
+// Returns nil when there are no items left in queue.
+func (q *FairQueue) Next() (item interface{}) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.count == 0 {
+		return nil
+	}
+	key := q.keys[q.cursor]
+	queue := q.queues[key]
+	item = queue.Next()
+	q.count--
+	q.served++
+
+	if queue.Len() == 0 {
+		delete(q.queues, key)
+		q.keys = append(q.keys[:q.cursor], q.keys[q.cursor+1:]...)
+		q.served = 0
+	} else if q.served >= q.getWeight(key) {
+		q.cursor++
+		q.served = 0
+	}
+	if q.cursor >= len(q.keys) {
+		q.cursor = 0
+	}
+	return item
+}
+
+func (q *FairQueue) getWeight(key string) int {
+	if q.weight != nil {
+		if weight := q.weight(key); weight > 0 {
+			return weight
+		}
+	}
+	return 1
+}
@@ -0,0 +1,40 @@
+package fifo
+
+import (
+	"testing"
+)
+
+func TestFairQueue(t *testing.T) {
+	weights := map[string]int{"b": 2}
+	q := NewFairQueue(func(key string) int { return weights[key] })
+	for _, item := range []string{"a1", "a2", "a3", "a4", "b1", "b2", "b3", "c1"} {
+		q.Add(item[:1], item)
+	}
+	if q.Len() != 8 {
+		t.Fatalf("fair queue length got %v, want %v", q.Len(), 8)
+	}
+
+	want := []string{"a1", "b1", "b2", "c1", "a2", "b3", "a3", "a4"}
+	for i, w := range want {
+		if item := q.Next(); item != w {
+			t.Errorf("item %v got %v, want %v", i, item, w)
+		}
+	}
+	if item := q.Next(); item != nil || q.Len() != 0 {
+		t.Errorf("empty fair queue got item %v and length %v", item, q.Len())
+	}
+
+	// fifo order with single key
+	q.Add("", "x1")
+	q.Add("", "x2")
+	if item := q.Next(); item != "x1" {
+		t.Errorf("single key got %v, want %v", item, "x1")
+	}
+	q.Add("y", "y1")
+	if item := q.Next(); item != "x2" {
+		t.Errorf("single key got %v, want %v", item, "x2")
+	}
+	if item := q.Next(); item != "y1" {
+		t.Errorf("new key got %v, want %v", item, "y1")
+	}
+}
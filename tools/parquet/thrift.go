@@ -0,0 +1,94 @@
+package parquet
+
+import (
+	"bytes"
+)
+
+// thrift compact protocol types
+const (
+	ctI32    = 5
+	ctI64    = 6
+	ctBinary = 8
+	ctList   = 9
+	ctStruct = 12
+)
+
+// thriftWriter encodes structs in thrift compact protocol,
+// which is used by parquet for page headers and file metadata.
+type thriftWriter struct {
+	buf       bytes.Buffer
+	lastField []int16 // last field id of nested structs
+}
+
+// newThriftWriter new writer with the top level struct begun
+func newThriftWriter() *thriftWriter {
+	t := &thriftWriter{}
+	t.begin()
+	return t
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	for v >= 0x80 {
+		t.buf.WriteByte(byte(v) | 0x80)
+		v >>= 7
+	}
+	t.buf.WriteByte(byte(v))
+}
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	last := &t.lastField[len(t.lastField)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(zigzag(int64(id)))
+	}
+	*last = id
+}
+
+// begin begin struct (also used for struct elements of list)
+func (t *thriftWriter) begin() {
+	t.lastField = append(t.lastField, 0)
+}
+
+// end end struct with stop field
+func (t *thriftWriter) end() {
+	t.buf.WriteByte(0)
+	t.lastField = t.lastField[:len(t.lastField)-1]
+}
+
+func (t *thriftWriter) structField(id int16) {
+	t.field(id, ctStruct)
+	t.begin()
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, ctI32)
+	t.varint(zigzag(int64(v)))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, ctI64)
+	t.varint(zigzag(v))
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.field(id, ctBinary)
+	t.varint(uint64(len(s)))
+	t.buf.WriteString(s)
+}
+
+// list write list header, the elements are written by the caller
+func (t *thriftWriter) list(id int16, elemType byte, size int) {
+	t.field(id, ctList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		t.buf.WriteByte(0xf0 | elemType)
+		t.varint(uint64(size))
+	}
+}
@@ -0,0 +1,295 @@
+// Package parquet implements a minimal writer of apache parquet files.
+// It supports flat required columns of int64 and utf8 string, which are
+// plain encoded without compression and streamed in row groups.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	magic     = "PAR1"
+	createdBy = "CrossChain-Router"
+)
+
+// parquet thrift enums
+const (
+	typeInt64     = 2
+	typeByteArray = 6
+
+	repetitionRequired = 0
+	convertedUTF8      = 0
+	encodingPlain      = 0
+	encodingRLE        = 3
+	codecUncompressed  = 0
+	pageTypeData       = 0
+)
+
+// ColumnType type of column
+type ColumnType int
+
+// column types
+const (
+	Int64 ColumnType = iota
+	String
+)
+
+// Column column of schema
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// DefaultRowGroupRows default rows of row group
+const DefaultRowGroupRows = 10000
+
+// Writer streams rows as parquet file, the rows are buffered column by column
+// until a row group is full, so the memory is bounded by the row group size.
+// Close must be called to flush the last row group and write the footer.
+type Writer struct {
+	out     io.Writer
+	columns []Column
+	values  []*bytes.Buffer // plain encoded values of each column of current row group
+
+	rowGroupRows int64
+	groupRows    int64
+	numRows      int64
+	written      int64
+	rowGroups    []*rowGroup
+	err          error
+}
+
+type columnChunk struct {
+	offset int64
+	size   int64
+}
+
+type rowGroup struct {
+	chunks  []columnChunk
+	numRows int64
+}
+
+// NewWriter new parquet writer of columns to out
+func NewWriter(out io.Writer, columns []Column) *Writer {
+	values := make([]*bytes.Buffer, len(columns))
+	for i := range values {
+		values[i] = new(bytes.Buffer)
+	}
+	return &Writer{
+		out:          out,
+		columns:      columns,
+		values:       values,
+		rowGroupRows: DefaultRowGroupRows,
+	}
+}
+
+// SetRowGroupRows set max rows of row group
+func (w *Writer) SetRowGroupRows(rows int64) {
+	if rows > 0 {
+		w.rowGroupRows = rows
+	}
+}
+
+// NumRows number of appended rows
+func (w *Writer) NumRows() int64 {
+	return w.numRows
+}
+
+// Written number of bytes written to out
+func (w *Writer) Written() int64 {
+	return w.written
+}
+
+// Append append a row, the values are int64 or string in columns order
+func (w *Writer) Append(row []interface{}) error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("row has %v values, but schema has %v columns", len(row), len(w.columns))
+	}
+	for i, col := range w.columns {
+		var ok bool
+		switch col.Type {
+		case Int64:
+			_, ok = row[i].(int64)
+		case String:
+			_, ok = row[i].(string)
+		}
+		if !ok {
+			return fmt.Errorf("column %v has wrong value type %T", col.Name, row[i])
+		}
+	}
+	for i, value := range row {
+		switch v := value.(type) {
+		case int64:
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], uint64(v))
+			w.values[i].Write(b[:])
+		case string:
+			var b [4]byte
+			binary.LittleEndian.PutUint32(b[:], uint32(len(v)))
+			w.values[i].Write(b[:])
+			w.values[i].WriteString(v)
+		}
+	}
+	w.numRows++
+	w.groupRows++
+	if w.groupRows >= w.rowGroupRows {
+		return w.flushRowGroup()
+	}
+	return nil
+}
+
+func (w *Writer) write(data []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	n, err := w.out.Write(data)
+	w.written += int64(n)
+	w.err = err
+	return err
+}
+
+func (w *Writer) writeMagic() error {
+	if w.written > 0 {
+		return w.err
+	}
+	return w.write([]byte(magic))
+}
+
+// flushRowGroup write the buffered rows as a row group of one page per column
+func (w *Writer) flushRowGroup() error {
+	if err := w.writeMagic(); err != nil {
+		return err
+	}
+	group := &rowGroup{
+		chunks:  make([]columnChunk, len(w.columns)),
+		numRows: w.groupRows,
+	}
+	for i, values := range w.values {
+		header := encodePageHeader(values.Len(), w.groupRows)
+		group.chunks[i] = columnChunk{
+			offset: w.written,
+			size:   int64(len(header) + values.Len()),
+		}
+		if err := w.write(header); err != nil {
+			return err
+		}
+		if err := w.write(values.Bytes()); err != nil {
+			return err
+		}
+		values.Reset()
+	}
+	w.rowGroups = append(w.rowGroups, group)
+	w.groupRows = 0
+	return nil
+}
+
+// Close flush the buffered rows and write the footer, it does not close out
+func (w *Writer) Close() error {
+	if len(w.columns) == 0 {
+		return errors.New("parquet schema has no columns")
+	}
+	if err := w.writeMagic(); err != nil {
+		return err
+	}
+	if w.groupRows > 0 {
+		if err := w.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+
+	footer := w.encodeFileMetaData()
+	var footerLen [4]byte
+	binary.LittleEndian.PutUint32(footerLen[:], uint32(len(footer)))
+	for _, data := range [][]byte{footer, footerLen[:], []byte(magic)} {
+		if err := w.write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodePageHeader(size int, numRows int64) []byte {
+	t := newThriftWriter()
+	t.i32(1, pageTypeData)
+	t.i32(2, int32(size)) // uncompressed_page_size
+	t.i32(3, int32(size)) // compressed_page_size
+	t.structField(5)      // data_page_header
+	t.i32(1, int32(numRows))
+	t.i32(2, encodingPlain)
+	t.i32(3, encodingRLE)
+	t.i32(4, encodingRLE)
+	t.end()
+	t.end()
+	return t.buf.Bytes()
+}
+
+func (w *Writer) encodeFileMetaData() []byte {
+	t := newThriftWriter()
+	t.i32(1, 1) // version
+
+	t.list(2, ctStruct, len(w.columns)+1) // schema
+	t.begin()
+	t.binary(4, "schema")
+	t.i32(5, int32(len(w.columns)))
+	t.end()
+	for _, col := range w.columns {
+		t.begin()
+		t.i32(1, col.physicalType())
+		t.i32(3, repetitionRequired)
+		t.binary(4, col.Name)
+		if col.Type == String {
+			t.i32(6, convertedUTF8)
+		}
+		t.end()
+	}
+
+	t.i64(3, w.numRows)
+
+	t.list(4, ctStruct, len(w.rowGroups)) // row_groups
+	for _, group := range w.rowGroups {
+		var totalSize int64
+		t.begin()
+		t.list(1, ctStruct, len(w.columns))
+		for i, col := range w.columns {
+			chunk := group.chunks[i]
+			totalSize += chunk.size
+			t.begin()
+			t.i64(2, chunk.offset) // file_offset
+			t.structField(3)       // meta_data
+			t.i32(1, col.physicalType())
+			t.list(2, ctI32, 1)
+			t.varint(zigzag(encodingPlain))
+			t.list(3, ctBinary, 1)
+			t.varint(uint64(len(col.Name)))
+			t.buf.WriteString(col.Name)
+			t.i32(4, codecUncompressed)
+			t.i64(5, group.numRows)
+			t.i64(6, chunk.size)
+			t.i64(7, chunk.size)
+			t.i64(9, chunk.offset) // data_page_offset
+			t.end()
+			t.end()
+		}
+		t.i64(2, totalSize)
+		t.i64(3, group.numRows)
+		t.end()
+	}
+
+	t.binary(6, createdBy)
+	t.end()
+	return t.buf.Bytes()
+}
+
+func (col *Column) physicalType() int32 {
+	if col.Type == Int64 {
+		return typeInt64
+	}
+	return typeByteArray
+}
@@ -0,0 +1,222 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// thriftReader decodes thrift compact structs into maps of field id to value
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) varint() uint64 {
+	var v uint64
+	for shift := 0; ; shift += 7 {
+		b := r.data[r.pos]
+		r.pos++
+		v |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return v
+		}
+	}
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case ctI32, ctI64:
+		return r.zigzag()
+	case ctBinary:
+		n := int(r.varint())
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case ctList:
+		header := r.data[r.pos]
+		r.pos++
+		size, elemType := int(header>>4), header&0x0f
+		if size == 15 {
+			size = int(r.varint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = r.value(elemType)
+		}
+		return list
+	case ctStruct:
+		return r.readStruct()
+	}
+	panic("unexpected thrift type")
+}
+
+func (r *thriftReader) readStruct() map[int16]interface{} {
+	result := make(map[int16]interface{})
+	var last int16
+	for {
+		header := r.data[r.pos]
+		r.pos++
+		if header == 0 {
+			return result
+		}
+		typ := header & 0x0f
+		if delta := int16(header >> 4); delta != 0 {
+			last += delta
+		} else {
+			last = int16(r.zigzag())
+		}
+		result[last] = r.value(typ)
+	}
+}
+
+func TestThriftFieldHeader(t *testing.T) {
+	w := newThriftWriter()
+	w.i32(1, -1)
+	w.i64(20, 300) // long delta
+	w.binary(21, "ab")
+	w.end()
+	want := []byte{0x15, 0x01, 0x06, 0x28, 0xd8, 0x04, 0x18, 0x02, 'a', 'b', 0x00}
+	if got := w.buf.Bytes(); !bytes.Equal(got, want) {
+		t.Fatalf("thrift encoding got %x, want %x", got, want)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, []Column{{Name: "id", Type: Int64}, {Name: "name", Type: String}})
+	if err := w.Append([]interface{}{int64(7), "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Append([]interface{}{int64(-1), ""}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Append([]interface{}{"x", "bob"}); err == nil {
+		t.Fatal("append wrong value type should fail")
+	}
+	if err := w.Append([]interface{}{int64(1)}); err == nil {
+		t.Fatal("append wrong values count should fail")
+	}
+
+	if buf.Len() != 0 {
+		t.Fatal("rows of unfinished row group should be buffered")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if w.Written() != int64(len(data)) {
+		t.Fatalf("written size got %v, want %v", w.Written(), len(data))
+	}
+	if string(data[:4]) != magic || string(data[len(data)-4:]) != magic {
+		t.Fatal("wrong parquet magic")
+	}
+
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := &thriftReader{data: data[len(data)-8-footerLen : len(data)-8]}
+	meta := footer.readStruct()
+	if footer.pos != footerLen {
+		t.Fatalf("footer decoded %v bytes, want %v", footer.pos, footerLen)
+	}
+	if meta[3] != int64(2) {
+		t.Fatalf("num rows got %v, want 2", meta[3])
+	}
+	schema := meta[2].([]interface{})
+	if len(schema) != 3 || schema[2].(map[int16]interface{})[4] != "name" {
+		t.Fatalf("wrong schema %v", schema)
+	}
+
+	rowGroup := meta[4].([]interface{})[0].(map[int16]interface{})
+	wantValues := [][]byte{
+		{7, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		{5, 0, 0, 0, 'a', 'l', 'i', 'c', 'e', 0, 0, 0, 0},
+	}
+	for i, chunk := range rowGroup[1].([]interface{}) {
+		colMeta := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+		offset := int(colMeta[9].(int64))
+		page := &thriftReader{data: data, pos: offset}
+		header := page.readStruct()
+		size := int(header[3].(int64))
+		if colMeta[6].(int64) != int64(page.pos-offset+size) {
+			t.Errorf("column %v chunk size mismatch", i)
+		}
+		if numValues := header[5].(map[int16]interface{})[1]; numValues != int64(2) {
+			t.Errorf("column %v page has %v values, want 2", i, numValues)
+		}
+		if got := data[page.pos : page.pos+size]; !bytes.Equal(got, wantValues[i]) {
+			t.Errorf("column %v values got %x, want %x", i, got, wantValues[i])
+		}
+	}
+}
+
+func readFooter(t *testing.T, data []byte) map[int16]interface{} {
+	if string(data[:4]) != magic || string(data[len(data)-4:]) != magic {
+		t.Fatal("wrong parquet magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := &thriftReader{data: data[len(data)-8-footerLen : len(data)-8]}
+	return footer.readStruct()
+}
+
+func TestWriterRowGroups(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, []Column{{Name: "id", Type: Int64}})
+	w.SetRowGroupRows(2)
+	for i := int64(0); i < 5; i++ {
+		if err := w.Append([]interface{}{i}); err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 && buf.Len() == 0 {
+			t.Fatal("full row group should be flushed")
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	meta := readFooter(t, data)
+	if meta[3] != int64(5) {
+		t.Fatalf("num rows got %v, want 5", meta[3])
+	}
+	rowGroups := meta[4].([]interface{})
+	wantRows := []int64{2, 2, 1}
+	if len(rowGroups) != len(wantRows) {
+		t.Fatalf("row groups count got %v, want %v", len(rowGroups), len(wantRows))
+	}
+	next := int64(0)
+	for i, item := range rowGroups {
+		group := item.(map[int16]interface{})
+		if group[3] != wantRows[i] {
+			t.Errorf("row group %v rows got %v, want %v", i, group[3], wantRows[i])
+		}
+		colMeta := group[1].([]interface{})[0].(map[int16]interface{})[3].(map[int16]interface{})
+		offset := int(colMeta[9].(int64))
+		page := &thriftReader{data: data, pos: offset}
+		header := page.readStruct()
+		size := int(header[3].(int64))
+		for j := 0; j < size; j += 8 {
+			if v := int64(binary.LittleEndian.Uint64(data[page.pos+j:])); v != next {
+				t.Errorf("row group %v value got %v, want %v", i, v, next)
+			}
+			next++
+		}
+	}
+}
+
+func TestWriterNoRows(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, []Column{{Name: "id", Type: Int64}})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	meta := readFooter(t, buf.Bytes())
+	if meta[3] != int64(0) || len(meta[4].([]interface{})) != 0 {
+		t.Fatalf("empty file got rows %v and row groups %v", meta[3], meta[4])
+	}
+}
@@ -0,0 +1,152 @@
+// Package retry provides retrying with exponential backoff, jitter,
+// max elapsed time, retry budgets and retryable error predicates.
+package retry
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// for testing
+var sleep = time.Sleep
+
+// Policy retry policy
+type Policy struct {
+	// max count of attempts (including the first one), 0 means no limit.
+	// at least one of MaxAttempts and MaxElapsedTime should be set,
+	// otherwise the operation is tried only once.
+	MaxAttempts int
+	// interval before the first retry
+	InitialInterval time.Duration
+	// max interval between retries, 0 means no limit
+	MaxInterval time.Duration
+	// interval is multiplied after each retry, less than 1 means constant interval
+	Multiplier float64
+	// randomization factor in [0, 1], the interval is randomized
+	// in range [interval*(1-Jitter), interval*(1+Jitter)]
+	Jitter float64
+	// max elapsed time since the first attempt, 0 means no limit
+	MaxElapsedTime time.Duration
+
+	// is error retryable, nil means all errors except permanent ones
+	Retryable func(error) bool
+	// shared retry budget, nil means no limit
+	Budget *Budget
+}
+
+// Do call fn until it succeeds or the policy stops retrying,
+// it returns the last error of fn.
+func Do(p *Policy, fn func() error) error {
+	start := time.Now()
+	interval := p.InitialInterval
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		var perr *permanentError
+		if errors.As(err, &perr) {
+			return perr.err
+		}
+		if p.Retryable != nil && !p.Retryable(err) {
+			return err
+		}
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return err
+		}
+		if p.MaxAttempts <= 0 && p.MaxElapsedTime <= 0 {
+			return err
+		}
+		wait := p.randomize(interval)
+		if p.MaxElapsedTime > 0 && time.Since(start)+wait > p.MaxElapsedTime {
+			return err
+		}
+		if p.Budget != nil && !p.Budget.Allow() {
+			return err
+		}
+		sleep(wait)
+		interval = p.next(interval)
+	}
+}
+
+func (p *Policy) next(interval time.Duration) time.Duration {
+	if p.Multiplier > 1 {
+		interval = time.Duration(float64(interval) * p.Multiplier)
+	}
+	if p.MaxInterval > 0 && interval > p.MaxInterval {
+		interval = p.MaxInterval
+	}
+	return interval
+}
+
+func (p *Policy) randomize(interval time.Duration) time.Duration {
+	if p.Jitter <= 0 || interval <= 0 {
+		return interval
+	}
+	jitter := p.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+	delta := jitter * float64(interval)
+	//nolint:gosec // no need of crypto random
+	return time.Duration(float64(interval) - delta + rand.Float64()*2*delta)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wrap error to stop retrying immediately
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Budget limits count of retries in a sliding time window,
+// it is shared by operations to prevent retry storms.
+type Budget struct {
+	max    int
+	window time.Duration
+
+	retries []time.Time
+	lock    sync.Mutex
+}
+
+// NewBudget new budget allowing max retries in every window
+func NewBudget(max int, window time.Duration) *Budget {
+	return &Budget{max: max, window: window}
+}
+
+// Max get max retries in window
+func (b *Budget) Max() int {
+	return b.max
+}
+
+// Allow consume a retry if the budget is not exhausted
+func (b *Budget) Allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := time.Now()
+	i := 0
+	for i < len(b.retries) && now.Sub(b.retries[i]) >= b.window {
+		i++
+	}
+	b.retries = b.retries[i:]
+	if len(b.retries) >= b.max {
+		return false
+	}
+	b.retries = append(b.retries, now)
+	return true
+}
@@ -0,0 +1,123 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errTest = errors.New("test error")
+
+func withSleeps(t *testing.T) *[]time.Duration {
+	sleeps := make([]time.Duration, 0)
+	sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	t.Cleanup(func() { sleep = time.Sleep })
+	return &sleeps
+}
+
+func failTimes(n int, counter *int) func() error {
+	return func() error {
+		*counter++
+		if *counter <= n {
+			return errTest
+		}
+		return nil
+	}
+}
+
+func TestDoMaxAttempts(t *testing.T) {
+	sleeps := withSleeps(t)
+	p := &Policy{MaxAttempts: 3, InitialInterval: time.Second}
+
+	var count int
+	if err := Do(p, failTimes(2, &count)); err != nil || count != 3 {
+		t.Fatalf("want success after 3 attempts, got err %v after %v", err, count)
+	}
+	count = 0
+	if err := Do(p, failTimes(5, &count)); !errors.Is(err, errTest) || count != 3 {
+		t.Fatalf("want failure after 3 attempts, got err %v after %v", err, count)
+	}
+	for _, d := range *sleeps {
+		if d != time.Second {
+			t.Fatalf("want constant interval, got %v", *sleeps)
+		}
+	}
+}
+
+func TestDoExponentialBackoff(t *testing.T) {
+	sleeps := withSleeps(t)
+	p := &Policy{
+		MaxAttempts:     5,
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Second,
+		Multiplier:      2,
+	}
+	var count int
+	_ = Do(p, failTimes(10, &count))
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	if len(*sleeps) != len(want) {
+		t.Fatalf("want sleeps %v, got %v", want, *sleeps)
+	}
+	for i, d := range want {
+		if (*sleeps)[i] != d {
+			t.Fatalf("want sleeps %v, got %v", want, *sleeps)
+		}
+	}
+}
+
+func TestDoJitter(t *testing.T) {
+	sleeps := withSleeps(t)
+	p := &Policy{MaxAttempts: 20, InitialInterval: time.Second, Jitter: 0.5}
+	var count int
+	_ = Do(p, failTimes(100, &count))
+	for _, d := range *sleeps {
+		if d < 500*time.Millisecond || d > 1500*time.Millisecond {
+			t.Fatalf("interval %v out of jitter range", d)
+		}
+	}
+}
+
+func TestDoPredicateAndPermanent(t *testing.T) {
+	withSleeps(t)
+	var count int
+	p := &Policy{MaxAttempts: 5, Retryable: func(err error) bool { return false }}
+	if err := Do(p, failTimes(10, &count)); !errors.Is(err, errTest) || count != 1 {
+		t.Fatalf("want no retry of unretryable error, got err %v after %v", err, count)
+	}
+
+	count = 0
+	p = &Policy{MaxAttempts: 5}
+	err := Do(p, func() error {
+		count++
+		return Permanent(errTest)
+	})
+	if err != errTest || count != 1 {
+		t.Fatalf("want no retry of permanent error, got err %v after %v", err, count)
+	}
+}
+
+func TestDoMaxElapsedTime(t *testing.T) {
+	withSleeps(t)
+	var count int
+	p := &Policy{InitialInterval: time.Second, MaxElapsedTime: 500 * time.Millisecond}
+	if err := Do(p, failTimes(10, &count)); !errors.Is(err, errTest) || count != 1 {
+		t.Fatalf("want stop by max elapsed time, got err %v after %v", err, count)
+	}
+}
+
+func TestDoBudget(t *testing.T) {
+	withSleeps(t)
+	budget := NewBudget(3, time.Minute)
+	p := &Policy{MaxAttempts: 10, Budget: budget}
+
+	var count int
+	_ = Do(p, failTimes(100, &count))
+	if count != 4 {
+		t.Fatalf("want 1 attempt and 3 retries in budget, got %v attempts", count)
+	}
+	count = 0
+	_ = Do(p, failTimes(100, &count))
+	if count != 1 {
+		t.Fatalf("want no retry with exhausted budget, got %v attempts", count)
+	}
+}
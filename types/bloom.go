@@ -0,0 +1,56 @@
+package types
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/common"
+)
+
+// BloomByteLength number of bytes used in a header log bloom
+const BloomByteLength = 256
+
+// Bloom represents a 2048 bit bloom filter
+type Bloom [BloomByteLength]byte
+
+// BytesToBloom converts a byte slice to a bloom filter (nil if length mismatch)
+func BytesToBloom(b []byte) *Bloom {
+	if len(b) != BloomByteLength {
+		return nil
+	}
+	var bloom Bloom
+	copy(bloom[:], b)
+	return &bloom
+}
+
+// Add adds data (address or topic) to the bloom filter
+func (b *Bloom) Add(data []byte) {
+	for _, bit := range bloomBits(data) {
+		b[bit.index] |= bit.value
+	}
+}
+
+// Test checks if data (address or topic) may be present in the bloom filter,
+// false positive is possible but false negative is impossible
+func (b *Bloom) Test(data []byte) bool {
+	for _, bit := range bloomBits(data) {
+		if b[bit.index]&bit.value != bit.value {
+			return false
+		}
+	}
+	return true
+}
+
+type bloomBit struct {
+	index int
+	value byte
+}
+
+func bloomBits(data []byte) (bits [3]bloomBit) {
+	hash := common.Keccak256Hash(data)
+	for i := 0; i < 3; i++ {
+		idx := (uint(hash[2*i])<<8 | uint(hash[2*i+1])) & 2047
+		bits[i] = bloomBit{
+			index: BloomByteLength - 1 - int(idx/8),
+			value: byte(1) << (idx % 8),
+		}
+	}
+	return bits
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestBloom(t *testing.T) {
+	positive := []string{
+		"testtest",
+		"test",
+		"hallo",
+		"other",
+	}
+	negative := []string{
+		"tes",
+		"lo",
+	}
+
+	var bloom Bloom
+	for _, data := range positive {
+		bloom.Add([]byte(data))
+	}
+
+	for _, data := range positive {
+		if !bloom.Test([]byte(data)) {
+			t.Errorf("expect %v in bloom", data)
+		}
+	}
+	for _, data := range negative {
+		if bloom.Test([]byte(data)) {
+			t.Errorf("unexpect %v in bloom", data)
+		}
+	}
+
+	if BytesToBloom(bloom[:10]) != nil {
+		t.Errorf("expect nil bloom of wrong length")
+	}
+	if b := BytesToBloom(bloom[:]); b == nil || *b != bloom {
+		t.Errorf("bytes to bloom mismatch")
+	}
+}
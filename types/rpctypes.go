@@ -33,6 +33,7 @@ type RPCBlock struct {
 	GasUsed      *hexutil.Uint64 `json:"gasUsed"`
 	Time         *hexutil.Big    `json:"timestamp"`
 	BaseFee      *hexutil.Big    `json:"baseFeePerGas"`
+	LogsBloom    *hexutil.Bytes  `json:"logsBloom"`
 	Transactions []*common.Hash  `json:"transactions"`
 }
 
@@ -107,6 +108,8 @@ type RPCTxReceipt struct {
 	Recipient   *common.Address `json:"to"`
 	GasUsed     *hexutil.Uint64 `json:"gasUsed"`
 	Logs        []*RPCLog       `json:"logs"`
+
+	EffectiveGasPrice *hexutil.Big `json:"effectiveGasPrice,omitempty"`
 }
 
 // IsStatusOk is status ok
@@ -114,6 +117,29 @@ func (r *RPCTxReceipt) IsStatusOk() bool {
 	return r != nil && r.Status != nil && *r.Status == 1 && len(r.Logs) > 0
 }
 
+// GetFailReason get fail reason of receipt which is not status ok
+func (r *RPCTxReceipt) GetFailReason() string {
+	switch {
+	case r == nil || r.Status == nil:
+		return "receipt without status"
+	case *r.Status != 1:
+		return "execution reverted"
+	case len(r.Logs) == 0:
+		return "no logs emitted"
+	default:
+		return ""
+	}
+}
+
+// GetFeePaid get effective fee paid (gas used multiplied by effective gas price)
+func (r *RPCTxReceipt) GetFeePaid() *big.Int {
+	if r == nil || r.GasUsed == nil || r.EffectiveGasPrice == nil {
+		return nil
+	}
+	fee := new(big.Int).SetUint64(uint64(*r.GasUsed))
+	return fee.Mul(fee, r.EffectiveGasPrice.ToInt())
+}
+
 // FilterQuery struct
 type FilterQuery struct {
 	BlockHash *common.Hash
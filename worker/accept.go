@@ -36,6 +36,7 @@ var (
 
 	// those errors will be ignored in accepting
 	errIdentifierMismatch = errors.New("cross chain bridge identifier mismatch")
+	errSaltMismatch       = errors.New("deployment salt mismatch")
 	errInitiatorMismatch  = errors.New("initiator mismatch")
 	errWrongMsgContext    = errors.New("wrong msg context")
 )
@@ -47,6 +48,8 @@ func StartAcceptSignJob() {
 	openLeveldb()
 	defer closeLeveldb()
 
+	StartDeliveryTrackJob()
+
 	if mpcConfig := mpc.GetMPCConfig(false); mpcConfig != nil {
 		initAcceptWorkers(false)
 
@@ -224,14 +227,19 @@ func processAcceptInfo(mpcConfig *mpc.Config, info *mpc.SignInfoData) error {
 	case // these maybe accepts of other bridges or routers, always discard them
 		errors.Is(err, errWrongMsgContext),
 		errors.Is(err, errIdentifierMismatch),
+		errors.Is(err, errSaltMismatch),
 		errors.Is(err, errInvalidAggregate):
 		ctx = append(ctx, "err", err)
 		logWorkerTrace("accept", "discard sign", ctx...)
 		isProcessed = true
 		return err
 	case // these are situations we can not judge, ignore them or disagree immediately
+		errors.Is(err, errChainHeadStale),
+		errors.Is(err, tokens.ErrRateUnavailable),
 		errors.Is(err, tokens.ErrTxNotStable),
 		errors.Is(err, tokens.ErrTxNotFound),
+		errors.Is(err, tokens.ErrReceiptDivergence),
+		errors.Is(err, tokens.ErrLightClientVerify),
 		tokens.IsRPCQueryOrNotFoundError(err):
 		if isPendingInvalidAccept {
 			ctx = append(ctx, "err", err)
@@ -266,7 +274,7 @@ func processAcceptInfo(mpcConfig *mpc.Config, info *mpc.SignInfoData) error {
 	ctx = append(ctx, "result", agreeResult)
 
 	start := time.Now()
-	res, err := mpcConfig.DoAcceptSign(keyID, agreeResult, info.MsgHash, aggreeMsgContext)
+	res, err := doAcceptSign(mpcConfig, keyID, agreeResult, info.MsgHash, aggreeMsgContext)
 	logWorker("accept", "call acceptSign finished", "keyID", keyID, "result", agreeResult, "timespent", time.Since(start).String())
 	if err != nil {
 		ctx = append(ctx, "rpcResult", res)
@@ -291,6 +299,9 @@ func filterSignInfo(signInfo *mpc.SignInfoData) (*tokens.BuildTxArgs, error) {
 	default:
 		return nil, errIdentifierMismatch
 	}
+	if args.Identifier != tokens.AggregateIdentifier && args.Salt != params.GetDeploymentSalt() {
+		return nil, errSaltMismatch
+	}
 	return &args, err
 }
 
@@ -309,6 +320,9 @@ func verifySignInfo(mpcConfig *mpc.Config, signInfo *mpc.SignInfoData) (*tokens.
 		}
 		return args, nil
 	}
+	if args.IsSignExpired(now()) {
+		return args, errSignRequestExpired
+	}
 	if lvldbHandle != nil && args.GetTxNonce() > 0 { // only for eth like chain
 		err = CheckAcceptRecord(args)
 		if err != nil {
@@ -356,8 +370,25 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 		SwapType:      args.SwapType,
 		LogIndex:      logIndex,
 		AllowUnstable: false,
+		Bind:          args.Bind,
+		ToChainID:     args.ToChainID,
+	}
+	if args.Reswapping {
+		// verify reswap against the same state as originally observed
+		verifyArgs.PinnedHeight = args.TxHeight
+	}
+	if IsChainHeadStale(args.FromChainID.String()) {
+		return errChainHeadStale
+	}
+	err = router.CheckRouteRate(args.GetTokenID(), args.FromChainID.String(), args.ToChainID.String(), args.ConversionRate)
+	if err != nil {
+		return err
+	}
+	err = tokens.CheckDeliveryFeePercent(args.GetTokenID(), args.ToChainID.String(), args.DeliveryFeePercent)
+	if err != nil {
+		return err
 	}
-	swapInfo, err := srcBridge.VerifyTransaction(txid, verifyArgs)
+	swapInfo, err := verifySwapTx(srcBridge, txid, args.ToChainID.String(), args.GetTokenID(), verifyArgs)
 	if err != nil {
 		logWorkerError("accept", "verifySignInfo failed", err, ctx...)
 		return err
@@ -383,6 +414,7 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 		SwapArgs: tokens.SwapArgs{
 			SwapInfo:    verifySwapInfo,
 			Identifier:  params.GetIdentifier(),
+			Salt:        params.GetDeploymentSalt(),
 			SwapID:      swapInfo.Hash,
 			SwapType:    swapInfo.SwapType,
 			Bind:        swapInfo.Bind,
@@ -390,6 +422,9 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 			FromChainID: swapInfo.FromChainID,
 			ToChainID:   swapInfo.ToChainID,
 			Reswapping:  args.Reswapping,
+			// checked against our own rate and delivery fee views already
+			ConversionRate:     args.ConversionRate,
+			DeliveryFeePercent: args.DeliveryFeePercent,
 		},
 		From:        args.From,
 		OriginFrom:  swapInfo.From,
@@ -435,4 +470,6 @@ func saveAcceptRecord(bridge tokens.IBridge, keyID string, args *tokens.BuildTxA
 		return
 	}
 	logWorker("accept", "save accept record to db success", ctx...)
+
+	trackAcceptedDelivery(args, swapTx)
 }
@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestFilterSignInfoDeploymentSalt(t *testing.T) {
+	cfg := params.GetRouterConfig()
+	oldIdentifier, oldSalt := cfg.Identifier, cfg.DeploymentSalt
+	defer func() { cfg.Identifier, cfg.DeploymentSalt = oldIdentifier, oldSalt }()
+	cfg.Identifier = "routerswap#test"
+	cfg.DeploymentSalt = "deployment-a"
+
+	tests := []struct {
+		identifier string
+		salt       string
+		want       error
+	}{
+		{"routerswap#test", "deployment-a", nil},
+		{"routerswap#test", "deployment-b", errSaltMismatch},
+		{"routerswap#test", "", errSaltMismatch},
+		{"routerswap#other", "deployment-a", errIdentifierMismatch},
+		{tokens.AggregateIdentifier, "", nil},
+	}
+	for _, test := range tests {
+		args := &tokens.BuildTxArgs{SwapArgs: tokens.SwapArgs{Identifier: test.identifier, Salt: test.salt}}
+		msgContext, _ := json.Marshal(args)
+		_, err := filterSignInfo(&mpc.SignInfoData{MsgContext: []string{string(msgContext)}})
+		if !errors.Is(err, test.want) {
+			t.Errorf("filter sign info of identifier %v salt %q got error %v, want %v", test.identifier, test.salt, err, test.want)
+		}
+	}
+}
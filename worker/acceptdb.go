@@ -15,6 +15,7 @@ import (
 
 const (
 	identifierKey = "router-identifier"
+	saltKey       = "router-deployment-salt"
 
 	allowReswapTimeInterval = 1800 // seconds
 )
@@ -80,14 +81,8 @@ func CheckAcceptRecord(args *tokens.BuildTxArgs) (err error) {
 		oldSwapTx := key[prefixLen:]
 		log.Info("[accept] check saved record", "key", key, "value", value)
 		txStatus, errt := toBridge.GetTransactionStatus(oldSwapTx)
-		if errt == nil && txStatus != nil && txStatus.BlockHeight > 0 { // on chain
-			if status, ok := txStatus.Receipt.(tokens.StatusInterface); ok {
-				if status.IsStatusOk() {
-					log.Warn("[accept] found already swapped tx", "key", key, "value", value)
-					alreadySwapped = true
-					break
-				}
-			} else {
+		if errt == nil && txStatus.IsSwapTxOnChain() { // on chain
+			if txStatus.IsSwapTxOnChainAndSucceed() && !(args.Extra != nil && args.Extra.DirectPayout && isRefundedClaimSwapTx(toBridge, oldSwapTx)) {
 				log.Warn("[accept] found already swapped tx", "key", key, "value", value)
 				alreadySwapped = true
 				break
@@ -158,26 +153,30 @@ func openLeveldb() {
 	}
 	log.Info("open accept database success", "path", path)
 
-	configIdentifier := params.GetIdentifier()
+	checkOrInitDBValue(db, identifierKey, params.GetIdentifier())
+	checkOrInitDBValue(db, saltKey, params.GetDeploymentSalt())
 
-	identifierVal, err := db.Get([]byte(identifierKey))
-	identifierInDB := string(identifierVal)
+	lvldbHandle = db
+}
+
+// checkOrInitDBValue init value if not exist, otherwise it must match
+func checkOrInitDBValue(db *leveldb.Database, key, configValue string) {
+	val, err := db.Get([]byte(key))
+	valueInDB := string(val)
 	if err != nil {
 		if !leveldb.IsNotFoundErr(err) {
-			log.Fatal("get identifier from database failed", "err", err)
+			log.Fatal("get value from database failed", "key", key, "err", err)
 		}
-		err = db.Put([]byte(identifierKey), []byte(configIdentifier)) // init identifier
+		err = db.Put([]byte(key), []byte(configValue)) // init value
 		if err != nil {
-			log.Fatal("write identifier to database failed", "identifier", configIdentifier, "err", err)
+			log.Fatal("write value to database failed", "key", key, "value", configValue, "err", err)
 		} else {
-			log.Info("write identifier to database success", "identifier", configIdentifier)
+			log.Info("write value to database success", "key", key, "value", configValue)
 		}
 	} else {
-		log.Info("get identifier from database success", "identifier", identifierInDB)
-		if identifierInDB != configIdentifier {
-			log.Fatal("identifier mismatch", "indb", identifierInDB, "inconfig", configIdentifier)
+		log.Info("get value from database success", "key", key, "value", valueInDB)
+		if valueInDB != configValue {
+			log.Fatal("value mismatch", "key", key, "indb", valueInDB, "inconfig", configValue)
 		}
 	}
-
-	lvldbHandle = db
 }
@@ -0,0 +1,216 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+)
+
+// audit statuses of account history entries
+const (
+	AuditMatched    = "matched"    // recorded in database with the same value
+	AuditMismatched = "mismatched" // recorded in database with different value
+	AuditMissing    = "missing"    // deposit or withdrawal on chain is not recorded in database
+	AuditInvalid    = "invalid"    // deposit is not a valid swap and is not recorded
+	AuditNotOnChain = "notOnChain" // database record in the range is not found on chain
+	AuditIgnored    = "ignored"    // txs without transfer or failed txs (fee only)
+)
+
+var (
+	// swap records queries of audits, they are replaced in tests
+	findAuditSwapResultsOfTx      = mongodb.FindRouterSwapResultsOfTx
+	findAuditSwapResultBySwapTx   = mongodb.FindRouterSwapResultBySwapTx
+	findAuditSwapResultsInHeights = mongodb.FindRouterSwapResultsInHeightRange
+)
+
+// AccountAuditEntry reconciliation of account tx or database record
+type AccountAuditEntry struct {
+	Status     string                  `json:"status"`
+	Transfer   *tokens.AccountTransfer `json:"transfer,omitempty"`
+	SwapKey    string                  `json:"swapKey,omitempty"`
+	SwapStatus string                  `json:"swapStatus,omitempty"`
+	Detail     string                  `json:"detail,omitempty"`
+}
+
+// AccountAuditReport audit report of account history in height range
+type AccountAuditReport struct {
+	ChainID   string               `json:"chainID"`
+	Account   string               `json:"account"`
+	MinHeight uint64               `json:"minHeight"`
+	MaxHeight uint64               `json:"maxHeight"`
+	Timestamp int64                `json:"timestamp"`
+	Entries   []*AccountAuditEntry `json:"entries"`
+	Summary   map[string]int       `json:"summary"`
+	Signer    string               `json:"signer,omitempty"`
+	Signature string               `json:"signature,omitempty"`
+}
+
+// Hash hash of report content (excluding the signer and signature)
+func (r *AccountAuditReport) Hash() (common.Hash, error) {
+	content := *r
+	content.Signer = ""
+	content.Signature = ""
+	data, err := json.Marshal(&content)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
+func (r *AccountAuditReport) addEntry(entry *AccountAuditEntry) {
+	r.Entries = append(r.Entries, entry)
+	r.Summary[entry.Status]++
+}
+
+// AuditAccountHistory replay history of account on chain in height range [minHeight, maxHeight],
+// and reconcile the re-derived deposits and withdrawals against the swap records in database.
+func AuditAccountHistory(chainID, account string, minHeight, maxHeight uint64) (*AccountAuditReport, error) {
+	bridge := router.GetBridgeByChainID(chainID)
+	if bridge == nil {
+		return nil, tokens.ErrNoBridgeForChainID
+	}
+	auditor, ok := bridge.(tokens.AccountHistoryAuditor)
+	if !ok {
+		return nil, fmt.Errorf("%w: audit account history of chain %v", tokens.ErrNotImplemented, chainID)
+	}
+	transfers, err := auditor.GetAccountTransfers(account, minHeight, maxHeight)
+	if err != nil {
+		return nil, err
+	}
+	report := &AccountAuditReport{
+		ChainID:   chainID,
+		Account:   account,
+		MinHeight: minHeight,
+		MaxHeight: maxHeight,
+		Timestamp: time.Now().Unix(),
+		Summary:   make(map[string]int),
+	}
+
+	onchainTxs := make(map[string]struct{}, len(transfers))
+	for _, transfer := range transfers {
+		onchainTxs[strings.ToLower(transfer.TxHash)] = struct{}{}
+		entry, errf := auditTransfer(chainID, transfer)
+		if errf != nil {
+			return nil, errf
+		}
+		report.addEntry(entry)
+	}
+
+	// swap records in the range which are not found on chain
+	deposits, err := findAuditSwapResultsInHeights(chainID, true, minHeight, maxHeight)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range deposits {
+		if _, exist := onchainTxs[strings.ToLower(res.TxID)]; !exist {
+			report.addEntry(getNotOnChainEntry(res, "deposit "+res.TxID))
+		}
+	}
+	withdrawals, err := findAuditSwapResultsInHeights(chainID, false, minHeight, maxHeight)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range withdrawals {
+		if !strings.EqualFold(res.MPC, account) {
+			continue
+		}
+		if _, exist := onchainTxs[strings.ToLower(res.SwapTx)]; !exist {
+			report.addEntry(getNotOnChainEntry(res, "withdrawal "+res.SwapTx))
+		}
+	}
+	return report, nil
+}
+
+func getNotOnChainEntry(res *mongodb.MgoSwapResult, detail string) *AccountAuditEntry {
+	return &AccountAuditEntry{
+		Status:     AuditNotOnChain,
+		SwapKey:    res.Key,
+		SwapStatus: res.Status.String(),
+		Detail:     detail,
+	}
+}
+
+func auditTransfer(chainID string, transfer *tokens.AccountTransfer) (*AccountAuditEntry, error) {
+	entry := &AccountAuditEntry{Transfer: transfer}
+	switch {
+	case transfer.Direction == "":
+		entry.Status = AuditIgnored
+		return entry, nil
+	case !transfer.Success:
+		entry.Status = AuditIgnored
+		entry.Detail = "failed tx"
+		return entry, nil
+	case transfer.Direction == tokens.TransferIn:
+		return auditDeposit(chainID, entry)
+	default:
+		return auditWithdrawal(chainID, entry)
+	}
+}
+
+func auditDeposit(chainID string, entry *AccountAuditEntry) (*AccountAuditEntry, error) {
+	transfer := entry.Transfer
+	results, err := findAuditSwapResultsOfTx(chainID, transfer.TxHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		if transfer.VerifyError != "" {
+			entry.Status = AuditInvalid
+			entry.Detail = transfer.VerifyError
+		} else {
+			entry.Status = AuditMissing
+		}
+		return entry, nil
+	}
+	res := results[0]
+	entry.SwapKey = res.Key
+	entry.SwapStatus = res.Status.String()
+	value := transfer.Value
+	if transfer.SwapInfo != nil && transfer.SwapInfo.Value != nil {
+		value = transfer.SwapInfo.Value
+	}
+	auditValue(entry, res.Value, value)
+	return entry, nil
+}
+
+func auditWithdrawal(chainID string, entry *AccountAuditEntry) (*AccountAuditEntry, error) {
+	transfer := entry.Transfer
+	res, err := findAuditSwapResultBySwapTx(chainID, transfer.TxHash)
+	if errors.Is(err, mongodb.ErrItemNotFound) {
+		entry.Status = AuditMissing
+		return entry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entry.SwapKey = res.Key
+	entry.SwapStatus = res.Status.String()
+	auditValue(entry, res.SwapValue, transfer.Value)
+	if entry.Status == AuditMatched && !strings.EqualFold(res.SwapTx, transfer.TxHash) {
+		entry.Detail = "recorded as old swap tx, swap tx is " + res.SwapTx
+	}
+	return entry, nil
+}
+
+func auditValue(entry *AccountAuditEntry, recorded string, value *big.Int) {
+	recordedValue, err := common.GetBigIntFromStr(recorded)
+	switch {
+	case err != nil || value == nil:
+		entry.Status = AuditMismatched
+		entry.Detail = fmt.Sprintf("can not compare value %v with recorded %v", value, recorded)
+	case recordedValue.Cmp(value) != 0:
+		entry.Status = AuditMismatched
+		entry.Detail = fmt.Sprintf("value %v, recorded %v", value, recordedValue)
+	default:
+		entry.Status = AuditMatched
+	}
+}
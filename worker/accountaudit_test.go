@@ -0,0 +1,197 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const testAuditChainID = "1000005788240"
+
+type testAuditorBridge struct {
+	tokens.IBridge
+	transfers []*tokens.AccountTransfer
+	err       error
+}
+
+func (b *testAuditorBridge) GetAccountTransfers(account string, minHeight, maxHeight uint64) ([]*tokens.AccountTransfer, error) {
+	return b.transfers, b.err
+}
+
+type testNonAuditorBridge struct {
+	tokens.IBridge
+}
+
+func setupAuditSwapResults(results []*mongodb.MgoSwapResult) func() {
+	oldOfTx, oldBySwapTx, oldInHeights := findAuditSwapResultsOfTx, findAuditSwapResultBySwapTx, findAuditSwapResultsInHeights
+	findAuditSwapResultsOfTx = func(fromChainID, txid string) (list []*mongodb.MgoSwapResult, err error) {
+		for _, res := range results {
+			if res.FromChainID == fromChainID && strings.EqualFold(res.TxID, txid) {
+				list = append(list, res)
+			}
+		}
+		return list, nil
+	}
+	findAuditSwapResultBySwapTx = func(toChainID, swapTx string) (*mongodb.MgoSwapResult, error) {
+		for _, res := range results {
+			if res.ToChainID != toChainID {
+				continue
+			}
+			if strings.EqualFold(res.SwapTx, swapTx) {
+				return res, nil
+			}
+			for _, oldSwapTx := range res.OldSwapTxs {
+				if strings.EqualFold(oldSwapTx, swapTx) {
+					return res, nil
+				}
+			}
+		}
+		return nil, mongodb.ErrItemNotFound
+	}
+	findAuditSwapResultsInHeights = func(chainID string, isFromChain bool, minHeight, maxHeight uint64) (list []*mongodb.MgoSwapResult, err error) {
+		for _, res := range results {
+			chain, height := res.ToChainID, res.SwapHeight
+			if isFromChain {
+				chain, height = res.FromChainID, res.TxHeight
+			}
+			if chain == chainID && height >= minHeight && height <= maxHeight {
+				list = append(list, res)
+			}
+		}
+		return list, nil
+	}
+	return func() {
+		findAuditSwapResultsOfTx, findAuditSwapResultBySwapTx, findAuditSwapResultsInHeights = oldOfTx, oldBySwapTx, oldInHeights
+	}
+}
+
+func TestAuditAccountHistory(t *testing.T) {
+	account := "rAccount"
+	results := []*mongodb.MgoSwapResult{
+		{Key: "deposit1", TxID: "D1", TxHeight: 10, FromChainID: testAuditChainID, ToChainID: "56", Value: "1000", Status: mongodb.MatchTxStable},
+		{Key: "deposit2", TxID: "D2", TxHeight: 11, FromChainID: testAuditChainID, ToChainID: "56", Value: "999"},
+		{Key: "deposit3", TxID: "D3", TxHeight: 12, FromChainID: testAuditChainID, ToChainID: "56", Value: "1"},
+		{Key: "withdraw1", TxID: "0x01", FromChainID: "56", ToChainID: testAuditChainID, SwapTx: "W1", SwapHeight: 20, SwapValue: "500", MPC: account},
+		{Key: "withdraw2", TxID: "0x02", FromChainID: "56", ToChainID: testAuditChainID, SwapTx: "W2", OldSwapTxs: []string{"W2", "W2OLD"}, SwapHeight: 200, SwapValue: "600", MPC: account},
+		{Key: "withdraw3", TxID: "0x03", FromChainID: "56", ToChainID: testAuditChainID, SwapTx: "W3", SwapHeight: 22, SwapValue: "1", MPC: "rOther"},
+		{Key: "withdraw4", TxID: "0x04", FromChainID: "56", ToChainID: testAuditChainID, SwapTx: "W4", SwapHeight: 23, SwapValue: "1", MPC: account},
+		{Key: "outOfRange", TxID: "D9", TxHeight: 1000, FromChainID: testAuditChainID, ToChainID: "56", Value: "1"},
+	}
+	defer setupAuditSwapResults(results)()
+
+	transfers := []*tokens.AccountTransfer{
+		{TxHash: "D1", Height: 10, Success: true, Direction: tokens.TransferIn, Value: big.NewInt(1000)},
+		{TxHash: "d2", Height: 11, Success: true, Direction: tokens.TransferIn, Value: big.NewInt(2000), SwapInfo: &tokens.SwapTxInfo{Value: big.NewInt(1000)}},
+		{TxHash: "D4", Height: 13, Success: true, Direction: tokens.TransferIn, Value: big.NewInt(1)},
+		{TxHash: "D5", Height: 14, Success: true, Direction: tokens.TransferIn, Value: big.NewInt(1), VerifyError: "missing memo"},
+		{TxHash: "W1", Height: 20, Success: true, Direction: tokens.TransferOut, Value: big.NewInt(500)},
+		{TxHash: "W2OLD", Height: 21, Success: true, Direction: tokens.TransferOut, Value: big.NewInt(600)},
+		{TxHash: "W5", Height: 24, Success: true, Direction: tokens.TransferOut, Value: big.NewInt(1)},
+		{TxHash: "F1", Height: 25, Success: false, Direction: tokens.TransferOut, Value: big.NewInt(1)},
+		{TxHash: "S1", Height: 26, Success: true},
+	}
+	router.SetBridge(testAuditChainID, &testAuditorBridge{transfers: transfers})
+	defer router.SetBridge(testAuditChainID, nil)
+
+	report, err := AuditAccountHistory(testAuditChainID, account, 1, 100)
+	if err != nil {
+		t.Fatalf("audit account history failed: %v", err)
+	}
+
+	wantEntries := []struct {
+		status  string
+		swapKey string
+	}{
+		{AuditMatched, "deposit1"},
+		{AuditMismatched, "deposit2"},
+		{AuditMissing, ""},
+		{AuditInvalid, ""},
+		{AuditMatched, "withdraw1"},
+		{AuditMatched, "withdraw2"},
+		{AuditMissing, ""},
+		{AuditIgnored, ""},
+		{AuditIgnored, ""},
+		{AuditNotOnChain, "deposit3"},
+		{AuditNotOnChain, "withdraw4"},
+	}
+	if len(report.Entries) != len(wantEntries) {
+		t.Fatalf("audit entries count mismatch, got %v, want %v", len(report.Entries), len(wantEntries))
+	}
+	for i, want := range wantEntries {
+		entry := report.Entries[i]
+		if entry.Status != want.status || entry.SwapKey != want.swapKey {
+			t.Errorf("test %v: audit entry mismatch, got %v/%v, want %v/%v", i, entry.Status, entry.SwapKey, want.status, want.swapKey)
+		}
+	}
+	if detail := report.Entries[5].Detail; !strings.Contains(detail, "old swap tx") {
+		t.Errorf("audit entry of old swap tx has wrong detail %q", detail)
+	}
+	wantSummary := map[string]int{
+		AuditMatched:    3,
+		AuditMismatched: 1,
+		AuditMissing:    2,
+		AuditInvalid:    1,
+		AuditIgnored:    2,
+		AuditNotOnChain: 2,
+	}
+	for status, count := range wantSummary {
+		if report.Summary[status] != count {
+			t.Errorf("audit summary of %v mismatch, got %v, want %v", status, report.Summary[status], count)
+		}
+	}
+}
+
+func TestAuditAccountHistoryError(t *testing.T) {
+	defer setupAuditSwapResults(nil)()
+	defer router.SetBridge(testAuditChainID, nil)
+
+	if _, err := AuditAccountHistory(testAuditChainID, "rAccount", 1, 100); !errors.Is(err, tokens.ErrNoBridgeForChainID) {
+		t.Errorf("audit without bridge, got error %v, want %v", err, tokens.ErrNoBridgeForChainID)
+	}
+
+	router.SetBridge(testAuditChainID, &testNonAuditorBridge{})
+	if _, err := AuditAccountHistory(testAuditChainID, "rAccount", 1, 100); !errors.Is(err, tokens.ErrNotImplemented) {
+		t.Errorf("audit of non auditor bridge, got error %v, want %v", err, tokens.ErrNotImplemented)
+	}
+
+	errRange := errors.New("ledger range not covered")
+	router.SetBridge(testAuditChainID, &testAuditorBridge{err: errRange})
+	if _, err := AuditAccountHistory(testAuditChainID, "rAccount", 1, 100); !errors.Is(err, errRange) {
+		t.Errorf("audit with auditor error, got error %v, want %v", err, errRange)
+	}
+}
+
+func TestAccountAuditReportHash(t *testing.T) {
+	report := &AccountAuditReport{
+		ChainID:   testAuditChainID,
+		Account:   "rAccount",
+		MinHeight: 1,
+		MaxHeight: 100,
+		Summary:   map[string]int{AuditMatched: 1},
+		Entries:   []*AccountAuditEntry{{Status: AuditMatched, SwapKey: "deposit1"}},
+	}
+	hash, err := report.Hash()
+	if err != nil {
+		t.Fatalf("hash report failed: %v", err)
+	}
+
+	report.Signer = "0x1111111111111111111111111111111111111111"
+	report.Signature = "0x1234"
+	signedHash, _ := report.Hash()
+	if signedHash != hash {
+		t.Errorf("report hash changed by signature, got %v, want %v", signedHash.String(), hash.String())
+	}
+	if report.Signature != "0x1234" {
+		t.Errorf("report hash modified the report signature")
+	}
+
+	report.Entries[0].Status = AuditMismatched
+	if changedHash, _ := report.Hash(); changedHash == hash {
+		t.Errorf("report hash not changed with entries")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tokens/cardano"
 )
@@ -58,6 +59,11 @@ func doAggregateJob() {
 	if utils.IsCleanuping() {
 		return
 	}
+	chainID := cardano.BridgeInstance.GetChainConfig().ChainID
+	if !params.IsFeatureEnabled(params.FeatureAggregate, chainID) {
+		logWorker("aggregate", "aggregate is disabled by feature flag", "chainID", chainID)
+		return
+	}
 	if txHash, err := cardano.BridgeInstance.AggregateTx(); err != nil {
 		logWorkerError("aggregate", "aggregate tx err", err)
 	} else {
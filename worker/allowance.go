@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// allowances of the mpc to spenders pulling underlying tokens by transferFrom
+// are checked per chain, otherwise a run out allowance only surfaces as
+// opaque reverts of the destination swap txs.
+var (
+	allowanceStarter sync.Once
+
+	// key is chainID:token:spender, value is the latest approve time
+	allowanceApproved = make(map[string]int64)
+
+	errAllowanceLow = errors.New("mpc allowance is low")
+)
+
+// StartAllowanceJob allowance monitor job
+func StartAllowanceJob() {
+	allowanceStarter.Do(func() {
+		logWorker("allowance", "start allowance monitor job")
+		goSupervisedJob("allowance", nil, doAllowanceJob)
+	})
+}
+
+func doAllowanceJob() {
+	for {
+		router.RouterBridges.Range(func(k, v interface{}) bool {
+			checkAllowances(k.(string), v.(tokens.IBridge))
+			return !utils.IsCleanuping()
+		})
+		if utils.IsCleanuping() {
+			logWorker("allowance", "stop allowance monitor job")
+			return
+		}
+		restInJob(restIntervalInAllowanceJob)
+	}
+}
+
+func checkAllowances(chainID string, bridge tokens.IBridge) {
+	cfg := params.GetAllowanceMonitorConfig(chainID)
+	if cfg == nil {
+		return
+	}
+	manager, ok := bridge.(tokens.AllowanceManager)
+	if !ok {
+		return
+	}
+	for _, allowanceCfg := range cfg.Allowances {
+		if utils.IsCleanuping() {
+			return
+		}
+		allowance, err := manager.GetMPCAllowance(allowanceCfg.Token, allowanceCfg.Spender)
+		if err != nil {
+			logWorkerWarn("allowance", "get mpc allowance failed", "chainID", chainID, "token", allowanceCfg.Token, "spender", allowanceCfg.Spender, "err", err)
+			continue
+		}
+		minAllowance := allowanceCfg.GetMinAllowance()
+		if allowance.Cmp(minAllowance) >= 0 {
+			continue
+		}
+		logWorkerError("allowance", "mpc allowance is low", errAllowanceLow, "chainID", chainID, "token", allowanceCfg.Token, "spender", allowanceCfg.Spender, "allowance", allowance, "minAllowance", minAllowance)
+		if !cfg.AutoApprove {
+			continue
+		}
+		key := strings.ToLower(chainID + ":" + allowanceCfg.Token + ":" + allowanceCfg.Spender)
+		nowTime := now()
+		if nowTime-allowanceApproved[key] < allowanceApproveRetryInterval {
+			continue // wait for the previous approve tx
+		}
+		allowanceApproved[key] = nowTime
+		approveAllowance(chainID, manager, allowanceCfg, allowance.Sign() > 0, int(nowTime))
+	}
+}
+
+// approveAllowance approve zero first for tokens rejecting changes of nonzero allowances,
+// the following approve tx is queued after it and has the next nonce.
+func approveAllowance(chainID string, manager tokens.AllowanceManager, allowanceCfg *params.AllowanceConfig, nonzero bool, sequence int) {
+	if nonzero && allowanceCfg.ZeroFirst {
+		if err := dispatchAllowanceApprove(chainID, manager, allowanceCfg, big.NewInt(0), sequence); err != nil {
+			return
+		}
+	}
+	_ = dispatchAllowanceApprove(chainID, manager, allowanceCfg, allowanceCfg.GetApproveAmount(), sequence)
+}
+
+// dispatchAllowanceApprove queue the approve tx in the swap task queue
+func dispatchAllowanceApprove(chainID string, manager tokens.AllowanceManager, allowanceCfg *params.AllowanceConfig, amount *big.Int, sequence int) error {
+	args, err := manager.GetAllowanceApproveArgs(allowanceCfg.Token, allowanceCfg.Spender, amount, sequence)
+	if err == nil {
+		err = dispatchInternalTx(args, func(txHash string) {
+			logWorker("allowance", "approve allowance success", "chainID", chainID, "token", allowanceCfg.Token, "spender", allowanceCfg.Spender, "amount", amount, "txHash", txHash)
+		})
+	}
+	if err != nil {
+		logWorkerError("allowance", "approve allowance failed", err, "chainID", chainID, "token", allowanceCfg.Token, "spender", allowanceCfg.Spender, "amount", amount)
+	}
+	return err
+}
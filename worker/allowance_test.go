@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testAllowanceBridge struct {
+	tokens.IBridge
+	allowance *big.Int
+	approved  []*big.Int
+}
+
+func (b *testAllowanceBridge) GetMPCAllowance(token, spender string) (*big.Int, error) {
+	return b.allowance, nil
+}
+
+// GetAllowanceApproveArgs records the approve amounts without dispatching the approve txs
+func (b *testAllowanceBridge) GetAllowanceApproveArgs(token, spender string, amount *big.Int, sequence int) (*tokens.BuildTxArgs, error) {
+	b.approved = append(b.approved, amount)
+	return nil, errors.New("approve is not dispatched in test")
+}
+
+func TestCheckAllowances(t *testing.T) {
+	monitorCfg := &params.AllowanceMonitorConfig{Allowances: []*params.AllowanceConfig{
+		{
+			Token:         "0x1111111111111111111111111111111111111111",
+			Spender:       "0x2222222222222222222222222222222222222222",
+			MinAllowance:  "100",
+			ApproveAmount: "1000",
+			ZeroFirst:     true,
+		},
+	}}
+	if err := monitorCfg.CheckConfig(); err != nil {
+		t.Fatalf("check allowance monitor config failed: %v", err)
+	}
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			"56": {AllowanceMonitor: monitorCfg},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }()
+
+	key := "56:0x1111111111111111111111111111111111111111:0x2222222222222222222222222222222222222222"
+	defer delete(allowanceApproved, key)
+
+	// enough allowance
+	bridge := &testAllowanceBridge{allowance: big.NewInt(100)}
+	checkAllowances("56", bridge)
+	if len(bridge.approved) != 0 {
+		t.Errorf("enough allowance got approved %v", bridge.approved)
+	}
+
+	// low allowance is only alerted if not auto approve
+	bridge.allowance = big.NewInt(99)
+	checkAllowances("56", bridge)
+	if len(bridge.approved) != 0 {
+		t.Errorf("low allowance without auto approve got approved %v", bridge.approved)
+	}
+
+	// nonzero allowance is approved zero first, the failure stops the following approve
+	monitorCfg.AutoApprove = true
+	checkAllowances("56", bridge)
+	if len(bridge.approved) != 1 || bridge.approved[0].Sign() != 0 {
+		t.Fatalf("low nonzero allowance got approved %v, want [0]", bridge.approved)
+	}
+	// wait for the previous approve tx
+	checkAllowances("56", bridge)
+	if len(bridge.approved) != 1 {
+		t.Errorf("approve again in retry interval got approved %v", bridge.approved)
+	}
+
+	// zero allowance is approved to the approve amount directly
+	delete(allowanceApproved, key)
+	bridge.allowance = big.NewInt(0)
+	checkAllowances("56", bridge)
+	if len(bridge.approved) != 2 || bridge.approved[1].Int64() != 1000 {
+		t.Errorf("zero allowance got approved %v, want [0 1000]", bridge.approved)
+	}
+
+	// unmonitored chain
+	checkAllowances("1", bridge)
+	if len(bridge.approved) != 2 {
+		t.Errorf("unmonitored chain got approved %v", bridge.approved)
+	}
+}
@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+)
+
+// ApprovalDecision decision of external approval hook
+type ApprovalDecision string
+
+// decisions of external approval hooks
+const (
+	ApprovalApprove ApprovalDecision = "approve"
+	ApprovalBlock   ApprovalDecision = "block"
+	ApprovalHold    ApprovalDecision = "hold"
+)
+
+// ApprovalRequest swap details posted to approval hooks
+type ApprovalRequest struct {
+	Identifier  string `json:"identifier"`
+	FromChainID string `json:"fromChainID"`
+	ToChainID   string `json:"toChainID"`
+	TxID        string `json:"txid"`
+	LogIndex    int    `json:"logIndex"`
+	SwapType    uint32 `json:"swapType"`
+	TokenID     string `json:"tokenID"`
+	Token       string `json:"token"`
+	From        string `json:"from"`
+	TxTo        string `json:"txto"`
+	Bind        string `json:"bind"`
+	Value       string `json:"value"`
+	InitTime    int64  `json:"inittime"` // milli seconds
+}
+
+// ApprovalResponse response of approval hooks
+type ApprovalResponse struct {
+	Decision   ApprovalDecision `json:"decision"`
+	Reason     string           `json:"reason,omitempty"`
+	RetryAfter int64            `json:"retryAfter,omitempty"` // seconds, only for hold
+}
+
+// checkApprovalHooks call the approval hooks in turn before the swap is signed,
+// the swap is processed only if all of the hooks approve it.
+func checkApprovalHooks(swap *mongodb.MgoSwap, res *mongodb.MgoSwapResult) error {
+	hooks := params.GetApprovalHooks()
+	if len(hooks) == 0 {
+		return nil
+	}
+	req := &ApprovalRequest{
+		Identifier:  params.GetIdentifier(),
+		FromChainID: swap.FromChainID,
+		ToChainID:   swap.ToChainID,
+		TxID:        swap.TxID,
+		LogIndex:    swap.LogIndex,
+		SwapType:    swap.SwapType,
+		TokenID:     swap.GetTokenID(),
+		Token:       swap.GetToken(),
+		From:        swap.From,
+		TxTo:        swap.TxTo,
+		Bind:        swap.Bind,
+		Value:       res.Value,
+		InitTime:    res.InitTime,
+	}
+	for _, hook := range hooks {
+		ctx := []interface{}{"hook", hook.Name, "fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex}
+		resp := askApprovalHook(hook, req, ctx)
+		if resp == nil {
+			continue
+		}
+		ctx = append(ctx, "decision", resp.Decision, "reason", resp.Reason)
+		recordSwapEvent(swap.FromChainID, swap.TxID, swap.LogIndex, mongodb.SwapEventApproval, 0,
+			fmt.Sprintf("%v:%v:%v", hook.Name, resp.Decision, resp.Reason))
+
+		switch resp.Decision {
+		case ApprovalApprove:
+			logWorkerTrace("approval", "swap is approved", ctx...)
+		case ApprovalBlock:
+			logWorkerWarn("approval", "swap is blocked", ctx...)
+			return blockSwapByHook(swap, hook.Name, resp.Reason)
+		default:
+			retryAfter := resp.RetryAfter
+			if retryAfter <= 0 {
+				retryAfter = hook.GetHoldInterval()
+			}
+			logWorker("approval", "swap is held", append(ctx, "retryAfter", retryAfter)...)
+			deferSwapUntil(swap, WaitExternalApproval, now()+retryAfter)
+			return errSwapDeferred
+		}
+	}
+	return nil
+}
+
+// askApprovalHook get decision of the hook, the failed call is regarded as hold
+// if the hook is fail closed, and nil is returned if the hook is fail open.
+func askApprovalHook(hook *params.ApprovalHookConfig, req *ApprovalRequest, ctx []interface{}) *ApprovalResponse {
+	resp, err := callApprovalHook(hook, req)
+	if err == nil {
+		return resp
+	}
+	if hook.FailOpen {
+		logWorkerWarn("approval", "call approval hook failed, fail open", append(ctx, "err", err)...)
+		return nil
+	}
+	logWorkerWarn("approval", "call approval hook failed, fail closed", append(ctx, "err", err)...)
+	return &ApprovalResponse{Decision: ApprovalHold, Reason: err.Error()}
+}
+
+func callApprovalHook(hook *params.ApprovalHookConfig, req *ApprovalRequest) (*ApprovalResponse, error) {
+	var resp ApprovalResponse
+	_, err := client.RPCPostBody(hook.URL, nil, hook.Headers, req, &resp, hook.GetTimeout(), nil)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.Decision {
+	case ApprovalApprove, ApprovalBlock, ApprovalHold:
+		return &resp, nil
+	default:
+		return nil, fmt.Errorf("unknown approval decision '%v'", resp.Decision)
+	}
+}
+
+func blockSwapByHook(swap *mongodb.MgoSwap, hookName, reason string) error {
+	memo := fmt.Sprintf("blocked by %v: %v", hookName, reason)
+	err := mongodb.UpdateRouterSwapResultStatus(swap.FromChainID, swap.TxID, swap.LogIndex, mongodb.ManualMakeFail, now(), memo)
+	if err != nil {
+		return err
+	}
+	return mongodb.UpdateRouterSwapStatus(swap.FromChainID, swap.TxID, swap.LogIndex, mongodb.ManualMakeFail, now(), memo)
+}
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+func TestCheckApprovalHooksNotConfigured(t *testing.T) {
+	cfg := params.GetRouterConfig()
+	oldServer := cfg.Server
+	defer func() { cfg.Server = oldServer }()
+	cfg.Server = &params.RouterServerConfig{}
+
+	// swaps are processed as before if there is no approval hooks
+	swap := &mongodb.MgoSwap{Key: "1:0x01:0", FromChainID: "1", TxID: "0x01"}
+	if err := checkApprovalHooks(swap, &mongodb.MgoSwapResult{}); err != nil {
+		t.Errorf("check approval hooks without hooks got error %v", err)
+	}
+}
+
+func TestAskApprovalHook(t *testing.T) {
+	var response string
+	var received ApprovalRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		if response == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	req := &ApprovalRequest{FromChainID: "1", ToChainID: "56", TxID: "0x01", TokenID: "USDC", Value: "1000"}
+	tests := []struct {
+		response   string
+		failOpen   bool
+		decision   ApprovalDecision
+		retryAfter int64
+	}{
+		{`{"decision":"approve"}`, false, ApprovalApprove, 0},
+		{`{"decision":"block","reason":"sanctioned"}`, false, ApprovalBlock, 0},
+		{`{"decision":"hold","retryAfter":60}`, false, ApprovalHold, 60},
+		// failed calls and unknown decisions are held if fail closed
+		{`{"decision":"maybe"}`, false, ApprovalHold, 0},
+		{"", false, ApprovalHold, 0},
+		// and skipped if fail open
+		{"", true, "", 0},
+	}
+	for i, test := range tests {
+		response = test.response
+		hook := &params.ApprovalHookConfig{Name: "compliance", URL: server.URL, Headers: map[string]string{"Authorization": "Bearer test"}, FailOpen: test.failOpen}
+		resp := askApprovalHook(hook, req, nil)
+		if test.decision == "" {
+			if resp != nil {
+				t.Errorf("test %v: ask fail open approval hook got %+v, want nil", i, resp)
+			}
+			continue
+		}
+		if resp == nil || resp.Decision != test.decision || resp.RetryAfter != test.retryAfter {
+			t.Errorf("test %v: ask approval hook got %+v, want decision %v", i, resp, test.decision)
+		}
+		if received.TxID != req.TxID || received.Value != req.Value {
+			t.Errorf("test %v: approval hook received %+v, want %+v", i, received, req)
+		}
+	}
+}
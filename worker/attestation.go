@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var errAttestationNotComplete = errors.New("attestation is not complete")
+
+// isWaitingForAttestation anycall swap with message sent (usdc cctp) but without attestation
+func isWaitingForAttestation(info *mongodb.AnyCallSwapInfo) bool {
+	return info != nil &&
+		len(common.FromHex(info.Message)) > 0 &&
+		len(common.FromHex(info.Attestation)) == 0
+}
+
+// checkAttestation defer the swap until the attestation of its message is complete,
+// the attestation may be not ready when the swap is verified.
+func checkAttestation(swap *mongodb.MgoSwap, res *mongodb.MgoSwapResult) error {
+	if !isWaitingForAttestation(res.AnyCallSwapInfo) {
+		return nil
+	}
+	deferSwap(swap, WaitOracleAttestation)
+	return errSwapDeferred
+}
+
+// getCompleteAttestation get the attestation of message if it is complete
+func getCompleteAttestation(message string) (string, error) {
+	attestation, err := tokens.GetUSDCAttestation(common.FromHex(message))
+	if err != nil {
+		return "", err
+	}
+	if !attestation.IsComplete() {
+		return "", errAttestationNotComplete
+	}
+	return attestation.Attestation, nil
+}
+
+// checkAttestationReady query the attestation of deferred swap,
+// and save it in database if it is complete.
+func checkAttestationReady(ds *DeferredSwap) bool {
+	swap := ds.Swap
+	if !isWaitingForAttestation(swap.AnyCallSwapInfo) {
+		return true
+	}
+	attestation, err := getCompleteAttestation(swap.AnyCallSwapInfo.Message)
+	if err != nil {
+		logWorkerTrace("deferred", "attestation is not ready", "key", swap.Key, "err", err)
+		return false
+	}
+	err = mongodb.UpdateRouterSwapAttestation(swap.FromChainID, swap.TxID, swap.LogIndex, attestation)
+	if err != nil {
+		logWorkerError("deferred", "save attestation failed", err, "key", swap.Key)
+		return false
+	}
+	swap.AnyCallSwapInfo.Attestation = attestation
+	logWorker("deferred", "attestation is complete", "key", swap.Key)
+	return true
+}
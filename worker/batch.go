@@ -0,0 +1,145 @@
+package worker
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	batchSubmitters     = make(map[string]*batchSubmitter) // key is chainID
+	batchSubmittersLock sync.Mutex
+
+	batchCollectInterval = 2 * time.Second
+	maxBatchSize         = 20
+
+	errBatchMemberNoResult = errors.New("batch member has no result")
+)
+
+type batchMember struct {
+	args     *tokens.BuildTxArgs
+	signedTx interface{}
+	result   chan *tokens.BatchSendResult
+}
+
+type batchSubmitter struct {
+	chainID string
+	bridge  tokens.IBridge
+	sender  tokens.BatchSender
+	members chan *batchMember
+
+	// sendTx send the failed member individually
+	sendTx func(signedTx interface{}, args *tokens.BuildTxArgs) (string, error)
+	// onSent is called after the member is sent in batch successfully
+	onSent func(txHash string, member *batchMember)
+}
+
+func newBatchSubmitter(chainID string, bridge tokens.IBridge, sender tokens.BatchSender) *batchSubmitter {
+	s := &batchSubmitter{
+		chainID: chainID,
+		bridge:  bridge,
+		sender:  sender,
+		members: make(chan *batchMember, maxBatchSize),
+	}
+	s.sendTx = func(signedTx interface{}, args *tokens.BuildTxArgs) (string, error) {
+		return sendSignedTransaction(bridge, signedTx, args)
+	}
+	s.onSent = func(txHash string, member *batchMember) {
+		if params.GetRouterServerConfig().SendTxLoopCount[chainID] >= 0 {
+			go sendTxLoopUntilSuccess(bridge, txHash, member.signedTx, member.args)
+		}
+	}
+	return s
+}
+
+// sendSignedTransactionInBatch send signed tx in batch if the bridge supports
+// batch submission and the feature is enabled, otherwise send it individually.
+func sendSignedTransactionInBatch(bridge tokens.IBridge, signedTx interface{}, args *tokens.BuildTxArgs) (txHash string, err error) {
+	sender, ok := bridge.(tokens.BatchSender)
+	if !ok || !params.IsFeatureEnabled(params.FeatureBatchSend, args.ToChainID.String()) {
+		return sendSignedTransaction(bridge, signedTx, args)
+	}
+	member := &batchMember{
+		args:     args,
+		signedTx: signedTx,
+		result:   make(chan *tokens.BatchSendResult, 1),
+	}
+	getBatchSubmitter(args.ToChainID.String(), bridge, sender).members <- member
+	res := <-member.result
+	return res.TxHash, res.Err
+}
+
+func getBatchSubmitter(chainID string, bridge tokens.IBridge, sender tokens.BatchSender) *batchSubmitter {
+	batchSubmittersLock.Lock()
+	defer batchSubmittersLock.Unlock()
+
+	submitter, exist := batchSubmitters[chainID]
+	if !exist {
+		submitter = newBatchSubmitter(chainID, bridge, sender)
+		batchSubmitters[chainID] = submitter
+		go submitter.run()
+	}
+	return submitter
+}
+
+// run collect members in a short interval and submit them in one batch
+func (s *batchSubmitter) run() {
+	for {
+		batch := []*batchMember{<-s.members}
+		timer := time.NewTimer(batchCollectInterval)
+	COLLECT_LOOP:
+		for len(batch) < maxBatchSize {
+			select {
+			case member := <-s.members:
+				batch = append(batch, member)
+			case <-timer.C:
+				break COLLECT_LOOP
+			}
+		}
+		timer.Stop()
+		s.submit(batch)
+	}
+}
+
+// submit maps each member's result back to its swap,
+// the failed members are retried individually,
+// and never fail the whole batch because of some members.
+func (s *batchSubmitter) submit(batch []*batchMember) {
+	signedTxs := make([]interface{}, len(batch))
+	for i, member := range batch {
+		signedTxs[i] = member.signedTx
+	}
+
+	results := s.sender.SendTransactions(signedTxs)
+
+	var failed int
+	for i, member := range batch {
+		args := member.args
+		var res *tokens.BatchSendResult
+		if i < len(results) {
+			res = results[i]
+		}
+		if res != nil && res.Err == nil {
+			logWorker("batch", "send tx in batch success", "txHash", res.TxHash, "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex, "swapNonce", args.GetTxNonce())
+			s.onSent(res.TxHash, member)
+			member.result <- res
+			continue
+		}
+
+		failed++
+		err := errBatchMemberNoResult
+		if res != nil {
+			err = res.Err
+		}
+		logWorkerWarn("batch", "send tx in batch failed, retry individually", "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex, "swapNonce", args.GetTxNonce(), "err", err)
+		go func(member *batchMember) {
+			txHash, err := s.sendTx(member.signedTx, member.args)
+			member.result <- &tokens.BatchSendResult{TxHash: txHash, Err: err}
+		}(member)
+	}
+
+	logWorker("batch", "submit batch finished", "chainID", s.chainID, "members", len(batch), "failed", failed)
+}
@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testBatchSender struct {
+	results []*tokens.BatchSendResult
+	batches [][]interface{}
+}
+
+func (s *testBatchSender) SendTransactions(signedTxs []interface{}) []*tokens.BatchSendResult {
+	s.batches = append(s.batches, signedTxs)
+	return s.results
+}
+
+func newTestBatchMember(nonce uint64) *batchMember {
+	return &batchMember{
+		args: &tokens.BuildTxArgs{
+			SwapArgs: tokens.SwapArgs{
+				FromChainID: big.NewInt(1),
+				ToChainID:   big.NewInt(56),
+			},
+			Extra: &tokens.AllExtras{Sequence: &nonce},
+		},
+		signedTx: nonce,
+		result:   make(chan *tokens.BatchSendResult, 1),
+	}
+}
+
+func TestBatchSubmitPartialFailure(t *testing.T) {
+	sender := &testBatchSender{
+		results: []*tokens.BatchSendResult{
+			{TxHash: "0x01"},
+			{Err: errors.New("execution reverted")},
+			{TxHash: "0x03"},
+			// the last member has no result
+		},
+	}
+	s := newBatchSubmitter("56", nil, sender)
+	var resent []interface{}
+	var resentLock sync.Mutex
+	s.sendTx = func(signedTx interface{}, args *tokens.BuildTxArgs) (string, error) {
+		resentLock.Lock()
+		resent = append(resent, signedTx)
+		resentLock.Unlock()
+		if signedTx.(uint64) == 3 {
+			return "", errors.New("send failed again")
+		}
+		return "0x02", nil
+	}
+	var sent []string
+	s.onSent = func(txHash string, member *batchMember) {
+		sent = append(sent, txHash)
+	}
+
+	batch := []*batchMember{newTestBatchMember(0), newTestBatchMember(1), newTestBatchMember(2), newTestBatchMember(3)}
+	s.submit(batch)
+
+	if len(sender.batches) != 1 || len(sender.batches[0]) != 4 {
+		t.Fatalf("batches got %v, want one batch of 4 members", sender.batches)
+	}
+	// each member's result is mapped back to its own swap
+	wants := []struct {
+		txHash string
+		failed bool
+	}{
+		{"0x01", false},
+		{"0x02", false}, // retried individually
+		{"0x03", false},
+		{"", true}, // no result and retry failed
+	}
+	for i, member := range batch {
+		select {
+		case res := <-member.result:
+			if res.TxHash != wants[i].txHash || (res.Err != nil) != wants[i].failed {
+				t.Errorf("member %v got (%v, %v), want (%v, failed %v)", i, res.TxHash, res.Err, wants[i].txHash, wants[i].failed)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("member %v has no result", i)
+		}
+	}
+	// only the failed members are retried
+	if len(resent) != 2 || resent[0].(uint64)+resent[1].(uint64) != 4 {
+		t.Errorf("retried members got %v, want [1 3]", resent)
+	}
+	if len(sent) != 2 || sent[0] != "0x01" || sent[1] != "0x03" {
+		t.Errorf("sent in batch got %v, want [0x01 0x03]", sent)
+	}
+}
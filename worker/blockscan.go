@@ -0,0 +1,188 @@
+package worker
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// routerTxScanner name of the scan checkpoint of router txs
+const routerTxScanner = "routertx"
+
+var (
+	blockScanStarter sync.Once
+
+	// chains whose scan checkpoint is recovered, key is chainID
+	blockScanRecovered = new(sync.Map)
+
+	// registerScannedTx register the router tx found by scanning as swaps,
+	// set by the server as the register api is out of the worker.
+	registerScannedTx func(chainID, txHash string) error
+
+	errNoScannedTxRegister = errors.New("scanned tx register is not set")
+)
+
+// SetScannedTxRegister set the func to register scanned router txs
+func SetScannedTxRegister(register func(chainID, txHash string) error) {
+	registerScannedTx = register
+}
+
+// StartBlockScanJob scan blocks for router txs job
+func StartBlockScanJob() {
+	blockScanStarter.Do(func() {
+		logWorker("blockscan", "start block scan job")
+		mongodb.MgoWaitGroup.Add(1)
+		go doBlockScanJob()
+		startRouterTxStreams()
+	})
+}
+
+// startRouterTxStreams register the streamed router txs ahead of scanning
+func startRouterTxStreams() {
+	router.RouterBridges.Range(func(k, v interface{}) bool {
+		chainID := k.(string)
+		streamer, ok := v.(tokens.RouterTxStreamer)
+		if !ok {
+			return true
+		}
+		txs := streamer.StreamRouterTxs()
+		if txs == nil {
+			return true
+		}
+		logWorker("blockscan", "start router tx stream", "chainID", chainID)
+		go consumeStreamedRouterTxs(chainID, txs)
+		return true
+	})
+}
+
+// consumeStreamedRouterTxs register the streamed router txs,
+// txs failed to register are not acknowledged and will be redelivered.
+func consumeStreamedRouterTxs(chainID string, txs <-chan *tokens.StreamedRouterTx) {
+	for tx := range txs {
+		if utils.IsCleanuping() {
+			return
+		}
+		if err := registerStreamedRouterTx(chainID, tx); err != nil {
+			logWorkerError("blockscan", "register streamed tx failed", err, "chainID", chainID, "height", tx.Height, "txHash", tx.TxHash)
+			continue
+		}
+		logWorker("blockscan", "register streamed tx success", "chainID", chainID, "height", tx.Height, "txHash", tx.TxHash)
+	}
+}
+
+func registerStreamedRouterTx(chainID string, tx *tokens.StreamedRouterTx) error {
+	if registerScannedTx == nil {
+		return errNoScannedTxRegister
+	}
+	if err := registerScannedTx(chainID, tx.TxHash); err != nil {
+		return err
+	}
+	tx.Ack()
+	return nil
+}
+
+func doBlockScanJob() {
+	defer mongodb.MgoWaitGroup.Done()
+	for {
+		router.RouterBridges.Range(func(k, v interface{}) bool {
+			scanChainBlocks(k.(string), v.(tokens.IBridge))
+			return !utils.IsCleanuping()
+		})
+		if utils.IsCleanuping() {
+			logWorker("blockscan", "stop block scan job")
+			return
+		}
+		restInJob(restIntervalInBlockScanJob)
+	}
+}
+
+// scanChainBlocks scan the stable blocks after the scan checkpoint
+// (at most BlocksPerRound blocks in one round), and then backfill the gaps.
+func scanChainBlocks(chainID string, bridge tokens.IBridge) {
+	cfg := params.GetBlockScanConfig(chainID)
+	if cfg == nil {
+		return
+	}
+	scanner, ok := bridge.(tokens.BlockScanner)
+	if !ok {
+		return
+	}
+	latest, err := bridge.GetLatestBlockNumber()
+	if err != nil {
+		logWorkerError("blockscan", "get latest block number failed", err, "chainID", chainID)
+		return
+	}
+	confirmations := bridge.GetChainConfig().Confirmations
+	if latest <= confirmations {
+		return
+	}
+	stable := latest - confirmations
+
+	if _, recovered := blockScanRecovered.Load(chainID); !recovered {
+		start, errf := tokens.RecoverScanCheckpoint(chainID, routerTxScanner, stable, cfg.GetMaxLag())
+		if errf != nil {
+			logWorkerError("blockscan", "recover scan checkpoint failed", errf, "chainID", chainID)
+			return
+		}
+		blockScanRecovered.Store(chainID, struct{}{})
+		logWorker("blockscan", "recover scan checkpoint success", "chainID", chainID, "start", start, "stable", stable)
+	}
+
+	scan := func(height uint64) error {
+		return scanBlockRouterTxs(chainID, scanner, height)
+	}
+
+	cp, err := tokens.GetScanCheckpoint(chainID, routerTxScanner)
+	if err != nil {
+		logWorkerError("blockscan", "get scan checkpoint failed", err, "chainID", chainID)
+		return
+	}
+	to := stable
+	if cp.Height+cfg.GetBlocksPerRound() < to {
+		to = cp.Height + cfg.GetBlocksPerRound()
+	}
+	scanned, err := tokens.ScanToHeight(chainID, routerTxScanner, to, true, scan)
+	if err != nil {
+		logWorkerError("blockscan", "scan blocks failed", err, "chainID", chainID, "from", cp.Height+1, "to", to, "scanned", scanned)
+		return
+	}
+	if scanned > 0 {
+		logWorker("blockscan", "scan blocks success", "chainID", chainID, "from", cp.Height+1, "to", to)
+	}
+
+	// backfill after catching up
+	if to < stable {
+		return
+	}
+	filled, err := tokens.BackfillScanGaps(chainID, routerTxScanner, scan)
+	if err != nil {
+		logWorkerError("blockscan", "backfill scan gaps failed", err, "chainID", chainID, "filled", filled)
+	} else if filled > 0 {
+		logWorker("blockscan", "backfill scan gaps success", "chainID", chainID, "filled", filled)
+	}
+}
+
+// scanBlockRouterTxs scan router txs of block and register them,
+// the block is failed (and recorded as gap) if any tx failed to register.
+func scanBlockRouterTxs(chainID string, scanner tokens.BlockScanner, height uint64) error {
+	if registerScannedTx == nil {
+		return errNoScannedTxRegister
+	}
+	txHashes, err := scanner.ScanBlockRouterTxs(height)
+	if err != nil {
+		return err
+	}
+	for _, txHash := range txHashes {
+		if err = registerScannedTx(chainID, txHash); err != nil {
+			logWorkerError("blockscan", "register scanned tx failed", err, "chainID", chainID, "height", height, "txHash", txHash)
+			return err
+		}
+		logWorker("blockscan", "register scanned tx success", "chainID", chainID, "height", height, "txHash", txHash)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testBlockScanner struct {
+	txs map[uint64][]string
+}
+
+func (s *testBlockScanner) ScanBlockRouterTxs(height uint64) ([]string, error) {
+	return s.txs[height], nil
+}
+
+func TestScanBlockRouterTxs(t *testing.T) {
+	const chainID = "1"
+	scanner := &testBlockScanner{txs: map[uint64][]string{
+		102: {"0x02"},
+		103: {"0x03", "0x13"},
+	}}
+	registered := make(map[string]bool)
+	failing := map[string]bool{"0x13": true}
+	SetScannedTxRegister(func(chainID, txHash string) error {
+		if failing[txHash] {
+			return fmt.Errorf("register %v failed", txHash)
+		}
+		registered[txHash] = true
+		return nil
+	})
+	defer SetScannedTxRegister(nil)
+
+	scan := func(height uint64) error {
+		return scanBlockRouterTxs(chainID, scanner, height)
+	}
+	if _, err := tokens.RecoverScanCheckpoint(chainID, routerTxScanner, 100, 0); err != nil {
+		t.Fatalf("recover scan checkpoint failed: %v", err)
+	}
+	scanned, err := tokens.ScanToHeight(chainID, routerTxScanner, 104, true, scan)
+	if err != nil || scanned != 4 {
+		t.Fatalf("scan to height got (%v, %v), want 4 scanned", scanned, err)
+	}
+	if !registered["0x02"] || !registered["0x03"] {
+		t.Errorf("scanned txs are not registered, got %v", registered)
+	}
+	// the block with tx failed to register is recorded as gap
+	gaps, _ := tokens.GetCheckpointStore().FindScanGaps(chainID, routerTxScanner)
+	if len(gaps) != 1 || gaps[0].From != 103 || gaps[0].To != 103 {
+		t.Fatalf("scan gaps got %v, want [103, 103]", gaps)
+	}
+
+	delete(failing, "0x13")
+	filled, err := tokens.BackfillScanGaps(chainID, routerTxScanner, scan)
+	if err != nil || filled != 1 || !registered["0x13"] {
+		t.Errorf("backfill got (%v, %v), registered %v", filled, err, registered)
+	}
+
+	SetScannedTxRegister(nil)
+	if err = scan(102); !errors.Is(err, errNoScannedTxRegister) {
+		t.Errorf("scan without register got error %v, want %v", err, errNoScannedTxRegister)
+	}
+}
+
+func TestRegisterStreamedRouterTx(t *testing.T) {
+	const chainID = "1"
+	var acked []string
+	newStreamedTx := func(txHash string) *tokens.StreamedRouterTx {
+		return &tokens.StreamedRouterTx{TxHash: txHash, Height: 100, Ack: func() { acked = append(acked, txHash) }}
+	}
+
+	if err := registerStreamedRouterTx(chainID, newStreamedTx("0x01")); !errors.Is(err, errNoScannedTxRegister) {
+		t.Errorf("register without register func got error %v, want %v", err, errNoScannedTxRegister)
+	}
+	SetScannedTxRegister(func(chainID, txHash string) error {
+		if txHash == "0x02" {
+			return fmt.Errorf("register %v failed", txHash)
+		}
+		return nil
+	})
+	defer SetScannedTxRegister(nil)
+
+	// the tx failed to register is not acknowledged and will be redelivered
+	if err := registerStreamedRouterTx(chainID, newStreamedTx("0x02")); err == nil {
+		t.Errorf("register failing tx should fail")
+	}
+	if err := registerStreamedRouterTx(chainID, newStreamedTx("0x03")); err != nil {
+		t.Errorf("register streamed tx failed: %v", err)
+	}
+	if len(acked) != 1 || acked[0] != "0x03" {
+		t.Errorf("acked txs got %v, want [0x03]", acked)
+	}
+}
@@ -80,7 +80,7 @@ func checkFailedRouterSwap(swap *mongodb.MgoSwapResult) error {
 			"txid", swap.TxID, "logIndex", swap.LogIndex,
 			"swaptx", swap.SwapTx, "swapnonce", swap.SwapNonce,
 			"swapheight", txStatus.BlockHeight, "confirmations", txStatus.Confirmations)
-		if txStatus.Confirmations < resBridge.GetChainConfig().Confirmations {
+		if !txStatus.IsFinalized() {
 			return markSwapResultUnstable(swap.FromChainID, swap.TxID, swap.LogIndex)
 		}
 		return markSwapResultStable(swap.FromChainID, swap.TxID, swap.LogIndex)
@@ -0,0 +1,213 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// StartClaimSwapJob track claim status of two-phase swaps and refund expired claims
+func StartClaimSwapJob() {
+	logWorker("claimswap", "start claim swap job")
+
+	mongodb.MgoWaitGroup.Add(1)
+	go doClaimSwapJob()
+}
+
+func doClaimSwapJob() {
+	defer mongodb.MgoWaitGroup.Done()
+	for {
+		checkClaimSwaps()
+		if utils.IsCleanuping() {
+			logWorker("claimswap", "stop claim swap job")
+			return
+		}
+		restInJob(restIntervalInClaimSwapJob)
+	}
+}
+
+// recordClaimSwap start tracking claim status of stable two-phase swap
+func recordClaimSwap(swap *mongodb.MgoSwapResult) {
+	if swap.ERC20SwapInfo == nil {
+		return
+	}
+	var claimID, escrow string
+	if cfg := params.GetClaimSwapConfig(swap.ToChainID); cfg != nil && cfg.IsClaimToken(swap.ERC20SwapInfo.TokenID) {
+		claimID, escrow = getClaimID(swap.FromChainID, swap.TxID, swap.LogIndex), cfg.Escrow
+	} else if claimID = getClaimIDOfSwapTx(swap); claimID == "" {
+		return
+	}
+	nowTime := now()
+	_ = mongodb.AddClaimSwap(&mongodb.MgoClaimSwap{
+		FromChainID: swap.FromChainID,
+		TxID:        swap.TxID,
+		LogIndex:    swap.LogIndex,
+		ToChainID:   swap.ToChainID,
+		TokenID:     swap.ERC20SwapInfo.TokenID,
+		ClaimID:     claimID,
+		Escrow:      escrow,
+		Receiver:    swap.Bind,
+		Value:       swap.SwapValue,
+		SwapTx:      swap.SwapTx,
+		Status:      mongodb.ClaimStatusPending,
+		InitTime:    nowTime,
+		Timestamp:   nowTime,
+	})
+}
+
+// getClaimIDOfSwapTx get claim id of swap tx if the destination chain
+// decides to deliver in two phases when building (eg. ripple checks)
+func getClaimIDOfSwapTx(swap *mongodb.MgoSwapResult) string {
+	resBridge := router.GetBridgeByChainID(swap.ToChainID)
+	if resBridge == nil {
+		return ""
+	}
+	resolver, ok := resBridge.(tokens.ClaimSwapTxResolver)
+	if !ok {
+		return ""
+	}
+	claimID, err := resolver.GetClaimIDOfSwapTx(swap.SwapTx)
+	if err != nil {
+		logWorkerError("claimswap", "get claim id of swap tx failed", err, "fromChainID", swap.FromChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "swaptx", swap.SwapTx)
+		return ""
+	}
+	return claimID
+}
+
+// getClaimID claim id is the unique swap identifier of the swapin
+func getClaimID(fromChainID, txid string, logIndex int) string {
+	fromChainIDBig, _ := new(big.Int).SetString(fromChainID, 0)
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			FromChainID: fromChainIDBig,
+			SwapID:      txid,
+			LogIndex:    logIndex,
+			Salt:        params.GetDeploymentSalt(),
+		},
+	}
+	return args.GetUniqueSwapIdentifier()
+}
+
+func checkClaimSwaps() {
+	res, err := mongodb.FindClaimSwapsToCheck()
+	if err != nil {
+		logWorkerError("claimswap", "find claim swaps error", err)
+		return
+	}
+	for _, claim := range res {
+		if utils.IsCleanuping() {
+			return
+		}
+		err = checkClaimSwap(claim)
+		if err != nil {
+			logWorkerError("claimswap", "check claim swap failed", err, "fromChainID", claim.FromChainID, "txid", claim.TxID, "logIndex", claim.LogIndex, "claimID", claim.ClaimID)
+		}
+	}
+}
+
+func checkClaimSwap(claim *mongodb.MgoClaimSwap) error {
+	bridge := router.GetBridgeByChainID(claim.ToChainID)
+	if bridge == nil {
+		return tokens.ErrNoBridgeForChainID
+	}
+	claimer, ok := bridge.(tokens.ClaimSwapper)
+	if !ok {
+		return tokens.ErrSwapTypeNotSupported
+	}
+	if claim.Status == mongodb.ClaimStatusRedelivering {
+		return redeliverRefundedClaim(claim)
+	}
+	info, err := claimer.GetClaimInfo(claim.ClaimID)
+	if err != nil {
+		return err
+	}
+	nowTime := now()
+	status := claim.Status
+	switch info.Status {
+	case tokens.ClaimClaimed:
+		status = mongodb.ClaimStatusClaimed
+	case tokens.ClaimRefunded:
+		status = mongodb.ClaimStatusRefunded
+		if claim.Escrow == "" { // refunded to the mpc
+			status = mongodb.ClaimStatusRedelivering
+		}
+	case tokens.ClaimNotExist:
+		status = mongodb.ClaimStatusMissing
+	case tokens.ClaimPending:
+		if nowTime < info.Expiry+claimRefundDelay ||
+			(claim.Status == mongodb.ClaimStatusRefunding && nowTime < claim.RefundTime+claimRefundRetryInterval) {
+			break
+		}
+		return refundExpiredClaim(claimer, claim, info.Expiry)
+	}
+	if status == claim.Status && info.Expiry == claim.Expiry {
+		return nil
+	}
+	logWorker("claimswap", "update claim swap", "fromChainID", claim.FromChainID, "txid", claim.TxID, "logIndex", claim.LogIndex, "claimID", claim.ClaimID, "status", status, "expiry", info.Expiry)
+	return mongodb.UpdateClaimSwap(claim.FromChainID, claim.TxID, claim.LogIndex, status, info.Expiry, "", nowTime)
+}
+
+// refundExpiredClaim queue the refund tx of expired claim in the swap task queue
+func refundExpiredClaim(claimer tokens.ClaimSwapper, claim *mongodb.MgoClaimSwap, expiry int64) error {
+	args, err := claimer.GetClaimRefundArgs(claim.ClaimID)
+	if err != nil {
+		return err
+	}
+	err = dispatchInternalTx(args, func(txHash string) {
+		logWorker("claimswap", "refund expired claim", "fromChainID", claim.FromChainID, "txid", claim.TxID, "logIndex", claim.LogIndex, "claimID", claim.ClaimID, "expiry", expiry, "refundTx", txHash)
+		_ = mongodb.UpdateClaimSwap(claim.FromChainID, claim.TxID, claim.LogIndex, mongodb.ClaimStatusRefunding, expiry, txHash, now())
+	})
+	if errors.Is(err, errInternalTxInQueue) {
+		return nil
+	}
+	return err
+}
+
+// redeliverRefundedClaim redeliver the swap of which the claim is refunded to the mpc,
+// the swap is swapped again with direct payout (eg. a normal Payment instead of a check).
+func redeliverRefundedClaim(claim *mongodb.MgoClaimSwap) error {
+	res, err := mongodb.FindRouterSwapResult(claim.FromChainID, claim.TxID, claim.LogIndex)
+	if err != nil {
+		return err
+	}
+	if res.SwapTx != "" && !strings.EqualFold(res.SwapTx, claim.SwapTx) {
+		logWorker("claimswap", "refunded claim is redelivered", "fromChainID", claim.FromChainID, "txid", claim.TxID, "logIndex", claim.LogIndex, "claimID", claim.ClaimID, "swaptx", res.SwapTx)
+		return mongodb.UpdateClaimSwap(claim.FromChainID, claim.TxID, claim.LogIndex, mongodb.ClaimStatusRedelivered, claim.Expiry, "", now())
+	}
+	if res.Status != mongodb.MatchTxStable {
+		return nil // being redelivered by the swap job
+	}
+	logWorker("claimswap", "redeliver refunded claim", "fromChainID", claim.FromChainID, "txid", claim.TxID, "logIndex", claim.LogIndex, "claimID", claim.ClaimID, "swaptx", res.SwapTx)
+	return mongodb.RedeliverRouterSwap(claim.FromChainID, claim.TxID, claim.LogIndex, "redeliver refunded claim "+claim.ClaimID)
+}
+
+// isRedeliveringClaimSwap is swap redelivering its refunded claim
+func isRedeliveringClaimSwap(fromChainID, txid string, logIndex int) bool {
+	claim, err := mongodb.FindClaimSwap(fromChainID, txid, logIndex)
+	return err == nil && claim.Status == mongodb.ClaimStatusRedelivering
+}
+
+// isRefundedClaimSwapTx is swap tx placing claim which is refunded to the mpc,
+// such swap tx delivers nothing and the swap can be redelivered.
+func isRefundedClaimSwapTx(bridge tokens.IBridge, swapTx string) bool {
+	resolver, ok := bridge.(tokens.ClaimSwapTxResolver)
+	if !ok {
+		return false
+	}
+	claimer, ok := bridge.(tokens.ClaimSwapper)
+	if !ok {
+		return false
+	}
+	claimID, err := resolver.GetClaimIDOfSwapTx(swapTx)
+	if err != nil || claimID == "" {
+		return false
+	}
+	info, err := claimer.GetClaimInfo(claimID)
+	return err == nil && info.Status == tokens.ClaimRefunded
+}
@@ -1,8 +1,6 @@
 package worker
 
 import (
-	"strings"
-
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
@@ -16,7 +14,11 @@ type MatchTx struct {
 	SwapTime   uint64
 	SwapValue  string
 	SwapNonce  uint64
+	SwapFee    string
 	TTL        uint64
+
+	BuilderVersion     uint64
+	DeliveryFeePercent uint64
 }
 
 // AddInitialSwapResult add initial result
@@ -80,9 +82,18 @@ func updateRouterSwapResult(fromChainID, txid string, logIndex int, mtx *MatchTx
 			updates.SwapTx = mtx.SwapTx
 		}
 	}
+	if mtx.SwapFee != "" {
+		updates.SwapFee = mtx.SwapFee
+	}
 	if mtx.TTL > 0 {
 		updates.TTL = mtx.TTL
 	}
+	if mtx.BuilderVersion > 0 {
+		updates.BuilderVersion = mtx.BuilderVersion
+	}
+	if mtx.DeliveryFeePercent > 0 {
+		updates.DeliveryFeePercent = mtx.DeliveryFeePercent
+	}
 	err = mongodb.UpdateRouterSwapResult(fromChainID, txid, logIndex, updates)
 	if err != nil {
 		logWorkerError("update", "updateSwapResult failed", err,
@@ -129,11 +140,12 @@ func updateSwapTimestamp(fromChainID, txid string, logIndex int) (err error) {
 	return err
 }
 
-func updateSwapTx(fromChainID, txid string, logIndex int, swapTx string) (err error) {
+func updateSwapTx(fromChainID, txid string, logIndex int, swapTx string, deliveryFeePercent uint64) (err error) {
 	updates := &mongodb.SwapResultUpdateItems{
-		Status:    mongodb.KeepStatus,
-		SwapTx:    swapTx,
-		Timestamp: now(),
+		Status:             mongodb.KeepStatus,
+		SwapTx:             swapTx,
+		Timestamp:          now(),
+		DeliveryFeePercent: deliveryFeePercent,
 	}
 	err = mongodb.UpdateRouterSwapResult(fromChainID, txid, logIndex, updates)
 	if err != nil {
@@ -197,7 +209,7 @@ func sendSignedTransaction(bridge tokens.IBridge, signedTx interface{}, args *to
 SENDTX_LOOP:
 	for loop := 0; loop < retrySendTxLoops; loop++ {
 		for i := 0; i < 3; i++ {
-			txHash, err = bridge.SendTransaction(signedTx)
+			txHash, err = sendTransaction(bridge, signedTx)
 			if err == nil {
 				logWorker("sendtx", "send tx success", "txHash", txHash, "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex, "swapNonce", swapTxNonce, "replaceNum", replaceNum)
 				break SENDTX_LOOP
@@ -206,7 +218,7 @@ SENDTX_LOOP:
 		}
 
 		// prevent sendtx failed cause many same swap nonce allocation
-		if !needRetrySendTx(err) || loop+1 == retrySendTxLoops {
+		if tokens.ClassifySendTxError(bridge, err) != tokens.SendTxErrTemporary || loop+1 == retrySendTxLoops {
 			break SENDTX_LOOP
 		}
 		logWorkerWarn("sendtx", "send tx failed and will retry",
@@ -218,26 +230,30 @@ SENDTX_LOOP:
 	}
 
 	increseNonceWhenSendTx := params.IncreaseNonceWhenSendTx(args.ToChainID.String())
-	if increseNonceWhenSendTx && !params.IsParallelSwapEnabled() {
+	if increseNonceWhenSendTx && !params.IsParallelSwapEnabledOnChain(args.ToChainID.String()) {
 		nonceSetter, ok := bridge.(tokens.NonceSetter)
 		if ok && nonceSetter != nil {
 			nonceSetter.SetNonce(args.From, swapTxNonce+1)
 		}
 	}
 
+	if err != nil {
+		err = handleSendTxError(bridge, args, err)
+	}
 	if err != nil {
 		logWorkerError("sendtx", "send tx failed", err, "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex, "swapNonce", swapTxNonce, "replaceNum", replaceNum)
 		return txHash, err
 	}
 
-	if !increseNonceWhenSendTx && !params.IsParallelSwapEnabled() {
+	if !increseNonceWhenSendTx && !params.IsParallelSwapEnabledOnChain(args.ToChainID.String()) {
 		nonceSetter, ok := bridge.(tokens.NonceSetter)
 		if ok && nonceSetter != nil {
 			nonceSetter.SetNonce(args.From, swapTxNonce+1)
 		}
 	}
 
-	if params.GetRouterServerConfig().SendTxLoopCount[args.ToChainID.String()] >= 0 {
+	// txHash is empty if the tx is already known by the nodes
+	if txHash != "" && params.GetRouterServerConfig().SendTxLoopCount[args.ToChainID.String()] >= 0 {
 		go sendTxLoopUntilSuccess(bridge, txHash, signedTx, args)
 	}
 
@@ -275,7 +291,7 @@ func sendTxLoopUntilSuccess(bridge tokens.IBridge, txHash string, signedTx inter
 			break
 		}
 
-		txHash, err = bridge.SendTransaction(signedTx)
+		txHash, err = sendTransaction(bridge, signedTx)
 		if err != nil {
 			logWorkerTrace("sendtx", "send tx in loop failed", err, "swapID", args.SwapID, "txHash", txHash, "loop", loop)
 		} else {
@@ -284,14 +300,32 @@ func sendTxLoopUntilSuccess(bridge tokens.IBridge, txHash string, signedTx inter
 	}
 }
 
-func needRetrySendTx(err error) bool {
-	errMsg := err.Error()
-	switch {
-	case strings.Contains(errMsg, "Client.Timeout exceeded while awaiting headers"): // timeout
-	case strings.Contains(errMsg, "json-rpc error -32000, internal"): // cronos specific
-	case strings.EqualFold(errMsg, "json-rpc error -32000, "): // cronos specific
-	default:
-		return false
+// handleSendTxError drive worker behavior by the error class of sending tx.
+// returns nil if the tx should be treated as sent.
+func handleSendTxError(bridge tokens.IBridge, args *tokens.BuildTxArgs, err error) error {
+	class := tokens.ClassifySendTxError(bridge, err)
+	ctx := []interface{}{"fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex, "swapNonce", args.GetTxNonce(), "class", class, "err", err}
+	switch class {
+	case tokens.SendTxErrAlreadyKnown:
+		logWorker("sendtx", "send tx already known, treat as sent", ctx...)
+		return nil
+	case tokens.SendTxErrNonceTooLow:
+		// the cached nonce is stale, refresh it from the pool
+		// (in parallel mode the replace job will recycle the swap nonce)
+		if nonceSetter, ok := bridge.(tokens.NonceSetter); ok && !params.IsParallelSwapEnabledOnChain(args.ToChainID.String()) {
+			if nonce, errf := nonceSetter.GetPoolNonce(args.From, "pending"); errf == nil {
+				nonceSetter.SetNonce(args.From, nonce)
+				ctx = append(ctx, "poolNonce", nonce)
+			}
+		}
+		logWorkerWarn("sendtx", "send tx with nonce too low", ctx...)
+	case tokens.SendTxErrUnderpriced:
+		// the replace job will bump gas price of the pending swap
+		logWorkerWarn("sendtx", "send tx underpriced, wait to replace", ctx...)
+	case tokens.SendTxErrInsufficientFunds:
+		logWorkerWarn("sendtx", "send tx with insufficient funds", ctx...)
+	case tokens.SendTxErrOutOfGas:
+		logWorkerWarn("sendtx", "send tx out of gas", ctx...)
 	}
-	return true
+	return err
 }
@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// getRequiredConfirmations get required source confirmations of swap.
+// it is tiered by swap value if configed, otherwise is 'Confirmations' of the source chain.
+func getRequiredConfirmations(bridge tokens.IBridge, swapInfo *tokens.SwapTxInfo) uint64 {
+	confirmations := bridge.GetChainConfig().Confirmations
+	if swapInfo.SwapType != tokens.ERC20SwapType || swapInfo.Value == nil {
+		return confirmations
+	}
+	tiers := params.GetConfirmationTiers(swapInfo.FromChainID.String(), swapInfo.ToChainID.String(), swapInfo.GetTokenID())
+	if len(tiers) == 0 {
+		return confirmations
+	}
+	tokenCfg := bridge.GetTokenConfig(swapInfo.ERC20SwapInfo.Token)
+	if tokenCfg == nil {
+		return confirmations
+	}
+	for _, tier := range tiers {
+		if tier.MaxValue == "" {
+			return tier.Confirmations
+		}
+		maxValue := tokens.ToBits(tier.MaxValue, tokenCfg.Decimals)
+		if maxValue != nil && swapInfo.Value.Cmp(maxValue) <= 0 {
+			return tier.Confirmations
+		}
+	}
+	return confirmations
+}
+
+// getMinRequiredConfirmations get the least confirmations swap may require before its value is known
+func getMinRequiredConfirmations(bridge tokens.IBridge, fromChainID, toChainID, tokenID string) uint64 {
+	confirmations := bridge.GetChainConfig().Confirmations
+	for _, tier := range params.GetConfirmationTiers(fromChainID, toChainID, tokenID) {
+		if tier.Confirmations < confirmations {
+			confirmations = tier.Confirmations
+		}
+	}
+	return confirmations
+}
+
+// verifySwapTx verify swap tx with value tiered confirmations.
+// if small swaps may require less confirmations than the source chain,
+// verify unstable tx first to get its value, and verify again without
+// allowing unstable if it requires no less confirmations or is big value swap.
+func verifySwapTx(bridge tokens.IBridge, txid, toChainID, tokenID string, args *tokens.VerifyArgs) (*tokens.SwapTxInfo, error) {
+	chainConfirmations := bridge.GetChainConfig().Confirmations
+	fromChainID := bridge.GetChainConfig().ChainID
+	args.AllowUnstable = getMinRequiredConfirmations(bridge, fromChainID, toChainID, tokenID) < chainConfirmations
+	swapInfo, err := bridge.VerifyTransaction(txid, args)
+	if err != nil {
+		return swapInfo, err
+	}
+	required := getRequiredConfirmations(bridge, swapInfo)
+	if args.AllowUnstable && (required >= chainConfirmations || router.IsBigValueSwap(swapInfo)) {
+		args.AllowUnstable = false
+		swapInfo, err = bridge.VerifyTransaction(txid, args)
+		if err != nil {
+			return swapInfo, err
+		}
+	}
+	if required == chainConfirmations {
+		return swapInfo, nil
+	}
+	txStatus, err := bridge.GetTransactionStatus(txid)
+	if err != nil {
+		return swapInfo, fmt.Errorf("%w: get tx status failed: %v", tokens.ErrRPCQueryError, err)
+	}
+	if txStatus.Confirmations < required {
+		logWorkerTrace("verify", "swap has not enough tiered confirmations", "fromChainID", fromChainID, "txid", txid, "logIndex", args.LogIndex, "confirmations", txStatus.Confirmations, "required", required)
+		return swapInfo, tokens.ErrTxNotStable
+	}
+	return swapInfo, nil
+}
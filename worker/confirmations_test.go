@@ -0,0 +1,154 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// testConfirmationBridge source chain requiring 12 confirmations,
+// txs are unstable until they have enough confirmations.
+type testConfirmationBridge struct {
+	tokens.IBridge
+	value         *big.Int
+	confirmations uint64
+	verifyCalls   []bool // allow unstable of verify calls
+}
+
+func (b *testConfirmationBridge) GetChainConfig() *tokens.ChainConfig {
+	return &tokens.ChainConfig{ChainID: "1", Confirmations: 12}
+}
+
+func (b *testConfirmationBridge) GetTokenConfig(string) *tokens.TokenConfig {
+	return &tokens.TokenConfig{Decimals: 6}
+}
+
+func (b *testConfirmationBridge) VerifyTransaction(txid string, args *tokens.VerifyArgs) (*tokens.SwapTxInfo, error) {
+	b.verifyCalls = append(b.verifyCalls, args.AllowUnstable)
+	swapInfo := &tokens.SwapTxInfo{
+		SwapInfo:    tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{TokenID: "USDC", Token: "0x01"}},
+		SwapType:    tokens.ERC20SwapType,
+		Hash:        txid,
+		Value:       b.value,
+		FromChainID: big.NewInt(1),
+		ToChainID:   big.NewInt(56),
+	}
+	if !args.AllowUnstable && b.confirmations < 12 {
+		return swapInfo, tokens.ErrTxNotStable
+	}
+	return swapInfo, nil
+}
+
+func (b *testConfirmationBridge) GetTransactionStatus(string) (*tokens.TxStatus, error) {
+	return &tokens.TxStatus{Confirmations: b.confirmations}, nil
+}
+
+func setupConfirmationTiers(t *testing.T) func() {
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		ConfirmationTiers: map[string]map[string][]*params.ConfirmationTier{
+			"1:56": {"USDC": {{MaxValue: "1000", Confirmations: 3}, {MaxValue: "100000", Confirmations: 12}, {Confirmations: 64}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	return func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }
+}
+
+func TestGetRequiredConfirmations(t *testing.T) {
+	defer setupConfirmationTiers(t)()
+
+	b := &testConfirmationBridge{}
+	tests := []struct {
+		value *big.Int
+		want  uint64
+	}{
+		{big.NewInt(1000000000), 3},
+		{big.NewInt(1000000001), 12},
+		{big.NewInt(100000000000), 12},
+		{big.NewInt(100000000001), 64},
+	}
+	for i, test := range tests {
+		b.value = test.value
+		swapInfo, _ := b.VerifyTransaction("0x01", &tokens.VerifyArgs{AllowUnstable: true})
+		if got := getRequiredConfirmations(b, swapInfo); got != test.want {
+			t.Errorf("test %v: required confirmations of value %v got %v, want %v", i, test.value, got, test.want)
+		}
+	}
+	if got := getMinRequiredConfirmations(b, "1", "56", "USDC"); got != 3 {
+		t.Errorf("min required confirmations got %v, want 3", got)
+	}
+	// swaps without tiers require confirmations of the source chain
+	if got := getMinRequiredConfirmations(b, "1", "56", "USDT"); got != 12 {
+		t.Errorf("min required confirmations without tiers got %v, want 12", got)
+	}
+}
+
+func TestVerifySwapTx(t *testing.T) {
+	defer setupConfirmationTiers(t)()
+
+	tests := []struct {
+		value         *big.Int
+		confirmations uint64
+		tokenID       string
+		wantErr       error
+		wantCalls     []bool
+	}{
+		// small swaps are verified with tiered confirmations
+		{big.NewInt(1000000000), 3, "USDC", nil, []bool{true}},
+		{big.NewInt(1000000000), 2, "USDC", tokens.ErrTxNotStable, []bool{true}},
+		// swaps requiring chain confirmations are verified again as stable
+		{big.NewInt(50000000000), 11, "USDC", tokens.ErrTxNotStable, []bool{true, false}},
+		{big.NewInt(50000000000), 12, "USDC", nil, []bool{true, false}},
+		// larger swaps require more than chain confirmations
+		{big.NewInt(200000000000), 12, "USDC", tokens.ErrTxNotStable, []bool{true, false}},
+		{big.NewInt(200000000000), 64, "USDC", nil, []bool{true, false}},
+		// swaps without tiers are never verified as unstable
+		{big.NewInt(1000000000), 3, "USDT", tokens.ErrTxNotStable, []bool{false}},
+		{big.NewInt(1000000000), 12, "USDT", nil, []bool{false}},
+	}
+	for i, test := range tests {
+		b := &testConfirmationBridge{value: test.value, confirmations: test.confirmations}
+		_, err := verifySwapTx(b, "0x01", "56", test.tokenID, &tokens.VerifyArgs{})
+		if !errors.Is(err, test.wantErr) || (test.wantErr == nil && err != nil) {
+			t.Errorf("test %v: verify swap tx got err %v, want %v", i, err, test.wantErr)
+		}
+		if len(b.verifyCalls) != len(test.wantCalls) {
+			t.Errorf("test %v: verify calls got %v, want %v", i, b.verifyCalls, test.wantCalls)
+			continue
+		}
+		for j, allowUnstable := range test.wantCalls {
+			if b.verifyCalls[j] != allowUnstable {
+				t.Errorf("test %v: verify calls got %v, want %v", i, b.verifyCalls, test.wantCalls)
+				break
+			}
+		}
+	}
+}
+
+func TestVerifyBigValueSwapTx(t *testing.T) {
+	defer setupConfirmationTiers(t)()
+
+	// small swaps above the big value threshold are verified again as stable
+	b := &testConfirmationBridge{value: big.NewInt(1000000000), confirmations: 3}
+	router.SetBridge("1", b)
+	defer router.SetBridge("1", nil)
+	swapCfgs := new(sync.Map)
+	toChainCfgs := new(sync.Map)
+	toChainCfgs.Store("56", &tokens.SwapConfig{BigValueThreshold: new(big.Int).Mul(big.NewInt(500), big.NewInt(1e18))})
+	fromChainCfgs := new(sync.Map)
+	fromChainCfgs.Store("1", toChainCfgs)
+	swapCfgs.Store("USDC", fromChainCfgs)
+	tokens.SetSwapConfigs(swapCfgs)
+	defer tokens.SetSwapConfigs(new(sync.Map))
+
+	_, err := verifySwapTx(b, "0x01", "56", "USDC", &tokens.VerifyArgs{})
+	if !errors.Is(err, tokens.ErrTxNotStable) || len(b.verifyCalls) != 2 || b.verifyCalls[1] {
+		t.Errorf("verify big value swap tx got err %v, verify calls %v", err, b.verifyCalls)
+	}
+}
@@ -0,0 +1,255 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+)
+
+// WaitCondition the condition a deferred swap is waiting for
+type WaitCondition uint8
+
+// wait conditions of deferred swaps
+const (
+	WaitChainUnpaused WaitCondition = iota
+	WaitDestLiquidity
+	WaitManualApproval
+	WaitOracleAttestation
+	WaitTimeLock
+	WaitExternalApproval
+	WaitSendDelay
+	WaitInFlightCap
+)
+
+func (c WaitCondition) String() string {
+	switch c {
+	case WaitChainUnpaused:
+		return "WaitChainUnpaused"
+	case WaitDestLiquidity:
+		return "WaitDestLiquidity"
+	case WaitManualApproval:
+		return "WaitManualApproval"
+	case WaitOracleAttestation:
+		return "WaitOracleAttestation"
+	case WaitTimeLock:
+		return "WaitTimeLock"
+	case WaitExternalApproval:
+		return "WaitExternalApproval"
+	case WaitSendDelay:
+		return "WaitSendDelay"
+	case WaitInFlightCap:
+		return "WaitInFlightCap"
+	default:
+		return "WaitUnknownCondition"
+	}
+}
+
+// WaitConditionChecker returns true if the wait condition of swap is satisfied
+type WaitConditionChecker func(swap *DeferredSwap) bool
+
+// DeferredSwap swap waiting for external condition
+type DeferredSwap struct {
+	Swap      *mongodb.MgoSwap `json:"swap"`
+	Condition WaitCondition    `json:"condition"`
+	Since     int64            `json:"since"`
+
+	UnlockTime int64 `json:"unlockTime,omitempty"` // only for time locked, held and send delayed swaps
+}
+
+// deferred swaps are kept in memory only, after restart they are deferred again
+// by the swap job which finds the not swapped swaps in database. the state the
+// conditions depend on (approvals, attestations) is persisted in database.
+var (
+	deferredSwaps     = make(map[string]*DeferredSwap) // key is swap key
+	deferredSwapsLock sync.RWMutex
+
+	waitConditionCheckers = map[WaitCondition]WaitConditionChecker{
+		WaitChainUnpaused:     checkChainUnpaused,
+		WaitDestLiquidity:     checkDestLiquidityRetryTime,
+		WaitManualApproval:    checkManualApproved,
+		WaitOracleAttestation: checkAttestationReady,
+		WaitTimeLock:          checkTimeLockExpired,
+		WaitExternalApproval:  checkTimeLockExpired,
+		WaitSendDelay:         checkTimeLockExpired,
+		WaitInFlightCap:       checkInFlightCapReleased,
+	}
+
+	destLiquidityRetryInterval = int64(300) // seconds
+
+	errSwapDeferred         = errors.New("swap is deferred")
+	errSwapNotWaitApproval  = errors.New("swap does not wait for manual approval")
+	errSwapAlreadyProcessed = errors.New("swap is already processed")
+)
+
+// StartDeferredJob deferred swaps job
+func StartDeferredJob() {
+	logWorker("deferred", "start deferred swaps job")
+	mongodb.MgoWaitGroup.Add(1)
+	go startDeferredDispatcher()
+}
+
+// ApproveDeferredSwap approve swap waiting for manual approval,
+// the approval is persisted so that it survives restart of swap server.
+func ApproveDeferredSwap(actor, fromChainID, txid string, logIndex int) error {
+	swap, err := mongodb.FindRouterSwap(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	if !params.IsManualApprovalRequired(swap.ToChainID) {
+		return errSwapNotWaitApproval
+	}
+	if swap.Status != mongodb.TxNotSwapped {
+		return fmt.Errorf("%w, status is %v", errSwapAlreadyProcessed, swap.Status.String())
+	}
+	err = mongodb.AddSwapApproval(fromChainID, txid, logIndex, actor)
+	if err != nil {
+		return err
+	}
+	logWorker("deferred", "approve deferred swap", "key", swap.Key, "actor", actor)
+	return nil
+}
+
+func deferSwap(swap *mongodb.MgoSwap, cond WaitCondition) {
+	deferSwapUntil(swap, cond, 0)
+}
+
+func deferSwapUntil(swap *mongodb.MgoSwap, cond WaitCondition, unlockTime int64) {
+	deferredSwapsLock.Lock()
+	defer deferredSwapsLock.Unlock()
+	if _, exist := deferredSwaps[swap.Key]; exist {
+		return
+	}
+	deferredSwaps[swap.Key] = &DeferredSwap{
+		Swap:       swap,
+		Condition:  cond,
+		Since:      now(),
+		UnlockTime: unlockTime,
+	}
+	logWorker("deferred", "defer swap", "key", swap.Key, "condition", cond, "unlockTime", unlockTime)
+}
+
+func isSwapDeferred(key string) bool {
+	deferredSwapsLock.RLock()
+	defer deferredSwapsLock.RUnlock()
+	_, exist := deferredSwaps[key]
+	return exist
+}
+
+func undeferSwap(key string) {
+	deferredSwapsLock.Lock()
+	defer deferredSwapsLock.Unlock()
+	delete(deferredSwaps, key)
+}
+
+// collectReadyDeferredSwaps evaluate every condition at most once per chain
+// in each round, and returns the swaps whose condition are satisfied.
+// the checkers may query database or attestation server, so they are
+// evaluated on a snapshot of deferred swaps without holding the lock.
+func collectReadyDeferredSwaps() (ready []*DeferredSwap) {
+	deferredSwapsLock.RLock()
+	snapshot := make([]*DeferredSwap, 0, len(deferredSwaps))
+	for _, ds := range deferredSwaps {
+		snapshot = append(snapshot, ds)
+	}
+	deferredSwapsLock.RUnlock()
+
+	expiredTime := getSepTimeInFind(maxDoSwapLifetime)
+	pausedResults := make(map[string]bool)
+	for _, ds := range snapshot {
+		if ds.Since < expiredTime && ds.Condition != WaitTimeLock {
+			ready = append(ready, ds) // let swap job decide what to do
+			continue
+		}
+		if ds.Condition == WaitChainUnpaused {
+			pairKey := ds.Swap.FromChainID + ":" + ds.Swap.ToChainID
+			satisfied, exist := pausedResults[pairKey]
+			if !exist {
+				satisfied = checkChainUnpaused(ds)
+				pausedResults[pairKey] = satisfied
+			}
+			if satisfied {
+				ready = append(ready, ds)
+			}
+			continue
+		}
+		checker := waitConditionCheckers[ds.Condition]
+		if checker != nil && checker(ds) {
+			ready = append(ready, ds)
+		}
+	}
+	return ready
+}
+
+func startDeferredDispatcher() {
+	defer mongodb.MgoWaitGroup.Done()
+	for {
+		ready := collectReadyDeferredSwaps()
+		if len(ready) > 0 {
+			logWorker("deferred", "find ready deferred swaps", "count", len(ready))
+		}
+		for _, ds := range ready {
+			if utils.IsCleanuping() {
+				logWorker("deferred", "stop deferred swaps job")
+				return
+			}
+			swap := ds.Swap
+			undeferSwap(swap.Key)
+			err := processRouterSwap(swap)
+			ctx := []interface{}{"fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "condition", ds.Condition, "waited", now() - ds.Since}
+			switch {
+			case err == nil:
+				logWorker("deferred", "process deferred swap success", ctx...)
+			case errors.Is(err, errAlreadySwapped),
+				errors.Is(err, errSwapDeferred),
+				errors.Is(err, errChainIsPaused):
+				ctx = append(ctx, "err", err)
+				logWorkerTrace("deferred", "process deferred swap error", ctx...)
+			default:
+				logWorkerError("deferred", "process deferred swap error", err, ctx...)
+			}
+		}
+		if utils.IsCleanuping() {
+			logWorker("deferred", "stop deferred swaps job")
+			return
+		}
+		restInJob(restIntervalInDeferredJob)
+	}
+}
+
+func checkChainUnpaused(ds *DeferredSwap) bool {
+	return !router.IsChainIDPaused(ds.Swap.FromChainID) &&
+		!router.IsChainIDPaused(ds.Swap.ToChainID)
+}
+
+func checkDestLiquidityRetryTime(ds *DeferredSwap) bool {
+	return ds.Since+destLiquidityRetryInterval <= now()
+}
+
+// checkManualApproval defer the swap to chain requiring manual approval until approved
+func checkManualApproval(swap *mongodb.MgoSwap) error {
+	if !params.IsManualApprovalRequired(swap.ToChainID) {
+		return nil
+	}
+	approved, err := mongodb.IsSwapApproved(swap.Key)
+	if err != nil {
+		return err
+	}
+	if !approved {
+		deferSwap(swap, WaitManualApproval)
+		return errSwapDeferred
+	}
+	return nil
+}
+
+func checkManualApproved(ds *DeferredSwap) bool {
+	approved, err := mongodb.IsSwapApproved(ds.Swap.Key)
+	if err != nil {
+		logWorkerError("deferred", "check swap approval failed", err, "key", ds.Swap.Key)
+	}
+	return approved
+}
@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+const (
+	testAttestationMessage = "0x0102030405"
+	testAttestation        = "0xaabbccdd"
+)
+
+func resetDeferredSwaps() {
+	deferredSwapsLock.Lock()
+	deferredSwaps = make(map[string]*DeferredSwap)
+	deferredSwapsLock.Unlock()
+}
+
+func newTestDeferredSwap(key string) *mongodb.MgoSwap {
+	return &mongodb.MgoSwap{Key: key, FromChainID: "1", ToChainID: "56"}
+}
+
+func TestCollectReadyDeferredSwaps(t *testing.T) {
+	resetDeferredSwaps()
+	defer resetDeferredSwaps()
+
+	deferSwapUntil(newTestDeferredSwap("unlocked"), WaitTimeLock, now()-1)
+	deferSwapUntil(newTestDeferredSwap("locked"), WaitTimeLock, now()+3600)
+	deferSwap(newTestDeferredSwap("liquidity"), WaitDestLiquidity)
+	deferSwap(newTestDeferredSwap("unlocked"), WaitDestLiquidity) // already deferred
+
+	ready := collectReadyDeferredSwaps()
+	if len(ready) != 1 || ready[0].Swap.Key != "unlocked" || ready[0].Condition != WaitTimeLock {
+		t.Fatalf("collect ready deferred swaps got %v items, want the unlocked one", len(ready))
+	}
+	if !isSwapDeferred("locked") || !isSwapDeferred("liquidity") {
+		t.Errorf("swaps not ready should be kept deferred")
+	}
+
+	// the dest liquidity is retried after the retry interval
+	deferredSwapsLock.Lock()
+	deferredSwaps["liquidity"].Since -= destLiquidityRetryInterval
+	deferredSwapsLock.Unlock()
+	undeferSwap("unlocked")
+	ready = collectReadyDeferredSwaps()
+	if len(ready) != 1 || ready[0].Swap.Key != "liquidity" {
+		t.Errorf("collect ready deferred swaps after retry interval got %v items, want the liquidity one", len(ready))
+	}
+	if isSwapDeferred("unlocked") {
+		t.Errorf("undeferred swap should be removed")
+	}
+}
+
+func TestIsWaitingForAttestation(t *testing.T) {
+	tests := []struct {
+		info *mongodb.AnyCallSwapInfo
+		want bool
+	}{
+		{nil, false},
+		{&mongodb.AnyCallSwapInfo{Message: "0x", Attestation: "0x"}, false},
+		{&mongodb.AnyCallSwapInfo{Message: testAttestationMessage, Attestation: "0x"}, true},
+		{&mongodb.AnyCallSwapInfo{Message: testAttestationMessage, Attestation: testAttestation}, false},
+	}
+	for i, test := range tests {
+		if got := isWaitingForAttestation(test.info); got != test.want {
+			t.Errorf("test %v: is waiting for attestation got %v, want %v", i, got, test.want)
+		}
+	}
+}
+
+func TestGetCompleteAttestation(t *testing.T) {
+	messageHash := common.Keccak256Hash(common.FromHex(testAttestationMessage)).String()
+	complete := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/attestations/"+messageHash) {
+			t.Errorf("wrong attestation request path %v", r.URL.Path)
+		}
+		if complete {
+			_, _ = w.Write([]byte(`{"attestation":"` + testAttestation + `","status":"complete"}`))
+		} else {
+			_, _ = w.Write([]byte(`{"attestation":"PENDING","status":"pending_confirmations"}`))
+		}
+	}))
+	defer server.Close()
+
+	err := params.SetExtraConfig(&params.ExtraConfig{AttestationServer: server.URL + "/"})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }()
+
+	if _, err = getCompleteAttestation(testAttestationMessage); !errors.Is(err, errAttestationNotComplete) {
+		t.Fatalf("get pending attestation got error %v", err)
+	}
+	complete = true
+	attestation, err := getCompleteAttestation(testAttestationMessage)
+	if err != nil {
+		t.Fatalf("get complete attestation failed: %v", err)
+	}
+	if attestation != testAttestation {
+		t.Errorf("get complete attestation got %v, want %v", attestation, testAttestation)
+	}
+}
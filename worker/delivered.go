@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// recordDeliveredAmount record the amount actually delivered to the receiver
+// by the stable swap tx if it is less than the swap value, fee-on-transfer (and
+// rebasing) underlying tokens deliver less than the amount sent by the router.
+func recordDeliveredAmount(resBridge tokens.IBridge, swap *mongodb.MgoSwapResult) {
+	if swap.SwapType != uint32(tokens.ERC20SwapType) || swap.ERC20SwapInfo == nil {
+		return
+	}
+	getter, ok := resBridge.(tokens.DeliveredAmountGetter)
+	if !ok {
+		return
+	}
+	tokenID := swap.ERC20SwapInfo.TokenID
+	if cfg := params.GetClaimSwapConfig(swap.ToChainID); cfg != nil && cfg.IsClaimToken(tokenID) {
+		return // delivered to escrow
+	}
+	swapValue, err := common.GetBigIntFromStr(swap.SwapValue)
+	if err != nil || swapValue.Sign() <= 0 {
+		return
+	}
+	tokenAddr := router.GetCachedMultichainToken(tokenID, swap.ToChainID)
+	tokenCfg := resBridge.GetTokenConfig(tokenAddr)
+	if tokenCfg == nil {
+		return
+	}
+	ctx := []interface{}{"fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "swaptx", swap.SwapTx, "tokenID", tokenID, "receiver", swap.Bind}
+
+	delivered, err := getter.GetDeliveredAmount(swap.SwapTx, swap.Bind, tokenAddr, tokenCfg.GetUnderlying())
+	if err != nil {
+		logWorkerError("delivered", "get delivered amount failed", err, ctx...)
+		return
+	}
+	// delivered in native or by other tokens, which is not counted
+	if delivered.Sign() <= 0 || delivered.Cmp(swapValue) >= 0 {
+		return
+	}
+	logWorkerWarn("delivered", "swap delivered less than swap value", append(ctx, "swapValue", swapValue, "delivered", delivered, "rebasingMode", params.GetRebasingTokenMode(tokenID))...)
+	recordSwapEvent(swap.FromChainID, swap.TxID, swap.LogIndex, mongodb.SwapEventDelivered, 0, fmt.Sprintf("%v:%v", delivered, swapValue))
+}
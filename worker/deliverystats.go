@@ -0,0 +1,238 @@
+package worker
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// the delivery stats scale the minimum swap fees (see tokens.RecordDelivery).
+// the swap server records the swap results turning stable or failed and
+// persists the stats in the database. the oracles have no swap results, they
+// track the swap txs they accepted to sign (the accept records of eth like chains)
+// until one of them is finalized on chain, so that the delivery fee percent pinned
+// by the swap server is checked against their own view. the tracked deliveries
+// and the stats of oracles are persisted in the accept database.
+const (
+	acceptedDeliveryPrefix = "delivery:"
+	deliveryStatsPrefix    = "deliverystats:"
+
+	acceptedDeliveryTimeout = int64(3 * 24 * 3600) // seconds
+)
+
+var (
+	deliveryTrackStarter sync.Once
+	acceptedDeliveryLock sync.Mutex
+)
+
+// acceptedDelivery swap txs of the swap accepted to sign by the oracle
+type acceptedDelivery struct {
+	TokenID   string   `json:"tokenID"`
+	ToChainID string   `json:"toChainID"`
+	SwapTxs   []string `json:"swapTxs"`
+	Timestamp int64    `json:"timestamp"` // of the first accepted swap tx
+}
+
+// loadServerDeliveryStats restore delivery stats of the swap server
+func loadServerDeliveryStats() {
+	list, err := mongodb.FindAllDeliveryStats()
+	if err != nil {
+		logWorkerError("deliverystats", "load delivery stats failed", err)
+		return
+	}
+	for _, ms := range list {
+		tokens.LoadDeliveryStats(&tokens.DeliveryStats{
+			TokenID:     ms.TokenID,
+			ToChainID:   ms.ToChainID,
+			Samples:     ms.Samples,
+			AvgFeePaid:  ms.AvgFeePaid,
+			FailureRate: ms.FailureRate,
+			ReplaceRate: ms.ReplaceRate,
+		})
+	}
+	logWorker("deliverystats", "load delivery stats success", "count", len(list))
+}
+
+func saveServerDeliveryStats(stats *tokens.DeliveryStats) {
+	if stats == nil {
+		return
+	}
+	err := mongodb.UpdateDeliveryStats(&mongodb.MgoDeliveryStats{
+		TokenID:     stats.TokenID,
+		ToChainID:   stats.ToChainID,
+		Samples:     stats.Samples,
+		AvgFeePaid:  stats.AvgFeePaid,
+		FailureRate: stats.FailureRate,
+		ReplaceRate: stats.ReplaceRate,
+	})
+	if err != nil {
+		logWorkerError("deliverystats", "save delivery stats failed", err, "tokenID", stats.TokenID, "toChainID", stats.ToChainID)
+	}
+}
+
+// loadOracleDeliveryStats restore delivery stats of the oracle
+func loadOracleDeliveryStats() {
+	if lvldbHandle == nil {
+		return
+	}
+	count := 0
+	iter := lvldbHandle.NewIterator([]byte(deliveryStatsPrefix), nil)
+	for iter.Next() {
+		var stats tokens.DeliveryStats
+		if err := json.Unmarshal(iter.Value(), &stats); err != nil {
+			logWorkerError("deliverystats", "unmarshal delivery stats failed", err, "key", string(iter.Key()))
+			continue
+		}
+		tokens.LoadDeliveryStats(&stats)
+		count++
+	}
+	iter.Release()
+	logWorker("deliverystats", "load delivery stats success", "count", count)
+}
+
+func saveOracleDeliveryStats(stats *tokens.DeliveryStats) {
+	if stats == nil || lvldbHandle == nil {
+		return
+	}
+	data, err := json.Marshal(stats)
+	if err == nil {
+		key := deliveryStatsPrefix + mongodb.GetDeliveryStatsKey(stats.TokenID, stats.ToChainID)
+		err = lvldbHandle.Put([]byte(key), data)
+	}
+	if err != nil {
+		logWorkerError("deliverystats", "save delivery stats failed", err, "tokenID", stats.TokenID, "toChainID", stats.ToChainID)
+	}
+}
+
+// trackAcceptedDelivery add the accepted swap tx to the tracked delivery of the swap,
+// the swap txs of the same swap other than the first are replaces.
+func trackAcceptedDelivery(args *tokens.BuildTxArgs, swapTx string) {
+	toChainID := args.ToChainID.String()
+	if lvldbHandle == nil || params.GetDeliveryFeeConfig(toChainID) == nil {
+		return
+	}
+	acceptedDeliveryLock.Lock()
+	defer acceptedDeliveryLock.Unlock()
+
+	key := []byte(acceptedDeliveryPrefix + getSwapKeyPrefix(args))
+	delivery := &acceptedDelivery{
+		TokenID:   args.GetTokenID(),
+		ToChainID: toChainID,
+		Timestamp: now(),
+	}
+	if data, err := lvldbHandle.Get(key); err == nil {
+		if err = json.Unmarshal(data, delivery); err != nil {
+			logWorkerError("deliverystats", "unmarshal accepted delivery failed", err, "key", string(key))
+		}
+	}
+	if !delivery.addSwapTx(swapTx) {
+		return
+	}
+	data, err := json.Marshal(delivery)
+	if err == nil {
+		err = lvldbHandle.Put(key, data)
+	}
+	if err != nil {
+		logWorkerError("deliverystats", "track accepted delivery failed", err, "key", string(key), "swaptx", swapTx)
+	}
+}
+
+func (d *acceptedDelivery) addSwapTx(swapTx string) bool {
+	for _, tx := range d.SwapTxs {
+		if tx == swapTx {
+			return false
+		}
+	}
+	d.SwapTxs = append(d.SwapTxs, swapTx)
+	return true
+}
+
+// checkAcceptedDelivery record the delivery if one of the swap txs is finalized on chain.
+// returns whether the tracking is done (recorded or timed out), and the updated stats.
+func checkAcceptedDelivery(d *acceptedDelivery, getTxStatus func(txHash string) (*tokens.TxStatus, error), nowTime int64) (done bool, stats *tokens.DeliveryStats) {
+	for _, swapTx := range d.SwapTxs {
+		txStatus, err := getTxStatus(swapTx)
+		if err != nil || !txStatus.IsSwapTxOnChain() {
+			continue
+		}
+		if !txStatus.IsFinalized() {
+			return false, nil
+		}
+		feePaid, _ := new(big.Int).SetString(txStatus.FeePaid, 10)
+		stats = tokens.RecordDelivery(d.TokenID, d.ToChainID, feePaid, len(d.SwapTxs)-1, txStatus.Failed)
+		return true, stats
+	}
+	return d.Timestamp+acceptedDeliveryTimeout < nowTime, nil
+}
+
+// StartDeliveryTrackJob track the deliveries accepted by the oracle,
+// it is started by the accept job after the accept database is opened.
+func StartDeliveryTrackJob() {
+	if lvldbHandle == nil {
+		return
+	}
+	deliveryTrackStarter.Do(func() {
+		logWorker("deliverystats", "start delivery track job")
+		loadOracleDeliveryStats()
+		go runDeliveryTrackJob()
+	})
+}
+
+func runDeliveryTrackJob() {
+	for {
+		if utils.IsCleanuping() {
+			return
+		}
+		trackAcceptedDeliveries()
+		restInJob(restIntervalInDeliveryTrackJob)
+	}
+}
+
+func trackAcceptedDeliveries() {
+	type trackedDelivery struct {
+		key      []byte
+		delivery *acceptedDelivery
+	}
+	var tracked []*trackedDelivery
+	acceptedDeliveryLock.Lock()
+	iter := lvldbHandle.NewIterator([]byte(acceptedDeliveryPrefix), nil)
+	for iter.Next() {
+		delivery := &acceptedDelivery{}
+		if err := json.Unmarshal(iter.Value(), delivery); err != nil {
+			logWorkerError("deliverystats", "unmarshal accepted delivery failed", err, "key", string(iter.Key()))
+			continue
+		}
+		key := append([]byte{}, iter.Key()...)
+		tracked = append(tracked, &trackedDelivery{key: key, delivery: delivery})
+	}
+	iter.Release()
+	acceptedDeliveryLock.Unlock()
+
+	nowTime := now()
+	for _, t := range tracked {
+		if utils.IsCleanuping() {
+			return
+		}
+		bridge := router.GetBridgeByChainID(t.delivery.ToChainID)
+		if bridge == nil {
+			continue
+		}
+		done, stats := checkAcceptedDelivery(t.delivery, bridge.GetTransactionStatus, nowTime)
+		if !done {
+			continue
+		}
+		saveOracleDeliveryStats(stats)
+		acceptedDeliveryLock.Lock()
+		err := lvldbHandle.Delete(t.key)
+		acceptedDeliveryLock.Unlock()
+		if err != nil {
+			logWorkerError("deliverystats", "remove accepted delivery failed", err, "key", string(t.key))
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestCheckAcceptedDelivery(t *testing.T) {
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			"56": {DeliveryFee: &params.DeliveryFeeConfig{BaseCost: "1000"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+
+	delivery := &acceptedDelivery{TokenID: "deliveryToken", ToChainID: "56", Timestamp: 1000}
+	if !delivery.addSwapTx("0x01") || !delivery.addSwapTx("0x02") || delivery.addSwapTx("0x01") {
+		t.Fatalf("add swap txs got %v, want [0x01 0x02]", delivery.SwapTxs)
+	}
+
+	statuses := map[string]*tokens.TxStatus{}
+	getTxStatus := func(txHash string) (*tokens.TxStatus, error) {
+		if status, exist := statuses[txHash]; exist {
+			return status, nil
+		}
+		return nil, errors.New("tx not found")
+	}
+
+	// not on chain, keep tracking until timeout
+	if done, _ := checkAcceptedDelivery(delivery, getTxStatus, 2000); done {
+		t.Errorf("delivery not on chain should be tracked")
+	}
+	if done, stats := checkAcceptedDelivery(delivery, getTxStatus, 1000+acceptedDeliveryTimeout+1); !done || stats != nil {
+		t.Errorf("timed out delivery got (%v, %v), want done without stats", done, stats)
+	}
+
+	// the replacing tx is on chain but not finalized
+	statuses["0x02"] = &tokens.TxStatus{BlockHeight: 100, FeePaid: "3000"}
+	if done, _ := checkAcceptedDelivery(delivery, getTxStatus, 2000); done {
+		t.Errorf("delivery not finalized should be tracked")
+	}
+
+	statuses["0x02"].SetConfirmations(10, 5)
+	done, stats := checkAcceptedDelivery(delivery, getTxStatus, 2000)
+	if !done || stats == nil {
+		t.Fatalf("finalized delivery got (%v, %v), want recorded", done, stats)
+	}
+	if stats.Samples != 1 || stats.AvgFeePaid != 3000 || stats.ReplaceRate != 1 || stats.FailureRate != 0 {
+		t.Errorf("recorded delivery stats got %+v", stats)
+	}
+}
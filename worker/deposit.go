@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// StartDepositSweepJob sweep deposit addresses job
+func StartDepositSweepJob() {
+	logWorker("depositsweep", "start deposit sweep job")
+
+	mongodb.MgoWaitGroup.Add(1)
+	go doDepositSweepJob()
+}
+
+func doDepositSweepJob() {
+	defer mongodb.MgoWaitGroup.Done()
+	for {
+		router.RouterBridges.Range(func(k, v interface{}) bool {
+			sweepDepositAddresses(k.(string), v.(tokens.IBridge))
+			return !utils.IsCleanuping()
+		})
+		if utils.IsCleanuping() {
+			logWorker("depositsweep", "stop deposit sweep job")
+			return
+		}
+		restInJob(restIntervalInDepositSweepJob)
+	}
+}
+
+func sweepDepositAddresses(chainID string, bridge tokens.IBridge) {
+	provider, ok := bridge.(tokens.DepositAddressProvider)
+	if !ok || !provider.IsDepositAddressEnabled() {
+		return
+	}
+	cfg := params.GetDepositFactoryConfig(chainID)
+	if cfg == nil {
+		return
+	}
+	nowTime := now()
+	res, err := mongodb.FindDepositAddressesToSweep(chainID, nowTime-cfg.GetSweepInterval())
+	if err != nil {
+		logWorkerError("depositsweep", "find deposit addresses error", err, "chainID", chainID)
+		return
+	}
+	for _, rec := range res {
+		if utils.IsCleanuping() {
+			return
+		}
+		fundTokens, err := provider.ScanDepositFunds(rec.Address)
+		if err != nil {
+			logWorkerError("depositsweep", "scan deposit funds failed", err, "chainID", chainID, "address", rec.Address)
+			continue
+		}
+		dispatched := 0
+		for _, token := range fundTokens {
+			if sweepDepositFunds(chainID, provider, rec, token) {
+				dispatched++
+			}
+		}
+		_ = mongodb.UpdateDepositAddressSwept(chainID, rec.Address, nowTime, dispatched > 0)
+	}
+}
+
+// sweepDepositFunds queue the sweep tx in the swap task queue
+func sweepDepositFunds(chainID string, provider tokens.DepositAddressProvider, rec *mongodb.MgoDepositAddress, token string) bool {
+	args, err := provider.GetDepositSweepArgs(rec.Address, token, rec.SweepCount+1)
+	if err != nil {
+		logWorkerError("depositsweep", "get deposit sweep args failed", err, "chainID", chainID, "address", rec.Address, "token", token)
+		return false
+	}
+	err = dispatchInternalTx(args, func(txHash string) {
+		logWorker("depositsweep", "sweep deposit address success", "chainID", chainID, "address", rec.Address, "token", token, "txHash", txHash)
+	})
+	if err != nil && !errors.Is(err, errInternalTxInQueue) {
+		logWorkerError("depositsweep", "sweep deposit address failed", err, "chainID", chainID, "address", rec.Address, "token", token)
+		return false
+	}
+	return err == nil
+}
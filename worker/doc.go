@@ -5,6 +5,8 @@
 //		verify registered swaps.
 //	swap
 //		build swaptx, mpc sign the tx, and send the tx to blockchain.
+//	deferred
+//		park swaps waiting for external conditions (chain unpause, liquidity, approval, attestation, in-flight cap) and re-evaluate them.
 //	accept
 //		the `oracle` node do the accept job, agree or disagree the signing after verifying by oralce itself.
 //	stable
@@ -13,5 +15,19 @@
 //		replace swap with the same tx nonce value when the sent swaptx is not packed into block because of lack fee or other reasons.
 //	passbigvalue
 //		pass big value swap if the swap value is too large.
+//	headwatchdog
+//		pause verification of chain when its gateway endpoints stall or diverge.
+//	rateoracle
+//		update conversion rates of cross-asset routes.
+//	allowance
+//		alert on low allowances of the mpc to spenders of underlying tokens and re-approve them.
+//	paramwatch
+//		watch on-chain params (eg. cosmos send_enabled, min gas price) and pause or adjust building of affected tokens.
+//	mpcusage
+//		account mpc sign requests per chain and alert on spikes of signs or failures.
+//	duplicate
+//		detect and record duplicate destination deliveries of stable swaps, and alert with the overpaid value.
+//	recovery
+//		on startup, repair swaps left half-completed by a crash (status not updated, signing or broadcasting interrupted) and re-sign them with the same nonce.
 // Most the above jobs is assigned to the `server` node, the `oracle` node mainly do the `accept` job.
 package worker
@@ -0,0 +1,187 @@
+package worker
+
+import (
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// DryRunArgs args of dry run of swaps in source tx
+type DryRunArgs struct {
+	ChainID  string
+	TxID     string
+	LogIndex int  // 0 means all swaps in the tx
+	Build    bool // also build the swap txs
+	From     string
+	Nonce    uint64
+}
+
+// DryRunResult intermediate structures of dry run of swap
+type DryRunResult struct {
+	FromChainID string              `json:"fromChainID"`
+	TxID        string              `json:"txid"`
+	LogIndex    int                 `json:"logIndex"`
+	SwapInfo    *tokens.SwapTxInfo  `json:"swapInfo,omitempty"` // decoded swap event
+	Amounts     *DryRunAmounts      `json:"amounts,omitempty"`
+	VerifyError string              `json:"verifyError,omitempty"`
+	BuildArgs   *tokens.BuildTxArgs `json:"buildArgs,omitempty"` // calldata is in input
+	RawTx       interface{}         `json:"rawTx,omitempty"`
+	BuildError  string              `json:"buildError,omitempty"`
+}
+
+// DryRunAmounts computed amounts of erc20 swap
+type DryRunAmounts struct {
+	FromDecimals       uint8    `json:"fromDecimals"`
+	ToDecimals         uint8    `json:"toDecimals"`
+	Value              *big.Int `json:"value"`
+	ReceiveValue       *big.Int `json:"receiveValue"` // in to decimals
+	SwapFee            *big.Int `json:"swapFee"`      // in from decimals
+	ConversionRate     *big.Int `json:"conversionRate,omitempty"`
+	DeliveryFeePercent uint64   `json:"deliveryFeePercent,omitempty"`
+	BigValueThreshold  *big.Int `json:"bigValueThreshold"`
+	IsBigValue         bool     `json:"isBigValue"`
+}
+
+// DryRunSwaps verify (and build) swaps of source tx without database, mpc signing and sending.
+// the tx is verified without waiting for confirmations (which are shown in the tx status),
+// and the swap txs are built with the given sender and nonce.
+func DryRunSwaps(args *DryRunArgs) (*tokens.TxStatus, []*DryRunResult, error) {
+	srcBridge := router.GetBridgeByChainID(args.ChainID)
+	if srcBridge == nil {
+		return nil, nil, tokens.ErrNoBridgeForChainID
+	}
+	txStatus, err := srcBridge.GetTransactionStatus(args.TxID)
+	if err != nil {
+		return nil, nil, err
+	}
+	registerArgs := &tokens.RegisterArgs{
+		SwapType: tokens.GetRouterSwapType(),
+		LogIndex: args.LogIndex,
+	}
+	swapInfos, errs := srcBridge.RegisterSwap(args.TxID, registerArgs)
+	results := make([]*DryRunResult, 0, len(swapInfos))
+	for i, swapInfo := range swapInfos {
+		result := &DryRunResult{
+			FromChainID: args.ChainID,
+			TxID:        args.TxID,
+			LogIndex:    swapInfo.LogIndex,
+			SwapInfo:    swapInfo,
+		}
+		results = append(results, result)
+		if errs[i] != nil {
+			result.VerifyError = errs[i].Error()
+			if swapInfo.ToChainID == nil {
+				continue // not decoded
+			}
+		}
+		result.Amounts, err = dryRunAmounts(swapInfo)
+		if err != nil && result.VerifyError == "" {
+			result.VerifyError = err.Error()
+		}
+		if args.Build && result.VerifyError == "" {
+			dryRunBuild(args, result)
+		}
+	}
+	return txStatus, results, nil
+}
+
+func dryRunAmounts(swapInfo *tokens.SwapTxInfo) (*DryRunAmounts, error) {
+	if swapInfo.SwapType != tokens.ERC20SwapType || swapInfo.ERC20SwapInfo == nil {
+		return nil, nil
+	}
+	fromChainID, toChainID := swapInfo.FromChainID.String(), swapInfo.ToChainID.String()
+	tokenID := swapInfo.ERC20SwapInfo.TokenID
+	srcBridge := router.GetBridgeByChainID(fromChainID)
+	dstBridge := router.GetBridgeByChainID(toChainID)
+	if srcBridge == nil || dstBridge == nil {
+		return nil, tokens.ErrNoBridgeForChainID
+	}
+	fromTokenCfg := srcBridge.GetTokenConfig(swapInfo.ERC20SwapInfo.Token)
+	toTokenCfg := dstBridge.GetTokenConfig(router.GetCachedMultichainToken(tokenID, toChainID))
+	if fromTokenCfg == nil || toTokenCfg == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	rate, err := router.GetRouteRate(tokenID, fromChainID, toChainID)
+	if err != nil {
+		return nil, err
+	}
+	args := &tokens.BuildTxArgs{SwapArgs: tokens.SwapArgs{
+		ConversionRate:     rate,
+		DeliveryFeePercent: tokens.GetDeliveryFeePercent(tokenID, toChainID),
+	}}
+	receiveValue := args.CalcSwapValue(tokenID, fromChainID, toChainID, swapInfo.Value,
+		fromTokenCfg.Decimals, toTokenCfg.Decimals, swapInfo.From, swapInfo.TxTo)
+	swapFee := new(big.Int).Sub(swapInfo.Value,
+		tokens.ConvertTokenValue(receiveValue, toTokenCfg.Decimals, fromTokenCfg.Decimals))
+	return &DryRunAmounts{
+		FromDecimals:       fromTokenCfg.Decimals,
+		ToDecimals:         toTokenCfg.Decimals,
+		Value:              swapInfo.Value,
+		ReceiveValue:       args.ApplyConversionRate(receiveValue),
+		SwapFee:            swapFee,
+		ConversionRate:     rate,
+		DeliveryFeePercent: args.DeliveryFeePercent,
+		BigValueThreshold:  tokens.GetBigValueThreshold(tokenID, fromChainID, toChainID, fromTokenCfg.Decimals),
+		IsBigValue:         router.IsBigValueSwap(swapInfo),
+	}, nil
+}
+
+// dryRunBuild build swap tx with the given sender and nonce, the built tx is never signed
+func dryRunBuild(args *DryRunArgs, result *DryRunResult) {
+	swapInfo := result.SwapInfo
+	toChainID := swapInfo.ToChainID.String()
+	dstBridge := router.GetBridgeByChainID(toChainID)
+	if dstBridge == nil {
+		result.BuildError = tokens.ErrNoBridgeForChainID.Error()
+		return
+	}
+	from := args.From
+	if from == "" {
+		routerMPC, err := router.GetRouterMPC(swapInfo.GetTokenID(), toChainID)
+		if err != nil {
+			result.BuildError = err.Error()
+			return
+		}
+		from = routerMPC
+	}
+	buildArgs := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			Identifier:  params.GetIdentifier(),
+			Salt:        params.GetDeploymentSalt(),
+			SwapID:      swapInfo.Hash,
+			SwapType:    swapInfo.SwapType,
+			Bind:        swapInfo.Bind,
+			LogIndex:    swapInfo.LogIndex,
+			FromChainID: swapInfo.FromChainID,
+			ToChainID:   swapInfo.ToChainID,
+			TxHeight:    swapInfo.Height,
+			SwapInfo:    swapInfo.SwapInfo,
+		},
+		From:        from,
+		OriginFrom:  swapInfo.From,
+		OriginTxTo:  swapInfo.TxTo,
+		OriginValue: swapInfo.Value,
+		Extra:       &tokens.AllExtras{},
+	}
+	if result.Amounts != nil {
+		buildArgs.ConversionRate = result.Amounts.ConversionRate
+		buildArgs.DeliveryFeePercent = result.Amounts.DeliveryFeePercent
+	}
+	// the nonce is given rather than allocated in database
+	nonce := args.Nonce
+	buildArgs.Extra.Sequence = &nonce
+
+	rawTx, err := dstBridge.BuildRawTransaction(buildArgs)
+	result.BuildArgs = buildArgs
+	if err != nil {
+		result.BuildError = err.Error()
+		return
+	}
+	if rawTx == nil {
+		result.BuildError = "build returns nil tx"
+		return
+	}
+	result.RawTx = rawTx
+}
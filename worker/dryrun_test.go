@@ -0,0 +1,127 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testDryRunSrcBridge struct {
+	tokens.IBridge
+	statusErr error
+	swapInfos []*tokens.SwapTxInfo
+	errs      []error
+}
+
+func (b *testDryRunSrcBridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	if b.statusErr != nil {
+		return nil, b.statusErr
+	}
+	return &tokens.TxStatus{BlockHeight: 100, Confirmations: 1}, nil
+}
+
+func (b *testDryRunSrcBridge) RegisterSwap(txHash string, args *tokens.RegisterArgs) ([]*tokens.SwapTxInfo, []error) {
+	return b.swapInfos, b.errs
+}
+
+func (b *testDryRunSrcBridge) GetTokenConfig(token string) *tokens.TokenConfig {
+	return nil
+}
+
+type testDryRunDstBridge struct {
+	tokens.IBridge
+	built []*tokens.BuildTxArgs
+}
+
+func (b *testDryRunDstBridge) GetTokenConfig(token string) *tokens.TokenConfig {
+	return nil
+}
+
+func (b *testDryRunDstBridge) BuildRawTransaction(args *tokens.BuildTxArgs) (interface{}, error) {
+	b.built = append(b.built, args)
+	return "rawtx", nil
+}
+
+func newTestDryRunSwapInfo(logIndex int, swapType tokens.SwapType, toChainID int64) *tokens.SwapTxInfo {
+	swapInfo := &tokens.SwapTxInfo{
+		SwapType:    swapType,
+		Hash:        "0x01",
+		LogIndex:    logIndex,
+		Value:       big.NewInt(1000),
+		FromChainID: big.NewInt(1),
+	}
+	if toChainID != 0 {
+		swapInfo.ToChainID = big.NewInt(toChainID)
+	}
+	if swapType == tokens.ERC20SwapType {
+		swapInfo.ERC20SwapInfo = &tokens.ERC20SwapInfo{TokenID: "USDC", Token: "0x02"}
+	}
+	return swapInfo
+}
+
+func TestDryRunSwaps(t *testing.T) {
+	src := &testDryRunSrcBridge{
+		swapInfos: []*tokens.SwapTxInfo{
+			newTestDryRunSwapInfo(1, tokens.AnyCallSwapType, 0),
+			newTestDryRunSwapInfo(2, tokens.AnyCallSwapType, 56),
+			newTestDryRunSwapInfo(3, tokens.AnyCallSwapType, 56),
+			newTestDryRunSwapInfo(4, tokens.ERC20SwapType, 56),
+			newTestDryRunSwapInfo(5, tokens.AnyCallSwapType, 137),
+		},
+		errs: []error{tokens.ErrSwapTypeNotSupported, nil, tokens.ErrTxWithWrongValue, nil, nil},
+	}
+	dst := &testDryRunDstBridge{}
+	router.SetBridge("1", src)
+	router.SetBridge("56", dst)
+	defer router.SetBridge("1", nil)
+	defer router.SetBridge("56", nil)
+
+	args := &DryRunArgs{ChainID: "1", TxID: "0x01", Build: true, From: "0x03", Nonce: 7}
+	txStatus, results, err := DryRunSwaps(args)
+	if err != nil || txStatus.BlockHeight != 100 || len(results) != 5 {
+		t.Fatalf("dry run swaps got (%+v, %v results, %v)", txStatus, len(results), err)
+	}
+	// swaps failed verification are not built
+	if results[0].VerifyError == "" || results[0].BuildArgs != nil {
+		t.Errorf("dry run of not decoded swap got %+v", results[0])
+	}
+	if results[2].VerifyError == "" || results[2].BuildArgs != nil {
+		t.Errorf("dry run of wrong value swap got %+v", results[2])
+	}
+	// amounts of erc20 swaps are computed with token configs
+	if results[3].VerifyError != tokens.ErrMissTokenConfig.Error() || results[3].BuildArgs != nil {
+		t.Errorf("dry run of erc20 swap without token config got %+v", results[3])
+	}
+	// the swap is built with the given sender and nonce
+	built := results[1]
+	if built.VerifyError != "" || built.BuildError != "" || built.RawTx != "rawtx" || len(dst.built) != 1 {
+		t.Errorf("dry run of verified swap got %+v", built)
+	} else if built.BuildArgs.From != "0x03" || *built.BuildArgs.Extra.Sequence != 7 || built.BuildArgs.LogIndex != 2 {
+		t.Errorf("dry run build args got from %v, nonce %v, log index %v", built.BuildArgs.From, *built.BuildArgs.Extra.Sequence, built.BuildArgs.LogIndex)
+	}
+	if results[4].BuildError != tokens.ErrNoBridgeForChainID.Error() {
+		t.Errorf("dry run of swap to chain without bridge got build error %v", results[4].BuildError)
+	}
+
+	// swaps are not built without the build flag
+	dst.built = nil
+	args.Build = false
+	if _, results, _ = DryRunSwaps(args); results[1].BuildArgs != nil || len(dst.built) != 0 {
+		t.Errorf("dry run without build flag got build args %+v", results[1].BuildArgs)
+	}
+}
+
+func TestDryRunSwapsError(t *testing.T) {
+	if _, _, err := DryRunSwaps(&DryRunArgs{ChainID: "1", TxID: "0x01"}); !errors.Is(err, tokens.ErrNoBridgeForChainID) {
+		t.Errorf("dry run swaps without bridge got error %v, want %v", err, tokens.ErrNoBridgeForChainID)
+	}
+
+	router.SetBridge("1", &testDryRunSrcBridge{statusErr: tokens.ErrTxNotFound})
+	defer router.SetBridge("1", nil)
+	if _, _, err := DryRunSwaps(&DryRunArgs{ChainID: "1", TxID: "0x01"}); !errors.Is(err, tokens.ErrTxNotFound) {
+		t.Errorf("dry run swaps of not found tx got error %v, want %v", err, tokens.ErrTxNotFound)
+	}
+}
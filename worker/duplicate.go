@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// a swap may have more than one swap txs (replaced or reswapped), normally
+// only one of them can land on chain. but if the replacing does not reuse the
+// nonce, or the reswap races with the original tx, the receiver is paid twice.
+// the duplicates are checked when the swap is stable and rechecked periodically,
+// and recorded with the overpaid value for tracking of the recovery.
+var (
+	duplicateCheckStarter sync.Once
+
+	errDuplicateDelivery = errors.New("duplicate destination delivery")
+)
+
+// StartDuplicateCheckJob recheck duplicate deliveries of stable swaps job
+func StartDuplicateCheckJob() {
+	cfg := params.GetDuplicateCheckConfig()
+	if cfg == nil {
+		return
+	}
+	duplicateCheckStarter.Do(func() {
+		logWorker("duplicate", "start duplicate check job", "interval", cfg.GetInterval(), "scanWindow", cfg.GetScanWindow(), "batchSize", cfg.GetBatchSize())
+		goSupervisedJob("duplicate", nil, func() { runDuplicateCheck(cfg) })
+	})
+}
+
+func runDuplicateCheck(cfg *params.DuplicateCheckConfig) {
+	interval := time.Duration(cfg.GetInterval()) * time.Second
+	for {
+		doDuplicateCheck(cfg)
+		time.Sleep(interval)
+	}
+}
+
+func doDuplicateCheck(cfg *params.DuplicateCheckConfig) {
+	nowTime := now()
+	swaps, err := mongodb.FindStableSwapResultsWithOldSwapTxs(nowTime-cfg.GetScanWindow(), nowTime, cfg.GetBatchSize())
+	if err != nil {
+		logWorkerError("duplicate", "find stable swaps with old swap txs failed", err)
+		return
+	}
+	found := 0
+	for _, swap := range swaps {
+		resBridge := router.GetBridgeByChainID(swap.ToChainID)
+		if resBridge == nil {
+			continue
+		}
+		found += checkDuplicateDeliveries(resBridge, swap)
+	}
+	logWorker("duplicate", "duplicate check finished", "scanned", len(swaps), "found", found)
+}
+
+// checkDuplicateDeliveries check whether the other swap txs of the stable swap
+// are also succeeded on chain, and record the new found duplicates.
+func checkDuplicateDeliveries(resBridge tokens.IBridge, swap *mongodb.MgoSwapResult) (found int) {
+	duplicates := findDuplicateDeliveries(resBridge, swap)
+	for _, md := range duplicates {
+		ctx := []interface{}{"fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex,
+			"swaptx", swap.SwapTx, "duplicateTx", md.DuplicateTx, "height", md.DuplicateHeight, "receiver", swap.Bind, "overpaid", md.OverpaidValue}
+
+		isNew, err := mongodb.AddDuplicateDelivery(md)
+		if err != nil {
+			logWorkerError("duplicate", "add duplicate delivery failed", err, ctx...)
+			continue
+		}
+		if !isNew {
+			continue
+		}
+		logWorkerError("duplicate", "found duplicate destination delivery", errDuplicateDelivery, ctx...)
+		recordSwapEvent(swap.FromChainID, swap.TxID, swap.LogIndex, mongodb.SwapEventDuplicate, 0, fmt.Sprintf("%v:%v", md.DuplicateTx, md.OverpaidValue))
+	}
+	return len(duplicates)
+}
+
+// findDuplicateDeliveries find the other swap txs of the swap which are succeeded and finalized on chain
+func findDuplicateDeliveries(resBridge tokens.IBridge, swap *mongodb.MgoSwapResult) (duplicates []*mongodb.MgoDuplicateDelivery) {
+	for _, oldSwapTx := range swap.OldSwapTxs {
+		if oldSwapTx == "" || oldSwapTx == swap.SwapTx {
+			continue
+		}
+		txStatus, err := resBridge.GetTransactionStatus(oldSwapTx)
+		if err != nil || !txStatus.IsSwapTxOnChainAndSucceed() || !txStatus.IsFinalized() {
+			continue
+		}
+		md := &mongodb.MgoDuplicateDelivery{
+			FromChainID:     swap.FromChainID,
+			TxID:            swap.TxID,
+			LogIndex:        swap.LogIndex,
+			ToChainID:       swap.ToChainID,
+			Bind:            swap.Bind,
+			SwapTx:          swap.SwapTx,
+			DuplicateTx:     oldSwapTx,
+			DuplicateHeight: txStatus.BlockHeight,
+			OverpaidValue:   getOverpaidValue(resBridge, swap, oldSwapTx),
+		}
+		if swap.ERC20SwapInfo != nil {
+			md.TokenID = swap.ERC20SwapInfo.TokenID
+		}
+		duplicates = append(duplicates, md)
+	}
+	return duplicates
+}
+
+// getOverpaidValue get the amount delivered by the duplicate tx if the bridge
+// supports, otherwise the swap value is regarded as overpaid.
+func getOverpaidValue(resBridge tokens.IBridge, swap *mongodb.MgoSwapResult, duplicateTx string) string {
+	getter, ok := resBridge.(tokens.DeliveredAmountGetter)
+	if !ok || swap.SwapType != uint32(tokens.ERC20SwapType) || swap.ERC20SwapInfo == nil {
+		return swap.SwapValue
+	}
+	tokenAddr := router.GetCachedMultichainToken(swap.ERC20SwapInfo.TokenID, swap.ToChainID)
+	tokenCfg := resBridge.GetTokenConfig(tokenAddr)
+	if tokenCfg == nil {
+		return swap.SwapValue
+	}
+	delivered, err := getter.GetDeliveredAmount(duplicateTx, swap.Bind, tokenAddr, tokenCfg.GetUnderlying())
+	if err != nil || delivered.Sign() <= 0 {
+		return swap.SwapValue
+	}
+	return delivered.String()
+}
@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testDeliveryBridge struct {
+	tokens.IBridge
+	txStatuses map[string]*tokens.TxStatus
+	delivered  map[string]*big.Int
+}
+
+func (b *testDeliveryBridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	if status, exist := b.txStatuses[txHash]; exist {
+		return status, nil
+	}
+	return nil, tokens.ErrTxNotFound
+}
+
+func (b *testDeliveryBridge) GetTokenConfig(tokenAddr string) *tokens.TokenConfig {
+	return &tokens.TokenConfig{}
+}
+
+func (b *testDeliveryBridge) GetDeliveredAmount(txHash, receiver string, tokenAddrs ...string) (*big.Int, error) {
+	if amount, exist := b.delivered[txHash]; exist {
+		return amount, nil
+	}
+	return nil, errors.New("no transfer to receiver")
+}
+
+func TestFindDuplicateDeliveries(t *testing.T) {
+	bridge := &testDeliveryBridge{
+		txStatuses: map[string]*tokens.TxStatus{
+			"0x01": {BlockHeight: 100, Finalized: true},
+			"0x02": {BlockHeight: 101, Finalized: true},
+			"0x03": {BlockHeight: 102, Finalized: true, Failed: true},
+			"0x04": {BlockHeight: 103},
+			"0x05": {BlockHeight: 104, Finalized: true},
+		},
+		delivered: map[string]*big.Int{"0x01": big.NewInt(990)},
+	}
+	swap := &mongodb.MgoSwapResult{
+		FromChainID: "1",
+		ToChainID:   "56",
+		TxID:        "0xaa",
+		SwapType:    uint32(tokens.ERC20SwapType),
+		SwapInfo:    mongodb.SwapInfo{ERC20SwapInfo: &mongodb.ERC20SwapInfo{TokenID: "USDC"}},
+		SwapValue:   "1000",
+		SwapTx:      "0x02",
+		// failed, not finalized, not found and the swap tx itself are not duplicates
+		OldSwapTxs: []string{"0x01", "0x02", "0x03", "0x04", "0x05", "0x06", ""},
+	}
+
+	duplicates := findDuplicateDeliveries(bridge, swap)
+	if len(duplicates) != 2 {
+		t.Fatalf("find duplicates got %v, want 2", len(duplicates))
+	}
+	// overpaid value is the delivered amount, or the swap value if unknown
+	for i, want := range []struct {
+		tx       string
+		height   uint64
+		overpaid string
+	}{
+		{"0x01", 100, "990"},
+		{"0x05", 104, "1000"},
+	} {
+		md := duplicates[i]
+		if md.DuplicateTx != want.tx || md.DuplicateHeight != want.height || md.OverpaidValue != want.overpaid || md.SwapTx != "0x02" || md.TokenID != "USDC" {
+			t.Errorf("duplicate %v got %+v, want tx %v height %v overpaid %v", i, md, want.tx, want.height, want.overpaid)
+		}
+	}
+}
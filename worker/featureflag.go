@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+)
+
+// admin overrides of feature flags are persisted in db by the server
+// receiving the admin call, and synced periodically by all the servers
+// sharing the db, so they survive restarts and apply to every server.
+// the oracles have no db, they sync the overrides from the server api.
+var featureFlagSyncStarter sync.Once
+
+// StartFeatureFlagSyncJob sync feature flag overrides job
+func StartFeatureFlagSyncJob(isServer bool) {
+	if !isServer && params.GetRouterOracleConfig() == nil {
+		return
+	}
+	featureFlagSyncStarter.Do(func() {
+		logWorker("featureflag", "start feature flag sync job", "isServer", isServer)
+		// load before the swap jobs start
+		syncFeatureFlags(isServer)
+		goSupervisedJob("featureflag", nil, func() { doFeatureFlagSyncJob(isServer) })
+	})
+}
+
+func doFeatureFlagSyncJob(isServer bool) {
+	for {
+		restInJob(restIntervalInFeatureFlagSyncJob)
+		if utils.IsCleanuping() {
+			logWorker("featureflag", "stop feature flag sync job")
+			return
+		}
+		syncFeatureFlags(isServer)
+	}
+}
+
+func syncFeatureFlags(isServer bool) {
+	var overrides []*params.FeatureFlagOverride
+	var err error
+	if isServer {
+		overrides, err = findFeatureFlagOverrides()
+	} else {
+		overrides, err = queryFeatureFlagOverrides(params.GetRouterOracleConfig().ServerAPIAddress)
+	}
+	if err != nil {
+		logWorkerError("featureflag", "sync feature flags failed", err, "isServer", isServer)
+		return
+	}
+	params.ResetFeatureFlagOverrides(filterFeatureFlagOverrides(overrides))
+}
+
+func findFeatureFlagOverrides() ([]*params.FeatureFlagOverride, error) {
+	flags, err := mongodb.FindFeatureFlags()
+	if err != nil {
+		return nil, err
+	}
+	overrides := make([]*params.FeatureFlagOverride, 0, len(flags))
+	for _, f := range flags {
+		overrides = append(overrides, &params.FeatureFlagOverride{
+			Flag:    f.Flag,
+			ChainID: f.ChainID,
+			Enabled: f.Enabled,
+		})
+	}
+	return overrides, nil
+}
+
+func queryFeatureFlagOverrides(url string) (overrides []*params.FeatureFlagOverride, err error) {
+	err = client.RPCPostWithTimeout(20, &overrides, url, "swap.GetFeatureFlags")
+	return overrides, err
+}
+
+func filterFeatureFlagOverrides(overrides []*params.FeatureFlagOverride) []*params.FeatureFlagOverride {
+	result := make([]*params.FeatureFlagOverride, 0, len(overrides))
+	for _, o := range overrides {
+		if !params.IsValidFeatureFlag(o.Flag) {
+			logWorkerWarn("featureflag", "ignore unknown feature flag", "flag", o.Flag, "chainID", o.ChainID)
+			continue
+		}
+		result = append(result, o)
+	}
+	return result
+}
+
+// isAutoReswapEnabled is timed out swaps to chain reswapped automatically
+func isAutoReswapEnabled(chainID string) bool {
+	return router.IsReswapSupported(chainID) && params.IsFeatureEnabled(params.FeatureAutoReswap, chainID)
+}
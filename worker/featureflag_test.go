@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+func TestQueryFeatureFlagOverrides(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"swap.GetFeatureFlags"`) {
+			t.Errorf("wrong request %s", body)
+		}
+		result := []*params.FeatureFlagOverride{
+			{Flag: params.FeatureParallelSwap, ChainID: "56", Enabled: false},
+			{Flag: "unknownFlag", ChainID: params.FeatureFlagAllChains, Enabled: false},
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	defer server.Close()
+
+	overrides, err := queryFeatureFlagOverrides(server.URL)
+	if err != nil {
+		t.Fatalf("query feature flag overrides failed: %v", err)
+	}
+	if len(overrides) != 2 {
+		t.Fatalf("query feature flag overrides got %v items, want 2", len(overrides))
+	}
+	overrides = filterFeatureFlagOverrides(overrides)
+	if len(overrides) != 1 || overrides[0].Flag != params.FeatureParallelSwap || overrides[0].ChainID != "56" || overrides[0].Enabled {
+		t.Errorf("filter feature flag overrides got %+v", overrides)
+	}
+}
@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// resendOutOfGasSwap rebuild the swap tx with re-estimated gas and resend it
+// in bounded attempts if the sending failed for out of gas (or insufficient fee).
+// returns the error of the last sending.
+func resendOutOfGasSwap(bridge tokens.IBridge, args *tokens.BuildTxArgs, sendErr error) (err error) {
+	reestimator, ok := bridge.(tokens.GasReestimator)
+	if !ok || tokens.ClassifySendTxError(bridge, sendErr) != tokens.SendTxErrOutOfGas {
+		return sendErr
+	}
+
+	fromChainID := args.FromChainID.String()
+	txid := args.SwapID
+	logIndex := args.LogIndex
+	cfg := params.GetGasRetryConfig(args.ToChainID.String())
+
+	err = sendErr
+	for attempt := 1; attempt <= cfg.GetMaxAttempts(); attempt++ {
+		adjustment := cfg.GetAdjustment(attempt)
+		ctx := []interface{}{"fromChainID", fromChainID, "toChainID", args.ToChainID, "txid", txid, "logIndex", logIndex, "swapNonce", args.GetTxNonce(), "attempt", attempt, "adjustment", adjustment}
+
+		if errf := reestimator.ReestimateGas(args, adjustment); errf != nil {
+			logWorkerError("gasretry", "re-estimate gas failed", errf, ctx...)
+			return err
+		}
+		rawTx, errf := bridge.BuildRawTransaction(args)
+		if errf != nil {
+			logWorkerError("gasretry", "rebuild tx failed", errf, ctx...)
+			return err
+		}
+		setSignExpiry(args)
+		signedTx, signedTxHash, errf := mpcSignTransaction(bridge, rawTx, args)
+		if errf != nil {
+			logWorkerError("gasretry", "sign tx failed", errf, ctx...)
+			return err
+		}
+		if errf = mongodb.UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, signedTxHash); errf != nil {
+			return err
+		}
+		recordSwapEvent(fromChainID, txid, logIndex, mongodb.SwapEventGasRetry, 0,
+			fmt.Sprintf("%v:%v:%v:%v", attempt, *args.Extra.Gas, *args.Extra.Fee, signedTxHash))
+		ctx = append(ctx, "txHash", signedTxHash)
+
+		start := time.Now()
+		var sentTxHash string
+		sentTxHash, err = sendSignedTransaction(bridge, signedTx, args)
+		recordSendSwapEvent(fromChainID, txid, logIndex, time.Since(start), signedTxHash, sentTxHash, err)
+		if err == nil {
+			if sentTxHash != "" && sentTxHash != signedTxHash {
+				_ = mongodb.UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, sentTxHash)
+			}
+			logWorker("gasretry", "resend tx with re-estimated gas success", append(ctx, "gas", *args.Extra.Gas, "fee", *args.Extra.Fee)...)
+			return nil
+		}
+		if tokens.ClassifySendTxError(bridge, err) != tokens.SendTxErrOutOfGas {
+			return err
+		}
+		logWorkerWarn("gasretry", "resend tx still out of gas", append(ctx, "err", err)...)
+	}
+	return err
+}
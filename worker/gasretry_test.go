@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var errTestOutOfGas = errors.New("out of gas")
+
+type testGasRetryBridge struct {
+	tokens.IBridge
+	reestimateErr error
+	buildErr      error
+	adjustments   []float64
+	builds        int
+}
+
+func (b *testGasRetryBridge) MapSendTxError(err error) tokens.SendTxErrorClass {
+	if errors.Is(err, errTestOutOfGas) {
+		return tokens.SendTxErrOutOfGas
+	}
+	return tokens.SendTxErrUnknown
+}
+
+func (b *testGasRetryBridge) ReestimateGas(args *tokens.BuildTxArgs, adjustment float64) error {
+	b.adjustments = append(b.adjustments, adjustment)
+	return b.reestimateErr
+}
+
+func (b *testGasRetryBridge) BuildRawTransaction(args *tokens.BuildTxArgs) (interface{}, error) {
+	b.builds++
+	return nil, b.buildErr
+}
+
+func TestResendOutOfGasSwap(t *testing.T) {
+	newArgs := func() *tokens.BuildTxArgs {
+		return &tokens.BuildTxArgs{
+			SwapArgs: tokens.SwapArgs{
+				FromChainID: big.NewInt(1),
+				ToChainID:   big.NewInt(4846305044286571602),
+				SwapID:      "0x01",
+			},
+		}
+	}
+	otherErr := errors.New("wrong sequence")
+
+	// other errors are returned as is
+	b := &testGasRetryBridge{}
+	if err := resendOutOfGasSwap(b, newArgs(), otherErr); err != otherErr || len(b.adjustments) != 0 {
+		t.Errorf("resend swap of other error got %v with adjustments %v, want %v", err, b.adjustments, otherErr)
+	}
+
+	// bridges can not re-estimate gas are not retried
+	type plainBridge struct{ tokens.IBridge }
+	if err := resendOutOfGasSwap(&plainBridge{}, newArgs(), errTestOutOfGas); err != errTestOutOfGas {
+		t.Errorf("resend swap of bridge without re-estimation got %v, want %v", err, errTestOutOfGas)
+	}
+
+	// the sending error is kept if the re-estimation fails
+	b = &testGasRetryBridge{reestimateErr: errors.New("simulate failed")}
+	if err := resendOutOfGasSwap(b, newArgs(), errTestOutOfGas); err != errTestOutOfGas {
+		t.Errorf("resend swap of failed re-estimation got %v, want %v", err, errTestOutOfGas)
+	}
+	if len(b.adjustments) != 1 || b.adjustments[0] != 1.5 || b.builds != 0 {
+		t.Errorf("failed re-estimation got adjustments %v and builds %v, want [1.5] and 0", b.adjustments, b.builds)
+	}
+
+	// and if the rebuilding fails
+	b = &testGasRetryBridge{buildErr: errors.New("build failed")}
+	if err := resendOutOfGasSwap(b, newArgs(), errTestOutOfGas); err != errTestOutOfGas {
+		t.Errorf("resend swap of failed rebuilding got %v, want %v", err, errTestOutOfGas)
+	}
+	if len(b.adjustments) != 1 || b.builds != 1 {
+		t.Errorf("failed rebuilding got adjustments %v and builds %v, want 1 and 1", b.adjustments, b.builds)
+	}
+}
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// recordGasUsed record gas used by the stable swap tx, the destination
+// bridge learns gas limits of later swap txs of the token from it.
+func recordGasUsed(resBridge tokens.IBridge, swap *mongodb.MgoSwapResult, txStatus *tokens.TxStatus) {
+	if swap.ERC20SwapInfo == nil || txStatus == nil || txStatus.Receipt == nil {
+		return
+	}
+	recorder, ok := resBridge.(tokens.GasUsageRecorder)
+	if !ok {
+		return
+	}
+	samples := recorder.RecordGasUsed(swap.ERC20SwapInfo.TokenID, swap.SwapTx, txStatus.Receipt)
+	if samples == nil {
+		return
+	}
+	err := mongodb.UpdateGasSamples(&mongodb.MgoGasSamples{
+		ChainID: samples.ChainID,
+		TokenID: samples.TokenID,
+		Method:  samples.Method,
+		Samples: samples.Samples,
+	})
+	if err != nil {
+		logWorkerError("gasusage", "save gas samples failed", err, "chainID", samples.ChainID, "tokenID", samples.TokenID, "method", samples.Method)
+	}
+}
+
+// loadServerGasSamples restore gas samples of the swap server
+func loadServerGasSamples() {
+	list, err := mongodb.FindAllGasSamples()
+	if err != nil {
+		logWorkerError("gasusage", "load gas samples failed", err)
+		return
+	}
+	for _, ms := range list {
+		recorder, ok := router.GetBridgeByChainID(ms.ChainID).(tokens.GasUsageRecorder)
+		if !ok {
+			continue
+		}
+		recorder.LoadGasSamples(&tokens.GasSamples{
+			ChainID: ms.ChainID,
+			TokenID: ms.TokenID,
+			Method:  ms.Method,
+			Samples: ms.Samples,
+		})
+	}
+	logWorker("gasusage", "load gas samples success", "count", len(list))
+}
+
+// recordDelivery record fee paid, replaces and result of the finalized swap tx,
+// the minimum swap fees of the token to the destination chain track them.
+func recordDelivery(swap *mongodb.MgoSwapResult, txStatus *tokens.TxStatus, failed bool) {
+	feePaid, _ := new(big.Int).SetString(txStatus.FeePaid, 10)
+	stats := tokens.RecordDelivery(swap.GetTokenID(), swap.ToChainID, feePaid, len(swap.OldSwapTxs), failed)
+	saveServerDeliveryStats(stats)
+}
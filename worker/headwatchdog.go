@@ -0,0 +1,176 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	mapset "github.com/deckarep/golang-set"
+)
+
+var (
+	headWatchdogStarter sync.Once
+
+	chainHeadStates     = make(map[string]*chainHeadState) // key is chainID
+	chainHeadStatesLock sync.Mutex
+
+	// chains whose verification is paused by the head watchdog
+	staleHeadChains = mapset.NewSet()
+
+	errChainHeadStale = errors.New("chain head is stale")
+)
+
+type endpointHead struct {
+	height     uint64
+	updateTime int64 // time when the height advanced last
+}
+
+type chainHeadState struct {
+	endpoints      map[string]*endpointHead // key is url
+	verifiedHeight uint64
+}
+
+// StartHeadWatchdogJob chain head lag watchdog job
+func StartHeadWatchdogJob() {
+	headWatchdogStarter.Do(func() {
+		logWorker("headwatchdog", "start chain head watchdog job")
+		go doHeadWatchdogJob()
+	})
+}
+
+func doHeadWatchdogJob() {
+	for {
+		router.RouterBridges.Range(func(k, v interface{}) bool {
+			checkChainHead(k.(string), v.(tokens.IBridge))
+			return !utils.IsCleanuping()
+		})
+		if utils.IsCleanuping() {
+			logWorker("headwatchdog", "stop chain head watchdog job")
+			return
+		}
+		restInJob(restIntervalInHeadWatchdogJob)
+	}
+}
+
+// IsChainHeadStale is verification of chain paused by head watchdog
+func IsChainHeadStale(chainID string) bool {
+	return staleHeadChains.Contains(chainID)
+}
+
+// GetStaleHeadChainIDs get chainIDs whose verification are paused by head watchdog
+func GetStaleHeadChainIDs() []string {
+	chainIDs := make([]string, 0, staleHeadChains.Cardinality())
+	staleHeadChains.Each(func(elem interface{}) bool {
+		chainIDs = append(chainIDs, elem.(string))
+		return false
+	})
+	sort.Strings(chainIDs)
+	return chainIDs
+}
+
+// recordVerifiedHeight record the highest verified tx height of chain,
+// endpoints reporting heads much lower than it are regarded as stalled or forked.
+func recordVerifiedHeight(chainID string, height uint64) {
+	if height == 0 || params.GetHeadWatchdogConfig(chainID) == nil {
+		return
+	}
+	chainHeadStatesLock.Lock()
+	defer chainHeadStatesLock.Unlock()
+	state := getChainHeadState(chainID)
+	if height > state.verifiedHeight {
+		state.verifiedHeight = height
+	}
+}
+
+// getChainHeadState must be called with chainHeadStatesLock held
+func getChainHeadState(chainID string) *chainHeadState {
+	state, exist := chainHeadStates[chainID]
+	if !exist {
+		state = &chainHeadState{endpoints: make(map[string]*endpointHead)}
+		chainHeadStates[chainID] = state
+	}
+	return state
+}
+
+func checkChainHead(chainID string, bridge tokens.IBridge) {
+	cfg := params.GetHeadWatchdogConfig(chainID)
+	if cfg == nil {
+		return
+	}
+	gateway := bridge.GetGatewayConfig()
+	if gateway == nil || len(gateway.AllGatewayURLs) == 0 {
+		return
+	}
+
+	heads := make(map[string]uint64, len(gateway.AllGatewayURLs))
+	for _, url := range gateway.AllGatewayURLs {
+		height, err := bridge.GetLatestBlockNumberOf(url)
+		if err != nil {
+			logWorkerTrace("headwatchdog", "get latest block number failed", "chainID", chainID, "url", url, "err", err)
+			continue
+		}
+		heads[url] = height
+	}
+
+	chainHeadStatesLock.Lock()
+	state := getChainHeadState(chainID)
+	reason := state.update(heads, now(), cfg)
+	chainHeadStatesLock.Unlock()
+
+	if reason != "" {
+		if staleHeadChains.Add(chainID) {
+			logWorkerError("headwatchdog", "pause verification of chain", errChainHeadStale, "chainID", chainID, "reason", reason)
+		}
+	} else if staleHeadChains.Contains(chainID) {
+		staleHeadChains.Remove(chainID)
+		logWorker("headwatchdog", "resume verification of chain", "chainID", chainID)
+	}
+}
+
+// update endpoint heads and return the reason if the chain view is stale
+func (s *chainHeadState) update(heads map[string]uint64, nowTime int64, cfg *params.HeadWatchdogConfig) string {
+	for url, height := range heads {
+		ep, exist := s.endpoints[url]
+		if !exist {
+			s.endpoints[url] = &endpointHead{height: height, updateTime: nowTime}
+			continue
+		}
+		if height > ep.height {
+			ep.updateTime = nowTime
+		}
+		ep.height = height
+	}
+
+	var minHead, maxHead uint64
+	liveCount := 0
+	for _, ep := range s.endpoints {
+		if nowTime-ep.updateTime > cfg.GetStallTimeout() {
+			continue
+		}
+		if liveCount == 0 || ep.height < minHead {
+			minHead = ep.height
+		}
+		if ep.height > maxHead {
+			maxHead = ep.height
+		}
+		liveCount++
+	}
+
+	maxDivergence := cfg.GetMaxDivergence()
+	switch {
+	case len(s.endpoints) == 0:
+		return ""
+	case liveCount == 0:
+		return fmt.Sprintf("all %v endpoints stalled longer than %v seconds", len(s.endpoints), cfg.GetStallTimeout())
+	case maxHead-minHead > maxDivergence:
+		return fmt.Sprintf("endpoint heads diverge from %v to %v", minHead, maxHead)
+	case s.verifiedHeight > maxHead+maxDivergence:
+		return fmt.Sprintf("endpoint heads %v are behind verified height %v", maxHead, s.verifiedHeight)
+	}
+	return ""
+}
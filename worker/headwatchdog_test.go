@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+func TestChainHeadStateUpdate(t *testing.T) {
+	cfg := &params.HeadWatchdogConfig{StallTimeout: 60, MaxDivergence: 10}
+	state := &chainHeadState{endpoints: make(map[string]*endpointHead)}
+
+	if reason := state.update(nil, 1000, cfg); reason != "" {
+		t.Errorf("no endpoints got stale reason %q", reason)
+	}
+	if reason := state.update(map[string]uint64{"a": 100, "b": 105}, 1000, cfg); reason != "" {
+		t.Errorf("live endpoints got stale reason %q", reason)
+	}
+	// endpoint b diverges from a
+	if reason := state.update(map[string]uint64{"a": 101, "b": 120}, 1010, cfg); reason == "" {
+		t.Errorf("divergent endpoints should be stale")
+	}
+	if reason := state.update(map[string]uint64{"a": 115, "b": 120}, 1020, cfg); reason != "" {
+		t.Errorf("endpoints caught up got stale reason %q", reason)
+	}
+
+	// stalled endpoint b is excluded from divergence check
+	if reason := state.update(map[string]uint64{"a": 140}, 1090, cfg); reason != "" {
+		t.Errorf("stalled endpoint should be ignored, got stale reason %q", reason)
+	}
+	if ep := state.endpoints["b"]; ep.height != 120 || ep.updateTime != 1010 {
+		t.Errorf("endpoint without head got %+v, want unchanged", ep)
+	}
+	// head not advanced does not refresh the update time
+	if reason := state.update(map[string]uint64{"a": 140, "b": 120}, 1160, cfg); reason == "" {
+		t.Errorf("all endpoints stalled should be stale")
+	}
+	if ep := state.endpoints["a"]; ep.updateTime != 1090 {
+		t.Errorf("endpoint head not advanced got update time %v, want 1090", ep.updateTime)
+	}
+
+	// endpoints behind the verified height
+	state.verifiedHeight = 200
+	if reason := state.update(map[string]uint64{"a": 180, "b": 185}, 1200, cfg); reason == "" {
+		t.Errorf("endpoints behind verified height should be stale")
+	}
+	if reason := state.update(map[string]uint64{"a": 195, "b": 195}, 1210, cfg); reason != "" {
+		t.Errorf("endpoints within divergence of verified height got stale reason %q", reason)
+	}
+}
@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+var (
+	// count of dispatched but not processed swap tasks, key is chain pair
+	dispatchedRouteTasks     = make(map[string]int64)
+	dispatchedRouteTasksLock sync.Mutex
+
+	// count of signed but not stable swaps of chain pair, it is replaced in tests
+	countSignedInFlightSwaps = mongodb.CountInFlightRouterSwaps
+)
+
+// acquireInFlightSlot defer the swap if the in-flight swaps of its chain pair
+// (signed but not stable, and dispatched but not processed) reach the cap,
+// otherwise count it as dispatched until released after processed.
+func acquireInFlightSlot(swap *mongodb.MgoSwap) error {
+	routeKey := params.GetTokenRouteKey(swap.FromChainID, swap.ToChainID)
+	maxInFlight := params.GetMaxInFlightSwaps(swap.FromChainID, swap.ToChainID)
+
+	dispatchedRouteTasksLock.Lock()
+	var inFlight int64
+	if maxInFlight > 0 {
+		var err error
+		inFlight, err = countInFlightSwaps(swap.FromChainID, swap.ToChainID)
+		if err != nil {
+			dispatchedRouteTasksLock.Unlock()
+			return err
+		}
+	}
+	isCapped := maxInFlight > 0 && inFlight >= maxInFlight
+	if !isCapped {
+		dispatchedRouteTasks[routeKey]++
+	}
+	dispatchedRouteTasksLock.Unlock()
+
+	if isCapped {
+		// defer after unlock, as the deferred job checks the cap with deferred swaps locked
+		logWorkerTrace("inflight", "swap is queued by in-flight cap", "fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "inFlight", inFlight, "maxInFlight", maxInFlight)
+		deferSwap(swap, WaitInFlightCap)
+		return errSwapDeferred
+	}
+	return nil
+}
+
+// releaseInFlightSlot release the dispatched count after the swap task is processed
+func releaseInFlightSlot(fromChainID, toChainID string) {
+	routeKey := params.GetTokenRouteKey(fromChainID, toChainID)
+	dispatchedRouteTasksLock.Lock()
+	defer dispatchedRouteTasksLock.Unlock()
+	if dispatchedRouteTasks[routeKey] > 1 {
+		dispatchedRouteTasks[routeKey]--
+	} else {
+		delete(dispatchedRouteTasks, routeKey)
+	}
+}
+
+// countInFlightSwaps must be called with dispatchedRouteTasksLock held
+func countInFlightSwaps(fromChainID, toChainID string) (int64, error) {
+	count, err := countSignedInFlightSwaps(fromChainID, toChainID)
+	if err != nil {
+		return 0, err
+	}
+	return count + dispatchedRouteTasks[params.GetTokenRouteKey(fromChainID, toChainID)], nil
+}
+
+func checkInFlightCapReleased(ds *DeferredSwap) bool {
+	swap := ds.Swap
+	maxInFlight := params.GetMaxInFlightSwaps(swap.FromChainID, swap.ToChainID)
+	if maxInFlight <= 0 {
+		return true
+	}
+	dispatchedRouteTasksLock.Lock()
+	defer dispatchedRouteTasksLock.Unlock()
+	inFlight, err := countInFlightSwaps(swap.FromChainID, swap.ToChainID)
+	return err == nil && inFlight < maxInFlight
+}
@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+var errTestCountInFlight = errors.New("count in-flight swaps failed")
+
+// setupInFlightCap cap in-flight swaps of chain pair 1:56, with signed swaps counted by `signed`
+func setupInFlightCap(maxInFlight int64, signed *int64, countErr *error) func() {
+	cfg := params.GetRouterConfig()
+	oldServer := cfg.Server
+	cfg.Server = &params.RouterServerConfig{MaxInFlightSwaps: map[string]int64{"1:56": maxInFlight}}
+	oldCount := countSignedInFlightSwaps
+	countSignedInFlightSwaps = func(fromChainID, toChainID string) (int64, error) {
+		return *signed, *countErr
+	}
+	resetDeferredSwaps()
+	dispatchedRouteTasksLock.Lock()
+	dispatchedRouteTasks = make(map[string]int64)
+	dispatchedRouteTasksLock.Unlock()
+	return func() {
+		cfg.Server = oldServer
+		countSignedInFlightSwaps = oldCount
+		resetDeferredSwaps()
+	}
+}
+
+func getDispatchedRouteTasks(routeKey string) int64 {
+	dispatchedRouteTasksLock.Lock()
+	defer dispatchedRouteTasksLock.Unlock()
+	return dispatchedRouteTasks[routeKey]
+}
+
+func TestAcquireInFlightSlot(t *testing.T) {
+	signed := int64(1)
+	var countErr error
+	defer setupInFlightCap(3, &signed, &countErr)()
+
+	// in-flight swaps are the signed and the dispatched
+	for _, key := range []string{"swap1", "swap2"} {
+		if err := acquireInFlightSlot(newTestDeferredSwap(key)); err != nil {
+			t.Fatalf("acquire in-flight slot of %v got err %v", key, err)
+		}
+	}
+	if dispatched := getDispatchedRouteTasks("1:56"); dispatched != 2 {
+		t.Errorf("dispatched tasks got %v, want 2", dispatched)
+	}
+
+	// the excess is queued until a slot is released
+	if err := acquireInFlightSlot(newTestDeferredSwap("swap3")); !errors.Is(err, errSwapDeferred) {
+		t.Fatalf("acquire in-flight slot above cap got err %v, want %v", err, errSwapDeferred)
+	}
+	if !isSwapDeferred("swap3") || getDispatchedRouteTasks("1:56") != 2 {
+		t.Errorf("swap above cap should be deferred without dispatching")
+	}
+	ds := deferredSwaps["swap3"]
+	if ds.Condition != WaitInFlightCap || checkInFlightCapReleased(ds) {
+		t.Errorf("deferred swap got condition %v, want %v not released", ds.Condition, WaitInFlightCap)
+	}
+	releaseInFlightSlot("1", "56")
+	if !checkInFlightCapReleased(ds) {
+		t.Errorf("in-flight cap should be released after processed")
+	}
+
+	// signed swaps becoming stable release slots too
+	releaseInFlightSlot("1", "56")
+	signed = 3
+	if err := acquireInFlightSlot(newTestDeferredSwap("swap4")); !errors.Is(err, errSwapDeferred) {
+		t.Errorf("acquire in-flight slot with signed swaps at cap got err %v", err)
+	}
+	signed = 0
+	if err := acquireInFlightSlot(newTestDeferredSwap("swap4")); err != nil {
+		t.Errorf("acquire in-flight slot after signed swaps stable got err %v", err)
+	}
+
+	// slots are not acquired if in-flight swaps can not be counted
+	countErr = errTestCountInFlight
+	if err := acquireInFlightSlot(newTestDeferredSwap("swap5")); !errors.Is(err, errTestCountInFlight) {
+		t.Errorf("acquire in-flight slot got err %v, want %v", err, errTestCountInFlight)
+	}
+	if isSwapDeferred("swap5") || getDispatchedRouteTasks("1:56") != 1 {
+		t.Errorf("swap should be neither deferred nor dispatched if counting fails")
+	}
+	if checkInFlightCapReleased(ds) {
+		t.Errorf("in-flight cap should not be released if counting fails")
+	}
+}
+
+func TestReleaseInFlightSlot(t *testing.T) {
+	signed := int64(0)
+	var countErr error
+	defer setupInFlightCap(0, &signed, &countErr)()
+
+	// swaps without cap are counted as dispatched too
+	countErr = errTestCountInFlight
+	for i := 0; i < 2; i++ {
+		if err := acquireInFlightSlot(newTestDeferredSwap("swap")); err != nil {
+			t.Fatalf("acquire in-flight slot without cap got err %v", err)
+		}
+	}
+	if !checkInFlightCapReleased(&DeferredSwap{Swap: newTestDeferredSwap("swap")}) {
+		t.Errorf("in-flight cap should be released without cap")
+	}
+	releaseInFlightSlot("1", "56")
+	if dispatched := getDispatchedRouteTasks("1:56"); dispatched != 1 {
+		t.Errorf("dispatched tasks got %v, want 1", dispatched)
+	}
+	// released more than acquired never goes negative
+	releaseInFlightSlot("1", "56")
+	releaseInFlightSlot("1", "56")
+	dispatchedRouteTasksLock.Lock()
+	_, exist := dispatchedRouteTasks["1:56"]
+	dispatchedRouteTasksLock.Unlock()
+	if exist {
+		t.Errorf("dispatched tasks should be removed after all released")
+	}
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+var (
+	integrityCheckStarter sync.Once
+
+	latestIntegrityReport     *mongodb.IntegrityReport
+	latestIntegrityReportLock sync.RWMutex
+)
+
+// StartIntegrityCheckJob consistency audit job of swap and swap result collections
+func StartIntegrityCheckJob() {
+	cfg := params.GetIntegrityCheckConfig()
+	if cfg == nil {
+		return
+	}
+	integrityCheckStarter.Do(func() {
+		logWorker("integrity", "start integrity check job", "interval", cfg.GetInterval(), "scanWindow", cfg.GetScanWindow(), "autoRepair", cfg.AutoRepair)
+		goSupervisedJob("integrity", nil, func() { runIntegrityCheck(cfg) })
+	})
+}
+
+func runIntegrityCheck(cfg *params.IntegrityCheckConfig) {
+	interval := time.Duration(cfg.GetInterval()) * time.Second
+	for {
+		doIntegrityCheck(cfg)
+		time.Sleep(interval)
+	}
+}
+
+func doIntegrityCheck(cfg *params.IntegrityCheckConfig) {
+	nowTime := now()
+	report, err := mongodb.CheckIntegrity(&mongodb.IntegrityCheckArgs{
+		Since:      nowTime - cfg.GetScanWindow(),
+		Before:     nowTime - cfg.GetGracePeriod(),
+		BatchSize:  cfg.GetBatchSize(),
+		AutoRepair: cfg.AutoRepair,
+	})
+	if err != nil {
+		logWorkerError("integrity", "integrity check failed", err)
+		return
+	}
+
+	latestIntegrityReportLock.Lock()
+	latestIntegrityReport = report
+	latestIntegrityReportLock.Unlock()
+
+	if len(report.Findings) > report.Repaired {
+		logWorkerWarn("integrity", "integrity check found divergences", "findings", len(report.Findings), "repaired", report.Repaired,
+			"scannedSwaps", report.ScannedSwaps, "scannedResults", report.ScannedResults)
+		return
+	}
+	logWorker("integrity", "integrity check finished", "findings", len(report.Findings), "repaired", report.Repaired,
+		"scannedSwaps", report.ScannedSwaps, "scannedResults", report.ScannedResults)
+}
+
+// GetIntegrityReport get the latest integrity check report (nil if not checked yet)
+func GetIntegrityReport() *mongodb.IntegrityReport {
+	latestIntegrityReportLock.RLock()
+	defer latestIntegrityReportLock.RUnlock()
+	return latestIntegrityReport
+}
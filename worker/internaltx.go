@@ -0,0 +1,134 @@
+package worker
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// internal txs (eg. claim refunds, deposit sweeps and allowance approves) are not swaps, but they are sent by the
+// router mpc and share its nonce with the swaps. they are queued in the swap
+// task queue of the chain, and are built, signed and sent in order with the
+// swaps, the nonce is advanced only after the tx is sent successfully.
+var (
+	internalTxCallbacks = new(sync.Map) // key is swap key, value is func(txHash string)
+
+	errInternalTxInQueue        = errors.New("internal tx is already in queue")
+	errInternalTxParallelSwap   = errors.New("internal tx is not supported in parallel swap mode")
+	errInternalTxWrongSwapType  = errors.New("not an internal tx swap type")
+	errInternalTxCallbackAbsent = errors.New("internal tx is not dispatched")
+)
+
+func isInternalTxType(swapType tokens.SwapType) bool {
+	switch swapType {
+	case tokens.ClaimRefundType, tokens.DepositSweepType, tokens.AllowanceApproveType:
+		return true
+	default:
+		return false
+	}
+}
+
+func getInternalTxKey(args *tokens.BuildTxArgs) string {
+	return mongodb.GetRouterSwapKey(args.FromChainID.String(), args.SwapID, args.LogIndex)
+}
+
+// dispatchInternalTx queue internal tx in the swap task queue of the chain,
+// onSent is called with the tx hash after it is sent successfully.
+func dispatchInternalTx(args *tokens.BuildTxArgs, onSent func(txHash string)) error {
+	if !isInternalTxType(args.SwapType) {
+		return errInternalTxWrongSwapType
+	}
+	if params.IsParallelSwapEnabledOnChain(args.ToChainID.String()) {
+		return errInternalTxParallelSwap
+	}
+	key := getInternalTxKey(args)
+	if swapTasksInQueue.Contains(key) {
+		return errInternalTxInQueue
+	}
+	internalTxCallbacks.Store(key, onSent)
+	if args.Extra == nil {
+		args.Extra = &tokens.AllExtras{}
+	}
+	err := dispatchSwapTask(args)
+	if err != nil {
+		internalTxCallbacks.Delete(key)
+	}
+	return err
+}
+
+func doInternalTx(args *tokens.BuildTxArgs) (err error) {
+	key := getInternalTxKey(args)
+	callback, exist := internalTxCallbacks.LoadAndDelete(key)
+	if !exist {
+		return errInternalTxCallbackAbsent
+	}
+	onSent := callback.(func(string))
+
+	toChainID := args.ToChainID.String()
+	ctx := []interface{}{"chainID", toChainID, "swapType", args.SwapType.String(), "swapID", args.SwapID}
+
+	resBridge := router.GetBridgeByChainID(toChainID)
+	if resBridge == nil {
+		return tokens.ErrNoBridgeForChainID
+	}
+
+	// in pipeline mode the tx is sent by the pipeline in order with the swaps
+	var sendTask *pipelineTask
+	pipe := getSwapPipeline(toChainID)
+	if pipe != nil {
+		seq, errp := pipe.reserve()
+		if errp != nil {
+			return errp
+		}
+		defer func() { pipe.deliver(seq, sendTask) }()
+	}
+	epoch := setPipelineNonce(pipe, resBridge, args)
+
+	start := time.Now()
+	rawTx, err := resBridge.BuildRawTransaction(args)
+	if err != nil {
+		logWorkerError("internaltx", "build tx failed", err, ctx...)
+		return err
+	}
+	ctx = append(ctx, "nonce", args.GetTxNonce())
+
+	setSignExpiry(args)
+	signedTx, txHash, err := mpcSignTransaction(resBridge, rawTx, args)
+	if err != nil {
+		logWorkerError("internaltx", "sign tx failed", err, ctx...)
+		return err
+	}
+	ctx = append(ctx, "txHash", txHash)
+	logWorker("internaltx", "sign tx success", append(ctx, "timespent", time.Since(start).String())...)
+
+	err = checkStaleSignature(resBridge, args, txHash)
+	if err != nil {
+		return err
+	}
+
+	send := func() error {
+		sentTxHash, errs := sendSignedTransaction(resBridge, signedTx, args)
+		if errs != nil {
+			logWorkerError("internaltx", "send tx failed", errs, ctx...)
+			return errs
+		}
+		if sentTxHash != "" {
+			txHash = sentTxHash
+		}
+		logWorker("internaltx", "send tx success", ctx...)
+		onSent(txHash)
+		return nil
+	}
+	if pipe != nil {
+		// the next swap can be built before this one is sent
+		pipe.signed(args.From, args.GetTxNonce(), epoch)
+		sendTask = &pipelineTask{epoch: epoch, send: send}
+		return nil
+	}
+	return send()
+}
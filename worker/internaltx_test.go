@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func newTestInternalTxArgs(swapType tokens.SwapType) *tokens.BuildTxArgs {
+	return &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapType:    swapType,
+			SwapID:      "0x1111111111111111111111111111111111111111111111111111111111111111",
+			LogIndex:    0,
+			FromChainID: big.NewInt(1000005788240),
+			ToChainID:   big.NewInt(1000005788240),
+		},
+	}
+}
+
+func TestDispatchInternalTx(t *testing.T) {
+	onSent := func(string) {}
+
+	args := newTestInternalTxArgs(tokens.ERC20SwapType)
+	if err := dispatchInternalTx(args, onSent); !errors.Is(err, errInternalTxWrongSwapType) {
+		t.Errorf("dispatch swap as internal tx got error %v, want %v", err, errInternalTxWrongSwapType)
+	}
+
+	args = newTestInternalTxArgs(tokens.ClaimRefundType)
+	key := getInternalTxKey(args)
+	swapTasksInQueue.Add(key)
+	defer swapTasksInQueue.Remove(key)
+	if err := dispatchInternalTx(args, onSent); !errors.Is(err, errInternalTxInQueue) {
+		t.Errorf("dispatch queued internal tx got error %v, want %v", err, errInternalTxInQueue)
+	}
+	if _, exist := internalTxCallbacks.Load(key); exist {
+		t.Errorf("callback of rejected internal tx should not be stored")
+	}
+
+	// internal txs are only built and sent with a dispatched callback
+	if err := doInternalTx(args); !errors.Is(err, errInternalTxCallbackAbsent) {
+		t.Errorf("do undispatched internal tx got error %v, want %v", err, errInternalTxCallbackAbsent)
+	}
+}
@@ -0,0 +1,124 @@
+package worker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/router/bridge"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// after mpc config changes (including startup), production sign requests are
+// refused until the canary message is signed and verified with every mpc key
+// of the router chains, so that a misconfigured mpc setting is found before
+// it fails (or mis-signs) real swaps. reloading router config may change the
+// router chains and their mpc keys, so canary signing is reset and run again.
+var (
+	mpcCanaryStarter sync.Once
+	mpcCanaryTrigger = make(chan struct{}, 1)
+)
+
+// StartMPCCanaryJob mpc canary signing job
+func StartMPCCanaryJob() {
+	cfg := params.GetRouterConfig().MPC.Canary
+	if fastMPC := params.GetRouterConfig().FastMPC; cfg == nil && fastMPC != nil {
+		cfg = fastMPC.Canary
+	}
+	if cfg == nil {
+		return
+	}
+	mpcCanaryStarter.Do(func() {
+		logWorker("mpccanary", "start mpc canary job", "interval", cfg.GetInterval())
+		bridge.OnReloadRouterConfig(resetMPCCanary)
+		goSupervisedJob("mpccanary", nil, func() { runMPCCanary(cfg) })
+	})
+}
+
+func runMPCCanary(cfg *params.MPCCanaryConfig) {
+	interval := time.Duration(cfg.GetInterval()) * time.Second
+	for {
+		for _, isFastMPC := range []bool{false, true} {
+			doMPCCanary(isFastMPC)
+		}
+		select {
+		case <-mpcCanaryTrigger:
+		case <-time.After(interval):
+		}
+	}
+}
+
+// resetMPCCanary reset canary signing after router config is reloaded
+func resetMPCCanary() {
+	for _, isFastMPC := range []bool{false, true} {
+		if mpcConfig := mpc.GetMPCConfig(isFastMPC); mpcConfig != nil {
+			mpcConfig.ResetCanary()
+		}
+	}
+	select {
+	case mpcCanaryTrigger <- struct{}{}:
+	default:
+	}
+}
+
+func doMPCCanary(isFastMPC bool) {
+	mpcConfig := mpc.GetMPCConfig(isFastMPC)
+	if mpcConfig == nil || !mpcConfig.IsCanaryEnabled() || mpcConfig.IsCanaryPassed() {
+		return
+	}
+	round := mpcConfig.GetCanaryRound()
+	fingerprint := mpcConfig.GetConfigFingerprint()
+	signKeys := getMPCCanarySignKeys(isFastMPC)
+	if len(signKeys) == 0 {
+		logWorkerWarn("mpccanary", "no mpc key found for canary signing", "isFastMPC", isFastMPC)
+		return
+	}
+	for _, key := range signKeys {
+		if err := signCanaryMessage(mpcConfig, key.family, key.signPubkey); err != nil {
+			logWorkerError("mpccanary", "mpc canary signing failed", err, "isFastMPC", isFastMPC, "family", key.family, "signPubkey", key.signPubkey, "chainIDs", key.chainIDs, "fingerprint", fingerprint)
+			return
+		}
+		logWorker("mpccanary", "mpc canary signing success", "isFastMPC", isFastMPC, "family", key.family, "signPubkey", key.signPubkey, "chainIDs", key.chainIDs)
+	}
+	mpcConfig.SetCanaryPassed(fingerprint, round)
+}
+
+type mpcCanarySignKey struct {
+	family     string
+	signPubkey string
+	chainIDs   []string
+}
+
+// getMPCCanarySignKeys get the distinct mpc sign keys of router chains using the mpc
+func getMPCCanarySignKeys(isFastMPC bool) (signKeys []*mpcCanarySignKey) {
+	keyIndexes := make(map[string]int)
+	router.RouterBridges.Range(func(k, v interface{}) bool {
+		chainID := k.(string)
+		signer, ok := v.(tokens.MPCSchemeSigner)
+		if !ok || params.IsUseFastMPC(chainID) != isFastMPC {
+			return true
+		}
+		routerContract := v.(tokens.IBridge).GetChainConfig().RouterContract
+		routerInfo := router.GetRouterInfo(routerContract, chainID)
+		if routerInfo == nil || routerInfo.RouterMPC == "" {
+			return true
+		}
+		family, signPubkey, err := signer.GetMPCSignKey(routerInfo.RouterMPC)
+		if err != nil {
+			logWorkerError("mpccanary", "get mpc sign key failed", err, "chainID", chainID, "mpc", routerInfo.RouterMPC)
+			return true
+		}
+		key := fmt.Sprintf("%v:%v", family, signPubkey)
+		if i, exist := keyIndexes[key]; exist {
+			signKeys[i].chainIDs = append(signKeys[i].chainIDs, chainID)
+			return true
+		}
+		keyIndexes[key] = len(signKeys)
+		signKeys = append(signKeys, &mpcCanarySignKey{family: family, signPubkey: signPubkey, chainIDs: []string{chainID}})
+		return true
+	})
+	return signKeys
+}
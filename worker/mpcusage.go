@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// mpc sign requests are counted per destination chain in time buckets when
+// signing, the job compares the current bucket of every chain with the
+// average of its previous buckets to alert on spikes (eg. runaway retries).
+var (
+	mpcUsageStarter sync.Once
+
+	// key is chainID:kind, value is the latest alerted bucket
+	mpcUsageAlerted = make(map[string]int64)
+
+	errMPCUsageSpike = errors.New("mpc usage spike")
+)
+
+// mpcUsageSpike spike of mpc usage of chain in current bucket
+type mpcUsageSpike struct {
+	ChainID  string
+	Kind     string // sign or fail
+	Count    int64
+	Baseline float64
+}
+
+// recordMPCUsage record mpc sign request of destination chain
+func recordMPCUsage(chainID string, signErr error) {
+	cfg := params.GetMPCUsageConfig()
+	if cfg == nil || !mongodb.HasClient() {
+		return
+	}
+	_ = mongodb.IncMPCUsage(cfg.GetBucketSize(), chainID, signErr != nil)
+}
+
+// StartMPCUsageJob mpc usage spike alerting job
+func StartMPCUsageJob() {
+	cfg := params.GetMPCUsageConfig()
+	if cfg == nil {
+		return
+	}
+	mpcUsageStarter.Do(func() {
+		logWorker("mpcusage", "start mpc usage job", "bucketSize", cfg.GetBucketSize(), "baselineBuckets", cfg.GetBaselineBuckets(),
+			"spikeRatio", cfg.GetSpikeRatio(), "minSpikeCount", cfg.GetMinSpikeCount())
+		goSupervisedJob("mpcusage", nil, func() { runMPCUsageCheck(cfg) })
+	})
+}
+
+func runMPCUsageCheck(cfg *params.MPCUsageConfig) {
+	interval := time.Duration(cfg.GetCheckInterval()) * time.Second
+	for {
+		doMPCUsageCheck(cfg)
+		time.Sleep(interval)
+	}
+}
+
+func doMPCUsageCheck(cfg *params.MPCUsageConfig) {
+	nowTime := now()
+	bucketSize := cfg.GetBucketSize()
+	curBucket := nowTime - nowTime%bucketSize
+	filter := &mongodb.MPCUsageFilter{
+		Start: curBucket - cfg.GetBaselineBuckets()*bucketSize,
+		End:   curBucket + bucketSize,
+	}
+	usages, err := mongodb.FindMPCUsages(filter, 0)
+	if err != nil {
+		logWorkerError("mpcusage", "find mpc usages failed", err)
+		return
+	}
+
+	for _, spike := range findMPCUsageSpikes(usages, curBucket, cfg) {
+		alertKey := spike.ChainID + ":" + spike.Kind
+		if mpcUsageAlerted[alertKey] == curBucket {
+			continue // alert once per bucket
+		}
+		mpcUsageAlerted[alertKey] = curBucket
+		logWorkerError("mpcusage", "mpc usage spike detected", errMPCUsageSpike,
+			"chainID", spike.ChainID, "kind", spike.Kind, "count", spike.Count, "baseline", spike.Baseline, "bucket", curBucket)
+	}
+
+	pruned, err := mongodb.PruneMPCUsages(nowTime-cfg.GetRetention(), 10000)
+	if err != nil {
+		logWorkerError("mpcusage", "prune mpc usages failed", err)
+	}
+	logWorker("mpcusage", "check mpc usage finished", "usages", len(usages), "pruned", pruned)
+}
+
+// findMPCUsageSpikes find spikes of sign and fail count in current bucket,
+// the baseline is the average of previous buckets (missing bucket is zero).
+func findMPCUsageSpikes(usages []*mongodb.MgoMPCUsage, curBucket int64, cfg *params.MPCUsageConfig) []*mpcUsageSpike {
+	type usageSum struct {
+		curSign, curFail   int64
+		prevSign, prevFail int64
+	}
+	sums := make(map[string]*usageSum)
+	chainIDs := make([]string, 0, len(usages))
+	for _, usage := range usages {
+		sum, exist := sums[usage.ChainID]
+		if !exist {
+			sum = &usageSum{}
+			sums[usage.ChainID] = sum
+			chainIDs = append(chainIDs, usage.ChainID)
+		}
+		if usage.Bucket == curBucket {
+			sum.curSign += usage.SignCount
+			sum.curFail += usage.FailCount
+		} else {
+			sum.prevSign += usage.SignCount
+			sum.prevFail += usage.FailCount
+		}
+	}
+
+	baselineBuckets := float64(cfg.GetBaselineBuckets())
+	isSpike := func(count int64, baseline float64) bool {
+		return count >= cfg.GetMinSpikeCount() && float64(count) > baseline*cfg.GetSpikeRatio()
+	}
+	spikes := make([]*mpcUsageSpike, 0)
+	for _, chainID := range chainIDs {
+		sum := sums[chainID]
+		if baseline := float64(sum.prevSign) / baselineBuckets; isSpike(sum.curSign, baseline) {
+			spikes = append(spikes, &mpcUsageSpike{ChainID: chainID, Kind: "sign", Count: sum.curSign, Baseline: baseline})
+		}
+		if baseline := float64(sum.prevFail) / baselineBuckets; isSpike(sum.curFail, baseline) {
+			spikes = append(spikes, &mpcUsageSpike{ChainID: chainID, Kind: "fail", Count: sum.curFail, Baseline: baseline})
+		}
+	}
+	return spikes
+}
@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+func TestFindMPCUsageSpikes(t *testing.T) {
+	cfg := &params.MPCUsageConfig{BucketSize: 100, BaselineBuckets: 4, SpikeRatio: 3, MinSpikeCount: 10}
+	const curBucket = 1000
+	usages := []*mongodb.MgoMPCUsage{
+		// chain 1: baseline sign 5 per bucket (missing buckets are zero), 30 signs in current bucket
+		{ChainID: "1", Bucket: 700, SignCount: 10},
+		{ChainID: "1", Bucket: 900, SignCount: 10, FailCount: 2},
+		{ChainID: "1", Bucket: curBucket, SignCount: 30, FailCount: 5},
+		// chain 56: steady usage
+		{ChainID: "56", Bucket: 800, SignCount: 40, FailCount: 20},
+		{ChainID: "56", Bucket: 900, SignCount: 40, FailCount: 20},
+		{ChainID: "56", Bucket: curBucket, SignCount: 50, FailCount: 20},
+		// chain 137: spike below min spike count
+		{ChainID: "137", Bucket: curBucket, SignCount: 9},
+		// chain 250: fail spike only
+		{ChainID: "250", Bucket: 900, SignCount: 100},
+		{ChainID: "250", Bucket: curBucket, SignCount: 60, FailCount: 40},
+	}
+
+	spikes := findMPCUsageSpikes(usages, curBucket, cfg)
+	want := []mpcUsageSpike{
+		{ChainID: "1", Kind: "sign", Count: 30, Baseline: 5},
+		{ChainID: "250", Kind: "fail", Count: 40, Baseline: 0},
+	}
+	if len(spikes) != len(want) {
+		t.Fatalf("find spikes got %v spikes, want %v", len(spikes), len(want))
+	}
+	for i, spike := range spikes {
+		if *spike != want[i] {
+			t.Errorf("spike %v got %+v, want %+v", i, *spike, want[i])
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	paramWatchStarter sync.Once
+
+	errChainParamChanged = errors.New("chain param affecting bridge operation is changed")
+)
+
+// StartChainParamWatchJob watch on-chain params affecting bridge operation job
+func StartChainParamWatchJob() {
+	paramWatchStarter.Do(func() {
+		logWorker("paramwatch", "start chain param watch job")
+		go doChainParamWatchJob()
+	})
+}
+
+func doChainParamWatchJob() {
+	for {
+		router.RouterBridges.Range(func(k, v interface{}) bool {
+			watchChainParams(k.(string), v.(tokens.IBridge))
+			return !utils.IsCleanuping()
+		})
+		if utils.IsCleanuping() {
+			logWorker("paramwatch", "stop chain param watch job")
+			return
+		}
+		restInJob(restIntervalInParamWatchJob)
+	}
+}
+
+func watchChainParams(chainID string, bridge tokens.IBridge) {
+	if !params.IsChainParamWatchEnabled(chainID) {
+		return
+	}
+	watcher, ok := bridge.(tokens.ChainParamWatcher)
+	if !ok {
+		return
+	}
+	changes, err := watcher.WatchChainParams()
+	if err != nil {
+		logWorkerWarn("paramwatch", "watch chain params failed", "chainID", chainID, "err", err)
+		return
+	}
+	for _, change := range changes {
+		logWorkerError("paramwatch", "chain param changed", errChainParamChanged, "chainID", chainID, "change", change)
+	}
+}
@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testParamWatchBridge struct {
+	tokens.IBridge
+	watched int
+}
+
+func (b *testParamWatchBridge) WatchChainParams() ([]string, error) {
+	b.watched++
+	return []string{"send of denom uatom is disabled"}, nil
+}
+
+func TestWatchChainParams(t *testing.T) {
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			"cosmoshub-4": {WatchChainParams: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }()
+
+	bridge := &testParamWatchBridge{}
+	watchChainParams("cosmoshub-4", bridge)
+	if bridge.watched != 1 {
+		t.Errorf("watch chain params of enabled chain got %v watches, want 1", bridge.watched)
+	}
+
+	// chains without param watch are not watched
+	bridge = &testParamWatchBridge{}
+	watchChainParams("osmosis-1", bridge)
+	if bridge.watched != 0 {
+		t.Errorf("watch chain params of disabled chain got %v watches, want 0", bridge.watched)
+	}
+}
@@ -106,7 +106,7 @@ func processPassBigValRouterSwap(swap *mongodb.MgoSwap) (err error) {
 		return err
 	}
 
-	err = mongodb.RouterAdminPassBigValue(fromChainID, txid, logIndex)
+	err = mongodb.RouterAdminPassBigValue(fromChainID, txid, logIndex, mongodb.ActorRouter)
 	if err != nil {
 		return err
 	}
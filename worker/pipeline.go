@@ -0,0 +1,206 @@
+package worker
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	swapPipelines     = make(map[string]*swapPipeline) // key is toChainID
+	swapPipelinesLock sync.Mutex
+
+	errSwapPipelineStopped = errors.New("swap pipeline is stopped")
+)
+
+// swapPipeline overlaps building and mpc signing of the next swap with
+// sending of the previous swap txs to the chain. the swap consumer reserves
+// a sequence before building and delivers the send task (nil if failed)
+// with it, the sender runs the tasks in sequence order through a reorder buffer.
+// reserving blocks if there are already depth swaps in the pipeline.
+//
+// the nonce of the bridge is advanced only after a tx is sent successfully,
+// the next swap is built with the nonce following the last signed one kept
+// by the pipeline. if sending fails, the pipeline starts a new epoch, the kept
+// nonces are dropped and the tasks built in the old epoch are discarded,
+// as their nonces follow the failed one (the swaps are replaced later).
+type swapPipeline struct {
+	chainID string
+	slots   chan struct{}
+	notify  chan struct{}
+
+	lock    sync.Mutex
+	nextSeq uint64
+	sendSeq uint64
+	pending map[uint64]*pipelineTask // reorder buffer
+	nonces  map[string]uint64        // key is lower sender, value is next nonce
+	epoch   uint64
+}
+
+type pipelineTask struct {
+	epoch uint64
+	send  func() error
+}
+
+// getSwapPipeline get swap pipeline of chain, nil if pipeline is disabled
+func getSwapPipeline(chainID string) *swapPipeline {
+	depth := params.GetSwapPipelineDepth(chainID)
+	if depth <= 0 {
+		return nil
+	}
+	swapPipelinesLock.Lock()
+	defer swapPipelinesLock.Unlock()
+	pipe, exist := swapPipelines[chainID]
+	if !exist {
+		pipe = newSwapPipeline(chainID, depth)
+		swapPipelines[chainID] = pipe
+		logWorker("pipeline", "start swap pipeline", "chainID", chainID, "depth", depth)
+		goSupervisedJob("swapPipeline:"+chainID, mongodb.MgoWaitGroup, pipe.run)
+	}
+	return pipe
+}
+
+func newSwapPipeline(chainID string, depth int) *swapPipeline {
+	return &swapPipeline{
+		chainID: chainID,
+		slots:   make(chan struct{}, depth),
+		notify:  make(chan struct{}, 1),
+		pending: make(map[uint64]*pipelineTask),
+		nonces:  make(map[string]uint64),
+	}
+}
+
+func (p *swapPipeline) reserve() (uint64, error) {
+	for {
+		select {
+		case p.slots <- struct{}{}:
+			p.lock.Lock()
+			defer p.lock.Unlock()
+			seq := p.nextSeq
+			p.nextSeq++
+			return seq, nil
+		case <-time.After(time.Second):
+			if utils.IsCleanuping() {
+				return 0, errSwapPipelineStopped
+			}
+		}
+	}
+}
+
+// getNonce get the nonce to build the next tx of sender and the current epoch,
+// the nonce is not exist if there is no signed tx of sender in this epoch.
+func (p *swapPipeline) getNonce(sender string) (nonce, epoch uint64, exist bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	nonce, exist = p.nonces[strings.ToLower(sender)]
+	return nonce, p.epoch, exist
+}
+
+// signed keep the nonce following the signed tx built in epoch
+func (p *swapPipeline) signed(sender string, nonce, epoch uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if epoch != p.epoch {
+		return
+	}
+	key := strings.ToLower(sender)
+	if next, exist := p.nonces[key]; !exist || next <= nonce {
+		p.nonces[key] = nonce + 1
+	}
+}
+
+// deliver deliver send task of the reserved sequence, nil task means nothing to send
+func (p *swapPipeline) deliver(seq uint64, task *pipelineTask) {
+	p.lock.Lock()
+	p.pending[seq] = task
+	p.lock.Unlock()
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (p *swapPipeline) next() (task *pipelineTask, ok bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	task, ok = p.pending[p.sendSeq]
+	if ok {
+		delete(p.pending, p.sendSeq)
+		p.sendSeq++
+	}
+	return task, ok
+}
+
+func (p *swapPipeline) isStale(task *pipelineTask) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return task.epoch != p.epoch
+}
+
+// fail start a new epoch after sending of task failed
+func (p *swapPipeline) fail(task *pipelineTask) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if task.epoch != p.epoch {
+		return
+	}
+	p.epoch++
+	p.nonces = make(map[string]uint64)
+}
+
+func (p *swapPipeline) send(task *pipelineTask) {
+	defer func() { <-p.slots }()
+	if task == nil {
+		return
+	}
+	if p.isStale(task) {
+		logWorkerWarn("pipeline", "discard tx built before a failed send", "chainID", p.chainID, "epoch", task.epoch)
+		return
+	}
+	if err := task.send(); err != nil {
+		logWorkerError("pipeline", "send tx failed, start new epoch", err, "chainID", p.chainID, "epoch", task.epoch)
+		p.fail(task)
+	}
+}
+
+func (p *swapPipeline) run() {
+	for {
+		if task, ok := p.next(); ok {
+			p.send(task)
+			continue
+		}
+		if utils.IsCleanuping() {
+			logWorker("pipeline", "stop swap pipeline", "chainID", p.chainID)
+			return
+		}
+		select {
+		case <-p.notify:
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// setPipelineNonce pin the nonce following the last signed tx in the pipeline
+// (eth like chains), returns the epoch the tx is built in.
+func setPipelineNonce(pipe *swapPipeline, bridge tokens.IBridge, args *tokens.BuildTxArgs) (epoch uint64) {
+	if pipe == nil {
+		return 0
+	}
+	nonce, epoch, exist := pipe.getNonce(args.From)
+	nonceSetter, ok := bridge.(tokens.NonceSetter)
+	if !exist || !ok || nonceSetter == nil {
+		return epoch
+	}
+	if args.Extra == nil {
+		args.Extra = &tokens.AllExtras{}
+	}
+	nonce = nonceSetter.AdjustNonce(args.From, nonce)
+	args.Extra.Sequence = &nonce
+	return epoch
+}
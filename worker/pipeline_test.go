@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+)
+
+const testPipelineSender = "0x1111111111111111111111111111111111111111"
+
+func newTestPipelineTask(epoch uint64, sent *[]int, id int, err error) *pipelineTask {
+	return &pipelineTask{
+		epoch: epoch,
+		send: func() error {
+			*sent = append(*sent, id)
+			return err
+		},
+	}
+}
+
+func runPipelineTasks(p *swapPipeline) {
+	for {
+		task, ok := p.next()
+		if !ok {
+			return
+		}
+		p.send(task)
+	}
+}
+
+func TestSwapPipelineOrder(t *testing.T) {
+	p := newSwapPipeline("1", 3)
+	var seqs []uint64
+	for i := 0; i < 3; i++ {
+		seq, err := p.reserve()
+		if err != nil {
+			t.Fatalf("reserve failed: %v", err)
+		}
+		seqs = append(seqs, seq)
+	}
+	if len(p.slots) != 3 {
+		t.Fatalf("reserved slots got %v, want 3", len(p.slots))
+	}
+
+	// tasks are sent in sequence order whatever the delivering order is
+	var sent []int
+	p.deliver(seqs[2], newTestPipelineTask(0, &sent, 2, nil))
+	p.deliver(seqs[1], nil) // failed to sign, nothing to send
+	runPipelineTasks(p)
+	if len(sent) != 0 {
+		t.Fatalf("tasks should wait for the first sequence, sent %v", sent)
+	}
+	p.deliver(seqs[0], newTestPipelineTask(0, &sent, 0, nil))
+	runPipelineTasks(p)
+	if len(sent) != 2 || sent[0] != 0 || sent[1] != 2 {
+		t.Errorf("sent tasks got %v, want [0 2]", sent)
+	}
+	if len(p.slots) != 0 {
+		t.Errorf("slots should be released after sending, got %v", len(p.slots))
+	}
+}
+
+func TestSwapPipelineNonce(t *testing.T) {
+	p := newSwapPipeline("1", 3)
+
+	// no signed tx yet, build with the nonce of the bridge
+	if _, epoch, exist := p.getNonce(testPipelineSender); exist || epoch != 0 {
+		t.Fatalf("get nonce of new pipeline got exist %v epoch %v", exist, epoch)
+	}
+	p.signed(testPipelineSender, 10, 0)
+	p.signed(testPipelineSender, 9, 0) // never go back
+	nonce, epoch, exist := p.getNonce("0x1111111111111111111111111111111111111111")
+	if !exist || nonce != 11 || epoch != 0 {
+		t.Fatalf("get nonce got (%v, %v, %v), want (11, 0, true)", nonce, epoch, exist)
+	}
+}
+
+func TestSwapPipelineSendFailed(t *testing.T) {
+	p := newSwapPipeline("1", 3)
+	for i := 0; i < 3; i++ {
+		if _, err := p.reserve(); err != nil {
+			t.Fatalf("reserve failed: %v", err)
+		}
+	}
+	p.signed(testPipelineSender, 10, 0)
+	p.signed(testPipelineSender, 11, 0)
+
+	var sent []int
+	p.deliver(0, newTestPipelineTask(0, &sent, 0, errors.New("send failed")))
+	p.deliver(1, newTestPipelineTask(0, &sent, 1, nil))
+	runPipelineTasks(p)
+
+	// the task built on the nonce following the failed one is discarded
+	if len(sent) != 1 || sent[0] != 0 {
+		t.Errorf("sent tasks got %v, want [0]", sent)
+	}
+	// the kept nonces are dropped, the next swap is built with the nonce of the bridge
+	_, epoch, exist := p.getNonce(testPipelineSender)
+	if exist || epoch != 1 {
+		t.Errorf("get nonce after failed send got exist %v epoch %v, want new epoch 1", exist, epoch)
+	}
+	// sign of the old epoch does not keep nonce any more
+	p.signed(testPipelineSender, 12, 0)
+	if _, _, exist = p.getNonce(testPipelineSender); exist {
+		t.Errorf("nonce signed in old epoch should not be kept")
+	}
+
+	p.deliver(2, newTestPipelineTask(1, &sent, 2, nil))
+	runPipelineTasks(p)
+	if len(sent) != 2 || sent[1] != 2 {
+		t.Errorf("sent tasks got %v, want [0 2]", sent)
+	}
+	if len(p.slots) != 0 {
+		t.Errorf("slots should be released after sending, got %v", len(p.slots))
+	}
+}
+
+func TestSetPipelineNonceWithoutPipeline(t *testing.T) {
+	args := newTestInternalTxArgs(0)
+	if epoch := setPipelineNonce(nil, nil, args); epoch != 0 || args.Extra != nil {
+		t.Errorf("set pipeline nonce without pipeline should do nothing")
+	}
+}
@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var errCrossVerifyNotSupported = errors.New("cross verify is not supported by the chain")
+
+// QuarantineSwaps quarantine swaps verified in the incident window (eg. during a
+// suspected rpc compromise), they are re-verified with cross verification against
+// all gateway endpoints before proceeding to signing. swaps already signed are skipped.
+func QuarantineSwaps(filter *mongodb.QuarantineFilter, memo, actor string) (*mongodb.QuarantineResult, error) {
+	res, err := mongodb.QuarantineRouterSwaps(filter, memo, actor)
+	if err != nil {
+		logWorkerError("quarantine", "quarantine swaps failed", err, "filter", filter)
+		return nil, err
+	}
+	for _, key := range res.Quarantined {
+		cachedVerifyingSwaps.Remove(key)
+		cachedSwapTasks.Remove(key)
+	}
+	logWorker("quarantine", "quarantine swaps success", "filter", filter, "quarantined", len(res.Quarantined), "skipped", len(res.Skipped), "memo", memo)
+	return res, nil
+}
+
+// crossVerifySwapTx cross verify the source tx of quarantined swap
+// against the gateway endpoints of the source chain
+func crossVerifySwapTx(bridge tokens.IBridge, txid string) error {
+	verifier, ok := bridge.(tokens.TxCrossVerifier)
+	if !ok {
+		return errCrossVerifyNotSupported
+	}
+	quorum := params.GetQuarantineQuorum(len(bridge.GetGatewayConfig().AllGatewayURLs))
+	return verifier.CrossVerifyTx(txid, quorum)
+}
+
+// isVerifyTimedOut whether the swap which can not be verified yet is timed out.
+// quarantined swaps wait for the sources to recover until the quarantine timeout.
+func isVerifyTimedOut(swap *mongodb.MgoSwap, nowMilli int64) bool {
+	if !swap.Quarantined {
+		return swap.InitTime+1000*maxTxNotFoundTime < nowMilli
+	}
+	quarantineTime := swap.QuarantineTime
+	if quarantineTime == 0 {
+		quarantineTime = swap.Timestamp
+	}
+	return quarantineTime+params.GetQuarantineNotFoundTimeout() < nowMilli/1000
+}
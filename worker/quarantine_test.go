@@ -0,0 +1,33 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+func TestIsVerifyTimedOut(t *testing.T) {
+	const nowMilli = int64(1e13)
+	initTime := nowMilli - 1000*maxTxNotFoundTime - 1
+	if !isVerifyTimedOut(&mongodb.MgoSwap{InitTime: initTime}, nowMilli) {
+		t.Errorf("not found swap should time out")
+	}
+
+	// quarantined swaps time out since quarantined
+	timeout := params.GetQuarantineNotFoundTimeout()
+	swap := &mongodb.MgoSwap{InitTime: initTime, Quarantined: true, QuarantineTime: nowMilli / 1000}
+	if isVerifyTimedOut(swap, nowMilli) {
+		t.Errorf("quarantined swap should not time out after the init time")
+	}
+	swap.QuarantineTime = nowMilli/1000 - timeout - 1
+	if !isVerifyTimedOut(swap, nowMilli) {
+		t.Errorf("quarantined swap should time out after the quarantine timeout")
+	}
+	// swaps quarantined before recording the quarantine time
+	swap.QuarantineTime = 0
+	swap.Timestamp = nowMilli / 1000
+	if isVerifyTimedOut(swap, nowMilli) {
+		t.Errorf("quarantined swap without quarantine time should use the timestamp")
+	}
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+)
+
+var rateOracleStarter sync.Once
+
+// StartRateOracleJob update conversion rates of cross-asset routes
+// and fee coin prices of replace guards job
+func StartRateOracleJob() {
+	if !hasRateOracles() {
+		return
+	}
+	rateOracleStarter.Do(func() {
+		logWorker("rateoracle", "start rate oracle job")
+		go doRateOracleJob()
+	})
+}
+
+func doRateOracleJob() {
+	for {
+		router.UpdateRouteRates()
+		if utils.IsCleanuping() {
+			logWorker("rateoracle", "stop rate oracle job")
+			return
+		}
+		restInJob(restIntervalInRateOracleJob)
+	}
+}
+
+func hasRateOracles() bool {
+	extra := params.GetExtraConfig()
+	if extra == nil {
+		return false
+	}
+	if len(extra.RateOracles) > 0 {
+		return true
+	}
+	for _, c := range extra.LocalChainConfig {
+		if c != nil && c.ReplaceGuard != nil && len(c.ReplaceGuard.PriceOracles) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+var errSwapNotStable = errors.New("swap is not stable")
+
+// GetSwapReceiptMessage get the message signed by swap receipt,
+// the signature is the secp256k1 signature of keccak256(message).
+func GetSwapReceiptMessage(r *mongodb.MgoSwapReceipt) string {
+	var sb strings.Builder
+	sb.WriteString("CrossChain-Router swap receipt\n")
+	fmt.Fprintf(&sb, "identifier: %v\n", r.Identifier)
+	fmt.Fprintf(&sb, "fromChainID: %v\n", r.FromChainID)
+	fmt.Fprintf(&sb, "txid: %v\n", r.TxID)
+	fmt.Fprintf(&sb, "logIndex: %v\n", r.LogIndex)
+	fmt.Fprintf(&sb, "toChainID: %v\n", r.ToChainID)
+	fmt.Fprintf(&sb, "tokenID: %v\n", r.TokenID)
+	fmt.Fprintf(&sb, "from: %v\n", r.From)
+	fmt.Fprintf(&sb, "bind: %v\n", r.Bind)
+	fmt.Fprintf(&sb, "value: %v\n", r.Value)
+	fmt.Fprintf(&sb, "swapValue: %v\n", r.SwapValue)
+	fmt.Fprintf(&sb, "swapFee: %v\n", r.SwapFee)
+	fmt.Fprintf(&sb, "swapTx: %v\n", r.SwapTx)
+	fmt.Fprintf(&sb, "swapHeight: %v\n", r.SwapHeight)
+	fmt.Fprintf(&sb, "timestamp: %v", r.Timestamp)
+	return sb.String()
+}
+
+// IssueSwapReceipt produce and store the signed completion receipt of stable swap
+func IssueSwapReceipt(res *mongodb.MgoSwapResult) (*mongodb.MgoSwapReceipt, error) {
+	if res.Status != mongodb.MatchTxStable {
+		return nil, errSwapNotStable
+	}
+	mpcConfig := mpc.GetMPCConfig(false)
+	if mpcConfig == nil {
+		return nil, errors.New("no mpc config to sign receipt")
+	}
+	receipt := newSwapReceipt(res)
+	msgHash := common.Keccak256Hash([]byte(receipt.Message))
+	signature, signer, err := signHashWithNodeKey(mpcConfig, msgHash[:])
+	if err != nil {
+		return nil, err
+	}
+	receipt.Signer = signer.String()
+	receipt.Signature = common.ToHex(signature)
+
+	err = mongodb.AddSwapReceipt(receipt)
+	if errors.Is(err, mongodb.ErrItemIsDup) {
+		return mongodb.FindSwapReceipt(res.FromChainID, res.TxID, res.LogIndex)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// newSwapReceipt new unsigned receipt of swap result with the message to sign
+func newSwapReceipt(res *mongodb.MgoSwapResult) *mongodb.MgoSwapReceipt {
+	receipt := &mongodb.MgoSwapReceipt{
+		Identifier:  params.GetIdentifier(),
+		FromChainID: res.FromChainID,
+		TxID:        res.TxID,
+		LogIndex:    res.LogIndex,
+		ToChainID:   res.ToChainID,
+		TokenID:     res.GetTokenID(),
+		From:        res.From,
+		Bind:        res.Bind,
+		Value:       res.Value,
+		SwapValue:   res.SwapValue,
+		SwapFee:     res.SwapFee,
+		SwapTx:      res.SwapTx,
+		SwapHeight:  res.SwapHeight,
+		Timestamp:   res.Timestamp,
+	}
+	receipt.Message = GetSwapReceiptMessage(receipt)
+	return receipt
+}
+
+func issueSwapReceiptOnStable(fromChainID, txid string, logIndex int) {
+	res, err := mongodb.FindRouterSwapResult(fromChainID, txid, logIndex)
+	if err == nil {
+		_, err = IssueSwapReceipt(res)
+	}
+	if err != nil {
+		logWorkerError("stable", "issue swap receipt failed", err, "fromChainID", fromChainID, "txid", txid, "logIndex", logIndex)
+	}
+}
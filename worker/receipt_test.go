@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+)
+
+func newTestReceiptSwapResult(status mongodb.SwapStatus) *mongodb.MgoSwapResult {
+	return &mongodb.MgoSwapResult{
+		FromChainID: "1",
+		TxID:        "0xabcd",
+		LogIndex:    2,
+		ToChainID:   "56",
+		From:        "0x1111",
+		Bind:        "0x2222",
+		Value:       "1000",
+		SwapValue:   "990",
+		SwapFee:     "10",
+		SwapTx:      "0xef01",
+		SwapHeight:  100,
+		Timestamp:   1700000000,
+		Status:      status,
+		SwapInfo: mongodb.SwapInfo{
+			ERC20SwapInfo: &mongodb.ERC20SwapInfo{TokenID: "USDC"},
+		},
+	}
+}
+
+func TestNewSwapReceipt(t *testing.T) {
+	res := newTestReceiptSwapResult(mongodb.MatchTxStable)
+	receipt := newSwapReceipt(res)
+	if receipt.TokenID != "USDC" || receipt.SwapTx != res.SwapTx || receipt.SwapFee != res.SwapFee || receipt.Signature != "" {
+		t.Errorf("new swap receipt got %+v", receipt)
+	}
+	if receipt.Message != GetSwapReceiptMessage(receipt) {
+		t.Errorf("receipt message is not the signed message of receipt")
+	}
+	for _, line := range []string{
+		"fromChainID: 1\n", "txid: 0xabcd\n", "logIndex: 2\n", "toChainID: 56\n",
+		"tokenID: USDC\n", "swapValue: 990\n", "swapTx: 0xef01\n", "timestamp: 1700000000",
+	} {
+		if !strings.Contains(receipt.Message, line) {
+			t.Errorf("receipt message %q does not contain %q", receipt.Message, line)
+		}
+	}
+
+	// receipts of different swaps sign different messages
+	other := newTestReceiptSwapResult(mongodb.MatchTxStable)
+	other.SwapValue = "999"
+	if newSwapReceipt(other).Message == receipt.Message {
+		t.Errorf("receipts of different swap values have the same message")
+	}
+}
+
+func TestIssueSwapReceiptNotStable(t *testing.T) {
+	for _, status := range []mongodb.SwapStatus{mongodb.MatchTxNotStable, mongodb.MatchTxFailed, mongodb.TxNotSwapped} {
+		res := newTestReceiptSwapResult(status)
+		if _, err := IssueSwapReceipt(res); !errors.Is(err, errSwapNotStable) {
+			t.Errorf("issue receipt of %v swap got error %v, want %v", status, err, errSwapNotStable)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const maxRecoverSignRequests = int64(1000)
+
+var (
+	recoveryStarter sync.Once
+
+	// swaps updated after the process started are being processed by the live jobs
+	processStartTime = now()
+)
+
+// RecoveryReport report of the startup recovery pass
+type RecoveryReport struct {
+	Checked        int `json:"checked"`
+	StatusRepaired int `json:"statusRepaired"` // swap status left behind the swap result
+	Resigned       int `json:"resigned"`       // signing or broadcasting interrupted, re-signed with the same nonce
+	Resumed        int `json:"resumed"`        // sign request persisted before restart, resumed with its build args
+	Deferred       int `json:"deferred"`       // interrupted but can not be re-signed, left to the timeouts
+	Failed         int `json:"failed"`
+}
+
+// String readable report
+func (r *RecoveryReport) String() string {
+	return fmt.Sprintf("startup recovery: checked %v, status repaired %v, resigned %v, resumed %v, deferred %v, failed %v",
+		r.Checked, r.StatusRepaired, r.Resigned, r.Resumed, r.Deferred, r.Failed)
+}
+
+// StartRecoveryJob run the startup recovery pass in background
+func StartRecoveryJob() {
+	recoveryStarter.Do(func() {
+		logWorker("recovery", "start recovery job")
+		goSupervisedJob("recovery", nil, func() {
+			_ = RecoverHalfCompletedSwaps()
+		})
+	})
+}
+
+// RecoverHalfCompletedSwaps repair swaps left in transient states by a crash.
+//
+// swaps of which the swap status is not updated after the swap result are marked 'TxProcessed'.
+// swaps with nonce assigned (parallel mode) but no swap tx recorded, and swaps with swap tx
+// recorded but neither found on chain or in the tx pool nor sent after signed, are re-signed
+// with the same nonce immediately, instead of waiting the replace job to time out.
+// swaps being signed in non-parallel mode keep status 'TxNotSwapped' and are rebuilt by the swap job.
+// sign requests persisted before restart are resumed first with the persisted build args,
+// so that the result of the sign round is used instead of signing another tx with the same nonce.
+// it runs along with the swap job, swaps and sign requests being processed are left to the swap job,
+// so are the swaps of which the nonce is assigned or the swap result is updated after the process started.
+func RecoverHalfCompletedSwaps() *RecoveryReport {
+	report := &RecoveryReport{}
+	recoverSignRequests(report)
+	septime := getSepTimeInFind(maxReplaceSwapLifetime)
+	results, err := mongodb.FindRouterSwapResultsWithStatus(mongodb.MatchTxNotStable, septime)
+	if err != nil {
+		logWorkerError("recovery", "find swap results failed", err)
+		return report
+	}
+	for _, res := range results {
+		if res.SwapHeight != 0 {
+			continue
+		}
+		report.Checked++
+		recoverSwap(report, res)
+	}
+	logWorker("recovery", report.String())
+	return report
+}
+
+func recoverSwap(report *RecoveryReport, res *mongodb.MgoSwapResult) {
+	ctx := []interface{}{"fromChainID", res.FromChainID, "toChainID", res.ToChainID, "txid", res.TxID, "logIndex", res.LogIndex, "swaptx", res.SwapTx, "swapNonce", res.SwapNonce}
+
+	if swapTasksInQueue.Contains(res.Key) || checkAndUpdateProcessSwapTaskCache(res.Key) != nil {
+		logWorker("recovery", "skip swap being processed", ctx...)
+		return
+	}
+	defer cachedSwapTasks.Remove(res.Key)
+
+	swap, err := mongodb.FindRouterSwap(res.FromChainID, res.TxID, res.LogIndex)
+	if err != nil {
+		report.Failed++
+		logWorkerError("recovery", "find swap failed", err, ctx...)
+		return
+	}
+	if swap.Status == mongodb.TxNotSwapped {
+		err = mongodb.UpdateRouterSwapStatus(res.FromChainID, res.TxID, res.LogIndex, mongodb.TxProcessed, now(), "")
+		if err != nil {
+			report.Failed++
+			logWorkerError("recovery", "repair swap status failed", err, ctx...)
+			return
+		}
+		report.StatusRepaired++
+		logWorker("recovery", "repair swap status to TxProcessed", ctx...)
+		recordSwapEvent(res.FromChainID, res.TxID, res.LogIndex, mongodb.SwapEventRecovered, 0, "status repaired")
+	}
+
+	reason, err := getInterruptedReason(res)
+	if err != nil {
+		report.Failed++
+		logWorkerError("recovery", "check swap tx failed", err, ctx...)
+		return
+	}
+	if reason == "" {
+		return
+	}
+	ctx = append(ctx, "reason", reason)
+	if !router.IsNonceSupported(res.ToChainID) {
+		report.Deferred++
+		logWorkerWarn("recovery", "interrupted swap is left to timeouts", ctx...)
+		return
+	}
+	err = ReplaceRouterSwap(res, nil, false)
+	if err != nil {
+		report.Failed++
+		logWorkerError("recovery", "resign interrupted swap failed", err, ctx...)
+		return
+	}
+	report.Resigned++
+	logWorker("recovery", "resign interrupted swap", ctx...)
+	recordSwapEvent(res.FromChainID, res.TxID, res.LogIndex, mongodb.SwapEventRecovered, 0, reason)
+}
+
+// getInterruptedReason returns why the swap is interrupted, or empty if it is not.
+// the nonce of swap which is assigned but not signed is set from the nonce assigned event.
+func getInterruptedReason(res *mongodb.MgoSwapResult) (string, error) {
+	if res.SwapTx == "" {
+		events, err := mongodb.FindSwapEvents(res.FromChainID, res.TxID, res.LogIndex)
+		if err != nil {
+			return "", err
+		}
+		nonce, assignTime, assigned := getAssignedNonce(events)
+		if !assigned || assignTime >= processStartTime*1000 {
+			return "", nil
+		}
+		res.SwapNonce = nonce
+		return "nonce assigned but not signed", nil
+	}
+	if res.Timestamp >= processStartTime {
+		return "", nil // signed by the live swap job
+	}
+	resBridge := router.GetBridgeByChainID(res.ToChainID)
+	if resBridge == nil {
+		return "", tokens.ErrNoBridgeForChainID
+	}
+	if txStatus := getSwapTxStatus(resBridge, res); txStatus != nil && txStatus.BlockHeight > 0 {
+		return "", nil // left to the stable job
+	}
+	if tx, err := resBridge.GetTransaction(res.SwapTx); err == nil && tx != nil {
+		return "", nil // in tx pool
+	}
+	events, err := mongodb.FindSwapEvents(res.FromChainID, res.TxID, res.LogIndex)
+	if err != nil {
+		return "", err
+	}
+	for _, event := range events {
+		if event.Event == mongodb.SwapEventSent && strings.EqualFold(event.Detail, res.SwapTx) {
+			return "", nil // broadcasted, left to the replace job if dropped
+		}
+	}
+	return "signed but not sent", nil
+}
+
+// getAssignedNonce get the swap nonce and the time (in milli seconds) of the last nonce assigned event,
+// the event is the marker of nonce assignment as zero is a valid nonce.
+func getAssignedNonce(events []*mongodb.MgoSwapEvent) (nonce uint64, assignTime int64, assigned bool) {
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Event != mongodb.SwapEventNonceAssigned {
+			continue
+		}
+		nonce, err := strconv.ParseUint(events[i].Detail, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		return nonce, events[i].Timestamp, true
+	}
+	return 0, 0, false
+}
+
+// recoverSignRequests resume the persisted sign requests of swaps
+func recoverSignRequests(report *RecoveryReport) {
+	reqs, err := mongodb.FindSwapSignRequests(maxRecoverSignRequests)
+	if err != nil {
+		logWorkerError("recovery", "find sign requests failed", err)
+		return
+	}
+	for _, req := range reqs {
+		report.Checked++
+		resumed, err := recoverSignRequest(req)
+		switch {
+		case err != nil:
+			report.Failed++
+			logWorkerError("recovery", "resume sign request failed", err, "key", req.Key, "keyID", req.KeyID)
+		case resumed:
+			report.Resumed++
+		}
+	}
+}
+
+// recoverSignRequest resume the sign request unless its swap is being processed by the swap job,
+// which resumes the sign request itself if it signs the same nonce again.
+func recoverSignRequest(req *mongodb.MgoSignRequest) (resumed bool, err error) {
+	if req.SwapKey == "" {
+		return resumeSignRequest(req)
+	}
+	if err = checkAndUpdateProcessSwapTaskCache(req.SwapKey); err != nil {
+		logWorker("recovery", "skip sign request of swap being processed", "key", req.Key, "keyID", req.KeyID, "swapKey", req.SwapKey)
+		return false, nil
+	}
+	defer cachedSwapTasks.Remove(req.SwapKey)
+	return resumeSignRequest(req)
+}
+
+// resumeSwapSignRequest resume the sign request of the same swap nonce,
+// which is signed with other msgs before restart.
+func resumeSwapSignRequest(args *tokens.BuildTxArgs) {
+	swapKey := mongodb.GetRouterSwapKey(args.FromChainID.String(), args.SwapID, args.LogIndex)
+	req, err := mongodb.FindSignRequest(mongodb.GetSignRequestKey(swapKey, args.GetTxNonce(), "", nil))
+	if err == nil {
+		_, err = resumeSignRequest(req)
+	}
+	if err != nil {
+		logWorkerError("recovery", "resume sign request failed", err, "swapKey", swapKey, "nonce", args.GetTxNonce())
+	}
+}
+
+// resumeSignRequest rebuild the tx of the persisted sign request from the stored swap
+// and its build args, get the sign result (of the pending sign round) and send the tx.
+func resumeSignRequest(req *mongodb.MgoSignRequest) (resumed bool, err error) {
+	if len(req.MsgContext) == 0 {
+		return false, errWrongMsgContext
+	}
+	var ctxArgs tokens.BuildTxArgs
+	if err = json.Unmarshal([]byte(req.MsgContext[0]), &ctxArgs); err != nil {
+		return false, errWrongMsgContext
+	}
+	ctx := []interface{}{"key", req.Key, "keyID", req.KeyID, "swapType", ctxArgs.SwapType.String(), "swapID", ctxArgs.SwapID, "logIndex", ctxArgs.LogIndex, "nonce", req.SwapNonce}
+	if isInternalTxType(ctxArgs.SwapType) || ctxArgs.FromChainID == nil || ctxArgs.ToChainID == nil {
+		// internal txs are dispatched again by their jobs
+		logWorker("recovery", "drop sign request of internal tx", ctx...)
+		return false, mongodb.RemoveSignRequest(req.Key, req.KeyID)
+	}
+	fromChainID := ctxArgs.FromChainID.String()
+	res, err := mongodb.FindRouterSwapResult(fromChainID, ctxArgs.SwapID, ctxArgs.LogIndex)
+	if err != nil {
+		return false, err
+	}
+	if res.SwapHeight != 0 || (res.SwapTx != "" && res.SwapNonce != req.SwapNonce) {
+		logWorker("recovery", "drop sign request of swap which is superseded", ctx...)
+		return false, mongodb.RemoveSignRequest(req.Key, req.KeyID)
+	}
+	resBridge := router.GetBridgeByChainID(res.ToChainID)
+	if resBridge == nil {
+		return false, tokens.ErrNoBridgeForChainID
+	}
+	args, err := getStoredSwapBuildArgs(&ctxArgs)
+	if err != nil {
+		return false, err
+	}
+	args.SignExpiry = ctxArgs.SignExpiry
+	rawTx, err := resBridge.BuildRawTransaction(args)
+	if err != nil {
+		return false, err
+	}
+	if err = resBridge.VerifyMsgHash(rawTx, req.MsgHash); err != nil {
+		logWorkerError("recovery", "drop sign request which can not be rebuilt", err, ctx...)
+		return false, mongodb.RemoveSignRequest(req.Key, req.KeyID)
+	}
+	// the sign round is resumed as the sign request key and msgs are the same
+	signedTx, txHash, err := mpcSignTransaction(resBridge, rawTx, args)
+	if err != nil {
+		return false, err
+	}
+	ctx = append(ctx, "txHash", txHash)
+	if res.SwapTx == "" {
+		err = updateRouterSwapResult(fromChainID, res.TxID, res.LogIndex, &MatchTx{
+			SwapTx:             txHash,
+			SwapNonce:          args.GetTxNonce(),
+			SwapValue:          args.SwapValue.String(),
+			MPC:                args.From,
+			DeliveryFeePercent: args.DeliveryFeePercent,
+		})
+		if err == nil {
+			err = mongodb.UpdateRouterSwapStatus(fromChainID, res.TxID, res.LogIndex, mongodb.TxProcessed, now(), "")
+		}
+	} else if !strings.EqualFold(res.SwapTx, txHash) {
+		err = mongodb.UpdateRouterOldSwapTxs(fromChainID, res.TxID, res.LogIndex, txHash)
+	}
+	if err != nil {
+		return false, err
+	}
+	recordSwapEvent(fromChainID, res.TxID, res.LogIndex, mongodb.SwapEventRecovered, 0, "sign request resumed")
+	logWorker("recovery", "resume sign request", ctx...)
+	_, err = sendSignedTransaction(resBridge, signedTx, args)
+	return true, err
+}
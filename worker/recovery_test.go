@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestGetAssignedNonce(t *testing.T) {
+	newEvent := func(event, detail string, timestamp int64) *mongodb.MgoSwapEvent {
+		return &mongodb.MgoSwapEvent{Event: event, Detail: detail, Timestamp: timestamp}
+	}
+	tests := []struct {
+		events     []*mongodb.MgoSwapEvent
+		nonce      uint64
+		assignTime int64
+		assigned   bool
+	}{
+		{nil, 0, 0, false},
+		{[]*mongodb.MgoSwapEvent{newEvent(mongodb.SwapEventSendFailed, "", 1000)}, 0, 0, false},
+		// zero is a valid nonce
+		{[]*mongodb.MgoSwapEvent{newEvent(mongodb.SwapEventNonceAssigned, "0", 1000)}, 0, 1000, true},
+		// the last assigned nonce is used
+		{[]*mongodb.MgoSwapEvent{
+			newEvent(mongodb.SwapEventNonceAssigned, "5", 1000),
+			newEvent(mongodb.SwapEventSendFailed, "", 2000),
+			newEvent(mongodb.SwapEventNonceAssigned, "7", 3000),
+		}, 7, 3000, true},
+		{[]*mongodb.MgoSwapEvent{newEvent(mongodb.SwapEventNonceAssigned, "bad", 1000)}, 0, 0, false},
+	}
+	for i, test := range tests {
+		nonce, assignTime, assigned := getAssignedNonce(test.events)
+		if nonce != test.nonce || assignTime != test.assignTime || assigned != test.assigned {
+			t.Errorf("test %v: got (%v, %v, %v), want (%v, %v, %v)", i, nonce, assignTime, assigned, test.nonce, test.assignTime, test.assigned)
+		}
+	}
+}
+
+func TestRecoverSwapBeingProcessed(t *testing.T) {
+	const swapKey = "1:0x01:0"
+	res := &mongodb.MgoSwapResult{Key: swapKey, FromChainID: "1", TxID: "0x01", ToChainID: "56"}
+
+	// dispatched to the swap task queue
+	swapTasksInQueue.Add(swapKey)
+	report := &RecoveryReport{}
+	recoverSwap(report, res)
+	swapTasksInQueue.Remove(swapKey)
+	if *report != (RecoveryReport{}) {
+		t.Errorf("recover swap in task queue got report %+v", report)
+	}
+
+	// being processed (or just processed) by the swap job, the swap task cache is kept
+	if err := checkAndUpdateProcessSwapTaskCache(swapKey); err != nil {
+		t.Fatalf("add swap task cache failed: %v", err)
+	}
+	defer cachedSwapTasks.Remove(swapKey)
+	recoverSwap(report, res)
+	if *report != (RecoveryReport{}) {
+		t.Errorf("recover swap being processed got report %+v", report)
+	}
+	if !cachedSwapTasks.Contains(swapKey) {
+		t.Errorf("swap task cache of swap being processed is removed")
+	}
+}
+
+func TestInterruptedReasonOfSwapSignedByLiveJob(t *testing.T) {
+	// signed after the process started, the bridge is not queried
+	res := &mongodb.MgoSwapResult{FromChainID: "1", TxID: "0x01", ToChainID: "unknown", SwapTx: "0x02", Timestamp: now()}
+	if reason, err := getInterruptedReason(res); reason != "" || err != nil {
+		t.Errorf("interrupted reason of swap signed by live job got (%v, %v)", reason, err)
+	}
+	res.Timestamp = processStartTime - 1
+	if _, err := getInterruptedReason(res); err != tokens.ErrNoBridgeForChainID {
+		t.Errorf("swap signed before the process started is not checked, got error %v", err)
+	}
+}
+
+func TestRecoverSignRequestOfSwapBeingProcessed(t *testing.T) {
+	const swapKey = "1:0x01:0"
+	if err := checkAndUpdateProcessSwapTaskCache(swapKey); err != nil {
+		t.Fatalf("add swap task cache failed: %v", err)
+	}
+	defer cachedSwapTasks.Remove(swapKey)
+
+	// left to the swap job, and the swap task cache is kept
+	req := &mongodb.MgoSignRequest{Key: swapKey + ":3", SwapKey: swapKey}
+	if resumed, err := recoverSignRequest(req); resumed || err != nil {
+		t.Errorf("recover sign request got (%v, %v), want skipped", resumed, err)
+	}
+	if !cachedSwapTasks.Contains(swapKey) {
+		t.Errorf("swap task cache of swap being processed is removed")
+	}
+}
@@ -43,7 +43,7 @@ func StartReplaceJob() {
 	}
 
 	// start producer
-	go startReplaceProducer()
+	goSupervisedJob("replace", nil, startReplaceProducer)
 }
 
 func startReplaceProducer() {
@@ -107,9 +107,6 @@ func dispatchSwapResultToReplace(res *mongodb.MgoSwapResult) error {
 		checkAndRecycleSwapNonce(res)
 		return nil
 	}
-	if res.SwapTx != "" && getSepTimeInFind(waitTimeToReplace) < res.Timestamp {
-		return nil
-	}
 	if !router.IsNonceSupported(res.ToChainID) {
 		return nil
 	}
@@ -117,6 +114,11 @@ func dispatchSwapResultToReplace(res *mongodb.MgoSwapResult) error {
 	if resBridge == nil {
 		return tokens.ErrNoBridgeForChainID
 	}
+	// expired swap tx will never be included, replace it without waiting
+	if res.SwapTx != "" && getSepTimeInFind(waitTimeToReplace) < res.Timestamp &&
+		!isSwapTxExpired(resBridge, res) {
+		return nil
+	}
 	nonceSetter, ok := resBridge.(tokens.NonceSetter)
 	if !ok {
 		return nil
@@ -141,8 +143,7 @@ func dispatchSwapResultToReplace(res *mongodb.MgoSwapResult) error {
 		// init replace task queue and start consumer routine
 		taskQueue = fifo.NewQueue()
 		replaceTaskQueues[chainID] = taskQueue
-		mongodb.MgoWaitGroup.Add(1)
-		go startReplaceConsumer(chainID)
+		goSupervisedJob("doReplace:"+chainID, mongodb.MgoWaitGroup, func() { startReplaceConsumer(chainID) })
 	}
 
 	logWorker("replace", "dispatch replace router swap task", "fromChainID", res.FromChainID, "toChainID", res.ToChainID, "txid", res.TxID, "logIndex", res.LogIndex, "value", res.SwapValue, "swapNonce", res.SwapNonce, "queue", taskQueue.Len())
@@ -154,17 +155,17 @@ func dispatchSwapResultToReplace(res *mongodb.MgoSwapResult) error {
 }
 
 func checkAndRecycleSwapNonce(res *mongodb.MgoSwapResult) {
-	if !params.IsParallelSwapEnabled() {
-		return
-	}
-	_, err := verifyReplaceSwap(res, false)
-	if err != nil {
+	if !params.IsParallelSwapEnabledOnChain(res.ToChainID) {
 		return
 	}
 	resBridge := router.GetBridgeByChainID(res.ToChainID)
 	if resBridge == nil {
 		return
 	}
+	_, err := verifyReplaceSwap(resBridge, res, false, isSwapTxExpired(resBridge, res))
+	if err != nil {
+		return
+	}
 	nonceSetter, ok := resBridge.(tokens.NonceSetter)
 	if !ok {
 		return
@@ -177,7 +178,6 @@ func checkAndRecycleSwapNonce(res *mongodb.MgoSwapResult) {
 }
 
 func startReplaceConsumer(chainID string) {
-	defer mongodb.MgoWaitGroup.Done()
 	logWorker("replace", "start replace swap task", "chainID", chainID)
 
 	taskQueue, exist := replaceTaskQueues[chainID]
@@ -210,15 +210,20 @@ func startReplaceConsumer(chainID string) {
 			continue
 		}
 
-		ctx := []interface{}{"fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex}
-		err := ReplaceRouterSwap(swap, nil, false)
-		if err == nil {
-			logWorker("doReplace", "replace router swap success", ctx...)
-		} else {
-			logWorkerError("doReplace", "replace router swap failed", err, ctx...)
-		}
+		processReplaceTask(swap)
+	}
+}
+
+func processReplaceTask(swap *mongodb.MgoSwapResult) {
+	// remove from queue even if panics, so that it can be dispatched again
+	defer replaceTasksInQueue.Remove(swap.Key)
 
-		replaceTasksInQueue.Remove(swap.Key)
+	ctx := []interface{}{"fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex}
+	err := ReplaceRouterSwap(swap, nil, false)
+	if err == nil {
+		logWorker("doReplace", "replace router swap success", ctx...)
+	} else {
+		logWorkerError("doReplace", "replace router swap failed", err, ctx...)
 	}
 }
 
@@ -227,15 +232,16 @@ func ReplaceRouterSwap(res *mongodb.MgoSwapResult, gasPrice *big.Int, isManual b
 	if !router.IsNonceSupported(res.ToChainID) {
 		return tokens.ErrNonceNotSupport
 	}
-	swap, err := verifyReplaceSwap(res, isManual)
-	if err != nil {
-		return err
-	}
-
 	resBridge := router.GetBridgeByChainID(res.ToChainID)
 	if resBridge == nil {
 		return tokens.ErrNoBridgeForChainID
 	}
+	// check expiry only once, the swap tx may expire in the meantime
+	expired := isSwapTxExpired(resBridge, res)
+	swap, err := verifyReplaceSwap(resBridge, res, isManual, expired)
+	if err != nil {
+		return err
+	}
 	routerMPC, err := router.GetRouterMPC(swap.GetTokenID(), res.ToChainID)
 	if err != nil {
 		return err
@@ -254,6 +260,12 @@ func ReplaceRouterSwap(res *mongodb.MgoSwapResult, gasPrice *big.Int, isManual b
 
 	txid := res.TxID
 	nonce := res.SwapNonce
+	if expired {
+		nonce, err = getFreshSwapNonce(resBridge, res)
+		if err != nil {
+			return err
+		}
+	}
 	replaceNum := uint64(len(res.OldSwapTxs))
 	if replaceNum == 0 {
 		replaceNum++
@@ -261,6 +273,7 @@ func ReplaceRouterSwap(res *mongodb.MgoSwapResult, gasPrice *big.Int, isManual b
 	args := &tokens.BuildTxArgs{
 		SwapArgs: tokens.SwapArgs{
 			Identifier:  params.GetIdentifier(),
+			Salt:        params.GetDeploymentSalt(),
 			SwapID:      txid,
 			SwapType:    tokens.SwapType(res.SwapType),
 			Bind:        res.Bind,
@@ -282,21 +295,37 @@ func ReplaceRouterSwap(res *mongodb.MgoSwapResult, gasPrice *big.Int, isManual b
 	if err != nil {
 		return err
 	}
+	args.ConversionRate, err = router.GetRouteRate(args.GetTokenID(), res.FromChainID, res.ToChainID)
+	if err != nil {
+		return err
+	}
+	args.DeliveryFeePercent = res.DeliveryFeePercent // keep the fees of the swap tx to replace
 	rawTx, err := resBridge.BuildRawTransaction(args)
 	if err != nil {
 		logWorkerError("replaceSwap", "build tx failed", err, "chainID", res.ToChainID, "txid", txid, "logIndex", res.LogIndex)
 		return err
 	}
-	go signAndSendReplaceTx(resBridge, rawTx, args, res)
+	if !isManual {
+		rawTx, err = guardReplaceCost(resBridge, res, args, rawTx)
+		if err != nil {
+			return err
+		}
+	}
+	goSafeTask("doReplace:"+res.ToChainID, func() {
+		signAndSendReplaceTx(resBridge, rawTx, args, res)
+	})
 	return nil
 }
 
 func signAndSendReplaceTx(resBridge tokens.IBridge, rawTx interface{}, args *tokens.BuildTxArgs, res *mongodb.MgoSwapResult) {
-	signedTx, txHash, err := resBridge.MPCSignTransaction(rawTx, args)
+	signedTx, txHash, err := mpcSignTransaction(resBridge, rawTx, args)
 	if err != nil {
 		logWorkerError("replaceSwap", "mpc sign tx failed", err, "fromChainID", res.FromChainID, "toChainID", res.ToChainID, "txid", res.TxID, "nonce", res.SwapNonce, "logIndex", res.LogIndex)
-		if errors.Is(err, mpc.ErrGetSignStatusHasDisagree) {
+		switch {
+		case errors.Is(err, mpc.ErrGetSignStatusHasDisagree):
 			reverifySwap(args)
+		case errors.Is(err, mpc.ErrNonceSignedWithOtherMsgs):
+			resumeSwapSignRequest(args)
 		}
 		return
 	}
@@ -313,16 +342,31 @@ func signAndSendReplaceTx(resBridge tokens.IBridge, rawTx interface{}, args *tok
 		return
 	}
 
+	recordSwapEvent(fromChainID, txid, logIndex, mongodb.SwapEventReplaced, 0, txHash)
+
 	sentTxHash, err := sendSignedTransaction(resBridge, signedTx, args)
-	if err == nil && txHash != sentTxHash {
+	if err != nil {
+		return
+	}
+	if sentTxHash != "" && txHash != sentTxHash {
 		logWorkerError("replaceSwap", "send tx success but with different hash", errSendTxWithDiffHash,
 			"fromChainID", fromChainID, "toChainID", res.ToChainID, "txid", txid, "nonce", res.SwapNonce,
 			"logIndex", logIndex, "txHash", txHash, "sentTxHash", sentTxHash)
 		_ = mongodb.UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, sentTxHash)
 	}
+
+	// record the fresh nonce (and ttl) only after the replacement is sent,
+	// otherwise the swap keeps its nonce and no nonce gap is left.
+	var ttl uint64
+	if args.Extra.TTL != nil {
+		ttl = *args.Extra.TTL
+	}
+	if nonce := args.GetTxNonce(); nonce != res.SwapNonce || ttl != res.TTL {
+		_ = mongodb.UpdateRouterSwapResultNonce(fromChainID, txid, logIndex, nonce, ttl)
+	}
 }
 
-func verifyReplaceSwap(res *mongodb.MgoSwapResult, isManual bool) (*mongodb.MgoSwap, error) {
+func verifyReplaceSwap(resBridge tokens.IBridge, res *mongodb.MgoSwapResult, isManual, expired bool) (*mongodb.MgoSwap, error) {
 	fromChainID, txid, logIndex := res.FromChainID, res.TxID, res.LogIndex
 	swap, err := mongodb.FindRouterSwap(fromChainID, txid, logIndex)
 	if err != nil {
@@ -339,7 +383,7 @@ func verifyReplaceSwap(res *mongodb.MgoSwapResult, isManual bool) (*mongodb.MgoS
 		return nil, fmt.Errorf("cannot replace swap with status not equal to 'TxProcessed'")
 	}
 
-	if res.SwapTx == "" && !params.IsParallelSwapEnabled() {
+	if res.SwapTx == "" && !params.IsParallelSwapEnabledOnChain(res.ToChainID) {
 		return nil, errors.New("swap without swaptx")
 	}
 	if res.SwapNonce == 0 && !isManual {
@@ -351,11 +395,7 @@ func verifyReplaceSwap(res *mongodb.MgoSwapResult, isManual bool) (*mongodb.MgoS
 	if res.SwapHeight != 0 && !isManual {
 		return nil, errors.New("swaptx with block height")
 	}
-	resBridge := router.GetBridgeByChainID(res.ToChainID)
-	if resBridge == nil {
-		return nil, tokens.ErrNoBridgeForChainID
-	}
-	err = checkIfSwapNonceHasPassed(resBridge, res, true)
+	err = checkIfSwapNonceHasPassed(resBridge, res, true, expired)
 	if err != nil {
 		return nil, err
 	}
@@ -363,7 +403,7 @@ func verifyReplaceSwap(res *mongodb.MgoSwapResult, isManual bool) (*mongodb.MgoS
 }
 
 //nolint:gocyclo // ok
-func checkIfSwapNonceHasPassed(bridge tokens.IBridge, res *mongodb.MgoSwapResult, isReplace bool) error {
+func checkIfSwapNonceHasPassed(bridge tokens.IBridge, res *mongodb.MgoSwapResult, isReplace, expired bool) error {
 	nonceSetter, ok := bridge.(tokens.NonceSetter)
 	if !ok {
 		return nil
@@ -379,6 +419,11 @@ func checkIfSwapNonceHasPassed(bridge tokens.IBridge, res *mongodb.MgoSwapResult
 		}
 		return nil
 	}
+	if expired {
+		// not failed, the replace job will resend it with a fresh nonce
+		logWorker("replace", "swap tx is expired", "fromChainID", res.FromChainID, "txid", res.TxID, "logIndex", res.LogIndex, "swaptx", res.SwapTx, "swapNonce", res.SwapNonce, "ttl", res.TTL)
+		return nil
+	}
 	if nonce > res.SwapNonce && res.SwapNonce > 0 {
 		var iden string
 		if isReplace {
@@ -419,3 +464,44 @@ func checkIfSwapNonceHasPassed(bridge tokens.IBridge, res *mongodb.MgoSwapResult
 	}
 	return nil
 }
+
+// isSwapTxExpired check if the swap tx will never be included
+// (its last valid height recorded in ttl has passed, eg. ripple LastLedgerSequence)
+func isSwapTxExpired(bridge tokens.IBridge, res *mongodb.MgoSwapResult) bool {
+	checker, ok := bridge.(tokens.TxExpiryChecker)
+	if !ok || res.SwapTx == "" || res.TTL == 0 {
+		return false
+	}
+	expired, err := checker.IsTxExpired(res.SwapTx, res.TTL)
+	if err != nil {
+		logWorkerTrace("replace", "check swap tx expiry failed", "toChainID", res.ToChainID, "swaptx", res.SwapTx, "ttl", res.TTL, "err", err)
+		return false
+	}
+	return expired
+}
+
+// getFreshSwapNonce get nonce of replacing an expired swap tx.
+// the expired tx does not consume its nonce, so the nonce is reused
+// unless it has been consumed by another tx in the meantime.
+// the fresh nonce is only taken here, the next nonce of the mpc is advanced
+// by sending the replacement and the swap records it after sent (see signAndSendReplaceTx),
+// so that failures of building, guarding, signing or sending leave no nonce gap.
+func getFreshSwapNonce(bridge tokens.IBridge, res *mongodb.MgoSwapResult) (uint64, error) {
+	nonceSetter, ok := bridge.(tokens.NonceSetter)
+	if !ok {
+		return res.SwapNonce, nil
+	}
+	nonce, err := nonceSetter.GetPoolNonce(res.MPC, "latest")
+	if err != nil {
+		return 0, fmt.Errorf("get router mpc nonce failed, %w", err)
+	}
+	if nonce <= res.SwapNonce {
+		return res.SwapNonce, nil
+	}
+	if params.IsParallelSwapEnabledOnChain(res.ToChainID) {
+		return 0, fmt.Errorf("nonce (%v) of expired swap tx is consumed in parallel mode", res.SwapNonce)
+	}
+	nonce = nonceSetter.AdjustNonce(res.MPC, nonce)
+	logWorker("replace", "use fresh nonce for expired swap tx", "fromChainID", res.FromChainID, "txid", res.TxID, "logIndex", res.LogIndex, "swapNonce", res.SwapNonce, "freshNonce", nonce)
+	return nonce, nil
+}
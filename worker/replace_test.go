@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testNonceBridge struct {
+	tokens.IBridge
+	tokens.NonceSetter
+	poolNonce  uint64
+	localNonce uint64
+	setNonces  []uint64
+}
+
+func (b *testNonceBridge) GetPoolNonce(address, height string) (uint64, error) {
+	return b.poolNonce, nil
+}
+
+func (b *testNonceBridge) AdjustNonce(address string, value uint64) uint64 {
+	if b.localNonce > value {
+		return b.localNonce
+	}
+	return value
+}
+
+func (b *testNonceBridge) SetNonce(address string, value uint64) {
+	b.setNonces = append(b.setNonces, value)
+}
+
+func TestGetFreshSwapNonce(t *testing.T) {
+	res := &mongodb.MgoSwapResult{FromChainID: "1", TxID: "0x01", ToChainID: "56", MPC: "0x1111111111111111111111111111111111111111", SwapNonce: 5}
+	tests := []struct {
+		poolNonce, localNonce uint64
+		want                  uint64
+	}{
+		// the nonce of the expired tx is not consumed
+		{5, 8, 5},
+		{3, 8, 5},
+		// consumed by another tx, take the next nonce
+		{6, 0, 6},
+		{6, 8, 8},
+	}
+	for i, test := range tests {
+		bridge := &testNonceBridge{poolNonce: test.poolNonce, localNonce: test.localNonce}
+		nonce, err := getFreshSwapNonce(bridge, res)
+		if err != nil || nonce != test.want {
+			t.Errorf("test %v: fresh swap nonce got (%v, %v), want %v", i, nonce, err, test.want)
+		}
+		// the next nonce is advanced by sending the replacement
+		if len(bridge.setNonces) != 0 {
+			t.Errorf("test %v: fresh swap nonce set next nonce %v before sent", i, bridge.setNonces)
+		}
+	}
+}
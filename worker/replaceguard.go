@@ -0,0 +1,156 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// swaps flagged by replace guard, key is swap key, value is the sent swap tx
+var replaceGuardedSwaps sync.Map
+
+var errReplaceCostExceeded = errors.New("replace cost exceeds limit")
+
+// guardReplaceCost compare the projected additional cost of the replacement
+// with the swap fee and value (see params.ReplaceGuardConfig).
+// returns the raw tx to send (rebuilt if the fee is capped), or an error if skipped.
+func guardReplaceCost(bridge tokens.IBridge, res *mongodb.MgoSwapResult, args *tokens.BuildTxArgs, rawTx interface{}) (interface{}, error) {
+	cfg := params.GetReplaceGuardConfig(res.ToChainID)
+	if cfg == nil || res.SwapTx == "" || args.ERC20SwapInfo == nil {
+		return rawTx, nil
+	}
+	estimator, ok := bridge.(tokens.ReplaceCostEstimator)
+	if !ok {
+		return rawTx, nil
+	}
+	ctx := []interface{}{"fromChainID", res.FromChainID, "toChainID", res.ToChainID, "txid", res.TxID, "logIndex", res.LogIndex, "swapTx", res.SwapTx}
+	price := getReplaceGuardPrice(cfg, res.ToChainID, args.GetTokenID(), ctx)
+	if price == nil {
+		return rawTx, nil
+	}
+
+	sentFee, err := estimator.GetSentTxMaxFee(res.SwapTx)
+	if err != nil {
+		// the sent tx is dropped, there is no gas to bump
+		logWorkerTrace("replaceguard", "get sent tx fee failed", append(ctx, "err", err)...)
+		return rawTx, nil
+	}
+	newFee := estimator.GetBuiltTxMaxFee(args)
+	if newFee == nil || newFee.Cmp(sentFee) <= 0 {
+		return rawTx, nil
+	}
+	additional := new(big.Int).Sub(newFee, sentFee)
+
+	decimals, swapFee, err := getSwapFeeRevenue(args)
+	if err != nil {
+		logWorkerWarn("replaceguard", "get swap fee failed", append(ctx, "err", err)...)
+		return rawTx, nil
+	}
+	limit := getReplaceCostLimit(cfg, swapFee, args.OriginValue)
+	cost := coinToTokenValue(additional, price, cfg.GetCoinDecimals(), decimals)
+	if cost.Cmp(limit) <= 0 {
+		replaceGuardedSwaps.Delete(res.Key)
+		return rawTx, nil
+	}
+
+	detail := fmt.Sprintf("additional fee %v (%v in token) exceeds limit %v of swap fee %v and value %v", additional, cost, limit, swapFee, args.OriginValue)
+	if cfg.CapFee {
+		maxFee := new(big.Int).Add(sentFee, tokenToCoinValue(limit, price, decimals, cfg.GetCoinDecimals()))
+		err = estimator.CapReplaceTxFee(args, res.SwapTx, maxFee)
+		if err == nil {
+			rawTx, err = bridge.BuildRawTransaction(args)
+		}
+		if err == nil {
+			logWorker("replaceguard", "cap replace tx fee", append(ctx, "sentFee", sentFee, "fee", newFee, "maxFee", maxFee)...)
+			flagReplaceGuardedSwap(res, detail+fmt.Sprintf(", capped max fee to %v", maxFee))
+			return rawTx, nil
+		}
+		detail += ", cap fee failed: " + err.Error()
+	}
+	flagReplaceGuardedSwap(res, detail)
+	return nil, fmt.Errorf("%w: %v", errReplaceCostExceeded, detail)
+}
+
+// getReplaceGuardPrice get price of one whole fee coin in whole tokens from the price oracle,
+// the configured price is used only if the oracle is not configured or unavailable.
+func getReplaceGuardPrice(cfg *params.ReplaceGuardConfig, chainID, tokenID string, ctx []interface{}) *big.Float {
+	rate, err := router.GetCoinPrice(chainID, tokenID)
+	if err != nil {
+		logWorkerWarn("replaceguard", "price oracle is unavailable, use the configured price", append(ctx, "tokenID", tokenID, "err", err)...)
+	}
+	if rate != nil {
+		price := new(big.Float).SetInt(rate)
+		return price.Quo(price, big.NewFloat(1e18))
+	}
+	return cfg.GetCoinPrice(tokenID)
+}
+
+// flagReplaceGuardedSwap record replace guard event once per sent swap tx for manual decision
+func flagReplaceGuardedSwap(res *mongodb.MgoSwapResult, detail string) {
+	if prev, exist := replaceGuardedSwaps.Load(res.Key); exist && prev.(string) == res.SwapTx {
+		return
+	}
+	replaceGuardedSwaps.Store(res.Key, res.SwapTx)
+	recordSwapEvent(res.FromChainID, res.TxID, res.LogIndex, mongodb.SwapEventReplaceGuard, 0, detail)
+	logWorkerWarn("replaceguard", "replace swap is guarded, need manual decision", "fromChainID", res.FromChainID, "toChainID", res.ToChainID, "txid", res.TxID, "logIndex", res.LogIndex, "swapTx", res.SwapTx, "detail", detail)
+}
+
+// getSwapFeeRevenue get swap fee in the smallest unit of the source token
+func getSwapFeeRevenue(args *tokens.BuildTxArgs) (decimals uint8, swapFee *big.Int, err error) {
+	tokenID := args.GetTokenID()
+	fromChainID, toChainID := args.FromChainID.String(), args.ToChainID.String()
+	srcBridge := router.GetBridgeByChainID(fromChainID)
+	dstBridge := router.GetBridgeByChainID(toChainID)
+	if srcBridge == nil || dstBridge == nil {
+		return 0, nil, tokens.ErrNoBridgeForChainID
+	}
+	fromTokenCfg := srcBridge.GetTokenConfig(args.ERC20SwapInfo.Token)
+	toTokenCfg := dstBridge.GetTokenConfig(router.GetCachedMultichainToken(tokenID, toChainID))
+	if fromTokenCfg == nil || toTokenCfg == nil || args.OriginValue == nil {
+		return 0, nil, tokens.ErrMissTokenConfig
+	}
+	receiveValue := args.CalcSwapValue(tokenID, fromChainID, toChainID, args.OriginValue,
+		fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo)
+	swapFee = new(big.Int).Sub(args.OriginValue,
+		tokens.ConvertTokenValue(receiveValue, toTokenCfg.Decimals, fromTokenCfg.Decimals))
+	return fromTokenCfg.Decimals, swapFee, nil
+}
+
+// getReplaceCostLimit get the lower of the fee and value limits
+func getReplaceCostLimit(cfg *params.ReplaceGuardConfig, swapFee, value *big.Int) *big.Int {
+	var limit *big.Int
+	if cfg.MaxFeeMultiple > 0 {
+		limit = new(big.Int).Mul(swapFee, new(big.Int).SetUint64(cfg.MaxFeeMultiple))
+	}
+	if cfg.MaxValuePercent > 0 {
+		valueLimit := new(big.Int).Mul(value, new(big.Int).SetUint64(cfg.MaxValuePercent))
+		valueLimit.Div(valueLimit, big.NewInt(100))
+		if limit == nil || valueLimit.Cmp(limit) < 0 {
+			limit = valueLimit
+		}
+	}
+	if limit.Sign() < 0 {
+		limit.SetInt64(0)
+	}
+	return limit
+}
+
+// coinToTokenValue value fee coin amount in token with price of one whole coin in whole tokens
+func coinToTokenValue(amount *big.Int, price *big.Float, coinDecimals, tokenDecimals uint8) *big.Int {
+	value := new(big.Float).Mul(new(big.Float).SetInt(amount), price)
+	result, _ := value.Int(nil)
+	return tokens.ConvertTokenValue(result, coinDecimals, tokenDecimals)
+}
+
+// tokenToCoinValue value token amount in fee coin with price of one whole coin in whole tokens
+func tokenToCoinValue(amount *big.Int, price *big.Float, tokenDecimals, coinDecimals uint8) *big.Int {
+	value := new(big.Float).SetInt(tokens.ConvertTokenValue(amount, tokenDecimals, coinDecimals))
+	result, _ := value.Quo(value, price).Int(nil)
+	return result
+}
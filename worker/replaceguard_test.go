@@ -0,0 +1,154 @@
+package worker
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testReplaceCostBridge struct {
+	tokens.IBridge
+	sentFee  *big.Int
+	builtFee *big.Int
+}
+
+func (b *testReplaceCostBridge) GetSentTxMaxFee(txHash string) (*big.Int, error) {
+	return b.sentFee, nil
+}
+
+func (b *testReplaceCostBridge) GetBuiltTxMaxFee(args *tokens.BuildTxArgs) *big.Int {
+	return b.builtFee
+}
+
+func (b *testReplaceCostBridge) CapReplaceTxFee(args *tokens.BuildTxArgs, sentTxHash string, maxFee *big.Int) error {
+	return nil
+}
+
+func TestGetReplaceCostLimit(t *testing.T) {
+	swapFee, value := big.NewInt(10), big.NewInt(1000)
+	tests := []struct {
+		cfg  *params.ReplaceGuardConfig
+		fee  *big.Int
+		want int64
+	}{
+		{&params.ReplaceGuardConfig{MaxFeeMultiple: 3, MaxValuePercent: 5}, swapFee, 30},
+		{&params.ReplaceGuardConfig{MaxFeeMultiple: 3, MaxValuePercent: 2}, swapFee, 20},
+		{&params.ReplaceGuardConfig{MaxFeeMultiple: 3}, swapFee, 30},
+		{&params.ReplaceGuardConfig{MaxValuePercent: 5}, swapFee, 50},
+		// negative swap fee has no budget
+		{&params.ReplaceGuardConfig{MaxFeeMultiple: 3}, big.NewInt(-5), 0},
+	}
+	for i, test := range tests {
+		if limit := getReplaceCostLimit(test.cfg, test.fee, value); limit.Int64() != test.want {
+			t.Errorf("test %v: replace cost limit got %v, want %v", i, limit, test.want)
+		}
+	}
+}
+
+func TestConvertReplaceCost(t *testing.T) {
+	// 1 ETH = 3000 USDC, 0.001 ETH is 3 USDC
+	price := big.NewFloat(3000)
+	coinAmount, _ := new(big.Int).SetString("1000000000000000", 10)
+	if value := coinToTokenValue(coinAmount, price, 18, 6); value.Int64() != 3000000 {
+		t.Errorf("coin to token value got %v, want 3000000", value)
+	}
+	if value := tokenToCoinValue(big.NewInt(3000000), price, 6, 18); value.Cmp(coinAmount) != 0 {
+		t.Errorf("token to coin value got %v, want %v", value, coinAmount)
+	}
+}
+
+func TestGuardReplaceCostNotGuarded(t *testing.T) {
+	cfg := &params.ReplaceGuardConfig{MaxFeeMultiple: 3, CoinPrices: map[string]string{"USDC": "3000"}}
+	if err := cfg.CheckConfig(); err != nil {
+		t.Fatalf("check replace guard config failed: %v", err)
+	}
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			"56": {ReplaceGuard: cfg},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }()
+
+	bridge := &testReplaceCostBridge{sentFee: big.NewInt(1000), builtFee: big.NewInt(900)}
+	newArgs := func(tokenID string) *tokens.BuildTxArgs {
+		return &tokens.BuildTxArgs{SwapArgs: tokens.SwapArgs{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{TokenID: tokenID}}}}
+	}
+	rawTx := "rawTx"
+	tests := []struct {
+		res  *mongodb.MgoSwapResult
+		args *tokens.BuildTxArgs
+	}{
+		// chain without replace guard
+		{&mongodb.MgoSwapResult{ToChainID: "1", SwapTx: "0x01"}, newArgs("USDC")},
+		// not sent yet
+		{&mongodb.MgoSwapResult{ToChainID: "56"}, newArgs("USDC")},
+		// token without coin price
+		{&mongodb.MgoSwapResult{ToChainID: "56", SwapTx: "0x01"}, newArgs("USDT")},
+		// replacement costs no more than the sent tx
+		{&mongodb.MgoSwapResult{ToChainID: "56", SwapTx: "0x01"}, newArgs("usdc")},
+	}
+	for i, test := range tests {
+		got, err := guardReplaceCost(bridge, test.res, test.args, rawTx)
+		if err != nil || got != rawTx {
+			t.Errorf("test %v: guard replace cost got (%v, %v), want the raw tx", i, got, err)
+		}
+	}
+}
+
+func TestGetReplaceGuardPrice(t *testing.T) {
+	priceAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"usdc":"2500.5"}`))
+	}))
+	defer priceAPI.Close()
+
+	cfg := &params.ReplaceGuardConfig{
+		MaxFeeMultiple: 3,
+		CoinPrices:     map[string]string{"USDC": "3000", "USDT": "3000"},
+		PriceOracles: map[string]*params.RateOracleConfig{
+			"USDC": {Type: "jsonapi", URL: priceAPI.URL, JSONPath: "usdc"},
+			"USDT": {Type: "jsonapi", URL: priceAPI.URL, JSONPath: "usdt"},
+		},
+	}
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			"56": {ReplaceGuard: cfg},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	defer func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) }()
+
+	// the price of usdt is not in the api response, the oracle is unavailable
+	router.UpdateRouteRates()
+
+	tests := []struct {
+		tokenID string
+		want    string
+	}{
+		{"USDC", "2500.5"}, // oracle price
+		{"USDT", "3000"},   // oracle is unavailable, use the configured price
+		{"DAI", ""},        // neither oracle nor configured price
+	}
+	for i, test := range tests {
+		price := getReplaceGuardPrice(cfg, "56", test.tokenID, nil)
+		if test.want == "" {
+			if price != nil {
+				t.Errorf("test %v: price of %v got %v, want nil", i, test.tokenID, price)
+			}
+			continue
+		}
+		if price == nil || price.Text('f', -1) != test.want {
+			t.Errorf("test %v: price of %v got %v, want %v", i, test.tokenID, price, test.want)
+		}
+	}
+}
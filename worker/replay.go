@@ -0,0 +1,161 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// ReplayResult result of replaying a historical swap against current code
+type ReplayResult struct {
+	FromChainID string        `json:"fromChainID"`
+	TxID        string        `json:"txid"`
+	LogIndex    int           `json:"logIndex"`
+	ToChainID   string        `json:"toChainID"`
+	VerifyError string        `json:"verifyError,omitempty"`
+	BuildError  string        `json:"buildError,omitempty"`
+	Diffs       []*ReplayDiff `json:"diffs,omitempty"`
+}
+
+// ReplayDiff difference between stored and replayed outputs
+type ReplayDiff struct {
+	Stage    string `json:"stage"` // verify or build
+	Field    string `json:"field"`
+	Stored   string `json:"stored"`
+	Replayed string `json:"replayed"`
+}
+
+// IsRegression is replayed outputs differ from the stored result
+func (r *ReplayResult) IsRegression() bool {
+	return r.VerifyError != "" || r.BuildError != "" || len(r.Diffs) > 0
+}
+
+func (r *ReplayResult) addDiff(stage, field, stored, replayed string) {
+	if strings.EqualFold(stored, replayed) {
+		return
+	}
+	r.Diffs = append(r.Diffs, &ReplayDiff{
+		Stage:    stage,
+		Field:    field,
+		Stored:   stored,
+		Replayed: replayed,
+	})
+}
+
+// ReplaySwap re-run verify and build (never sign or send) of a historical swap
+// with the current bridges, and diff the outputs against the stored result.
+func ReplaySwap(res *mongodb.MgoSwapResult) *ReplayResult {
+	result := &ReplayResult{
+		FromChainID: res.FromChainID,
+		TxID:        res.TxID,
+		LogIndex:    res.LogIndex,
+		ToChainID:   res.ToChainID,
+	}
+	swapInfo, err := replayVerify(res, result)
+	if err != nil {
+		result.VerifyError = err.Error()
+		return result
+	}
+	if err = replayBuild(res, swapInfo); err != nil {
+		result.BuildError = err.Error()
+	}
+	if res.SwapValue != "" && swapInfo.swapValue != "" {
+		result.addDiff("build", "swapValue", res.SwapValue, swapInfo.swapValue)
+	}
+	return result
+}
+
+type replayedSwap struct {
+	*tokens.SwapTxInfo
+	swapValue string
+}
+
+func replayVerify(res *mongodb.MgoSwapResult, result *ReplayResult) (*replayedSwap, error) {
+	srcBridge := router.GetBridgeByChainID(res.FromChainID)
+	if srcBridge == nil {
+		return nil, tokens.ErrNoBridgeForChainID
+	}
+	toChainID, err := common.GetBigIntFromStr(res.ToChainID)
+	if err != nil {
+		return nil, fmt.Errorf("wrong toChainID %v", res.ToChainID)
+	}
+	verifyArgs := &tokens.VerifyArgs{
+		SwapType:      tokens.SwapType(res.SwapType),
+		LogIndex:      res.LogIndex,
+		AllowUnstable: true,
+		Bind:          res.Bind,
+		ToChainID:     toChainID,
+		// replay against the same state as originally observed
+		PinnedHeight: res.TxHeight,
+	}
+	swapInfo, err := srcBridge.VerifyTransaction(res.TxID, verifyArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	result.addDiff("verify", "from", res.From, swapInfo.From)
+	result.addDiff("verify", "txTo", res.TxTo, swapInfo.TxTo)
+	result.addDiff("verify", "to", res.To, swapInfo.To)
+	result.addDiff("verify", "bind", res.Bind, swapInfo.Bind)
+	result.addDiff("verify", "value", res.Value, swapInfo.Value.String())
+	result.addDiff("verify", "toChainID", res.ToChainID, swapInfo.ToChainID.String())
+	result.addDiff("verify", "txHeight", fmt.Sprint(res.TxHeight), fmt.Sprint(swapInfo.Height))
+	storedInfo, _ := json.Marshal(res.SwapInfo)
+	replayedInfo, _ := json.Marshal(mongodb.ConvertToSwapInfo(&swapInfo.SwapInfo))
+	result.addDiff("verify", "swapInfo", string(storedInfo), string(replayedInfo))
+
+	return &replayedSwap{SwapTxInfo: swapInfo}, nil
+}
+
+// replayBuild build tx with the stored mpc and nonce, the built tx is discarded
+func replayBuild(res *mongodb.MgoSwapResult, swap *replayedSwap) error {
+	if res.MPC == "" {
+		return nil // never built
+	}
+	dstBridge := router.GetBridgeByChainID(res.ToChainID)
+	if dstBridge == nil {
+		return tokens.ErrNoBridgeForChainID
+	}
+	rate, err := router.GetRouteRate(swap.GetTokenID(), res.FromChainID, res.ToChainID)
+	if err != nil {
+		return err
+	}
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			Identifier:         params.GetIdentifier(),
+			Salt:               params.GetDeploymentSalt(),
+			SwapID:             res.TxID,
+			SwapType:           tokens.SwapType(res.SwapType),
+			Bind:               swap.Bind,
+			LogIndex:           res.LogIndex,
+			FromChainID:        swap.FromChainID,
+			ToChainID:          swap.ToChainID,
+			TxHeight:           swap.Height,
+			SwapInfo:           swap.SwapInfo,
+			ConversionRate:     rate,
+			DeliveryFeePercent: res.DeliveryFeePercent,
+		},
+		From:        res.MPC,
+		OriginFrom:  swap.From,
+		OriginTxTo:  swap.TxTo,
+		OriginValue: swap.Value,
+		Extra:       &tokens.AllExtras{},
+	}
+	if res.SwapNonce > 0 {
+		nonce := res.SwapNonce
+		args.Extra.Sequence = &nonce
+	}
+	if _, err = dstBridge.BuildRawTransaction(args); err != nil {
+		return err
+	}
+	if args.SwapValue != nil {
+		swap.swapValue = args.SwapValue.String()
+	}
+	return nil
+}
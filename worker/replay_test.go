@@ -0,0 +1,130 @@
+package worker
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// testReplayBridge verifies and builds swaps, signing or sending panics
+type testReplayBridge struct {
+	tokens.IBridge
+	value        *big.Int
+	swapValue    *big.Int
+	verifyErr    error
+	pinnedHeight uint64
+	buildNonce   uint64
+}
+
+func (b *testReplayBridge) VerifyTransaction(txid string, args *tokens.VerifyArgs) (*tokens.SwapTxInfo, error) {
+	b.pinnedHeight = args.PinnedHeight
+	if b.verifyErr != nil {
+		return nil, b.verifyErr
+	}
+	return &tokens.SwapTxInfo{
+		SwapInfo:    tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{TokenID: "USDC", Token: "0x01"}},
+		SwapType:    tokens.ERC20SwapType,
+		Hash:        txid,
+		Height:      100,
+		From:        "0x1111",
+		TxTo:        "0x01",
+		To:          "0x01",
+		Bind:        "0x2222",
+		Value:       b.value,
+		LogIndex:    args.LogIndex,
+		FromChainID: big.NewInt(7001),
+		ToChainID:   big.NewInt(7002),
+	}, nil
+}
+
+func (b *testReplayBridge) BuildRawTransaction(args *tokens.BuildTxArgs) (interface{}, error) {
+	b.buildNonce = *args.Extra.Sequence
+	args.SwapValue = b.swapValue
+	return "rawTx", nil
+}
+
+func newTestReplaySwapResult() *mongodb.MgoSwapResult {
+	return &mongodb.MgoSwapResult{
+		FromChainID: "7001",
+		TxID:        "0xabcd",
+		TxHeight:    100,
+		TxTo:        "0x01",
+		To:          "0x01",
+		From:        "0x1111",
+		Bind:        "0x2222",
+		Value:       "1000",
+		LogIndex:    1,
+		ToChainID:   "7002",
+		SwapType:    uint32(tokens.ERC20SwapType),
+		SwapInfo:    mongodb.SwapInfo{ERC20SwapInfo: &mongodb.ERC20SwapInfo{TokenID: "USDC", Token: "0x01"}},
+		SwapValue:   "990",
+		SwapNonce:   5,
+		MPC:         "0x3333",
+	}
+}
+
+func TestReplaySwap(t *testing.T) {
+	srcBridge := &testReplayBridge{value: big.NewInt(1000)}
+	dstBridge := &testReplayBridge{swapValue: big.NewInt(990)}
+	router.SetBridge("7001", srcBridge)
+	router.SetBridge("7002", dstBridge)
+	defer router.SetBridge("7001", nil)
+	defer router.SetBridge("7002", nil)
+
+	res := newTestReplaySwapResult()
+	result := ReplaySwap(res)
+	if result.IsRegression() {
+		t.Errorf("replay unchanged swap got regression %+v, diffs %v", result, result.Diffs)
+	}
+	if srcBridge.pinnedHeight != res.TxHeight || dstBridge.buildNonce != res.SwapNonce {
+		t.Errorf("replay swap got pinned height %v and build nonce %v, want %v and %v",
+			srcBridge.pinnedHeight, dstBridge.buildNonce, res.TxHeight, res.SwapNonce)
+	}
+
+	// replayed outputs differ from the stored result
+	srcBridge.value = big.NewInt(1001)
+	dstBridge.swapValue = big.NewInt(991)
+	result = ReplaySwap(res)
+	if !result.IsRegression() || len(result.Diffs) != 2 {
+		t.Fatalf("replay changed swap got %+v, want 2 diffs", result)
+	}
+	if diff := result.Diffs[0]; diff.Stage != "verify" || diff.Field != "value" || diff.Stored != "1000" || diff.Replayed != "1001" {
+		t.Errorf("replay changed swap got verify diff %+v", diff)
+	}
+	if diff := result.Diffs[1]; diff.Stage != "build" || diff.Field != "swapValue" || diff.Stored != "990" || diff.Replayed != "991" {
+		t.Errorf("replay changed swap got build diff %+v", diff)
+	}
+}
+
+func TestReplaySwapErrors(t *testing.T) {
+	srcBridge := &testReplayBridge{value: big.NewInt(1000), verifyErr: tokens.ErrTxWithWrongValue}
+	router.SetBridge("7001", srcBridge)
+	defer router.SetBridge("7001", nil)
+
+	res := newTestReplaySwapResult()
+	result := ReplaySwap(res)
+	if result.VerifyError != tokens.ErrTxWithWrongValue.Error() || result.BuildError != "" || !result.IsRegression() {
+		t.Errorf("replay swap failing verify got %+v", result)
+	}
+
+	// no destination bridge to build
+	srcBridge.verifyErr = nil
+	result = ReplaySwap(res)
+	if result.BuildError != tokens.ErrNoBridgeForChainID.Error() || !result.IsRegression() {
+		t.Errorf("replay swap failing build got %+v", result)
+	}
+
+	// swaps never built are only verified
+	res.MPC = ""
+	if result = ReplaySwap(res); result.IsRegression() {
+		t.Errorf("replay unbuilt swap got regression %+v", result)
+	}
+
+	router.SetBridge("7001", nil)
+	if result = ReplaySwap(res); result.VerifyError != tokens.ErrNoBridgeForChainID.Error() {
+		t.Errorf("replay swap without source bridge got %+v", result)
+	}
+}
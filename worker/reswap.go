@@ -48,7 +48,7 @@ func startReswapProducer() {
 				return
 			}
 
-			if !router.IsReswapSupported(swap.ToChainID) {
+			if !isAutoReswapEnabled(swap.ToChainID) {
 				continue
 			}
 
@@ -180,6 +180,7 @@ func reswapRouterSwap(res *mongodb.MgoSwapResult, isManual bool) error {
 	args := &tokens.BuildTxArgs{
 		SwapArgs: tokens.SwapArgs{
 			Identifier:  params.GetIdentifier(),
+			Salt:        params.GetDeploymentSalt(),
 			SwapID:      txid,
 			SwapType:    tokens.SwapType(res.SwapType),
 			Bind:        res.Bind,
@@ -197,6 +198,11 @@ func reswapRouterSwap(res *mongodb.MgoSwapResult, isManual bool) error {
 	if err != nil {
 		return err
 	}
+	args.ConversionRate, err = router.GetRouteRate(args.GetTokenID(), res.FromChainID, res.ToChainID)
+	if err != nil {
+		return err
+	}
+	args.DeliveryFeePercent = res.DeliveryFeePercent
 	rawTx, err := resBridge.BuildRawTransaction(args)
 	if err != nil {
 		logWorkerError("reswapSwap", "build tx failed", err, "chainID", res.ToChainID, "txid", txid, "logIndex", res.LogIndex)
@@ -207,7 +213,7 @@ func reswapRouterSwap(res *mongodb.MgoSwapResult, isManual bool) error {
 }
 
 func signAndSendReswapTx(resBridge tokens.IBridge, rawTx interface{}, args *tokens.BuildTxArgs, res *mongodb.MgoSwapResult) {
-	signedTx, txHash, err := resBridge.MPCSignTransaction(rawTx, args)
+	signedTx, txHash, err := mpcSignTransaction(resBridge, rawTx, args)
 	if err != nil {
 		logWorkerError("reswapSwap", "mpc sign tx failed", err, "fromChainID", res.FromChainID, "toChainID", res.ToChainID, "txid", res.TxID, "nonce", res.SwapNonce, "logIndex", res.LogIndex)
 		if errors.Is(err, mpc.ErrGetSignStatusHasDisagree) {
@@ -238,6 +244,11 @@ func signAndSendReswapTx(resBridge tokens.IBridge, rawTx interface{}, args *toke
 		MPC:       args.From,
 		TTL:       *args.Extra.TTL,
 	}
+	if args.Extra.Fee != nil {
+		matchTx.SwapFee = *args.Extra.Fee
+	}
+	matchTx.BuilderVersion = args.Extra.BuilderVersion
+	matchTx.DeliveryFeePercent = args.DeliveryFeePercent
 
 	err = updateRouterSwapResult(fromChainID, txid, logIndex, matchTx)
 	if err != nil {
@@ -246,7 +257,7 @@ func signAndSendReswapTx(resBridge tokens.IBridge, rawTx interface{}, args *toke
 	}
 
 	sentTxHash, err := sendSignedTransaction(resBridge, signedTx, args)
-	if err == nil && txHash != sentTxHash {
+	if err == nil && sentTxHash != "" && txHash != sentTxHash {
 		logWorkerError("reswapSwap", "send tx success but with different hash", errSendTxWithDiffHash,
 			"fromChainID", fromChainID, "toChainID", res.ToChainID, "txid", txid, "nonce", res.SwapNonce,
 			"logIndex", logIndex, "txHash", txHash, "sentTxHash", sentTxHash)
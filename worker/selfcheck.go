@@ -0,0 +1,174 @@
+package worker
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type signerChainIDGetter interface {
+	GetSignerChainID() (*big.Int, error)
+}
+
+type mpcPubkeyVerifier interface {
+	VerifyMPCPubKey(mpcAddress, mpcPubkey string) error
+}
+
+// routerMPCGetter call "mpc()" of router contract
+type routerMPCGetter interface {
+	GetMPCAddress(contractAddr string) (string, error)
+}
+
+// SelfCheckItem result of one startup self check
+type SelfCheckItem struct {
+	Check  string `json:"check"`
+	Target string `json:"target"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SelfCheckReport report of startup self check suite
+type SelfCheckReport struct {
+	Items  []*SelfCheckItem `json:"items"`
+	Failed int              `json:"failed"`
+}
+
+func (r *SelfCheckReport) add(check, target string, err error) {
+	item := &SelfCheckItem{Check: check, Target: target}
+	if err != nil {
+		item.Error = err.Error()
+		r.Failed++
+	}
+	r.Items = append(r.Items, item)
+}
+
+// String readable report with the failed items
+func (r *SelfCheckReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "startup self check: %v passed, %v failed", len(r.Items)-r.Failed, r.Failed)
+	for _, item := range r.Items {
+		if item.Error != "" {
+			fmt.Fprintf(&sb, "\n  [FAIL] %v %v: %v", item.Check, item.Target, item.Error)
+		}
+	}
+	return sb.String()
+}
+
+// RunStartupSelfCheck validate end-to-end readiness before starting jobs
+func RunStartupSelfCheck(isServer bool) *SelfCheckReport {
+	report := &SelfCheckReport{}
+	if isServer {
+		checkMongoIndexes(report)
+	}
+	for _, chainID := range router.AllChainIDs {
+		chainIDStr := chainID.String()
+		if params.IsChainIDInBlackList(chainIDStr) {
+			continue
+		}
+		bridge := router.GetBridgeByChainID(chainIDStr)
+		if bridge == nil {
+			report.add("bridge", chainIDStr, tokens.ErrNoBridgeForChainID)
+			continue
+		}
+		if !checkChainReachable(report, chainIDStr, bridge) {
+			continue
+		}
+		checkChainRouters(report, chainIDStr, bridge)
+	}
+	return report
+}
+
+// StartupSelfCheck run startup self check suite and exit if any check fails
+func StartupSelfCheck(isServer bool) {
+	report := RunStartupSelfCheck(isServer)
+	if report.Failed > 0 {
+		log.Fatal(report.String())
+	}
+	logWorker("selfcheck", report.String())
+}
+
+func checkMongoIndexes(report *SelfCheckReport) {
+	missing, err := mongodb.FindMissingIndexes()
+	if err == nil && len(missing) > 0 {
+		err = fmt.Errorf("missing indexes %v", missing)
+	}
+	report.add("mongodb", "indexes", err)
+}
+
+func checkChainReachable(report *SelfCheckReport, chainID string, bridge tokens.IBridge) bool {
+	_, err := bridge.GetLatestBlockNumber()
+	report.add("reachable", chainID, err)
+	if err != nil {
+		return false
+	}
+	if getter, ok := bridge.(signerChainIDGetter); ok {
+		onchainID, errf := getter.GetSignerChainID()
+		if errf == nil && onchainID.String() != chainID {
+			errf = fmt.Errorf("chainID mismatch, config %v, online %v", chainID, onchainID)
+		}
+		report.add("chainID", chainID, errf)
+	}
+	return true
+}
+
+// checkChainRouters check router contracts, mpc public keys and token configs
+// of all tokens on the chain
+func checkChainRouters(report *SelfCheckReport, chainID string, bridge tokens.IBridge) {
+	checkedRouters := make(map[string]bool)
+	for _, tokenID := range router.AllTokenIDs {
+		tokenAddr := router.GetCachedMultichainToken(tokenID, chainID)
+		if tokenAddr == "" {
+			continue
+		}
+		target := fmt.Sprintf("%v:%v", chainID, tokenID)
+		// token config is checked on getting (eg. decimals, minter, underlying)
+		if bridge.GetTokenConfig(tokenAddr) == nil {
+			report.add("token", target, fmt.Errorf("token config of %v is missing or failed checking", tokenAddr))
+			continue
+		}
+
+		routerInfo, err := router.GetTokenRouterInfo(tokenID, chainID)
+		if err != nil {
+			report.add("router", target, err)
+			continue
+		}
+		routerContract := bridge.GetRouterContract(tokenAddr)
+		if checkedRouters[routerContract] {
+			continue
+		}
+		checkedRouters[routerContract] = true
+		target = fmt.Sprintf("%v:%v", chainID, routerContract)
+		if getter, ok := bridge.(routerMPCGetter); ok {
+			report.add("router", target, checkRouterMPC(getter, routerContract, routerInfo.RouterMPC))
+		} else {
+			// router info is loaded from chain on initing bridge
+			report.add("router", target, nil)
+		}
+
+		mpcPubkey := router.GetMPCPublicKey(routerInfo.RouterMPC)
+		if mpcPubkey == "" {
+			report.add("mpcPubkey", target, fmt.Errorf("miss public key of mpc %v", routerInfo.RouterMPC))
+			continue
+		}
+		if verifier, ok := bridge.(mpcPubkeyVerifier); ok {
+			report.add("mpcPubkey", target, verifier.VerifyMPCPubKey(routerInfo.RouterMPC, mpcPubkey))
+		}
+	}
+}
+
+// checkRouterMPC check the router contract responds and its mpc is the loaded router mpc
+func checkRouterMPC(getter routerMPCGetter, routerContract, routerMPC string) error {
+	onchainMPC, err := getter.GetMPCAddress(routerContract)
+	if err != nil {
+		return fmt.Errorf("call mpc of router contract failed: %w", err)
+	}
+	if !strings.EqualFold(onchainMPC, routerMPC) {
+		return fmt.Errorf("router mpc mismatch, loaded %v, online %v", routerMPC, onchainMPC)
+	}
+	return nil
+}
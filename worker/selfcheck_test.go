@@ -0,0 +1,29 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+)
+
+type testRouterMPCGetter struct {
+	mpc string
+	err error
+}
+
+func (g *testRouterMPCGetter) GetMPCAddress(contractAddr string) (string, error) {
+	return g.mpc, g.err
+}
+
+func TestCheckRouterMPC(t *testing.T) {
+	routerMPC := "0xAbCd000000000000000000000000000000000001"
+	if err := checkRouterMPC(&testRouterMPCGetter{mpc: "0xabcd000000000000000000000000000000000001"}, "0xrouter", routerMPC); err != nil {
+		t.Errorf("check router mpc got error %v", err)
+	}
+	if err := checkRouterMPC(&testRouterMPCGetter{mpc: "0xabcd000000000000000000000000000000000002"}, "0xrouter", routerMPC); err == nil {
+		t.Errorf("check router mpc with mismatched mpc should fail")
+	}
+	callErr := errors.New("execution reverted")
+	if err := checkRouterMPC(&testRouterMPCGetter{err: callErr}, "0xrouter", routerMPC); !errors.Is(err, callErr) {
+		t.Errorf("check router mpc of not responding contract got error %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// unlock time of send delayed swaps, key is swap key
+var sendDelayedSwaps sync.Map
+
+// checkSendDelay defer the swap by a random delay before building and sending
+// if the dest chain is configured with send delay, the delay bound is weighted
+// by the swap value and the applied delay is recorded in the swap timeline.
+func checkSendDelay(swap *mongodb.MgoSwap, res *mongodb.MgoSwapResult) error {
+	cfg := params.GetSendDelayConfig(swap.ToChainID)
+	if cfg == nil || cfg.MaxDelay <= 0 {
+		return nil
+	}
+	if unlockTime, exist := sendDelayedSwaps.Load(swap.Key); exist {
+		if unlockTime.(int64) <= now() {
+			sendDelayedSwaps.Delete(swap.Key)
+			return nil
+		}
+		deferSwapUntil(swap, WaitSendDelay, unlockTime.(int64))
+		return errSwapDeferred
+	}
+
+	value, ok := new(big.Int).SetString(res.Value, 0)
+	if !ok {
+		return nil
+	}
+	delay, bound := getRandomSendDelay(swap, value, cfg.MaxDelay)
+	if delay <= 0 {
+		return nil
+	}
+	unlockTime := now() + delay
+	sendDelayedSwaps.Store(swap.Key, unlockTime)
+	recordSwapEvent(swap.FromChainID, swap.TxID, swap.LogIndex, mongodb.SwapEventSendDelayed,
+		time.Duration(delay)*time.Second, fmt.Sprintf("random delay %vs of bound %vs", delay, bound))
+	logWorker("senddelay", "delay swap randomly", "fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "value", value, "delay", delay, "bound", bound)
+	deferSwapUntil(swap, WaitSendDelay, unlockTime)
+	return errSwapDeferred
+}
+
+// getRandomSendDelay get uniform random delay in [0, bound] seconds,
+// zero is returned if the random source fails.
+func getRandomSendDelay(swap *mongodb.MgoSwap, value *big.Int, maxDelay int64) (delay, bound int64) {
+	bound = getSendDelayBound(swap, value, maxDelay)
+	if bound <= 0 {
+		return 0, bound
+	}
+	randDelay, err := rand.Int(rand.Reader, big.NewInt(bound+1))
+	if err != nil {
+		return 0, bound
+	}
+	return randDelay.Int64(), bound
+}
+
+// getSendDelayBound get max delay weighted by value relative to the big value threshold,
+// swaps of value above the threshold (or without threshold) are bounded by max delay.
+func getSendDelayBound(swap *mongodb.MgoSwap, value *big.Int, maxDelay int64) int64 {
+	bridge := router.GetBridgeByChainID(swap.FromChainID)
+	if bridge == nil {
+		return maxDelay
+	}
+	tokenCfg := bridge.GetTokenConfig(swap.GetToken())
+	if tokenCfg == nil {
+		return maxDelay
+	}
+	threshold := tokens.GetBigValueThreshold(swap.GetTokenID(), swap.FromChainID, swap.ToChainID, tokenCfg.Decimals)
+	if threshold.Sign() <= 0 || value.Cmp(threshold) >= 0 {
+		return maxDelay
+	}
+	bound := new(big.Int).Mul(value, big.NewInt(maxDelay))
+	return bound.Div(bound, threshold).Int64()
+}
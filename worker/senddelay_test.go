@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func setupSendDelay(t *testing.T) func() {
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{
+			"56": {SendDelay: &params.SendDelayConfig{MaxDelay: 60}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+	router.SetBridge("1", &testTokenConfigBridge{decimals: 6})
+	// big value threshold is 1000 USDC
+	swapCfgs := new(sync.Map)
+	toChainCfgs := new(sync.Map)
+	toChainCfgs.Store("56", &tokens.SwapConfig{BigValueThreshold: new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))})
+	fromChainCfgs := new(sync.Map)
+	fromChainCfgs.Store("1", toChainCfgs)
+	swapCfgs.Store("USDC", fromChainCfgs)
+	tokens.SetSwapConfigs(swapCfgs)
+	resetDeferredSwaps()
+	return func() {
+		_ = params.SetExtraConfig(&params.ExtraConfig{})
+		router.SetBridge("1", nil)
+		tokens.SetSwapConfigs(new(sync.Map))
+		resetDeferredSwaps()
+		sendDelayedSwaps = sync.Map{}
+	}
+}
+
+func TestGetSendDelayBound(t *testing.T) {
+	defer setupSendDelay(t)()
+
+	tests := []struct {
+		value string
+		bound int64
+	}{
+		{"0", 0},
+		{"250000000", 15},
+		{"500000000", 30},
+		// values above the big value threshold are bounded by max delay
+		{"1000000000", 60},
+		{"5000000000", 60},
+	}
+	for i, test := range tests {
+		swap, _ := newTestTimeLockSwap("bound", test.value, 0)
+		value, _ := new(big.Int).SetString(test.value, 0)
+		if bound := getSendDelayBound(swap, value, 60); bound != test.bound {
+			t.Errorf("test %v: send delay bound of value %v got %v, want %v", i, test.value, bound, test.bound)
+		}
+		for j := 0; j < 20; j++ {
+			if delay, _ := getRandomSendDelay(swap, value, 60); delay < 0 || delay > test.bound {
+				t.Errorf("test %v: random send delay of value %v got %v, want in [0, %v]", i, test.value, delay, test.bound)
+			}
+		}
+	}
+
+	// swaps of unknown token are bounded by max delay
+	swap, _ := newTestTimeLockSwap("unknown", "1", 0)
+	swap.SwapInfo.ERC20SwapInfo.TokenID = "UNKNOWN"
+	if bound := getSendDelayBound(swap, big.NewInt(1), 60); bound != 60 {
+		t.Errorf("send delay bound of unknown token got %v, want 60", bound)
+	}
+}
+
+func TestCheckSendDelay(t *testing.T) {
+	defer setupSendDelay(t)()
+	current := int64(1700000000)
+	defer setFakeNow(&current)()
+
+	// swaps to chains without send delay are not deferred
+	swap, res := newTestTimeLockSwap("other", "5000000000", current)
+	swap.ToChainID = "137"
+	if err := checkSendDelay(swap, res); err != nil || isSwapDeferred("other") {
+		t.Errorf("check send delay of chain without config got err %v", err)
+	}
+
+	// swaps of zero bound are not deferred
+	swap, res = newTestTimeLockSwap("zero", "0", current)
+	if err := checkSendDelay(swap, res); err != nil || isSwapDeferred("zero") {
+		t.Errorf("check send delay of zero value got err %v", err)
+	}
+
+	// delayed swaps are deferred until the unlock time
+	swap, res = newTestTimeLockSwap("delayed", "5000000000", current)
+	sendDelayedSwaps.Store(swap.Key, current+30)
+	if err := checkSendDelay(swap, res); !errors.Is(err, errSwapDeferred) || !isSwapDeferred("delayed") {
+		t.Errorf("check send delay before unlock time got err %v, want %v", err, errSwapDeferred)
+	}
+	current += 30
+	if err := checkSendDelay(swap, res); err != nil {
+		t.Errorf("check send delay after unlock time got err %v", err)
+	}
+	if _, exist := sendDelayedSwaps.Load(swap.Key); exist {
+		t.Errorf("unlocked send delay should be removed")
+	}
+}
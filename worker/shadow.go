@@ -0,0 +1,157 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// ShadowDivergence divergence between production and shadow bridges
+type ShadowDivergence struct {
+	Stage       string `json:"stage"` // verify or build
+	ChainID     string `json:"chainID"`
+	TxID        string `json:"txid"`
+	LogIndex    int    `json:"logIndex"`
+	Production  string `json:"production"`
+	Shadow      string `json:"shadow"`
+	ProducedAt  int64  `json:"timestamp"`
+	Description string `json:"description"`
+}
+
+var (
+	shadowDivergences        []*ShadowDivergence
+	shadowDivergencesLock    sync.RWMutex
+	shadowDivergencesMaxSize = 1000
+
+	shadowComparedCount  uint64
+	shadowDivergentCount uint64
+)
+
+// GetShadowDivergences get shadow comparison counters and recent divergences
+func GetShadowDivergences() (compared, divergent uint64, result []*ShadowDivergence) {
+	shadowDivergencesLock.RLock()
+	defer shadowDivergencesLock.RUnlock()
+	result = make([]*ShadowDivergence, len(shadowDivergences))
+	copy(result, shadowDivergences)
+	return shadowComparedCount, shadowDivergentCount, result
+}
+
+func recordShadowComparison(div *ShadowDivergence) {
+	shadowDivergencesLock.Lock()
+	defer shadowDivergencesLock.Unlock()
+
+	shadowComparedCount++
+	if div == nil {
+		return
+	}
+	shadowDivergentCount++
+
+	if len(shadowDivergences) >= shadowDivergencesMaxSize {
+		shadowDivergences = shadowDivergences[1:]
+	}
+	shadowDivergences = append(shadowDivergences, div)
+
+	logWorkerWarn("shadow", "shadow bridge diverges from production",
+		"stage", div.Stage, "chainID", div.ChainID, "txid", div.TxID, "logIndex", div.LogIndex,
+		"description", div.Description, "production", div.Production, "shadow", div.Shadow)
+}
+
+func toComparableString(v interface{}, err error) string {
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	data, jsonErr := json.Marshal(v)
+	if jsonErr != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	return string(data)
+}
+
+func runShadow(stage string, f func()) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logWorkerWarn("shadow", "shadow bridge panics", "stage", stage, "panic", r)
+			}
+		}()
+		f()
+	}()
+}
+
+// shadowVerify verify tx with shadow bridge and compare with production result.
+// it runs asynchronously and never affects the production processing.
+func shadowVerify(chainID, txid string, verifyArgs *tokens.VerifyArgs, prodSwapInfo *tokens.SwapTxInfo, prodErr error) {
+	shadow := router.GetShadowBridge(chainID)
+	if shadow == nil {
+		return
+	}
+	argsCopy := *verifyArgs
+	production := toComparableString(prodSwapInfo, prodErr)
+	runShadow("verify", func() {
+		swapInfo, err := shadow.VerifyTransaction(txid, &argsCopy)
+		shadowResult := toComparableString(swapInfo, err)
+		var div *ShadowDivergence
+		if shadowResult != production {
+			div = &ShadowDivergence{
+				Stage:       "verify",
+				ChainID:     chainID,
+				TxID:        txid,
+				LogIndex:    argsCopy.LogIndex,
+				Production:  production,
+				Shadow:      shadowResult,
+				ProducedAt:  now(),
+				Description: "verify result mismatch",
+			}
+		}
+		recordShadowComparison(div)
+	})
+}
+
+// shadowBuild build tx with shadow bridge using a copy of production args
+// (after production build, so nonce and gas are the same) and compare the
+// built tx, swap value and fees. the shadow tx is never signed.
+func shadowBuild(args *tokens.BuildTxArgs, prodRawTx interface{}) {
+	chainID := args.ToChainID.String()
+	shadow := router.GetShadowBridge(chainID)
+	if shadow == nil {
+		return
+	}
+	argsData, err := json.Marshal(args)
+	if err != nil {
+		return
+	}
+	var argsCopy tokens.BuildTxArgs
+	if err = json.Unmarshal(argsData, &argsCopy); err != nil {
+		return
+	}
+	production := toComparableString(prodRawTx, nil)
+	prodArgs := string(argsData)
+	runShadow("build", func() {
+		rawTx, err := shadow.BuildRawTransaction(&argsCopy)
+		div := &ShadowDivergence{
+			Stage:      "build",
+			ChainID:    chainID,
+			TxID:       args.SwapID,
+			LogIndex:   args.LogIndex,
+			ProducedAt: now(),
+		}
+		shadowResult := toComparableString(rawTx, err)
+		shadowArgs := toComparableString(&argsCopy, nil)
+		switch {
+		case shadowResult != production:
+			div.Description = "built tx mismatch"
+			div.Production = production
+			div.Shadow = shadowResult
+		case shadowArgs != prodArgs:
+			div.Description = "swap value or fee mismatch"
+			div.Production = prodArgs
+			div.Shadow = shadowArgs
+		default:
+			div = nil
+		}
+		recordShadowComparison(div)
+	})
+}
@@ -0,0 +1,37 @@
+//go:build !watcher
+
+package worker
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// IsWatcherMode is watcher mode (build with `-tags watcher`),
+// which has signing and sending compiled out.
+const IsWatcherMode = params.IsWatcherMode
+
+func mpcSignTransaction(bridge tokens.IBridge, rawTx interface{}, args *tokens.BuildTxArgs) (signedTx interface{}, txHash string, err error) {
+	signedTx, txHash, err = bridge.MPCSignTransaction(rawTx, args)
+	recordMPCUsage(args.ToChainID.String(), err)
+	return signedTx, txHash, err
+}
+
+func sendTransaction(bridge tokens.IBridge, signedTx interface{}) (txHash string, err error) {
+	return bridge.SendTransaction(signedTx)
+}
+
+func doAcceptSign(mpcConfig *mpc.Config, keyID, agreeResult string, msgHash, msgContext []string) (string, error) {
+	return mpcConfig.DoAcceptSign(keyID, agreeResult, msgHash, msgContext)
+}
+
+func signHashWithNodeKey(mpcConfig *mpc.Config, hash []byte) (signature []byte, signer common.Address, err error) {
+	return mpcConfig.SignHashWithNodeKey(hash)
+}
+
+func signCanaryMessage(mpcConfig *mpc.Config, family, signPubkey string) error {
+	_, _, _, err := mpcConfig.DoSignMessageWithScheme(family, signPubkey, []byte(mpc.CanaryMessage), mpc.CanaryMsgContext)
+	return err
+}
@@ -0,0 +1,40 @@
+//go:build !watcher
+
+package worker
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testSignerBridge struct {
+	tokens.IBridge
+	signed, sent int
+}
+
+func (b *testSignerBridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (interface{}, string, error) {
+	b.signed++
+	return rawTx, "0x02", nil
+}
+
+func (b *testSignerBridge) SendTransaction(signedTx interface{}) (string, error) {
+	b.sent++
+	return "0x02", nil
+}
+
+func TestNotWatcherModeSigner(t *testing.T) {
+	if IsWatcherMode {
+		t.Fatalf("default build should not be watcher mode")
+	}
+	b := &testSignerBridge{}
+	args := &tokens.BuildTxArgs{SwapArgs: tokens.SwapArgs{ToChainID: big.NewInt(56)}}
+	signedTx, txHash, err := mpcSignTransaction(b, "rawTx", args)
+	if err != nil || signedTx != "rawTx" || txHash != "0x02" || b.signed != 1 {
+		t.Errorf("mpc sign transaction got (%v, %v, %v)", signedTx, txHash, err)
+	}
+	if txHash, err = sendTransaction(b, signedTx); err != nil || txHash != "0x02" || b.sent != 1 {
+		t.Errorf("send transaction got (%v, %v)", txHash, err)
+	}
+}
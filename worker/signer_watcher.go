@@ -0,0 +1,37 @@
+//go:build watcher
+
+package worker
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// IsWatcherMode is watcher mode (build with `-tags watcher`),
+// which has signing and sending compiled out.
+// the mpc and bridges refuse signing and sending in watcher mode too.
+const IsWatcherMode = params.IsWatcherMode
+
+var errWatcherMode = params.ErrWatcherMode
+
+func mpcSignTransaction(tokens.IBridge, interface{}, *tokens.BuildTxArgs) (signedTx interface{}, txHash string, err error) {
+	return nil, "", errWatcherMode
+}
+
+func sendTransaction(tokens.IBridge, interface{}) (txHash string, err error) {
+	return "", errWatcherMode
+}
+
+func doAcceptSign(*mpc.Config, string, string, []string, []string) (string, error) {
+	return "", errWatcherMode
+}
+
+func signHashWithNodeKey(*mpc.Config, []byte) (signature []byte, signer common.Address, err error) {
+	return nil, common.Address{}, errWatcherMode
+}
+
+func signCanaryMessage(*mpc.Config, string, string) error {
+	return errWatcherMode
+}
@@ -0,0 +1,49 @@
+//go:build watcher
+
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testSignerBridge struct {
+	tokens.IBridge
+}
+
+func (b *testSignerBridge) MPCSignTransaction(interface{}, *tokens.BuildTxArgs) (interface{}, string, error) {
+	panic("signing is not compiled out in watcher mode")
+}
+
+func (b *testSignerBridge) SendTransaction(interface{}) (string, error) {
+	panic("sending is not compiled out in watcher mode")
+}
+
+func TestWatcherModeSigner(t *testing.T) {
+	if !IsWatcherMode {
+		t.Fatalf("watcher build should be watcher mode")
+	}
+	b := &testSignerBridge{}
+	args := &tokens.BuildTxArgs{SwapArgs: tokens.SwapArgs{ToChainID: big.NewInt(56)}}
+	if _, _, err := mpcSignTransaction(b, "rawTx", args); !errors.Is(err, params.ErrWatcherMode) {
+		t.Errorf("mpc sign transaction got err %v, want %v", err, params.ErrWatcherMode)
+	}
+	if _, err := sendTransaction(b, "signedTx"); !errors.Is(err, params.ErrWatcherMode) {
+		t.Errorf("send transaction got err %v, want %v", err, params.ErrWatcherMode)
+	}
+	mpcConfig := mpc.InitConfig(&params.MPCConfig{}, true)
+	if _, err := doAcceptSign(mpcConfig, "keyID", "AGREE", []string{"0x01"}, []string{"swap"}); !errors.Is(err, params.ErrWatcherMode) {
+		t.Errorf("do accept sign got err %v, want %v", err, params.ErrWatcherMode)
+	}
+	if _, _, err := signHashWithNodeKey(mpcConfig, make([]byte, 32)); !errors.Is(err, params.ErrWatcherMode) {
+		t.Errorf("sign hash with node key got err %v, want %v", err, params.ErrWatcherMode)
+	}
+	if err := signCanaryMessage(mpcConfig, "EC256K1", "0x04aa"); !errors.Is(err, params.ErrWatcherMode) {
+		t.Errorf("sign canary message got err %v, want %v", err, params.ErrWatcherMode)
+	}
+}
@@ -0,0 +1,138 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var errSignRequestMismatch = errors.New("sign request mismatch with stored swap")
+
+// verifySignRequest is the mpc sign request verifier of dual control.
+// it rebuilds the tx of the sign request and verifies the msg hash.
+// swaps are rebuilt from the stored swap results (not from the sign request),
+// internal txs are checked by the tx builders of the bridges
+// (eg. claim refunds must be expired and allowances must be monitored).
+func verifySignRequest(msgHash []string, args *tokens.BuildTxArgs) error {
+	if args.Identifier != params.GetIdentifier() {
+		return errIdentifierMismatch
+	}
+	if args.FromChainID == nil || args.ToChainID == nil {
+		return errSignRequestMismatch
+	}
+	if !args.SwapType.IsValidType() {
+		return fmt.Errorf("unknown router swap type %d", args.SwapType)
+	}
+	dstBridge := router.GetBridgeByChainID(args.ToChainID.String())
+	if dstBridge == nil {
+		return tokens.ErrNoBridgeForChainID
+	}
+
+	var buildTxArgs *tokens.BuildTxArgs
+	switch args.SwapType {
+	case tokens.SapphireRPCType:
+		// sapphire envelope of a tx which is already signed by the mpc,
+		// the bridge verifies the inner tx is signed by the sender.
+		buildTxArgs = args
+	case tokens.DepositSweepType, tokens.ClaimRefundType, tokens.AllowanceApproveType:
+		if args.Salt != params.GetDeploymentSalt() {
+			return errSaltMismatch
+		}
+		if args.FromChainID.Cmp(args.ToChainID) != 0 {
+			return errSignRequestMismatch
+		}
+		buildTxArgs = &tokens.BuildTxArgs{
+			SwapArgs: args.SwapArgs,
+			From:     args.From,
+			Extra:    args.Extra,
+		}
+	default:
+		if args.Salt != params.GetDeploymentSalt() {
+			return errSaltMismatch
+		}
+		var err error
+		buildTxArgs, err = getStoredSwapBuildArgs(args)
+		if err != nil {
+			return err
+		}
+	}
+
+	rawTx, err := dstBridge.BuildRawTransaction(buildTxArgs)
+	if err != nil {
+		return err
+	}
+	return dstBridge.VerifyMsgHash(rawTx, msgHash)
+}
+
+// getStoredSwapBuildArgs get build args of the stored swap result,
+// only the pinned values of the sign request are taken from args.
+func getStoredSwapBuildArgs(args *tokens.BuildTxArgs) (*tokens.BuildTxArgs, error) {
+	fromChainID := args.FromChainID.String()
+	toChainID := args.ToChainID.String()
+	res, err := mongodb.FindRouterSwapResult(fromChainID, args.SwapID, args.LogIndex)
+	if err != nil {
+		return nil, err
+	}
+	if res.ToChainID != toChainID ||
+		res.SwapType != uint32(args.SwapType) ||
+		!strings.EqualFold(res.Bind, args.Bind) {
+		return nil, errSignRequestMismatch
+	}
+	value, err := common.GetBigIntFromStr(res.Value)
+	if err != nil {
+		return nil, err
+	}
+	swapInfo, err := mongodb.ConvertFromSwapInfo(&res.SwapInfo)
+	if err != nil {
+		return nil, err
+	}
+	if swapInfo.GetTokenID() != args.GetTokenID() {
+		return nil, errSignRequestMismatch
+	}
+	err = router.CheckRouteRate(args.GetTokenID(), fromChainID, toChainID, args.ConversionRate)
+	if err != nil {
+		return nil, err
+	}
+	// the fees of a processed swap are pinned when its first swap tx is built
+	if res.DeliveryFeePercent != 0 && args.DeliveryFeePercent != res.DeliveryFeePercent {
+		return nil, errSignRequestMismatch
+	}
+	err = tokens.CheckDeliveryFeePercent(args.GetTokenID(), toChainID, args.DeliveryFeePercent)
+	if err != nil {
+		return nil, err
+	}
+	argsSwapInfo := args.SwapArgs.SwapInfo
+	if swapInfo.AnyCallSwapInfo != nil &&
+		argsSwapInfo.AnyCallSwapInfo != nil &&
+		len(argsSwapInfo.AnyCallSwapInfo.Attestation) > 0 {
+		swapInfo.AnyCallSwapInfo.Attestation = argsSwapInfo.AnyCallSwapInfo.Attestation
+	}
+	return &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapInfo:           swapInfo,
+			Identifier:         args.Identifier,
+			Salt:               args.Salt,
+			SwapID:             res.TxID,
+			SwapType:           tokens.SwapType(res.SwapType),
+			Bind:               res.Bind,
+			LogIndex:           res.LogIndex,
+			FromChainID:        args.FromChainID,
+			ToChainID:          args.ToChainID,
+			Reswapping:         args.Reswapping,
+			TxHeight:           res.TxHeight,
+			ConversionRate:     args.ConversionRate,
+			DeliveryFeePercent: args.DeliveryFeePercent,
+		},
+		From:        args.From,
+		OriginFrom:  res.From,
+		OriginTxTo:  res.TxTo,
+		OriginValue: value,
+		Extra:       args.Extra,
+	}, nil
+}
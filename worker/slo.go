@@ -0,0 +1,172 @@
+package worker
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+)
+
+var (
+	sloWindow           = int64(24 * 3600 * 1000) // milli seconds
+	sloMaxSamplesOfPair = 10000
+
+	// chain pair is flagged degraded if success rate is below the threshold
+	// with at least sloMinSamplesOfFlag samples in the window
+	sloSuccessRateThreshold = 0.95
+	sloMinSamplesOfFlag     = 10
+
+	sloSamples     = make(map[sloPairKey][]*sloSample)
+	sloSamplesLock sync.Mutex
+)
+
+type sloPairKey struct {
+	fromChainID string
+	toChainID   string
+}
+
+type sloSample struct {
+	timestamp int64 // milli seconds
+	duration  int64 // milli seconds
+	success   bool
+}
+
+// SLOPairStats swap completion stats of chain pair in the rolling window
+type SLOPairStats struct {
+	FromChainID string  `json:"fromChainID"`
+	ToChainID   string  `json:"toChainID"`
+	Total       int     `json:"total"`
+	Failed      int     `json:"failed"`
+	SuccessRate float64 `json:"successRate"`
+	MedianMs    int64   `json:"medianMs"` // completion time of successful swaps
+	P95Ms       int64   `json:"p95Ms"`
+}
+
+// SLOIncidents current incident flags
+type SLOIncidents struct {
+	StaleHeadChainIDs []string `json:"staleHeadChainIDs,omitempty"`
+	PausedChainIDs    []string `json:"pausedChainIDs,omitempty"`
+	DegradedPairs     []string `json:"degradedPairs,omitempty"` // fromChainID:toChainID
+}
+
+// SLOReport service level report for public status pages
+type SLOReport struct {
+	Window      int64           `json:"window"` // seconds
+	Total       int             `json:"total"`
+	Failed      int             `json:"failed"`
+	SuccessRate float64         `json:"successRate"`
+	Pairs       []*SLOPairStats `json:"pairs"`
+	Incidents   *SLOIncidents   `json:"incidents"`
+	HasIncident bool            `json:"hasIncident"`
+}
+
+// recordSwapCompletion record completed swap result (stable or failed on chain)
+func recordSwapCompletion(swap *mongodb.MgoSwapResult, success bool) {
+	nowMilli := common.NowMilli()
+	sample := &sloSample{
+		timestamp: nowMilli,
+		success:   success,
+	}
+	if swap.InitTime > 0 && swap.InitTime < nowMilli {
+		sample.duration = nowMilli - swap.InitTime
+	}
+	key := sloPairKey{fromChainID: swap.FromChainID, toChainID: swap.ToChainID}
+
+	sloSamplesLock.Lock()
+	defer sloSamplesLock.Unlock()
+	samples := pruneSLOSamples(sloSamples[key], nowMilli)
+	if len(samples) >= sloMaxSamplesOfPair {
+		samples = samples[1:]
+	}
+	sloSamples[key] = append(samples, sample)
+}
+
+func pruneSLOSamples(samples []*sloSample, nowMilli int64) []*sloSample {
+	i := 0
+	for i < len(samples) && samples[i].timestamp+sloWindow < nowMilli {
+		i++
+	}
+	return samples[i:]
+}
+
+// GetSLOReport get rolling success rate, completion time and incident flags
+func GetSLOReport() *SLOReport {
+	nowMilli := common.NowMilli()
+	report := &SLOReport{
+		Window:    sloWindow / 1000,
+		Incidents: &SLOIncidents{},
+	}
+
+	sloSamplesLock.Lock()
+	for key, samples := range sloSamples {
+		samples = pruneSLOSamples(samples, nowMilli)
+		if len(samples) == 0 {
+			delete(sloSamples, key)
+			continue
+		}
+		sloSamples[key] = samples
+		stats := calcSLOPairStats(key, samples)
+		report.Pairs = append(report.Pairs, stats)
+		report.Total += stats.Total
+		report.Failed += stats.Failed
+		if stats.Total >= sloMinSamplesOfFlag && stats.SuccessRate < sloSuccessRateThreshold {
+			report.Incidents.DegradedPairs = append(report.Incidents.DegradedPairs, key.fromChainID+":"+key.toChainID)
+		}
+	}
+	sloSamplesLock.Unlock()
+
+	sort.Slice(report.Pairs, func(i, j int) bool {
+		if report.Pairs[i].FromChainID != report.Pairs[j].FromChainID {
+			return report.Pairs[i].FromChainID < report.Pairs[j].FromChainID
+		}
+		return report.Pairs[i].ToChainID < report.Pairs[j].ToChainID
+	})
+	sort.Strings(report.Incidents.DegradedPairs)
+	if report.Total > 0 {
+		report.SuccessRate = float64(report.Total-report.Failed) / float64(report.Total)
+	}
+
+	report.Incidents.StaleHeadChainIDs = GetStaleHeadChainIDs()
+	sort.Strings(report.Incidents.StaleHeadChainIDs)
+	for _, chainID := range router.GetPausedChainIDs() {
+		report.Incidents.PausedChainIDs = append(report.Incidents.PausedChainIDs, chainID.String())
+	}
+	report.HasIncident = len(report.Incidents.StaleHeadChainIDs) > 0 ||
+		len(report.Incidents.PausedChainIDs) > 0 ||
+		len(report.Incidents.DegradedPairs) > 0
+	return report
+}
+
+func calcSLOPairStats(key sloPairKey, samples []*sloSample) *SLOPairStats {
+	stats := &SLOPairStats{
+		FromChainID: key.fromChainID,
+		ToChainID:   key.toChainID,
+		Total:       len(samples),
+	}
+	durations := make([]int64, 0, len(samples))
+	for _, sample := range samples {
+		if !sample.success {
+			stats.Failed++
+			continue
+		}
+		durations = append(durations, sample.duration)
+	}
+	stats.SuccessRate = float64(stats.Total-stats.Failed) / float64(stats.Total)
+	if len(durations) > 0 {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		stats.MedianMs = getPercentile(durations, 50)
+		stats.P95Ms = getPercentile(durations, 95)
+	}
+	return stats
+}
+
+// getPercentile get nearest-rank percentile of sorted values
+func getPercentile(sorted []int64, percent int) int64 {
+	rank := (percent*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
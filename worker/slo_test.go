@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+)
+
+func resetSLOSamples() {
+	sloSamplesLock.Lock()
+	sloSamples = make(map[sloPairKey][]*sloSample)
+	sloSamplesLock.Unlock()
+}
+
+func TestCalcSLOPairStats(t *testing.T) {
+	if p := getPercentile([]int64{100}, 95); p != 100 {
+		t.Errorf("percentile of one value got %v, want 100", p)
+	}
+	var samples []*sloSample
+	for i := int64(1); i <= 20; i++ {
+		samples = append(samples, &sloSample{duration: i * 1000, success: true})
+	}
+	samples = append(samples, &sloSample{duration: 100000, success: false})
+
+	stats := calcSLOPairStats(sloPairKey{fromChainID: "1", toChainID: "56"}, samples)
+	want := &SLOPairStats{
+		FromChainID: "1",
+		ToChainID:   "56",
+		Total:       21,
+		Failed:      1,
+		SuccessRate: 20.0 / 21.0,
+		MedianMs:    10000, // durations of failed swaps are excluded
+		P95Ms:       19000,
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("calc slo pair stats got %+v, want %+v", stats, want)
+	}
+}
+
+func TestPruneSLOSamples(t *testing.T) {
+	nowMilli := int64(100 * 24 * 3600 * 1000)
+	samples := []*sloSample{
+		{timestamp: nowMilli - sloWindow - 1},
+		{timestamp: nowMilli - sloWindow},
+		{timestamp: nowMilli},
+	}
+	if pruned := pruneSLOSamples(samples, nowMilli); len(pruned) != 2 || pruned[0] != samples[1] {
+		t.Errorf("prune slo samples got %v samples, want the 2 samples in window", len(pruned))
+	}
+}
+
+func TestGetSLOReport(t *testing.T) {
+	resetSLOSamples()
+	defer resetSLOSamples()
+
+	if report := GetSLOReport(); report.Total != 0 || report.HasIncident || len(report.Pairs) != 0 {
+		t.Errorf("slo report without swaps got %+v", report)
+	}
+
+	initTime := common.NowMilli() - 60000
+	degraded := &mongodb.MgoSwapResult{FromChainID: "1", ToChainID: "56", InitTime: initTime}
+	for i := 0; i < sloMinSamplesOfFlag; i++ {
+		recordSwapCompletion(degraded, i > 0)
+	}
+	// pairs with too few samples are not flagged
+	few := &mongodb.MgoSwapResult{FromChainID: "1", ToChainID: "137", InitTime: initTime}
+	recordSwapCompletion(few, false)
+
+	report := GetSLOReport()
+	if report.Total != sloMinSamplesOfFlag+1 || report.Failed != 2 || len(report.Pairs) != 2 {
+		t.Fatalf("slo report got total %v, failed %v, pairs %v", report.Total, report.Failed, len(report.Pairs))
+	}
+	if pair := report.Pairs[0]; pair.ToChainID != "137" || pair.SuccessRate != 0 {
+		t.Errorf("slo report first pair got %+v, want pairs sorted by chain IDs", pair)
+	}
+	if pair := report.Pairs[1]; pair.ToChainID != "56" || pair.MedianMs < 60000 {
+		t.Errorf("slo report second pair got %+v, want completion time of at least 60s", pair)
+	}
+	if !report.HasIncident || !reflect.DeepEqual(report.Incidents.DegradedPairs, []string{"1:56"}) {
+		t.Errorf("slo report got incidents %+v, want degraded pair 1:56", report.Incidents)
+	}
+}
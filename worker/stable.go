@@ -20,7 +20,7 @@ func StartStableJob() {
 	logWorker("stable", "start router swap stable job")
 
 	// start producer
-	go startStableProducer()
+	goSupervisedJob("stable", nil, startStableProducer)
 }
 
 func startStableProducer() {
@@ -61,20 +61,37 @@ func findRouterSwapResultsToStable() ([]*mongodb.MgoSwapResult, error) {
 	return mongodb.FindRouterSwapResultsWithStatus(mongodb.MatchTxNotStable, septime)
 }
 
+// getSwapTxStatus get status of swap tx or old swap txs,
+// prefer the succeed one if there are failed txs on chain.
 func getSwapTxStatus(resBridge tokens.IBridge, swap *mongodb.MgoSwapResult) *tokens.TxStatus {
 	txStatus, err := resBridge.GetTransactionStatus(swap.SwapTx)
-	if err == nil && txStatus.IsSwapTxOnChain() {
+	if err == nil && txStatus.IsSwapTxOnChainAndSucceed() {
 		return txStatus
 	}
+	var failedTx string
+	var failedStatus *tokens.TxStatus
+	if err == nil && txStatus.IsSwapTxOnChain() {
+		failedTx, failedStatus = swap.SwapTx, txStatus
+	}
 	for _, oldSwapTx := range swap.OldSwapTxs {
 		if swap.SwapTx == oldSwapTx {
 			continue
 		}
 		txStatus2, err2 := resBridge.GetTransactionStatus(oldSwapTx)
-		if err2 == nil && txStatus2.IsSwapTxOnChain() {
+		if err2 != nil {
+			continue
+		}
+		if txStatus2.IsSwapTxOnChainAndSucceed() {
 			swap.SwapTx = oldSwapTx
 			return txStatus2
 		}
+		if failedStatus == nil && txStatus2.IsSwapTxOnChain() {
+			failedTx, failedStatus = oldSwapTx, txStatus2
+		}
+	}
+	if failedStatus != nil {
+		swap.SwapTx = failedTx
+		return failedStatus
 	}
 	return txStatus
 }
@@ -90,8 +107,7 @@ func dispatchSwapResultToStable(res *mongodb.MgoSwapResult) error {
 		// init stable task queue and start consumer routine
 		taskQueue = fifo.NewQueue()
 		stableTaskQueues[chainID] = taskQueue
-		mongodb.MgoWaitGroup.Add(1)
-		go startStableConsumer(chainID)
+		goSupervisedJob("doStable:"+chainID, mongodb.MgoWaitGroup, func() { startStableConsumer(chainID) })
 	}
 
 	logWorker("stable", "dispatch stable router swap task", "fromChainID", res.FromChainID, "toChainID", res.ToChainID, "txid", res.TxID, "logIndex", res.LogIndex, "value", res.SwapValue, "swapNonce", res.SwapNonce, "queue", taskQueue.Len())
@@ -103,7 +119,6 @@ func dispatchSwapResultToStable(res *mongodb.MgoSwapResult) error {
 }
 
 func startStableConsumer(chainID string) {
-	defer mongodb.MgoWaitGroup.Done()
 	logWorker("doStable", "start process swap task", "chainID", chainID)
 
 	taskQueue, exist := stableTaskQueues[chainID]
@@ -136,15 +151,20 @@ func startStableConsumer(chainID string) {
 			continue
 		}
 
-		ctx := []interface{}{"fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex}
-		err := processRouterSwapStable(swap)
-		if err == nil {
-			logWorker("doStable", "process router swap success", ctx...)
-		} else {
-			logWorkerError("doStable", "process router swap failed", err, ctx...)
-		}
+		processStableTask(swap)
+	}
+}
 
-		stableTasksInQueue.Remove(swap.Key)
+func processStableTask(swap *mongodb.MgoSwapResult) {
+	// remove from queue even if panics, so that it can be dispatched again
+	defer stableTasksInQueue.Remove(swap.Key)
+
+	ctx := []interface{}{"fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex}
+	err := processRouterSwapStable(swap)
+	if err == nil {
+		logWorker("doStable", "process router swap success", ctx...)
+	} else {
+		logWorkerError("doStable", "process router swap failed", err, ctx...)
 	}
 }
 
@@ -166,7 +186,7 @@ func processRouterSwapStable(swap *mongodb.MgoSwapResult) (err error) {
 		}
 
 		var err error
-		if router.IsReswapSupported(swap.ToChainID) {
+		if isAutoReswapEnabled(swap.ToChainID) {
 			err = reswapIfTimeout(resBridge, swap)
 			if err == nil {
 				return nil
@@ -174,26 +194,43 @@ func processRouterSwapStable(swap *mongodb.MgoSwapResult) (err error) {
 		}
 
 		if router.IsNonceSupported(swap.ToChainID) {
-			err = checkIfSwapNonceHasPassed(resBridge, swap, false)
+			err = checkIfSwapNonceHasPassed(resBridge, swap, false, isSwapTxExpired(resBridge, swap))
 		}
 
 		return err
 	}
 
 	if swap.SwapHeight != 0 {
-		if txStatus.Confirmations < resBridge.GetChainConfig().Confirmations {
+		if !txStatus.IsFinalized() {
 			return nil
 		}
 		if swap.SwapTx != oldSwapTx {
-			_ = updateSwapTx(swap.FromChainID, swap.TxID, swap.LogIndex, swap.SwapTx)
+			_ = updateSwapTx(swap.FromChainID, swap.TxID, swap.LogIndex, swap.SwapTx, 0)
 		}
 		if txStatus.IsSwapTxOnChainAndFailed() {
 			logWorker("stable", "mark swap result onchain failed",
 				"fromChainID", swap.FromChainID, "txid", swap.TxID, "logIndex", swap.LogIndex,
 				"swaptime", swap.Timestamp, "nowtime", now())
-			return markSwapResultFailed(swap.FromChainID, swap.TxID, swap.LogIndex)
+			err = markSwapResultFailed(swap.FromChainID, swap.TxID, swap.LogIndex)
+			if err == nil {
+				recordSwapCompletion(swap, false)
+				recordSwapEvent(swap.FromChainID, swap.TxID, swap.LogIndex, mongodb.SwapEventFailed, 0, swap.SwapTx)
+				recordDelivery(swap, txStatus, true)
+			}
+			return err
+		}
+		err = markSwapResultStable(swap.FromChainID, swap.TxID, swap.LogIndex)
+		if err == nil {
+			recordSwapCompletion(swap, true)
+			recordSwapEvent(swap.FromChainID, swap.TxID, swap.LogIndex, mongodb.SwapEventStable, 0, swap.SwapTx)
+			recordClaimSwap(swap)
+			recordDeliveredAmount(resBridge, swap)
+			recordGasUsed(resBridge, swap, txStatus)
+			recordDelivery(swap, txStatus, false)
+			checkDuplicateDeliveries(resBridge, swap)
+			issueSwapReceiptOnStable(swap.FromChainID, swap.TxID, swap.LogIndex)
 		}
-		return markSwapResultStable(swap.FromChainID, swap.TxID, swap.LogIndex)
+		return err
 	}
 
 	matchTx := &MatchTx{
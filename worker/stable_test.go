@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testTxStatusBridge struct {
+	tokens.IBridge
+	txStatuses map[string]*tokens.TxStatus
+}
+
+func (b *testTxStatusBridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	if status, exist := b.txStatuses[txHash]; exist {
+		return status, nil
+	}
+	return nil, errors.New("tx not found")
+}
+
+func TestGetSwapTxStatus(t *testing.T) {
+	succeed := &tokens.TxStatus{BlockHeight: 100}
+	failed := &tokens.TxStatus{BlockHeight: 99}
+	failed.SetFailed("out of gas")
+	pending := &tokens.TxStatus{}
+
+	tests := []struct {
+		swapTx     string
+		oldSwapTxs []string
+		txStatuses map[string]*tokens.TxStatus
+		wantTx     string
+		wantStatus *tokens.TxStatus
+	}{
+		{"tx1", nil, map[string]*tokens.TxStatus{"tx1": succeed}, "tx1", succeed},
+		// the succeed old swap tx is preferred
+		{"tx2", []string{"tx1", "tx2"}, map[string]*tokens.TxStatus{"tx1": succeed, "tx2": failed}, "tx1", succeed},
+		{"tx2", []string{"tx1", "tx2"}, map[string]*tokens.TxStatus{"tx1": failed, "tx2": succeed}, "tx2", succeed},
+		// the failed one is reported if no tx succeed
+		{"tx2", []string{"tx1", "tx2"}, map[string]*tokens.TxStatus{"tx1": failed, "tx2": pending}, "tx1", failed},
+		{"tx2", []string{"tx1", "tx2"}, map[string]*tokens.TxStatus{"tx2": pending}, "tx2", pending},
+	}
+	for i, test := range tests {
+		bridge := &testTxStatusBridge{txStatuses: test.txStatuses}
+		swap := &mongodb.MgoSwapResult{SwapTx: test.swapTx, OldSwapTxs: test.oldSwapTxs}
+		status := getSwapTxStatus(bridge, swap)
+		if status != test.wantStatus || swap.SwapTx != test.wantTx {
+			t.Errorf("test %v: get swap tx status got (%v, %+v), want (%v, %+v)", i, swap.SwapTx, status, test.wantTx, test.wantStatus)
+		}
+	}
+}
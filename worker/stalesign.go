@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	discardedSignCount uint64
+
+	errStaleSignature     = errors.New("stale signature")
+	errSignRequestExpired = errors.New("sign request is expired")
+)
+
+// GetDiscardedSignCount get count of signatures discarded as stale
+func GetDiscardedSignCount() uint64 {
+	return atomic.LoadUint64(&discardedSignCount)
+}
+
+// setSignExpiry attach expiry to sign request, oracles refuse to sign after it
+func setSignExpiry(args *tokens.BuildTxArgs) {
+	if expiry := params.GetSignExpiry(); expiry > 0 {
+		args.SignExpiry = now() + expiry
+	}
+}
+
+// checkStaleSignature check whether the signature is still valid in the destination context,
+// a stale signature should be discarded and the swap should be rebuilt rather than broadcasted.
+func checkStaleSignature(resBridge tokens.IBridge, args *tokens.BuildTxArgs, txHash string) error {
+	res, err := mongodb.FindRouterSwapResult(args.FromChainID.String(), args.SwapID, args.LogIndex)
+	if err != nil {
+		res = nil
+	}
+	return discardStaleSignature(resBridge, args, res, txHash)
+}
+
+// discardStaleSignature count and discard the signature if it is stale against the swap result
+func discardStaleSignature(resBridge tokens.IBridge, args *tokens.BuildTxArgs, res *mongodb.MgoSwapResult, txHash string) error {
+	reason := getStaleSignatureReason(resBridge, args, res)
+	if reason == "" {
+		return nil
+	}
+	count := atomic.AddUint64(&discardedSignCount, 1)
+	logWorkerWarn("doSwap", "discard stale signature", "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex, "txHash", txHash, "swapNonce", args.GetTxNonce(), "reason", reason, "discarded", count)
+	return fmt.Errorf("%w: %v", errStaleSignature, reason)
+}
+
+func getStaleSignatureReason(resBridge tokens.IBridge, args *tokens.BuildTxArgs, res *mongodb.MgoSwapResult) string {
+	if args.IsSignExpired(now()) {
+		return errSignRequestExpired.Error()
+	}
+	// swap result is already MatchTxNotStable after nonce allocated in parallel mode
+	if res != nil && !isPendingSwapStatus(res.Status) && res.Status != mongodb.MatchTxNotStable {
+		return fmt.Sprintf("swap is cancelled with status %v", res.Status.String())
+	}
+	if checker, ok := resBridge.(tokens.SignedTxStaleChecker); ok {
+		if err := checker.CheckSignedTxStale(args); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var errTestContextChanged = errors.New("destination context changed")
+
+type testStaleCheckBridge struct {
+	tokens.IBridge
+	staleErr error
+}
+
+func (b *testStaleCheckBridge) CheckSignedTxStale(*tokens.BuildTxArgs) error {
+	return b.staleErr
+}
+
+func newTestSignArgs(signExpiry int64) *tokens.BuildTxArgs {
+	return &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapID:      "0x01",
+			FromChainID: big.NewInt(1),
+			ToChainID:   big.NewInt(56),
+		},
+		SignExpiry: signExpiry,
+	}
+}
+
+func TestDiscardStaleSignature(t *testing.T) {
+	current := int64(1700000000)
+	defer setFakeNow(&current)()
+
+	tests := []struct {
+		args      *tokens.BuildTxArgs
+		staleErr  error
+		status    mongodb.SwapStatus
+		wantStale bool
+	}{
+		{newTestSignArgs(0), nil, mongodb.MatchTxEmpty, false},
+		{newTestSignArgs(current), nil, mongodb.MatchTxEmpty, false},
+		{newTestSignArgs(current), nil, mongodb.MatchTxNotStable, false},
+		{newTestSignArgs(current), nil, mongodb.Reswapping, false},
+		{newTestSignArgs(current - 1), nil, mongodb.MatchTxEmpty, true},
+		{newTestSignArgs(current), errTestContextChanged, mongodb.MatchTxEmpty, true},
+		{newTestSignArgs(current), nil, mongodb.ManualMakeFail, true},
+	}
+	for i, test := range tests {
+		bridge := &testStaleCheckBridge{staleErr: test.staleErr}
+		res := &mongodb.MgoSwapResult{Status: test.status}
+		before := GetDiscardedSignCount()
+		err := discardStaleSignature(bridge, test.args, res, "0x02")
+		if errors.Is(err, errStaleSignature) != test.wantStale || (!test.wantStale && err != nil) {
+			t.Errorf("test %v: discard stale signature got err %v, want stale %v", i, err, test.wantStale)
+		}
+		wantCount := before
+		if test.wantStale {
+			wantCount++
+		}
+		if count := GetDiscardedSignCount(); count != wantCount {
+			t.Errorf("test %v: discarded sign count got %v, want %v", i, count, wantCount)
+		}
+	}
+}
+
+func TestRebuildStaleSignature(t *testing.T) {
+	cfg := params.GetRouterConfig()
+	oldServer := cfg.Server
+	cfg.Server = &params.RouterServerConfig{SignExpiry: 60}
+	defer func() { cfg.Server = oldServer }()
+
+	current := int64(1700000000)
+	defer setFakeNow(&current)()
+
+	args := newTestSignArgs(0)
+	setSignExpiry(args)
+	if args.SignExpiry != current+60 {
+		t.Fatalf("sign expiry got %v, want %v", args.SignExpiry, current+60)
+	}
+
+	// signing takes longer than the validity period
+	current += 61
+	bridge := &testStaleCheckBridge{}
+	res := &mongodb.MgoSwapResult{Status: mongodb.MatchTxEmpty}
+	before := GetDiscardedSignCount()
+	err := discardStaleSignature(bridge, args, res, "0x02")
+	if !errors.Is(err, errStaleSignature) || GetDiscardedSignCount() != before+1 {
+		t.Fatalf("expired signature got err %v, want %v", err, errStaleSignature)
+	}
+
+	// the swap is left pending and rebuilt with a new sign request
+	rebuilt := newTestSignArgs(0)
+	setSignExpiry(rebuilt)
+	if err = discardStaleSignature(bridge, rebuilt, res, "0x03"); err != nil {
+		t.Errorf("rebuilt signature got err %v", err)
+	}
+	if GetDiscardedSignCount() != before+1 {
+		t.Errorf("rebuilt signature should not be discarded")
+	}
+}
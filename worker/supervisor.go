@@ -0,0 +1,152 @@
+package worker
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+// job supervision:
+// job routines are run by supervisors which recover panics and restart the routine
+// with exponential backoff, so a panic in one chain's processing neither kills the
+// process nor leaves the job silently dead. a job panicking too often in the window
+// is regarded as crash looping and alarmed until it runs stably again.
+var (
+	jobRestartInitialBackoff = time.Second
+	jobRestartMaxBackoff     = 5 * time.Minute
+	jobCrashLoopWindow       = int64(600) // seconds
+	jobCrashLoopThreshold    = 5
+
+	jobPanicStates     = make(map[string]*jobPanicState) // key is job name
+	jobPanicStatesLock sync.Mutex
+)
+
+type jobPanicState struct {
+	panics      []int64 // panic times (seconds) in the crash loop window
+	totalPanics int
+	lastPanic   string
+	isCrashLoop bool
+}
+
+// goSupervisedJob start job routine supervised by panic recovery and restart backoff,
+// wg (can be nil) is done when the job returns normally.
+func goSupervisedJob(name string, wg *sync.WaitGroup, job func()) {
+	if wg != nil {
+		wg.Add(1)
+	}
+	go func() {
+		if wg != nil {
+			defer wg.Done()
+		}
+		superviseJob(name, job)
+	}()
+}
+
+func superviseJob(name string, job func()) {
+	for {
+		if !runJobRecovered(name, job) {
+			return
+		}
+		if utils.IsCleanuping() {
+			return
+		}
+		backoff := getJobRestartBackoff(name)
+		logWorkerWarn("supervisor", "restart job after panic", "job", name, "backoff", backoff.String())
+		time.Sleep(backoff)
+	}
+}
+
+// goSafeTask run one-off task routine of job with panic recovery (without restart)
+func goSafeTask(name string, task func()) {
+	go func() {
+		_ = runJobRecovered(name, task)
+	}()
+}
+
+func runJobRecovered(name string, job func()) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			recordJobPanic(name, r, debug.Stack())
+		}
+	}()
+	job()
+	return false
+}
+
+func recordJobPanic(name string, r interface{}, stack []byte) {
+	nowTime := now()
+
+	jobPanicStatesLock.Lock()
+	defer jobPanicStatesLock.Unlock()
+
+	state, exist := jobPanicStates[name]
+	if !exist {
+		state = &jobPanicState{}
+		jobPanicStates[name] = state
+	}
+	state.panics = append(pruneJobPanics(state.panics, nowTime), nowTime)
+	state.totalPanics++
+	state.lastPanic = fmt.Sprint(r)
+
+	log.Error("[supervisor] job panics", "job", name, "panic", r, "panics", len(state.panics), "total", state.totalPanics, "stack", string(stack))
+	if len(state.panics) >= jobCrashLoopThreshold {
+		state.isCrashLoop = true
+		log.Error("[supervisor] ALARM: job is crash looping", "job", name, "panics", len(state.panics), "window", jobCrashLoopWindow, "lastPanic", state.lastPanic)
+	}
+}
+
+func pruneJobPanics(panics []int64, nowTime int64) []int64 {
+	i := 0
+	for i < len(panics) && panics[i]+jobCrashLoopWindow < nowTime {
+		i++
+	}
+	return panics[i:]
+}
+
+// getJobRestartBackoff backoff doubles with every panic in the crash loop window
+func getJobRestartBackoff(name string) time.Duration {
+	jobPanicStatesLock.Lock()
+	defer jobPanicStatesLock.Unlock()
+
+	state, exist := jobPanicStates[name]
+	if !exist {
+		return jobRestartInitialBackoff
+	}
+	backoff := jobRestartInitialBackoff
+	for i := 1; i < len(state.panics) && backoff < jobRestartMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > jobRestartMaxBackoff {
+		backoff = jobRestartMaxBackoff
+	}
+	return backoff
+}
+
+// GetCrashLoopJobs get jobs which are crash looping,
+// a job recovers from crash loop when no panic in the window.
+func GetCrashLoopJobs() []string {
+	nowTime := now()
+
+	jobPanicStatesLock.Lock()
+	defer jobPanicStatesLock.Unlock()
+
+	jobs := make([]string, 0)
+	for name, state := range jobPanicStates {
+		state.panics = pruneJobPanics(state.panics, nowTime)
+		if state.isCrashLoop && len(state.panics) == 0 {
+			state.isCrashLoop = false
+			logWorker("supervisor", "job recovers from crash loop", "job", name, "total", state.totalPanics)
+		}
+		if state.isCrashLoop {
+			jobs = append(jobs, name)
+		}
+	}
+	sort.Strings(jobs)
+	return jobs
+}
@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func resetJobPanicStates() {
+	jobPanicStatesLock.Lock()
+	jobPanicStates = make(map[string]*jobPanicState)
+	jobPanicStatesLock.Unlock()
+}
+
+func panicJobTimes(name string, times int) {
+	for i := 0; i < times; i++ {
+		_ = runJobRecovered(name, func() { panic("test panic") })
+	}
+}
+
+func TestSuperviseJob(t *testing.T) {
+	resetJobPanicStates()
+	defer resetJobPanicStates()
+	oldBackoff := jobRestartInitialBackoff
+	jobRestartInitialBackoff = time.Millisecond
+	defer func() { jobRestartInitialBackoff = oldBackoff }()
+
+	runs := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		superviseJob("test", func() {
+			runs++
+			if runs <= 3 {
+				panic("test panic")
+			}
+		})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervised job is not restarted after panics")
+	}
+	if runs != 4 {
+		t.Errorf("supervised job runs got %v, want 4", runs)
+	}
+	if state := jobPanicStates["test"]; state == nil || state.totalPanics != 3 || state.lastPanic != "test panic" {
+		t.Errorf("supervised job panics are not recorded")
+	}
+}
+
+func TestGetJobRestartBackoff(t *testing.T) {
+	resetJobPanicStates()
+	defer resetJobPanicStates()
+	current := int64(1700000000)
+	defer setFakeNow(&current)()
+
+	if backoff := getJobRestartBackoff("test"); backoff != jobRestartInitialBackoff {
+		t.Errorf("backoff without panic got %v, want %v", backoff, jobRestartInitialBackoff)
+	}
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second} {
+		panicJobTimes("test", 1)
+		if backoff := getJobRestartBackoff("test"); backoff != want {
+			t.Errorf("test %v: backoff got %v, want %v", i, backoff, want)
+		}
+	}
+
+	// backoff is capped
+	panicJobTimes("test", 20)
+	if backoff := getJobRestartBackoff("test"); backoff != jobRestartMaxBackoff {
+		t.Errorf("backoff of many panics got %v, want %v", backoff, jobRestartMaxBackoff)
+	}
+
+	// backoff is reset by panics outside the window
+	current += jobCrashLoopWindow + 1
+	panicJobTimes("test", 1)
+	if backoff := getJobRestartBackoff("test"); backoff != jobRestartInitialBackoff {
+		t.Errorf("backoff after window got %v, want %v", backoff, jobRestartInitialBackoff)
+	}
+}
+
+func TestGetCrashLoopJobs(t *testing.T) {
+	resetJobPanicStates()
+	defer resetJobPanicStates()
+	current := int64(1700000000)
+	defer setFakeNow(&current)()
+
+	panicJobTimes("stable", jobCrashLoopThreshold-1)
+	panicJobTimes("loopB", jobCrashLoopThreshold)
+	panicJobTimes("loopA", jobCrashLoopThreshold+1)
+	if jobs := GetCrashLoopJobs(); !reflect.DeepEqual(jobs, []string{"loopA", "loopB"}) {
+		t.Errorf("crash loop jobs got %v, want [loopA loopB]", jobs)
+	}
+
+	// still crash looping while panics are in the window
+	current += jobCrashLoopWindow
+	if jobs := GetCrashLoopJobs(); len(jobs) != 2 {
+		t.Errorf("crash loop jobs in window got %v, want 2 jobs", jobs)
+	}
+	current += 10
+	panicJobTimes("loopB", 1)
+	current += jobCrashLoopWindow - 5
+	if jobs := GetCrashLoopJobs(); !reflect.DeepEqual(jobs, []string{"loopB"}) {
+		t.Errorf("crash loop jobs got %v, want [loopB]", jobs)
+	}
+
+	// recovers when no panic in the window
+	current += jobCrashLoopWindow
+	if jobs := GetCrashLoopJobs(); len(jobs) != 0 {
+		t.Errorf("crash loop jobs after recovery got %v, want none", jobs)
+	}
+	if state := jobPanicStates["loopB"]; state.totalPanics != jobCrashLoopThreshold+1 {
+		t.Errorf("total panics got %v, want %v", state.totalPanics, jobCrashLoopThreshold+1)
+	}
+}
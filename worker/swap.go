@@ -29,8 +29,9 @@ var (
 	cachedSwapTasks    = mapset.NewSet()
 	maxCachedSwapTasks = 1000
 
-	swapTaskQueues   = make(map[string]*fifo.Queue) // key is toChainID
-	swapTasksInQueue = mapset.NewSet()
+	swapTaskQueues     = make(map[string]*fifo.FairQueue) // key is toChainID
+	swapTaskQueuesLock sync.Mutex
+	swapTasksInQueue   = mapset.NewSet()
 
 	disagreeRecords      = new(sync.Map)
 	maxDisagreeCount     = uint64(10)
@@ -46,8 +47,7 @@ func StartSwapJob() {
 	logWorker("swap", "start router swap job")
 
 	// start producer
-	go startSwapProducer()
-
+	goSupervisedJob("swap", nil, startSwapProducer)
 }
 
 func startSwapProducer() {
@@ -75,12 +75,18 @@ func startSwapProducer() {
 				continue
 			}
 
+			if isSwapDeferred(swap.Key) {
+				logWorkerTrace("swap", "ignore deferred swap", "key", swap.Key)
+				continue
+			}
+
 			err = processRouterSwap(swap)
 			ctx := []interface{}{"fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex}
 			switch {
 			case err == nil:
 				logWorker("swap", "process router swap success", ctx...)
 			case errors.Is(err, errAlreadySwapped),
+				errors.Is(err, errSwapDeferred),
 				errors.Is(err, errChainIsPaused):
 				ctx = append(ctx, "err", err)
 				logWorkerTrace("swap", "process router swap error", ctx...)
@@ -103,6 +109,7 @@ func findRouterSwapToSwap() ([]*mongodb.MgoSwap, error) {
 
 func processRouterSwap(swap *mongodb.MgoSwap) (err error) {
 	if router.IsChainIDPaused(swap.FromChainID) || router.IsChainIDPaused(swap.ToChainID) {
+		deferSwap(swap, WaitChainUnpaused)
 		return errChainIsPaused
 	}
 
@@ -124,6 +131,14 @@ func processRouterSwap(swap *mongodb.MgoSwap) (err error) {
 		return nil
 	}
 
+	if !params.IsTokenRouteAllowed(swap.GetTokenID(), fromChainID, toChainID) {
+		logWorkerWarn("swap", "swap token route is disabled", "txid", txid, "logIndex", logIndex, "fromChainID", fromChainID, "toChainID", toChainID, "tokenID", swap.GetTokenID())
+		err = tokens.ErrTokenRouteDisabled
+		_ = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TokenRouteDisabled, now(), err.Error())
+		_ = mongodb.UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, mongodb.TokenRouteDisabled, now(), err.Error())
+		return nil
+	}
+
 	res, err := mongodb.FindRouterSwapResult(fromChainID, txid, logIndex)
 	if err != nil {
 		if errors.Is(err, mongodb.ErrItemNotFound) {
@@ -132,8 +147,29 @@ func processRouterSwap(swap *mongodb.MgoSwap) (err error) {
 		return err
 	}
 
-	if strings.HasPrefix(res.Memo, tokens.ErrBuildTxErrorAndDelay.Error()) && res.Timestamp+300 > now() {
-		return nil
+	if strings.HasPrefix(res.Memo, tokens.ErrBuildTxErrorAndDelay.Error()) && res.Timestamp+destLiquidityRetryInterval > now() {
+		deferSwap(swap, WaitDestLiquidity)
+		return errSwapDeferred
+	}
+
+	if err = checkManualApproval(swap); err != nil {
+		return err
+	}
+
+	if err = checkAttestation(swap, res); err != nil {
+		return err
+	}
+
+	if err = checkTimeLock(swap, res); err != nil {
+		return err
+	}
+
+	if err = checkApprovalHooks(swap, res); err != nil {
+		return err
+	}
+
+	if err = checkSendDelay(swap, res); err != nil {
+		return err
 	}
 
 	var disagreeCount uint64
@@ -175,6 +211,7 @@ func processRouterSwap(swap *mongodb.MgoSwap) (err error) {
 	args := &tokens.BuildTxArgs{
 		SwapArgs: tokens.SwapArgs{
 			Identifier:  params.GetIdentifier(),
+			Salt:        params.GetDeploymentSalt(),
 			SwapID:      txid,
 			SwapType:    tokens.SwapType(swap.SwapType),
 			Bind:        bind,
@@ -182,6 +219,7 @@ func processRouterSwap(swap *mongodb.MgoSwap) (err error) {
 			FromChainID: biFromChainID,
 			ToChainID:   biToChainID,
 			Reswapping:  res.Status == mongodb.Reswapping,
+			TxHeight:    res.TxHeight,
 		},
 		From:        routerMPC,
 		OriginFrom:  swap.From,
@@ -193,8 +231,18 @@ func processRouterSwap(swap *mongodb.MgoSwap) (err error) {
 	if err != nil {
 		return err
 	}
+	if res.Status == mongodb.Reswapping && isRedeliveringClaimSwap(fromChainID, txid, logIndex) {
+		args.Extra.DirectPayout = true
+	}
 
-	return dispatchSwapTask(args)
+	if err = acquireInFlightSlot(swap); err != nil {
+		return err
+	}
+	err = dispatchSwapTask(args)
+	if err != nil {
+		releaseInFlightSlot(fromChainID, toChainID)
+	}
+	return err
 }
 
 func getFromToChainIDAndValue(fromChainIDStr, toChainIDStr, valueStr string) (fromChainID, toChainID, value *big.Int, err error) {
@@ -262,6 +310,8 @@ func dispatchSwapTask(args *tokens.BuildTxArgs) error {
 	}
 
 	chainID := args.ToChainID.String()
+	swapTaskQueuesLock.Lock()
+	defer swapTaskQueuesLock.Unlock()
 	taskQueue, exist := swapTaskQueues[chainID]
 	if !exist {
 		bridge := router.GetBridgeByChainID(chainID)
@@ -269,15 +319,19 @@ func dispatchSwapTask(args *tokens.BuildTxArgs) error {
 			return tokens.ErrNoBridgeForChainID
 		}
 		// init swap task queue and start consumer routine
-		taskQueue = fifo.NewQueue()
+		taskQueue = fifo.NewFairQueue(func(tokenID string) int {
+			if cfg := params.GetSwapFairnessConfig(chainID); cfg != nil {
+				return cfg.GetTokenWeight(tokenID)
+			}
+			return 1
+		})
 		swapTaskQueues[chainID] = taskQueue
-		mongodb.MgoWaitGroup.Add(1)
-		go startSwapConsumer(chainID)
+		goSupervisedJob("doSwap:"+chainID, mongodb.MgoWaitGroup, func() { startSwapConsumer(chainID) })
 	}
 
 	logWorker("doSwap", "dispatch router swap task", "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex, "value", args.OriginValue, "swapNonce", args.GetTxNonce(), "queue", taskQueue.Len())
 
-	taskQueue.Add(args)
+	taskQueue.Add(getSwapTaskQueueKey(args), args)
 
 	cacheKey := mongodb.GetRouterSwapKey(args.FromChainID.String(), args.SwapID, args.LogIndex)
 	swapTasksInQueue.Add(cacheKey)
@@ -285,11 +339,21 @@ func dispatchSwapTask(args *tokens.BuildTxArgs) error {
 	return nil
 }
 
+// getSwapTaskQueueKey swap tasks are in fifo order without fair scheduling,
+// otherwise they are queued by tokenID and taken in turn.
+func getSwapTaskQueueKey(args *tokens.BuildTxArgs) string {
+	if params.GetSwapFairnessConfig(args.ToChainID.String()) == nil {
+		return ""
+	}
+	return args.GetTokenID()
+}
+
 func startSwapConsumer(chainID string) {
-	defer mongodb.MgoWaitGroup.Done()
 	logWorker("doSwap", "start process swap task", "chainID", chainID)
 
+	swapTaskQueuesLock.Lock()
 	taskQueue, exist := swapTaskQueues[chainID]
+	swapTaskQueuesLock.Unlock()
 	if !exist {
 		log.Fatal("no task queue", "chainID", chainID)
 	}
@@ -318,22 +382,33 @@ func startSwapConsumer(chainID string) {
 			logWorkerWarn("doSwap", "ignore swap task as toChainID mismatch", "want", chainID, "args", args)
 			continue
 		}
-		logWorker("doSwap", "process router swap start", "args", args)
-		ctx := []interface{}{"fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex}
-		err := doSwap(args)
-		switch {
-		case err == nil:
-			logWorker("doSwap", "process router swap success", ctx...)
-		case errors.Is(err, errAlreadySwapped),
-			errors.Is(err, tokens.ErrNoBridgeForChainID):
-			ctx = append(ctx, "err", err)
-			logWorkerTrace("doSwap", "process router swap failed", ctx...)
-		default:
-			logWorkerError("doSwap", "process router swap failed", err, ctx...)
-		}
+		processSwapTask(args)
+	}
+}
+
+func processSwapTask(args *tokens.BuildTxArgs) {
+	// remove from queue even if panics, so that it can be dispatched again
+	cacheKey := mongodb.GetRouterSwapKey(args.FromChainID.String(), args.SwapID, args.LogIndex)
+	defer swapTasksInQueue.Remove(cacheKey)
 
-		cacheKey := mongodb.GetRouterSwapKey(args.FromChainID.String(), args.SwapID, args.LogIndex)
-		swapTasksInQueue.Remove(cacheKey)
+	if isInternalTxType(args.SwapType) {
+		_ = doInternalTx(args)
+		return
+	}
+	defer releaseInFlightSlot(args.FromChainID.String(), args.ToChainID.String())
+
+	logWorker("doSwap", "process router swap start", "args", args)
+	ctx := []interface{}{"fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex}
+	err := doSwap(args)
+	switch {
+	case err == nil:
+		logWorker("doSwap", "process router swap success", ctx...)
+	case errors.Is(err, errAlreadySwapped),
+		errors.Is(err, tokens.ErrNoBridgeForChainID):
+		ctx = append(ctx, "err", err)
+		logWorkerTrace("doSwap", "process router swap failed", ctx...)
+	default:
+		logWorkerError("doSwap", "process router swap failed", err, ctx...)
 	}
 }
 
@@ -350,7 +425,7 @@ func checkAndUpdateProcessSwapTaskCache(key string) error {
 
 //nolint:funlen,gocyclo // ok
 func doSwap(args *tokens.BuildTxArgs) (err error) {
-	if params.IsParallelSwapEnabled() {
+	if params.IsParallelSwapEnabledOnChain(args.ToChainID.String()) {
 		return doSwapParallel(args)
 	}
 
@@ -381,6 +456,24 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 		return tokens.ErrNoBridgeForChainID
 	}
 
+	// in pipeline mode the signed tx is sent by the pipeline in order
+	var sendTask *pipelineTask
+	pipe := getSwapPipeline(toChainID)
+	if pipe != nil {
+		seq, errp := pipe.reserve()
+		if errp != nil {
+			return errp
+		}
+		defer func() { pipe.deliver(seq, sendTask) }()
+	}
+	epoch := setPipelineNonce(pipe, resBridge, args)
+
+	args.ConversionRate, err = router.GetRouteRate(args.GetTokenID(), fromChainID, toChainID)
+	if err != nil {
+		return err
+	}
+	args.DeliveryFeePercent = tokens.GetDeliveryFeePercent(args.GetTokenID(), toChainID)
+
 	start := time.Now()
 	rawTx, err := resBridge.BuildRawTransaction(args)
 	if err != nil {
@@ -393,22 +486,35 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 	if args.SwapValue == nil {
 		return tokens.ErrNilSwapValue
 	}
+	shadowBuild(args, rawTx)
 	swapTxNonce := args.GetTxNonce() // assign after build tx
 	logWorker("doSwap", "build tx success", "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "swapNonce", swapTxNonce, "timespent", time.Since(start).String())
+	recordSwapEvent(fromChainID, txid, logIndex, mongodb.SwapEventNonceAssigned, 0, fmt.Sprint(swapTxNonce))
 
 	start = time.Now()
-	signedTx, txHash, err := resBridge.MPCSignTransaction(rawTx, args)
+	setSignExpiry(args)
+	signedTx, txHash, err := mpcSignTransaction(resBridge, rawTx, args)
 	if err != nil {
 		logWorkerError("doSwap", "sign tx failed", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "timespent", time.Since(start).String())
-		if errors.Is(err, mpc.ErrGetSignStatusHasDisagree) {
+		switch {
+		case errors.Is(err, mpc.ErrGetSignStatusHasDisagree):
 			reverifySwap(args)
+		case errors.Is(err, mpc.ErrNonceSignedWithOtherMsgs):
+			resumeSwapSignRequest(args)
 		}
 		return err
 	}
 	logWorker("doSwap", "sign tx success", "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "txHash", txHash, "swapNonce", swapTxNonce, "timespent", time.Since(start).String())
+	recordSwapEvent(fromChainID, txid, logIndex, mongodb.SwapEventSigned, time.Since(start), txHash)
 
 	disagreeRecords.Delete(cacheKey)
 
+	// discard stale signature and rebuild the swap in next round
+	err = checkStaleSignature(resBridge, args, txHash)
+	if err != nil {
+		return err
+	}
+
 	// recheck reswap before update db
 	res, err := mongodb.FindRouterSwapResult(fromChainID, txid, logIndex)
 	if err != nil {
@@ -430,6 +536,11 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 	if args.Extra.TTL != nil {
 		matchTx.TTL = *args.Extra.TTL
 	}
+	if args.Extra.Fee != nil {
+		matchTx.SwapFee = *args.Extra.Fee
+	}
+	matchTx.BuilderVersion = args.Extra.BuilderVersion
+	matchTx.DeliveryFeePercent = args.DeliveryFeePercent
 	err = updateRouterSwapResult(fromChainID, txid, logIndex, matchTx)
 	if err != nil {
 		logWorkerError("doSwap", "update router swap result failed", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "swapNonce", swapTxNonce)
@@ -443,9 +554,29 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 		return err
 	}
 
-	start = time.Now()
+	if pipe != nil {
+		// the next swap can be built before this one is sent
+		pipe.signed(args.From, swapTxNonce, epoch)
+		sendTask = &pipelineTask{
+			epoch: epoch,
+			send:  func() error { return sendSwapTx(resBridge, signedTx, txHash, args) },
+		}
+		logWorker("doSwap", "add signed tx to pipeline", "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "txHash", txHash, "swapNonce", swapTxNonce)
+		return nil
+	}
+	return sendSwapTx(resBridge, signedTx, txHash, args)
+}
+
+func sendSwapTx(resBridge tokens.IBridge, signedTx interface{}, txHash string, args *tokens.BuildTxArgs) error {
+	fromChainID := args.FromChainID.String()
+	toChainID := args.ToChainID.String()
+	txid := args.SwapID
+	logIndex := args.LogIndex
+	swapTxNonce := args.GetTxNonce()
+
+	start := time.Now()
 	sentTxHash, err := sendSignedTransaction(resBridge, signedTx, args)
-	if err == nil && txHash != sentTxHash {
+	if err == nil && sentTxHash != "" && txHash != sentTxHash {
 		logWorkerError("doSwap", "send tx success but with different hash", errSendTxWithDiffHash,
 			"fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex,
 			"txHash", txHash, "sentTxHash", sentTxHash, "swapNonce", swapTxNonce,
@@ -456,7 +587,11 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 			"fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex,
 			"txHash", txHash, "swapNonce", swapTxNonce, "timespent", time.Since(start).String())
 	}
-	logWorker("doSwap", "finish to process", "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "value", originValue)
+	recordSendSwapEvent(fromChainID, txid, logIndex, time.Since(start), txHash, sentTxHash, err)
+	if err != nil {
+		err = resendOutOfGasSwap(resBridge, args, err)
+	}
+	logWorker("doSwap", "finish to process", "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "value", args.OriginValue)
 	return err
 }
 
@@ -488,6 +623,12 @@ func doSwapParallel(args *tokens.BuildTxArgs) (err error) {
 		return tokens.ErrNoBridgeForChainID
 	}
 
+	args.ConversionRate, err = router.GetRouteRate(args.GetTokenID(), fromChainID, toChainID)
+	if err != nil {
+		return err
+	}
+	args.DeliveryFeePercent = tokens.GetDeliveryFeePercent(args.GetTokenID(), toChainID)
+
 	rawTx, err := resBridge.BuildRawTransaction(args)
 	if err != nil {
 		logWorkerError("doSwap", "build tx failed", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex)
@@ -496,11 +637,13 @@ func doSwapParallel(args *tokens.BuildTxArgs) (err error) {
 		}
 		return err
 	}
+	shadowBuild(args, rawTx)
+	recordSwapEvent(fromChainID, txid, logIndex, mongodb.SwapEventNonceAssigned, 0, fmt.Sprint(args.GetTxNonce()))
 
 	isCachedSwapProcessed = true
-	go func() {
+	goSafeTask("doSwap:"+toChainID, func() {
 		_ = signAndSendTx(rawTx, args)
-	}()
+	})
 	return nil
 }
 
@@ -513,7 +656,8 @@ func signAndSendTx(rawTx interface{}, args *tokens.BuildTxArgs) error {
 	resBridge := router.GetBridgeByChainID(toChainID)
 
 	start := time.Now()
-	signedTx, txHash, err := resBridge.MPCSignTransaction(rawTx, args)
+	setSignExpiry(args)
+	signedTx, txHash, err := mpcSignTransaction(resBridge, rawTx, args)
 	if err != nil {
 		logWorkerError("doSwap", "sign tx failed", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "swapNonce", swapTxNonce, "timespent", time.Since(start).String())
 		if errors.Is(err, mpc.ErrGetSignStatusHasDisagree) {
@@ -522,17 +666,24 @@ func signAndSendTx(rawTx interface{}, args *tokens.BuildTxArgs) error {
 		return err
 	}
 	logWorker("doSwap", "sign tx success", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "swapNonce", swapTxNonce, "timespent", time.Since(start).String())
+	recordSwapEvent(fromChainID, txid, logIndex, mongodb.SwapEventSigned, time.Since(start), txHash)
 
 	cacheKey := mongodb.GetRouterSwapKey(fromChainID, txid, logIndex)
 	disagreeRecords.Delete(cacheKey)
 
+	// discard stale signature, the allocated nonce is left to the replace job
+	err = checkStaleSignature(resBridge, args, txHash)
+	if err != nil {
+		return err
+	}
+
 	// update database before sending transaction
 	addSwapHistory(fromChainID, txid, logIndex, txHash)
-	_ = updateSwapTx(fromChainID, txid, logIndex, txHash)
+	_ = updateSwapTx(fromChainID, txid, logIndex, txHash, args.DeliveryFeePercent)
 
 	start = time.Now()
-	sentTxHash, err := sendSignedTransaction(resBridge, signedTx, args)
-	if err == nil && txHash != sentTxHash {
+	sentTxHash, err := sendSignedTransactionInBatch(resBridge, signedTx, args)
+	if err == nil && sentTxHash != "" && txHash != sentTxHash {
 		logWorkerError("doSwap", "send tx success but with different hash", errSendTxWithDiffHash,
 			"fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex,
 			"txHash", txHash, "sentTxHash", sentTxHash, "swapNonce", swapTxNonce,
@@ -543,6 +694,10 @@ func signAndSendTx(rawTx interface{}, args *tokens.BuildTxArgs) error {
 			"fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex,
 			"txHash", txHash, "swapNonce", swapTxNonce, "timespent", time.Since(start).String())
 	}
+	recordSendSwapEvent(fromChainID, txid, logIndex, time.Since(start), txHash, sentTxHash, err)
+	if err != nil {
+		err = resendOutOfGasSwap(resBridge, args, err)
+	}
 	return err
 }
 
@@ -561,6 +716,7 @@ func reverifySwap(args *tokens.BuildTxArgs) {
 		SwapType:      args.SwapType,
 		LogIndex:      logIndex,
 		AllowUnstable: false,
+		PinnedHeight:  args.TxHeight,
 	}
 	srcBridge := router.GetBridgeByChainID(fromChainID)
 	if srcBridge == nil {
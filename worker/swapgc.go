@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+var swapGCStarter sync.Once
+
+// StartSwapGCJob garbage collection job of swap records,
+// it keeps documents small so that replace path queries and updates stay fast.
+func StartSwapGCJob() {
+	cfg := params.GetSwapGCConfig()
+	if cfg == nil {
+		return
+	}
+	swapGCStarter.Do(func() {
+		logWorker("swapgc", "start swap gc job", "interval", cfg.GetInterval(), "retention", cfg.GetRetention())
+		go runSwapGC(cfg)
+	})
+}
+
+func runSwapGC(cfg *params.SwapGCConfig) {
+	interval := time.Duration(cfg.GetInterval()) * time.Second
+	for {
+		doSwapGC(cfg)
+		time.Sleep(interval)
+	}
+}
+
+func doSwapGC(cfg *params.SwapGCConfig) {
+	before := time.Now().Unix() - cfg.GetRetention()
+	oldSwapTxsBefore := time.Now().Unix() - cfg.GetOldSwapTxsRetention()
+	batchSize := cfg.GetBatchSize()
+
+	pruned, err := mongodb.PruneFinalizedOldSwapTxs(oldSwapTxsBefore, batchSize)
+	if err != nil {
+		logWorkerError("swapgc", "prune old swap txs failed", err)
+	}
+	compacted, err := mongodb.CompactOldSwapTxs(oldSwapTxsBefore, cfg.GetMaxOldSwapTxs(), batchSize)
+	if err != nil {
+		logWorkerError("swapgc", "compact old swap txs failed", err)
+	}
+	orphanedResults, err := mongodb.RemoveOrphanedSwapResults(before, batchSize)
+	if err != nil {
+		logWorkerError("swapgc", "remove orphaned swap results failed", err)
+	}
+	orphanedQueueItems, err := mongodb.RemoveOrphanedQueueItems(before, batchSize)
+	if err != nil {
+		logWorkerError("swapgc", "remove orphaned queue items failed", err)
+	}
+	prunedEvents, err := mongodb.PruneSwapEvents(before, batchSize)
+	if err != nil {
+		logWorkerError("swapgc", "prune swap events failed", err)
+	}
+	prunedSignRequests, err := mongodb.PruneSignRequests(before, batchSize)
+	if err != nil {
+		logWorkerError("swapgc", "prune sign requests failed", err)
+	}
+	prunedSignApprovals, err := mongodb.PruneSignApprovals(now(), batchSize)
+	if err != nil {
+		logWorkerError("swapgc", "prune sign approvals failed", err)
+	}
+	logWorker("swapgc", "swap gc finished", "pruned", pruned, "compacted", compacted,
+		"orphanedResults", orphanedResults, "orphanedQueueItems", orphanedQueueItems,
+		"prunedEvents", prunedEvents, "prunedSignRequests", prunedSignRequests, "prunedSignApprovals", prunedSignApprovals)
+}
@@ -0,0 +1,211 @@
+package worker
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// swap stats are recomputed from stable swap results of yesterday and today
+// in every round, so that swaps finalized later are counted in their buckets
+// and the stats are not affected by restarts.
+var (
+	swapStatsStarter sync.Once
+
+	swapStatsDecimals = uint8(18) // values are normalized to this decimals
+
+	swapStatIntervals = map[string]int64{
+		mongodb.SwapStatHourly: 3600,
+		mongodb.SwapStatDaily:  86400,
+	}
+)
+
+type swapStatAccumulator struct {
+	stat          *mongodb.MgoSwapStat
+	volume        *big.Int
+	fee           *big.Int
+	totalDuration int64
+	durationCount int64
+}
+
+// StartSwapStatsJob time bucketed stats of finalized swaps job
+func StartSwapStatsJob() {
+	cfg := params.GetSwapStatsConfig()
+	if cfg == nil {
+		return
+	}
+	swapStatsStarter.Do(func() {
+		logWorker("swapstats", "start swap stats job", "interval", cfg.GetInterval(), "retention", cfg.GetRetention())
+		goSupervisedJob("swapstats", nil, func() { runSwapStats(cfg) })
+	})
+}
+
+func runSwapStats(cfg *params.SwapStatsConfig) {
+	interval := time.Duration(cfg.GetInterval()) * time.Second
+	for {
+		doSwapStats(cfg)
+		time.Sleep(interval)
+	}
+}
+
+func doSwapStats(cfg *params.SwapStatsConfig) {
+	nowTime := now()
+	since := nowTime - nowTime%86400 - 86400 // start of yesterday
+	results, err := findStableSwapResultsInRange(mongodb.FindStableSwapResultsAfter, since, nowTime+1, cfg.GetBatchSize())
+	if err != nil {
+		logWorkerError("swapstats", "find stable swap results failed", err)
+		return
+	}
+
+	stats := calcSwapStats(results, since, nowTime)
+	if err = mongodb.UpsertSwapStats(stats); err != nil {
+		logWorkerError("swapstats", "save swap stats failed", err)
+		return
+	}
+
+	pruned, err := mongodb.PruneSwapStats(nowTime-cfg.GetRetention(), cfg.GetBatchSize())
+	if err != nil {
+		logWorkerError("swapstats", "prune swap stats failed", err)
+	}
+	logWorker("swapstats", "compute swap stats finished", "swaps", len(results), "stats", len(stats), "pruned", pruned)
+}
+
+// findStableSwapResultsInRange find stable swap results in [since, before)
+// page by page until the range is exhausted.
+func findStableSwapResultsInRange(
+	findAfter func(timestamp int64, key string, before, limit int64) ([]*mongodb.MgoSwapResult, error),
+	since, before, batchSize int64,
+) ([]*mongodb.MgoSwapResult, error) {
+	timestamp, key := since, ""
+	var results []*mongodb.MgoSwapResult
+	for {
+		page, err := findAfter(timestamp, key, before, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, page...)
+		if int64(len(page)) < batchSize {
+			return results, nil
+		}
+		last := page[len(page)-1]
+		timestamp, key = last.Timestamp, last.Key
+	}
+}
+
+func calcSwapStats(results []*mongodb.MgoSwapResult, since, timestamp int64) []*mongodb.MgoSwapStat {
+	accumulators := make(map[string]*swapStatAccumulator)
+	for _, res := range results {
+		completeTime := getSwapCompleteTime(res)
+		if completeTime < since {
+			continue
+		}
+		var duration int64
+		if res.InitTime > 0 && completeTime*1000 > res.InitTime {
+			duration = completeTime*1000 - res.InitTime // init time is milli seconds
+		}
+		volume, fee := getSwapStatValues(res)
+		tokenID := res.GetTokenID()
+
+		for interval, size := range swapStatIntervals {
+			bucket := completeTime - completeTime%size
+			key := mongodb.GetSwapStatKey(interval, bucket, res.FromChainID, res.ToChainID, tokenID)
+			acc, exist := accumulators[key]
+			if !exist {
+				acc = &swapStatAccumulator{
+					stat: &mongodb.MgoSwapStat{
+						Key:         key,
+						Interval:    interval,
+						Bucket:      bucket,
+						FromChainID: res.FromChainID,
+						ToChainID:   res.ToChainID,
+						TokenID:     tokenID,
+						Timestamp:   timestamp,
+					},
+					volume: big.NewInt(0),
+					fee:    big.NewInt(0),
+				}
+				accumulators[key] = acc
+			}
+			acc.stat.Count++
+			acc.volume.Add(acc.volume, volume)
+			acc.fee.Add(acc.fee, fee)
+			if duration > 0 {
+				acc.totalDuration += duration
+				acc.durationCount++
+			}
+		}
+	}
+
+	stats := make([]*mongodb.MgoSwapStat, 0, len(accumulators))
+	for _, acc := range accumulators {
+		acc.stat.Volume = acc.volume.String()
+		acc.stat.FeeRevenue = acc.fee.String()
+		if acc.durationCount > 0 {
+			acc.stat.AvgCompletionTime = acc.totalDuration / acc.durationCount
+		}
+		stats = append(stats, acc.stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Key < stats[j].Key
+	})
+	return stats
+}
+
+// getSwapCompleteTime get complete time of stable swap (seconds)
+func getSwapCompleteTime(res *mongodb.MgoSwapResult) int64 {
+	if res.SwapTime > 0 {
+		return int64(res.SwapTime)
+	}
+	return res.Timestamp
+}
+
+// getSwapStatValues get deposited value and fee (deposited minus delivered)
+// normalized to swapStatsDecimals, the values are zero if not erc20 swap.
+func getSwapStatValues(res *mongodb.MgoSwapResult) (volume, fee *big.Int) {
+	volume, fee = big.NewInt(0), big.NewInt(0)
+	if res.SwapType != uint32(tokens.ERC20SwapType) || res.ERC20SwapInfo == nil {
+		return volume, fee
+	}
+	fromBridge := router.GetBridgeByChainID(res.FromChainID)
+	if fromBridge == nil {
+		return volume, fee
+	}
+	fromTokenCfg := fromBridge.GetTokenConfig(res.ERC20SwapInfo.Token)
+	if fromTokenCfg == nil {
+		return volume, fee
+	}
+	value, err := common.GetBigIntFromStr(res.Value)
+	if err != nil {
+		return volume, fee
+	}
+	volume = tokens.ConvertTokenValue(value, fromTokenCfg.Decimals, swapStatsDecimals)
+	if delivered := getSwapStatDeliveredValue(res); delivered != nil && volume.Cmp(delivered) > 0 {
+		fee = new(big.Int).Sub(volume, delivered)
+	}
+	return volume, fee
+}
+
+// getSwapStatDeliveredValue get swap value normalized to swapStatsDecimals
+func getSwapStatDeliveredValue(res *mongodb.MgoSwapResult) *big.Int {
+	toBridge := router.GetBridgeByChainID(res.ToChainID)
+	if toBridge == nil {
+		return nil
+	}
+	tokenAddr := router.GetCachedMultichainToken(res.ERC20SwapInfo.TokenID, res.ToChainID)
+	toTokenCfg := toBridge.GetTokenConfig(tokenAddr)
+	if toTokenCfg == nil {
+		return nil
+	}
+	value, err := common.GetBigIntFromStr(res.SwapValue)
+	if err != nil {
+		return nil
+	}
+	return tokens.ConvertTokenValue(value, toTokenCfg.Decimals, swapStatsDecimals)
+}
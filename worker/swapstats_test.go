@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+)
+
+// newTestStableSwapResultsFinder find in sorted results as the database does
+func newTestStableSwapResultsFinder(results []*mongodb.MgoSwapResult, queries *int) func(int64, string, int64, int64) ([]*mongodb.MgoSwapResult, error) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Timestamp != results[j].Timestamp {
+			return results[i].Timestamp < results[j].Timestamp
+		}
+		return results[i].Key < results[j].Key
+	})
+	return func(timestamp int64, key string, before, limit int64) ([]*mongodb.MgoSwapResult, error) {
+		*queries++
+		var page []*mongodb.MgoSwapResult
+		for _, res := range results {
+			if int64(len(page)) >= limit {
+				break
+			}
+			if res.Timestamp >= before {
+				continue
+			}
+			if res.Timestamp > timestamp || (res.Timestamp == timestamp && res.Key > key) {
+				page = append(page, res)
+			}
+		}
+		return page, nil
+	}
+}
+
+func TestFindStableSwapResultsInRange(t *testing.T) {
+	var results []*mongodb.MgoSwapResult
+	for i := 0; i < 7; i++ {
+		// results of the same timestamp span pages
+		results = append(results, &mongodb.MgoSwapResult{Key: fmt.Sprintf("key%d", i), Timestamp: 100 + int64(i/3)})
+	}
+	results = append(results,
+		&mongodb.MgoSwapResult{Key: "before", Timestamp: 99},
+		&mongodb.MgoSwapResult{Key: "after", Timestamp: 200},
+	)
+
+	queries := 0
+	found, err := findStableSwapResultsInRange(newTestStableSwapResultsFinder(results, &queries), 100, 200, 2)
+	if err != nil {
+		t.Fatalf("find stable swap results failed: %v", err)
+	}
+	if len(found) != 7 {
+		t.Fatalf("find stable swap results got %v items, want 7", len(found))
+	}
+	for i, res := range found {
+		if res.Key != fmt.Sprintf("key%d", i) {
+			t.Errorf("result %v got key %v", i, res.Key)
+		}
+	}
+	if queries != 4 {
+		t.Errorf("find stable swap results queried %v pages, want 4", queries)
+	}
+
+	errFind := errors.New("find failed")
+	_, err = findStableSwapResultsInRange(func(int64, string, int64, int64) ([]*mongodb.MgoSwapResult, error) {
+		return nil, errFind
+	}, 100, 200, 2)
+	if !errors.Is(err, errFind) {
+		t.Errorf("find stable swap results got error %v, want %v", err, errFind)
+	}
+}
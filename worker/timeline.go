@@ -0,0 +1,129 @@
+package worker
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+)
+
+// swap lifecycle stages of timeline
+const (
+	SwapStageRegistered = "registered"
+	SwapStageRejected   = "rejected"
+	SwapStageVerified   = "verified"
+)
+
+// SwapTimelineEntry stage of swap lifecycle
+type SwapTimelineEntry struct {
+	Stage     string `json:"stage"`
+	Timestamp int64  `json:"timestamp"`          // milli seconds
+	Duration  int64  `json:"duration,omitempty"` // milli seconds
+	Detail    string `json:"detail,omitempty"`
+}
+
+// SwapTimeline lifecycle timeline of swap
+type SwapTimeline struct {
+	FromChainID  string               `json:"fromChainID"`
+	TxID         string               `json:"txid"`
+	LogIndex     int                  `json:"logIndex"`
+	Status       string               `json:"status"`
+	SwapTx       string               `json:"swaptx,omitempty"`
+	SwapNonce    uint64               `json:"swapnonce,omitempty"`
+	ReplaceCount int                  `json:"replaceCount"`
+	Entries      []*SwapTimelineEntry `json:"entries"`
+}
+
+// recordSwapEvent record swap lifecycle event which is not kept in swap records
+func recordSwapEvent(fromChainID, txid string, logIndex int, event string, duration time.Duration, detail string) {
+	err := mongodb.AddSwapEvent(fromChainID, txid, logIndex, event, duration.Milliseconds(), detail)
+	if err != nil {
+		logWorkerTrace("timeline", "record swap event failed", "fromChainID", fromChainID, "txid", txid, "logIndex", logIndex, "event", event, "err", err)
+	}
+}
+
+func recordSendSwapEvent(fromChainID, txid string, logIndex int, duration time.Duration, txHash, sentTxHash string, err error) {
+	switch {
+	case err != nil:
+		recordSwapEvent(fromChainID, txid, logIndex, mongodb.SwapEventSendFailed, duration, err.Error())
+	case sentTxHash != "":
+		recordSwapEvent(fromChainID, txid, logIndex, mongodb.SwapEventSent, duration, sentTxHash)
+	default:
+		recordSwapEvent(fromChainID, txid, logIndex, mongodb.SwapEventSent, duration, txHash)
+	}
+}
+
+// GetSwapTimeline get lifecycle timeline of swap from stored timestamps and swap events
+func GetSwapTimeline(fromChainID, txid string, logIndex int) (*SwapTimeline, error) {
+	swap, err := mongodb.FindRouterSwap(fromChainID, txid, logIndex)
+	if err != nil {
+		return nil, err
+	}
+	rejections, _ := mongodb.FindSwapRejectionsOfTx(fromChainID, txid)
+	res, err := mongodb.FindRouterSwapResult(fromChainID, txid, logIndex)
+	if err != nil {
+		res = nil
+	}
+	events, err := mongodb.FindSwapEvents(fromChainID, txid, logIndex)
+	if err != nil {
+		return nil, err
+	}
+	timeline := &SwapTimeline{
+		FromChainID: fromChainID,
+		TxID:        txid,
+		LogIndex:    logIndex,
+	}
+	timeline.build(swap, rejections, res, events)
+	return timeline, nil
+}
+
+// build timeline entries sorted by timestamp, res is nil if the swap is not verified
+func (t *SwapTimeline) build(swap *mongodb.MgoSwap, rejections []*mongodb.MgoSwapRejection, res *mongodb.MgoSwapResult, events []*mongodb.MgoSwapEvent) {
+	t.Status = swap.Status.String()
+	t.addEntry(SwapStageRegistered, swap.InitTime, 0, "")
+
+	for _, rejection := range rejections {
+		if rejection.LogIndex != t.LogIndex {
+			continue
+		}
+		detail := fmt.Sprintf("%v (count %v): %v", rejection.Reason, rejection.Count, rejection.Message)
+		t.addEntry(SwapStageRejected, rejection.InitTime*1000, 0, detail)
+	}
+
+	if res != nil {
+		t.Status = res.Status.String()
+		t.SwapTx = res.SwapTx
+		t.SwapNonce = res.SwapNonce
+		t.ReplaceCount = len(res.OldSwapTxs)
+		t.addEntry(SwapStageVerified, res.InitTime, 0, "")
+	}
+
+	hasStableEvent := false
+	for _, event := range events {
+		if event.Event == mongodb.SwapEventStable {
+			hasStableEvent = true
+		}
+		t.addEntry(event.Event, event.Timestamp, event.Duration, event.Detail)
+	}
+	// swaps stabilized before swap events are recorded
+	if res != nil && res.Status == mongodb.MatchTxStable && !hasStableEvent {
+		t.addEntry(mongodb.SwapEventStable, res.Timestamp*1000, 0, "")
+	}
+
+	sort.SliceStable(t.Entries, func(i, j int) bool {
+		return t.Entries[i].Timestamp < t.Entries[j].Timestamp
+	})
+}
+
+func (t *SwapTimeline) addEntry(stage string, timestamp, duration int64, detail string) {
+	if timestamp <= 0 {
+		return
+	}
+	t.Entries = append(t.Entries, &SwapTimelineEntry{
+		Stage:     stage,
+		Timestamp: timestamp,
+		Duration:  duration,
+		Detail:    detail,
+	})
+}
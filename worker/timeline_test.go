@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+)
+
+func getTimelineStages(timeline *SwapTimeline) []string {
+	stages := make([]string, 0, len(timeline.Entries))
+	for _, entry := range timeline.Entries {
+		stages = append(stages, entry.Stage)
+	}
+	return stages
+}
+
+func TestBuildSwapTimeline(t *testing.T) {
+	swap := &mongodb.MgoSwap{Status: mongodb.TxNotSwapped, InitTime: 1000000}
+	rejections := []*mongodb.MgoSwapRejection{
+		{LogIndex: 1, Reason: "txNotStable", Count: 2, InitTime: 1001},
+		{LogIndex: 2, Reason: "other log", InitTime: 1002},
+	}
+	res := &mongodb.MgoSwapResult{
+		Status:     mongodb.MatchTxStable,
+		SwapTx:     "0x02",
+		SwapNonce:  5,
+		OldSwapTxs: []string{"0x01", "0x02"},
+		InitTime:   1003000,
+		Timestamp:  1010,
+	}
+	events := []*mongodb.MgoSwapEvent{
+		{Event: mongodb.SwapEventSent, Timestamp: 1005000, Duration: 300, Detail: "0x01"},
+		{Event: mongodb.SwapEventSigned, Timestamp: 1004000, Duration: 2000},
+		{Event: mongodb.SwapEventReplaced, Timestamp: 0},
+	}
+
+	timeline := &SwapTimeline{FromChainID: "1", TxID: "0x00", LogIndex: 1}
+	timeline.build(swap, rejections, res, events)
+
+	if timeline.Status != mongodb.MatchTxStable.String() || timeline.SwapTx != "0x02" || timeline.SwapNonce != 5 || timeline.ReplaceCount != 2 {
+		t.Errorf("timeline got %+v", timeline)
+	}
+	// rejections of other logs and events without timestamp are ignored,
+	// stable entry is added from the swap result without stable event
+	want := []string{
+		SwapStageRegistered, SwapStageRejected, SwapStageVerified,
+		mongodb.SwapEventSigned, mongodb.SwapEventSent, mongodb.SwapEventStable,
+	}
+	if stages := getTimelineStages(timeline); len(stages) != len(want) {
+		t.Fatalf("timeline stages got %v, want %v", stages, want)
+	}
+	for i, entry := range timeline.Entries {
+		if entry.Stage != want[i] {
+			t.Errorf("timeline stages got %v, want %v", getTimelineStages(timeline), want)
+			break
+		}
+	}
+	if entry := timeline.Entries[1]; entry.Timestamp != 1001000 || entry.Detail != "txNotStable (count 2): " {
+		t.Errorf("rejected entry got %+v", entry)
+	}
+	if entry := timeline.Entries[5]; entry.Timestamp != 1010000 {
+		t.Errorf("stable entry got %+v", entry)
+	}
+}
+
+func TestBuildSwapTimelineNotVerified(t *testing.T) {
+	swap := &mongodb.MgoSwap{Status: mongodb.TxNotStable, InitTime: 1000000}
+	events := []*mongodb.MgoSwapEvent{{Event: mongodb.SwapEventStable, Timestamp: 1002000}}
+	timeline := &SwapTimeline{LogIndex: 0}
+	timeline.build(swap, nil, nil, events)
+	if timeline.Status != mongodb.TxNotStable.String() || timeline.SwapTx != "" {
+		t.Errorf("timeline of not verified swap got %+v", timeline)
+	}
+	if stages := getTimelineStages(timeline); len(stages) != 2 || stages[0] != SwapStageRegistered || stages[1] != mongodb.SwapEventStable {
+		t.Errorf("timeline stages got %v", stages)
+	}
+}
@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+)
+
+var errSwapNotTimeLocked = errors.New("swap is not time locked")
+
+// TimeLockedSwap pending swap delayed by time lock policy
+type TimeLockedSwap struct {
+	FromChainID string `json:"fromChainID"`
+	ToChainID   string `json:"toChainID"`
+	TxID        string `json:"txid"`
+	LogIndex    int    `json:"logIndex"`
+	TokenID     string `json:"tokenID"`
+	Bind        string `json:"bind"`
+	Value       string `json:"value"`
+	UnlockTime  int64  `json:"unlockTime"`
+}
+
+// checkTimeLock defer the swap if its value is above the time lock threshold
+// and the enforced delay since verified (swap result creation) is not passed.
+func checkTimeLock(swap *mongodb.MgoSwap, res *mongodb.MgoSwapResult) error {
+	delay := params.GetTimeLockDelay()
+	if delay <= 0 {
+		return nil
+	}
+	value, ok := new(big.Int).SetString(res.Value, 0)
+	if !ok || !router.IsTimeLockedSwap(swap.GetTokenID(), swap.FromChainID, swap.GetToken(), value) {
+		return nil
+	}
+	unlockTime := res.InitTime/1000 + delay // init time is milli seconds
+	if unlockTime <= now() {
+		return nil
+	}
+	deferSwapUntil(swap, WaitTimeLock, unlockTime)
+	return errSwapDeferred
+}
+
+func checkTimeLockExpired(ds *DeferredSwap) bool {
+	return ds.UnlockTime <= now()
+}
+
+// GetTimeLockedSwaps get pending swaps delayed by time lock policy
+func GetTimeLockedSwaps() []*TimeLockedSwap {
+	deferredSwapsLock.RLock()
+	defer deferredSwapsLock.RUnlock()
+	result := make([]*TimeLockedSwap, 0)
+	for _, ds := range deferredSwaps {
+		if ds.Condition != WaitTimeLock {
+			continue
+		}
+		swap := ds.Swap
+		result = append(result, &TimeLockedSwap{
+			FromChainID: swap.FromChainID,
+			ToChainID:   swap.ToChainID,
+			TxID:        swap.TxID,
+			LogIndex:    swap.LogIndex,
+			TokenID:     swap.GetTokenID(),
+			Bind:        swap.Bind,
+			Value:       swap.Value,
+			UnlockTime:  ds.UnlockTime,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UnlockTime < result[j].UnlockTime
+	})
+	return result
+}
+
+// VetoTimeLockedSwap veto pending time locked swap, the swap is marked as manual failed
+func VetoTimeLockedSwap(fromChainID, txid string, logIndex int, memo string) error {
+	key := mongodb.GetRouterSwapKey(fromChainID, txid, logIndex)
+	// take the swap out first, so it can not be released while vetoing
+	ds, err := claimTimeLockedSwap(key)
+	if err != nil {
+		return err
+	}
+
+	memo = "vetoed: " + memo
+	err = mongodb.UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, mongodb.ManualMakeFail, now(), memo)
+	if err != nil {
+		redeferSwap(ds)
+		return err
+	}
+	_ = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.ManualMakeFail, now(), memo)
+	logWorkerWarn("timelock", "veto time locked swap", "key", key, "memo", memo)
+	return nil
+}
+
+// claimTimeLockedSwap remove the pending time locked swap from deferred swaps
+func claimTimeLockedSwap(key string) (*DeferredSwap, error) {
+	deferredSwapsLock.Lock()
+	defer deferredSwapsLock.Unlock()
+	ds, exist := deferredSwaps[key]
+	if !exist || ds.Condition != WaitTimeLock {
+		return nil, errSwapNotTimeLocked
+	}
+	delete(deferredSwaps, key)
+	return ds, nil
+}
+
+// redeferSwap put back the claimed deferred swap
+func redeferSwap(ds *DeferredSwap) {
+	deferredSwapsLock.Lock()
+	defer deferredSwapsLock.Unlock()
+	if _, exist := deferredSwaps[ds.Swap.Key]; !exist {
+		deferredSwaps[ds.Swap.Key] = ds
+	}
+}
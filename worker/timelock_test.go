@@ -0,0 +1,154 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testTokenConfigBridge struct {
+	tokens.IBridge
+	decimals uint8
+}
+
+func (b *testTokenConfigBridge) GetTokenConfig(token string) *tokens.TokenConfig {
+	return &tokens.TokenConfig{Decimals: b.decimals}
+}
+
+// setFakeNow replace the clock of worker, returns the restore func
+func setFakeNow(current *int64) func() {
+	oldNow := now
+	now = func() int64 { return *current }
+	return func() { now = oldNow }
+}
+
+func setupTimeLock(t *testing.T) func() {
+	cfg := params.GetRouterConfig()
+	oldServer := cfg.Server
+	cfg.Server = &params.RouterServerConfig{
+		TimeLock: &params.TimeLockConfig{
+			DelaySeconds: 3600,
+			Thresholds:   map[string]string{"USDC": "1000"},
+		},
+	}
+	router.SetBridge("1", &testTokenConfigBridge{decimals: 6})
+	resetDeferredSwaps()
+	return func() {
+		cfg.Server = oldServer
+		router.SetBridge("1", nil)
+		resetDeferredSwaps()
+	}
+}
+
+func newTestTimeLockSwap(key, value string, initTime int64) (*mongodb.MgoSwap, *mongodb.MgoSwapResult) {
+	swapInfo := mongodb.SwapInfo{ERC20SwapInfo: &mongodb.ERC20SwapInfo{TokenID: "USDC", Token: "0x01"}}
+	swap := &mongodb.MgoSwap{Key: key, FromChainID: "1", ToChainID: "56", SwapInfo: swapInfo, Value: value}
+	res := &mongodb.MgoSwapResult{Key: key, FromChainID: "1", ToChainID: "56", SwapInfo: swapInfo, Value: value, InitTime: initTime * 1000}
+	return swap, res
+}
+
+func TestCheckTimeLock(t *testing.T) {
+	defer setupTimeLock(t)()
+	current := int64(1700000000)
+	defer setFakeNow(&current)()
+
+	tests := []struct {
+		key        string
+		value      string
+		initTime   int64
+		wantLocked bool
+	}{
+		{"below", "999000000", current, false},
+		{"threshold", "1000000000", current, false},
+		{"above", "1000000001", current, true},
+		// the enforced delay since verified is passed
+		{"passed", "1000000001", current - 3600, false},
+		{"partly", "1000000001", current - 3599, true},
+	}
+	for i, test := range tests {
+		swap, res := newTestTimeLockSwap(test.key, test.value, test.initTime)
+		err := checkTimeLock(swap, res)
+		if test.wantLocked != errors.Is(err, errSwapDeferred) || (!test.wantLocked && err != nil) {
+			t.Errorf("test %v: check time lock of %v got err %v, want locked %v", i, test.key, err, test.wantLocked)
+		}
+		if isSwapDeferred(test.key) != test.wantLocked {
+			t.Errorf("test %v: swap %v deferred got %v, want %v", i, test.key, !test.wantLocked, test.wantLocked)
+		}
+	}
+
+	locked := GetTimeLockedSwaps()
+	if len(locked) != 2 || locked[0].UnlockTime != current+1 || locked[1].UnlockTime != current+3600 {
+		t.Errorf("time locked swaps got %v items, want 2 in unlock time order", len(locked))
+	}
+
+	// no time lock if not configured
+	params.GetRouterConfig().Server.TimeLock = nil
+	swap, res := newTestTimeLockSwap("unconfigured", "1000000001", current)
+	if err := checkTimeLock(swap, res); err != nil {
+		t.Errorf("check time lock without config got err %v", err)
+	}
+}
+
+func TestTimeLockRelease(t *testing.T) {
+	defer setupTimeLock(t)()
+	current := int64(1700000000)
+	defer setFakeNow(&current)()
+
+	swap, res := newTestTimeLockSwap("locked", "2000000000", current)
+	if err := checkTimeLock(swap, res); !errors.Is(err, errSwapDeferred) {
+		t.Fatalf("check time lock got err %v, want %v", err, errSwapDeferred)
+	}
+
+	current += 3599
+	if ready := collectReadyDeferredSwaps(); len(ready) != 0 {
+		t.Fatalf("time locked swap is released before the delay")
+	}
+	current++
+	ready := collectReadyDeferredSwaps()
+	if len(ready) != 1 || ready[0].Swap.Key != "locked" {
+		t.Fatalf("time locked swap should be released after the delay, got %v items", len(ready))
+	}
+	// released swap passes the time lock when processed again
+	if err := checkTimeLock(swap, res); err != nil {
+		t.Errorf("check time lock after the delay got err %v", err)
+	}
+}
+
+func TestClaimTimeLockedSwap(t *testing.T) {
+	defer setupTimeLock(t)()
+
+	deferSwapUntil(newTestDeferredSwap("locked"), WaitTimeLock, now()+3600)
+	deferSwap(newTestDeferredSwap("liquidity"), WaitDestLiquidity)
+
+	if _, err := claimTimeLockedSwap("unknown"); !errors.Is(err, errSwapNotTimeLocked) {
+		t.Errorf("veto unknown swap got err %v, want %v", err, errSwapNotTimeLocked)
+	}
+	if _, err := claimTimeLockedSwap("liquidity"); !errors.Is(err, errSwapNotTimeLocked) {
+		t.Errorf("veto swap deferred by other condition got err %v, want %v", err, errSwapNotTimeLocked)
+	}
+
+	ds, err := claimTimeLockedSwap("locked")
+	if err != nil || ds.Swap.Key != "locked" {
+		t.Fatalf("veto time locked swap got err %v", err)
+	}
+	// vetoed swap is never released, and can not be vetoed again
+	if isSwapDeferred("locked") || len(GetTimeLockedSwaps()) != 0 {
+		t.Errorf("vetoed swap should be removed from time locked swaps")
+	}
+	if _, err = claimTimeLockedSwap("locked"); !errors.Is(err, errSwapNotTimeLocked) {
+		t.Errorf("veto swap again got err %v, want %v", err, errSwapNotTimeLocked)
+	}
+
+	// the swap is put back if the veto fails to update database
+	redeferSwap(ds)
+	if locked := GetTimeLockedSwaps(); len(locked) != 1 || locked[0].UnlockTime != ds.UnlockTime {
+		t.Errorf("swap of failed veto should be time locked again")
+	}
+	if err := VetoTimeLockedSwap("1", "0x02", 0, "fraud"); !errors.Is(err, errSwapNotTimeLocked) {
+		t.Errorf("veto not time locked swap got err %v, want %v", err, errSwapNotTimeLocked)
+	}
+}
@@ -25,11 +25,35 @@ var (
 	restIntervalInPassBigValJob = 300 * time.Second
 	passBigValueTimeRequired    = int64(12 * 3600) // seconds
 
+	restIntervalInDeferredJob = 10 * time.Second
+
 	maxCheckFailedSwapLifetime       = int64(2 * 24 * 3600)
 	restIntervalInCheckFailedSwapJob = 60 * time.Second
+
+	restIntervalInDepositSweepJob = 60 * time.Second
+
+	restIntervalInAllowanceJob    = 300 * time.Second
+	allowanceApproveRetryInterval = int64(1800) // seconds, wait for the pending approve tx
+
+	restIntervalInHeadWatchdogJob = 30 * time.Second
+
+	restIntervalInBlockScanJob = 10 * time.Second
+
+	restIntervalInParamWatchJob = 60 * time.Second
+
+	restIntervalInFeatureFlagSyncJob = 30 * time.Second
+
+	restIntervalInRateOracleJob = 30 * time.Second
+
+	restIntervalInDeliveryTrackJob = 60 * time.Second
+
+	restIntervalInClaimSwapJob = 60 * time.Second
+	claimRefundDelay           = int64(60)  // seconds after expiry, tolerate clock drift of mpc nodes
+	claimRefundRetryInterval   = int64(600) // seconds
 )
 
-func now() int64 {
+// now get current unix seconds, it is replaced by fake clock in tests
+var now = func() int64 {
 	return time.Now().Unix()
 }
 
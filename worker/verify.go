@@ -28,7 +28,7 @@ func StartVerifyJob() {
 	logWorker("verify", "start router swap verify job")
 
 	// start producer
-	go startVerifyProducer()
+	goSupervisedJob("verify", nil, startVerifyProducer)
 }
 
 func startVerifyProducer() {
@@ -61,7 +61,7 @@ func startVerifyProducer() {
 			if swap, err := mongodb.FindRouterSwap(swap.FromChainID, swap.TxID, swap.LogIndex); err == nil {
 				bridge := router.GetBridgeByChainID(swap.FromChainID)
 				if bridge != nil && swap.TxHeight > 0 &&
-					swap.TxHeight+bridge.GetChainConfig().Confirmations >
+					swap.TxHeight+getMinRequiredConfirmations(bridge, swap.FromChainID, swap.ToChainID, swap.GetTokenID()) >
 						router.GetCachedLatestBlockNumber(swap.FromChainID) {
 					logWorkerTrace("verify", "ignore swap not stable", "key", swap.Key)
 					continue
@@ -69,7 +69,7 @@ func startVerifyProducer() {
 			}
 
 			err := dispatchVerifyTask(swap) // produce
-			ctx := []interface{}{"fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex}
+			ctx := []interface{}{"fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "requestID", swap.RequestID}
 			if err == nil {
 				logWorker("verify", "verify router swap success", ctx...)
 			} else {
@@ -91,8 +91,7 @@ func dispatchVerifyTask(swap *mongodb.MgoSwap) error {
 		// init verify task queue and start consumer routine
 		taskQueue = fifo.NewQueue()
 		verifyTaskQueues[chainID] = taskQueue
-		mongodb.MgoWaitGroup.Add(1)
-		go startVerifyConsumer(chainID)
+		goSupervisedJob("doVerify:"+chainID, mongodb.MgoWaitGroup, func() { startVerifyConsumer(chainID) })
 	}
 
 	logWorker("verify", "dispatch verify swap task", "fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "queue", taskQueue.Len())
@@ -104,7 +103,6 @@ func dispatchVerifyTask(swap *mongodb.MgoSwap) error {
 }
 
 func startVerifyConsumer(chainID string) {
-	defer mongodb.MgoWaitGroup.Done()
 	logWorker("doVerify", "start verify swap task", "chainID", chainID)
 
 	taskQueue, exist := verifyTaskQueues[chainID]
@@ -137,7 +135,10 @@ func startVerifyConsumer(chainID string) {
 			continue
 		}
 
-		go func() {
+		goSafeTask("doVerify:"+chainID, func() {
+			// remove from queue even if panics, so that it can be dispatched again
+			defer verifyTasksInQueue.Remove(swap.Key)
+
 			ctx := []interface{}{"fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex}
 			err := processRouterSwapVerify(swap)
 			if err == nil {
@@ -145,9 +146,7 @@ func startVerifyConsumer(chainID string) {
 			} else {
 				logWorkerError("doVerify", "verify router swap failed", err, ctx...)
 			}
-
-			verifyTasksInQueue.Remove(swap.Key)
-		}()
+		})
 	}
 }
 
@@ -167,6 +166,13 @@ func processRouterSwapVerify(swap *mongodb.MgoSwap) (err error) {
 	if router.IsChainIDPaused(swap.FromChainID) || router.IsChainIDPaused(swap.ToChainID) {
 		return nil
 	}
+	if IsChainHeadStale(swap.FromChainID) {
+		return nil
+	}
+	if _, err = router.GetRouteRate(swap.GetTokenID(), swap.FromChainID, swap.ToChainID); err != nil {
+		logWorkerTrace("verify", "ignore swap as conversion rate is unavailable", "key", swap.Key, "err", err)
+		return nil
+	}
 
 	fromChainID := swap.FromChainID
 	txid := swap.TxID
@@ -191,6 +197,17 @@ func processRouterSwapVerify(swap *mongodb.MgoSwap) (err error) {
 	if isBlacked(swap) {
 		err = tokens.ErrSwapInBlacklist
 		dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.SwapInBlacklist, now(), err.Error())
+		_ = mongodb.AddSwapRejection(fromChainID, txid, logIndex, err)
+		if dbErr != nil {
+			logWorkerError("verify", "verify router swap db error", dbErr, "fromChainID", fromChainID, "toChainID", swap.ToChainID, "txid", txid, "logIndex", logIndex)
+		}
+		return err
+	}
+
+	if !params.IsTokenRouteAllowed(swap.GetTokenID(), fromChainID, swap.ToChainID) {
+		err = tokens.ErrTokenRouteDisabled
+		dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TokenRouteDisabled, now(), err.Error())
+		_ = mongodb.AddSwapRejection(fromChainID, txid, logIndex, err)
 		if dbErr != nil {
 			logWorkerError("verify", "verify router swap db error", dbErr, "fromChainID", fromChainID, "toChainID", swap.ToChainID, "txid", txid, "logIndex", logIndex)
 		}
@@ -202,7 +219,7 @@ func processRouterSwapVerify(swap *mongodb.MgoSwap) (err error) {
 		return tokens.ErrNoBridgeForChainID
 	}
 
-	logWorker("verify", "process swap verify", "fromChainID", fromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex)
+	logWorker("verify", "process swap verify", "fromChainID", fromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "requestID", swap.RequestID)
 
 	verifyArgs := &tokens.VerifyArgs{
 		SwapType:      tokens.SwapType(swap.SwapType),
@@ -211,11 +228,19 @@ func processRouterSwapVerify(swap *mongodb.MgoSwap) (err error) {
 	}
 
 	start := time.Now()
-	swapInfo, err := bridge.VerifyTransaction(txid, verifyArgs)
-	logWorker("verify", "verify tx finished job", "fromChainID", fromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "timespent", time.Since(start).String())
+	var swapInfo *tokens.SwapTxInfo
+	if swap.Quarantined {
+		err = crossVerifySwapTx(bridge, txid)
+	}
+	if err == nil {
+		swapInfo, err = verifySwapTx(bridge, txid, swap.ToChainID, swap.GetTokenID(), verifyArgs)
+		shadowVerify(fromChainID, txid, verifyArgs, swapInfo, err)
+	}
+	logWorker("verify", "verify tx finished job", "fromChainID", fromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "timespent", time.Since(start).String(), "requestID", swap.RequestID)
 
 	switch {
 	case err == nil:
+		recordVerifiedHeight(fromChainID, swapInfo.Height)
 		if router.IsBigValueSwap(swapInfo) {
 			dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxWithBigValue, now(), "big swap value")
 		} else {
@@ -224,7 +249,14 @@ func processRouterSwapVerify(swap *mongodb.MgoSwap) (err error) {
 				dbErr = AddInitialSwapResult(swapInfo, mongodb.MatchTxEmpty)
 			}
 		}
+	case errors.Is(err, tokens.ErrTxPruned):
+		// not expired as not found swaps, wait for archive gateways to be configured
+		logWorkerWarn("verify", "swap tx data is pruned by rpc nodes", "fromChainID", fromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "err", err)
+		isProcessed = false
+		return err
 	case errors.Is(err, tokens.ErrTxNotStable),
+		errors.Is(err, tokens.ErrReceiptDivergence),
+		errors.Is(err, tokens.ErrLightClientVerify),
 		errors.Is(err, tokens.ErrRPCQueryError),
 		errors.Is(err, tokens.ErrTxNotFound),
 		errors.Is(err, tokens.ErrNotFound):
@@ -232,7 +264,7 @@ func processRouterSwapVerify(swap *mongodb.MgoSwap) (err error) {
 			_ = mongodb.UpdateRouterSwapHeight(fromChainID, txid, logIndex, swapInfo.Height)
 		}
 		nowMilli := common.NowMilli()
-		if swap.InitTime+1000*maxTxNotFoundTime < nowMilli {
+		if isVerifyTimedOut(swap, nowMilli) {
 			duration := time.Duration((nowMilli - swap.InitTime) / 1000 * int64(time.Second))
 			logWorker("verify", "set longer not found swap to verify failed", "fromChainID", fromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "inittime", swap.InitTime, "duration", duration.String())
 			dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxVerifyFailed, now(), err.Error())
@@ -262,6 +294,7 @@ func processRouterSwapVerify(swap *mongodb.MgoSwap) (err error) {
 	}
 
 	if err != nil {
+		_ = mongodb.AddSwapRejection(fromChainID, txid, logIndex, err)
 		logWorkerError("verify", "verify router swap error", err, "fromChainID", fromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex)
 	}
 
@@ -0,0 +1,154 @@
+package worker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/sdk"
+)
+
+// swap events are inserted by concurrent jobs, the events of the last seconds
+// are delivered in the next round so that an event inserted a bit later with
+// an earlier timestamp is not skipped by the delivery position.
+const webhookSettleTime = int64(5000) // milli seconds
+
+var (
+	webhookStarter sync.Once
+
+	errWrongSwapEventKey = errors.New("wrong swap event key")
+)
+
+// StartWebhookJob deliver swap events to the configured webhooks
+func StartWebhookJob() {
+	hooks := params.GetWebhooks()
+	if len(hooks) == 0 {
+		return
+	}
+	webhookStarter.Do(func() {
+		for _, hook := range hooks {
+			logWorker("webhook", "start webhook job", "name", hook.Name, "url", hook.URL, "events", hook.Events)
+			go runWebhookJob(hook)
+		}
+	})
+}
+
+func runWebhookJob(hook *params.WebhookConfig) {
+	interval := time.Duration(hook.GetInterval()) * time.Second
+	for {
+		if utils.IsCleanuping() {
+			return
+		}
+		delivered, err := deliverWebhookEvents(hook)
+		if err != nil {
+			logWorkerError("webhook", "deliver webhook events failed", err, "name", hook.Name, "delivered", delivered)
+		} else if delivered > 0 {
+			logWorker("webhook", "deliver webhook events success", "name", hook.Name, "delivered", delivered)
+		}
+		if delivered < int(hook.GetBatchSize()) {
+			restInJob(interval)
+		}
+	}
+}
+
+// deliverWebhookEvents deliver the events after the persisted position in time order,
+// the position is moved forward after each successful delivery.
+func deliverWebhookEvents(hook *params.WebhookConfig) (delivered int, err error) {
+	nowMilli := common.NowMilli()
+	cursor, err := mongodb.FindWebhookCursor(hook.Name)
+	if errors.Is(err, mongodb.ErrItemNotFound) {
+		// a new webhook starts from now
+		return 0, mongodb.UpdateWebhookCursor(hook.Name, nowMilli-webhookSettleTime, "")
+	}
+	if err != nil {
+		return 0, err
+	}
+	events, err := mongodb.FindSwapEventsAfter(cursor.Timestamp, cursor.EventKey, nowMilli-webhookSettleTime, hook.Events, hook.GetBatchSize())
+	if err != nil {
+		return 0, err
+	}
+	for _, event := range events {
+		if utils.IsCleanuping() {
+			return delivered, nil
+		}
+		whEvent, errc := newWebhookEvent(event)
+		if errc != nil {
+			logWorkerWarn("webhook", "skip wrong swap event", "name", hook.Name, "key", event.Key, "err", errc)
+		} else if err = postWebhookEvent(hook, whEvent); err != nil {
+			return delivered, fmt.Errorf("post event %v: %w", event.Key, err)
+		}
+		if err = mongodb.UpdateWebhookCursor(hook.Name, event.Timestamp, event.Key); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+func newWebhookEvent(event *mongodb.MgoSwapEvent) (*sdk.WebhookEvent, error) {
+	// swap key is fromChainID:txid:logIndex
+	first := strings.Index(event.SwapKey, ":")
+	last := strings.LastIndex(event.SwapKey, ":")
+	if first <= 0 || last <= first+1 {
+		return nil, errWrongSwapEventKey
+	}
+	logIndex, err := strconv.Atoi(event.SwapKey[last+1:])
+	if err != nil {
+		return nil, errWrongSwapEventKey
+	}
+	return &sdk.WebhookEvent{
+		ID:          event.Key,
+		Identifier:  params.GetIdentifier(),
+		FromChainID: event.SwapKey[:first],
+		TxID:        event.SwapKey[first+1 : last],
+		LogIndex:    logIndex,
+		Event:       event.Event,
+		Timestamp:   event.Timestamp,
+		Duration:    event.Duration,
+		Detail:      event.Detail,
+	}, nil
+}
+
+// postWebhookEvent post the signed event, any 2xx response is a successful delivery
+func postWebhookEvent(hook *params.WebhookConfig, event *sdk.WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	secrets := make([][]byte, len(hook.Secrets))
+	for i, secret := range hook.Secrets {
+		secrets[i] = []byte(secret)
+	}
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(sdk.HeaderWebhookSignature, sdk.SignWebhookWithSecrets(secrets, body, time.Now()))
+
+	httpClient := &http.Client{Timeout: time.Duration(hook.GetTimeout()) * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("wrong response status %v", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/sdk"
+)
+
+func TestNewWebhookEvent(t *testing.T) {
+	event := &mongodb.MgoSwapEvent{
+		Key:       "1:0xabc:2:stable:1700000000000",
+		SwapKey:   mongodb.GetRouterSwapKey("1", "0xABC", 2),
+		Event:     mongodb.SwapEventStable,
+		Timestamp: 1700000000000,
+	}
+	got, err := newWebhookEvent(event)
+	if err != nil {
+		t.Fatalf("new webhook event failed: %v", err)
+	}
+	if got.ID != event.Key || got.FromChainID != "1" || got.TxID != "0xabc" || got.LogIndex != 2 || got.Event != "stable" {
+		t.Errorf("new webhook event got %+v", got)
+	}
+	for _, swapKey := range []string{"", "1:0xabc", "1::2", "1:0xabc:x"} {
+		if _, err := newWebhookEvent(&mongodb.MgoSwapEvent{SwapKey: swapKey}); err != errWrongSwapEventKey {
+			t.Errorf("swap key %q got error %v", swapKey, err)
+		}
+	}
+}
+
+func TestPostWebhookEvent(t *testing.T) {
+	secret := []byte("new-secret")
+	status := http.StatusOK
+	var received sdk.WebhookEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := sdk.VerifyWebhook(secret, body, r.Header.Get(sdk.HeaderWebhookSignature), 0); err != nil {
+			t.Errorf("verify webhook failed: %v", err)
+		}
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("unmarshal webhook event failed: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	// the subscriber rotating its secret verifies with either secret
+	hook := &params.WebhookConfig{Name: "test", URL: server.URL, Secrets: []string{"old-secret", string(secret)}}
+	event := &sdk.WebhookEvent{ID: "1:0xabc:0:failed:1", FromChainID: "1", TxID: "0xabc", Event: "failed", Detail: "reverted"}
+	if err := postWebhookEvent(hook, event); err != nil {
+		t.Fatalf("post webhook event failed: %v", err)
+	}
+	if received != *event {
+		t.Errorf("received webhook event %+v, want %+v", received, event)
+	}
+
+	status = http.StatusInternalServerError
+	if err := postWebhookEvent(hook, event); err == nil {
+		t.Errorf("post webhook event with failed response should fail")
+	}
+}
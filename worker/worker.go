@@ -3,6 +3,8 @@ package worker
 import (
 	"time"
 
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router/bridge"
 )
 
@@ -13,8 +15,30 @@ func StartRouterSwapWork(isServer bool) {
 	logWorker("worker", "start router swap worker")
 
 	bridge.InitRouterBridges(isServer)
+	if params.IsStartupCheckEnabled() {
+		StartupSelfCheck(isServer)
+	}
 	bridge.StartReloadRouterConfigTask()
 
+	StartHeadWatchdogJob()
+	time.Sleep(interval)
+
+	StartRateOracleJob()
+	time.Sleep(interval)
+
+	StartFeatureFlagSyncJob(isServer)
+	time.Sleep(interval)
+
+	if isServer {
+		loadServerDeliveryStats()
+		loadServerGasSamples()
+	}
+
+	if IsWatcherMode {
+		startWatcherJobs(isServer)
+		return
+	}
+
 	if !isServer {
 		go StartAcceptSignJob()
 		time.Sleep(interval)
@@ -22,9 +46,23 @@ func StartRouterSwapWork(isServer bool) {
 		return
 	}
 
+	mpc.SetSignRequestVerifier(verifySignRequest)
+
+	StartMPCCanaryJob()
+	time.Sleep(interval)
+
+	StartRecoveryJob()
+	time.Sleep(interval)
+
 	StartSwapJob()
 	time.Sleep(interval)
 
+	StartBlockScanJob()
+	time.Sleep(interval)
+
+	StartDeferredJob()
+	time.Sleep(interval)
+
 	StartVerifyJob()
 	time.Sleep(interval)
 
@@ -45,4 +83,69 @@ func StartRouterSwapWork(isServer bool) {
 
 	StartReswapJob()
 	time.Sleep(interval)
+
+	StartDepositSweepJob()
+	time.Sleep(interval)
+
+	StartAllowanceJob()
+	time.Sleep(interval)
+
+	StartChainParamWatchJob()
+	time.Sleep(interval)
+
+	StartClaimSwapJob()
+	time.Sleep(interval)
+
+	StartSwapGCJob()
+	time.Sleep(interval)
+
+	StartIntegrityCheckJob()
+	time.Sleep(interval)
+
+	StartSwapStatsJob()
+	time.Sleep(interval)
+
+	StartMPCUsageJob()
+	time.Sleep(interval)
+
+	StartDuplicateCheckJob()
+	time.Sleep(interval)
+
+	StartWebhookJob()
+	time.Sleep(interval)
+}
+
+// startWatcherJobs only start the jobs which need no signing capability
+func startWatcherJobs(isServer bool) {
+	logWorker("worker", "start in watcher mode, signing and sending are disabled")
+	if !isServer {
+		return
+	}
+
+	StartBlockScanJob()
+	time.Sleep(interval)
+
+	StartVerifyJob()
+	time.Sleep(interval)
+
+	StartStableJob()
+	time.Sleep(interval)
+
+	StartCheckFailedSwapJob()
+	time.Sleep(interval)
+
+	StartSwapGCJob()
+	time.Sleep(interval)
+
+	StartIntegrityCheckJob()
+	time.Sleep(interval)
+
+	StartSwapStatsJob()
+	time.Sleep(interval)
+
+	StartDuplicateCheckJob()
+	time.Sleep(interval)
+
+	StartWebhookJob()
+	time.Sleep(interval)
 }